@@ -17,6 +17,7 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/app"
 	"github.com/keyxmakerx/chronicle/internal/config"
 	"github.com/keyxmakerx/chronicle/internal/database"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/bestiary"
 	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
 	"github.com/keyxmakerx/chronicle/internal/plugins/foundry_vtt"
@@ -46,7 +47,8 @@ func main() {
 	)
 
 	// --- Connect to MariaDB ---
-	db, err := database.NewMariaDB(cfg.Database)
+	queryStats := database.NewQueryStats(cfg.Database.SlowQueryThreshold)
+	db, err := database.NewMariaDB(cfg.Database, queryStats)
 	if err != nil {
 		slog.Error("failed to connect to MariaDB", slog.Any("error", err))
 		os.Exit(1)
@@ -54,6 +56,20 @@ func main() {
 	defer db.Close()
 	slog.Info("connected to MariaDB")
 
+	// Optional read replicas (DB_READ_REPLICAS). Opted-in read paths use
+	// them through the router; everything else stays on the primary.
+	replicas, err := database.OpenReplicas(cfg.Database, queryStats)
+	if err != nil {
+		slog.Error("failed to open read replicas", slog.Any("error", err))
+		os.Exit(1)
+	}
+	dbRouter := database.NewDBRouter(db, replicas)
+	defer dbRouter.Close()
+	go dbRouter.Start(context.Background())
+	if len(replicas) > 0 {
+		slog.Info("read replicas configured", slog.Int("count", len(replicas)))
+	}
+
 	// --- Run Database Migrations ---
 	// Auto-apply pending migrations on every startup. Already-applied
 	// migrations are skipped. This eliminates the need to run migrate
@@ -72,7 +88,7 @@ func main() {
 		BackupDir:      cfg.BackupDir,
 		BackupRequired: backupRequired,
 		MediaPath:      cfg.Upload.MediaPath,
-		RedisURL:       cfg.Redis.URL,
+		RedisURL:       redisBackupURL(cfg.Redis),
 		DBName:         cfg.Database.Name,
 		DBHost:         cfg.Database.Host,
 		DBUser:         cfg.Database.User,
@@ -106,6 +122,14 @@ func main() {
 		slog.Error("foundry_vtt pre-migration check failed", slog.Any("error", err))
 		os.Exit(1)
 	}
+	// syncapi migration 009 makes external records unique in
+	// sync_mappings; merge any duplicates first. A failure leaves them in
+	// place, and the migration then fails and degrades the plugin.
+	if n, err := syncapi.MergeDuplicateSyncMappings(context.Background(), db); err != nil {
+		slog.Error("sync mapping merge failed", slog.Any("error", err))
+	} else if n > 0 {
+		slog.Info("sync mapping merge: dropped duplicates", slog.Int("mappings", n))
+	}
 	pluginHealth := database.NewPluginHealthRegistry()
 	pluginSchemas := registeredPlugins()
 	pluginResults := database.RunPluginMigrations(db, pluginSchemas)
@@ -119,13 +143,17 @@ func main() {
 	}
 
 	// --- Connect to Redis ---
-	rdb, err := database.NewRedis(cfg.Redis)
+	rdb, redisInMemory, err := database.OpenRedis(cfg.Redis)
 	if err != nil {
 		slog.Error("failed to connect to Redis", slog.Any("error", err))
 		os.Exit(1)
 	}
 	defer func() { _ = rdb.Close() }()
-	slog.Info("connected to Redis")
+	if redisInMemory {
+		slog.Warn("using the in-process Redis store: sessions are lost on restart and not shared between instances")
+	} else {
+		slog.Info("connected to Redis")
+	}
 
 	// --- Initialize Game Systems ---
 	// Discover and load system manifests + data from internal/systems/.
@@ -137,11 +165,18 @@ func main() {
 	systems.ScanPackageDir(filepath.Join(cfg.Upload.MediaPath, "packages", "systems"))
 
 	// --- Create Application ---
-	application := app.New(cfg, db, rdb, pluginHealth, pluginSchemas)
+	application := app.New(cfg, db, rdb, queryStats, dbRouter, pluginHealth, pluginSchemas)
 
 	// Register all routes (public, plugin, system, widget, API).
 	application.RegisterRoutes()
 
+	// Two plugins registering the same route means one silently never
+	// runs; refuse to start instead.
+	if err := application.Routes.Err(); err != nil {
+		slog.Error("route wiring conflict", slog.Any("error", err))
+		os.Exit(1)
+	}
+
 	// --- Graceful Shutdown ---
 	// Listen for interrupt/term signals to drain connections cleanly.
 	// This is required for Docker/Cosmos restarts to be seamless.
@@ -189,7 +224,7 @@ func setupLogging(cfg *config.Config) {
 		})
 	}
 
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(middleware.NewContextHandler(handler)))
 }
 
 // registeredPlugins returns the list of built-in plugins with their embedded
@@ -202,6 +237,16 @@ func getEnvDefault(key, fallback string) string {
 	return fallback
 }
 
+// redisBackupURL is the Redis URL for the pre-migration snapshot, empty
+// (skip it) when running on the in-process store, which has nothing worth
+// keeping across the restart anyway.
+func redisBackupURL(cfg config.RedisConfig) string {
+	if cfg.InMemory() {
+		return ""
+	}
+	return cfg.URL
+}
+
 // fatalBoot logs an unrecoverable boot error and exits — but first sleeps a
 // backoff (BOOT_FAIL_BACKOFF, default 45s) so that a `restart: unless-stopped`
 // container retries at ~1/min instead of hot-looping ~60/min (which floods logs
@@ -0,0 +1,4 @@
+-- Reverse 000031: drop the slug history and the custom-slug flag. Old slug
+-- URLs stop redirecting.
+DROP TABLE IF EXISTS entity_slug_history;
+ALTER TABLE entities DROP COLUMN IF EXISTS slug_custom;
@@ -0,0 +1,22 @@
+-- Retired entity slugs, so links to an entity's old URL keep resolving (301)
+-- after a rename or a custom slug. A row maps (campaign, old slug) to the
+-- entity that last held it. The primary key makes a retired slug belong to
+-- exactly one entity: slug generation treats it as taken, and custom slugs
+-- that collide with another entity's history are rejected rather than
+-- silently stealing its old links. An entity reclaiming one of its own old
+-- slugs deletes the row. Cascades away with the entity or campaign.
+--
+-- entities.slug_custom marks an Owner-chosen slug, which a rename keeps
+-- instead of re-deriving the slug from the new name.
+ALTER TABLE entities ADD COLUMN IF NOT EXISTS slug_custom BOOLEAN NOT NULL DEFAULT FALSE AFTER slug;
+
+CREATE TABLE IF NOT EXISTS entity_slug_history (
+  campaign_id CHAR(36)     NOT NULL,
+  slug        VARCHAR(200) NOT NULL,
+  entity_id   CHAR(36)     NOT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, slug),
+  INDEX idx_entity_slug_history_entity (entity_id),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Reverse 000032: drop announcements and their dismissal state.
+DROP TABLE IF EXISTS announcement_dismissals;
+DROP TABLE IF EXISTS announcements;
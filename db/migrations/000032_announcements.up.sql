@@ -0,0 +1,30 @@
+-- Instance announcements: admin-published banners (maintenance windows, new
+-- features) shown to every signed-in user. starts_at/ends_at bound when a
+-- banner shows (NULL = no bound). announcement_dismissals records who closed
+-- which banner, so a dismissal follows the user across devices; it cascades
+-- away with either side.
+CREATE TABLE IF NOT EXISTS announcements (
+  id          INT          AUTO_INCREMENT PRIMARY KEY,
+  title       VARCHAR(200) NOT NULL,
+  body        TEXT         NOT NULL,
+  severity    VARCHAR(20)  NOT NULL DEFAULT 'info',
+  dismissible BOOLEAN      NOT NULL DEFAULT TRUE,
+  starts_at   DATETIME     DEFAULT NULL,
+  ends_at     DATETIME     DEFAULT NULL,
+  created_by  CHAR(36)     DEFAULT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX idx_announcements_window (starts_at, ends_at),
+  CONSTRAINT chk_announcements_severity CHECK (severity IN ('info', 'success', 'warning', 'critical')),
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS announcement_dismissals (
+  announcement_id INT      NOT NULL,
+  user_id         CHAR(36) NOT NULL,
+  dismissed_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (announcement_id, user_id),
+  INDEX idx_announcement_dismissals_user (user_id),
+  FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Reverse 000033: drop feature flag overrides.
+DROP TABLE IF EXISTS campaign_feature_flags;
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,23 @@
+-- Feature flags: admin overrides for experimental plugins/blocks. Flags are
+-- declared in code (settings.RegisterFlag) with a default; these tables only
+-- hold overrides. A campaign override beats the instance one, which beats
+-- the code default. Campaign overrides cascade away with the campaign.
+CREATE TABLE IF NOT EXISTS feature_flags (
+  flag_key   VARCHAR(100) NOT NULL PRIMARY KEY,
+  enabled    BOOLEAN      NOT NULL,
+  updated_by CHAR(36)     DEFAULT NULL,
+  updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS campaign_feature_flags (
+  campaign_id CHAR(36)     NOT NULL,
+  flag_key    VARCHAR(100) NOT NULL,
+  enabled     BOOLEAN      NOT NULL,
+  updated_by  CHAR(36)     DEFAULT NULL,
+  updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, flag_key),
+  INDEX idx_campaign_feature_flags_key (flag_key),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+-- Reverse 000034: drop the mail outbox.
+DROP TABLE IF EXISTS mail_outbox;
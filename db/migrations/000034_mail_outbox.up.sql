@@ -0,0 +1,21 @@
+-- Mail outbox: every email other plugins send is queued here and delivered
+-- by the smtp plugin's outbox worker, which retries transient SMTP failures
+-- with backoff instead of dropping the message. Recipients are stored as a
+-- JSON array. Rows in 'pending' are due at next_attempt_at; a worker claims
+-- one by pushing next_attempt_at forward (a lease) before sending.
+CREATE TABLE IF NOT EXISTS mail_outbox (
+  id              BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  recipients      JSON         NOT NULL,
+  subject         VARCHAR(500) NOT NULL,
+  text_body       MEDIUMTEXT   NOT NULL,
+  html_body       MEDIUMTEXT   DEFAULT NULL,
+  status          VARCHAR(10)  NOT NULL DEFAULT 'pending',
+  attempts        INT          NOT NULL DEFAULT 0,
+  last_error      TEXT         DEFAULT NULL,
+  next_attempt_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  sent_at         DATETIME     DEFAULT NULL,
+  CONSTRAINT chk_mail_outbox_status CHECK (status IN ('pending', 'sent', 'failed')),
+  INDEX idx_mail_outbox_due (status, next_attempt_at),
+  INDEX idx_mail_outbox_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
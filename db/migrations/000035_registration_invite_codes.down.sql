@@ -0,0 +1,2 @@
+-- Reverse 000035: drop registration invite codes.
+DROP TABLE IF EXISTS registration_invite_codes;
@@ -0,0 +1,17 @@
+-- Registration invite codes: admin-issued codes that let someone create an
+-- account while registration is invite-only, without needing a campaign
+-- invite. A code may be limited to a number of uses and an expiry; NULL means
+-- unlimited / never. Codes are stored as issued so admins can copy them again
+-- from the security page.
+CREATE TABLE IF NOT EXISTS registration_invite_codes (
+  id         INT          NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  code       VARCHAR(64)  NOT NULL,
+  note       VARCHAR(200) NOT NULL DEFAULT '',
+  max_uses   INT          DEFAULT NULL,
+  use_count  INT          NOT NULL DEFAULT 0,
+  expires_at DATETIME     DEFAULT NULL,
+  created_by CHAR(36)     DEFAULT NULL,
+  created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_registration_invite_codes_code (code),
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+-- Reverse 000036: drop per-member personal entity notes.
+DROP TABLE IF EXISTS entity_personal_notes;
@@ -0,0 +1,17 @@
+-- Personal notes: one free-text scratchpad per member per entity, readable
+-- only by the member who wrote it. Kept apart from entity_notes on purpose:
+-- there is no audience column here, so no role or grant (not even the
+-- campaign owner's) can widen who sees a row. An emptied note deletes its
+-- row rather than storing a blank body.
+CREATE TABLE IF NOT EXISTS entity_personal_notes (
+  entity_id   CHAR(36)   NOT NULL,
+  user_id     CHAR(36)   NOT NULL,
+  campaign_id CHAR(36)   NOT NULL,
+  body        TEXT       NOT NULL,
+  updated_at  DATETIME   NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (entity_id, user_id),
+  KEY idx_entity_personal_notes_user (user_id, campaign_id),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
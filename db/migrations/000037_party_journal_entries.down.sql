@@ -0,0 +1,2 @@
+-- Reverse 000037: drop party journal entries.
+DROP TABLE IF EXISTS party_journal_entries;
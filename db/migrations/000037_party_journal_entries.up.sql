@@ -0,0 +1,25 @@
+-- Party journal: dated entries that any member appends to a Journal-type
+-- entity, separate from the entity's own entry (which only Scribe+ edit).
+-- Entries from players start 'pending' and are hidden from other players
+-- until a GM approves them; a GM may also attach an annotation. Authors and
+-- moderators are SET NULL on account deletion so the party's record of the
+-- campaign survives a member leaving the site.
+CREATE TABLE IF NOT EXISTS party_journal_entries (
+  id             CHAR(36)                   NOT NULL PRIMARY KEY,
+  entity_id      CHAR(36)                   NOT NULL,
+  campaign_id    CHAR(36)                   NOT NULL,
+  author_user_id CHAR(36)                   DEFAULT NULL,
+  entry_date     DATE                       NOT NULL,
+  body           TEXT                       NOT NULL,
+  status         ENUM('pending','approved') NOT NULL DEFAULT 'pending',
+  gm_note        TEXT                       DEFAULT NULL,
+  moderated_by   CHAR(36)                   DEFAULT NULL,
+  moderated_at   DATETIME                   DEFAULT NULL,
+  created_at     DATETIME                   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at     DATETIME                   NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_party_journal_entries_entity (entity_id, entry_date, created_at),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (author_user_id) REFERENCES users(id) ON DELETE SET NULL,
+  FOREIGN KEY (moderated_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Reverse 000038: drop entity/session attachments. The media files they
+-- pointed at stay in media_files.
+DROP TABLE IF EXISTS media_attachments;
@@ -0,0 +1,27 @@
+-- Media attachments: handout PDFs, audio, token packs and images attached
+-- to an entity or a session. The bytes live in media_files; this row adds
+-- the target, a title, who may see it and a download counter.
+--
+-- target_id is polymorphic (entities.id or sessions.id) so it carries no
+-- foreign key: deleting an entity or session leaves its attachment rows
+-- behind. They are unreachable (every read goes through the target) and
+-- go with the campaign, but anything that lists attachments by campaign
+-- must filter on live targets.
+CREATE TABLE IF NOT EXISTS media_attachments (
+  id                 CHAR(36)                     NOT NULL PRIMARY KEY,
+  campaign_id        CHAR(36)                     NOT NULL,
+  media_id           CHAR(36)                     NOT NULL,
+  target_type        ENUM('entity','session')     NOT NULL,
+  target_id          CHAR(36)                     NOT NULL,
+  title              VARCHAR(200)                 NOT NULL DEFAULT '',
+  visibility         ENUM('everyone','dm_only')   NOT NULL DEFAULT 'everyone',
+  download_count     INT UNSIGNED                 NOT NULL DEFAULT 0,
+  last_downloaded_at DATETIME                     DEFAULT NULL,
+  created_by         CHAR(36)                     DEFAULT NULL,
+  created_at         DATETIME                     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY idx_media_attachments_target (target_type, target_id, created_at),
+  KEY idx_media_attachments_media (media_id),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (media_id) REFERENCES media_files(id) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Reverse 000039: drop image alt text and captions.
+ALTER TABLE media_files DROP COLUMN IF EXISTS caption, DROP COLUMN IF EXISTS alt_text;
+ALTER TABLE entities DROP COLUMN IF EXISTS image_caption, DROP COLUMN IF EXISTS image_alt;
@@ -0,0 +1,13 @@
+-- Alt text and captions for images. entities.image_alt / image_caption
+-- describe the header image (image_path) and reset whenever it changes;
+-- media_files.alt_text / caption are the library asset's own description.
+-- Public campaigns require alt text on header images (enforced in the
+-- entities service); NULL means "not written yet", and renderers fall back
+-- to the entity name or file name.
+ALTER TABLE entities
+  ADD COLUMN IF NOT EXISTS image_alt VARCHAR(300) NULL AFTER cover_image_path,
+  ADD COLUMN IF NOT EXISTS image_caption VARCHAR(500) NULL AFTER image_alt;
+
+ALTER TABLE media_files
+  ADD COLUMN IF NOT EXISTS alt_text VARCHAR(300) NULL AFTER original_name,
+  ADD COLUMN IF NOT EXISTS caption VARCHAR(500) NULL AFTER alt_text;
@@ -0,0 +1,5 @@
+-- Reverse 000040: drop the campaign deletion grace-period columns.
+ALTER TABLE campaigns
+  DROP INDEX IF EXISTS idx_campaigns_purge_after,
+  DROP COLUMN IF EXISTS purge_after,
+  DROP COLUMN IF EXISTS deletion_requested_at;
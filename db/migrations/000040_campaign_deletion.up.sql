@@ -0,0 +1,9 @@
+-- Two-step campaign deletion. Deleting a campaign now only marks it:
+-- deletion_requested_at records when, purge_after when the purge job may
+-- hard-delete it (seven days later). While marked, the campaign is hidden
+-- from everyone but its owner and site admins, either of whom can restore
+-- it by clearing both columns.
+ALTER TABLE campaigns
+  ADD COLUMN IF NOT EXISTS deletion_requested_at DATETIME NULL AFTER archived_at,
+  ADD COLUMN IF NOT EXISTS purge_after DATETIME NULL AFTER deletion_requested_at,
+  ADD INDEX IF NOT EXISTS idx_campaigns_purge_after (purge_after);
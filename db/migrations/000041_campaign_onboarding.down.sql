@@ -0,0 +1,2 @@
+-- Reverse 000041: drop member onboarding progress.
+DROP TABLE IF EXISTS campaign_onboarding_progress;
@@ -0,0 +1,13 @@
+-- Member onboarding progress. The checklist itself lives in
+-- campaigns.settings (onboarding.checklist); this table records which items
+-- each member has ticked off. Keyed to the membership so leaving or being
+-- removed from a campaign clears the member's progress with it.
+CREATE TABLE IF NOT EXISTS campaign_onboarding_progress (
+  campaign_id  CHAR(36)    NOT NULL,
+  user_id      CHAR(36)    NOT NULL,
+  item_id      VARCHAR(16) NOT NULL,
+  completed_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, user_id, item_id),
+  CONSTRAINT fk_onboarding_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
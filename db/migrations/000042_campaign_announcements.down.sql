@@ -0,0 +1,3 @@
+-- Reverse 000042: drop campaign announcements.
+DROP TABLE IF EXISTS campaign_announcement_reads;
+DROP TABLE IF EXISTS campaign_announcements;
@@ -0,0 +1,33 @@
+-- Campaign announcements: owner posts shown on the campaign dashboard.
+-- publish_at schedules a post (it stays hidden from members until then);
+-- notified_at records that members were notified, so the delivery job
+-- sends each announcement exactly once. body is the owner's markdown,
+-- body_html its sanitized rendering.
+CREATE TABLE IF NOT EXISTS campaign_announcements (
+  id          CHAR(36)     NOT NULL PRIMARY KEY,
+  campaign_id CHAR(36)     NOT NULL,
+  title       VARCHAR(200) NOT NULL,
+  body        MEDIUMTEXT   NOT NULL,
+  body_html   MEDIUMTEXT   NOT NULL,
+  pinned      BOOLEAN      NOT NULL DEFAULT FALSE,
+  publish_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  notified_at DATETIME     DEFAULT NULL,
+  created_by  CHAR(36)     DEFAULT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_campaign_announcements_feed (campaign_id, pinned, publish_at),
+  KEY idx_campaign_announcements_delivery (notified_at, publish_at),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Which members have read which announcement.
+CREATE TABLE IF NOT EXISTS campaign_announcement_reads (
+  announcement_id CHAR(36) NOT NULL,
+  user_id         CHAR(36) NOT NULL,
+  read_at         DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (announcement_id, user_id),
+  KEY idx_campaign_announcement_reads_user (user_id),
+  FOREIGN KEY (announcement_id) REFERENCES campaign_announcements(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
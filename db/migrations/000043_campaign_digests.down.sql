@@ -0,0 +1,3 @@
+-- Reverse 000043: drop the campaign digest tables.
+DROP TABLE IF EXISTS campaign_digest_sends;
+DROP TABLE IF EXISTS campaign_digest_opt_outs;
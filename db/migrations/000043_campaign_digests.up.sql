@@ -0,0 +1,25 @@
+-- Weekly campaign digest email. Members get it by default; a row in
+-- campaign_digest_opt_outs turns it off for that member and campaign.
+-- campaign_digest_sends records each member's digest per week (period_start
+-- is the week's Monday, UTC) so the job sends at most one, even when several
+-- app instances run it. Both are keyed to the membership so leaving the
+-- campaign clears them.
+CREATE TABLE IF NOT EXISTS campaign_digest_opt_outs (
+  campaign_id CHAR(36) NOT NULL,
+  user_id     CHAR(36) NOT NULL,
+  created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, user_id),
+  CONSTRAINT fk_digest_opt_out_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS campaign_digest_sends (
+  campaign_id  CHAR(36) NOT NULL,
+  user_id      CHAR(36) NOT NULL,
+  period_start DATE     NOT NULL,
+  sent_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, user_id, period_start),
+  KEY idx_campaign_digest_sends_period (period_start),
+  CONSTRAINT fk_digest_send_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
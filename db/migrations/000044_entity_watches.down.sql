@@ -0,0 +1,4 @@
+-- Reverse 000044: drop entity watches.
+DROP TABLE IF EXISTS entity_watch_pending;
+DROP TABLE IF EXISTS entity_type_watches;
+DROP TABLE IF EXISTS entity_watches;
@@ -0,0 +1,50 @@
+-- Entity watches: members follow a page (entity_watches) or every page of a
+-- type (entity_type_watches) and are notified when they change, by email
+-- too if they ask. Keyed to the membership so leaving the campaign drops a
+-- member's watches.
+CREATE TABLE IF NOT EXISTS entity_watches (
+  user_id     CHAR(36) NOT NULL,
+  entity_id   CHAR(36) NOT NULL,
+  campaign_id CHAR(36) NOT NULL,
+  email       BOOLEAN  NOT NULL DEFAULT FALSE,
+  created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, entity_id),
+  KEY idx_entity_watches_entity (entity_id),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  CONSTRAINT fk_entity_watch_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS entity_type_watches (
+  user_id        CHAR(36) NOT NULL,
+  entity_type_id INT      NOT NULL,
+  campaign_id    CHAR(36) NOT NULL,
+  email          BOOLEAN  NOT NULL DEFAULT FALSE,
+  created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, entity_type_id),
+  KEY idx_entity_type_watches_type (entity_type_id),
+  FOREIGN KEY (entity_type_id) REFERENCES entity_types(id) ON DELETE CASCADE,
+  CONSTRAINT fk_entity_type_watch_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Changes waiting to be sent. Every save of a watched page bumps one row per
+-- watcher; the flush job sends it once the page has been quiet for a few
+-- minutes (or has kept changing for an hour), so autosave produces one
+-- notification rather than dozens.
+CREATE TABLE IF NOT EXISTS entity_watch_pending (
+  user_id          CHAR(36) NOT NULL,
+  entity_id        CHAR(36) NOT NULL,
+  campaign_id      CHAR(36) NOT NULL,
+  is_new           BOOLEAN  NOT NULL DEFAULT FALSE,
+  email            BOOLEAN  NOT NULL DEFAULT FALSE,
+  change_count     INT      NOT NULL DEFAULT 1,
+  first_changed_at DATETIME NOT NULL,
+  last_changed_at  DATETIME NOT NULL,
+  PRIMARY KEY (user_id, entity_id),
+  KEY idx_entity_watch_pending_last (last_changed_at),
+  KEY idx_entity_watch_pending_first (first_changed_at),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  CONSTRAINT fk_entity_watch_pending_member FOREIGN KEY (campaign_id, user_id)
+    REFERENCES campaign_members(campaign_id, user_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
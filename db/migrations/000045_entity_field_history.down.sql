@@ -0,0 +1,2 @@
+-- Reverse 000045: drop numeric field history.
+DROP TABLE IF EXISTS entity_field_history;
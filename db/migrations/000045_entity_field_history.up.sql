@@ -0,0 +1,20 @@
+-- Numeric field history: every change to a "number" custom field is kept,
+-- with the campaign calendar's current date when there is one, so a GM can
+-- chart faction influence or a town's population across the campaign.
+-- Cleared values aren't recorded; the series only holds numbers.
+CREATE TABLE IF NOT EXISTS entity_field_history (
+  id          BIGINT       NOT NULL AUTO_INCREMENT,
+  entity_id   CHAR(36)     NOT NULL,
+  campaign_id CHAR(36)     NOT NULL,
+  field_key   VARCHAR(100) NOT NULL,
+  old_value   DOUBLE       NULL,
+  new_value   DOUBLE       NOT NULL,
+  game_year   INT          NULL,
+  game_month  INT          NULL,
+  game_day    INT          NULL,
+  changed_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_entity_field_history_field (entity_id, field_key, changed_at),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
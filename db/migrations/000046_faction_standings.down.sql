@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS faction_standing_scales;
+DROP TABLE IF EXISTS faction_standing_history;
+DROP TABLE IF EXISTS faction_standings;
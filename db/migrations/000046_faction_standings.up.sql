@@ -0,0 +1,44 @@
+-- Faction standing tracker: how each faction regards the party as a whole
+-- (subject_entity_id = '') or a single character, on a per-campaign scale
+-- with labeled tiers. Every change is kept in faction_standing_history.
+-- The subject column has no foreign key because '' stands for the party;
+-- reads join entities and skip subjects that no longer exist.
+CREATE TABLE IF NOT EXISTS faction_standings (
+  faction_entity_id CHAR(36)    NOT NULL,
+  subject_entity_id VARCHAR(36) NOT NULL DEFAULT '',
+  campaign_id       CHAR(36)    NOT NULL,
+  score             INT         NOT NULL DEFAULT 0,
+  updated_at        DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (faction_entity_id, subject_entity_id),
+  KEY idx_faction_standings_campaign (campaign_id),
+  FOREIGN KEY (faction_entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS faction_standing_history (
+  id                BIGINT       NOT NULL AUTO_INCREMENT,
+  faction_entity_id CHAR(36)     NOT NULL,
+  subject_entity_id VARCHAR(36)  NOT NULL DEFAULT '',
+  campaign_id       CHAR(36)     NOT NULL,
+  old_score         INT          NULL,
+  new_score         INT          NOT NULL,
+  reason            VARCHAR(255) NULL,
+  changed_by        CHAR(36)     NULL,
+  changed_at        DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY idx_faction_standing_history_pair (faction_entity_id, subject_entity_id, changed_at),
+  FOREIGN KEY (faction_entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One scale per campaign; campaigns without a row use the default scale
+-- (-100..100, Hostile to Allied) defined in code.
+CREATE TABLE IF NOT EXISTS faction_standing_scales (
+  campaign_id CHAR(36) NOT NULL,
+  min_score   INT      NOT NULL,
+  max_score   INT      NOT NULL,
+  tiers_json  JSON     NOT NULL,
+  updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
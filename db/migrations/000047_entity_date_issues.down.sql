@@ -0,0 +1,2 @@
+-- Reverse 000047: drop date field issue flags.
+DROP TABLE IF EXISTS entity_date_issues;
@@ -0,0 +1,16 @@
+-- Date fields that stopped fitting the campaign calendar. Saves reject a
+-- date the calendar doesn't have, but changing the calendar itself (fewer
+-- months, shorter months, new leap rules) can strand dates already saved;
+-- each re-check replaces a campaign's rows so the list is current.
+CREATE TABLE IF NOT EXISTS entity_date_issues (
+  entity_id   CHAR(36)     NOT NULL,
+  field_key   VARCHAR(100) NOT NULL,
+  campaign_id CHAR(36)     NOT NULL,
+  value       VARCHAR(100) NOT NULL,
+  reason      VARCHAR(255) NOT NULL,
+  flagged_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (entity_id, field_key),
+  KEY idx_entity_date_issues_campaign (campaign_id),
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
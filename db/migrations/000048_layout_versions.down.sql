@@ -0,0 +1,2 @@
+-- Reverse 000048: drop layout version history.
+DROP TABLE IF EXISTS layout_versions;
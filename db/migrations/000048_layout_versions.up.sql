@@ -0,0 +1,19 @@
+-- Earlier versions of campaign dashboards and entity type layouts, so a
+-- botched editing session can be undone. Each save (or restore) records
+-- the layout it replaced; layout_json NULL means the built-in default was
+-- in use. target_id is the entity type ID for type layouts and category
+-- dashboards, '' for campaign-level dashboards. Only the newest few per
+-- (campaign, kind, target) are kept.
+CREATE TABLE IF NOT EXISTS layout_versions (
+  id          BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  campaign_id CHAR(36)     NOT NULL,
+  kind        VARCHAR(30)  NOT NULL,
+  target_id   VARCHAR(36)  NOT NULL DEFAULT '',
+  layout_json MEDIUMTEXT   DEFAULT NULL,
+  note        VARCHAR(100) NOT NULL DEFAULT '',
+  created_by  CHAR(36)     DEFAULT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY idx_layout_versions_target (campaign_id, kind, target_id, id),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
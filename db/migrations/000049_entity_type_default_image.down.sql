@@ -0,0 +1,2 @@
+-- Reverse 000049: drop per-type default images and avatar styles.
+ALTER TABLE entity_types DROP COLUMN IF EXISTS avatar_style, DROP COLUMN IF EXISTS default_image;
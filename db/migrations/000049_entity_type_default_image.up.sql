@@ -0,0 +1,8 @@
+-- Per-type fallbacks for pages without a header image of their own.
+-- default_image is a media file name like entities.image_path, shown in
+-- cards, previews and map marker popups. avatar_style picks how a page is
+-- drawn when neither it nor its type has an image: 'icon' (the type icon,
+-- the original look) or 'initials' (the page's initials on the type color).
+ALTER TABLE entity_types
+  ADD COLUMN IF NOT EXISTS default_image VARCHAR(500) NULL AFTER color,
+  ADD COLUMN IF NOT EXISTS avatar_style VARCHAR(20) NOT NULL DEFAULT 'icon' AFTER default_image;
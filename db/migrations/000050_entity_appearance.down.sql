@@ -0,0 +1,2 @@
+-- Reverse 000050: drop per-page appearance overrides.
+ALTER TABLE entities DROP COLUMN IF EXISTS appearance;
@@ -0,0 +1,5 @@
+-- Per-page look overrides, stored as JSON next to popup_config.
+-- {"color": "#rrggbb"} replaces the type color on the page's cards,
+-- breadcrumb and header; NULL keeps the type's. The banner shown on cards
+-- and the show-page header is the existing cover_image_path.
+ALTER TABLE entities ADD COLUMN IF NOT EXISTS appearance JSON DEFAULT NULL AFTER popup_config;
@@ -0,0 +1,3 @@
+-- Reverse 000051: drop page lifecycle states (index first, then column).
+ALTER TABLE entities DROP INDEX IF EXISTS idx_entities_campaign_status;
+ALTER TABLE entities DROP COLUMN IF EXISTS status;
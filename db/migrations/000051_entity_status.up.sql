@@ -0,0 +1,10 @@
+-- Lifecycle state for pages: 'draft' (visible only to its creator and the
+-- campaign owner), 'published' (normal visibility rules) or 'archived'
+-- (kept, but left out of lists and search unless asked for). Existing
+-- pages are published. The index backs the status filters and the
+-- dashboard's draft count.
+ALTER TABLE entities
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'published' AFTER is_template;
+
+ALTER TABLE entities
+    ADD INDEX IF NOT EXISTS idx_entities_campaign_status (campaign_id, status);
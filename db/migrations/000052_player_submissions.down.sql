@@ -0,0 +1,2 @@
+-- Reverse 000052: drop the per-type player submissions flag.
+ALTER TABLE entity_types DROP COLUMN IF EXISTS player_submissions;
@@ -0,0 +1,6 @@
+-- Entity types players may submit pages to (backstories, journal entries).
+-- A player's submission is created with entities.status = 'pending' and is
+-- shown to others only once a Scribe or the owner approves it. Off by
+-- default, so existing types stay Scribe-only.
+ALTER TABLE entity_types
+    ADD COLUMN IF NOT EXISTS player_submissions BOOLEAN NOT NULL DEFAULT FALSE AFTER claimable;
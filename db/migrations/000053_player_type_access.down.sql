@@ -0,0 +1,4 @@
+-- Reverse 000053: drop the per-type player co-editing flags.
+ALTER TABLE entity_types
+    DROP COLUMN IF EXISTS players_can_edit,
+    DROP COLUMN IF EXISTS players_can_create;
@@ -0,0 +1,7 @@
+-- Entity types players may co-edit. players_can_create lets Players create
+-- pages of the type (and edit the ones they created); players_can_edit lets
+-- them edit any page of the type they can see. Off by default, so existing
+-- types stay Scribe-only.
+ALTER TABLE entity_types
+    ADD COLUMN IF NOT EXISTS players_can_create BOOLEAN NOT NULL DEFAULT FALSE AFTER player_submissions,
+    ADD COLUMN IF NOT EXISTS players_can_edit BOOLEAN NOT NULL DEFAULT FALSE AFTER players_can_create;
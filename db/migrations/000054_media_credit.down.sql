@@ -0,0 +1,2 @@
+-- Reverse 000054: drop media credits.
+ALTER TABLE media_files DROP COLUMN IF EXISTS credit;
@@ -0,0 +1,5 @@
+-- Per-image credit ("Map by J. Doe, CC BY 4.0") for the media library,
+-- shown with the asset and carried into campaign exports. NULL means no
+-- credit is stated.
+ALTER TABLE media_files
+  ADD COLUMN IF NOT EXISTS credit VARCHAR(300) NULL AFTER caption;
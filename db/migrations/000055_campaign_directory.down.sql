@@ -0,0 +1,3 @@
+-- Reverse 000055: drop the campaign directory listing and tags.
+DROP TABLE IF EXISTS campaign_directory_tags;
+DROP TABLE IF EXISTS campaign_directory;
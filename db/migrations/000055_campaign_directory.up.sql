@@ -0,0 +1,20 @@
+-- Public campaign directory. Listing is an owner opt-in separate from
+-- is_public: a public campaign is readable by link, a listed one also
+-- shows on the discover page. Only campaigns that are both appear there.
+-- campaign_directory_tags holds the owner's genre/system tags, lowercase.
+CREATE TABLE IF NOT EXISTS campaign_directory (
+  campaign_id CHAR(36) NOT NULL PRIMARY KEY,
+  listed      BOOLEAN  NOT NULL DEFAULT FALSE,
+  updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  CONSTRAINT fk_campaign_directory_campaign FOREIGN KEY (campaign_id)
+    REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS campaign_directory_tags (
+  campaign_id CHAR(36)    NOT NULL,
+  tag         VARCHAR(30) NOT NULL,
+  PRIMARY KEY (campaign_id, tag),
+  KEY idx_campaign_directory_tags_tag (tag),
+  CONSTRAINT fk_campaign_directory_tag_campaign FOREIGN KEY (campaign_id)
+    REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Reverse 000056: drop the house rules, their revisions and acknowledgements.
+DROP TABLE IF EXISTS campaign_house_rules_acks;
+DROP TABLE IF EXISTS campaign_house_rules_revisions;
+DROP TABLE IF EXISTS campaign_house_rules;
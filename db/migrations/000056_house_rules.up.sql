@@ -0,0 +1,40 @@
+-- Per-campaign house rules: a markdown document the owner keeps apart from
+-- entities. Every save is a numbered revision (body is the markdown, body_html
+-- its sanitized rendering); campaign_house_rules points at the current one.
+-- When require_ack is on, members must acknowledge the rules; ack_version is
+-- the oldest revision an acknowledgement still counts for, bumped when the
+-- owner asks everyone to read a change.
+CREATE TABLE IF NOT EXISTS campaign_house_rules (
+  campaign_id CHAR(36) NOT NULL PRIMARY KEY,
+  version     INT      NOT NULL DEFAULT 0,
+  require_ack BOOLEAN  NOT NULL DEFAULT FALSE,
+  ack_version INT      NOT NULL DEFAULT 0,
+  updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  CONSTRAINT fk_campaign_house_rules_campaign FOREIGN KEY (campaign_id)
+    REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS campaign_house_rules_revisions (
+  campaign_id CHAR(36)     NOT NULL,
+  version     INT          NOT NULL,
+  body        MEDIUMTEXT   NOT NULL,
+  body_html   MEDIUMTEXT   NOT NULL,
+  note        VARCHAR(200) NOT NULL DEFAULT '',
+  edited_by   CHAR(36)     DEFAULT NULL,
+  created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, version),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- The newest revision each member has acknowledged.
+CREATE TABLE IF NOT EXISTS campaign_house_rules_acks (
+  campaign_id     CHAR(36) NOT NULL,
+  user_id         CHAR(36) NOT NULL,
+  version         INT      NOT NULL,
+  acknowledged_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, user_id),
+  KEY idx_campaign_house_rules_acks_user (user_id),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+-- Reverse 000057: drop the compendium import records.
+DROP TABLE IF EXISTS entity_content_imports;
@@ -0,0 +1,16 @@
+-- Pages created by the compendium import (Open5e search, 5eTools JSON), keyed
+-- by where they came from so a re-import finds the page it made last time
+-- instead of creating a duplicate. source and license record the publishing
+-- document and its licence as they were at import time.
+CREATE TABLE IF NOT EXISTS entity_content_imports (
+  campaign_id CHAR(36)     NOT NULL,
+  source_key  VARCHAR(255) NOT NULL,
+  entity_id   CHAR(36)     NOT NULL,
+  source      VARCHAR(255) NOT NULL DEFAULT '',
+  license     VARCHAR(255) NOT NULL DEFAULT '',
+  imported_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (campaign_id, source_key),
+  KEY idx_entity_content_imports_entity (entity_id),
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+  FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
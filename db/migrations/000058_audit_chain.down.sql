@@ -0,0 +1,5 @@
+-- Reverse 000058: drop the audit log hash chain.
+DROP TABLE IF EXISTS audit_chain_heads;
+ALTER TABLE audit_log
+  DROP COLUMN IF EXISTS entry_hash,
+  DROP COLUMN IF EXISTS prev_hash;
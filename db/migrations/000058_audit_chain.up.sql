@@ -0,0 +1,20 @@
+-- Tamper evidence for the audit log. Each campaign's entries form a hash
+-- chain: entry_hash is an HMAC over the entry and the previous entry's hash
+-- (prev_hash), so editing, deleting or reordering a row in the database
+-- breaks every link after it. Rows written before this migration keep empty
+-- hashes and are reported as unsealed.
+ALTER TABLE audit_log
+  ADD COLUMN IF NOT EXISTS prev_hash CHAR(64) NOT NULL DEFAULT '' AFTER created_at,
+  ADD COLUMN IF NOT EXISTS entry_hash CHAR(64) NOT NULL DEFAULT '' AFTER prev_hash;
+
+-- One row per campaign chain. head_* is the newest entry, which new entries
+-- link to and which catches deleted tail rows. anchor_* is the newest entry
+-- the retention job purged; the oldest surviving entry links to it.
+CREATE TABLE IF NOT EXISTS audit_chain_heads (
+  campaign_id CHAR(36) NOT NULL PRIMARY KEY,
+  head_id     BIGINT   NOT NULL DEFAULT 0,
+  head_hash   CHAR(64) NOT NULL DEFAULT '',
+  anchor_id   BIGINT   NOT NULL DEFAULT 0,
+  anchor_hash CHAR(64) NOT NULL DEFAULT '',
+  FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,244 @@
+// Package a11y runs accessibility checks on owner-built layouts: images
+// without alt text and empty headings in rich-text blocks, and entity type
+// colors that don't stand out from the page. Findings are warnings, never
+// errors -- the save always goes through and the layout editor shows what
+// to fix. Like timeutil, it imports no Chronicle plugin packages so the
+// entities and campaigns plugins can both call it.
+package a11y
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Warning codes, stable for the client to key help text on.
+const (
+	CodeImageMissingAlt = "image_missing_alt"
+	CodeEmptyHeading    = "empty_heading"
+	CodeLowContrast     = "low_contrast"
+)
+
+// Warning is one accessibility finding in a saved layout.
+type Warning struct {
+	Code    string `json:"code"`
+	BlockID string `json:"block_id,omitempty"`
+	Message string `json:"message"`
+}
+
+// MinColorContrast is the WCAG 2.1 ratio for large text and UI components
+// (1.4.3 / 1.4.11). Type colors tint badges and icons rather than body
+// text, so the 3:1 bar applies, not 4.5:1.
+const MinColorContrast = 3.0
+
+// themeBackgrounds are the card backgrounds type colors are drawn on
+// (--color-card-bg in static/css/input.css, light and dark theme).
+var themeBackgrounds = []struct {
+	name string
+	hex  string
+}{
+	{"light", "#ffffff"},
+	{"dark", "#1f2937"},
+}
+
+// CheckHTML reports images without an alt attribute and headings with no
+// text in a rich-text block. alt="" is left alone: it is how an author
+// marks an image as decorative.
+func CheckHTML(blockID, content string) []Warning {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(content),
+		&html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return nil
+	}
+
+	missingAlt, emptyHeadings := 0, 0
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Img:
+				if _, ok := attr(n, "alt"); !ok {
+					missingAlt++
+				}
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				if !hasAccessibleText(n) {
+					emptyHeadings++
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	var out []Warning
+	if missingAlt > 0 {
+		out = append(out, Warning{Code: CodeImageMissingAlt, BlockID: blockID,
+			Message: plural(missingAlt, "image has", "images have") + " no alt text; screen readers can't describe them"})
+	}
+	if emptyHeadings > 0 {
+		out = append(out, Warning{Code: CodeEmptyHeading, BlockID: blockID,
+			Message: plural(emptyHeadings, "heading is", "headings are") + " empty; screen reader users navigate by headings"})
+	}
+	return out
+}
+
+// CheckBlock audits one layout block and, for containers, the blocks
+// nested in its config. Page templates and dashboards share the block
+// config schema (both are built in layout_editor.js), so the walk is the
+// same for both; block types without text of their own are skipped --
+// image blocks take their alt text from the entity name.
+func CheckBlock(id, blockType string, config map[string]any) []Warning {
+	var out []Warning
+	switch blockType {
+	case "text_block":
+		content, _ := config["content"].(string)
+		out = append(out, CheckHTML(id, content)...)
+	case "section":
+		if title, _ := config["title"].(string); strings.TrimSpace(title) == "" {
+			out = append(out, emptyHeading(id, "A section"))
+		}
+		out = append(out, checkSubBlocks(config["blocks"])...)
+	case "two_column":
+		out = append(out, checkSubBlocks(config["left"])...)
+		out = append(out, checkSubBlocks(config["right"])...)
+	case "three_column":
+		cols, _ := config["columns"].([]any)
+		for _, col := range cols {
+			out = append(out, checkSubBlocks(col)...)
+		}
+	case "tabs":
+		tabs, _ := config["tabs"].([]any)
+		for _, t := range tabs {
+			tab, _ := t.(map[string]any)
+			if label, _ := tab["label"].(string); strings.TrimSpace(label) == "" {
+				out = append(out, emptyHeading(id, "A tab"))
+			}
+			out = append(out, checkSubBlocks(tab["blocks"])...)
+		}
+	}
+	return out
+}
+
+// checkSubBlocks audits a container's decoded JSON block list.
+func checkSubBlocks(v any) []Warning {
+	list, _ := v.([]any)
+	var out []Warning
+	for _, item := range list {
+		b, _ := item.(map[string]any)
+		id, _ := b["id"].(string)
+		blockType, _ := b["type"].(string)
+		config, _ := b["config"].(map[string]any)
+		out = append(out, CheckBlock(id, blockType, config)...)
+	}
+	return out
+}
+
+// emptyHeading is the warning for a section title or tab label left
+// blank, which renders as an unlabeled heading or tab.
+func emptyHeading(blockID, what string) Warning {
+	return Warning{Code: CodeEmptyHeading, BlockID: blockID,
+		Message: what + " has no title; screen reader users navigate by headings"}
+}
+
+// CheckColor warns when a type color falls below MinColorContrast against
+// either theme's card background. Colors that don't parse are skipped;
+// the color endpoint validates them separately.
+func CheckColor(label, hexColor string) *Warning {
+	for _, bg := range themeBackgrounds {
+		ratio, ok := ContrastRatio(hexColor, bg.hex)
+		if !ok {
+			return nil
+		}
+		if ratio < MinColorContrast {
+			return &Warning{Code: CodeLowContrast, Message: fmt.Sprintf(
+				"%s color %s has a contrast ratio of %.1f:1 on the %s theme (at least %.0f:1 recommended); badges and icons in it are hard to read",
+				label, hexColor, ratio, bg.name, MinColorContrast)}
+		}
+	}
+	return nil
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two #rgb/#rrggbb
+// colors, from 1 (identical) to 21 (black on white).
+func ContrastRatio(a, b string) (float64, bool) {
+	la, ok := relativeLuminance(a)
+	if !ok {
+		return 0, false
+	}
+	lb, ok := relativeLuminance(b)
+	if !ok {
+		return 0, false
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05), true
+}
+
+// relativeLuminance implements the WCAG 2.1 definition for sRGB.
+func relativeLuminance(hexColor string) (float64, bool) {
+	h := strings.TrimPrefix(strings.TrimSpace(hexColor), "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	channel := func(c uint64) float64 {
+		s := float64(c) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	r, g, b := channel(v>>16&0xff), channel(v>>8&0xff), channel(v&0xff)
+	return 0.2126*r + 0.7152*g + 0.0722*b, true
+}
+
+// hasAccessibleText reports whether a heading has text or a labeled image.
+func hasAccessibleText(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) != "":
+			return true
+		case c.Type == html.ElementNode && c.DataAtom == atom.Img:
+			if alt, _ := attr(c, "alt"); strings.TrimSpace(alt) != "" {
+				return true
+			}
+		case c.Type == html.ElementNode && hasAccessibleText(c):
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
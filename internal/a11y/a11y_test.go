@@ -0,0 +1,87 @@
+package a11y
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#fff", "#ffffff", 1},
+		{"#777777", "#ffffff", 4.48},
+	}
+	for _, tc := range cases {
+		got, ok := ContrastRatio(tc.a, tc.b)
+		if !ok || math.Abs(got-tc.want) > 0.01 {
+			t.Errorf("ContrastRatio(%s, %s) = %.2f, %v; want %.2f", tc.a, tc.b, got, ok, tc.want)
+		}
+	}
+	if _, ok := ContrastRatio("red", "#ffffff"); ok {
+		t.Error("named colors should not parse")
+	}
+}
+
+func TestCheckColor(t *testing.T) {
+	cases := []struct {
+		color string
+		warn  bool
+	}{
+		{"#6366f1", false}, // indigo: readable on both themes
+		{"#f59e0b", true},  // amber: too light on white
+		{"#111827", true},  // near-black: disappears on the dark theme
+		{"not-a-color", false},
+	}
+	for _, tc := range cases {
+		w := CheckColor("The NPC type", tc.color)
+		if (w != nil) != tc.warn {
+			t.Errorf("CheckColor(%s) = %+v, want warning %v", tc.color, w, tc.warn)
+		}
+		if w != nil && w.Code != CodeLowContrast {
+			t.Errorf("CheckColor(%s) code = %q", tc.color, w.Code)
+		}
+	}
+}
+
+func TestCheckBlock(t *testing.T) {
+	text := func(id, content string) map[string]any {
+		return map[string]any{"id": id, "type": "text_block", "config": map[string]any{"content": content}}
+	}
+	cases := []struct {
+		name      string
+		blockType string
+		config    map[string]any
+		want      []string // codes, in order
+	}{
+		{"clean text", "text_block", map[string]any{"content": `<h2>Lore</h2><img src="a.png" alt="A map">`}, nil},
+		{"decorative image", "text_block", map[string]any{"content": `<img src="rule.png" alt="">`}, nil},
+		{"missing alt", "text_block", map[string]any{"content": `<img src="a.png"><img src="b.png">`}, []string{CodeImageMissingAlt}},
+		{"empty heading", "text_block", map[string]any{"content": `<h3>  </h3><p>body</p>`}, []string{CodeEmptyHeading}},
+		{"heading with labeled image", "text_block", map[string]any{"content": `<h2><img src="x.png" alt="Crest"></h2>`}, nil},
+		{"untitled section", "section", map[string]any{"title": "", "blocks": []any{text("t1", `<img src="a.png">`)}},
+			[]string{CodeEmptyHeading, CodeImageMissingAlt}},
+		{"columns", "three_column", map[string]any{"columns": []any{[]any{}, []any{text("t2", "<h1></h1>")}, []any{}}},
+			[]string{CodeEmptyHeading}},
+		{"unlabeled tab", "tabs", map[string]any{"tabs": []any{
+			map[string]any{"label": "Stats", "blocks": []any{}},
+			map[string]any{"label": " ", "blocks": []any{text("t3", "<p>ok</p>")}},
+		}}, []string{CodeEmptyHeading}},
+		{"non-text block", "image", map[string]any{}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := CheckBlock("b1", tc.blockType, tc.config)
+			if len(got) != len(tc.want) {
+				t.Fatalf("warnings = %+v, want codes %v", got, tc.want)
+			}
+			for i, w := range got {
+				if w.Code != tc.want[i] {
+					t.Errorf("warning %d code = %q, want %q", i, w.Code, tc.want[i])
+				}
+			}
+		})
+	}
+}
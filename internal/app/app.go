@@ -25,6 +25,7 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/packages"
 	"github.com/keyxmakerx/chronicle/internal/plugins/settings"
+	"github.com/keyxmakerx/chronicle/internal/routereg"
 	"github.com/keyxmakerx/chronicle/internal/templates/pages"
 )
 
@@ -51,6 +52,13 @@ type App struct {
 	// Set during route registration; nil until then.
 	WASMHookDispatcher *extensions.HookDispatcher
 
+	// QueryStats times every query on DB, per request and per route.
+	QueryStats *database.QueryStats
+
+	// DBRouter sends opted-in read-only queries to read replicas when
+	// DB_READ_REPLICAS is set; without replicas it always returns DB.
+	DBRouter *database.DBRouter
+
 	// PluginHealth tracks which built-in plugins have healthy schemas.
 	// Used during route registration to skip degraded plugins.
 	PluginHealth *database.PluginHealthRegistry
@@ -68,12 +76,19 @@ type App struct {
 	// setup point. Per cordinator/decisions/2026-05-23-plugin-registration.md
 	// + NW-2.2 Chunk A.
 	registeredPlugins []PluginRegistration
+
+	// Routes records every route as it's wired, catching two plugins
+	// registering the same path and reserving top-level campaign segments
+	// so entity type slugs can't shadow plugin pages.
+	Routes *routereg.Registry
 }
 
 // New creates a new App instance with the given dependencies and configures
 // the Echo server with global middleware and error handling.
-func New(cfg *config.Config, db *sql.DB, rdb *redis.Client, pluginHealth *database.PluginHealthRegistry, pluginSchemas []database.PluginSchema) *App {
+func New(cfg *config.Config, db *sql.DB, rdb *redis.Client, queryStats *database.QueryStats, dbRouter *database.DBRouter, pluginHealth *database.PluginHealthRegistry, pluginSchemas []database.PluginSchema) *App {
 	e := echo.New()
+	routes := routereg.New()
+	routes.Attach(e)
 
 	// Disable Echo's default banner and startup message -- we log our own.
 	e.HideBanner = true
@@ -95,10 +110,17 @@ func New(cfg *config.Config, db *sql.DB, rdb *redis.Client, pluginHealth *databa
 		DB:           db,
 		Redis:        rdb,
 		Echo:         e,
+		QueryStats:   queryStats,
+		DBRouter:     dbRouter,
 		PluginHealth:  pluginHealth,
 		PluginSchemas: pluginSchemas,
+		Routes:        routes,
 	}
 
+	// An admin-set base URL (runtime settings) beats BASE_URL. Applied before
+	// anything copies Config.BaseURL, which is why it needs a restart.
+	app.applyBaseURLOverride()
+
 	// Register global middleware in order of execution.
 	app.setupMiddleware()
 
@@ -111,29 +133,71 @@ func New(cfg *config.Config, db *sql.DB, rdb *redis.Client, pluginHealth *databa
 	return app
 }
 
+// applyBaseURLOverride replaces Config.BaseURL with the stored runtime
+// setting, if one is set and valid. Failures keep the env value.
+func (a *App) applyBaseURLOverride() {
+	if a.DB == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	raw, err := settings.NewSettingsRepository(a.DB).Get(ctx, settings.KeyBaseURL)
+	if err != nil {
+		return
+	}
+	baseURL, err := settings.NormalizeBaseURL(raw)
+	if err != nil {
+		slog.Warn("ignoring invalid stored base URL", slog.String("value", raw), slog.Any("error", err))
+		return
+	}
+	if baseURL != "" && baseURL != a.Config.BaseURL {
+		slog.Info("base URL overridden by runtime settings",
+			slog.String("env", a.Config.BaseURL), slog.String("active", baseURL))
+		a.Config.BaseURL = baseURL
+	}
+}
+
 // setupMiddleware registers global middleware on the Echo instance.
 // Order matters: outermost (recovery) runs first, innermost (CSRF) runs last.
 func (a *App) setupMiddleware() {
-	// Panic recovery -- must be outermost to catch panics from all other middleware.
+	// Cluster mode: every rate limiter counts in Redis so the instances
+	// behind the load balancer share one budget per client.
+	if a.Config.ClusterMode {
+		middleware.UseSharedRateLimits(a.Redis)
+	}
+
+	// Request logging -- assigns the request ID and logs every request with
+	// method, path, status, latency, user and campaign. Outermost so the
+	// panic and error logs below carry the request ID.
+	a.Echo.Use(middleware.RequestLogger())
+
+	// Panic recovery -- wraps everything else to catch panics from all other middleware.
 	a.Echo.Use(middleware.Recovery())
 
+	// Per-request query count and DB time, attributed to the matched route.
+	a.Echo.Use(middleware.QueryStats(a.QueryStats))
+
 	// Global request body size limit -- prevents memory exhaustion from
 	// oversized payloads on non-upload endpoints. The media upload endpoint
 	// has its own per-route body limit based on the configured max upload size,
-	// so we skip this global limit for that path.
+	// as do the bulk image ZIP upload and the email-in webhook, so we skip this
+	// global limit for those.
 	a.Echo.Use(echomw.BodyLimitWithConfig(echomw.BodyLimitConfig{
 		Limit: "2M",
 		Skipper: func(c echo.Context) bool {
 			path := c.Request().URL.Path
-			return strings.HasPrefix(path, "/media/upload") || path == "/ws"
+			return strings.HasPrefix(path, "/media/upload") || path == "/ws" ||
+				strings.Contains(path, "/entities/bulk-images/") ||
+				strings.HasPrefix(path, "/api/inbound/email")
 		},
 	}))
 
-	// Request logging -- log every request with method, path, status, latency.
-	a.Echo.Use(middleware.RequestLogger())
-
 	// Security headers -- CSP, X-Frame-Options, X-Content-Type-Options, etc.
-	a.Echo.Use(middleware.SecurityHeaders())
+	a.Echo.Use(middleware.SecurityHeaders(middleware.SecurityConfig{
+		CSPMode:      a.Config.Security.CSPMode,
+		CSPReportURI: a.Config.Security.CSPReportURI,
+		HSTS:         a.Config.Security.HSTS,
+	}))
 
 	// CORS -- allow cross-origin requests for the REST API.
 	// Only relevant for external clients (Foundry VTT module, etc.).
@@ -201,7 +265,7 @@ func (a *App) errorHandler(err error, c echo.Context) {
 	// Previously, AppError with Internal==nil and echo.HTTPError from
 	// panic recovery were both swallowed with no log output.
 	if code >= http.StatusInternalServerError {
-		slog.Error("server error",
+		slog.ErrorContext(c.Request().Context(), "server error",
 			slog.Int("code", code),
 			slog.String("message", message),
 			slog.Any("error", err),
@@ -0,0 +1,169 @@
+// Package app — digest_adapters.go builds the weekly campaign digest's
+// cross-plugin sections. Each returns a campaigns.DigestItemsFunc over one
+// plugin's data, so the campaigns plugin never imports them.
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/plugins/sessions"
+)
+
+// digestAuditScanLimit bounds the audit rows read per digest; a busy week
+// has many edits to the same few pages.
+const digestAuditScanLimit = 500
+
+// digestPageItems lists pages created or updated during the week, from the
+// audit log. Only pages every member can read make the digest: private and
+// custom-visibility entities are left out, as are deleted ones.
+func digestPageItems(auditRepo audit.AuditRepository, entitySvc entities.EntityService) campaigns.DigestItemsFunc {
+	return func(ctx context.Context, campaignID, _ string, w campaigns.DigestWindow) ([]campaigns.DigestItem, error) {
+		entries, err := auditRepo.ListEntityChangesSince(ctx, campaignID, w.Since, digestAuditScanLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		// Entries are newest first; collapse them to one line per page,
+		// "new" if it was created inside the window.
+		var order []string
+		created := make(map[string]bool)
+		for _, e := range entries {
+			if e.EntityID == "" {
+				continue
+			}
+			if _, seen := created[e.EntityID]; !seen {
+				order = append(order, e.EntityID)
+				created[e.EntityID] = false
+			}
+			if e.Action == audit.ActionEntityCreated {
+				created[e.EntityID] = true
+			}
+		}
+
+		var items []campaigns.DigestItem
+		for _, id := range order {
+			ent, err := entitySvc.GetByID(ctx, id)
+			if err != nil || ent == nil || ent.CampaignID != campaignID {
+				continue
+			}
+			if ent.IsPrivate || ent.Visibility == entities.VisibilityCustom || ent.IsTemplate {
+				continue
+			}
+			note := "updated"
+			if created[id] {
+				note = "new"
+			}
+			items = append(items, campaigns.DigestItem{
+				Text: ent.Name,
+				Note: note,
+				Link: fmt.Sprintf("/campaigns/%s/entities/%s", campaignID, ent.ID),
+			})
+		}
+		return items, nil
+	}
+}
+
+// digestEventItems lists the member's upcoming calendar events, the same
+// player-visible set the My events page shows.
+func digestEventItems(calendarSvc calendar.CalendarService) campaigns.DigestItemsFunc {
+	return func(ctx context.Context, campaignID, userID string, _ campaigns.DigestWindow) ([]campaigns.DigestItem, error) {
+		cals, err := calendarSvc.MyUpcomingEvents(ctx, userID, []calendar.MyEventsCampaign{{ID: campaignID}})
+		if err != nil {
+			return nil, err
+		}
+		var items []campaigns.DigestItem
+		for _, mc := range cals {
+			for _, ev := range mc.Events {
+				items = append(items, campaigns.DigestItem{
+					Text: ev.Name,
+					Note: digestEventDate(mc.Calendar, ev),
+					Link: fmt.Sprintf("/campaigns/%s/calendars/%s?year=%d&month=%d", campaignID, mc.Calendar.ID, ev.Year, ev.Month),
+				})
+			}
+		}
+		return items, nil
+	}
+}
+
+// digestEventDate formats an event's date with the calendar's month names,
+// e.g. "3 Mirtul 1492 DR".
+func digestEventDate(cal *calendar.Calendar, ev calendar.Event) string {
+	monthName := fmt.Sprintf("Month %d", ev.Month)
+	if ev.Month >= 1 && ev.Month <= len(cal.Months) {
+		monthName = cal.Months[ev.Month-1].Name
+	}
+	s := fmt.Sprintf("%d %s %d", ev.Day, monthName, ev.Year)
+	if cal.EpochName != nil && *cal.EpochName != "" {
+		s += " " + *cal.EpochName
+	}
+	return s
+}
+
+// digestSessionItems lists planned sessions scheduled in the coming week.
+// Scheduled dates are zone-less, so the window is compared by UTC date.
+func digestSessionItems(sessionsSvc sessions.SessionService) campaigns.DigestItemsFunc {
+	return func(ctx context.Context, campaignID, _ string, w campaigns.DigestWindow) ([]campaigns.DigestItem, error) {
+		planned, err := sessionsSvc.ListPlannedSessions(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		from, to := w.Now.UTC().Format("2006-01-02"), w.Until.UTC().Format("2006-01-02")
+		var items []campaigns.DigestItem
+		for _, sess := range planned {
+			if sess.ScheduledDate == nil || *sess.ScheduledDate < from || *sess.ScheduledDate > to {
+				continue
+			}
+			note := *sess.ScheduledDate
+			if d, err := time.Parse("2006-01-02", *sess.ScheduledDate); err == nil {
+				note = d.Format("Mon Jan 2")
+			}
+			if sess.ScheduledTime != nil {
+				note += " at " + *sess.ScheduledTime
+			}
+			items = append(items, campaigns.DigestItem{
+				Text: sess.Name,
+				Note: note,
+				Link: fmt.Sprintf("/campaigns/%s/sessions/%s", campaignID, sess.ID),
+			})
+		}
+		return items, nil
+	}
+}
+
+// digestNotificationLimit is how far back in the member's notification list
+// the digest looks for this campaign's unread ones.
+const digestNotificationLimit = 100
+
+// digestNotificationItems lists the member's unread notifications from this
+// campaign during the week, so a digest also catches what they missed in
+// the bell.
+func digestNotificationItems(sessionsSvc sessions.SessionService) campaigns.DigestItemsFunc {
+	return func(ctx context.Context, campaignID, userID string, w campaigns.DigestWindow) ([]campaigns.DigestItem, error) {
+		list, err := sessionsSvc.ListMyNotifications(ctx, userID, digestNotificationLimit)
+		if err != nil {
+			return nil, err
+		}
+		var items []campaigns.DigestItem
+		for _, n := range list {
+			// Announcements already have their own section.
+			if n.Type == campaigns.NotifAnnouncement {
+				continue
+			}
+			if n.ReadAt != nil || n.CampaignID == nil || *n.CampaignID != campaignID || n.CreatedAt.Before(w.Since) {
+				continue
+			}
+			item := campaigns.DigestItem{Text: n.Message(), Note: n.CreatedAt.Format("Jan 2")}
+			if n.Link != nil {
+				item.Link = *n.Link
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+}
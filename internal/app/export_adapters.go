@@ -110,6 +110,10 @@ func (a *entityExportAdapter) ExportEntities(ctx context.Context, campaignID str
 			if e.PopupConfig != nil {
 				popupConfig, _ = json.Marshal(e.PopupConfig)
 			}
+			var appearance json.RawMessage
+			if e.Appearance != nil {
+				appearance, _ = json.Marshal(e.Appearance)
+			}
 
 			exportEntity := campaigns.ExportEntity{
 				OriginalID:     e.ID,
@@ -126,6 +130,7 @@ func (a *entityExportAdapter) ExportEntities(ctx context.Context, campaignID str
 				FieldsData:     fieldsData,
 				FieldOverrides: fieldOverrides,
 				PopupConfig:    popupConfig,
+				Appearance:     appearance,
 			}
 
 			// Export per-entity permissions when visibility is custom.
@@ -270,6 +275,7 @@ func (a *calendarExportAdapter) ExportCalendar(ctx context.Context, campaignID s
 		data.Months = append(data.Months, campaigns.ExportCalendarMonth{
 			Name: m.Name, Days: m.Days, SortOrder: m.SortOrder,
 			IsIntercalary: m.IsIntercalary, LeapYearDays: m.LeapYearDays,
+			StartingWeekday: m.StartingWeekday, SkipsWeekday: m.SkipsWeekday,
 		})
 	}
 	for _, w := range cal.Weekdays {
@@ -295,6 +301,11 @@ func (a *calendarExportAdapter) ExportCalendar(ctx context.Context, campaignID s
 			Description: e.Description, Color: e.Color, SortOrder: e.SortOrder,
 		})
 	}
+	for _, yn := range cal.YearNames {
+		data.YearNames = append(data.YearNames, campaigns.ExportCalendarYearName{
+			Year: yn.Year, Name: yn.Name,
+		})
+	}
 
 	// Event categories.
 	cats, err := a.svc.GetEventCategories(ctx, cal.ID)
@@ -733,6 +744,7 @@ func (a *mediaExportAdapter) ExportMedia(ctx context.Context, campaignID string)
 				MimeType:     f.MimeType,
 				FileSize:     f.FileSize,
 				UsageType:    f.UsageType,
+				Credit:       f.Credit,
 			})
 		}
 		page++
@@ -754,6 +766,8 @@ type entityImportAdapter struct {
 // import data. Returns an IDMap for cross-referencing by other importers.
 func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, userID string, data *campaigns.ExportEntityData) (*campaigns.IDMap, error) {
 	idMap := campaigns.NewIDMap(campaignID)
+	idMap.OriginalCampaignID = data.OriginalCampaignID
+	summary := idMap.Summary
 
 	// 1. Create entity types.
 	typeSlugToNewID := make(map[string]int)
@@ -767,8 +781,10 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 		})
 		if err != nil {
 			slog.Warn("import: create entity type failed", slog.String("slug", et.Slug), slog.Any("error", err))
+			summary.Skip(campaigns.ImportKindEntityTypes, et.Name, "could not be created")
 			continue
 		}
+		summary.Created(campaigns.ImportKindEntityTypes)
 
 		idMap.EntityTypeIDs[et.OriginalID] = newType.ID
 		typeSlugToNewID[et.Slug] = newType.ID
@@ -812,6 +828,7 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 		typeID, ok := typeSlugToNewID[e.EntityTypeSlug]
 		if !ok {
 			slog.Warn("import: unknown entity type", slog.String("slug", e.EntityTypeSlug))
+			summary.Skip(campaigns.ImportKindEntities, e.Name, "its category wasn't imported")
 			continue
 		}
 
@@ -829,15 +846,18 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 		})
 		if err != nil {
 			slog.Warn("import: create entity failed", slog.String("name", e.Name), slog.Any("error", err))
+			summary.Skip(campaigns.ImportKindEntities, e.Name, "could not be created")
 			continue
 		}
+		summary.Created(campaigns.ImportKindEntities)
 
 		idMap.EntityIDs[e.OriginalID] = newEntity.ID
 		idMap.EntitySlugToID[e.Slug] = newEntity.ID
 		entitySlugToNewID[e.Slug] = newEntity.ID
 
-		// Apply entry content and image via Update.
-		if e.Entry != nil || e.ImagePath != nil {
+		// Apply the image via Update. Entry content waits for step 2c,
+		// when every mention target has its new ID.
+		if e.ImagePath != nil {
 			// Import carries the source row's is_private; pass through
 			// as a pointer so the nil-preserving service layer writes it.
 			isPrivate := e.IsPrivate
@@ -845,12 +865,11 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 				Name:       e.Name,
 				TypeLabel:  ptrString(e.TypeLabel),
 				IsPrivate:  &isPrivate,
-				Entry:      ptrString(e.Entry),
 				ImagePath:  ptrString(e.ImagePath),
 				FieldsData: fieldsData,
 			})
 			if updateErr != nil {
-				slog.Warn("import: update entity entry/image failed", slog.String("entity", e.Name), slog.Any("error", updateErr))
+				slog.Warn("import: update entity image failed", slog.String("entity", e.Name), slog.Any("error", updateErr))
 			}
 		}
 
@@ -874,6 +893,16 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 			}
 		}
 
+		// Apply appearance overrides.
+		if len(e.Appearance) > 0 {
+			var appearance entities.EntityAppearance
+			if err := json.Unmarshal(e.Appearance, &appearance); err == nil {
+				if err := a.entitySvc.UpdateAppearance(ctx, newEntity.ID, &appearance); err != nil {
+					slog.Warn("import: apply appearance failed", slog.String("entity", e.Name), slog.Any("error", err))
+				}
+			}
+		}
+
 		// Apply entity permissions and visibility mode.
 		if e.Visibility == string(entities.VisibilityCustom) && len(e.Permissions) > 0 {
 			grants := make([]entities.PermissionGrant, 0, len(e.Permissions))
@@ -906,6 +935,7 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 		parentNewID, ok := entitySlugToNewID[*e.ParentSlug]
 		if !ok {
 			slog.Warn("import: parent entity not found", slog.String("entity", e.Name), slog.String("parent_slug", *e.ParentSlug))
+			summary.Warn("%s was imported without its parent, which wasn't imported", e.Name)
 			continue
 		}
 
@@ -921,12 +951,29 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 			TypeLabel:  ptrString(e.TypeLabel),
 			ParentID:   parentNewID,
 			IsPrivate:  &isPrivate,
-			Entry:      ptrString(e.Entry),
 			ImagePath:  ptrString(e.ImagePath),
 			FieldsData: fieldsData,
 		})
 		if err != nil {
 			slog.Warn("import: set parent failed", slog.String("entity", e.Name), slog.Any("error", err))
+			summary.Warn("%s was imported without its parent", e.Name)
+		}
+	}
+
+	// 2c. Write entry content with mentions pointing at the new entities.
+	for _, e := range data.Entities {
+		if e.Entry == nil || *e.Entry == "" {
+			continue
+		}
+		entityNewID, ok := entitySlugToNewID[e.Slug]
+		if !ok {
+			continue
+		}
+		entryJSON := idMap.RemapReferences(*e.Entry)
+		entryHTML := idMap.RemapReferences(ptrString(e.EntryHTML))
+		if err := a.entitySvc.UpdateEntry(ctx, entityNewID, entryJSON, entryHTML); err != nil {
+			slog.Warn("import: update entity entry failed", slog.String("entity", e.Name), slog.Any("error", err))
+			summary.Warn("%s was imported without its entry", e.Name)
 		}
 	}
 
@@ -936,8 +983,10 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 		newTag, err := a.tagSvc.Create(ctx, campaignID, t.Name, t.Color, t.DmOnly)
 		if err != nil {
 			slog.Warn("import: create tag failed", slog.String("name", t.Name), slog.Any("error", err))
+			summary.Skip(campaigns.ImportKindTags, t.Name, "could not be created")
 			continue
 		}
+		summary.Created(campaigns.ImportKindTags)
 		idMap.TagIDs[t.OriginalID] = newTag.ID
 		idMap.TagSlugToID[t.Slug] = newTag.ID
 		tagSlugToNewID[t.Slug] = newTag.ID
@@ -969,12 +1018,15 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 
 	// 5. Create relations.
 	for _, r := range data.Relations {
+		name := r.SourceEntitySlug + " → " + r.TargetEntitySlug
 		sourceID, ok := entitySlugToNewID[r.SourceEntitySlug]
 		if !ok {
+			summary.Skip(campaigns.ImportKindRelations, name, "an entity it links wasn't imported")
 			continue
 		}
 		targetID, ok := entitySlugToNewID[r.TargetEntitySlug]
 		if !ok {
+			summary.Skip(campaigns.ImportKindRelations, name, "an entity it links wasn't imported")
 			continue
 		}
 		_, err := a.relationSvc.Create(ctx, campaignID, sourceID, targetID,
@@ -984,7 +1036,10 @@ func (a *entityImportAdapter) ImportEntities(ctx context.Context, campaignID, us
 				slog.String("source", r.SourceEntitySlug),
 				slog.String("target", r.TargetEntitySlug),
 				slog.Any("error", err))
+			summary.Skip(campaigns.ImportKindRelations, name, "could not be created")
+			continue
 		}
+		summary.Created(campaigns.ImportKindRelations)
 	}
 
 	return idMap, nil
@@ -1003,6 +1058,7 @@ func (a *calendarImportAdapter) ImportCalendar(ctx context.Context, campaignID s
 		months[i] = calendar.MonthInput{
 			Name: m.Name, Days: m.Days, SortOrder: m.SortOrder,
 			IsIntercalary: m.IsIntercalary, LeapYearDays: m.LeapYearDays,
+			StartingWeekday: m.StartingWeekday, SkipsWeekday: m.SkipsWeekday,
 		}
 	}
 
@@ -1056,6 +1112,15 @@ func (a *calendarImportAdapter) ImportCalendar(ctx context.Context, campaignID s
 		_ = a.svc.SetEras(ctx, cal.ID, eras)
 	}
 
+	// Named years.
+	if len(data.YearNames) > 0 {
+		names := make([]calendar.YearNameInput, len(data.YearNames))
+		for i, yn := range data.YearNames {
+			names[i] = calendar.YearNameInput{Year: yn.Year, Name: yn.Name}
+		}
+		_ = a.svc.SetYearNames(ctx, cal.ID, names)
+	}
+
 	// Event categories.
 	if len(data.EventCategories) > 0 {
 		cats := make([]calendar.EventCategoryInput, len(data.EventCategories))
@@ -1090,10 +1155,11 @@ func (a *calendarImportAdapter) ImportCalendar(ctx context.Context, campaignID s
 				entityID = &id
 			}
 		}
+		desc, descHTML := remapOptional(idMap, evt.Description), remapOptional(idMap, evt.DescriptionHTML)
 		_, err := a.svc.CreateEvent(ctx, cal.ID, calendar.CreateEventInput{
 			Name:           evt.Name,
-			Description:    evt.Description,
-			DescriptionHTML: evt.DescriptionHTML,
+			Description:    desc,
+			DescriptionHTML: descHTML,
 			EntityID:       entityID,
 			Year:           evt.Year,
 			Month:          evt.Month,
@@ -1112,12 +1178,24 @@ func (a *calendarImportAdapter) ImportCalendar(ctx context.Context, campaignID s
 		})
 		if err != nil {
 			slog.Warn("import: create calendar event failed", slog.String("name", evt.Name), slog.Any("error", err))
+			idMap.Summary.Skip(campaigns.ImportKindCalendarEvents, evt.Name, "could not be created")
+			continue
 		}
+		idMap.Summary.Created(campaigns.ImportKindCalendarEvents)
 	}
 
 	return nil
 }
 
+// remapOptional rewrites exported IDs in optional content; nil stays nil.
+func remapOptional(idMap *campaigns.IDMap, content *string) *string {
+	if content == nil {
+		return nil
+	}
+	remapped := idMap.RemapReferences(*content)
+	return &remapped
+}
+
 // sessionImportAdapter implements campaigns.SessionImporter.
 type sessionImportAdapter struct {
 	svc sessions.SessionService
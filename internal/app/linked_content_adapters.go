@@ -0,0 +1,116 @@
+// Package app — linked_content_adapters.go builds the entity page's
+// "Appears in" providers. Each wraps one plugin's reverse lookup (records
+// pointing at an entity) as an entities.LinkedContentProvider, so the
+// entities plugin never imports them.
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/plugins/maps"
+	"github.com/keyxmakerx/chronicle/internal/plugins/sessions"
+)
+
+// linkedEventProvider lists the calendar events an entity is tied to
+// (attendee, location, ...). Visibility is applied by the calendar.
+func linkedEventProvider(calendarSvc calendar.CalendarService) entities.LinkedContentProvider {
+	return entities.LinkedContentProvider{
+		ID:        "calendar-events",
+		Label:     "Calendar events",
+		Icon:      "fa-calendar",
+		Color:     "#f59e0b",
+		Addon:     "calendar",
+		SortOrder: 10,
+		List: func(ctx context.Context, campaignID, entityID string, role int, userID string) ([]entities.LinkedItem, error) {
+			events, err := calendarSvc.EntityEventBacklinks(ctx, campaignID, entityID, role, userID)
+			if err != nil {
+				return nil, err
+			}
+			items := make([]entities.LinkedItem, 0, len(events))
+			for _, ev := range events {
+				items = append(items, entities.LinkedItem{
+					Name: ev["name"],
+					URL:  ev["url"],
+					Meta: joinMeta(ev["date"], ev["role"], ev["calendar_name"]),
+				})
+			}
+			return items, nil
+		},
+	}
+}
+
+// linkedMarkerProvider lists the map markers pinned to an entity that the
+// viewer may see.
+func linkedMarkerProvider(mapsSvc maps.MapService) entities.LinkedContentProvider {
+	return entities.LinkedContentProvider{
+		ID:        "map-markers",
+		Label:     "Maps",
+		Icon:      "fa-location-dot",
+		Color:     "#10b981",
+		Addon:     "maps",
+		SortOrder: 20,
+		List: func(ctx context.Context, campaignID, entityID string, role int, userID string) ([]entities.LinkedItem, error) {
+			markers, err := mapsSvc.ListMarkersForEntity(ctx, campaignID, entityID, role, userID)
+			if err != nil {
+				return nil, err
+			}
+			items := make([]entities.LinkedItem, 0, len(markers))
+			for _, m := range markers {
+				items = append(items, entities.LinkedItem{
+					Name: m.MapName,
+					URL:  fmt.Sprintf("/campaigns/%s/maps/%s", campaignID, m.MapID),
+					Meta: m.MarkerName,
+				})
+			}
+			return items, nil
+		},
+	}
+}
+
+// linkedSessionProvider lists the sessions an entity was linked to. Session
+// pages are readable by anyone who can view the campaign, so no role
+// filter applies.
+func linkedSessionProvider(sessionsSvc sessions.SessionService) entities.LinkedContentProvider {
+	return entities.LinkedContentProvider{
+		ID:        "sessions",
+		Label:     "Sessions",
+		Icon:      "fa-calendar-check",
+		Color:     "#6366f1",
+		Addon:     "sessions",
+		SortOrder: 30,
+		List: func(ctx context.Context, campaignID, entityID string, _ int, _ string) ([]entities.LinkedItem, error) {
+			linked, err := sessionsSvc.ListEntitySessions(ctx, campaignID, entityID)
+			if err != nil {
+				return nil, err
+			}
+			items := make([]entities.LinkedItem, 0, len(linked))
+			for _, s := range linked {
+				date := ""
+				if s.ScheduledDate != nil {
+					date = *s.ScheduledDate
+				}
+				items = append(items, entities.LinkedItem{
+					Name: s.Name,
+					URL:  fmt.Sprintf("/campaigns/%s/sessions/%s", campaignID, s.SessionID),
+					Meta: joinMeta(date, s.Role),
+				})
+			}
+			return items, nil
+		},
+	}
+}
+
+// joinMeta joins the non-empty parts of a row's secondary text.
+func joinMeta(parts ...string) string {
+	kept := parts[:0]
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, " · ")
+}
@@ -0,0 +1,176 @@
+// Package app — overlay_adapters.go builds the stream overlay widgets'
+// data. Each returns a campaigns.OverlayDataFunc over one plugin's data, so
+// the campaigns plugin never imports them. Overlays are shown on stream,
+// so every widget reads what a logged-out visitor could see.
+package app
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/plugins/sessions"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// overlayRecapMaxRunes caps the recap text; an overlay has room for a
+// paragraph, not a session log.
+const overlayRecapMaxRunes = 600
+
+// overlayTagRegex strips markup from rendered recaps.
+var overlayTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// overlayDateData shows the campaign calendar's current date, time and
+// season.
+func overlayDateData(calendarSvc calendar.CalendarService) campaigns.OverlayDataFunc {
+	return func(ctx context.Context, campaignID string) (*campaigns.OverlayData, error) {
+		cal, err := calendarSvc.GetCalendar(ctx, campaignID)
+		if err != nil || cal == nil {
+			return nil, err
+		}
+		data := &campaigns.OverlayData{
+			Title: cal.Name,
+			Text: digestEventDate(cal, calendar.Event{
+				Year: cal.CurrentYear, Month: cal.CurrentMonth, Day: cal.CurrentDay,
+			}),
+			Detail: cal.FormatCurrentTime(),
+		}
+		if season := cal.CurrentSeason(); season != nil {
+			data.Detail += " · " + season.Name
+		}
+		return data, nil
+	}
+}
+
+// overlaySessionData counts down to the next planned session that has a
+// date. Scheduled dates are zone-less, so "next" is compared by UTC date
+// and the start is handed on as a wall-clock time.
+func overlaySessionData(sessionsSvc sessions.SessionService) campaigns.OverlayDataFunc {
+	return func(ctx context.Context, campaignID string) (*campaigns.OverlayData, error) {
+		planned, err := sessionsSvc.ListPlannedSessions(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		today := time.Now().UTC().Format("2006-01-02")
+		var next *sessions.Session
+		for i := range planned {
+			sess := &planned[i]
+			if sess.ScheduledDate == nil || *sess.ScheduledDate < today {
+				continue
+			}
+			if next == nil || *sess.ScheduledDate < *next.ScheduledDate {
+				next = sess
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+
+		day, err := time.Parse("2006-01-02", *next.ScheduledDate)
+		if err != nil {
+			return nil, nil
+		}
+		data := &campaigns.OverlayData{
+			Title:  "Next session",
+			Text:   next.Name,
+			Detail: day.Format("Mon Jan 2"),
+			Start:  *next.ScheduledDate + "T00:00",
+		}
+		if next.ScheduledTime != nil && *next.ScheduledTime != "" {
+			data.Detail += " at " + next.FormatScheduledTime()
+			data.Start = *next.ScheduledDate + "T" + *next.ScheduledTime
+		}
+		return data, nil
+	}
+}
+
+// overlayRosterData lists the party: every claimed character a visitor
+// could see, by name.
+func overlayRosterData(entitySvc entities.EntityService) campaigns.OverlayDataFunc {
+	return func(ctx context.Context, campaignID string) (*campaigns.OverlayData, error) {
+		claimed, err := entitySvc.ListClaimed(ctx, campaignID, int(campaigns.RoleNone), "")
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(claimed))
+		for _, ent := range claimed {
+			names = append(names, ent.Name)
+		}
+		sort.Strings(names)
+		return &campaigns.OverlayData{Title: "The party", Items: names}, nil
+	}
+}
+
+// overlayRecapData shows the most recently completed session's recap,
+// falling back to its summary, as plain text.
+func overlayRecapData(sessionsSvc sessions.SessionService) campaigns.OverlayDataFunc {
+	return func(ctx context.Context, campaignID string) (*campaigns.OverlayData, error) {
+		all, err := sessionsSvc.ListSessions(ctx, campaignID)
+		if err != nil {
+			return nil, err
+		}
+		var latest *sessions.Session
+		for i := range all {
+			sess := &all[i]
+			if sess.Status != sessions.StatusCompleted {
+				continue
+			}
+			if latest == nil || sessionEnded(sess).After(sessionEnded(latest)) {
+				latest = sess
+			}
+		}
+		if latest == nil {
+			return nil, nil
+		}
+
+		// The list query leaves out recap bodies.
+		full, err := sessionsSvc.GetSession(ctx, latest.ID)
+		if err != nil {
+			return nil, err
+		}
+		text := ""
+		if full.RecapHTML != nil {
+			// Inline GM secrets never reach a public overlay.
+			text = overlayPlainText(sanitize.StripSecretsHTML(*full.RecapHTML))
+		}
+		if text == "" && full.Summary != nil {
+			text = strings.TrimSpace(*full.Summary)
+		}
+		return &campaigns.OverlayData{
+			Title: "Last time: " + full.Name,
+			Text:  truncateRunes(text, overlayRecapMaxRunes),
+		}, nil
+	}
+}
+
+// sessionEnded orders completed sessions: by scheduled date when set,
+// otherwise by last update.
+func sessionEnded(s *sessions.Session) time.Time {
+	if s.ScheduledDate != nil {
+		if d, err := time.Parse("2006-01-02", *s.ScheduledDate); err == nil {
+			return d
+		}
+	}
+	return s.UpdatedAt
+}
+
+// overlayPlainText turns rendered HTML into one line of text.
+func overlayPlainText(s string) string {
+	s = overlayTagRegex.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// truncateRunes shortens s to at most max runes, ending in an ellipsis.
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(r[:max-1])) + "…"
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/sessions"
+)
+
+// recapSessionSvc serves one completed session with a recap.
+type recapSessionSvc struct {
+	sessions.SessionService
+	sess sessions.Session
+}
+
+func (s recapSessionSvc) ListSessions(context.Context, string) ([]sessions.Session, error) {
+	return []sessions.Session{s.sess}, nil
+}
+
+func (s recapSessionSvc) GetSession(context.Context, string) (*sessions.Session, error) {
+	return &s.sess, nil
+}
+
+func TestOverlayRecapData_StripsSecrets(t *testing.T) {
+	recap := `<p>The party reached the keep. <span data-secret="true">The steward is the lich.</span> They rested.</p>`
+	svc := recapSessionSvc{sess: sessions.Session{
+		ID: "s-1", Name: "Session 4", Status: sessions.StatusCompleted, RecapHTML: &recap,
+	}}
+
+	data, err := overlayRecapData(svc)(context.Background(), "c-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(data.Text, "lich") {
+		t.Errorf("secret leaked into overlay: %q", data.Text)
+	}
+	if !strings.Contains(data.Text, "reached the keep") || !strings.Contains(data.Text, "They rested") {
+		t.Errorf("recap text = %q", data.Text)
+	}
+}
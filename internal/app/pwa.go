@@ -0,0 +1,54 @@
+package app
+
+// pwa.go -- installable-app plumbing: the web app manifest and the service
+// worker script. Offline content itself comes from each campaign's
+// /campaigns/:id/offline-manifest (entities plugin); the worker here only
+// caches what that manifest lists.
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/settings"
+)
+
+// webAppManifest builds the W3C web app manifest served at
+// /manifest.webmanifest, named after the instance. Icons reuse the SVG
+// favicon, which browsers accept at any size.
+func webAppManifest(name string) []byte {
+	manifest, _ := json.Marshal(map[string]any{
+		"name":             name,
+		"short_name":       name,
+		"description":      "Worldbuilding wiki and campaign manager",
+		"start_url":        "/",
+		"scope":            "/",
+		"display":          "standalone",
+		"background_color": "#111827",
+		"theme_color":      "#111827",
+		"icons": []map[string]string{
+			{"src": "/static/img/favicon.svg", "sizes": "any", "type": "image/svg+xml", "purpose": "any"},
+		},
+	})
+	return manifest
+}
+
+// registerPWARoutes mounts the manifest and the service worker. Both live at
+// the site root: a worker only controls pages under its own path, so it must
+// not be served from /static/js.
+func (a *App) registerPWARoutes(rc *settings.RuntimeConfig) {
+	a.Echo.GET("/manifest.webmanifest", func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "public, max-age=86400")
+		return c.Blob(http.StatusOK, "application/manifest+json", webAppManifest(rc.Branding().Name()))
+	})
+
+	a.Echo.GET("/sw.js", func(c echo.Context) error {
+		h := c.Response().Header()
+		// Browsers check for worker updates on navigation; no-cache makes a
+		// deploy reach clients on the next page load.
+		h.Set("Cache-Control", "no-cache")
+		h.Set("Service-Worker-Allowed", "/")
+		return c.File("static/js/sw.js")
+	})
+}
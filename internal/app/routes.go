@@ -16,6 +16,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/database"
 	"github.com/keyxmakerx/chronicle/internal/extensions"
 	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/permissions"
@@ -24,6 +25,7 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/plugins/ai_workspace"
 	"github.com/keyxmakerx/chronicle/internal/plugins/ai_workspace/aiexport"
 	"github.com/keyxmakerx/chronicle/internal/plugins/ai_workspace/importer"
+	"github.com/keyxmakerx/chronicle/internal/plugins/ai_workspace/importer/htmlconv"
 	"github.com/keyxmakerx/chronicle/internal/plugins/ai_workspace/prompt"
 	"github.com/keyxmakerx/chronicle/internal/plugins/armory"
 	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
@@ -50,9 +52,11 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/templates/demo"
 	"github.com/keyxmakerx/chronicle/internal/templates/layouts"
 	"github.com/keyxmakerx/chronicle/internal/templates/pages"
+	"github.com/keyxmakerx/chronicle/internal/timeutil"
 	ws "github.com/keyxmakerx/chronicle/internal/websocket"
 	"github.com/keyxmakerx/chronicle/internal/widgets/entity_notes"
 	"github.com/keyxmakerx/chronicle/internal/widgets/notes"
+	"github.com/keyxmakerx/chronicle/internal/widgets/party_journal"
 	"github.com/keyxmakerx/chronicle/internal/widgets/posts"
 	"github.com/keyxmakerx/chronicle/internal/widgets/relations"
 	"github.com/keyxmakerx/chronicle/internal/widgets/tags"
@@ -151,18 +155,39 @@ func (a *entityTypeListerAdapter) GetEntityTypesForSettings(ctx context.Context,
 	result := make([]campaigns.SettingsEntityType, len(etypes))
 	for i, et := range etypes {
 		result[i] = campaigns.SettingsEntityType{
-			ID:           et.ID,
-			Name:         et.Name,
-			NamePlural:   et.NamePlural,
-			Icon:         et.Icon,
-			Color:        et.Color,
-			Description:  et.Description,
-			ParentTypeID: et.ParentTypeID,
+			ID:                 et.ID,
+			Name:               et.Name,
+			NamePlural:         et.NamePlural,
+			Icon:               et.Icon,
+			Color:              et.Color,
+			Description:        et.Description,
+			ParentTypeID:       et.ParentTypeID,
+			HasDashboardLayout: et.DashboardLayout != nil && *et.DashboardLayout != "",
 		}
 	}
 	return result, nil
 }
 
+// dashboardBlockCheckerAdapter answers campaigns' layout import screening
+// from the entities block registry and the addon service.
+type dashboardBlockCheckerAdapter struct {
+	registry *entities.BlockRegistry
+	addons   entities.AddonChecker
+}
+
+// DashboardBlockStatus reports whether a campaign can render a dashboard block.
+func (a *dashboardBlockCheckerAdapter) DashboardBlockStatus(ctx context.Context, campaignID, blockType string) (bool, string, bool) {
+	meta, ok := a.registry.Meta(blockType)
+	if !ok || !a.registry.IsValidForContext(blockType, "dashboard") {
+		return false, "", false
+	}
+	if meta.Addon == "" {
+		return true, "", true
+	}
+	enabled, err := a.addons.IsEnabledForCampaign(ctx, campaignID, meta.Addon)
+	return true, meta.Addon, err == nil && enabled
+}
+
 // recentEntityListerAdapter wraps entities.EntityService to implement the
 // campaigns.RecentEntityLister interface without creating a circular import.
 type recentEntityListerAdapter struct {
@@ -191,6 +216,39 @@ func (a *recentEntityListerAdapter) ListRecentForDashboard(ctx context.Context,
 	return result, nil
 }
 
+// welcomeEntityFetcherAdapter wraps entities.EntityService to implement the
+// campaigns.WelcomeEntityFetcher interface for the welcome page's pinned
+// entity.
+type welcomeEntityFetcherAdapter struct {
+	svc entities.EntityService
+}
+
+// GetWelcomeEntity returns the pinned entity if it belongs to the campaign
+// and the member may view it.
+func (a *welcomeEntityFetcherAdapter) GetWelcomeEntity(ctx context.Context, campaignID, entityID string, role int, userID string) (*campaigns.RecentEntity, error) {
+	e, err := a.svc.GetByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if e.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	access, err := a.svc.CheckEntityAccess(ctx, e.ID, role, userID)
+	if err != nil || !access.CanView {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	return &campaigns.RecentEntity{
+		ID:        e.ID,
+		Name:      e.Name,
+		TypeName:  e.TypeName,
+		TypeIcon:  e.TypeIcon,
+		TypeColor: e.TypeColor,
+		ImagePath: e.ImagePath,
+		IsPrivate: e.IsPrivate,
+		UpdatedAt: e.UpdatedAt,
+	}, nil
+}
+
 // entityTypeLayoutFetcherAdapter wraps entities.EntityService to implement the
 // campaigns.EntityTypeLayoutFetcher interface. Fetches a single entity type
 // with pre-serialized layout and fields JSON for the page layout editor.
@@ -329,6 +387,36 @@ func (a *addonListerAdapter) ListForPluginHub(ctx context.Context, campaignID st
 	return result, nil
 }
 
+// campaignAddonEnablerAdapter wraps addons.AddonService to implement
+// campaigns.CampaignAddonEnabler for the create form's extension picker.
+type campaignAddonEnablerAdapter struct {
+	svc addons.AddonService
+}
+
+// EnableAddonsForNewCampaign resolves and checks every slug before
+// enabling any, so an unknown or unavailable pick fails without leaving
+// half the selection enabled. Game systems are refused here; they're set
+// from Settings, where their mutual exclusivity is handled.
+func (a *campaignAddonEnablerAdapter) EnableAddonsForNewCampaign(ctx context.Context, campaignID, userID string, slugs []string) error {
+	ids := make([]int, 0, len(slugs))
+	for _, slug := range slugs {
+		addon, err := a.svc.GetBySlug(ctx, slug)
+		if err != nil {
+			return apperror.NewBadRequest(fmt.Sprintf("unknown extension %q", slug))
+		}
+		if addon.Category == addons.CategorySystem || addon.Status != addons.StatusActive || !addons.IsInstalled(addon.Slug) {
+			return apperror.NewBadRequest(fmt.Sprintf("extension %q can't be enabled at creation", addon.Name))
+		}
+		ids = append(ids, addon.ID)
+	}
+	for _, id := range ids {
+		if err := a.svc.EnableForCampaign(ctx, campaignID, id, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // addonListerAPIAdapter wraps the addon service to implement the
 // syncapi.AddonLister interface for the REST API addon discovery endpoint.
 type addonListerAPIAdapter struct {
@@ -354,6 +442,77 @@ func (a *addonListerAPIAdapter) ListForCampaign(ctx context.Context, campaignID
 	return result, nil
 }
 
+// markdownEntryAdapter implements syncapi.MarkdownEntryConverter with the
+// AI workspace importer's Markdown funnel, for Obsidian note pushes.
+type markdownEntryAdapter struct{}
+
+// ConvertMarkdown renders sanitized HTML and the matching editor JSON.
+func (markdownEntryAdapter) ConvertMarkdown(markdown string) (string, string, error) {
+	entryHTML, err := importer.MarkdownToHTML(markdown)
+	if err != nil {
+		return "", "", err
+	}
+	entryJSON, err := htmlconv.Convert(entryHTML)
+	if err != nil {
+		return "", "", err
+	}
+	return entryJSON, entryHTML, nil
+}
+
+// sessionRecapAppenderAdapter implements syncapi.SessionRecapAppender for
+// email-in "Session:" emails: the HTML is added to the end of the recap of
+// the campaign's latest session that has already happened.
+type sessionRecapAppenderAdapter struct {
+	svc    sessions.SessionService
+	addons addons.AddonService
+}
+
+// AppendToLatestRecap appends html to the latest past, non-cancelled
+// session's recap and re-derives the editor JSON from the combined HTML.
+func (a *sessionRecapAppenderAdapter) AppendToLatestRecap(ctx context.Context, campaignID, html string) (string, string, error) {
+	enabled, err := a.addons.IsEnabledForCampaign(ctx, campaignID, "sessions")
+	if err != nil {
+		return "", "", err
+	}
+	if !enabled {
+		return "", "", apperror.NewNotFound("sessions addon is not enabled for this campaign")
+	}
+	list, err := a.svc.ListSessions(ctx, campaignID)
+	if err != nil {
+		return "", "", err
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	var latest *sessions.Session
+	for i := range list {
+		s := &list[i]
+		if s.Status == sessions.StatusCancelled || s.ScheduledDate == nil || *s.ScheduledDate > today {
+			continue
+		}
+		if latest == nil || *s.ScheduledDate > *latest.ScheduledDate {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return "", "", apperror.NewNotFound("no past session to add the note to")
+	}
+	sess, err := a.svc.GetSession(ctx, latest.ID)
+	if err != nil {
+		return "", "", err
+	}
+	recapHTML := html
+	if sess.RecapHTML != nil && *sess.RecapHTML != "" {
+		recapHTML = *sess.RecapHTML + html
+	}
+	recapJSON, err := htmlconv.Convert(recapHTML)
+	if err != nil {
+		return "", "", err
+	}
+	if err := a.svc.UpdateSessionRecap(ctx, sess.ID, &recapJSON, &recapHTML); err != nil {
+		return "", "", err
+	}
+	return sess.ID, sess.Name, nil
+}
+
 // backdropUploaderAdapter wraps the media service to implement the
 // campaigns.MediaUploader interface for backdrop image uploads.
 type backdropUploaderAdapter struct {
@@ -377,6 +536,29 @@ func (a *backdropUploaderAdapter) UploadBackdrop(ctx context.Context, campaignID
 	return mf.Filename, nil
 }
 
+// entityImageUploaderAdapter wraps the media service to implement the
+// entities.BulkImageUploader interface for bulk image assignment.
+type entityImageUploaderAdapter struct {
+	svc media.MediaService
+}
+
+// UploadEntityImage uploads an image via the media service with entity image usage type.
+func (a *entityImageUploaderAdapter) UploadEntityImage(ctx context.Context, campaignID, userID string, data []byte, originalName, mimeType string) (string, error) {
+	mf, err := a.svc.Upload(ctx, media.UploadInput{
+		CampaignID:   campaignID,
+		UploadedBy:   userID,
+		OriginalName: originalName,
+		MimeType:     mimeType,
+		FileSize:     int64(len(data)),
+		UsageType:    media.UsageEntityImage,
+		FileBytes:    data,
+	})
+	if err != nil {
+		return "", err
+	}
+	return mf.Filename, nil
+}
+
 // entityTagFetcherAdapter wraps tags.TagService to implement the
 // entities.EntityTagFetcher interface for batch tag loading in list views.
 // grantSvc backs the tag-grant glance methods (C-PERM-W1-TAG-GRANTS).
@@ -461,6 +643,79 @@ func (a *entityCampaignCheckerAdapter) EntityBelongsToCampaign(ctx context.Conte
 	return entity.CampaignID == campaignID, nil
 }
 
+// transcriptNameAdapter wraps entities.EntityService to implement the
+// sessions.EntityNameLister interface: the same visibility-filtered names
+// and aliases the editor's auto-linking uses.
+type transcriptNameAdapter struct {
+	svc entities.EntityService
+}
+
+// ListTranscriptNames returns the page names and aliases the viewer can see.
+func (a *transcriptNameAdapter) ListTranscriptNames(ctx context.Context, campaignID string, role int, userID string) ([]sessions.TranscriptName, error) {
+	entries, err := a.svc.ListEntityNames(ctx, campaignID, role, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]sessions.TranscriptName, len(entries))
+	for i, e := range entries {
+		names[i] = sessions.TranscriptName{EntityID: e.ID, Name: e.Name, Slug: e.Slug, IsAlias: e.IsAlias}
+	}
+	return names, nil
+}
+
+// pollCalendarEventAdapter wraps calendar.CalendarService to implement the
+// sessions.PollEventCreator interface: a poll that closes on a date option
+// drops that slot onto the campaign's first real-life calendar. Campaigns
+// without one simply get no event.
+type pollCalendarEventAdapter struct {
+	svc calendar.CalendarService
+}
+
+// CreatePollEvent creates the winning slot as an everyone-visible event, in the
+// calendar's own zone when it has one and the poll's otherwise, and returns
+// its ID ("" when there is no real-life calendar).
+func (a *pollCalendarEventAdapter) CreatePollEvent(ctx context.Context, campaignID string, ev sessions.PollEvent) (string, error) {
+	cals, err := a.svc.ListCalendars(ctx, campaignID)
+	if err != nil {
+		return "", err
+	}
+	for i := range cals {
+		cal := &cals[i]
+		if !cal.IsRealLife() {
+			continue
+		}
+		tz := ev.TZ
+		if cal.RealTimeZone != nil && *cal.RealTimeZone != "" {
+			tz = *cal.RealTimeZone
+		}
+		loc := timeutil.LoadLocation(tz)
+		start, end := ev.StartsAt.In(loc), ev.EndsAt.In(loc)
+		startHour, startMinute := start.Hour(), start.Minute()
+		endYear, endMonth, endDay := end.Year(), int(end.Month()), end.Day()
+		endHour, endMinute := end.Hour(), end.Minute()
+		created, err := a.svc.CreateEvent(ctx, cal.ID, calendar.CreateEventInput{
+			Name:        ev.Name,
+			Year:        start.Year(),
+			Month:       int(start.Month()),
+			Day:         start.Day(),
+			StartHour:   &startHour,
+			StartMinute: &startMinute,
+			EndYear:     &endYear,
+			EndMonth:    &endMonth,
+			EndDay:      &endDay,
+			EndHour:     &endHour,
+			EndMinute:   &endMinute,
+			Visibility:  "everyone",
+			CreatedBy:   ev.CreatedBy,
+		})
+		if err != nil {
+			return "", err
+		}
+		return created.ID, nil
+	}
+	return "", nil
+}
+
 // calendarListerAdapter wraps calendar.CalendarService to implement the
 // timeline.CalendarLister interface. Returns available calendars for the
 // timeline create form's calendar selector dropdown.
@@ -665,6 +920,16 @@ func (a *wsCampaignRoleAdapter) IsUserDmGranted(ctx context.Context, campaignID,
 	return a.svc.IsUserDmGranted(ctx, campaignID, userID)
 }
 
+// IsCampaignPendingDeletion reports whether the campaign is in its deletion
+// grace period.
+func (a *wsCampaignRoleAdapter) IsCampaignPendingDeletion(ctx context.Context, campaignID string) (bool, error) {
+	campaign, err := a.svc.GetByID(ctx, campaignID)
+	if err != nil {
+		return false, err
+	}
+	return campaign.IsPendingDeletion(), nil
+}
+
 // calendarEventPublisherAdapter bridges the websocket.EventBus to the
 // calendar.CalendarEventPublisher interface.
 type calendarEventPublisherAdapter struct {
@@ -720,6 +985,10 @@ func (a *calendarEventPublisherAdapter) PublishCalendarEvent(eventType, campaign
 // entities.EntityEventPublisher interface.
 type entityEventPublisherAdapter struct {
 	bus ws.EventBus
+
+	// watches, when set, also queues entity changes for watch
+	// notifications.
+	watches entities.WatchService
 }
 
 // PublishEntityEvent translates entity domain events into WebSocket messages.
@@ -739,6 +1008,11 @@ func (a *entityEventPublisherAdapter) PublishEntityEvent(eventType, campaignID,
 		return
 	}
 	a.bus.Publish(ws.NewMessage(msgType, campaignID, entityID, entity))
+	if a.watches != nil {
+		// Off the request path: the edit's response shouldn't wait on
+		// watcher lookups.
+		go a.watches.RecordChange(context.Background(), eventType, campaignID, entity)
+	}
 }
 
 // PublishEntityTypeEvent translates entity type domain events into WebSocket messages.
@@ -760,6 +1034,152 @@ func (a *entityEventPublisherAdapter) PublishEntityTypeEvent(eventType, campaign
 	a.bus.Publish(ws.NewMessage(msgType, campaignID, fmt.Sprintf("%d", entityType.ID), entityType))
 }
 
+// watchEditorScanLimit bounds the audit rows read per watch notification.
+const watchEditorScanLimit = 200
+
+// watchEditorAdapter lists a page's recent editors from the audit log for
+// entities.WatchService, which has no actor on entity events.
+type watchEditorAdapter struct {
+	repo audit.AuditRepository
+}
+
+// ListEntityEditors returns audit authors since the given time, newest first.
+func (a *watchEditorAdapter) ListEntityEditors(ctx context.Context, campaignID, entityID string, since time.Time) ([]entities.WatchEditor, error) {
+	entries, err := a.repo.ListByEntity(ctx, entityID, campaignID, watchEditorScanLimit)
+	if err != nil {
+		return nil, err
+	}
+	var editors []entities.WatchEditor
+	for _, e := range entries {
+		if e.CreatedAt.Before(since) {
+			break
+		}
+		editors = append(editors, entities.WatchEditor{UserID: e.UserID, Name: e.UserName})
+	}
+	return editors, nil
+}
+
+// securityAlertAdminAdapter lists the instance admins that API security
+// alerts go to.
+type securityAlertAdminAdapter struct {
+	repo auth.UserRepository
+}
+
+// ListAdmins returns every enabled admin's ID and email.
+func (a *securityAlertAdminAdapter) ListAdmins(ctx context.Context) ([]syncapi.SecurityAlertAdmin, error) {
+	users, err := a.repo.ListAdmins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	admins := make([]syncapi.SecurityAlertAdmin, len(users))
+	for i, u := range users {
+		admins[i] = syncapi.SecurityAlertAdmin{UserID: u.ID, Email: u.Email}
+	}
+	return admins, nil
+}
+
+// calendarGameDateAdapter gives entity field history the campaign
+// calendar's current date.
+type calendarGameDateAdapter struct {
+	svc calendar.CalendarService
+}
+
+// CurrentGameDate returns nil when the campaign has no calendar.
+func (a *calendarGameDateAdapter) CurrentGameDate(ctx context.Context, campaignID string) (*entities.GameDate, error) {
+	cal, err := a.svc.GetCalendar(ctx, campaignID)
+	if err != nil || cal == nil {
+		return nil, err
+	}
+	return &entities.GameDate{Year: cal.CurrentYear, Month: cal.CurrentMonth, Day: cal.CurrentDay}, nil
+}
+
+// calendarDateSyncAdapter puts entity anniversary date fields on the
+// campaign calendar as yearly events.
+type calendarDateSyncAdapter struct {
+	svc calendar.CalendarService
+}
+
+// SyncEntityDates hides the events from players when the entity isn't
+// visible to all of them.
+func (a *calendarDateSyncAdapter) SyncEntityDates(ctx context.Context, entity *entities.Entity, dates []entities.EntityDate) error {
+	in := calendar.EntityDateSync{
+		EntityID:   entity.ID,
+		EntityName: entity.Name,
+		GMOnly:     entity.IsPrivate || entity.Visibility == entities.VisibilityCustom,
+	}
+	for _, d := range dates {
+		in.Dates = append(in.Dates, calendar.EntityDateField{
+			FieldKey: d.FieldKey, Label: d.Label,
+			Year: d.Date.Year, Month: d.Date.Month, Day: d.Date.Day,
+		})
+	}
+	return a.svc.SyncEntityDateEvents(ctx, entity.CampaignID, in)
+}
+
+// calendarDateValidatorAdapter checks entity date fields against the
+// campaign calendar's months and leap rules.
+type calendarDateValidatorAdapter struct {
+	svc calendar.CalendarService
+}
+
+// DateChecker returns nil when the campaign has no calendar, or one with no
+// months yet, so date fields aren't held to a calendar that isn't set up.
+func (a *calendarDateValidatorAdapter) DateChecker(ctx context.Context, campaignID string) (entities.DateCheck, error) {
+	cal, err := a.svc.GetCalendar(ctx, campaignID)
+	if err != nil || cal == nil || len(cal.Months) == 0 {
+		return nil, err
+	}
+	return func(d entities.GameDate) string {
+		return cal.DateProblem(d.Year, d.Month, d.Day)
+	}, nil
+}
+
+// dateIssueListenerAdapter re-checks a campaign's date fields when its
+// calendar changes shape. Best-effort: the calendar edit is already saved.
+type dateIssueListenerAdapter struct {
+	svc entities.DateIssueService
+}
+
+// CalendarDatesChanged flags the entities whose dates no longer fit.
+func (a *dateIssueListenerAdapter) CalendarDatesChanged(ctx context.Context, campaignID string) {
+	n, err := a.svc.Revalidate(ctx, campaignID)
+	if err != nil {
+		slog.Warn("re-checking date fields after calendar change failed",
+			slog.String("campaign_id", campaignID),
+			slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		slog.Info("date fields flagged after calendar change",
+			slog.String("campaign_id", campaignID),
+			slog.Int("count", n))
+	}
+}
+
+// dateFieldScannerAdapter previews which entity date fields a proposed
+// calendar shape would strand, for the calendar's structure impact report.
+type dateFieldScannerAdapter struct {
+	svc entities.DateIssueService
+}
+
+// StrandedDateFields scans without touching the stored flags.
+func (a *dateFieldScannerAdapter) StrandedDateFields(ctx context.Context, campaignID string, check func(year, month, day int) string) ([]calendar.ImpactedDateField, error) {
+	issues, err := a.svc.Scan(ctx, campaignID, func(d entities.GameDate) string {
+		return check(d.Year, d.Month, d.Day)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]calendar.ImpactedDateField, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, calendar.ImpactedDateField{
+			EntityID: is.EntityID, EntityName: is.EntityName,
+			FieldLabel: is.FieldLabel, Value: is.Value, Reason: is.Reason,
+		})
+	}
+	return out, nil
+}
+
 // sidebarConfigStore is the narrow slice of the campaign service the sidebar
 // auto-adder needs: read the current config and write back the items. Narrowing
 // it (from the full CampaignService) keeps the auto-add behavior unit-testable.
@@ -836,6 +1256,21 @@ var defaultSidebarAddons = []campaigns.SidebarItem{
 	{Type: "addon", Slug: "calendar", Label: "Calendar", Icon: "fa-calendar-days", Visible: true},
 }
 
+// instanceBrandingData converts the admin branding settings for the layout.
+func instanceBrandingData(b settings.Branding) *layouts.InstanceBrandingData {
+	data := &layouts.InstanceBrandingData{
+		Name:            b.InstanceName,
+		Logo:            b.Logo,
+		Favicon:         b.Favicon,
+		LandingHeadline: b.LandingHeadline,
+		LandingText:     b.LandingText,
+	}
+	for _, l := range b.FooterLinks {
+		data.FooterLinks = append(data.FooterLinks, layouts.FooterLinkData{Label: l.Label, URL: l.URL})
+	}
+	return data
+}
+
 // injectDefaultSidebarItems completes a sidebar items array with the standard
 // scaffold — Dashboard, the default addon shortcuts, every top-level entity
 // type, and All Pages — adding only what is missing and preserving the caller's
@@ -1381,6 +1816,29 @@ func (a *entityAccessAdapter) FilterViewableEntityIDs(ctx context.Context, campa
 	return a.svc.FilterViewableEntityIDs(ctx, campaignID, entityIDs, role, userID)
 }
 
+// attachmentTargetAdapter implements media.AttachmentTargetResolver over the
+// entities and sessions services: entities use the same gate as the entity
+// page, sessions are visible to every campaign member.
+type attachmentTargetAdapter struct {
+	entities *entityAccessAdapter
+	sessions sessions.SessionService
+}
+
+// ResolveTarget returns the target's campaign and whether the viewer may see it.
+func (a *attachmentTargetAdapter) ResolveTarget(ctx context.Context, targetType, targetID string, role int, userID string) (string, bool, error) {
+	switch targetType {
+	case media.AttachTargetEntity:
+		return a.entities.ResolveViewableEntity(ctx, targetID, role, userID)
+	case media.AttachTargetSession:
+		sess, err := a.sessions.GetSession(ctx, targetID)
+		if err != nil {
+			return "", false, err
+		}
+		return sess.CampaignID, true, nil
+	}
+	return "", false, nil
+}
+
 // npcEntityTypeFinderAdapter wraps entities.EntityService to implement the
 // npcs.EntityTypeFinder interface. Resolves the "characters" entity type ID
 // for the NPC gallery without creating a circular import.
@@ -1557,6 +2015,68 @@ func (a *armoryRelationFinderAdapter) GetByID(ctx context.Context, id int) (*arm
 	}, nil
 }
 
+// armoryHoldingsAdapter wraps the relations service to implement
+// armory.InventoryHoldings over "Has Item" relations, the same rows the
+// inventory block edits.
+type armoryHoldingsAdapter struct {
+	svc relations.RelationService
+}
+
+// FindHolding scans the owner's relations for its "Has Item" link to the item.
+func (a *armoryHoldingsAdapter) FindHolding(ctx context.Context, campaignID, ownerEntityID, itemEntityID string) (*armory.Holding, error) {
+	rels, err := a.svc.ListByEntity(ctx, campaignID, ownerEntityID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		if rel.RelationType != armory.HoldingRelationType || rel.TargetEntityID != itemEntityID {
+			continue
+		}
+		h := &armory.Holding{RelationID: rel.ID, Metadata: map[string]any{}}
+		if len(rel.Metadata) > 0 {
+			_ = json.Unmarshal(rel.Metadata, &h.Metadata)
+		}
+		return h, nil
+	}
+	return nil, nil
+}
+
+// CreateHolding links the owner to the item (and the reverse direction).
+func (a *armoryHoldingsAdapter) CreateHolding(ctx context.Context, campaignID, ownerEntityID, itemEntityID, userID string, metadata json.RawMessage) error {
+	_, err := a.svc.Create(ctx, campaignID, ownerEntityID, itemEntityID,
+		armory.HoldingRelationType, armory.HoldingReverseRelationType, userID, metadata)
+	return err
+}
+
+// UpdateHolding replaces the relation's metadata.
+func (a *armoryHoldingsAdapter) UpdateHolding(ctx context.Context, relationID int, metadata json.RawMessage) error {
+	return a.svc.UpdateMetadata(ctx, relationID, metadata)
+}
+
+// DeleteHolding removes both directions of the relation.
+func (a *armoryHoldingsAdapter) DeleteHolding(ctx context.Context, relationID int) error {
+	return a.svc.Delete(ctx, relationID)
+}
+
+// armoryEntityNamerAdapter wraps entities.EntityService to implement
+// armory.InventoryEntityNamer. Entities from other campaigns read as not
+// found, so a transfer can't reach across campaigns.
+type armoryEntityNamerAdapter struct {
+	svc entities.EntityService
+}
+
+// EntityName returns the entity's name when it belongs to the campaign.
+func (a *armoryEntityNamerAdapter) EntityName(ctx context.Context, campaignID, entityID string) (string, error) {
+	e, err := a.svc.GetByID(ctx, entityID)
+	if err != nil {
+		return "", err
+	}
+	if e.CampaignID != campaignID {
+		return "", apperror.NewNotFound("entity not found")
+	}
+	return e.Name, nil
+}
+
 // loadSystemsFromPackages scans installed system packages and loads them into
 // the system registry. Package-managed systems override bundled ones.
 func (a *App) loadSystemsFromPackages(pkgService packages.PackageService) {
@@ -1653,17 +2173,66 @@ func (a *App) RegisterRoutes() {
 
 	// --- Plugin Routes ---
 
+	// Runtime settings: admin-editable overrides for env config. Built before
+	// the auth and media routes because their rate limiters read the live
+	// caps from it on every request. Start keeps other instances' edits
+	// flowing in.
+	serveRateDefault := a.Config.Upload.ServeRateLimit
+	if serveRateDefault <= 0 {
+		serveRateDefault = media.DefaultServeRateLimit
+	}
+	runtimeConfig := settings.NewRuntimeConfig(settings.NewSettingsRepository(a.DB), settings.RuntimeDefaults{
+		BaseURL:                 a.Config.BaseURL,
+		LoginRatePerMin:         auth.DefaultLoginRateLimit,
+		RegisterRatePerMin:      auth.DefaultRegisterRateLimit,
+		PasswordResetRatePerMin: auth.DefaultPasswordResetRateLimit,
+		MediaServeRatePerMin:    serveRateDefault,
+		UploadRatePerMin:        media.DefaultUploadRateLimit,
+	})
+	if err := runtimeConfig.Reload(context.Background()); err != nil {
+		slog.Warn("runtime settings: initial load failed, using defaults", slog.Any("error", err))
+	}
+	go runtimeConfig.Start(context.Background())
+
+	// Installable PWA: web app manifest + service worker (offline reading).
+	// The manifest carries the instance name from the branding settings.
+	a.registerPWARoutes(runtimeConfig)
+
 	// Auth plugin: login, register, logout (public routes).
 	authRepo := auth.NewUserRepository(a.DB)
 	authService := auth.NewAuthService(authRepo, a.Redis, a.Config.Auth.SessionTTL)
 	authHandler := auth.NewHandler(authService, a.Config.Auth.SessionTTL)
-	auth.RegisterRoutes(e, authHandler)
+	auth.RegisterRoutes(e, authHandler, auth.RateLimits{
+		Login:         runtimeConfig.LoginRateLimit,
+		Register:      runtimeConfig.RegisterRateLimit,
+		PasswordReset: runtimeConfig.PasswordResetRateLimit,
+	})
+	// Optional CAPTCHA / proof-of-work on signup, password reset, and login
+	// after repeated failures. Read from the runtime snapshot per request so
+	// admin changes apply immediately.
+	authHandler.SetCaptcha(auth.NewCaptcha(func() auth.CaptchaConfig {
+		rs := runtimeConfig.Current()
+		return auth.CaptchaConfig{
+			Provider:           rs.CaptchaProvider,
+			SiteKey:            rs.CaptchaSiteKey,
+			SecretKey:          rs.CaptchaSecretKey,
+			LoginAfterFailures: runtimeConfig.CaptchaLoginAfterFailures(),
+		}
+	}, a.Config.Auth.SecretKey, a.Redis))
 
 	// SMTP plugin: outbound email for transfers, password resets.
 	smtpRepo := smtp.NewSMTPRepository(a.DB)
 	smtpService := smtp.NewSMTPService(smtpRepo, a.Config.Auth.SecretKey)
 	smtpHandler := smtp.NewHandler(smtpService)
 
+	// Mail outbox: plugins that send mail get the outbox, which queues each
+	// message and delivers it in the background with retries. The SMTP
+	// service itself stays wired where a direct send or a config check is
+	// wanted (admin test email, IsConfigured checks).
+	mailOutbox := smtp.NewMailOutbox(smtp.NewOutboxRepository(a.DB), smtpService)
+	go mailOutbox.Start(context.Background())
+	smtpHandler.SetOutbox(mailOutbox)
+
 	// NW-2.2 Chunk A pilot: register smtp in the App's metadata registry.
 	// Per cordinator/decisions/2026-05-23-plugin-registration.md. Slug is
 	// the canonical plugin identifier; HealthCheck wraps the existing
@@ -1680,12 +2249,12 @@ func (a *App) RegisterRoutes() {
 	})
 
 	// Wire SMTP into auth service for password reset emails.
-	auth.ConfigureMailSender(authService, smtpService, a.Config.BaseURL)
+	auth.ConfigureMailSender(authService, mailOutbox, a.Config.BaseURL)
 
 	// Entities plugin: entity types + entity CRUD (must be created before
 	// campaigns so we can pass EntityService as the EntityTypeSeeder).
 	entityTypeRepo := entities.NewEntityTypeRepository(a.DB)
-	entityRepo := entities.NewEntityRepository(a.DB)
+	entityRepo := entities.NewEntityRepository(a.DB, a.DBRouter)
 	entityPermRepo := entities.NewEntityPermissionRepository(a.DB)
 	entityService := entities.NewEntityService(entityRepo, entityTypeRepo, entityPermRepo)
 
@@ -1749,7 +2318,7 @@ func (a *App) RegisterRoutes() {
 	// EntityService is passed as EntityTypeSeeder to seed defaults on campaign creation.
 	userFinder := campaigns.NewUserFinderAdapter(authRepo)
 	campaignRepo := campaigns.NewCampaignRepository(a.DB)
-	campaignService := campaigns.NewCampaignService(campaignRepo, userFinder, smtpService, entityService, a.Config.BaseURL)
+	campaignService := campaigns.NewCampaignService(campaignRepo, userFinder, mailOutbox, entityService, a.Config.BaseURL)
 
 	// One-time, idempotent boot reconciler: convert any campaign still on the
 	// legacy sidebar model onto the unified items model (C-NAV-V3). Runs
@@ -1767,30 +2336,57 @@ func (a *App) RegisterRoutes() {
 	campaignHandler.SetEntityLister(&entityTypeListerAdapter{svc: entityService})
 	campaignHandler.SetLayoutFetcher(&entityTypeLayoutFetcherAdapter{svc: entityService})
 	campaignHandler.SetRecentEntityLister(&recentEntityListerAdapter{svc: entityService})
+	campaignHandler.SetDraftCounter(entityService)
+	campaignHandler.SetWelcomeEntityFetcher(&welcomeEntityFetcherAdapter{svc: entityService})
 	groupRepo := campaigns.NewGroupRepository(a.DB)
 	groupService := campaigns.NewGroupService(groupRepo)
 	campaignHandler.SetGroupService(groupService)
+	campaignAnnouncementService := campaigns.NewAnnouncementService(campaigns.NewAnnouncementRepository(a.DB), campaignService)
+	campaignHandler.SetAnnouncementService(campaignAnnouncementService)
+	campaignHouseRulesService := campaigns.NewHouseRulesService(campaigns.NewHouseRulesRepository(a.DB), campaignService)
+	campaignHandler.SetHouseRulesService(campaignHouseRulesService)
+	campaignDigestService := campaigns.NewDigestService(campaigns.NewDigestRepository(a.DB), campaignService, campaignAnnouncementService, mailOutbox, a.Config.BaseURL)
+	campaignHandler.SetDigestService(campaignDigestService)
+	directoryService := campaigns.NewDirectoryService(campaigns.NewDirectoryRepository(a.DB))
+	if n, err := directoryService.EnsureCampaignDirectory(context.Background()); err != nil {
+		slog.Error("campaign directory reconcile failed", slog.String("error", err.Error()))
+	} else if n > 0 {
+		slog.Info("campaign directory reconcile: added listings", slog.Int("campaigns", n))
+	}
+	campaignHandler.SetDirectoryService(directoryService)
+	layoutVersionService := campaigns.NewLayoutVersionService(campaigns.NewLayoutVersionRepository(a.DB))
+	campaignHandler.SetLayoutVersionService(layoutVersionService)
+	campaignHandler.SetFragmentCache(a.Redis)
 	campaigns.RegisterRoutes(e, campaignHandler, campaignService, authService)
+	campaigns.RegisterOverlayRoutes(e, campaignHandler, middleware.RateLimit(300, time.Minute))
 
 	// Campaign invites.
 	inviteRepo := campaigns.NewInviteRepository(a.DB)
-	inviteService := campaigns.NewInviteService(inviteRepo, campaignRepo, smtpService, a.Config.BaseURL)
+	inviteService := campaigns.NewInviteService(inviteRepo, campaignRepo, mailOutbox, a.Config.BaseURL)
+	inviteService.SetUserFinder(userFinder)
 	inviteHandler := campaigns.NewInviteHandler(inviteService, campaignService, a.Config.BaseURL)
 	campaigns.RegisterInviteRoutes(e, inviteHandler, campaignService, authService)
 
-	// Discover page (/) -- browse public campaigns. Uses OptionalAuth so
-	// authenticated users get the App layout with sidebar, while guests
-	// see a standalone page with signup CTA.
+	// Discover page (/) -- the public campaign directory (listed public
+	// campaigns, searchable by ?q= and filterable by ?tag=, paged by
+	// ?page=). Uses OptionalAuth so authenticated users get the App
+	// layout with sidebar, while guests see a standalone page with
+	// signup CTA.
 	e.GET("/", func(c echo.Context) error {
-		publicCampaigns, err := campaignService.ListPublic(c.Request().Context(), 24)
+		page, _ := strconv.Atoi(c.QueryParam("page"))
+		dir, err := directoryService.Search(c.Request().Context(), campaigns.DirectoryQuery{
+			Search: c.QueryParam("q"),
+			Tag:    c.QueryParam("tag"),
+			Page:   page,
+		})
 		if err != nil {
-			slog.Warn("failed to load public campaigns for discover page", slog.Any("error", err))
-			publicCampaigns = nil
+			slog.Warn("failed to load campaign directory for discover page", slog.Any("error", err))
+			dir = nil
 		}
 		if auth.GetSession(c) != nil {
-			return middleware.Render(c, http.StatusOK, pages.DiscoverAuthPage(publicCampaigns))
+			return middleware.Render(c, http.StatusOK, pages.DiscoverAuthPage(dir))
 		}
-		return middleware.Render(c, http.StatusOK, pages.DiscoverPublicPage(publicCampaigns))
+		return middleware.Render(c, http.StatusOK, pages.DiscoverPublicPage(dir))
 	}, auth.OptionalAuth(authService))
 
 	// About/Welcome page -- Chronicle marketing and feature highlights.
@@ -1805,6 +2401,15 @@ func (a *App) RegisterRoutes() {
 	entityHandler.SetSidebarNodeRepo(sidebarNodeRepo)
 	entityHandler.SetFavoriteRepo(favoriteRepo)
 	entityHandler.SetSavedFilterRepo(entities.NewSavedFilterRepository(a.DB))
+	entityHandler.SetCompendium(entities.NewOpen5eClient(), entities.NewContentImportRepository(a.DB))
+	entityHandler.SetLayoutVersionService(layoutVersionService)
+	entityWatchService := entities.NewWatchService(entities.NewWatchRepository(a.DB), entityService, campaignService)
+	entityWatchService.SetMailer(mailOutbox, a.Config.BaseURL)
+	entityHandler.SetWatchService(entityWatchService)
+	fieldHistoryService := entities.NewFieldHistoryService(entities.NewFieldHistoryRepository(a.DB), entityTypeRepo)
+	entityService.SetFieldHistoryRecorder(fieldHistoryService)
+	entityHandler.SetFieldHistoryService(fieldHistoryService)
+	entityHandler.SetStandingService(entities.NewStandingService(entities.NewStandingRepository(a.DB), entityService))
 	entities.RegisterRoutes(e, entityHandler, campaignService, authService)
 
 	// Expose the entities plugin's embedded static assets at
@@ -1860,12 +2465,25 @@ func (a *App) RegisterRoutes() {
 	settingsService := settings.NewSettingsService(settingsRepo)
 	mediaService.SetStorageLimiter(&storageLimiterAdapter{svc: settingsService})
 
+	// Feature flags: overrides in the DB, cached in Redis. InjectFlags puts
+	// the service on every request context so any handler can call
+	// settings.FlagEnabled without wiring. Plugins declare their flags with
+	// settings.RegisterFlag before routes are registered.
+	flagService := settings.NewFlagService(settings.NewFlagRepository(a.DB), a.Redis)
+	e.Use(settings.InjectFlags(flagService))
+
 	// Beta registration gate (B-R4): the auth service reads the site registration
 	// mode from settings and validates invite-only signups against live campaign
 	// invites. Both deps are optional at the auth layer (nil ⇒ open), wired here
 	// now that settings + invites exist.
 	auth.ConfigureRegistrationGate(authService, settingsService, &registrationInviteCheckerAdapter{invites: inviteService})
 
+	// Registration restrictions: the signup email-domain allowlist (settings)
+	// and admin-issued invite codes, an alternative to campaign invites in
+	// invite-only mode. Also optional at the auth layer.
+	inviteCodeService := settings.NewInviteCodeService(settings.NewInviteCodeRepository(a.DB))
+	auth.ConfigureRegistrationRestrictions(authService, settingsService, inviteCodeService)
+
 	// Migration 26 added media_files.content_hash for per-campaign upload
 	// dedup. Existing rows from before the migration have NULL hashes —
 	// run the backfill in a detached goroutine so a campaign with
@@ -1947,7 +2565,16 @@ func (a *App) RegisterRoutes() {
 	// Wire campaign membership checker for private media access control.
 	mediaHandler.SetMemberChecker(&mediaMemberCheckerAdapter{svc: campaignService})
 
-	media.RegisterRoutes(e, mediaHandler, authService, resolveMaxUpload, a.Config.Upload.ServeRateLimit)
+	// External image proxy. The cache lives beside the media directory, not
+	// inside it, so orphan cleanup and the hygiene scan never see it.
+	imageProxy := media.NewImageProxy(a.Config.Auth.SecretKey,
+		filepath.Join(filepath.Dir(a.Config.Upload.MediaPath), "image-cache"))
+	go imageProxy.Start(context.Background())
+	mediaHandler.SetImageProxy(imageProxy)
+	entityHandler.SetImageProxy(imageProxy)
+
+	media.RegisterRoutes(e, mediaHandler, authService, resolveMaxUpload,
+		runtimeConfig.MediaServeRateLimit, runtimeConfig.UploadRateLimit)
 	// Campaign media routes registered after addon service init (needs media-gallery addon gating).
 
 	// Admin plugin: site-wide management (users, campaigns, SMTP settings, storage).
@@ -1962,6 +2589,14 @@ func (a *App) RegisterRoutes() {
 		return a.Config.Upload.MaxSize
 	})
 	adminHandler.SetBaseURL(a.Config.BaseURL)
+
+	// Instance announcements: admin-published banners injected into every
+	// authenticated page render (see LayoutInjector below).
+	announcementService := admin.NewAnnouncementService(admin.NewAnnouncementRepository(a.DB))
+	adminHandler.SetAnnouncementService(announcementService)
+	adminHandler.SetFlagService(flagService)
+	adminHandler.SetInviteCodeService(inviteCodeService)
+	adminHandler.SetRuntimeConfig(runtimeConfig)
 	adminGroup := admin.RegisterRoutes(e, adminHandler, authService, smtpHandler)
 
 	// Admin Backup plugin: lists backup artifacts and exposes a "Run
@@ -2047,6 +2682,7 @@ func (a *App) RegisterRoutes() {
 
 	// Campaign media browser routes (gated behind media-gallery addon).
 	media.RegisterCampaignRoutes(e, mediaHandler, campaignService, authService, addonService)
+	media.RegisterImageProxyRoutes(e, mediaHandler, campaignService, authService, runtimeConfig.MediaServeRateLimit)
 
 	// Wire addon count into admin dashboard for the Extensions stat card.
 	adminHandler.SetAddonCounter(addonService)
@@ -2235,7 +2871,13 @@ func (a *App) RegisterRoutes() {
 		// Store package service reference.
 		a.pkgService = pkgService
 
-		// Start background auto-update worker.
+		// Start background auto-update worker. In cluster mode the first
+		// instance to tick each hour takes the run.
+		if a.Config.ClusterMode {
+			packages.SetAutoUpdateLeader(pkgService, func(ctx context.Context) bool {
+				return database.TryLock(ctx, a.Redis, "packages:auto-update", 55*time.Minute)
+			})
+		}
 		go pkgService.StartAutoUpdateWorker(context.Background())
 	} else {
 		slog.Warn("packages plugin degraded — routes not registered")
@@ -2246,10 +2888,19 @@ func (a *App) RegisterRoutes() {
 	securityService := admin.NewSecurityService(securityRepo, authRepo, authService)
 	adminHandler.SetSecurityService(securityService)
 
+	// Account merge: rehomes a duplicate account's rows across every
+	// plugin's tables, so it works on the DB directly like the hygiene scanner.
+	adminHandler.SetUserMerger(admin.NewUserMergeService(a.DB, authRepo, authService))
+
 	// Data hygiene scanner: orphan detection and cleanup for media, API keys, stale files.
 	hygieneScanner := admin.NewHygieneService(a.DB, mediaRepo, mediaService, a.Config.Upload.MediaPath, securityRepo)
 	adminHandler.SetHygieneScanner(hygieneScanner)
 
+	// Per-campaign usage counters for hosting providers (admin JSON and
+	// Prometheus text; /metrics/usage too when METRICS_TOKEN is set).
+	adminHandler.SetUsageReporter(admin.NewUsageService(a.DB))
+	admin.RegisterMetricsRoutes(e, adminHandler, a.Config.MetricsToken)
+
 	// Database explorer: schema visualization and migration management.
 	dbExplorer := admin.NewDatabaseExplorer(a.DB, a.PluginHealth, a.PluginSchemas)
 	adminHandler.SetDatabaseExplorer(dbExplorer)
@@ -2257,6 +2908,9 @@ func (a *App) RegisterRoutes() {
 	// surface the existing backup/restore artifacts — adapters so admin imports
 	// neither the boot config nor the backup/restore plugins.
 	adminHandler.SetHealthChecker(&adminHealthChecker{db: a.DB, cfg: a.Config})
+	if a.QueryStats != nil {
+		adminHandler.SetQueryStats(a.QueryStats)
+	}
 	adminHandler.SetBackupLister(&adminBackupLister{backups: backupSvc, restores: restoreSvc, backupDir: a.Config.BackupDir})
 
 	// Wire security event logging into the auth handler so logins, logouts,
@@ -2308,7 +2962,7 @@ func (a *App) RegisterRoutes() {
 	}
 	fvttService := foundry_vtt.NewService(
 		fvttRepo, fvttTokenSigner, fvttCampaignAdapter, pkgService,
-		securityService, smtpService, fvttOwnerLookup,
+		securityService, mailOutbox, fvttOwnerLookup,
 		settingsRepo, // C-FMC-8: powers the auto-pin install banner
 		a.Config.BaseURL,
 	)
@@ -2372,6 +3026,11 @@ func (a *App) RegisterRoutes() {
 	// request logging, security monitoring, and admin dashboard.
 	syncRepo := syncapi.NewSyncAPIRepository(a.DB)
 	syncService := syncapi.NewSyncAPIService(syncRepo)
+	// Request log rows queue in Redis and are inserted in batches; the
+	// sample rate thins out successful calls under heavy sync traffic.
+	syncLogBuffer := syncapi.NewRequestLogBuffer(a.Redis, syncRepo)
+	syncapi.SetRequestLogging(syncService, syncLogBuffer, a.Config.APILog.SampleRate)
+	go syncLogBuffer.Start(context.Background())
 	syncHandler := syncapi.NewHandler(syncService)
 	// Inject sync mapping service early so the owner dashboard can show sync status.
 	syncMappingRepoEarly := syncapi.NewSyncMappingRepository(a.DB)
@@ -2405,6 +3064,10 @@ func (a *App) RegisterRoutes() {
 	if a.PluginHealth.IsHealthy("syncapi") {
 		syncapi.RegisterAdminRoutes(adminGroup, syncHandler)
 		syncapi.RegisterCampaignRoutes(e, syncHandler, campaignService, authService)
+		// API keys + sync management cards on Settings > Integrations.
+		for _, section := range syncHandler.SettingsSections() {
+			campaignHandler.RegisterSettingsSection(section)
+		}
 	} else {
 		slog.Warn("syncapi plugin degraded — routes not registered")
 	}
@@ -2447,6 +3110,11 @@ func (a *App) RegisterRoutes() {
 	}
 
 	if a.PluginHealth.IsHealthy("calendar") {
+		if n, err := calendarService.EnsureEventEntityLinks(context.Background()); err != nil {
+			slog.Error("event entity links reconcile failed", slog.String("error", err.Error()))
+		} else if n > 0 {
+			slog.Info("event entity links reconcile: tied legacy event entities", slog.Int("links", n))
+		}
 		calendar.RegisterRoutes(e, calendarHandler, campaignService, authService, addonService)
 
 		// C-CALENDAR-ENDPOINTS: public Foundry-facing calendar API
@@ -2503,14 +3171,25 @@ func (a *App) RegisterRoutes() {
 	sessionsRepo := sessions.NewSessionRepository(a.DB)
 	sessionsService := sessions.NewSessionService(sessionsRepo, &entityCampaignCheckerAdapter{svc: entityService})
 	sessionsHandler := sessions.NewHandler(sessionsService)
+	sessionsService.SetPollEventCreator(&pollCalendarEventAdapter{svc: calendarService})
+	sessionsService.SetEntityNameLister(&transcriptNameAdapter{svc: entityService})
 	sessionsHandler.SetMemberLister(campaignService)
-	sessionsHandler.SetMailSender(smtpService, a.Config.BaseURL)
+	sessionsHandler.SetMailSender(mailOutbox, a.Config.BaseURL)
 	if a.PluginHealth.IsHealthy("sessions") {
 		sessions.RegisterRoutes(e, sessionsHandler, campaignService, authService, addonService)
 	} else {
 		slog.Warn("sessions plugin degraded — routes not registered")
 	}
 
+	// Attachments: handouts, audio and token packs on entities and sessions.
+	// Registered here because the target resolver needs sessionsService.
+	attachmentHandler := media.NewAttachmentHandler(media.NewAttachmentService(media.NewAttachmentRepository(a.DB), mediaService))
+	attachmentHandler.SetTargetResolver(&attachmentTargetAdapter{
+		entities: &entityAccessAdapter{svc: entityService},
+		sessions: sessionsService,
+	})
+	media.RegisterAttachmentRoutes(e, attachmentHandler, campaignService, authService, resolveMaxUpload, runtimeConfig.UploadRateLimit)
+
 	// Timeline plugin: interactive visual timelines with zoom levels and entity grouping.
 	timelineRepo := timeline.NewTimelineRepository(a.DB)
 	timelineSvc := timeline.NewTimelineService(timelineRepo, &calendarListerAdapter{svc: calendarService}, &calendarEventListerAdapter{svc: calendarService}, &calendarEraListerAdapter{svc: calendarService})
@@ -2559,8 +3238,20 @@ func (a *App) RegisterRoutes() {
 	entityNotesNotifier := &entityNotesNotifierHolder{}
 	entityNotesService := entity_notes.NewService(entityNotesRepo, entityNotesNotifier.Notify)
 	entityNotesHandler := entity_notes.NewHandler(entityNotesService)
+	// Personal notes ("My notes" panel): each member's own scratchpad on an
+	// entity. The entity gate pins the note to a viewable entity of the URL
+	// campaign.
+	entityNotesHandler.SetPersonalNoteService(entity_notes.NewPersonalNoteService(entity_notes.NewPersonalNoteRepository(a.DB)))
+	entityNotesHandler.SetEntityGate(&entityAccessAdapter{svc: entityService})
 	entity_notes.RegisterRoutes(e, entityNotesHandler, campaignService, authService)
 
+	// Party journal: members append dated entries to Journal-type entities;
+	// the GM approves and annotates them. Gated by view access, not entity
+	// edit access, via the same entity gate as posts.
+	partyJournalHandler := party_journal.NewHandler(party_journal.NewService(party_journal.NewRepository(a.DB)))
+	partyJournalHandler.SetEntityGate(&entityAccessAdapter{svc: entityService})
+	party_journal.RegisterRoutes(e, partyJournalHandler, campaignService, authService)
+
 	// Tags widget: campaign-scoped entity tagging (CRUD + entity associations).
 	// Created before sync API so the tag service is available for the REST API handler.
 	tagRepo := tags.NewTagRepository(a.DB)
@@ -2589,6 +3280,10 @@ func (a *App) RegisterRoutes() {
 	// sync (C-PERM-W1-TAG-GRANTS), reusing the entities glance adapter.
 	syncAPIHandler.SetTagGrantLister(tagFetcherAdapter)
 	syncAPIHandler.SetSystemEnabler(addonService)
+	syncAPIHandler.SetMarkdownConverter(markdownEntryAdapter{})
+	syncAPIHandler.SetAnnouncementService(campaignAnnouncementService)
+	// Batch upsert resolves external IDs through the same sync mappings.
+	syncAPIHandler.SetSyncMappingService(syncMappingSvcEarly)
 	calendarAPIHandler := syncapi.NewCalendarAPIHandler(syncService, calendarService)
 	mediaAPIHandler := syncapi.NewMediaAPIHandler(syncService, mediaService)
 	if urlSigner != nil {
@@ -2611,7 +3306,16 @@ func (a *App) RegisterRoutes() {
 	// Tag API handler for sync API — exposes tag CRUD and bulk tag operations.
 	tagAPIHandler := syncapi.NewTagAPIHandler(syncService, tagService, entityService, campaignService)
 
+	// Email-in: members email notes to a per-campaign address; see
+	// syncapi/inbound_email_service.go.
+	inboundEmailSvc := syncapi.NewInboundEmailService(syncapi.NewInboundEmailRepository(a.DB),
+		entityService, campaignService, mediaService, markdownEntryAdapter{},
+		&sessionRecapAppenderAdapter{svc: sessionsService, addons: addonService})
+	inboundEmailHandler := syncapi.NewInboundEmailHandler(inboundEmailSvc, a.Config.BaseURL, a.Config.InboundEmailDomain)
+
 	if a.PluginHealth.IsHealthy("syncapi") {
+		syncapi.RegisterInboundEmailRoutes(e, inboundEmailHandler, campaignService, authService)
+		campaignHandler.RegisterSettingsSection(inboundEmailHandler.SettingsSection())
 		syncapi.RegisterAPIRoutes(e, syncAPIHandler, calendarAPIHandler, mediaAPIHandler, mapAPIHandler, noteAPIHandler, tagAPIHandler, syncMappingHandler, syncService, addonService, authService, campaignService)
 	}
 
@@ -2643,6 +3347,7 @@ func (a *App) RegisterRoutes() {
 	txSvc.SetRelationMetadataUpdater(&armoryRelationMetadataAdapter{svc: relService})
 	txSvc.SetRelationFinder(&armoryRelationFinderAdapter{svc: relService})
 	txSvc.SetBuyerAccessChecker(&armoryBuyerAccessAdapter{svc: entityService})
+	txSvc.SetInventoryHoldings(&armoryHoldingsAdapter{svc: relService}, &armoryEntityNamerAdapter{svc: entityService})
 	txHandler := armory.NewTransactionHandler(txSvc)
 	armory.RegisterRoutes(e, armoryHandler, txHandler, instHandler, campaignService, authService, addonService)
 
@@ -2651,6 +3356,7 @@ func (a *App) RegisterRoutes() {
 	noteHandler := notes.NewHandler(noteSvc)
 	noteHandler.SetAttachmentService(noteSvc)
 	noteHandler.SetMediaUploader(&mediaUploadAdapter{svc: mediaService})
+	entityHandler.SetBulkImageUploader(&entityImageUploaderAdapter{svc: mediaService})
 	noteHandler.SetMemberLister(campaignService)
 	notes.RegisterRoutes(e, noteHandler, campaignService, authService)
 
@@ -2658,28 +3364,46 @@ func (a *App) RegisterRoutes() {
 
 	// Audit plugin: campaign activity logging and history.
 	auditRepo := audit.NewAuditRepository(a.DB)
+	audit.SetChainKey(auditRepo, a.Config.Auth.SecretKey)
 	auditService := audit.NewAuditService(auditRepo)
 	auditHandler := audit.NewHandler(auditService)
+	// Hash chain verification for admins; merges reseal the chains they touch.
+	auditChain := audit.NewChainService(auditRepo)
+	auditHandler.SetChainService(auditChain)
+	audit.RegisterAdminRoutes(adminGroup, auditHandler)
+	adminHandler.SetAuditResealer(auditChain)
 	// Guard the entity-history endpoint with campaign ownership + per-entity
 	// visibility, resolved via the entities service (SEC-IDOR-2).
 	auditHandler.SetEntityViewGuard(&auditEntityViewGuardAdapter{svc: entityService})
+	// Owners can revert recent entity and calendar event edits from the
+	// activity feed; each plugin restores its own records.
+	auditHandler.SetRevertService(audit.NewRevertService(auditRepo, map[string]audit.ChangeReverter{
+		"entity":         entities.NewAuditReverter(entityService),
+		"calendar_event": calendar.NewAuditReverter(calendarService),
+	}))
 	audit.RegisterRoutes(e, auditHandler, campaignService, authService)
 
 	// Wire audit logging into mutation handlers so CRUD actions are recorded.
 	entityHandler.SetAuditService(auditService)
 	calendarHandler.SetAuditService(auditService)
+	txHandler.SetAuditService(auditService)
 	// Wave 1.6.5: wire campaign tier vocabulary into the V2 calendar
 	// shell so EventCard + MultiDayRibbon render campaign-aware tier
 	// labels + colors. CampaignService satisfies the narrow
 	// TierDefinitionsLister interface via its existing
 	// GetEventTierDefinitions method.
 	calendarHandler.SetTierDefinitionsLister(campaignService)
+	// The user-level My events page reads the viewer's memberships through
+	// the same service (List + GetMember).
+	calendarHandler.SetUserCampaignLister(campaignService)
 	// C-EXT-HUB Phase 2: register the calendar inline dashboard with
 	// the Extensions hub. Mirrors ai_workspace.SettingsTabFactory at
 	// the campaignHandler.RegisterSettingsTab call below. Per-request
 	// data load lives inside the factory closure (see
 	// internal/plugins/calendar/extension_dashboard.go).
 	campaignHandler.RegisterExtensionDashboard(calendarHandler.ExtensionDashboardFactory())
+	// The calendar_preview dashboard block loads as a cached fragment.
+	campaignHandler.RegisterDashboardFragment(campaigns.BlockCalendarPreview, calendarHandler.UpcomingDashboardFragment())
 	// Calendars dashboard (E1 W1): inject the cross-plugin timeline read so the
 	// associations panel can list timelines bound to a calendar.
 	calendarHandler.SetTimelineLister(&timelineForCalendarAdapter{svc: timelineSvc})
@@ -2693,12 +3417,50 @@ func (a *App) RegisterRoutes() {
 	entityHandler.SetTimelineSearcher(timelineSvc)
 	entityHandler.SetMapSearcher(mapsService)
 	entityHandler.SetCalendarSearcher(calendarService)
+	// Numeric field history stamps points with the calendar's date.
+	fieldHistoryService.SetGameDateSource(&calendarGameDateAdapter{svc: calendarService})
+	// Anniversary date fields become yearly calendar events.
+	entityService.SetDateSyncer(&calendarDateSyncAdapter{svc: calendarService})
+	// Date fields must exist on the calendar; reshaping the calendar flags
+	// the ones it strands.
+	dateValidator := &calendarDateValidatorAdapter{svc: calendarService}
+	entityService.SetGameDateValidator(dateValidator)
+	dateIssueService := entities.NewDateIssueService(entities.NewDateIssueRepository(a.DB), entityRepo, entityTypeRepo, dateValidator)
+	entityHandler.SetDateIssueService(dateIssueService)
+	calendarService.SetDateStructureListener(&dateIssueListenerAdapter{svc: dateIssueService})
+	calendarService.SetDateFieldScanner(&dateFieldScannerAdapter{svc: dateIssueService})
+	// "Appears in" panel on entity pages (linked_content_adapters.go).
+	entityHandler.RegisterLinkedContentProvider(linkedEventProvider(calendarService))
+	entityHandler.RegisterLinkedContentProvider(linkedMarkerProvider(mapsService))
+	entityHandler.RegisterLinkedContentProvider(linkedSessionProvider(sessionsService))
 	entityHandler.SetSessionSearcher(sessionsService)
+	entityHandler.SetNotifier(sessionsService)
+	// Watched-page changes also land in the notification list; the audit
+	// log says who made them.
+	entityWatchService.SetNotifier(sessionsService)
+	entityWatchService.SetEditorLister(&watchEditorAdapter{repo: auditRepo})
+	// High-severity API security events (key brute force, blocklisted IPs
+	// still calling) alert every instance admin in-app and by email.
+	syncapi.SetSecurityAlerter(syncService, syncapi.NewSecurityAlerter(
+		&securityAlertAdminAdapter{repo: authRepo}, sessionsService, mailOutbox, a.Redis, a.Config.BaseURL))
+	// Ownership transfer offers, accepts, declines and cancellations,
+	// campaign announcements and house rules changes land in the same
+	// in-app notification list.
+	campaignHandler.SetNotifier(sessionsService)
+	campaignAnnouncementService.SetNotifier(sessionsService)
+	campaignHouseRulesService.SetNotifier(sessionsService)
 	entityHandler.SetSystemSearcher(systems.NewSystemSearchAdapter(addonService))
 	entityHandler.SetMemberLister(campaignService)
 	entityHandler.SetGroupLister(groupService)
 	entityHandler.SetCache(a.Redis)
 
+	// Public wiki mode: per-campaign sitemap.xml, rebuilt in the background
+	// whenever the entity service reports a page-tree change.
+	sitemapService := entities.NewSitemapService(entityRepo, campaignService, a.Redis, a.Config.BaseURL)
+	entityService.SetHierarchyListener(sitemapService)
+	entityHandler.SetSitemapService(sitemapService)
+	entityHandler.SetBaseURL(a.Config.BaseURL)
+
 	// --- Entity Block Registry ---
 	// Create the block registry and let each plugin register its block types.
 	// This drives validation, rendering, and the template editor palette.
@@ -2965,8 +3727,12 @@ func (a *App) RegisterRoutes() {
 	// The addon checker lets Render() skip blocks whose addon is disabled.
 	blockRegistry.SetAddonChecker(addonService)
 	entityService.SetBlockRegistry(blockRegistry)
+	// New type slugs steer around plugin pages (/campaigns/:id/maps etc.).
+	// The registry fills as routes are wired; it's consulted per request.
+	entityService.SetReservedPathChecker(a.Routes)
 	entities.SetGlobalBlockRegistry(blockRegistry)
 	entityHandler.SetBlockRegistry(blockRegistry)
+	campaignHandler.SetDashboardBlockChecker(&dashboardBlockCheckerAdapter{registry: blockRegistry, addons: addonService})
 	entityHandler.SetWidgetBlockLister(&widgetBlockListerAdapter{extHandler: extHandler})
 
 	// Slug-keyed entity-show renderer registry (CH4). System packages
@@ -2991,6 +3757,7 @@ func (a *App) RegisterRoutes() {
 	campaignHandler.SetAuditLogger(&campaignAuditAdapter{svc: auditService})
 	campaignHandler.SetAddonLister(&addonListerAdapter{svc: addonService})
 	campaignHandler.SetSystemAddonEnabler(addonService)
+	campaignService.SetAddonEnabler(&campaignAddonEnablerAdapter{svc: addonService})
 	campaignHandler.SetMediaUploader(&backdropUploaderAdapter{svc: mediaService})
 	campaignHandler.SetSMTPChecker(smtpService)
 	campaignHandler.SetSystemLister(&systemListerAdapter{})
@@ -3378,6 +4145,10 @@ func (a *App) RegisterRoutes() {
 		// the core base.templ layout (Finding 4 / M-B2.1 quick-win).
 		ctx = layouts.SetPluginBodyScripts(ctx, pluginBodyScripts)
 
+		// Instance white-labeling (admin branding page), read from the
+		// runtime snapshot so edits show on the next render.
+		ctx = layouts.SetInstanceBranding(ctx, instanceBrandingData(runtimeConfig.Branding()))
+
 		// User info from auth session.
 		if session := auth.GetSession(c); session != nil {
 			ctx = layouts.SetIsAuthenticated(ctx, true)
@@ -3390,6 +4161,21 @@ func (a *App) RegisterRoutes() {
 			if session.IsAdmin {
 				ctx = layouts.SetDegradedPluginCount(ctx, len(a.PluginHealth.DegradedPlugins()))
 			}
+
+			// Instance announcements not yet dismissed by this user.
+			if active := announcementService.ActiveFor(c.Request().Context(), session.UserID); len(active) > 0 {
+				banners := make([]layouts.AnnouncementBanner, len(active))
+				for i, an := range active {
+					banners[i] = layouts.AnnouncementBanner{
+						ID:          an.ID,
+						Title:       an.Title,
+						Body:        an.Body,
+						Severity:    an.Severity,
+						Dismissible: an.Dismissible,
+					}
+				}
+				ctx = layouts.SetAnnouncements(ctx, banners)
+			}
 		}
 
 		// Campaign info from campaign middleware.
@@ -3447,6 +4233,14 @@ func (a *App) RegisterRoutes() {
 				}
 				ctx = layouts.SetTopbarContent(ctx, tc)
 			}
+			// Public campaigns credit their content in the page footer.
+			if license := campaignSettings.GetLicense(); cc.Campaign.IsPublic && license.IsSet() {
+				data := &layouts.ContentLicenseData{Attribution: license.Attribution}
+				if o, ok := license.Option(); ok {
+					data.Name, data.URL = o.Name, o.URL
+				}
+				ctx = layouts.SetContentLicense(ctx, data)
+			}
 
 			// "View as player" override: when an owner has the toggle active,
 			// templates see RolePlayer instead of RoleOwner. Access control
@@ -3454,6 +4248,9 @@ func (a *App) RegisterRoutes() {
 			effectiveRole := int(cc.MemberRole)
 			isOwner := cc.MemberRole >= campaigns.RoleOwner
 			ctx = layouts.SetIsOwner(ctx, isOwner)
+			if cc.Campaign.IsPendingDeletion() && cc.Campaign.PurgeAfter != nil {
+				ctx = layouts.SetCampaignPurgeAfter(ctx, *cc.Campaign.PurgeAfter)
+			}
 			if isOwner {
 				if cookie, err := c.Cookie("chronicle_view_as_player"); err == nil && cookie.Value == "1" {
 					effectiveRole = int(campaigns.RolePlayer)
@@ -3644,6 +4441,19 @@ func (a *App) RegisterRoutes() {
 	// --- WebSocket Hub ---
 	// Real-time bidirectional sync for Foundry VTT and browser clients.
 	wsHub := ws.NewHub()
+	if a.Config.ClusterMode {
+		// Clients are spread over the instances; relay broadcasts so each
+		// one reaches the whole campaign.
+		wsHub.EnableRelay(context.Background(), a.Redis)
+	}
+	// Editor presence shows who else is editing an entry by name.
+	wsHub.SetNameLookup(func(ctx context.Context, userID string) string {
+		u, err := authService.GetUser(ctx, userID)
+		if err != nil {
+			return ""
+		}
+		return u.DisplayName
+	})
 	go wsHub.Run()
 
 	// Wire the WS hub's presence lookup into foundry_vtt — the only
@@ -3681,7 +4491,7 @@ func (a *App) RegisterRoutes() {
 	// Wire EventBus into services for real-time event publishing.
 	wsEventBus := ws.NewEventBus(wsHub)
 
-	entityService.SetEventPublisher(&entityEventPublisherAdapter{bus: wsEventBus})
+	entityService.SetEventPublisher(&entityEventPublisherAdapter{bus: wsEventBus, watches: entityWatchService})
 	entityService.SetSidebarAutoAdder(&sidebarAutoAdderAdapter{campaignService: campaignService})
 	calendarService.SetEventPublisher(&calendarEventPublisherAdapter{bus: wsEventBus})
 	noteSvc.SetEventPublisher(&noteEventPublisherAdapter{bus: wsEventBus})
@@ -3701,6 +4511,101 @@ func (a *App) RegisterRoutes() {
 	})
 	mapsService.SetEventPublisher(&mapEventPublisherAdapter{bus: wsEventBus})
 
+	// --- Data Retention ---
+	// Daily purge of rows older than each campaign's retention windows. The
+	// tables are owned by other plugins, so their purge funcs are passed in;
+	// a degraded plugin's table is simply left out.
+	retentionTargets := []campaigns.RetentionTarget{{
+		Name:  "audit_log",
+		Days:  func(r campaigns.RetentionSettings) int { return r.AuditDays },
+		Purge: auditRepo.PurgeBefore,
+	}}
+	if a.PluginHealth.IsHealthy("sessions") {
+		retentionTargets = append(retentionTargets, campaigns.RetentionTarget{
+			Name:  "notifications",
+			Days:  func(r campaigns.RetentionSettings) int { return r.NotificationDays },
+			Purge: sessionsRepo.PurgeNotificationsBefore,
+		})
+	}
+	if a.PluginHealth.IsHealthy("syncapi") {
+		retentionTargets = append(retentionTargets, campaigns.RetentionTarget{
+			Name:  "api_request_log",
+			Days:  func(r campaigns.RetentionSettings) int { return r.RequestLogDays },
+			Purge: syncRepo.PurgeRequestLogsBefore,
+		})
+	}
+	go campaigns.NewRetentionJob(campaignRepo, retentionTargets...).Start(context.Background())
+
+	// Request log rows past API_LOG_ROLLUP_AFTER become hourly totals. In
+	// cluster mode the first instance to tick each hour takes the run.
+	if a.PluginHealth.IsHealthy("syncapi") && a.Config.APILog.RollupAfter > 0 {
+		var rollupLeader func(ctx context.Context) bool
+		if a.Config.ClusterMode {
+			rollupLeader = func(ctx context.Context) bool {
+				return database.TryLock(ctx, a.Redis, "syncapi:request-log-rollup", 55*time.Minute)
+			}
+		}
+		go syncapi.NewRequestLogRollupJob(syncRepo, a.Config.APILog.RollupAfter, rollupLeader).Start(context.Background())
+	}
+
+	// --- Campaign Deletion Purge ---
+	// Owner deletes only mark a campaign; this hard-deletes campaigns whose
+	// seven-day grace period has ended (media cleanup and WASM hooks
+	// included, via CampaignService.Delete).
+	go campaigns.NewDeletionPurgeJob(campaignService).Start(context.Background())
+
+	// Scheduled campaign announcements notify members when they publish.
+	go campaigns.NewAnnouncementDeliveryJob(campaignAnnouncementService).Start(context.Background())
+
+	// --- Weekly Campaign Digest ---
+	// Pages come from the audit log; events, sessions and notifications from
+	// their plugins, each left out while its plugin is degraded.
+	digestSources := []campaigns.DigestSource{{
+		Heading: "New and updated pages",
+		Items:   digestPageItems(auditRepo, entityService),
+	}}
+	if a.PluginHealth.IsHealthy("calendar") {
+		digestSources = append(digestSources, campaigns.DigestSource{
+			Heading: "Upcoming events",
+			Items:   digestEventItems(calendarService),
+		})
+	}
+	if a.PluginHealth.IsHealthy("sessions") {
+		digestSources = append(digestSources,
+			campaigns.DigestSource{Heading: "Sessions this week", Items: digestSessionItems(sessionsService)},
+			campaigns.DigestSource{Heading: "Unread notifications", Items: digestNotificationItems(sessionsService)},
+		)
+	}
+	campaignDigestService.SetSources(digestSources...)
+
+	// --- Stream Overlays ---
+	// Widget data from the calendar and sessions plugins; a widget whose
+	// plugin is degraded is unavailable rather than broken on stream.
+	campaignHandler.SetOverlaySource(campaigns.OverlayRoster, overlayRosterData(entityService))
+	if a.PluginHealth.IsHealthy("calendar") {
+		campaignHandler.SetOverlaySource(campaigns.OverlayDate, overlayDateData(calendarService))
+	}
+	if a.PluginHealth.IsHealthy("sessions") {
+		campaignHandler.SetOverlaySource(campaigns.OverlaySession, overlaySessionData(sessionsService))
+		campaignHandler.SetOverlaySource(campaigns.OverlayRecap, overlayRecapData(sessionsService))
+	}
+
+	// --- Stream Mode ---
+	var streamCalendar campaigns.StreamCalendarFunc
+	if a.PluginHealth.IsHealthy("calendar") {
+		streamCalendar = streamCalendarSource(calendarService)
+	}
+	campaignHandler.SetStreamSources(streamPageSource(entityService), streamCalendar)
+	campaignHandler.SetStreamImageProxy(imageProxy.DisplaySrc)
+	go campaigns.NewDigestJob(campaignDigestService).Start(context.Background())
+
+	// Watched-page changes are sent once the page's edits settle.
+	go entities.NewWatchFlushJob(entityWatchService).Start(context.Background())
+
+	// Polls close themselves at their deadline (and post a winning date to
+	// the real-life calendar).
+	go sessions.NewPollCloseJob(sessionsService).Start(context.Background())
+
 	// --- Module Routes ---
 	// Game system reference pages and tooltip APIs.
 	// ref := e.Group("/ref")
@@ -0,0 +1,78 @@
+// Package app — stream_adapters.go builds stream mode's player projection
+// of pages and the calendar. Everything is loaded at the Player role with
+// no user, so per-user grants and GM-only content never reach the shared
+// screen, whoever opened it.
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// streamEventLimit is how many upcoming events the stream calendar lists.
+const streamEventLimit = 20
+
+// streamPageSource loads a page as players see it, with inline secrets
+// stripped from the entry.
+func streamPageSource(entitySvc entities.EntityService) campaigns.StreamPageFunc {
+	return func(ctx context.Context, campaignID, entityID string) (*campaigns.StreamPage, error) {
+		ent, err := entitySvc.GetByID(ctx, entityID)
+		if err != nil || ent == nil || ent.CampaignID != campaignID || ent.IsTemplate {
+			return nil, nil
+		}
+		access, err := entitySvc.CheckEntityAccess(ctx, ent.ID, int(campaigns.RolePlayer), "")
+		if err != nil || !access.CanView {
+			return nil, nil
+		}
+
+		page := &campaigns.StreamPage{ID: ent.ID, Name: ent.Name}
+		if et, err := entitySvc.GetEntityTypeByID(ctx, ent.EntityTypeID); err == nil && et != nil {
+			page.TypeName, page.TypeIcon, page.TypeColor = et.Name, et.Icon, et.Color
+		}
+		if ent.TypeLabel != nil && *ent.TypeLabel != "" {
+			page.TypeName = *ent.TypeLabel
+		}
+		if ent.ImagePath != nil && *ent.ImagePath != "" {
+			page.ImageURL = fmt.Sprintf("/media/%s", *ent.ImagePath)
+		}
+		if ent.EntryHTML != nil {
+			page.EntryHTML = sanitize.StripSecretsHTML(*ent.EntryHTML)
+		}
+		return page, nil
+	}
+}
+
+// streamCalendarSource shows the calendar's current date and the upcoming
+// events players can see.
+func streamCalendarSource(calendarSvc calendar.CalendarService) campaigns.StreamCalendarFunc {
+	return func(ctx context.Context, campaignID string) (*campaigns.StreamCalendar, error) {
+		cal, err := calendarSvc.GetCalendar(ctx, campaignID)
+		if err != nil || cal == nil {
+			return nil, err
+		}
+		events, err := calendarSvc.ListUpcomingEvents(ctx, cal.ID, streamEventLimit, int(campaigns.RolePlayer), "")
+		if err != nil {
+			return nil, err
+		}
+
+		out := &campaigns.StreamCalendar{
+			Name: cal.Name,
+			Today: digestEventDate(cal, calendar.Event{
+				Year: cal.CurrentYear, Month: cal.CurrentMonth, Day: cal.CurrentDay,
+			}),
+			Detail: cal.FormatCurrentTime(),
+		}
+		if season := cal.CurrentSeason(); season != nil {
+			out.Detail += " · " + season.Name
+		}
+		for _, ev := range events {
+			out.Events = append(out.Events, campaigns.StreamEvent{Name: ev.Name, Date: digestEventDate(cal, ev)})
+		}
+		return out, nil
+	}
+}
@@ -29,6 +29,27 @@ type Config struct {
 	// LogLevel controls log verbosity: "debug", "info", "warn", "error".
 	LogLevel string
 
+	// ClusterMode marks this instance as one of several behind a load
+	// balancer (CLUSTER_MODE). Shared state then lives in Redis: rate limit
+	// counters, WebSocket broadcasts and Foundry presence, and a lock keeps
+	// hourly jobs to one instance. Requires a real Redis and an explicit
+	// MEDIA_SIGNING_SECRET.
+	ClusterMode bool
+
+	// MetricsToken, when set (METRICS_TOKEN), lets scrapers read the
+	// per-campaign usage metrics at /metrics/usage with a bearer token
+	// instead of an admin session.
+	MetricsToken string
+
+	// InboundEmailDomain is the mail domain campaign email-in addresses are
+	// shown on (INBOUND_EMAIL_DOMAIN), e.g. "in.example.com" for
+	// <token>@in.example.com. The operator points the domain's inbound mail
+	// at /api/inbound/email. Unset: owners only see the webhook URL.
+	InboundEmailDomain string
+
+	// APILog holds API request log settings.
+	APILog APILogConfig
+
 	// Database holds MariaDB connection settings.
 	Database DatabaseConfig
 
@@ -41,6 +62,9 @@ type Config struct {
 	// Upload holds file upload settings.
 	Upload UploadConfig
 
+	// Security holds response security header settings.
+	Security SecurityConfig
+
 	// ExtensionsPath is the root directory for user-installed content extensions.
 	ExtensionsPath string
 
@@ -98,6 +122,15 @@ type DatabaseConfig struct {
 
 	// ConnMaxLifetime is how long a connection can be reused.
 	ConnMaxLifetime time.Duration
+
+	// SlowQueryThreshold is the duration at or above which a query is
+	// logged as slow (default 200ms, 0 disables slow query logging).
+	SlowQueryThreshold time.Duration
+
+	// ReplicaDSNs are optional read replica DSNs (go-sql-driver/mysql
+	// format), from the comma-separated DB_READ_REPLICAS. Empty means every
+	// query goes to the primary.
+	ReplicaDSNs []string
 }
 
 // DSN returns the go-sql-driver/mysql connection string. If DATABASE_URL was
@@ -142,10 +175,54 @@ func ensurePort(host, defaultPort string) string {
 	return host
 }
 
+// SecurityConfig holds settings for the security headers middleware.
+type SecurityConfig struct {
+	// CSPMode is "compat" (inline scripts allowed), "strict" (inline scripts
+	// need the per-request nonce) or "report-only" (compat enforced, strict
+	// reported). Defaults to report-only in development so violations show
+	// up in the console, compat elsewhere.
+	CSPMode string
+
+	// CSPReportURI is an optional endpoint browsers POST violations to.
+	CSPReportURI string
+
+	// HSTS sends Strict-Transport-Security. Defaults to on outside
+	// development.
+	HSTS bool
+}
+
 // RedisConfig holds Redis connection parameters.
 type RedisConfig struct {
 	// URL is the Redis connection URL (e.g., "redis://localhost:6379").
+	// "memory" (or empty) runs an in-process store instead, for small
+	// single-instance installs without a Redis server.
 	URL string
+
+	// Required makes an unreachable Redis fatal at startup instead of
+	// falling back to the in-process store. Set it when running more than
+	// one replica: in-process state is not shared between them.
+	Required bool
+}
+
+// InMemory reports whether the in-process store was asked for explicitly.
+func (r RedisConfig) InMemory() bool {
+	return r.URL == "" || strings.EqualFold(r.URL, "memory")
+}
+
+// APILogConfig controls how API-key requests are recorded. Logging is
+// buffered in Redis and inserted in batches, so these only trade detail for
+// database load.
+type APILogConfig struct {
+	// SampleRate is the share of successful requests written to the log
+	// (API_LOG_SAMPLE_RATE, 0 < rate <= 1, default 1). Failed requests
+	// are always written. Sampled rows are weighted so the dashboard
+	// counts still cover every request.
+	SampleRate float64
+
+	// RollupAfter is how long rows stay individually in the log before
+	// the hourly job folds them into per-hour totals (API_LOG_ROLLUP_AFTER,
+	// default 168h; 0 keeps every row).
+	RollupAfter time.Duration
 }
 
 // AuthConfig holds authentication settings.
@@ -194,6 +271,16 @@ func Load() (*Config, error) {
 		BaseURL:  strings.TrimRight(getEnv("BASE_URL", "http://localhost:8080"), "/"),
 		LogLevel: getEnv("LOG_LEVEL", "debug"),
 
+		ClusterMode:  getEnvBool("CLUSTER_MODE", false),
+		MetricsToken: getEnv("METRICS_TOKEN", ""),
+
+		InboundEmailDomain: strings.TrimSpace(getEnv("INBOUND_EMAIL_DOMAIN", "")),
+
+		APILog: APILogConfig{
+			SampleRate:  getEnvFloat("API_LOG_SAMPLE_RATE", 1),
+			RollupAfter: getEnvDuration("API_LOG_ROLLUP_AFTER", 7*24*time.Hour),
+		},
+
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost:3306"),
 			User:            getEnv("DB_USER", "chronicle"),
@@ -204,10 +291,14 @@ func Load() (*Config, error) {
 			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+			SlowQueryThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			ReplicaDSNs:        getEnvList("DB_READ_REPLICAS"),
 		},
 
 		Redis: RedisConfig{
-			URL: getEnv("REDIS_URL", "redis://localhost:6379"),
+			URL:      getEnv("REDIS_URL", "redis://localhost:6379"),
+			Required: getEnvBool("REDIS_REQUIRED", false),
 		},
 
 		Auth: AuthConfig{
@@ -230,6 +321,28 @@ func Load() (*Config, error) {
 		},
 	}
 
+	defaultCSP := "compat"
+	if cfg.IsDevelopment() {
+		defaultCSP = "report-only"
+	}
+	cfg.Security = SecurityConfig{
+		CSPMode:      strings.ToLower(getEnv("CSP_MODE", defaultCSP)),
+		CSPReportURI: getEnv("CSP_REPORT_URI", ""),
+		HSTS:         getEnvBool("HSTS", !cfg.IsDevelopment()),
+	}
+	switch cfg.Security.CSPMode {
+	case "compat", "strict", "report-only":
+	default:
+		return nil, fmt.Errorf("CSP_MODE must be compat, strict or report-only, got %q", cfg.Security.CSPMode)
+	}
+
+	if r := cfg.APILog.SampleRate; r <= 0 || r > 1 {
+		return nil, fmt.Errorf("API_LOG_SAMPLE_RATE must be greater than 0 and at most 1, got %v", r)
+	}
+	if cfg.APILog.RollupAfter < 0 {
+		return nil, fmt.Errorf("API_LOG_ROLLUP_AFTER must not be negative")
+	}
+
 	// Validate required fields in production. Case-insensitive check catches
 	// common variants like "Production", "prod", etc.
 	envLower := strings.ToLower(cfg.Env)
@@ -246,6 +359,19 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// Cluster mode needs every instance to share state and secrets: an
+	// in-process Redis or a per-instance generated signing secret would
+	// split sessions and break signed media URLs between instances.
+	if cfg.ClusterMode {
+		if cfg.Redis.InMemory() {
+			return nil, fmt.Errorf("CLUSTER_MODE needs a shared Redis; REDIS_URL=memory is per instance")
+		}
+		cfg.Redis.Required = true
+		if cfg.Upload.SigningSecret == "" {
+			return nil, fmt.Errorf("CLUSTER_MODE needs MEDIA_SIGNING_SECRET set to the same value on every instance")
+		}
+	}
+
 	// Provide a dev-only default secret so local dev works without .env.
 	if cfg.Auth.SecretKey == "" {
 		cfg.Auth.SecretKey = "dev-secret-key-do-not-use-in-production!!"
@@ -290,6 +416,39 @@ func getEnvInt64(key string, defaultVal int64) int64 {
 	return defaultVal
 }
 
+// getEnvFloat reads a float env var or returns the default.
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val, ok := os.LookupEnv(key); ok {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return defaultVal
+}
+
+// getEnvBool reads a boolean env var ("true", "1", "false", ...) or returns
+// the default.
+func getEnvBool(key string, defaultVal bool) bool {
+	if val, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
+// getEnvList reads a comma-separated environment variable, dropping empty
+// entries. Returns nil when unset.
+func getEnvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // getEnvDuration reads a duration env var (e.g., "720h") or returns the default.
 func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
 	if val, ok := os.LookupEnv(key); ok {
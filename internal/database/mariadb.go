@@ -11,20 +11,27 @@ import (
 	"log/slog"
 	"time"
 
-	// MariaDB driver -- imported for side effect of registering the driver.
-	_ "github.com/go-sql-driver/mysql"
+	// MariaDB driver. Also registers "mysql" for the migration runner's
+	// sql.Open.
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/keyxmakerx/chronicle/internal/config"
 )
 
 // NewMariaDB creates a new MariaDB connection pool configured with the
 // settings from the provided config. It pings the database to verify
-// connectivity before returning.
-func NewMariaDB(cfg config.DatabaseConfig) (*sql.DB, error) {
-	db, err := sql.Open("mysql", cfg.DSN())
+// connectivity before returning. Every query on the pool is timed into
+// stats (see query_stats.go).
+func NewMariaDB(cfg config.DatabaseConfig, stats *QueryStats) (*sql.DB, error) {
+	mysqlCfg, err := mysql.ParseDSN(cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("parsing mariadb dsn: %w", err)
+	}
+	connector, err := mysql.NewConnector(mysqlCfg)
 	if err != nil {
 		return nil, fmt.Errorf("opening mariadb connection: %w", err)
 	}
+	db := sql.OpenDB(stats.Connector(connector))
 
 	// Configure connection pool settings to prevent connection exhaustion
 	// and stale connections under load.
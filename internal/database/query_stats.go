@@ -0,0 +1,392 @@
+package database
+
+// query_stats.go — an instrumented driver.Connector that times every query.
+// Counts and durations are attributed to the request that issued them (via
+// the context from WithRequestQueries), aggregated per route for the admin
+// Database page, and queries slower than the configured threshold are
+// logged with the route that ran them.
+//
+// Timing covers the round trip until the driver returns — for SELECTs that
+// is until the first rows arrive, not until the caller has scanned them all.
+
+import (
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// slowQueryHistory is how many recent slow queries the admin page shows.
+	slowQueryHistory = 50
+
+	// slowQueryMaxLen truncates logged query text.
+	slowQueryMaxLen = 500
+
+	// queryStatsTopRoutes is how many routes the snapshot lists.
+	queryStatsTopRoutes = 25
+
+	// backgroundRoute labels queries issued outside an HTTP request
+	// (jobs, schedulers, startup).
+	backgroundRoute = "(background)"
+)
+
+// QueryStats aggregates query timings for the life of the process.
+type QueryStats struct {
+	slowThreshold time.Duration
+	started       time.Time
+
+	mu     sync.Mutex
+	total  queryAgg
+	routes map[string]*queryAgg
+	slow   []SlowQuery // oldest first, capped at slowQueryHistory
+}
+
+// queryAgg accumulates one route's (or the overall) numbers.
+type queryAgg struct {
+	requests int64
+	queries  int64
+	slow     int64
+	total    time.Duration
+	max      time.Duration
+}
+
+// SlowQuery is one query that took at least the slow threshold.
+type SlowQuery struct {
+	At       time.Time     `json:"at"`
+	Route    string        `json:"route"`
+	Query    string        `json:"query"`
+	Duration time.Duration `json:"duration"`
+}
+
+// RouteQueryStats is a route's aggregate in a snapshot.
+type RouteQueryStats struct {
+	Route    string        `json:"route"`
+	Requests int64         `json:"requests"`
+	Queries  int64         `json:"queries"`
+	Slow     int64         `json:"slow"`
+	Total    time.Duration `json:"total"`
+	Max      time.Duration `json:"max"`
+}
+
+// QueriesPerRequest is the average query count per request, 0 for
+// background work.
+func (r RouteQueryStats) QueriesPerRequest() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Queries) / float64(r.Requests)
+}
+
+// QueryStatsSnapshot is a point-in-time copy for display.
+type QueryStatsSnapshot struct {
+	Since         time.Time         `json:"since"`
+	SlowThreshold time.Duration     `json:"slowThreshold"`
+	Queries       int64             `json:"queries"`
+	Slow          int64             `json:"slow"`
+	Total         time.Duration     `json:"total"`
+	Routes        []RouteQueryStats `json:"routes"`     // by total time, descending
+	RecentSlow    []SlowQuery       `json:"recentSlow"` // newest first
+}
+
+// NewQueryStats creates a collector. A zero slowThreshold disables slow
+// query logging; counting still happens.
+func NewQueryStats(slowThreshold time.Duration) *QueryStats {
+	return &QueryStats{
+		slowThreshold: slowThreshold,
+		started:       time.Now(),
+		routes:        make(map[string]*queryAgg),
+	}
+}
+
+// RequestQueries counts the queries of one request and remembers whether
+// it has written, which pins its later reads to the primary (replicas.go).
+type RequestQueries struct {
+	route string
+	count atomic.Int64
+	nanos atomic.Int64
+	wrote atomic.Bool
+}
+
+// Count returns how many queries the request has run so far.
+func (r *RequestQueries) Count() int64 { return r.count.Load() }
+
+// Duration returns the request's total query time so far.
+func (r *RequestQueries) Duration() time.Duration { return time.Duration(r.nanos.Load()) }
+
+// Wrote reports whether the request has run an exec or opened a transaction.
+func (r *RequestQueries) Wrote() bool { return r.wrote.Load() }
+
+// markWrite flags the request in ctx as having written. Any exec counts,
+// even a no-op UPDATE; over-pinning to the primary is the safe direction.
+func markWrite(ctx context.Context) {
+	if rq, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries); ok {
+		rq.wrote.Store(true)
+	}
+}
+
+// requestQueriesKey is the context key for the current RequestQueries.
+type requestQueriesKey struct{}
+
+// WithRequestQueries returns a context whose queries are attributed to
+// route, and the counter they land in.
+func WithRequestQueries(ctx context.Context, route string) (context.Context, *RequestQueries) {
+	rq := &RequestQueries{route: route}
+	return context.WithValue(ctx, requestQueriesKey{}, rq), rq
+}
+
+// FinishRequest counts a completed request against its route, so the page
+// can show queries per request.
+func (s *QueryStats) FinishRequest(rq *RequestQueries) {
+	s.mu.Lock()
+	s.routeAgg(rq.route).requests++
+	s.mu.Unlock()
+}
+
+// routeAgg returns the aggregate for route, creating it. Caller holds mu.
+func (s *QueryStats) routeAgg(route string) *queryAgg {
+	agg := s.routes[route]
+	if agg == nil {
+		agg = &queryAgg{}
+		s.routes[route] = agg
+	}
+	return agg
+}
+
+// record folds one query into the request and the aggregates.
+func (s *QueryStats) record(ctx context.Context, query string, d time.Duration) {
+	route := backgroundRoute
+	if rq, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries); ok {
+		rq.count.Add(1)
+		rq.nanos.Add(int64(d))
+		route = rq.route
+	}
+	slow := s.slowThreshold > 0 && d >= s.slowThreshold
+
+	s.mu.Lock()
+	for _, agg := range []*queryAgg{&s.total, s.routeAgg(route)} {
+		agg.queries++
+		agg.total += d
+		agg.max = max(agg.max, d)
+		if slow {
+			agg.slow++
+		}
+	}
+	var text string
+	if slow {
+		text = compactQuery(query)
+		if len(s.slow) == slowQueryHistory {
+			s.slow = append(s.slow[:0], s.slow[1:]...)
+		}
+		s.slow = append(s.slow, SlowQuery{At: time.Now(), Route: route, Query: text, Duration: d})
+	}
+	s.mu.Unlock()
+
+	if slow {
+		// Arguments are left out on purpose: they carry user data.
+		slog.WarnContext(ctx, "slow query",
+			slog.String("route", route),
+			slog.Duration("duration", d),
+			slog.String("query", text),
+		)
+	}
+}
+
+// compactQuery collapses whitespace and truncates for logging.
+func compactQuery(q string) string {
+	q = strings.Join(strings.Fields(q), " ")
+	if len(q) > slowQueryMaxLen {
+		q = q[:slowQueryMaxLen] + "…"
+	}
+	return q
+}
+
+// Snapshot copies the current numbers.
+func (s *QueryStats) Snapshot() QueryStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := QueryStatsSnapshot{
+		Since:         s.started,
+		SlowThreshold: s.slowThreshold,
+		Queries:       s.total.queries,
+		Slow:          s.total.slow,
+		Total:         s.total.total,
+		Routes:        make([]RouteQueryStats, 0, len(s.routes)),
+		RecentSlow:    make([]SlowQuery, 0, len(s.slow)),
+	}
+	for route, agg := range s.routes {
+		if agg.queries == 0 {
+			continue
+		}
+		snap.Routes = append(snap.Routes, RouteQueryStats{
+			Route: route, Requests: agg.requests, Queries: agg.queries,
+			Slow: agg.slow, Total: agg.total, Max: agg.max,
+		})
+	}
+	sort.Slice(snap.Routes, func(i, j int) bool { return snap.Routes[i].Total > snap.Routes[j].Total })
+	if len(snap.Routes) > queryStatsTopRoutes {
+		snap.Routes = snap.Routes[:queryStatsTopRoutes]
+	}
+	for i := len(s.slow) - 1; i >= 0; i-- {
+		snap.RecentSlow = append(snap.RecentSlow, s.slow[i])
+	}
+	return snap
+}
+
+// Connector wraps a driver connector so every connection it opens is timed.
+func (s *QueryStats) Connector(base driver.Connector) driver.Connector {
+	return instrumentedConnector{Connector: base, stats: s}
+}
+
+// instrumentedConnector hands out instrumentedConns.
+type instrumentedConnector struct {
+	driver.Connector
+	stats *QueryStats
+}
+
+// Connect opens a connection on the wrapped connector.
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, stats: c.stats}, nil
+}
+
+// instrumentedConn times queries and passes every optional driver interface
+// database/sql looks for through to the real connection, so pooling,
+// argument conversion and transactions behave exactly as without it.
+type instrumentedConn struct {
+	driver.Conn
+	stats *QueryStats
+}
+
+// ExecContext times a direct exec. driver.ErrSkip (the driver wants a
+// prepared statement instead) is passed on untimed; the statement path
+// records it.
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.stats.record(ctx, query, time.Since(start))
+		markWrite(ctx)
+	}
+	return res, err
+}
+
+// QueryContext times a direct query; see ExecContext.
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.stats.record(ctx, query, time.Since(start))
+	}
+	return rows, err
+}
+
+// PrepareContext wraps the prepared statement so its executions are timed.
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, query: query, stats: c.stats}, nil
+}
+
+// BeginTx passes through to the driver. A transaction counts as a write:
+// reads after it must see what it committed.
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	markWrite(ctx)
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() // fallback for drivers without BeginTx
+}
+
+// Ping passes through to the driver.
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession passes through to the driver.
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid passes through to the driver.
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue passes argument conversion through to the driver.
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// instrumentedStmt times executions of a prepared statement.
+type instrumentedStmt struct {
+	driver.Stmt
+	query string
+	stats *QueryStats
+}
+
+// ExecContext times one execution.
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	defer func() { s.stats.record(ctx, s.query, time.Since(start)) }()
+	markWrite(ctx)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedToValues(args)) // fallback for old drivers
+}
+
+// QueryContext times one execution.
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	defer func() { s.stats.record(ctx, s.query, time.Since(start)) }()
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(namedToValues(args)) // fallback for old drivers
+}
+
+// namedToValues drops the names for drivers without context support.
+func namedToValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+	return values
+}
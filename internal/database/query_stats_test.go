@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeConnector/fakeConn are a minimal driver: direct exec/query succeed,
+// except queries containing "PREPARED", which return driver.ErrSkip so
+// database/sql falls back to a prepared statement — the path the MySQL
+// driver takes for queries with arguments. "SLOW" queries sleep.
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, "PREPARED") {
+		return nil, driver.ErrSkip
+	}
+	fakeRun(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "PREPARED") {
+		return nil, driver.ErrSkip
+	}
+	fakeRun(query)
+	return &fakeRows{}, nil
+}
+
+func fakeRun(query string) {
+	if strings.Contains(query, "SLOW") {
+		time.Sleep(15 * time.Millisecond)
+	}
+}
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	fakeRun(s.query)
+	return driver.RowsAffected(1), nil
+}
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	fakeRun(s.query)
+	return &fakeRows{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{ done bool }
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestQueryStats(t *testing.T) {
+	stats := NewQueryStats(10 * time.Millisecond)
+	db := sql.OpenDB(stats.Connector(fakeConnector{}))
+	defer db.Close()
+
+	ctx, rq := WithRequestQueries(context.Background(), "GET /campaigns/:id")
+	var n int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE PREPARED SET x = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT   SLOW\n  FROM t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	stats.FinishRequest(rq)
+
+	// Outside a request.
+	if _, err := db.ExecContext(context.Background(), "DELETE FROM jobs"); err != nil {
+		t.Fatal(err)
+	}
+
+	if rq.Count() != 3 {
+		t.Errorf("request counted %d queries, want 3 (ErrSkip must not count twice)", rq.Count())
+	}
+	if rq.Duration() < 10*time.Millisecond {
+		t.Errorf("request DB time %v does not include the slow query", rq.Duration())
+	}
+
+	snap := stats.Snapshot()
+	if snap.Queries != 4 || snap.Slow != 1 {
+		t.Errorf("totals: %d queries, %d slow; want 4 and 1", snap.Queries, snap.Slow)
+	}
+	routes := map[string]RouteQueryStats{}
+	for _, r := range snap.Routes {
+		routes[r.Route] = r
+	}
+	if r := routes["GET /campaigns/:id"]; r.Requests != 1 || r.Queries != 3 || r.Slow != 1 || r.QueriesPerRequest() != 3 {
+		t.Errorf("route stats = %+v", r)
+	}
+	if r := routes[backgroundRoute]; r.Queries != 1 || r.Requests != 0 {
+		t.Errorf("background stats = %+v", r)
+	}
+	if snap.Routes[0].Route != "GET /campaigns/:id" {
+		t.Errorf("routes not sorted by total time: %+v", snap.Routes)
+	}
+	if len(snap.RecentSlow) != 1 || snap.RecentSlow[0].Query != "SELECT SLOW FROM t" || snap.RecentSlow[0].Route != "GET /campaigns/:id" {
+		t.Errorf("recent slow = %+v", snap.RecentSlow)
+	}
+}
+
+func TestQueryStats_SlowHistoryCapped(t *testing.T) {
+	stats := NewQueryStats(time.Nanosecond)
+	for i := 0; i < slowQueryHistory+5; i++ {
+		stats.record(context.Background(), "SELECT "+strings.Repeat("x", i), time.Millisecond)
+	}
+	snap := stats.Snapshot()
+	if len(snap.RecentSlow) != slowQueryHistory {
+		t.Fatalf("kept %d slow queries, want %d", len(snap.RecentSlow), slowQueryHistory)
+	}
+	if want := "SELECT " + strings.Repeat("x", slowQueryHistory+4); snap.RecentSlow[0].Query != want {
+		t.Errorf("newest slow query first: got %q", snap.RecentSlow[0].Query)
+	}
+}
@@ -2,15 +2,74 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/keyxmakerx/chronicle/internal/config"
 )
 
+// OpenRedis returns the Redis client the app runs on. With REDIS_URL set to
+// "memory" it is the in-process store; otherwise it is the configured
+// server, falling back to the in-process store (with a warning) when the
+// server can't be reached and REDIS_REQUIRED is off. inMemory reports which
+// one the caller got.
+func OpenRedis(cfg config.RedisConfig) (client *redis.Client, inMemory bool, err error) {
+	if cfg.InMemory() {
+		client, err = NewMemoryRedis()
+		return client, true, err
+	}
+	client, err = NewRedis(cfg)
+	if err == nil || cfg.Required {
+		return client, false, err
+	}
+	slog.Warn("redis unreachable, falling back to the in-process store (set REDIS_REQUIRED=true to fail instead)",
+		slog.Any("error", err),
+	)
+	client, err = NewMemoryRedis()
+	return client, true, err
+}
+
+// NewMemoryRedis starts an in-process Redis-compatible store (miniredis) on
+// a random loopback port and returns a client for it, so sessions, rate
+// limits and caches keep working unchanged without a Redis server. Its data
+// lives only as long as the process: a restart logs everyone out, and it is
+// not shared between replicas. A random password keeps other local
+// processes off the port.
+func NewMemoryRedis() (*redis.Client, error) {
+	pw := make([]byte, 16)
+	if _, err := rand.Read(pw); err != nil {
+		return nil, fmt.Errorf("generating in-process redis password: %w", err)
+	}
+	password := hex.EncodeToString(pw)
+
+	m := miniredis.NewMiniRedis()
+	m.RequireAuth(password)
+	if err := m.StartAddr("127.0.0.1:0"); err != nil {
+		return nil, fmt.Errorf("starting in-process redis: %w", err)
+	}
+	go expireMemoryRedis(m)
+
+	return redis.NewClient(&redis.Options{Addr: m.Addr(), Password: password}), nil
+}
+
+// expireMemoryRedis advances the store's clock once a second. miniredis
+// only expires keys when told time has passed, and session expiry depends
+// on it. Runs for the life of the process.
+func expireMemoryRedis(m *miniredis.Miniredis) {
+	last := time.Now()
+	for range time.Tick(time.Second) {
+		now := time.Now()
+		m.FastForward(now.Sub(last))
+		last = now
+	}
+}
+
 // NewRedis creates a new Redis client from the given config. It parses the
 // URL, connects, and pings to verify connectivity before returning.
 func NewRedis(cfg config.RedisConfig) (*redis.Client, error) {
@@ -53,3 +112,16 @@ func NewRedis(cfg config.RedisConfig) (*redis.Client, error) {
 	_ = client.Close()
 	return nil, fmt.Errorf("pinging redis after %d attempts: %w", maxRetries, pingErr)
 }
+
+// TryLock takes a cluster-wide lock on name for ttl and reports whether
+// this caller got it. It is never released early: periodic jobs use it so
+// only the first instance to tick runs each period. Redis errors count as
+// not acquired, so an outage skips a run rather than doubling it.
+func TryLock(ctx context.Context, rdb *redis.Client, name string, ttl time.Duration) bool {
+	ok, err := rdb.SetNX(ctx, "lock:"+name, 1, ttl).Result()
+	if err != nil {
+		slog.Warn("cluster lock unavailable", slog.String("lock", name), slog.Any("error", err))
+		return false
+	}
+	return ok
+}
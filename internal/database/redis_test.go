@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewMemoryRedis(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewMemoryRedis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := client.Set(ctx, "k", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := client.Get(ctx, "k").Result(); err != nil || got != "v" {
+		t.Fatalf("Get = %q, %v; want v", got, err)
+	}
+
+	// Other local processes can't use the port without the password.
+	stranger := redis.NewClient(&redis.Options{Addr: client.Options().Addr})
+	defer stranger.Close()
+	if err := stranger.Get(ctx, "k").Err(); err == nil {
+		t.Error("unauthenticated client could read the store")
+	}
+
+	// TTLs expire in real time, which session expiry relies on.
+	if err := client.Set(ctx, "short", "v", time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for client.Exists(ctx, "short").Val() == 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("key with a 1s TTL never expired")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
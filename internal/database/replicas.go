@@ -0,0 +1,168 @@
+package database
+
+// replicas.go — optional MariaDB read replicas. Repositories that opt in ask
+// the DBRouter for a reader per call: a healthy replica (round-robin) for
+// plain reads during an HTTP request, the primary for everything else.
+//
+// Stickiness: once a request has written to the primary (an exec or a
+// transaction on the instrumented connection, see markWrite), every later
+// read in that request goes to the primary too, so a handler never reads
+// back stale data from a replica that hasn't caught up with its own write.
+// Reads outside a request (jobs, schedulers) always use the primary, since
+// they often write and read in a loop with no request to track it.
+//
+// Only listing and display reads should use Reader. Reads that feed a write
+// (load, modify, save) must stay on the primary or replica lag could turn
+// into lost updates.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/keyxmakerx/chronicle/internal/config"
+)
+
+// replicaHealthInterval is how often replicas are pinged.
+const replicaHealthInterval = 15 * time.Second
+
+// replicaPingTimeout bounds one health ping.
+const replicaPingTimeout = 2 * time.Second
+
+// DBRouter picks the pool for a query. With no replicas configured every
+// call returns the primary, so repositories can use it unconditionally.
+type DBRouter struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// replica is one read replica pool and its last health check result.
+type replica struct {
+	addr    string // for logs only; never the full DSN (it has the password)
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewDBRouter creates a router over primary and the given replica pools.
+// Replicas start healthy; Start keeps that current.
+func NewDBRouter(primary *sql.DB, replicas map[string]*sql.DB) *DBRouter {
+	r := &DBRouter{primary: primary}
+	for addr, db := range replicas {
+		rep := &replica{addr: addr, db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r
+}
+
+// Primary returns the primary pool.
+func (r *DBRouter) Primary() *sql.DB { return r.primary }
+
+// Reader returns the pool for a read-only query: a healthy replica when the
+// query runs inside a request that hasn't written yet, otherwise the primary.
+func (r *DBRouter) Reader(ctx context.Context) *sql.DB {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+	rq, ok := ctx.Value(requestQueriesKey{}).(*RequestQueries)
+	if !ok || rq.Wrote() {
+		return r.primary
+	}
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return r.primary
+}
+
+// Start pings the replicas until ctx is cancelled, taking failing ones out
+// of rotation and putting them back once they answer again.
+func (r *DBRouter) Start(ctx context.Context) {
+	if len(r.replicas) == 0 {
+		return
+	}
+	ticker := time.NewTicker(replicaHealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth pings every replica once and logs state changes.
+func (r *DBRouter) checkHealth(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			slog.Info("read replica back in rotation", slog.String("addr", rep.addr))
+		} else {
+			slog.Warn("read replica unreachable, reading from primary instead",
+				slog.String("addr", rep.addr),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// Close closes the replica pools. The primary is owned by the caller.
+func (r *DBRouter) Close() {
+	for _, rep := range r.replicas {
+		_ = rep.db.Close()
+	}
+}
+
+// OpenReplicas opens a pool per DB_READ_REPLICAS DSN, with the primary's
+// pool settings and the same query timing. Replicas are not pinged here: a
+// replica that is down at boot just starts out of rotation after the first
+// health check instead of blocking startup. The map is keyed by address.
+func OpenReplicas(cfg config.DatabaseConfig, stats *QueryStats) (map[string]*sql.DB, error) {
+	pools := make(map[string]*sql.DB, len(cfg.ReplicaDSNs))
+	for i, dsn := range cfg.ReplicaDSNs {
+		mysqlCfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			closePools(pools)
+			// The DSN itself is left out: it carries the password.
+			return nil, fmt.Errorf("parsing read replica %d dsn: %w", i+1, err)
+		}
+		// Scanning into time.Time relies on this, as on the primary.
+		mysqlCfg.ParseTime = true
+		connector, err := mysql.NewConnector(mysqlCfg)
+		if err != nil {
+			closePools(pools)
+			return nil, fmt.Errorf("opening read replica %d: %w", i+1, err)
+		}
+		db := sql.OpenDB(stats.Connector(connector))
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		pools[fmt.Sprintf("%d:%s", i+1, mysqlCfg.Addr)] = db
+	}
+	return pools, nil
+}
+
+// closePools closes pools opened before a later DSN failed.
+func closePools(pools map[string]*sql.DB) {
+	for _, db := range pools {
+		_ = db.Close()
+	}
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestDBRouterReader(t *testing.T) {
+	stats := NewQueryStats(0)
+	open := func() *sql.DB { return sql.OpenDB(stats.Connector(fakeConnector{})) }
+	primary, r1, r2 := open(), open(), open()
+	defer primary.Close()
+
+	router := NewDBRouter(primary, map[string]*sql.DB{"1:r1": r1, "2:r2": r2})
+	defer router.Close()
+	isReplica := func(db *sql.DB) bool { return db == r1 || db == r2 }
+
+	if got := NewDBRouter(primary, nil).Reader(context.Background()); got != primary {
+		t.Error("no replicas: want primary")
+	}
+	if got := router.Reader(context.Background()); got != primary {
+		t.Error("outside a request: want primary")
+	}
+
+	ctx, _ := WithRequestQueries(context.Background(), "GET /x")
+	seen := map[*sql.DB]bool{}
+	for i := 0; i < 4; i++ {
+		db := router.Reader(ctx)
+		if !isReplica(db) {
+			t.Fatalf("read %d before any write went to the primary", i)
+		}
+		seen[db] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("reads used %d replicas, want round-robin over 2", len(seen))
+	}
+
+	// A read on the primary doesn't pin the request.
+	if _, err := primary.QueryContext(ctx, "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if !isReplica(router.Reader(ctx)) {
+		t.Error("a read on the primary pinned the request")
+	}
+
+	// An exec does.
+	if _, err := primary.ExecContext(ctx, "UPDATE PREPARED SET x = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := router.Reader(ctx); got != primary {
+		t.Error("read after a write: want primary")
+	}
+
+	// So does a transaction, even before anything runs in it.
+	txCtx, _ := WithRequestQueries(context.Background(), "POST /x")
+	tx, err := primary.BeginTx(txCtx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = tx.Rollback()
+	if got := router.Reader(txCtx); got != primary {
+		t.Error("read after a transaction: want primary")
+	}
+}
+
+func TestDBRouterHealth(t *testing.T) {
+	stats := NewQueryStats(0)
+	primary := sql.OpenDB(stats.Connector(fakeConnector{}))
+	defer primary.Close()
+	up := sql.OpenDB(stats.Connector(fakeConnector{}))
+	down := sql.OpenDB(stats.Connector(fakeConnector{}))
+	_ = down.Close() // pings on a closed pool fail
+
+	router := NewDBRouter(primary, map[string]*sql.DB{"1:up": up, "2:down": down})
+	defer router.Close()
+	router.checkHealth(context.Background())
+
+	ctx, _ := WithRequestQueries(context.Background(), "GET /x")
+	for i := 0; i < 4; i++ {
+		if got := router.Reader(ctx); got != up {
+			t.Fatalf("read %d: want the healthy replica", i)
+		}
+	}
+
+	only := NewDBRouter(primary, map[string]*sql.DB{"1:down": down})
+	only.checkHealth(context.Background())
+	if got := only.Reader(ctx); got != primary {
+		t.Error("every replica down: want primary")
+	}
+}
@@ -47,6 +47,9 @@ func Render(c echo.Context, statusCode int, component templ.Component) error {
 	if LayoutInjector != nil {
 		ctx = LayoutInjector(c, ctx)
 	}
+	// Inline scripts read the nonce with templ.GetNonce; templ's own
+	// script helpers pick it up automatically.
+	ctx = templ.WithNonce(ctx, CSPNonce(c))
 
 	c.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
 	c.Response().WriteHeader(statusCode)
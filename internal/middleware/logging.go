@@ -4,6 +4,7 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
 	"net/url"
 	"strings"
@@ -23,6 +24,11 @@ var sensitiveParams = []string{
 // structured fields: method, path, status, latency, and remote IP.
 // Uses Go's built-in slog for structured logging.
 //
+// It also assigns the request ID (returned in X-Request-ID) and puts it in
+// the request context, where NewContextHandler adds it — plus the user and
+// campaign, once known — to every context-aware log line. Registered
+// outermost so panic and error logs carry the ID too.
+//
 // Sensitive query parameters (token, key, password, secret) are redacted
 // to prevent credential leakage in log files.
 func RequestLogger() echo.MiddlewareFunc {
@@ -30,7 +36,17 @@ func RequestLogger() echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 
+			fields := &logFields{requestID: requestIDFor(c.Request().Header.Get(RequestIDHeader))}
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), logFieldsKey{}, fields)))
+			c.Response().Header().Set(RequestIDHeader, fields.requestID)
+
 			err := next(c)
+			if err != nil {
+				// Write the error response now so the logged status is the
+				// one the client gets, not the 200 placeholder.
+				c.Error(err)
+				err = nil
+			}
 
 			// Log after the request completes so we have the status code.
 			latency := time.Since(start)
@@ -46,6 +62,8 @@ func RequestLogger() echo.MiddlewareFunc {
 				slog.String("remote_ip", c.RealIP()),
 			}
 
+			attrs = append(attrs, fields.extra...)
+
 			// Include query string if present, redacting sensitive values.
 			if req.URL.RawQuery != "" {
 				attrs = append(attrs, slog.String("query", redactQuery(req.URL.RawQuery)))
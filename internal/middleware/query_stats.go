@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/database"
+)
+
+// QueryStats returns middleware that attributes the request's database
+// queries to its route ("GET /campaigns/:id"), folds them into the
+// process-wide aggregates on the admin Database page, and adds db_queries
+// and db_time to the request log line. A nil stats makes it a no-op.
+func QueryStats(stats *database.QueryStats) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if stats == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			route := c.Path()
+			if route == "" {
+				route = "(unmatched)"
+			}
+			ctx, rq := database.WithRequestQueries(c.Request().Context(), c.Request().Method+" "+route)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+
+			stats.FinishRequest(rq)
+			AddRequestLogAttrs(c,
+				slog.Int64("db_queries", rq.Count()),
+				slog.Duration("db_time", rq.Duration()),
+			)
+			return err
+		}
+	}
+}
@@ -1,65 +1,153 @@
 // Package middleware provides HTTP middleware for Chronicle.
-// ratelimit.go implements a per-IP rate limiter using a sliding window
-// counter stored in memory. Designed for auth endpoints and upload endpoints.
+// ratelimit.go implements a per-IP rate limiter using a fixed window
+// counter. Designed for auth endpoints and upload endpoints. Counts live
+// in memory, or in Redis once UseSharedRateLimits is called (cluster mode),
+// so every instance behind a load balancer enforces the same budget.
 package middleware
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 )
 
-// rateLimitEntry tracks request counts for a single IP within a time window.
+// rateLimitKeyPrefix namespaces shared rate limit counters in Redis.
+const rateLimitKeyPrefix = "ratelimit:"
+
+// sharedRateLimits is the Redis client shared counters use; nil keeps
+// every RateWindow in process.
+var sharedRateLimits atomic.Pointer[redis.Client]
+
+// UseSharedRateLimits moves every rate limiter's counts to Redis. Called at
+// startup in cluster mode; limiters created earlier switch over too, since
+// the client is read per request.
+func UseSharedRateLimits(rdb *redis.Client) {
+	sharedRateLimits.Store(rdb)
+}
+
+// rateLimitEntry tracks request counts for a single key within a time window.
 type rateLimitEntry struct {
 	count       int
 	windowStart time.Time
 }
 
-// RateLimit returns middleware that limits requests per IP to maxRequests
-// within the given window duration. Returns 429 when exceeded.
-func RateLimit(maxRequests int, window time.Duration) echo.MiddlewareFunc {
-	var mu sync.Mutex
-	entries := make(map[string]*rateLimitEntry)
+// RateWindow counts requests per key in fixed windows for one limiter.
+// Plugins with their own limit rules (per user, per API key) build on it
+// so they share across instances the same way RateLimit does.
+type RateWindow struct {
+	scope  string
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*rateLimitEntry
+}
+
+// NewRateWindow creates a counter with windows of the given length. scope
+// names its shared Redis keys; "" scopes them by route ("GET /path"), so a
+// limiter attached to several routes gives each route its own budget in
+// cluster mode. In process, one RateWindow is always one budget.
+func NewRateWindow(scope string, window time.Duration) *RateWindow {
+	w := &RateWindow{scope: scope, window: window, entries: make(map[string]*rateLimitEntry)}
 
 	// Background cleanup of expired entries every minute.
 	go func() {
 		for {
 			time.Sleep(time.Minute)
-			mu.Lock()
+			w.mu.Lock()
 			now := time.Now()
-			for ip, entry := range entries {
+			for key, entry := range w.entries {
 				if now.Sub(entry.windowStart) > window*2 {
-					delete(entries, ip)
+					delete(w.entries, key)
 				}
 			}
-			mu.Unlock()
+			w.mu.Unlock()
 		}
 	}()
+	return w
+}
 
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			ip := c.RealIP()
-			now := time.Now()
+// Hit counts one request for key and returns the count in the current
+// window, this one included, and how long until the window resets.
+func (w *RateWindow) Hit(c echo.Context, key string) (count int, resetIn time.Duration) {
+	if rdb := sharedRateLimits.Load(); rdb != nil {
+		scope := w.scope
+		if scope == "" {
+			scope = c.Request().Method + " " + c.Path()
+		}
+		n, reset, err := w.hitShared(c.Request().Context(), rdb, rateLimitKeyPrefix+scope+":"+key)
+		if err == nil {
+			return n, reset
+		}
+		// Fall back to this instance's count rather than failing open:
+		// a Redis outage shouldn't lift every limit at once.
+		slog.WarnContext(c.Request().Context(), "shared rate limit unavailable, counting locally",
+			slog.Any("error", err),
+		)
+	}
+	return w.hitLocal(key)
+}
 
-			mu.Lock()
-			entry, exists := entries[ip]
-			if !exists || now.Sub(entry.windowStart) > window {
-				entries[ip] = &rateLimitEntry{count: 1, windowStart: now}
-				mu.Unlock()
-				return next(c)
-			}
+// hitLocal counts in process.
+func (w *RateWindow) hitLocal(key string) (int, time.Duration) {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, exists := w.entries[key]
+	if !exists || now.Sub(entry.windowStart) > w.window {
+		w.entries[key] = &rateLimitEntry{count: 1, windowStart: now}
+		return 1, w.window
+	}
+	entry.count++
+	return entry.count, w.window - now.Sub(entry.windowStart)
+}
+
+// hitShared counts in Redis: INCR, with the window set as the key's expiry
+// by whichever request starts it. A key found without an expiry (the
+// setting request died between the two calls) gets one here, so a counter
+// can never block forever.
+func (w *RateWindow) hitShared(ctx context.Context, rdb *redis.Client, key string) (int, time.Duration, error) {
+	pipe := rdb.Pipeline()
+	incr := pipe.Incr(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	resetIn := ttl.Val()
+	if resetIn < 0 {
+		if err := rdb.PExpire(ctx, key, w.window).Err(); err != nil {
+			return 0, 0, err
+		}
+		resetIn = w.window
+	}
+	return int(incr.Val()), resetIn, nil
+}
 
-			entry.count++
-			if entry.count > maxRequests {
-				mu.Unlock()
+// RateLimit returns middleware that limits requests per IP to maxRequests
+// within the given window duration. Returns 429 when exceeded.
+func RateLimit(maxRequests int, window time.Duration) echo.MiddlewareFunc {
+	return DynamicRateLimit(func() int { return maxRequests }, window)
+}
+
+// DynamicRateLimit is RateLimit with the cap read on every request, so an
+// admin-edited limit (runtime settings) applies without a restart. limit
+// must return a positive value.
+func DynamicRateLimit(limit func() int, window time.Duration) echo.MiddlewareFunc {
+	counter := NewRateWindow("", window)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if count, _ := counter.Hit(c, c.RealIP()); count > limit() {
 				return c.JSON(http.StatusTooManyRequests, map[string]string{
 					"error":   "Too Many Requests",
 					"message": "Rate limit exceeded. Please try again later.",
 				})
 			}
-			mu.Unlock()
 			return next(c)
 		}
 	}
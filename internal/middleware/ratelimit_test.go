@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// limitedServer serves GET /x behind DynamicRateLimit(2/min) and returns a
+// function issuing one request and returning its status.
+func limitedServer() func() int {
+	e := echo.New()
+	e.GET("/x", func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+		RateLimit(2, time.Minute))
+	return func() int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/x", nil))
+		return rec.Code
+	}
+}
+
+func TestRateLimit_Local(t *testing.T) {
+	get := limitedServer()
+	for i, want := range []int{200, 200, 429, 429} {
+		if got := get(); got != want {
+			t.Errorf("request %d: status %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+// TestRateLimit_Shared checks cluster mode: two servers (two instances)
+// draw on one budget, and the counter expires with the window.
+func TestRateLimit_Shared(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	UseSharedRateLimits(rdb)
+	defer UseSharedRateLimits(nil)
+
+	a, b := limitedServer(), limitedServer()
+	if a() != 200 || b() != 200 {
+		t.Fatal("first two requests across instances should pass")
+	}
+	if got := a(); got != 429 {
+		t.Errorf("third request: status %d, want 429 (budget is shared)", got)
+	}
+
+	mr.FastForward(time.Minute)
+	if got := b(); got != 200 {
+		t.Errorf("after the window: status %d, want 200", got)
+	}
+}
+
+func TestRateWindow_SharedFallsBackToLocal(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialerRetries: 1})
+	UseSharedRateLimits(rdb)
+	defer UseSharedRateLimits(nil)
+	mr.Close() // Redis gone
+
+	w := NewRateWindow("test", time.Minute)
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	w.Hit(c, "k")
+	if n, _ := w.Hit(c, "k"); n != 2 {
+		t.Errorf("local fallback count = %d, want 2", n)
+	}
+}
@@ -18,7 +18,7 @@ func Recovery() echo.MiddlewareFunc {
 				if r := recover(); r != nil {
 					// Log the panic with full stack trace for debugging.
 					stack := debug.Stack()
-					slog.Error("panic recovered",
+					slog.ErrorContext(c.Request().Context(), "panic recovered",
 						slog.Any("panic", r),
 						slog.String("stack", string(stack)),
 						slog.String("method", c.Request().Method),
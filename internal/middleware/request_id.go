@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestIDHeader carries the request ID both ways: a well-formed inbound
+// value (from a reverse proxy) is kept, and every response echoes the ID so
+// a user reporting a problem can quote it.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDMaxLen caps an inbound request ID; longer values are replaced.
+const requestIDMaxLen = 64
+
+// logFieldsKey is the context key for the per-request log correlation fields.
+type logFieldsKey struct{}
+
+// logFields is shared by pointer through the request context so auth and
+// campaign middleware, which run after RequestLogger, can fill in the user
+// and campaign for every later log line (and the final request line).
+type logFields struct {
+	requestID  string
+	userID     string
+	campaignID string
+
+	// extra is added to the final request line only.
+	extra []slog.Attr
+}
+
+// RequestID returns the request ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	if f, ok := ctx.Value(logFieldsKey{}).(*logFields); ok {
+		return f.requestID
+	}
+	return ""
+}
+
+// SetLogUser records the authenticated user for this request's log lines.
+// Called by the auth middleware once a session is resolved.
+func SetLogUser(c echo.Context, userID string) {
+	if f, ok := c.Request().Context().Value(logFieldsKey{}).(*logFields); ok {
+		f.userID = userID
+	}
+}
+
+// SetLogCampaign records the campaign for this request's log lines. Called
+// by the campaign middleware once the campaign is resolved.
+func SetLogCampaign(c echo.Context, campaignID string) {
+	if f, ok := c.Request().Context().Value(logFieldsKey{}).(*logFields); ok {
+		f.campaignID = campaignID
+	}
+}
+
+// AddRequestLogAttrs adds fields to this request's final log line, for
+// per-request measurements taken by inner middleware.
+func AddRequestLogAttrs(c echo.Context, attrs ...slog.Attr) {
+	if f, ok := c.Request().Context().Value(logFieldsKey{}).(*logFields); ok {
+		f.extra = append(f.extra, attrs...)
+	}
+}
+
+// requestIDFor returns the inbound request ID when it is safe to log
+// verbatim, otherwise a fresh random one.
+func requestIDFor(inbound string) string {
+	if inbound != "" && len(inbound) <= requestIDMaxLen && isRequestIDSafe(inbound) {
+		return inbound
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isRequestIDSafe allows letters, digits, '-', '_' and '.', which covers
+// UUIDs and the common proxy formats while keeping log injection out.
+func isRequestIDSafe(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') &&
+			r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// contextHandler adds request_id, user_id and campaign_id to every record
+// logged with a request context (slog.InfoContext, slog.LogAttrs(ctx, ...)).
+type contextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h so context-aware log calls carry the request's
+// correlation fields. Installed around the default handler in main.
+func NewContextHandler(h slog.Handler) slog.Handler {
+	return contextHandler{Handler: h}
+}
+
+// Handle adds the correlation fields, if any, and passes the record on.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f, ok := ctx.Value(logFieldsKey{}).(*logFields); ok {
+		r.AddAttrs(slog.String("request_id", f.requestID))
+		if f.userID != "" {
+			r.AddAttrs(slog.String("user_id", f.userID))
+		}
+		if f.campaignID != "" {
+			r.AddAttrs(slog.String("campaign_id", f.campaignID))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs keeps the wrapper around derived handlers.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the wrapper around derived handlers.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
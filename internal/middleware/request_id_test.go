@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequestLogger_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	e := echo.New()
+	e.Use(RequestLogger())
+	e.GET("/c/:id", func(c echo.Context) error {
+		SetLogUser(c, "user-1")
+		SetLogCampaign(c, c.Param("id"))
+		slog.InfoContext(c.Request().Context(), "handler ran")
+		return echo.NewHTTPError(http.StatusTeapot)
+	})
+
+	tests := []struct {
+		name    string
+		inbound string
+		keep    bool
+	}{
+		{name: "generated", inbound: ""},
+		{name: "proxy id kept", inbound: "3f2a9c1e-7b7d-4d8e-9f00-1c2b3a4d5e6f", keep: true},
+		{name: "unsafe id replaced", inbound: "abc\ninjected=1"},
+		{name: "overlong id replaced", inbound: strings.Repeat("a", requestIDMaxLen+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/c/camp-9", nil)
+			if tt.inbound != "" {
+				req.Header.Set(RequestIDHeader, tt.inbound)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if id == "" {
+				t.Fatal("no X-Request-ID on the response")
+			}
+			if tt.keep != (id == tt.inbound) {
+				t.Errorf("request ID = %q, inbound %q, keep %v", id, tt.inbound, tt.keep)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("got %d log lines, want handler + request:\n%s", len(lines), buf.String())
+			}
+			for _, line := range lines {
+				var rec map[string]any
+				if err := json.Unmarshal([]byte(line), &rec); err != nil {
+					t.Fatal(err)
+				}
+				if rec["request_id"] != id || rec["user_id"] != "user-1" || rec["campaign_id"] != "camp-9" {
+					t.Errorf("log line missing correlation fields: %s", line)
+				}
+			}
+			// The request line reports the status the client actually got.
+			if !strings.Contains(lines[1], `"status":418`) {
+				t.Errorf("request line status: %s", lines[1])
+			}
+		})
+	}
+}
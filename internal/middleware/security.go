@@ -1,16 +1,73 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
 	"github.com/labstack/echo/v4"
 )
 
+// CSP modes. Compat is the long-standing policy: inline scripts run without a
+// nonce. Strict drops 'unsafe-inline' from script-src so only self-hosted
+// files and nonce-tagged inline scripts execute. Report-only enforces compat
+// and sends strict as Content-Security-Policy-Report-Only, so an operator can
+// see in the browser console what strict would block before switching.
+const (
+	CSPCompat     = "compat"
+	CSPStrict     = "strict"
+	CSPReportOnly = "report-only"
+)
+
+// SecurityConfig holds configuration for the security headers middleware.
+type SecurityConfig struct {
+	// CSPMode is one of CSPCompat, CSPStrict or CSPReportOnly. Empty means
+	// compat.
+	CSPMode string
+
+	// CSPReportURI, when set, is added as the policy's report-uri so
+	// violations are POSTed there as well as logged in the console.
+	CSPReportURI string
+
+	// HSTS sends Strict-Transport-Security. Off for plain-HTTP development
+	// hosts, where a stray HSTS pin on a LAN name is painful to undo.
+	HSTS bool
+}
+
+// cspNonceKey is the Echo context key for the per-request script nonce.
+const cspNonceKey = "csp_nonce"
+
+// cspConfigKey is the Echo context key for the SecurityConfig in effect, so
+// ExtendCSP can rebuild the same policy with extra origins.
+const cspConfigKey = "csp_config"
+
+// CSPNonce returns this request's script nonce, or "" outside the security
+// middleware. Inline <script> tags must carry it to run under strict mode;
+// Render hands it to templates via templ.WithNonce.
+func CSPNonce(c echo.Context) string {
+	nonce, _ := c.Get(cspNonceKey).(string)
+	return nonce
+}
+
+// newCSPNonce returns 128 random bits, base64-encoded.
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms; an empty nonce
+		// just means inline scripts won't run under strict.
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
 // SecurityHeaders returns middleware that sets security-related HTTP headers
 // on every response. These headers protect against common web attacks even
 // if application-level vulnerabilities exist.
 //
 // Since Chronicle runs behind Cosmos Cloud's reverse proxy, TLS is handled
 // externally. These headers provide defense-in-depth at the application layer.
-func SecurityHeaders() echo.MiddlewareFunc {
+func SecurityHeaders(cfg SecurityConfig) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			h := c.Response().Header()
@@ -18,38 +75,33 @@ func SecurityHeaders() echo.MiddlewareFunc {
 			// Content-Security-Policy: restrict what resources the browser can load.
 			// 'self' allows resources from the same origin only.
 			//
-			// SECURITY TRADEOFF: 'unsafe-inline' and 'unsafe-eval' are required by
-			// Alpine.js (x-* attribute expressions). This weakens XSS protection since
-			// inline scripts can execute. Mitigated by server-side HTML sanitization
-			// (bluemonday) on all user-generated content. Future improvement: migrate
-			// to nonce-based CSP or replace Alpine.js with a CSP-compatible alternative.
+			// SECURITY TRADEOFF: 'unsafe-eval' is required by Alpine.js (x-*
+			// attribute expressions are compiled with new Function). In compat
+			// mode 'unsafe-inline' is also allowed, which lets any inline script
+			// run; strict mode replaces it with a per-request nonce. Either way
+			// user content is sanitized server-side (bluemonday).
 			//
-			// Google Fonts + Font Awesome CDN are explicitly allowed.
-			// All scripts are self-hosted (vendored). No external script CDNs needed.
+			// All scripts are self-hosted (vendored). No external script CDNs needed,
+			// except an optional CAPTCHA provider on the auth forms (ExtendCSP).
 			// Google Fonts + Font Awesome CDN are explicitly allowed for fonts/styles.
-			h.Set("Content-Security-Policy",
-				"default-src 'self'; "+
-					"script-src 'self' 'unsafe-inline' 'unsafe-eval'; "+
-					"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com https://cdnjs.cloudflare.com; "+
-					"img-src 'self' data: blob:; "+
-					"font-src 'self' https://fonts.gstatic.com https://cdnjs.cloudflare.com; "+
-					"connect-src 'self'; "+
-					"frame-ancestors 'none'; "+
-					"base-uri 'self'; "+
-					"form-action 'self'",
-			)
+			nonce := newCSPNonce()
+			c.Set(cspNonceKey, nonce)
+			c.Set(cspConfigKey, cfg)
+			setCSP(h, cfg, nonce, nil)
 
 			// Cross-Origin-Opener-Policy: isolate the browsing context from
-		// cross-origin popups. Mitigates Spectre-class side-channel attacks
-		// and XS-Leaks. Safe for same-origin self-hosted apps.
-		// NOTE: We do NOT set Cross-Origin-Resource-Policy because external
-		// clients (Foundry VTT) make cross-origin API requests via CORS.
-		h.Set("Cross-Origin-Opener-Policy", "same-origin")
+			// cross-origin popups. Mitigates Spectre-class side-channel attacks
+			// and XS-Leaks. Safe for same-origin self-hosted apps.
+			// NOTE: We do NOT set Cross-Origin-Resource-Policy because external
+			// clients (Foundry VTT) make cross-origin API requests via CORS.
+			h.Set("Cross-Origin-Opener-Policy", "same-origin")
 
-		// Strict-Transport-Security: enforce HTTPS for 1 year including subdomains.
+			// Strict-Transport-Security: enforce HTTPS for 1 year including subdomains.
 			// Chronicle runs behind a reverse proxy that terminates TLS; this header
 			// tells browsers to always use HTTPS for subsequent requests.
-			h.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			if cfg.HSTS {
+				h.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			}
 
 			// X-Content-Type-Options: prevent MIME type sniffing.
 			h.Set("X-Content-Type-Options", "nosniff")
@@ -75,3 +127,79 @@ func SecurityHeaders() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// setCSP writes the policy headers for the configured mode.
+func setCSP(h http.Header, cfg SecurityConfig, nonce string, extra []string) {
+	switch cfg.CSPMode {
+	case CSPStrict:
+		h.Set("Content-Security-Policy", contentSecurityPolicy(extra, nonce, cfg.CSPReportURI))
+	case CSPReportOnly:
+		h.Set("Content-Security-Policy", contentSecurityPolicy(extra, "", cfg.CSPReportURI))
+		h.Set("Content-Security-Policy-Report-Only", contentSecurityPolicy(extra, nonce, cfg.CSPReportURI))
+	default:
+		h.Set("Content-Security-Policy", contentSecurityPolicy(extra, "", cfg.CSPReportURI))
+	}
+}
+
+// contentSecurityPolicy builds the CSP header value. extra origins are
+// appended to script-src, style-src, frame-src, and connect-src; nil gives the
+// default self-hosted policy. A non-empty nonce gives the strict script-src
+// (no 'unsafe-inline'). Styles keep 'unsafe-inline' in every mode: the editor
+// and Alpine set inline styles constantly.
+func contentSecurityPolicy(extra []string, nonce, reportURI string) string {
+	with := func(base string) string {
+		if len(extra) == 0 {
+			return base
+		}
+		return base + " " + strings.Join(extra, " ")
+	}
+	scripts := "'self' 'unsafe-inline' 'unsafe-eval'"
+	if nonce != "" {
+		scripts = "'self' 'nonce-" + nonce + "' 'unsafe-eval'"
+	}
+	policy := "default-src 'self'; " +
+		"script-src " + with(scripts) + "; " +
+		"style-src " + with("'self' 'unsafe-inline' https://fonts.googleapis.com https://cdnjs.cloudflare.com") + "; " +
+		"img-src 'self' data: blob:; " +
+		"font-src 'self' https://fonts.gstatic.com https://cdnjs.cloudflare.com; " +
+		"connect-src " + with("'self'") + "; "
+	if len(extra) > 0 {
+		// frame-src otherwise falls back to default-src 'self'.
+		policy += "frame-src " + with("'self'") + "; "
+	}
+	policy += "frame-ancestors 'none'; " +
+		"base-uri 'self'; " +
+		"form-action 'self'"
+	if reportURI != "" {
+		policy += "; report-uri " + reportURI
+	}
+	return policy
+}
+
+// ExtendCSP widens this response's Content-Security-Policy to load scripts,
+// styles, frames, and XHR from the given origins. For the few pages that
+// embed a third-party widget (CAPTCHA on the public auth forms); everything
+// else keeps the self-hosted policy. Must be called before the body is
+// written.
+func ExtendCSP(c echo.Context, origins ...string) {
+	if len(origins) == 0 {
+		return
+	}
+	cfg, _ := c.Get(cspConfigKey).(SecurityConfig)
+	setCSP(c.Response().Header(), cfg, CSPNonce(c), origins)
+}
+
+// AllowFraming lets this response be embedded in a frame on any site by
+// dropping X-Frame-Options and relaxing CSP frame-ancestors. Only for
+// token-protected pages meant to be embedded, such as stream overlays
+// loaded as OBS browser sources. Must be called before the body is
+// written.
+func AllowFraming(c echo.Context) {
+	h := c.Response().Header()
+	h.Del("X-Frame-Options")
+	for _, name := range []string{"Content-Security-Policy", "Content-Security-Policy-Report-Only"} {
+		if v := h.Get(name); v != "" {
+			h.Set(name, strings.Replace(v, "frame-ancestors 'none'", "frame-ancestors *", 1))
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+func TestExtendCSP(t *testing.T) {
+	e := echo.New()
+	e.Use(SecurityHeaders(SecurityConfig{}))
+	e.GET("/plain", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.GET("/widget", func(c echo.Context) error {
+		ExtendCSP(c, "https://challenges.cloudflare.com")
+		return c.NoContent(http.StatusOK)
+	})
+
+	get := func(path string) string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Header().Get("Content-Security-Policy")
+	}
+
+	plain := get("/plain")
+	if strings.Contains(plain, "frame-src") {
+		t.Errorf("default policy must not allow third-party frames: %s", plain)
+	}
+	if !strings.Contains(plain, "script-src 'self' 'unsafe-inline' 'unsafe-eval';") {
+		t.Errorf("default script-src changed: %s", plain)
+	}
+
+	widget := get("/widget")
+	for _, want := range []string{
+		"script-src 'self' 'unsafe-inline' 'unsafe-eval' https://challenges.cloudflare.com;",
+		"frame-src 'self' https://challenges.cloudflare.com;",
+		"connect-src 'self' https://challenges.cloudflare.com;",
+		"frame-ancestors 'none'",
+	} {
+		if !strings.Contains(widget, want) {
+			t.Errorf("extended policy missing %q: %s", want, widget)
+		}
+	}
+}
+
+func TestSecurityHeaders_CSPModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          SecurityConfig
+		wantEnforced string // substring of Content-Security-Policy
+		wantReport   string // substring of the report-only header; "" = absent
+		wantHSTS     bool
+	}{
+		{
+			name:         "compat",
+			cfg:          SecurityConfig{HSTS: true},
+			wantEnforced: "script-src 'self' 'unsafe-inline' 'unsafe-eval';",
+			wantHSTS:     true,
+		},
+		{
+			name:         "strict",
+			cfg:          SecurityConfig{CSPMode: CSPStrict},
+			wantEnforced: "script-src 'self' 'nonce-",
+		},
+		{
+			name:         "report-only",
+			cfg:          SecurityConfig{CSPMode: CSPReportOnly, CSPReportURI: "/csp-report"},
+			wantEnforced: "'unsafe-inline' 'unsafe-eval'; ",
+			wantReport:   "script-src 'self' 'nonce-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(SecurityHeaders(tt.cfg))
+			var nonce string
+			e.GET("/", func(c echo.Context) error {
+				nonce = CSPNonce(c)
+				return c.NoContent(http.StatusOK)
+			})
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			enforced := rec.Header().Get("Content-Security-Policy")
+			if !strings.Contains(enforced, tt.wantEnforced) {
+				t.Errorf("CSP = %s, want %q", enforced, tt.wantEnforced)
+			}
+			report := rec.Header().Get("Content-Security-Policy-Report-Only")
+			if tt.wantReport == "" && report != "" {
+				t.Errorf("unexpected report-only policy: %s", report)
+			}
+			if tt.wantReport != "" && !strings.Contains(report, tt.wantReport) {
+				t.Errorf("report-only CSP = %s, want %q", report, tt.wantReport)
+			}
+			if tt.cfg.CSPReportURI != "" && !strings.HasSuffix(enforced, "; report-uri "+tt.cfg.CSPReportURI) {
+				t.Errorf("report-uri missing: %s", enforced)
+			}
+
+			strictPolicy := enforced
+			if tt.cfg.CSPMode == CSPReportOnly {
+				strictPolicy = report
+			}
+			if tt.cfg.CSPMode != "" {
+				if len(nonce) < 20 || !strings.Contains(strictPolicy, "'nonce-"+nonce+"'") {
+					t.Errorf("policy does not carry the request nonce %q: %s", nonce, strictPolicy)
+				}
+				if strings.Contains(strictPolicy, "script-src 'self' 'unsafe-inline'") {
+					t.Errorf("strict policy still allows inline scripts: %s", strictPolicy)
+				}
+			}
+			if got := rec.Header().Get("Strict-Transport-Security") != ""; got != tt.wantHSTS {
+				t.Errorf("HSTS sent = %v, want %v", got, tt.wantHSTS)
+			}
+		})
+	}
+}
+
+func TestSecurityHeaders_NonceReachesTemplates(t *testing.T) {
+	e := echo.New()
+	e.Use(SecurityHeaders(SecurityConfig{CSPMode: CSPStrict}))
+	e.GET("/", func(c echo.Context) error {
+		return Render(c, http.StatusOK, templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, templ.GetNonce(ctx))
+			return err
+		}))
+	})
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := rec.Body.String()
+		if nonce == "" || !strings.Contains(rec.Header().Get("Content-Security-Policy"), "'nonce-"+nonce+"'") {
+			t.Fatalf("template nonce %q does not match the policy", nonce)
+		}
+		seen[nonce] = true
+	}
+	if len(seen) != 2 {
+		t.Error("nonce reused across requests")
+	}
+}
+
+func TestAllowFraming(t *testing.T) {
+	e := echo.New()
+	e.Use(SecurityHeaders(SecurityConfig{CSPMode: CSPReportOnly}))
+	e.GET("/overlay", func(c echo.Context) error {
+		AllowFraming(c)
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overlay", nil))
+	if v := rec.Header().Get("X-Frame-Options"); v != "" {
+		t.Errorf("X-Frame-Options = %q, want unset", v)
+	}
+	for _, name := range []string{"Content-Security-Policy", "Content-Security-Policy-Report-Only"} {
+		csp := rec.Header().Get(name)
+		if !strings.Contains(csp, "frame-ancestors *") || strings.Contains(csp, "frame-ancestors 'none'") {
+			t.Errorf("%s not relaxed: %s", name, csp)
+		}
+	}
+}
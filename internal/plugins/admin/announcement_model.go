@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Announcement severity levels. They map onto the banner colors in the app
+// layout; the migration's CHECK constraint holds the same list.
+const (
+	SeverityInfo     = "info"
+	SeveritySuccess  = "success"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// announcementSeverities is the allowed severity set, in display order.
+var announcementSeverities = []string{SeverityInfo, SeveritySuccess, SeverityWarning, SeverityCritical}
+
+// maxAnnouncementBodyLength caps the banner text. Banners sit above every
+// page, so anything longer belongs in a linked page rather than the banner.
+const maxAnnouncementBodyLength = 2000
+
+// Announcement is an instance-wide banner published by a site admin.
+// StartsAt/EndsAt bound when it shows; nil means no bound on that side.
+type Announcement struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Severity    string     `json:"severity"`
+	Dismissible bool       `json:"dismissible"`
+	StartsAt    *time.Time `json:"startsAt,omitempty"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+	CreatedBy   string     `json:"createdBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+
+	// Joined for the admin list (not stored in announcements).
+	CreatedByName string `json:"createdByName,omitempty"`
+}
+
+// ActiveAt reports whether the announcement's schedule covers now.
+func (a *Announcement) ActiveAt(now time.Time) bool {
+	if a.StartsAt != nil && now.Before(*a.StartsAt) {
+		return false
+	}
+	if a.EndsAt != nil && !now.Before(*a.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Status labels the announcement's schedule relative to now for the admin
+// list: "scheduled", "active", or "ended".
+func (a *Announcement) Status(now time.Time) string {
+	switch {
+	case a.StartsAt != nil && now.Before(*a.StartsAt):
+		return "scheduled"
+	case a.EndsAt != nil && !now.Before(*a.EndsAt):
+		return "ended"
+	default:
+		return "active"
+	}
+}
+
+// AnnouncementInput is the create/update request body. Severity defaults to
+// info; Dismissible is a pointer so an omitted field means "yes" rather than
+// locking the banner on every page.
+type AnnouncementInput struct {
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Severity    string     `json:"severity"`
+	Dismissible *bool      `json:"dismissible"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+}
+
+// normalize trims the input, applies defaults, and validates it.
+func (in *AnnouncementInput) normalize() error {
+	in.Title = strings.TrimSpace(in.Title)
+	in.Body = strings.TrimSpace(in.Body)
+	in.Severity = strings.ToLower(strings.TrimSpace(in.Severity))
+	if in.Severity == "" {
+		in.Severity = SeverityInfo
+	}
+
+	if err := apperror.ValidateRequired("title", in.Title); err != nil {
+		return err
+	}
+	if err := apperror.ValidateStringLength("title", in.Title, apperror.MaxNameLength); err != nil {
+		return err
+	}
+	if err := apperror.ValidateStringLength("body", in.Body, maxAnnouncementBodyLength); err != nil {
+		return err
+	}
+	if !isAnnouncementSeverity(in.Severity) {
+		return apperror.NewBadRequest(fmt.Sprintf("severity must be one of %s", strings.Join(announcementSeverities, ", ")))
+	}
+	if in.StartsAt != nil && in.EndsAt != nil && !in.EndsAt.After(*in.StartsAt) {
+		return apperror.NewBadRequest("end time must be after start time")
+	}
+
+	// Stored as UTC DATETIME, like every other timestamp.
+	if in.StartsAt != nil {
+		t := in.StartsAt.UTC()
+		in.StartsAt = &t
+	}
+	if in.EndsAt != nil {
+		t := in.EndsAt.UTC()
+		in.EndsAt = &t
+	}
+	return nil
+}
+
+// dismissible resolves the Dismissible default.
+func (in *AnnouncementInput) dismissible() bool {
+	return in.Dismissible == nil || *in.Dismissible
+}
+
+func isAnnouncementSeverity(s string) bool {
+	for _, v := range announcementSeverities {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
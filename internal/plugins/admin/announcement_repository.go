@@ -0,0 +1,210 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// AnnouncementRepository defines the data access contract for instance
+// announcements and per-user dismissals.
+type AnnouncementRepository interface {
+	// List returns every announcement, newest first, with the creator's name.
+	List(ctx context.Context) ([]Announcement, error)
+
+	// FindByID returns one announcement or a 404.
+	FindByID(ctx context.Context, id int) (*Announcement, error)
+
+	// ListLive returns announcements that haven't ended as of now, including
+	// scheduled ones, so a cached copy can start showing them on time.
+	ListLive(ctx context.Context, now time.Time) ([]Announcement, error)
+
+	// Create inserts an announcement and sets its ID.
+	Create(ctx context.Context, a *Announcement) error
+
+	// Update overwrites an announcement's editable fields.
+	Update(ctx context.Context, a *Announcement) error
+
+	// Delete removes an announcement; dismissals cascade.
+	Delete(ctx context.Context, id int) error
+
+	// Dismiss records that a user closed an announcement. Idempotent.
+	Dismiss(ctx context.Context, id int, userID string) error
+
+	// DismissedIDs returns which of the given announcements the user has
+	// dismissed.
+	DismissedIDs(ctx context.Context, userID string, ids []int) (map[int]bool, error)
+}
+
+// announcementRepository implements AnnouncementRepository with MariaDB.
+type announcementRepository struct {
+	db *sql.DB
+}
+
+// NewAnnouncementRepository creates a new repository backed by the given DB.
+func NewAnnouncementRepository(db *sql.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+const announcementColumns = `a.id, a.title, a.body, a.severity, a.dismissible,
+	a.starts_at, a.ends_at, COALESCE(a.created_by, ''), a.created_at, a.updated_at,
+	COALESCE(u.display_name, '')`
+
+// List returns every announcement, newest first.
+func (r *announcementRepository) List(ctx context.Context) ([]Announcement, error) {
+	return r.query(ctx, `SELECT `+announcementColumns+`
+		FROM announcements a LEFT JOIN users u ON u.id = a.created_by
+		ORDER BY a.created_at DESC, a.id DESC`)
+}
+
+// FindByID returns one announcement.
+func (r *announcementRepository) FindByID(ctx context.Context, id int) (*Announcement, error) {
+	list, err := r.query(ctx, `SELECT `+announcementColumns+`
+		FROM announcements a LEFT JOIN users u ON u.id = a.created_by
+		WHERE a.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, apperror.NewNotFound("announcement not found")
+	}
+	return &list[0], nil
+}
+
+// ListLive returns announcements with no end or an end after now. Critical
+// first so the most important banner sits on top.
+func (r *announcementRepository) ListLive(ctx context.Context, now time.Time) ([]Announcement, error) {
+	return r.query(ctx, `SELECT `+announcementColumns+`
+		FROM announcements a LEFT JOIN users u ON u.id = a.created_by
+		WHERE a.ends_at IS NULL OR a.ends_at > ?
+		ORDER BY FIELD(a.severity, 'critical', 'warning', 'success', 'info'), a.created_at DESC`, now.UTC())
+}
+
+// query runs an announcement SELECT built on announcementColumns.
+func (r *announcementRepository) query(ctx context.Context, query string, args ...any) ([]Announcement, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Announcement
+	for rows.Next() {
+		var a Announcement
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Title, &a.Body, &a.Severity, &a.Dismissible,
+			&startsAt, &endsAt, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.CreatedByName); err != nil {
+			return nil, fmt.Errorf("scanning announcement: %w", err)
+		}
+		if startsAt.Valid {
+			a.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			a.EndsAt = &endsAt.Time
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// Create inserts an announcement.
+func (r *announcementRepository) Create(ctx context.Context, a *Announcement) error {
+	// Use NULL for an empty creator (foreign key compatibility).
+	var createdBy any
+	if a.CreatedBy != "" {
+		createdBy = a.CreatedBy
+	}
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO announcements (title, body, severity, dismissible, starts_at, ends_at, created_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.Title, a.Body, a.Severity, a.Dismissible, a.StartsAt, a.EndsAt, createdBy)
+	if err != nil {
+		return fmt.Errorf("inserting announcement: %w", err)
+	}
+	id, _ := result.LastInsertId()
+	a.ID = int(id)
+	return nil
+}
+
+// Update overwrites an announcement's editable fields.
+func (r *announcementRepository) Update(ctx context.Context, a *Announcement) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE announcements
+		 SET title = ?, body = ?, severity = ?, dismissible = ?, starts_at = ?, ends_at = ?
+		 WHERE id = ?`,
+		a.Title, a.Body, a.Severity, a.Dismissible, a.StartsAt, a.EndsAt, a.ID)
+	if err != nil {
+		return fmt.Errorf("updating announcement: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		// RowsAffected is 0 for an unchanged row too; tell the two apart.
+		var exists bool
+		if err := r.db.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM announcements WHERE id = ?)`, a.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("checking announcement: %w", err)
+		}
+		if !exists {
+			return apperror.NewNotFound("announcement not found")
+		}
+	}
+	return nil
+}
+
+// Delete removes an announcement.
+func (r *announcementRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM announcements WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting announcement: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apperror.NewNotFound("announcement not found")
+	}
+	return nil
+}
+
+// Dismiss records a dismissal. INSERT IGNORE makes a repeat click a no-op.
+func (r *announcementRepository) Dismiss(ctx context.Context, id int, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO announcement_dismissals (announcement_id, user_id) VALUES (?, ?)`,
+		id, userID)
+	if err != nil {
+		return fmt.Errorf("dismissing announcement: %w", err)
+	}
+	return nil
+}
+
+// DismissedIDs returns the subset of ids the user has dismissed.
+func (r *announcementRepository) DismissedIDs(ctx context.Context, userID string, ids []int) (map[int]bool, error) {
+	dismissed := make(map[int]bool)
+	if len(ids) == 0 {
+		return dismissed, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(ids))
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, userID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT announcement_id FROM announcement_dismissals
+		 WHERE user_id = ? AND announcement_id IN (`+placeholders[:len(placeholders)-1]+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing dismissals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning dismissal: %w", err)
+		}
+		dismissed[id] = true
+	}
+	return dismissed, rows.Err()
+}
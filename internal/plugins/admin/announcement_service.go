@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// announcementCacheTTL bounds how stale the cached live set can get. Writes
+// invalidate it immediately; the TTL only matters for other instances
+// behind a load balancer, and for scheduled banners reaching their end.
+const announcementCacheTTL = 30 * time.Second
+
+// AnnouncementService manages instance announcements and resolves which
+// banners a user should see.
+type AnnouncementService interface {
+	// List returns every announcement for the admin page.
+	List(ctx context.Context) ([]Announcement, error)
+
+	// Get returns one announcement.
+	Get(ctx context.Context, id int) (*Announcement, error)
+
+	// Create validates and publishes an announcement.
+	Create(ctx context.Context, in AnnouncementInput, createdBy string) (*Announcement, error)
+
+	// Update validates and replaces an announcement's fields.
+	Update(ctx context.Context, id int, in AnnouncementInput) (*Announcement, error)
+
+	// Delete removes an announcement.
+	Delete(ctx context.Context, id int) error
+
+	// ActiveFor returns the banners to show a user right now: inside their
+	// schedule and not dismissed by that user. Errors are logged and yield
+	// no banners, since this runs on every page render.
+	ActiveFor(ctx context.Context, userID string) []Announcement
+
+	// Dismiss hides an announcement for a user.
+	Dismiss(ctx context.Context, id int, userID string) error
+}
+
+// announcementService implements AnnouncementService.
+type announcementService struct {
+	repo AnnouncementRepository
+	now  func() time.Time
+
+	// Cached ListLive result. Nearly every page render asks for banners and
+	// there are usually none, so this keeps the common case off the DB.
+	mu       sync.Mutex
+	live     []Announcement
+	loadedAt time.Time
+}
+
+// NewAnnouncementService creates a new announcement service.
+func NewAnnouncementService(repo AnnouncementRepository) AnnouncementService {
+	return &announcementService{repo: repo, now: time.Now}
+}
+
+// List returns every announcement.
+func (s *announcementService) List(ctx context.Context) ([]Announcement, error) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return list, nil
+}
+
+// Get returns one announcement.
+func (s *announcementService) Get(ctx context.Context, id int) (*Announcement, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Create validates and inserts an announcement.
+func (s *announcementService) Create(ctx context.Context, in AnnouncementInput, createdBy string) (*Announcement, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+	a := &Announcement{
+		Title:       in.Title,
+		Body:        in.Body,
+		Severity:    in.Severity,
+		Dismissible: in.dismissible(),
+		StartsAt:    in.StartsAt,
+		EndsAt:      in.EndsAt,
+		CreatedBy:   createdBy,
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	s.invalidate()
+	return s.repo.FindByID(ctx, a.ID)
+}
+
+// Update validates and replaces an announcement's fields. Existing
+// dismissals are kept: an edit is a correction, not a new announcement.
+func (s *announcementService) Update(ctx context.Context, id int, in AnnouncementInput) (*Announcement, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+	a := &Announcement{
+		ID:          id,
+		Title:       in.Title,
+		Body:        in.Body,
+		Severity:    in.Severity,
+		Dismissible: in.dismissible(),
+		StartsAt:    in.StartsAt,
+		EndsAt:      in.EndsAt,
+	}
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete removes an announcement.
+func (s *announcementService) Delete(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ActiveFor filters the cached live set by schedule, then drops the user's
+// dismissals. The dismissal lookup only runs when a banner is showing.
+func (s *announcementService) ActiveFor(ctx context.Context, userID string) []Announcement {
+	live, err := s.liveSet(ctx)
+	if err != nil {
+		slog.Warn("announcements: failed to load live set", slog.Any("error", err))
+		return nil
+	}
+
+	now := s.now().UTC()
+	var active []Announcement
+	var ids []int
+	for _, a := range live {
+		if a.ActiveAt(now) {
+			active = append(active, a)
+			ids = append(ids, a.ID)
+		}
+	}
+	if len(active) == 0 || userID == "" {
+		return active
+	}
+
+	dismissed, err := s.repo.DismissedIDs(ctx, userID, ids)
+	if err != nil {
+		slog.Warn("announcements: failed to load dismissals",
+			slog.String("user_id", userID), slog.Any("error", err))
+		return active
+	}
+	out := active[:0]
+	for _, a := range active {
+		// A banner made non-dismissible after someone closed it shows again.
+		if !a.Dismissible || !dismissed[a.ID] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Dismiss records a dismissal after checking the banner allows it.
+func (s *announcementService) Dismiss(ctx context.Context, id int, userID string) error {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !a.Dismissible {
+		return apperror.NewBadRequest("this announcement cannot be dismissed")
+	}
+	if err := s.repo.Dismiss(ctx, id, userID); err != nil {
+		return apperror.NewInternal(err)
+	}
+	return nil
+}
+
+// liveSet returns the cached ListLive result, reloading it after the TTL.
+func (s *announcementService) liveSet(ctx context.Context) ([]Announcement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	if !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < announcementCacheTTL {
+		return s.live, nil
+	}
+	live, err := s.repo.ListLive(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	s.live, s.loadedAt = live, now
+	return live, nil
+}
+
+// invalidate forces the next ActiveFor to reload the live set.
+func (s *announcementService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
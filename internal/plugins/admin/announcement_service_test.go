@@ -0,0 +1,236 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeAnnouncementRepo is an in-memory AnnouncementRepository.
+type fakeAnnouncementRepo struct {
+	AnnouncementRepository // embed to satisfy interface
+	items                  map[int]*Announcement
+	dismissed              map[string]map[int]bool
+	liveCalls              int
+	dismissCalls           int
+}
+
+func newFakeAnnouncementRepo(items ...Announcement) *fakeAnnouncementRepo {
+	r := &fakeAnnouncementRepo{items: map[int]*Announcement{}, dismissed: map[string]map[int]bool{}}
+	for i := range items {
+		r.items[items[i].ID] = &items[i]
+	}
+	return r
+}
+
+func (r *fakeAnnouncementRepo) FindByID(_ context.Context, id int) (*Announcement, error) {
+	if a, ok := r.items[id]; ok {
+		return a, nil
+	}
+	return nil, apperror.NewNotFound("announcement not found")
+}
+
+func (r *fakeAnnouncementRepo) ListLive(_ context.Context, now time.Time) ([]Announcement, error) {
+	r.liveCalls++
+	var out []Announcement
+	for _, a := range r.items {
+		if a.EndsAt == nil || a.EndsAt.After(now) {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAnnouncementRepo) Create(_ context.Context, a *Announcement) error {
+	a.ID = len(r.items) + 1
+	r.items[a.ID] = a
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) Dismiss(_ context.Context, id int, userID string) error {
+	if r.dismissed[userID] == nil {
+		r.dismissed[userID] = map[int]bool{}
+	}
+	r.dismissed[userID][id] = true
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) DismissedIDs(_ context.Context, userID string, _ []int) (map[int]bool, error) {
+	r.dismissCalls++
+	return r.dismissed[userID], nil
+}
+
+func TestAnnouncementInput_Normalize(t *testing.T) {
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	cases := []struct {
+		name    string
+		in      AnnouncementInput
+		wantErr bool
+	}{
+		{name: "minimal", in: AnnouncementInput{Title: "Maintenance"}},
+		{name: "scheduled window", in: AnnouncementInput{Title: "Maintenance", Severity: "warning", StartsAt: &start, EndsAt: &end}},
+		{name: "missing title", in: AnnouncementInput{Title: "   "}, wantErr: true},
+		{name: "unknown severity", in: AnnouncementInput{Title: "x", Severity: "urgent"}, wantErr: true},
+		{name: "end before start", in: AnnouncementInput{Title: "x", StartsAt: &end, EndsAt: &start}, wantErr: true},
+		{name: "empty window", in: AnnouncementInput{Title: "x", StartsAt: &start, EndsAt: &start}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.in.normalize()
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) || appErr.Code != 400 {
+				t.Errorf("err = %v, want AppError 400", err)
+			}
+		})
+	}
+}
+
+func TestAnnouncementInput_Defaults(t *testing.T) {
+	in := AnnouncementInput{Title: " New maps ", Severity: " SUCCESS "}
+	if err := in.normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if in.Title != "New maps" || in.Severity != SeveritySuccess {
+		t.Errorf("got title %q severity %q", in.Title, in.Severity)
+	}
+	if !in.dismissible() {
+		t.Error("omitted dismissible should default to true")
+	}
+
+	in = AnnouncementInput{Title: "x"}
+	_ = in.normalize()
+	if in.Severity != SeverityInfo {
+		t.Errorf("severity = %q, want info default", in.Severity)
+	}
+}
+
+func TestAnnouncement_Status(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	cases := []struct {
+		name       string
+		a          Announcement
+		want       string
+		wantActive bool
+	}{
+		{name: "unbounded", a: Announcement{}, want: "active", wantActive: true},
+		{name: "started", a: Announcement{StartsAt: &past, EndsAt: &future}, want: "active", wantActive: true},
+		{name: "scheduled", a: Announcement{StartsAt: &future}, want: "scheduled"},
+		{name: "ended", a: Announcement{EndsAt: &past}, want: "ended"},
+		{name: "ends exactly now", a: Announcement{EndsAt: &now}, want: "ended"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Status(now); got != tc.want {
+				t.Errorf("Status = %q, want %q", got, tc.want)
+			}
+			if got := tc.a.ActiveAt(now); got != tc.wantActive {
+				t.Errorf("ActiveAt = %v, want %v", got, tc.wantActive)
+			}
+		})
+	}
+}
+
+func TestAnnouncementService_ActiveFor(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	repo := newFakeAnnouncementRepo(
+		Announcement{ID: 1, Title: "live", Dismissible: true},
+		Announcement{ID: 2, Title: "later", Dismissible: true, StartsAt: &future},
+		Announcement{ID: 3, Title: "pinned", Dismissible: false},
+	)
+	svc := NewAnnouncementService(repo).(*announcementService)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got := ids(svc.ActiveFor(ctx, "u1")); !sameIDs(got, 1, 3) {
+		t.Fatalf("active = %v, want [1 3]", got)
+	}
+
+	if err := svc.Dismiss(ctx, 1, "u1"); err != nil {
+		t.Fatalf("Dismiss: %v", err)
+	}
+	if got := ids(svc.ActiveFor(ctx, "u1")); !sameIDs(got, 3) {
+		t.Errorf("after dismiss = %v, want [3]", got)
+	}
+	if got := ids(svc.ActiveFor(ctx, "u2")); !sameIDs(got, 1, 3) {
+		t.Errorf("other user = %v, want [1 3]", got)
+	}
+
+	// A pinned banner refuses dismissal.
+	var appErr *apperror.AppError
+	if err := svc.Dismiss(ctx, 3, "u1"); !errors.As(err, &appErr) || appErr.Code != 400 {
+		t.Errorf("dismiss pinned err = %v, want 400", err)
+	}
+
+	// The scheduled banner appears once its start passes, from the cache.
+	svc.now = func() time.Time { return future.Add(time.Second) }
+	if got := ids(svc.ActiveFor(ctx, "u1")); !sameIDs(got, 2, 3) {
+		t.Errorf("after start = %v, want [2 3]", got)
+	}
+}
+
+func TestAnnouncementService_Cache(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeAnnouncementRepo()
+	svc := NewAnnouncementService(repo).(*announcementService)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	svc.ActiveFor(ctx, "u1")
+	svc.ActiveFor(ctx, "u1")
+	if repo.liveCalls != 1 {
+		t.Errorf("ListLive calls = %d, want 1 within the TTL", repo.liveCalls)
+	}
+	if repo.dismissCalls != 0 {
+		t.Errorf("DismissedIDs calls = %d, want none with no banners", repo.dismissCalls)
+	}
+
+	// Publishing invalidates, so the new banner shows on the next render.
+	if _, err := svc.Create(ctx, AnnouncementInput{Title: "Hello"}, "admin"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := svc.ActiveFor(ctx, "u1"); len(got) != 1 {
+		t.Errorf("active after create = %d, want 1", len(got))
+	}
+	if repo.liveCalls != 2 {
+		t.Errorf("ListLive calls = %d, want a reload after create", repo.liveCalls)
+	}
+}
+
+func ids(list []Announcement) []int {
+	out := make([]int, len(list))
+	for i, a := range list {
+		out[i] = a.ID
+	}
+	return out
+}
+
+// sameIDs compares ignoring order (the fake repo iterates a map).
+func sameIDs(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := map[int]bool{}
+	for _, id := range got {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -22,6 +22,12 @@ type HealthChecker interface {
 	RunChecks() *HealthResult
 }
 
+// QueryStatsSource supplies the query timing aggregates for the
+// Performance tab. Implemented by *database.QueryStats.
+type QueryStatsSource interface {
+	Snapshot() database.QueryStatsSnapshot
+}
+
 // BackupArtifact is one file in the backup directory, surfaced on the Backups tab.
 type BackupArtifact struct {
 	Name       string
@@ -6,14 +6,18 @@ package admin
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/database"
 	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
@@ -58,12 +62,20 @@ type Handler struct {
 	settingsService      settings.SettingsService
 	addonCounter     AddonCounter
 	securityService  SecurityService
+	userMerger       UserMerger
+	auditResealer    AuditResealer
+	announcements    AnnouncementService
+	flagService      settings.FlagService
+	inviteCodes      settings.InviteCodeService
+	runtimeConfig    *settings.RuntimeConfig
 	hygieneScanner   DataHygieneScanner
 	databaseExplorer DatabaseExplorer
 	healthChecker    HealthChecker
 	backupLister     BackupLister
+	queryStats       QueryStatsSource
 	pendingCounter    PendingCounter
 	addonUsageCounter AddonUsageCounter
+	usageReporter     UsageReporter
 	baseURL           string
 }
 
@@ -121,6 +133,37 @@ func (h *Handler) SetSecurityService(svc SecurityService) {
 	h.securityService = svc
 }
 
+// SetUserMerger wires the account merge service for the user detail page.
+func (h *Handler) SetUserMerger(m UserMerger) {
+	h.userMerger = m
+}
+
+// SetAuditResealer wires the audit chain reseal run after account merges.
+func (h *Handler) SetAuditResealer(r AuditResealer) {
+	h.auditResealer = r
+}
+
+// SetAnnouncementService sets the service behind the announcements page.
+func (h *Handler) SetAnnouncementService(svc AnnouncementService) {
+	h.announcements = svc
+}
+
+// SetFlagService sets the service behind the feature flags page.
+func (h *Handler) SetFlagService(svc settings.FlagService) {
+	h.flagService = svc
+}
+
+// SetInviteCodeService sets the store behind the registration invite codes
+// on the security page.
+func (h *Handler) SetInviteCodeService(svc settings.InviteCodeService) {
+	h.inviteCodes = svc
+}
+
+// SetRuntimeConfig sets the store behind the runtime settings page.
+func (h *Handler) SetRuntimeConfig(rc *settings.RuntimeConfig) {
+	h.runtimeConfig = rc
+}
+
 // SetHygieneScanner wires the data hygiene scanner for the hygiene dashboard.
 func (h *Handler) SetHygieneScanner(scanner DataHygieneScanner) {
 	h.hygieneScanner = scanner
@@ -143,6 +186,12 @@ func (h *Handler) SetBackupLister(lister BackupLister) {
 	h.backupLister = lister
 }
 
+// SetQueryStats injects the query timing aggregates for the Database >
+// Performance tab. Optional — when nil the tab shows "unavailable".
+func (h *Handler) SetQueryStats(stats QueryStatsSource) {
+	h.queryStats = stats
+}
+
 // SetBaseURL sets the public-facing base URL for the Foundry module admin page.
 func (h *Handler) SetBaseURL(url string) {
 	h.baseURL = url
@@ -158,6 +207,439 @@ func (h *Handler) SetAddonUsageCounter(counter AddonUsageCounter) {
 	h.addonUsageCounter = counter
 }
 
+// SetUsageReporter wires the per-campaign usage counters.
+func (h *Handler) SetUsageReporter(r UsageReporter) {
+	h.usageReporter = r
+}
+
+// --- Announcements ---
+
+// AnnouncementsData holds the announcements admin page.
+type AnnouncementsData struct {
+	Announcements []Announcement
+	Now           time.Time
+	CSRFToken     string
+}
+
+// Announcements renders the announcement manager (GET /admin/announcements).
+func (h *Handler) Announcements(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	list, err := h.announcements.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, AdminAnnouncementsPage(AnnouncementsData{
+		Announcements: list,
+		Now:           time.Now().UTC(),
+		CSRFToken:     middleware.GetCSRFToken(c),
+	}))
+}
+
+// ListAnnouncementsAPI returns every announcement as JSON
+// (GET /admin/announcements/list).
+func (h *Handler) ListAnnouncementsAPI(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	list, err := h.announcements.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		list = []Announcement{}
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// CreateAnnouncementAPI publishes an announcement (POST /admin/announcements).
+func (h *Handler) CreateAnnouncementAPI(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	var req AnnouncementInput
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.announcements.Create(c.Request().Context(), req, auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	slog.Info("admin published announcement",
+		slog.Int("announcement_id", a.ID),
+		slog.String("severity", a.Severity),
+		slog.String("by", auth.GetUserID(c)),
+	)
+	return c.JSON(http.StatusCreated, a)
+}
+
+// UpdateAnnouncementAPI edits an announcement (PUT /admin/announcements/:id).
+func (h *Handler) UpdateAnnouncementAPI(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid announcement ID")
+	}
+	var req AnnouncementInput
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.announcements.Update(c.Request().Context(), id, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, a)
+}
+
+// DeleteAnnouncementAPI removes an announcement (DELETE /admin/announcements/:id).
+func (h *Handler) DeleteAnnouncementAPI(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid announcement ID")
+	}
+	if err := h.announcements.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	slog.Info("admin deleted announcement",
+		slog.Int("announcement_id", id),
+		slog.String("by", auth.GetUserID(c)),
+	)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DismissAnnouncementAPI hides a banner for the current user
+// (POST /announcements/:id/dismiss). Any signed-in user, not just admins.
+func (h *Handler) DismissAnnouncementAPI(c echo.Context) error {
+	if h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid announcement ID")
+	}
+	if err := h.announcements.Dismiss(c.Request().Context(), id, auth.GetUserID(c)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// --- Feature Flags ---
+
+// FeatureFlagsData holds the feature flags admin page.
+type FeatureFlagsData struct {
+	Flags             []settings.FlagState
+	CampaignOverrides []settings.CampaignFlagOverride
+	Campaigns         []campaigns.Campaign
+	CSRFToken         string
+}
+
+// flagUpdateRequest is the body of the flag update endpoints. A null (or
+// omitted) enabled clears the override.
+type flagUpdateRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// FeatureFlags renders registered flags with their instance and campaign
+// overrides (GET /admin/flags).
+func (h *Handler) FeatureFlags(c echo.Context) error {
+	if h.flagService == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	flags, overrides, err := h.flagService.List(ctx)
+	if err != nil {
+		return err
+	}
+	allCampaigns, _, _ := h.campaignService.ListAll(ctx, campaigns.ListOptions{Page: 1, PerPage: 1000})
+	return middleware.Render(c, http.StatusOK, AdminFeatureFlagsPage(FeatureFlagsData{
+		Flags:             flags,
+		CampaignOverrides: overrides,
+		Campaigns:         allCampaigns,
+		CSRFToken:         middleware.GetCSRFToken(c),
+	}))
+}
+
+// UpdateFlagAPI sets or clears a flag's instance override
+// (PUT /admin/flags/:key).
+func (h *Handler) UpdateFlagAPI(c echo.Context) error {
+	return h.updateFlag(c, "")
+}
+
+// UpdateCampaignFlagAPI sets or clears a flag's override for one campaign
+// (PUT /admin/flags/:key/campaigns/:campaignID).
+func (h *Handler) UpdateCampaignFlagAPI(c echo.Context) error {
+	if c.Param("campaignID") == "" {
+		return apperror.NewBadRequest("campaign is required")
+	}
+	return h.updateFlag(c, c.Param("campaignID"))
+}
+
+// updateFlag applies a flag update for the instance (campaignID "") or a
+// campaign, and records it in the security log.
+func (h *Handler) updateFlag(c echo.Context, campaignID string) error {
+	if h.flagService == nil {
+		return apperror.NewMissingContext()
+	}
+	var req flagUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	ctx := c.Request().Context()
+	key := c.Param("key")
+	actorID := auth.GetUserID(c)
+	var err error
+	scope := "instance"
+	if campaignID == "" {
+		err = h.flagService.SetInstance(ctx, key, req.Enabled, actorID)
+	} else {
+		scope = "campaign " + campaignID
+		err = h.flagService.SetCampaign(ctx, campaignID, key, req.Enabled, actorID)
+	}
+	if err != nil {
+		return err
+	}
+
+	state := "default"
+	if req.Enabled != nil && *req.Enabled {
+		state = "on"
+	} else if req.Enabled != nil {
+		state = "off"
+	}
+	if h.securityService != nil {
+		_ = h.securityService.LogEvent(ctx, EventFeatureFlagChanged,
+			"", actorID, c.RealIP(), c.Request().UserAgent(),
+			map[string]any{"flag": key, "state": state, "scope": scope})
+	}
+	slog.Info("admin changed feature flag",
+		slog.String("flag", key),
+		slog.String("state", state),
+		slog.String("scope", scope),
+		slog.String("by", actorID),
+	)
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// --- Runtime Settings ---
+
+// RuntimeSettingsData holds the runtime settings admin page.
+type RuntimeSettingsData struct {
+	Settings settings.RuntimeSettings
+	Defaults settings.RuntimeDefaults
+	// ActiveBaseURL is what this process booted with; it differs from the
+	// stored value until the next restart.
+	ActiveBaseURL string
+	// UploadRateLimit is the effective upload cap, shown read-only because
+	// the storage page owns it.
+	UploadRateLimit int
+	CSRFToken       string
+}
+
+// RuntimeSettings renders the editable runtime settings (GET /admin/runtime).
+func (h *Handler) RuntimeSettings(c echo.Context) error {
+	if h.runtimeConfig == nil {
+		return apperror.NewMissingContext()
+	}
+	return middleware.Render(c, http.StatusOK, AdminRuntimeSettingsPage(RuntimeSettingsData{
+		Settings:        h.runtimeConfig.Current().Redacted(),
+		Defaults:        h.runtimeConfig.Defaults(),
+		ActiveBaseURL:   h.baseURL,
+		UploadRateLimit: h.runtimeConfig.UploadRateLimit(),
+		CSRFToken:       middleware.GetCSRFToken(c),
+	}))
+}
+
+// UpdateRuntimeSettingsAPI validates and saves runtime settings
+// (PUT /admin/runtime). Rate limits and registration mode apply at once;
+// reauth-gated like the registration gate on the security page.
+func (h *Handler) UpdateRuntimeSettingsAPI(c echo.Context) error {
+	if h.runtimeConfig == nil {
+		return apperror.NewMissingContext()
+	}
+	var req settings.RuntimeSettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	ctx := c.Request().Context()
+	before := h.runtimeConfig.Current()
+	after, err := h.runtimeConfig.Update(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	changed := runtimeSettingsDiff(before, after)
+	actorID := auth.GetUserID(c)
+	if h.securityService != nil && len(changed) > 0 {
+		_ = h.securityService.LogEvent(ctx, EventRuntimeSettingsChanged,
+			"", actorID, c.RealIP(), c.Request().UserAgent(),
+			map[string]any{"changed": strings.Join(changed, ", ")})
+	}
+	slog.Info("admin updated runtime settings",
+		slog.Any("changed", changed),
+		slog.String("by", actorID),
+	)
+	return c.JSON(http.StatusOK, after.Redacted())
+}
+
+// runtimeSettingsDiff names the editable fields that changed, for the audit
+// log. Values are left out: a base URL can be sensitive-ish and the field
+// names are what an auditor scans for.
+func runtimeSettingsDiff(before, after settings.RuntimeSettings) []string {
+	var changed []string
+	if before.BaseURL != after.BaseURL {
+		changed = append(changed, "base_url")
+	}
+	if before.LoginRatePerMin != after.LoginRatePerMin {
+		changed = append(changed, "login_rate")
+	}
+	if before.RegisterRatePerMin != after.RegisterRatePerMin {
+		changed = append(changed, "register_rate")
+	}
+	if before.PasswordResetRatePerMin != after.PasswordResetRatePerMin {
+		changed = append(changed, "password_reset_rate")
+	}
+	if before.MediaServeRatePerMin != after.MediaServeRatePerMin {
+		changed = append(changed, "media_serve_rate")
+	}
+	if before.RegistrationMode != after.RegistrationMode {
+		changed = append(changed, "registration_mode")
+	}
+	if before.CaptchaProvider != after.CaptchaProvider {
+		changed = append(changed, "captcha_provider")
+	}
+	if before.CaptchaSiteKey != after.CaptchaSiteKey {
+		changed = append(changed, "captcha_site_key")
+	}
+	if before.CaptchaSecretKey != after.CaptchaSecretKey {
+		changed = append(changed, "captcha_secret_key")
+	}
+	if before.CaptchaLoginAfterFailures != after.CaptchaLoginAfterFailures {
+		changed = append(changed, "captcha_login_threshold")
+	}
+	return changed
+}
+
+// --- Branding ---
+
+// maxBrandingImageSize caps an uploaded logo or favicon.
+const maxBrandingImageSize = 2 << 20
+
+// BrandingData holds the instance branding admin page.
+type BrandingData struct {
+	Branding  settings.Branding
+	CSRFToken string
+}
+
+// Branding renders the instance white-labeling page (GET /admin/branding).
+func (h *Handler) Branding(c echo.Context) error {
+	if h.runtimeConfig == nil {
+		return apperror.NewMissingContext()
+	}
+	return middleware.Render(c, http.StatusOK, AdminBrandingPage(BrandingData{
+		Branding:  h.runtimeConfig.Branding(),
+		CSRFToken: middleware.GetCSRFToken(c),
+	}))
+}
+
+// UpdateBrandingAPI validates and saves the instance branding
+// (PUT /admin/branding). Applies on the next page render.
+func (h *Handler) UpdateBrandingAPI(c echo.Context) error {
+	if h.runtimeConfig == nil {
+		return apperror.NewMissingContext()
+	}
+	var req settings.Branding
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	ctx := c.Request().Context()
+	before := h.runtimeConfig.Branding()
+	after, err := h.runtimeConfig.UpdateBranding(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	changed := brandingDiff(before, after)
+	actorID := auth.GetUserID(c)
+	if h.securityService != nil && len(changed) > 0 {
+		_ = h.securityService.LogEvent(ctx, EventBrandingChanged,
+			"", actorID, c.RealIP(), c.Request().UserAgent(),
+			map[string]any{"changed": strings.Join(changed, ", ")})
+	}
+	return c.JSON(http.StatusOK, after)
+}
+
+// UploadBrandingImageAPI stores a logo or favicon image and returns its
+// media ID (POST /admin/branding/image). The image is campaign-less, so
+// it is served unsigned to logged-out visitors; it takes effect once the
+// branding form is saved with the returned ID.
+func (h *Handler) UploadBrandingImageAPI(c echo.Context) error {
+	if h.mediaService == nil {
+		return apperror.NewMissingContext()
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.NewBadRequest("no file provided")
+	}
+	if file.Size > maxBrandingImageSize {
+		return apperror.NewBadRequest("branding images must be 2 MB or smaller")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	defer func() { _ = src.Close() }()
+	fileBytes, err := io.ReadAll(io.LimitReader(src, maxBrandingImageSize+1))
+	if err != nil {
+		return apperror.NewBadRequest("failed to read file")
+	}
+
+	mimeType := http.DetectContentType(fileBytes)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return apperror.NewBadRequest("branding images must be PNG, JPEG, GIF or WebP")
+	}
+	mf, err := h.mediaService.Upload(c.Request().Context(), media.UploadInput{
+		UploadedBy:   auth.GetUserID(c),
+		OriginalName: file.Filename,
+		MimeType:     mimeType,
+		FileSize:     int64(len(fileBytes)),
+		UsageType:    media.UsageBranding,
+		FileBytes:    fileBytes,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"id": mf.Filename, "url": "/media/" + mf.Filename})
+}
+
+// brandingDiff names the branding fields that changed, for the audit log.
+func brandingDiff(before, after settings.Branding) []string {
+	var changed []string
+	if before.InstanceName != after.InstanceName {
+		changed = append(changed, "instance_name")
+	}
+	if before.Logo != after.Logo {
+		changed = append(changed, "logo")
+	}
+	if before.Favicon != after.Favicon {
+		changed = append(changed, "favicon")
+	}
+	if before.LandingHeadline != after.LandingHeadline || before.LandingText != after.LandingText {
+		changed = append(changed, "landing_copy")
+	}
+	if fmt.Sprint(before.FooterLinks) != fmt.Sprint(after.FooterLinks) {
+		changed = append(changed, "footer_links")
+	}
+	return changed
+}
+
 // --- Data Hygiene ---
 
 // DataHygiene renders the data hygiene dashboard (GET /admin/data-hygiene).
@@ -315,6 +797,128 @@ func (h *Handler) Users(c echo.Context) error {
 	return middleware.Render(c, http.StatusOK, AdminUsersPage(users, total, page, perPage, csrfToken))
 }
 
+// UserDetailData holds everything the admin user detail page shows.
+type UserDetailData struct {
+	User           *auth.User
+	Memberships    []campaigns.UserMembership
+	SMTPConfigured bool
+	IsSelf         bool
+	CSRFToken      string
+}
+
+// UserDetail renders one user's account status, campaign memberships, and
+// account actions (GET /admin/users/:id).
+func (h *Handler) UserDetail(c echo.Context) error {
+	ctx := c.Request().Context()
+	user, err := h.authRepo.FindByID(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	memberships, err := h.campaignService.ListUserMemberships(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	data := UserDetailData{
+		User:        user,
+		Memberships: memberships,
+		IsSelf:      user.ID == auth.GetUserID(c),
+		CSRFToken:   middleware.GetCSRFToken(c),
+	}
+	if h.smtpService != nil {
+		data.SMTPConfigured = h.smtpService.IsConfigured(ctx)
+	}
+	return middleware.Render(c, http.StatusOK, AdminUserDetailPage(data))
+}
+
+// ForcePasswordReset emails a user a password reset link and signs them out
+// everywhere (POST /admin/users/:id/password-reset).
+func (h *Handler) ForcePasswordReset(c echo.Context) error {
+	if h.securityService == nil {
+		return apperror.NewMissingContext()
+	}
+
+	targetID := c.Param("id")
+	currentUserID := auth.GetUserID(c)
+
+	if err := h.securityService.ForcePasswordReset(c.Request().Context(), targetID); err != nil {
+		return err
+	}
+
+	_ = h.securityService.LogEvent(c.Request().Context(), EventPasswordResetForced,
+		targetID, currentUserID, c.RealIP(), c.Request().UserAgent(), nil)
+
+	slog.Info("admin forced password reset",
+		slog.String("target_user", targetID),
+		slog.String("by", currentUserID),
+	)
+
+	return middleware.HTMXRedirect(c, "/admin/users/"+targetID)
+}
+
+// MergeUser folds the account in :id into the account named by the "target"
+// form value (an email address or user ID), then deletes :id
+// (POST /admin/users/:id/merge).
+func (h *Handler) MergeUser(c echo.Context) error {
+	if h.userMerger == nil {
+		return apperror.NewMissingContext()
+	}
+
+	ctx := c.Request().Context()
+	sourceID := c.Param("id")
+	currentUserID := auth.GetUserID(c)
+
+	targetRef := strings.TrimSpace(c.FormValue("target"))
+	if targetRef == "" {
+		return apperror.NewBadRequest("enter the email or ID of the account to keep")
+	}
+	var target *auth.User
+	var err error
+	if strings.Contains(targetRef, "@") {
+		target, err = h.authRepo.FindByEmail(ctx, strings.ToLower(targetRef))
+	} else {
+		target, err = h.authRepo.FindByID(ctx, targetRef)
+	}
+	if err != nil {
+		return apperror.NewBadRequest("no account matches " + targetRef)
+	}
+
+	result, err := h.userMerger.MergeUsers(ctx, sourceID, target.ID, currentUserID)
+	if err != nil {
+		return err
+	}
+
+	// The merge rewrote user IDs on audit entries; reseal their hash chains
+	// so verification doesn't report the merge as tampering.
+	if h.auditResealer != nil {
+		if err := h.auditResealer.ResealMergedUser(ctx, sourceID, target.ID); err != nil {
+			slog.Warn("audit chain reseal after merge failed",
+				slog.String("target_user", target.ID), slog.Any("error", err))
+		}
+	}
+
+	if h.securityService != nil {
+		_ = h.securityService.LogEvent(ctx, EventUserMerged,
+			target.ID, currentUserID, c.RealIP(), c.Request().UserAgent(),
+			map[string]any{
+				"source_id":    sourceID,
+				"source_email": result.SourceEmail,
+				"memberships":  result.Memberships,
+				"rows":         result.Rows,
+			})
+	}
+
+	slog.Info("admin merged accounts",
+		slog.String("source_user", sourceID),
+		slog.String("target_user", target.ID),
+		slog.Int64("memberships", result.Memberships),
+		slog.String("by", currentUserID),
+	)
+
+	return middleware.HTMXRedirect(c, "/admin/users/"+target.ID)
+}
+
 // ToggleAdmin toggles a user's is_admin flag (PUT /admin/users/:id/admin).
 func (h *Handler) ToggleAdmin(c echo.Context) error {
 	targetID := c.Param("id")
@@ -377,7 +981,22 @@ func (h *Handler) ToggleAdmin(c echo.Context) error {
 			map[string]any{"action": action, "target_name": user.DisplayName})
 	}
 
-	return middleware.HTMXRedirect(c, "/admin/users")
+	return middleware.HTMXRedirect(c, adminReturnPath(c, "/admin/users"))
+}
+
+// adminReturnPath returns the admin page an HTMX action was triggered from
+// (HX-Current-URL), so user actions shared by the users list, user detail and
+// security pages land back where the admin was. Only /admin/ paths are
+// honoured; anything else falls back.
+func adminReturnPath(c echo.Context, fallback string) string {
+	u, err := url.Parse(c.Request().Header.Get("HX-Current-URL"))
+	if err != nil || !strings.HasPrefix(u.Path, "/admin/") {
+		return fallback
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
 }
 
 // --- Campaigns ---
@@ -400,6 +1019,8 @@ func (h *Handler) Campaigns(c echo.Context) error {
 }
 
 // DeleteCampaign force-deletes a campaign (DELETE /admin/campaigns/:id).
+// Unlike an owner's delete there is no grace period: admins use this for
+// abuse and for purging a marked campaign early.
 func (h *Handler) DeleteCampaign(c echo.Context) error {
 	campaignID := c.Param("id")
 
@@ -415,6 +1036,23 @@ func (h *Handler) DeleteCampaign(c echo.Context) error {
 	return middleware.HTMXRedirect(c, "/admin/campaigns")
 }
 
+// RestoreCampaign cancels a campaign's pending deletion within its grace
+// period (POST /admin/campaigns/:id/restore).
+func (h *Handler) RestoreCampaign(c echo.Context) error {
+	campaignID := c.Param("id")
+
+	if err := h.campaignService.RestoreDeletion(c.Request().Context(), campaignID); err != nil {
+		return err
+	}
+
+	slog.Info("admin restored campaign",
+		slog.String("campaign_id", campaignID),
+		slog.String("by", auth.GetUserID(c)),
+	)
+
+	return middleware.HTMXRedirect(c, "/admin/campaigns")
+}
+
 // JoinCampaign adds the admin to a campaign with the selected role
 // (POST /admin/campaigns/:id/join).
 func (h *Handler) JoinCampaign(c echo.Context) error {
@@ -583,10 +1221,16 @@ func (h *Handler) Security(c echo.Context) error {
 	// Current site registration mode (defaults to "open" when the settings
 	// service is absent or unset).
 	registrationMode := settings.RegistrationOpen
+	var allowedDomains []string
 	if h.settingsService != nil {
 		if m, err := h.settingsService.GetRegistrationMode(ctx); err == nil {
 			registrationMode = m
 		}
+		allowedDomains, _ = h.settingsService.GetAllowedEmailDomains(ctx)
+	}
+	var inviteCodes []settings.InviteCode
+	if h.inviteCodes != nil {
+		inviteCodes, _ = h.inviteCodes.List(ctx)
 	}
 
 	data := SecurityPageData{
@@ -599,6 +1243,9 @@ func (h *Handler) Security(c echo.Context) error {
 		Sessions:         sessions,
 		CSRFToken:        csrfToken,
 		RegistrationMode: registrationMode,
+		AllowedDomains:   allowedDomains,
+		InviteCodes:      inviteCodes,
+		InviteCodesOn:    h.inviteCodes != nil,
 	}
 
 	return middleware.Render(c, http.StatusOK, AdminSecurityPage(data))
@@ -610,11 +1257,62 @@ func (h *Handler) UpdateRegistrationMode(c echo.Context) error {
 	if h.settingsService == nil {
 		return apperror.NewMissingContext()
 	}
+	ctx := c.Request().Context()
 	mode := c.FormValue("registration_mode")
-	if err := h.settingsService.UpdateRegistrationMode(c.Request().Context(), mode); err != nil {
+	// Domains arrive one per line or comma-separated from the textarea.
+	domains := strings.FieldsFunc(c.FormValue("allowed_domains"), func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r' || r == ' '
+	})
+	// Validate the allowlist before saving anything, so a typo doesn't leave
+	// the mode changed and the domains not.
+	if _, err := settings.NormalizeEmailDomains(domains); err != nil {
+		return err
+	}
+	if err := h.settingsService.UpdateRegistrationMode(ctx, mode); err != nil {
+		return err
+	}
+	if err := h.settingsService.UpdateAllowedEmailDomains(ctx, domains); err != nil {
+		return err
+	}
+	slog.Info("registration settings updated", slog.String("mode", mode), slog.Int("allowed_domains", len(domains)))
+	c.Response().Header().Set("HX-Redirect", "/admin/security")
+	return c.NoContent(http.StatusOK)
+}
+
+// CreateInviteCode issues a registration invite code (POST
+// /admin/security/invite-codes). Reauth-gated: a code is a way in.
+func (h *Handler) CreateInviteCode(c echo.Context) error {
+	if h.inviteCodes == nil {
+		return apperror.NewMissingContext()
+	}
+	var input settings.CreateInviteCodeInput
+	if err := c.Bind(&input); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	code, err := h.inviteCodes.Create(c.Request().Context(), input, auth.GetUserID(c))
+	if err != nil {
 		return err
 	}
-	slog.Info("registration mode updated", slog.String("mode", mode))
+	slog.Info("registration invite code created",
+		slog.Int("id", code.ID), slog.String("created_by", code.CreatedBy))
+	c.Response().Header().Set("HX-Redirect", "/admin/security")
+	return c.NoContent(http.StatusOK)
+}
+
+// DeleteInviteCode revokes a registration invite code (DELETE
+// /admin/security/invite-codes/:id). Accounts already created with it stay.
+func (h *Handler) DeleteInviteCode(c echo.Context) error {
+	if h.inviteCodes == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid invite code ID")
+	}
+	if err := h.inviteCodes.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	slog.Info("registration invite code revoked", slog.Int("id", id))
 	c.Response().Header().Set("HX-Redirect", "/admin/security")
 	return c.NoContent(http.StatusOK)
 }
@@ -669,7 +1367,7 @@ func (h *Handler) ForceLogoutUser(c echo.Context) error {
 		slog.String("by", currentUserID),
 	)
 
-	return middleware.HTMXRedirect(c, "/admin/security")
+	return middleware.HTMXRedirect(c, adminReturnPath(c, "/admin/security"))
 }
 
 // DisableUser disables a user account (PUT /admin/security/users/:id/disable).
@@ -698,7 +1396,7 @@ func (h *Handler) DisableUser(c echo.Context) error {
 		slog.String("by", currentUserID),
 	)
 
-	return middleware.HTMXRedirect(c, "/admin/security")
+	return middleware.HTMXRedirect(c, adminReturnPath(c, "/admin/security"))
 }
 
 // EnableUser re-enables a disabled user account (PUT /admin/security/users/:id/enable).
@@ -722,7 +1420,7 @@ func (h *Handler) EnableUser(c echo.Context) error {
 		slog.String("by", currentUserID),
 	)
 
-	return middleware.HTMXRedirect(c, "/admin/security")
+	return middleware.HTMXRedirect(c, adminReturnPath(c, "/admin/security"))
 }
 
 // --- Database Explorer ---
@@ -771,8 +1469,14 @@ func (h *Handler) Database(c echo.Context) error {
 		}
 	}
 
+	var queries *database.QueryStatsSnapshot
+	if h.queryStats != nil {
+		snap := h.queryStats.Snapshot()
+		queries = &snap
+	}
+
 	csrfToken := middleware.GetCSRFToken(c)
-	return middleware.Render(c, http.StatusOK, AdminDatabasePage(core, statuses, health, backups, tableCount, csrfToken))
+	return middleware.Render(c, http.StatusOK, AdminDatabasePage(core, statuses, health, backups, queries, tableCount, csrfToken))
 }
 
 // DatabaseStatusAPI returns core + plugin migration status as JSON
@@ -791,10 +1495,14 @@ func (h *Handler) DatabaseStatusAPI(c echo.Context) error {
 	if err != nil {
 		return apperror.NewInternal(fmt.Errorf("plugin migration status: %w", err))
 	}
-	return c.JSON(http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"core":    core,
 		"plugins": plugins,
-	})
+	}
+	if h.queryStats != nil {
+		resp["queries"] = h.queryStats.Snapshot()
+	}
+	return c.JSON(http.StatusOK, resp)
 }
 
 // DatabaseSchemaAPI returns the full schema as JSON (GET /admin/database/schema).
@@ -812,6 +1520,34 @@ func (h *Handler) DatabaseSchemaAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, schema)
 }
 
+// UsageAPI returns per-campaign usage counters as JSON (GET /admin/usage).
+func (h *Handler) UsageAPI(c echo.Context) error {
+	if h.usageReporter == nil {
+		return apperror.NewInternal(fmt.Errorf("usage reporter not configured"))
+	}
+	report, err := h.usageReporter.Report(c.Request().Context())
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("usage report: %w", err))
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// UsageMetrics returns the same counters in the Prometheus text format
+// (GET /admin/usage/metrics, and /metrics/usage for token-authenticated
+// scrapers).
+func (h *Handler) UsageMetrics(c echo.Context) error {
+	if h.usageReporter == nil {
+		return apperror.NewInternal(fmt.Errorf("usage reporter not configured"))
+	}
+	report, err := h.usageReporter.Report(c.Request().Context())
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("usage report: %w", err))
+	}
+	c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return WriteUsageMetrics(c.Response(), report)
+}
+
 // ApplyMigrationsAPI runs all pending plugin migrations (POST /admin/database/migrations/apply).
 func (h *Handler) ApplyMigrationsAPI(c echo.Context) error {
 	if h.databaseExplorer == nil {
@@ -984,5 +1720,11 @@ type SecurityPageData struct {
 	// RegistrationMode is the current site registration gate ("open", "invite",
 	// "closed"). Rendered as a select on the security page (B-R4).
 	RegistrationMode string
+	// AllowedDomains is the signup email-domain allowlist (empty = any).
+	AllowedDomains []string
+	// InviteCodes lists the admin-issued registration codes; InviteCodesOn is
+	// false when no invite code service is wired, hiding the section.
+	InviteCodes   []settings.InviteCode
+	InviteCodesOn bool
 }
 
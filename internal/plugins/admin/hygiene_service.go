@@ -94,13 +94,13 @@ func NewHygieneService(db *sql.DB, mediaRepo media.MediaRepository, mediaService
 }
 
 // ScanOrphanedMedia finds media files with NULL campaign_id that are not
-// avatars or backdrops. Cross-checks each against entity references to
+// avatars, backdrops or instance branding. Cross-checks each against entity references to
 // determine if it's safe to delete.
 func (s *hygieneService) ScanOrphanedMedia(ctx context.Context) ([]OrphanedMediaItem, error) {
 	query := `SELECT id, filename, file_size, created_at
 	          FROM media_files
 	          WHERE campaign_id IS NULL
-	            AND usage_type NOT IN ('avatar', 'backdrop')
+	            AND usage_type NOT IN ('avatar', 'backdrop', 'branding')
 	          ORDER BY created_at DESC`
 
 	rows, err := s.db.QueryContext(ctx, query)
@@ -243,7 +243,7 @@ func (s *hygieneService) GetDiskUsageStats(ctx context.Context) (*DiskUsageStats
 	// Orphaned media count and size.
 	err = s.db.QueryRowContext(ctx,
 		`SELECT COUNT(*), COALESCE(SUM(file_size), 0) FROM media_files
-		 WHERE campaign_id IS NULL AND usage_type NOT IN ('avatar', 'backdrop')`,
+		 WHERE campaign_id IS NULL AND usage_type NOT IN ('avatar', 'backdrop', 'branding')`,
 	).Scan(&stats.OrphanMediaCount, &stats.OrphanMediaBytes)
 	if err != nil {
 		return nil, fmt.Errorf("querying orphan media stats: %w", err)
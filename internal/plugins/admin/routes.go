@@ -1,8 +1,13 @@
 package admin
 
 import (
+	"crypto/subtle"
+	"strings"
+
 	"github.com/labstack/echo/v4"
 
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/smtp"
 )
@@ -22,19 +27,47 @@ func RegisterRoutes(e *echo.Echo, h *Handler, authService auth.AuthService, smtp
 
 	// Reauth middleware for sensitive operations — requires recent password
 	// re-confirmation (within 5 minutes). Applied to ToggleAdmin, DisableUser,
-	// EnableUser, and ForceLogoutUser.
+	// EnableUser, ForceLogoutUser, ForcePasswordReset, and MergeUser.
 	reauth := auth.RequireReauth(authService)
 
 	// User management.
 	admin.GET("/users", h.Users)
+	admin.GET("/users/:id", h.UserDetail)
 	admin.PUT("/users/:id/admin", h.ToggleAdmin, reauth)
+	admin.POST("/users/:id/password-reset", h.ForcePasswordReset, reauth)
+	admin.POST("/users/:id/merge", h.MergeUser, reauth)
 
 	// Campaign management.
 	admin.GET("/campaigns", h.Campaigns)
 	admin.DELETE("/campaigns/:id", h.DeleteCampaign)
+	admin.POST("/campaigns/:id/restore", h.RestoreCampaign)
 	admin.POST("/campaigns/:id/join", h.JoinCampaign)
 	admin.DELETE("/campaigns/:id/leave", h.LeaveCampaign)
 
+	// Instance announcements (banners shown to every signed-in user).
+	admin.GET("/announcements", h.Announcements)
+	admin.GET("/announcements/list", h.ListAnnouncementsAPI)
+	admin.POST("/announcements", h.CreateAnnouncementAPI)
+	admin.PUT("/announcements/:id", h.UpdateAnnouncementAPI)
+	admin.DELETE("/announcements/:id", h.DeleteAnnouncementAPI)
+
+	// Dismissing a banner is per-user, so it lives outside /admin.
+	e.POST("/announcements/:id/dismiss", h.DismissAnnouncementAPI, auth.RequireAuth(authService))
+
+	// Feature flags (instance and per-campaign overrides).
+	admin.GET("/flags", h.FeatureFlags)
+	admin.PUT("/flags/:key", h.UpdateFlagAPI)
+	admin.PUT("/flags/:key/campaigns/:campaignID", h.UpdateCampaignFlagAPI)
+
+	// Runtime settings (rate limits, registration mode, base URL).
+	admin.GET("/runtime", h.RuntimeSettings)
+	admin.PUT("/runtime", h.UpdateRuntimeSettingsAPI, reauth)
+
+	// Instance branding (name, logo, favicon, landing copy, footer links).
+	admin.GET("/branding", h.Branding)
+	admin.PUT("/branding", h.UpdateBrandingAPI)
+	admin.POST("/branding/image", h.UploadBrandingImageAPI)
+
 	// Storage management.
 	admin.GET("/storage", h.Storage)
 	admin.DELETE("/media/:fileID", h.DeleteMedia)
@@ -42,6 +75,8 @@ func RegisterRoutes(e *echo.Echo, h *Handler, authService auth.AuthService, smtp
 	// Security dashboard.
 	admin.GET("/security", h.Security)
 	admin.POST("/security/registration", h.UpdateRegistrationMode, reauth)
+	admin.POST("/security/invite-codes", h.CreateInviteCode, reauth)
+	admin.DELETE("/security/invite-codes/:id", h.DeleteInviteCode, reauth)
 	admin.DELETE("/security/sessions/:hash", h.TerminateSession)
 	admin.POST("/security/users/:id/force-logout", h.ForceLogoutUser, reauth)
 	admin.PUT("/security/users/:id/disable", h.DisableUser, reauth)
@@ -70,6 +105,10 @@ func RegisterRoutes(e *echo.Echo, h *Handler, authService auth.AuthService, smtp
 	admin.GET("/database/status", h.DatabaseStatusAPI)
 	admin.POST("/database/migrations/apply", h.ApplyMigrationsAPI)
 
+	// Per-campaign usage counters (JSON and Prometheus text).
+	admin.GET("/usage", h.UsageAPI)
+	admin.GET("/usage/metrics", h.UsageMetrics)
+
 	// SMTP settings (delegates to SMTP plugin handler).
 	if smtpHandler != nil {
 		smtp.RegisterRoutes(admin, smtpHandler)
@@ -77,3 +116,26 @@ func RegisterRoutes(e *echo.Echo, h *Handler, authService auth.AuthService, smtp
 
 	return admin
 }
+
+// RegisterMetricsRoutes exposes the usage metrics to scrapers that can't hold
+// an admin session, behind a bearer token (METRICS_TOKEN). With no token set
+// the route isn't registered and only /admin/usage/metrics serves them.
+func RegisterMetricsRoutes(e *echo.Echo, h *Handler, token string) {
+	if token == "" {
+		return
+	}
+	e.GET("/metrics/usage", h.UsageMetrics, requireBearerToken(token))
+}
+
+// requireBearerToken allows requests carrying "Authorization: Bearer <token>".
+func requireBearerToken(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				return apperror.NewUnauthorized("valid metrics token required")
+			}
+			return next(c)
+		}
+	}
+}
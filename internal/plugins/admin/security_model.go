@@ -15,6 +15,11 @@ const (
 	EventUserEnabled            = "admin.user_enabled"
 	EventSessionTerminated      = "admin.session_terminated"
 	EventForceLogout            = "admin.force_logout"
+	EventPasswordResetForced    = "admin.password_reset_forced"
+	EventUserMerged             = "admin.user_merged"
+	EventFeatureFlagChanged     = "admin.feature_flag_changed"
+	EventRuntimeSettingsChanged = "admin.runtime_settings_changed"
+	EventBrandingChanged        = "admin.branding_changed"
 	EventDiagnosticsBatchRun    = "admin.diagnostics_batch_run"
 	EventMediaUploaded          = "media.uploaded"
 	EventMediaDeleted           = "media.deleted"
@@ -62,6 +67,11 @@ func EventTypeLabel(eventType string) string {
 		EventUserEnabled:            "User Enabled",
 		EventSessionTerminated:      "Session Terminated",
 		EventForceLogout:            "Force Logout",
+		EventPasswordResetForced:    "Password Reset Forced",
+		EventUserMerged:             "Accounts Merged",
+		EventFeatureFlagChanged:     "Feature Flag Changed",
+		EventRuntimeSettingsChanged: "Runtime Settings Changed",
+		EventBrandingChanged:        "Branding Changed",
 		EventDiagnosticsBatchRun:    "Diagnostics Batch Run",
 		EventMediaUploaded:          "Media Uploaded",
 		EventMediaDeleted:           "Media Deleted",
@@ -86,6 +96,11 @@ func EventTypeIcon(eventType string) string {
 		EventUserEnabled:            "fa-solid fa-user-check text-emerald-500",
 		EventSessionTerminated:      "fa-solid fa-plug-circle-xmark text-orange-500",
 		EventForceLogout:            "fa-solid fa-power-off text-red-500",
+		EventPasswordResetForced:    "fa-solid fa-key text-amber-500",
+		EventUserMerged:             "fa-solid fa-code-merge text-purple-500",
+		EventFeatureFlagChanged:     "fa-solid fa-flask text-indigo-500",
+		EventRuntimeSettingsChanged: "fa-solid fa-sliders text-indigo-500",
+		EventBrandingChanged:        "fa-solid fa-palette text-indigo-500",
 		EventDiagnosticsBatchRun:    "fa-solid fa-stethoscope text-slate-500",
 		EventMediaUploaded:          "fa-solid fa-cloud-arrow-up text-blue-500",
 		EventMediaDeleted:           "fa-solid fa-trash text-red-400",
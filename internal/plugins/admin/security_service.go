@@ -39,6 +39,9 @@ type SecurityService interface {
 
 	// EnableUser re-enables a previously disabled user account.
 	EnableUser(ctx context.Context, userID string) error
+
+	// ForcePasswordReset emails the user a reset link and signs them out.
+	ForcePasswordReset(ctx context.Context, userID string) error
 }
 
 // securityService implements SecurityService.
@@ -199,3 +202,11 @@ func (s *securityService) EnableUser(ctx context.Context, userID string) error {
 
 	return nil
 }
+
+// ForcePasswordReset sends a password reset link on the admin's behalf.
+func (s *securityService) ForcePasswordReset(ctx context.Context, userID string) error {
+	if userID == "" {
+		return apperror.NewBadRequest("user ID is required")
+	}
+	return s.authService.AdminForcePasswordReset(ctx, userID)
+}
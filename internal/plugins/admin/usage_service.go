@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// usageCacheTTL keeps a scraper polling every few seconds from re-running
+// the per-campaign aggregates on every request.
+const usageCacheTTL = 30 * time.Second
+
+// activeMemberWindow is how far back a member's last edit makes them active.
+const activeMemberWindow = 30 * 24 * time.Hour
+
+// CampaignUsage is one campaign's usage counters, for hosting providers that
+// bill or cap by usage.
+type CampaignUsage struct {
+	CampaignID    string `json:"campaign_id"`
+	Name          string `json:"name"`
+	Entities      int    `json:"entities"`
+	Members       int    `json:"members"`
+	ActiveMembers int    `json:"active_members_30d"` // distinct editors in the last 30 days
+	Edits24h      int    `json:"edits_24h"`          // audit log entries in the last 24 hours
+	StorageBytes  int64  `json:"storage_bytes"`
+}
+
+// UsageReport is a point-in-time snapshot of every campaign's usage.
+type UsageReport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Campaigns   []CampaignUsage `json:"campaigns"`
+}
+
+// UsageReporter produces per-campaign usage counters.
+type UsageReporter interface {
+	Report(ctx context.Context) (*UsageReport, error)
+}
+
+// usageService implements UsageReporter with direct aggregate queries over
+// other plugins' tables, like the hygiene scanner.
+type usageService struct {
+	db *sql.DB
+
+	mu     sync.Mutex
+	cached *UsageReport
+}
+
+// NewUsageService creates the usage reporter.
+func NewUsageService(db *sql.DB) UsageReporter {
+	return &usageService{db: db}
+}
+
+// Report returns the usage of every campaign, at most usageCacheTTL old.
+// Edits come from the audit log, so they count what the Activity page shows.
+func (s *usageService) Report(ctx context.Context) (*UsageReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != nil && time.Since(s.cached.GeneratedAt) < usageCacheTTL {
+		return s.cached, nil
+	}
+
+	now := time.Now().UTC()
+	query := `SELECT c.id, c.name,
+	                 COALESCE(e.n, 0), COALESCE(m.n, 0), COALESCE(a.active, 0),
+	                 COALESCE(a.edits, 0), COALESCE(f.bytes, 0)
+	          FROM campaigns c
+	          LEFT JOIN (SELECT campaign_id, COUNT(*) AS n FROM entities GROUP BY campaign_id) e
+	                 ON e.campaign_id = c.id
+	          LEFT JOIN (SELECT campaign_id, COUNT(*) AS n FROM campaign_members GROUP BY campaign_id) m
+	                 ON m.campaign_id = c.id
+	          LEFT JOIN (SELECT campaign_id,
+	                            COUNT(DISTINCT user_id) AS active,
+	                            SUM(created_at >= ?) AS edits
+	                     FROM audit_log WHERE created_at >= ? GROUP BY campaign_id) a
+	                 ON a.campaign_id = c.id
+	          LEFT JOIN (SELECT campaign_id, SUM(file_size) AS bytes
+	                     FROM media_files WHERE campaign_id IS NOT NULL GROUP BY campaign_id) f
+	                 ON f.campaign_id = c.id
+	          ORDER BY c.created_at`
+
+	rows, err := s.db.QueryContext(ctx, query, now.Add(-24*time.Hour), now.Add(-activeMemberWindow))
+	if err != nil {
+		return nil, fmt.Errorf("querying campaign usage: %w", err)
+	}
+	defer rows.Close()
+
+	report := &UsageReport{GeneratedAt: now, Campaigns: []CampaignUsage{}}
+	for rows.Next() {
+		var u CampaignUsage
+		if err := rows.Scan(&u.CampaignID, &u.Name, &u.Entities, &u.Members,
+			&u.ActiveMembers, &u.Edits24h, &u.StorageBytes); err != nil {
+			return nil, fmt.Errorf("scanning campaign usage: %w", err)
+		}
+		report.Campaigns = append(report.Campaigns, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating campaign usage: %w", err)
+	}
+
+	s.cached = report
+	return report, nil
+}
+
+// usageMetrics lists the exported gauges in output order.
+var usageMetrics = []struct {
+	name  string
+	help  string
+	value func(CampaignUsage) int64
+}{
+	{"chronicle_campaign_entities", "Entities in the campaign.", func(u CampaignUsage) int64 { return int64(u.Entities) }},
+	{"chronicle_campaign_members", "Members of the campaign.", func(u CampaignUsage) int64 { return int64(u.Members) }},
+	{"chronicle_campaign_active_members", "Distinct members who edited the campaign in the last 30 days.", func(u CampaignUsage) int64 { return int64(u.ActiveMembers) }},
+	{"chronicle_campaign_edits_24h", "Edits (audit log entries) in the campaign in the last 24 hours.", func(u CampaignUsage) int64 { return int64(u.Edits24h) }},
+	{"chronicle_campaign_storage_bytes", "Bytes of media uploaded to the campaign.", func(u CampaignUsage) int64 { return u.StorageBytes }},
+}
+
+// WriteUsageMetrics writes the report in the Prometheus text exposition
+// format, one gauge family per counter labelled by campaign.
+func WriteUsageMetrics(w io.Writer, report *UsageReport) error {
+	var b strings.Builder
+	for _, m := range usageMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+		for _, u := range report.Campaigns {
+			fmt.Fprintf(&b, "%s{campaign_id=\"%s\",campaign=\"%s\"} %d\n",
+				m.name, escapeLabel(u.CampaignID), escapeLabel(u.Name), m.value(u))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelEscaper escapes a Prometheus label value.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value; campaign names are user input.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+func TestWriteUsageMetrics(t *testing.T) {
+	report := &UsageReport{Campaigns: []CampaignUsage{
+		{CampaignID: "c1", Name: "Plain", Entities: 12, Members: 3, ActiveMembers: 2, Edits24h: 40, StorageBytes: 2048},
+		{CampaignID: "c2", Name: "The \"Red\" \\ Keep\nII"},
+	}}
+
+	var b strings.Builder
+	if err := WriteUsageMetrics(&b, report); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE chronicle_campaign_entities gauge\n",
+		`chronicle_campaign_entities{campaign_id="c1",campaign="Plain"} 12` + "\n",
+		`chronicle_campaign_active_members{campaign_id="c1",campaign="Plain"} 2` + "\n",
+		`chronicle_campaign_edits_24h{campaign_id="c1",campaign="Plain"} 40` + "\n",
+		`chronicle_campaign_storage_bytes{campaign_id="c1",campaign="Plain"} 2048` + "\n",
+		// Campaign names are user input; quotes, backslashes and newlines
+		// must not break the exposition format.
+		`chronicle_campaign_members{campaign_id="c2",campaign="The \"Red\" \\ Keep\nII"} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	e := echo.New()
+	mw := requireBearerToken("s3cret")
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+	cases := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"correct token", "Bearer s3cret", true},
+		{"wrong token", "Bearer nope", false},
+		{"token prefix only", "Bearer s3c", false},
+		{"no scheme", "s3cret", false},
+		{"missing", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics/usage", nil)
+			if tc.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.header)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+			err := mw(ok)(c)
+			if tc.want && err != nil {
+				t.Fatalf("rejected: %v", err)
+			}
+			if !tc.want {
+				appErr, isApp := err.(*apperror.AppError)
+				if !isApp || appErr.Code != http.StatusUnauthorized {
+					t.Fatalf("err = %v, want 401", err)
+				}
+			}
+		})
+	}
+}
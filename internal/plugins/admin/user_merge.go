@@ -0,0 +1,329 @@
+package admin
+
+// user_merge.go — folds a duplicate account into another. Every row that
+// points at the duplicate (memberships, authored content, favorites, API
+// keys) is rehomed onto the surviving account in one transaction, then the
+// duplicate is deleted. Plugin tables that aren't installed are skipped.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// userRef is one column that stores a user ID.
+type userRef struct {
+	table  string
+	column string
+
+	// unique marks a column that is part of a unique key (favorites,
+	// attendance, group membership): rows the surviving account already has
+	// are dropped rather than moved.
+	unique bool
+
+	// where narrows the rows, for tables that mix user IDs with other
+	// subjects (entity_permissions also grants to roles and groups).
+	where string
+}
+
+// mergeUserRefs lists every user reference a merge rehomes. campaign_members
+// is absent: the target joins the duplicate's campaigns before these run and
+// the duplicate's memberships are dropped after (see foldMemberships), so
+// rows keyed to a membership can move. Not rehomed: sessions/tokens/
+// notifications (per-login or transient) and the user IDs inside
+// entity_notes.shared_with JSON.
+var mergeUserRefs = []userRef{
+	// Memberships and grants.
+	{table: "campaign_group_members", column: "user_id", unique: true},
+	{table: "session_attendees", column: "user_id", unique: true},
+	{table: "entity_permissions", column: "subject_id", unique: true, where: "subject_type = 'user'"},
+
+	// Authored content.
+	{table: "campaigns", column: "created_by"},
+	{table: "entities", column: "created_by"},
+	{table: "entities", column: "owner_user_id"},
+	{table: "entity_posts", column: "created_by"},
+	{table: "entity_relations", column: "created_by"},
+	{table: "entity_notes", column: "author_user_id"},
+	{table: "notes", column: "user_id"},
+	{table: "notes", column: "last_edited_by"},
+	{table: "note_versions", column: "user_id"},
+	{table: "media_files", column: "uploaded_by"},
+	{table: "calendar_events", column: "created_by"},
+	{table: "map_markers", column: "created_by"},
+	{table: "map_drawings", column: "created_by"},
+	{table: "map_tokens", column: "created_by"},
+	{table: "sessions", column: "created_by"},
+	{table: "timelines", column: "created_by"},
+	{table: "timeline_events", column: "created_by"},
+	{table: "tag_permissions", column: "created_by"},
+	{table: "shop_transactions", column: "created_by"},
+	{table: "packages", column: "submitted_by"},
+	{table: "announcements", column: "created_by"},
+	{table: "campaign_announcements", column: "created_by"},
+	{table: "campaign_house_rules_revisions", column: "edited_by"},
+	{table: "feature_flags", column: "updated_by"},
+	{table: "campaign_feature_flags", column: "updated_by"},
+	{table: "polls", column: "created_by"},
+	{table: "party_journal_entries", column: "author_user_id"},
+	{table: "media_attachments", column: "created_by"},
+	{table: "layout_versions", column: "created_by"},
+	{table: "safety_entries", column: "submitted_by"},
+	{table: "session_transcripts", column: "uploaded_by"},
+
+	// Attribution that blocks deleting the duplicate if left behind.
+	{table: "audit_log", column: "user_id"},
+	{table: "campaign_invites", column: "created_by"},
+	{table: "ownership_transfers", column: "from_user_id"},
+	{table: "ownership_transfers", column: "to_user_id"},
+	{table: "extensions", column: "installed_by"},
+	{table: "campaign_addons", column: "enabled_by"},
+	{table: "campaign_extensions", column: "enabled_by"},
+
+	// Keyed to a membership; the target has joined the campaign by now.
+	{table: "campaign_onboarding_progress", column: "user_id", unique: true},
+	{table: "campaign_digest_opt_outs", column: "user_id", unique: true},
+	{table: "campaign_digest_sends", column: "user_id", unique: true},
+	{table: "entity_watches", column: "user_id", unique: true},
+	{table: "entity_type_watches", column: "user_id", unique: true},
+	{table: "entity_watch_pending", column: "user_id", unique: true},
+
+	// Per-user data worth keeping.
+	{table: "entity_favorites", column: "user_id", unique: true},
+	{table: "announcement_dismissals", column: "user_id", unique: true},
+	{table: "campaign_announcement_reads", column: "user_id", unique: true},
+	{table: "calendar_user_hidden_categories", column: "user_id", unique: true},
+	{table: "safety_consent_answers", column: "user_id", unique: true},
+	{table: "campaign_house_rules_acks", column: "user_id", unique: true},
+	{table: "entity_personal_notes", column: "user_id", unique: true},
+	{table: "poll_votes", column: "user_id", unique: true},
+	{table: "saved_filters", column: "user_id"},
+	{table: "api_keys", column: "user_id"},
+}
+
+// key is the information_schema lookup key and the MergeResult row label.
+func (r userRef) key() string { return r.table + "." + r.column }
+
+// statements returns the SQL that moves this reference from the source user
+// (second ?) to the target (first ?). Unique refs use UPDATE IGNORE, which
+// skips rows that would collide, then delete the skipped leftovers (args:
+// source). Identifiers come from mergeUserRefs, never from input.
+func (r userRef) statements() (move, cleanup string) {
+	cond := fmt.Sprintf("`%s` = ?", r.column)
+	if r.where != "" {
+		cond += " AND " + r.where
+	}
+	if !r.unique {
+		return fmt.Sprintf("UPDATE `%s` SET `%s` = ? WHERE %s", r.table, r.column, cond), ""
+	}
+	return fmt.Sprintf("UPDATE IGNORE `%s` SET `%s` = ? WHERE %s", r.table, r.column, cond),
+		fmt.Sprintf("DELETE FROM `%s` WHERE %s", r.table, cond)
+}
+
+// installedRefs filters mergeUserRefs to the columns that exist.
+func installedRefs(existing map[string]bool) []userRef {
+	var out []userRef
+	for _, r := range mergeUserRefs {
+		if existing[r.key()] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// MergeResult reports what a merge moved.
+type MergeResult struct {
+	SourceEmail string
+	TargetEmail string
+	// Memberships is the number of campaigns the duplicate belonged to.
+	Memberships int64
+	// Rows is rows rehomed per "table.column".
+	Rows map[string]int64
+}
+
+// UserMerger folds one account into another.
+type UserMerger interface {
+	// MergeUsers moves everything owned by sourceID onto targetID and deletes
+	// sourceID. actorID is the admin performing it (who can't merge away
+	// their own account).
+	MergeUsers(ctx context.Context, sourceID, targetID, actorID string) (*MergeResult, error)
+}
+
+// AuditResealer reseals audit log hash chains after a merge rewrote the
+// user ID on audit entries. Implemented by audit.ChainService.
+type AuditResealer interface {
+	ResealMergedUser(ctx context.Context, sourceID, targetID string) error
+}
+
+// userMergeService implements UserMerger with direct SQL, like the data
+// hygiene scanner: the rows span every plugin's tables.
+type userMergeService struct {
+	db          *sql.DB
+	authRepo    auth.UserRepository
+	authService auth.AuthService
+}
+
+// NewUserMergeService creates the account merge service.
+func NewUserMergeService(db *sql.DB, authRepo auth.UserRepository, authService auth.AuthService) UserMerger {
+	return &userMergeService{db: db, authRepo: authRepo, authService: authService}
+}
+
+// validateMerge loads both accounts and rejects merges that would lose
+// access or privileges.
+func (s *userMergeService) validateMerge(ctx context.Context, sourceID, targetID, actorID string) (source, target *auth.User, err error) {
+	if sourceID == "" || targetID == "" {
+		return nil, nil, apperror.NewBadRequest("both accounts are required")
+	}
+	if sourceID == targetID {
+		return nil, nil, apperror.NewBadRequest("cannot merge an account into itself")
+	}
+	if sourceID == actorID {
+		return nil, nil, apperror.NewBadRequest("cannot merge away your own account")
+	}
+	if source, err = s.authRepo.FindByID(ctx, sourceID); err != nil {
+		return nil, nil, err
+	}
+	if target, err = s.authRepo.FindByID(ctx, targetID); err != nil {
+		return nil, nil, err
+	}
+	// Same rule as DisableUser: an admin account must be demoted first, so a
+	// merge can't silently drop a site admin.
+	if source.IsAdmin {
+		return nil, nil, apperror.NewBadRequest("remove admin privileges from the duplicate account first")
+	}
+	if target.IsDisabled {
+		return nil, nil, apperror.NewBadRequest("cannot merge into a disabled account")
+	}
+	return source, target, nil
+}
+
+// MergeUsers runs the merge in one transaction; any failure (including a
+// foreign key still pointing at the duplicate) rolls the whole thing back.
+func (s *userMergeService) MergeUsers(ctx context.Context, sourceID, targetID, actorID string) (*MergeResult, error) {
+	source, target, err := s.validateMerge(ctx, sourceID, targetID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("starting merge: %w", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing, err := userRefColumns(ctx, tx)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+
+	result := &MergeResult{SourceEmail: source.Email, TargetEmail: target.Email, Rows: map[string]int64{}}
+	if result.Memberships, err = foldMemberships(ctx, tx, source.ID, target.ID); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+
+	for _, ref := range installedRefs(existing) {
+		move, cleanup := ref.statements()
+		res, err := tx.ExecContext(ctx, move, target.ID, source.ID)
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("rehoming %s: %w", ref.key(), err))
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.Rows[ref.key()] += n
+		}
+		if cleanup != "" {
+			if _, err := tx.ExecContext(ctx, cleanup, source.ID); err != nil {
+				return nil, apperror.NewInternal(fmt.Errorf("clearing %s: %w", ref.key(), err))
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM campaign_members WHERE user_id = ?`, source.ID,
+	); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("clearing folded memberships: %w", err))
+	}
+
+	// Anything not rehomed (sessions, tokens, notifications) cascades.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, source.ID); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("deleting merged account: %w", err))
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("committing merge: %w", err))
+	}
+
+	// The account row is gone; drop its Redis sessions too so nothing keeps
+	// acting as it until expiry.
+	if _, err := s.authService.DestroyAllUserSessions(ctx, source.ID); err != nil {
+		slog.Warn("merge: failed to destroy merged account sessions",
+			slog.String("user_id", source.ID), slog.Any("error", err))
+	}
+	return result, nil
+}
+
+// userRefColumns returns which "table.column" pairs exist in this database,
+// so refs on uninstalled plugins' tables are skipped.
+func userRefColumns(ctx context.Context, tx *sql.Tx) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT TABLE_NAME, COLUMN_NAME FROM information_schema.COLUMNS
+		 WHERE TABLE_SCHEMA = DATABASE()`)
+	if err != nil {
+		return nil, fmt.Errorf("listing columns: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("scanning column: %w", err)
+		}
+		existing[table+"."+column] = true
+	}
+	return existing, rows.Err()
+}
+
+// foldMemberships gives the target every campaign membership the source
+// has. Where both belong to a campaign the target keeps the higher role (so
+// a duplicate that owns a campaign hands ownership over) and the source's
+// character link fills an empty one; elsewhere the membership is copied.
+// The source's own rows stay until MergeUsers has rehomed the rows keyed to
+// them: the foreign keys onto campaign_members don't cascade updates, so
+// moving a membership in place is refused while anything points at it.
+// Returns the source's membership count.
+func foldMemberships(ctx context.Context, tx *sql.Tx, sourceID, targetID string) (int64, error) {
+	var count int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM campaign_members WHERE user_id = ?`, sourceID,
+	).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting memberships: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE campaign_members t
+		 JOIN campaign_members s ON s.campaign_id = t.campaign_id AND s.user_id = ?
+		 SET t.role = CASE
+		         WHEN 'owner' IN (t.role, s.role) THEN 'owner'
+		         WHEN 'scribe' IN (t.role, s.role) THEN 'scribe'
+		         ELSE 'player' END,
+		     t.character_entity_id = COALESCE(t.character_entity_id, s.character_entity_id)
+		 WHERE t.user_id = ?`, sourceID, targetID,
+	); err != nil {
+		return 0, fmt.Errorf("folding shared memberships: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO campaign_members (campaign_id, user_id, role, joined_at, character_entity_id)
+		 SELECT s.campaign_id, ?, s.role, s.joined_at, s.character_entity_id
+		   FROM campaign_members s
+		  WHERE s.user_id = ?
+		    AND NOT EXISTS (SELECT 1 FROM campaign_members t
+		                     WHERE t.campaign_id = s.campaign_id AND t.user_id = ?)`,
+		targetID, sourceID, targetID,
+	); err != nil {
+		return 0, fmt.Errorf("copying memberships: %w", err)
+	}
+	return count, nil
+}
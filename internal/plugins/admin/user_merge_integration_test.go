@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// TestUserMerge_Integration runs a merge against a real, migrated MariaDB.
+// The mock-based tests check the SQL text; only the real schema's foreign
+// keys show whether a membership, and the rows keyed to it, survive the
+// move onto the target account.
+//
+// Skipped under -short or when no DB answers (same discovery as the
+// entities integration test). Run with: `make docker-up && make migrate-up
+// && make test-int`.
+func TestUserMerge_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test requires a database; skipped under -short")
+	}
+	db := openMergeTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	dupID, keepID := mergeTestUUID(t), mergeTestUUID(t)
+	ownedID, sharedID := mergeTestUUID(t), mergeTestUUID(t)
+	for _, id := range []string{dupID, keepID} {
+		mustExecMerge(t, db, `INSERT INTO users (id, email, display_name, password_hash) VALUES (?, ?, ?, ?)`,
+			id, "merge-int-"+id+"@example.test", "Merge Int Test", "x")
+	}
+	defer func() {
+		mustExecMerge(t, db, `DELETE FROM campaigns WHERE id IN (?, ?)`, ownedID, sharedID)
+		mustExecMerge(t, db, `DELETE FROM users WHERE id IN (?, ?)`, dupID, keepID)
+	}()
+
+	// The duplicate owns one campaign and plays in another where the
+	// surviving account is a scribe.
+	mustExecMerge(t, db, `INSERT INTO campaigns (id, name, slug, created_by) VALUES (?, ?, ?, ?)`,
+		ownedID, "Merge Owned", "merge-int-"+ownedID[:8], dupID)
+	mustExecMerge(t, db, `INSERT INTO campaigns (id, name, slug, created_by) VALUES (?, ?, ?, ?)`,
+		sharedID, "Merge Shared", "merge-int-"+sharedID[:8], keepID)
+	mustExecMerge(t, db, `INSERT INTO campaign_members (campaign_id, user_id, role) VALUES (?, ?, 'owner')`, ownedID, dupID)
+	mustExecMerge(t, db, `INSERT INTO campaign_members (campaign_id, user_id, role) VALUES (?, ?, 'player')`, sharedID, dupID)
+	mustExecMerge(t, db, `INSERT INTO campaign_members (campaign_id, user_id, role) VALUES (?, ?, 'scribe')`, sharedID, keepID)
+
+	// A page and its type in the owned campaign, for the watch rows.
+	res, err := db.Exec(`INSERT INTO entity_types (campaign_id, slug, name, name_plural) VALUES (?, 'merge-int', 'Merge', 'Merges')`, ownedID)
+	if err != nil {
+		t.Fatalf("insert entity type: %v", err)
+	}
+	typeID, _ := res.LastInsertId()
+	entityID := mergeTestUUID(t)
+	mustExecMerge(t, db, `INSERT INTO entities (id, campaign_id, entity_type_id, name, slug, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, 'Merge Page', 'merge-page', ?, NOW(), NOW())`, entityID, ownedID, typeID, dupID)
+
+	// Rows keyed to the duplicate's membership in the owned campaign. Each
+	// insert takes (campaign_id, user_id) followed by args.
+	keyed := []struct {
+		table, insert string
+		args          []any
+	}{
+		{"campaign_onboarding_progress",
+			`INSERT INTO campaign_onboarding_progress (campaign_id, user_id, item_id) VALUES (?, ?, 'intro')`, nil},
+		{"campaign_digest_opt_outs",
+			`INSERT INTO campaign_digest_opt_outs (campaign_id, user_id) VALUES (?, ?)`, nil},
+		{"campaign_digest_sends",
+			`INSERT INTO campaign_digest_sends (campaign_id, user_id, period_start) VALUES (?, ?, '2026-01-05')`, nil},
+		{"entity_watches",
+			`INSERT INTO entity_watches (campaign_id, user_id, entity_id) VALUES (?, ?, ?)`, []any{entityID}},
+		{"entity_type_watches",
+			`INSERT INTO entity_type_watches (campaign_id, user_id, entity_type_id) VALUES (?, ?, ?)`, []any{typeID}},
+		{"entity_watch_pending",
+			`INSERT INTO entity_watch_pending (campaign_id, user_id, entity_id, first_changed_at, last_changed_at)
+			 VALUES (?, ?, ?, NOW(), NOW())`, []any{entityID}},
+	}
+	for _, k := range keyed {
+		mustExecMerge(t, db, k.insert, append([]any{ownedID, dupID}, k.args...)...)
+	}
+
+	svc := NewUserMergeService(db, &stubMergeUserRepo{users: map[string]*auth.User{
+		dupID:  {ID: dupID, Email: "dup@example.test"},
+		keepID: {ID: keepID, Email: "keep@example.test"},
+	}}, stubMergeAuthService{})
+	result, err := svc.MergeUsers(ctx, dupID, keepID, "admin")
+	if err != nil {
+		t.Fatalf("MergeUsers: %v", err)
+	}
+	if result.Memberships != 2 {
+		t.Errorf("Memberships = %d, want 2", result.Memberships)
+	}
+
+	for _, tc := range []struct{ campaign, want string }{
+		{ownedID, "owner"},
+		{sharedID, "scribe"},
+	} {
+		var role string
+		if err := db.QueryRow(`SELECT role FROM campaign_members WHERE campaign_id = ? AND user_id = ?`,
+			tc.campaign, keepID).Scan(&role); err != nil {
+			t.Fatalf("target membership in %s: %v", tc.campaign, err)
+		}
+		if role != tc.want {
+			t.Errorf("role in %s = %q, want %q", tc.campaign, role, tc.want)
+		}
+	}
+	for _, k := range keyed {
+		q := "SELECT COUNT(*) FROM `" + k.table + "` WHERE campaign_id = ? AND user_id = ?"
+		if n := countMergeRows(t, db, q, ownedID, keepID); n != 1 {
+			t.Errorf("%s: target has %d rows, want the duplicate's 1", k.table, n)
+		}
+	}
+	if n := countMergeRows(t, db, `SELECT COUNT(*) FROM campaign_members WHERE user_id = ?`, dupID); n != 0 {
+		t.Errorf("duplicate still has %d memberships", n)
+	}
+	if n := countMergeRows(t, db, `SELECT COUNT(*) FROM users WHERE id = ?`, dupID); n != 0 {
+		t.Error("duplicate account was not deleted")
+	}
+}
+
+// stubMergeAuthService satisfies the session cleanup after a merge.
+type stubMergeAuthService struct {
+	auth.AuthService // embed to satisfy interface
+}
+
+func (stubMergeAuthService) DestroyAllUserSessions(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+// --- helpers ---
+
+func openMergeTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("CHRONICLE_TEST_DB_DSN")
+	if dsn == "" {
+		cfg := mysql.NewConfig()
+		cfg.User = mergeTestEnv("DB_USER", "chronicle")
+		cfg.Passwd = mergeTestEnv("DB_PASSWORD", "chronicle")
+		cfg.Net = "tcp"
+		cfg.Addr = mergeTestEnv("DB_HOST", "127.0.0.1:3306")
+		cfg.DBName = mergeTestEnv("DB_NAME", "chronicle")
+		cfg.ParseTime = true
+		dsn = cfg.FormatDSN()
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Skipf("no test DB (sql.Open: %v)", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("no test DB reachable (ping: %v) — run `make docker-up && make migrate-up`", err)
+	}
+	return db
+}
+
+func mergeTestEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func mustExecMerge(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
+
+func countMergeRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("query %q: %v", query, err)
+	}
+	return n
+}
+
+func mergeTestUUID(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,142 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// stubMergeUserRepo serves fixed users by ID.
+type stubMergeUserRepo struct {
+	auth.UserRepository // embed to satisfy interface
+	users               map[string]*auth.User
+}
+
+func (m *stubMergeUserRepo) FindByID(_ context.Context, id string) (*auth.User, error) {
+	if u, ok := m.users[id]; ok {
+		return u, nil
+	}
+	return nil, apperror.NewNotFound("user not found")
+}
+
+func TestValidateMerge(t *testing.T) {
+	svc := &userMergeService{authRepo: &stubMergeUserRepo{users: map[string]*auth.User{
+		"dup":      {ID: "dup", Email: "dup@example.com"},
+		"keep":     {ID: "keep", Email: "keep@example.com"},
+		"admin":    {ID: "admin", IsAdmin: true},
+		"disabled": {ID: "disabled", IsDisabled: true},
+	}}}
+
+	cases := []struct {
+		name           string
+		source, target string
+		actor          string
+		wantCode       int
+	}{
+		{name: "valid", source: "dup", target: "keep", actor: "admin"},
+		{name: "into itself", source: "dup", target: "dup", actor: "admin", wantCode: 400},
+		{name: "own account", source: "dup", target: "keep", actor: "dup", wantCode: 400},
+		{name: "admin source", source: "admin", target: "keep", actor: "other", wantCode: 400},
+		{name: "disabled target", source: "dup", target: "disabled", actor: "admin", wantCode: 400},
+		{name: "unknown target", source: "dup", target: "nobody", actor: "admin", wantCode: 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := svc.validateMerge(context.Background(), tc.source, tc.target, tc.actor)
+			if tc.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) || appErr.Code != tc.wantCode {
+				t.Errorf("err = %v, want AppError %d", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestUserRefStatements(t *testing.T) {
+	cases := []struct {
+		name        string
+		ref         userRef
+		wantMove    string
+		wantCleanup string
+	}{
+		{
+			name:     "plain reference",
+			ref:      userRef{table: "entities", column: "created_by"},
+			wantMove: "UPDATE `entities` SET `created_by` = ? WHERE `created_by` = ?",
+		},
+		{
+			name:        "unique reference drops collisions",
+			ref:         userRef{table: "entity_favorites", column: "user_id", unique: true},
+			wantMove:    "UPDATE IGNORE `entity_favorites` SET `user_id` = ? WHERE `user_id` = ?",
+			wantCleanup: "DELETE FROM `entity_favorites` WHERE `user_id` = ?",
+		},
+		{
+			name:        "narrowed reference",
+			ref:         userRef{table: "entity_permissions", column: "subject_id", unique: true, where: "subject_type = 'user'"},
+			wantMove:    "UPDATE IGNORE `entity_permissions` SET `subject_id` = ? WHERE `subject_id` = ? AND subject_type = 'user'",
+			wantCleanup: "DELETE FROM `entity_permissions` WHERE `subject_id` = ? AND subject_type = 'user'",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			move, cleanup := tc.ref.statements()
+			if move != tc.wantMove {
+				t.Errorf("move = %q, want %q", move, tc.wantMove)
+			}
+			if cleanup != tc.wantCleanup {
+				t.Errorf("cleanup = %q, want %q", cleanup, tc.wantCleanup)
+			}
+		})
+	}
+}
+
+func TestInstalledRefs_SkipsMissingTables(t *testing.T) {
+	// Core tables only: no calendar, maps, sessions, timeline, syncapi.
+	existing := map[string]bool{"entities.created_by": true, "notes.user_id": true, "maps.name": true}
+	refs := installedRefs(existing)
+	if len(refs) != 2 {
+		t.Fatalf("got %d refs, want 2: %+v", len(refs), refs)
+	}
+	for _, r := range refs {
+		if !existing[r.key()] {
+			t.Errorf("ref %s is not installed", r.key())
+		}
+	}
+}
+
+func TestAdminReturnPath(t *testing.T) {
+	cases := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{name: "no header", want: "/admin/security"},
+		{name: "user detail", current: "https://chronicle.example.com/admin/users/u1", want: "/admin/users/u1"},
+		{name: "keeps query", current: "https://chronicle.example.com/admin/users?page=2", want: "/admin/users?page=2"},
+		{name: "outside admin", current: "https://evil.example.com/login", want: "/admin/security"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/security/users/u1/force-logout", nil)
+			if tc.current != "" {
+				req.Header.Set("HX-Current-URL", tc.current)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			if got := adminReturnPath(c, "/admin/security"); got != tc.want {
+				t.Errorf("adminReturnPath = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
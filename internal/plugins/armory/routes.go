@@ -49,6 +49,9 @@ func RegisterRoutes(e *echo.Echo, h *Handler, th *TransactionHandler, ih *Instan
 	// Purchase — buyer ownership is enforced server-side in transaction_service.
 	cg.POST("/armory/purchase", th.Purchase, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.POST("/armory/transactions", th.CreateTransaction, campaigns.RequireRole(campaigns.RoleScribe))
+	// Transfer checks edit access to the giver server-side, so a Player can
+	// hand over their own character's items.
+	cg.POST("/armory/transfer", th.Transfer, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.GET("/armory/transactions", th.ListTransactions, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.GET("/armory/shops/:eid/transactions", th.ListShopTransactions, campaigns.RequireRole(campaigns.RolePlayer))
 }
@@ -4,19 +4,22 @@ package armory
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
 )
 
 // TransactionHandler serves transaction REST endpoints.
 type TransactionHandler struct {
-	svc TransactionService
+	svc      TransactionService
+	auditSvc audit.AuditService
 }
 
 // NewTransactionHandler creates a new transaction handler.
@@ -24,6 +27,11 @@ func NewTransactionHandler(svc TransactionService) *TransactionHandler {
 	return &TransactionHandler{svc: svc}
 }
 
+// SetAuditService wires the audit-log emitter for item transfers.
+func (h *TransactionHandler) SetAuditService(svc audit.AuditService) {
+	h.auditSvc = svc
+}
+
 // Purchase handles POST /campaigns/:id/armory/purchase.
 // Validates stock, creates transaction, decrements shop inventory.
 func (h *TransactionHandler) Purchase(c echo.Context) error {
@@ -154,3 +162,49 @@ func (h *TransactionHandler) ListShopTransactions(c echo.Context) error {
 		"page":  opts.Page,
 	})
 }
+
+// Transfer handles POST /campaigns/:id/armory/transfer.
+// Moves items from one owner's inventory to another's and logs an
+// item.transferred audit entry against the item.
+func (h *TransactionHandler) Transfer(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	var input TransferInput
+	if err := json.NewDecoder(c.Request().Body).Decode(&input); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+
+	userID := auth.GetUserID(c)
+	result, err := h.svc.Transfer(c.Request().Context(), cc.Campaign.ID, userID, int(cc.MemberRole), input)
+	if err != nil {
+		return err
+	}
+
+	if h.auditSvc != nil {
+		if err := h.auditSvc.Log(c.Request().Context(), &audit.AuditEntry{
+			CampaignID: cc.Campaign.ID,
+			UserID:     userID,
+			Action:     audit.ActionItemTransferred,
+			EntityType: "entity",
+			EntityID:   result.Transaction.ItemEntityID,
+			EntityName: result.ItemName,
+			Details: map[string]any{
+				"from_entity_id": result.Transaction.ShopEntityID,
+				"from_name":      result.FromName,
+				"to_entity_id":   input.ToEntityID,
+				"to_name":        result.ToName,
+				"quantity":       result.Transaction.Quantity,
+			},
+		}); err != nil {
+			slog.Warn("armory transfer audit log failed",
+				slog.String("item_id", result.Transaction.ItemEntityID),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
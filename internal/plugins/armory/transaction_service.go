@@ -69,6 +69,10 @@ type TransactionService interface {
 
 	// ListBuyerTransactions returns transactions for a specific buyer.
 	ListBuyerTransactions(ctx context.Context, buyerEntityID string, opts TransactionListOptions) ([]Transaction, int, error)
+
+	// Transfer moves items between two owners' inventories and records a
+	// transfer transaction. See transfer.go.
+	Transfer(ctx context.Context, campaignID, userID string, role int, input TransferInput) (*TransferResult, error)
 }
 
 // transactionService implements TransactionService.
@@ -78,6 +82,8 @@ type transactionService struct {
 	relationFinder  RelationFinder
 	entityFields    EntityFieldUpdater
 	buyerAccess     BuyerAccessChecker
+	holdings        InventoryHoldings
+	names           InventoryEntityNamer
 }
 
 // NewTransactionService creates a new transaction service. Returns the concrete
@@ -108,6 +114,13 @@ func (s *transactionService) SetBuyerAccessChecker(b BuyerAccessChecker) {
 	s.buyerAccess = b
 }
 
+// SetInventoryHoldings injects the "Has Item" relation store and the entity
+// namer used by Transfer. Transfers return 404 until both are set.
+func (s *transactionService) SetInventoryHoldings(h InventoryHoldings, n InventoryEntityNamer) {
+	s.holdings = h
+	s.names = n
+}
+
 // Purchase validates stock, creates the transaction, decrements shop stock,
 // and optionally deducts currency from the buyer entity.
 func (s *transactionService) Purchase(ctx context.Context, campaignID, userID string, role int, input CreateTransactionInput) (*PurchaseResult, error) {
@@ -0,0 +1,204 @@
+// transfer.go moves items between inventories. An inventory is the set of
+// "Has Item" relations from an owner entity (a character, a location, a
+// ship's hold) to item entities, with the stack size in the relation's
+// metadata — the same relations the inventory block edits. A transfer
+// shrinks or removes the giver's stack, grows or creates the receiver's,
+// and is logged as a "transfer" transaction (giver in shop_entity_id,
+// receiver in buyer_entity_id).
+package armory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Relation labels the inventory block uses for an owner's items.
+const (
+	HoldingRelationType        = "Has Item"
+	HoldingReverseRelationType = "In Inventory Of"
+)
+
+// Holding is one owner's stack of one item.
+type Holding struct {
+	RelationID int
+	Metadata   map[string]any // quantity, equipped, attuned, and anything else the block stores.
+}
+
+// Quantity returns the stack size. Missing or invalid quantities count as
+// one, matching the inventory block.
+func (h *Holding) Quantity() int {
+	switch q := h.Metadata["quantity"].(type) {
+	case float64:
+		if q >= 1 {
+			return int(q)
+		}
+	case int:
+		if q >= 1 {
+			return q
+		}
+	}
+	return 1
+}
+
+// withQuantity sets the stack size and returns the metadata to store,
+// keeping the holding's other keys.
+func (h *Holding) withQuantity(n int) json.RawMessage {
+	if h.Metadata == nil {
+		h.Metadata = map[string]any{}
+	}
+	h.Metadata["quantity"] = n
+	raw, _ := json.Marshal(h.Metadata)
+	return raw
+}
+
+// InventoryHoldings reads and writes "Has Item" relations. Implemented by
+// an adapter over the relations service — wired in routes.go.
+type InventoryHoldings interface {
+	// FindHolding returns the owner's holding of the item, or nil.
+	FindHolding(ctx context.Context, campaignID, ownerEntityID, itemEntityID string) (*Holding, error)
+	CreateHolding(ctx context.Context, campaignID, ownerEntityID, itemEntityID, userID string, metadata json.RawMessage) error
+	UpdateHolding(ctx context.Context, relationID int, metadata json.RawMessage) error
+	DeleteHolding(ctx context.Context, relationID int) error
+}
+
+// InventoryEntityNamer resolves entity names within a campaign. Returns a
+// not-found error for entities in other campaigns. Implemented by an
+// adapter over entities.EntityService.
+type InventoryEntityNamer interface {
+	EntityName(ctx context.Context, campaignID, entityID string) (string, error)
+}
+
+// TransferInput is the request payload for moving items between owners.
+type TransferInput struct {
+	ItemEntityID string `json:"item_entity_id"`
+	FromEntityID string `json:"from_entity_id"`
+	ToEntityID   string `json:"to_entity_id"`
+	Quantity     int    `json:"quantity"`
+	Notes        string `json:"notes"`
+}
+
+// TransferResult reports a completed transfer. The names are for the audit
+// entry and the widget's toast.
+type TransferResult struct {
+	Transaction  *Transaction `json:"transaction"`
+	ItemName     string       `json:"item_name"`
+	FromName     string       `json:"from_name"`
+	ToName       string       `json:"to_name"`
+	FromQuantity int          `json:"from_quantity"` // Left with the giver; 0 when the stack is gone.
+	ToQuantity   int          `json:"to_quantity"`
+}
+
+// Transfer moves quantity of an item from one owner's inventory to
+// another's. The caller must be able to edit the giver (a player hands
+// over their own character's things); the receiver only has to be in the
+// campaign.
+func (s *transactionService) Transfer(ctx context.Context, campaignID, userID string, role int, input TransferInput) (*TransferResult, error) {
+	if s.holdings == nil || s.names == nil {
+		return nil, apperror.NewNotFound("inventory transfers are not available")
+	}
+	input.ItemEntityID = strings.TrimSpace(input.ItemEntityID)
+	input.FromEntityID = strings.TrimSpace(input.FromEntityID)
+	input.ToEntityID = strings.TrimSpace(input.ToEntityID)
+	if input.ItemEntityID == "" || input.FromEntityID == "" || input.ToEntityID == "" {
+		return nil, apperror.NewBadRequest("item, from and to entity IDs are required")
+	}
+	if input.FromEntityID == input.ToEntityID {
+		return nil, apperror.NewBadRequest("cannot transfer an item to its current owner")
+	}
+	if input.ItemEntityID == input.FromEntityID || input.ItemEntityID == input.ToEntityID {
+		return nil, apperror.NewBadRequest("an item cannot hold itself")
+	}
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+	if input.Quantity < 1 {
+		return nil, apperror.NewBadRequest("quantity must be at least 1")
+	}
+
+	if s.buyerAccess != nil {
+		ok, err := s.buyerAccess.CanUserActAsBuyer(ctx, input.FromEntityID, userID, role)
+		if err != nil {
+			return nil, fmt.Errorf("verify giver access: %w", err)
+		}
+		if !ok {
+			return nil, apperror.NewForbidden("you cannot give away that owner's items")
+		}
+	}
+
+	result := &TransferResult{}
+	var err error
+	if result.ItemName, err = s.names.EntityName(ctx, campaignID, input.ItemEntityID); err != nil {
+		return nil, err
+	}
+	if result.FromName, err = s.names.EntityName(ctx, campaignID, input.FromEntityID); err != nil {
+		return nil, err
+	}
+	if result.ToName, err = s.names.EntityName(ctx, campaignID, input.ToEntityID); err != nil {
+		return nil, err
+	}
+
+	src, err := s.holdings.FindHolding(ctx, campaignID, input.FromEntityID, input.ItemEntityID)
+	if err != nil {
+		return nil, fmt.Errorf("finding giver's holding: %w", err)
+	}
+	if src == nil {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("%s does not have %s", result.FromName, result.ItemName))
+	}
+	have := src.Quantity()
+	if have < input.Quantity {
+		return nil, apperror.NewBadRequest(
+			fmt.Sprintf("%s only has %d of %s", result.FromName, have, result.ItemName),
+		)
+	}
+	dst, err := s.holdings.FindHolding(ctx, campaignID, input.ToEntityID, input.ItemEntityID)
+	if err != nil {
+		return nil, fmt.Errorf("finding receiver's holding: %w", err)
+	}
+
+	// Credit the receiver before debiting the giver: if the second write
+	// fails the item is briefly in both inventories, which a GM can see and
+	// fix, rather than in neither.
+	if dst == nil {
+		meta, _ := json.Marshal(map[string]any{"quantity": input.Quantity, "equipped": false})
+		if err := s.holdings.CreateHolding(ctx, campaignID, input.ToEntityID, input.ItemEntityID, userID, meta); err != nil {
+			return nil, fmt.Errorf("adding to receiver: %w", err)
+		}
+		result.ToQuantity = input.Quantity
+	} else {
+		result.ToQuantity = dst.Quantity() + input.Quantity
+		if err := s.holdings.UpdateHolding(ctx, dst.RelationID, dst.withQuantity(result.ToQuantity)); err != nil {
+			return nil, fmt.Errorf("adding to receiver: %w", err)
+		}
+	}
+
+	result.FromQuantity = have - input.Quantity
+	if result.FromQuantity == 0 {
+		err = s.holdings.DeleteHolding(ctx, src.RelationID)
+	} else {
+		err = s.holdings.UpdateHolding(ctx, src.RelationID, src.withQuantity(result.FromQuantity))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("removing from giver: %w", err)
+	}
+
+	tx := &Transaction{
+		CampaignID:      campaignID,
+		ShopEntityID:    input.FromEntityID,
+		ItemEntityID:    input.ItemEntityID,
+		BuyerEntityID:   strPtr(input.ToEntityID),
+		Quantity:        input.Quantity,
+		Currency:        "gp",
+		TransactionType: TxTransfer,
+		Notes:           strPtr(strings.TrimSpace(input.Notes)),
+		CreatedBy:       strPtr(userID),
+	}
+	if err := s.repo.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("recording transfer: %w", err)
+	}
+	result.Transaction = tx
+	return result, nil
+}
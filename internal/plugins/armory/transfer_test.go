@@ -0,0 +1,174 @@
+package armory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeHoldings keeps "Has Item" stacks in memory, keyed by owner then item.
+type fakeHoldings struct {
+	nextID int
+	stacks map[string]map[string]*Holding
+	byID   map[int][2]string
+}
+
+func newFakeHoldings() *fakeHoldings {
+	return &fakeHoldings{nextID: 1, stacks: map[string]map[string]*Holding{}, byID: map[int][2]string{}}
+}
+
+func (f *fakeHoldings) give(owner, item string, qty int) {
+	meta, _ := json.Marshal(map[string]any{"quantity": qty, "equipped": true})
+	_ = f.CreateHolding(context.Background(), "c1", owner, item, "", meta)
+}
+
+func (f *fakeHoldings) qty(owner, item string) int {
+	h := f.stacks[owner][item]
+	if h == nil {
+		return 0
+	}
+	return h.Quantity()
+}
+
+func (f *fakeHoldings) FindHolding(_ context.Context, _, owner, item string) (*Holding, error) {
+	h := f.stacks[owner][item]
+	if h == nil {
+		return nil, nil
+	}
+	// Copy like a fresh read from the database.
+	meta := map[string]any{}
+	for k, v := range h.Metadata {
+		meta[k] = v
+	}
+	return &Holding{RelationID: h.RelationID, Metadata: meta}, nil
+}
+
+func (f *fakeHoldings) CreateHolding(_ context.Context, _, owner, item, _ string, metadata json.RawMessage) error {
+	h := &Holding{RelationID: f.nextID}
+	_ = json.Unmarshal(metadata, &h.Metadata)
+	if f.stacks[owner] == nil {
+		f.stacks[owner] = map[string]*Holding{}
+	}
+	f.stacks[owner][item] = h
+	f.byID[f.nextID] = [2]string{owner, item}
+	f.nextID++
+	return nil
+}
+
+func (f *fakeHoldings) UpdateHolding(_ context.Context, id int, metadata json.RawMessage) error {
+	k := f.byID[id]
+	h := f.stacks[k[0]][k[1]]
+	h.Metadata = nil
+	return json.Unmarshal(metadata, &h.Metadata)
+}
+
+func (f *fakeHoldings) DeleteHolding(_ context.Context, id int) error {
+	k := f.byID[id]
+	delete(f.stacks[k[0]], k[1])
+	delete(f.byID, id)
+	return nil
+}
+
+// fakeNamer knows the entities of campaign c1.
+type fakeNamer map[string]string
+
+func (n fakeNamer) EntityName(_ context.Context, campaignID, id string) (string, error) {
+	if name, ok := n[id]; ok && campaignID == "c1" {
+		return name, nil
+	}
+	return "", apperror.NewNotFound("entity not found")
+}
+
+func newTransferService(t *testing.T, h *fakeHoldings, repo *mockTransactionRepo) *transactionService {
+	t.Helper()
+	svc := NewTransactionService(repo)
+	svc.SetInventoryHoldings(h, fakeNamer{"potion": "Potion of Healing", "tyne": "Tyne", "mira": "Mira", "vault": "Guild Vault"})
+	return svc
+}
+
+func TestTransfer_MovesPartOfStack(t *testing.T) {
+	h := newFakeHoldings()
+	h.give("tyne", "potion", 3)
+	var recorded *Transaction
+	svc := newTransferService(t, h, &mockTransactionRepo{createFn: func(_ context.Context, tx *Transaction) error {
+		recorded = tx
+		return nil
+	}})
+
+	res, err := svc.Transfer(context.Background(), "c1", "u1", 1, TransferInput{
+		ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "mira", Quantity: 2,
+	})
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if h.qty("tyne", "potion") != 1 || h.qty("mira", "potion") != 2 {
+		t.Errorf("stacks tyne=%d mira=%d; want 1 and 2", h.qty("tyne", "potion"), h.qty("mira", "potion"))
+	}
+	if eq := h.stacks["tyne"]["potion"].Metadata["equipped"]; eq != true {
+		t.Errorf("giver's other metadata lost: equipped=%v", eq)
+	}
+	if res.FromQuantity != 1 || res.ToQuantity != 2 || res.ToName != "Mira" {
+		t.Errorf("result = %+v", res)
+	}
+	if recorded == nil || recorded.TransactionType != TxTransfer || recorded.ShopEntityID != "tyne" ||
+		recorded.BuyerEntityID == nil || *recorded.BuyerEntityID != "mira" || recorded.Quantity != 2 {
+		t.Errorf("transaction = %+v", recorded)
+	}
+}
+
+func TestTransfer_WholeStackMergesIntoReceiver(t *testing.T) {
+	h := newFakeHoldings()
+	h.give("tyne", "potion", 2)
+	h.give("vault", "potion", 5)
+	svc := newTransferService(t, h, &mockTransactionRepo{})
+
+	if _, err := svc.Transfer(context.Background(), "c1", "u1", 1, TransferInput{
+		ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "vault", Quantity: 2,
+	}); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if _, still := h.stacks["tyne"]["potion"]; still {
+		t.Error("giver's emptied stack should be removed")
+	}
+	if h.qty("vault", "potion") != 7 {
+		t.Errorf("vault has %d; want 7", h.qty("vault", "potion"))
+	}
+}
+
+func TestTransfer_Rejects(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  TransferInput
+		access bool
+		code   int
+	}{
+		{"same owner", TransferInput{ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "tyne"}, true, 400},
+		{"more than held", TransferInput{ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "mira", Quantity: 4}, true, 400},
+		{"not held", TransferInput{ItemEntityID: "potion", FromEntityID: "mira", ToEntityID: "tyne"}, true, 400},
+		{"negative quantity", TransferInput{ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "mira", Quantity: -1}, true, 400},
+		{"receiver in another campaign", TransferInput{ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "elsewhere"}, true, 404},
+		{"cannot edit giver", TransferInput{ItemEntityID: "potion", FromEntityID: "tyne", ToEntityID: "mira"}, false, 403},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newFakeHoldings()
+			h.give("tyne", "potion", 3)
+			svc := newTransferService(t, h, &mockTransactionRepo{})
+			svc.SetBuyerAccessChecker(&mockBuyerAccess{canFn: func(context.Context, string, string, int) (bool, error) {
+				return tc.access, nil
+			}})
+
+			_, err := svc.Transfer(context.Background(), "c1", "u1", 1, tc.input)
+			var ae *apperror.AppError
+			if !errors.As(err, &ae) || ae.Code != tc.code {
+				t.Fatalf("err = %v; want code %d", err, tc.code)
+			}
+			if h.qty("tyne", "potion") != 3 || h.qty("mira", "potion") != 0 {
+				t.Error("a rejected transfer must not change stacks")
+			}
+		})
+	}
+}
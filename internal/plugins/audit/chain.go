@@ -0,0 +1,219 @@
+package audit
+
+// chain.go — tamper evidence for the audit log. Every entry stores an HMAC
+// over its own fields and the previous entry's hash, so each campaign's log
+// forms a chain. Editing, deleting, inserting or reordering rows directly in
+// the database breaks the chain at that row, and without the server secret
+// the hashes can't be recomputed to hide it. audit_chain_heads records each
+// chain's newest entry (catching deleted tail rows) and its purge anchor (the
+// newest entry retention deleted, which the oldest survivor links to).
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// ChainVerification reports whether one campaign's audit chain is intact.
+// HeadID and HeadHash identify the newest entry; recording them somewhere
+// outside the database makes a later rewind of the log detectable too.
+type ChainVerification struct {
+	CampaignID string `json:"campaignId"`
+	Valid      bool   `json:"valid"`
+
+	// Entries is the number of sealed entries checked.
+	Entries int `json:"entries"`
+
+	// Unsealed counts entries written before chaining was introduced.
+	Unsealed int `json:"unsealed"`
+
+	// BrokenAt is the first entry that failed and Problem says why.
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+
+	HeadID   int64  `json:"headId"`
+	HeadHash string `json:"headHash,omitempty"`
+}
+
+// ChainService verifies and maintains audit log hash chains.
+type ChainService interface {
+	// VerifyChains checks one campaign's chain, or every campaign's when
+	// campaignID is empty.
+	VerifyChains(ctx context.Context, campaignID string) ([]ChainVerification, error)
+
+	// ResealMergedUser rehashes entries whose only change is an account
+	// merge rewriting sourceID to targetID, so a merge doesn't read as
+	// tampering. Entries that fail for any other reason stay broken.
+	ResealMergedUser(ctx context.Context, sourceID, targetID string) error
+}
+
+// chainService implements ChainService.
+type chainService struct {
+	repo AuditRepository
+}
+
+// NewChainService creates a chain service over the audit repository.
+func NewChainService(repo AuditRepository) ChainService {
+	return &chainService{repo: repo}
+}
+
+// VerifyChains verifies the requested chains, logging any that are broken.
+func (s *chainService) VerifyChains(ctx context.Context, campaignID string) ([]ChainVerification, error) {
+	ids := []string{campaignID}
+	if campaignID == "" {
+		var err error
+		if ids, err = s.repo.ListChainCampaigns(ctx); err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("listing audit chains: %w", err))
+		}
+	}
+
+	results := make([]ChainVerification, 0, len(ids))
+	for _, id := range ids {
+		v, err := s.repo.VerifyChain(ctx, id)
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("verifying audit chain: %w", err))
+		}
+		if !v.Valid {
+			slog.Warn("audit chain broken",
+				slog.String("campaign_id", id),
+				slog.Int64("entry_id", v.BrokenAt),
+				slog.String("problem", v.Problem),
+			)
+		}
+		results = append(results, *v)
+	}
+	return results, nil
+}
+
+// ResealMergedUser reseals the chains an account merge touched.
+func (s *chainService) ResealMergedUser(ctx context.Context, sourceID, targetID string) error {
+	n, err := s.repo.ResealMergedUser(ctx, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("resealing audit chains: %w", err)
+	}
+	if n > 0 {
+		slog.Info("audit chains resealed after account merge",
+			slog.String("source_id", sourceID),
+			slog.String("target_id", targetID),
+			slog.Int("entries", n),
+		)
+	}
+	return nil
+}
+
+// SetChainKey keys entry hashes with a value derived from the server
+// secret, so someone with only database access can't recompute them.
+// Changing the secret makes existing entries fail verification.
+func SetChainKey(repo AuditRepository, secret string) {
+	if r, ok := repo.(*auditRepository); ok {
+		sum := sha256.Sum256([]byte("chronicle audit chain\x00" + secret))
+		r.chainKey = sum[:]
+	}
+}
+
+// chainHead mirrors a row of audit_chain_heads.
+type chainHead struct {
+	headID     int64
+	headHash   string
+	anchorID   int64
+	anchorHash string
+}
+
+// chainRow is an audit_log row as stored. Details is the raw JSON text,
+// which is what the hash covers.
+type chainRow struct {
+	ID         int64
+	CampaignID string
+	UserID     string
+	Action     string
+	EntityType string
+	EntityID   string
+	EntityName string
+	Details    string
+	CreatedAt  time.Time
+	PrevHash   string
+	EntryHash  string
+}
+
+// entryHash returns the hex HMAC-SHA256 of row linked to prev. Fields are
+// length-prefixed so different rows never hash the same input. CreatedAt
+// counts in whole seconds, the precision audit_log stores.
+func entryHash(key []byte, prev string, row *chainRow) string {
+	mac := hmac.New(sha256.New, key)
+	for _, f := range []string{
+		prev, row.CampaignID, row.UserID, row.Action,
+		row.EntityType, row.EntityID, row.EntityName, row.Details,
+		strconv.FormatInt(row.CreatedAt.Unix(), 10),
+	} {
+		fmt.Fprintf(mac, "%d:%s", len(f), f)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// chainVerifier walks one campaign's entries in id order.
+type chainVerifier struct {
+	key     []byte
+	prev    string // Hash the next sealed entry must link to.
+	started bool   // A sealed entry has been seen.
+	result  ChainVerification
+}
+
+// newChainVerifier starts a walk at the chain's purge anchor. head is nil
+// for a campaign that has never written a sealed entry.
+func newChainVerifier(key []byte, campaignID string, head *chainHead) *chainVerifier {
+	v := &chainVerifier{key: key, result: ChainVerification{CampaignID: campaignID}}
+	if head != nil {
+		v.prev = head.anchorHash
+		v.result.HeadID, v.result.HeadHash = head.headID, head.headHash
+	}
+	return v
+}
+
+// check verifies the next entry and reports whether the walk should go on.
+// Unsealed entries are only allowed before the first sealed one.
+func (v *chainVerifier) check(row *chainRow) bool {
+	switch {
+	case row.EntryHash == "" && !v.started:
+		v.result.Unsealed++
+		return true
+	case row.EntryHash == "":
+		return v.fail(row.ID, "unsealed entry inside the chain")
+	case row.PrevHash != v.prev:
+		return v.fail(row.ID, "an earlier entry was removed, added or reordered")
+	case entryHash(v.key, v.prev, row) != row.EntryHash:
+		return v.fail(row.ID, "entry was modified")
+	}
+	v.started = true
+	v.prev = row.EntryHash
+	v.result.Entries++
+	return true
+}
+
+// fail records the first broken entry and stops the walk.
+func (v *chainVerifier) fail(id int64, problem string) bool {
+	v.result.BrokenAt, v.result.Problem = id, problem
+	return false
+}
+
+// finish checks that the walk ended at the recorded head, which catches
+// deleted newest entries, and returns the result.
+func (v *chainVerifier) finish(head *chainHead) *ChainVerification {
+	if v.result.Problem == "" {
+		switch {
+		case head == nil && v.started:
+			v.result.Problem = "chain head is missing"
+		case head != nil && v.prev != head.headHash:
+			v.result.BrokenAt = head.headID
+			v.result.Problem = "newest entries were removed"
+		}
+	}
+	v.result.Valid = v.result.Problem == ""
+	return &v.result
+}
@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var testChainKey = []byte("test-chain-key")
+
+// sealChain links and hashes rows the way Log does, returning the head.
+func sealChain(anchorHash string, rows []*chainRow) *chainHead {
+	head := &chainHead{anchorHash: anchorHash, headHash: anchorHash}
+	for _, row := range rows {
+		row.PrevHash = head.headHash
+		row.EntryHash = entryHash(testChainKey, head.headHash, row)
+		head.headID, head.headHash = row.ID, row.EntryHash
+	}
+	return head
+}
+
+func testChainRows(n int) []*chainRow {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rows := make([]*chainRow, n)
+	for i := range rows {
+		rows[i] = &chainRow{
+			ID: int64(i + 1), CampaignID: "camp-1", UserID: "user-1",
+			Action: ActionEntityUpdated, EntityID: "ent-1", EntityName: "Tyne",
+			Details: `{"field":"name"}`, CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return rows
+}
+
+func verifyRows(key []byte, head *chainHead, rows []*chainRow) *ChainVerification {
+	v := newChainVerifier(key, "camp-1", head)
+	for _, row := range rows {
+		if !v.check(row) {
+			break
+		}
+	}
+	return v.finish(head)
+}
+
+func TestChainVerifier(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		tamper   func(rows []*chainRow, head *chainHead) []*chainRow
+		key      []byte
+		brokenAt int64
+		problem  string
+	}{
+		{name: "intact"},
+		{
+			name:     "modified entry",
+			tamper:   func(rows []*chainRow, _ *chainHead) []*chainRow { rows[2].UserID = "user-2"; return rows },
+			brokenAt: 3, problem: "entry was modified",
+		},
+		{
+			name: "deleted middle entry",
+			tamper: func(rows []*chainRow, _ *chainHead) []*chainRow {
+				return append(rows[:2:2], rows[3:]...)
+			},
+			brokenAt: 4, problem: "an earlier entry was removed, added or reordered",
+		},
+		{
+			name:     "deleted newest entry",
+			tamper:   func(rows []*chainRow, _ *chainHead) []*chainRow { return rows[:4] },
+			brokenAt: 5, problem: "newest entries were removed",
+		},
+		{
+			name: "unsealed entry inside chain",
+			tamper: func(rows []*chainRow, _ *chainHead) []*chainRow {
+				rows[3].PrevHash, rows[3].EntryHash = "", ""
+				return rows
+			},
+			brokenAt: 4, problem: "unsealed entry inside the chain",
+		},
+		{
+			name:     "different key",
+			key:      []byte("other-key"),
+			brokenAt: 1, problem: "entry was modified",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rows := testChainRows(5)
+			head := sealChain("", rows)
+			if tc.tamper != nil {
+				rows = tc.tamper(rows, head)
+			}
+			key := testChainKey
+			if tc.key != nil {
+				key = tc.key
+			}
+			got := verifyRows(key, head, rows)
+			if got.Valid != (tc.problem == "") || got.Problem != tc.problem || got.BrokenAt != tc.brokenAt {
+				t.Errorf("got %+v, want broken at %d (%q)", got, tc.brokenAt, tc.problem)
+			}
+		})
+	}
+}
+
+func TestChainVerifier_LegacyAndPurged(t *testing.T) {
+	rows := testChainRows(6)
+	// The first two entries predate chaining.
+	head := sealChain("", rows[2:])
+	got := verifyRows(testChainKey, head, rows)
+	if !got.Valid || got.Unsealed != 2 || got.Entries != 4 {
+		t.Fatalf("legacy prefix: %+v", got)
+	}
+
+	// Retention purged entries up to #4; #5 still links to the anchor.
+	head.anchorID, head.anchorHash = 4, rows[3].EntryHash
+	if got := verifyRows(testChainKey, head, rows[4:]); !got.Valid || got.Entries != 2 {
+		t.Errorf("after purge: %+v", got)
+	}
+	// Dropping the anchor exposes the missing entries.
+	head.anchorHash = ""
+	if got := verifyRows(testChainKey, head, rows[4:]); got.Valid || got.BrokenAt != 5 {
+		t.Errorf("without anchor: %+v", got)
+	}
+}
+
+func TestSealedAsMerged(t *testing.T) {
+	r := &auditRepository{chainKey: testChainKey}
+	rows := testChainRows(2)
+	sealChain("", rows)
+
+	merged := *rows[1]
+	merged.UserID = "user-keep"
+	if !r.sealedAsMerged(rows[0].EntryHash, &merged, "user-1", "user-keep") {
+		t.Error("entry rewritten by the merge was rejected")
+	}
+	edited := merged
+	edited.EntityName = "Someone else"
+	if r.sealedAsMerged(rows[0].EntryHash, &edited, "user-1", "user-keep") {
+		t.Error("edited entry accepted")
+	}
+	if r.sealedAsMerged(rows[0].EntryHash, &merged, "user-2", "user-keep") {
+		t.Error("entry accepted for a different merge")
+	}
+}
+
+func TestAdminVerifyChain(t *testing.T) {
+	repo := &mockAuditRepo{
+		listChainsFn: func(context.Context) ([]string, error) { return []string{"camp-1", "camp-2"}, nil },
+		verifyChainFn: func(_ context.Context, id string) (*ChainVerification, error) {
+			if id == "camp-2" {
+				return &ChainVerification{CampaignID: id, BrokenAt: 9, Problem: "entry was modified"}, nil
+			}
+			return &ChainVerification{CampaignID: id, Valid: true}, nil
+		},
+	}
+	h := NewHandler(newTestAuditService(repo))
+	h.SetChainService(NewChainService(repo))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit/verify", nil)
+	rec := httptest.NewRecorder()
+	if err := h.AdminVerifyChain(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("AdminVerifyChain: %v", err)
+	}
+	var report chainReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if report.Valid || len(report.Campaigns) != 2 || report.Campaigns[1].BrokenAt != 9 {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,227 @@
+package audit
+
+// diff.go — before/after diffs on audit entries. A plugin snapshots the
+// record it is about to change, snapshots it again afterwards, and stores
+// Diff(before, after) under Details["changes"], so the activity feed can
+// say what changed and not just that something did. Values are stored as
+// JSON-normalized copies; long text is cut to maxChangeValueLen and the
+// change marked Partial. Changes to values players may not see (GM-only
+// fields, dm_only events) are marked Private and dropped by
+// RedactPrivateChanges before a history reaches a player.
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// DetailsChanges is the Details key holding an entry's []Change.
+const DetailsChanges = "changes"
+
+// maxChangeValueLen caps a stored string value, in runes.
+const maxChangeValueLen = 300
+
+// SnapshotField is one audited value of a record at a point in time.
+type SnapshotField struct {
+	// Key identifies the value across snapshots, e.g. "name" or "fields.hp".
+	Key string
+	// Label is the name shown in the activity feed.
+	Label string
+	Value any
+	// Private hides the change from players.
+	Private bool
+	// Partial marks a value that only describes the stored one (a word
+	// count standing in for an entry), so it can't be restored.
+	Partial bool
+}
+
+// Change is one value that differs between two snapshots.
+type Change struct {
+	Field   string `json:"field"`
+	Label   string `json:"label"`
+	Before  any    `json:"before"`
+	After   any    `json:"after"`
+	Private bool   `json:"private,omitempty"`
+	Partial bool   `json:"partial,omitempty"`
+}
+
+// Diff returns the values that differ between two snapshots of the same
+// record, in after's order followed by keys only before has. A key missing
+// from one side counts as nil there.
+func Diff(before, after []SnapshotField) []Change {
+	old := make(map[string]SnapshotField, len(before))
+	for _, f := range before {
+		old[f.Key] = f
+	}
+
+	var changes []Change
+	seen := make(map[string]bool, len(after))
+	for _, a := range after {
+		seen[a.Key] = true
+		b, ok := old[a.Key]
+		if !ok {
+			b = SnapshotField{Key: a.Key}
+		}
+		if c, changed := diffField(b, a); changed {
+			changes = append(changes, c)
+		}
+	}
+	for _, b := range before {
+		if !seen[b.Key] {
+			if c, changed := diffField(b, SnapshotField{Key: b.Key, Label: b.Label, Private: b.Private}); changed {
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// diffField compares one value and builds its Change.
+func diffField(before, after SnapshotField) (Change, bool) {
+	bv, bCut := normalizeValue(before.Value)
+	av, aCut := normalizeValue(after.Value)
+	if reflect.DeepEqual(bv, av) {
+		return Change{}, false
+	}
+	label := after.Label
+	if label == "" {
+		label = before.Label
+	}
+	return Change{
+		Field:   after.Key,
+		Label:   label,
+		Before:  bv,
+		After:   av,
+		Private: before.Private || after.Private,
+		Partial: before.Partial || after.Partial || bCut || aCut,
+	}, true
+}
+
+// normalizeValue converts v to the form it takes after a JSON round trip
+// (pointers dereferenced, numbers float64), so a fresh snapshot compares
+// equal to a stored one. Long strings are cut; the bool reports it.
+func normalizeValue(v any) (any, bool) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v), false
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return fmt.Sprint(v), false
+	}
+	if s, ok := out.(string); ok {
+		if r := []rune(s); len(r) > maxChangeValueLen {
+			return string(r[:maxChangeValueLen]) + "…", true
+		}
+	}
+	return out, false
+}
+
+// WithChanges returns details with changes stored under DetailsChanges.
+// details may be nil; it is returned unchanged when there are no changes.
+func WithChanges(details map[string]any, changes []Change) map[string]any {
+	if len(changes) == 0 {
+		return details
+	}
+	if details == nil {
+		details = make(map[string]any, 1)
+	}
+	details[DetailsChanges] = changes
+	return details
+}
+
+// Changes returns the diff stored on the entry, or nil if it has none.
+// Read-back entries hold the diff as decoded JSON; a round trip turns
+// either form into []Change.
+func (e *AuditEntry) Changes() []Change {
+	v, ok := e.Details[DetailsChanges]
+	if !ok {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var changes []Change
+	if err := json.Unmarshal(raw, &changes); err != nil {
+		return nil
+	}
+	return changes
+}
+
+// RedactPrivateChanges strips Private changes from entries' diffs, for
+// viewers below the GM bar. Entries are copied; the input is not modified.
+func RedactPrivateChanges(entries []AuditEntry) []AuditEntry {
+	out := make([]AuditEntry, len(entries))
+	for i, e := range entries {
+		out[i] = e
+		changes := e.Changes()
+		if changes == nil {
+			continue
+		}
+		visible := make([]Change, 0, len(changes))
+		for _, c := range changes {
+			if !c.Private {
+				visible = append(visible, c)
+			}
+		}
+		details := make(map[string]any, len(e.Details))
+		for k, v := range e.Details {
+			details[k] = v
+		}
+		delete(details, DetailsChanges)
+		if len(visible) < len(changes) {
+			details["redacted"] = len(changes) - len(visible)
+		}
+		out[i].Details = WithChanges(details, visible)
+	}
+	return out
+}
+
+// FormatChangeValue renders a stored change value for the activity feed.
+func FormatChangeValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "(empty)"
+	case string:
+		if val == "" {
+			return "(empty)"
+		}
+		return val
+	case bool:
+		if val {
+			return "yes"
+		}
+		return "no"
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			return fmt.Sprintf("%d", int64(val))
+		}
+		return fmt.Sprint(val)
+	case map[string]any:
+		// References are stored as {"id", "name"} so they stay revertible
+		// and readable; stand-ins for long content carry a "summary".
+		if name, ok := val["name"].(string); ok && name != "" {
+			return name
+		}
+		if summary, ok := val["summary"].(string); ok {
+			return summary
+		}
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, FormatChangeValue(item))
+		}
+		if len(parts) == 0 {
+			return "(empty)"
+		}
+		return strings.Join(parts, ", ")
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
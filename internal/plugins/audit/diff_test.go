@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+func TestDiff(t *testing.T) {
+	hp := 10
+	before := []SnapshotField{
+		{Key: "name", Label: "Name", Value: "Tyne"},
+		{Key: "fields.hp", Label: "HP", Value: &hp},
+		{Key: "fields.secret", Label: "Secret", Value: "old plan", Private: true},
+		{Key: "fields.gone", Label: "Gone", Value: "bye"},
+	}
+	after := []SnapshotField{
+		{Key: "name", Label: "Name", Value: "Tyne the Bold"},
+		{Key: "fields.hp", Label: "HP", Value: 10.0}, // Same value, other Go type.
+		{Key: "fields.secret", Label: "Secret", Value: "new plan", Private: true},
+		{Key: "fields.bio", Label: "Bio", Value: strings.Repeat("a", maxChangeValueLen+10)},
+	}
+
+	changes := Diff(before, after)
+	if len(changes) != 4 {
+		t.Fatalf("got %d changes, want 4: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.Field != "name" || c.Before != "Tyne" || c.After != "Tyne the Bold" || c.Private {
+		t.Errorf("name change = %+v", c)
+	}
+	if !changes[1].Private {
+		t.Errorf("secret change not private: %+v", changes[1])
+	}
+	if c := changes[2]; c.Field != "fields.bio" || c.Before != nil || !c.Partial {
+		t.Errorf("long added value should be partial: %+v", c)
+	}
+	if c := changes[3]; c.Field != "fields.gone" || c.Label != "Gone" || c.After != nil {
+		t.Errorf("removed value = %+v", c)
+	}
+}
+
+func TestChanges_RoundTrip(t *testing.T) {
+	entry := AuditEntry{Details: WithChanges(nil, []Change{{Field: "name", Label: "Name", Before: "a", After: "b"}})}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored AuditEntry
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		t.Fatal(err)
+	}
+	got := stored.Changes()
+	if len(got) != 1 || got[0].Field != "name" || got[0].After != "b" {
+		t.Errorf("Changes() after round trip = %+v", got)
+	}
+	if WithChanges(nil, nil) != nil {
+		t.Error("WithChanges with no changes should leave details nil")
+	}
+}
+
+func TestRedactPrivateChanges(t *testing.T) {
+	entries := []AuditEntry{
+		{ID: 1, Details: WithChanges(map[string]any{"slug": "x"}, []Change{
+			{Field: "name", Before: "a", After: "b"},
+			{Field: "fields.secret", Before: "s1", After: "s2", Private: true},
+		})},
+		{ID: 2, Details: WithChanges(nil, []Change{{Field: "fields.secret", Private: true}})},
+		{ID: 3},
+	}
+
+	got := RedactPrivateChanges(entries)
+	if c := got[0].Changes(); len(c) != 1 || c[0].Field != "name" {
+		t.Errorf("entry 1 changes = %+v", c)
+	}
+	if got[0].Details["slug"] != "x" || got[0].Details["redacted"] != 1 {
+		t.Errorf("entry 1 details = %+v", got[0].Details)
+	}
+	if got[1].Changes() != nil {
+		t.Errorf("entry 2 kept private changes: %+v", got[1].Details)
+	}
+	if len(entries[0].Changes()) != 2 {
+		t.Error("input entries were modified")
+	}
+}
+
+func TestFormatChangeValue(t *testing.T) {
+	for _, tc := range []struct {
+		in   any
+		want string
+	}{
+		{nil, "(empty)"},
+		{"", "(empty)"},
+		{true, "yes"},
+		{float64(42), "42"},
+		{1.5, "1.5"},
+		{map[string]any{"id": "e1", "name": "Waterdeep"}, "Waterdeep"},
+		{map[string]any{"summary": "120 words", "digest": "ab"}, "120 words"},
+		{[]any{"a", "b"}, "a, b"},
+	} {
+		if got := FormatChangeValue(tc.in); got != tc.want {
+			t.Errorf("FormatChangeValue(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// historyWithChangesSvc serves one entry whose diff holds a GM-only change.
+type historyWithChangesSvc struct {
+	AuditService
+}
+
+func (historyWithChangesSvc) GetEntityHistory(context.Context, string, string) ([]AuditEntry, error) {
+	return []AuditEntry{{ID: 1, Details: WithChanges(nil, []Change{
+		{Field: "name", Label: "Name", Before: "a", After: "b"},
+		{Field: "fields.secret", Label: "Secret", Before: "hidden-before", After: "hidden-after", Private: true},
+	})}}, nil
+}
+
+func TestEntityHistory_RedactsPrivateChangesForPlayers(t *testing.T) {
+	for _, tc := range []struct {
+		role       campaigns.Role
+		wantSecret bool
+	}{
+		{campaigns.RolePlayer, false},
+		{campaigns.RoleScribe, true},
+		{campaigns.RoleOwner, true},
+	} {
+		h := NewHandler(historyWithChangesSvc{})
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id", "eid")
+		c.SetParamValues("c1", "e1")
+		c.Set("campaign_context", &campaigns.CampaignContext{
+			Campaign: &campaigns.Campaign{ID: "c1"}, MemberRole: tc.role, IsMember: true,
+		})
+		if err := h.EntityHistory(c); err != nil {
+			t.Fatalf("role %d: %v", tc.role, err)
+		}
+		body := rec.Body.String()
+		if got := strings.Contains(body, "hidden-after"); got != tc.wantSecret {
+			t.Errorf("role %d: secret in response = %v, want %v: %s", tc.role, got, tc.wantSecret, body)
+		}
+		if !strings.Contains(body, `"field":"name"`) {
+			t.Errorf("role %d: public change missing: %s", tc.role, body)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package audit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -32,6 +34,8 @@ type EntityViewGuard interface {
 type Handler struct {
 	service     AuditService
 	entityGuard EntityViewGuard
+	chain       ChainService
+	reverts     RevertService
 }
 
 // NewHandler creates a new audit handler.
@@ -45,6 +49,16 @@ func (h *Handler) SetEntityViewGuard(g EntityViewGuard) {
 	h.entityGuard = g
 }
 
+// SetChainService wires the audit chain verification behind AdminVerifyChain.
+func (h *Handler) SetChainService(svc ChainService) {
+	h.chain = svc
+}
+
+// SetRevertService wires the activity feed's revert action.
+func (h *Handler) SetRevertService(svc RevertService) {
+	h.reverts = svc
+}
+
 // Activity redirects to the unified settings page Activity tab.
 // GET /campaigns/:id/activity
 func (h *Handler) Activity(c echo.Context) error {
@@ -85,6 +99,34 @@ func (h *Handler) ActivityFragment(c echo.Context) error {
 	return middleware.Render(c, http.StatusOK, ActivityContent(cc, stats, entries, total, page, perPage))
 }
 
+// RevertEntry reverts an audited edit and re-renders the activity page it
+// was clicked on, with a toast. Conflicts and unrevertible entries surface
+// as error toasts. POST /campaigns/:id/activity/:entryId/revert
+func (h *Handler) RevertEntry(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if h.reverts == nil {
+		return apperror.NewNotFound("reverting changes is not available")
+	}
+
+	entryID, err := strconv.ParseInt(c.Param("entryId"), 10, 64)
+	if err != nil {
+		return apperror.NewBadRequest("invalid activity entry")
+	}
+	if _, err := h.reverts.Revert(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), entryID); err != nil {
+		return err
+	}
+
+	if trigger, err := json.Marshal(map[string]any{
+		"chronicle:notify": map[string]string{"message": "Change reverted", "type": "success"},
+	}); err == nil {
+		c.Response().Header().Set("HX-Trigger", string(trigger))
+	}
+	return h.ActivityFragment(c)
+}
+
 // EmbedActivity returns an HTMX fragment for the dashboard activity feed block.
 // Shows recent campaign activity entries in a compact feed format.
 // GET /campaigns/:id/activity/embed
@@ -120,7 +162,8 @@ func (h *Handler) EmbedActivity(c echo.Context) error {
 
 // EntityHistory returns JSON history for a specific entity
 // (GET /campaigns/:id/entities/:eid/history). Used by HTMX or API clients
-// to display per-entity change logs.
+// to display per-entity change logs. Private changes in an entry's diff
+// are removed for Players.
 func (h *Handler) EntityHistory(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
 	if cc == nil {
@@ -151,6 +194,39 @@ func (h *Handler) EntityHistory(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	// Diffs can carry GM-only values; players get them redacted, on the
+	// same Scribe+ bar the entities plugin uses for GM-only fields.
+	if cc.MemberRole < campaigns.RoleScribe {
+		entries = RedactPrivateChanges(entries)
+	}
 
 	return c.JSON(http.StatusOK, entries)
 }
+
+// chainReport is the AdminVerifyChain response.
+type chainReport struct {
+	Valid      bool                `json:"valid"`
+	VerifiedAt time.Time           `json:"verifiedAt"`
+	Campaigns  []ChainVerification `json:"campaigns"`
+}
+
+// AdminVerifyChain checks the audit log hash chains and reports any broken
+// entries (GET /admin/audit/verify). campaign_id limits it to one campaign.
+func (h *Handler) AdminVerifyChain(c echo.Context) error {
+	if h.chain == nil {
+		return apperror.NewMissingContext()
+	}
+
+	results, err := h.chain.VerifyChains(c.Request().Context(), c.QueryParam("campaign_id"))
+	if err != nil {
+		return err
+	}
+
+	report := chainReport{Valid: true, VerifiedAt: time.Now().UTC(), Campaigns: results}
+	for _, r := range results {
+		if !r.Valid {
+			report.Valid = false
+		}
+	}
+	return c.JSON(http.StatusOK, report)
+}
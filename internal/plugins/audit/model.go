@@ -33,6 +33,10 @@ const (
 	// clears an entity's owner (the GM-side counterpart to a player claim).
 	ActionEntityOwnerChanged = "entity.owner_changed"
 
+	// ActionItemTransferred is logged when the armory moves an item between
+	// two owners' inventories. EntityID is the item.
+	ActionItemTransferred = "item.transferred"
+
 	// ActionMemberJoined is logged when a user is added to a campaign.
 	ActionMemberJoined = "member.joined"
 
@@ -60,6 +64,11 @@ const (
 	// ActionTagDeleted is logged when a tag is removed from a campaign.
 	ActionTagDeleted = "tag.deleted"
 
+	// ActionChangeReverted is logged when an owner reverts an audited edit
+	// from the activity feed. Details carry the reverted entry's id and the
+	// revert's own diff, so a revert can itself be reverted.
+	ActionChangeReverted = "change.reverted"
+
 	// --- Calendar plugin (V2 Wave 0 PR 4 / C-CAL-V2-AUDIT-LOG-INTEGRATION) ---
 	// Naming: snake_case verb suffix after the resource. Counts-only
 	// payload discipline — bulk Set* events log before/after counts in
@@ -75,6 +84,8 @@ const (
 	ActionCalendarSeasonsSet         = "calendar.seasons_set"
 	ActionCalendarErasSet            = "calendar.eras_set"
 	ActionCalendarCategoriesSet      = "calendar.categories_set"
+	ActionCalendarCategoryUpserted   = "calendar.category_upserted"
+	ActionCalendarCategoryDeleted    = "calendar.category_deleted"
 	ActionCalendarVisibilityChanged  = "calendar.visibility_changed"
 	ActionCalendarEraCreated         = "calendar.era_created"
 	ActionCalendarEraUpdated         = "calendar.era_updated"
@@ -82,17 +93,23 @@ const (
 	ActionCalendarWeatherSet         = "calendar.weather_set"
 	ActionCalendarCyclesSet          = "calendar.cycles_set"
 	ActionCalendarFestivalsSet       = "calendar.festivals_set"
+	ActionCalendarTimePresetsSet     = "calendar.time_presets_set"
+	ActionCalendarYearNamesSet       = "calendar.year_names_set"
 	ActionCalendarWeatherZonesSet    = "calendar.weather_zones_set"
+	ActionCalendarZoneWeatherSet     = "calendar.zone_weather_set"
+	ActionCalendarLocationZonesSet   = "calendar.location_zones_set"
 	// ActionCalendarWeatherActiveZoneChanged covers SetActiveWeatherZone
 	// (added in PR #360 alongside SetWeatherZones; refresh per coordinator
 	// commit 672ef9e expanded the dispatch to log this separately).
 	ActionCalendarWeatherActiveZoneChanged = "calendar.weather_active_zone_changed"
 	ActionCalendarEventCreated             = "calendar.event_created"
+	ActionCalendarEventsBulkCreated        = "calendar.events_bulk_created"
 	ActionCalendarEventUpdated             = "calendar.event_updated"
 	ActionCalendarEventDeleted             = "calendar.event_deleted"
 	ActionCalendarEventVisibilityChanged   = "calendar.event_visibility_changed"
 	ActionCalendarDateAdvanced             = "calendar.date_advanced"
 	ActionCalendarTimeAdvanced             = "calendar.time_advanced"
+	ActionCalendarAdvanceUndone            = "calendar.advance_undone"
 	ActionCalendarDateSet                  = "calendar.date_set"
 	ActionCalendarImported                 = "calendar.imported"
 	// Note: tier definitions are emitted by the campaigns plugin
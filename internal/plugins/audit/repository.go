@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -24,17 +25,45 @@ type AuditRepository interface {
 	// change history (SEC-IDOR-2).
 	ListByEntity(ctx context.Context, entityID, campaignID string, limit int) ([]AuditEntry, error)
 
+	// GetByID returns one of a campaign's audit entries, or nil when no
+	// entry with that id belongs to the campaign.
+	GetByID(ctx context.Context, campaignID string, id int64) (*AuditEntry, error)
+
+	// ListEntityChangesSince returns a campaign's entity.created and
+	// entity.updated entries at or after since, most recent first. Used by
+	// the weekly campaign digest.
+	ListEntityChangesSince(ctx context.Context, campaignID string, since time.Time, limit int) ([]AuditEntry, error)
+
 	// CountByCampaign returns the total number of audit entries for a campaign.
 	CountByCampaign(ctx context.Context, campaignID string) (int, error)
 
 	// GetCampaignStats returns aggregate statistics for a campaign including
 	// entity count, approximate word count, last edit time, and active editors.
 	GetCampaignStats(ctx context.Context, campaignID string) (*CampaignStats, error)
+
+	// PurgeBefore deletes up to limit of a campaign's entries created before
+	// the cutoff and returns the number deleted. Used by the retention job.
+	// Entries go oldest first and the chain's purge anchor moves past them.
+	PurgeBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error)
+
+	// ListChainCampaigns returns every campaign with audit entries or a
+	// chain head.
+	ListChainCampaigns(ctx context.Context) ([]string, error)
+
+	// VerifyChain walks a campaign's audit chain from its purge anchor.
+	VerifyChain(ctx context.Context, campaignID string) (*ChainVerification, error)
+
+	// ResealMergedUser rehashes chain entries an account merge moved from
+	// sourceID to targetID and returns how many entries it rewrote.
+	ResealMergedUser(ctx context.Context, sourceID, targetID string) (int, error)
 }
 
 // auditRepository implements AuditRepository with MariaDB queries.
 type auditRepository struct {
 	db *sql.DB
+
+	// chainKey keys entry hashes; see SetChainKey.
+	chainKey []byte
 }
 
 // NewAuditRepository creates a new repository backed by the given DB pool.
@@ -43,11 +72,10 @@ func NewAuditRepository(db *sql.DB) AuditRepository {
 }
 
 // Log inserts a new audit entry. The details map is serialized to JSON
-// before storage. Nil details are stored as SQL NULL.
+// before storage. Nil details are stored as SQL NULL. The entry is sealed
+// onto its campaign's chain: the chain head row is locked for the insert so
+// concurrent writers link one after another.
 func (r *auditRepository) Log(ctx context.Context, entry *AuditEntry) error {
-	query := `INSERT INTO audit_log (campaign_id, user_id, action, entity_type, entity_id, entity_name, details, created_at)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-
 	var detailsJSON []byte
 	if entry.Details != nil {
 		var err error
@@ -60,11 +88,32 @@ func (r *auditRepository) Log(ctx context.Context, entry *AuditEntry) error {
 	if entry.CreatedAt.IsZero() {
 		entry.CreatedAt = time.Now().UTC()
 	}
+	// audit_log stores whole seconds; hash what will be read back.
+	entry.CreatedAt = entry.CreatedAt.Truncate(time.Second)
 
-	result, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning audit insert: %w", err)
+	}
+	defer tx.Rollback()
+
+	head, err := lockChainHead(ctx, tx, entry.CampaignID)
+	if err != nil {
+		return err
+	}
+	row := chainRow{
+		CampaignID: entry.CampaignID, UserID: entry.UserID, Action: entry.Action,
+		EntityType: entry.EntityType, EntityID: entry.EntityID, EntityName: entry.EntityName,
+		Details: string(detailsJSON), CreatedAt: entry.CreatedAt,
+	}
+	hash := entryHash(r.chainKey, head.headHash, &row)
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (campaign_id, user_id, action, entity_type, entity_id, entity_name, details, created_at, prev_hash, entry_hash)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		entry.CampaignID, entry.UserID, entry.Action,
 		entry.EntityType, entry.EntityID, entry.EntityName,
-		detailsJSON, entry.CreatedAt,
+		detailsJSON, entry.CreatedAt, head.headHash, hash,
 	)
 	if err != nil {
 		return fmt.Errorf("inserting audit entry: %w", err)
@@ -74,6 +123,15 @@ func (r *auditRepository) Log(ctx context.Context, entry *AuditEntry) error {
 	if err != nil {
 		return fmt.Errorf("getting audit entry id: %w", err)
 	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE audit_chain_heads SET head_id = ?, head_hash = ? WHERE campaign_id = ?`,
+		id, hash, entry.CampaignID,
+	); err != nil {
+		return fmt.Errorf("advancing audit chain head: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing audit entry: %w", err)
+	}
 	entry.ID = id
 
 	return nil
@@ -137,6 +195,52 @@ func (r *auditRepository) ListByEntity(ctx context.Context, entityID, campaignID
 	return scanAuditRows(rows)
 }
 
+// GetByID returns a single audit entry scoped to campaignID. Returns nil,
+// nil when it doesn't exist or belongs to another campaign.
+func (r *auditRepository) GetByID(ctx context.Context, campaignID string, id int64) (*AuditEntry, error) {
+	query := `SELECT a.id, a.campaign_id, a.user_id, a.action,
+	                 a.entity_type, a.entity_id, a.entity_name,
+	                 a.details, a.created_at,
+	                 COALESCE(u.display_name, 'Unknown User') AS user_name
+	          FROM audit_log a
+	          LEFT JOIN users u ON u.id = a.user_id
+	          WHERE a.id = ? AND a.campaign_id = ?`
+
+	rows, err := r.db.QueryContext(ctx, query, id, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("getting audit entry: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanAuditRows(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// ListEntityChangesSince returns recent entity create/update entries for a
+// campaign, newest first.
+func (r *auditRepository) ListEntityChangesSince(ctx context.Context, campaignID string, since time.Time, limit int) ([]AuditEntry, error) {
+	query := `SELECT a.id, a.campaign_id, a.user_id, a.action,
+	                 a.entity_type, a.entity_id, a.entity_name,
+	                 a.details, a.created_at,
+	                 COALESCE(u.display_name, 'Unknown User') AS user_name
+	          FROM audit_log a
+	          LEFT JOIN users u ON u.id = a.user_id
+	          WHERE a.campaign_id = ? AND a.action IN (?, ?) AND a.created_at >= ?
+	          ORDER BY a.created_at DESC
+	          LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, ActionEntityCreated, ActionEntityUpdated, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing entity changes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAuditRows(rows)
+}
+
 // CountByCampaign returns the total number of audit entries for a campaign.
 func (r *auditRepository) CountByCampaign(ctx context.Context, campaignID string) (int, error) {
 	var count int
@@ -149,6 +253,295 @@ func (r *auditRepository) CountByCampaign(ctx context.Context, campaignID string
 	return count, nil
 }
 
+// PurgeBefore deletes a campaign's audit entries older than before, at most
+// limit rows per call so the retention job can purge in short batches.
+// Rows go in id order and the chain's purge anchor moves to the newest one
+// deleted, so the oldest surviving entry still verifies.
+func (r *auditRepository) PurgeBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning audit purge: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the head first, the same order Log takes its locks in.
+	if _, err := lockChainHead(ctx, tx, campaignID); err != nil {
+		return 0, err
+	}
+
+	var lastID int64
+	var lastHash string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, entry_hash FROM (
+		     SELECT id, entry_hash FROM audit_log
+		     WHERE campaign_id = ? AND created_at < ?
+		     ORDER BY id LIMIT ?
+		 ) oldest ORDER BY id DESC LIMIT 1`,
+		campaignID, before, limit,
+	).Scan(&lastID, &lastHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("finding audit entries to purge: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM audit_log WHERE campaign_id = ? AND id <= ?`, campaignID, lastID)
+	if err != nil {
+		return 0, fmt.Errorf("purging audit entries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE audit_chain_heads SET anchor_id = ?, anchor_hash = ? WHERE campaign_id = ?`,
+		lastID, lastHash, campaignID,
+	); err != nil {
+		return 0, fmt.Errorf("moving audit chain anchor: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing audit purge: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// lockChainHead creates the campaign's chain head if needed and locks it
+// for the rest of tx.
+func lockChainHead(ctx context.Context, tx *sql.Tx, campaignID string) (*chainHead, error) {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT IGNORE INTO audit_chain_heads (campaign_id) VALUES (?)`, campaignID,
+	); err != nil {
+		return nil, fmt.Errorf("creating audit chain head: %w", err)
+	}
+	var h chainHead
+	if err := tx.QueryRowContext(ctx,
+		`SELECT head_id, head_hash, anchor_id, anchor_hash FROM audit_chain_heads
+		 WHERE campaign_id = ? FOR UPDATE`, campaignID,
+	).Scan(&h.headID, &h.headHash, &h.anchorID, &h.anchorHash); err != nil {
+		return nil, fmt.Errorf("locking audit chain head: %w", err)
+	}
+	return &h, nil
+}
+
+// chainRowColumns is the column list scanChainRow expects.
+const chainRowColumns = `id, campaign_id, user_id, action, entity_type, entity_id, entity_name,
+	COALESCE(details, ''), created_at, prev_hash, entry_hash`
+
+// scanChainRow scans one row selected with chainRowColumns.
+func scanChainRow(rows *sql.Rows) (*chainRow, error) {
+	var c chainRow
+	if err := rows.Scan(&c.ID, &c.CampaignID, &c.UserID, &c.Action, &c.EntityType, &c.EntityID,
+		&c.EntityName, &c.Details, &c.CreatedAt, &c.PrevHash, &c.EntryHash); err != nil {
+		return nil, fmt.Errorf("scanning audit chain entry: %w", err)
+	}
+	return &c, nil
+}
+
+// ListChainCampaigns returns every campaign with audit entries or a chain head.
+func (r *auditRepository) ListChainCampaigns(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT campaign_id FROM audit_chain_heads
+		 UNION SELECT DISTINCT campaign_id FROM audit_log
+		 ORDER BY campaign_id`)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit chains: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning audit chain campaign: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// VerifyChain reads the chain head, then streams the campaign's entries past
+// the purge anchor through a chainVerifier.
+func (r *auditRepository) VerifyChain(ctx context.Context, campaignID string) (*ChainVerification, error) {
+	var head *chainHead
+	var h chainHead
+	err := r.db.QueryRowContext(ctx,
+		`SELECT head_id, head_hash, anchor_id, anchor_hash FROM audit_chain_heads WHERE campaign_id = ?`,
+		campaignID,
+	).Scan(&h.headID, &h.headHash, &h.anchorID, &h.anchorHash)
+	switch {
+	case err == nil:
+		head = &h
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("reading audit chain head: %w", err)
+	}
+
+	v := newChainVerifier(r.chainKey, campaignID, head)
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+chainRowColumns+` FROM audit_log WHERE campaign_id = ? AND id > ? ORDER BY id`,
+		campaignID, h.anchorID)
+	if err != nil {
+		return nil, fmt.Errorf("reading audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanChainRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		if !v.check(row) {
+			return v.finish(head), nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating audit chain: %w", err)
+	}
+	return v.finish(head), nil
+}
+
+// ResealMergedUser reseals every chain holding entries of targetID. See
+// resealChain.
+func (r *auditRepository) ResealMergedUser(ctx context.Context, sourceID, targetID string) (int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT campaign_id FROM audit_log WHERE user_id = ? AND entry_hash <> ''`, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("listing merged audit chains: %w", err)
+	}
+	var campaignIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning merged audit chain: %w", err)
+		}
+		campaignIDs = append(campaignIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("listing merged audit chains: %w", err)
+	}
+
+	total := 0
+	for _, id := range campaignIDs {
+		n, err := r.resealChain(ctx, id, sourceID, targetID)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// resealChainBatch is how many entries resealChain reads at a time.
+const resealChainBatch = 500
+
+// resealChain walks one chain with its head locked. An entry is accepted if
+// it verifies as stored, or verifies once its user is put back from targetID
+// to sourceID (the merge rewrote it). Accepted entries are relinked and
+// rehashed; the walk stops at the first entry that fails both ways, leaving
+// it and everything after untouched so real tampering stays visible.
+func (r *auditRepository) resealChain(ctx context.Context, campaignID, sourceID, targetID string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("beginning audit reseal: %w", err)
+	}
+	defer tx.Rollback()
+
+	head, err := lockChainHead(ctx, tx, campaignID)
+	if err != nil {
+		return 0, err
+	}
+
+	oldPrev, newPrev := head.anchorHash, head.anchorHash
+	started, intact, rewritten := false, true, 0
+	for afterID := head.anchorID; intact; {
+		batch, err := r.readChainBatch(ctx, tx, campaignID, afterID)
+		if err != nil {
+			return 0, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		afterID = batch[len(batch)-1].ID
+
+		for _, row := range batch {
+			if row.EntryHash == "" && !started {
+				continue
+			}
+			if !r.sealedAsMerged(oldPrev, row, sourceID, targetID) {
+				intact = false
+				break
+			}
+			started = true
+			oldPrev = row.EntryHash
+			hash := entryHash(r.chainKey, newPrev, row)
+			if hash != row.EntryHash || newPrev != row.PrevHash {
+				if _, err := tx.ExecContext(ctx,
+					`UPDATE audit_log SET prev_hash = ?, entry_hash = ? WHERE id = ?`,
+					newPrev, hash, row.ID,
+				); err != nil {
+					return 0, fmt.Errorf("resealing audit entry: %w", err)
+				}
+				rewritten++
+			}
+			newPrev = hash
+		}
+	}
+
+	// Move the head only if the whole chain was accepted; a broken chain
+	// keeps failing where it did.
+	if intact && head.headHash == oldPrev {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE audit_chain_heads SET head_hash = ? WHERE campaign_id = ?`, newPrev, campaignID,
+		); err != nil {
+			return 0, fmt.Errorf("resealing audit chain head: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing audit reseal: %w", err)
+	}
+	return rewritten, nil
+}
+
+// sealedAsMerged reports whether row links to prev and its hash matches
+// either as stored or with its user set back to sourceID.
+func (r *auditRepository) sealedAsMerged(prev string, row *chainRow, sourceID, targetID string) bool {
+	if row.EntryHash == "" || row.PrevHash != prev {
+		return false
+	}
+	if entryHash(r.chainKey, prev, row) == row.EntryHash {
+		return true
+	}
+	if row.UserID != targetID {
+		return false
+	}
+	original := *row
+	original.UserID = sourceID
+	return entryHash(r.chainKey, prev, &original) == row.EntryHash
+}
+
+// readChainBatch reads the next resealChainBatch entries after afterID.
+func (r *auditRepository) readChainBatch(ctx context.Context, tx *sql.Tx, campaignID string, afterID int64) ([]*chainRow, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+chainRowColumns+` FROM audit_log WHERE campaign_id = ? AND id > ? ORDER BY id LIMIT ?`,
+		campaignID, afterID, resealChainBatch)
+	if err != nil {
+		return nil, fmt.Errorf("reading audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	var batch []*chainRow
+	for rows.Next() {
+		row, err := scanChainRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating audit chain: %w", err)
+	}
+	return batch, nil
+}
+
 // GetCampaignStats computes aggregate statistics for a campaign by querying
 // across entities and audit_log tables. The word count is approximated by
 // counting spaces in the HTML content (fast but rough).
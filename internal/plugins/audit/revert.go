@@ -0,0 +1,154 @@
+package audit
+
+// revert.go — undoing an audited edit from the activity feed. An entry is
+// revertible when it is recent, its action is one whose diff describes a
+// whole edit, and none of its changes is Partial. Reverting writes each
+// change's Before value back through the ChangeReverter registered for the
+// entry's EntityType, after checking that every field still holds the
+// entry's After value: anything edited since is left alone and the revert
+// is refused rather than overwriting the newer edit. The revert is logged
+// as its own entry with its own diff.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// revertWindow is how long after an edit the activity feed offers to
+// revert it.
+const revertWindow = 7 * 24 * time.Hour
+
+// revertibleActions are the actions whose diffs can be applied in reverse.
+var revertibleActions = map[string]bool{
+	ActionEntityUpdated:                  true,
+	ActionCalendarEventUpdated:           true,
+	ActionCalendarEventVisibilityChanged: true,
+	ActionChangeReverted:                 true,
+}
+
+// ChangeReverter reads and restores the values of one kind of record,
+// keyed as in that record's audit diffs. Implemented by the plugin that
+// owns the record and registered under the EntityType its entries carry.
+type ChangeReverter interface {
+	// CurrentValues returns the record's name and a snapshot of its current
+	// values. Returns an apperror (NotFound) when the record doesn't exist
+	// or belongs to another campaign.
+	CurrentValues(ctx context.Context, campaignID, recordID string) (string, []SnapshotField, error)
+
+	// RestoreValues sets each field key to its stored value, as one update.
+	RestoreValues(ctx context.Context, campaignID, recordID string, values map[string]any) error
+}
+
+// Revertible reports whether the activity feed may offer to revert the
+// entry at now.
+func (e *AuditEntry) Revertible(now time.Time) bool {
+	if !revertibleActions[e.Action] || e.EntityID == "" || now.Sub(e.CreatedAt) > revertWindow {
+		return false
+	}
+	changes := e.Changes()
+	if len(changes) == 0 {
+		return false
+	}
+	for _, c := range changes {
+		if c.Partial {
+			return false
+		}
+	}
+	return true
+}
+
+// RevertService undoes audited edits.
+type RevertService interface {
+	// Revert restores the values an entry's diff replaced and logs the
+	// revert as a new entry by userID, which it returns.
+	Revert(ctx context.Context, campaignID, userID string, entryID int64) (*AuditEntry, error)
+}
+
+// revertService implements RevertService.
+type revertService struct {
+	repo      AuditRepository
+	reverters map[string]ChangeReverter
+}
+
+// NewRevertService creates a revert service. reverters maps an audit
+// EntityType ("entity", "calendar_event") to the plugin that restores it;
+// entries of other types can't be reverted.
+func NewRevertService(repo AuditRepository, reverters map[string]ChangeReverter) RevertService {
+	return &revertService{repo: repo, reverters: reverters}
+}
+
+// Revert applies the inverse of the entry's diff.
+func (s *revertService) Revert(ctx context.Context, campaignID, userID string, entryID int64) (*AuditEntry, error) {
+	entry, err := s.repo.GetByID(ctx, campaignID, entryID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading audit entry: %w", err))
+	}
+	if entry == nil {
+		return nil, apperror.NewNotFound("activity entry not found")
+	}
+	reverter := s.reverters[entry.EntityType]
+	if reverter == nil || !entry.Revertible(time.Now().UTC()) {
+		return nil, apperror.NewBadRequest("this change can't be reverted")
+	}
+
+	_, current, err := reverter.CurrentValues(ctx, campaignID, entry.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any, len(current))
+	for _, f := range current {
+		values[f.Key] = f.Value
+	}
+	restore := make(map[string]any)
+	for _, c := range entry.Changes() {
+		if !sameValue(values[c.Field], c.After) {
+			return nil, apperror.NewConflict(fmt.Sprintf("%s has changed since; edit it directly instead", c.Label))
+		}
+		restore[c.Field] = c.Before
+	}
+
+	if err := reverter.RestoreValues(ctx, campaignID, entry.EntityID, restore); err != nil {
+		return nil, err
+	}
+
+	name, after, err := reverter.CurrentValues(ctx, campaignID, entry.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	revert := &AuditEntry{
+		CampaignID: campaignID,
+		UserID:     userID,
+		Action:     ActionChangeReverted,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		EntityName: name,
+		Details: WithChanges(map[string]any{
+			"reverted_entry_id": entry.ID,
+			"reverted_action":   entry.Action,
+		}, Diff(current, after)),
+	}
+	// The values are already restored; a lost audit entry shouldn't turn
+	// that into an error.
+	if err := s.repo.Log(ctx, revert); err != nil {
+		slog.Warn("audit log failed", slog.String("action", ActionChangeReverted), slog.Any("error", err))
+	}
+	return revert, nil
+}
+
+// sameValue reports whether a current snapshot value matches a stored one.
+// References match on their ID alone, so renaming a linked record since
+// doesn't count as a conflicting edit.
+func sameValue(current, stored any) bool {
+	cur, _ := normalizeValue(current)
+	if c, ok := cur.(map[string]any); ok && c["id"] != nil {
+		if s, ok := stored.(map[string]any); ok {
+			return c["id"] == s["id"]
+		}
+	}
+	return reflect.DeepEqual(cur, stored)
+}
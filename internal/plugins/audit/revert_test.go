@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeReverter holds one record's values in memory.
+type fakeReverter struct {
+	name     string
+	values   map[string]any
+	restored map[string]any
+}
+
+func (f *fakeReverter) CurrentValues(context.Context, string, string) (string, []SnapshotField, error) {
+	var snap []SnapshotField
+	for _, k := range []string{"name", "parent"} {
+		snap = append(snap, SnapshotField{Key: k, Label: k, Value: f.values[k]})
+	}
+	return f.name, snap, nil
+}
+
+func (f *fakeReverter) RestoreValues(_ context.Context, _, _ string, values map[string]any) error {
+	f.restored = values
+	for k, v := range values {
+		f.values[k] = v
+	}
+	if name, ok := f.values["name"].(string); ok {
+		f.name = name
+	}
+	return nil
+}
+
+func renameEntry(created time.Time) *AuditEntry {
+	return &AuditEntry{
+		ID: 7, CampaignID: "c1", Action: ActionEntityUpdated, EntityType: "entity", EntityID: "e1",
+		CreatedAt: created,
+		Details: WithChanges(nil, []Change{
+			{Field: "name", Label: "Name", Before: "Tyne", After: "Tyne the Bold"},
+			{Field: "parent", Label: "Parent", Before: nil, After: map[string]any{"id": "p1", "name": "Old name"}},
+		}),
+	}
+}
+
+func TestRevertible(t *testing.T) {
+	now := time.Now()
+	if !renameEntry(now).Revertible(now) {
+		t.Error("recent rename should be revertible")
+	}
+	if renameEntry(now.Add(-revertWindow - time.Hour)).Revertible(now) {
+		t.Error("old entry should not be revertible")
+	}
+	partial := renameEntry(now)
+	partial.Details = WithChanges(nil, []Change{{Field: "entry", Partial: true}})
+	if partial.Revertible(now) {
+		t.Error("entry with a partial change should not be revertible")
+	}
+	created := renameEntry(now)
+	created.Action = ActionEntityCreated
+	if created.Revertible(now) {
+		t.Error("entity.created should not be revertible")
+	}
+}
+
+func TestRevert(t *testing.T) {
+	newService := func(entry *AuditEntry, rec *fakeReverter, logged *[]*AuditEntry) RevertService {
+		repo := &mockAuditRepo{
+			getByIDFn: func(_ context.Context, campaignID string, id int64) (*AuditEntry, error) {
+				if campaignID != entry.CampaignID || id != entry.ID {
+					return nil, nil
+				}
+				return entry, nil
+			},
+			logFn: func(_ context.Context, e *AuditEntry) error {
+				e.CreatedAt = time.Now() // As the repository stamps it.
+				*logged = append(*logged, e)
+				return nil
+			},
+		}
+		return NewRevertService(repo, map[string]ChangeReverter{"entity": rec})
+	}
+
+	t.Run("restores before values and logs the revert", func(t *testing.T) {
+		// The parent was renamed since; it is still the same parent.
+		rec := &fakeReverter{name: "Tyne the Bold", values: map[string]any{
+			"name": "Tyne the Bold", "parent": map[string]any{"id": "p1", "name": "New name"},
+		}}
+		var logged []*AuditEntry
+		svc := newService(renameEntry(time.Now()), rec, &logged)
+
+		revert, err := svc.Revert(context.Background(), "c1", "u1", 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.restored["name"] != "Tyne" || rec.restored["parent"] != nil {
+			t.Errorf("restored = %+v", rec.restored)
+		}
+		if len(logged) != 1 || logged[0] != revert {
+			t.Fatalf("logged = %+v", logged)
+		}
+		if revert.Action != ActionChangeReverted || revert.UserID != "u1" || revert.EntityName != "Tyne" {
+			t.Errorf("revert entry = %+v", revert)
+		}
+		if revert.Details["reverted_entry_id"] != int64(7) {
+			t.Errorf("details = %+v", revert.Details)
+		}
+		if c := revert.Changes(); len(c) != 2 || c[0].Before != "Tyne the Bold" || c[0].After != "Tyne" {
+			t.Errorf("revert changes = %+v", c)
+		}
+		if !revert.Revertible(time.Now()) {
+			t.Error("a revert should itself be revertible")
+		}
+	})
+
+	t.Run("refuses when a field changed since", func(t *testing.T) {
+		rec := &fakeReverter{values: map[string]any{
+			"name": "Tyne the Great", "parent": map[string]any{"id": "p1"},
+		}}
+		var logged []*AuditEntry
+		_, err := newService(renameEntry(time.Now()), rec, &logged).Revert(context.Background(), "c1", "u1", 7)
+		assertAppError(t, err, http.StatusConflict)
+		if rec.restored != nil || len(logged) != 0 {
+			t.Error("conflicting revert should change nothing")
+		}
+	})
+
+	t.Run("other campaign's entry is not found", func(t *testing.T) {
+		var logged []*AuditEntry
+		_, err := newService(renameEntry(time.Now()), &fakeReverter{}, &logged).Revert(context.Background(), "c2", "u1", 7)
+		assertAppError(t, err, http.StatusNotFound)
+	})
+
+	t.Run("unregistered type is refused", func(t *testing.T) {
+		entry := renameEntry(time.Now())
+		entry.EntityType = "note"
+		var logged []*AuditEntry
+		_, err := newService(entry, &fakeReverter{}, &logged).Revert(context.Background(), "c1", "u1", 7)
+		assertAppError(t, err, http.StatusBadRequest)
+	})
+}
@@ -26,6 +26,15 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	// Activity embed -- owner only (used by dashboard activity feed block).
 	cg.GET("/activity/embed", h.EmbedActivity, campaigns.RequireRole(campaigns.RoleOwner))
 
+	// Revert an audited edit from the activity feed -- owner only.
+	cg.POST("/activity/:entryId/revert", h.RevertEntry, campaigns.RequireRole(campaigns.RoleOwner))
+
 	// Entity history -- any campaign member can view change history.
 	cg.GET("/entities/:eid/history", h.EntityHistory, campaigns.RequireRole(campaigns.RolePlayer))
 }
+
+// RegisterAdminRoutes adds audit chain verification to the admin group,
+// which requires site admin privileges.
+func RegisterAdminRoutes(adminGroup *echo.Group, h *Handler) {
+	adminGroup.GET("/audit/verify", h.AdminVerifyChain)
+}
@@ -16,8 +16,11 @@ type mockAuditRepo struct {
 	logFn              func(ctx context.Context, entry *AuditEntry) error
 	listByCampaignFn   func(ctx context.Context, campaignID string, limit, offset int) ([]AuditEntry, int, error)
 	listByEntityFn     func(ctx context.Context, entityID, campaignID string, limit int) ([]AuditEntry, error)
+	getByIDFn          func(ctx context.Context, campaignID string, id int64) (*AuditEntry, error)
 	countByCampaignFn  func(ctx context.Context, campaignID string) (int, error)
 	getCampaignStatsFn func(ctx context.Context, campaignID string) (*CampaignStats, error)
+	listChainsFn       func(ctx context.Context) ([]string, error)
+	verifyChainFn      func(ctx context.Context, campaignID string) (*ChainVerification, error)
 }
 
 func (m *mockAuditRepo) Log(ctx context.Context, entry *AuditEntry) error {
@@ -41,6 +44,17 @@ func (m *mockAuditRepo) ListByEntity(ctx context.Context, entityID, campaignID s
 	return nil, nil
 }
 
+func (m *mockAuditRepo) GetByID(ctx context.Context, campaignID string, id int64) (*AuditEntry, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, campaignID, id)
+	}
+	return nil, nil
+}
+
+func (m *mockAuditRepo) ListEntityChangesSince(ctx context.Context, campaignID string, since time.Time, limit int) ([]AuditEntry, error) {
+	return nil, nil
+}
+
 func (m *mockAuditRepo) CountByCampaign(ctx context.Context, campaignID string) (int, error) {
 	if m.countByCampaignFn != nil {
 		return m.countByCampaignFn(ctx, campaignID)
@@ -55,6 +69,28 @@ func (m *mockAuditRepo) GetCampaignStats(ctx context.Context, campaignID string)
 	return &CampaignStats{}, nil
 }
 
+func (m *mockAuditRepo) PurgeBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAuditRepo) ListChainCampaigns(ctx context.Context) ([]string, error) {
+	if m.listChainsFn != nil {
+		return m.listChainsFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockAuditRepo) VerifyChain(ctx context.Context, campaignID string) (*ChainVerification, error) {
+	if m.verifyChainFn != nil {
+		return m.verifyChainFn(ctx, campaignID)
+	}
+	return &ChainVerification{CampaignID: campaignID, Valid: true}, nil
+}
+
+func (m *mockAuditRepo) ResealMergedUser(ctx context.Context, sourceID, targetID string) (int, error) {
+	return 0, nil
+}
+
 // --- Test Helpers ---
 
 func newTestAuditService(repo *mockAuditRepo) *auditService {
@@ -0,0 +1,317 @@
+package auth
+
+// captcha.go — optional human check on the public auth forms: signup,
+// password reset, and login once an email has racked up failed attempts.
+// Providers are hCaptcha and Cloudflare Turnstile (both verified server-side
+// against their siteverify APIs) or a built-in proof-of-work for instances
+// that don't want a third party. The provider and keys are runtime settings,
+// read through a func so admin changes apply without a restart.
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+)
+
+// CAPTCHA providers. These mirror the settings plugin's constants; kept local
+// so auth does not import that plugin (T-B2 plugin isolation).
+const (
+	captchaHCaptcha  = "hcaptcha"
+	captchaTurnstile = "turnstile"
+	captchaPoW       = "pow"
+)
+
+// Proof-of-work parameters. 18 leading zero bits is ~262k SHA-256 hashes on
+// average: around a second in a browser, but enough to make scripted
+// signups and password reset floods cost real CPU.
+const (
+	powDifficulty = 18
+	powTTL        = 10 * time.Minute
+	powUsedPrefix = "captcha:pow:used:"
+)
+
+// captchaVerifyTimeout bounds the provider siteverify call.
+const captchaVerifyTimeout = 10 * time.Second
+
+// captchaProviders describes the third-party providers: where to verify a
+// token, which form field the widget posts it in, and which origins the page
+// CSP has to allow for the widget script and iframe.
+var captchaProviders = map[string]struct {
+	verifyURL string
+	field     string
+	origins   []string
+}{
+	captchaHCaptcha: {
+		verifyURL: "https://api.hcaptcha.com/siteverify",
+		field:     "h-captcha-response",
+		origins:   []string{"https://hcaptcha.com", "https://*.hcaptcha.com"},
+	},
+	captchaTurnstile: {
+		verifyURL: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+		field:     "cf-turnstile-response",
+		origins:   []string{"https://challenges.cloudflare.com"},
+	},
+}
+
+// CaptchaConfig is the live CAPTCHA configuration. An empty Provider turns
+// the challenge off.
+type CaptchaConfig struct {
+	Provider  string
+	SiteKey   string
+	SecretKey string
+	// LoginAfterFailures is how many recent failed logins for an email make
+	// the next attempt require the challenge.
+	LoginAfterFailures int
+}
+
+// CaptchaWidget is what a form needs to render the challenge.
+type CaptchaWidget struct {
+	Provider string
+	SiteKey  string
+	// Challenge and Difficulty are set for proof-of-work.
+	Challenge  string
+	Difficulty int
+}
+
+// Captcha issues and verifies challenges for the auth forms.
+type Captcha struct {
+	config func() CaptchaConfig
+	powKey []byte
+	redis  *redis.Client
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewCaptcha creates a CAPTCHA verifier. config is read on every request.
+// secretKey (SECRET_KEY) keys the proof-of-work challenge signatures; rdb
+// makes each solved challenge single-use and may be nil.
+func NewCaptcha(config func() CaptchaConfig, secretKey string, rdb *redis.Client) *Captcha {
+	// Derive a dedicated key so a challenge MAC can never double as any other
+	// SECRET_KEY-based value.
+	powKey := sha256.Sum256([]byte("chronicle-captcha-pow:" + secretKey))
+	return &Captcha{
+		config: config,
+		powKey: powKey[:],
+		redis:  rdb,
+		client: &http.Client{Timeout: captchaVerifyTimeout},
+		now:    time.Now,
+	}
+}
+
+// current returns the config, treating an unknown or half-configured
+// provider as off so a bad setting can't lock everyone out of signup.
+func (c *Captcha) current() CaptchaConfig {
+	if c == nil || c.config == nil {
+		return CaptchaConfig{}
+	}
+	cfg := c.config()
+	switch cfg.Provider {
+	case captchaPoW:
+	case captchaHCaptcha, captchaTurnstile:
+		if cfg.SiteKey == "" || cfg.SecretKey == "" {
+			cfg.Provider = ""
+		}
+	default:
+		cfg.Provider = ""
+	}
+	return cfg
+}
+
+// Enabled reports whether a challenge is configured.
+func (c *Captcha) Enabled() bool {
+	return c.current().Provider != ""
+}
+
+// LoginThreshold returns the failure count at which login needs a challenge,
+// or 0 when the challenge is off.
+func (c *Captcha) LoginThreshold() int {
+	cfg := c.current()
+	if cfg.Provider == "" {
+		return 0
+	}
+	return max(cfg.LoginAfterFailures, 1)
+}
+
+// Widget returns the challenge to render, or nil when off. A proof-of-work
+// widget carries a freshly signed challenge.
+func (c *Captcha) Widget() *CaptchaWidget {
+	cfg := c.current()
+	switch cfg.Provider {
+	case "":
+		return nil
+	case captchaPoW:
+		challenge, err := c.issuePoW()
+		if err != nil {
+			slog.Warn("captcha: issuing proof-of-work challenge failed", slog.Any("error", err))
+			return nil
+		}
+		return &CaptchaWidget{Provider: captchaPoW, Challenge: challenge, Difficulty: powDifficulty}
+	default:
+		return &CaptchaWidget{Provider: cfg.Provider, SiteKey: cfg.SiteKey}
+	}
+}
+
+// AllowWidgetOrigins widens the response CSP for the configured third-party
+// provider so its script and iframe load. Applied to every auth form page,
+// since an HTMX partial that adds the widget inherits the page's policy.
+func (c *Captcha) AllowWidgetOrigins(ctx echo.Context) {
+	if p, ok := captchaProviders[c.current().Provider]; ok {
+		middleware.ExtendCSP(ctx, p.origins...)
+	}
+}
+
+// Verify checks the challenge answer submitted with the form. Returns nil
+// when the challenge is off. Fails closed when the provider can't be
+// reached: the forms it guards are exactly what a flood would target.
+func (c *Captcha) Verify(ctx context.Context, form url.Values, remoteIP string) error {
+	cfg := c.current()
+	switch cfg.Provider {
+	case "":
+		return nil
+	case captchaPoW:
+		return c.verifyPoW(ctx, form.Get("pow_challenge"), form.Get("pow_nonce"))
+	}
+
+	p := captchaProviders[cfg.Provider]
+	token := form.Get(p.field)
+	if token == "" {
+		return apperror.NewBadRequest("please complete the verification challenge")
+	}
+	ok, err := c.siteVerify(ctx, p.verifyURL, cfg.SecretKey, token, remoteIP)
+	if err != nil {
+		slog.Warn("captcha: provider verification failed",
+			slog.String("provider", cfg.Provider), slog.Any("error", err))
+		return apperror.NewBadRequest("verification is unavailable right now — please try again shortly")
+	}
+	if !ok {
+		return apperror.NewBadRequest("verification failed — please try the challenge again")
+	}
+	return nil
+}
+
+// siteVerify posts a widget token to the provider. hCaptcha and Turnstile
+// share the request and response shape.
+func (c *Captcha) siteVerify(ctx context.Context, endpoint, secret, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("siteverify returned %d", resp.StatusCode)
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("decoding siteverify response: %w", err)
+	}
+	return result.Success, nil
+}
+
+// issuePoW signs a challenge: "<issued unix>.<difficulty>.<random>.<mac>".
+// The client finds a nonce such that SHA-256(challenge + ":" + nonce) starts
+// with difficulty zero bits.
+func (c *Captcha) issuePoW() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	payload := fmt.Sprintf("%d.%d.%s", c.now().Unix(), powDifficulty, hex.EncodeToString(buf))
+	return payload + "." + c.powMAC(payload), nil
+}
+
+// powMAC signs a challenge payload.
+func (c *Captcha) powMAC(payload string) string {
+	mac := hmac.New(sha256.New, c.powKey)
+	mac.Write([]byte("captcha-pow:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyPoW checks a solved challenge: our signature, not expired, enough
+// work, and not used before.
+func (c *Captcha) verifyPoW(ctx context.Context, challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return apperror.NewBadRequest("please wait for the verification check to finish")
+	}
+	invalid := apperror.NewBadRequest("verification failed — please reload the page and try again")
+
+	cut := strings.LastIndex(challenge, ".")
+	if cut < 0 {
+		return invalid
+	}
+	payload, mac := challenge[:cut], challenge[cut+1:]
+	if !hmac.Equal([]byte(mac), []byte(c.powMAC(payload))) {
+		return invalid
+	}
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return invalid
+	}
+	issued, err1 := strconv.ParseInt(parts[0], 10, 64)
+	difficulty, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || difficulty < powDifficulty {
+		return invalid
+	}
+	age := c.now().Sub(time.Unix(issued, 0))
+	if age < -time.Minute || age > powTTL {
+		return apperror.NewBadRequest("the verification check expired — please try again")
+	}
+	if len(nonce) > 20 || !powSolves(challenge, nonce, difficulty) {
+		return invalid
+	}
+
+	// Single use: a solved challenge must not be replayed for more requests.
+	// Without Redis the expiry window is the only bound.
+	if c.redis != nil {
+		ok, err := c.redis.SetNX(ctx, powUsedPrefix+mac, 1, powTTL).Result()
+		if err != nil {
+			slog.Warn("captcha: recording proof-of-work use failed", slog.Any("error", err))
+		} else if !ok {
+			return invalid
+		}
+	}
+	return nil
+}
+
+// powSolves reports whether SHA-256(challenge + ":" + nonce) starts with at
+// least difficulty zero bits.
+func powSolves(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+	return zeros >= difficulty
+}
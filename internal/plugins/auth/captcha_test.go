@@ -0,0 +1,182 @@
+// captcha_test.go — the optional human check on the auth forms: the built-in
+// proof-of-work (signing, expiry, replay) and provider token verification.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// solvePoW brute-forces a nonce for a challenge, like captcha.js does.
+func solvePoW(t *testing.T, challenge string) string {
+	t.Helper()
+	for n := 0; n < 1<<26; n++ {
+		nonce := strconv.Itoa(n)
+		if powSolves(challenge, nonce, powDifficulty) {
+			return nonce
+		}
+	}
+	t.Fatal("no nonce found")
+	return ""
+}
+
+func newPoWCaptcha(t *testing.T) *Captcha {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewCaptcha(func() CaptchaConfig { return CaptchaConfig{Provider: captchaPoW} }, "test-secret", rdb)
+}
+
+func powForm(challenge, nonce string) url.Values {
+	return url.Values{"pow_challenge": {challenge}, "pow_nonce": {nonce}}
+}
+
+func TestCaptcha_ProofOfWork(t *testing.T) {
+	c := newPoWCaptcha(t)
+	ctx := context.Background()
+
+	w := c.Widget()
+	if w == nil || w.Provider != captchaPoW || w.Challenge == "" {
+		t.Fatalf("Widget() = %+v, want a proof-of-work challenge", w)
+	}
+	nonce := solvePoW(t, w.Challenge)
+
+	// Tampering with any part of the challenge breaks the signature.
+	parts := strings.Split(w.Challenge, ".")
+	easier := strings.Join([]string{parts[0], "1", parts[2], parts[3]}, ".")
+	if err := c.Verify(ctx, powForm(easier, nonce), ""); err == nil {
+		t.Error("a challenge with a lowered difficulty was accepted")
+	}
+	if err := c.Verify(ctx, powForm(w.Challenge, ""), ""); err == nil {
+		t.Error("a missing nonce was accepted")
+	}
+
+	if err := c.Verify(ctx, powForm(w.Challenge, nonce), ""); err != nil {
+		t.Fatalf("solved challenge rejected: %v", err)
+	}
+	// Single use.
+	if err := c.Verify(ctx, powForm(w.Challenge, nonce), ""); err == nil {
+		t.Error("a solved challenge was accepted twice")
+	}
+
+	// Another instance key can't mint challenges for this one.
+	other := NewCaptcha(func() CaptchaConfig { return CaptchaConfig{Provider: captchaPoW} }, "other-secret", nil)
+	foreign := other.Widget().Challenge
+	if err := c.Verify(ctx, powForm(foreign, solvePoW(t, foreign)), ""); err == nil {
+		t.Error("a challenge signed with another key was accepted")
+	}
+}
+
+func TestCaptcha_ProofOfWorkExpiry(t *testing.T) {
+	c := newPoWCaptcha(t)
+	issued := time.Now()
+	c.now = func() time.Time { return issued }
+	challenge := c.Widget().Challenge
+	nonce := solvePoW(t, challenge)
+
+	c.now = func() time.Time { return issued.Add(powTTL + time.Minute) }
+	err := c.Verify(context.Background(), powForm(challenge, nonce), "")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != http.StatusBadRequest {
+		t.Fatalf("expired challenge: err = %v, want 400", err)
+	}
+}
+
+// rewriteTransport sends every request to a test server, standing in for
+// the provider's siteverify host.
+type rewriteTransport struct{ target *url.URL }
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCaptcha_ProviderVerify(t *testing.T) {
+	var gotSecret, gotIP string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotSecret, gotIP = r.PostForm.Get("secret"), r.PostForm.Get("remoteip")
+		switch r.PostForm.Get("response") {
+		case "good":
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case "down":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"success":false}`))
+		}
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	c := NewCaptcha(func() CaptchaConfig {
+		return CaptchaConfig{Provider: captchaTurnstile, SiteKey: "site", SecretKey: "shh"}
+	}, "test-secret", nil)
+	c.client = &http.Client{Transport: rewriteTransport{target: target}}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid token", token: "good"},
+		{name: "rejected token", token: "bad", wantErr: true},
+		{name: "missing token", token: "", wantErr: true},
+		{name: "provider unavailable fails closed", token: "down", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{}
+			if tt.token != "" {
+				form.Set("cf-turnstile-response", tt.token)
+			}
+			err := c.Verify(context.Background(), form, "203.0.113.7")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if gotSecret != "shh" || gotIP != "203.0.113.7" {
+		t.Errorf("siteverify got secret=%q remoteip=%q", gotSecret, gotIP)
+	}
+}
+
+func TestCaptcha_Disabled(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  CaptchaConfig
+	}{
+		{name: "off", cfg: CaptchaConfig{}},
+		{name: "unknown provider", cfg: CaptchaConfig{Provider: "recaptcha"}},
+		{name: "hcaptcha without secret", cfg: CaptchaConfig{Provider: captchaHCaptcha, SiteKey: "site"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCaptcha(func() CaptchaConfig { return tt.cfg }, "k", nil)
+			if c.Enabled() || c.Widget() != nil || c.LoginThreshold() != 0 {
+				t.Error("challenge should be off")
+			}
+			if err := c.Verify(context.Background(), url.Values{}, ""); err != nil {
+				t.Errorf("Verify() = %v, want nil when off", err)
+			}
+		})
+	}
+
+	// A nil *Captcha (not wired) behaves the same.
+	var c *Captcha
+	if c.Enabled() || c.Verify(context.Background(), url.Values{}, "") != nil {
+		t.Error("nil Captcha should be a no-op")
+	}
+}
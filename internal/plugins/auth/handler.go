@@ -71,6 +71,7 @@ type SecurityEventLogger interface {
 type Handler struct {
 	service        AuthService
 	securityLogger SecurityEventLogger
+	captcha        *Captcha      // Optional human check; nil-safe.
 	sessionTTL     time.Duration // Cookie MaxAge matches Redis session TTL.
 }
 
@@ -84,6 +85,31 @@ func (h *Handler) SetSecurityLogger(logger SecurityEventLogger) {
 	h.securityLogger = logger
 }
 
+// SetCaptcha wires the optional CAPTCHA / proof-of-work check for signup,
+// password reset, and repeated failed logins.
+func (h *Handler) SetCaptcha(captcha *Captcha) {
+	h.captcha = captcha
+}
+
+// verifyCaptcha checks the challenge answer posted with the current form.
+func (h *Handler) verifyCaptcha(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	return h.captcha.Verify(c.Request().Context(), form, c.RealIP())
+}
+
+// loginCaptcha returns the challenge to show on the login form for email,
+// or nil while it hasn't failed enough attempts to need one.
+func (h *Handler) loginCaptcha(ctx context.Context, email string) *CaptchaWidget {
+	threshold := h.captcha.LoginThreshold()
+	if threshold == 0 || email == "" || h.service.LoginFailureCount(ctx, email) < threshold {
+		return nil
+	}
+	return h.captcha.Widget()
+}
+
 // LoginForm renders the login page (GET /login).
 func (h *Handler) LoginForm(c echo.Context) error {
 	// If the user already has a valid session, redirect to dashboard.
@@ -109,7 +135,8 @@ func (h *Handler) LoginForm(c echo.Context) error {
 		errMsg = middleware.CSRFFriendlyMessage
 	}
 
-	return middleware.Render(c, http.StatusOK, LoginPage(csrfToken, "", errMsg, successMsg))
+	h.captcha.AllowWidgetOrigins(c)
+	return middleware.Render(c, http.StatusOK, LoginPage(csrfToken, "", errMsg, successMsg, nil))
 }
 
 // Login processes the login form submission (POST /login).
@@ -129,6 +156,21 @@ func (h *Handler) Login(c echo.Context) error {
 		UserAgent: ua,
 	}
 
+	// Past the failure threshold the challenge gates the password check, so a
+	// credential-stuffing script can't keep guessing at full speed.
+	h.captcha.AllowWidgetOrigins(c)
+	if h.loginCaptcha(c.Request().Context(), req.Email) != nil {
+		if err := h.verifyCaptcha(c); err != nil {
+			csrfToken := middleware.GetCSRFToken(c)
+			errMsg := apperror.UserMessage(err, "verification failed")
+			widget := h.captcha.Widget()
+			if middleware.IsHTMX(c) {
+				return middleware.Render(c, http.StatusOK, LoginForm_(csrfToken, req.Email, errMsg, widget))
+			}
+			return middleware.Render(c, http.StatusOK, LoginPage(csrfToken, req.Email, errMsg, "", widget))
+		}
+	}
+
 	token, user, err := h.service.Login(c.Request().Context(), input)
 	if err != nil {
 		// Log failed login attempt as a security event.
@@ -138,10 +180,12 @@ func (h *Handler) Login(c echo.Context) error {
 		csrfToken := middleware.GetCSRFToken(c)
 		errMsg := apperror.UserMessage(err, "invalid email or password")
 
+		widget := h.loginCaptcha(c.Request().Context(), req.Email)
+
 		if middleware.IsHTMX(c) {
-			return middleware.Render(c, http.StatusOK, LoginForm_(csrfToken, req.Email, errMsg))
+			return middleware.Render(c, http.StatusOK, LoginForm_(csrfToken, req.Email, errMsg, widget))
 		}
-		return middleware.Render(c, http.StatusOK, LoginPage(csrfToken, req.Email, errMsg, ""))
+		return middleware.Render(c, http.StatusOK, LoginPage(csrfToken, req.Email, errMsg, "", widget))
 	}
 
 	// Log successful login as a security event.
@@ -181,7 +225,8 @@ func (h *Handler) RegisterForm(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, nil, "", redirect, !allowed, mode))
+	h.captcha.AllowWidgetOrigins(c)
+	return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, nil, "", redirect, !allowed, mode, h.captcha.Widget()))
 }
 
 // Register processes the registration form submission (POST /register).
@@ -195,14 +240,23 @@ func (h *Handler) Register(c echo.Context) error {
 	// token it embeds, which the service uses to satisfy invite-only mode.
 	redirect := sanitizeRedirect(c.FormValue("redirect"))
 	inviteToken := extractInviteToken(redirect)
-
-	// Basic server-side validation.
-	if validationErr := validateRegisterRequest(&req); validationErr != "" {
+	h.captcha.AllowWidgetOrigins(c)
+
+	// Basic server-side validation, then the human check. Both re-render the
+	// form, with a fresh challenge since a solved one is single-use.
+	validationErr := validateRegisterRequest(&req)
+	if validationErr == "" {
+		if err := h.verifyCaptcha(c); err != nil {
+			validationErr = apperror.UserMessage(err, "verification failed")
+		}
+	}
+	if validationErr != "" {
 		csrfToken := middleware.GetCSRFToken(c)
+		mode, _, _ := h.service.RegistrationStatus(c.Request().Context(), inviteToken)
 		if middleware.IsHTMX(c) {
-			return middleware.Render(c, http.StatusOK, RegisterFormComponent(csrfToken, &req, validationErr, redirect))
+			return middleware.Render(c, http.StatusOK, RegisterFormComponent(csrfToken, &req, validationErr, redirect, mode == "invite", h.captcha.Widget()))
 		}
-		return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, validationErr, redirect, false, ""))
+		return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, validationErr, redirect, false, mode, h.captcha.Widget()))
 	}
 
 	input := RegisterInput{
@@ -210,6 +264,7 @@ func (h *Handler) Register(c echo.Context) error {
 		DisplayName: req.DisplayName,
 		Password:    req.Password,
 		InviteToken: inviteToken,
+		InviteCode:  req.InviteCode,
 	}
 
 	_, err := h.service.Register(c.Request().Context(), input)
@@ -224,14 +279,15 @@ func (h *Handler) Register(c echo.Context) error {
 			if middleware.IsHTMX(c) {
 				return middleware.Render(c, http.StatusOK, registrationGatedPanel(mode, redirect))
 			}
-			return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, "", redirect, true, mode))
+			return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, "", redirect, true, mode, nil))
 		}
 
 		errMsg := apperror.UserMessage(err, "registration failed")
+		mode, _, _ := h.service.RegistrationStatus(c.Request().Context(), inviteToken)
 		if middleware.IsHTMX(c) {
-			return middleware.Render(c, http.StatusOK, RegisterFormComponent(csrfToken, &req, errMsg, redirect))
+			return middleware.Render(c, http.StatusOK, RegisterFormComponent(csrfToken, &req, errMsg, redirect, mode == "invite", h.captcha.Widget()))
 		}
-		return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, errMsg, redirect, false, ""))
+		return middleware.Render(c, http.StatusOK, RegisterPage(csrfToken, &req, errMsg, redirect, false, mode, h.captcha.Widget()))
 	}
 
 	// Auto-login after successful registration.
@@ -285,16 +341,27 @@ func (h *Handler) Logout(c echo.Context) error {
 // ForgotPasswordForm renders the forgot password page (GET /forgot-password).
 func (h *Handler) ForgotPasswordForm(c echo.Context) error {
 	csrfToken := middleware.GetCSRFToken(c)
-	return middleware.Render(c, http.StatusOK, ForgotPasswordPage(csrfToken, "", ""))
+	h.captcha.AllowWidgetOrigins(c)
+	return middleware.Render(c, http.StatusOK, ForgotPasswordPage(csrfToken, "", "", h.captcha.Widget()))
 }
 
 // ForgotPassword processes the forgot password form (POST /forgot-password).
 // Always shows a success message to avoid leaking whether the email exists.
 func (h *Handler) ForgotPassword(c echo.Context) error {
 	email := c.FormValue("email")
+	h.captcha.AllowWidgetOrigins(c)
+	errMsg := ""
 	if email == "" {
+		errMsg = "email is required"
+	} else if err := h.verifyCaptcha(c); err != nil {
+		errMsg = apperror.UserMessage(err, "verification failed")
+	}
+	if errMsg != "" {
 		csrfToken := middleware.GetCSRFToken(c)
-		return middleware.Render(c, http.StatusOK, ForgotPasswordPage(csrfToken, "", "email is required"))
+		if middleware.IsHTMX(c) {
+			return middleware.Render(c, http.StatusOK, ForgotPasswordForm_(csrfToken, email, errMsg, h.captcha.Widget()))
+		}
+		return middleware.Render(c, http.StatusOK, ForgotPasswordPage(csrfToken, email, errMsg, h.captcha.Widget()))
 	}
 
 	// Initiate reset (fire-and-forget — always returns nil to avoid leaking info).
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 )
 
 // Context keys for storing session data in Echo context. Other plugins
@@ -36,6 +38,7 @@ func RequireAuth(service AuthService) echo.MiddlewareFunc {
 			// Store session data in context for downstream handlers.
 			c.Set(contextKeySession, session)
 			c.Set(contextKeyUserID, session.UserID)
+			middleware.SetLogUser(c, session.UserID)
 
 			return next(c)
 		}
@@ -82,6 +85,7 @@ func OptionalAuth(service AuthService) echo.MiddlewareFunc {
 				if err == nil {
 					c.Set(contextKeySession, session)
 					c.Set(contextKeyUserID, session.UserID)
+					middleware.SetLogUser(c, session.UserID)
 				} else {
 					clearSessionCookie(c)
 				}
@@ -133,6 +137,7 @@ func GetSessionTokenFromCookie(c echo.Context) string {
 func SetSession(c echo.Context, session *Session) {
 	c.Set(contextKeySession, session)
 	c.Set(contextKeyUserID, session.UserID)
+	middleware.SetLogUser(c, session.UserID)
 }
 
 // RequireSiteAdmin returns middleware that ensures the user has the site-wide
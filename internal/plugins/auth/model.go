@@ -35,6 +35,9 @@ type RegisterRequest struct {
 	DisplayName string `json:"display_name" form:"display_name" validate:"required,min=2,max=100"`
 	Password    string `json:"password" form:"password" validate:"required,min=8,max=128"`
 	Confirm     string `json:"confirm" form:"confirm" validate:"required,eqfield=Password"`
+	// InviteCode is an admin-issued registration code, asked for only while
+	// registration is invite-only.
+	InviteCode string `json:"invite_code" form:"invite_code"`
 }
 
 // LoginRequest holds the data submitted by the login form.
@@ -51,9 +54,12 @@ type RegisterInput struct {
 	DisplayName string
 	Password    string
 	// InviteToken carries a campaign invite token when the registration came
-	// through the invite-accept flow. In invite-only mode it is the only way a
-	// non-first user may register; ignored in open mode.
+	// through the invite-accept flow. In invite-only mode it admits the invited
+	// email without an invite code; ignored in open mode.
 	InviteToken string
+	// InviteCode is an admin-issued registration invite code, the alternative
+	// to InviteToken in invite-only mode.
+	InviteCode string
 }
 
 // LoginInput is the validated input for authenticating a user.
@@ -231,3 +231,106 @@ func TestRegistrationStatus(t *testing.T) {
 		})
 	}
 }
+
+// fakeDomainPolicy returns a fixed allowlist (or an error).
+type fakeDomainPolicy struct {
+	domains []string
+	err     error
+}
+
+func (f fakeDomainPolicy) GetAllowedEmailDomains(context.Context) ([]string, error) {
+	return f.domains, f.err
+}
+
+// fakeCodeRedeemer accepts one code with a fixed number of uses.
+type fakeCodeRedeemer struct {
+	code string
+	uses int
+}
+
+func (f *fakeCodeRedeemer) RedeemInviteCode(_ context.Context, code string) (bool, error) {
+	if !strings.EqualFold(code, f.code) || f.uses == 0 {
+		return false, nil
+	}
+	f.uses--
+	return true, nil
+}
+
+// TestRegister_RegistrationRestrictions covers the domain allowlist and admin
+// invite codes: bad codes and disallowed domains are form errors (400), a
+// campaign invite never burns a code, and a failed allowlist read fails closed.
+func TestRegister_RegistrationRestrictions(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		domains     fakeDomainPolicy
+		email       string
+		inviteToken string
+		inviteCode  string
+		wantStatus  int // 0 = registered
+		wantUses    int // remaining code uses afterwards
+	}{
+		{name: "open, no allowlist", mode: "open", email: "a@any.net", wantUses: 1},
+		{name: "open, allowed domain", mode: "open", domains: fakeDomainPolicy{domains: []string{"guild.org"}}, email: "A@Guild.org", wantUses: 1},
+		{name: "open, other domain", mode: "open", domains: fakeDomainPolicy{domains: []string{"guild.org"}}, email: "a@evil.com", wantStatus: http.StatusBadRequest, wantUses: 1},
+		{name: "open, subdomain not implied", mode: "open", domains: fakeDomainPolicy{domains: []string{"guild.org"}}, email: "a@mail.guild.org", wantStatus: http.StatusBadRequest, wantUses: 1},
+		{name: "allowlist read fails closed", mode: "open", domains: fakeDomainPolicy{err: errors.New("db down")}, email: "a@guild.org", wantStatus: http.StatusForbidden, wantUses: 1},
+		{name: "invite, valid code", mode: "invite", email: "a@x.com", inviteCode: "join-us", wantUses: 0},
+		{name: "invite, missing code", mode: "invite", email: "a@x.com", wantStatus: http.StatusBadRequest, wantUses: 1},
+		{name: "invite, wrong code", mode: "invite", email: "a@x.com", inviteCode: "nope", wantStatus: http.StatusBadRequest, wantUses: 1},
+		{name: "invite, campaign invite keeps the code", mode: "invite", email: "a@x.com", inviteToken: "good", inviteCode: "JOIN-US", wantUses: 1},
+		{name: "invite, valid code but other domain", mode: "invite", domains: fakeDomainPolicy{domains: []string{"guild.org"}}, email: "a@x.com", inviteCode: "JOIN-US", wantStatus: http.StatusBadRequest, wantUses: 1},
+		{name: "closed ignores codes", mode: "closed", email: "a@x.com", inviteCode: "JOIN-US", wantStatus: http.StatusForbidden, wantUses: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *User
+			repo := &mockUserRepo{
+				countUsersFn:  func(context.Context) (int, error) { return 3, nil },
+				emailExistsFn: func(context.Context, string) (bool, error) { return false, nil },
+				createFn:      func(_ context.Context, u *User) error { created = u; return nil },
+			}
+			codes := &fakeCodeRedeemer{code: "JOIN-US", uses: 1}
+			svc := newTestAuthService(repo)
+			svc.regPolicy = fakeRegPolicy{mode: tt.mode}
+			svc.inviteChecker = fakeInviteChecker{valid: true}
+			svc.domainPolicy = tt.domains
+			svc.inviteCodes = codes
+
+			_, err := svc.Register(context.Background(), RegisterInput{
+				Email: tt.email, DisplayName: "X", Password: "password123",
+				InviteToken: tt.inviteToken, InviteCode: tt.inviteCode,
+			})
+			if tt.wantStatus != 0 {
+				assertAppError(t, err, tt.wantStatus)
+				if created != nil {
+					t.Error("a blocked registration still created a user")
+				}
+			} else if err != nil || created == nil {
+				t.Fatalf("Register: err = %v, created = %v; want success", err, created)
+			}
+			if codes.uses != tt.wantUses {
+				t.Errorf("code uses left = %d, want %d", codes.uses, tt.wantUses)
+			}
+		})
+	}
+}
+
+// TestRegistrationStatus_InviteCodes: with invite codes wired, invite mode
+// shows the form to visitors without a campaign invite.
+func TestRegistrationStatus_InviteCodes(t *testing.T) {
+	repo := &mockUserRepo{countUsersFn: func(context.Context) (int, error) { return 2, nil }}
+	svc := newTestAuthService(repo)
+	svc.regPolicy = fakeRegPolicy{mode: "invite"}
+	svc.inviteCodes = &fakeCodeRedeemer{}
+
+	if _, allowed, err := svc.RegistrationStatus(context.Background(), ""); err != nil || !allowed {
+		t.Errorf("RegistrationStatus = %v, %v; want the form shown", allowed, err)
+	}
+
+	svc.regPolicy = fakeRegPolicy{mode: "closed"}
+	if _, allowed, _ := svc.RegistrationStatus(context.Background(), ""); allowed {
+		t.Error("closed mode must stay gated with invite codes wired")
+	}
+}
@@ -41,6 +41,7 @@ type UserRepository interface {
 	UpdateIsDisabled(ctx context.Context, id string, isDisabled bool) error
 	CountUsers(ctx context.Context) (int, error)
 	CountAdmins(ctx context.Context) (int, error)
+	ListAdmins(ctx context.Context) ([]User, error)
 }
 
 // userRepository implements UserRepository with hand-written MariaDB queries.
@@ -259,6 +260,28 @@ func (r *userRepository) CountAdmins(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// ListAdmins returns every enabled admin's ID, email and display name.
+// Used to route instance alerts.
+func (r *userRepository) ListAdmins(ctx context.Context) ([]User, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, email, display_name FROM users
+		 WHERE is_admin = true AND is_disabled = false ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("listing admins: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		u := User{IsAdmin: true}
+		if err := rows.Scan(&u.ID, &u.Email, &u.DisplayName); err != nil {
+			return nil, fmt.Errorf("scanning admin row: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
 // --- User Profile ---
 
 // UpdateTimezone sets the IANA timezone for a user. Empty string sets NULL.
@@ -8,24 +8,57 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/middleware"
 )
 
+// Default per-IP, per-minute caps for the public auth endpoints.
+const (
+	DefaultLoginRateLimit         = 10
+	DefaultRegisterRateLimit      = 5
+	DefaultPasswordResetRateLimit = 3
+)
+
+// RateLimits supplies the live per-minute caps for the public auth POST
+// endpoints. A nil func uses the matching default.
+type RateLimits struct {
+	Login         func() int
+	Register      func() int
+	PasswordReset func() int
+}
+
+// withDefaults fills nil funcs with the default caps.
+func (l RateLimits) withDefaults() RateLimits {
+	fixed := func(n int) func() int { return func() int { return n } }
+	if l.Login == nil {
+		l.Login = fixed(DefaultLoginRateLimit)
+	}
+	if l.Register == nil {
+		l.Register = fixed(DefaultRegisterRateLimit)
+	}
+	if l.PasswordReset == nil {
+		l.PasswordReset = fixed(DefaultPasswordResetRateLimit)
+	}
+	return l
+}
+
 // RegisterRoutes sets up all auth-related routes on the given Echo instance.
 // Auth routes are public (no session required) -- the middleware is exported
 // separately for other plugins to use on their route groups.
 //
-// POST endpoints are rate-limited to prevent brute-force and credential
-// stuffing attacks: 10 attempts per IP per minute for login, 5 for register.
-func RegisterRoutes(e *echo.Echo, h *Handler) {
+// POST endpoints are rate-limited per IP per minute to prevent brute-force
+// and credential stuffing attacks. The caps come from limits so admins can
+// tune them from runtime settings without a restart.
+func RegisterRoutes(e *echo.Echo, h *Handler, limits RateLimits) {
+	limits = limits.withDefaults()
+
 	// Public routes -- no auth required.
 	e.GET("/login", h.LoginForm)
-	e.POST("/login", h.Login, middleware.RateLimit(10, time.Minute))
+	e.POST("/login", h.Login, middleware.DynamicRateLimit(limits.Login, time.Minute))
 	e.GET("/register", h.RegisterForm)
-	e.POST("/register", h.Register, middleware.RateLimit(5, time.Minute))
+	e.POST("/register", h.Register, middleware.DynamicRateLimit(limits.Register, time.Minute))
 
 	// Password reset (public, rate-limited to prevent abuse).
 	e.GET("/forgot-password", h.ForgotPasswordForm)
-	e.POST("/forgot-password", h.ForgotPassword, middleware.RateLimit(3, time.Minute))
+	e.POST("/forgot-password", h.ForgotPassword, middleware.DynamicRateLimit(limits.PasswordReset, time.Minute))
 	e.GET("/reset-password", h.ResetPasswordForm)
-	e.POST("/reset-password", h.ResetPassword, middleware.RateLimit(3, time.Minute))
+	e.POST("/reset-password", h.ResetPassword, middleware.DynamicRateLimit(limits.PasswordReset, time.Minute))
 
 	// Logout requires an active session.
 	e.POST("/logout", h.Logout)
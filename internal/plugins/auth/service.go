@@ -17,6 +17,7 @@ import (
 	"golang.org/x/crypto/argon2"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
 )
 
 // sessionKeyPrefix is the Redis key prefix for session data.
@@ -58,6 +59,7 @@ const (
 // Matches smtp.MailService to avoid importing the smtp package directly.
 type MailSender interface {
 	SendMail(ctx context.Context, to []string, subject, body string) error
+	SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error
 	IsConfigured(ctx context.Context) bool
 }
 
@@ -67,16 +69,26 @@ type AuthService interface {
 	Register(ctx context.Context, input RegisterInput) (*User, error)
 
 	// RegistrationStatus reports the current registration mode and whether a
-	// registration bearing inviteToken would be allowed right now (first-user
-	// bootstrap is always allowed). Used by the register page to choose between
-	// the form and a friendly gated state.
+	// registration bearing inviteToken could go ahead (first-user bootstrap is
+	// always allowed; in invite mode with invite codes enabled, anyone may try
+	// a code). Used by the register page to choose between the form and a
+	// friendly gated state.
 	RegistrationStatus(ctx context.Context, inviteToken string) (mode string, allowed bool, err error)
 	Login(ctx context.Context, input LoginInput) (token string, user *User, err error)
+
+	// LoginFailureCount returns the recent failed login attempts for an email
+	// (the throttle counter). The login handler uses it to decide when to ask
+	// for a CAPTCHA.
+	LoginFailureCount(ctx context.Context, email string) int
 	ValidateSession(ctx context.Context, token string) (*Session, error)
 	DestroySession(ctx context.Context, token string) error
 
 	// Password reset flow.
 	InitiatePasswordReset(ctx context.Context, email string) error
+	// AdminForcePasswordReset sends userID a reset link on an admin's behalf
+	// and signs them out everywhere. Unlike InitiatePasswordReset it reports
+	// failures (no SMTP, unknown user) since the caller is trusted.
+	AdminForcePasswordReset(ctx context.Context, userID string) error
 	ValidateResetToken(ctx context.Context, token string) (email string, err error)
 	ResetPassword(ctx context.Context, token, newPassword string) error
 
@@ -117,6 +129,12 @@ type authService struct {
 	// absent wiring fails open rather than locking out an instance.
 	regPolicy     RegistrationPolicy
 	inviteChecker RegistrationInviteChecker
+
+	// Registration restrictions. Also optional: nil domains admits any email
+	// domain, nil inviteCodes means invite-only mode accepts campaign invites
+	// alone.
+	domainPolicy EmailDomainPolicy
+	inviteCodes  InviteCodeRedeemer
 }
 
 // Registration modes. These mirror the settings plugin's canonical constants;
@@ -154,6 +172,30 @@ func ConfigureRegistrationGate(svc AuthService, policy RegistrationPolicy, check
 	}
 }
 
+// EmailDomainPolicy reads the signup email-domain allowlist. Satisfied by the
+// settings service. An empty list means any domain may register.
+type EmailDomainPolicy interface {
+	GetAllowedEmailDomains(ctx context.Context) ([]string, error)
+}
+
+// InviteCodeRedeemer consumes admin-issued registration invite codes, the
+// second way (besides a campaign invite) to register in invite-only mode.
+// Satisfied by the settings invite code service.
+type InviteCodeRedeemer interface {
+	// RedeemInviteCode consumes one use of code; false means the code is
+	// unknown, expired, or used up.
+	RedeemInviteCode(ctx context.Context, code string) (bool, error)
+}
+
+// ConfigureRegistrationRestrictions wires the email-domain allowlist and the
+// invite code store into the auth service. Mirrors ConfigureRegistrationGate.
+func ConfigureRegistrationRestrictions(svc AuthService, domains EmailDomainPolicy, codes InviteCodeRedeemer) {
+	if s, ok := svc.(*authService); ok {
+		s.domainPolicy = domains
+		s.inviteCodes = codes
+	}
+}
+
 // NewAuthService creates a new auth service with the given dependencies.
 func NewAuthService(repo UserRepository, rdb *redis.Client, sessionTTL time.Duration) AuthService {
 	return &authService{
@@ -197,7 +239,7 @@ func (s *authService) Register(ctx context.Context, input RegisterInput) (*User,
 	// (defense in depth — the register page also gates itself, but the service
 	// is the authoritative check). Runs before the expensive hash.
 	if userCount > 0 {
-		if err := s.checkRegistrationGate(ctx, input.InviteToken, input.Email); err != nil {
+		if err := s.checkRegistrationGate(ctx, input); err != nil {
 			return nil, err
 		}
 	}
@@ -260,22 +302,74 @@ func (s *authService) gateAllows(ctx context.Context, mode, inviteToken, email s
 	}
 }
 
-// checkRegistrationGate returns a Forbidden error when the current mode blocks a
-// (non-first) registration bearing inviteToken for email. Caller must have
-// confirmed there is already at least one user (the first-user bootstrap bypasses
-// the gate). Fails CLOSED if the mode can't be read.
-func (s *authService) checkRegistrationGate(ctx context.Context, inviteToken, email string) error {
+// checkRegistrationGate returns an error when the current mode or the domain
+// allowlist blocks a (non-first) registration. Caller must have confirmed
+// there is already at least one user (the first-user bootstrap bypasses the
+// gate). Fails CLOSED if the mode or allowlist can't be read.
+//
+// Blocks the visitor can't fix are Forbidden (the handler shows the gated
+// panel); a disallowed domain or a bad invite code is BadRequest, so the form
+// re-renders with the message. In invite mode a campaign invite for this email
+// wins; only without one is an invite code redeemed, so a campaign invitee
+// never burns a code. The code is consumed before the account is created: a
+// failed insert afterwards costs one use, which is preferable to two
+// concurrent signups overrunning a code's limit.
+func (s *authService) checkRegistrationGate(ctx context.Context, input RegisterInput) error {
+	unavailable := apperror.NewForbidden("registration is temporarily unavailable — please try again shortly")
 	mode, err := s.registrationMode(ctx)
+	if err != nil {
+		return unavailable
+	}
+	if mode == registrationClosed {
+		return apperror.NewForbidden("registration is currently closed")
+	}
+
+	if err := s.checkEmailDomain(ctx, input.Email); err != nil {
+		return err
+	}
+
+	if s.gateAllows(ctx, mode, input.InviteToken, input.Email) {
+		return nil
+	}
+	if mode == registrationInvite && s.inviteCodes != nil {
+		code := strings.TrimSpace(input.InviteCode)
+		if code == "" {
+			return apperror.NewBadRequest("an invite code is required to register")
+		}
+		ok, err := s.inviteCodes.RedeemInviteCode(ctx, code)
+		if err != nil {
+			return unavailable
+		}
+		if !ok {
+			return apperror.NewBadRequest("that invite code is invalid, expired, or already used up")
+		}
+		return nil
+	}
+	return apperror.NewForbidden("registration is invite-only — open your invite link and register with the email it was sent to")
+}
+
+// checkEmailDomain enforces the signup domain allowlist, if one is set.
+// Matching is exact on the part after the last "@": subdomains must be listed
+// separately.
+func (s *authService) checkEmailDomain(ctx context.Context, email string) error {
+	if s.domainPolicy == nil {
+		return nil
+	}
+	domains, err := s.domainPolicy.GetAllowedEmailDomains(ctx)
 	if err != nil {
 		return apperror.NewForbidden("registration is temporarily unavailable — please try again shortly")
 	}
-	if s.gateAllows(ctx, mode, inviteToken, email) {
+	if len(domains) == 0 {
 		return nil
 	}
-	if mode == registrationInvite {
-		return apperror.NewForbidden("registration is invite-only — open your invite link and register with the email it was sent to")
+	email = strings.ToLower(strings.TrimSpace(email))
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for _, d := range domains {
+		if domain == d {
+			return nil
+		}
 	}
-	return apperror.NewForbidden("registration is currently closed")
+	return apperror.NewBadRequest("registration is limited to email addresses at " + strings.Join(domains, ", "))
 }
 
 // RegistrationStatus reports the mode and whether a registration bearing
@@ -296,6 +390,11 @@ func (s *authService) RegistrationStatus(ctx context.Context, inviteToken string
 	if modeErr != nil {
 		return registrationClosed, false, nil
 	}
+	// With invite codes wired, invite mode shows the form (with a code
+	// field) to everyone; the POST redeems the code.
+	if mode == registrationInvite && s.inviteCodes != nil {
+		return mode, true, nil
+	}
 	return mode, s.gateAllows(ctx, mode, inviteToken, ""), nil
 }
 
@@ -423,6 +522,20 @@ func (s *authService) loginThrottleDelay(ctx context.Context, email string) time
 	return delay
 }
 
+// LoginFailureCount returns the failed attempts for an email within the
+// throttle window. 0 without Redis or on a Redis error.
+func (s *authService) LoginFailureCount(ctx context.Context, email string) int {
+	if s.redis == nil {
+		return 0
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	count, err := s.redis.Get(ctx, loginFailureKey(email)).Int()
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
 // recordLoginFailure increments the failed attempt counter for an email.
 // The counter auto-expires after the throttle window.
 func (s *authService) recordLoginFailure(ctx context.Context, email string) {
@@ -738,14 +851,7 @@ func (s *authService) InitiatePasswordReset(ctx context.Context, email string) e
 
 	// Send the email with the plaintext token in the link.
 	if s.mail != nil && s.mail.IsConfigured(ctx) {
-		link := fmt.Sprintf("%s/reset-password?token=%s", s.baseURL, plainToken)
-		body := fmt.Sprintf(
-			"A password reset was requested for your Chronicle account.\n\n"+
-				"Click the link below to set a new password:\n%s\n\n"+
-				"This link expires in 1 hour. If you did not request this, you can safely ignore this email.",
-			link,
-		)
-		if err := s.mail.SendMail(ctx, []string{user.Email}, "Password Reset — Chronicle", body); err != nil {
+		if err := s.sendEmail(ctx, user.Email, emails.PasswordReset(s.resetLink(plainToken))); err != nil {
 			slog.Warn("failed to send password reset email",
 				slog.String("email", user.Email),
 				slog.Any("error", err),
@@ -765,6 +871,54 @@ func (s *authService) InitiatePasswordReset(ctx context.Context, email string) e
 	return nil
 }
 
+// AdminForcePasswordReset issues a reset token for userID, emails the link,
+// and destroys the user's sessions so they have to sign in again. Skips the
+// per-email rate limit: it exists to stop anonymous abuse of the public form.
+func (s *authService) AdminForcePasswordReset(ctx context.Context, userID string) error {
+	if s.mail == nil || !s.mail.IsConfigured(ctx) {
+		return apperror.NewBadRequest("SMTP is not configured; the reset email cannot be sent")
+	}
+
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tokenBytes := make([]byte, resetTokenBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return apperror.NewInternal(fmt.Errorf("generating reset token: %w", err))
+	}
+	plainToken := hex.EncodeToString(tokenBytes)
+	expiresAt := time.Now().UTC().Add(resetTokenExpiry)
+	if err := s.repo.CreateResetToken(ctx, user.ID, user.Email, hashToken(plainToken), expiresAt); err != nil {
+		return apperror.NewInternal(fmt.Errorf("storing reset token: %w", err))
+	}
+
+	if err := s.sendEmail(ctx, user.Email, emails.AdminPasswordReset(s.resetLink(plainToken))); err != nil {
+		return apperror.NewInternal(fmt.Errorf("sending password reset email: %w", err))
+	}
+
+	s.destroyUserSessions(ctx, user.ID)
+
+	slog.Info("admin forced password reset", slog.String("user_id", user.ID))
+	return nil
+}
+
+// sendEmail renders a templated message and hands both bodies to the mail
+// sender.
+func (s *authService) sendEmail(ctx context.Context, to string, msg emails.Message) error {
+	plainBody, htmlBody, err := msg.Render(ctx)
+	if err != nil {
+		return err
+	}
+	return s.mail.SendHTMLMail(ctx, []string{to}, msg.Subject, plainBody, htmlBody)
+}
+
+// resetLink builds the emailed password reset URL for a plaintext token.
+func (s *authService) resetLink(plainToken string) string {
+	return fmt.Sprintf("%s/reset-password?token=%s", s.baseURL, plainToken)
+}
+
 // ValidateResetToken checks that a reset token is valid, unused, and unexpired.
 // Returns the associated email address on success.
 func (s *authService) ValidateResetToken(ctx context.Context, token string) (string, error) {
@@ -995,13 +1149,7 @@ func (s *authService) RequestEmailChange(ctx context.Context, userID, newEmail,
 	// Send verification email to the NEW address.
 	if s.mail != nil && s.mail.IsConfigured(ctx) {
 		link := fmt.Sprintf("%s/account/email/verify?token=%s", s.baseURL, plainToken)
-		body := fmt.Sprintf(
-			"An email change was requested for your Chronicle account.\n\n"+
-				"Click the link below to confirm your new email address:\n%s\n\n"+
-				"This link expires in 24 hours. If you did not request this change, you can safely ignore this email.",
-			link,
-		)
-		if err := s.mail.SendMail(ctx, []string{newEmail}, "Verify Your New Email — Chronicle", body); err != nil {
+		if err := s.sendEmail(ctx, newEmail, emails.EmailVerification(link)); err != nil {
 			slog.Warn("failed to send email verification",
 				slog.String("user_id", userID),
 				slog.String("new_email", newEmail),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -123,6 +124,10 @@ func (m *mockUserRepo) CountAdmins(ctx context.Context) (int, error) {
 	return 0, nil
 }
 
+func (m *mockUserRepo) ListAdmins(ctx context.Context) ([]User, error) {
+	return nil, nil
+}
+
 func (m *mockUserRepo) UpdateIsDisabled(ctx context.Context, id string, isDisabled bool) error {
 	if m.updateIsDisabledFn != nil {
 		return m.updateIsDisabledFn(ctx, id, isDisabled)
@@ -164,6 +169,7 @@ type mockMailSender struct {
 	lastTo      []string
 	lastSubject string
 	lastBody    string
+	lastHTML    string
 	sendCount   int
 }
 
@@ -178,6 +184,12 @@ func (m *mockMailSender) SendMail(ctx context.Context, to []string, subject, bod
 	return nil
 }
 
+// SendHTMLMail captures the HTML part and records the plain part like SendMail.
+func (m *mockMailSender) SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error {
+	m.lastHTML = htmlBody
+	return m.SendMail(ctx, to, subject, plainBody)
+}
+
 func (m *mockMailSender) IsConfigured(ctx context.Context) bool {
 	if m.isConfiguredFn != nil {
 		return m.isConfiguredFn(ctx)
@@ -557,6 +569,62 @@ func TestInitiatePasswordReset_EmailNormalization(t *testing.T) {
 	}
 }
 
+func TestAdminForcePasswordReset(t *testing.T) {
+	user := &User{ID: "user-123", Email: "alice@example.com"}
+	cases := []struct {
+		name       string
+		configured bool
+		findErr    error
+		sendErr    error
+		wantCode   int
+		wantSent   bool
+	}{
+		{name: "sends the link", configured: true, wantSent: true},
+		{name: "SMTP not configured", configured: false, wantCode: 400},
+		{name: "unknown user", configured: true, findErr: apperror.NewNotFound("user not found"), wantCode: 404},
+		{name: "send failure is reported", configured: true, sendErr: errors.New("smtp down"), wantCode: 500, wantSent: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var tokenStored bool
+			repo := &mockUserRepo{
+				findByIDFn: func(context.Context, string) (*User, error) {
+					if tc.findErr != nil {
+						return nil, tc.findErr
+					}
+					return user, nil
+				},
+				createResetTokenFn: func(context.Context, string, string, string, time.Time) error {
+					tokenStored = true
+					return nil
+				},
+			}
+			mail := &mockMailSender{
+				isConfiguredFn: func(context.Context) bool { return tc.configured },
+				sendMailFn: func(context.Context, []string, string, string) error {
+					return tc.sendErr
+				},
+			}
+			svc := newTestAuthService(repo)
+			svc.mail = mail
+			svc.baseURL = "https://chronicle.example.com"
+
+			err := svc.AdminForcePasswordReset(context.Background(), "user-123")
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := mail.sendCount == 1; got != tc.wantSent {
+				t.Errorf("email sent = %v, want %v", got, tc.wantSent)
+			}
+			if tc.wantSent && (!tokenStored || !strings.Contains(mail.lastBody, "https://chronicle.example.com/reset-password?token=")) {
+				t.Errorf("expected a stored token and a reset link, body: %q", mail.lastBody)
+			}
+		})
+	}
+}
+
 func TestValidateResetToken_Valid(t *testing.T) {
 	plainToken := "abcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890"
 	expectedHash := hashToken(plainToken)
@@ -0,0 +1,62 @@
+package calendar
+
+// audit_diff.go — before/after diffs for calendar event edits, stored on
+// the event's audit entry so the activity feed shows what was moved or
+// renamed. Every value of a dm_only event (before or after the edit) is
+// marked Private: the event itself is a secret from players.
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+)
+
+// eventSnapshot captures the values an event edit can change.
+func eventSnapshot(evt *Event, private bool) []audit.SnapshotField {
+	var entity any
+	if evt.EntityID != nil && *evt.EntityID != "" {
+		entity = map[string]any{"id": *evt.EntityID, "name": evt.EntityName}
+	}
+	snap := []audit.SnapshotField{
+		{Key: "name", Label: "Name", Value: evt.Name},
+		// Only the plain text is kept, not the rich HTML shown on the
+		// calendar, so a description can't be restored from the diff.
+		{Key: "description", Label: "Description", Value: evt.Description, Partial: true},
+		{Key: "year", Label: "Year", Value: evt.Year},
+		{Key: "month", Label: "Month", Value: evt.Month},
+		{Key: "day", Label: "Day", Value: evt.Day},
+		{Key: "start_hour", Label: "Start hour", Value: evt.StartHour},
+		{Key: "start_minute", Label: "Start minute", Value: evt.StartMinute},
+		{Key: "end_year", Label: "End year", Value: evt.EndYear},
+		{Key: "end_month", Label: "End month", Value: evt.EndMonth},
+		{Key: "end_day", Label: "End day", Value: evt.EndDay},
+		{Key: "end_hour", Label: "End hour", Value: evt.EndHour},
+		{Key: "end_minute", Label: "End minute", Value: evt.EndMinute},
+		{Key: "all_day", Label: "All day", Value: evt.AllDay},
+		{Key: "is_recurring", Label: "Recurring", Value: evt.IsRecurring},
+		{Key: "recurrence_type", Label: "Repeats", Value: evt.RecurrenceType},
+		{Key: "recurrence_interval", Label: "Repeat interval", Value: evt.RecurrenceInterval},
+		{Key: "visibility", Label: "Visibility", Value: evt.Visibility},
+		{Key: "category", Label: "Category", Value: evt.Category},
+		{Key: "tier", Label: "Tier", Value: evt.Tier},
+		{Key: "entity", Label: "Linked page", Value: entity},
+	}
+	for i := range snap {
+		snap[i].Private = private
+	}
+	return snap
+}
+
+// eventChanges diffs an event before and after an edit. after is re-read;
+// nil means it couldn't be, and the entry goes without a diff.
+func (h *Handler) eventChanges(c echo.Context, before *Event) []audit.Change {
+	after, err := h.svc.GetEvent(c.Request().Context(), before.ID)
+	if err != nil || after == nil {
+		slog.Warn("audit diff: reloading event failed", slog.String("event_id", before.ID), slog.Any("error", err))
+		return nil
+	}
+	private := before.Visibility == "dm_only" || after.Visibility == "dm_only"
+	return audit.Diff(eventSnapshot(before, private), eventSnapshot(after, private))
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/keyxmakerx/chronicle/internal/apperror"
 	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
 )
@@ -105,6 +106,7 @@ type stubCalSvc struct {
 	visErr error
 	advanceDateErr error
 	advanceTimeErr error
+	undoneAdv *DateAdvancement
 	deleteCalErr error
 	updateCalErr error
 
@@ -136,6 +138,12 @@ func (s *stubCalSvc) SetMonths(_ context.Context, _ string, _ []MonthInput) erro
 func (s *stubCalSvc) SetWeekdays(_ context.Context, _ string, _ []WeekdayInput) error {
 	return s.setWeekdaysErr
 }
+func (s *stubCalSvc) SetMonthsWithRemedy(_ context.Context, _ string, _ []MonthInput, _ string) error {
+	return s.setMonthsErr
+}
+func (s *stubCalSvc) SetWeekdaysWithRemedy(_ context.Context, _ string, _ []WeekdayInput, _ string) error {
+	return s.setWeekdaysErr
+}
 func (s *stubCalSvc) SetMoons(_ context.Context, _ string, _ []MoonInput) error {
 	return s.setMoonsErr
 }
@@ -161,6 +169,15 @@ func (s *stubCalSvc) SetCycles(_ context.Context, _ string, _ []CycleInput) erro
 func (s *stubCalSvc) SetFestivals(_ context.Context, _ string, _ []FestivalInput) error {
 	return s.festivalsErr
 }
+func (s *stubCalSvc) SetYearNames(_ context.Context, _ string, _ []YearNameInput) error {
+	return nil
+}
+func (s *stubCalSvc) SetLocationZones(_ context.Context, _ string, _ []LocationZone) error {
+	return nil
+}
+func (s *stubCalSvc) SetZoneWeather(_ context.Context, _, _ string, _ WeatherInput) error {
+	return nil
+}
 func (s *stubCalSvc) CreateEvent(_ context.Context, _ string, _ CreateEventInput) (*Event, error) {
 	if s.createEvtErr != nil {
 		return nil, s.createEvtErr
@@ -183,10 +200,27 @@ func (s *stubCalSvc) GetEvent(_ context.Context, id string) (*Event, error) {
 	}
 	return nil, nil
 }
-func (s *stubCalSvc) AdvanceDate(_ context.Context, _ string, _ int) error { return s.advanceDateErr }
-func (s *stubCalSvc) AdvanceTime(_ context.Context, _ string, _, _ int) error {
+// AdvanceDate/AdvanceTime move the stub calendar's clock (no rollover) so
+// the handler's before/after stamps differ.
+func (s *stubCalSvc) AdvanceDate(_ context.Context, _, _ string, days int) error {
+	if s.advanceDateErr == nil && s.cal != nil {
+		s.cal.CurrentDay += days
+	}
+	return s.advanceDateErr
+}
+func (s *stubCalSvc) AdvanceTime(_ context.Context, _, _ string, hours, minutes int) error {
+	if s.advanceTimeErr == nil && s.cal != nil {
+		s.cal.CurrentHour += hours
+		s.cal.CurrentMinute += minutes
+	}
 	return s.advanceTimeErr
 }
+func (s *stubCalSvc) UndoLastAdvancement(_ context.Context, _ string) (*DateAdvancement, error) {
+	if s.undoneAdv == nil {
+		return nil, apperror.NewValidation("there is no advancement to undo")
+	}
+	return s.undoneAdv, nil
+}
 func (s *stubCalSvc) GetCalendar(_ context.Context, _ string) (*Calendar, error) {
 	return s.cal, nil
 }
@@ -375,6 +409,47 @@ func TestUpdateFestivalsAPI_EmitsFestivalsSet(t *testing.T) {
 	}
 }
 
+// TestUpdateYearNamesAPI_EmitsYearNamesSet
+func TestUpdateYearNamesAPI_EmitsYearNamesSet(t *testing.T) {
+	svc := &stubCalSvc{cal: &Calendar{ID: "cal-1", CampaignID: "camp-1"}}
+	h, rec := makeHandler(svc)
+	c, _, _ := newReqWithCC(http.MethodPut, "/api", []byte(`[{"year":1492,"name":"Year of Three Ships Sailing"}]`), "cal-1", "camp-1", "u-1")
+	if err := h.UpdateYearNamesAPI(c); err != nil {
+		t.Fatalf("UpdateYearNamesAPI: %v", err)
+	}
+	if got := rec.findByAction(audit.ActionCalendarYearNamesSet); got.Details["count"] != 1 {
+		t.Errorf("Details[count]=%v; want 1", got.Details["count"])
+	}
+}
+
+// TestUpdateLocationZonesAPI_EmitsLocationZonesSet
+func TestUpdateLocationZonesAPI_EmitsLocationZonesSet(t *testing.T) {
+	svc := &stubCalSvc{cal: &Calendar{ID: "cal-1", CampaignID: "camp-1"}}
+	h, rec := makeHandler(svc)
+	c, _, _ := newReqWithCC(http.MethodPut, "/api", []byte(`[{"entity_id":"ent-1","zone_id":"desert"}]`), "cal-1", "camp-1", "u-1")
+	if err := h.UpdateLocationZonesAPI(c); err != nil {
+		t.Fatalf("UpdateLocationZonesAPI: %v", err)
+	}
+	if got := rec.findByAction(audit.ActionCalendarLocationZonesSet); got.Details["count"] != 1 {
+		t.Errorf("Details[count]=%v; want 1", got.Details["count"])
+	}
+}
+
+// TestUpdateZoneWeatherAPI_EmitsZoneWeatherSet
+func TestUpdateZoneWeatherAPI_EmitsZoneWeatherSet(t *testing.T) {
+	svc := &stubCalSvc{cal: &Calendar{ID: "cal-1", CampaignID: "camp-1"}}
+	h, rec := makeHandler(svc)
+	c, _, _ := newReqWithCC(http.MethodPut, "/api", []byte(`{"preset_id":"sandstorm"}`), "cal-1", "camp-1", "u-1")
+	c.SetParamNames("id", "calId", "zoneId")
+	c.SetParamValues("camp-1", "cal-1", "desert")
+	if err := h.UpdateZoneWeatherAPI(c); err != nil {
+		t.Fatalf("UpdateZoneWeatherAPI: %v", err)
+	}
+	if got := rec.findByAction(audit.ActionCalendarZoneWeatherSet); got.Details["zone_id"] != "desert" {
+		t.Errorf("Details[zone_id]=%v; want desert", got.Details["zone_id"])
+	}
+}
+
 // TestCreateEventAPI_EmitsEventCreated
 func TestCreateEventAPI_EmitsEventCreated(t *testing.T) {
 	svc := &stubCalSvc{
@@ -476,6 +551,68 @@ func TestAdvanceTimeAPI_EmitsTimeAdvanced(t *testing.T) {
 	}
 }
 
+// TestAdvanceAPI_RecordsBeforeAfterAndPreset — the GM console presets post
+// here; the audit entry carries both clock stamps and the preset name.
+func TestAdvanceAPI_RecordsBeforeAfterAndPreset(t *testing.T) {
+	svc := &stubCalSvc{cal: &Calendar{ID: "cal-1", CampaignID: "camp-1",
+		CurrentYear: 1492, CurrentMonth: 3, CurrentDay: 10, CurrentHour: 9}}
+	h, rec := makeHandler(svc)
+
+	c, _, _ := newReqWithCC(http.MethodPost, "/api", []byte(`{"hours":8,"preset":"Long rest"}`), "cal-1", "camp-1", "u-1")
+	if err := h.AdvanceTimeAPI(c); err != nil {
+		t.Fatalf("AdvanceTimeAPI: %v", err)
+	}
+	got := rec.findByAction(audit.ActionCalendarTimeAdvanced)
+	if got.Details["from"] != "1492-03-10 09:00" || got.Details["to"] != "1492-03-10 17:00" {
+		t.Errorf("from/to = %v → %v; want 1492-03-10 09:00 → 1492-03-10 17:00", got.Details["from"], got.Details["to"])
+	}
+	if got.Details["preset"] != "Long rest" {
+		t.Errorf("Details[preset]=%v; want Long rest", got.Details["preset"])
+	}
+
+	c, resp, _ := newReqWithCC(http.MethodPost, "/api", []byte(`{"days":1}`), "cal-1", "camp-1", "u-1")
+	if err := h.AdvanceDateAPI(c); err != nil {
+		t.Fatalf("AdvanceDateAPI: %v", err)
+	}
+	got = rec.findByAction(audit.ActionCalendarDateAdvanced)
+	if got.Details["to"] != "1492-03-11 17:00" {
+		t.Errorf("Details[to]=%v; want 1492-03-11 17:00", got.Details["to"])
+	}
+	if _, ok := got.Details["preset"]; ok {
+		t.Errorf("manual advance must not record a preset, got %v", got.Details["preset"])
+	}
+	if !strings.Contains(resp.Body.String(), `"to":"1492-03-11 17:00"`) {
+		t.Errorf("response body = %s; want the after stamp", resp.Body.String())
+	}
+}
+
+// TestUndoAdvanceAPI_RecordsRevertedSpan — the undo's from/to run the
+// advancement backwards; a failed undo writes no audit entry.
+func TestUndoAdvanceAPI_RecordsRevertedSpan(t *testing.T) {
+	svc := &stubCalSvc{cal: &Calendar{ID: "cal-1", CampaignID: "camp-1", Name: "Harptos"}}
+	h, rec := makeHandler(svc)
+
+	c, _, _ := newReqWithCC(http.MethodPost, "/api", nil, "cal-1", "camp-1", "u-1")
+	if err := h.UndoAdvanceAPI(c); err == nil {
+		t.Fatal("undo with nothing to undo should fail")
+	}
+	if len(rec.entries) != 0 {
+		t.Errorf("failed undo logged %d entries; want 0", len(rec.entries))
+	}
+
+	svc.undoneAdv = &DateAdvancement{Kind: AdvanceKindTime,
+		FromYear: 1492, FromMonth: 3, FromDay: 10, FromHour: 9,
+		ToYear: 1492, ToMonth: 3, ToDay: 10, ToHour: 17}
+	c, _, _ = newReqWithCC(http.MethodPost, "/api", nil, "cal-1", "camp-1", "u-1")
+	if err := h.UndoAdvanceAPI(c); err != nil {
+		t.Fatalf("UndoAdvanceAPI: %v", err)
+	}
+	got := rec.findByAction(audit.ActionCalendarAdvanceUndone)
+	if got.Details["from"] != "1492-03-10 17:00" || got.Details["to"] != "1492-03-10 09:00" {
+		t.Errorf("from/to = %v → %v; want 1492-03-10 17:00 → 1492-03-10 09:00", got.Details["from"], got.Details["to"])
+	}
+}
+
 // TestDeleteCalendarAPI_EmitsCalendarDeleted — pre-state snapshot
 // (mode, epoch, year) populated from the pre-delete read.
 func TestDeleteCalendarAPI_EmitsCalendarDeleted(t *testing.T) {
@@ -542,3 +679,110 @@ func (failingAuditSvc) GetEntityHistory(_ context.Context, _, _ string) ([]audit
 func (failingAuditSvc) GetCampaignStats(_ context.Context, _ string) (*audit.CampaignStats, error) {
 	return nil, nil
 }
+
+// renamingCalSvc applies an event update's name and date to the stub event,
+// so the handler's re-read sees the edit.
+type renamingCalSvc struct {
+	*stubCalSvc
+}
+
+func (s renamingCalSvc) UpdateEvent(_ context.Context, _ string, in UpdateEventInput) error {
+	updated := *s.evt
+	updated.Name, updated.Year, updated.Month, updated.Day = in.Name, in.Year, in.Month, in.Day
+	updated.Visibility = in.Visibility
+	s.evt = &updated
+	return nil
+}
+
+// TestUpdateEventAPI_RecordsChanges — the event_updated entry carries a
+// before/after diff of the edited values, private when the event is dm_only.
+func TestUpdateEventAPI_RecordsChanges(t *testing.T) {
+	for _, tc := range []struct {
+		visibility  string
+		wantPrivate bool
+	}{
+		{"everyone", false},
+		{"dm_only", true},
+	} {
+		svc := renamingCalSvc{&stubCalSvc{
+			cal: &Calendar{ID: "cal-1", CampaignID: "camp-1"},
+			evt: &Event{ID: "evt-1", Name: "Feast", CalendarID: "cal-1", Year: 1490, Month: 3, Day: 1, Visibility: tc.visibility},
+		}}
+		h, rec := makeHandler(svc)
+		body := `{"name":"Great Feast","year":1490,"month":3,"day":5,"visibility":"` + tc.visibility + `"}`
+		c, _, _ := newReqWithCC(http.MethodPut, "/api", []byte(body), "cal-1", "camp-1", "u-1")
+		c.SetParamNames("id", "eid")
+		c.SetParamValues("camp-1", "evt-1")
+		if err := h.UpdateEventAPI(c); err != nil {
+			t.Fatalf("UpdateEventAPI: %v", err)
+		}
+
+		got := rec.findByAction(audit.ActionCalendarEventUpdated)
+		changes := got.Changes()
+		if len(changes) != 2 {
+			t.Fatalf("%s: changes = %+v, want name and day", tc.visibility, changes)
+		}
+		if changes[0].Field != "name" || changes[0].Before != "Feast" || changes[0].After != "Great Feast" {
+			t.Errorf("%s: name change = %+v", tc.visibility, changes[0])
+		}
+		if changes[1].Field != "day" || changes[1].Before != float64(1) || changes[1].After != float64(5) {
+			t.Errorf("%s: day change = %+v", tc.visibility, changes[1])
+		}
+		if changes[0].Private != tc.wantPrivate {
+			t.Errorf("%s: private = %v, want %v", tc.visibility, changes[0].Private, tc.wantPrivate)
+		}
+	}
+}
+
+// capturingCalSvc records the input UpdateEvent receives.
+type capturingCalSvc struct {
+	*stubCalSvc
+	got *UpdateEventInput
+}
+
+func (s *capturingCalSvc) UpdateEvent(_ context.Context, _ string, in UpdateEventInput) error {
+	s.got = &in
+	return nil
+}
+
+// TestAuditReverter_RestoreValues — stored diff values go back through
+// UpdateEvent; fields the revert doesn't touch are preserved.
+func TestAuditReverter_RestoreValues(t *testing.T) {
+	newSvc := func() *capturingCalSvc {
+		cat := "festival"
+		return &capturingCalSvc{stubCalSvc: &stubCalSvc{
+			cal: &Calendar{ID: "cal-1", CampaignID: "camp-1"},
+			evt: &Event{ID: "evt-1", Name: "Great Feast", CalendarID: "cal-1", Year: 1490, Month: 3, Day: 5,
+				Visibility: "dm_only", Category: &cat},
+		}}
+	}
+	ctx := context.Background()
+
+	svc := newSvc()
+	err := NewAuditReverter(svc).RestoreValues(ctx, "camp-1", "evt-1", map[string]any{
+		"name": "Feast", "day": float64(1), "category": nil, "start_hour": float64(9),
+	})
+	if err != nil {
+		t.Fatalf("RestoreValues: %v", err)
+	}
+	in := svc.got
+	if in.Name != "Feast" || in.Day != 1 || in.Year != 1490 || in.Visibility != "dm_only" {
+		t.Errorf("input = %+v", in)
+	}
+	if in.Category == nil || *in.Category != "" || in.StartHour == nil || *in.StartHour != 9 {
+		t.Errorf("category/start hour = %v/%v", in.Category, in.StartHour)
+	}
+	if in.Description != nil || in.VisibilityRules != nil {
+		t.Error("untouched pointer fields should be left nil (preserved)")
+	}
+
+	if err := NewAuditReverter(newSvc()).RestoreValues(ctx, "camp-1", "evt-1", map[string]any{"start_hour": nil}); err == nil {
+		t.Error("clearing a clock time without all-day should be refused")
+	}
+	if err := NewAuditReverter(newSvc()).RestoreValues(ctx, "camp-1", "evt-1", map[string]any{"description": "x"}); err == nil {
+		t.Error("description should not be revertible")
+	}
+	if _, _, err := NewAuditReverter(newSvc()).CurrentValues(ctx, "camp-2", "evt-1"); err == nil {
+		t.Error("event from another campaign should not be found")
+	}
+}
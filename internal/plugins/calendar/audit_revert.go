@@ -0,0 +1,152 @@
+package calendar
+
+// audit_revert.go — restores event values for the activity feed's revert
+// action. Values arrive keyed and shaped as eventSnapshot stores them and
+// are written back through UpdateEvent. UpdateEvent treats a nil pointer
+// as "keep", so a clock time or end date that the edit added can only be
+// cleared by reverting all-day back on; otherwise the revert is refused.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+)
+
+// AuditReverter implements audit.ChangeReverter for calendar events.
+type AuditReverter struct {
+	svc CalendarService
+}
+
+// NewAuditReverter creates the event reverter registered with the audit
+// plugin's revert service.
+func NewAuditReverter(svc CalendarService) *AuditReverter {
+	return &AuditReverter{svc: svc}
+}
+
+// CurrentValues snapshots the event as the audit diffs do.
+func (r *AuditReverter) CurrentValues(ctx context.Context, campaignID, eventID string) (string, []audit.SnapshotField, error) {
+	evt, err := r.load(ctx, campaignID, eventID)
+	if err != nil {
+		return "", nil, err
+	}
+	return evt.Name, eventSnapshot(evt, evt.Visibility == "dm_only"), nil
+}
+
+// RestoreValues writes the given snapshot values back to the event.
+func (r *AuditReverter) RestoreValues(ctx context.Context, campaignID, eventID string, values map[string]any) error {
+	evt, err := r.load(ctx, campaignID, eventID)
+	if err != nil {
+		return err
+	}
+
+	// Value-typed fields are always written; pointers left nil are kept.
+	input := UpdateEventInput{
+		Name:        evt.Name,
+		EntityID:    evt.EntityID,
+		Year:        evt.Year,
+		Month:       evt.Month,
+		Day:         evt.Day,
+		IsRecurring: evt.IsRecurring,
+		Visibility:  evt.Visibility,
+		AllDay:      evt.AllDay,
+	}
+	ints := map[string]**int{
+		"start_hour":          &input.StartHour,
+		"start_minute":        &input.StartMinute,
+		"end_year":            &input.EndYear,
+		"end_month":           &input.EndMonth,
+		"end_day":             &input.EndDay,
+		"end_hour":            &input.EndHour,
+		"end_minute":          &input.EndMinute,
+		"recurrence_interval": &input.RecurrenceInterval,
+	}
+	strs := map[string]**string{
+		"recurrence_type": &input.RecurrenceType,
+		"category":        &input.Category,
+		"tier":            &input.Tier,
+	}
+	var cleared []string
+	for key, v := range values {
+		var ok bool
+		switch key {
+		case "name":
+			input.Name, ok = v.(string)
+		case "year":
+			input.Year, ok = revertInt(v)
+		case "month":
+			input.Month, ok = revertInt(v)
+		case "day":
+			input.Day, ok = revertInt(v)
+		case "all_day":
+			input.AllDay, ok = v.(bool)
+		case "is_recurring":
+			input.IsRecurring, ok = v.(bool)
+		case "visibility":
+			input.Visibility, ok = v.(string)
+		case "entity":
+			input.EntityID, ok = nil, v == nil
+			if ref, isRef := v.(map[string]any); isRef {
+				if id, _ := ref["id"].(string); id != "" {
+					input.EntityID, ok = &id, true
+				}
+			}
+		default:
+			if dst, isInt := ints[key]; isInt {
+				if v == nil {
+					cleared, ok = append(cleared, key), true
+					break
+				}
+				var n int
+				n, ok = revertInt(v)
+				*dst = &n
+			} else if dst, isStr := strs[key]; isStr {
+				// An empty string clears these, which nil can't.
+				var s string
+				s, ok = v.(string)
+				ok = ok || v == nil
+				*dst = &s
+			} else {
+				return apperror.NewBadRequest(fmt.Sprintf("%s can't be reverted", key))
+			}
+		}
+		if !ok {
+			return apperror.NewBadRequest(fmt.Sprintf("stored value for %s can't be restored", key))
+		}
+	}
+	for _, key := range cleared {
+		if !input.AllDay || (key != "start_hour" && key != "start_minute" && key != "end_hour" && key != "end_minute") {
+			return apperror.NewBadRequest(fmt.Sprintf("%s can't be cleared by a revert; edit the event instead", key))
+		}
+	}
+
+	return r.svc.UpdateEvent(ctx, eventID, input)
+}
+
+// load fetches the event, hiding one from another campaign as missing —
+// the same check as requireEventInCampaign.
+func (r *AuditReverter) load(ctx context.Context, campaignID, eventID string) (*Event, error) {
+	evt, err := r.svc.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if evt == nil {
+		return nil, apperror.NewNotFound("event not found")
+	}
+	cal, err := r.svc.GetCalendarByID(ctx, evt.CalendarID)
+	if err != nil || cal == nil || cal.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("event not found")
+	}
+	return evt, nil
+}
+
+// revertInt reads a stored whole number, which JSON decoding left as a
+// float64.
+func revertInt(v any) (int, bool) {
+	f, ok := v.(float64)
+	if !ok || f != float64(int(f)) {
+		return 0, false
+	}
+	return int(f), true
+}
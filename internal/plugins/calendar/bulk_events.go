@@ -0,0 +1,171 @@
+// Package calendar — bulk_events.go parses bulk event payloads for seeding
+// many events (festivals, holidays) in one request. Rows use the same shape
+// as Chronicle's export events (ExportEvent), either as a JSON array or as a
+// CSV whose header names the ExportEvent JSON fields.
+package calendar
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// maxBulkEvents caps a single bulk create. Enough for a full festival and
+// holiday calendar; larger sets should be split (or imported as a file).
+const maxBulkEvents = 500
+
+// BulkEventResult reports the outcome of a bulk event create. Rows fail
+// independently, so a partial success still returns 200 with Errors set.
+type BulkEventResult struct {
+	Created int              `json:"created"`
+	Errors  []BulkEventError `json:"errors,omitempty"`
+}
+
+// BulkEventError describes one rejected row.
+type BulkEventError struct {
+	Row     int    `json:"row"` // 1-based position in the submitted list
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+// ParseBulkEventsJSON accepts either a bare array of events or an
+// {"events": [...]} envelope (the shape of a Chronicle export's events).
+func ParseBulkEventsJSON(data []byte) ([]ExportEvent, error) {
+	var events []ExportEvent
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var env struct {
+			Events []ExportEvent `json:"events"`
+		}
+		if err := json.Unmarshal(trimmed, &env); err != nil {
+			return nil, apperror.NewBadRequest("invalid JSON: " + err.Error())
+		}
+		events = env.Events
+	} else if err := json.Unmarshal(trimmed, &events); err != nil {
+		return nil, apperror.NewBadRequest("invalid JSON: expected an array of events")
+	}
+	return normalizeBulkEvents(events), nil
+}
+
+// bulkCSVIntFields maps the integer CSV columns to their ExportEvent target.
+var bulkCSVIntFields = map[string]func(*ExportEvent, int){
+	"year":                       func(e *ExportEvent, v int) { e.Year = v },
+	"month":                      func(e *ExportEvent, v int) { e.Month = v },
+	"day":                        func(e *ExportEvent, v int) { e.Day = v },
+	"start_hour":                 func(e *ExportEvent, v int) { e.StartHour = &v },
+	"start_minute":               func(e *ExportEvent, v int) { e.StartMinute = &v },
+	"end_year":                   func(e *ExportEvent, v int) { e.EndYear = &v },
+	"end_month":                  func(e *ExportEvent, v int) { e.EndMonth = &v },
+	"end_day":                    func(e *ExportEvent, v int) { e.EndDay = &v },
+	"end_hour":                   func(e *ExportEvent, v int) { e.EndHour = &v },
+	"end_minute":                 func(e *ExportEvent, v int) { e.EndMinute = &v },
+	"recurrence_interval":        func(e *ExportEvent, v int) { e.RecurrenceInterval = &v },
+	"recurrence_end_year":        func(e *ExportEvent, v int) { e.RecurrenceEndYear = &v },
+	"recurrence_end_month":       func(e *ExportEvent, v int) { e.RecurrenceEndMonth = &v },
+	"recurrence_end_day":         func(e *ExportEvent, v int) { e.RecurrenceEndDay = &v },
+	"recurrence_max_occurrences": func(e *ExportEvent, v int) { e.RecurrenceMaxOccurrences = &v },
+}
+
+// bulkCSVStringFields maps the text CSV columns to their ExportEvent target.
+var bulkCSVStringFields = map[string]func(*ExportEvent, string){
+	"name":            func(e *ExportEvent, v string) { e.Name = v },
+	"description":     func(e *ExportEvent, v string) { e.Description = &v },
+	"visibility":      func(e *ExportEvent, v string) { e.Visibility = v },
+	"category":        func(e *ExportEvent, v string) { e.Category = &v },
+	"recurrence_type": func(e *ExportEvent, v string) { e.RecurrenceType = &v },
+	"color":           func(e *ExportEvent, v string) { e.Color = &v },
+	"icon":            func(e *ExportEvent, v string) { e.Icon = &v },
+}
+
+// ParseBulkEventsCSV parses a CSV with a header row. Columns are the
+// ExportEvent JSON field names (name, year, month, day are required);
+// unknown columns are rejected so a typo doesn't silently drop data.
+// Empty cells leave the field unset.
+func ParseBulkEventsCSV(data []byte) ([]ExportEvent, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, apperror.NewBadRequest("CSV must start with a header row")
+	}
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		header[i] = col
+		_, isInt := bulkCSVIntFields[col]
+		_, isStr := bulkCSVStringFields[col]
+		if !isInt && !isStr && col != "all_day" {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("unknown CSV column %q", col))
+		}
+	}
+	for _, required := range []string{"name", "year", "month", "day"} {
+		if !containsString(header, required) {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("CSV is missing the %q column", required))
+		}
+	}
+
+	var events []ExportEvent
+	for row := 2; ; row++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("CSV row %d: %v", row, err))
+		}
+		var evt ExportEvent
+		for i, cell := range rec {
+			cell = strings.TrimSpace(cell)
+			if cell == "" || i >= len(header) {
+				continue
+			}
+			col := header[i]
+			if set, ok := bulkCSVIntFields[col]; ok {
+				v, err := strconv.Atoi(cell)
+				if err != nil {
+					return nil, apperror.NewBadRequest(fmt.Sprintf("CSV row %d: %s must be a number", row, col))
+				}
+				set(&evt, v)
+				continue
+			}
+			if set, ok := bulkCSVStringFields[col]; ok {
+				set(&evt, cell)
+				continue
+			}
+			// all_day is the only boolean column.
+			v, err := strconv.ParseBool(cell)
+			if err != nil {
+				return nil, apperror.NewBadRequest(fmt.Sprintf("CSV row %d: all_day must be true or false", row))
+			}
+			evt.AllDay = v
+		}
+		events = append(events, evt)
+	}
+	return normalizeBulkEvents(events), nil
+}
+
+// normalizeBulkEvents derives is_recurring from recurrence_type, matching
+// the editor drawer (which never sends one without the other).
+func normalizeBulkEvents(events []ExportEvent) []ExportEvent {
+	for i := range events {
+		if rt := events[i].RecurrenceType; rt != nil && *rt != "" {
+			events[i].IsRecurring = true
+		}
+	}
+	return events
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
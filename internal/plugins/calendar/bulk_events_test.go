@@ -0,0 +1,192 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParseBulkEventsCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr bool
+		want    int
+	}{
+		{
+			name: "header plus rows",
+			csv:  "name,year,month,day,recurrence_type,all_day,category\nMidsummer,1492,7,1,yearly,true,holiday\nMarket day,1492,1,3,,,\n",
+			want: 2,
+		},
+		{name: "BOM and mixed-case header", csv: "\ufeffName,Year,Month,Day\nX,1,1,1\n", want: 1},
+		{name: "header only", csv: "name,year,month,day\n", want: 0},
+		{name: "unknown column", csv: "name,year,month,day,colour\nX,1,1,1,red\n", wantErr: true},
+		{name: "missing required column", csv: "name,year,month\nX,1,1\n", wantErr: true},
+		{name: "non-numeric day", csv: "name,year,month,day\nX,1,1,first\n", wantErr: true},
+		{name: "bad all_day", csv: "name,year,month,day,all_day\nX,1,1,1,sometimes\n", wantErr: true},
+		{name: "ragged row", csv: "name,year,month,day\nX,1,1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseBulkEventsCSV([]byte(tt.csv))
+			if tt.wantErr {
+				assertAppError(t, err, 400)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != tt.want {
+				t.Fatalf("got %d events, want %d", len(events), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBulkEventsCSV_Fields(t *testing.T) {
+	events, err := ParseBulkEventsCSV([]byte("name,year,month,day,start_hour,recurrence_type,all_day,category,visibility\n" +
+		"Midsummer,1492,7,1,18,yearly,true,holiday,dm_only\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	evt := events[0]
+	if evt.Name != "Midsummer" || evt.Year != 1492 || evt.Month != 7 || evt.Day != 1 {
+		t.Errorf("event = %+v", evt)
+	}
+	if evt.StartHour == nil || *evt.StartHour != 18 || !evt.AllDay || evt.Visibility != "dm_only" {
+		t.Errorf("optional fields not parsed: %+v", evt)
+	}
+	if !evt.IsRecurring || evt.RecurrenceType == nil || *evt.RecurrenceType != RecurrenceYearly {
+		t.Errorf("expected is_recurring derived from recurrence_type, got %+v", evt)
+	}
+	if evt.Category == nil || *evt.Category != "holiday" {
+		t.Errorf("Category = %v, want holiday", evt.Category)
+	}
+}
+
+func TestParseBulkEventsJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		want    int
+	}{
+		{name: "bare array", body: `[{"name":"A","year":1,"month":1,"day":1},{"name":"B","year":1,"month":1,"day":2}]`, want: 2},
+		{name: "export envelope", body: `{"events":[{"name":"A","year":1,"month":1,"day":1,"recurrence_type":"yearly"}]}`, want: 1},
+		{name: "not an array", body: `"festivals"`, wantErr: true},
+		{name: "malformed", body: `[{"name":}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseBulkEventsJSON([]byte(tt.body))
+			if tt.wantErr {
+				assertAppError(t, err, 400)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != tt.want {
+				t.Fatalf("got %d events, want %d", len(events), tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkCreateEvents_ReportsRowErrors(t *testing.T) {
+	created := 0
+	repo := &mockCalendarRepo{
+		createEventFn: func(_ context.Context, _ *Event) error {
+			created++
+			return nil
+		},
+	}
+	res, err := newTestCalendarService(repo).BulkCreateEvents(context.Background(), "cal-1", "user-1", []ExportEvent{
+		{Name: "Midwinter", Year: 1, Month: 1, Day: 1},
+		{Name: "No month", Year: 1, Day: 1},
+		{Name: "Greengrass", Year: 1, Month: 4, Day: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Created != 2 || created != 2 {
+		t.Fatalf("Created = %d (repo saw %d), want 2", res.Created, created)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Row != 2 || res.Errors[0].Name != "No month" {
+		t.Fatalf("Errors = %+v, want row 2 rejected", res.Errors)
+	}
+}
+
+func TestBulkCreateEvents_Limits(t *testing.T) {
+	svc := newTestCalendarService(&mockCalendarRepo{})
+
+	_, err := svc.BulkCreateEvents(context.Background(), "cal-1", "user-1", nil)
+	assertAppError(t, err, 422)
+
+	tooMany := make([]ExportEvent, maxBulkEvents+1)
+	for i := range tooMany {
+		tooMany[i] = ExportEvent{Name: fmt.Sprintf("E%d", i), Year: 1, Month: 1, Day: 1}
+	}
+	_, err = svc.BulkCreateEvents(context.Background(), "cal-1", "user-1", tooMany)
+	assertAppError(t, err, 422)
+}
+
+func TestImportEvents_SkipsExisting(t *testing.T) {
+	var names []string
+	repo := &mockCalendarRepo{
+		listAllEventsFn: func(_ context.Context, _ string) ([]Event, error) {
+			return []Event{{Name: "Midwinter", Year: 1, Month: 1, Day: 31}}, nil
+		},
+		createEventFn: func(_ context.Context, evt *Event) error {
+			names = append(names, evt.Name)
+			return nil
+		},
+	}
+	n, err := newTestCalendarService(repo).ImportEvents(context.Background(), "cal-1", "user-1", []ExportEvent{
+		{Name: "Midwinter", Year: 1, Month: 1, Day: 31},
+		{Name: "Greengrass", Year: 1, Month: 4, Day: 31},
+		{Name: "Greengrass", Year: 1, Month: 4, Day: 31},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(names) != 1 || names[0] != "Greengrass" {
+		t.Fatalf("created %v (n=%d), want only one Greengrass", names, n)
+	}
+}
+
+func TestParseCalendaria_FestivalsBecomeYearlyEvents(t *testing.T) {
+	data := []byte(`{
+		"name": "Harptos",
+		"years": {"yearZero": 0},
+		"days": {"hoursPerDay": 24, "values": {"a": {"name": "First", "ordinal": 1}}},
+		"months": {
+			"hammer": {"name": "Hammer", "days": 30, "ordinal": 1},
+			"alturiak": {"name": "Alturiak", "days": 30, "ordinal": 2}
+		},
+		"festivals": {
+			"greengrass": {"name": "Greengrass", "month": 2, "day": 30, "color": "#00ff00", "description": "Spring"},
+			"midwinter": {"name": "CALENDARIA.Festival.Midwinter", "month": 1, "day": 15},
+			"bogus": {"name": "Off the calendar", "month": 3, "day": 1}
+		}
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Events) != 2 {
+		t.Fatalf("got %d events, want 2 (out-of-range festival skipped)", len(result.Events))
+	}
+	first, second := result.Events[0], result.Events[1]
+	if first.Name != "Midwinter" || first.Month != 1 || second.Name != "Greengrass" {
+		t.Errorf("events not sorted by date or names not stripped: %q, %q", first.Name, second.Name)
+	}
+	for _, evt := range result.Events {
+		if !evt.IsRecurring || evt.RecurrenceType == nil || *evt.RecurrenceType != RecurrenceYearly || !evt.AllDay {
+			t.Errorf("%s: want an all-day yearly recurring event, got %+v", evt.Name, evt)
+		}
+	}
+	if second.Color == nil || *second.Color != "#00ff00" || second.Description == nil {
+		t.Errorf("festival color/description not carried: %+v", second)
+	}
+}
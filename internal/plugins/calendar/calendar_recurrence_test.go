@@ -72,6 +72,15 @@ func TestEventOccursOn(t *testing.T) {
 		{"monthly day30 skips short month", recurEvent(RecurrenceMonthly, 1, 1, 30), 1, 2, 30, false},
 		{"monthly day29 in leap month", recurEvent(RecurrenceMonthly, 4, 1, 29), 4, 2, 29, true},
 		{"monthly day29 in non-leap month (no)", recurEvent(RecurrenceMonthly, 1, 1, 29), 1, 2, 29, false},
+
+		// Yearly: same month + day each year, forward from the base year.
+		{"yearly base", recurEvent(RecurrenceYearly, 1, 6, 10), 1, 6, 10, true},
+		{"yearly +5y", recurEvent(RecurrenceYearly, 1, 6, 10), 6, 6, 10, true},
+		{"yearly other month (no)", recurEvent(RecurrenceYearly, 1, 6, 10), 2, 7, 10, false},
+		{"yearly before base (no)", recurEvent(RecurrenceYearly, 5, 6, 10), 4, 6, 10, false},
+		// A leap-day festival only lands in leap years.
+		{"yearly leap day in leap year", recurEvent(RecurrenceYearly, 4, 2, 29), 8, 2, 29, true},
+		{"yearly leap day in non-leap year (no)", recurEvent(RecurrenceYearly, 4, 2, 29), 9, 2, 29, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -116,6 +125,16 @@ func TestEventOccursOn_EndAndMax(t *testing.T) {
 	if ev2.OccursOn(cal, 1, 1, 15) {
 		t.Errorf("the third occurrence must be capped by max occurrences")
 	}
+
+	// Yearly max occurrences count years from the base.
+	ev3 := recurEvent(RecurrenceYearly, 10, 3, 3)
+	ev3.RecurrenceMaxOccurrences = ptr(3)
+	if !ev3.OccursOn(cal, 12, 3, 3) {
+		t.Errorf("the third yearly occurrence should land")
+	}
+	if ev3.OccursOn(cal, 13, 3, 3) {
+		t.Errorf("the fourth yearly occurrence must be capped by max occurrences")
+	}
 }
 
 // TestRecurrenceMigration_AddsAndDropsColumn pins the default-safe migration:
@@ -35,16 +35,19 @@ func TestV2Root_CarriesDateContext(t *testing.T) {
 	}
 }
 
-// TestShellJS_HasWorldStatePeekWiring: the popover fetches the #401 seed for
-// the clicked day and renders moon/weather/celestial — no new endpoint.
+// TestShellJS_HasWorldStatePeekWiring: the popover fetches the day-detail
+// aggregate for the clicked day and renders moon/weather/celestial plus
+// anniversaries, with a link to the day page.
 func TestShellJS_HasWorldStatePeekWiring(t *testing.T) {
 	js := readRepoFile(t, "internal/plugins/calendar/static/js/calendar_v2_shell.js")
 	for _, marker := range []string{
 		"renderWorldStatePeek",            // the peek entry point
-		"/calendar/world-state?year=",     // reuses the existing #401 GET seed
+		"/calendar/day?year=",             // the day-detail aggregate
 		"data-day-popover-worldstate",     // target container
-		"m.namedPhase",                    // renders the moon phase label
-		"ws.weather.type",                 // renders weather
+		"m.named_phase",                   // renders the moon phase label
+		"dd.weather.type",                 // renders weather
+		"dd.anniversaries",                // renders entity anniversaries
+		"renderPopoverRows(list, dd.events)", // recurrence-expanded events
 	} {
 		if !strings.Contains(js, marker) {
 			t.Errorf("shell JS missing worldState-peek wiring: %q", marker)
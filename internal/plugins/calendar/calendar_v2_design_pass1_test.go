@@ -115,6 +115,7 @@ func TestCommandBar_NavLabelsTrackView(t *testing.T) {
 		"week":   "week",
 		"day":    "day",
 		"ledger": "year",
+		"year":   "year",
 	}
 	for view, unit := range cases {
 		html := renderHeader(t, designPass1Data(view, nil))
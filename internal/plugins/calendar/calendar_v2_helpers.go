@@ -91,6 +91,10 @@ func v2Step(data CalendarV2ViewData, dir int) (int, int, int) {
 		// guard (§T-B2).
 		year += dir
 		return year, month, day
+	case "year":
+		// The Year overview steps by whole years too; cursor preserved.
+		year += dir
+		return year, month, day
 	case "month":
 		month += dir
 		if month < 1 {
@@ -166,23 +170,30 @@ func v2PeriodHeading(data CalendarV2ViewData) string {
 		return dayHeading(data)
 	case "ledger":
 		return ledgerHeading(data)
+	case "year":
+		return yearHeading(data)
 	default:
 		return monthHeading(data)
 	}
 }
 
 // v2PeriodSubLabel is the command bar's secondary "fantasy year line": the era
-// that contains the displayed year (e.g. "Age of the Broken Lantern"), or ""
-// when the calendar defines no eras. Pure flavor — the primary label already
-// carries the machine-readable month + year.
+// that contains the displayed year and the year's names (e.g. "Age of the
+// Broken Lantern · Year of the Shattered Crown"), or "" when nothing names
+// it. Pure flavor — the primary label already carries the machine-readable
+// month + year.
 func v2PeriodSubLabel(data CalendarV2ViewData) string {
 	if data.ActiveCalendar == nil {
 		return ""
 	}
+	var parts []string
 	if era := data.ActiveCalendar.EraForYear(data.Year); era != nil {
-		return era.Name
+		parts = append(parts, era.Name)
 	}
-	return ""
+	if label := data.ActiveCalendar.YearLabel(data.Year); label != "" {
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, " · ")
 }
 
 // v2NavUnit is the per-view period noun ("month"/"week"/"day"/"year") used to
@@ -193,9 +204,9 @@ func v2NavUnit(data CalendarV2ViewData) string {
 		return "week"
 	case "day":
 		return "day"
-	case "ledger":
-		// The Ledger steps by whole years (v2Step "ledger"); its shortcut hook
-		// is 'Previous year'/'Next year'.
+	case "ledger", "year":
+		// The Ledger and Year overview step by whole years (v2Step); their
+		// shortcut hook is 'Previous year'/'Next year'.
 		return "year"
 	default:
 		return "month"
@@ -409,6 +420,11 @@ func v2WeekdayIndexFor(cal *Calendar, year, month, day int) int {
 	if cal.UsesRealTime() {
 		return realTimeWeekdayIndex(cal, year, month, day, wl)
 	}
+	// Days of an intercalary month outside the week have no weekday: -1 is
+	// already out of range for every caller's Weekdays[idx] guard.
+	if cal.outsideWeek(month) {
+		return -1
+	}
 	idx := cal.constLenDayIndex(year, month, day) % wl
 	if idx < 0 {
 		idx += wl
@@ -452,7 +468,9 @@ func realTimeWeekdayIndex(cal *Calendar, year, month, day, wl int) int {
 // (monthRibbonRows), and the era bands (monthEraBands) all consume, so every
 // layer aligns to the same columns.
 func v2MonthLeadOffset(data CalendarV2ViewData) int {
-	return v2WeekdayIndex(data, 1)
+	// A month outside the week has no weekday columns to align to; its days
+	// flow from the first column.
+	return max(v2WeekdayIndex(data, 1), 0)
 }
 
 // monthDayFor builds the monthDay struct for a specific day-of-month,
@@ -0,0 +1,205 @@
+// calendar_v2_year.go — server-side assembly for the Year overview, the
+// year-at-a-glance V2 view: every month of the displayed year as a compact
+// mini-grid with per-day event-density shading and a season band along each
+// month's top edge. Unlike the Ledger (a chronology of rows), this is a grid
+// for spotting busy stretches and jumping to a day; each cell links to the
+// Day view.
+//
+// The event set is the same one-year window the Ledger loads (see ShowV2), so
+// dm_only rows are already absent for players and density never leaks them.
+
+package calendar
+
+import (
+	"fmt"
+
+	"github.com/a-h/templ"
+)
+
+// yearDensityLevels is how many non-zero density steps a day cell can show.
+// Counts at or above the top step render the same darkest shade, so one
+// festival day with twenty entries doesn't wash out the rest of the year.
+const yearDensityLevels = 3
+
+// yearDayCell is one day in a Year-overview mini-month.
+type yearDayCell struct {
+	Day     int
+	Count   int // events occurring on the day (multi-day spans count each day)
+	Density int // 0 (none) … yearDensityLevels
+	IsToday bool
+}
+
+// yearSeasonBand is a run of consecutive days in one month sharing a season.
+// Name/Color are empty for days no season covers (the band leaves a gap).
+type yearSeasonBand struct {
+	Name  string
+	Color string
+	Days  int
+}
+
+// yearMonthData is one mini-month of the Year overview.
+type yearMonthData struct {
+	Index  int // 1-based month number
+	Name   string
+	Days   []yearDayCell
+	Bands  []yearSeasonBand
+	Events int // total occurrences in the month
+}
+
+// yearOverviewMonths assembles the Year overview for the displayed year.
+// Leap-aware via MonthDays, like the Ledger's window.
+func yearOverviewMonths(data CalendarV2ViewData) []yearMonthData {
+	cal := data.ActiveCalendar
+	if cal == nil {
+		return nil
+	}
+	out := make([]yearMonthData, 0, len(cal.Months))
+	for m := 1; m <= len(cal.Months); m++ {
+		month := yearMonthData{Index: m, Name: cal.Months[m-1].Name}
+		dim := cal.MonthDays(m-1, data.Year)
+		for d := 1; d <= dim; d++ {
+			n := yearEventCount(cal, data.Events, data.Year, m, d)
+			month.Days = append(month.Days, yearDayCell{
+				Day:     d,
+				Count:   n,
+				Density: yearDensity(n),
+				IsToday: data.Year == data.TodayYear && m == data.TodayMonth && d == data.TodayDay,
+			})
+			month.Events += n
+			month.Bands = appendSeasonDay(month.Bands, cal.SeasonForDate(m, d))
+		}
+		out = append(out, month)
+	}
+	return out
+}
+
+// yearEventCount counts the events on one day: single-day and recurring
+// events through OccursOn (the shared recurrence predicate), multi-day
+// events on every day their span covers.
+func yearEventCount(cal *Calendar, events []Event, year, month, day int) int {
+	n := 0
+	for _, e := range events {
+		if isMultiDayEvent(e) {
+			if spanCoversDay(e, year, month, day) {
+				n++
+			}
+			continue
+		}
+		if e.OccursOn(cal, year, month, day) {
+			n++
+		}
+	}
+	return n
+}
+
+// spanCoversDay reports whether a multi-day event's start…end range includes
+// the date. Compares (year, month, day) tuples directly — no absolute-day
+// arithmetic, which walks every year from 0.
+func spanCoversDay(e Event, year, month, day int) bool {
+	endYear, endMonth, endDay := e.Year, e.Month, e.Day
+	if e.EndYear != nil {
+		endYear = *e.EndYear
+	}
+	if e.EndMonth != nil {
+		endMonth = *e.EndMonth
+	}
+	if e.EndDay != nil {
+		endDay = *e.EndDay
+	}
+	return !dateBefore(year, month, day, e.Year, e.Month, e.Day) &&
+		!dateBefore(endYear, endMonth, endDay, year, month, day)
+}
+
+// dateBefore reports whether (y1, m1, d1) is strictly earlier than (y2, m2, d2).
+func dateBefore(y1, m1, d1, y2, m2, d2 int) bool {
+	if y1 != y2 {
+		return y1 < y2
+	}
+	if m1 != m2 {
+		return m1 < m2
+	}
+	return d1 < d2
+}
+
+// yearDensity buckets a day's event count into a density step.
+func yearDensity(n int) int {
+	if n > yearDensityLevels {
+		return yearDensityLevels
+	}
+	return n
+}
+
+// appendSeasonDay extends the trailing band when the day shares its season,
+// otherwise starts a new one.
+func appendSeasonDay(bands []yearSeasonBand, s *Season) []yearSeasonBand {
+	name, color := "", ""
+	if s != nil {
+		name, color = s.Name, s.Color
+	}
+	if n := len(bands); n > 0 && bands[n-1].Name == name {
+		bands[n-1].Days++
+		return bands
+	}
+	return append(bands, yearSeasonBand{Name: name, Color: color, Days: 1})
+}
+
+// yearHeading is the Year overview's command-bar label (e.g. "1492 DR").
+func yearHeading(data CalendarV2ViewData) string {
+	return ledgerYearLabel(data)
+}
+
+// yearSeasonBandStyle sizes a band segment by its day count (flex-grow) and
+// tints it with the season color; uncovered runs stay transparent.
+func yearSeasonBandStyle(b yearSeasonBand) string {
+	style := fmt.Sprintf("flex-grow: %d;", b.Days)
+	if b.Color != "" {
+		style += " background-color: " + b.Color + ";"
+	}
+	return style
+}
+
+// yearDayClasses styles a mini-month cell by density, with the today ring.
+// Density shades run through the accent token so they follow the theme.
+func yearDayClasses(d yearDayCell) string {
+	base := "aspect-square grid place-items-center rounded text-[10px] tabular-nums transition-colors duration-micro hover:ring-1 hover:ring-accent"
+	switch d.Density {
+	case 0:
+		base += " text-fg-secondary"
+	case 1:
+		base += " bg-accent/20 text-fg"
+	case 2:
+		base += " bg-accent/45 text-fg"
+	default:
+		base += " bg-accent/75 text-white font-semibold"
+	}
+	if d.IsToday {
+		base += " ring-2 ring-accent"
+	}
+	return base
+}
+
+// yearDayTitle is a cell's tooltip: "Mirtul 5 — 2 events".
+func yearDayTitle(cal *Calendar, month int, d yearDayCell) string {
+	label := ledgerDayLabel(cal, month, d.Day)
+	switch d.Count {
+	case 0:
+		return label
+	case 1:
+		return label + " — 1 event"
+	default:
+		return fmt.Sprintf("%s — %d events", label, d.Count)
+	}
+}
+
+// yearDayHref links a cell to the Day view for that date.
+func yearDayHref(data CalendarV2ViewData, month, day int) templ.SafeURL {
+	return templ.SafeURL(fmt.Sprintf("/campaigns/%s/calendar/v2/%s/day?year=%d&month=%d&day=%d",
+		data.CampaignID, data.ActiveCalendar.ID, data.Year, month, day))
+}
+
+// v2JumpAction is the date-jump form's GET target: the active view's route,
+// so jumping keeps the view and only moves the cursor.
+func v2JumpAction(data CalendarV2ViewData) templ.SafeURL {
+	return templ.SafeURL(fmt.Sprintf("/campaigns/%s/calendar/v2/%s/%s",
+		data.CampaignID, data.ActiveCalendar.ID, data.View))
+}
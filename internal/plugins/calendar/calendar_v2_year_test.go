@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestYearOverviewMonths_DensityAndSeasons(t *testing.T) {
+	endMonth, endDay := 2, 2
+	span := allDayEvent("ev-span", "Siege", 29)
+	span.EndMonth, span.EndDay = &endMonth, &endDay
+	data := designPass1Data("year", []Event{
+		allDayEvent("ev-1", "Fair", 5),
+		allDayEvent("ev-2", "Feast", 5),
+		span,
+	})
+	data.TodayYear, data.TodayMonth, data.TodayDay = 1492, 1, 13
+	data.ActiveCalendar.Seasons = []Season{
+		{Name: "Autumn", StartMonth: 1, StartDay: 1, EndMonth: 1, EndDay: 20, Color: "#c2410c"},
+		{Name: "Winter", StartMonth: 1, StartDay: 21, EndMonth: 2, EndDay: 30, Color: "#60a5fa"},
+	}
+
+	months := yearOverviewMonths(data)
+	if len(months) != 2 || len(months[0].Days) != 30 {
+		t.Fatalf("got %d months; want 2 × 30 days", len(months))
+	}
+	first, second := months[0], months[1]
+	if first.Days[4].Count != 2 || first.Days[4].Density != 2 {
+		t.Errorf("day 5 = %+v; want 2 events at density 2", first.Days[4])
+	}
+	// The span covers Harvestwane 29–30 and Frostmere 1–2.
+	if first.Days[28].Count != 1 || first.Days[29].Count != 1 || second.Days[1].Count != 1 || second.Days[2].Count != 0 {
+		t.Errorf("multi-day span counted wrong: %d %d %d %d",
+			first.Days[28].Count, first.Days[29].Count, second.Days[1].Count, second.Days[2].Count)
+	}
+	if first.Events != 4 {
+		t.Errorf("month event total = %d; want 4", first.Events)
+	}
+	if !first.Days[12].IsToday || first.Days[11].IsToday {
+		t.Error("only day 13 should be marked today")
+	}
+	if len(first.Bands) != 2 || first.Bands[0].Name != "Autumn" || first.Bands[0].Days != 20 || first.Bands[1].Days != 10 {
+		t.Errorf("season bands = %+v; want Autumn ×20 then Winter ×10", first.Bands)
+	}
+}
+
+func TestYearDensity_CapsAtTopLevel(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 1: 1, 3: 3, 20: yearDensityLevels} {
+		if got := yearDensity(n); got != want {
+			t.Errorf("yearDensity(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestYearView_RendersMonthsAndJump(t *testing.T) {
+	data := designPass1Data("year", []Event{allDayEvent("ev-1", "Fair", 5)})
+	var sb strings.Builder
+	if err := calendarV2View(nil, data).Render(context.Background(), &sb); err != nil {
+		t.Fatalf("render year view: %v", err)
+	}
+	html := sb.String()
+	for _, want := range []string{`data-year-view="true"`, `data-year-month="2"`, "Frostmere",
+		`/calendar/v2/cal-1/day?year=1492&amp;month=1&amp;day=5`, `title="Harvestwane 5 — 1 event"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("year view missing %q", want)
+		}
+	}
+
+	header := renderHeader(t, data)
+	for _, want := range []string{`data-date-jump="true"`, `action="/campaigns/camp-1/calendar/v2/cal-1/year"`, `name="month"`, "1492 of the Broken Lantern"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("command bar missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// climate_regions.go — regional climate (migration 019). A calendar's
+// weather zones double as climate regions: seasons can be scoped to a
+// region, each region can carry its own current weather, and location
+// entities are assigned to a region. A northern city and a southern desert
+// then show different current seasons from the same calendar date.
+//
+// Resolution rules (shared by the internal UI and the sync API):
+//   - a location with no assignment inherits its nearest assigned ancestor's
+//     region (entities.parent_id), else it has no region;
+//   - a region's seasons replace the calendar-wide set when it defines any;
+//   - a region's weather falls back to the calendar-wide weather.
+package calendar
+
+// maxLocationAncestry bounds the parent walk when resolving an inherited
+// region. Real location trees are a handful of levels deep (world →
+// continent → realm → city → district); the cap also stops a corrupt
+// parent cycle.
+const maxLocationAncestry = 16
+
+// LocationZone assigns a location entity to a climate region.
+// EntityName is read-only (joined for display) and ignored on write.
+type LocationZone struct {
+	EntityID   string `json:"entity_id"`
+	EntityName string `json:"entity_name,omitempty"`
+	ZoneID     string `json:"zone_id"`
+}
+
+// RegionClimate is one region's climate on the calendar's current date.
+// Weather is the region's own row when set, else the calendar-wide weather
+// (RegionalWeather reports which).
+type RegionClimate struct {
+	ZoneID          string   `json:"zone_id"`
+	Name            string   `json:"name"`
+	Season          *Season  `json:"season,omitempty"`
+	Weather         *Weather `json:"weather,omitempty"`
+	RegionalWeather bool     `json:"regional_weather"`
+	Locations       []string `json:"locations"`
+}
+
+// LocationClimate is the climate at one location entity. ZoneID is "" when
+// neither the entity nor any ancestor is assigned a region; Season and
+// Weather are then the calendar-wide ones. InheritedFrom names the ancestor
+// whose assignment applied, nil for a direct assignment.
+type LocationClimate struct {
+	EntityID        string   `json:"entity_id"`
+	ZoneID          string   `json:"zone_id,omitempty"`
+	ZoneName        string   `json:"zone_name,omitempty"`
+	InheritedFrom   *string  `json:"inherited_from,omitempty"`
+	Season          *Season  `json:"season,omitempty"`
+	Weather         *Weather `json:"weather,omitempty"`
+	RegionalWeather bool     `json:"regional_weather"`
+}
+
+// resolveLocationZone picks the region for a location from its ancestry
+// (the entity first, then parents nearest-first). Returns the zone id and
+// the entity whose assignment matched, or "" when none did.
+func resolveLocationZone(ancestry []string, assigned map[string]string) (zoneID, via string) {
+	for _, id := range ancestry {
+		if z, ok := assigned[id]; ok {
+			return z, id
+		}
+	}
+	return "", ""
+}
+
+// weatherForZone picks a region's weather: its own row when present,
+// otherwise the calendar-wide state.
+func weatherForZone(zoneID string, regional map[string]*Weather, calendarWide *Weather) (*Weather, bool) {
+	if w, ok := regional[zoneID]; ok && zoneID != "" {
+		return w, true
+	}
+	return calendarWide, false
+}
@@ -0,0 +1,77 @@
+// climate_regions_handler.go — HTTP surface for regional climate: the
+// location → region assignments and per-region current weather. Thin: the
+// service validates zones and entities.
+package calendar
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// GetLocationZonesAPI returns the calendar's location assignments.
+// GET /campaigns/:id/calendars/:calId/location-zones
+func (h *Handler) GetLocationZonesAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireVisibleCalendar(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+	zones, err := h.svc.ListLocationZones(c.Request().Context(), cal.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+	if zones == nil {
+		zones = []LocationZone{}
+	}
+	return c.JSON(http.StatusOK, zones)
+}
+
+// UpdateLocationZonesAPI replaces the calendar's location assignments.
+// PUT /campaigns/:id/calendars/:calId/location-zones
+func (h *Handler) UpdateLocationZonesAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+
+	var assignments []LocationZone
+	if err := c.Bind(&assignments); err != nil {
+		return respondSettingsError(c, apperror.NewBadRequest("invalid location zones payload"))
+	}
+
+	if err := h.svc.SetLocationZones(c.Request().Context(), cal.ID, assignments); err != nil {
+		return respondSettingsError(c, err)
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarLocationZonesSet, "calendar", cal.ID, cal.Name,
+		map[string]any{"count": len(assignments)})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateZoneWeatherAPI sets one region's current weather.
+// PUT /campaigns/:id/calendars/:calId/weather/zones/:zoneId/current
+func (h *Handler) UpdateZoneWeatherAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+
+	var input WeatherInput
+	if err := c.Bind(&input); err != nil {
+		return respondSettingsError(c, apperror.NewBadRequest("invalid weather payload"))
+	}
+
+	zoneID := c.Param("zoneId")
+	if err := h.svc.SetZoneWeather(c.Request().Context(), cal.ID, zoneID, input); err != nil {
+		return respondSettingsError(c, err)
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarZoneWeatherSet, "calendar", cal.ID, cal.Name,
+		map[string]any{"zone_id": zoneID})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,219 @@
+// climate_regions_repository.go — MariaDB reads/writes for regional climate
+// (calendar_zone_weather + calendar_location_zones, migration 019).
+// Hand-written SQL per the conventions; rows cascade away with their
+// calendar, and location rows with their entity.
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetZoneWeather returns every region's current weather row for a
+// calendar, ZoneID set on each, ordered by zone id.
+func (r *calendarRepo) GetZoneWeather(ctx context.Context, calendarID string) ([]Weather, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT calendar_id, zone_id, preset_id, preset_label, icon, color,
+		        temperature_celsius, wind_speed_kph, wind_speed_tier,
+		        wind_direction, wind_direction_degrees,
+		        precipitation_type, precipitation_intensity,
+		        description, updated_at
+		 FROM calendar_zone_weather WHERE calendar_id = ? ORDER BY zone_id`,
+		calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Weather
+	for rows.Next() {
+		var w Weather
+		var zoneID string
+		var ws weatherScan
+		if err := rows.Scan(&w.CalendarID, &zoneID, &w.PresetID, &w.PresetLabel, &w.Icon, &w.Color,
+			&w.TemperatureCelsius, &ws.windSpeedKPH, &ws.windSpeedTier,
+			&ws.windDir, &ws.windDirDeg,
+			&ws.precipType, &ws.precipIntensity,
+			&w.Description, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		w.ZoneID = &zoneID
+		ws.apply(&w)
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// SetZoneWeather upserts one region's current weather. The input's
+// zone_id/zone_name fields are ignored — the region is the key.
+func (r *calendarRepo) SetZoneWeather(ctx context.Context, calendarID, zoneID string, input WeatherInput) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO calendar_zone_weather (calendar_id, zone_id, preset_id, preset_label, icon, color,
+		        temperature_celsius, wind_speed_kph, wind_speed_tier,
+		        wind_direction, wind_direction_degrees,
+		        precipitation_type, precipitation_intensity, description)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE
+		        preset_id = VALUES(preset_id), preset_label = VALUES(preset_label),
+		        icon = VALUES(icon), color = VALUES(color),
+		        temperature_celsius = VALUES(temperature_celsius),
+		        wind_speed_kph = VALUES(wind_speed_kph), wind_speed_tier = VALUES(wind_speed_tier),
+		        wind_direction = VALUES(wind_direction), wind_direction_degrees = VALUES(wind_direction_degrees),
+		        precipitation_type = VALUES(precipitation_type), precipitation_intensity = VALUES(precipitation_intensity),
+		        description = VALUES(description)`,
+		calendarID, zoneID, input.PresetID, input.PresetLabel, input.Icon, input.Color,
+		input.TemperatureCelsius, input.WindSpeedKPH, input.WindSpeedTier,
+		input.WindDirection, input.WindDirectionDeg,
+		input.PrecipitationType, input.PrecipitationIntensity, input.Description,
+	)
+	return err
+}
+
+// ListLocationZones returns a calendar's location assignments ordered by
+// entity name.
+func (r *calendarRepo) ListLocationZones(ctx context.Context, calendarID string) ([]LocationZone, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT lz.entity_id, e.name, lz.zone_id
+		 FROM calendar_location_zones lz
+		 JOIN entities e ON e.id = lz.entity_id
+		 WHERE lz.calendar_id = ?
+		 ORDER BY e.name, lz.entity_id`,
+		calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []LocationZone
+	for rows.Next() {
+		var lz LocationZone
+		if err := rows.Scan(&lz.EntityID, &lz.EntityName, &lz.ZoneID); err != nil {
+			return nil, err
+		}
+		out = append(out, lz)
+	}
+	return out, rows.Err()
+}
+
+// SetLocationZones replaces a calendar's location assignments in one
+// transaction (delete-then-insert, like SetSeasons). Validation runs at the
+// service layer.
+func (r *calendarRepo) SetLocationZones(ctx context.Context, calendarID string, assignments []LocationZone) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM calendar_location_zones WHERE calendar_id = ?`, calendarID); err != nil {
+		return fmt.Errorf("delete location zones: %w", err)
+	}
+	for _, a := range assignments {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO calendar_location_zones (calendar_id, entity_id, zone_id) VALUES (?, ?, ?)`,
+			calendarID, a.EntityID, a.ZoneID); err != nil {
+			return fmt.Errorf("insert location zone %q: %w", a.EntityID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// LocationAncestry returns the entity followed by its parents, nearest
+// first, up to maxLocationAncestry entries. A missing entity yields an
+// empty slice; a parent cycle stops at the first repeat.
+func (r *calendarRepo) LocationAncestry(ctx context.Context, entityID string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool)
+	id := entityID
+	for len(out) < maxLocationAncestry && !seen[id] {
+		var parent sql.NullString
+		err := r.db.QueryRowContext(ctx,
+			`SELECT parent_id FROM entities WHERE id = ?`, id).Scan(&parent)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+		seen[id] = true
+		if !parent.Valid || parent.String == "" {
+			break
+		}
+		id = parent.String
+	}
+	return out, nil
+}
+
+// EntitiesOutsideCampaign returns the ids from entityIDs that don't name an
+// entity in the campaign (missing or foreign). Empty input is a no-op.
+func (r *calendarRepo) EntitiesOutsideCampaign(ctx context.Context, campaignID string, entityIDs []string) ([]string, error) {
+	if len(entityIDs) == 0 {
+		return nil, nil
+	}
+	ph := make([]string, len(entityIDs))
+	args := make([]any, 0, len(entityIDs)+1)
+	args = append(args, campaignID)
+	for i, id := range entityIDs {
+		ph[i] = "?"
+		args = append(args, id)
+	}
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT id FROM entities WHERE campaign_id = ? AND id IN (%s)`,
+		strings.Join(ph, ",")), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool, len(entityIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		found[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, id := range entityIDs {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// pruneDroppedZones clears regional state for zones no longer in the
+// catalog, inside ApplyWeatherZones' transaction: their weather rows,
+// location assignments and seasons go with them. Seasons are deleted rather
+// than demoted to calendar-wide — folding a desert's seasons into the
+// calendar-wide set would double up every season name.
+func pruneDroppedZones(ctx context.Context, tx *sql.Tx, calendarID string, zones []WeatherZone) error {
+	keep := ""
+	args := []any{calendarID}
+	if len(zones) > 0 {
+		ph := make([]string, len(zones))
+		for i, z := range zones {
+			ph[i] = "?"
+			args = append(args, z.ZoneID)
+		}
+		keep = fmt.Sprintf(" AND zone_id NOT IN (%s)", strings.Join(ph, ","))
+	}
+	for _, q := range []string{
+		`DELETE FROM calendar_zone_weather WHERE calendar_id = ?` + keep,
+		`DELETE FROM calendar_location_zones WHERE calendar_id = ?` + keep,
+		`DELETE FROM calendar_seasons WHERE calendar_id = ? AND zone_id IS NOT NULL` + keep,
+	} {
+		if _, err := tx.ExecContext(ctx, q, args...); err != nil {
+			return fmt.Errorf("prune dropped zones: %w", err)
+		}
+	}
+	return nil
+}
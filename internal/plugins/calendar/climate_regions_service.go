@@ -0,0 +1,239 @@
+// climate_regions_service.go — service-layer logic for regional climate
+// (migration 019): location → region assignments, per-region weather, and
+// resolving the current season/weather for a region or a location.
+package calendar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// ListLocationZones returns a calendar's location assignments.
+func (s *calendarService) ListLocationZones(ctx context.Context, calendarID string) ([]LocationZone, error) {
+	return s.repo.ListLocationZones(ctx, calendarID)
+}
+
+// SetLocationZones replaces a calendar's location assignments. Every zone
+// must be in the calendar's catalog and every entity in its campaign; an
+// entity may appear once.
+func (s *calendarService) SetLocationZones(ctx context.Context, calendarID string, assignments []LocationZone) error {
+	cal, err := s.repo.GetByID(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if cal == nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+	zones, err := s.zoneNames(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(assignments))
+	ids := make([]string, 0, len(assignments))
+	for i, a := range assignments {
+		if a.EntityID == "" {
+			return apperror.NewValidation(fmt.Sprintf("assignment %d: entity_id is required", i+1))
+		}
+		if seen[a.EntityID] {
+			return apperror.NewValidation(fmt.Sprintf("assignment %d: entity %q is assigned twice", i+1, a.EntityID))
+		}
+		seen[a.EntityID] = true
+		if _, ok := zones[a.ZoneID]; !ok {
+			return apperror.NewValidation(fmt.Sprintf("assignment %d: zone %q is not a weather zone of this calendar", i+1, a.ZoneID))
+		}
+		ids = append(ids, a.EntityID)
+	}
+	missing, err := s.repo.EntitiesOutsideCampaign(ctx, cal.CampaignID, ids)
+	if err != nil {
+		return fmt.Errorf("check location entities: %w", err)
+	}
+	if len(missing) > 0 {
+		return apperror.NewValidation(fmt.Sprintf("entity %q is not in this campaign", missing[0]))
+	}
+	if err := s.repo.SetLocationZones(ctx, calendarID, assignments); err != nil {
+		return fmt.Errorf("set location zones: %w", err)
+	}
+	return nil
+}
+
+// SetZoneWeather sets one region's current weather, with the same
+// load-merge-write semantics as SetWeather (nil fields keep the region's
+// stored value). Publishes calendar.weather.changed with the zone id so
+// subscribers can tell regional from calendar-wide changes.
+func (s *calendarService) SetZoneWeather(ctx context.Context, calendarID, zoneID string, input WeatherInput) error {
+	zones, err := s.zoneNames(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	name, ok := zones[zoneID]
+	if !ok {
+		return apperror.NewNotFound(fmt.Sprintf("weather zone %q not found", zoneID))
+	}
+	regional, err := s.zoneWeather(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	merged := mergeWeatherInput(regional[zoneID], input)
+	merged.ZoneID, merged.ZoneName = &zoneID, &name
+	if err := s.repo.SetZoneWeather(ctx, calendarID, zoneID, merged); err != nil {
+		return fmt.Errorf("set zone weather: %w", err)
+	}
+	if cal, err := s.repo.GetByID(ctx, calendarID); err == nil && cal != nil {
+		s.events.PublishCalendarEvent("calendar.weather.changed", cal.CampaignID, calendarID, merged)
+	}
+	return nil
+}
+
+// RegionalClimate returns every region's season and weather on the
+// calendar's current date, with the locations assigned to it directly.
+func (s *calendarService) RegionalClimate(ctx context.Context, calendarID string) ([]RegionClimate, error) {
+	cal, err := s.GetCalendarByID(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if cal == nil {
+		return nil, apperror.NewNotFound("calendar not found")
+	}
+	zones, err := s.repo.GetWeatherZones(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load weather zones: %w", err)
+	}
+	regional, err := s.zoneWeather(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	wide, err := s.repo.GetWeather(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load weather: %w", err)
+	}
+	assignments, err := s.repo.ListLocationZones(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load location zones: %w", err)
+	}
+
+	out := make([]RegionClimate, 0, len(zones))
+	for _, z := range zones {
+		rc := RegionClimate{ZoneID: z.ZoneID, Name: z.Name, Locations: []string{}}
+		rc.Season = cal.SeasonForDateIn(z.ZoneID, cal.CurrentMonth, cal.CurrentDay)
+		rc.Weather, rc.RegionalWeather = weatherForZone(z.ZoneID, regional, wide)
+		for _, a := range assignments {
+			if a.ZoneID == z.ZoneID {
+				rc.Locations = append(rc.Locations, a.EntityID)
+			}
+		}
+		out = append(out, rc)
+	}
+	return out, nil
+}
+
+// ClimateForLocation resolves the season and weather at a location entity
+// on the calendar's current date. Unassigned locations inherit the nearest
+// assigned ancestor's region; with none, the calendar-wide climate applies.
+func (s *calendarService) ClimateForLocation(ctx context.Context, calendarID, entityID string) (*LocationClimate, error) {
+	cal, err := s.GetCalendarByID(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if cal == nil {
+		return nil, apperror.NewNotFound("calendar not found")
+	}
+	// Scope the entity to the calendar's campaign before walking parents —
+	// the ancestry query itself is campaign-blind.
+	missing, err := s.repo.EntitiesOutsideCampaign(ctx, cal.CampaignID, []string{entityID})
+	if err != nil {
+		return nil, fmt.Errorf("check location entity: %w", err)
+	}
+	ancestry, err := s.repo.LocationAncestry(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("load location ancestry: %w", err)
+	}
+	if len(missing) > 0 || len(ancestry) == 0 {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	assignments, err := s.repo.ListLocationZones(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load location zones: %w", err)
+	}
+	assigned := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		assigned[a.EntityID] = a.ZoneID
+	}
+
+	lc := &LocationClimate{EntityID: entityID}
+	zoneID, via := resolveLocationZone(ancestry, assigned)
+	if zoneID != "" {
+		zones, err := s.zoneNames(ctx, calendarID)
+		if err != nil {
+			return nil, err
+		}
+		lc.ZoneID, lc.ZoneName = zoneID, zones[zoneID]
+		if via != entityID {
+			lc.InheritedFrom = &via
+		}
+	}
+	regional, err := s.zoneWeather(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	wide, err := s.repo.GetWeather(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load weather: %w", err)
+	}
+	lc.Season = cal.SeasonForDateIn(zoneID, cal.CurrentMonth, cal.CurrentDay)
+	lc.Weather, lc.RegionalWeather = weatherForZone(zoneID, regional, wide)
+	return lc, nil
+}
+
+// zoneNames maps the calendar's zone ids to their display names.
+func (s *calendarService) zoneNames(ctx context.Context, calendarID string) (map[string]string, error) {
+	zones, err := s.repo.GetWeatherZones(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load weather zones: %w", err)
+	}
+	out := make(map[string]string, len(zones))
+	for _, z := range zones {
+		out[z.ZoneID] = z.Name
+	}
+	return out, nil
+}
+
+// zoneWeather indexes the calendar's regional weather rows by zone id.
+func (s *calendarService) zoneWeather(ctx context.Context, calendarID string) (map[string]*Weather, error) {
+	rows, err := s.repo.GetZoneWeather(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("load zone weather: %w", err)
+	}
+	out := make(map[string]*Weather, len(rows))
+	for i := range rows {
+		out[*rows[i].ZoneID] = &rows[i]
+	}
+	return out, nil
+}
+
+// validateSeasonZones normalizes blank season zone ids to calendar-wide and
+// checks the rest against the zone catalog. Only queries the catalog when a
+// season is actually region-scoped.
+func (s *calendarService) validateSeasonZones(ctx context.Context, calendarID string, seasons []Season) error {
+	scoped := false
+	for i := range seasons {
+		if seasons[i].ZoneID != nil && *seasons[i].ZoneID == "" {
+			seasons[i].ZoneID = nil
+		}
+		scoped = scoped || seasons[i].ZoneID != nil
+	}
+	if !scoped {
+		return nil
+	}
+	zones, err := s.zoneNames(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	for _, sn := range seasons {
+		if _, ok := zones[sn.Zone()]; sn.ZoneID != nil && !ok {
+			return apperror.NewValidation(fmt.Sprintf("season %q: zone %q is not a weather zone of this calendar", sn.Name, sn.Zone()))
+		}
+	}
+	return nil
+}
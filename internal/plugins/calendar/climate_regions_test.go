@@ -0,0 +1,233 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+)
+
+// regionalCalendar has a calendar-wide Summer/Winter split and a desert
+// region running Dry/Wet seasons of its own over a 12-month year.
+func regionalCalendar() *Calendar {
+	desert := "desert"
+	return &Calendar{
+		ID: "cal-1", CampaignID: "camp-1", CurrentYear: 1492, CurrentMonth: 7, CurrentDay: 10,
+		Seasons: []Season{
+			{Name: "Summer", StartMonth: 4, StartDay: 1, EndMonth: 9, EndDay: 30},
+			{Name: "Winter", StartMonth: 10, StartDay: 1, EndMonth: 3, EndDay: 31},
+			{Name: "Dry", StartMonth: 1, StartDay: 1, EndMonth: 8, EndDay: 31, ZoneID: &desert},
+			{Name: "Wet", StartMonth: 10, StartDay: 1, EndMonth: 12, EndDay: 31, ZoneID: &desert},
+		},
+	}
+}
+
+func TestSeasonForDateIn(t *testing.T) {
+	cal := regionalCalendar()
+	tests := []struct {
+		name       string
+		zone       string
+		month, day int
+		want       string // "" = no season
+	}{
+		{"calendar-wide", "", 7, 10, "Summer"},
+		{"region's own season", "desert", 7, 10, "Dry"},
+		{"region gap has no season", "desert", 9, 15, ""},
+		{"region without seasons falls back", "tundra", 11, 1, "Winter"},
+		{"calendar-wide ignores regional seasons", "", 11, 1, "Winter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if s := cal.SeasonForDateIn(tt.zone, tt.month, tt.day); s != nil {
+				got = s.Name
+			}
+			if got != tt.want {
+				t.Errorf("SeasonForDateIn(%q, %d, %d) = %q, want %q", tt.zone, tt.month, tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+// climateRepo wires a mock repo for the regional resolver: city-1 sits in
+// realm-1 (assigned to desert) inside world-1; outpost-1 is assigned tundra
+// directly. Only the desert has a weather row of its own.
+func climateRepo() *mockCalendarRepo {
+	cal := regionalCalendar()
+	sandstorm, clear := "sandstorm", "clear"
+	desert := "desert"
+	parents := map[string][]string{
+		"city-1":    {"city-1", "realm-1", "world-1"},
+		"outpost-1": {"outpost-1", "world-1"},
+		"world-1":   {"world-1"},
+	}
+	return &mockCalendarRepo{
+		getByIDFn: func(_ context.Context, _ string) (*Calendar, error) {
+			c := *cal
+			return &c, nil
+		},
+		getSeasonsFn: func(_ context.Context, _ string) ([]Season, error) { return cal.Seasons, nil },
+		getWeatherZonesFn: func(_ context.Context, _ string) ([]WeatherZone, error) {
+			return []WeatherZone{{ZoneID: "desert", Name: "Southern Desert"}, {ZoneID: "tundra", Name: "Northern Tundra"}}, nil
+		},
+		getWeatherFn: func(_ context.Context, _ string) (*Weather, error) {
+			return &Weather{PresetID: &clear}, nil
+		},
+		getZoneWeatherFn: func(_ context.Context, _ string) ([]Weather, error) {
+			return []Weather{{ZoneID: &desert, PresetID: &sandstorm}}, nil
+		},
+		listLocationZonesFn: func(_ context.Context, _ string) ([]LocationZone, error) {
+			return []LocationZone{{EntityID: "realm-1", ZoneID: "desert"}, {EntityID: "outpost-1", ZoneID: "tundra"}}, nil
+		},
+		locationAncestryFn: func(_ context.Context, id string) ([]string, error) {
+			return parents[id], nil
+		},
+	}
+}
+
+func TestClimateForLocation(t *testing.T) {
+	tests := []struct {
+		name          string
+		entity        string
+		wantZone      string
+		wantInherited string
+		wantSeason    string
+		wantPreset    string
+		wantRegional  bool
+	}{
+		{name: "inherits from an assigned ancestor", entity: "city-1", wantZone: "desert",
+			wantInherited: "realm-1", wantSeason: "Dry", wantPreset: "sandstorm", wantRegional: true},
+		{name: "direct assignment, calendar-wide fallbacks", entity: "outpost-1", wantZone: "tundra",
+			wantSeason: "Summer", wantPreset: "clear"},
+		{name: "unassigned location", entity: "world-1", wantSeason: "Summer", wantPreset: "clear"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc, err := newTestCalendarService(climateRepo()).ClimateForLocation(context.Background(), "cal-1", tt.entity)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lc.ZoneID != tt.wantZone {
+				t.Errorf("ZoneID = %q, want %q", lc.ZoneID, tt.wantZone)
+			}
+			inherited := ""
+			if lc.InheritedFrom != nil {
+				inherited = *lc.InheritedFrom
+			}
+			if inherited != tt.wantInherited {
+				t.Errorf("InheritedFrom = %q, want %q", inherited, tt.wantInherited)
+			}
+			if lc.Season == nil || lc.Season.Name != tt.wantSeason {
+				t.Errorf("Season = %+v, want %q", lc.Season, tt.wantSeason)
+			}
+			if lc.Weather == nil || *lc.Weather.PresetID != tt.wantPreset || lc.RegionalWeather != tt.wantRegional {
+				t.Errorf("Weather = %+v (regional %v), want %q (regional %v)", lc.Weather, lc.RegionalWeather, tt.wantPreset, tt.wantRegional)
+			}
+		})
+	}
+}
+
+func TestClimateForLocation_ForeignEntity(t *testing.T) {
+	repo := climateRepo()
+	repo.entitiesOutsideCampaignFn = func(_ context.Context, _ string, ids []string) ([]string, error) {
+		return ids, nil
+	}
+	_, err := newTestCalendarService(repo).ClimateForLocation(context.Background(), "cal-1", "city-1")
+	assertAppError(t, err, 404)
+}
+
+func TestRegionalClimate(t *testing.T) {
+	regions, err := newTestCalendarService(climateRepo()).RegionalClimate(context.Background(), "cal-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(regions) != 2 {
+		t.Fatalf("got %d regions, want 2", len(regions))
+	}
+	desert, tundra := regions[0], regions[1]
+	if desert.Season.Name != "Dry" || !desert.RegionalWeather || len(desert.Locations) != 1 || desert.Locations[0] != "realm-1" {
+		t.Errorf("desert = %+v; want Dry, regional weather, realm-1", desert)
+	}
+	if tundra.Season.Name != "Summer" || tundra.RegionalWeather {
+		t.Errorf("tundra = %+v; want calendar-wide Summer and weather", tundra)
+	}
+}
+
+func TestSetLocationZones_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []LocationZone
+		foreign  []string
+		wantCode int
+	}{
+		{name: "valid", in: []LocationZone{{EntityID: "realm-1", ZoneID: "desert"}, {EntityID: "outpost-1", ZoneID: "tundra"}}},
+		{name: "clear all", in: nil},
+		{name: "unknown zone", in: []LocationZone{{EntityID: "realm-1", ZoneID: "jungle"}}, wantCode: 422},
+		{name: "missing entity", in: []LocationZone{{ZoneID: "desert"}}, wantCode: 422},
+		{name: "entity twice", in: []LocationZone{{EntityID: "realm-1", ZoneID: "desert"}, {EntityID: "realm-1", ZoneID: "tundra"}}, wantCode: 422},
+		{name: "entity from another campaign", in: []LocationZone{{EntityID: "stranger", ZoneID: "desert"}}, foreign: []string{"stranger"}, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			repo := climateRepo()
+			repo.entitiesOutsideCampaignFn = func(_ context.Context, _ string, _ []string) ([]string, error) {
+				return tt.foreign, nil
+			}
+			repo.setLocationZonesFn = func(_ context.Context, _ string, _ []LocationZone) error {
+				saved = true
+				return nil
+			}
+			err := newTestCalendarService(repo).SetLocationZones(context.Background(), "cal-1", tt.in)
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				if saved {
+					t.Error("invalid assignments must not reach the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetSeasons_ZoneValidation(t *testing.T) {
+	blank, desert, jungle := "", "desert", "jungle"
+	tests := []struct {
+		name     string
+		zone     *string
+		wantCode int
+	}{
+		{name: "calendar-wide", zone: nil},
+		{name: "blank is calendar-wide", zone: &blank},
+		{name: "known region", zone: &desert},
+		{name: "unknown region", zone: &jungle, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []Season
+			repo := climateRepo()
+			repo.setSeasonsFn = func(_ context.Context, _ string, seasons []Season) error {
+				saved = seasons
+				return nil
+			}
+			err := newTestCalendarService(repo).SetSeasons(context.Background(), "cal-1",
+				[]Season{{Name: "Dry", StartMonth: 1, StartDay: 1, EndMonth: 6, EndDay: 30, ZoneID: tt.zone}})
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.zone != nil && *tt.zone == "" && saved[0].ZoneID != nil {
+				t.Error("blank zone_id should be stored as calendar-wide (nil)")
+			}
+		})
+	}
+}
+
+func TestSetZoneWeather_UnknownZone(t *testing.T) {
+	err := newTestCalendarService(climateRepo()).SetZoneWeather(context.Background(), "cal-1", "jungle", WeatherInput{})
+	assertAppError(t, err, 404)
+}
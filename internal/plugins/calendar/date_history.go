@@ -0,0 +1,177 @@
+// date_history.go — the historical log of clock advancements (migration
+// 016). Every AdvanceDate / AdvanceTime call records who moved the clock,
+// when, and from → to; the log backs the "campaign so far" timeline (real
+// play sessions vs in-game time elapsed) and the undo of the most recent
+// advancement.
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxDateHistory caps how many advancements the timeline loads. A long
+// campaign advances a handful of times per session, so this covers years of
+// weekly play; older rows stay in the table.
+const maxDateHistory = 1000
+
+// Advancement kinds: which advance path wrote the row.
+const (
+	AdvanceKindDate = "date" // AdvanceDate (whole days)
+	AdvanceKindTime = "time" // AdvanceTime (hours/minutes)
+)
+
+// DateAdvancement is one recorded clock move.
+type DateAdvancement struct {
+	ID         int     `json:"id"`
+	CalendarID string  `json:"calendar_id"`
+	UserID     *string `json:"user_id,omitempty"` // nil for token-authenticated sync writes
+	UserName   string  `json:"user_name,omitempty"`
+	Kind       string  `json:"kind"`
+
+	FromYear   int `json:"from_year"`
+	FromMonth  int `json:"from_month"`
+	FromDay    int `json:"from_day"`
+	FromHour   int `json:"from_hour"`
+	FromMinute int `json:"from_minute"`
+	ToYear     int `json:"to_year"`
+	ToMonth    int `json:"to_month"`
+	ToDay      int `json:"to_day"`
+	ToHour     int `json:"to_hour"`
+	ToMinute   int `json:"to_minute"`
+
+	// AdvancedMinutes is the move in the calendar's own minutes at write
+	// time, so elapsed totals are a sum rather than date arithmetic.
+	AdvancedMinutes int `json:"advanced_minutes"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	UndoneAt  *time.Time `json:"undone_at,omitempty"`
+}
+
+// FromStamp returns the pre-advance clock as "YYYY-MM-DD HH:MM".
+func (a DateAdvancement) FromStamp() string {
+	return formatClockStamp(a.FromYear, a.FromMonth, a.FromDay, a.FromHour, a.FromMinute)
+}
+
+// ToStamp returns the post-advance clock as "YYYY-MM-DD HH:MM".
+func (a DateAdvancement) ToStamp() string {
+	return formatClockStamp(a.ToYear, a.ToMonth, a.ToDay, a.ToHour, a.ToMinute)
+}
+
+// IsUndone reports whether the advancement was reverted.
+func (a DateAdvancement) IsUndone() bool {
+	return a.UndoneAt != nil
+}
+
+// endsAt reports whether the calendar's clock still sits where this
+// advancement left it — the precondition for undoing it.
+func (a DateAdvancement) endsAt(cal *Calendar) bool {
+	return cal.CurrentYear == a.ToYear && cal.CurrentMonth == a.ToMonth && cal.CurrentDay == a.ToDay &&
+		cal.CurrentHour == a.ToHour && cal.CurrentMinute == a.ToMinute
+}
+
+// formatClockStamp renders a calendar date + time as "YYYY-MM-DD HH:MM".
+func formatClockStamp(year, month, day, hour, minute int) string {
+	return fmt.Sprintf("%d-%02d-%02d %02d:%02d", year, month, day, hour, minute)
+}
+
+// PlaySession groups the advancements made on one real-world day — in
+// practice one session at the table.
+type PlaySession struct {
+	Date         string            // real-world day, "2006-01-02" (UTC)
+	Advancements []DateAdvancement // newest first, undone rows included
+	From, To     string            // in-game span covered (empty when every row was undone)
+	Elapsed      int               // in-game minutes advanced, undone rows excluded
+}
+
+// DateHistoryTimeline is the "campaign so far" view: play sessions newest
+// first plus the running in-game total.
+type DateHistoryTimeline struct {
+	Sessions     []PlaySession
+	TotalElapsed int // in-game minutes across every session
+	// LastUndoable is the most recent advancement that hasn't been undone,
+	// or nil when there is nothing to undo.
+	LastUndoable *DateAdvancement
+}
+
+// DateHistoryViewData feeds the "campaign so far" page.
+type DateHistoryViewData struct {
+	Calendar   *Calendar
+	CampaignID string
+	Timeline   DateHistoryTimeline
+	CanUndo    bool // Owner on a manually-advanced calendar
+	CSRFToken  string
+}
+
+// buildDateHistoryTimeline rolls a newest-first advancement list up into
+// per-day play sessions.
+func buildDateHistoryTimeline(history []DateAdvancement) DateHistoryTimeline {
+	var tl DateHistoryTimeline
+	for i := range history {
+		a := history[i]
+		day := a.CreatedAt.UTC().Format("2006-01-02")
+		if n := len(tl.Sessions); n == 0 || tl.Sessions[n-1].Date != day {
+			tl.Sessions = append(tl.Sessions, PlaySession{Date: day})
+		}
+		s := &tl.Sessions[len(tl.Sessions)-1]
+		s.Advancements = append(s.Advancements, a)
+		if a.IsUndone() {
+			continue
+		}
+		if tl.LastUndoable == nil {
+			tl.LastUndoable = &history[i]
+		}
+		// Newest first: the first live row sets To, each later one
+		// pushes From further back.
+		if s.To == "" {
+			s.To = a.ToStamp()
+		}
+		s.From = a.FromStamp()
+		s.Elapsed += a.AdvancedMinutes
+		tl.TotalElapsed += a.AdvancedMinutes
+	}
+	return tl
+}
+
+// advancedMinutes converts an advance into the calendar's minutes. Defaults
+// match AdvanceTime's for calendars with unset hours/minutes.
+func advancedMinutes(cal *Calendar, days, hours, minutes int) int {
+	hpd, mph := clockUnits(cal)
+	return (days*hpd+hours)*mph + minutes
+}
+
+// clockUnits returns the calendar's hours-per-day and minutes-per-hour,
+// defaulting to 24/60 when unset.
+func clockUnits(cal *Calendar) (int, int) {
+	hpd, mph := 24, 60
+	if cal != nil && cal.HoursPerDay > 0 {
+		hpd = cal.HoursPerDay
+	}
+	if cal != nil && cal.MinutesPerHour > 0 {
+		mph = cal.MinutesPerHour
+	}
+	return hpd, mph
+}
+
+// formatElapsed renders in-game minutes as "2d 8h 15m", dropping zero
+// units ("8h", "45m"); zero renders as "0m".
+func formatElapsed(cal *Calendar, minutes int) string {
+	hpd, mph := clockUnits(cal)
+	days := minutes / (hpd * mph)
+	minutes -= days * hpd * mph
+	hours := minutes / mph
+	minutes -= hours * mph
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
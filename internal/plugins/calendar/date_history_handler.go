@@ -0,0 +1,80 @@
+// date_history_handler.go — HTTP surface for the clock advancement log:
+// the JSON list, the "campaign so far" page, and undo of the most recent
+// advancement. Thin: the service owns the undo precondition.
+package calendar
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// DateHistoryAPI returns a calendar's advancements, newest first.
+// GET /campaigns/:id/calendars/:calId/date-history
+func (h *Handler) DateHistoryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireVisibleCalendar(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	history, err := h.svc.ListDateHistory(c.Request().Context(), cal.ID)
+	if err != nil {
+		return err
+	}
+	if history == nil {
+		history = []DateAdvancement{}
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+// UndoAdvanceAPI reverts the most recent advancement. The audit entry's
+// from/to describe the undo itself (the advancement's To → its From).
+// POST /campaigns/:id/calendars/:calId/advance/undo
+func (h *Handler) UndoAdvanceAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	last, err := h.svc.UndoLastAdvancement(c.Request().Context(), cal.ID)
+	if err != nil {
+		return err
+	}
+	from, to := last.ToStamp(), last.FromStamp()
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarAdvanceUndone, "calendar", cal.ID, cal.Name,
+		map[string]any{"from": from, "to": to, "kind": last.Kind})
+	return c.JSON(http.StatusOK, map[string]string{"from": from, "to": to})
+}
+
+// ShowV2DateHistory renders the "campaign so far" timeline: advancements
+// grouped into real-world play sessions with the in-game time each covered.
+// GET /campaigns/:id/calendar/v2/:calId/history
+func (h *Handler) ShowV2DateHistory(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireVisibleCalendar(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	history, err := h.svc.ListDateHistory(c.Request().Context(), cal.ID)
+	if err != nil {
+		return err
+	}
+
+	data := DateHistoryViewData{
+		Calendar:   cal,
+		CampaignID: cc.Campaign.ID,
+		Timeline:   buildDateHistoryTimeline(history),
+		// Undo is offered only where it can succeed: a world-state
+		// controller on a manually-advanced calendar.
+		CanUndo:   cc.MemberRole >= campaigns.RoleOwner && !cal.UsesRealTime(),
+		CSRFToken: middleware.GetCSRFToken(c),
+	}
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, dateHistoryBody(data))
+	}
+	return middleware.Render(c, http.StatusOK, DateHistoryPage(data))
+}
@@ -0,0 +1,106 @@
+// date_history_repository.go — MariaDB reads/writes for the clock
+// advancement log (calendar_date_history, migration 016). Hand-written SQL
+// per the conventions; rows cascade away with their calendar.
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// dateHistoryColumns is the shared SELECT list; user_name resolves through a
+// LEFT JOIN so sync writes (NULL user_id) and departed members still list.
+const dateHistoryColumns = `h.id, h.calendar_id, h.user_id, COALESCE(u.display_name, ''), h.kind,
+	h.from_year, h.from_month, h.from_day, h.from_hour, h.from_minute,
+	h.to_year, h.to_month, h.to_day, h.to_hour, h.to_minute,
+	h.advanced_minutes, h.created_at, h.undone_at`
+
+// RecordDateAdvancement inserts one advancement row and sets its ID.
+func (r *calendarRepo) RecordDateAdvancement(ctx context.Context, a *DateAdvancement) error {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO calendar_date_history
+		 (calendar_id, user_id, kind, from_year, from_month, from_day, from_hour, from_minute,
+		  to_year, to_month, to_day, to_hour, to_minute, advanced_minutes)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.CalendarID, a.UserID, a.Kind, a.FromYear, a.FromMonth, a.FromDay, a.FromHour, a.FromMinute,
+		a.ToYear, a.ToMonth, a.ToDay, a.ToHour, a.ToMinute, a.AdvancedMinutes)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+	return nil
+}
+
+// ListDateHistory returns a calendar's advancements newest first.
+func (r *calendarRepo) ListDateHistory(ctx context.Context, calendarID string, limit int) ([]DateAdvancement, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+dateHistoryColumns+`
+		 FROM calendar_date_history h
+		 LEFT JOIN users u ON u.id = h.user_id
+		 WHERE h.calendar_id = ?
+		 ORDER BY h.id DESC
+		 LIMIT ?`, calendarID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DateAdvancement
+	for rows.Next() {
+		a, err := scanDateAdvancement(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+// LastDateAdvancement returns the most recent advancement that hasn't been
+// undone, or nil when there is none.
+func (r *calendarRepo) LastDateAdvancement(ctx context.Context, calendarID string) (*DateAdvancement, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+dateHistoryColumns+`
+		 FROM calendar_date_history h
+		 LEFT JOIN users u ON u.id = h.user_id
+		 WHERE h.calendar_id = ? AND h.undone_at IS NULL
+		 ORDER BY h.id DESC
+		 LIMIT 1`, calendarID)
+	a, err := scanDateAdvancement(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+// MarkDateAdvancementUndone stamps an advancement as reverted.
+func (r *calendarRepo) MarkDateAdvancementUndone(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE calendar_date_history SET undone_at = NOW() WHERE id = ? AND undone_at IS NULL`, id)
+	return err
+}
+
+// scanDateAdvancement scans one dateHistoryColumns row.
+func scanDateAdvancement(row interface{ Scan(...any) error }) (*DateAdvancement, error) {
+	var a DateAdvancement
+	var userID sql.NullString
+	var undoneAt sql.NullTime
+	if err := row.Scan(&a.ID, &a.CalendarID, &userID, &a.UserName, &a.Kind,
+		&a.FromYear, &a.FromMonth, &a.FromDay, &a.FromHour, &a.FromMinute,
+		&a.ToYear, &a.ToMonth, &a.ToDay, &a.ToHour, &a.ToMinute,
+		&a.AdvancedMinutes, &a.CreatedAt, &undoneAt); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		a.UserID = &userID.String
+	}
+	if undoneAt.Valid {
+		a.UndoneAt = &undoneAt.Time
+	}
+	return &a, nil
+}
@@ -0,0 +1,101 @@
+// date_history_service.go — service-layer logic for the clock advancement
+// log (migration 016). AdvanceDate / AdvanceTime write through
+// recordAdvancement; undo reverts the latest live row.
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// recordAdvancement logs a committed clock move. Best-effort: the advance
+// itself already succeeded, so a failed history write is logged rather than
+// surfaced — the timeline just misses one row.
+func (s *calendarService) recordAdvancement(ctx context.Context, before, after *Calendar, userID, kind string, minutes int) {
+	a := &DateAdvancement{
+		CalendarID:      after.ID,
+		Kind:            kind,
+		FromYear:        before.CurrentYear,
+		FromMonth:       before.CurrentMonth,
+		FromDay:         before.CurrentDay,
+		FromHour:        before.CurrentHour,
+		FromMinute:      before.CurrentMinute,
+		ToYear:          after.CurrentYear,
+		ToMonth:         after.CurrentMonth,
+		ToDay:           after.CurrentDay,
+		ToHour:          after.CurrentHour,
+		ToMinute:        after.CurrentMinute,
+		AdvancedMinutes: minutes,
+	}
+	if userID != "" {
+		a.UserID = &userID
+	}
+	if err := s.repo.RecordDateAdvancement(ctx, a); err != nil {
+		slog.Warn("failed to record date advancement",
+			slog.String("calendar_id", after.ID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// ListDateHistory returns a calendar's advancements, newest first.
+func (s *calendarService) ListDateHistory(ctx context.Context, calendarID string) ([]DateAdvancement, error) {
+	history, err := s.repo.ListDateHistory(ctx, calendarID, maxDateHistory)
+	if err != nil {
+		return nil, fmt.Errorf("list date history: %w", err)
+	}
+	return history, nil
+}
+
+// UndoLastAdvancement restores the clock to where the most recent live
+// advancement started and marks that row undone. Repeated undos walk further
+// back. Refused when the clock has since moved by other means (an absolute
+// set, a GM console verb, a sync push) — rewinding then would silently
+// discard that change.
+func (s *calendarService) UndoLastAdvancement(ctx context.Context, calendarID string) (*DateAdvancement, error) {
+	cal, err := s.repo.GetByID(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("get calendar: %w", err)
+	}
+	if cal == nil {
+		return nil, apperror.NewNotFound("calendar not found")
+	}
+	if err := guardManualDateChange(cal); err != nil {
+		return nil, err
+	}
+
+	last, err := s.repo.LastDateAdvancement(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("last date advancement: %w", err)
+	}
+	if last == nil {
+		return nil, apperror.NewValidation("there is no advancement to undo")
+	}
+	if !last.endsAt(cal) {
+		return nil, apperror.NewConflict("the calendar has moved since the last advancement; set the date directly instead")
+	}
+
+	beforeSeason, beforeEra, beforeMoonPhases := s.snapshotState(ctx, cal)
+	cal.CurrentYear = last.FromYear
+	cal.CurrentMonth = last.FromMonth
+	cal.CurrentDay = last.FromDay
+	cal.CurrentHour = last.FromHour
+	cal.CurrentMinute = last.FromMinute
+	if err := s.repo.Update(ctx, cal); err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkDateAdvancementUndone(ctx, last.ID); err != nil {
+		return nil, fmt.Errorf("mark advancement undone: %w", err)
+	}
+
+	s.events.PublishCalendarEvent("date.advanced", cal.CampaignID, calendarID, map[string]int{
+		"year":  cal.CurrentYear,
+		"month": cal.CurrentMonth,
+		"day":   cal.CurrentDay,
+	})
+	s.publishStateChanges(ctx, cal, beforeSeason, beforeEra, beforeMoonPhases)
+	return last, nil
+}
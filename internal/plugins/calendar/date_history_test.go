@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// historyCalendar is a manual 24h/60m calendar with one 30-day month.
+func historyCalendar() *Calendar {
+	return &Calendar{ID: "cal-1", CampaignID: "camp-1", HoursPerDay: 24, MinutesPerHour: 60,
+		CurrentYear: 1492, CurrentMonth: 1, CurrentDay: 10, CurrentHour: 9}
+}
+
+func historyRepo(cal *Calendar) *mockCalendarRepo {
+	return &mockCalendarRepo{
+		getByIDFn: func(_ context.Context, _ string) (*Calendar, error) {
+			cp := *cal
+			return &cp, nil
+		},
+		getMonthsFn: func(_ context.Context, _ string) ([]Month, error) {
+			return []Month{{Name: "Hammer", Days: 30, SortOrder: 1}}, nil
+		},
+		updateFn: func(_ context.Context, c *Calendar) error {
+			*cal = *c
+			return nil
+		},
+	}
+}
+
+func TestAdvance_RecordsHistory(t *testing.T) {
+	cal := historyCalendar()
+	repo := historyRepo(cal)
+	var got []DateAdvancement
+	repo.recordDateAdvancementFn = func(_ context.Context, a *DateAdvancement) error {
+		got = append(got, *a)
+		return nil
+	}
+	svc := newTestCalendarService(repo)
+
+	if err := svc.AdvanceTime(context.Background(), "cal-1", "u-1", 8, 0); err != nil {
+		t.Fatalf("AdvanceTime: %v", err)
+	}
+	if err := svc.AdvanceDate(context.Background(), "cal-1", "", 2); err != nil {
+		t.Fatalf("AdvanceDate: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("recorded %d advancements; want 2", len(got))
+	}
+	if got[0].Kind != AdvanceKindTime || got[0].UserID == nil || *got[0].UserID != "u-1" {
+		t.Errorf("time row = %+v; want kind time by u-1", got[0])
+	}
+	if got[0].FromStamp() != "1492-01-10 09:00" || got[0].ToStamp() != "1492-01-10 17:00" || got[0].AdvancedMinutes != 480 {
+		t.Errorf("time row span = %s → %s (+%d)", got[0].FromStamp(), got[0].ToStamp(), got[0].AdvancedMinutes)
+	}
+	if got[1].Kind != AdvanceKindDate || got[1].UserID != nil {
+		t.Errorf("sync date row = %+v; want kind date with no user", got[1])
+	}
+	if got[1].ToStamp() != "1492-01-12 17:00" || got[1].AdvancedMinutes != 2*24*60 {
+		t.Errorf("date row to = %s (+%d)", got[1].ToStamp(), got[1].AdvancedMinutes)
+	}
+}
+
+func TestUndoLastAdvancement(t *testing.T) {
+	last := &DateAdvancement{ID: 7, Kind: AdvanceKindTime,
+		FromYear: 1492, FromMonth: 1, FromDay: 10, FromHour: 9,
+		ToYear: 1492, ToMonth: 1, ToDay: 10, ToHour: 17, AdvancedMinutes: 480}
+	tests := []struct {
+		name     string
+		clock    int // cal.CurrentHour before undo
+		last     *DateAdvancement
+		wantCode int
+	}{
+		{name: "restores the advancement start", clock: 17, last: last},
+		{name: "clock moved since", clock: 18, last: last, wantCode: 409},
+		{name: "nothing to undo", clock: 17, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := historyCalendar()
+			cal.CurrentHour = tt.clock
+			repo := historyRepo(cal)
+			repo.lastDateAdvancementFn = func(_ context.Context, _ string) (*DateAdvancement, error) {
+				return tt.last, nil
+			}
+			marked := 0
+			repo.markDateAdvancementUndoneFn = func(_ context.Context, id int) error {
+				marked = id
+				return nil
+			}
+
+			_, err := newTestCalendarService(repo).UndoLastAdvancement(context.Background(), "cal-1")
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				if marked != 0 || cal.CurrentHour != tt.clock {
+					t.Error("a refused undo must not touch the clock or the log")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cal.FormatCurrentStamp() != "1492-01-10 09:00" {
+				t.Errorf("clock = %s; want 1492-01-10 09:00", cal.FormatCurrentStamp())
+			}
+			if marked != 7 {
+				t.Errorf("marked row %d undone; want 7", marked)
+			}
+		})
+	}
+}
+
+func TestBuildDateHistoryTimeline(t *testing.T) {
+	day1 := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC)
+	undone := day2.Add(time.Hour)
+	// Newest first, as ListDateHistory returns them.
+	history := []DateAdvancement{
+		{ID: 4, CreatedAt: day2.Add(time.Hour), UndoneAt: &undone, FromDay: 3, ToDay: 9, AdvancedMinutes: 8640},
+		{ID: 3, CreatedAt: day2, FromDay: 2, ToDay: 3, AdvancedMinutes: 1440},
+		{ID: 2, CreatedAt: day1.Add(time.Hour), FromDay: 1, FromHour: 8, ToDay: 2, AdvancedMinutes: 960},
+		{ID: 1, CreatedAt: day1, FromDay: 1, ToDay: 1, ToHour: 8, AdvancedMinutes: 480},
+	}
+	tl := buildDateHistoryTimeline(history)
+
+	if len(tl.Sessions) != 2 || tl.Sessions[0].Date != "2026-03-08" || tl.Sessions[1].Date != "2026-03-01" {
+		t.Fatalf("sessions = %+v; want 2026-03-08 then 2026-03-01", tl.Sessions)
+	}
+	if len(tl.Sessions[0].Advancements) != 2 {
+		t.Errorf("undone rows should still be listed, got %d", len(tl.Sessions[0].Advancements))
+	}
+	if tl.Sessions[0].Elapsed != 1440 || tl.TotalElapsed != 2880 {
+		t.Errorf("elapsed = %d / total %d; want 1440 / 2880 (undone excluded)", tl.Sessions[0].Elapsed, tl.TotalElapsed)
+	}
+	if tl.Sessions[1].From != "0-00-01 00:00" || tl.Sessions[1].To != "0-00-02 00:00" {
+		t.Errorf("session span = %s → %s", tl.Sessions[1].From, tl.Sessions[1].To)
+	}
+	if tl.LastUndoable == nil || tl.LastUndoable.ID != 3 {
+		t.Errorf("LastUndoable = %+v; want row 3", tl.LastUndoable)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{0, "0m"},
+		{45, "45m"},
+		{480, "8h"},
+		{3375, "2d 8h 15m"},
+		{1440, "1d"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(nil, tt.minutes); got != tt.want {
+			t.Errorf("formatElapsed(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
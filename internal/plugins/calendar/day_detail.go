@@ -0,0 +1,378 @@
+// day_detail.go — everything about one in-game day: events (recurrences
+// expanded), festivals, entity anniversaries, moons, season, era, weather.
+// /campaigns/:id/calendar/:y/:m/:d renders it as a bookmarkable page (the
+// bare panel for HTMX) so events, session notes and entity pages can link to
+// a date; /calendar/day returns the same data as JSON for the grid's
+// click-through panel.
+
+package calendar
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// DayDetailData is the view model for one in-game day.
+type DayDetailData struct {
+	// View carries the calendar, cursor and viewer flags; View.View is
+	// always "day" so the shared day helpers (heading, stepping) apply.
+	View          CalendarV2ViewData
+	Weekday       string     // "" when the calendar has no weekdays
+	Era           *Era       // era containing the year, if any
+	Season        *Season    // calendar-wide season containing the date, if any
+	Festivals     []Festival // fixed holidays on this date
+	Anniversaries []DayAnniversary
+	IsToday       bool // the date is the calendar's current in-world date
+}
+
+// DayAnniversary is an entity-linked event from an earlier year on the
+// same month and day (a birth, death or founding).
+type DayAnniversary struct {
+	Event    Event
+	YearsAgo int
+}
+
+// dayDetailResponse is the JSON shape of DayDetailAPI.
+type dayDetailResponse struct {
+	CalendarID    string                 `json:"calendar_id"`
+	Date          WorldStateDate         `json:"date"`
+	Heading       string                 `json:"heading"`
+	Weekday       string                 `json:"weekday,omitempty"`
+	IsToday       bool                   `json:"is_today"`
+	URL           string                 `json:"url"`
+	Season        *dayDetailLabel        `json:"season"`
+	Era           *dayDetailLabel        `json:"era"`
+	Weather       *WorldStateWeather     `json:"weather"`
+	Moons         []dayDetailMoon        `json:"moons"`
+	Celestial     []WorldStateEvent      `json:"celestial"`
+	Festivals     []Festival             `json:"festivals"`
+	Events        []Event                `json:"events"`
+	Anniversaries []dayDetailAnniversary `json:"anniversaries"`
+}
+
+// dayDetailLabel is a named, colored period (season or era).
+type dayDetailLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// dayDetailMoon is one moon's phase on the day.
+type dayDetailMoon struct {
+	Name       string  `json:"name"`
+	Phase      int     `json:"phase"`
+	NamedPhase string  `json:"named_phase"`
+	CyclePct   float64 `json:"cycle_pct"`
+}
+
+// dayDetailAnniversary is an anniversary with its linked entity.
+type dayDetailAnniversary struct {
+	EventID     string `json:"event_id"`
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	Year        int    `json:"year"`
+	YearsAgo    int    `json:"years_ago"`
+	EntityID    string `json:"entity_id"`
+	EntityName  string `json:"entity_name"`
+	EntityIcon  string `json:"entity_icon,omitempty"`
+	EntityColor string `json:"entity_color,omitempty"`
+}
+
+// newDayDetailResponse flattens the view model for JSON. Lists are never
+// null so the panel can iterate without guards.
+func newDayDetailResponse(d DayDetailData) dayDetailResponse {
+	cal := d.View.ActiveCalendar
+	resp := dayDetailResponse{
+		CalendarID:    cal.ID,
+		Date:          WorldStateDate{Year: d.View.Year, Month: d.View.Month, Day: d.View.Day},
+		Heading:       dayHeading(d.View),
+		Weekday:       d.Weekday,
+		IsToday:       d.IsToday,
+		URL:           dayDetailHref(d.View, d.View.Year, d.View.Month, d.View.Day),
+		Moons:         []dayDetailMoon{},
+		Celestial:     []WorldStateEvent{},
+		Festivals:     []Festival{},
+		Events:        []Event{},
+		Anniversaries: []dayDetailAnniversary{},
+	}
+	if d.Season != nil {
+		resp.Season = &dayDetailLabel{Name: d.Season.Name, Color: d.Season.Color}
+	}
+	if d.Era != nil {
+		resp.Era = &dayDetailLabel{Name: d.Era.Name, Color: d.Era.Color}
+	}
+	if ws := d.View.WorldState; ws != nil {
+		weather := ws.Weather
+		resp.Weather = &weather
+		for _, m := range ws.Moons {
+			resp.Moons = append(resp.Moons, dayDetailMoon{Name: m.Name, Phase: m.Phase, NamedPhase: m.NamedPhase, CyclePct: m.CyclePct})
+		}
+		resp.Celestial = append(resp.Celestial, ws.Events...)
+	}
+	resp.Festivals = append(resp.Festivals, d.Festivals...)
+	resp.Events = append(resp.Events, d.View.Events...)
+	for _, a := range d.Anniversaries {
+		item := dayDetailAnniversary{
+			EventID:     a.Event.ID,
+			Name:        a.Event.Name,
+			Year:        a.Event.Year,
+			YearsAgo:    a.YearsAgo,
+			EntityName:  a.Event.EntityName,
+			EntityIcon:  a.Event.EntityIcon,
+			EntityColor: a.Event.EntityColor,
+		}
+		if a.Event.Category != nil {
+			item.Category = *a.Event.Category
+		}
+		if a.Event.EntityID != nil {
+			item.EntityID = *a.Event.EntityID
+		}
+		resp.Anniversaries = append(resp.Anniversaries, item)
+	}
+	return resp
+}
+
+// DayDetailPath is the bookmarkable URL of a calendar day. calendarID may be
+// empty for the viewer's active calendar.
+func DayDetailPath(campaignID, calendarID string, year, month, day int) string {
+	path := fmt.Sprintf("/campaigns/%s/calendar/%d/%d/%d", campaignID, year, month, day)
+	if calendarID != "" {
+		path += "?calendarId=" + calendarID
+	}
+	return path
+}
+
+// ShowDayDetail renders the day page.
+// GET /campaigns/:id/calendar/:year/:month/:day[?calendarId=]
+func (h *Handler) ShowDayDetail(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+
+	year, yerr := strconv.Atoi(c.Param("year"))
+	month, merr := strconv.Atoi(c.Param("month"))
+	day, derr := strconv.Atoi(c.Param("day"))
+	if yerr != nil || merr != nil || derr != nil {
+		return apperror.NewNotFound("day not found")
+	}
+
+	cal, err := h.resolveDayCalendar(c)
+	if err != nil {
+		return err
+	}
+	data, err := h.loadDayDetail(c, cal, year, month, day)
+	if err != nil {
+		return err
+	}
+
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, DayDetailFragment(cc, data))
+	}
+	return middleware.Render(c, http.StatusOK, DayDetailPage(cc, data))
+}
+
+// DayDetailAPI returns everything on one day as JSON for the grid's
+// click-through panel. Missing date parts default to the calendar's
+// current date.
+// GET /campaigns/:id/calendar/day?year=&month=&day=[&calendarId=]
+func (h *Handler) DayDetailAPI(c echo.Context) error {
+	cal, err := h.resolveDayCalendar(c)
+	if err != nil {
+		return err
+	}
+	year := atoiOr(c.QueryParam("year"), cal.CurrentYear)
+	month := atoiOr(c.QueryParam("month"), cal.CurrentMonth)
+	day := atoiOr(c.QueryParam("day"), cal.CurrentDay)
+
+	data, err := h.loadDayDetail(c, cal, year, month, day)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, newDayDetailResponse(data))
+}
+
+// loadDayDetail validates the date against the calendar and gathers the
+// day's events, festivals, anniversaries, era and world state.
+func (h *Handler) loadDayDetail(c echo.Context, cal *Calendar, year, month, day int) (DayDetailData, error) {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+
+	if month < 1 || month > len(cal.Months) || day < 1 || day > cal.MonthDays(month-1, year) {
+		return DayDetailData{}, apperror.NewNotFound("that day doesn't exist on this calendar")
+	}
+
+	role := cc.VisibilityRole()
+	data := DayDetailData{
+		View: CalendarV2ViewData{
+			ActiveCalendar:  cal,
+			View:            "day",
+			Year:            year,
+			Month:           month,
+			Day:             day,
+			TodayYear:       cal.CurrentYear,
+			TodayMonth:      cal.CurrentMonth,
+			TodayDay:        cal.CurrentDay,
+			CampaignID:      cc.Campaign.ID,
+			UserID:          userID,
+			IsOwner:         cc.MemberRole >= campaigns.RoleOwner,
+			IsScribe:        cc.MemberRole >= campaigns.RoleScribe,
+			CSRFToken:       middleware.GetCSRFToken(c),
+			TierDefinitions: h.loadTierDefinitions(ctx, cc.Campaign.ID),
+		},
+		Era:     cal.EraForYear(year),
+		Season:  cal.SeasonForDate(month, day),
+		IsToday: year == cal.CurrentYear && month == cal.CurrentMonth && day == cal.CurrentDay,
+	}
+	if len(cal.Weekdays) > 0 {
+		if idx := v2WeekdayIndexFor(cal, year, month, day); idx >= 0 && idx < len(cal.Weekdays) {
+			data.Weekday = cal.Weekdays[idx].Name
+		}
+	}
+	for _, f := range cal.Festivals {
+		if f.Month != nil && f.Day != nil && *f.Month == month && *f.Day == day {
+			data.Festivals = append(data.Festivals, f)
+		}
+	}
+
+	// Load from the start of the year so multi-day events that began
+	// before this day are found; dayEvents keeps the ones covering it.
+	events, err := h.svc.ListEventsForDateRange(ctx, cal.ID, year, 1, 1, month, day, role, userID)
+	if err != nil {
+		return DayDetailData{}, err
+	}
+	// Generated entity anniversaries are listed with their age below
+	// rather than as plain events (except in the year they happened).
+	for _, e := range dayEvents(cal, events, year, month, day) {
+		if e.SourceField == nil || e.Year >= year {
+			data.View.Events = append(data.View.Events, e)
+		}
+	}
+
+	anniversaries, err := h.svc.ListEntityAnniversaries(ctx, cal.ID, year, month, day, role, userID)
+	if err != nil {
+		return DayDetailData{}, err
+	}
+	for _, e := range anniversaries {
+		data.Anniversaries = append(data.Anniversaries, DayAnniversary{Event: e, YearsAgo: year - e.Year})
+	}
+
+	// Moons and weather come from the world-state seed for the date.
+	// Best-effort like the calendar shell: the events still render.
+	if seed, serr := h.svc.BuildWorldStateSeed(ctx, cal.ID, year, month, day, role, userID); serr == nil {
+		data.View.WorldState = seed
+	}
+	return data, nil
+}
+
+// dayAnniversaryIcon shows the linked entity's icon, or a generic marker.
+func dayAnniversaryIcon(a DayAnniversary) string {
+	if a.Event.EntityIcon != "" {
+		return a.Event.EntityIcon
+	}
+	return "fa-cake-candles"
+}
+
+// dayAnniversaryAgo is the "N years ago" label for an anniversary.
+func dayAnniversaryAgo(a DayAnniversary) string {
+	if a.YearsAgo == 1 {
+		return "1 year ago"
+	}
+	return fmt.Sprintf("%d years ago", a.YearsAgo)
+}
+
+// resolveDayCalendar picks ?calendarId= (when the viewer may see it) or the
+// viewer's active calendar, eager-loaded so months, moons and festivals are
+// available.
+func (h *Handler) resolveDayCalendar(c echo.Context) (*Calendar, error) {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	if calID := c.QueryParam("calendarId"); calID != "" {
+		return h.requireVisibleCalendar(c, calID, cc.Campaign.ID)
+	}
+	active, err := h.svc.GetActiveVisibleCalendar(ctx, cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return nil, err
+	}
+	if active == nil {
+		return nil, apperror.NewNotFound("no calendar configured for this campaign")
+	}
+	full, err := h.svc.GetCalendarByID(ctx, active.ID)
+	if err != nil {
+		return nil, err
+	}
+	if full == nil {
+		return nil, apperror.NewNotFound("calendar not found")
+	}
+	return full, nil
+}
+
+// dayEvents keeps the events on the given day: single-day events (with
+// recurrences expanded by OccursOn) and multi-day events whose span
+// covers it. Timed events sort by start time after all-day ones.
+func dayEvents(cal *Calendar, events []Event, year, month, day int) []Event {
+	target := cal.absDayIndex(year, month, day)
+	var out []Event
+	for _, e := range events {
+		if isMultiDayEvent(e) {
+			endYear, endMonth, endDay := e.Year, e.Month, e.Day
+			if e.EndYear != nil {
+				endYear = *e.EndYear
+			}
+			if e.EndMonth != nil {
+				endMonth = *e.EndMonth
+			}
+			if e.EndDay != nil {
+				endDay = *e.EndDay
+			}
+			if cal.absDayIndex(e.Year, e.Month, e.Day) <= target && target <= cal.absDayIndex(endYear, endMonth, endDay) {
+				out = append(out, e)
+			}
+			continue
+		}
+		if e.OccursOn(cal, year, month, day) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// dayDetailHref is the day page for a date on the same calendar.
+func dayDetailHref(data CalendarV2ViewData, year, month, day int) string {
+	calID := ""
+	if data.ActiveCalendar != nil {
+		calID = data.ActiveCalendar.ID
+	}
+	return DayDetailPath(data.CampaignID, calID, year, month, day)
+}
+
+// dayDetailStepHref links to the previous (dir -1) or next (dir 1) day.
+func dayDetailStepHref(data CalendarV2ViewData, dir int) string {
+	y, m, d := v2Step(data, dir)
+	return dayDetailHref(data, y, m, d)
+}
+
+// dayDetailTitle is the heading with the weekday, when the calendar has one.
+func dayDetailTitle(d DayDetailData) string {
+	heading := dayHeading(d.View)
+	if d.Weekday != "" {
+		return d.Weekday + ", " + heading
+	}
+	return heading
+}
+
+// dayDetailWeather capitalizes the seed's weather key ("heavy_rain" →
+// "Heavy rain").
+func dayDetailWeather(d DayDetailData) string {
+	if d.View.WorldState == nil || d.View.WorldState.Weather.Type == "" {
+		return ""
+	}
+	w := strings.ReplaceAll(d.View.WorldState.Weather.Type, "_", " ")
+	return strings.ToUpper(w[:1]) + w[1:]
+}
@@ -0,0 +1,83 @@
+// day_detail_test.go — the day page's event selection and links, and the
+// JSON shape of the day-detail API.
+package calendar
+
+import "testing"
+
+func TestDayEvents(t *testing.T) {
+	cal := recurrenceCal()
+	events := []Event{
+		{ID: "on-day", Year: 3, Month: 4, Day: 10},
+		{ID: "other-day", Year: 3, Month: 4, Day: 11},
+		// Spans 3/4/8 → 3/4/12, so it covers the 10th.
+		{ID: "span", Year: 3, Month: 4, Day: 8, EndYear: ptr(3), EndMonth: ptr(4), EndDay: ptr(12)},
+		{ID: "span-ended", Year: 3, Month: 4, Day: 1, EndYear: ptr(3), EndMonth: ptr(4), EndDay: ptr(9)},
+		recurEvent(RecurrenceYearly, 1, 4, 10),
+	}
+	events[4].ID = "yearly"
+
+	got := dayEvents(cal, events, 3, 4, 10)
+	var ids []string
+	for _, e := range got {
+		ids = append(ids, e.ID)
+	}
+	want := []string{"on-day", "span", "yearly"}
+	if len(ids) != len(want) {
+		t.Fatalf("dayEvents = %v; want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("dayEvents[%d] = %q; want %q", i, ids[i], want[i])
+		}
+	}
+}
+
+func TestDayDetailStepHref(t *testing.T) {
+	cal := recurrenceCal()
+	cal.ID = "cal-1"
+	data := CalendarV2ViewData{ActiveCalendar: cal, CampaignID: "camp", View: "day", Year: 5, Month: 12, Day: 30}
+
+	if got, want := dayDetailStepHref(data, 1), "/campaigns/camp/calendar/6/1/1?calendarId=cal-1"; got != want {
+		t.Errorf("next = %q; want %q", got, want)
+	}
+	data.Month, data.Day = 1, 1
+	if got, want := dayDetailStepHref(data, -1), "/campaigns/camp/calendar/4/12/30?calendarId=cal-1"; got != want {
+		t.Errorf("previous = %q; want %q", got, want)
+	}
+	if got, want := DayDetailPath("camp", "", 1492, 3, 15), "/campaigns/camp/calendar/1492/3/15"; got != want {
+		t.Errorf("DayDetailPath = %q; want %q", got, want)
+	}
+}
+
+func TestNewDayDetailResponse(t *testing.T) {
+	cal := recurrenceCal()
+	cal.ID = "cal-1"
+	hero := "hero-1"
+	data := DayDetailData{
+		View: CalendarV2ViewData{ActiveCalendar: cal, CampaignID: "camp", View: "day", Year: 10, Month: 4, Day: 10},
+		Anniversaries: []DayAnniversary{
+			{Event: Event{ID: "born", Name: "Born", Year: 9, EntityID: &hero, EntityName: "Aria"}, YearsAgo: 1},
+		},
+	}
+
+	resp := newDayDetailResponse(data)
+	if resp.Events == nil || resp.Moons == nil || resp.Festivals == nil || resp.Celestial == nil {
+		t.Error("empty lists should serialize as [] rather than null")
+	}
+	if resp.Weather != nil || resp.Season != nil || resp.Era != nil {
+		t.Errorf("weather/season/era = %v/%v/%v; want nil without a seed, season or era", resp.Weather, resp.Season, resp.Era)
+	}
+	if got, want := resp.URL, "/campaigns/camp/calendar/10/4/10?calendarId=cal-1"; got != want {
+		t.Errorf("url = %q; want %q", got, want)
+	}
+	if len(resp.Anniversaries) != 1 {
+		t.Fatalf("anniversaries = %+v; want one", resp.Anniversaries)
+	}
+	a := resp.Anniversaries[0]
+	if a.EntityID != hero || a.EntityName != "Aria" || a.YearsAgo != 1 {
+		t.Errorf("anniversary = %+v; want Aria's, one year ago", a)
+	}
+	if got := dayAnniversaryAgo(data.Anniversaries[0]); got != "1 year ago" {
+		t.Errorf("dayAnniversaryAgo = %q; want %q", got, "1 year ago")
+	}
+}
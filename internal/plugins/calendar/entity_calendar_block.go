@@ -10,10 +10,10 @@ package calendar
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/a-h/templ"
 
-	"github.com/keyxmakerx/chronicle/internal/permissions"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
 )
 
@@ -52,6 +52,7 @@ func EntityCalendarBlock(svc CalendarService, cc *campaigns.CampaignContext, ent
 		cal      *Calendar
 		seed     *WorldStateSeed
 		seedJSON string
+		climate  *LocationClimate
 	)
 	// Resolve the bound instance first (calendarID), falling back to the
 	// campaign default exactly as before when unbound. Identical output when
@@ -73,25 +74,45 @@ func EntityCalendarBlock(svc CalendarService, cc *campaigns.CampaignContext, ent
 				seedJSON = string(b)
 			}
 		}
+		// Regional climate (migration 019): only shown when the entity (or
+		// an ancestor) is assigned a region — the band already shows the
+		// calendar-wide season.
+		if lc, err := svc.ClimateForLocation(ctx, cal.ID, entityID); err == nil && lc != nil && lc.ZoneID != "" {
+			climate = lc
+		}
 	}
 
-	// This entity's linked events (#402), dm_only filtered by viewer role —
-	// mirrors filterEventsByUser so players never see secret events.
+	// This entity's linked events (#402), dm_only filtered by viewer role so
+	// players (and anonymous viewers of a public campaign) never see secret
+	// events.
 	var ties []EntityEventTie
 	if all, err := svc.EventsForEntity(ctx, entityID); err == nil {
-		if permissions.CanSeeDmOnly(role) || userID == "" {
-			ties = all
-		} else {
-			for _, t := range all {
-				if canUserView(t.Event.Visibility, t.Event.VisibilityRules, role, userID) {
-					ties = append(ties, t)
-				}
-			}
-		}
+		ties = visibleEventTies(all, role, userID)
 	}
 
 	data := CalendarV2ViewData{ActiveCalendar: cal, WorldState: seed, WorldStateJSON: seedJSON}
-	return entityCalendarBlockView(cc.Campaign.ID, cal, data, ties, entityID, source, cc.MemberRole >= campaigns.RoleScribe)
+	return entityCalendarBlockView(cc.Campaign.ID, cal, data, ties, climate, entityID, source, cc.MemberRole >= campaigns.RoleScribe)
+}
+
+// entityClimateSummary is the embed's one-line regional climate, e.g.
+// "Southern Desert · Dry season · Scorching".
+func entityClimateSummary(lc *LocationClimate) string {
+	parts := []string{lc.ZoneName}
+	if lc.ZoneName == "" {
+		parts[0] = lc.ZoneID
+	}
+	if lc.Season != nil {
+		parts = append(parts, lc.Season.Name)
+	}
+	if w := lc.Weather; w != nil {
+		switch {
+		case w.PresetLabel != nil && *w.PresetLabel != "":
+			parts = append(parts, *w.PresetLabel)
+		case w.PresetID != nil && *w.PresetID != "":
+			parts = append(parts, *w.PresetID)
+		}
+	}
+	return strings.Join(parts, " · ")
 }
 
 // entityEventHref links a linked-event row to the v2 calendar at that event's
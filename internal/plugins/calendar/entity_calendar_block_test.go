@@ -48,13 +48,14 @@ func TestEntityEmbeds_DistinctSeedIds(t *testing.T) {
 	}
 }
 
-// entityCalBlockStub satisfies CalendarService via embedding; only the three
+// entityCalBlockStub satisfies CalendarService via embedding; only the
 // methods EntityCalendarBlock calls are overridden.
 type entityCalBlockStub struct {
 	CalendarService
-	cal  *Calendar
-	seed *WorldStateSeed
-	ties []EntityEventTie
+	cal     *Calendar
+	seed    *WorldStateSeed
+	ties    []EntityEventTie
+	climate *LocationClimate
 }
 
 func (s *entityCalBlockStub) GetCalendar(context.Context, string) (*Calendar, error) {
@@ -66,6 +67,9 @@ func (s *entityCalBlockStub) BuildWorldStateSeed(context.Context, string, int, i
 func (s *entityCalBlockStub) EventsForEntity(context.Context, string) ([]EntityEventTie, error) {
 	return s.ties, nil
 }
+func (s *entityCalBlockStub) ClimateForLocation(context.Context, string, string) (*LocationClimate, error) {
+	return s.climate, nil
+}
 
 func renderEntityCal(t *testing.T, svc CalendarService, role campaigns.Role, dmGranted bool) string {
 	t.Helper()
@@ -206,3 +210,22 @@ func TestEntityCalendarBlock_EmptyStates(t *testing.T) {
 		t.Errorf("no-ties should show the header + empty note, got: %q", noTies)
 	}
 }
+
+func TestEntityCalendarBlock_RegionalClimate(t *testing.T) {
+	svc := sampleEmbedSvc()
+	// No region → no climate line (the band already shows the season).
+	if html := renderEntityCal(t, svc, campaigns.RolePlayer, false); strings.Contains(html, "data-entity-climate") {
+		t.Error("climate line rendered for an entity without a region")
+	}
+	parent, label := "ent-0", "Scorching"
+	svc.climate = &LocationClimate{
+		EntityID: "ent-1", ZoneID: "desert", ZoneName: "Southern Desert", InheritedFrom: &parent,
+		Season: &Season{Name: "Dry"}, Weather: &Weather{PresetLabel: &label},
+	}
+	html := renderEntityCal(t, svc, campaigns.RolePlayer, false)
+	for _, want := range []string{`data-entity-climate="desert"`, "Southern Desert · Dry · Scorching", "from a parent location"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("climate line missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// entity_dates.go — yearly events generated from entity "date" fields
+// (migration 021). The entities plugin marks date fields as anniversaries
+// (births, foundings, deaths); on every entity save internal/app hands the
+// dated fields here and the campaign calendar's generated events for that
+// entity are replaced to match. Generated events are ordinary recurring
+// events tagged with source_field, so the grid, day pages and exports show
+// them with no special casing; editing one by hand lasts until the entity's
+// next save.
+package calendar
+
+import (
+	"context"
+	"fmt"
+)
+
+// EntityDateField is one dated anniversary field of an entity.
+type EntityDateField struct {
+	FieldKey string
+	Label    string
+	Year     int
+	Month    int
+	Day      int
+}
+
+// EntityDateSync is an entity's complete set of anniversary dates. An empty
+// Dates removes the entity's generated events.
+type EntityDateSync struct {
+	EntityID   string
+	EntityName string
+	// GMOnly hides the events from players: the entity isn't visible to
+	// all of them (private or custom permissions).
+	GMOnly bool
+	Dates  []EntityDateField
+}
+
+// SyncEntityDateEvents replaces the entity's generated events on the
+// campaign's calendar. Dates the calendar doesn't have (month 13) are
+// skipped. A campaign without a calendar just drops any stale events.
+func (s *calendarService) SyncEntityDateEvents(ctx context.Context, campaignID string, in EntityDateSync) error {
+	cal, err := s.GetCalendar(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if cal == nil {
+		return s.repo.ReplaceEntityDateEvents(ctx, "", in.EntityID, nil)
+	}
+	events := entityDateEvents(cal, in)
+	if err := s.repo.ReplaceEntityDateEvents(ctx, cal.ID, in.EntityID, events); err != nil {
+		return fmt.Errorf("syncing entity date events: %w", err)
+	}
+	return nil
+}
+
+// entityDateEvents builds the yearly, all-day events for an entity's dates.
+func entityDateEvents(cal *Calendar, in EntityDateSync) []Event {
+	visibility := "everyone"
+	if in.GMOnly {
+		visibility = "dm_only"
+	}
+	yearly := RecurrenceYearly
+	var events []Event
+	for _, d := range in.Dates {
+		// Leap days count: a birthday on one is still a birthday.
+		if d.Month < 1 || d.Month > len(cal.Months) || d.Day < 1 ||
+			d.Day > cal.Months[d.Month-1].Days+cal.Months[d.Month-1].LeapYearDays {
+			continue
+		}
+		field := d.FieldKey
+		events = append(events, Event{
+			ID:             generateID(),
+			CalendarID:     cal.ID,
+			EntityID:       &in.EntityID,
+			Name:           fmt.Sprintf("%s: %s", in.EntityName, d.Label),
+			Year:           d.Year,
+			Month:          d.Month,
+			Day:            d.Day,
+			IsRecurring:    true,
+			RecurrenceType: &yearly,
+			Visibility:     visibility,
+			AllDay:         true,
+			SourceField:    &field,
+		})
+	}
+	return events
+}
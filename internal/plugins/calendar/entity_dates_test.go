@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEntityDateEvents(t *testing.T) {
+	cal := recurrenceCal()
+	cal.ID = "cal-1"
+	events := entityDateEvents(cal, EntityDateSync{
+		EntityID:   "hero",
+		EntityName: "Aria",
+		GMOnly:     true,
+		Dates: []EntityDateField{
+			{FieldKey: "born", Label: "Born", Year: 1470, Month: 2, Day: 29}, // leap day
+			{FieldKey: "died", Label: "Died", Year: 1500, Month: 13, Day: 1},
+			{FieldKey: "crowned", Label: "Crowned", Year: 1490, Month: 4, Day: 31},
+		},
+	})
+	if len(events) != 1 {
+		t.Fatalf("events = %+v; want only the leap-day birth", events)
+	}
+	e := events[0]
+	if e.Name != "Aria: Born" || *e.SourceField != "born" || *e.EntityID != "hero" {
+		t.Errorf("event = %q field %q entity %q", e.Name, *e.SourceField, *e.EntityID)
+	}
+	if !e.IsRecurring || *e.RecurrenceType != RecurrenceYearly || !e.AllDay {
+		t.Errorf("event should be a yearly all-day recurrence: %+v", e)
+	}
+	if e.Visibility != "dm_only" {
+		t.Errorf("visibility = %q; want dm_only for a GM-only entity", e.Visibility)
+	}
+}
+
+func TestSyncEntityDateEvents_NoCalendarClears(t *testing.T) {
+	var gotCal string
+	var gotEvents []Event
+	called := false
+	svc := newTestCalendarService(&mockCalendarRepo{
+		replaceDateEventsFn: func(_ context.Context, calendarID, entityID string, events []Event) error {
+			called, gotCal, gotEvents = true, calendarID, events
+			return nil
+		},
+	})
+	err := svc.SyncEntityDateEvents(context.Background(), "camp", EntityDateSync{
+		EntityID: "hero",
+		Dates:    []EntityDateField{{FieldKey: "born", Label: "Born", Year: 1, Month: 1, Day: 1}},
+	})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if !called || gotCal != "" || gotEvents != nil {
+		t.Errorf("replace called=%v cal=%q events=%v; want a clear with no calendar", called, gotCal, gotEvents)
+	}
+}
+
+func TestCalendarDateProblem(t *testing.T) {
+	cal := recurrenceCal()
+	cal.Name = "Harptos"
+	cal.Months[1].Name = "Alturiak"
+
+	for _, tc := range []struct {
+		y, m, d int
+		want    string
+	}{
+		{1472, 2, 29, ""}, // leap year
+		{1471, 2, 29, "Alturiak has 28 days in 1471"},
+		{1471, 13, 1, "Harptos has no month 13 (it has 12)"},
+		{1471, 1, 30, ""},
+	} {
+		if got := cal.DateProblem(tc.y, tc.m, tc.d); got != tc.want {
+			t.Errorf("DateProblem(%d-%d-%d) = %q; want %q", tc.y, tc.m, tc.d, got, tc.want)
+		}
+	}
+}
@@ -101,6 +101,13 @@ type EntityEventLink struct {
 	ParticipationRole string `json:"participation_role"`
 }
 
+// EventEntityLink is a requested entity tie on event create — the entity and
+// its participation role (empty = "involved").
+type EventEntityLink struct {
+	EntityID string `json:"entity_id"`
+	Role     string `json:"role"`
+}
+
 // EntityEraLink is one entity<->era tie row. ParticipationRole is nil when
 // the tie carries no finer semantics.
 type EntityEraLink struct {
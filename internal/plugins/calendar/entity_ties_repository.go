@@ -22,6 +22,34 @@ func (r *calendarRepo) LinkEntityEvent(ctx context.Context, entityID, eventID, r
 	return err
 }
 
+// LinkEntityEventIfAbsent ties an entity to an event with the default
+// "involved" role unless the pair is already linked — an existing tie keeps
+// its role. Mirrors the legacy single calendar_events.entity_id into the link
+// table so those events surface on the entity's page like any other tie.
+func (r *calendarRepo) LinkEntityEventIfAbsent(ctx context.Context, entityID, eventID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO entity_event_links (entity_id, event_id, participation_role)
+		 VALUES (?, ?, 'involved')`,
+		entityID, eventID)
+	return err
+}
+
+// BackfillPrimaryEntityLinks runs LinkEntityEventIfAbsent for every event
+// with a legacy entity_id in one statement, returning how many ties it
+// added.
+func (r *calendarRepo) BackfillPrimaryEntityLinks(ctx context.Context) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO entity_event_links (entity_id, event_id, participation_role)
+		 SELECT e.entity_id, e.id, 'involved'
+		 FROM calendar_events e
+		 WHERE e.entity_id IS NOT NULL AND e.entity_id <> ''`)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
 // UnlinkEntityEvent removes an entity<->event tie. No-op if absent.
 func (r *calendarRepo) UnlinkEntityEvent(ctx context.Context, entityID, eventID string) error {
 	_, err := r.db.ExecContext(ctx,
@@ -56,9 +84,11 @@ func (r *calendarRepo) UnlinkEntityEra(ctx context.Context, entityID string, era
 // rather than invent a new one). Keep the two in sync: the alias is `e`
 // (entities), the "default" mode honors the legacy is_private flag (Scribe+ see
 // all, players see public only), the "custom" mode checks entity_permissions
-// for a role/user/group/public grant, and an additive tag-grant branch widens
+// for a role/user/group/public grant, an additive tag-grant branch widens
 // visibility when any tag the entity bears carries a matching tag_permissions
-// grant (C-PERM-W1-TAG-GRANTS — additive only, never hides). Role-tier matches
+// grant (C-PERM-W1-TAG-GRANTS — additive only, never hides), and a draft is
+// hidden from everyone but its creator (a page pending review from everyone
+// but its creator and Scribe+) whatever its grants. Role-tier matches
 // use subject_id <= role, so a Player-role grant is invisible to an anonymous/
 // public viewer (role 0); the 'public' subject matches everyone, anonymous
 // included (C-PERM-ANON-IDENTITY). Owners (role >= RoleOwner) get no filter —
@@ -102,8 +132,9 @@ func entityVisibilityFilter(role int, userID string) (string, []any) {
 				))
 			)
 		)
-	)`
-	return filter, []any{role, role, userID, userID, role, userID, userID}
+	)
+	AND (e.status NOT IN ('draft', 'pending') OR e.created_by = ? OR (e.status = 'pending' AND ? >= 2))`
+	return filter, []any{role, role, userID, userID, role, userID, userID, userID, userID, role}
 }
 
 // EntitiesForCalendar returns the DISTINCT entities tied to any event or era
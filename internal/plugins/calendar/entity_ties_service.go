@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/permissions"
 )
 
 // LinkEntityToEvent ties an entity to an event with a participation role.
@@ -27,6 +28,19 @@ func (s *calendarService) UnlinkEntityFromEvent(ctx context.Context, entityID, e
 	return s.repo.UnlinkEntityEvent(ctx, entityID, eventID)
 }
 
+// EnsureEventEntityLinks does for events saved before mirrorPrimaryEntityLink
+// existed what it does on every save since: ties the legacy entity_id with
+// the "involved" role, leaving existing ties and their roles alone. A data
+// reconciler rather than a migration, so it runs on every boot; once every
+// event is mirrored it adds nothing. Returns the number of ties added.
+func (s *calendarService) EnsureEventEntityLinks(ctx context.Context) (int, error) {
+	n, err := s.repo.BackfillPrimaryEntityLinks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("backfilling event entity links: %w", err)
+	}
+	return n, nil
+}
+
 // CreateEntityFromEvent creates an entity (via the cross-plugin EntityCreator)
 // named after the event, then links it to that event with the default
 // "involved" role. The entity exists even if the link fails, so a link error is
@@ -89,3 +103,65 @@ func (s *calendarService) EntitiesForEra(ctx context.Context, eraID int) ([]Enti
 func (s *calendarService) EntitiesForCalendar(ctx context.Context, calendarID string, role int, userID string) ([]EntityTieRef, error) {
 	return s.repo.EntitiesForCalendar(ctx, calendarID, role, userID)
 }
+
+// EntityEventBacklinks returns the events of a campaign's calendars tied to an
+// entity, for the entity page's "Appears in" backlinks section. The link table
+// is campaign-agnostic, so ties are scoped to this campaign's calendars here.
+// Each map carries id, name, date (Y/M/D), role, url, and calendar_name (set
+// only when the campaign has several calendars, like SearchCalendarEvents).
+func (s *calendarService) EntityEventBacklinks(ctx context.Context, campaignID, entityID string, role int, userID string) ([]map[string]string, error) {
+	cals, err := s.repo.ListByCampaignID(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("entity event backlinks: %w", err)
+	}
+	if len(cals) == 0 {
+		return nil, nil
+	}
+	calNames := make(map[string]string, len(cals))
+	for _, cal := range cals {
+		calNames[cal.ID] = cal.Name
+	}
+	ties, err := s.repo.EventsForEntity(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("entity event backlinks: %w", err)
+	}
+
+	var out []map[string]string
+	for _, t := range visibleEventTies(ties, role, userID) {
+		calName, ok := calNames[t.Event.CalendarID]
+		if !ok {
+			continue
+		}
+		if len(cals) == 1 {
+			calName = ""
+		}
+		evt := t.Event
+		out = append(out, map[string]string{
+			"id":            evt.ID,
+			"name":          evt.Name,
+			"date":          fmt.Sprintf("%d/%d/%d", evt.Year, evt.Month, evt.Day),
+			"role":          entityEventRole(t),
+			"calendar_name": calName,
+			"url": fmt.Sprintf("/campaigns/%s/calendar/v2/%s?year=%d&month=%d&day=%d",
+				campaignID, evt.CalendarID, evt.Year, evt.Month, evt.Day),
+		})
+	}
+	return out, nil
+}
+
+// visibleEventTies drops ties to events the viewer may not see. The link-table
+// reads carry no visibility filter, so dm_only is enforced here for every
+// non-owner — including anonymous viewers, who have no userID for the
+// per-user rules to match.
+func visibleEventTies(ties []EntityEventTie, role int, userID string) []EntityEventTie {
+	if permissions.CanSeeDmOnly(role) {
+		return ties
+	}
+	var out []EntityEventTie
+	for _, t := range ties {
+		if canUserView(t.Event.Visibility, t.Event.VisibilityRules, role, userID) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
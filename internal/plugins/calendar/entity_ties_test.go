@@ -9,6 +9,7 @@ package calendar
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -23,6 +24,18 @@ func (m *mockCalendarRepo) LinkEntityEvent(ctx context.Context, entityID, eventI
 	}
 	return nil
 }
+func (m *mockCalendarRepo) LinkEntityEventIfAbsent(ctx context.Context, entityID, eventID string) error {
+	if m.linkIfAbsentFn != nil {
+		return m.linkIfAbsentFn(ctx, entityID, eventID)
+	}
+	return nil
+}
+func (m *mockCalendarRepo) BackfillPrimaryEntityLinks(ctx context.Context) (int, error) {
+	if m.backfillLinksFn != nil {
+		return m.backfillLinksFn(ctx)
+	}
+	return 0, nil
+}
 func (m *mockCalendarRepo) UnlinkEntityEvent(ctx context.Context, entityID, eventID string) error {
 	if m.unlinkEntityEventFn != nil {
 		return m.unlinkEntityEventFn(ctx, entityID, eventID)
@@ -261,6 +274,8 @@ func TestEntityVisibilityFilter(t *testing.T) {
 				"tag_permissions",
 				"ep.subject_type = 'public'",
 				"tp.subject_type = 'public'",
+				"e.status NOT IN ('draft', 'pending') OR e.created_by = ?",
+				"e.status = 'pending' AND ? >= 2",
 			} {
 				if !strings.Contains(frag, want) {
 					t.Errorf("filter missing %q (drift from entities policy?)\nfrag=%s", want, frag)
@@ -269,9 +284,11 @@ func TestEntityVisibilityFilter(t *testing.T) {
 			// Args bind role twice + userID twice for the entity_permissions
 			// branch (default threshold, role grant, user grant, group
 			// membership), then role + userID + userID again for the additive
-			// tag_permissions branch (role grant, user grant, group membership).
+			// tag_permissions branch (role grant, user grant, group membership),
+			// then userID + role for the draft/pending gate (the creator sees their
+			// page, Scribe+ see pages pending review).
 			// The 'public' subject matches unconditionally and adds NO arg.
-			wantArgs := []any{role, role, "user-9", "user-9", role, "user-9", "user-9"}
+			wantArgs := []any{role, role, "user-9", "user-9", role, "user-9", "user-9", "user-9", "user-9", role}
 			if len(args) != len(wantArgs) {
 				t.Fatalf("arg count = %d, want %d (%v)", len(args), len(wantArgs), args)
 			}
@@ -315,3 +332,124 @@ func TestEntityTies_OptionalBothWays(t *testing.T) {
 		t.Errorf("event with no entities should yield empty, got %v err=%v", ents, err)
 	}
 }
+
+// TestCreateEvent_LinksAttendees: an event can be created with several tied
+// entities at once, and the legacy single entity_id is mirrored into the link
+// table so it shows on that entity's page too.
+func TestCreateEvent_LinksAttendees(t *testing.T) {
+	links := map[string]string{}
+	var mirrored []string
+	repo := &mockCalendarRepo{
+		linkEntityEventFn: func(_ context.Context, entityID, _ string, role string) error {
+			links[entityID] = role
+			return nil
+		},
+		linkIfAbsentFn: func(_ context.Context, entityID, _ string) error {
+			mirrored = append(mirrored, entityID)
+			return nil
+		},
+	}
+	primary := "ent-host"
+	_, err := newTestCalendarService(repo).CreateEvent(context.Background(), "cal-1", CreateEventInput{
+		Name: "Council of Waterdeep", Year: 1492, Month: 3, Day: 1, EntityID: &primary, CreatedBy: "u1",
+		LinkedEntities: []EventEntityLink{
+			{EntityID: "ent-npc", Role: "present"},
+			{EntityID: "ent-palace"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if links["ent-npc"] != "present" || links["ent-palace"] != "involved" || len(links) != 2 {
+		t.Errorf("links = %v, want ent-npc=present, ent-palace=involved (default)", links)
+	}
+	if len(mirrored) != 1 || mirrored[0] != primary {
+		t.Errorf("mirrored = %v, want the primary entity_id", mirrored)
+	}
+}
+
+// TestEnsureEventEntityLinks: the boot reconciler reports the ties the
+// backfill added and surfaces a failure rather than swallowing it.
+func TestEnsureEventEntityLinks(t *testing.T) {
+	repo := &mockCalendarRepo{backfillLinksFn: func(context.Context) (int, error) { return 3, nil }}
+	if n, err := newTestCalendarService(repo).EnsureEventEntityLinks(context.Background()); err != nil || n != 3 {
+		t.Errorf("EnsureEventEntityLinks = %d, %v; want 3, nil", n, err)
+	}
+
+	repo.backfillLinksFn = func(context.Context) (int, error) { return 0, errors.New("db down") }
+	if _, err := newTestCalendarService(repo).EnsureEventEntityLinks(context.Background()); err == nil {
+		t.Error("expected the repo error to be returned")
+	}
+}
+
+// TestCreateEvent_InvalidAttendeeRoleRejectedBeforeCreate: a bad role fails
+// validation before the event is written, so no half-linked event is left.
+func TestCreateEvent_InvalidAttendeeRoleRejectedBeforeCreate(t *testing.T) {
+	created := false
+	repo := &mockCalendarRepo{
+		createEventFn: func(context.Context, *Event) error { created = true; return nil },
+	}
+	_, err := newTestCalendarService(repo).CreateEvent(context.Background(), "cal-1", CreateEventInput{
+		Name: "Feast", Year: 1, Month: 1, Day: 1, CreatedBy: "u1",
+		LinkedEntities: []EventEntityLink{{EntityID: "ent-1", Role: "host"}},
+	})
+	assertAppError(t, err, 422)
+	if created {
+		t.Error("event must not be created when a linked-entity role is invalid")
+	}
+}
+
+// TestEntityEventBacklinks: the "Appears in" feed is scoped to the campaign's
+// calendars and hides dm_only events from players and anonymous viewers.
+func TestEntityEventBacklinks(t *testing.T) {
+	repo := &mockCalendarRepo{
+		listByCampaignIDFn: func(context.Context, string) ([]Calendar, error) {
+			return []Calendar{{ID: "cal-1", Name: "Harptos"}}, nil
+		},
+		eventsForEntityFn: func(context.Context, string) ([]EntityEventTie, error) {
+			return []EntityEventTie{
+				{Event: Event{ID: "e1", CalendarID: "cal-1", Name: "Greengrass", Year: 1492, Month: 4, Day: 1, Visibility: "everyone"}, ParticipationRole: "present"},
+				{Event: Event{ID: "e2", CalendarID: "cal-1", Name: "Secret Pact", Year: 1492, Month: 4, Day: 16, Visibility: "dm_only"}, ParticipationRole: "involved"},
+				{Event: Event{ID: "e3", CalendarID: "other-campaign-cal", Name: "Elsewhere", Year: 1, Month: 1, Day: 1, Visibility: "everyone"}},
+			}, nil
+		},
+	}
+	svc := newTestCalendarService(repo)
+	tests := []struct {
+		name   string
+		role   int
+		userID string
+		want   []string
+	}{
+		{name: "owner sees dm_only", role: permissions.RoleOwner, userID: "owner", want: []string{"e1", "e2"}},
+		{name: "player does not", role: permissions.RolePlayer, userID: "player", want: []string{"e1"}},
+		{name: "anonymous does not", role: 0, userID: "", want: []string{"e1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.EntityEventBacklinks(context.Background(), "camp-1", "ent-1", tt.role, tt.userID)
+			if err != nil {
+				t.Fatalf("EntityEventBacklinks: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i, id := range tt.want {
+				if got[i]["id"] != id {
+					t.Errorf("event %d = %q, want %q", i, got[i]["id"], id)
+				}
+			}
+		})
+	}
+
+	got, _ := svc.EntityEventBacklinks(context.Background(), "camp-1", "ent-1", permissions.RolePlayer, "player")
+	want := map[string]string{
+		"id": "e1", "name": "Greengrass", "date": "1492/4/1", "role": "present", "calendar_name": "",
+		"url": "/campaigns/camp-1/calendar/v2/cal-1?year=1492&month=4&day=1",
+	}
+	for k, v := range want {
+		if got[0][k] != v {
+			t.Errorf("%s = %q, want %q", k, got[0][k], v)
+		}
+	}
+}
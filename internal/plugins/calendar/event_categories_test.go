@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateEventCategoryInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   EventCategoryInput
+		wantErr bool
+		wantVis string
+	}{
+		{name: "defaults visibility to everyone", input: EventCategoryInput{Slug: "holiday", Name: "Holiday"}, wantVis: "everyone"},
+		{name: "accepts dm_only", input: EventCategoryInput{Slug: "secret", Name: "Secret", DefaultVisibility: "dm_only"}, wantVis: "dm_only"},
+		{name: "rejects unknown visibility", input: EventCategoryInput{Slug: "x", Name: "X", DefaultVisibility: "public"}, wantErr: true},
+		{name: "rejects missing name", input: EventCategoryInput{Slug: "x"}, wantErr: true},
+		{name: "rejects missing slug", input: EventCategoryInput{Name: "X"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.input
+			err := validateEventCategoryInput(&in, "category")
+			if tt.wantErr {
+				assertAppError(t, err, 422)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if in.DefaultVisibility != tt.wantVis {
+				t.Errorf("DefaultVisibility = %q, want %q", in.DefaultVisibility, tt.wantVis)
+			}
+			if in.Color == "" {
+				t.Error("expected color to be defaulted")
+			}
+		})
+	}
+}
+
+func TestFilterEventsByCategory(t *testing.T) {
+	holiday, battle := "holiday", "battle"
+	events := []Event{
+		{ID: "1", Category: &holiday},
+		{ID: "2", Category: &battle},
+		{ID: "3"},
+	}
+	tests := []struct {
+		name  string
+		slugs []string
+		want  []string
+	}{
+		{name: "no filter keeps everything", want: []string{"1", "2", "3"}},
+		{name: "single slug", slugs: []string{"battle"}, want: []string{"2"}},
+		{name: "none selects uncategorized", slugs: []string{"none", "holiday"}, want: []string{"1", "3"}},
+		{name: "unknown slug selects nothing", slugs: []string{"quest"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterEventsByCategory(events, tt.slugs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Errorf("event %d = %q, want %q", i, got[i].ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateEvent_InheritsCategoryDefaultVisibility(t *testing.T) {
+	repo := &mockCalendarRepo{
+		getEventCategoriesFn: func(_ context.Context, _ string) ([]EventCategory, error) {
+			return []EventCategory{{Slug: "secret", DefaultVisibility: "dm_only"}}, nil
+		},
+	}
+	svc := newTestCalendarService(repo)
+	secret := "secret"
+
+	evt, err := svc.CreateEvent(context.Background(), "cal-1", CreateEventInput{
+		Name: "Cult meeting", Year: 1, Month: 1, Day: 1, Category: &secret, CreatedBy: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Visibility != "dm_only" {
+		t.Errorf("Visibility = %q, want dm_only from category default", evt.Visibility)
+	}
+
+	// An explicit visibility always wins over the category default.
+	evt, err = svc.CreateEvent(context.Background(), "cal-1", CreateEventInput{
+		Name: "Public rite", Year: 1, Month: 1, Day: 1, Category: &secret, Visibility: "everyone", CreatedBy: "user-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evt.Visibility != "everyone" {
+		t.Errorf("Visibility = %q, want explicit everyone", evt.Visibility)
+	}
+}
+
+func TestDeleteEventCategory_NotFound(t *testing.T) {
+	repo := &mockCalendarRepo{
+		deleteEventCategoryFn: func(_ context.Context, _, _ string) (bool, error) { return false, nil },
+	}
+	err := newTestCalendarService(repo).DeleteEventCategory(context.Background(), "cal-1", "gone")
+	assertAppError(t, err, 404)
+}
+
+func TestParseSimpleCalendar_NoteCategories(t *testing.T) {
+	data := []byte(`{"calendar": {
+		"months": [{"name": "Hammer", "numberOfDays": 30}],
+		"weekdays": [{"name": "One"}],
+		"noteCategories": [
+			{"name": "Holy Day", "color": "#ff0000ff"},
+			{"name": "holy day", "color": "#00ff00"},
+			{"name": "Battle", "color": "00ff00"}
+		]
+	}}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Categories) != 2 {
+		t.Fatalf("got %d categories, want 2 (duplicate slug dropped)", len(result.Categories))
+	}
+	first := result.Categories[0]
+	if first.Slug != "holy-day" || first.Name != "Holy Day" || first.Color != "#ff0000" {
+		t.Errorf("first category = %+v", first)
+	}
+	if result.Categories[1].Color != "#00ff00" {
+		t.Errorf("second category color = %q, want #00ff00", result.Categories[1].Color)
+	}
+}
+
+func TestBuildExport_CategoriesRoundTrip(t *testing.T) {
+	cal := &Calendar{
+		Name:   "Harptos",
+		Months: []Month{{Name: "Hammer", Days: 30}},
+		EventCategories: []EventCategory{
+			{Slug: "secret", Name: "Secret", Icon: "🤫", Color: "#000000", DefaultVisibility: "dm_only", SortOrder: 1},
+		},
+	}
+	raw, err := json.Marshal(BuildExport(cal, nil, false))
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+	result, err := DetectAndParse(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Categories) != 1 || result.Categories[0].DefaultVisibility != "dm_only" {
+		t.Fatalf("categories did not round-trip: %+v", result.Categories)
+	}
+}
@@ -1,6 +1,6 @@
 // Package calendar — export.go provides JSON export of calendar configurations.
 // Exports include all sub-resources (months, weekdays, moons, seasons, eras,
-// cycles, festivals, weather) in Chronicle's native format. Events are
+// cycles, festivals, named years, weather) in Chronicle's native format. Events are
 // optionally included.
 package calendar
 
@@ -45,6 +45,8 @@ type ExportCalendar struct {
 	Eras             []ExportEra       `json:"eras,omitempty"`
 	Cycles           []ExportCycle     `json:"cycles,omitempty"`
 	Festivals        []ExportFestival  `json:"festivals,omitempty"`
+	YearNames        []ExportYearName  `json:"year_names,omitempty"`
+	Categories       []ExportCategory  `json:"categories,omitempty"`
 	Weather          *WeatherInput     `json:"weather,omitempty"`
 }
 
@@ -55,6 +57,10 @@ type ExportMonth struct {
 	SortOrder     int    `json:"sort_order"`
 	IsIntercalary bool   `json:"is_intercalary"`
 	LeapYearDays  int    `json:"leap_year_days"`
+	// StartingWeekday / SkipsWeekday — see Month. Absent in older exports,
+	// which import with today's flowing weekday geometry.
+	StartingWeekday *int `json:"starting_weekday,omitempty"`
+	SkipsWeekday    bool `json:"skips_weekday,omitempty"`
 }
 
 // ExportWeekday is a weekday definition for export.
@@ -111,6 +117,12 @@ type ExportCycleEntry struct {
 	SortOrder  int     `json:"sort_order"`
 }
 
+// ExportYearName is a named year for export.
+type ExportYearName struct {
+	Year int    `json:"year"`
+	Name string `json:"name"`
+}
+
 // ExportFestival is a festival definition for export.
 type ExportFestival struct {
 	Name        string  `json:"name"`
@@ -123,6 +135,17 @@ type ExportFestival struct {
 	SortOrder   int     `json:"sort_order"`
 }
 
+// ExportCategory is an event category definition for export. Field order
+// mirrors EventCategoryInput so the importer can convert directly.
+type ExportCategory struct {
+	Slug              string `json:"slug"`
+	Name              string `json:"name"`
+	Icon              string `json:"icon"`
+	Color             string `json:"color"`
+	DefaultVisibility string `json:"default_visibility"`
+	SortOrder         int    `json:"sort_order"`
+}
+
 // ExportEvent is a calendar event for export.
 type ExportEvent struct {
 	Name                     string  `json:"name"`
@@ -152,6 +175,39 @@ type ExportEvent struct {
 	AllDay                   bool    `json:"all_day"`
 }
 
+// createInput maps an exported (or imported / bulk-submitted) event onto the
+// service create input, attributing it to createdBy.
+func (e ExportEvent) createInput(createdBy string) CreateEventInput {
+	return CreateEventInput{
+		Name:                     e.Name,
+		Description:              e.Description,
+		DescriptionHTML:          e.DescriptionHTML,
+		Year:                     e.Year,
+		Month:                    e.Month,
+		Day:                      e.Day,
+		StartHour:                e.StartHour,
+		StartMinute:              e.StartMinute,
+		EndYear:                  e.EndYear,
+		EndMonth:                 e.EndMonth,
+		EndDay:                   e.EndDay,
+		EndHour:                  e.EndHour,
+		EndMinute:                e.EndMinute,
+		IsRecurring:              e.IsRecurring,
+		RecurrenceType:           e.RecurrenceType,
+		RecurrenceInterval:       e.RecurrenceInterval,
+		RecurrenceEndYear:        e.RecurrenceEndYear,
+		RecurrenceEndMonth:       e.RecurrenceEndMonth,
+		RecurrenceEndDay:         e.RecurrenceEndDay,
+		RecurrenceMaxOccurrences: e.RecurrenceMaxOccurrences,
+		Visibility:               e.Visibility,
+		Category:                 e.Category,
+		Color:                    e.Color,
+		Icon:                     e.Icon,
+		AllDay:                   e.AllDay,
+		CreatedBy:                createdBy,
+	}
+}
+
 // BuildExport creates a ChronicleExport from a fully-loaded Calendar and
 // optional events. The calendar must have sub-resources eager-loaded.
 func BuildExport(cal *Calendar, events []Event, includeEvents bool) *ChronicleExport {
@@ -181,11 +237,13 @@ func BuildExport(cal *Calendar, events []Event, includeEvents bool) *ChronicleEx
 	// Months.
 	for _, m := range cal.Months {
 		export.Calendar.Months = append(export.Calendar.Months, ExportMonth{
-			Name:          m.Name,
-			Days:          m.Days,
-			SortOrder:     m.SortOrder,
-			IsIntercalary: m.IsIntercalary,
-			LeapYearDays:  m.LeapYearDays,
+			Name:            m.Name,
+			Days:            m.Days,
+			SortOrder:       m.SortOrder,
+			IsIntercalary:   m.IsIntercalary,
+			LeapYearDays:    m.LeapYearDays,
+			StartingWeekday: m.StartingWeekday,
+			SkipsWeekday:    m.SkipsWeekday,
 		})
 	}
 
@@ -210,6 +268,12 @@ func BuildExport(cal *Calendar, events []Event, includeEvents bool) *ChronicleEx
 
 	// Seasons.
 	for _, s := range cal.Seasons {
+		// Region-scoped seasons stay behind: the zone catalog they point at
+		// isn't part of a calendar export, so on import they'd collapse
+		// into the calendar-wide set.
+		if s.ZoneID != nil {
+			continue
+		}
 		export.Calendar.Seasons = append(export.Calendar.Seasons, ExportSeason{
 			Name:          s.Name,
 			StartMonth:    s.StartMonth,
@@ -253,6 +317,11 @@ func BuildExport(cal *Calendar, events []Event, includeEvents bool) *ChronicleEx
 		export.Calendar.Cycles = append(export.Calendar.Cycles, ec)
 	}
 
+	// Named years.
+	for _, yn := range cal.YearNames {
+		export.Calendar.YearNames = append(export.Calendar.YearNames, ExportYearName{Year: yn.Year, Name: yn.Name})
+	}
+
 	// Festivals.
 	for _, f := range cal.Festivals {
 		export.Calendar.Festivals = append(export.Calendar.Festivals, ExportFestival{
@@ -267,6 +336,18 @@ func BuildExport(cal *Calendar, events []Event, includeEvents bool) *ChronicleEx
 		})
 	}
 
+	// Event categories.
+	for _, c := range cal.EventCategories {
+		export.Calendar.Categories = append(export.Calendar.Categories, ExportCategory{
+			Slug:              c.Slug,
+			Name:              c.Name,
+			Icon:              c.Icon,
+			Color:             c.Color,
+			DefaultVisibility: c.DefaultVisibility,
+			SortOrder:         c.SortOrder,
+		})
+	}
+
 	// Events (optional).
 	if includeEvents && len(events) > 0 {
 		for _, evt := range events {
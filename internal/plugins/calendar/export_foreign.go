@@ -0,0 +1,367 @@
+// Package calendar — export_foreign.go builds exports in the Simple Calendar
+// (Foundry VTT) and Fantasy-Calendar.com formats. Both outputs reuse the
+// importer's wire structs (import.go) so whatever we export parses back
+// through DetectAndParse. Year naming maps where the format can express it
+// (SC yearNames, FC cycles); Chronicle-only concepts (festivals, weather,
+// real-time tracking) have no equivalent in either format and are dropped;
+// use the native export for a lossless round-trip.
+package calendar
+
+import (
+	"html"
+	"strconv"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// BuildExportAs builds an export of cal in the requested format; an empty
+// format means Chronicle native. Events are written only when includeEvents
+// is set, matching BuildExport.
+func BuildExportAs(format ImportFormat, cal *Calendar, events []Event, includeEvents bool) (any, error) {
+	if !includeEvents {
+		events = nil
+	}
+	switch format {
+	case "", FormatChronicle:
+		return BuildExport(cal, events, includeEvents), nil
+	case FormatSimpleCal:
+		return buildSimpleCalendarExport(cal, events), nil
+	case FormatFantasyCal:
+		return buildFantasyCalendarExport(cal, events), nil
+	default:
+		return nil, apperror.NewBadRequest("export format must be chronicle, simple-calendar, or fantasy-calendar")
+	}
+}
+
+// scExport is the Simple Calendar v2 export envelope.
+type scExport struct {
+	ExportVersion int                 `json:"exportVersion"`
+	Calendars     []scCalendar        `json:"calendars"`
+	Notes         map[string][]scNote `json:"notes,omitempty"`
+}
+
+// buildSimpleCalendarExport converts a fully-loaded calendar and its events
+// into a Simple Calendar v2 export. Events become journal-entry notes keyed
+// by the calendar ID. SC months/days are 0-indexed; biweekly and custom
+// recurrences have no SC equivalent and export as non-repeating notes.
+func buildSimpleCalendarExport(cal *Calendar, events []Event) *scExport {
+	sc := scCalendar{
+		ID:   cal.ID,
+		Name: cal.Name,
+		CurrentDate: scCurrentDate{
+			Year:    cal.CurrentYear,
+			Month:   cal.CurrentMonth - 1,
+			Day:     cal.CurrentDay - 1,
+			Seconds: (cal.CurrentHour*cal.MinutesPerHour + cal.CurrentMinute) * cal.SecondsPerMinute,
+		},
+		Time: scTime{
+			HoursInDay:      cal.HoursPerDay,
+			MinutesInHour:   cal.MinutesPerHour,
+			SecondsInMinute: cal.SecondsPerMinute,
+			GameTimeRatio:   1,
+		},
+		Year: scYear{NumericRepresentation: cal.CurrentYear},
+	}
+	if cal.EpochName != nil {
+		sc.Year.Postfix = *cal.EpochName
+	}
+	sc.Year.YearNames, sc.Year.YearNamesStart, sc.Year.YearNamingRule = scYearNamingExport(cal)
+	sc.LeapYear.Rule = "none"
+	if cal.LeapYearEvery > 0 {
+		sc.LeapYear = scLeapYear{Rule: "custom", CustomMod: cal.LeapYearEvery}
+	}
+
+	for i, m := range cal.Months {
+		sm := scMonth{
+			Name:                  m.Name,
+			NumericRepresentation: i + 1,
+			NumberOfDays:          m.Days,
+			NumberOfLeapYearDays:  m.Days + m.LeapYearDays,
+			Intercalary:           m.IsIntercalary,
+			IntercalaryInclude:    m.IsIntercalary && !m.SkipsWeekday,
+		}
+		if m.StartingWeekday != nil {
+			// SC names the weekday by numericRepresentation (i + 1 below).
+			w := *m.StartingWeekday + 1
+			sm.StartingWeekday = &w
+		}
+		sc.Months = append(sc.Months, sm)
+	}
+	for i, w := range cal.Weekdays {
+		sc.Weekdays = append(sc.Weekdays, scWeekday{
+			Name:                  w.Name,
+			NumericRepresentation: i + 1,
+			Restday:               w.IsRestDay,
+		})
+	}
+	for _, m := range cal.Moons {
+		sc.Moons = append(sc.Moons, scMoon{
+			Name:           m.Name,
+			CycleLength:    m.CycleDays,
+			CycleDayAdjust: m.PhaseOffset,
+			Color:          m.Color,
+		})
+	}
+	for _, s := range cal.Seasons {
+		if s.ZoneID != nil {
+			continue // single season set per format; see BuildExport
+		}
+		sc.Seasons = append(sc.Seasons, scSeason{
+			Name:          s.Name,
+			StartingMonth: s.StartMonth - 1,
+			StartingDay:   s.StartDay - 1,
+			Color:         s.Color,
+		})
+	}
+	for _, c := range cal.EventCategories {
+		sc.NoteCategories = append(sc.NoteCategories, scNoteCategory{Name: c.Name, Color: c.Color})
+	}
+
+	export := &scExport{ExportVersion: 2, Calendars: []scCalendar{sc}}
+	for _, evt := range events {
+		if export.Notes == nil {
+			export.Notes = map[string][]scNote{}
+		}
+		export.Notes[cal.ID] = append(export.Notes[cal.ID], eventToSCNote(cal, evt))
+	}
+	return export
+}
+
+// eventToSCNote converts one event into a Simple Calendar journal-entry note.
+func eventToSCNote(cal *Calendar, evt Event) scNote {
+	start := scNoteDate{Year: evt.Year, Month: evt.Month - 1, Day: evt.Day - 1}
+	if evt.StartHour != nil {
+		start.Hour = *evt.StartHour
+	}
+	if evt.StartMinute != nil {
+		start.Minute = *evt.StartMinute
+	}
+	end := start
+	if evt.EndYear != nil && evt.EndMonth != nil && evt.EndDay != nil {
+		end = scNoteDate{Year: *evt.EndYear, Month: *evt.EndMonth - 1, Day: *evt.EndDay - 1}
+		if evt.EndHour != nil {
+			end.Hour = *evt.EndHour
+		}
+		if evt.EndMinute != nil {
+			end.Minute = *evt.EndMinute
+		}
+	}
+
+	nd := &scNoteData{StartDate: start, EndDate: end, AllDay: evt.AllDay, Repeats: scRepeatNever}
+	if evt.IsRecurring && evt.RecurrenceType != nil {
+		switch *evt.RecurrenceType {
+		case RecurrenceWeekly:
+			nd.Repeats = scRepeatWeekly
+		case RecurrenceMonthly:
+			nd.Repeats = scRepeatMonthly
+		case RecurrenceYearly:
+			nd.Repeats = scRepeatYearly
+		}
+	}
+	// SC references categories by name.
+	if evt.Category != nil && *evt.Category != "" {
+		name := *evt.Category
+		if c := FindEventCategory(cal.EventCategories, name); c != nil {
+			name = c.Name
+		}
+		nd.Categories = []scCategoryRef{scCategoryRef(name)}
+	}
+
+	// Foundry ownership: 2 (observer) lets players read; 0 hides the note.
+	level := 2
+	if evt.Visibility == "dm_only" {
+		level = 0
+	}
+	note := scNote{Name: evt.Name, Ownership: map[string]int{"default": level}}
+	note.Flags.SimpleCalendar.NoteData = nd
+	if content := exportDescriptionHTML(evt); content != "" {
+		var page scNotePage
+		page.Text.Content = content
+		note.Pages = []scNotePage{page}
+	}
+	return note
+}
+
+// scYearNamingExport picks the SC year naming for a calendar: its explicit
+// named years under the "default" rule when they cover a contiguous run of
+// years, otherwise a one-name-per-year cycle under "repeat". Anything else
+// (gaps, multi-year entries) has no SC shape and is dropped.
+func scYearNamingExport(cal *Calendar) ([]string, int, string) {
+	if len(cal.YearNames) > 0 {
+		byYear := make(map[int]string, len(cal.YearNames))
+		start := cal.YearNames[0].Year
+		for _, yn := range cal.YearNames {
+			byYear[yn.Year] = yn.Name
+			start = min(start, yn.Year)
+		}
+		names := make([]string, 0, len(byYear))
+		for y := start; y < start+len(byYear); y++ {
+			name, ok := byYear[y]
+			if !ok {
+				return nil, 0, ""
+			}
+			names = append(names, name)
+		}
+		return names, start, "default"
+	}
+	for _, c := range cal.Cycles {
+		if c.EntryForYear(0) == nil || c.CycleLength != len(c.Entries) {
+			continue
+		}
+		names := make([]string, c.CycleLength)
+		for _, e := range c.Entries {
+			off := (e.YearOffset%c.CycleLength + c.CycleLength) % c.CycleLength
+			if names[off] != "" {
+				names = nil
+				break
+			}
+			names[off] = e.Name
+		}
+		if names != nil {
+			return names, 0, "repeat"
+		}
+	}
+	return nil, 0, ""
+}
+
+// fcCyclesExport converts yearly cycles whose entries are evenly spaced —
+// the only shape FC can express — inverting importFCCycles. Nil when no
+// cycle qualifies, so the export omits the block.
+func fcCyclesExport(cycles []Cycle) *fcCycles {
+	out := &fcCycles{}
+	for _, c := range cycles {
+		n := len(c.Entries)
+		if c.EntryForYear(0) == nil || c.CycleLength%n != 0 {
+			continue
+		}
+		step := c.CycleLength / n
+		names := make([]string, n)
+		phase := -1 // shared offset remainder; evenly spaced entries agree on it
+		for _, e := range c.Entries {
+			off := (e.YearOffset%c.CycleLength + c.CycleLength) % c.CycleLength
+			if phase < 0 {
+				phase = off % step
+			}
+			if off%step != phase || names[off/step] != "" {
+				names = nil
+				break
+			}
+			names[off/step] = e.Name
+		}
+		if names == nil {
+			continue
+		}
+		out.Data = append(out.Data, fcCycle{Length: step, Offset: (c.CycleLength - phase) % c.CycleLength, Names: names})
+		if out.Format != "" {
+			out.Format += " · "
+		}
+		out.Format += "{{" + strconv.Itoa(len(out.Data)) + "}}"
+	}
+	if len(out.Data) == 0 {
+		return nil
+	}
+	return out
+}
+
+// buildFantasyCalendarExport converts a fully-loaded calendar and its events
+// into a Fantasy-Calendar.com export. FC expresses recurrence as a condition
+// tree, so each event exports at its anchor date only.
+func buildFantasyCalendarExport(cal *Calendar, events []Event) *fcData {
+	fc := &fcData{
+		Name: cal.Name,
+		StaticData: fcStaticData{
+			Clock: fcClock{Enabled: true, Hours: cal.HoursPerDay, Minutes: cal.MinutesPerHour},
+		},
+		DynamicData: fcDynamicData{
+			Year:     cal.CurrentYear,
+			Timespan: cal.CurrentMonth - 1,
+			Day:      cal.CurrentDay,
+			Hour:     cal.CurrentHour,
+			Minute:   cal.CurrentMinute,
+		},
+	}
+
+	for i, m := range cal.Months {
+		tsType := "month"
+		if m.IsIntercalary {
+			tsType = "intercalary"
+		}
+		fc.StaticData.YearData.Timespans = append(fc.StaticData.YearData.Timespans, fcTimespan{
+			Name:     m.Name,
+			Type:     tsType,
+			Length:   m.Days,
+			Interval: 1,
+		})
+		// FC models leap days individually; one entry per extra day.
+		for d := 0; d < m.LeapYearDays && cal.LeapYearEvery > 0; d++ {
+			fc.StaticData.YearData.LeapDays = append(fc.StaticData.YearData.LeapDays, fcLeapDay{
+				Name:     m.Name + " Leap Day",
+				Timespan: i,
+				Interval: strconv.Itoa(cal.LeapYearEvery),
+			})
+		}
+	}
+	for _, w := range cal.Weekdays {
+		fc.StaticData.YearData.GlobalWeek = append(fc.StaticData.YearData.GlobalWeek, w.Name)
+	}
+	for _, m := range cal.Moons {
+		fc.StaticData.Moons = append(fc.StaticData.Moons, fcMoon{
+			Name:  m.Name,
+			Cycle: m.CycleDays,
+			Shift: m.PhaseOffset,
+			Color: m.Color,
+		})
+	}
+	for _, s := range cal.Seasons {
+		if s.ZoneID != nil {
+			continue // single season set per format; see BuildExport
+		}
+		fc.StaticData.Seasons.Data = append(fc.StaticData.Seasons.Data, fcSeason{
+			Name:  s.Name,
+			Color: [2]string{s.Color, s.Color},
+		})
+	}
+	for _, e := range cal.Eras {
+		era := fcEra{Name: e.Name, Date: fcDate{Year: e.StartYear}}
+		if e.Description != nil {
+			era.Description = *e.Description
+		}
+		fc.StaticData.Eras = append(fc.StaticData.Eras, era)
+	}
+
+	fc.StaticData.Cycles = fcCyclesExport(cal.Cycles)
+
+	for _, c := range cal.EventCategories {
+		fc.EventCategories = append(fc.EventCategories, fcEventCategory{
+			ID:            fcID(c.Slug),
+			Name:          c.Name,
+			EventSettings: fcEventSettings{Hide: c.DefaultVisibility == "dm_only"},
+		})
+	}
+	for _, evt := range events {
+		fe := fcEvent{
+			Name:        evt.Name,
+			Description: exportDescriptionHTML(evt),
+			Data:        fcEventData{Date: []fcInt{fcInt(evt.Year), fcInt(evt.Month - 1), fcInt(evt.Day)}},
+			Settings:    fcEventSettings{Hide: evt.Visibility == "dm_only"},
+		}
+		if evt.Category != nil {
+			fe.EventCategoryID = fcID(*evt.Category)
+		}
+		fc.Events = append(fc.Events, fe)
+	}
+	return fc
+}
+
+// exportDescriptionHTML returns the event description as HTML, which is
+// what both foreign formats store. Legacy plain-text descriptions are
+// escaped so they can't inject markup into the target app.
+func exportDescriptionHTML(evt Event) string {
+	if evt.HasRichText() {
+		return *evt.DescriptionHTML
+	}
+	if evt.Description != nil {
+		return html.EscapeString(*evt.Description)
+	}
+	return ""
+}
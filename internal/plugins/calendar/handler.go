@@ -7,8 +7,10 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/a-h/templ"
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
@@ -25,8 +27,9 @@ type Handler struct {
 	addonSvc       addons.AddonService
 	auditSvc       audit.AuditService
 	tierLister     TierDefinitionsLister
-	timelineLister TimelineLister // cross-plugin read for the Calendars dashboard (W1).
-	entityCreator  EntityCreator  // cross-plugin write for "create entity from event" (C-CAL-EDITOR-EXPANSION PR1).
+	timelineLister TimelineLister     // cross-plugin read for the Calendars dashboard (W1).
+	entityCreator  EntityCreator      // cross-plugin write for "create entity from event" (C-CAL-EDITOR-EXPANSION PR1).
+	campaignLister UserCampaignLister // cross-plugin read of the viewer's memberships for My events.
 }
 
 // TierDefinitionsLister surfaces the campaign-aware tier vocabulary
@@ -220,6 +223,7 @@ func (h *Handler) EmbedCalendar(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	events = FilterEventsByCategory(events, categoryFilterParam(c))
 
 	data := CalendarViewData{
 		Calendar:        cal,
@@ -372,7 +376,7 @@ func (h *Handler) UpdateCalendarAPI(c echo.Context) error {
 	return nil
 }
 
-// UpdateMonthsAPI replaces all months.
+// UpdateMonthsAPI replaces all months, optionally fixing stranded events.
 // PUT /campaigns/:id/calendars/:calId/months
 func (h *Handler) UpdateMonthsAPI(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -389,15 +393,22 @@ func (h *Handler) UpdateMonthsAPI(c echo.Context) error {
 		return apperror.NewBadRequest("invalid request")
 	}
 
-	if err := h.svc.SetMonths(ctx, cal.ID, months); err != nil {
+	// ?remedy=clamp|shift|delete fixes the events the change strands
+	// (see PreviewMonthsImpactAPI); without it events are left as they are.
+	remedy := c.QueryParam("remedy")
+	if err := h.svc.SetMonthsWithRemedy(ctx, cal.ID, months, remedy); err != nil {
 		return err
 	}
-	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarMonthsSet, "calendar", cal.ID, cal.Name,
-		map[string]any{"count": len(months)})
+	meta := map[string]any{"count": len(months)}
+	if remedy != "" {
+		meta["remedy"] = remedy
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarMonthsSet, "calendar", cal.ID, cal.Name, meta)
 	return nil
 }
 
-// UpdateWeekdaysAPI replaces all weekdays.
+// UpdateWeekdaysAPI replaces all weekdays, optionally fixing stranded
+// weekly events.
 // PUT /campaigns/:id/calendars/:calId/weekdays
 func (h *Handler) UpdateWeekdaysAPI(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -414,11 +425,17 @@ func (h *Handler) UpdateWeekdaysAPI(c echo.Context) error {
 		return apperror.NewBadRequest("invalid request")
 	}
 
-	if err := h.svc.SetWeekdays(ctx, cal.ID, weekdays); err != nil {
+	// ?remedy=clamp|shift|delete fixes the events the change strands
+	// (see PreviewWeekdaysImpactAPI); without it events are left as they are.
+	remedy := c.QueryParam("remedy")
+	if err := h.svc.SetWeekdaysWithRemedy(ctx, cal.ID, weekdays, remedy); err != nil {
 		return err
 	}
-	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarWeekdaysSet, "calendar", cal.ID, cal.Name,
-		map[string]any{"count": len(weekdays)})
+	meta := map[string]any{"count": len(weekdays)}
+	if remedy != "" {
+		meta["remedy"] = remedy
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarWeekdaysSet, "calendar", cal.ID, cal.Name, meta)
 	return nil
 }
 
@@ -502,6 +519,9 @@ func (h *Handler) CreateEventAPI(c echo.Context) error {
 		// default); AllDay pairs with the drawer's nil-times all-day model.
 		Tier   *string `json:"tier"`
 		AllDay bool    `json:"all_day"`
+		// LinkedEntities attaches attendees/locations in the same request
+		// (each {entity_id, role}); the picker's per-entity PUT still works.
+		LinkedEntities []EventEntityLink `json:"linked_entities"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return apperror.NewBadRequest("invalid request")
@@ -546,17 +566,83 @@ func (h *Handler) CreateEventAPI(c echo.Context) error {
 		Tier:               req.Tier,
 		AllDay:             req.AllDay,
 		CreatedBy:          userID,
+		LinkedEntities:     req.LinkedEntities,
 	})
 	if err != nil {
 		return err
 	}
 	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarEventCreated, "calendar_event", evt.ID, evt.Name,
 		map[string]any{"calendar_id": cal.ID, "year": req.Year, "month": req.Month, "day": req.Day,
-			"is_recurring": req.IsRecurring, "visibility": visibility})
+			"is_recurring": req.IsRecurring, "visibility": visibility, "linked_entities": len(req.LinkedEntities)})
 
 	return c.JSON(http.StatusCreated, evt)
 }
 
+// BulkCreateEventsAPI creates many events at once — seeding a setting's
+// festivals and holidays without dozens of drawer round-trips. Accepts a JSON
+// array of events (the Chronicle export event shape), or a CSV with a header
+// row, sent as the request body (Content-Type text/csv) or as a "file"
+// upload. Rows fail independently; the response lists any rejected rows.
+// POST /campaigns/:id/calendars/:calId/events/bulk
+func (h *Handler) BulkCreateEventsAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	isCSV := strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv")
+	if file, fileErr := c.FormFile("file"); fileErr == nil {
+		src, openErr := file.Open()
+		if openErr != nil {
+			return apperror.NewBadRequest("could not read uploaded file")
+		}
+		defer func() { _ = src.Close() }()
+		data, err = io.ReadAll(io.LimitReader(src, 10*1024*1024))
+		if err != nil {
+			return apperror.NewBadRequest("could not read uploaded file")
+		}
+		isCSV = strings.HasSuffix(strings.ToLower(file.Filename), ".csv")
+	} else {
+		data, err = io.ReadAll(io.LimitReader(c.Request().Body, 10*1024*1024))
+		if err != nil || len(data) == 0 {
+			return apperror.NewBadRequest("no file uploaded and no request body")
+		}
+	}
+
+	var events []ExportEvent
+	if isCSV {
+		events, err = ParseBulkEventsCSV(data)
+	} else {
+		events, err = ParseBulkEventsJSON(data)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Same dm_only gate as CreateEventAPI, applied per row.
+	if !cc.CanAuthorDmOnly() && !cc.IsSiteAdmin {
+		for i := range events {
+			if events[i].Visibility == "dm_only" {
+				events[i].Visibility = "everyone"
+			}
+		}
+	}
+
+	result, err := h.svc.BulkCreateEvents(ctx, cal.ID, auth.GetUserID(c), events)
+	if err != nil {
+		return err
+	}
+	// One audit entry for the batch rather than one per row.
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarEventsBulkCreated, "calendar", cal.ID, cal.Name,
+		map[string]any{"submitted": len(events), "created": result.Created, "failed": len(result.Errors)})
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // requireEventInCampaign fetches an event and verifies its calendar belongs to
 // the given campaign. Returns 404 for cross-campaign IDOR attempts.
 func (h *Handler) requireEventInCampaign(c echo.Context, eventID, campaignID string) (*Event, error) {
@@ -581,9 +667,12 @@ func (h *Handler) UpdateEventAPI(c echo.Context) error {
 	eventID := c.Param("eid")
 
 	// IDOR protection: verify event belongs to this campaign's calendar.
-	if _, err := h.requireEventInCampaign(c, eventID, cc.Campaign.ID); err != nil {
+	evt, err := h.requireEventInCampaign(c, eventID, cc.Campaign.ID)
+	if err != nil {
 		return err
 	}
+	// Copy the pre-edit state for the audit diff.
+	before := *evt
 
 	var req struct {
 		Name               string  `json:"name"`
@@ -650,7 +739,8 @@ func (h *Handler) UpdateEventAPI(c echo.Context) error {
 		return err
 	}
 	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarEventUpdated, "calendar_event", eventID, req.Name,
-		map[string]any{"year": req.Year, "month": req.Month, "day": req.Day, "visibility": visibility})
+		audit.WithChanges(map[string]any{"year": req.Year, "month": req.Month, "day": req.Day, "visibility": visibility},
+			h.eventChanges(c, &before)))
 	return nil
 }
 
@@ -693,11 +783,13 @@ func (h *Handler) UpdateEventVisibilityAPI(c echo.Context) error {
 		return apperror.NewBadRequest("invalid request")
 	}
 
+	before := *evt
 	if err := h.svc.UpdateEventVisibility(ctx, eventID, input); err != nil {
 		return err
 	}
 	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarEventVisibilityChanged, "calendar_event", eventID, evt.Name,
-		map[string]any{"old_visibility": evt.Visibility, "new_visibility": input.Visibility})
+		audit.WithChanges(map[string]any{"old_visibility": evt.Visibility, "new_visibility": input.Visibility},
+			h.eventChanges(c, &before)))
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
@@ -930,6 +1022,75 @@ func (h *Handler) UpdateFestivalsAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// GetTimePresetsAPI returns the GM console's quick-advance presets (the
+// defaults when the owner hasn't saved any).
+// GET /campaigns/:id/calendars/:calId/time-presets
+func (h *Handler) GetTimePresetsAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	calID := c.Param("calId")
+
+	cal, err := h.requireCalendarInCampaign(c, calID, cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+
+	presets, err := h.svc.GetTimePresets(c.Request().Context(), cal.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+	return c.JSON(http.StatusOK, presets)
+}
+
+// UpdateTimePresetsAPI replaces the quick-advance presets.
+// PUT /campaigns/:id/calendars/:calId/time-presets
+func (h *Handler) UpdateTimePresetsAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	calID := c.Param("calId")
+
+	cal, err := h.requireCalendarInCampaign(c, calID, cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+
+	var presets []TimePresetInput
+	if err := c.Bind(&presets); err != nil {
+		return respondSettingsError(c, apperror.NewBadRequest("invalid time presets payload"))
+	}
+
+	if err := h.svc.SetTimePresets(ctx, cal.ID, presets); err != nil {
+		return respondSettingsError(c, err)
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarTimePresetsSet, "calendar", cal.ID, cal.Name,
+		map[string]any{"count": len(presets)})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateYearNamesAPI replaces the calendar's explicitly named years.
+// PUT /campaigns/:id/calendars/:calId/year-names
+func (h *Handler) UpdateYearNamesAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	calID := c.Param("calId")
+
+	cal, err := h.requireCalendarInCampaign(c, calID, cc.Campaign.ID)
+	if err != nil {
+		return respondSettingsError(c, err)
+	}
+
+	var names []YearNameInput
+	if err := c.Bind(&names); err != nil {
+		return respondSettingsError(c, apperror.NewBadRequest("invalid year names payload"))
+	}
+
+	if err := h.svc.SetYearNames(ctx, cal.ID, names); err != nil {
+		return respondSettingsError(c, err)
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarYearNamesSet, "calendar", cal.ID, cal.Name,
+		map[string]any{"count": len(names)})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
 // UpdateEventCategoriesAPI replaces all event categories.
 // PUT /campaigns/:id/calendars/:calId/event-categories
 func (h *Handler) UpdateEventCategoriesAPI(c echo.Context) error {
@@ -955,6 +1116,70 @@ func (h *Handler) UpdateEventCategoriesAPI(c echo.Context) error {
 	return nil
 }
 
+// UpsertEventCategoryAPI creates or updates a single event category. The
+// slug comes from the path so a rename of the display name never changes
+// the key events reference.
+// PUT /campaigns/:id/calendars/:calId/event-categories/:slug
+func (h *Handler) UpsertEventCategoryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	calID := c.Param("calId")
+
+	cal, err := h.requireCalendarInCampaign(c, calID, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	var input EventCategoryInput
+	if err := c.Bind(&input); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	input.Slug = c.Param("slug")
+
+	if err := h.svc.UpsertEventCategory(ctx, cal.ID, input); err != nil {
+		return err
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarCategoryUpserted, "calendar", cal.ID, cal.Name,
+		map[string]any{"slug": input.Slug})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// DeleteEventCategoryAPI removes a single event category by slug.
+// DELETE /campaigns/:id/calendars/:calId/event-categories/:slug
+func (h *Handler) DeleteEventCategoryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	ctx := c.Request().Context()
+	calID := c.Param("calId")
+
+	cal, err := h.requireCalendarInCampaign(c, calID, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	slug := c.Param("slug")
+	if err := h.svc.DeleteEventCategory(ctx, cal.ID, slug); err != nil {
+		return err
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarCategoryDeleted, "calendar", cal.ID, cal.Name,
+		map[string]any{"slug": slug})
+	return c.NoContent(http.StatusNoContent)
+}
+
+// categoryFilterParam reads the ?category= filter shared by the grid,
+// timeline, and embed views. Accepts a comma-separated list of slugs
+// (repeated params also work); "none" selects uncategorized events.
+func categoryFilterParam(c echo.Context) []string {
+	var slugs []string
+	for _, raw := range c.QueryParams()["category"] {
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				slugs = append(slugs, s)
+			}
+		}
+	}
+	return slugs
+}
+
 // GetEventCategoriesAPI returns all event categories for a calendar.
 // GET /campaigns/:id/calendars/:calId/event-categories
 func (h *Handler) GetEventCategoriesAPI(c echo.Context) error {
@@ -1019,7 +1244,9 @@ func (h *Handler) ShowSettings(c echo.Context) error {
 	return middleware.Render(c, http.StatusOK, CalendarSettingsPage(cc, cal, csrfToken))
 }
 
-// AdvanceDateAPI moves the current date forward by N days.
+// AdvanceDateAPI moves the current date forward by N days. The audit entry
+// records the before/after stamps plus the GM console preset that fired it,
+// if any.
 // POST /campaigns/:id/calendars/:calId/advance
 func (h *Handler) AdvanceDateAPI(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -1032,7 +1259,8 @@ func (h *Handler) AdvanceDateAPI(c echo.Context) error {
 	}
 
 	var req struct {
-		Days int `json:"days"`
+		Days   int    `json:"days"`
+		Preset string `json:"preset"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return apperror.NewBadRequest("invalid request")
@@ -1040,13 +1268,21 @@ func (h *Handler) AdvanceDateAPI(c echo.Context) error {
 	if req.Days < 1 || req.Days > 3650 {
 		return apperror.NewBadRequest("days must be between 1 and 3650")
 	}
+	if len(req.Preset) > 100 {
+		return apperror.NewBadRequest("preset must be at most 100 characters")
+	}
 
-	if err := h.svc.AdvanceDate(ctx, cal.ID, req.Days); err != nil {
+	from := cal.FormatCurrentStamp()
+	if err := h.svc.AdvanceDate(ctx, cal.ID, auth.GetUserID(c), req.Days); err != nil {
 		return err
 	}
-	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarDateAdvanced, "calendar", cal.ID, cal.Name,
-		map[string]any{"days": req.Days})
-	return nil
+	to := h.currentStamp(ctx, cal.ID)
+	details := map[string]any{"days": req.Days, "from": from, "to": to}
+	if req.Preset != "" {
+		details["preset"] = req.Preset
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarDateAdvanced, "calendar", cal.ID, cal.Name, details)
+	return c.JSON(http.StatusOK, map[string]string{"from": from, "to": to})
 }
 
 // AdvanceTimeAPI moves the current time forward by hours and/or minutes,
@@ -1063,8 +1299,9 @@ func (h *Handler) AdvanceTimeAPI(c echo.Context) error {
 	}
 
 	var req struct {
-		Hours   int `json:"hours"`
-		Minutes int `json:"minutes"`
+		Hours   int    `json:"hours"`
+		Minutes int    `json:"minutes"`
+		Preset  string `json:"preset"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return apperror.NewBadRequest("invalid request")
@@ -1078,13 +1315,33 @@ func (h *Handler) AdvanceTimeAPI(c echo.Context) error {
 	if req.Hours > 87600 { // ~10 years of 24-hour days
 		return apperror.NewBadRequest("hours must be at most 87600")
 	}
+	if len(req.Preset) > 100 {
+		return apperror.NewBadRequest("preset must be at most 100 characters")
+	}
 
-	if err := h.svc.AdvanceTime(ctx, cal.ID, req.Hours, req.Minutes); err != nil {
+	from := cal.FormatCurrentStamp()
+	if err := h.svc.AdvanceTime(ctx, cal.ID, auth.GetUserID(c), req.Hours, req.Minutes); err != nil {
 		return err
 	}
-	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarTimeAdvanced, "calendar", cal.ID, cal.Name,
-		map[string]any{"hours": req.Hours, "minutes": req.Minutes})
-	return nil
+	to := h.currentStamp(ctx, cal.ID)
+	details := map[string]any{"hours": req.Hours, "minutes": req.Minutes, "from": from, "to": to}
+	if req.Preset != "" {
+		details["preset"] = req.Preset
+	}
+	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarTimeAdvanced, "calendar", cal.ID, cal.Name, details)
+	return c.JSON(http.StatusOK, map[string]string{"from": from, "to": to})
+}
+
+// currentStamp re-reads a calendar after an advance and returns its new
+// "YYYY-MM-DD HH:MM" stamp for the audit entry. The advance already
+// committed, so a failed re-read leaves "to" empty rather than failing
+// the request.
+func (h *Handler) currentStamp(ctx context.Context, calendarID string) string {
+	cal, err := h.svc.GetCalendarByID(ctx, calendarID)
+	if err != nil || cal == nil {
+		return ""
+	}
+	return cal.FormatCurrentStamp()
 }
 
 // (EntityEventsFragment — the per-entity calendar-events HTMX fragment — was
@@ -1135,6 +1392,41 @@ func (h *Handler) UpcomingEventsFragment(c echo.Context) error {
 	return middleware.Render(c, http.StatusOK, UpcomingEventsBlock(cc, cal, events))
 }
 
+// UpcomingDashboardFragment is the lazy-loaded body of the calendar_preview
+// dashboard block, registered with the campaigns handler. Cached per
+// viewer: event visibility depends on who is looking, not just their role.
+func (h *Handler) UpcomingDashboardFragment() campaigns.DashboardFragment {
+	return campaigns.DashboardFragment{
+		Render: func(ctx context.Context, req campaigns.DashboardFragmentRequest) (templ.Component, error) {
+			cc := req.Campaign
+			// The fragment route sits outside the calendar addon gate.
+			if h.addonSvc != nil {
+				if enabled, err := h.addonSvc.IsEnabledForCampaign(ctx, cc.Campaign.ID, "calendar"); err != nil || !enabled {
+					return templ.NopComponent, nil
+				}
+			}
+			limit := 5
+			if v, ok := req.Block.Config["limit"].(float64); ok && v >= 1 && v <= 20 {
+				limit = int(v)
+			}
+			cal, err := h.svc.GetCalendar(ctx, cc.Campaign.ID)
+			if err != nil {
+				return nil, err
+			}
+			var events []Event
+			if cal != nil {
+				events, err = h.svc.ListUpcomingEvents(ctx, cal.ID, limit, cc.VisibilityRole(), req.UserID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return UpcomingEventsDashboardBlock(cc, cal, events, limit), nil
+		},
+		Fresh: time.Minute,
+		Stale: 15 * time.Minute,
+	}
+}
+
 // ShowTimeline renders the timeline (list) view of calendar events.
 // GET /campaigns/:id/calendars/:calId/timeline
 func (h *Handler) ShowTimeline(c echo.Context) error {
@@ -1161,6 +1453,7 @@ func (h *Handler) ShowTimeline(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	events = FilterEventsByCategory(events, categoryFilterParam(c))
 
 	data := TimelineViewData{
 		Calendar:        cal,
@@ -1201,9 +1494,19 @@ func (h *Handler) ExportCalendarAPI(c echo.Context) error {
 		}
 	}
 
-	export := BuildExport(cal, events, includeEvents)
+	// ?format= selects a foreign format for moving calendars into Foundry
+	// (simple-calendar) or Fantasy-Calendar.com; default is Chronicle native.
+	format := ImportFormat(c.QueryParam("format"))
+	export, err := BuildExportAs(format, cal, events, includeEvents)
+	if err != nil {
+		return err
+	}
+	filename := fmt.Sprintf("%s-calendar.json", cc.Campaign.Slug)
+	if format != "" && format != FormatChronicle {
+		filename = fmt.Sprintf("%s-calendar-%s.json", cc.Campaign.Slug, format)
+	}
 	c.Response().Header().Set("Content-Disposition",
-		fmt.Sprintf(`attachment; filename="%s-calendar.json"`, cc.Campaign.Slug))
+		fmt.Sprintf(`attachment; filename="%s"`, filename))
 	return c.JSON(http.StatusOK, export)
 }
 
@@ -1258,6 +1561,12 @@ func (h *Handler) ImportCalendarAPI(c echo.Context) error {
 		slog.Error("import: failed to apply", slog.Any("error", err))
 		return apperror.NewInternal(fmt.Errorf("failed to apply import"))
 	}
+	// Events go in after the structure so their categories already exist
+	// when CreateEvent resolves category default visibility.
+	eventCount, evErr := h.svc.ImportEvents(ctx, cal.ID, auth.GetUserID(c), result.Events)
+	if evErr != nil {
+		slog.Warn("import: event import interrupted", slog.Any("error", evErr))
+	}
 	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarImported, "calendar", cal.ID, cal.Name,
 		map[string]any{
 			"format":   result.Format,
@@ -1266,6 +1575,7 @@ func (h *Handler) ImportCalendarAPI(c echo.Context) error {
 			"moons":    len(result.Moons),
 			"seasons":  len(result.Seasons),
 			"eras":     len(result.Eras),
+			"events":   eventCount,
 		})
 
 	// Return JSON response with summary.
@@ -1278,6 +1588,7 @@ func (h *Handler) ImportCalendarAPI(c echo.Context) error {
 		"moons":    len(result.Moons),
 		"seasons":  len(result.Seasons),
 		"eras":     len(result.Eras),
+		"events":   eventCount,
 	})
 }
 
@@ -1372,6 +1683,10 @@ func (h *Handler) ImportFromSetupAPI(c echo.Context) error {
 		slog.Error("import-setup: failed to apply", slog.Any("error", err))
 		return apperror.NewInternal(fmt.Errorf("failed to apply import"))
 	}
+	eventCount, evErr := h.svc.ImportEvents(ctx, cal.ID, auth.GetUserID(c), result.Events)
+	if evErr != nil {
+		slog.Warn("import-setup: event import interrupted", slog.Any("error", evErr))
+	}
 	h.logCalendarAudit(c, cc.Campaign.ID, audit.ActionCalendarImported, "calendar", cal.ID, cal.Name,
 		map[string]any{
 			"format":   result.Format,
@@ -1380,6 +1695,7 @@ func (h *Handler) ImportFromSetupAPI(c echo.Context) error {
 			"moons":    len(result.Moons),
 			"seasons":  len(result.Seasons),
 			"eras":     len(result.Eras),
+			"events":   eventCount,
 		})
 
 	// Auto-enable the calendar addon.
@@ -1492,24 +1808,17 @@ func (d CalendarViewData) AbsoluteDay(day int) int {
 	return total
 }
 
-// WeekdayIndex returns the weekday index (0-based) for a given day in the current month/year.
+// WeekdayIndex returns the weekday index (0-based) for a given day in the
+// current month/year, or -1 for a day outside the week. Shares the V2 path so
+// month weekday overrides land the same in both views.
 func (d CalendarViewData) WeekdayIndex(day int) int {
-	wl := d.Calendar.WeekLength()
-	if wl == 0 {
-		return 0
-	}
-	absDay := d.AbsoluteDay(day)
-	idx := absDay % wl
-	if idx < 0 {
-		idx += wl
-	}
-	return idx
+	return v2WeekdayIndexFor(d.Calendar, d.Year, d.MonthIndex, day)
 }
 
 // StartWeekdayOffset returns how many blank cells to render before day 1
 // of the current month in the grid.
 func (d CalendarViewData) StartWeekdayOffset() int {
-	return d.WeekdayIndex(1)
+	return max(d.WeekdayIndex(1), 0)
 }
 
 // TimelineViewData holds data for the chronological timeline view.
@@ -114,6 +114,12 @@ func (h *Handler) ShowV2(c echo.Context) error {
 		data.EntityTypes, _ = h.entityCreator.ListEntityTypes(ctx, cc.Campaign.ID)
 	}
 
+	// GM console quick-advance presets. Best-effort: a read failure just
+	// leaves the console with its fixed verbs.
+	if data.IsOwner && active != nil {
+		data.TimePresets, _ = h.svc.GetTimePresets(ctx, active.ID)
+	}
+
 	// Cursor (year/month/day) — fall back to the calendar's stored
 	// in-world clock when the URL omits them. Zero-calendar campaigns
 	// skip cursor population since there's no calendar to anchor to.
@@ -139,6 +145,13 @@ func (h *Handler) ShowV2(c echo.Context) error {
 				data.Day = v
 			}
 		}
+		// Clamp the day to the cursor month's length: the date-jump picker
+		// (and a month step from the 31st) can name a day the month lacks.
+		if data.Month >= 1 && data.Month <= len(active.Months) {
+			if dim := active.MonthDays(data.Month-1, data.Year); data.Day > dim {
+				data.Day = dim
+			}
+		}
 	}
 
 	// Load events for the visible window (Wave 1 PR 4 — Month/Week/Day
@@ -161,13 +174,14 @@ func (h *Handler) ShowV2(c echo.Context) error {
 			if events, err := h.svc.ListEventsForDateRange(ctx, active.ID, data.Year, startMonth, startDay, endMonth, endDay, role, userID); err == nil {
 				data.Events = events
 			}
-		case "ledger":
+		case "ledger", "year":
 			// The Ledger loads a ONE-YEAR window: the full displayed year
 			// (Jan 1 → last day of the last month). ListEventsForDateRange
 			// (NOT ListEventsForYear) is used deliberately — it projects
 			// recurrence via Event.OccursOn across the window, per the
 			// C-CAL-TIMELINE-V2-W1 data-layer ruling. filterEventsByUser runs
 			// inside the service, so dm_only rows are absent for players.
+			// The Year overview counts density from the same window.
 			lastMonth, lastDay := ledgerYearWindowEnd(active, data.Year)
 			if events, err := h.svc.ListEventsForDateRange(ctx, active.ID, data.Year, 1, 1, lastMonth, lastDay, role, userID); err == nil {
 				data.Events = events
@@ -178,6 +192,12 @@ func (h *Handler) ShowV2(c echo.Context) error {
 			}
 		}
 
+		// Category filter (?category=holiday,battle) applies to every view
+		// after visibility filtering, so a player can never use it to probe
+		// hidden events.
+		data.CategoryFilter = categoryFilterParam(c)
+		data.Events = FilterEventsByCategory(data.Events, data.CategoryFilter)
+
 		// Live ambient worldState (C-CAL-WORLDSTATE-PRODUCTION-PORT, 2a).
 		// Build the CATALOG Part-8 seed for the cursor date (dm_only
 		// celestial events filtered by role) and stash both the struct (for
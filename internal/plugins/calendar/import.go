@@ -1,5 +1,6 @@
-// Package calendar — import.go provides calendar import from three formats:
-// Chronicle native JSON, Simple Calendar (Foundry VTT), and Calendaria (Foundry VTT).
+// Package calendar — import.go provides calendar import from four formats:
+// Chronicle native JSON, Simple Calendar (Foundry VTT), Calendaria (Foundry VTT),
+// and Fantasy-Calendar.com.
 //
 // # Supported Formats
 //
@@ -11,12 +12,19 @@
 // "calendar" key containing "months", "weekdays", "time", "leapYear", etc.
 // Months use numberOfDays/numberOfLeapYearDays. Time uses hoursInDay/minutesInHour.
 // Seasons have startingMonth/startingDay. Moons have cycleLength/cycleDayAdjust.
+// Top-level "notes" import as events; noteCategories as event categories.
 //
 // ## Calendaria (Foundry VTT)
 // A newer Foundry VTT calendar module. Identified by top-level "months" as an
 // object (not array) with keyed entries, or by presence of "days.hoursPerDay".
 // Months use days/leapDays. Seasons use dayStart/dayEnd (day-of-year numbers).
-// Moons have cycleLength/referenceDate. Supports eras and festivals natively.
+// Moons have cycleLength/referenceDate. Supports eras and festivals natively;
+// festivals import as yearly recurring events.
+//
+// ## Fantasy-Calendar.com
+// Identified by top-level "static_data" + "dynamic_data". Timespans become
+// months; "events" with an anchor date import as events and
+// "event_categories" as event categories.
 package calendar
 
 import (
@@ -24,6 +32,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -47,7 +56,19 @@ type ImportResult struct {
 	Moons        []MoonInput      `json:"moons"`
 	Seasons      []Season         `json:"seasons"`
 	Eras         []EraInput       `json:"eras"`
-	Settings     ImportedSettings `json:"settings"`
+	// Categories are merged (upserted by slug) into the target calendar's
+	// existing categories rather than replacing them — events reference
+	// categories by slug, so a replace would orphan them.
+	Categories []EventCategoryInput `json:"categories,omitempty"`
+	// Events are created (never replaced) after the structure is applied,
+	// via CalendarService.ImportEvents. Visibility may be empty when the
+	// source format carries none — the category default then applies.
+	Events []ExportEvent `json:"events,omitempty"`
+	// Cycles and YearNames carry year naming. Unlike moons/seasons/eras they
+	// replace the calendar's only when non-empty (see repo ApplyImport).
+	Cycles    []CycleInput     `json:"cycles,omitempty"`
+	YearNames []YearNameInput  `json:"year_names,omitempty"`
+	Settings  ImportedSettings `json:"settings"`
 }
 
 // ImportedSettings holds calendar-level settings extracted from the import.
@@ -205,6 +226,26 @@ func parseChronicle(data []byte) (*ImportResult, error) {
 		result.Eras = append(result.Eras, EraInput(e))
 	}
 
+	// Copy event categories.
+	for _, c := range export.Calendar.Categories {
+		result.Categories = append(result.Categories, EventCategoryInput(c))
+	}
+
+	// Copy cycles + named years (older exports carry cycles only, or neither).
+	for _, c := range export.Calendar.Cycles {
+		ci := CycleInput{Name: c.Name, CycleLength: c.CycleLength, Type: c.Type, SortOrder: c.SortOrder}
+		for _, e := range c.Entries {
+			ci.Entries = append(ci.Entries, CycleEntryInput(e))
+		}
+		result.Cycles = append(result.Cycles, ci)
+	}
+	for _, yn := range export.Calendar.YearNames {
+		result.YearNames = append(result.YearNames, YearNameInput(yn))
+	}
+
+	// Events are already in the export shape.
+	result.Events = export.Events
+
 	return result, nil
 }
 
@@ -212,12 +253,14 @@ func parseChronicle(data []byte) (*ImportResult, error) {
 
 // scData is the top-level Simple Calendar export structure.
 type scData struct {
-	Calendar scCalendar `json:"calendar"`
+	Calendar scCalendar      `json:"calendar"`
+	Notes    json.RawMessage `json:"notes"`
 }
 
 // scCalendar holds the Simple Calendar configuration. Supports both v2 field names
 // and v1 legacy aliases (yearSettings, monthSettings, etc.) via custom UnmarshalJSON.
 type scCalendar struct {
+	ID             string           `json:"id"`
 	Name           string           `json:"name"`
 	CurrentDate    scCurrentDate    `json:"currentDate"`
 	General        scGeneral        `json:"general"`
@@ -365,6 +408,7 @@ type scYear struct {
 	FirstWeekday          int      `json:"firstWeekday"`
 	YearNames             []string `json:"yearNames"`
 	YearNamingRule        string   `json:"yearNamingRule"`
+	YearNamesStart        int      `json:"yearNamesStart"`
 }
 
 type scNoteCategory struct {
@@ -372,16 +416,103 @@ type scNoteCategory struct {
 	Color string `json:"color"`
 }
 
+// Simple Calendar NoteRepeat values.
+const (
+	scRepeatNever   = 0
+	scRepeatWeekly  = 1
+	scRepeatMonthly = 2
+	scRepeatYearly  = 3
+)
+
+// scNote is a Simple Calendar note. v2 exports store notes as Foundry
+// journal entries with the calendar data under
+// flags["foundryvtt-simple-calendar"].noteData; older exports flattened the
+// title and date onto the note itself. Both shapes are accepted.
+type scNote struct {
+	Name          string         `json:"name"`
+	Title         string         `json:"title,omitempty"`
+	Content       string         `json:"content,omitempty"`
+	Pages         []scNotePage   `json:"pages,omitempty"`
+	Flags         scNoteFlags    `json:"flags"`
+	Ownership     map[string]int `json:"ownership,omitempty"`
+	PlayerVisible *bool          `json:"playerVisible,omitempty"`
+
+	// Legacy flat fields. Month and day are 0-indexed like the rest of SC.
+	// omitempty keeps them out of our own (journal-entry shaped) exports.
+	Year       *int            `json:"year,omitempty"`
+	Month      int             `json:"month,omitempty"`
+	Day        int             `json:"day,omitempty"`
+	Hour       int             `json:"hour,omitempty"`
+	Minute     int             `json:"minute,omitempty"`
+	AllDay     bool            `json:"allDay,omitempty"`
+	Repeats    int             `json:"repeats,omitempty"`
+	Categories []scCategoryRef `json:"categories,omitempty"`
+}
+
+type scNotePage struct {
+	Text struct {
+		Content string `json:"content"`
+	} `json:"text"`
+}
+
+type scNoteFlags struct {
+	SimpleCalendar struct {
+		NoteData *scNoteData `json:"noteData"`
+	} `json:"foundryvtt-simple-calendar"`
+}
+
+type scNoteData struct {
+	StartDate  scNoteDate      `json:"startDate"`
+	EndDate    scNoteDate      `json:"endDate"`
+	AllDay     bool            `json:"allDay"`
+	Repeats    int             `json:"repeats"`
+	Categories []scCategoryRef `json:"categories"`
+}
+
+// scNoteDate is a note timestamp. Month and day are 0-indexed.
+type scNoteDate struct {
+	Year   int `json:"year"`
+	Month  int `json:"month"`
+	Day    int `json:"day"`
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+}
+
+// scCategoryRef is a note's category reference. Current SC versions store
+// the category name; some older exports embedded the whole category object.
+type scCategoryRef string
+
+// UnmarshalJSON accepts either a bare name or a {"name": ...} object.
+func (r *scCategoryRef) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = scCategoryRef(name)
+		return nil
+	}
+	var obj scNoteCategory
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*r = scCategoryRef(obj.Name)
+	return nil
+}
+
 // parseSimpleCalendar converts a Simple Calendar JSON export into an ImportResult.
 // Handles both v1 format (top-level "calendar" key) and v2 format ("calendars" array).
 func parseSimpleCalendar(data []byte) (*ImportResult, error) {
 	// Try v2 format first (has "calendars" array).
 	var v2 struct {
-		ExportVersion int          `json:"exportVersion"`
-		Calendars     []scCalendar `json:"calendars"`
+		ExportVersion int             `json:"exportVersion"`
+		Calendars     []scCalendar    `json:"calendars"`
+		Notes         json.RawMessage `json:"notes"`
 	}
 	if err := json.Unmarshal(data, &v2); err == nil && len(v2.Calendars) > 0 {
-		return parseSimpleCalendarInner(v2.Calendars[0])
+		result, err := parseSimpleCalendarInner(v2.Calendars[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Events = importNotes(scNotesFor(v2.Notes, v2.Calendars[0].ID))
+		return result, nil
 	}
 
 	// Fall back to v1 format (single "calendar" key).
@@ -390,7 +521,150 @@ func parseSimpleCalendar(data []byte) (*ImportResult, error) {
 		return nil, fmt.Errorf("parse simple calendar JSON: %w", err)
 	}
 
-	return parseSimpleCalendarInner(sc.Calendar)
+	result, err := parseSimpleCalendarInner(sc.Calendar)
+	if err != nil {
+		return nil, err
+	}
+	result.Events = importNotes(scNotesFor(sc.Notes, sc.Calendar.ID))
+	return result, nil
+}
+
+// scNotesFor extracts the notes belonging to one calendar. v2 exports key
+// notes by calendar ID; a flat array (older exports) applies as-is. When the
+// calendar's ID isn't a key but only one calendar's notes are present, those
+// are used — otherwise notes from sibling calendars would be mixed in.
+// Malformed notes sections are ignored rather than failing the import.
+func scNotesFor(raw json.RawMessage, calendarID string) []scNote {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []scNote
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	var byCal map[string][]scNote
+	if err := json.Unmarshal(raw, &byCal); err != nil {
+		return nil
+	}
+	if notes, ok := byCal[calendarID]; ok {
+		return notes
+	}
+	if len(byCal) == 1 {
+		for _, notes := range byCal {
+			return notes
+		}
+	}
+	return nil
+}
+
+// importNotes converts Simple Calendar notes into Chronicle events. Notes
+// without a name or date are skipped.
+func importNotes(notes []scNote) []ExportEvent {
+	var out []ExportEvent
+	for _, n := range notes {
+		nd, ok := n.data()
+		name := strings.TrimSpace(n.Name)
+		if name == "" {
+			name = strings.TrimSpace(n.Title)
+		}
+		if !ok || name == "" {
+			continue
+		}
+
+		evt := ExportEvent{
+			Name:       name,
+			Year:       nd.StartDate.Year,
+			Month:      nd.StartDate.Month + 1,
+			Day:        nd.StartDate.Day + 1,
+			AllDay:     nd.AllDay,
+			Visibility: n.visibility(),
+		}
+		if content := n.content(); content != "" {
+			evt.DescriptionHTML = &content
+		}
+		if !nd.AllDay {
+			hour, minute := nd.StartDate.Hour, nd.StartDate.Minute
+			evt.StartHour, evt.StartMinute = &hour, &minute
+		}
+		if nd.EndDate != nd.StartDate && nd.EndDate != (scNoteDate{}) {
+			endYear, endMonth, endDay := nd.EndDate.Year, nd.EndDate.Month+1, nd.EndDate.Day+1
+			evt.EndYear, evt.EndMonth, evt.EndDay = &endYear, &endMonth, &endDay
+			if !nd.AllDay {
+				endHour, endMinute := nd.EndDate.Hour, nd.EndDate.Minute
+				evt.EndHour, evt.EndMinute = &endHour, &endMinute
+			}
+		}
+		switch nd.Repeats {
+		case scRepeatWeekly:
+			rt := RecurrenceWeekly
+			evt.IsRecurring, evt.RecurrenceType = true, &rt
+		case scRepeatMonthly:
+			rt := RecurrenceMonthly
+			evt.IsRecurring, evt.RecurrenceType = true, &rt
+		case scRepeatYearly:
+			rt := RecurrenceYearly
+			evt.IsRecurring, evt.RecurrenceType = true, &rt
+		}
+		// Chronicle events carry one category; SC notes can have several,
+		// so the first one wins.
+		for _, c := range nd.Categories {
+			if slug := categorySlug(stripLocalizationKey(string(c))); slug != "" {
+				evt.Category = &slug
+				break
+			}
+		}
+		out = append(out, evt)
+	}
+	return out
+}
+
+// data returns the note's calendar data from whichever shape the export used.
+func (n scNote) data() (scNoteData, bool) {
+	if nd := n.Flags.SimpleCalendar.NoteData; nd != nil {
+		return *nd, true
+	}
+	if n.Year == nil {
+		return scNoteData{}, false
+	}
+	start := scNoteDate{Year: *n.Year, Month: n.Month, Day: n.Day, Hour: n.Hour, Minute: n.Minute}
+	return scNoteData{
+		StartDate:  start,
+		EndDate:    start,
+		AllDay:     n.AllDay,
+		Repeats:    n.Repeats,
+		Categories: n.Categories,
+	}, true
+}
+
+// content returns the note body HTML. Journal-entry notes keep it on the
+// first page; legacy notes carry it inline.
+func (n scNote) content() string {
+	if n.Content != "" {
+		return n.Content
+	}
+	if len(n.Pages) > 0 {
+		return n.Pages[0].Text.Content
+	}
+	return ""
+}
+
+// visibility maps Foundry ownership onto Chronicle visibility. Observer (2)
+// or higher for the default role means players can read the note. Notes with
+// no ownership info return "" so the category default applies.
+func (n scNote) visibility() string {
+	if level, ok := n.Ownership["default"]; ok {
+		if level >= 2 {
+			return "everyone"
+		}
+		return "dm_only"
+	}
+	if n.PlayerVisible != nil {
+		if *n.PlayerVisible {
+			return "everyone"
+		}
+		return "dm_only"
+	}
+	return ""
 }
 
 // parseSimpleCalendarInner does the actual conversion from a Simple Calendar
@@ -449,6 +723,10 @@ func parseSimpleCalendarInner(cal scCalendar) (*ImportResult, error) {
 			SortOrder:     i,
 			IsIntercalary: m.Intercalary,
 			LeapYearDays:  leapExtra,
+			// SC intercalary months stay out of the weekday count unless
+			// intercalaryInclude is set.
+			SkipsWeekday:    m.Intercalary && !m.IntercalaryInclude,
+			StartingWeekday: scStartingWeekday(m, cal.Weekdays),
 		})
 	}
 
@@ -497,9 +775,125 @@ func parseSimpleCalendarInner(cal scCalendar) (*ImportResult, error) {
 		})
 	}
 
+	// Named years.
+	result.YearNames, result.Cycles = scYearNaming(cal.Year)
+
+	// Note categories — Simple Calendar's per-note tags map onto Chronicle's
+	// event categories. SC has no icon or visibility per category, so those
+	// take the Chronicle defaults.
+	result.Categories = importCategories(cal.NoteCategories)
+
 	return result, nil
 }
 
+// importCategories converts Simple Calendar note categories into event
+// category inputs, deriving a slug from each name and dropping duplicates
+// (the slug is the unique key on calendar_event_categories).
+func importCategories(cats []scNoteCategory) []EventCategoryInput {
+	var out []EventCategoryInput
+	seen := map[string]bool{}
+	for _, c := range cats {
+		name := strings.TrimSpace(stripLocalizationKey(c.Name))
+		slug := categorySlug(name)
+		if name == "" || slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		// The category color column is VARCHAR(7); SC sometimes stores
+		// #RRGGBBAA, so drop any alpha channel.
+		color := normalizeColor(c.Color)
+		if len(color) > 7 {
+			color = color[:7]
+		}
+		out = append(out, EventCategoryInput{
+			Slug:              slug,
+			Name:              name,
+			Color:             color,
+			DefaultVisibility: "everyone",
+			SortOrder:         len(out),
+		})
+	}
+	return out
+}
+
+// categorySlug lowercases a category name and collapses every run of
+// non-alphanumerics into a single dash, capped to the 50-char column.
+func categorySlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > 50 {
+		slug = strings.TrimSuffix(slug[:50], "-")
+	}
+	return slug
+}
+
+// scYearNaming maps SC's yearNames onto Chronicle's year naming. The
+// "default" rule names consecutive years from yearNamesStart, which become
+// explicit named years (SC keeps showing the last name after the list runs
+// out; Chronicle leaves later years unnamed). "repeat" rotates through the
+// list — a yearly cycle — and "random" has no deterministic equivalent, so
+// it imports as a rotation too.
+func scYearNaming(y scYear) ([]YearNameInput, []CycleInput) {
+	if len(y.YearNames) == 0 {
+		return nil, nil
+	}
+	switch y.YearNamingRule {
+	case "repeat", "random":
+		n := len(y.YearNames)
+		cycle := CycleInput{Name: "Year names", CycleLength: n, Type: "yearly"}
+		for k, name := range y.YearNames {
+			if name = strings.TrimSpace(stripLocalizationKey(name)); name == "" {
+				continue
+			}
+			cycle.Entries = append(cycle.Entries, CycleEntryInput{
+				Name:       name,
+				YearOffset: ((y.YearNamesStart+k)%n + n) % n,
+				SortOrder:  k,
+			})
+		}
+		if len(cycle.Entries) == 0 {
+			return nil, nil
+		}
+		return nil, []CycleInput{cycle}
+	default:
+		var names []YearNameInput
+		for k, name := range y.YearNames {
+			if name = strings.TrimSpace(stripLocalizationKey(name)); name != "" {
+				names = append(names, YearNameInput{Year: y.YearNamesStart + k, Name: name})
+			}
+		}
+		return names, nil
+	}
+}
+
+// scStartingWeekday maps a month's startingWeekday — the weekday's
+// numericRepresentation, as SC numbers them — to a 0-based index into the
+// imported weekday list. Nil when unset or naming no weekday; intercalary
+// months outside the week never take one.
+func scStartingWeekday(m scMonth, weekdays []scWeekday) *int {
+	if m.StartingWeekday == nil || (m.Intercalary && !m.IntercalaryInclude) {
+		return nil
+	}
+	for i, w := range weekdays {
+		if w.NumericRepresentation == *m.StartingWeekday {
+			return &i
+		}
+	}
+	return nil
+}
+
 // dayBefore returns the month+day that is one day before the given month+day.
 // Uses the Simple Calendar months list for day counts. Both params are 1-indexed.
 func dayBefore(month, day int, scMonths []scMonth) (int, int) {
@@ -858,9 +1252,63 @@ func parseCalendaria(data []byte) (*ImportResult, error) {
 		})
 	}
 
+	// Festivals — fixed feast days become yearly recurring events.
+	result.Events = importFestivals(cal.Festivals, result.Months, result.Settings.CurrentYear)
+
 	return result, nil
 }
 
+// importFestivals converts Calendaria festivals into yearly recurring events
+// anchored at anchorYear (recurrence only projects forward from the base
+// date, so the anchor must precede any year the calendar will show).
+// Festival month/day are 1-based; a festival that falls outside the parsed
+// months is skipped rather than created on a day the grid can't render.
+// Output is sorted by date so re-imports create events in a stable order.
+func importFestivals(festivals map[string]calFestival, months []MonthInput, anchorYear int) []ExportEvent {
+	var out []ExportEvent
+	for _, f := range festivals {
+		name := strings.TrimSpace(stripLocalizationKey(f.Name))
+		if name == "" || f.Month < 1 || f.Month > len(months) || f.Day < 1 {
+			continue
+		}
+		if m := months[f.Month-1]; f.Day > m.Days+m.LeapYearDays {
+			continue
+		}
+		rt := RecurrenceYearly
+		evt := ExportEvent{
+			Name:           name,
+			Year:           anchorYear,
+			Month:          f.Month,
+			Day:            f.Day,
+			AllDay:         true,
+			IsRecurring:    true,
+			RecurrenceType: &rt,
+		}
+		if desc := strings.TrimSpace(f.Description); desc != "" {
+			evt.Description = &desc
+		}
+		if f.Color != "" {
+			color := normalizeColor(f.Color)
+			evt.Color = &color
+		}
+		if f.Icon != "" {
+			icon := f.Icon
+			evt.Icon = &icon
+		}
+		out = append(out, evt)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Month != out[j].Month {
+			return out[i].Month < out[j].Month
+		}
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
 // dayOfYearToMonthDay converts a 1-based day-of-year number to a 1-based
 // month index and day-of-month, using the parsed month list.
 func dayOfYearToMonthDay(dayOfYear int, months []MonthInput) (int, int) {
@@ -886,9 +1334,11 @@ func dayOfYearToMonthDay(dayOfYear int, months []MonthInput) (int, int) {
 
 // fcData is the top-level Fantasy-Calendar.com export structure.
 type fcData struct {
-	Name        string        `json:"name"`
-	StaticData  fcStaticData  `json:"static_data"`
-	DynamicData fcDynamicData `json:"dynamic_data"`
+	Name            string            `json:"name"`
+	StaticData      fcStaticData      `json:"static_data"`
+	DynamicData     fcDynamicData     `json:"dynamic_data"`
+	Events          []fcEvent         `json:"events"`
+	EventCategories []fcEventCategory `json:"event_categories"`
 }
 
 type fcStaticData struct {
@@ -897,6 +1347,21 @@ type fcStaticData struct {
 	Clock    fcClock    `json:"clock"`
 	Seasons  fcSeasons  `json:"seasons"`
 	Eras     []fcEra    `json:"eras"`
+	Cycles   *fcCycles  `json:"cycles,omitempty"`
+}
+
+// fcCycles is FC's year-naming cycles block: each cycle steps to its next
+// name every Length years, shifted by Offset; Format combines the cycles'
+// current names ("Year of the {{1}}").
+type fcCycles struct {
+	Format string    `json:"format"`
+	Data   []fcCycle `json:"data"`
+}
+
+type fcCycle struct {
+	Length int      `json:"length"`
+	Offset int      `json:"offset"`
+	Names  []string `json:"names"`
 }
 
 type fcYearData struct {
@@ -978,6 +1443,67 @@ type fcDynamicData struct {
 	Minute   int `json:"minute"`
 }
 
+// fcEvent is a Fantasy-Calendar event. Recurrence in FC is a free-form
+// condition tree; only the anchor date (data.date) is imported.
+type fcEvent struct {
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	EventCategoryID fcID            `json:"event_category_id"`
+	Data            fcEventData     `json:"data"`
+	Settings        fcEventSettings `json:"settings"`
+}
+
+type fcEventData struct {
+	// Date is [year, timespan index (0-based), day (1-based)].
+	Date []fcInt `json:"date"`
+}
+
+type fcEventSettings struct {
+	Color string `json:"color,omitempty"`
+	Text  string `json:"text,omitempty"`
+	Hide  bool   `json:"hide"`
+}
+
+type fcEventCategory struct {
+	ID               fcID               `json:"id"`
+	Name             string             `json:"name"`
+	CategorySettings fcCategorySettings `json:"category_settings"`
+	EventSettings    fcEventSettings    `json:"event_settings"`
+}
+
+type fcCategorySettings struct {
+	Hide bool `json:"hide"`
+}
+
+// fcID is an identifier FC emits as a number in older exports and a string
+// slug in newer ones.
+type fcID string
+
+// UnmarshalJSON accepts a JSON number or string. null yields "".
+func (id *fcID) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		*id = ""
+		return nil
+	}
+	*id = fcID(strings.Trim(s, `"`))
+	return nil
+}
+
+// fcInt is an integer FC sometimes quotes. Unparseable values decode as 0.
+type fcInt int
+
+// UnmarshalJSON accepts a JSON number or a quoted number.
+func (n *fcInt) UnmarshalJSON(data []byte) error {
+	v, err := strconv.Atoi(strings.Trim(strings.TrimSpace(string(data)), `"`))
+	if err != nil {
+		*n = 0
+		return nil
+	}
+	*n = fcInt(v)
+	return nil
+}
+
 // parseFantasyCalendar converts a Fantasy-Calendar.com JSON export into an ImportResult.
 func parseFantasyCalendar(data []byte) (*ImportResult, error) {
 	var fc fcData
@@ -1100,9 +1626,108 @@ func parseFantasyCalendar(data []byte) (*ImportResult, error) {
 		})
 	}
 
+	// Year-naming cycles.
+	if fc.StaticData.Cycles != nil {
+		result.Cycles = importFCCycles(fc.StaticData.Cycles.Data)
+	}
+
+	// Event categories and events.
+	result.Categories = importFCCategories(fc.EventCategories)
+	result.Events = importFCEvents(fc.Events, fc.EventCategories)
+
 	return result, nil
 }
 
+// importFCCycles converts FC cycles to yearly Chronicle cycles. FC names
+// year y with names[floor((y + offset) / length) % len(names)]; the
+// equivalent cycle spans length × len(names) years with name k starting at
+// year offset k × length − offset (mod the span).
+func importFCCycles(cycles []fcCycle) []CycleInput {
+	var out []CycleInput
+	for _, c := range cycles {
+		if c.Length <= 0 || len(c.Names) == 0 {
+			continue
+		}
+		span := c.Length * len(c.Names)
+		ci := CycleInput{Name: fmt.Sprintf("Cycle %d", len(out)+1), CycleLength: span, Type: "yearly", SortOrder: len(out)}
+		for k, name := range c.Names {
+			ci.Entries = append(ci.Entries, CycleEntryInput{
+				Name:       strings.TrimSpace(name),
+				YearOffset: ((k*c.Length-c.Offset)%span + span) % span,
+				SortOrder:  k,
+			})
+		}
+		out = append(out, ci)
+	}
+	return out
+}
+
+// importFCCategories converts FC event categories. A category that hides
+// its events from players becomes a dm_only default. FC colors are named
+// swatches ("Dark-Solid"), not hex, so the Chronicle default color is used.
+func importFCCategories(cats []fcEventCategory) []EventCategoryInput {
+	var out []EventCategoryInput
+	seen := map[string]bool{}
+	for _, c := range cats {
+		name := strings.TrimSpace(c.Name)
+		slug := categorySlug(name)
+		if name == "" || slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		vis := "everyone"
+		if c.CategorySettings.Hide || c.EventSettings.Hide {
+			vis = "dm_only"
+		}
+		out = append(out, EventCategoryInput{
+			Slug:              slug,
+			Name:              name,
+			DefaultVisibility: vis,
+			SortOrder:         len(out),
+		})
+	}
+	return out
+}
+
+// importFCEvents converts FC events that have an anchor date. Events defined
+// purely by conditions (e.g. "every Monday") have no date and are skipped.
+// An event hidden in FC imports as dm_only; otherwise visibility is left
+// empty so the category default applies.
+func importFCEvents(events []fcEvent, cats []fcEventCategory) []ExportEvent {
+	catSlugs := map[fcID]string{}
+	for _, c := range cats {
+		if c.ID != "" {
+			catSlugs[c.ID] = categorySlug(c.Name)
+		}
+	}
+
+	var out []ExportEvent
+	for _, e := range events {
+		name := strings.TrimSpace(e.Name)
+		if name == "" || len(e.Data.Date) < 3 {
+			continue
+		}
+		evt := ExportEvent{
+			Name:   name,
+			Year:   int(e.Data.Date[0]),
+			Month:  int(e.Data.Date[1]) + 1,
+			Day:    int(e.Data.Date[2]),
+			AllDay: true,
+		}
+		if e.Settings.Hide {
+			evt.Visibility = "dm_only"
+		}
+		if desc := strings.TrimSpace(e.Description); desc != "" {
+			evt.DescriptionHTML = &desc
+		}
+		if slug := catSlugs[e.EventCategoryID]; slug != "" {
+			evt.Category = &slug
+		}
+		out = append(out, evt)
+	}
+	return out
+}
+
 // --- Helpers ---
 
 // stripLocalizationKey removes Foundry VTT localization prefixes from names.
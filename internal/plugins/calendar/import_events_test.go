@@ -0,0 +1,268 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseSimpleCalendar_Notes(t *testing.T) {
+	data := []byte(`{
+		"exportVersion": 2,
+		"calendars": [{
+			"id": "cal-a",
+			"months": [{"name": "Hammer", "numberOfDays": 30}, {"name": "Alturiak", "numberOfDays": 30}],
+			"weekdays": [{"name": "One"}],
+			"noteCategories": [{"name": "Holy Day", "color": "#ff0000"}]
+		}],
+		"notes": {
+			"cal-a": [
+				{
+					"name": "Midwinter",
+					"pages": [{"text": {"content": "<p>Feast</p>"}}],
+					"ownership": {"default": 2},
+					"flags": {"foundryvtt-simple-calendar": {"noteData": {
+						"startDate": {"year": 1492, "month": 1, "day": 4, "hour": 18, "minute": 30},
+						"endDate": {"year": 1492, "month": 1, "day": 4, "hour": 18, "minute": 30},
+						"repeats": 1,
+						"categories": ["Holy Day"]
+					}}}
+				},
+				{
+					"name": "Secret council",
+					"ownership": {"default": 0},
+					"flags": {"foundryvtt-simple-calendar": {"noteData": {
+						"startDate": {"year": 1492, "month": 0, "day": 0},
+						"endDate": {"year": 1492, "month": 0, "day": 2},
+						"allDay": true
+					}}}
+				},
+				{"name": "No date"}
+			],
+			"cal-b": [
+				{"name": "Other calendar", "flags": {"foundryvtt-simple-calendar": {"noteData": {"startDate": {"year": 1}}}}}
+			]
+		}
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Events) != 2 {
+		t.Fatalf("got %d events, want 2 (undated and other-calendar notes dropped)", len(result.Events))
+	}
+
+	first := result.Events[0]
+	if first.Year != 1492 || first.Month != 2 || first.Day != 5 {
+		t.Errorf("date = %d-%d-%d, want 1492-2-5 (SC is 0-indexed)", first.Year, first.Month, first.Day)
+	}
+	if first.StartHour == nil || *first.StartHour != 18 || first.EndYear != nil {
+		t.Errorf("expected 18:30 start with no end date, got %+v", first)
+	}
+	if first.Visibility != "everyone" {
+		t.Errorf("Visibility = %q, want everyone for observer ownership", first.Visibility)
+	}
+	if first.Category == nil || *first.Category != "holy-day" {
+		t.Errorf("Category = %v, want holy-day", first.Category)
+	}
+	if !first.IsRecurring || first.RecurrenceType == nil || *first.RecurrenceType != RecurrenceWeekly {
+		t.Errorf("expected weekly recurrence, got %+v", first)
+	}
+	if first.DescriptionHTML == nil || *first.DescriptionHTML != "<p>Feast</p>" {
+		t.Errorf("DescriptionHTML = %v, want page content", first.DescriptionHTML)
+	}
+
+	second := result.Events[1]
+	if second.Visibility != "dm_only" {
+		t.Errorf("Visibility = %q, want dm_only for no ownership", second.Visibility)
+	}
+	if !second.AllDay || second.StartHour != nil {
+		t.Errorf("expected an all-day event with no start time, got %+v", second)
+	}
+	if second.EndDay == nil || *second.EndDay != 3 {
+		t.Errorf("EndDay = %v, want 3", second.EndDay)
+	}
+}
+
+func TestParseSimpleCalendar_LegacyNotes(t *testing.T) {
+	data := []byte(`{
+		"calendar": {"months": [{"name": "Hammer", "numberOfDays": 30}]},
+		"notes": [
+			{"title": "Old note", "year": 10, "month": 0, "day": 9, "playerVisible": false, "categories": [{"name": "Battle"}]}
+		]
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Events) != 1 {
+		t.Fatalf("got %d events, want 1", len(result.Events))
+	}
+	evt := result.Events[0]
+	if evt.Name != "Old note" || evt.Day != 10 || evt.Visibility != "dm_only" {
+		t.Errorf("event = %+v", evt)
+	}
+	if evt.Category == nil || *evt.Category != "battle" {
+		t.Errorf("Category = %v, want battle", evt.Category)
+	}
+}
+
+func TestParseFantasyCalendar_Events(t *testing.T) {
+	data := []byte(`{
+		"name": "Exandria",
+		"static_data": {"year_data": {"global_week": ["Miresen"], "timespans": [{"name": "Horisal", "length": 29}, {"name": "Misuthar", "length": 30}]}},
+		"dynamic_data": {"year": 812},
+		"event_categories": [
+			{"id": 3, "name": "Secret Rites", "event_settings": {"hide": true}},
+			{"id": "festival", "name": "Festival"}
+		],
+		"events": [
+			{"name": "New Dawn", "description": "<p>Year start</p>", "event_category_id": "festival", "data": {"date": [812, 0, 1]}},
+			{"name": "Blood moon", "event_category_id": 3, "data": {"date": ["812", "1", "12"]}},
+			{"name": "Hidden", "settings": {"hide": true}, "data": {"date": [812, 1, 2]}},
+			{"name": "Every week", "event_category_id": -1, "data": {"conditions": []}}
+		]
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Categories) != 2 || result.Categories[0].DefaultVisibility != "dm_only" {
+		t.Fatalf("categories = %+v, want secret-rites as dm_only default", result.Categories)
+	}
+	if len(result.Events) != 3 {
+		t.Fatalf("got %d events, want 3 (condition-only event dropped)", len(result.Events))
+	}
+
+	tests := []struct {
+		idx      int
+		month    int
+		day      int
+		category string
+		vis      string
+	}{
+		{idx: 0, month: 1, day: 1, category: "festival", vis: ""},
+		{idx: 1, month: 2, day: 12, category: "secret-rites", vis: ""},
+		{idx: 2, month: 2, day: 2, vis: "dm_only"},
+	}
+	for _, tt := range tests {
+		evt := result.Events[tt.idx]
+		if evt.Month != tt.month || evt.Day != tt.day {
+			t.Errorf("%s: date = %d/%d, want %d/%d", evt.Name, evt.Month, evt.Day, tt.month, tt.day)
+		}
+		got := ""
+		if evt.Category != nil {
+			got = *evt.Category
+		}
+		if got != tt.category {
+			t.Errorf("%s: category = %q, want %q", evt.Name, got, tt.category)
+		}
+		if evt.Visibility != tt.vis {
+			t.Errorf("%s: visibility = %q, want %q", evt.Name, evt.Visibility, tt.vis)
+		}
+	}
+}
+
+func TestBuildExportAs_ForeignRoundTrip(t *testing.T) {
+	hour, minute := 9, 15
+	holiday := "holiday"
+	desc := "plain <text>"
+	cal := &Calendar{
+		ID:               "cal-1",
+		Name:             "Harptos",
+		CurrentYear:      1492,
+		CurrentMonth:     1,
+		CurrentDay:       1,
+		HoursPerDay:      24,
+		MinutesPerHour:   60,
+		SecondsPerMinute: 60,
+		Months:           []Month{{Name: "Hammer", Days: 30}, {Name: "Alturiak", Days: 30}},
+		Weekdays:         []Weekday{{Name: "One"}},
+		EventCategories:  []EventCategory{{Slug: holiday, Name: "Holiday", Color: "#ff0000", DefaultVisibility: "everyone"}},
+	}
+	events := []Event{
+		{Name: "Shieldmeet", Year: 1492, Month: 2, Day: 10, StartHour: &hour, StartMinute: &minute, Category: &holiday, Visibility: "everyone", Description: &desc},
+		{Name: "Plot twist", Year: 1493, Month: 1, Day: 3, AllDay: true, Visibility: "dm_only"},
+	}
+
+	for _, format := range []ImportFormat{FormatSimpleCal, FormatFantasyCal} {
+		t.Run(string(format), func(t *testing.T) {
+			export, err := BuildExportAs(format, cal, events, true)
+			if err != nil {
+				t.Fatalf("build export: %v", err)
+			}
+			raw, err := json.Marshal(export)
+			if err != nil {
+				t.Fatalf("marshal export: %v", err)
+			}
+			result, err := DetectAndParse(raw)
+			if err != nil {
+				t.Fatalf("re-parse export: %v", err)
+			}
+			if result.Format != format {
+				t.Errorf("detected %q, want %q", result.Format, format)
+			}
+			if len(result.Months) != 2 || len(result.Categories) != 1 {
+				t.Errorf("structure lost: %d months, %d categories", len(result.Months), len(result.Categories))
+			}
+			if len(result.Events) != 2 {
+				t.Fatalf("got %d events, want 2", len(result.Events))
+			}
+			first, second := result.Events[0], result.Events[1]
+			if first.Name != "Shieldmeet" || first.Year != 1492 || first.Month != 2 || first.Day != 10 {
+				t.Errorf("first event = %+v", first)
+			}
+			if first.Category == nil || *first.Category != holiday {
+				t.Errorf("category = %v, want holiday", first.Category)
+			}
+			if first.DescriptionHTML == nil || *first.DescriptionHTML != "plain &lt;text&gt;" {
+				t.Errorf("description = %v, want escaped plain text", first.DescriptionHTML)
+			}
+			if second.Visibility != "dm_only" || second.Year != 1493 {
+				t.Errorf("second event = %+v", second)
+			}
+		})
+	}
+}
+
+func TestBuildExportAs_UnknownFormat(t *testing.T) {
+	_, err := BuildExportAs("ical", &Calendar{}, nil, false)
+	assertAppError(t, err, 400)
+}
+
+func TestImportEvents_SkipsInvalidRows(t *testing.T) {
+	var created []*Event
+	repo := &mockCalendarRepo{
+		createEventFn: func(_ context.Context, evt *Event) error {
+			created = append(created, evt)
+			return nil
+		},
+	}
+	svc := newTestCalendarService(repo)
+
+	n, err := svc.ImportEvents(context.Background(), "cal-1", "user-1", []ExportEvent{
+		{Name: "Good", Year: 1, Month: 1, Day: 1},
+		{Name: "", Year: 1, Month: 1, Day: 1},
+		{Name: "Bad day", Year: 1, Month: 1, Day: 0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(created) != 1 {
+		t.Fatalf("created %d events (returned %d), want 1", len(created), n)
+	}
+	if created[0].Visibility != "everyone" || created[0].CreatedBy == nil || *created[0].CreatedBy != "user-1" {
+		t.Errorf("created event = %+v", created[0])
+	}
+}
+
+func TestImportEvents_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, err := newTestCalendarService(&mockCalendarRepo{}).ImportEvents(ctx, "cal-1", "user-1",
+		[]ExportEvent{{Name: "Never", Year: 1, Month: 1, Day: 1}})
+	if !errors.Is(err, context.Canceled) || n != 0 {
+		t.Fatalf("got (%d, %v), want (0, context.Canceled)", n, err)
+	}
+}
@@ -0,0 +1,3 @@
+-- Revert per-category default visibility.
+ALTER TABLE calendar_event_categories
+    DROP COLUMN IF EXISTS default_visibility;
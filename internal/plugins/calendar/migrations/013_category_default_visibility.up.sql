@@ -0,0 +1,12 @@
+-- Event categories as first-class records: per-category default visibility.
+-- An event created with a category but no explicit visibility inherits the
+-- category's default (e.g. a "gm-secret" category defaults to dm_only) so the
+-- GM doesn't have to remember to flip visibility on every plot-hook event.
+--
+-- `calendar_event_categories` is this plugin's own table (migration 001), so
+-- this plugin-scoped migration references only a plugin table — safe.
+--
+-- DEFAULT 'everyone' backfills every existing category with today's behavior
+-- (events default to everyone), so nothing changes on upgrade.
+ALTER TABLE calendar_event_categories
+    ADD COLUMN IF NOT EXISTS default_visibility VARCHAR(20) NOT NULL DEFAULT 'everyone';
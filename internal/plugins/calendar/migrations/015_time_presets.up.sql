@@ -0,0 +1,20 @@
+-- Owner-configurable quick-advance presets for the GM console clock
+-- ("Short rest +1h", "Long rest +8h", "Travel day +1d"). Replace-all list per
+-- calendar, same shape as calendar_festivals (migration 003). A calendar with
+-- no rows falls back to the built-in defaults in the service layer, so no
+-- seed/backfill is needed here.
+--
+-- A preset advances EITHER by days OR by hours+minutes (validated in the
+-- service layer) so one click maps onto exactly one of the two advance
+-- endpoints — and one audit entry.
+CREATE TABLE IF NOT EXISTS calendar_time_presets (
+    id          INT          AUTO_INCREMENT PRIMARY KEY,
+    calendar_id VARCHAR(36)  NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    days        INT          NOT NULL DEFAULT 0,
+    hours       INT          NOT NULL DEFAULT 0,
+    minutes     INT          NOT NULL DEFAULT 0,
+    sort_order  INT          NOT NULL DEFAULT 0,
+    CONSTRAINT fk_cal_time_presets FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE,
+    INDEX idx_cal_time_presets (calendar_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,37 @@
+-- Historical log of clock advancements: one row per AdvanceDate /
+-- AdvanceTime call (GM console presets, the V1 advance buttons, Foundry
+-- sync). Backs the "campaign so far" timeline and the undo of the most
+-- recent advancement.
+--
+-- user_id is NULL for token-authenticated sync writes (no user session).
+-- It is deliberately NOT a foreign key: history should outlive a departed
+-- member, and the timeline LEFT JOINs users for the display name.
+--
+-- advanced_minutes is the move expressed in the calendar's own minutes at the
+-- time of writing (days × hours_per_day × minutes_per_hour for date moves),
+-- so elapsed-time totals are a SUM rather than date arithmetic over
+-- variable-length months.
+--
+-- undone_at marks a row reverted by undo; undone rows stay for the record
+-- but no longer count toward elapsed time.
+CREATE TABLE IF NOT EXISTS calendar_date_history (
+    id               INT          AUTO_INCREMENT PRIMARY KEY,
+    calendar_id      VARCHAR(36)  NOT NULL,
+    user_id          VARCHAR(36)  DEFAULT NULL,
+    kind             VARCHAR(10)  NOT NULL,
+    from_year        INT          NOT NULL,
+    from_month       INT          NOT NULL,
+    from_day         INT          NOT NULL,
+    from_hour        INT          NOT NULL DEFAULT 0,
+    from_minute      INT          NOT NULL DEFAULT 0,
+    to_year          INT          NOT NULL,
+    to_month         INT          NOT NULL,
+    to_day           INT          NOT NULL,
+    to_hour          INT          NOT NULL DEFAULT 0,
+    to_minute        INT          NOT NULL DEFAULT 0,
+    advanced_minutes INT          NOT NULL DEFAULT 0,
+    created_at       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    undone_at        DATETIME     DEFAULT NULL,
+    CONSTRAINT fk_cal_date_history FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE,
+    INDEX idx_cal_date_history (calendar_id, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Revert per-month weekday overrides.
+ALTER TABLE calendar_months
+    DROP COLUMN IF EXISTS skips_weekday,
+    DROP COLUMN IF EXISTS starting_weekday;
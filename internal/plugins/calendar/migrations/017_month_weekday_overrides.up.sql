@@ -0,0 +1,13 @@
+-- Per-month weekday overrides (Simple Calendar's startingWeekday and
+-- intercalary "include in weekday count").
+--
+-- starting_weekday pins day 1 of a month to a weekday (0-based index into the
+-- calendar's weekdays); NULL lets the weekday flow on from the previous month.
+-- skips_weekday marks an intercalary month whose days sit outside the week
+-- and don't advance it. Both are validated in the service layer.
+--
+-- NULL / FALSE backfill every existing month with today's geometry, so no
+-- calendar's weekday columns move on upgrade.
+ALTER TABLE calendar_months
+    ADD COLUMN IF NOT EXISTS starting_weekday INT NULL,
+    ADD COLUMN IF NOT EXISTS skips_weekday BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+-- Named years: an explicit name per year ("Year of Three Ships Sailing"),
+-- the list Simple Calendar calls yearNames. Cyclic naming (zodiac-style
+-- rotations, FC cycles, SC's "repeat" rule) reuses calendar_cycles
+-- (migration 003); this table holds only the one-off names.
+--
+-- One name per year per calendar (UNIQUE), replace-all like the other
+-- calendar sub-resources.
+CREATE TABLE IF NOT EXISTS calendar_year_names (
+    id          INT          AUTO_INCREMENT PRIMARY KEY,
+    calendar_id VARCHAR(36)  NOT NULL,
+    year        INT          NOT NULL,
+    name        VARCHAR(200) NOT NULL,
+    CONSTRAINT fk_cal_year_names FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_cal_year_names (calendar_id, year)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Revert regional climate.
+DROP TABLE IF EXISTS calendar_location_zones;
+DROP TABLE IF EXISTS calendar_zone_weather;
+ALTER TABLE calendar_seasons DROP COLUMN IF EXISTS zone_id;
@@ -0,0 +1,48 @@
+-- Regional climate: seasons and current weather can vary by climate region,
+-- and location entities are assigned to a region. Regions ARE the weather
+-- zones catalog (calendar_weather_zones, migration 005) — a zone already
+-- names a climate; this migration lets the rest of the calendar key off it.
+--
+-- calendar_seasons.zone_id: NULL = the calendar-wide season set (what every
+-- existing row is). A region with seasons of its own uses those instead; a
+-- region without any falls back to the calendar-wide set.
+ALTER TABLE calendar_seasons ADD COLUMN IF NOT EXISTS zone_id VARCHAR(50) DEFAULT NULL;
+
+-- Current weather per region. Same shape as calendar_weather (migration 003)
+-- keyed by (calendar, zone); a region with no row shows the calendar-wide
+-- weather. Zone ids are not FKs (the zones table is replace-all); the
+-- repository prunes rows for zones dropped from the catalog.
+CREATE TABLE IF NOT EXISTS calendar_zone_weather (
+    calendar_id             VARCHAR(36)  NOT NULL,
+    zone_id                 VARCHAR(50)  NOT NULL,
+    preset_id               VARCHAR(50)  DEFAULT NULL,
+    preset_label            VARCHAR(100) DEFAULT NULL,
+    icon                    VARCHAR(50)  DEFAULT NULL,
+    color                   VARCHAR(20)  DEFAULT NULL,
+    temperature_celsius     FLOAT        DEFAULT NULL,
+    wind_speed_kph          FLOAT        DEFAULT NULL,
+    wind_speed_tier         VARCHAR(20)  DEFAULT NULL,
+    wind_direction          VARCHAR(5)   DEFAULT NULL,
+    wind_direction_degrees  INT          DEFAULT NULL,
+    precipitation_type      VARCHAR(20)  DEFAULT NULL,
+    precipitation_intensity FLOAT        DEFAULT NULL,
+    description             TEXT         DEFAULT NULL,
+    updated_at              DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (calendar_id, zone_id),
+    CONSTRAINT fk_cal_zone_weather FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Location entity -> region assignment, one region per entity per calendar.
+-- Child entities without an assignment inherit their nearest assigned
+-- ancestor's (resolved in the service by walking entities.parent_id).
+CREATE TABLE IF NOT EXISTS calendar_location_zones (
+    calendar_id VARCHAR(36) NOT NULL,
+    entity_id   VARCHAR(36) NOT NULL,
+    zone_id     VARCHAR(50) NOT NULL,
+    PRIMARY KEY (calendar_id, entity_id),
+    INDEX idx_cal_location_zones_entity (entity_id),
+    CONSTRAINT fk_cal_location_zones_calendar
+      FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE,
+    CONSTRAINT fk_cal_location_zones_entity
+      FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
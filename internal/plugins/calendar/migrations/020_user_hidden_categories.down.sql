@@ -0,0 +1,2 @@
+-- Revert per-user category hiding.
+DROP TABLE IF EXISTS calendar_user_hidden_categories;
@@ -0,0 +1,17 @@
+-- Per-user event-category hiding for the cross-campaign "My events" page.
+-- A row hides one calendar's category from one user's aggregate; it is a
+-- viewing preference only — the events stay visible on the calendar itself.
+-- Keyed by slug (categories are replace-all per calendar, so there is no
+-- stable row id); a slug that no longer exists simply matches nothing.
+CREATE TABLE IF NOT EXISTS calendar_user_hidden_categories (
+    user_id       VARCHAR(36) NOT NULL,
+    calendar_id   VARCHAR(36) NOT NULL,
+    category_slug VARCHAR(50) NOT NULL,
+    created_at    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, calendar_id, category_slug),
+    INDEX idx_cal_hidden_categories_calendar (calendar_id),
+    CONSTRAINT fk_cal_hidden_categories_user
+      FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_cal_hidden_categories_calendar
+      FOREIGN KEY (calendar_id) REFERENCES calendars(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Revert entity date events. Generated rows stay as ordinary events.
+ALTER TABLE calendar_events
+  DROP INDEX IF EXISTS idx_cal_events_entity_source,
+  DROP COLUMN IF EXISTS source_field;
@@ -0,0 +1,8 @@
+-- Anniversary events generated from entity "date" fields (births,
+-- foundings, deaths). source_field names the entity field an event was
+-- made from; NULL for every hand-made event. The sync replaces an
+-- entity's generated events by (entity_id, source_field) and never
+-- touches rows where it is NULL.
+ALTER TABLE calendar_events
+  ADD COLUMN IF NOT EXISTS source_field VARCHAR(100) DEFAULT NULL,
+  ADD INDEX IF NOT EXISTS idx_cal_events_entity_source (entity_id, source_field);
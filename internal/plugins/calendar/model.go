@@ -8,6 +8,7 @@ package calendar
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -114,6 +115,7 @@ type Calendar struct {
 	EventCategories []EventCategory `json:"event_categories,omitempty"`
 	Cycles          []Cycle         `json:"cycles,omitempty"`
 	Festivals       []Festival      `json:"festivals,omitempty"`
+	YearNames       []YearName      `json:"year_names,omitempty"`
 	// Weather is nil when no row exists in calendar_weather for this
 	// calendar. Added in C-CAL-WCF-UI so the settings page can render
 	// the current state without a second handler-side fetch.
@@ -201,6 +203,19 @@ func (c *Calendar) MonthDays(monthIdx int, year int) int {
 	return days
 }
 
+// DateProblem explains why year/month/day isn't a date on this calendar
+// ("Flamerule has 30 days in 1492"), or returns "" when it is. Leap days
+// only exist in leap years.
+func (c *Calendar) DateProblem(year, month, day int) string {
+	if month < 1 || month > len(c.Months) {
+		return fmt.Sprintf("%s has no month %d (it has %d)", c.Name, month, len(c.Months))
+	}
+	if n := c.MonthDays(month-1, year); day < 1 || day > n {
+		return fmt.Sprintf("%s has %d days in %d", c.Months[month-1].Name, n, year)
+	}
+	return ""
+}
+
 // WeekLength returns the number of days in a week (number of weekdays).
 func (c *Calendar) WeekLength() int {
 	return len(c.Weekdays)
@@ -208,13 +223,15 @@ func (c *Calendar) WeekLength() int {
 
 // Recurrence type constants — mirror the sessions plugin's vocabulary verbatim
 // (internal/plugins/sessions/model.go) so the two share semantics
-// (C-CAL-EDITOR-EXPANSION PR2). Any other / empty recurrence_type renders ONCE
-// at its stored date, so legacy rows are untouched.
+// (C-CAL-EDITOR-EXPANSION PR2). Yearly is calendar-only: sessions don't recur
+// annually, but festivals and holidays do. Any other / empty recurrence_type
+// renders ONCE at its stored date, so legacy rows are untouched.
 const (
 	RecurrenceWeekly   = "weekly"   // every week, on the base date's weekday
 	RecurrenceBiWeekly = "biweekly" // every 2 weeks
 	RecurrenceMonthly  = "monthly"  // same day-of-month each month
 	RecurrenceCustom   = "custom"   // every N weeks (RecurrenceInterval)
+	RecurrenceYearly   = "yearly"   // same month + day each year
 )
 
 // absDayIndex returns a calendar-absolute day number for (year, month, day).
@@ -247,7 +264,15 @@ func (c *Calendar) absDayIndex(year, month, day int) int {
 // branch), the display weekday path (v2WeekdayIndexFor), and the real-time
 // display calibration's 2026 epoch anchor (realTimeWeekdayIndex) all share, so
 // the geometry can never drift between recurrence and display.
+//
+// Calendars with per-month weekday overrides (a fixed StartingWeekday or an
+// intercalary month that SkipsWeekday) count in week space instead — see
+// weekSpaceDayIndex. Calendars without overrides never enter that path, so
+// their geometry is unchanged.
 func (c *Calendar) constLenDayIndex(year, month, day int) int {
+	if c.hasWeekdayOverrides() {
+		return c.weekSpaceDayIndex(year, month, day)
+	}
 	abs := year * c.YearLength()
 	for i := 0; i < month-1 && i < len(c.Months); i++ {
 		abs += c.Months[i].Days
@@ -255,17 +280,125 @@ func (c *Calendar) constLenDayIndex(year, month, day int) int {
 	return abs + day
 }
 
+// hasWeekdayOverrides reports whether any month pins its starting weekday or
+// sits outside the week.
+func (c *Calendar) hasWeekdayOverrides() bool {
+	for _, m := range c.Months {
+		if m.StartingWeekday != nil || m.SkipsWeekday {
+			return true
+		}
+	}
+	return false
+}
+
+// outsideWeek reports whether days of the 1-based month don't advance the
+// weekday (an intercalary festival that belongs to no weekday).
+func (c *Calendar) outsideWeek(month int) bool {
+	return month >= 1 && month <= len(c.Months) && c.Months[month-1].SkipsWeekday
+}
+
+// weekSpaceDayIndex is constLenDayIndex for calendars with weekday overrides.
+// It counts only days that advance the weekday, so the result mod
+// WeekLength() is the weekday column:
+//   - a SkipsWeekday month contributes no days; its days share the preceding
+//     day's count (they have no weekday of their own — v2WeekdayIndexFor
+//     reports -1 for them);
+//   - a month with StartingWeekday rounds the count up to the next day on that
+//     weekday, leaving the unused weekdays of the previous week blank (Simple
+//     Calendar's startingWeekday).
+//
+// With anchors, every year has the same length in week space (the stride from
+// the first anchored month to the same month a year later), so the count
+// stays a closed-form year*stride + offset rather than a walk from year 0.
+func (c *Calendar) weekSpaceDayIndex(year, month, day int) int {
+	weekDays := func(i int) int {
+		if c.Months[i].SkipsWeekday {
+			return 0
+		}
+		return c.Months[i].Days
+	}
+	wl := c.WeekLength()
+	first := -1
+	if wl > 0 {
+		for i, m := range c.Months {
+			if m.StartingWeekday != nil {
+				first = i
+				break
+			}
+		}
+	}
+	if first < 0 {
+		yearLen := 0
+		for i := range c.Months {
+			yearLen += weekDays(i)
+		}
+		abs := year * yearLen
+		for i := 0; i < month-1 && i < len(c.Months); i++ {
+			abs += weekDays(i)
+		}
+		if c.outsideWeek(month) {
+			return abs
+		}
+		return abs + day
+	}
+
+	// pos is the count of day 1 of month i; anchors snap it forward.
+	anchor := func(pos, i int) int {
+		if w := c.Months[i].StartingWeekday; w != nil {
+			return pos + ((*w-pos)%wl+wl)%wl
+		}
+		return pos
+	}
+	n := len(c.Months)
+	start := *c.Months[first].StartingWeekday
+	pos := start
+	for k := 0; k < n; k++ {
+		i := (first + k) % n
+		if k > 0 {
+			pos = anchor(pos, i)
+		}
+		pos += weekDays(i)
+	}
+	stride := anchor(pos, first) - start
+
+	mi := month - 1
+	if mi >= n {
+		mi = n - 1
+	}
+	if mi < 0 {
+		mi = 0
+	}
+	// Walk forward from the nearest preceding first-anchor: this year's for
+	// months at or after it, last year's for the months before it (the snap
+	// gap sits just before the anchor, so walking backward would misplace it).
+	y, steps := year, mi-first
+	if mi < first {
+		y, steps = year-1, mi+n-first
+	}
+	pos = y*stride + start
+	for k := 0; k < steps; k++ {
+		i := (first + k) % n
+		pos = anchor(pos+weekDays(i), (i+1)%n)
+	}
+	if c.outsideWeek(month) {
+		return pos - 1 // the preceding day's count, as in the unanchored path
+	}
+	return pos + day - 1
+}
+
 // OccursOn reports whether the event lands on (year, month, day) for cal. The
 // SINGLE recurrence-expansion predicate — every grid/list projection routes
 // through it so there is one source of truth (C-CAL-EDITOR-EXPANSION PR2).
 //
 // Non-recurring events (or a legacy/empty/unknown recurrence_type) match only
 // their stored date — the prior behavior, so existing rows are untouched. The
-// four recurring types expand forward from the base date:
+// recurring types expand forward from the base date:
 //   - weekly/biweekly/custom: every (interval × week) days, base-anchored, so
 //     each instance shares the base weekday;
 //   - monthly: the same day-of-month each month, skipped in months too short for
-//     that day (leap-aware via MonthDays).
+//     that day (leap-aware via MonthDays);
+//   - yearly: the same month + day each year, skipped in years where that month
+//     is too short (a leap-day festival only lands in leap years).
 //
 // Recurrence stops at the recurrence-end date (inclusive) and/or after
 // RecurrenceMaxOccurrences. Multi-day events are not expanded here (the ribbon
@@ -276,7 +409,7 @@ func (e Event) OccursOn(cal *Calendar, year, month, day int) bool {
 		return onBase
 	}
 	switch *e.RecurrenceType {
-	case RecurrenceWeekly, RecurrenceBiWeekly, RecurrenceMonthly, RecurrenceCustom:
+	case RecurrenceWeekly, RecurrenceBiWeekly, RecurrenceMonthly, RecurrenceCustom, RecurrenceYearly:
 		// expanded below
 	default:
 		return onBase // legacy / unknown type → single occurrence
@@ -305,7 +438,21 @@ func (e Event) OccursOn(cal *Calendar, year, month, day int) bool {
 		return true
 	}
 
-	// Week-based (weekly / biweekly / custom).
+	if *e.RecurrenceType == RecurrenceYearly {
+		if month != e.Month || day != e.Day || day > cal.MonthDays(month-1, year) {
+			return false
+		}
+		if e.RecurrenceMaxOccurrences != nil && year-e.Year >= *e.RecurrenceMaxOccurrences {
+			return false
+		}
+		return true
+	}
+
+	// Week-based (weekly / biweekly / custom). A day outside the week has no
+	// weekday to repeat on, so such a base — or target — matches only on base.
+	if cal.outsideWeek(e.Month) || cal.outsideWeek(month) {
+		return onBase
+	}
 	wl := cal.WeekLength()
 	stride := wl * recurrenceWeeks(*e.RecurrenceType, e.RecurrenceInterval)
 	if stride <= 0 {
@@ -351,22 +498,51 @@ func (c *Calendar) FormatCurrentTime() string {
 	return fmt.Sprintf("%02d:%02d", c.CurrentHour, c.CurrentMinute)
 }
 
+// FormatCurrentStamp returns the current date and time as
+// "YYYY-MM-DD HH:MM" — the before/after stamp recorded on advance audits.
+func (c *Calendar) FormatCurrentStamp() string {
+	return formatClockStamp(c.CurrentYear, c.CurrentMonth, c.CurrentDay, c.CurrentHour, c.CurrentMinute)
+}
+
 // CurrentSeason returns the season for the current date, or nil if none match.
 func (c *Calendar) CurrentSeason() *Season {
 	return c.SeasonForDate(c.CurrentMonth, c.CurrentDay)
 }
 
-// SeasonForDate returns the season containing the given month+day, or nil.
+// SeasonForDate returns the calendar-wide season containing the given
+// month+day, or nil. Region-scoped seasons never match here; see
+// SeasonForDateIn.
 func (c *Calendar) SeasonForDate(month, day int) *Season {
+	return c.SeasonForDateIn("", month, day)
+}
+
+// SeasonForDateIn returns the season containing month+day in a climate
+// region. A region that defines seasons of its own uses only those (so a
+// date between its seasons has none); otherwise — and for zoneID "" — the
+// calendar-wide seasons apply.
+func (c *Calendar) SeasonForDateIn(zoneID string, month, day int) *Season {
+	if zoneID != "" && !c.hasRegionalSeasons(zoneID) {
+		zoneID = ""
+	}
 	for i := range c.Seasons {
 		s := &c.Seasons[i]
-		if s.ContainsDate(month, day) {
+		if s.Zone() == zoneID && s.ContainsDate(month, day) {
 			return s
 		}
 	}
 	return nil
 }
 
+// hasRegionalSeasons reports whether any season is scoped to the region.
+func (c *Calendar) hasRegionalSeasons(zoneID string) bool {
+	for _, s := range c.Seasons {
+		if s.Zone() == zoneID {
+			return true
+		}
+	}
+	return false
+}
+
 // CurrentEra returns the era containing the current year, or nil if none match.
 func (c *Calendar) CurrentEra() *Era {
 	return c.EraForYear(c.CurrentYear)
@@ -414,6 +590,13 @@ type Month struct {
 	SortOrder     int    `json:"sort_order"`
 	IsIntercalary bool   `json:"is_intercalary"`
 	LeapYearDays  int    `json:"leap_year_days"`
+	// StartingWeekday pins day 1 of this month to a weekday (0-based index
+	// into Weekdays) regardless of where the previous month ended. Nil = the
+	// weekday flows on from the previous month.
+	StartingWeekday *int `json:"starting_weekday,omitempty"`
+	// SkipsWeekday marks an intercalary month whose days sit outside the
+	// week: they carry no weekday and don't advance it.
+	SkipsWeekday bool `json:"skips_weekday"`
 }
 
 // Weekday is a named day in the repeating weekly cycle.
@@ -519,6 +702,17 @@ type Season struct {
 	Description   *string `json:"description,omitempty"`
 	Color         string  `json:"color"`
 	WeatherEffect *string `json:"weather_effect,omitempty"`
+	// ZoneID scopes the season to one climate region (a weather zone id);
+	// nil seasons are the calendar-wide set. See SeasonForDateIn.
+	ZoneID *string `json:"zone_id,omitempty"`
+}
+
+// Zone returns the season's region, "" for the calendar-wide set.
+func (s *Season) Zone() string {
+	if s.ZoneID == nil {
+		return ""
+	}
+	return *s.ZoneID
 }
 
 // ContainsDate returns true if the given month+day falls within this season.
@@ -598,6 +792,9 @@ type Event struct {
 	Color                    *string `json:"color,omitempty"`
 	Icon                     *string `json:"icon,omitempty"`
 	AllDay                   bool    `json:"all_day"`
+	// SourceField names the entity "date" field a generated anniversary
+	// was made from (migration 021); nil for hand-made events.
+	SourceField              *string `json:"source_field,omitempty"`
 	CreatedBy                *string `json:"created_by,omitempty"`
 	CreatedAt                time.Time `json:"created_at"`
 	UpdatedAt                time.Time `json:"updated_at"`
@@ -759,6 +956,9 @@ type CreateEventInput struct {
 	Icon                     *string
 	AllDay                   bool
 	CreatedBy                string
+	// LinkedEntities ties extra entities (attendees, locations) to the new
+	// event in the same call; EntityID stays the single "primary" link.
+	LinkedEntities []EventEntityLink
 }
 
 // UpdateEventInput is the validated input for updating an event.
@@ -801,6 +1001,9 @@ type MonthInput struct {
 	SortOrder     int    `json:"sort_order"`
 	IsIntercalary bool   `json:"is_intercalary"`
 	LeapYearDays  int    `json:"leap_year_days"`
+	// StartingWeekday / SkipsWeekday — see Month.
+	StartingWeekday *int `json:"starting_weekday,omitempty"`
+	SkipsWeekday    bool `json:"skips_weekday"`
 }
 
 // WeekdayInput is the input for creating/updating a weekday.
@@ -829,24 +1032,63 @@ type EraInput struct {
 }
 
 // EventCategory is a campaign-defined event category for calendar events.
-// Categories have a slug (stored on events), display name, emoji icon, and color.
+// Categories have a slug (stored on events), display name, emoji icon, color,
+// and a default visibility applied to new events that don't pick one.
 type EventCategory struct {
-	ID         int    `json:"id"`
-	CalendarID string `json:"calendar_id"`
-	Slug       string `json:"slug"`
-	Name       string `json:"name"`
-	Icon       string `json:"icon"`
-	Color      string `json:"color"`
-	SortOrder  int    `json:"sort_order"`
+	ID                int    `json:"id"`
+	CalendarID        string `json:"calendar_id"`
+	Slug              string `json:"slug"`
+	Name              string `json:"name"`
+	Icon              string `json:"icon"`
+	Color             string `json:"color"`
+	DefaultVisibility string `json:"default_visibility"`
+	SortOrder         int    `json:"sort_order"`
 }
 
 // EventCategoryInput is the input for creating/updating an event category.
+// DefaultVisibility is "everyone" or "dm_only"; empty means "everyone".
 type EventCategoryInput struct {
-	Slug      string `json:"slug"`
-	Name      string `json:"name"`
-	Icon      string `json:"icon"`
-	Color     string `json:"color"`
-	SortOrder int    `json:"sort_order"`
+	Slug              string `json:"slug"`
+	Name              string `json:"name"`
+	Icon              string `json:"icon"`
+	Color             string `json:"color"`
+	DefaultVisibility string `json:"default_visibility"`
+	SortOrder         int    `json:"sort_order"`
+}
+
+// FindEventCategory returns the category with the given slug, or nil.
+func FindEventCategory(cats []EventCategory, slug string) *EventCategory {
+	for i := range cats {
+		if cats[i].Slug == slug {
+			return &cats[i]
+		}
+	}
+	return nil
+}
+
+// FilterEventsByCategory keeps only events whose category slug is in the
+// given set. An empty set is "no filter" and returns the input unchanged.
+// The pseudo-slug "none" matches uncategorized events so the grid can show
+// "everything I haven't tagged yet".
+func FilterEventsByCategory(events []Event, slugs []string) []Event {
+	if len(slugs) == 0 {
+		return events
+	}
+	want := make(map[string]bool, len(slugs))
+	for _, s := range slugs {
+		want[s] = true
+	}
+	out := make([]Event, 0, len(events))
+	for _, e := range events {
+		slug := "none"
+		if e.Category != nil && *e.Category != "" {
+			slug = *e.Category
+		}
+		if want[slug] {
+			out = append(out, e)
+		}
+	}
+	return out
 }
 
 // Weather represents the current weather state for a calendar.
@@ -969,6 +1211,69 @@ type CycleEntryInput struct {
 	SortOrder  int     `json:"sort_order"`
 }
 
+// EntryForYear returns the entry a yearly cycle names the given year with,
+// or nil for non-yearly or empty cycles. The cycle repeats every CycleLength
+// years counted from year 0; each entry starts at its YearOffset within the
+// cycle and lasts until the next entry's offset (wrapping around), so an
+// entry per year with offsets 0..N-1 is the plain "one name per year" case.
+func (c Cycle) EntryForYear(year int) *CycleEntry {
+	if (c.Type != "" && c.Type != "yearly") || c.CycleLength <= 0 || len(c.Entries) == 0 {
+		return nil
+	}
+	l := c.CycleLength
+	pos := (year%l + l) % l
+	var best, last *CycleEntry
+	bestOff, lastOff := -1, -1
+	for i := range c.Entries {
+		e := &c.Entries[i]
+		off := (e.YearOffset%l + l) % l
+		if off <= pos && off > bestOff {
+			best, bestOff = e, off
+		}
+		if off > lastOff {
+			last, lastOff = e, off
+		}
+	}
+	if best == nil {
+		return last // before the first offset: still in the previous lap's last entry
+	}
+	return best
+}
+
+// YearName is an explicit name for one year ("Year of Three Ships Sailing").
+// Explicit names sit alongside yearly cycles: a year may carry both.
+type YearName struct {
+	ID         int    `json:"id"`
+	CalendarID string `json:"calendar_id"`
+	Year       int    `json:"year"`
+	Name       string `json:"name"`
+}
+
+// YearNameInput is the input for replacing a calendar's named years.
+type YearNameInput struct {
+	Year int    `json:"year"`
+	Name string `json:"name"`
+}
+
+// YearLabel returns the names the calendar gives a year, joined with " · ":
+// the explicit named year first, then each yearly cycle's entry in sort
+// order. Empty when nothing names the year.
+func (c *Calendar) YearLabel(year int) string {
+	var names []string
+	for _, yn := range c.YearNames {
+		if yn.Year == year {
+			names = append(names, yn.Name)
+			break
+		}
+	}
+	for _, cy := range c.Cycles {
+		if e := cy.EntryForYear(year); e != nil {
+			names = append(names, e.Name)
+		}
+	}
+	return strings.Join(names, " · ")
+}
+
 // Festival is a fixed calendar entry (holiday) that is part of the calendar
 // structure rather than a recurring event. month+day specifies the date;
 // after_month is used for intercalary festivals that fall between months.
@@ -997,6 +1302,58 @@ type FestivalInput struct {
 	SortOrder   int     `json:"sort_order"`
 }
 
+// maxTimePresets caps the quick-advance list. The presets render as one row
+// of buttons in the GM console clock; past a dozen they stop fitting.
+const maxTimePresets = 12
+
+// TimePreset is an owner-configured quick-advance button on the GM console
+// clock ("Long rest" → +8h). A preset moves the clock EITHER by days OR by
+// hours+minutes, so a click maps onto exactly one of AdvanceDate /
+// AdvanceTime (and one audit entry).
+type TimePreset struct {
+	ID         int    `json:"id"`
+	CalendarID string `json:"calendar_id"`
+	Name       string `json:"name"`
+	Days       int    `json:"days"`
+	Hours      int    `json:"hours"`
+	Minutes    int    `json:"minutes"`
+	SortOrder  int    `json:"sort_order"`
+}
+
+// TimePresetInput is the input for creating/updating a time preset.
+type TimePresetInput struct {
+	Name      string `json:"name"`
+	Days      int    `json:"days"`
+	Hours     int    `json:"hours"`
+	Minutes   int    `json:"minutes"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// DefaultTimePresets returns the presets shown for a calendar that has
+// never saved its own. Not persisted — saving any list replaces them.
+func DefaultTimePresets() []TimePreset {
+	return []TimePreset{
+		{Name: "Short rest", Hours: 1, SortOrder: 1},
+		{Name: "Long rest", Hours: 8, SortOrder: 2},
+		{Name: "Travel day", Days: 1, SortOrder: 3},
+	}
+}
+
+// AdvanceLabel renders the preset's magnitude for its button ("+8h",
+// "+1d", "+1h 30m").
+func (p TimePreset) AdvanceLabel() string {
+	if p.Days > 0 {
+		return fmt.Sprintf("+%dd", p.Days)
+	}
+	switch {
+	case p.Hours > 0 && p.Minutes > 0:
+		return fmt.Sprintf("+%dh %dm", p.Hours, p.Minutes)
+	case p.Hours > 0:
+		return fmt.Sprintf("+%dh", p.Hours)
+	}
+	return fmt.Sprintf("+%dm", p.Minutes)
+}
+
 // DefaultEventCategories returns the default set of event categories seeded
 // for new calendars. Provides a sensible starting point for TTRPG campaigns.
 func DefaultEventCategories() []EventCategoryInput {
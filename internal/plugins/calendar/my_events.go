@@ -0,0 +1,61 @@
+// my_events.go — the user-level "My events" page (migration 020): upcoming
+// player-visible events from every calendar the user can see, across all of
+// their campaigns, minus the event categories they've chosen to hide.
+//
+// Read-only by design. Events are always filtered at player visibility, even
+// for a campaign the user owns, so the page is the same "what's coming up"
+// digest for everyone; hiding a category is a per-user view preference and
+// never changes what other members see.
+package calendar
+
+// myEventsPerCalendar is how many upcoming events each calendar contributes
+// to the page. Hidden categories are dropped after the fetch, so the service
+// over-fetches up to the repository cap and trims back to this.
+const myEventsPerCalendar = 5
+
+// HiddenCategory is one category a user has hidden on one calendar.
+type HiddenCategory struct {
+	CalendarID string `json:"calendar_id"`
+	Slug       string `json:"slug"`
+}
+
+// MyEventsCampaign identifies a campaign contributing to the page. The
+// handler resolves these from the user's memberships (the calendar plugin
+// doesn't own that list).
+type MyEventsCampaign struct {
+	ID   string
+	Name string
+}
+
+// MyEventsCalendar is one calendar's slice of the page: its upcoming events
+// with hidden categories removed, and the categories hidden so the page can
+// offer to show them again.
+type MyEventsCalendar struct {
+	CampaignID   string
+	CampaignName string
+	Calendar     *Calendar
+	Events       []Event
+	Hidden       []EventCategory
+}
+
+// MyEventsViewData is the projection the My events templ renders.
+type MyEventsViewData struct {
+	Calendars []MyEventsCalendar
+	CSRFToken string
+}
+
+// withoutHiddenCategories drops events whose category slug is hidden.
+// Uncategorized events always stay — there's no category to hide.
+func withoutHiddenCategories(events []Event, hidden map[string]bool) []Event {
+	if len(hidden) == 0 {
+		return events
+	}
+	out := make([]Event, 0, len(events))
+	for _, e := range events {
+		if e.Category != nil && hidden[*e.Category] {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
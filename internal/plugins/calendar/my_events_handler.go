@@ -0,0 +1,120 @@
+// my_events_handler.go — HTTP surface for the user-level "My events" page
+// and its category hiding. Lives outside the campaign route group: the
+// campaign list comes from the user's memberships, and every write is
+// re-checked against the calendar's campaign here.
+package calendar
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// myEventsCampaignCap bounds the memberships read for the page — one page
+// of the campaign list at its maximum size.
+const myEventsCampaignCap = 100
+
+// UserCampaignLister surfaces the viewer's campaign memberships to the My
+// events page without a broad campaigns service dependency. Implemented by
+// `campaigns.CampaignService`.
+type UserCampaignLister interface {
+	List(ctx context.Context, userID string, opts campaigns.ListOptions) ([]campaigns.Campaign, int, error)
+	GetMember(ctx context.Context, campaignID, userID string) (*campaigns.CampaignMember, error)
+}
+
+// SetUserCampaignLister wires the membership source for the My events page.
+// Called after both calendar and campaigns plugins are constructed; without
+// it the page renders empty.
+func (h *Handler) SetUserCampaignLister(l UserCampaignLister) { h.campaignLister = l }
+
+// ShowMyEvents renders the user's cross-campaign upcoming events.
+// GET /my-events
+func (h *Handler) ShowMyEvents(c echo.Context) error {
+	data, err := h.myEventsData(c)
+	if err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, myEventsBody(data))
+	}
+	return middleware.Render(c, http.StatusOK, MyEventsPage(data))
+}
+
+// UpdateHiddenCategoryAPI hides or shows one calendar category on the
+// user's My events page. HTMX callers get the refreshed page body back.
+// PUT /my-events/hidden-categories
+func (h *Handler) UpdateHiddenCategoryAPI(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+
+	var req struct {
+		CalendarID string `json:"calendar_id" form:"calendar_id"`
+		Slug       string `json:"slug" form:"slug"`
+		Hidden     bool   `json:"hidden" form:"hidden"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+
+	// Only calendars that could appear on the page: the user is a member of
+	// the calendar's campaign and can see it at player visibility.
+	cal, err := h.svc.GetCalendarByID(ctx, req.CalendarID)
+	if err != nil {
+		return err
+	}
+	if cal == nil || h.campaignLister == nil || !calendarVisibleTo(cal, permissions.RolePlayer, userID) {
+		return apperror.NewNotFound("calendar not found")
+	}
+	if _, err := h.campaignLister.GetMember(ctx, cal.CampaignID, userID); err != nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+
+	if err := h.svc.SetCategoryHidden(ctx, userID, cal.ID, req.Slug, req.Hidden); err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		data, err := h.myEventsData(c)
+		if err != nil {
+			return err
+		}
+		return middleware.Render(c, http.StatusOK, myEventsBody(data))
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// myEventsData resolves the user's calendar-enabled campaigns and loads
+// their upcoming events.
+func (h *Handler) myEventsData(c echo.Context) (MyEventsViewData, error) {
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	data := MyEventsViewData{CSRFToken: middleware.GetCSRFToken(c)}
+	if h.campaignLister == nil {
+		return data, nil
+	}
+
+	camps, _, err := h.campaignLister.List(ctx, userID, campaigns.ListOptions{Page: 1, PerPage: myEventsCampaignCap})
+	if err != nil {
+		return data, err
+	}
+	memberships := make([]MyEventsCampaign, 0, len(camps))
+	for _, camp := range camps {
+		// The aggregate honours the addon toggle like the campaign routes
+		// do: a campaign with the calendar addon off contributes nothing.
+		if h.addonSvc != nil {
+			if on, err := h.addonSvc.IsEnabledForCampaign(ctx, camp.ID, "calendar"); err != nil || !on {
+				continue
+			}
+		}
+		memberships = append(memberships, MyEventsCampaign{ID: camp.ID, Name: camp.Name})
+	}
+
+	data.Calendars, err = h.svc.MyUpcomingEvents(ctx, userID, memberships)
+	return data, err
+}
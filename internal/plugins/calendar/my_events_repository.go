@@ -0,0 +1,48 @@
+// my_events_repository.go — MariaDB reads/writes for per-user hidden event
+// categories (calendar_user_hidden_categories, migration 020). Rows cascade
+// away with their user or calendar; a category deleted from the calendar
+// just leaves an inert row that matches no events.
+package calendar
+
+import "context"
+
+// ListHiddenCategories returns every category the user hides, across all
+// calendars, ordered by calendar then slug.
+func (r *calendarRepo) ListHiddenCategories(ctx context.Context, userID string) ([]HiddenCategory, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT calendar_id, category_slug FROM calendar_user_hidden_categories
+		 WHERE user_id = ? ORDER BY calendar_id, category_slug`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HiddenCategory
+	for rows.Next() {
+		var hc HiddenCategory
+		if err := rows.Scan(&hc.CalendarID, &hc.Slug); err != nil {
+			return nil, err
+		}
+		out = append(out, hc)
+	}
+	return out, rows.Err()
+}
+
+// SetCategoryHidden hides or shows one category for a user. Both directions
+// are idempotent: hiding twice keeps one row, showing a visible one is a
+// no-op.
+func (r *calendarRepo) SetCategoryHidden(ctx context.Context, userID, calendarID, slug string, hidden bool) error {
+	if hidden {
+		_, err := r.db.ExecContext(ctx,
+			`INSERT IGNORE INTO calendar_user_hidden_categories (user_id, calendar_id, category_slug)
+			 VALUES (?, ?, ?)`,
+			userID, calendarID, slug)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM calendar_user_hidden_categories
+		 WHERE user_id = ? AND calendar_id = ? AND category_slug = ?`,
+		userID, calendarID, slug)
+	return err
+}
@@ -0,0 +1,92 @@
+// my_events_service.go — service-layer logic for the user-level "My events"
+// page: gathering upcoming player-visible events across the user's
+// campaigns and managing their hidden categories.
+package calendar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+// MyUpcomingEvents returns, for each calendar the user can see in the given
+// campaigns, its next upcoming events with the user's hidden categories
+// removed. Every campaign is read at player visibility regardless of the
+// user's actual role (see my_events.go). Calendars with nothing upcoming
+// and nothing hidden are left out so quiet campaigns don't pad the page.
+func (s *calendarService) MyUpcomingEvents(ctx context.Context, userID string, memberships []MyEventsCampaign) ([]MyEventsCalendar, error) {
+	rows, err := s.repo.ListHiddenCategories(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list hidden categories: %w", err)
+	}
+	hidden := make(map[string]map[string]bool)
+	for _, hc := range rows {
+		if hidden[hc.CalendarID] == nil {
+			hidden[hc.CalendarID] = make(map[string]bool)
+		}
+		hidden[hc.CalendarID][hc.Slug] = true
+	}
+
+	var out []MyEventsCalendar
+	for _, camp := range memberships {
+		cals, err := s.ListVisibleCalendars(ctx, camp.ID, permissions.RolePlayer, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cals {
+			// Full load for month names (event dates) and the category
+			// catalog; one calendar at a time is fine at page scale.
+			cal, err := s.GetCalendarByID(ctx, c.ID)
+			if err != nil {
+				return nil, err
+			}
+			if cal == nil {
+				continue
+			}
+			events, err := s.ListUpcomingEvents(ctx, cal.ID, 20, permissions.RolePlayer, userID)
+			if err != nil {
+				return nil, fmt.Errorf("list upcoming events: %w", err)
+			}
+			events = withoutHiddenCategories(events, hidden[cal.ID])
+			if len(events) > myEventsPerCalendar {
+				events = events[:myEventsPerCalendar]
+			}
+			mc := MyEventsCalendar{CampaignID: camp.ID, CampaignName: camp.Name, Calendar: cal, Events: events}
+			for _, cat := range cal.EventCategories {
+				if hidden[cal.ID][cat.Slug] {
+					mc.Hidden = append(mc.Hidden, cat)
+				}
+			}
+			if len(mc.Events) == 0 && len(mc.Hidden) == 0 {
+				continue
+			}
+			out = append(out, mc)
+		}
+	}
+	return out, nil
+}
+
+// SetCategoryHidden hides or shows one of a calendar's categories on the
+// user's My events page. Hiding needs a category that exists on the
+// calendar; showing is always allowed so a row for a since-deleted category
+// can still be cleared.
+func (s *calendarService) SetCategoryHidden(ctx context.Context, userID, calendarID, slug string, hidden bool) error {
+	if slug == "" {
+		return apperror.NewValidation("category slug is required")
+	}
+	if hidden {
+		cats, err := s.repo.GetEventCategories(ctx, calendarID)
+		if err != nil {
+			return fmt.Errorf("get event categories: %w", err)
+		}
+		if FindEventCategory(cats, slug) == nil {
+			return apperror.NewNotFound(fmt.Sprintf("event category %q not found", slug))
+		}
+	}
+	if err := s.repo.SetCategoryHidden(ctx, userID, calendarID, slug, hidden); err != nil {
+		return fmt.Errorf("set category hidden: %w", err)
+	}
+	return nil
+}
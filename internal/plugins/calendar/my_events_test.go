@@ -0,0 +1,122 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+// myEventsRepo wires two campaigns: camp-1 has a players' calendar (cal-a)
+// and a dm_only one; camp-2's calendar (cal-b) has nothing upcoming. The
+// user hides cal-a's "holiday" category.
+func myEventsRepo(t *testing.T) *mockCalendarRepo {
+	holiday, session := "holiday", "session"
+	cals := map[string]Calendar{
+		"cal-a":  {ID: "cal-a", CampaignID: "camp-1", Name: "Harptos"},
+		"cal-dm": {ID: "cal-dm", CampaignID: "camp-1", Name: "Secret", Visibility: "dm_only"},
+		"cal-b":  {ID: "cal-b", CampaignID: "camp-2", Name: "Quiet"},
+	}
+	events := map[string][]Event{
+		"cal-a": {
+			{ID: "e1", Name: "Midwinter", Category: &holiday},
+			{ID: "e2", Name: "Session 12", Category: &session},
+			{ID: "e3", Name: "Untagged"},
+		},
+		"cal-dm": {{ID: "e4", Name: "Ambush"}},
+	}
+	return &mockCalendarRepo{
+		listByCampaignIDFn: func(_ context.Context, campaignID string) ([]Calendar, error) {
+			var out []Calendar
+			for _, id := range []string{"cal-a", "cal-dm", "cal-b"} {
+				if cals[id].CampaignID == campaignID {
+					out = append(out, cals[id])
+				}
+			}
+			return out, nil
+		},
+		getByIDFn: func(_ context.Context, id string) (*Calendar, error) {
+			c, ok := cals[id]
+			if !ok {
+				return nil, nil
+			}
+			return &c, nil
+		},
+		getEventCategoriesFn: func(_ context.Context, _ string) ([]EventCategory, error) {
+			return []EventCategory{{Slug: "holiday", Name: "Holiday"}, {Slug: "session", Name: "Session"}}, nil
+		},
+		listUpcomingEventsFn: func(_ context.Context, calendarID string, _, _, _ int, role int, _ int) ([]Event, error) {
+			if role != permissions.RolePlayer {
+				t.Errorf("upcoming events read at role %d, want player", role)
+			}
+			return events[calendarID], nil
+		},
+		listHiddenCategoriesFn: func(_ context.Context, _ string) ([]HiddenCategory, error) {
+			return []HiddenCategory{{CalendarID: "cal-a", Slug: "holiday"}}, nil
+		},
+	}
+}
+
+func TestMyUpcomingEvents(t *testing.T) {
+	got, err := newTestCalendarService(myEventsRepo(t)).MyUpcomingEvents(context.Background(), "user-1",
+		[]MyEventsCampaign{{ID: "camp-1", Name: "Waterdeep"}, {ID: "camp-2", Name: "Quiet Realm"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// cal-dm is owner-only and cal-b has nothing to show: only cal-a remains.
+	if len(got) != 1 || got[0].Calendar.ID != "cal-a" {
+		t.Fatalf("got %+v, want only cal-a", got)
+	}
+	mc := got[0]
+	if mc.CampaignName != "Waterdeep" {
+		t.Errorf("CampaignName = %q, want Waterdeep", mc.CampaignName)
+	}
+	var names []string
+	for _, e := range mc.Events {
+		names = append(names, e.Name)
+	}
+	if len(names) != 2 || names[0] != "Session 12" || names[1] != "Untagged" {
+		t.Errorf("events = %v, want [Session 12 Untagged] (holiday hidden)", names)
+	}
+	if len(mc.Hidden) != 1 || mc.Hidden[0].Slug != "holiday" {
+		t.Errorf("Hidden = %+v, want holiday", mc.Hidden)
+	}
+}
+
+func TestSetCategoryHidden_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		hidden   bool
+		wantCode int
+	}{
+		{name: "hide known category", slug: "holiday", hidden: true},
+		{name: "show, even a since-deleted category", slug: "retired", hidden: false},
+		{name: "hide unknown category", slug: "retired", hidden: true, wantCode: 404},
+		{name: "missing slug", slug: "", hidden: true, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			repo := myEventsRepo(t)
+			repo.setCategoryHiddenFn = func(_ context.Context, _, _, _ string, _ bool) error {
+				saved = true
+				return nil
+			}
+			err := newTestCalendarService(repo).SetCategoryHidden(context.Background(), "user-1", "cal-a", tt.slug, tt.hidden)
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				if saved {
+					t.Error("rejected change must not reach the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !saved {
+				t.Error("expected the preference to be saved")
+			}
+		})
+	}
+}
@@ -241,7 +241,7 @@ var rtFixedClock = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
 // with a validation error and never writes.
 func TestRealTime_GuardAdvanceDate(t *testing.T) {
 	svc, updated := trackingService(rtCalendar("America/New_York"), rtFixedClock)
-	err := svc.AdvanceDate(context.Background(), "cal-rt", 1)
+	err := svc.AdvanceDate(context.Background(), "cal-rt", "", 1)
 	if !isAppErrorType(err, "validation_error") {
 		t.Fatalf("AdvanceDate on real-time calendar: err = %v, want validation", err)
 	}
@@ -253,7 +253,7 @@ func TestRealTime_GuardAdvanceDate(t *testing.T) {
 // TestRealTime_GuardAdvanceTime — W2.
 func TestRealTime_GuardAdvanceTime(t *testing.T) {
 	svc, updated := trackingService(rtCalendar("America/New_York"), rtFixedClock)
-	err := svc.AdvanceTime(context.Background(), "cal-rt", 1, 0)
+	err := svc.AdvanceTime(context.Background(), "cal-rt", "", 1, 0)
 	if !isAppErrorType(err, "validation_error") {
 		t.Fatalf("AdvanceTime: err = %v, want validation", err)
 	}
@@ -344,7 +344,7 @@ func TestRealTime_ManualWritersAllowed(t *testing.T) {
 	manual := rtCalendar("America/New_York")
 	manual.TracksRealTime = false
 	svc, updated := trackingService(manual, rtFixedClock)
-	if err := svc.AdvanceDate(context.Background(), "cal-rt", 1); err != nil {
+	if err := svc.AdvanceDate(context.Background(), "cal-rt", "", 1); err != nil {
 		t.Fatalf("manual reallife AdvanceDate should succeed: %v", err)
 	}
 	if !*updated {
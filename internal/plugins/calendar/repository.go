@@ -44,6 +44,10 @@ type CalendarRepository interface {
 	SetWeekdays(ctx context.Context, calendarID string, weekdays []WeekdayInput) error
 	GetWeekdays(ctx context.Context, calendarID string) ([]Weekday, error)
 
+	// SetStructureWithFixes replaces months and/or weekdays (nil leaves
+	// that part alone) and applies the event fixes in one transaction.
+	SetStructureWithFixes(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput, fixes []EventDateFix) error
+
 	// Moons.
 	SetMoons(ctx context.Context, calendarID string, moons []MoonInput) error
 	GetMoons(ctx context.Context, calendarID string) ([]Moon, error)
@@ -78,6 +82,8 @@ type CalendarRepository interface {
 	// Event categories.
 	SetEventCategories(ctx context.Context, calendarID string, cats []EventCategoryInput) error
 	GetEventCategories(ctx context.Context, calendarID string) ([]EventCategory, error)
+	UpsertEventCategory(ctx context.Context, calendarID string, cat EventCategoryInput) error
+	DeleteEventCategory(ctx context.Context, calendarID, slug string) (bool, error)
 
 	// Weather.
 	GetWeather(ctx context.Context, calendarID string) (*Weather, error)
@@ -101,6 +107,14 @@ type CalendarRepository interface {
 	SetFestivals(ctx context.Context, calendarID string, festivals []FestivalInput) error
 	GetFestivals(ctx context.Context, calendarID string) ([]Festival, error)
 
+	// Time presets (GM console quick-advance buttons).
+	SetTimePresets(ctx context.Context, calendarID string, presets []TimePresetInput) error
+	GetTimePresets(ctx context.Context, calendarID string) ([]TimePreset, error)
+
+	// Named years.
+	SetYearNames(ctx context.Context, calendarID string, names []YearNameInput) error
+	GetYearNames(ctx context.Context, calendarID string) ([]YearName, error)
+
 	// Events.
 	CreateEvent(ctx context.Context, evt *Event) error
 	GetEvent(ctx context.Context, id string) (*Event, error)
@@ -110,6 +124,12 @@ type CalendarRepository interface {
 	ListEventsForYear(ctx context.Context, calendarID string, year int, role int) ([]Event, error)
 	ListEventsForDateRange(ctx context.Context, calendarID string, year, startMonth, startDay, endMonth, endDay int, role int) ([]Event, error)
 	ListEventsForEntity(ctx context.Context, entityID string, role int) ([]Event, error)
+	ListEntityAnniversaries(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error)
+	// ReplaceEntityDateEvents makes an entity's generated anniversary events
+	// exactly events (matched by SourceField, on calendarID), updating rows
+	// in place so event IDs and links stay stable. Generated rows on other
+	// calendars, or for fields no longer listed, are deleted.
+	ReplaceEntityDateEvents(ctx context.Context, calendarID, entityID string, events []Event) error
 	ListUpcomingEvents(ctx context.Context, calendarID string, year, month, day int, role int, limit int) ([]Event, error)
 	SearchEvents(ctx context.Context, calendarID, query string, role int) ([]Event, error)
 	// ListAllEvents returns every event for a calendar with no
@@ -137,7 +157,9 @@ type CalendarRepository interface {
 	// there is no unlink-all method. Implementations in
 	// entity_ties_repository.go.
 	LinkEntityEvent(ctx context.Context, entityID, eventID, role string) error
+	LinkEntityEventIfAbsent(ctx context.Context, entityID, eventID string) error
 	UnlinkEntityEvent(ctx context.Context, entityID, eventID string) error
+	BackfillPrimaryEntityLinks(ctx context.Context) (int, error)
 	LinkEntityEra(ctx context.Context, entityID string, eraID int, role *string) error
 	UnlinkEntityEra(ctx context.Context, entityID string, eraID int) error
 	EntitiesForEvent(ctx context.Context, eventID string) ([]EntityTieRef, error)
@@ -145,6 +167,24 @@ type CalendarRepository interface {
 	EntitiesForCalendar(ctx context.Context, calendarID string, role int, userID string) ([]EntityTieRef, error)
 	EventsForEntity(ctx context.Context, entityID string) ([]EntityEventTie, error)
 	ErasForEntity(ctx context.Context, entityID string) ([]EntityEraTie, error)
+	// Clock advancement log (migration 016). Implementations in
+	// date_history_repository.go.
+	RecordDateAdvancement(ctx context.Context, a *DateAdvancement) error
+	ListDateHistory(ctx context.Context, calendarID string, limit int) ([]DateAdvancement, error)
+	LastDateAdvancement(ctx context.Context, calendarID string) (*DateAdvancement, error)
+	MarkDateAdvancementUndone(ctx context.Context, id int) error
+	// Regional climate (migration 019). Implementations in
+	// climate_regions_repository.go.
+	GetZoneWeather(ctx context.Context, calendarID string) ([]Weather, error)
+	SetZoneWeather(ctx context.Context, calendarID, zoneID string, input WeatherInput) error
+	ListLocationZones(ctx context.Context, calendarID string) ([]LocationZone, error)
+	SetLocationZones(ctx context.Context, calendarID string, assignments []LocationZone) error
+	LocationAncestry(ctx context.Context, entityID string) ([]string, error)
+	EntitiesOutsideCampaign(ctx context.Context, campaignID string, entityIDs []string) ([]string, error)
+	// Per-user hidden categories (migration 020). Implementations in
+	// my_events_repository.go.
+	ListHiddenCategories(ctx context.Context, userID string) ([]HiddenCategory, error)
+	SetCategoryHidden(ctx context.Context, userID, calendarID, slug string, hidden bool) error
 	// World-state model (migration 008 / C-CAL-WORLDSTATE-SERVER-MODEL).
 	// All reads are scoped to a single date (year/month/day) except
 	// GetMoonPhasesForCalendar which loads the named-phase vocab for every
@@ -389,25 +429,36 @@ func (r *calendarRepo) SetMonths(ctx context.Context, calendarID string, months
 	}
 	defer tx.Rollback()
 
+	if err := setMonthsTx(ctx, tx, calendarID, months); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setMonthsTx replaces a calendar's months inside tx.
+func setMonthsTx(ctx context.Context, tx *sql.Tx, calendarID string, months []MonthInput) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_months WHERE calendar_id = ?`, calendarID); err != nil {
 		return err
 	}
 	for _, m := range months {
 		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO calendar_months (calendar_id, name, days, sort_order, is_intercalary, leap_year_days)
-			 VALUES (?, ?, ?, ?, ?, ?)`,
+			`INSERT INTO calendar_months (calendar_id, name, days, sort_order, is_intercalary, leap_year_days,
+			                              starting_weekday, skips_weekday)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 			calendarID, m.Name, m.Days, m.SortOrder, m.IsIntercalary, m.LeapYearDays,
+			m.StartingWeekday, m.SkipsWeekday,
 		); err != nil {
 			return err
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
 // GetMonths returns all months for a calendar ordered by sort_order.
 func (r *calendarRepo) GetMonths(ctx context.Context, calendarID string) ([]Month, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, calendar_id, name, days, sort_order, is_intercalary, leap_year_days
+		`SELECT id, calendar_id, name, days, sort_order, is_intercalary, leap_year_days,
+		        starting_weekday, skips_weekday
 		 FROM calendar_months WHERE calendar_id = ? ORDER BY sort_order`, calendarID)
 	if err != nil {
 		return nil, err
@@ -417,7 +468,8 @@ func (r *calendarRepo) GetMonths(ctx context.Context, calendarID string) ([]Mont
 	var months []Month
 	for rows.Next() {
 		var m Month
-		if err := rows.Scan(&m.ID, &m.CalendarID, &m.Name, &m.Days, &m.SortOrder, &m.IsIntercalary, &m.LeapYearDays); err != nil {
+		if err := rows.Scan(&m.ID, &m.CalendarID, &m.Name, &m.Days, &m.SortOrder, &m.IsIntercalary, &m.LeapYearDays,
+			&m.StartingWeekday, &m.SkipsWeekday); err != nil {
 			return nil, err
 		}
 		months = append(months, m)
@@ -433,6 +485,14 @@ func (r *calendarRepo) SetWeekdays(ctx context.Context, calendarID string, weekd
 	}
 	defer tx.Rollback()
 
+	if err := setWeekdaysTx(ctx, tx, calendarID, weekdays); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setWeekdaysTx replaces a calendar's weekdays inside tx.
+func setWeekdaysTx(ctx context.Context, tx *sql.Tx, calendarID string, weekdays []WeekdayInput) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_weekdays WHERE calendar_id = ?`, calendarID); err != nil {
 		return err
 	}
@@ -445,6 +505,51 @@ func (r *calendarRepo) SetWeekdays(ctx context.Context, calendarID string, weekd
 			return err
 		}
 	}
+	return nil
+}
+
+// SetStructureWithFixes replaces months and/or weekdays and rewrites or
+// deletes the events the change strands, all or nothing. Only the date
+// and weekday columns of fixed events are written.
+func (r *calendarRepo) SetStructureWithFixes(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput, fixes []EventDateFix) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if months != nil {
+		if err := setMonthsTx(ctx, tx, calendarID, months); err != nil {
+			return err
+		}
+	}
+	if weekdays != nil {
+		if err := setWeekdaysTx(ctx, tx, calendarID, weekdays); err != nil {
+			return err
+		}
+	}
+	for _, f := range fixes {
+		if f.Delete {
+			if _, err := tx.ExecContext(ctx,
+				`DELETE FROM calendar_events WHERE id = ? AND calendar_id = ?`, f.EventID, calendarID); err != nil {
+				return err
+			}
+			continue
+		}
+		e := f.Event
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE calendar_events
+			 SET year = ?, month = ?, day = ?, end_year = ?, end_month = ?, end_day = ?,
+			     recurrence_end_year = ?, recurrence_end_month = ?, recurrence_end_day = ?,
+			     recurrence_day_of_week = ?
+			 WHERE id = ? AND calendar_id = ?`,
+			e.Year, e.Month, e.Day, e.EndYear, e.EndMonth, e.EndDay,
+			e.RecurrenceEndYear, e.RecurrenceEndMonth, e.RecurrenceEndDay,
+			e.RecurrenceDayOfWeek, f.EventID, calendarID,
+		); err != nil {
+			return err
+		}
+	}
 	return tx.Commit()
 }
 
@@ -530,9 +635,9 @@ func (r *calendarRepo) SetSeasons(ctx context.Context, calendarID string, season
 	}
 	for _, s := range seasons {
 		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO calendar_seasons (calendar_id, name, start_month, start_day, end_month, end_day, description, color, weather_effect)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			calendarID, s.Name, s.StartMonth, s.StartDay, s.EndMonth, s.EndDay, s.Description, s.Color, s.WeatherEffect,
+			`INSERT INTO calendar_seasons (calendar_id, name, start_month, start_day, end_month, end_day, description, color, weather_effect, zone_id)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			calendarID, s.Name, s.StartMonth, s.StartDay, s.EndMonth, s.EndDay, s.Description, s.Color, s.WeatherEffect, s.ZoneID,
 		); err != nil {
 			return err
 		}
@@ -543,7 +648,7 @@ func (r *calendarRepo) SetSeasons(ctx context.Context, calendarID string, season
 // GetSeasons returns all seasons for a calendar.
 func (r *calendarRepo) GetSeasons(ctx context.Context, calendarID string) ([]Season, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, calendar_id, name, start_month, start_day, end_month, end_day, description, color, weather_effect
+		`SELECT id, calendar_id, name, start_month, start_day, end_month, end_day, description, color, weather_effect, zone_id
 		 FROM calendar_seasons WHERE calendar_id = ?`, calendarID)
 	if err != nil {
 		return nil, err
@@ -553,7 +658,7 @@ func (r *calendarRepo) GetSeasons(ctx context.Context, calendarID string) ([]Sea
 	var seasons []Season
 	for rows.Next() {
 		var s Season
-		if err := rows.Scan(&s.ID, &s.CalendarID, &s.Name, &s.StartMonth, &s.StartDay, &s.EndMonth, &s.EndDay, &s.Description, &s.Color, &s.WeatherEffect); err != nil {
+		if err := rows.Scan(&s.ID, &s.CalendarID, &s.Name, &s.StartMonth, &s.StartDay, &s.EndMonth, &s.EndDay, &s.Description, &s.Color, &s.WeatherEffect, &s.ZoneID); err != nil {
 			return nil, err
 		}
 		seasons = append(seasons, s)
@@ -754,9 +859,11 @@ func (r *calendarRepo) ApplyImport(ctx context.Context, cal *Calendar, result *I
 		}
 		for _, m := range result.Months {
 			if _, err := tx.ExecContext(ctx,
-				`INSERT INTO calendar_months (calendar_id, name, days, sort_order, is_intercalary, leap_year_days)
-				 VALUES (?, ?, ?, ?, ?, ?)`,
+				`INSERT INTO calendar_months (calendar_id, name, days, sort_order, is_intercalary, leap_year_days,
+				                              starting_weekday, skips_weekday)
+				 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 				cal.ID, m.Name, m.Days, m.SortOrder, m.IsIntercalary, m.LeapYearDays,
+				m.StartingWeekday, m.SkipsWeekday,
 			); err != nil {
 				return fmt.Errorf("insert month %q: %w", m.Name, err)
 			}
@@ -825,6 +932,66 @@ func (r *calendarRepo) ApplyImport(ctx context.Context, cal *Calendar, result *I
 		}
 	}
 
+	// 7. Cycles + named years (optional — replaced only when the import
+	// carries any, so formats without year naming leave the GM's alone).
+	if len(result.Cycles) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM calendar_cycles WHERE calendar_id = ?`, cal.ID); err != nil {
+			return fmt.Errorf("delete cycles: %w", err)
+		}
+		for _, c := range result.Cycles {
+			res, err := tx.ExecContext(ctx,
+				`INSERT INTO calendar_cycles (calendar_id, name, cycle_length, type, sort_order)
+				 VALUES (?, ?, ?, ?, ?)`,
+				cal.ID, c.Name, c.CycleLength, c.Type, c.SortOrder)
+			if err != nil {
+				return fmt.Errorf("insert cycle %q: %w", c.Name, err)
+			}
+			cycleID, err := res.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("insert cycle %q: %w", c.Name, err)
+			}
+			for _, e := range c.Entries {
+				if _, err := tx.ExecContext(ctx,
+					`INSERT INTO calendar_cycle_entries (cycle_id, name, icon, year_offset, sort_order)
+					 VALUES (?, ?, ?, ?, ?)`,
+					cycleID, e.Name, e.Icon, e.YearOffset, e.SortOrder,
+				); err != nil {
+					return fmt.Errorf("insert cycle entry %q: %w", e.Name, err)
+				}
+			}
+		}
+	}
+	if len(result.YearNames) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM calendar_year_names WHERE calendar_id = ?`, cal.ID); err != nil {
+			return fmt.Errorf("delete year names: %w", err)
+		}
+		for _, n := range result.YearNames {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO calendar_year_names (calendar_id, year, name) VALUES (?, ?, ?)`,
+				cal.ID, n.Year, n.Name,
+			); err != nil {
+				return fmt.Errorf("insert year name %d: %w", n.Year, err)
+			}
+		}
+	}
+
+	// 8. Event categories (MERGE, not replace). Existing events reference
+	// category slugs, so wiping the table on import would orphan them;
+	// imported categories are upserted by slug alongside the existing set.
+	for _, c := range result.Categories {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO calendar_event_categories (calendar_id, slug, name, icon, color, default_visibility, sort_order)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)
+			 ON DUPLICATE KEY UPDATE name = VALUES(name), icon = VALUES(icon), color = VALUES(color),
+			        default_visibility = VALUES(default_visibility)`,
+			cal.ID, c.Slug, c.Name, c.Icon, c.Color, c.DefaultVisibility, c.SortOrder,
+		); err != nil {
+			return fmt.Errorf("insert category %q: %w", c.Slug, err)
+		}
+	}
+
 	return tx.Commit()
 }
 
@@ -862,9 +1029,9 @@ func (r *calendarRepo) SetEventCategories(ctx context.Context, calendarID string
 	}
 	for _, c := range cats {
 		if _, err := tx.ExecContext(ctx,
-			`INSERT INTO calendar_event_categories (calendar_id, slug, name, icon, color, sort_order)
-			 VALUES (?, ?, ?, ?, ?, ?)`,
-			calendarID, c.Slug, c.Name, c.Icon, c.Color, c.SortOrder,
+			`INSERT INTO calendar_event_categories (calendar_id, slug, name, icon, color, default_visibility, sort_order)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			calendarID, c.Slug, c.Name, c.Icon, c.Color, c.DefaultVisibility, c.SortOrder,
 		); err != nil {
 			return err
 		}
@@ -872,10 +1039,40 @@ func (r *calendarRepo) SetEventCategories(ctx context.Context, calendarID string
 	return tx.Commit()
 }
 
+// UpsertEventCategory creates or updates a single category keyed by
+// (calendar_id, slug). Lets the per-category management endpoints edit one
+// row without the DELETE+INSERT churn of SetEventCategories.
+func (r *calendarRepo) UpsertEventCategory(ctx context.Context, calendarID string, c EventCategoryInput) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO calendar_event_categories (calendar_id, slug, name, icon, color, default_visibility, sort_order)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE name = VALUES(name), icon = VALUES(icon), color = VALUES(color),
+		        default_visibility = VALUES(default_visibility), sort_order = VALUES(sort_order)`,
+		calendarID, c.Slug, c.Name, c.Icon, c.Color, c.DefaultVisibility, c.SortOrder,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert event category: %w", err)
+	}
+	return nil
+}
+
+// DeleteEventCategory removes one category by slug. Returns false when no
+// row matched so the service can surface a 404. Events keep their category
+// slug (free-text column) — they simply render uncategorized.
+func (r *calendarRepo) DeleteEventCategory(ctx context.Context, calendarID, slug string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM calendar_event_categories WHERE calendar_id = ? AND slug = ?`, calendarID, slug)
+	if err != nil {
+		return false, fmt.Errorf("delete event category: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
 // GetEventCategories returns all event categories for a calendar ordered by sort_order.
 func (r *calendarRepo) GetEventCategories(ctx context.Context, calendarID string) ([]EventCategory, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, calendar_id, slug, name, icon, color, sort_order
+		`SELECT id, calendar_id, slug, name, icon, color, default_visibility, sort_order
 		 FROM calendar_event_categories WHERE calendar_id = ? ORDER BY sort_order`, calendarID)
 	if err != nil {
 		return nil, err
@@ -885,7 +1082,7 @@ func (r *calendarRepo) GetEventCategories(ctx context.Context, calendarID string
 	var cats []EventCategory
 	for rows.Next() {
 		var c EventCategory
-		if err := rows.Scan(&c.ID, &c.CalendarID, &c.Slug, &c.Name, &c.Icon, &c.Color, &c.SortOrder); err != nil {
+		if err := rows.Scan(&c.ID, &c.CalendarID, &c.Slug, &c.Name, &c.Icon, &c.Color, &c.DefaultVisibility, &c.SortOrder); err != nil {
 			return nil, err
 		}
 		cats = append(cats, c)
@@ -903,7 +1100,7 @@ const eventCols = `e.id, e.calendar_id, e.entity_id, e.name, e.description, e.de
        e.recurrence_interval, e.recurrence_end_year, e.recurrence_end_month,
        e.recurrence_end_day, e.recurrence_max_occurrences, e.recurrence_day_of_week,
        e.visibility, e.visibility_rules, e.category, e.tier,
-       e.color, e.icon, e.all_day,
+       e.color, e.icon, e.all_day, e.source_field,
        e.created_by, e.created_at, e.updated_at,
        COALESCE(ent.name, ''), COALESCE(et.icon, ''), COALESCE(et.color, '')`
 
@@ -949,7 +1146,7 @@ func (r *calendarRepo) GetEvent(ctx context.Context, id string) (*Event, error)
 		&evt.RecurrenceInterval, &evt.RecurrenceEndYear, &evt.RecurrenceEndMonth,
 		&evt.RecurrenceEndDay, &evt.RecurrenceMaxOccurrences, &evt.RecurrenceDayOfWeek,
 		&evt.Visibility, &evt.VisibilityRules, &evt.Category, &evt.Tier,
-		&evt.Color, &evt.Icon, &evt.AllDay,
+		&evt.Color, &evt.Icon, &evt.AllDay, &evt.SourceField,
 		&evt.CreatedBy, &evt.CreatedAt, &evt.UpdatedAt,
 		&evt.EntityName, &evt.EntityIcon, &evt.EntityColor)
 	if err == sql.ErrNoRows {
@@ -1001,7 +1198,7 @@ func (r *calendarRepo) ListEventsForMonth(ctx context.Context, calendarID string
 	}
 
 	// C-CAL-EDITOR-EXPANSION PR2: fetch this month's events PLUS every
-	// recurring candidate (the four recurrence types) for the calendar — the
+	// recurring candidate (every recurrence type) for the calendar — the
 	// recurring rows may have a base date in another month/year but project
 	// into this month. The precise placement is decided in Go by
 	// Event.OccursOn (the single expansion predicate), so the SQL just widens
@@ -1014,7 +1211,7 @@ func (r *calendarRepo) ListEventsForMonth(ctx context.Context, calendarID string
 		WHERE e.calendar_id = ?
 		  AND (
 		    (e.year = ? AND e.month = ?)
-		    OR (e.is_recurring = 1 AND e.recurrence_type IN ('weekly','biweekly','monthly','custom'))
+		    OR (e.is_recurring = 1 AND e.recurrence_type IN ('weekly','biweekly','monthly','custom','yearly'))
 		  )
 		  %s
 		ORDER BY e.day, COALESCE(e.start_hour, 99), COALESCE(e.start_minute, 99), e.name`, visFilter)
@@ -1096,7 +1293,7 @@ func (r *calendarRepo) ListEventsForDateRange(ctx context.Context, calendarID st
 		WHERE e.calendar_id = ?
 		  AND (
 		    (e.year = ? AND (e.month * 100 + e.day) >= ? AND (e.month * 100 + e.day) <= ?)
-		    OR (e.is_recurring = 1 AND e.recurrence_type IN ('weekly','biweekly','monthly','custom'))
+		    OR (e.is_recurring = 1 AND e.recurrence_type IN ('weekly','biweekly','monthly','custom','yearly'))
 		  )
 		  %s
 		ORDER BY e.month, e.day, COALESCE(e.start_hour, 99), COALESCE(e.start_minute, 99), e.name`, visFilter)
@@ -1113,8 +1310,119 @@ func (r *calendarRepo) ListEventsForDateRange(ctx context.Context, calendarID st
 	return scanEvents(rows)
 }
 
-// ListEventsForEntity returns all events linked to a specific entity.
-// Used for the reverse entity-event lookup on entity pages.
+// ListEntityAnniversaries returns the entity-linked events that fell on
+// (month, day) in an earlier year — births, deaths, foundings — for the day
+// panel's anniversaries: one-off events plus the yearly ones generated from
+// entity date fields (whose start is the original date). The linked entity must be visible to the
+// viewer (entityVisibilityFilter; the IN subquery's `e` is the entity), so
+// a player never learns a hidden entity's name from its birthday.
+func (r *calendarRepo) ListEntityAnniversaries(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error) {
+	visFilter := "AND e.visibility = 'everyone'"
+	if permissions.CanSeeDmOnly(role) {
+		visFilter = ""
+	}
+	entFilter, entArgs := entityVisibilityFilter(role, userID)
+
+	query := fmt.Sprintf(`
+		SELECT `+eventCols+`
+		FROM calendar_events e `+eventJoins+`
+		WHERE e.calendar_id = ?
+		  AND e.month = ? AND e.day = ? AND e.year < ?
+		  AND (e.is_recurring = 0 OR e.source_field IS NOT NULL)
+		  AND e.entity_id IN (SELECT e.id FROM entities e WHERE 1 = 1 %s)
+		  %s
+		ORDER BY e.year, e.name
+		LIMIT 50`, entFilter, visFilter)
+
+	args := append([]any{calendarID, month, day, year}, entArgs...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanEvents(rows)
+}
+
+// ReplaceEntityDateEvents syncs an entity's generated anniversaries in one
+// transaction. Only generated rows (source_field NOT NULL) are touched.
+func (r *calendarRepo) ReplaceEntityDateEvents(ctx context.Context, calendarID, entityID string, events []Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, calendar_id, source_field FROM calendar_events
+		 WHERE entity_id = ? AND source_field IS NOT NULL`, entityID)
+	if err != nil {
+		return fmt.Errorf("listing entity date events: %w", err)
+	}
+	existing := map[string]string{} // source field → event id on calendarID
+	var stale []string
+	for rows.Next() {
+		var id, calID, field string
+		if err := rows.Scan(&id, &calID, &field); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning entity date event: %w", err)
+		}
+		if calID == calendarID {
+			if _, dup := existing[field]; !dup {
+				existing[field] = id
+				continue
+			}
+		}
+		stale = append(stale, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range events {
+		evt := &events[i]
+		if evt.SourceField == nil {
+			continue
+		}
+		if id, ok := existing[*evt.SourceField]; ok {
+			delete(existing, *evt.SourceField)
+			evt.ID = id
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE calendar_events
+				 SET name = ?, year = ?, month = ?, day = ?, visibility = ?
+				 WHERE id = ?`,
+				evt.Name, evt.Year, evt.Month, evt.Day, evt.Visibility, id,
+			); err != nil {
+				return fmt.Errorf("updating entity date event: %w", err)
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO calendar_events (id, calendar_id, entity_id, name, year, month, day,
+			        is_recurring, recurrence_type, visibility, all_day, source_field)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			evt.ID, calendarID, entityID, evt.Name, evt.Year, evt.Month, evt.Day,
+			evt.IsRecurring, evt.RecurrenceType, evt.Visibility, evt.AllDay, evt.SourceField,
+		); err != nil {
+			return fmt.Errorf("inserting entity date event: %w", err)
+		}
+	}
+	for _, id := range existing {
+		stale = append(stale, id)
+	}
+	for _, id := range stale {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_events WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("deleting entity date event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListEventsForEntity returns all events linked to a specific entity — via
+// the legacy single entity_id column OR an entity_event_links tie, so an event
+// with several attendees lists under each of them. Used for the reverse
+// entity-event lookup (sync API).
 func (r *calendarRepo) ListEventsForEntity(ctx context.Context, entityID string, role int) ([]Event, error) {
 	visFilter := "AND e.visibility = 'everyone'"
 	if permissions.CanSeeDmOnly(role) {
@@ -1124,11 +1432,13 @@ func (r *calendarRepo) ListEventsForEntity(ctx context.Context, entityID string,
 	query := fmt.Sprintf(`
 		SELECT `+eventCols+`
 		FROM calendar_events e `+eventJoins+`
-		WHERE e.entity_id = ?
+		WHERE (e.entity_id = ? OR EXISTS (
+		    SELECT 1 FROM entity_event_links l
+		    WHERE l.event_id = e.id AND l.entity_id = ?))
 		  %s
 		ORDER BY e.year, e.month, e.day`, visFilter)
 
-	rows, err := r.db.QueryContext(ctx, query, entityID)
+	rows, err := r.db.QueryContext(ctx, query, entityID, entityID)
 	if err != nil {
 		return nil, err
 	}
@@ -1228,9 +1538,9 @@ func scanEvents(rows *sql.Rows) ([]Event, error) {
 			&evt.EndYear, &evt.EndMonth, &evt.EndDay, &evt.EndHour, &evt.EndMinute,
 			&evt.IsRecurring, &evt.RecurrenceType,
 			&evt.RecurrenceInterval, &evt.RecurrenceEndYear, &evt.RecurrenceEndMonth,
-			&evt.RecurrenceEndDay, &evt.RecurrenceMaxOccurrences,
+			&evt.RecurrenceEndDay, &evt.RecurrenceMaxOccurrences, &evt.RecurrenceDayOfWeek,
 			&evt.Visibility, &evt.VisibilityRules, &evt.Category, &evt.Tier,
-			&evt.Color, &evt.Icon, &evt.AllDay,
+			&evt.Color, &evt.Icon, &evt.AllDay, &evt.SourceField,
 			&evt.CreatedBy, &evt.CreatedAt, &evt.UpdatedAt,
 			&evt.EntityName, &evt.EntityIcon, &evt.EntityColor,
 		); err != nil {
@@ -1290,11 +1600,7 @@ func (r *calendarRepo) SearchEvents(ctx context.Context, calendarID, query strin
 // GetWeather returns the current weather state for a calendar, or nil if none set.
 func (r *calendarRepo) GetWeather(ctx context.Context, calendarID string) (*Weather, error) {
 	w := &Weather{}
-	var windSpeedKPHf sql.NullFloat64
-	var windSpeedTier, windDir sql.NullString
-	var windDirDegi sql.NullInt32
-	var precipType sql.NullString
-	var precipIntensity sql.NullFloat64
+	var ws weatherScan
 
 	err := r.db.QueryRowContext(ctx,
 		`SELECT id, calendar_id, preset_id, preset_label, icon, color,
@@ -1304,9 +1610,9 @@ func (r *calendarRepo) GetWeather(ctx context.Context, calendarID string) (*Weat
 		        zone_id, zone_name, description, updated_at
 		 FROM calendar_weather WHERE calendar_id = ?`, calendarID,
 	).Scan(&w.ID, &w.CalendarID, &w.PresetID, &w.PresetLabel, &w.Icon, &w.Color,
-		&w.TemperatureCelsius, &windSpeedKPHf, &windSpeedTier,
-		&windDir, &windDirDegi,
-		&precipType, &precipIntensity,
+		&w.TemperatureCelsius, &ws.windSpeedKPH, &ws.windSpeedTier,
+		&ws.windDir, &ws.windDirDeg,
+		&ws.precipType, &ws.precipIntensity,
 		&w.ZoneID, &w.ZoneName, &w.Description, &w.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -1314,37 +1620,50 @@ func (r *calendarRepo) GetWeather(ctx context.Context, calendarID string) (*Weat
 	if err != nil {
 		return nil, err
 	}
+	ws.apply(w)
+	return w, nil
+}
+
+// weatherScan holds the nullable wind/precipitation columns of a weather
+// row; apply folds them into the nested Wind / Precipitation structs.
+// Shared by calendar_weather and calendar_zone_weather reads.
+type weatherScan struct {
+	windSpeedKPH           sql.NullFloat64
+	windSpeedTier, windDir sql.NullString
+	windDirDeg             sql.NullInt32
+	precipType             sql.NullString
+	precipIntensity        sql.NullFloat64
+}
 
+func (ws weatherScan) apply(w *Weather) {
 	// Build Wind struct if any wind data is present.
-	if windSpeedKPHf.Valid || windDir.Valid {
+	if ws.windSpeedKPH.Valid || ws.windDir.Valid {
 		wind := &Wind{}
-		if windSpeedKPHf.Valid {
-			v := windSpeedKPHf.Float64
+		if ws.windSpeedKPH.Valid {
+			v := ws.windSpeedKPH.Float64
 			wind.SpeedKPH = &v
 		}
-		if windSpeedTier.Valid {
-			wind.SpeedTier = &windSpeedTier.String
+		if ws.windSpeedTier.Valid {
+			wind.SpeedTier = &ws.windSpeedTier.String
 		}
-		if windDir.Valid {
-			wind.Direction = &windDir.String
+		if ws.windDir.Valid {
+			wind.Direction = &ws.windDir.String
 		}
-		if windDirDegi.Valid {
-			v := int(windDirDegi.Int32)
+		if ws.windDirDeg.Valid {
+			v := int(ws.windDirDeg.Int32)
 			wind.DirectionDegrees = &v
 		}
 		w.Wind = wind
 	}
 
 	// Build Precipitation struct if any precipitation data is present.
-	if precipType.Valid {
-		p := &Precipitation{Type: &precipType.String}
-		if precipIntensity.Valid {
-			p.Intensity = &precipIntensity.Float64
+	if ws.precipType.Valid {
+		p := &Precipitation{Type: &ws.precipType.String}
+		if ws.precipIntensity.Valid {
+			p.Intensity = &ws.precipIntensity.Float64
 		}
 		w.Precipitation = p
 	}
-
-	return w, nil
 }
 
 // SetWeather upserts the current weather state for a calendar.
@@ -1433,6 +1752,9 @@ func (r *calendarRepo) ApplyWeatherZones(ctx context.Context, calendarID string,
 			return fmt.Errorf("insert zone %q: %w", z.ZoneID, err)
 		}
 	}
+	if err := pruneDroppedZones(ctx, tx, calendarID, zones); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
@@ -1590,3 +1912,94 @@ func (r *calendarRepo) GetFestivals(ctx context.Context, calendarID string) ([]F
 	}
 	return festivals, rows.Err()
 }
+
+// --- Time presets ---
+
+// SetTimePresets replaces all quick-advance presets for a calendar.
+func (r *calendarRepo) SetTimePresets(ctx context.Context, calendarID string, presets []TimePresetInput) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_time_presets WHERE calendar_id = ?`, calendarID); err != nil {
+		return err
+	}
+	for _, p := range presets {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO calendar_time_presets (calendar_id, name, days, hours, minutes, sort_order)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			calendarID, p.Name, p.Days, p.Hours, p.Minutes, p.SortOrder,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTimePresets returns the saved quick-advance presets for a calendar.
+func (r *calendarRepo) GetTimePresets(ctx context.Context, calendarID string) ([]TimePreset, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, calendar_id, name, days, hours, minutes, sort_order
+		 FROM calendar_time_presets WHERE calendar_id = ? ORDER BY sort_order, id`, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var presets []TimePreset
+	for rows.Next() {
+		var p TimePreset
+		if err := rows.Scan(&p.ID, &p.CalendarID, &p.Name, &p.Days, &p.Hours, &p.Minutes, &p.SortOrder); err != nil {
+			return nil, err
+		}
+		presets = append(presets, p)
+	}
+	return presets, rows.Err()
+}
+
+// --- Year names ---
+
+// SetYearNames replaces all named years for a calendar.
+func (r *calendarRepo) SetYearNames(ctx context.Context, calendarID string, names []YearNameInput) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_year_names WHERE calendar_id = ?`, calendarID); err != nil {
+		return err
+	}
+	for _, n := range names {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO calendar_year_names (calendar_id, year, name) VALUES (?, ?, ?)`,
+			calendarID, n.Year, n.Name,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetYearNames returns a calendar's named years in year order.
+func (r *calendarRepo) GetYearNames(ctx context.Context, calendarID string) ([]YearName, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, calendar_id, year, name
+		 FROM calendar_year_names WHERE calendar_id = ? ORDER BY year`, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []YearName
+	for rows.Next() {
+		var n YearName
+		if err := rows.Scan(&n.ID, &n.CalendarID, &n.Year, &n.Name); err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, rows.Err()
+}
@@ -39,11 +39,17 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	cg.PUT("/calendars/:calId/settings", h.UpdateCalendarAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/months", h.UpdateMonthsAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/weekdays", h.UpdateWeekdaysAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	// Structure impact: what a months/weekdays edit would strand, before
+	// the PUT (which takes ?remedy= to fix it).
+	cg.POST("/calendars/:calId/months/impact", h.PreviewMonthsImpactAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/calendars/:calId/weekdays/impact", h.PreviewWeekdaysImpactAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/moons", h.UpdateMoonsAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/seasons", h.UpdateSeasonsAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/eras", h.UpdateErasAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.GET("/calendars/:calId/event-categories", h.GetEventCategoriesAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/event-categories", h.UpdateEventCategoriesAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/calendars/:calId/event-categories/:slug", h.UpsertEventCategoryAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.DELETE("/calendars/:calId/event-categories/:slug", h.DeleteEventCategoryAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	// C-CAL-WCF-UI: internal UI bindings for weather, cycles, festivals.
 	// Data layer / service / syncapi were already shipped; these three
 	// PUTs are what the settings page calls when the operator clicks
@@ -56,13 +62,30 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	// picker reads zone labels); PUT is Owner-only (catalog edit).
 	cg.GET("/calendars/:calId/weather/zones", h.GetWeatherZonesAPI, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.PUT("/calendars/:calId/weather/zones", h.UpdateWeatherZonesAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	// Regional climate (migration 019): per-zone current weather and the
+	// location → zone assignments. Reads are Player+ like the catalog.
+	cg.PUT("/calendars/:calId/weather/zones/:zoneId/current", h.UpdateZoneWeatherAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.GET("/calendars/:calId/location-zones", h.GetLocationZonesAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.PUT("/calendars/:calId/location-zones", h.UpdateLocationZonesAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/cycles", h.UpdateCyclesAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/calendars/:calId/festivals", h.UpdateFestivalsAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/calendars/:calId/year-names", h.UpdateYearNamesAPI, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Advance date/time (Owner only — GMs advance time during play).
 	cg.POST("/calendars/:calId/advance", h.AdvanceDateAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.POST("/calendars/:calId/advance-time", h.AdvanceTimeAPI, campaigns.RequireRole(campaigns.RoleOwner))
 
+	// Advancement history (migration 016). The log is Player+ readable like
+	// the calendar itself; undoing the latest advancement moves the clock,
+	// so it shares the advance gate.
+	cg.GET("/calendars/:calId/date-history", h.DateHistoryAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/calendars/:calId/advance/undo", h.UndoAdvanceAPI, campaigns.RequireRole(campaigns.RoleOwner))
+
+	// Quick-advance presets for the GM console clock. GET is Player+ like
+	// the other settings reads; PUT is Owner-only (catalog edit).
+	cg.GET("/calendars/:calId/time-presets", h.GetTimePresetsAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.PUT("/calendars/:calId/time-presets", h.UpdateTimePresetsAPI, campaigns.RequireRole(campaigns.RoleOwner))
+
 	// Import/export (Owner only).
 	cg.GET("/calendars/:calId/export", h.ExportCalendarAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.POST("/calendars/:calId/import", h.ImportCalendarAPI, campaigns.RequireRole(campaigns.RoleOwner))
@@ -70,6 +93,7 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 
 	// Events CRUD (Scribe+ can create/edit, Owner can delete/set visibility).
 	cg.POST("/calendars/:calId/events", h.CreateEventAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/calendars/:calId/events/bulk", h.BulkCreateEventsAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.PUT("/calendars/:calId/events/:eid", h.UpdateEventAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.PUT("/calendars/:calId/events/:eid/visibility", h.UpdateEventVisibilityAPI, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.DELETE("/calendars/:calId/events/:eid", h.DeleteEventAPI, campaigns.RequireRole(campaigns.RoleOwner))
@@ -148,6 +172,16 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	pub.GET("/calendar/v2/:calId", h.ShowV2, campaigns.RequireViewAccess())
 	pub.GET("/calendar/v2/:calId/:view", h.ShowV2, campaigns.RequireViewAccess())
 
+	// Bookmarkable day pages: /calendar/1492/3/15 (?calendarId= for a
+	// non-active calendar). Same READ surface as the shell; the static "v2"
+	// segment above outranks :year.
+	pub.GET("/calendar/:year/:month/:day", h.ShowDayDetail, campaigns.RequireViewAccess())
+
+	// Day-detail JSON for the grid's click-through panel: the same data as
+	// the day page (recurrences expanded, sky, era, anniversaries) in one
+	// response. ?year=&month=&day= default to the calendar's current date.
+	pub.GET("/calendar/day", h.DayDetailAPI, campaigns.RequireViewAccess())
+
 	// World-state seed GET (C-CAL-WORLDSTATE-SERVER-MODEL). Player+ READ — the
 	// worldstate band lazy-loads this on the public calendar + entity-embed
 	// surfaces, so it is public-capable (GM-only celestial events are filtered
@@ -193,6 +227,18 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	// are gated client-side by IsOwner; mutations go through the
 	// existing V1 PUT endpoints which retain Owner-only auth.
 	cg.GET("/calendar/v2/:calId/settings/:resource", h.ShowV2SubresourceSettings, campaigns.RequireRole(campaigns.RolePlayer))
+
+	// "Campaign so far" timeline (advancement history grouped by play
+	// session). Player+; the undo button renders only for world-state
+	// controllers. The static segment outranks the pub group's /:view.
+	cg.GET("/calendar/v2/:calId/history", h.ShowV2DateHistory, campaigns.RequireRole(campaigns.RolePlayer))
+
+	// User-level "My events" (cross-campaign, read-only). Outside the
+	// campaign group — there is no :id; the handler reads the user's own
+	// memberships and re-checks membership on every hide/show write.
+	my := e.Group("/my-events", auth.RequireAuth(authSvc))
+	my.GET("", h.ShowMyEvents)
+	my.PUT("/hidden-categories", h.UpdateHiddenCategoryAPI)
 }
 
 // legacyRedirect 301s the bare /campaigns/:id/calendar to V2 (C-CAL-V1-V2-
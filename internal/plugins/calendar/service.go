@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
@@ -67,6 +68,12 @@ type CalendarService interface {
 	// Sub-resource bulk updates (replace all).
 	SetMonths(ctx context.Context, calendarID string, months []MonthInput) error
 	SetWeekdays(ctx context.Context, calendarID string, weekdays []WeekdayInput) error
+	// Structure change impact (structure_impact.go): preview what a months
+	// or weekdays edit strands, then save with a remedy for the events.
+	PreviewMonthsImpact(ctx context.Context, calendarID string, months []MonthInput) (*StructureImpact, error)
+	PreviewWeekdaysImpact(ctx context.Context, calendarID string, weekdays []WeekdayInput) (*StructureImpact, error)
+	SetMonthsWithRemedy(ctx context.Context, calendarID string, months []MonthInput, remedy string) error
+	SetWeekdaysWithRemedy(ctx context.Context, calendarID string, weekdays []WeekdayInput, remedy string) error
 	SetMoons(ctx context.Context, calendarID string, moons []MoonInput) error
 	SetSeasons(ctx context.Context, calendarID string, seasons []Season) error
 	SetEras(ctx context.Context, calendarID string, eras []EraInput) error
@@ -78,6 +85,10 @@ type CalendarService interface {
 	DeleteEra(ctx context.Context, eraID int) error
 	SetEventCategories(ctx context.Context, calendarID string, cats []EventCategoryInput) error
 	GetEventCategories(ctx context.Context, calendarID string) ([]EventCategory, error)
+	// Per-category management. UpsertEventCategory creates or updates one
+	// category by slug; DeleteEventCategory 404s an unknown slug.
+	UpsertEventCategory(ctx context.Context, calendarID string, input EventCategoryInput) error
+	DeleteEventCategory(ctx context.Context, calendarID, slug string) error
 
 	// Weather.
 	GetWeather(ctx context.Context, calendarID string) (*Weather, error)
@@ -100,6 +111,14 @@ type CalendarService interface {
 	GetFestivals(ctx context.Context, calendarID string) ([]Festival, error)
 	SetFestivals(ctx context.Context, calendarID string, festivals []FestivalInput) error
 
+	// Time presets (GM console quick-advance buttons).
+	GetTimePresets(ctx context.Context, calendarID string) ([]TimePreset, error)
+	SetTimePresets(ctx context.Context, calendarID string, presets []TimePresetInput) error
+
+	// Named years (explicit per-year names; cyclic names live on Cycles).
+	GetYearNames(ctx context.Context, calendarID string) ([]YearName, error)
+	SetYearNames(ctx context.Context, calendarID string, names []YearNameInput) error
+
 	// Events.
 	CreateEvent(ctx context.Context, calendarID string, input CreateEventInput) (*Event, error)
 	GetEvent(ctx context.Context, eventID string) (*Event, error)
@@ -115,6 +134,12 @@ type CalendarService interface {
 	ListUpcomingEvents(ctx context.Context, calendarID string, limit int, role int, userID string) ([]Event, error)
 	ListEventsForYear(ctx context.Context, calendarID string, year int, role int, userID string) ([]Event, error)
 	ListEventsForDateRange(ctx context.Context, calendarID string, year, startMonth, startDay, endMonth, endDay int, role int, userID string) ([]Event, error)
+	// ListEntityAnniversaries returns entity-linked events from earlier
+	// years on the same month and day (births, deaths, foundings).
+	ListEntityAnniversaries(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error)
+	// SyncEntityDateEvents makes an entity's generated yearly events match
+	// its anniversary date fields (entity_dates.go).
+	SyncEntityDateEvents(ctx context.Context, campaignID string, in EntityDateSync) error
 	// ListAllEventsForCalendar returns every event with no role
 	// filter — for the public Foundry API only. See repository
 	// comment for the rationale.
@@ -123,13 +148,31 @@ type CalendarService interface {
 	// Search.
 	SearchCalendarEvents(ctx context.Context, campaignID, query string, role int) ([]map[string]string, error)
 
-	// Date/time helpers.
-	AdvanceDate(ctx context.Context, calendarID string, days int) error
-	AdvanceTime(ctx context.Context, calendarID string, hours, minutes int) error
+	// Date/time helpers. userID is the actor recorded in the date history
+	// ("" for token-authenticated sync writes).
+	AdvanceDate(ctx context.Context, calendarID, userID string, days int) error
+	AdvanceTime(ctx context.Context, calendarID, userID string, hours, minutes int) error
+	// Date history (date_history_service.go): the advancement log behind the
+	// "campaign so far" timeline, and undo of the latest advancement.
+	ListDateHistory(ctx context.Context, calendarID string) ([]DateAdvancement, error)
+	UndoLastAdvancement(ctx context.Context, calendarID string) (*DateAdvancement, error)
+	// Regional climate (climate_regions_service.go): weather zones double
+	// as climate regions with their own seasons, weather and locations.
+	ListLocationZones(ctx context.Context, calendarID string) ([]LocationZone, error)
+	SetLocationZones(ctx context.Context, calendarID string, assignments []LocationZone) error
+	SetZoneWeather(ctx context.Context, calendarID, zoneID string, input WeatherInput) error
+	RegionalClimate(ctx context.Context, calendarID string) ([]RegionClimate, error)
+	ClimateForLocation(ctx context.Context, calendarID, entityID string) (*LocationClimate, error)
+	// My events (my_events_service.go): the user-level cross-campaign
+	// digest of upcoming events and its per-user category hiding.
+	MyUpcomingEvents(ctx context.Context, userID string, memberships []MyEventsCampaign) ([]MyEventsCalendar, error)
+	SetCategoryHidden(ctx context.Context, userID, calendarID, slug string, hidden bool) error
 	SetDate(ctx context.Context, calendarID string, year, month, day, hour, minute int) error
 
 	// Import/export.
 	ApplyImport(ctx context.Context, calendarID string, result *ImportResult) error
+	ImportEvents(ctx context.Context, calendarID, userID string, events []ExportEvent) (int, error)
+	BulkCreateEvents(ctx context.Context, calendarID, userID string, events []ExportEvent) (*BulkEventResult, error)
 	ListAllEvents(ctx context.Context, calendarID string) ([]Event, error)
 
 	// Entity ties (C-CAL-ENTITY-TIES-DATA-MODEL). Optional M:N both ways
@@ -139,6 +182,9 @@ type CalendarService interface {
 	// against the four pinned ParticipationRoles; era roles are optional.
 	LinkEntityToEvent(ctx context.Context, entityID, eventID, role string) error
 	UnlinkEntityFromEvent(ctx context.Context, entityID, eventID string) error
+	// EnsureEventEntityLinks is the boot reconciler that ties each event's
+	// legacy entity_id into entity_event_links; see mirrorPrimaryEntityLink.
+	EnsureEventEntityLinks(ctx context.Context) (int, error)
 	LinkEntityToEra(ctx context.Context, entityID string, eraID int, role *string) error
 	UnlinkEntityFromEra(ctx context.Context, entityID string, eraID int) error
 	EventsForEntity(ctx context.Context, entityID string) ([]EntityEventTie, error)
@@ -150,6 +196,10 @@ type CalendarService interface {
 	// viewer's role + userID so players never see dm_only / custom-restricted
 	// entity names (cordinator#32 gap #1). Owners/co-DMs see all.
 	EntitiesForCalendar(ctx context.Context, calendarID string, role int, userID string) ([]EntityTieRef, error)
+	// EntityEventBacklinks lists the campaign's events tied to an entity,
+	// visibility-filtered for the viewer, as generic maps for the entities
+	// plugin's "Appears in" backlinks section (same seam as SearchCalendarEvents).
+	EntityEventBacklinks(ctx context.Context, campaignID, entityID string, role int, userID string) ([]map[string]string, error)
 	// CreateEntityFromEvent creates a campaign entity (via the cross-plugin
 	// EntityCreator) named after the event and links it to that event
 	// (C-CAL-EDITOR-EXPANSION PR1). Returns the new entity's id. The creator is
@@ -165,6 +215,8 @@ type CalendarService interface {
 
 	// Wiring.
 	SetEventPublisher(pub CalendarEventPublisher)
+	SetDateStructureListener(l DateStructureListener)
+	SetDateFieldScanner(d DateFieldScanner)
 }
 
 // WorldStateUpdateInput is the writable slice of world-state the PUT exposes.
@@ -268,11 +320,21 @@ type BindingCleaner interface {
 	OnInstanceDeleted(ctx context.Context, campaignID, widgetType, instanceID string) (int, error)
 }
 
+// DateStructureListener hears when a calendar's dates change shape (months
+// replaced, leap rules edited, an import applied), so dates stored outside
+// the plugin can be re-checked. Implemented in internal/app over the
+// entities plugin's date fields.
+type DateStructureListener interface {
+	CalendarDatesChanged(ctx context.Context, campaignID string)
+}
+
 // calendarService is the default CalendarService implementation.
 type calendarService struct {
 	repo           CalendarRepository
 	events         CalendarEventPublisher
 	bindingCleaner BindingCleaner
+	dateListener   DateStructureListener
+	dateScanner    DateFieldScanner
 	// now is the injectable wall clock (C-REAL-CALENDAR-P1). Defaults to
 	// time.Now via NewCalendarService; tests substitute a fixed instant to
 	// pin real-time behavior deterministically (DST edges, Feb-29 vs Feb-2100,
@@ -301,6 +363,19 @@ func NewCalendarService(repo CalendarRepository) CalendarService {
 // routes.go so the CalendarService interface stays unchanged.
 func (s *calendarService) SetBindingCleaner(c BindingCleaner) { s.bindingCleaner = c }
 
+// SetDateStructureListener wires re-checking of dates stored elsewhere.
+func (s *calendarService) SetDateStructureListener(l DateStructureListener) {
+	s.dateListener = l
+}
+
+// notifyDatesChanged tells the listener, if any, the calendar's dates
+// changed shape.
+func (s *calendarService) notifyDatesChanged(ctx context.Context, campaignID string) {
+	if s.dateListener != nil && campaignID != "" {
+		s.dateListener.CalendarDatesChanged(ctx, campaignID)
+	}
+}
+
 // SetEventPublisher sets the event publisher for real-time sync.
 func (s *calendarService) SetEventPublisher(pub CalendarEventPublisher) {
 	s.events = pub
@@ -686,6 +761,9 @@ func (s *calendarService) eagerLoad(ctx context.Context, cal *Calendar) (*Calend
 	if cal.Festivals, err = s.repo.GetFestivals(ctx, cal.ID); err != nil {
 		return nil, fmt.Errorf("get festivals: %w", err)
 	}
+	if cal.YearNames, err = s.repo.GetYearNames(ctx, cal.ID); err != nil {
+		return nil, fmt.Errorf("get year names: %w", err)
+	}
 	// Weather is best-effort: a missing row is a normal "no weather
 	// set yet" state, not a load failure. Repo returns nil + nil err
 	// in that case.
@@ -808,6 +886,7 @@ func (s *calendarService) UpdateCalendar(ctx context.Context, calendarID string,
 	cal.HoursPerDay = input.HoursPerDay
 	cal.MinutesPerHour = input.MinutesPerHour
 	cal.SecondsPerMinute = input.SecondsPerMinute
+	leapChanged := cal.LeapYearEvery != input.LeapYearEvery || cal.LeapYearOffset != input.LeapYearOffset
 	cal.LeapYearEvery = input.LeapYearEvery
 	cal.LeapYearOffset = input.LeapYearOffset
 
@@ -822,6 +901,9 @@ func (s *calendarService) UpdateCalendar(ctx context.Context, calendarID string,
 	if err := s.repo.Update(ctx, cal); err != nil {
 		return fmt.Errorf("update calendar: %w", err)
 	}
+	if leapChanged {
+		s.notifyDatesChanged(ctx, cal.CampaignID)
+	}
 	return nil
 }
 
@@ -1115,11 +1197,47 @@ func (s *calendarService) SetSidebarPinned(ctx context.Context, userID, campaign
 	return s.repo.SetSidebarPinned(ctx, userID, campaignID, pinned)
 }
 
-// SetMonths replaces all months. Validates at least one month exists.
+// SetMonths replaces all months, leaving events as they are.
 func (s *calendarService) SetMonths(ctx context.Context, calendarID string, months []MonthInput) error {
+	return s.SetMonthsWithRemedy(ctx, calendarID, months, "")
+}
+
+// SetMonthsWithRemedy replaces all months and, with a remedy, fixes the
+// events the change strands in the same transaction (structure_impact.go).
+func (s *calendarService) SetMonthsWithRemedy(ctx context.Context, calendarID string, months []MonthInput, remedy string) error {
+	if err := s.validateMonths(ctx, calendarID, months); err != nil {
+		return err
+	}
+	fixes, err := s.remedyFixes(ctx, calendarID, months, nil, remedy)
+	if err != nil {
+		return err
+	}
+	if len(fixes) > 0 {
+		err = s.repo.SetStructureWithFixes(ctx, calendarID, months, nil, fixes)
+	} else {
+		err = s.repo.SetMonths(ctx, calendarID, months)
+	}
+	if err != nil {
+		return err
+	}
+	s.publishStructureUpdated(ctx, calendarID)
+	if cal, err := s.repo.GetByID(ctx, calendarID); err == nil && cal != nil {
+		s.notifyDatesChanged(ctx, cal.CampaignID)
+	}
+	return nil
+}
+
+// validateMonths checks a months list. Validates at least one month exists.
+func (s *calendarService) validateMonths(ctx context.Context, calendarID string, months []MonthInput) error {
 	if len(months) == 0 {
 		return apperror.NewValidation("calendar must have at least one month")
 	}
+	// Starting weekdays index into the weekday list, so bound them by it when
+	// one exists (a brand-new calendar may set months before weekdays).
+	weekdays, err := s.repo.GetWeekdays(ctx, calendarID)
+	if err != nil {
+		return err
+	}
 	for i, m := range months {
 		if m.Name == "" {
 			return apperror.NewValidation(fmt.Sprintf("month %d: name is required", i+1))
@@ -1130,16 +1248,50 @@ func (s *calendarService) SetMonths(ctx context.Context, calendarID string, mont
 		if m.LeapYearDays < 0 {
 			return apperror.NewValidation(fmt.Sprintf("month %q: leap_year_days cannot be negative", m.Name))
 		}
+		if m.SkipsWeekday && !m.IsIntercalary {
+			return apperror.NewValidation(fmt.Sprintf("month %q: only intercalary months can sit outside the week", m.Name))
+		}
+		if w := m.StartingWeekday; w != nil {
+			if m.SkipsWeekday {
+				return apperror.NewValidation(fmt.Sprintf("month %q: a month outside the week cannot have a starting weekday", m.Name))
+			}
+			if *w < 0 || (len(weekdays) > 0 && *w >= len(weekdays)) {
+				return apperror.NewValidation(fmt.Sprintf("month %q: starting_weekday must name one of the calendar's weekdays", m.Name))
+			}
+		}
 	}
-	if err := s.repo.SetMonths(ctx, calendarID, months); err != nil {
+	return nil
+}
+
+// SetWeekdays replaces all weekdays, leaving events as they are.
+func (s *calendarService) SetWeekdays(ctx context.Context, calendarID string, weekdays []WeekdayInput) error {
+	return s.SetWeekdaysWithRemedy(ctx, calendarID, weekdays, "")
+}
+
+// SetWeekdaysWithRemedy replaces all weekdays and, with a remedy, fixes
+// weekly events on weekdays that no longer exist in the same transaction.
+func (s *calendarService) SetWeekdaysWithRemedy(ctx context.Context, calendarID string, weekdays []WeekdayInput, remedy string) error {
+	if err := validateWeekdays(weekdays); err != nil {
+		return err
+	}
+	fixes, err := s.remedyFixes(ctx, calendarID, nil, weekdays, remedy)
+	if err != nil {
+		return err
+	}
+	if len(fixes) > 0 {
+		err = s.repo.SetStructureWithFixes(ctx, calendarID, nil, weekdays, fixes)
+	} else {
+		err = s.repo.SetWeekdays(ctx, calendarID, weekdays)
+	}
+	if err != nil {
 		return err
 	}
 	s.publishStructureUpdated(ctx, calendarID)
 	return nil
 }
 
-// SetWeekdays replaces all weekdays.
-func (s *calendarService) SetWeekdays(ctx context.Context, calendarID string, weekdays []WeekdayInput) error {
+// validateWeekdays checks a weekdays list.
+func validateWeekdays(weekdays []WeekdayInput) error {
 	if len(weekdays) == 0 {
 		return apperror.NewValidation("calendar must have at least one weekday")
 	}
@@ -1148,10 +1300,6 @@ func (s *calendarService) SetWeekdays(ctx context.Context, calendarID string, we
 			return apperror.NewValidation(fmt.Sprintf("weekday %d: name is required", i+1))
 		}
 	}
-	if err := s.repo.SetWeekdays(ctx, calendarID, weekdays); err != nil {
-		return err
-	}
-	s.publishStructureUpdated(ctx, calendarID)
 	return nil
 }
 
@@ -1179,6 +1327,9 @@ func (s *calendarService) SetSeasons(ctx context.Context, calendarID string, sea
 			return apperror.NewValidation(fmt.Sprintf("season %d: name is required", i+1))
 		}
 	}
+	if err := s.validateSeasonZones(ctx, calendarID, seasons); err != nil {
+		return err
+	}
 	if err := s.repo.SetSeasons(ctx, calendarID, seasons); err != nil {
 		return err
 	}
@@ -1271,22 +1422,71 @@ func (s *calendarService) DeleteEra(ctx context.Context, eraID int) error {
 	return nil
 }
 
-// SetEventCategories replaces all event categories. Validates names and slugs.
+// SetEventCategories replaces all event categories. Validates names, slugs,
+// and default visibility.
 func (s *calendarService) SetEventCategories(ctx context.Context, calendarID string, cats []EventCategoryInput) error {
-	for i, c := range cats {
-		if c.Name == "" {
-			return apperror.NewValidation(fmt.Sprintf("category %d: name is required", i+1))
-		}
-		if c.Slug == "" {
-			return apperror.NewValidation(fmt.Sprintf("category %d: slug is required", i+1))
-		}
-		if c.Color == "" {
-			cats[i].Color = "#6b7280"
+	for i := range cats {
+		if err := validateEventCategoryInput(&cats[i], fmt.Sprintf("category %d", i+1)); err != nil {
+			return err
 		}
 	}
 	return s.repo.SetEventCategories(ctx, calendarID, cats)
 }
 
+// validateEventCategoryInput runs the per-row rules shared by the bulk
+// replace, the per-category upsert, and import. Normalizes empty color and
+// default visibility in place. label prefixes error messages so the bulk
+// path can point at the offending row.
+func validateEventCategoryInput(in *EventCategoryInput, label string) error {
+	in.Slug = strings.TrimSpace(in.Slug)
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" {
+		return apperror.NewValidation(label + ": name is required")
+	}
+	if in.Slug == "" {
+		return apperror.NewValidation(label + ": slug is required")
+	}
+	if len(in.Slug) > 50 {
+		return apperror.NewValidation(label + ": slug must be 50 characters or less")
+	}
+	if len(in.Name) > 100 {
+		return apperror.NewValidation(label + ": name must be 100 characters or less")
+	}
+	if in.Color == "" {
+		in.Color = "#6b7280"
+	}
+	if in.DefaultVisibility == "" {
+		in.DefaultVisibility = "everyone"
+	}
+	if in.DefaultVisibility != "everyone" && in.DefaultVisibility != "dm_only" {
+		return apperror.NewValidation(label + ": default_visibility must be 'everyone' or 'dm_only'")
+	}
+	return nil
+}
+
+// UpsertEventCategory creates or updates a single category by slug.
+func (s *calendarService) UpsertEventCategory(ctx context.Context, calendarID string, input EventCategoryInput) error {
+	if err := validateEventCategoryInput(&input, "category"); err != nil {
+		return err
+	}
+	if err := s.repo.UpsertEventCategory(ctx, calendarID, input); err != nil {
+		return fmt.Errorf("upsert event category: %w", err)
+	}
+	return nil
+}
+
+// DeleteEventCategory removes a single category by slug.
+func (s *calendarService) DeleteEventCategory(ctx context.Context, calendarID, slug string) error {
+	found, err := s.repo.DeleteEventCategory(ctx, calendarID, slug)
+	if err != nil {
+		return fmt.Errorf("delete event category: %w", err)
+	}
+	if !found {
+		return apperror.NewNotFound("event category not found")
+	}
+	return nil
+}
+
 // GetEventCategories returns all event categories for a calendar.
 func (s *calendarService) GetEventCategories(ctx context.Context, calendarID string) ([]EventCategory, error) {
 	return s.repo.GetEventCategories(ctx, calendarID)
@@ -1627,6 +1827,96 @@ func (s *calendarService) SetFestivals(ctx context.Context, calendarID string, f
 	return nil
 }
 
+// GetTimePresets returns a calendar's quick-advance presets, or the built-in
+// defaults when the owner has never saved any.
+func (s *calendarService) GetTimePresets(ctx context.Context, calendarID string) ([]TimePreset, error) {
+	presets, err := s.repo.GetTimePresets(ctx, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("get time presets: %w", err)
+	}
+	if len(presets) == 0 {
+		return DefaultTimePresets(), nil
+	}
+	return presets, nil
+}
+
+// SetTimePresets replaces a calendar's quick-advance presets. Saving an
+// empty list restores the defaults. Bounds mirror the advance endpoints
+// (days ≤ 3650, hours ≤ 87600) so a saved preset can never be rejected
+// when it's clicked.
+func (s *calendarService) SetTimePresets(ctx context.Context, calendarID string, presets []TimePresetInput) error {
+	if len(presets) > maxTimePresets {
+		return apperror.NewValidation(fmt.Sprintf("at most %d time presets are allowed", maxTimePresets))
+	}
+	for i := range presets {
+		p := &presets[i]
+		p.Name = strings.TrimSpace(p.Name)
+		if p.Name == "" {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: name is required", i+1))
+		}
+		if len(p.Name) > 100 {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: name must be at most 100 characters", i+1))
+		}
+		if p.Days < 0 || p.Hours < 0 || p.Minutes < 0 {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: amounts must be non-negative", i+1))
+		}
+		// One preset = one advance call: days go through AdvanceDate,
+		// hours/minutes through AdvanceTime.
+		if p.Days > 0 && (p.Hours > 0 || p.Minutes > 0) {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: advance by days or by hours/minutes, not both", i+1))
+		}
+		if p.Days == 0 && p.Hours == 0 && p.Minutes == 0 {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: must advance by at least 1 minute", i+1))
+		}
+		if p.Days > 3650 || p.Hours > 87600 || p.Minutes > 100000 {
+			return apperror.NewValidation(fmt.Sprintf("time preset %d: advance is too large", i+1))
+		}
+		p.SortOrder = i + 1
+	}
+	if err := s.repo.SetTimePresets(ctx, calendarID, presets); err != nil {
+		return fmt.Errorf("set time presets: %w", err)
+	}
+	return nil
+}
+
+// GetYearNames returns a calendar's named years in year order.
+func (s *calendarService) GetYearNames(ctx context.Context, calendarID string) ([]YearName, error) {
+	return s.repo.GetYearNames(ctx, calendarID)
+}
+
+// SetYearNames replaces a calendar's named years.
+func (s *calendarService) SetYearNames(ctx context.Context, calendarID string, names []YearNameInput) error {
+	if err := validateYearNames(names); err != nil {
+		return err
+	}
+	if err := s.repo.SetYearNames(ctx, calendarID, names); err != nil {
+		return fmt.Errorf("set year names: %w", err)
+	}
+	s.publishStructureUpdated(ctx, calendarID)
+	return nil
+}
+
+// validateYearNames trims names in place and enforces one name per year —
+// shared by SetYearNames and ApplyImport so both reject the same input.
+func validateYearNames(names []YearNameInput) error {
+	seen := make(map[int]bool, len(names))
+	for i := range names {
+		n := &names[i]
+		n.Name = strings.TrimSpace(n.Name)
+		if n.Name == "" {
+			return apperror.NewValidation(fmt.Sprintf("year name %d: name is required", i+1))
+		}
+		if len(n.Name) > 200 {
+			return apperror.NewValidation(fmt.Sprintf("year %d: name must be at most 200 characters", n.Year))
+		}
+		if seen[n.Year] {
+			return apperror.NewValidation(fmt.Sprintf("year %d is named more than once", n.Year))
+		}
+		seen[n.Year] = true
+	}
+	return nil
+}
+
 // CreateEvent creates a new calendar event.
 func (s *calendarService) CreateEvent(ctx context.Context, calendarID string, input CreateEventInput) (*Event, error) {
 	if input.Name == "" {
@@ -1642,7 +1932,7 @@ func (s *calendarService) CreateEvent(ctx context.Context, calendarID string, in
 		return nil, apperror.NewValidation("day must be at least 1")
 	}
 	if input.Visibility == "" {
-		input.Visibility = "everyone"
+		input.Visibility = s.categoryDefaultVisibility(ctx, calendarID, input.Category)
 	}
 	if input.Visibility != "everyone" && input.Visibility != "dm_only" {
 		return nil, apperror.NewValidation("visibility must be 'everyone' or 'dm_only'")
@@ -1650,6 +1940,18 @@ func (s *calendarService) CreateEvent(ctx context.Context, calendarID string, in
 	if err := validateVisibilityRules(input.VisibilityRules); err != nil {
 		return nil, err
 	}
+	// Validate every tie up front so a bad role can't leave a half-linked event.
+	roles := make([]ParticipationRole, len(input.LinkedEntities))
+	for i, l := range input.LinkedEntities {
+		if l.EntityID == "" {
+			return nil, apperror.NewValidation("linked entity is missing entity_id")
+		}
+		pr, err := validateEventRole(l.Role)
+		if err != nil {
+			return nil, err
+		}
+		roles[i] = pr
+	}
 
 	// Sanitize HTML if provided (rich text descriptions from TipTap editor).
 	var descHTML *string
@@ -1703,6 +2005,14 @@ func (s *calendarService) CreateEvent(ctx context.Context, calendarID string, in
 	if err := s.repo.CreateEvent(ctx, evt); err != nil {
 		return nil, fmt.Errorf("create event: %w", err)
 	}
+	s.mirrorPrimaryEntityLink(ctx, evt)
+	// The event exists even if a tie fails, so surface the error (not swallow
+	// it) for an explicit retry — same contract as CreateEntityFromEvent.
+	for i, l := range input.LinkedEntities {
+		if err := s.repo.LinkEntityEvent(ctx, l.EntityID, evt.ID, string(roles[i])); err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("linking entity %s to event %s: %w", l.EntityID, evt.ID, err))
+		}
+	}
 	// Resolve campaign ID for event publishing.
 	if cal, err := s.repo.GetByID(ctx, calendarID); err == nil && cal != nil {
 		s.events.PublishCalendarEvent("event.created", cal.CampaignID, evt.ID, evt)
@@ -1710,6 +2020,26 @@ func (s *calendarService) CreateEvent(ctx context.Context, calendarID string, in
 	return evt, nil
 }
 
+// categoryDefaultVisibility resolves the visibility a new event gets when the
+// caller didn't pick one: the category's default if the event has a known
+// category, otherwise "everyone". Best-effort — a category lookup failure
+// falls back to "everyone" rather than blocking the create.
+func (s *calendarService) categoryDefaultVisibility(ctx context.Context, calendarID string, category *string) string {
+	if category == nil || *category == "" {
+		return "everyone"
+	}
+	cats, err := s.repo.GetEventCategories(ctx, calendarID)
+	if err != nil {
+		slog.Warn("load event categories for default visibility failed",
+			slog.String("calendar_id", calendarID), slog.Any("error", err))
+		return "everyone"
+	}
+	if c := FindEventCategory(cats, *category); c != nil && c.DefaultVisibility != "" {
+		return c.DefaultVisibility
+	}
+	return "everyone"
+}
+
 // GetEvent returns an event by ID.
 func (s *calendarService) GetEvent(ctx context.Context, eventID string) (*Event, error) {
 	evt, err := s.repo.GetEvent(ctx, eventID)
@@ -1867,12 +2197,29 @@ func (s *calendarService) UpdateEvent(ctx context.Context, eventID string, input
 	if err := s.repo.UpdateEvent(ctx, evt); err != nil {
 		return err
 	}
+	s.mirrorPrimaryEntityLink(ctx, evt)
 	if cal, err := s.repo.GetByID(ctx, evt.CalendarID); err == nil && cal != nil {
 		s.events.PublishCalendarEvent("event.updated", cal.CampaignID, evt.ID, evt)
 	}
 	return nil
 }
 
+// mirrorPrimaryEntityLink copies the legacy single entity_id into
+// entity_event_links so the entity page and its "appears in" count (both read
+// the link table) include it. An existing tie keeps its role. Clearing or
+// changing entity_id leaves the old tie in place — it may have been attached
+// through the picker on purpose; the picker is where ties are removed.
+// Best-effort: the event is already saved, so a failure is only logged.
+func (s *calendarService) mirrorPrimaryEntityLink(ctx context.Context, evt *Event) {
+	if evt.EntityID == nil || *evt.EntityID == "" {
+		return
+	}
+	if err := s.repo.LinkEntityEventIfAbsent(ctx, *evt.EntityID, evt.ID); err != nil {
+		slog.Warn("mirroring event entity link",
+			slog.String("event_id", evt.ID), slog.String("entity_id", *evt.EntityID), slog.Any("error", err))
+	}
+}
+
 // DeleteEvent removes an event.
 func (s *calendarService) DeleteEvent(ctx context.Context, eventID string) error {
 	// Fetch event before deletion for event publishing.
@@ -1923,6 +2270,16 @@ func (s *calendarService) ListEventsForYear(ctx context.Context, calendarID stri
 	return filterEventsByUser(events, role, userID), nil
 }
 
+// ListEntityAnniversaries returns earlier years' entity-linked events on
+// (month, day), filtered by per-user rules.
+func (s *calendarService) ListEntityAnniversaries(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error) {
+	events, err := s.repo.ListEntityAnniversaries(ctx, calendarID, year, month, day, role, userID)
+	if err != nil {
+		return nil, err
+	}
+	return filterEventsByUser(events, role, userID), nil
+}
+
 // ListEventsForDateRange returns events within a date range for a given year.
 func (s *calendarService) ListEventsForDateRange(ctx context.Context, calendarID string, year, startMonth, startDay, endMonth, endDay int, role int, userID string) ([]Event, error) {
 	events, err := s.repo.ListEventsForDateRange(ctx, calendarID, year, startMonth, startDay, endMonth, endDay, role)
@@ -1998,7 +2355,7 @@ func (s *calendarService) ListUpcomingEvents(ctx context.Context, calendarID str
 
 // AdvanceDate moves the current date forward by the given number of days,
 // rolling over months and years as needed. Accounts for leap years.
-func (s *calendarService) AdvanceDate(ctx context.Context, calendarID string, days int) error {
+func (s *calendarService) AdvanceDate(ctx context.Context, calendarID, userID string, days int) error {
 	cal, err := s.repo.GetByID(ctx, calendarID)
 	if err != nil {
 		return fmt.Errorf("get calendar: %w", err)
@@ -2025,6 +2382,7 @@ func (s *calendarService) AdvanceDate(ctx context.Context, calendarID string, da
 
 	// Snapshot before state for change detection.
 	beforeSeason, beforeEra, beforeMoonPhases := s.snapshotState(ctx, cal)
+	before := *cal
 
 	day := cal.CurrentDay
 	monthIdx := cal.CurrentMonth - 1 // 0-indexed
@@ -2049,6 +2407,7 @@ func (s *calendarService) AdvanceDate(ctx context.Context, calendarID string, da
 	if err := s.repo.Update(ctx, cal); err != nil {
 		return err
 	}
+	s.recordAdvancement(ctx, &before, cal, userID, AdvanceKindDate, advancedMinutes(cal, days, 0, 0))
 	s.events.PublishCalendarEvent("date.advanced", cal.CampaignID, calendarID, map[string]int{
 		"year":  cal.CurrentYear,
 		"month": cal.CurrentMonth,
@@ -2061,7 +2420,7 @@ func (s *calendarService) AdvanceDate(ctx context.Context, calendarID string, da
 
 // AdvanceTime moves the current time forward by the given hours and minutes,
 // rolling over into days (and subsequently months/years) as needed.
-func (s *calendarService) AdvanceTime(ctx context.Context, calendarID string, hours, minutes int) error {
+func (s *calendarService) AdvanceTime(ctx context.Context, calendarID, userID string, hours, minutes int) error {
 	cal, err := s.repo.GetByID(ctx, calendarID)
 	if err != nil {
 		return fmt.Errorf("get calendar: %w", err)
@@ -2082,6 +2441,7 @@ func (s *calendarService) AdvanceTime(ctx context.Context, calendarID string, ho
 		return apperror.NewValidation("calendar has no months configured")
 	}
 	cal.Months = months
+	before := *cal
 
 	hpd := cal.HoursPerDay
 	if hpd <= 0 {
@@ -2126,7 +2486,11 @@ func (s *calendarService) AdvanceTime(ctx context.Context, calendarID string, ho
 		cal.CurrentYear = year
 	}
 
-	return s.repo.Update(ctx, cal)
+	if err := s.repo.Update(ctx, cal); err != nil {
+		return err
+	}
+	s.recordAdvancement(ctx, &before, cal, userID, AdvanceKindTime, advancedMinutes(cal, 0, hours, minutes))
+	return nil
 }
 
 // SetDate sets the calendar's current date/time to an absolute value.
@@ -2277,6 +2641,19 @@ func (s *calendarService) ApplyImport(ctx context.Context, calendarID string, re
 			result.Eras[i].Color = "#6366f1"
 		}
 	}
+	for i := range result.Categories {
+		if err := validateEventCategoryInput(&result.Categories[i], fmt.Sprintf("category %d", i+1)); err != nil {
+			return err
+		}
+	}
+	for i, c := range result.Cycles {
+		if c.Name == "" {
+			return apperror.NewValidation(fmt.Sprintf("cycle %d: name is required", i+1))
+		}
+	}
+	if err := validateYearNames(result.YearNames); err != nil {
+		return err
+	}
 
 	// Mutate cal to reflect import-side fields; repo.ApplyImport reads
 	// these to UPDATE the calendars row within the tx.
@@ -2326,21 +2703,101 @@ func (s *calendarService) ApplyImport(ctx context.Context, calendarID string, re
 	}
 
 	s.publishStructureUpdated(ctx, calendarID)
+	s.notifyDatesChanged(ctx, cal.CampaignID)
 	return nil
 }
 
-// ListAllEvents returns all events for a calendar (owner visibility, no limit).
-// Used for calendar export.
-func (s *calendarService) ListAllEvents(ctx context.Context, calendarID string) ([]Event, error) {
-	cal, err := s.repo.GetByID(ctx, calendarID)
+// ImportEvents creates the events parsed from an import file. Each event goes
+// through CreateEvent so imported rows get the same validation, HTML
+// sanitization, and category-default visibility as hand-entered ones. The
+// calendar structure has already been applied by this point, so a bad row
+// is logged and skipped rather than failing the import.
+//
+// Events already on the calendar (same name and base date) are skipped, so
+// re-importing a file — or a Foundry module re-syncing its festivals through
+// the sync API — doesn't stack duplicates. Returns the number created.
+func (s *calendarService) ImportEvents(ctx context.Context, calendarID, userID string, events []ExportEvent) (int, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+	existing, err := s.repo.ListAllEvents(ctx, calendarID)
 	if err != nil {
-		return nil, fmt.Errorf("get calendar: %w", err)
+		return 0, fmt.Errorf("list existing events: %w", err)
 	}
-	if cal == nil {
-		return nil, nil
+	type eventKey struct {
+		name             string
+		year, month, day int
+	}
+	seen := make(map[eventKey]bool, len(existing))
+	for _, e := range existing {
+		seen[eventKey{e.Name, e.Year, e.Month, e.Day}] = true
 	}
-	// Use current year, owner role (3) to get all events including dm_only.
-	return s.repo.ListEventsForYear(ctx, calendarID, cal.CurrentYear, 3)
+
+	created := 0
+	for _, e := range events {
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+		key := eventKey{e.Name, e.Year, e.Month, e.Day}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		_, err := s.CreateEvent(ctx, calendarID, e.createInput(userID))
+		if err != nil {
+			slog.Warn("import: skipping event",
+				slog.String("calendar_id", calendarID),
+				slog.String("event", e.Name),
+				slog.Any("error", err))
+			continue
+		}
+		created++
+	}
+	return created, nil
+}
+
+// BulkCreateEvents creates up to maxBulkEvents events in one call, for
+// seeding festivals and holidays. Unlike ImportEvents the caller gets
+// per-row feedback: a row that fails validation is reported in the result
+// and the rest still land. Server-side failures are logged and reported
+// with a generic message so internals don't leak into the response.
+func (s *calendarService) BulkCreateEvents(ctx context.Context, calendarID, userID string, events []ExportEvent) (*BulkEventResult, error) {
+	if len(events) == 0 {
+		return nil, apperror.NewValidation("at least one event is required")
+	}
+	if len(events) > maxBulkEvents {
+		return nil, apperror.NewValidation(fmt.Sprintf("at most %d events can be created at once", maxBulkEvents))
+	}
+
+	result := &BulkEventResult{}
+	for i, e := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if _, err := s.CreateEvent(ctx, calendarID, e.createInput(userID)); err != nil {
+			msg := "could not create event"
+			var appErr *apperror.AppError
+			if errors.As(err, &appErr) && appErr.Code < 500 {
+				msg = appErr.Message
+			} else {
+				slog.Error("bulk create: event failed",
+					slog.String("calendar_id", calendarID),
+					slog.Int("row", i+1),
+					slog.Any("error", err))
+			}
+			result.Errors = append(result.Errors, BulkEventError{Row: i + 1, Name: e.Name, Message: msg})
+			continue
+		}
+		result.Created++
+	}
+	return result, nil
+}
+
+// ListAllEvents returns all events for a calendar (owner visibility, no limit).
+// Used for calendar export, so it must span every year — not just the
+// current one — or a re-import would silently lose history.
+func (s *calendarService) ListAllEvents(ctx context.Context, calendarID string) ([]Event, error) {
+	return s.repo.ListAllEvents(ctx, calendarID)
 }
 
 // --- Visibility Helpers ---
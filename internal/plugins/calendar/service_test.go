@@ -20,6 +20,7 @@ type mockCalendarRepo struct {
 	getMonthsFn              func(ctx context.Context, calendarID string) ([]Month, error)
 	setWeekdaysFn            func(ctx context.Context, calendarID string, weekdays []WeekdayInput) error
 	getWeekdaysFn            func(ctx context.Context, calendarID string) ([]Weekday, error)
+	setStructureFixesFn      func(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput, fixes []EventDateFix) error
 	setMoonsFn               func(ctx context.Context, calendarID string, moons []MoonInput) error
 	getMoonsFn               func(ctx context.Context, calendarID string) ([]Moon, error)
 	setSeasonsFn             func(ctx context.Context, calendarID string, seasons []Season) error
@@ -28,6 +29,8 @@ type mockCalendarRepo struct {
 	getErasFn                func(ctx context.Context, calendarID string) ([]Era, error)
 	setEventCategoriesFn     func(ctx context.Context, calendarID string, cats []EventCategoryInput) error
 	getEventCategoriesFn     func(ctx context.Context, calendarID string) ([]EventCategory, error)
+	upsertEventCategoryFn    func(ctx context.Context, calendarID string, cat EventCategoryInput) error
+	deleteEventCategoryFn    func(ctx context.Context, calendarID, slug string) (bool, error)
 	createEventFn            func(ctx context.Context, evt *Event) error
 	getEventFn               func(ctx context.Context, id string) (*Event, error)
 	updateEventFn            func(ctx context.Context, evt *Event) error
@@ -35,7 +38,10 @@ type mockCalendarRepo struct {
 	listEventsForMonthFn     func(ctx context.Context, calendarID string, year, month int, role int) ([]Event, error)
 	listEventsForYearFn      func(ctx context.Context, calendarID string, year int, role int) ([]Event, error)
 	listEventsForDateRangeFn func(ctx context.Context, calendarID string, year, startMonth, startDay, endMonth, endDay int, role int) ([]Event, error)
+	listAllEventsFn          func(ctx context.Context, calendarID string) ([]Event, error)
 	listEventsForEntityFn    func(ctx context.Context, entityID string, role int) ([]Event, error)
+	listAnniversariesFn      func(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error)
+	replaceDateEventsFn      func(ctx context.Context, calendarID, entityID string, events []Event) error
 	listUpcomingEventsFn     func(ctx context.Context, calendarID string, year, month, day int, role int, limit int) ([]Event, error)
 	searchEventsFn           func(ctx context.Context, calendarID, query string, role int) ([]Event, error)
 	updateEventVisFn         func(ctx context.Context, eventID string, visibility string, visRules *string) error
@@ -64,6 +70,8 @@ type mockCalendarRepo struct {
 	setSidebarPinnedFn func(ctx context.Context, userID, campaignID string, pinned bool) error
 	// C-CAL-ENTITY-TIES-DATA-MODEL: link-table injection.
 	linkEntityEventFn     func(ctx context.Context, entityID, eventID, role string) error
+	linkIfAbsentFn        func(ctx context.Context, entityID, eventID string) error
+	backfillLinksFn       func(ctx context.Context) (int, error)
 	unlinkEntityEventFn   func(ctx context.Context, entityID, eventID string) error
 	linkEntityEraFn       func(ctx context.Context, entityID string, eraID int, role *string) error
 	unlinkEntityEraFn     func(ctx context.Context, entityID string, eraID int) error
@@ -82,6 +90,32 @@ type mockCalendarRepo struct {
 	getMoonPhasesFn              func(ctx context.Context, calendarID string) (map[int][]MoonPhaseVocab, error)
 	getSpecialDaysFn             func(ctx context.Context, calendarID string, year, month, day int) ([]SpecialDay, error)
 	setMoodTintFn                func(ctx context.Context, calendarID string, color *string, intensity *float64) error
+
+	// Time presets (GM console quick-advance).
+	getTimePresetsFn func(ctx context.Context, calendarID string) ([]TimePreset, error)
+	setTimePresetsFn func(ctx context.Context, calendarID string, presets []TimePresetInput) error
+
+	// Named years.
+	getYearNamesFn func(ctx context.Context, calendarID string) ([]YearName, error)
+	setYearNamesFn func(ctx context.Context, calendarID string, names []YearNameInput) error
+
+	// Date history (clock advancement log).
+	recordDateAdvancementFn     func(ctx context.Context, a *DateAdvancement) error
+	listDateHistoryFn           func(ctx context.Context, calendarID string, limit int) ([]DateAdvancement, error)
+	lastDateAdvancementFn       func(ctx context.Context, calendarID string) (*DateAdvancement, error)
+	markDateAdvancementUndoneFn func(ctx context.Context, id int) error
+
+	// Regional climate.
+	getZoneWeatherFn          func(ctx context.Context, calendarID string) ([]Weather, error)
+	setZoneWeatherFn          func(ctx context.Context, calendarID, zoneID string, input WeatherInput) error
+	listLocationZonesFn       func(ctx context.Context, calendarID string) ([]LocationZone, error)
+	setLocationZonesFn        func(ctx context.Context, calendarID string, assignments []LocationZone) error
+	locationAncestryFn        func(ctx context.Context, entityID string) ([]string, error)
+	entitiesOutsideCampaignFn func(ctx context.Context, campaignID string, entityIDs []string) ([]string, error)
+
+	// Per-user hidden categories.
+	listHiddenCategoriesFn func(ctx context.Context, userID string) ([]HiddenCategory, error)
+	setCategoryHiddenFn    func(ctx context.Context, userID, calendarID, slug string, hidden bool) error
 }
 
 func (m *mockCalendarRepo) Create(ctx context.Context, cal *Calendar) error {
@@ -166,6 +200,13 @@ func (m *mockCalendarRepo) GetWeekdays(ctx context.Context, calendarID string) (
 	return nil, nil
 }
 
+func (m *mockCalendarRepo) SetStructureWithFixes(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput, fixes []EventDateFix) error {
+	if m.setStructureFixesFn != nil {
+		return m.setStructureFixesFn(ctx, calendarID, months, weekdays, fixes)
+	}
+	return nil
+}
+
 func (m *mockCalendarRepo) SetMoons(ctx context.Context, calendarID string, moons []MoonInput) error {
 	if m.setMoonsFn != nil {
 		return m.setMoonsFn(ctx, calendarID, moons)
@@ -257,6 +298,20 @@ func (m *mockCalendarRepo) GetEventCategories(ctx context.Context, calendarID st
 	return nil, nil
 }
 
+func (m *mockCalendarRepo) UpsertEventCategory(ctx context.Context, calendarID string, cat EventCategoryInput) error {
+	if m.upsertEventCategoryFn != nil {
+		return m.upsertEventCategoryFn(ctx, calendarID, cat)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) DeleteEventCategory(ctx context.Context, calendarID, slug string) (bool, error) {
+	if m.deleteEventCategoryFn != nil {
+		return m.deleteEventCategoryFn(ctx, calendarID, slug)
+	}
+	return true, nil
+}
+
 func (m *mockCalendarRepo) CreateEvent(ctx context.Context, evt *Event) error {
 	if m.createEventFn != nil {
 		return m.createEventFn(ctx, evt)
@@ -306,13 +361,29 @@ func (m *mockCalendarRepo) ListEventsForDateRange(ctx context.Context, calendarI
 	return nil, nil
 }
 
-func (m *mockCalendarRepo) ListAllEvents(_ context.Context, _ string) ([]Event, error) {
+func (m *mockCalendarRepo) ListAllEvents(ctx context.Context, calendarID string) ([]Event, error) {
 	// C-CALENDAR-ENDPOINTS: unfiltered list used by the public
-	// Foundry API. Existing service tests don't exercise this
-	// path, so the default zero-value return is fine.
+	// Foundry API and the import dedupe.
+	if m.listAllEventsFn != nil {
+		return m.listAllEventsFn(ctx, calendarID)
+	}
 	return nil, nil
 }
 
+func (m *mockCalendarRepo) ListEntityAnniversaries(ctx context.Context, calendarID string, year, month, day int, role int, userID string) ([]Event, error) {
+	if m.listAnniversariesFn != nil {
+		return m.listAnniversariesFn(ctx, calendarID, year, month, day, role, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) ReplaceEntityDateEvents(ctx context.Context, calendarID, entityID string, events []Event) error {
+	if m.replaceDateEventsFn != nil {
+		return m.replaceDateEventsFn(ctx, calendarID, entityID, events)
+	}
+	return nil
+}
+
 func (m *mockCalendarRepo) ListEventsForEntity(ctx context.Context, entityID string, role int) ([]Event, error) {
 	if m.listEventsForEntityFn != nil {
 		return m.listEventsForEntityFn(ctx, entityID, role)
@@ -434,6 +505,119 @@ func (m *mockCalendarRepo) SetFestivals(ctx context.Context, calendarID string,
 	return nil
 }
 
+func (m *mockCalendarRepo) GetTimePresets(ctx context.Context, calendarID string) ([]TimePreset, error) {
+	if m.getTimePresetsFn != nil {
+		return m.getTimePresetsFn(ctx, calendarID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SetTimePresets(ctx context.Context, calendarID string, presets []TimePresetInput) error {
+	if m.setTimePresetsFn != nil {
+		return m.setTimePresetsFn(ctx, calendarID, presets)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) GetYearNames(ctx context.Context, calendarID string) ([]YearName, error) {
+	if m.getYearNamesFn != nil {
+		return m.getYearNamesFn(ctx, calendarID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SetYearNames(ctx context.Context, calendarID string, names []YearNameInput) error {
+	if m.setYearNamesFn != nil {
+		return m.setYearNamesFn(ctx, calendarID, names)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) RecordDateAdvancement(ctx context.Context, a *DateAdvancement) error {
+	if m.recordDateAdvancementFn != nil {
+		return m.recordDateAdvancementFn(ctx, a)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) ListDateHistory(ctx context.Context, calendarID string, limit int) ([]DateAdvancement, error) {
+	if m.listDateHistoryFn != nil {
+		return m.listDateHistoryFn(ctx, calendarID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) LastDateAdvancement(ctx context.Context, calendarID string) (*DateAdvancement, error) {
+	if m.lastDateAdvancementFn != nil {
+		return m.lastDateAdvancementFn(ctx, calendarID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) MarkDateAdvancementUndone(ctx context.Context, id int) error {
+	if m.markDateAdvancementUndoneFn != nil {
+		return m.markDateAdvancementUndoneFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) GetZoneWeather(ctx context.Context, calendarID string) ([]Weather, error) {
+	if m.getZoneWeatherFn != nil {
+		return m.getZoneWeatherFn(ctx, calendarID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SetZoneWeather(ctx context.Context, calendarID, zoneID string, input WeatherInput) error {
+	if m.setZoneWeatherFn != nil {
+		return m.setZoneWeatherFn(ctx, calendarID, zoneID, input)
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) ListLocationZones(ctx context.Context, calendarID string) ([]LocationZone, error) {
+	if m.listLocationZonesFn != nil {
+		return m.listLocationZonesFn(ctx, calendarID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SetLocationZones(ctx context.Context, calendarID string, assignments []LocationZone) error {
+	if m.setLocationZonesFn != nil {
+		return m.setLocationZonesFn(ctx, calendarID, assignments)
+	}
+	return nil
+}
+
+// LocationAncestry defaults to a root entity (no parents).
+func (m *mockCalendarRepo) LocationAncestry(ctx context.Context, entityID string) ([]string, error) {
+	if m.locationAncestryFn != nil {
+		return m.locationAncestryFn(ctx, entityID)
+	}
+	return []string{entityID}, nil
+}
+
+func (m *mockCalendarRepo) EntitiesOutsideCampaign(ctx context.Context, campaignID string, entityIDs []string) ([]string, error) {
+	if m.entitiesOutsideCampaignFn != nil {
+		return m.entitiesOutsideCampaignFn(ctx, campaignID, entityIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) ListHiddenCategories(ctx context.Context, userID string) ([]HiddenCategory, error) {
+	if m.listHiddenCategoriesFn != nil {
+		return m.listHiddenCategoriesFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockCalendarRepo) SetCategoryHidden(ctx context.Context, userID, calendarID, slug string, hidden bool) error {
+	if m.setCategoryHiddenFn != nil {
+		return m.setCategoryHiddenFn(ctx, userID, calendarID, slug, hidden)
+	}
+	return nil
+}
+
 // --- Test Helpers ---
 
 func newTestCalendarService(repo *mockCalendarRepo) CalendarService {
@@ -1194,7 +1378,7 @@ func TestAdvanceDate_NotFound(t *testing.T) {
 	repo := &mockCalendarRepo{}
 	svc := newTestCalendarService(repo)
 
-	err := svc.AdvanceDate(context.Background(), "nonexistent", 5)
+	err := svc.AdvanceDate(context.Background(), "nonexistent", "", 5)
 	assertAppError(t, err, 404)
 }
 
@@ -1209,7 +1393,7 @@ func TestAdvanceDate_NoMonths(t *testing.T) {
 	}
 	svc := newTestCalendarService(repo)
 
-	err := svc.AdvanceDate(context.Background(), "cal-1", 5)
+	err := svc.AdvanceDate(context.Background(), "cal-1", "", 5)
 	assertAppError(t, err, 422)
 }
 
@@ -1219,7 +1403,7 @@ func TestAdvanceTime_NotFound(t *testing.T) {
 	repo := &mockCalendarRepo{}
 	svc := newTestCalendarService(repo)
 
-	err := svc.AdvanceTime(context.Background(), "nonexistent", 2, 30)
+	err := svc.AdvanceTime(context.Background(), "nonexistent", "", 2, 30)
 	assertAppError(t, err, 404)
 }
 
@@ -380,8 +380,8 @@
                     break;
                 case 'j':
                 case 'ArrowLeft':
-                    // 'Previous year' covers the Timeline (Ledger) view, which
-                    // steps by year (C-CAL-TIMELINE-V2-W1).
+                    // 'Previous year' covers the Timeline (Ledger) and Year
+                    // views, which step by year (C-CAL-TIMELINE-V2-W1).
                     clickByLabel('Previous month', 'Previous week', 'Previous day', 'Previous year');
                     if (e.key === 'j') e.preventDefault();
                     break;
@@ -399,6 +399,9 @@
                 case 'd':
                     window.location.href = path + '/day';
                     break;
+                case 'y':
+                    window.location.href = path + '/year';
+                    break;
                 case 'l':
                     // 'l' → Timeline (Ledger) view. Route segment is 'ledger'
                     // (the design name) to avoid the timeline-plugin slug
@@ -546,10 +549,11 @@
             if (typeof popover.focus === 'function') popover.focus();
         }
 
-        // WorldState peek (2b-2): fetch the clicked day's seed (#401 GET,
-        // dm_only filtered by role) and show its moon phase(s) + weather +
-        // celestial events read-only above the events list. Reuses the same
-        // BuildWorldStateSeed the 2a band renders — no new endpoint.
+        // Day peek: fetch the clicked day from GET /calendar/day — one
+        // response with the sky (season, era, weather, moons, celestial
+        // events; dm_only filtered by role), festivals, entity anniversaries
+        // and the day's events with recurrences expanded. Shown read-only
+        // above the events list, with a link to the bookmarkable day page.
         function wsEsc(s) {
             return String(s == null ? '' : s).replace(/[&<>"']/g, function (c) {
                 return { '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' }[c];
@@ -567,41 +571,68 @@
             var year = root.dataset.calV2Year;
             var month = root.dataset.calV2Month;
             if (!cid || !year || !month) return;
-            var url = '/campaigns/' + cid + '/calendar/world-state?year=' + year +
+            var url = '/campaigns/' + cid + '/calendar/day?year=' + year +
                 '&month=' + month + '&day=' + day + (calId ? '&calendarId=' + calId : '');
             window.Chronicle.apiFetch(url, { method: 'GET' })
                 .then(function (r) { return r.ok ? r.json() : null; })
-                .then(function (ws) { if (ws) fillWorldStatePeek(box, ws); })
+                .then(function (dd) {
+                    // Ignore a late response for a day the popover has left.
+                    if (!dd || popover.dataset.dvDay !== String(day)) return;
+                    fillWorldStatePeek(box, dd);
+                    if (list && dd.events) renderPopoverRows(list, dd.events);
+                })
                 .catch(function () {});
         }
 
-        function fillWorldStatePeek(box, ws) {
+        function fillWorldStatePeek(box, dd) {
             var rows = [];
-            if (ws.weather && ws.weather.type) {
-                rows.push('<div><span class="text-fg-secondary">Weather:</span> ' + wsEsc(wsTitleCase(ws.weather.type)) + '</div>');
+            if (dd.season && dd.season.name) {
+                rows.push('<div><span class="text-fg-secondary">Season:</span> ' + wsEsc(dd.season.name) + '</div>');
+            }
+            if (dd.era && dd.era.name) {
+                rows.push('<div><span class="text-fg-secondary">Era:</span> ' + wsEsc(dd.era.name) + '</div>');
+            }
+            if (dd.weather && dd.weather.type) {
+                rows.push('<div><span class="text-fg-secondary">Weather:</span> ' + wsEsc(wsTitleCase(dd.weather.type)) + '</div>');
             }
-            (ws.moons || []).forEach(function (m) {
+            (dd.moons || []).forEach(function (m) {
                 if (m && m.name) {
-                    rows.push('<div><span class="text-fg-secondary">' + wsEsc(m.name) + ':</span> ' + wsEsc(m.namedPhase || '') + '</div>');
+                    rows.push('<div><span class="text-fg-secondary">' + wsEsc(m.name) + ':</span> ' + wsEsc(m.named_phase || '') + '</div>');
                 }
             });
-            (ws.events || []).forEach(function (ev) {
+            (dd.celestial || []).forEach(function (ev) {
                 if (ev && ev.name) rows.push('<div>✦ ' + wsEsc(ev.name) + '</div>');
             });
+            (dd.festivals || []).forEach(function (f) {
+                if (f && f.name) rows.push('<div><i class="fa-solid fa-star text-[10px] mr-1" aria-hidden="true"></i>' + wsEsc(f.name) + '</div>');
+            });
+            (dd.anniversaries || []).forEach(function (a) {
+                var who = a.entity_name ? ' · ' + wsEsc(a.entity_name) : '';
+                rows.push('<div><i class="fa-solid fa-cake-candles text-[10px] mr-1" aria-hidden="true"></i>' +
+                    wsEsc(a.name) + who + ' <span class="text-fg-muted">(' + a.years_ago + 'y ago)</span></div>');
+            });
+            if (dd.url) {
+                rows.push('<div class="pt-1"><a class="text-accent hover:underline" href="' + wsEsc(dd.url) + '">Open day page</a></div>');
+            }
             if (!rows.length) return;
             box.innerHTML = rows.join('');
             box.classList.remove('hidden');
         }
 
+        // First paint from the page's event JSON; the /calendar/day response
+        // replaces it with the recurrence-expanded list when it lands.
         function renderPopoverList(listEl, day) {
-            listEl.innerHTML = '';
             var events = [];
             try {
                 events = JSON.parse(root.dataset.calV2Events || '[]');
             } catch (e) { return; }
-            var filtered = events.filter(function (ev) {
+            renderPopoverRows(listEl, events.filter(function (ev) {
                 return ev.day === day && !isMultiDay(ev);
-            });
+            }));
+        }
+
+        function renderPopoverRows(listEl, filtered) {
+            listEl.innerHTML = '';
             if (filtered.length === 0) {
                 var empty = document.createElement('div');
                 empty.className = 'text-xs text-fg-secondary italic';
@@ -573,6 +573,7 @@
             if (v === 'weekly') txt = 'Repeats weekly.';
             else if (v === 'biweekly') txt = 'Repeats every 2 weeks.';
             else if (v === 'monthly') txt = 'Repeats monthly, on the same day.';
+            else if (v === 'yearly') txt = 'Repeats every year, on the same date.';
             else if (v === 'custom') {
                 var n = parseInt((drawer.querySelector('[data-field="recurrence_interval"]') || {}).value, 10);
                 txt = 'Repeats every ' + (isNaN(n) ? 'N' : n) + ' week' + (n === 1 ? '' : 's') + '.';
@@ -8,7 +8,9 @@
 // which (a) re-renders the ambient band via window.__calSetWorldState and
 // (b) re-syncs the console's own state (active weather tile, active
 // world-event chips, the header clock). Pause is the lone client-only
-// control (atmosphere freeze is ephemeral — the GM's own view).
+// control (atmosphere freeze is ephemeral — the GM's own view). The
+// time-preset buttons are the one other exception: they POST the audited
+// advance endpoints, then GET the seed.
 //
 // The card is TRANSLUCENT GLASS over the living sky; on every world
 // mutation it drops to near-transparent (data-gm-transition) so the DM
@@ -226,6 +228,50 @@
             });
         });
 
+        // --- Quick-advance presets (owner-configured) ---
+        // Unlike the verbs above these post to the advance endpoints, which
+        // audit the move (before/after + preset name); the seed is then
+        // re-read from the world-state GET to re-render the sky. A preset is
+        // days XOR hours/minutes (server-validated), so one click = one call.
+        var advanceBase = '/campaigns/' + campaignID + '/calendars/' + calendarID;
+        panel.querySelectorAll('[data-gm-preset]').forEach(function (btn) {
+            btn.addEventListener('click', function () {
+                var days = parseInt(btn.dataset.gmDays || '0', 10) || 0;
+                var body = days > 0
+                    ? { days: days, preset: btn.dataset.gmPreset }
+                    : {
+                        hours: parseInt(btn.dataset.gmHours || '0', 10) || 0,
+                        minutes: parseInt(btn.dataset.gmMinutes || '0', 10) || 0,
+                        preset: btn.dataset.gmPreset,
+                    };
+                btn.disabled = true;
+                transitionFade();
+                window.Chronicle.apiFetch(advanceBase + (days > 0 ? '/advance' : '/advance-time'), {
+                    method: 'POST', body: body, headers: { 'X-CSRF-Token': csrfToken },
+                }).then(function (resp) {
+                    if (!resp.ok) {
+                        return resp.json().catch(function () { return {}; }).then(function (b) {
+                            throw new Error((b && b.message) || 'Advance failed');
+                        });
+                    }
+                    return window.Chronicle.apiFetch(url, { method: 'GET' });
+                }).then(function (resp) {
+                    return resp.ok ? resp.json() : null;
+                }).then(function (seed) {
+                    if (seed && window.__calSetWorldState) {
+                        window.__calSetWorldState(seed);
+                        syncFromSeed(seed);
+                    } else {
+                        window.location.reload();
+                    }
+                }).catch(function (e) {
+                    window.Chronicle.notify((e && e.message) || 'Advance failed', 'error');
+                }).then(function () {
+                    btn.disabled = false;
+                });
+            });
+        });
+
         // --- Absolute set time / set date ---
         var setTimeBtn = panel.querySelector('[data-gm-set-time]');
         if (setTimeBtn) {
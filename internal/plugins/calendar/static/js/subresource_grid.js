@@ -153,6 +153,11 @@
                             jsonError = field + ': invalid JSON';
                         }
                     }
+                } else if (el.dataset.fieldType === 'int') {
+                    // Integer-valued selects (e.g. a month's starting
+                    // weekday); blank stays as no-key (= unset).
+                    var i = parseInt(el.value, 10);
+                    if (!isNaN(i)) item[field] = i;
                 } else if (el.type === 'checkbox') {
                     item[field] = el.checked;
                 } else if (el.type === 'number') {
@@ -236,13 +241,118 @@
             commitPayload(next, true);
         }
 
+        // --- Structure impact (months / weekdays) ---
+        // Reshaping months or weekdays can strand events (day 31 of a
+        // now-30-day month). Ask the server what would break first; if
+        // anything would, the owner picks a remedy or cancels.
+        function checkImpact(next) {
+            if (kind !== 'months' && kind !== 'weekdays') return Promise.resolve('');
+            return window.Chronicle.apiFetch(putURL + '/impact', {
+                method: 'POST',
+                body: next,
+                headers: { 'X-CSRF-Token': csrfToken },
+            }).then(function (resp) {
+                // A failed preview falls through to the PUT, which
+                // reports validation errors itself.
+                if (!resp.ok) return '';
+                return resp.json().then(function (impact) {
+                    var events = (impact && impact.events) || [];
+                    var fields = (impact && impact.date_fields) || [];
+                    if (!events.length && !fields.length) return '';
+                    return chooseRemedy(events, fields);
+                });
+            }).catch(function () { return ''; });
+        }
+
+        // chooseRemedy lists what the change strands and resolves to a
+        // remedy ('clamp', 'shift', 'delete', '' to leave events as they
+        // are) or null when the owner cancels.
+        function chooseRemedy(events, fields) {
+            return new Promise(function (resolve) {
+                var overlay = document.createElement('div');
+                overlay.className = 'fixed inset-0 z-50 flex items-center justify-center bg-black/50 p-4';
+                overlay.setAttribute('role', 'dialog');
+                overlay.setAttribute('aria-modal', 'true');
+                overlay.setAttribute('aria-labelledby', 'structure-impact-title');
+
+                var box = document.createElement('div');
+                box.className = 'card p-4 max-w-lg w-full max-h-[80vh] overflow-y-auto space-y-3';
+                var title = document.createElement('h2');
+                title.id = 'structure-impact-title';
+                title.className = 'text-base font-semibold text-fg';
+                title.textContent = 'This change strands some dates';
+                box.appendChild(title);
+
+                function section(label, rows) {
+                    if (!rows.length) return;
+                    var h = document.createElement('p');
+                    h.className = 'text-sm font-medium text-fg';
+                    h.textContent = label;
+                    box.appendChild(h);
+                    var ul = document.createElement('ul');
+                    ul.className = 'text-xs text-fg-secondary space-y-1 list-disc pl-5';
+                    rows.forEach(function (text) {
+                        var li = document.createElement('li');
+                        li.textContent = text;
+                        ul.appendChild(li);
+                    });
+                    box.appendChild(ul);
+                }
+                section(events.length + ' event(s)', events.map(function (e) {
+                    return e.name + ' (' + e.date + '): ' + e.reason +
+                        ' · clamp → ' + e.clamped + ' · shift → ' + e.shifted;
+                }));
+                section(fields.length + ' entity date field(s), flagged for review after saving', fields.map(function (f) {
+                    return f.entity_name + ' · ' + f.field_label + ' (' + f.value + '): ' + f.reason;
+                }));
+
+                var actions = document.createElement('div');
+                actions.className = 'flex flex-wrap justify-end gap-2 pt-2';
+                function done(remedy) {
+                    overlay.remove();
+                    resolve(remedy);
+                }
+                [
+                    ['Cancel', null, 'btn-secondary'],
+                    ['Leave events', '', 'btn-secondary'],
+                    ['Delete events', 'delete', 'btn-secondary'],
+                    ['Shift forward', 'shift', 'btn-secondary'],
+                    ['Clamp', 'clamp', 'btn-primary'],
+                ].forEach(function (a) {
+                    if (a[1] !== null && a[1] !== '' && !events.length) return;
+                    var b = document.createElement('button');
+                    b.type = 'button';
+                    b.className = a[2] + ' text-sm';
+                    b.textContent = a[0];
+                    b.addEventListener('click', function () { done(a[1]); });
+                    actions.appendChild(b);
+                });
+                box.appendChild(actions);
+                overlay.appendChild(box);
+                overlay.addEventListener('keydown', function (ev) {
+                    if (ev.key === 'Escape') done(null);
+                });
+                document.body.appendChild(overlay);
+                var primary = actions.querySelector('.btn-primary') || actions.lastChild;
+                if (primary) primary.focus();
+            });
+        }
+
         // --- Bulk-set PUT + UI refresh ---
         function commitPayload(next, closeAfter) {
+            checkImpact(next).then(function (remedy) {
+                if (remedy === null) return;
+                putPayload(next, closeAfter, remedy);
+            });
+        }
+
+        function putPayload(next, closeAfter, remedy) {
             var prev = payload.slice();
             payload = next;
             renderGrid();
             // Optimistic dnd / save / delete; revert on PUT failure.
-            window.Chronicle.apiFetch(putURL, {
+            var url = remedy ? putURL + '?remedy=' + encodeURIComponent(remedy) : putURL;
+            window.Chronicle.apiFetch(url, {
                 method: 'PUT',
                 body: next,
                 headers: { 'X-CSRF-Token': csrfToken },
@@ -348,6 +458,13 @@
                 case 'months': {
                     var parts = [(item.days === 1 ? '1 day' : (item.days || 0) + ' days')];
                     if (item.is_intercalary) parts.push('intercalary');
+                    if (item.skips_weekday) parts.push('outside the week');
+                    if (item.starting_weekday !== undefined && item.starting_weekday !== null) {
+                        // Name the weekday from the drawer's own options so
+                        // the card matches the server-rendered subtitle.
+                        var opt = drawer.querySelector('[data-field="starting_weekday"] option[value="' + item.starting_weekday + '"]');
+                        if (opt) parts.push('starts ' + opt.textContent.trim());
+                    }
                     if (item.leap_year_days) parts.push('+' + item.leap_year_days + ' leap');
                     return parts.join(' · ');
                 }
@@ -355,9 +472,15 @@
                     return item.is_rest_day ? 'rest day' : '';
                 case 'moons':
                     return 'cycle ' + (item.cycle_days || 0) + 'd';
-                case 'seasons':
-                    return 'month ' + item.start_month + ' · day ' + item.start_day +
+                case 'seasons': {
+                    var range = 'month ' + item.start_month + ' · day ' + item.start_day +
                         ' → month ' + item.end_month + ' · day ' + item.end_day;
+                    if (item.zone_id) {
+                        var zopt = drawer.querySelector('[data-field="zone_id"] option[value="' + item.zone_id + '"]');
+                        range += ' · ' + (zopt ? zopt.textContent.trim() : item.zone_id);
+                    }
+                    return range;
+                }
                 case 'eras': {
                     var s = 'from year ' + (item.start_year || 0);
                     if (item.end_year !== null && item.end_year !== undefined && item.end_year !== '') {
@@ -392,6 +515,15 @@
                     }
                     return z;
                 }
+                case 'time-presets': {
+                    // Mirrors TimePreset.AdvanceLabel (model.go).
+                    if (item.days) return '+' + item.days + 'd';
+                    if (item.hours && item.minutes) return '+' + item.hours + 'h ' + item.minutes + 'm';
+                    if (item.hours) return '+' + item.hours + 'h';
+                    return '+' + (item.minutes || 0) + 'm';
+                }
+                case 'year-names':
+                    return 'year ' + (item.year || 0);
             }
             return '';
         }
@@ -502,6 +634,8 @@
             case 'cycles': return 'cycle';
             case 'zones': return 'zone';
             case 'weather': return 'weather state';
+            case 'time-presets': return 'time preset';
+            case 'year-names': return 'year name';
         }
         return 'item';
     }
@@ -0,0 +1,349 @@
+package calendar
+
+// structure_impact.go — what a months or weekdays edit would strand.
+// Shortening or removing months can leave events on dates the calendar no
+// longer has (day 31 of a now-30-day month), and dropping weekdays can
+// leave weekly events on a weekday that's gone. Before saving, the editor
+// asks for an impact report listing those events (and, on the campaign's
+// main calendar, the entity date fields that would stop fitting), then
+// saves with a remedy: clamp to the nearest valid date, shift forward by
+// the overflow, or delete. The structure change and the event fixes are
+// written in one transaction. Entity date fields are not rewritten; the
+// DateStructureListener flags them for review after the save.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Remedies for events a structure change strands.
+const (
+	RemedyClamp  = "clamp"  // move to the last valid month/day/weekday
+	RemedyShift  = "shift"  // keep the day's position in the year, rolling forward
+	RemedyDelete = "delete" // remove the event
+)
+
+// validRemedy reports whether r is a known remedy ("" means leave events
+// as they are).
+func validRemedy(r string) bool {
+	switch r {
+	case "", RemedyClamp, RemedyShift, RemedyDelete:
+		return true
+	}
+	return false
+}
+
+// ImpactedEvent is an event a structure change would strand, with where
+// each remedy would move it.
+type ImpactedEvent struct {
+	EventID string `json:"event_id"`
+	Name    string `json:"name"`
+	Date    string `json:"date"` // the stranded date, or weekday number
+	Reason  string `json:"reason"`
+	Clamped string `json:"clamped"`
+	Shifted string `json:"shifted"`
+}
+
+// ImpactedDateField is an entity date field a structure change would
+// strand. Reported only; saving flags it for review.
+type ImpactedDateField struct {
+	EntityID   string `json:"entity_id"`
+	EntityName string `json:"entity_name"`
+	FieldLabel string `json:"field_label"`
+	Value      string `json:"value"`
+	Reason     string `json:"reason"`
+}
+
+// StructureImpact is the pre-save report for a months or weekdays edit.
+type StructureImpact struct {
+	Events     []ImpactedEvent     `json:"events"`
+	DateFields []ImpactedDateField `json:"date_fields"`
+}
+
+// EventDateFix is one stranded event's remediation: Delete, or Event
+// carrying the corrected dates.
+type EventDateFix struct {
+	EventID string
+	Delete  bool
+	Event   Event
+}
+
+// DateFieldScanner finds entity date fields a proposed calendar shape
+// would strand. Implemented in internal/app over the entities plugin.
+type DateFieldScanner interface {
+	StrandedDateFields(ctx context.Context, campaignID string, check func(year, month, day int) string) ([]ImpactedDateField, error)
+}
+
+// SetDateFieldScanner wires entity date fields into impact reports.
+func (s *calendarService) SetDateFieldScanner(d DateFieldScanner) {
+	s.dateScanner = d
+}
+
+// fmtDate formats a calendar date the way impact reports show it.
+func fmtDate(y, m, d int) string {
+	return fmt.Sprintf("%d-%d-%d", y, m, d)
+}
+
+// eventDates returns pointers to each date an event carries (start, end,
+// recurrence end), so checks and fixes treat them alike.
+func eventDates(e *Event) [][3]*int {
+	dates := [][3]*int{{&e.Year, &e.Month, &e.Day}}
+	if e.EndYear != nil && e.EndMonth != nil && e.EndDay != nil {
+		dates = append(dates, [3]*int{e.EndYear, e.EndMonth, e.EndDay})
+	}
+	if e.RecurrenceEndYear != nil && e.RecurrenceEndMonth != nil && e.RecurrenceEndDay != nil {
+		dates = append(dates, [3]*int{e.RecurrenceEndYear, e.RecurrenceEndMonth, e.RecurrenceEndDay})
+	}
+	return dates
+}
+
+// clampDate moves a date onto the calendar: past the last month goes to
+// the last month, past a month's end goes to its last day.
+func clampDate(cal *Calendar, y, m, d int) (int, int, int) {
+	if m > len(cal.Months) {
+		m = len(cal.Months)
+	}
+	if m < 1 {
+		m = 1
+	}
+	if n := cal.MonthDays(m-1, y); d > n {
+		d = n
+	}
+	if d < 1 {
+		d = 1
+	}
+	return y, m, d
+}
+
+// shiftDate keeps a date's day-of-year from the old calendar and finds
+// that day on the new one, rolling into the next year when the new year
+// is shorter. Dates the old calendar didn't have either are clamped on it
+// first.
+func shiftDate(old, next *Calendar, y, m, d int) (int, int, int) {
+	y, m, d = clampDate(old, y, m, d)
+	ordinal := d
+	for i := 0; i < m-1; i++ {
+		ordinal += old.MonthDays(i, y)
+	}
+	for {
+		for i := range next.Months {
+			n := next.MonthDays(i, y)
+			if ordinal <= n {
+				return y, i + 1, ordinal
+			}
+			ordinal -= n
+		}
+		y++
+	}
+}
+
+// strandedEvent reports why e doesn't fit next, with the clamp and shift
+// results for its first stranded date. ok is false when e still fits.
+// Generated anniversary events follow their entity's date field and are
+// left to it.
+func strandedEvent(old, next *Calendar, e Event) (ImpactedEvent, bool) {
+	if e.SourceField != nil {
+		return ImpactedEvent{}, false
+	}
+	if len(next.Months) > 0 {
+		for _, dp := range eventDates(&e) {
+			y, m, d := *dp[0], *dp[1], *dp[2]
+			if reason := next.DateProblem(y, m, d); reason != "" {
+				cy, cm, cd := clampDate(next, y, m, d)
+				sy, sm, sd := shiftDate(old, next, y, m, d)
+				return ImpactedEvent{
+					EventID: e.ID, Name: e.Name, Date: fmtDate(y, m, d), Reason: reason,
+					Clamped: fmtDate(cy, cm, cd), Shifted: fmtDate(sy, sm, sd),
+				}, true
+			}
+		}
+	}
+	if n := len(next.Weekdays); n > 0 && e.IsRecurring && e.RecurrenceDayOfWeek != nil && *e.RecurrenceDayOfWeek >= n {
+		w := *e.RecurrenceDayOfWeek
+		return ImpactedEvent{
+			EventID: e.ID, Name: e.Name, Date: fmt.Sprintf("weekday %d", w+1),
+			Reason:  fmt.Sprintf("the week has %d days", n),
+			Clamped: next.Weekdays[n-1].Name, Shifted: next.Weekdays[w%n].Name,
+		}, true
+	}
+	return ImpactedEvent{}, false
+}
+
+// fixEvent applies remedy to a stranded event's dates and weekday.
+func fixEvent(old, next *Calendar, e Event, remedy string) EventDateFix {
+	if remedy == RemedyDelete {
+		return EventDateFix{EventID: e.ID, Delete: true}
+	}
+	if len(next.Months) > 0 {
+		for _, dp := range eventDates(&e) {
+			y, m, d := *dp[0], *dp[1], *dp[2]
+			if next.DateProblem(y, m, d) == "" {
+				continue
+			}
+			if remedy == RemedyShift {
+				y, m, d = shiftDate(old, next, y, m, d)
+			} else {
+				y, m, d = clampDate(next, y, m, d)
+			}
+			*dp[0], *dp[1], *dp[2] = y, m, d
+		}
+	}
+	if n := len(next.Weekdays); n > 0 && e.RecurrenceDayOfWeek != nil && *e.RecurrenceDayOfWeek >= n {
+		w := n - 1
+		if remedy == RemedyShift {
+			w = *e.RecurrenceDayOfWeek % n
+		}
+		e.RecurrenceDayOfWeek = &w
+	}
+	return EventDateFix{EventID: e.ID, Event: e}
+}
+
+// proposedCalendar copies cal with months or weekdays replaced by the
+// edit's input (nil leaves that part as it is).
+func proposedCalendar(cal *Calendar, months []MonthInput, weekdays []WeekdayInput) *Calendar {
+	next := *cal
+	if months != nil {
+		next.Months = make([]Month, len(months))
+		for i, m := range months {
+			next.Months[i] = Month{
+				CalendarID: cal.ID, Name: m.Name, Days: m.Days, SortOrder: m.SortOrder,
+				IsIntercalary: m.IsIntercalary, LeapYearDays: m.LeapYearDays,
+				StartingWeekday: m.StartingWeekday, SkipsWeekday: m.SkipsWeekday,
+			}
+		}
+	}
+	if weekdays != nil {
+		next.Weekdays = make([]Weekday, len(weekdays))
+		for i, w := range weekdays {
+			next.Weekdays[i] = Weekday{CalendarID: cal.ID, Name: w.Name, SortOrder: w.SortOrder, IsRestDay: w.IsRestDay}
+		}
+	}
+	return &next
+}
+
+// structureImpact builds the report for replacing cal's months or
+// weekdays, returning the loaded calendar, the proposed one and the
+// stranded events alongside it.
+func (s *calendarService) structureImpact(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput) (*StructureImpact, *Calendar, *Calendar, []Event, error) {
+	cal, err := s.GetCalendarByID(ctx, calendarID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if cal == nil {
+		return nil, nil, nil, nil, apperror.NewNotFound("calendar not found")
+	}
+	next := proposedCalendar(cal, months, weekdays)
+
+	events, err := s.repo.ListAllEvents(ctx, calendarID)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("listing events: %w", err)
+	}
+	impact := &StructureImpact{Events: []ImpactedEvent{}, DateFields: []ImpactedDateField{}}
+	var stranded []Event
+	for _, e := range events {
+		if ie, ok := strandedEvent(cal, next, e); ok {
+			impact.Events = append(impact.Events, ie)
+			stranded = append(stranded, e)
+		}
+	}
+
+	// Entity date fields are checked against the campaign's main calendar.
+	if months != nil && s.dateScanner != nil && len(next.Months) > 0 {
+		if main, err := s.repo.GetByCampaignID(ctx, cal.CampaignID); err == nil && main != nil && main.ID == cal.ID {
+			fields, err := s.dateScanner.StrandedDateFields(ctx, cal.CampaignID, next.DateProblem)
+			if err != nil {
+				slog.Warn("scanning date fields for calendar impact failed",
+					slog.String("calendar_id", calendarID),
+					slog.Any("error", err))
+			} else if fields != nil {
+				impact.DateFields = fields
+			}
+		}
+	}
+	return impact, cal, next, stranded, nil
+}
+
+// PreviewMonthsImpact reports what replacing the months would strand,
+// without saving anything.
+func (s *calendarService) PreviewMonthsImpact(ctx context.Context, calendarID string, months []MonthInput) (*StructureImpact, error) {
+	if err := s.validateMonths(ctx, calendarID, months); err != nil {
+		return nil, err
+	}
+	impact, _, _, _, err := s.structureImpact(ctx, calendarID, months, nil)
+	return impact, err
+}
+
+// PreviewWeekdaysImpact reports what replacing the weekdays would strand.
+func (s *calendarService) PreviewWeekdaysImpact(ctx context.Context, calendarID string, weekdays []WeekdayInput) (*StructureImpact, error) {
+	if err := validateWeekdays(weekdays); err != nil {
+		return nil, err
+	}
+	impact, _, _, _, err := s.structureImpact(ctx, calendarID, nil, weekdays)
+	return impact, err
+}
+
+// remedyFixes works out the event fixes for a remedied structure save.
+func (s *calendarService) remedyFixes(ctx context.Context, calendarID string, months []MonthInput, weekdays []WeekdayInput, remedy string) ([]EventDateFix, error) {
+	if !validRemedy(remedy) {
+		return nil, apperror.NewValidation("remedy must be clamp, shift or delete")
+	}
+	if remedy == "" {
+		return nil, nil
+	}
+	_, cal, next, stranded, err := s.structureImpact(ctx, calendarID, months, weekdays)
+	if err != nil {
+		return nil, err
+	}
+	fixes := make([]EventDateFix, 0, len(stranded))
+	for _, e := range stranded {
+		fixes = append(fixes, fixEvent(cal, next, e, remedy))
+	}
+	return fixes, nil
+}
+
+// PreviewMonthsImpactAPI reports what saving the posted months would
+// strand, without saving.
+// POST /campaigns/:id/calendars/:calId/months/impact
+func (h *Handler) PreviewMonthsImpactAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	var months []MonthInput
+	if err := c.Bind(&months); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	impact, err := h.svc.PreviewMonthsImpact(c.Request().Context(), cal.ID, months)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, impact)
+}
+
+// PreviewWeekdaysImpactAPI reports which weekly events saving the posted
+// weekdays would strand, without saving.
+// POST /campaigns/:id/calendars/:calId/weekdays/impact
+func (h *Handler) PreviewWeekdaysImpactAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	cal, err := h.requireCalendarInCampaign(c, c.Param("calId"), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	var weekdays []WeekdayInput
+	if err := c.Bind(&weekdays); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	impact, err := h.svc.PreviewWeekdaysImpact(c.Request().Context(), cal.ID, weekdays)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, impact)
+}
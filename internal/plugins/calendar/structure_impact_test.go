@@ -0,0 +1,135 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+)
+
+// impactRepo is a mock repo holding a 2-month, 3-weekday calendar with
+// the given events.
+func impactRepo(events []Event) *mockCalendarRepo {
+	return &mockCalendarRepo{
+		getByIDFn: func(_ context.Context, id string) (*Calendar, error) {
+			return &Calendar{ID: id, CampaignID: "camp-1", Name: "Test"}, nil
+		},
+		getMonthsFn: func(context.Context, string) ([]Month, error) {
+			return []Month{{Name: "Frost", Days: 31, SortOrder: 1}, {Name: "Thaw", Days: 30, SortOrder: 2}}, nil
+		},
+		getWeekdaysFn: func(context.Context, string) ([]Weekday, error) {
+			return []Weekday{{Name: "One"}, {Name: "Two"}, {Name: "Three"}}, nil
+		},
+		listAllEventsFn: func(context.Context, string) ([]Event, error) {
+			return events, nil
+		},
+	}
+}
+
+func TestShiftDate_RollsOverflowForward(t *testing.T) {
+	old := &Calendar{Months: []Month{{Days: 31}, {Days: 30}}}
+	next := &Calendar{Months: []Month{{Days: 30}, {Days: 30}}}
+
+	// Day 31 of month 1 is day 31 of the year: day 1 of month 2 now.
+	if y, m, d := shiftDate(old, next, 1000, 1, 31); y != 1000 || m != 2 || d != 1 {
+		t.Errorf("shift = %d-%d-%d, want 1000-2-1", y, m, d)
+	}
+	// Day 61 no longer fits the 60-day year: it rolls into next year.
+	if y, m, d := shiftDate(old, next, 1000, 2, 30); y != 1001 || m != 1 || d != 1 {
+		t.Errorf("shift = %d-%d-%d, want 1001-1-1", y, m, d)
+	}
+}
+
+func TestClampDate(t *testing.T) {
+	next := &Calendar{Months: []Month{{Days: 30}}}
+	if y, m, d := clampDate(next, 5, 2, 31); y != 5 || m != 1 || d != 30 {
+		t.Errorf("clamp = %d-%d-%d, want 5-1-30", y, m, d)
+	}
+}
+
+func TestPreviewMonthsImpact_ListsStrandedEvents(t *testing.T) {
+	svc := newTestCalendarService(impactRepo([]Event{
+		{ID: "fits", Name: "Fits", Year: 1, Month: 1, Day: 30},
+		{ID: "day31", Name: "Day 31", Year: 1, Month: 1, Day: 31},
+		{ID: "gone", Name: "Gone month", Year: 1, Month: 2, Day: 5},
+		{ID: "anniv", Name: "Birthday", Year: 1, Month: 2, Day: 5, SourceField: ptr("birthday")},
+	}))
+
+	impact, err := svc.PreviewMonthsImpact(context.Background(), "cal-1", []MonthInput{{Name: "Frost", Days: 30}})
+	if err != nil {
+		t.Fatalf("PreviewMonthsImpact: %v", err)
+	}
+	if len(impact.Events) != 2 {
+		t.Fatalf("want 2 stranded events, got %+v", impact.Events)
+	}
+	if e := impact.Events[0]; e.EventID != "day31" || e.Clamped != "1-1-30" || e.Shifted != "2-1-1" {
+		t.Errorf("day31 impact = %+v", e)
+	}
+	if e := impact.Events[1]; e.EventID != "gone" || e.Clamped != "1-1-5" {
+		t.Errorf("gone impact = %+v", e)
+	}
+}
+
+func TestPreviewWeekdaysImpact_ListsWeeklyEvents(t *testing.T) {
+	svc := newTestCalendarService(impactRepo([]Event{
+		{ID: "w3", Name: "Market", Year: 1, Month: 1, Day: 1, IsRecurring: true, RecurrenceDayOfWeek: ptr(2)},
+	}))
+	impact, err := svc.PreviewWeekdaysImpact(context.Background(), "cal-1", []WeekdayInput{{Name: "One"}, {Name: "Two"}})
+	if err != nil {
+		t.Fatalf("PreviewWeekdaysImpact: %v", err)
+	}
+	if len(impact.Events) != 1 || impact.Events[0].Clamped != "Two" || impact.Events[0].Shifted != "One" {
+		t.Errorf("impact = %+v", impact.Events)
+	}
+}
+
+func TestSetMonthsWithRemedy_AppliesFixesInOneWrite(t *testing.T) {
+	repo := impactRepo([]Event{
+		{ID: "day31", Name: "Day 31", Year: 1, Month: 1, Day: 31},
+		{ID: "gone", Name: "Gone month", Year: 1, Month: 2, Day: 5},
+	})
+	var got []EventDateFix
+	repo.setMonthsFn = func(context.Context, string, []MonthInput) error {
+		t.Error("plain SetMonths used despite a remedy")
+		return nil
+	}
+	repo.setStructureFixesFn = func(_ context.Context, _ string, months []MonthInput, weekdays []WeekdayInput, fixes []EventDateFix) error {
+		if len(months) != 1 || weekdays != nil {
+			t.Errorf("months=%v weekdays=%v", months, weekdays)
+		}
+		got = fixes
+		return nil
+	}
+	svc := newTestCalendarService(repo)
+
+	if err := svc.SetMonthsWithRemedy(context.Background(), "cal-1", []MonthInput{{Name: "Frost", Days: 30}}, RemedyClamp); err != nil {
+		t.Fatalf("SetMonthsWithRemedy: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 fixes, got %+v", got)
+	}
+	if e := got[0].Event; got[0].Delete || e.Month != 1 || e.Day != 30 {
+		t.Errorf("day31 fix = %+v", got[0])
+	}
+	if e := got[1].Event; e.Month != 1 || e.Day != 5 {
+		t.Errorf("gone fix = %+v", got[1])
+	}
+}
+
+func TestSetMonthsWithRemedy_DeleteAndUnknown(t *testing.T) {
+	repo := impactRepo([]Event{{ID: "day31", Year: 1, Month: 1, Day: 31}})
+	var got []EventDateFix
+	repo.setStructureFixesFn = func(_ context.Context, _ string, _ []MonthInput, _ []WeekdayInput, fixes []EventDateFix) error {
+		got = fixes
+		return nil
+	}
+	svc := newTestCalendarService(repo)
+	months := []MonthInput{{Name: "Frost", Days: 30}}
+
+	if err := svc.SetMonthsWithRemedy(context.Background(), "cal-1", months, RemedyDelete); err != nil {
+		t.Fatalf("SetMonthsWithRemedy: %v", err)
+	}
+	if len(got) != 1 || !got[0].Delete || got[0].EventID != "day31" {
+		t.Errorf("fixes = %+v", got)
+	}
+	err := svc.SetMonthsWithRemedy(context.Background(), "cal-1", months, "explode")
+	assertAppError(t, err, 422)
+}
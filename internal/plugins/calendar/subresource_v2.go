@@ -45,6 +45,10 @@ const (
 	SubresourceCycles     SubresourceKind = "cycles"
 	SubresourceZones      SubresourceKind = "zones"
 	SubresourceWeather    SubresourceKind = "weather"
+	// GM console quick-advance buttons (session clock presets).
+	SubresourceTimePresets SubresourceKind = "time-presets"
+	// Explicitly named years ("Year of the Shattered Crown").
+	SubresourceYearNames SubresourceKind = "year-names"
 )
 
 // isSingular reports whether the kind renders as a single state card
@@ -92,6 +96,8 @@ type SubresourceViewData struct {
 	Cycles          []Cycle
 	Zones           []WeatherZone
 	Weather         *Weather // populated only when Kind == SubresourceWeather
+	TimePresets     []TimePreset
+	YearNames       []YearName
 }
 
 // ShowV2SubresourceSettings renders the V2 card-grid editor for one
@@ -122,7 +128,7 @@ func (h *Handler) ShowV2SubresourceSettings(c echo.Context) error {
 	switch resource {
 	case SubresourceMonths:
 		data.Months = cal.Months
-		data.Cards = monthsToCards(cal.Months)
+		data.Cards = monthsToCards(cal.Months, cal.Weekdays)
 	case SubresourceWeekdays:
 		data.Weekdays = cal.Weekdays
 		data.Cards = weekdaysToCards(cal.Weekdays)
@@ -130,8 +136,16 @@ func (h *Handler) ShowV2SubresourceSettings(c echo.Context) error {
 		data.Moons = cal.Moons
 		data.Cards = moonsToCards(cal.Moons)
 	case SubresourceSeasons:
+		// Zones feed the drawer's region picker and the card subtitles.
+		zonesState, err := h.svc.GetWeatherZones(c.Request().Context(), cal.ID)
+		if err != nil {
+			return err
+		}
+		if zonesState != nil {
+			data.Zones = zonesState.Zones
+		}
 		data.Seasons = cal.Seasons
-		data.Cards = seasonsToCards(cal.Seasons)
+		data.Cards = seasonsToCards(cal.Seasons, data.Zones)
 	case SubresourceEras:
 		data.Eras = cal.Eras
 		data.Cards = erasToCards(cal.Eras)
@@ -179,6 +193,22 @@ func (h *Handler) ShowV2SubresourceSettings(c echo.Context) error {
 		if zonesState != nil {
 			data.Zones = zonesState.Zones
 		}
+	case SubresourceTimePresets:
+		// Unsaved calendars get the defaults, so the first Save persists
+		// them along with whatever the owner edited.
+		presets, err := h.svc.GetTimePresets(c.Request().Context(), cal.ID)
+		if err != nil {
+			return err
+		}
+		data.TimePresets = presets
+		data.Cards = timePresetsToCards(presets)
+	case SubresourceYearNames:
+		names, err := h.svc.GetYearNames(c.Request().Context(), cal.ID)
+		if err != nil {
+			return err
+		}
+		data.YearNames = names
+		data.Cards = yearNamesToCards(names)
 	default:
 		return apperror.NewNotFound("unknown sub-resource")
 	}
@@ -195,13 +225,20 @@ func (h *Handler) ShowV2SubresourceSettings(c echo.Context) error {
 
 // --- Per-resource → card-data projections ---
 
-func monthsToCards(months []Month) []SubresourceCardData {
+// monthsToCards takes the weekdays to name a month's pinned starting weekday.
+func monthsToCards(months []Month, weekdays []Weekday) []SubresourceCardData {
 	out := make([]SubresourceCardData, len(months))
 	for i, m := range months {
 		sub := pluralizeDays(m.Days)
 		if m.IsIntercalary {
 			sub += " · intercalary"
 		}
+		if m.SkipsWeekday {
+			sub += " · outside the week"
+		}
+		if w := m.StartingWeekday; w != nil && *w >= 0 && *w < len(weekdays) {
+			sub += " · starts " + weekdays[*w].Name
+		}
 		if m.LeapYearDays > 0 {
 			sub += " · +" + itoa(m.LeapYearDays) + " leap"
 		}
@@ -248,11 +285,22 @@ func moonsToCards(moons []Moon) []SubresourceCardData {
 	return out
 }
 
-func seasonsToCards(seasons []Season) []SubresourceCardData {
+func seasonsToCards(seasons []Season, zones []WeatherZone) []SubresourceCardData {
+	names := make(map[string]string, len(zones))
+	for _, z := range zones {
+		names[z.ZoneID] = z.Name
+	}
 	out := make([]SubresourceCardData, len(seasons))
 	for i, s := range seasons {
 		sub := "month " + itoa(s.StartMonth) + " · day " + itoa(s.StartDay) +
 			" → month " + itoa(s.EndMonth) + " · day " + itoa(s.EndDay)
+		if s.ZoneID != nil {
+			name := names[*s.ZoneID]
+			if name == "" {
+				name = *s.ZoneID
+			}
+			sub += " · " + name
+		}
 		out[i] = SubresourceCardData{
 			ID:       itoa(i),
 			Index:    i,
@@ -295,6 +343,9 @@ func categoriesToCards(cats []EventCategory) []SubresourceCardData {
 		if c.Icon != "" {
 			sub += " · " + c.Icon
 		}
+		if c.DefaultVisibility == "dm_only" {
+			sub += " · DM only"
+		}
 		out[i] = SubresourceCardData{
 			ID:       itoa(i),
 			Index:    i,
@@ -381,6 +432,32 @@ func zonesToCards(zones []WeatherZone, activeZoneID string) []SubresourceCardDat
 	return out
 }
 
+func timePresetsToCards(presets []TimePreset) []SubresourceCardData {
+	out := make([]SubresourceCardData, len(presets))
+	for i, p := range presets {
+		out[i] = SubresourceCardData{
+			ID:       itoa(i),
+			Index:    i,
+			Name:     p.Name,
+			Subtitle: p.AdvanceLabel(),
+		}
+	}
+	return out
+}
+
+func yearNamesToCards(names []YearName) []SubresourceCardData {
+	out := make([]SubresourceCardData, len(names))
+	for i, yn := range names {
+		out[i] = SubresourceCardData{
+			ID:       itoa(i),
+			Index:    i,
+			Name:     yn.Name,
+			Subtitle: "year " + itoa(yn.Year),
+		}
+	}
+	return out
+}
+
 // pluralizeDays returns "N day" or "N days" so the card subtitle
 // reads naturally for intercalary single-day months.
 func pluralizeDays(n int) string {
@@ -458,6 +535,10 @@ func subresourceTitle(kind SubresourceKind) string {
 		return "Weather Zones"
 	case SubresourceWeather:
 		return "Weather"
+	case SubresourceTimePresets:
+		return "Time Presets"
+	case SubresourceYearNames:
+		return "Named Years"
 	}
 	return "Sub-resource"
 }
@@ -485,6 +566,10 @@ func subresourceSingular(kind SubresourceKind) string {
 		return "zone"
 	case SubresourceWeather:
 		return "weather state"
+	case SubresourceTimePresets:
+		return "time preset"
+	case SubresourceYearNames:
+		return "year name"
 	}
 	return "item"
 }
@@ -127,6 +127,10 @@ func subresourcePayloadJSON(data SubresourceViewData) string {
 		payload = data.Cycles
 	case SubresourceZones:
 		payload = data.Zones
+	case SubresourceTimePresets:
+		payload = data.TimePresets
+	case SubresourceYearNames:
+		payload = data.YearNames
 	case SubresourceWeather:
 		// Singular: marshal the Weather struct directly. Drawer JS
 		// reads it as an object, not an array. nil means "no state
@@ -178,6 +182,8 @@ func subresourceEmptyExplainer(kind SubresourceKind) string {
 		return "Festivals are fixed calendar entries — holidays that don't recur as events. Each lives on a specific month + day, or intercalary between months."
 	case SubresourceCycles:
 		return "Cycles rotate through entries over time (zodiac, elemental, seasonal). Each cycle has a length in years and an ordered list of entries."
+	case SubresourceYearNames:
+		return "Name individual years (\"Year of the Shattered Crown\"). Names show beside the year number, along with any yearly cycle entries."
 	case SubresourceZones:
 		return "Weather zones define climate regions (temperate, tropical, arctic). The active zone drives the weather generator. Foundry sync may also edit zones."
 	case SubresourceWeather:
@@ -25,10 +25,12 @@ func TestMonthsToCards_SubtitleComposition(t *testing.T) {
 		{Name: "Mirtul", Days: 30},
 		{Name: "Shieldmeet", Days: 1, IsIntercalary: true},
 		{Name: "Tarsakh", Days: 30, LeapYearDays: 1},
+		{Name: "Midwinter", Days: 1, IsIntercalary: true, SkipsWeekday: true},
+		{Name: "Flamerule", Days: 30, StartingWeekday: intPtr(1)},
 	}
-	cards := monthsToCards(months)
-	if len(cards) != 3 {
-		t.Fatalf("expected 3 cards; got %d", len(cards))
+	cards := monthsToCards(months, []Weekday{{Name: "Sul"}, {Name: "Mol"}})
+	if len(cards) != 5 {
+		t.Fatalf("expected 5 cards; got %d", len(cards))
 	}
 	if cards[0].Subtitle != "30 days" {
 		t.Errorf("Mirtul subtitle = %q; want '30 days'", cards[0].Subtitle)
@@ -39,6 +41,12 @@ func TestMonthsToCards_SubtitleComposition(t *testing.T) {
 	if !strings.Contains(cards[2].Subtitle, "+1 leap") {
 		t.Errorf("Tarsakh subtitle = %q; want '+1 leap'", cards[2].Subtitle)
 	}
+	if !strings.Contains(cards[3].Subtitle, "outside the week") {
+		t.Errorf("Midwinter subtitle = %q; want 'outside the week'", cards[3].Subtitle)
+	}
+	if !strings.Contains(cards[4].Subtitle, "starts Mol") {
+		t.Errorf("Flamerule subtitle = %q; want 'starts Mol'", cards[4].Subtitle)
+	}
 }
 
 func TestWeekdaysToCards_RestDayAccent(t *testing.T) {
@@ -70,14 +78,21 @@ func TestMoonsToCards_ColorPropagates(t *testing.T) {
 }
 
 func TestSeasonsToCards_RangeSubtitle(t *testing.T) {
-	seasons := []Season{{Name: "Summer", StartMonth: 6, StartDay: 1, EndMonth: 8, EndDay: 31, Color: "#ffcc00"}}
-	cards := seasonsToCards(seasons)
+	desert := "desert"
+	seasons := []Season{
+		{Name: "Summer", StartMonth: 6, StartDay: 1, EndMonth: 8, EndDay: 31, Color: "#ffcc00"},
+		{Name: "Dry", StartMonth: 1, StartDay: 1, EndMonth: 6, EndDay: 30, ZoneID: &desert},
+	}
+	cards := seasonsToCards(seasons, []WeatherZone{{ZoneID: "desert", Name: "Southern Desert"}})
 	if cards[0].Color != "#ffcc00" {
 		t.Errorf("expected color propagation; got %q", cards[0].Color)
 	}
 	if !strings.Contains(cards[0].Subtitle, "month 6") || !strings.Contains(cards[0].Subtitle, "month 8") {
 		t.Errorf("season subtitle = %q; want range with month 6 + 8", cards[0].Subtitle)
 	}
+	if !strings.HasSuffix(cards[1].Subtitle, " · Southern Desert") {
+		t.Errorf("regional season subtitle = %q; want the region name", cards[1].Subtitle)
+	}
 }
 
 func TestSubresourcePayloadJSON_RoundTrip(t *testing.T) {
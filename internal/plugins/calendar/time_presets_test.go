@@ -0,0 +1,144 @@
+package calendar
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetTimePresets_Validation(t *testing.T) {
+	tooMany := make([]TimePresetInput, maxTimePresets+1)
+	for i := range tooMany {
+		tooMany[i] = TimePresetInput{Name: "Rest", Hours: 1}
+	}
+	tests := []struct {
+		name    string
+		presets []TimePresetInput
+		wantErr bool
+	}{
+		{name: "hours preset", presets: []TimePresetInput{{Name: "Long rest", Hours: 8}}},
+		{name: "days preset", presets: []TimePresetInput{{Name: "Travel day", Days: 1}}},
+		{name: "hours and minutes", presets: []TimePresetInput{{Name: "Dungeon turn", Hours: 1, Minutes: 30}}},
+		{name: "empty list restores defaults", presets: nil},
+		{name: "blank name", presets: []TimePresetInput{{Name: "  ", Hours: 1}}, wantErr: true},
+		{name: "zero advance", presets: []TimePresetInput{{Name: "Nothing"}}, wantErr: true},
+		{name: "negative", presets: []TimePresetInput{{Name: "Back", Hours: -1}}, wantErr: true},
+		{name: "days and hours", presets: []TimePresetInput{{Name: "Mixed", Days: 1, Hours: 2}}, wantErr: true},
+		{name: "too large", presets: []TimePresetInput{{Name: "Age", Days: 3651}}, wantErr: true},
+		{name: "too many", presets: tooMany, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			repo := &mockCalendarRepo{
+				setTimePresetsFn: func(_ context.Context, _ string, _ []TimePresetInput) error {
+					saved = true
+					return nil
+				},
+			}
+			err := newTestCalendarService(repo).SetTimePresets(context.Background(), "cal-1", tt.presets)
+			if tt.wantErr {
+				assertAppError(t, err, 422)
+				if saved {
+					t.Error("invalid presets must not reach the repo")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetTimePresets_NormalizesNameAndOrder(t *testing.T) {
+	var got []TimePresetInput
+	repo := &mockCalendarRepo{
+		setTimePresetsFn: func(_ context.Context, _ string, presets []TimePresetInput) error {
+			got = presets
+			return nil
+		},
+	}
+	err := newTestCalendarService(repo).SetTimePresets(context.Background(), "cal-1", []TimePresetInput{
+		{Name: " Travel day ", Days: 1, SortOrder: 9},
+		{Name: "Short rest", Hours: 1, SortOrder: 9},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[0].Name != "Travel day" || got[0].SortOrder != 1 || got[1].SortOrder != 2 {
+		t.Errorf("presets not normalized: %+v", got)
+	}
+}
+
+func TestGetTimePresets_DefaultsWhenUnsaved(t *testing.T) {
+	svc := newTestCalendarService(&mockCalendarRepo{})
+	presets, err := svc.GetTimePresets(context.Background(), "cal-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(presets) != 3 || presets[1].Name != "Long rest" || presets[1].Hours != 8 {
+		t.Errorf("got %+v, want the built-in defaults", presets)
+	}
+
+	saved := []TimePreset{{Name: "Watch", Hours: 4}}
+	svc = newTestCalendarService(&mockCalendarRepo{
+		getTimePresetsFn: func(_ context.Context, _ string) ([]TimePreset, error) { return saved, nil },
+	})
+	presets, _ = svc.GetTimePresets(context.Background(), "cal-1")
+	if len(presets) != 1 || presets[0].Name != "Watch" {
+		t.Errorf("got %+v, want the saved presets", presets)
+	}
+}
+
+func TestTimePreset_AdvanceLabel(t *testing.T) {
+	tests := []struct {
+		preset TimePreset
+		want   string
+	}{
+		{TimePreset{Days: 1}, "+1d"},
+		{TimePreset{Hours: 8}, "+8h"},
+		{TimePreset{Hours: 1, Minutes: 30}, "+1h 30m"},
+		{TimePreset{Minutes: 10}, "+10m"},
+	}
+	for _, tt := range tests {
+		if got := tt.preset.AdvanceLabel(); got != tt.want {
+			t.Errorf("AdvanceLabel(%+v) = %q, want %q", tt.preset, got, tt.want)
+		}
+	}
+}
+
+func TestGMPanel_RendersTimePresets(t *testing.T) {
+	data := CalendarV2ViewData{
+		ActiveCalendar:       gmTestCalendar(),
+		CanControlWorldState: true,
+		IsOwner:              true,
+		TimePresets:          DefaultTimePresets(),
+	}
+	var sb strings.Builder
+	if err := gmControlPanelV2(data).Render(context.Background(), &sb); err != nil {
+		t.Fatalf("render panel: %v", err)
+	}
+	html := sb.String()
+	for _, want := range []string{`data-gm-preset="Short rest"`, `data-gm-preset="Travel day"`, `data-gm-days="1"`, `title="Long rest (+8h)"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("panel missing %q", want)
+		}
+	}
+	if !strings.Contains(html, "/settings/time-presets") {
+		t.Error("owner console should link the presets editor")
+	}
+
+	// Presets and their editor are Owner-only (the advance endpoints and the
+	// presets PUT are Owner-gated); a co-DM gets the fixed world-state steps.
+	data.IsOwner = false
+	sb.Reset()
+	_ = gmControlPanelV2(data).Render(context.Background(), &sb)
+	html = sb.String()
+	if strings.Contains(html, "data-gm-preset=") || strings.Contains(html, "data-gm-presets-edit") {
+		t.Error("co-DM console must not render presets or the presets editor")
+	}
+	if !strings.Contains(html, `data-gm-advance data-gm-days="1"`) {
+		t.Error("co-DM console should keep the fixed +1d step")
+	}
+}
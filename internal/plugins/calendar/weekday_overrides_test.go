@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+)
+
+// overrideCalendar is a 7-day-week calendar of Harvest (30), Midfeast (1,
+// intercalary) and Frost (30), with the given per-month overrides applied.
+func overrideCalendar(midSkips bool, frostStart *int) *Calendar {
+	weekdays := make([]Weekday, 7)
+	for i := range weekdays {
+		weekdays[i] = Weekday{Name: string(rune('A' + i))}
+	}
+	return &Calendar{
+		Weekdays: weekdays,
+		Months: []Month{
+			{Name: "Harvest", Days: 30},
+			{Name: "Midfeast", Days: 1, IsIntercalary: true, SkipsWeekday: midSkips},
+			{Name: "Frost", Days: 30, StartingWeekday: frostStart},
+		},
+	}
+}
+
+func TestWeekdayIndex_MonthOverrides(t *testing.T) {
+	plain := overrideCalendar(false, nil)
+	skip := overrideCalendar(true, nil)
+	anchored := overrideCalendar(true, intPtr(0))
+	tests := []struct {
+		name             string
+		cal              *Calendar
+		year, month, day int
+		want             int
+	}{
+		// No overrides: year*YearLength + prior days + day, unchanged.
+		{"plain geometry", plain, 3, 3, 5, (3*61 + 31 + 5) % 7},
+		{"skip: before the festival", skip, 0, 1, 30, 2},
+		{"skip: festival has no weekday", skip, 0, 2, 1, -1},
+		{"skip: week resumes after the festival", skip, 0, 3, 1, 3},
+		{"skip: next year", skip, 1, 1, 1, 5},
+		{"anchor: pinned month start", anchored, 0, 3, 1, 0},
+		{"anchor: pinned start a year later", anchored, 1, 3, 1, 0},
+		{"anchor: pinned start far out", anchored, 40, 3, 1, 0},
+		{"anchor: week flows within the month", anchored, 1, 3, 9, 1},
+		{"anchor: flows into the next year", anchored, 1, 1, 1, 2},
+		{"anchor: end of the unpinned month", anchored, 1, 1, 30, 3},
+		{"anchor: festival stays outside", anchored, 1, 2, 1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v2WeekdayIndexFor(tt.cal, tt.year, tt.month, tt.day); got != tt.want {
+				t.Errorf("v2WeekdayIndexFor(%d, %d, %d) = %d, want %d", tt.year, tt.month, tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccursOn_WeeklyWithMonthOverrides(t *testing.T) {
+	cal := overrideCalendar(true, intPtr(0))
+	weekly := RecurrenceWeekly
+	e := Event{Year: 1, Month: 3, Day: 1, IsRecurring: true, RecurrenceType: &weekly}
+	tests := []struct {
+		name             string
+		year, month, day int
+		want             bool
+	}{
+		{"one week on", 1, 3, 8, true},
+		{"across the year boundary", 2, 1, 6, true},
+		{"the pinned start next year", 2, 3, 1, true},
+		{"off-weekday", 2, 3, 2, false},
+		{"festival outside the week", 2, 2, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.OccursOn(cal, tt.year, tt.month, tt.day); got != tt.want {
+				t.Errorf("OccursOn(%d, %d, %d) = %v, want %v", tt.year, tt.month, tt.day, got, tt.want)
+			}
+			// Recurrence and display must agree on the weekday.
+			if tt.want && v2WeekdayIndexFor(cal, tt.year, tt.month, tt.day) != 0 {
+				t.Errorf("occurrence rendered off the base weekday")
+			}
+		})
+	}
+}
+
+func TestSetMonths_WeekdayOverrides(t *testing.T) {
+	repo := &mockCalendarRepo{
+		getWeekdaysFn: func(_ context.Context, _ string) ([]Weekday, error) {
+			return make([]Weekday, 7), nil
+		},
+	}
+	tests := []struct {
+		name     string
+		month    MonthInput
+		wantCode int
+	}{
+		{name: "pinned start", month: MonthInput{Name: "Frost", Days: 30, StartingWeekday: intPtr(6)}},
+		{name: "intercalary outside the week", month: MonthInput{Name: "Midfeast", Days: 1, IsIntercalary: true, SkipsWeekday: true}},
+		{name: "start past the last weekday", month: MonthInput{Name: "Frost", Days: 30, StartingWeekday: intPtr(7)}, wantCode: 422},
+		{name: "negative start", month: MonthInput{Name: "Frost", Days: 30, StartingWeekday: intPtr(-1)}, wantCode: 422},
+		{name: "regular month outside the week", month: MonthInput{Name: "Frost", Days: 30, SkipsWeekday: true}, wantCode: 422},
+		{name: "outside the week with a start", month: MonthInput{Name: "Midfeast", Days: 1, IsIntercalary: true,
+			SkipsWeekday: true, StartingWeekday: intPtr(0)}, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestCalendarService(repo).SetMonths(context.Background(), "cal-1", []MonthInput{tt.month})
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSimpleCalendar_WeekdayOverrides(t *testing.T) {
+	data := []byte(`{
+		"calendar": {
+			"months": [
+				{"name": "Hammer", "numberOfDays": 30, "startingWeekday": 2},
+				{"name": "Midwinter", "numberOfDays": 1, "intercalary": true},
+				{"name": "Shieldmeet", "numberOfDays": 1, "intercalary": true, "intercalaryInclude": true},
+				{"name": "Alturiak", "numberOfDays": 30, "startingWeekday": 9}
+			],
+			"weekdays": [
+				{"name": "One", "numericRepresentation": 1},
+				{"name": "Two", "numericRepresentation": 2}
+			]
+		}
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Months) != 4 {
+		t.Fatalf("got %d months, want 4", len(result.Months))
+	}
+	if w := result.Months[0].StartingWeekday; w == nil || *w != 1 {
+		t.Errorf("Hammer StartingWeekday = %v, want index 1 (numericRepresentation 2)", w)
+	}
+	if !result.Months[1].SkipsWeekday || result.Months[2].SkipsWeekday {
+		t.Errorf("SkipsWeekday = %v/%v; want only the non-included intercalary to skip",
+			result.Months[1].SkipsWeekday, result.Months[2].SkipsWeekday)
+	}
+	if result.Months[3].StartingWeekday != nil {
+		t.Errorf("Alturiak StartingWeekday = %v; an unknown weekday should be dropped", *result.Months[3].StartingWeekday)
+	}
+}
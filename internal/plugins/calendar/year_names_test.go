@@ -0,0 +1,224 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// namedCalendar names 1492 explicitly and runs an 11-year yearly cycle whose
+// "Ember" entry starts at offset 3 and "Frost" at offset 8.
+func namedCalendar() *Calendar {
+	return &Calendar{
+		YearNames: []YearName{{Year: 1492, Name: "Year of Three Ships Sailing"}},
+		Cycles: []Cycle{
+			{Name: "Monthly", Type: "monthly", CycleLength: 12, Entries: []CycleEntry{{Name: "Ignored"}}},
+			{Name: "Omens", Type: "yearly", CycleLength: 11, Entries: []CycleEntry{
+				{Name: "Frost", YearOffset: 8},
+				{Name: "Ember", YearOffset: 3},
+			}},
+		},
+	}
+}
+
+func TestYearLabel(t *testing.T) {
+	cal := namedCalendar()
+	tests := []struct {
+		year int
+		want string
+	}{
+		{1492, "Year of Three Ships Sailing · Ember"}, // 1492 = 135×11 + 7
+		{3, "Ember"},
+		{7, "Ember"},
+		{8, "Frost"},
+		{10, "Frost"},
+		{11, "Frost"}, // next lap, before the first offset: still the last entry
+		{14, "Ember"},
+		{-1, "Frost"},
+	}
+	for _, tt := range tests {
+		if got := cal.YearLabel(tt.year); got != tt.want {
+			t.Errorf("YearLabel(%d) = %q, want %q", tt.year, got, tt.want)
+		}
+	}
+	if got := (&Calendar{}).YearLabel(1492); got != "" {
+		t.Errorf("unnamed calendar YearLabel = %q, want empty", got)
+	}
+}
+
+func TestSetYearNames_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []YearNameInput
+		wantCode int
+	}{
+		{name: "valid", in: []YearNameInput{{Year: 1, Name: "First"}, {Year: -4, Name: "Before"}}},
+		{name: "clear all", in: nil},
+		{name: "blank name", in: []YearNameInput{{Year: 1, Name: "  "}}, wantCode: 422},
+		{name: "duplicate year", in: []YearNameInput{{Year: 1, Name: "A"}, {Year: 1, Name: "B"}}, wantCode: 422},
+		{name: "name too long", in: []YearNameInput{{Year: 1, Name: strings.Repeat("x", 201)}}, wantCode: 422},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []YearNameInput
+			repo := &mockCalendarRepo{
+				setYearNamesFn: func(_ context.Context, _ string, names []YearNameInput) error {
+					saved = names
+					return nil
+				},
+			}
+			err := newTestCalendarService(repo).SetYearNames(context.Background(), "cal-1", tt.in)
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				if saved != nil {
+					t.Error("invalid names must not reach the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSimpleCalendar_YearNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		wantNames []YearNameInput
+		wantCycle map[int]string // year → cycle entry
+	}{
+		{name: "default names consecutive years", rule: "default",
+			wantNames: []YearNameInput{{Year: 1490, Name: "Wyrm"}, {Year: 1491, Name: "Blade"}, {Year: 1492, Name: "Lantern"}}},
+		{name: "repeat rotates", rule: "repeat",
+			wantCycle: map[int]string{1490: "Wyrm", 1491: "Blade", 1492: "Lantern", 1493: "Wyrm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(`{"calendar": {
+				"months": [{"name": "Hammer", "numberOfDays": 30}],
+				"weekdays": [{"name": "One", "numericRepresentation": 1}],
+				"year": {"numericRepresentation": 1492, "yearNamesStart": 1490,
+					"yearNamingRule": "` + tt.rule + `", "yearNames": ["Wyrm", "Blade", "Lantern"]}
+			}}`)
+			result, err := DetectAndParse(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.YearNames) != len(tt.wantNames) {
+				t.Fatalf("got year names %+v, want %+v", result.YearNames, tt.wantNames)
+			}
+			for i, want := range tt.wantNames {
+				if result.YearNames[i] != want {
+					t.Errorf("year name %d = %+v, want %+v", i, result.YearNames[i], want)
+				}
+			}
+			cal := calendarFromImport(result)
+			for year, want := range tt.wantCycle {
+				if got := cal.YearLabel(year); got != want {
+					t.Errorf("YearLabel(%d) = %q, want %q", year, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFantasyCalendar_Cycles(t *testing.T) {
+	data := []byte(`{
+		"name": "Exandria",
+		"static_data": {
+			"year_data": {"global_week": ["One"], "timespans": [{"name": "Hammer", "length": 30}]},
+			"cycles": {"format": "{{1}}", "data": [{"length": 2, "offset": 1, "names": ["Rat", "Ox", "Tiger"]}]}
+		},
+		"dynamic_data": {"year": 812}
+	}`)
+	result, err := DetectAndParse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Cycles) != 1 || result.Cycles[0].CycleLength != 6 {
+		t.Fatalf("cycles = %+v; want one 6-year cycle", result.Cycles)
+	}
+	cal := calendarFromImport(result)
+	// FC: names[floor((year + 1) / 2) % 3].
+	for year, want := range map[int]string{0: "Rat", 1: "Ox", 2: "Ox", 3: "Tiger", 5: "Rat", 6: "Rat", 7: "Ox", -1: "Rat", -2: "Tiger"} {
+		if got := cal.YearLabel(year); got != want {
+			t.Errorf("YearLabel(%d) = %q, want %q", year, got, want)
+		}
+	}
+}
+
+func TestBuildExportAs_YearNamingRoundTrip(t *testing.T) {
+	cal := &Calendar{
+		ID: "cal-1", Name: "Harptos", CurrentYear: 1492, CurrentMonth: 1, CurrentDay: 1,
+		HoursPerDay: 24, MinutesPerHour: 60, SecondsPerMinute: 60,
+		Months:    []Month{{Name: "Hammer", Days: 30}},
+		Weekdays:  []Weekday{{Name: "One"}},
+		YearNames: []YearName{{Year: 1491, Name: "Year of the Blade"}, {Year: 1492, Name: "Year of Three Ships Sailing"}},
+		Cycles: []Cycle{{Name: "Zodiac", Type: "yearly", CycleLength: 9, Entries: []CycleEntry{
+			{Name: "Rat", YearOffset: 2}, {Name: "Ox", YearOffset: 5}, {Name: "Tiger", YearOffset: 8},
+		}}},
+	}
+
+	for _, format := range []ImportFormat{FormatSimpleCal, FormatFantasyCal} {
+		t.Run(string(format), func(t *testing.T) {
+			export, err := BuildExportAs(format, cal, nil, false)
+			if err != nil {
+				t.Fatalf("build export: %v", err)
+			}
+			raw, err := json.Marshal(export)
+			if err != nil {
+				t.Fatalf("marshal export: %v", err)
+			}
+			result, err := DetectAndParse(raw)
+			if err != nil {
+				t.Fatalf("re-parse export: %v", err)
+			}
+			back := calendarFromImport(result)
+			// SC carries the explicit names; FC only has cycles.
+			for year := 1480; year < 1500; year++ {
+				want := ""
+				if format == FormatSimpleCal {
+					want = (&Calendar{YearNames: cal.YearNames}).YearLabel(year)
+				} else {
+					want = (&Calendar{Cycles: cal.Cycles}).YearLabel(year)
+				}
+				if got := back.YearLabel(year); got != want {
+					t.Errorf("YearLabel(%d) = %q, want %q", year, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestV2PeriodSubLabel_YearNames(t *testing.T) {
+	cal := namedCalendar()
+	cal.Eras = []Era{{Name: "Age of Humanity", StartYear: 1000}}
+	data := CalendarV2ViewData{ActiveCalendar: cal, Year: 1492}
+	if got, want := v2PeriodSubLabel(data), "Age of Humanity · Year of Three Ships Sailing · Ember"; got != want {
+		t.Errorf("v2PeriodSubLabel = %q, want %q", got, want)
+	}
+	data.ActiveCalendar = &Calendar{}
+	if got := v2PeriodSubLabel(data); got != "" {
+		t.Errorf("unnamed v2PeriodSubLabel = %q, want empty", got)
+	}
+}
+
+// calendarFromImport projects an import result's year naming onto a
+// Calendar so tests can read it back through YearLabel.
+func calendarFromImport(r *ImportResult) *Calendar {
+	cal := &Calendar{}
+	for _, yn := range r.YearNames {
+		cal.YearNames = append(cal.YearNames, YearName{Year: yn.Year, Name: yn.Name})
+	}
+	for _, ci := range r.Cycles {
+		c := Cycle{Name: ci.Name, Type: ci.Type, CycleLength: ci.CycleLength}
+		for _, e := range ci.Entries {
+			c.Entries = append(c.Entries, CycleEntry{Name: e.Name, YearOffset: e.YearOffset})
+		}
+		cal.Cycles = append(cal.Cycles, c)
+	}
+	return cal
+}
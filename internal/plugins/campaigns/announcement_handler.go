@@ -0,0 +1,169 @@
+package campaigns
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// Announcements renders the campaign's announcements, with the editor for
+// the owner (GET /campaigns/:id/announcements).
+func (h *Handler) Announcements(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	list, err := h.announcements.List(ctx, cc.Campaign.ID, userID, cc.MemberRole)
+	if err != nil {
+		return err
+	}
+	var digestSubscribed *bool
+	if h.digests != nil {
+		subscribed, err := h.digests.IsSubscribed(ctx, cc.Campaign.ID, userID)
+		if err != nil {
+			return err
+		}
+		digestSubscribed = &subscribed
+	}
+	return middleware.Render(c, http.StatusOK, AnnouncementsPage(cc, list, time.Now().UTC(), middleware.GetCSRFToken(c), digestSubscribed))
+}
+
+// UpdateDigestPreferenceAPI turns the member's weekly digest email on or off
+// (POST /campaigns/:id/digest, form field subscribed=true|false).
+func (h *Handler) UpdateDigestPreferenceAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.digests == nil {
+		return apperror.NewMissingContext()
+	}
+	subscribed := c.FormValue("subscribed") == "true"
+	if err := h.digests.SetSubscribed(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), subscribed); err != nil {
+		return err
+	}
+	if !middleware.IsHTMX(c) {
+		return c.Redirect(http.StatusSeeOther, "/campaigns/"+cc.Campaign.ID+"/announcements")
+	}
+	return middleware.Render(c, http.StatusOK, digestPreferenceCard(cc, subscribed, middleware.GetCSRFToken(c)))
+}
+
+// ShowAnnouncement renders one announcement and marks it read for the
+// viewer (GET /campaigns/:id/announcements/:aid). Notification links land
+// here.
+func (h *Handler) ShowAnnouncement(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	a, err := h.announcements.Get(ctx, cc.Campaign.ID, c.Param("aid"), userID, cc.MemberRole)
+	if err != nil {
+		return err
+	}
+	if !a.Read && a.IsPublishedAt(time.Now()) {
+		if err := h.announcements.MarkRead(ctx, cc.Campaign.ID, a.ID, userID); err != nil {
+			return err
+		}
+	}
+	return middleware.Render(c, http.StatusOK, AnnouncementPage(cc, a, time.Now().UTC()))
+}
+
+// AnnouncementsFragment returns the dashboard's announcements card
+// (GET /campaigns/:id/announcements/fragment). Empty when there is nothing
+// pinned or unread.
+func (h *Handler) AnnouncementsFragment(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return c.NoContent(http.StatusOK)
+	}
+	list, err := h.announcements.ListForDashboard(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, announcementsDashboardCard(cc, list, middleware.GetCSRFToken(c)))
+}
+
+// MarkAnnouncementReadAPI marks an announcement read for the current member
+// (POST /campaigns/:id/announcements/:aid/read).
+func (h *Handler) MarkAnnouncementReadAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	if err := h.announcements.MarkRead(c.Request().Context(), cc.Campaign.ID, c.Param("aid"), auth.GetUserID(c)); err != nil {
+		return err
+	}
+	if !middleware.IsHTMX(c) {
+		return c.Redirect(http.StatusSeeOther, "/campaigns/"+cc.Campaign.ID)
+	}
+	// The dashboard card swaps the entry out with the empty body.
+	return c.NoContent(http.StatusOK)
+}
+
+// CreateAnnouncementAPI publishes or schedules an announcement
+// (POST /campaigns/:id/announcements).
+func (h *Handler) CreateAnnouncementAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	var req AnnouncementInput
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.announcements.Create(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), req)
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.announcement.created", map[string]any{
+		"announcement_id": a.ID,
+		"title":           a.Title,
+		"scheduled":       !a.IsPublishedAt(time.Now()),
+	})
+	return c.JSON(http.StatusCreated, a)
+}
+
+// UpdateAnnouncementAPI edits an announcement
+// (PUT /campaigns/:id/announcements/:aid).
+func (h *Handler) UpdateAnnouncementAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	var req AnnouncementInput
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.announcements.Update(c.Request().Context(), cc.Campaign.ID, c.Param("aid"), req)
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.announcement.updated", map[string]any{
+		"announcement_id": a.ID,
+		"title":           a.Title,
+	})
+	return c.JSON(http.StatusOK, a)
+}
+
+// DeleteAnnouncementAPI removes an announcement
+// (DELETE /campaigns/:id/announcements/:aid).
+func (h *Handler) DeleteAnnouncementAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.announcements == nil {
+		return apperror.NewMissingContext()
+	}
+	id := c.Param("aid")
+	if err := h.announcements.Delete(c.Request().Context(), cc.Campaign.ID, id); err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.announcement.deleted", map[string]any{
+		"announcement_id": id,
+	})
+	return c.NoContent(http.StatusNoContent)
+}
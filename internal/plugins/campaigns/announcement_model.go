@@ -0,0 +1,99 @@
+package campaigns
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// maxAnnouncementBodyLength caps an announcement's markdown source.
+const maxAnnouncementBodyLength = 20000
+
+// NotifAnnouncement is the notification kind sent to members when an
+// announcement is published.
+const NotifAnnouncement = "campaign_announcement"
+
+// Announcement is an owner post on the campaign dashboard. It stays hidden
+// from members until PublishAt; NotifiedAt is set once members have been
+// notified.
+type Announcement struct {
+	ID         string     `json:"id"`
+	CampaignID string     `json:"campaign_id"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	BodyHTML   string     `json:"body_html"`
+	Pinned     bool       `json:"pinned"`
+	PublishAt  time.Time  `json:"publish_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Joined for display (not stored in campaign_announcements).
+	CreatedByName string `json:"created_by_name,omitempty"`
+	Read          bool   `json:"read"` // Whether the viewing member has read it.
+}
+
+// IsPublishedAt reports whether members can see the announcement at now.
+func (a *Announcement) IsPublishedAt(now time.Time) bool {
+	return !now.Before(a.PublishAt)
+}
+
+// AnnouncementInput is the create/update request body. Body is markdown.
+// A nil PublishAt publishes immediately on create and keeps the current
+// schedule on update.
+type AnnouncementInput struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Pinned    bool       `json:"pinned"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// normalize trims and validates the input.
+func (in *AnnouncementInput) normalize() error {
+	in.Title = strings.TrimSpace(in.Title)
+	in.Body = strings.TrimSpace(in.Body)
+	if err := apperror.ValidateRequired("title", in.Title); err != nil {
+		return err
+	}
+	if err := apperror.ValidateStringLength("title", in.Title, apperror.MaxNameLength); err != nil {
+		return err
+	}
+	if err := apperror.ValidateStringLength("body", in.Body, maxAnnouncementBodyLength); err != nil {
+		return err
+	}
+	// Stored as UTC DATETIME, like every other timestamp.
+	if in.PublishAt != nil {
+		t := in.PublishAt.UTC().Truncate(time.Second)
+		in.PublishAt = &t
+	}
+	return nil
+}
+
+// announcementMarkdown renders announcement bodies. Raw HTML in the
+// source is dropped (goldmark's default) and the output still goes
+// through sanitize.HTML before it is stored.
+var announcementMarkdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(html.WithHardWraps()),
+)
+
+// renderAnnouncementBody converts markdown to sanitized HTML.
+func renderAnnouncementBody(body string) (string, error) {
+	if body == "" {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := announcementMarkdown.Convert([]byte(body), &buf); err != nil {
+		return "", apperror.NewBadRequest(fmt.Sprintf("could not read the announcement text: %v", err))
+	}
+	return sanitize.HTML(buf.String()), nil
+}
@@ -0,0 +1,189 @@
+package campaigns
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// AnnouncementRepository handles persistence for campaign announcements and
+// per-member read state.
+type AnnouncementRepository interface {
+	// ListByCampaign returns the campaign's announcements, pinned first then
+	// newest, with Read set for userID. Scheduled posts (publish_at after
+	// now) are left out unless includeScheduled.
+	ListByCampaign(ctx context.Context, campaignID, userID string, includeScheduled bool, now time.Time) ([]Announcement, error)
+	FindByID(ctx context.Context, id, userID string) (*Announcement, error)
+	Create(ctx context.Context, a *Announcement) error
+	Update(ctx context.Context, a *Announcement) error
+	Delete(ctx context.Context, id string) error
+	MarkRead(ctx context.Context, id, userID string) error
+
+	// ListDueDelivery returns published announcements whose members have not
+	// been notified yet, oldest first.
+	ListDueDelivery(ctx context.Context, now time.Time, limit int) ([]Announcement, error)
+
+	// ClaimDelivery sets notified_at if it is still unset and reports
+	// whether this caller won, so a post is only ever sent once.
+	ClaimDelivery(ctx context.Context, id string) (bool, error)
+}
+
+// announcementRepository implements AnnouncementRepository using MariaDB.
+type announcementRepository struct {
+	db *sql.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository.
+func NewAnnouncementRepository(db *sql.DB) AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+const announcementColumns = `a.id, a.campaign_id, a.title, a.body, a.body_html, a.pinned,
+	a.publish_at, a.notified_at, COALESCE(a.created_by, ''), a.created_at, a.updated_at,
+	COALESCE(u.display_name, ''), r.user_id IS NOT NULL`
+
+// announcementFrom joins the author and the viewer's read row; the first
+// placeholder is the viewing user's ID.
+const announcementFrom = `FROM campaign_announcements a
+	LEFT JOIN users u ON u.id = a.created_by
+	LEFT JOIN campaign_announcement_reads r ON r.announcement_id = a.id AND r.user_id = ?`
+
+func scanAnnouncement(scan func(...any) error) (*Announcement, error) {
+	var a Announcement
+	if err := scan(
+		&a.ID, &a.CampaignID, &a.Title, &a.Body, &a.BodyHTML, &a.Pinned,
+		&a.PublishAt, &a.NotifiedAt, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt,
+		&a.CreatedByName, &a.Read,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListByCampaign returns the campaign's announcements for one viewer.
+func (r *announcementRepository) ListByCampaign(ctx context.Context, campaignID, userID string, includeScheduled bool, now time.Time) ([]Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` ` + announcementFrom + `
+	           WHERE a.campaign_id = ?`
+	args := []any{userID, campaignID}
+	if !includeScheduled {
+		query += ` AND a.publish_at <= ?`
+		args = append(args, now.UTC())
+	}
+	query += ` ORDER BY a.pinned DESC, a.publish_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing announcements: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Announcement
+	for rows.Next() {
+		a, err := scanAnnouncement(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scanning announcement: %w", err)
+		}
+		list = append(list, *a)
+	}
+	return list, rows.Err()
+}
+
+// FindByID returns one announcement with Read set for userID.
+func (r *announcementRepository) FindByID(ctx context.Context, id, userID string) (*Announcement, error) {
+	query := `SELECT ` + announcementColumns + ` ` + announcementFrom + `
+	           WHERE a.id = ?`
+	a, err := scanAnnouncement(r.db.QueryRowContext(ctx, query, userID, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NewNotFound("announcement not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching announcement: %w", err)
+	}
+	return a, nil
+}
+
+// Create inserts a new announcement.
+func (r *announcementRepository) Create(ctx context.Context, a *Announcement) error {
+	query := `INSERT INTO campaign_announcements
+	           (id, campaign_id, title, body, body_html, pinned, publish_at, created_by)
+	           VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		a.ID, a.CampaignID, a.Title, a.Body, a.BodyHTML, a.Pinned, a.PublishAt, a.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("inserting announcement: %w", err)
+	}
+	return nil
+}
+
+// Update saves an announcement's content, pin and schedule.
+func (r *announcementRepository) Update(ctx context.Context, a *Announcement) error {
+	query := `UPDATE campaign_announcements
+	           SET title = ?, body = ?, body_html = ?, pinned = ?, publish_at = ?
+	           WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		a.Title, a.Body, a.BodyHTML, a.Pinned, a.PublishAt, a.ID)
+	if err != nil {
+		return fmt.Errorf("updating announcement: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an announcement; read rows cascade.
+func (r *announcementRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM campaign_announcements WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("deleting announcement: %w", err)
+	}
+	return nil
+}
+
+// MarkRead records that the user has read the announcement. Re-reading is
+// a no-op.
+func (r *announcementRepository) MarkRead(ctx context.Context, id, userID string) error {
+	query := `INSERT IGNORE INTO campaign_announcement_reads (announcement_id, user_id) VALUES (?, ?)`
+	if _, err := r.db.ExecContext(ctx, query, id, userID); err != nil {
+		return fmt.Errorf("marking announcement read: %w", err)
+	}
+	return nil
+}
+
+// ListDueDelivery returns published announcements not yet sent to members.
+func (r *announcementRepository) ListDueDelivery(ctx context.Context, now time.Time, limit int) ([]Announcement, error) {
+	query := `SELECT id, campaign_id, title, COALESCE(created_by, ''), publish_at
+	           FROM campaign_announcements
+	           WHERE notified_at IS NULL AND publish_at <= ?
+	           ORDER BY publish_at
+	           LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing announcements due for delivery: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Announcement
+	for rows.Next() {
+		var a Announcement
+		if err := rows.Scan(&a.ID, &a.CampaignID, &a.Title, &a.CreatedBy, &a.PublishAt); err != nil {
+			return nil, fmt.Errorf("scanning announcement: %w", err)
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// ClaimDelivery marks the announcement as notified if nobody else has.
+func (r *announcementRepository) ClaimDelivery(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE campaign_announcements SET notified_at = NOW() WHERE id = ? AND notified_at IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("claiming announcement delivery: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claiming announcement delivery: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,298 @@
+package campaigns
+
+// announcement_service.go — owner announcements on the campaign dashboard.
+// A post can be pinned above the rest and scheduled for later; members
+// don't see it until publish_at. Members are notified once per post, when
+// it publishes: straight away from Create for an immediate post, or from
+// AnnouncementDeliveryJob for a scheduled one. ClaimDelivery makes that
+// hand-off safe when both race (or several app instances run the job).
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// announcementDashboardLimit caps how many posts the dashboard card
+	// shows; the rest are on the announcements page.
+	announcementDashboardLimit = 5
+
+	// announcementDeliveryBatchSize bounds one delivery pass.
+	announcementDeliveryBatchSize = 50
+
+	// announcementDeliveryInterval is how often scheduled posts are checked.
+	// A minute keeps "publish at 19:00" honest without hammering the table.
+	announcementDeliveryInterval = time.Minute
+)
+
+// AnnouncementService handles business logic for campaign announcements.
+type AnnouncementService interface {
+	// List returns the campaign's announcements for a viewer. Owners also
+	// see scheduled posts.
+	List(ctx context.Context, campaignID, userID string, role Role) ([]Announcement, error)
+
+	// ListForDashboard returns the pinned and unread published posts shown
+	// on the campaign dashboard.
+	ListForDashboard(ctx context.Context, campaignID, userID string) ([]Announcement, error)
+
+	Get(ctx context.Context, campaignID, id, userID string, role Role) (*Announcement, error)
+	Create(ctx context.Context, campaignID, createdBy string, in AnnouncementInput) (*Announcement, error)
+	Update(ctx context.Context, campaignID, id string, in AnnouncementInput) (*Announcement, error)
+	Delete(ctx context.Context, campaignID, id string) error
+	MarkRead(ctx context.Context, campaignID, id, userID string) error
+
+	// DeliverDue notifies members of every post that has published since
+	// the last pass and returns how many posts it sent.
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+
+	// SetNotifier wires in-app notifications; nil leaves posts unannounced.
+	SetNotifier(n UserNotifier)
+}
+
+// announcementService implements AnnouncementService.
+type announcementService struct {
+	repo     AnnouncementRepository
+	members  MemberLister
+	notifier UserNotifier
+	now      func() time.Time
+}
+
+// NewAnnouncementService creates a new announcement service.
+func NewAnnouncementService(repo AnnouncementRepository, members MemberLister) AnnouncementService {
+	return &announcementService{repo: repo, members: members, now: time.Now}
+}
+
+// SetNotifier sets the in-app notification sink.
+func (s *announcementService) SetNotifier(n UserNotifier) {
+	s.notifier = n
+}
+
+// List returns the campaign's announcements for a viewer.
+func (s *announcementService) List(ctx context.Context, campaignID, userID string, role Role) ([]Announcement, error) {
+	return s.repo.ListByCampaign(ctx, campaignID, userID, role >= RoleOwner, s.now())
+}
+
+// ListForDashboard keeps pinned posts up until they're unpinned, and shows
+// the rest until the member has read them.
+func (s *announcementService) ListForDashboard(ctx context.Context, campaignID, userID string) ([]Announcement, error) {
+	all, err := s.repo.ListByCampaign(ctx, campaignID, userID, false, s.now())
+	if err != nil {
+		return nil, err
+	}
+	var list []Announcement
+	for _, a := range all {
+		if !a.Pinned && a.Read {
+			continue
+		}
+		list = append(list, a)
+		if len(list) == announcementDashboardLimit {
+			break
+		}
+	}
+	return list, nil
+}
+
+// Get returns one announcement. A scheduled post is not found for anyone
+// but the owner, so its ID can't be probed early.
+func (s *announcementService) Get(ctx context.Context, campaignID, id, userID string, role Role) (*Announcement, error) {
+	a, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if a.CampaignID != campaignID || (role < RoleOwner && !a.IsPublishedAt(s.now())) {
+		return nil, apperror.NewNotFound("announcement not found")
+	}
+	return a, nil
+}
+
+// Create saves a new announcement and, if it publishes now, notifies members.
+func (s *announcementService) Create(ctx context.Context, campaignID, createdBy string, in AnnouncementInput) (*Announcement, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+	bodyHTML, err := renderAnnouncementBody(in.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Announcement{
+		ID:         generateUUID(),
+		CampaignID: campaignID,
+		Title:      in.Title,
+		Body:       in.Body,
+		BodyHTML:   bodyHTML,
+		Pinned:     in.Pinned,
+		PublishAt:  s.now().UTC().Truncate(time.Second),
+		CreatedBy:  createdBy,
+	}
+	if in.PublishAt != nil {
+		a.PublishAt = *in.PublishAt
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+
+	if a.IsPublishedAt(s.now()) {
+		s.deliver(ctx, a)
+	}
+	return a, nil
+}
+
+// Update edits an announcement. Rescheduling an already-sent post doesn't
+// send it again.
+func (s *announcementService) Update(ctx context.Context, campaignID, id string, in AnnouncementInput) (*Announcement, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.FindByID(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	if a.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("announcement not found")
+	}
+	bodyHTML, err := renderAnnouncementBody(in.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Title, a.Body, a.BodyHTML, a.Pinned = in.Title, in.Body, bodyHTML, in.Pinned
+	if in.PublishAt != nil {
+		a.PublishAt = *in.PublishAt
+	}
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+
+	// Moving a scheduled post to now publishes it.
+	if a.NotifiedAt == nil && a.IsPublishedAt(s.now()) {
+		s.deliver(ctx, a)
+	}
+	return a, nil
+}
+
+// Delete removes an announcement.
+func (s *announcementService) Delete(ctx context.Context, campaignID, id string) error {
+	a, err := s.repo.FindByID(ctx, id, "")
+	if err != nil {
+		return err
+	}
+	if a.CampaignID != campaignID {
+		return apperror.NewNotFound("announcement not found")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// MarkRead records that the member has read a published announcement.
+func (s *announcementService) MarkRead(ctx context.Context, campaignID, id, userID string) error {
+	if _, err := s.Get(ctx, campaignID, id, userID, RolePlayer); err != nil {
+		return err
+	}
+	return s.repo.MarkRead(ctx, id, userID)
+}
+
+// DeliverDue sends every post that has published but not been sent.
+func (s *announcementService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDueDelivery(ctx, now, announcementDeliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if s.deliver(ctx, &due[i]) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver claims the post and notifies every member but its author. It
+// reports whether this call sent it. Notification failures are logged
+// rather than returned: the post is saved either way and members still
+// see it on the dashboard.
+func (s *announcementService) deliver(ctx context.Context, a *Announcement) bool {
+	claimed, err := s.repo.ClaimDelivery(ctx, a.ID)
+	if err != nil {
+		slog.Warn("announcement delivery claim failed", slog.String("announcement_id", a.ID), slog.Any("error", err))
+		return false
+	}
+	if !claimed || s.notifier == nil || s.members == nil {
+		return claimed
+	}
+
+	members, err := s.members.ListMembers(ctx, a.CampaignID)
+	if err != nil {
+		slog.Warn("listing members for announcement failed", slog.String("announcement_id", a.ID), slog.Any("error", err))
+		return true
+	}
+	message := fmt.Sprintf("New announcement: %s", a.Title)
+	link := fmt.Sprintf("/campaigns/%s/announcements/%s", a.CampaignID, a.ID)
+	for _, m := range members {
+		if m.UserID == a.CreatedBy {
+			continue
+		}
+		if err := s.notifier.NotifyUser(ctx, m.UserID, a.CampaignID, NotifAnnouncement, message, link); err != nil {
+			slog.Warn("notification failed", slog.String("kind", NotifAnnouncement), slog.Any("error", err))
+		}
+	}
+	return true
+}
+
+// AnnouncementDeliverer is the slice of AnnouncementService the delivery
+// job needs.
+type AnnouncementDeliverer interface {
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// AnnouncementDeliveryJob notifies members when scheduled announcements
+// publish.
+type AnnouncementDeliveryJob struct {
+	deliverer AnnouncementDeliverer
+	now       func() time.Time
+}
+
+// NewAnnouncementDeliveryJob creates the delivery job.
+func NewAnnouncementDeliveryJob(d AnnouncementDeliverer) *AnnouncementDeliveryJob {
+	return &AnnouncementDeliveryJob{deliverer: d, now: time.Now}
+}
+
+// Run delivers every due announcement, batch by batch.
+func (j *AnnouncementDeliveryJob) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := j.deliverer.DeliverDue(ctx, j.now())
+		total += n
+		if err != nil || n < announcementDeliveryBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job every minute until ctx is cancelled.
+func (j *AnnouncementDeliveryJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(announcementDeliveryInterval)
+	defer ticker.Stop()
+
+	slog.Info("announcement delivery worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("announcement delivery worker stopped")
+			return
+		case <-ticker.C:
+			n, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("announcement delivery run failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("announcements delivered", slog.Int("announcements", n))
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package campaigns
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeAnnouncementRepo is an in-memory AnnouncementRepository.
+type fakeAnnouncementRepo struct {
+	AnnouncementRepository // embed to satisfy interface
+	items                  map[string]*Announcement
+	reads                  map[string]bool // announcementID + "/" + userID
+	now                    func() time.Time
+}
+
+func newFakeAnnouncementRepo(now func() time.Time) *fakeAnnouncementRepo {
+	return &fakeAnnouncementRepo{items: map[string]*Announcement{}, reads: map[string]bool{}, now: now}
+}
+
+func (r *fakeAnnouncementRepo) ListByCampaign(_ context.Context, campaignID, userID string, includeScheduled bool, now time.Time) ([]Announcement, error) {
+	var out []Announcement
+	for _, a := range r.items {
+		if a.CampaignID != campaignID || (!includeScheduled && !a.IsPublishedAt(now)) {
+			continue
+		}
+		c := *a
+		c.Read = r.reads[a.ID+"/"+userID]
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (r *fakeAnnouncementRepo) FindByID(_ context.Context, id, userID string) (*Announcement, error) {
+	a, ok := r.items[id]
+	if !ok {
+		return nil, apperror.NewNotFound("announcement not found")
+	}
+	c := *a
+	c.Read = r.reads[id+"/"+userID]
+	return &c, nil
+}
+
+func (r *fakeAnnouncementRepo) Create(_ context.Context, a *Announcement) error {
+	c := *a
+	r.items[a.ID] = &c
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) Update(_ context.Context, a *Announcement) error {
+	stored := r.items[a.ID]
+	stored.Title, stored.Body, stored.BodyHTML, stored.Pinned, stored.PublishAt = a.Title, a.Body, a.BodyHTML, a.Pinned, a.PublishAt
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) MarkRead(_ context.Context, id, userID string) error {
+	r.reads[id+"/"+userID] = true
+	return nil
+}
+
+func (r *fakeAnnouncementRepo) ListDueDelivery(_ context.Context, now time.Time, _ int) ([]Announcement, error) {
+	var out []Announcement
+	for _, a := range r.items {
+		if a.NotifiedAt == nil && a.IsPublishedAt(now) {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAnnouncementRepo) ClaimDelivery(_ context.Context, id string) (bool, error) {
+	a := r.items[id]
+	if a.NotifiedAt != nil {
+		return false, nil
+	}
+	t := r.now()
+	a.NotifiedAt = &t
+	return true, nil
+}
+
+type fakeMemberLister struct{ members []CampaignMember }
+
+func (f *fakeMemberLister) ListMembers(context.Context, string) ([]CampaignMember, error) {
+	return f.members, nil
+}
+
+// recordingNotifier collects the users notified.
+type recordingNotifier struct{ to []string }
+
+func (n *recordingNotifier) NotifyUser(_ context.Context, userID, _, kind, _, _ string) error {
+	if kind == NotifAnnouncement {
+		n.to = append(n.to, userID)
+	}
+	return nil
+}
+
+func newTestAnnouncementService(now time.Time) (*announcementService, *fakeAnnouncementRepo, *recordingNotifier) {
+	clock := func() time.Time { return now }
+	repo := newFakeAnnouncementRepo(clock)
+	members := &fakeMemberLister{members: []CampaignMember{{UserID: "owner"}, {UserID: "p1"}, {UserID: "p2"}}}
+	svc := NewAnnouncementService(repo, members).(*announcementService)
+	svc.now = clock
+	n := &recordingNotifier{}
+	svc.SetNotifier(n)
+	return svc, repo, n
+}
+
+func TestRenderAnnouncementBody(t *testing.T) {
+	html, err := renderAnnouncementBody("Session **moved** to Friday.\n<script>alert(1)</script>\n\n[map](javascript:alert(1))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "<strong>moved</strong>") {
+		t.Errorf("markdown not rendered: %s", html)
+	}
+	if strings.Contains(html, "<script") || strings.Contains(html, "javascript:") {
+		t.Errorf("unsafe markup survived: %s", html)
+	}
+}
+
+func TestAnnouncementService_CreateNotifiesOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, n := newTestAnnouncementService(now)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "camp-1", "owner", AnnouncementInput{Title: "  Session moved  "}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if strings.Join(n.to, ",") != "p1,p2" {
+		t.Errorf("notified %v; want every member but the author", n.to)
+	}
+
+	sent, err := svc.DeliverDue(ctx, now)
+	if err != nil || sent != 0 || len(n.to) != 2 {
+		t.Errorf("second delivery sent %d (%v), notified %v", sent, err, n.to)
+	}
+
+	_, err = svc.Create(ctx, "camp-1", "owner", AnnouncementInput{Title: "   "})
+	assertAppError(t, err, 400)
+}
+
+func TestAnnouncementService_Scheduled(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, n := newTestAnnouncementService(now)
+	ctx := context.Background()
+
+	later := now.Add(2 * time.Hour)
+	a, err := svc.Create(ctx, "camp-1", "owner", AnnouncementInput{Title: "Next arc", PublishAt: &later})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(n.to) != 0 {
+		t.Errorf("scheduled post notified early: %v", n.to)
+	}
+
+	_, err = svc.Get(ctx, "camp-1", a.ID, "p1", RolePlayer)
+	assertAppError(t, err, 404)
+	if _, err := svc.Get(ctx, "camp-1", a.ID, "owner", RoleOwner); err != nil {
+		t.Errorf("owner can't see own scheduled post: %v", err)
+	}
+	_, err = svc.Get(ctx, "camp-2", a.ID, "owner", RoleOwner)
+	assertAppError(t, err, 404)
+	if list, _ := svc.List(ctx, "camp-1", "p1", RolePlayer); len(list) != 0 {
+		t.Errorf("player list includes scheduled post: %+v", list)
+	}
+
+	if sent, _ := svc.DeliverDue(ctx, now); sent != 0 {
+		t.Errorf("delivered %d before publish time", sent)
+	}
+	if sent, _ := svc.DeliverDue(ctx, later); sent != 1 || len(n.to) != 2 {
+		t.Errorf("delivered %d at publish time, notified %v", sent, n.to)
+	}
+}
+
+func TestAnnouncementService_ListForDashboard(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, _ := newTestAnnouncementService(now)
+	ctx := context.Background()
+
+	pinned, _ := svc.Create(ctx, "camp-1", "owner", AnnouncementInput{Title: "House rules", Pinned: true})
+	news, _ := svc.Create(ctx, "camp-1", "owner", AnnouncementInput{Title: "Session moved"})
+
+	for _, id := range []string{pinned.ID, news.ID} {
+		if err := svc.MarkRead(ctx, "camp-1", id, "p1"); err != nil {
+			t.Fatalf("MarkRead: %v", err)
+		}
+	}
+	list, err := svc.ListForDashboard(ctx, "camp-1", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != pinned.ID {
+		t.Errorf("dashboard = %+v; want only the pinned post once both are read", list)
+	}
+	if list, _ := svc.ListForDashboard(ctx, "camp-1", "p2"); len(list) != 2 {
+		t.Errorf("unread member sees %d posts, want 2", len(list))
+	}
+}
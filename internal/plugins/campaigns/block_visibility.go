@@ -0,0 +1,143 @@
+package campaigns
+
+// block_visibility.go — per-block visibility rules shared by campaign
+// dashboards, category dashboards and entity page layouts. A block's
+// config may carry:
+//
+//	visibility:     "everyone" (default), "members", "scribe" or "dm_only"
+//	visible_groups: campaign group IDs; other viewers must be in one
+//
+// Rules are applied while rendering, so a hidden block never reaches the
+// viewer's HTML. Owners and DM-granted members see every block.
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// Block visibility levels stored in a block's config["visibility"].
+const (
+	BlockVisibilityEveryone = "everyone"
+	BlockVisibilityMembers  = "members" // any campaign member, hidden from the public
+	BlockVisibilityScribe   = "scribe"
+	BlockVisibilityDMOnly   = "dm_only"
+)
+
+// BlockMinRole returns the lowest role that may see a block.
+func BlockMinRole(config map[string]any) Role {
+	v, _ := config["visibility"].(string)
+	switch v {
+	case BlockVisibilityMembers:
+		return RolePlayer
+	case BlockVisibilityScribe:
+		return RoleScribe
+	case BlockVisibilityDMOnly:
+		return RoleOwner
+	}
+	return RoleNone
+}
+
+// BlockGroupIDs returns the groups a block is limited to, if any. JSON
+// numbers decode as float64; ints come from layouts built in Go.
+func BlockGroupIDs(config map[string]any) []int {
+	raw, ok := config["visible_groups"].([]any)
+	if !ok {
+		if ids, ok := config["visible_groups"].([]int); ok {
+			return ids
+		}
+		return nil
+	}
+	ids := make([]int, 0, len(raw))
+	for _, v := range raw {
+		switch n := v.(type) {
+		case float64:
+			ids = append(ids, int(n))
+		case int:
+			ids = append(ids, n)
+		}
+	}
+	return ids
+}
+
+// viewerGroupsKey carries the viewer's group IDs on the request context.
+type viewerGroupsKey struct{}
+
+// WithViewerGroups records the viewer's campaign group IDs for block
+// visibility checks made while rendering.
+func WithViewerGroups(ctx context.Context, groupIDs []int) context.Context {
+	set := make(map[int]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		set[id] = true
+	}
+	return context.WithValue(ctx, viewerGroupsKey{}, set)
+}
+
+// BlockVisibleTo reports whether the viewer may see a block. Group rules
+// need WithViewerGroups on ctx; without it a group-limited block is hidden.
+func BlockVisibleTo(ctx context.Context, cc *CampaignContext, config map[string]any) bool {
+	role := Role(cc.VisibilityRole())
+	if role >= RoleOwner {
+		return true
+	}
+	if role < BlockMinRole(config) {
+		return false
+	}
+	groups := BlockGroupIDs(config)
+	if len(groups) == 0 {
+		return true
+	}
+	in, _ := ctx.Value(viewerGroupsKey{}).(map[int]bool)
+	for _, id := range groups {
+		if in[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// DashboardHasGroupRules reports whether any block is limited to groups,
+// so the viewer's groups are only looked up when a rule needs them.
+func DashboardHasGroupRules(layout *DashboardLayout) bool {
+	if layout == nil {
+		return false
+	}
+	for _, row := range layout.Rows {
+		for _, col := range row.Columns {
+			for _, b := range col.Blocks {
+				if len(BlockGroupIDs(b.Config)) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ViewerGroupLister lists the campaign groups a user belongs to.
+// Implemented by GroupService.
+type ViewerGroupLister interface {
+	ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error)
+}
+
+// LoadViewerGroups puts the signed-in viewer's groups on the request
+// context for BlockVisibleTo. Call it before rendering a layout that has
+// group rules; owners skip the lookup since they see every block.
+func LoadViewerGroups(c echo.Context, cc *CampaignContext, lister ViewerGroupLister) {
+	userID := auth.GetUserID(c)
+	if lister == nil || userID == "" || Role(cc.VisibilityRole()) >= RoleOwner {
+		return
+	}
+	ids, err := lister.ListUserGroupIDs(c.Request().Context(), cc.Campaign.ID, userID)
+	if err != nil {
+		// Fail closed: group-limited blocks stay hidden.
+		slog.Warn("loading viewer groups for block visibility failed",
+			slog.String("campaign_id", cc.Campaign.ID),
+			slog.Any("error", err))
+		return
+	}
+	c.SetRequest(c.Request().WithContext(WithViewerGroups(c.Request().Context(), ids)))
+}
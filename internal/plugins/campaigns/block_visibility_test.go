@@ -0,0 +1,71 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestBlockVisibleTo_MinRole(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		vis  string
+		role Role
+		want bool
+	}{
+		{"", RoleNone, true},
+		{BlockVisibilityMembers, RoleNone, false},
+		{BlockVisibilityMembers, RolePlayer, true},
+		{BlockVisibilityScribe, RolePlayer, false},
+		{BlockVisibilityScribe, RoleScribe, true},
+		{BlockVisibilityDMOnly, RoleScribe, false},
+		{BlockVisibilityDMOnly, RoleOwner, true},
+	} {
+		cc := &CampaignContext{MemberRole: tc.role}
+		if got := BlockVisibleTo(ctx, cc, map[string]any{"visibility": tc.vis}); got != tc.want {
+			t.Errorf("visibility %q, role %d: got %v, want %v", tc.vis, tc.role, got, tc.want)
+		}
+	}
+}
+
+func TestBlockVisibleTo_DmGrantSeesAll(t *testing.T) {
+	cc := &CampaignContext{MemberRole: RolePlayer, IsDmGranted: true}
+	config := map[string]any{"visibility": BlockVisibilityDMOnly, "visible_groups": []any{float64(9)}}
+	if !BlockVisibleTo(context.Background(), cc, config) {
+		t.Error("DM-granted member should see a DM-only, group-limited block")
+	}
+}
+
+func TestBlockVisibleTo_Groups(t *testing.T) {
+	var config map[string]any
+	if err := json.Unmarshal([]byte(`{"visible_groups":[3,5]}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	cc := &CampaignContext{MemberRole: RolePlayer}
+
+	if BlockVisibleTo(context.Background(), cc, config) {
+		t.Error("group-limited block shown without the viewer's groups loaded")
+	}
+	if BlockVisibleTo(WithViewerGroups(context.Background(), []int{4}), cc, config) {
+		t.Error("group-limited block shown to a non-member")
+	}
+	if !BlockVisibleTo(WithViewerGroups(context.Background(), []int{4, 5}), cc, config) {
+		t.Error("group-limited block hidden from a group member")
+	}
+	if !BlockVisibleTo(context.Background(), &CampaignContext{MemberRole: RoleOwner}, config) {
+		t.Error("owner should see every block")
+	}
+}
+
+func TestDashboardHasGroupRules(t *testing.T) {
+	layout := &DashboardLayout{Rows: []DashboardRow{{Columns: []DashboardColumn{{Blocks: []DashboardBlock{
+		{Type: BlockWelcomeBanner, Config: map[string]any{"visibility": BlockVisibilityScribe}},
+	}}}}}}
+	if DashboardHasGroupRules(layout) || DashboardHasGroupRules(nil) {
+		t.Error("no group rules expected")
+	}
+	layout.Rows[0].Columns[0].Blocks[0].Config["visible_groups"] = []int{2}
+	if !DashboardHasGroupRules(layout) {
+		t.Error("group rule not detected")
+	}
+}
@@ -0,0 +1,155 @@
+package campaigns
+
+// create_addons.go — choosing extensions when a campaign is created. The
+// create form lists the installed non-system addons with a preset picked
+// by the genre; whatever the GM leaves ticked is enabled before the
+// campaign is handed back, so a new campaign starts with only the
+// features they asked for. Game systems are chosen separately (Settings >
+// General) because they're mutually exclusive and seed presets.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// genreDefaultAddons is the extension preset for each creation genre. ""
+// is the Standard template. Slugs that aren't installed are dropped when
+// the form is built.
+var genreDefaultAddons = map[string][]string{
+	"":           {"calendar", "maps", "sessions", "notes", "attributes"},
+	"fantasy":    {"calendar", "maps", "sessions", "npcs", "armory", "notes", "attributes"},
+	"sci-fi":     {"maps", "sessions", "npcs", "notes", "attributes"},
+	"horror":     {"calendar", "sessions", "npcs", "notes", "attributes"},
+	"modern":     {"maps", "sessions", "npcs", "notes", "attributes"},
+	"historical": {"calendar", "maps", "timeline", "sessions", "notes", "attributes"},
+}
+
+// defaultAddonsForGenre returns the extension preset for a creation
+// genre, falling back to the Standard preset for unknown genres.
+func defaultAddonsForGenre(genre string) []string {
+	if slugs, ok := genreDefaultAddons[genre]; ok {
+		return slugs
+	}
+	return genreDefaultAddons[""]
+}
+
+// CampaignAddonEnabler enables a set of addons for a freshly created
+// campaign. Implementations validate every slug before enabling any, so a
+// bad selection leaves nothing behind. Wired in internal/app/routes.go.
+type CampaignAddonEnabler interface {
+	EnableAddonsForNewCampaign(ctx context.Context, campaignID, userID string, slugs []string) error
+}
+
+// SetAddonEnabler sets the enabler for the create form's extension
+// selection. Called after all plugins are wired to avoid initialization
+// order issues.
+func (s *campaignService) SetAddonEnabler(enabler CampaignAddonEnabler) {
+	s.addonEnabler = enabler
+}
+
+// enableCreationAddons enables the addons picked on the create form. On
+// failure the half-made campaign is deleted (members, seeded types and
+// addon rows cascade with it) so creation is all-or-nothing.
+func (s *campaignService) enableCreationAddons(ctx context.Context, campaign *Campaign, userID string, slugs []string) error {
+	if len(slugs) == 0 || s.addonEnabler == nil {
+		return nil
+	}
+	err := s.addonEnabler.EnableAddonsForNewCampaign(ctx, campaign.ID, userID, dedupeSlugs(slugs))
+	if err == nil {
+		return nil
+	}
+	if delErr := s.repo.Delete(ctx, campaign.ID); delErr != nil {
+		slog.Error("failed to roll back campaign after addon enable error",
+			slog.String("campaign_id", campaign.ID),
+			slog.Any("error", delErr),
+		)
+	}
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return apperror.NewInternal(fmt.Errorf("enabling extensions: %w", err))
+}
+
+// dedupeSlugs drops empty and repeated slugs, keeping the first order.
+func dedupeSlugs(slugs []string) []string {
+	seen := make(map[string]bool, len(slugs))
+	out := make([]string, 0, len(slugs))
+	for _, s := range slugs {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+// creationAddons returns the addons offered on the create form: installed,
+// non-system ones. A nil lister or a lookup error offers none, and the
+// campaign is created without extensions as before.
+func (h *Handler) creationAddons(ctx context.Context) []PluginHubAddon {
+	if h.addonLister == nil {
+		return nil
+	}
+	all, err := h.addonLister.ListForPluginHub(ctx, "")
+	if err != nil {
+		slog.Warn("listing addons for campaign create form", slog.Any("error", err))
+		return nil
+	}
+	out := make([]PluginHubAddon, 0, len(all))
+	for _, a := range all {
+		if a.Installed && a.Category != "system" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// creationAddonState is the create form's Alpine state: the genre, the
+// ticked slugs and every genre's preset limited to the offered addons.
+// A re-render after an error keeps the GM's own ticks.
+func creationAddonState(req *CreateCampaignRequest, offered []PluginHubAddon) string {
+	have := make(map[string]bool, len(offered))
+	for _, a := range offered {
+		have[a.Slug] = true
+	}
+	offeredOnly := func(slugs []string) []string {
+		list := []string{}
+		for _, s := range slugs {
+			if have[s] {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	presets := make(map[string][]string, len(genreDefaultAddons))
+	for genre, slugs := range genreDefaultAddons {
+		presets[genre] = offeredOnly(slugs)
+	}
+
+	genre := ""
+	if req != nil {
+		genre = req.Genre
+	}
+	selected := offeredOnly(defaultAddonsForGenre(genre))
+	if req != nil && req.Addons != nil {
+		selected = req.Addons
+	}
+
+	state := map[string]any{
+		"genre":   genre,
+		"addons":  selected,
+		"presets": presets,
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return "{ genre: '', addons: [], presets: {} }"
+	}
+	return string(b)
+}
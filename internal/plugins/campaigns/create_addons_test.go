@@ -0,0 +1,107 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+type mockAddonEnabler struct {
+	gotSlugs []string
+	err      error
+}
+
+func (m *mockAddonEnabler) EnableAddonsForNewCampaign(_ context.Context, _, _ string, slugs []string) error {
+	m.gotSlugs = slugs
+	return m.err
+}
+
+func TestCreate_EnablesPickedAddons(t *testing.T) {
+	svc := newTestCampaignService(&mockCampaignRepo{}, &mockUserFinder{})
+	enabler := &mockAddonEnabler{}
+	svc.SetAddonEnabler(enabler)
+
+	_, err := svc.Create(context.Background(), "user-1", CreateCampaignInput{
+		Name:   "Test",
+		Addons: []string{"maps", "", "calendar", "maps"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(enabler.gotSlugs) != 2 || enabler.gotSlugs[0] != "maps" || enabler.gotSlugs[1] != "calendar" {
+		t.Errorf("enabled %v, want [maps calendar]", enabler.gotSlugs)
+	}
+}
+
+func TestCreate_NoAddonsSkipsEnabler(t *testing.T) {
+	svc := newTestCampaignService(&mockCampaignRepo{}, &mockUserFinder{})
+	enabler := &mockAddonEnabler{err: errors.New("should not be called")}
+	svc.SetAddonEnabler(enabler)
+
+	if _, err := svc.Create(context.Background(), "user-1", CreateCampaignInput{Name: "Test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if enabler.gotSlugs != nil {
+		t.Errorf("enabler called with %v", enabler.gotSlugs)
+	}
+}
+
+func TestCreate_AddonFailureRollsBackCampaign(t *testing.T) {
+	var deletedID string
+	repo := &mockCampaignRepo{
+		deleteFn: func(_ context.Context, id string) error {
+			deletedID = id
+			return nil
+		},
+	}
+	svc := newTestCampaignService(repo, &mockUserFinder{})
+	svc.SetAddonEnabler(&mockAddonEnabler{err: apperror.NewBadRequest("unknown extension")})
+
+	campaign, err := svc.Create(context.Background(), "user-1", CreateCampaignInput{Name: "Test", Addons: []string{"nope"}})
+	assertAppError(t, err, 400)
+	if campaign != nil {
+		t.Error("expected no campaign")
+	}
+	if deletedID == "" {
+		t.Error("half-made campaign was not deleted")
+	}
+
+	// Non-AppErrors surface as internal errors.
+	svc.SetAddonEnabler(&mockAddonEnabler{err: errors.New("db down")})
+	_, err = svc.Create(context.Background(), "user-1", CreateCampaignInput{Name: "Test", Addons: []string{"maps"}})
+	assertAppError(t, err, 500)
+}
+
+func TestCreationAddonState(t *testing.T) {
+	offered := []PluginHubAddon{{Slug: "calendar"}, {Slug: "maps"}, {Slug: "notes"}}
+
+	decode := func(raw string) (genre string, addons []string, presets map[string][]string) {
+		var st struct {
+			Genre   string              `json:"genre"`
+			Addons  []string            `json:"addons"`
+			Presets map[string][]string `json:"presets"`
+		}
+		if err := json.Unmarshal([]byte(raw), &st); err != nil {
+			t.Fatalf("state is not JSON: %v", err)
+		}
+		return st.Genre, st.Addons, st.Presets
+	}
+
+	// Fresh form: Standard preset, limited to what's offered.
+	_, addons, presets := decode(creationAddonState(nil, offered))
+	if len(addons) != 3 {
+		t.Errorf("standard preset = %v, want calendar, maps, notes", addons)
+	}
+	if got := presets["sci-fi"]; len(got) != 2 || got[0] != "maps" || got[1] != "notes" {
+		t.Errorf("sci-fi preset = %v, want [maps notes]", got)
+	}
+
+	// Re-render after an error keeps the GM's ticks.
+	genre, addons, _ := decode(creationAddonState(&CreateCampaignRequest{Genre: "horror", Addons: []string{"maps"}}, offered))
+	if genre != "horror" || len(addons) != 1 || addons[0] != "maps" {
+		t.Errorf("re-render = %q %v, want horror [maps]", genre, addons)
+	}
+}
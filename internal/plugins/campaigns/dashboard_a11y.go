@@ -0,0 +1,22 @@
+package campaigns
+
+import "github.com/keyxmakerx/chronicle/internal/a11y"
+
+// AuditDashboardLayout runs the accessibility checks over every block in a
+// dashboard layout. The result is never nil so save responses always carry
+// a "warnings" array. Exported for the entities plugin's category
+// dashboards, which share this layout type.
+func AuditDashboardLayout(layout *DashboardLayout) []a11y.Warning {
+	warnings := []a11y.Warning{}
+	if layout == nil {
+		return warnings
+	}
+	for _, row := range layout.Rows {
+		for _, col := range row.Columns {
+			for _, b := range col.Blocks {
+				warnings = append(warnings, a11y.CheckBlock(b.ID, b.Type, b.Config)...)
+			}
+		}
+	}
+	return warnings
+}
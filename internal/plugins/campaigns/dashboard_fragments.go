@@ -0,0 +1,269 @@
+package campaigns
+
+// dashboard_fragments.go — lazy-loaded campaign dashboard blocks. A block
+// type with a registered DashboardFragment renders as a placeholder on the
+// dashboard page, and its body is fetched over HTMX from
+//
+//	GET /campaigns/:id/dashboard/blocks/:bid
+//
+// where :bid is the block's ID in the viewer's dashboard layout (or the
+// owner dashboard, for owners). The block is looked up server-side, so its
+// config can't be forged from the query string and block visibility rules
+// still apply. Plugins register bodies
+// for their own block types with RegisterDashboardFragment.
+//
+// Rendered bodies are cached in Redis per block, per config and per viewer
+// (or per role for Shared fragments) with stale-while-revalidate: a body
+// older than Fresh but within Stale is served as-is while one request
+// re-renders it in the background.
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// fragmentRefreshTimeout bounds a background re-render of a stale body.
+const fragmentRefreshTimeout = 30 * time.Second
+
+// DashboardFragmentRequest is what a fragment renderer gets: the campaign
+// as the viewer sees it, the viewer ("" when logged out) and the block
+// with its saved config.
+type DashboardFragmentRequest struct {
+	Campaign *CampaignContext
+	UserID   string
+	Block    DashboardBlock
+}
+
+// DashboardFragment renders the body of one dashboard block type.
+//
+//   - Render loads the block's data and returns its markup. ctx may outlive
+//     the request (background refresh), so it must not depend on anything
+//     but its values.
+//   - Fresh is how long a rendered body is served from cache; zero turns
+//     caching off for the type.
+//   - Stale is how much longer an expired body may still be served while
+//     it is re-rendered in the background.
+//   - Shared caches one body per role rather than per viewer. Only for
+//     blocks whose content depends on nothing but the viewer's role.
+type DashboardFragment struct {
+	Render func(ctx context.Context, req DashboardFragmentRequest) (templ.Component, error)
+	Fresh  time.Duration
+	Stale  time.Duration
+	Shared bool
+}
+
+// RegisterDashboardFragment makes blockType lazy-loaded on the campaign
+// dashboard. Called at startup by the plugin that owns the block type; a
+// later registration for the same type replaces the earlier one.
+func (h *Handler) RegisterDashboardFragment(blockType string, f DashboardFragment) {
+	if h.fragments == nil {
+		h.fragments = make(map[string]DashboardFragment)
+	}
+	h.fragments[blockType] = f
+}
+
+// SetFragmentCache enables Redis caching of dashboard fragments. Without
+// it every fragment request renders.
+func (h *Handler) SetFragmentCache(rdb *redis.Client) {
+	h.fragmentCache = rdb
+}
+
+// DashboardFragmentURL is the standard fragment route for a block.
+func DashboardFragmentURL(campaignID, blockID string) string {
+	return fmt.Sprintf("/campaigns/%s/dashboard/blocks/%s", campaignID, blockID)
+}
+
+// lazyBlocksKey carries the lazy block types on the request context so the
+// dashboard templates can tell which blocks to defer.
+type lazyBlocksKey struct{}
+
+// withLazyDashboardBlocks records which block types have fragments.
+func withLazyDashboardBlocks(ctx context.Context, fragments map[string]DashboardFragment) context.Context {
+	return context.WithValue(ctx, lazyBlocksKey{}, fragments)
+}
+
+// isLazyDashboardBlock reports whether blockType loads as a fragment.
+func isLazyDashboardBlock(ctx context.Context, blockType string) bool {
+	fragments, _ := ctx.Value(lazyBlocksKey{}).(map[string]DashboardFragment)
+	_, ok := fragments[blockType]
+	return ok
+}
+
+// findDashboardBlock returns the block with the given ID, or nil.
+func findDashboardBlock(layout *DashboardLayout, blockID string) *DashboardBlock {
+	for _, row := range layout.Rows {
+		for _, col := range row.Columns {
+			for i := range col.Blocks {
+				if col.Blocks[i].ID == blockID {
+					return &col.Blocks[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DashboardBlockFragment renders the body of one lazy dashboard block
+// (GET /campaigns/:id/dashboard/blocks/:bid).
+func (h *Handler) DashboardBlockFragment(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	layout := cc.Campaign.ParseRoleDashboardLayout(cc.MemberRole)
+	if layout == nil {
+		layout = DefaultDashboardLayout()
+	}
+	block := findDashboardBlock(layout, c.Param("bid"))
+	if block == nil && cc.MemberRole >= RoleOwner {
+		// Owner dashboard blocks load through the same route.
+		if owner := cc.Campaign.ParseOwnerDashboardLayout(); owner != nil {
+			block = findDashboardBlock(owner, c.Param("bid"))
+		}
+	}
+	if block == nil {
+		return apperror.NewNotFound("dashboard block not found")
+	}
+	frag, ok := h.fragments[block.Type]
+	if !ok {
+		return apperror.NewNotFound("dashboard block not found")
+	}
+	if len(BlockGroupIDs(block.Config)) > 0 {
+		LoadViewerGroups(c, cc, h.groupSvc)
+	}
+	if !BlockVisibleTo(c.Request().Context(), cc, block.Config) {
+		return apperror.NewNotFound("dashboard block not found")
+	}
+
+	req := DashboardFragmentRequest{Campaign: cc, UserID: auth.GetUserID(c), Block: *block}
+	body, status, err := h.fragmentBody(c.Request().Context(), frag, req)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set("X-Fragment-Cache", status)
+	return c.HTMLBlob(http.StatusOK, body)
+}
+
+// cachedFragment is a rendered body as stored in Redis.
+type cachedFragment struct {
+	HTML       []byte    `json:"html"`
+	RenderedAt time.Time `json:"rendered_at"`
+}
+
+// fragmentCacheKey identifies one cached body. The config hash means an
+// edited block never serves its old body; the scope is the viewer, or the
+// role for shared fragments.
+func fragmentCacheKey(req DashboardFragmentRequest, shared bool) string {
+	raw, _ := json.Marshal(req.Block)
+	sum := sha256.Sum256(raw)
+	scope := "u:" + req.UserID
+	if shared || req.UserID == "" {
+		scope = "r:" + strconv.Itoa(req.Campaign.VisibilityRole())
+	}
+	return fmt.Sprintf("dashfrag:%s:%s:%s:%s", req.Campaign.Campaign.ID, req.Block.ID, hex.EncodeToString(sum[:8]), scope)
+}
+
+// fragmentBody returns the block body and how it was served: "miss"
+// (rendered now), "hit" (fresh from cache) or "stale" (cached, refresh
+// started).
+func (h *Handler) fragmentBody(ctx context.Context, frag DashboardFragment, req DashboardFragmentRequest) ([]byte, string, error) {
+	if h.fragmentCache == nil || frag.Fresh <= 0 {
+		body, err := renderFragment(ctx, frag, req)
+		return body, "miss", err
+	}
+
+	key := fragmentCacheKey(req, frag.Shared)
+	if raw, err := h.fragmentCache.Get(ctx, key).Bytes(); err == nil {
+		var cached cachedFragment
+		if json.Unmarshal(raw, &cached) == nil {
+			age := time.Since(cached.RenderedAt)
+			if age < frag.Fresh {
+				return cached.HTML, "hit", nil
+			}
+			if age < frag.Fresh+frag.Stale {
+				h.refreshFragment(ctx, frag, req, key)
+				return cached.HTML, "stale", nil
+			}
+		}
+	}
+
+	body, err := renderFragment(ctx, frag, req)
+	if err != nil {
+		return nil, "", err
+	}
+	h.storeFragment(ctx, key, body, frag)
+	return body, "miss", nil
+}
+
+// refreshFragment re-renders a stale body in the background. A short Redis
+// lock keeps concurrent viewers from all re-rendering the same block.
+func (h *Handler) refreshFragment(ctx context.Context, frag DashboardFragment, req DashboardFragmentRequest, key string) {
+	ok, err := h.fragmentCache.SetNX(ctx, key+":refresh", 1, fragmentRefreshTimeout).Result()
+	if err != nil || !ok {
+		return
+	}
+	bg, cancel := context.WithTimeout(context.WithoutCancel(ctx), fragmentRefreshTimeout)
+	go func() {
+		defer cancel()
+		body, err := renderFragment(bg, frag, req)
+		if err != nil {
+			slog.Warn("dashboard fragment refresh failed",
+				slog.String("campaign_id", req.Campaign.Campaign.ID),
+				slog.String("block_type", req.Block.Type),
+				slog.Any("error", err))
+			return
+		}
+		h.storeFragment(bg, key, body, frag)
+	}()
+}
+
+// storeFragment caches a rendered body until its stale window ends. A
+// failed write only costs the next request a render.
+func (h *Handler) storeFragment(ctx context.Context, key string, body []byte, frag DashboardFragment) {
+	raw, err := json.Marshal(cachedFragment{HTML: body, RenderedAt: time.Now()})
+	if err != nil {
+		return
+	}
+	if err := h.fragmentCache.Set(ctx, key, raw, frag.Fresh+frag.Stale).Err(); err != nil {
+		slog.Warn("dashboard fragment cache write failed", slog.String("key", key), slog.Any("error", err))
+	}
+}
+
+// renderFragment runs a fragment renderer into a byte slice.
+func renderFragment(ctx context.Context, frag DashboardFragment, req DashboardFragmentRequest) ([]byte, error) {
+	component, err := frag.Render(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := component.Render(ctx, &buf); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("rendering %s dashboard block: %w", req.Block.Type, err))
+	}
+	return buf.Bytes(), nil
+}
+
+// recentPagesFragment renders the recent_pages block. Per viewer, since
+// page visibility can be granted to individual users.
+func (h *Handler) recentPagesFragment(ctx context.Context, req DashboardFragmentRequest) (templ.Component, error) {
+	cc := req.Campaign
+	entities, err := h.recentLister.ListRecentForDashboard(ctx, cc.Campaign.ID, int(cc.MemberRole), req.UserID, dashRecentLimit(req.Block.Config))
+	if err != nil {
+		return nil, err
+	}
+	return dashRecentPages(cc, entities), nil
+}
@@ -0,0 +1,116 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// countingFragment renders "render N" and counts its calls.
+func countingFragment(calls *atomic.Int32, fresh, stale time.Duration) DashboardFragment {
+	return DashboardFragment{
+		Render: func(_ context.Context, _ DashboardFragmentRequest) (templ.Component, error) {
+			n := calls.Add(1)
+			return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, "render "+string(rune('0'+n)))
+				return err
+			}), nil
+		},
+		Fresh: fresh,
+		Stale: stale,
+	}
+}
+
+func fragmentRequest(userID string) DashboardFragmentRequest {
+	return DashboardFragmentRequest{
+		Campaign: &CampaignContext{Campaign: &Campaign{ID: "c1"}, MemberRole: RolePlayer},
+		UserID:   userID,
+		Block:    DashboardBlock{ID: "b1", Type: BlockRecentPages},
+	}
+}
+
+func TestFragmentBody_FreshHitThenStaleRefresh(t *testing.T) {
+	mr := miniredis.RunT(t)
+	h := &Handler{fragmentCache: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+	var calls atomic.Int32
+	frag := countingFragment(&calls, time.Minute, time.Hour)
+	ctx := context.Background()
+	req := fragmentRequest("u1")
+
+	body, status, err := h.fragmentBody(ctx, frag, req)
+	if err != nil || status != "miss" || string(body) != "render 1" {
+		t.Fatalf("first = %q %s %v", body, status, err)
+	}
+	body, status, _ = h.fragmentBody(ctx, frag, req)
+	if status != "hit" || string(body) != "render 1" || calls.Load() != 1 {
+		t.Fatalf("second = %q %s, calls %d", body, status, calls.Load())
+	}
+
+	// Age the cached body past Fresh: served stale, refreshed behind it.
+	key := fragmentCacheKey(req, false)
+	raw, _ := json.Marshal(cachedFragment{HTML: []byte("old"), RenderedAt: time.Now().Add(-2 * time.Minute)})
+	mr.Set(key, string(raw))
+	body, status, _ = h.fragmentBody(ctx, frag, req)
+	if status != "stale" || string(body) != "old" {
+		t.Fatalf("stale = %q %s", body, status)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("background refresh did not run, calls %d", calls.Load())
+	}
+}
+
+func TestFragmentBody_NoCacheAlwaysRenders(t *testing.T) {
+	h := &Handler{}
+	var calls atomic.Int32
+	frag := countingFragment(&calls, time.Minute, time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, status, _ := h.fragmentBody(context.Background(), frag, fragmentRequest("u1")); status != "miss" {
+			t.Errorf("status = %s, want miss", status)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestFragmentCacheKey_Scope(t *testing.T) {
+	a, b := fragmentRequest("u1"), fragmentRequest("u2")
+	if fragmentCacheKey(a, false) == fragmentCacheKey(b, false) {
+		t.Error("per-viewer fragments share a cache key")
+	}
+	if fragmentCacheKey(a, true) != fragmentCacheKey(b, true) {
+		t.Error("shared fragments differ by viewer")
+	}
+	edited := fragmentRequest("u1")
+	edited.Block.Config = map[string]any{"limit": float64(4)}
+	if fragmentCacheKey(a, false) == fragmentCacheKey(edited, false) {
+		t.Error("config change kept the cache key")
+	}
+	if !strings.HasPrefix(fragmentCacheKey(a, false), "dashfrag:c1:b1:") {
+		t.Errorf("key = %s", fragmentCacheKey(a, false))
+	}
+}
+
+func TestIsLazyDashboardBlock(t *testing.T) {
+	h := &Handler{}
+	h.RegisterDashboardFragment(BlockCalendarPreview, DashboardFragment{})
+	ctx := withLazyDashboardBlocks(context.Background(), h.fragments)
+	if !isLazyDashboardBlock(ctx, BlockCalendarPreview) || isLazyDashboardBlock(ctx, BlockWelcomeBanner) {
+		t.Error("lazy block types wrong")
+	}
+	if isLazyDashboardBlock(context.Background(), BlockCalendarPreview) {
+		t.Error("no registry on context should render inline")
+	}
+}
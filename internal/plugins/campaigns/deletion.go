@@ -0,0 +1,146 @@
+package campaigns
+
+// deletion.go — two-step campaign deletion. An owner's delete only marks
+// the campaign (deletion_requested_at / purge_after); for the grace period
+// it is hidden from members, listings and the public, while the owner sees
+// a restore banner and site admins can restore it from /admin/campaigns. A
+// background job hard-deletes campaigns once their grace period ends,
+// through the same Delete path (media cleanup, WASM hooks, FK cascade) an
+// immediate delete used to take.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// DeletionGracePeriod is how long a marked campaign can be restored.
+	DeletionGracePeriod = 7 * 24 * time.Hour
+
+	// deletionPurgeBatchSize bounds how many campaigns one pass deletes;
+	// each delete walks the campaign's media on disk.
+	deletionPurgeBatchSize = 20
+
+	// deletionPurgeInterval is how often the purge job runs. Hourly keeps
+	// the actual purge close to the deadline the banner promised.
+	deletionPurgeInterval = time.Hour
+
+	// deletionPurgeStartDelay keeps the first run off the boot path.
+	deletionPurgeStartDelay = 5 * time.Minute
+)
+
+// MarkForDeletion starts the campaign's grace period and returns when it
+// will be purged.
+func (s *campaignService) MarkForDeletion(ctx context.Context, campaignID string) (time.Time, error) {
+	purgeAfter := time.Now().UTC().Add(DeletionGracePeriod)
+	if err := s.repo.MarkForDeletion(ctx, campaignID, purgeAfter); err != nil {
+		return time.Time{}, err
+	}
+	slog.Info("campaign marked for deletion",
+		slog.String("campaign_id", campaignID),
+		slog.Time("purge_after", purgeAfter),
+	)
+	return purgeAfter, nil
+}
+
+// RestoreDeletion cancels a pending deletion. The purge job checks
+// purge_after at run time, so a restore any time before the purge sticks.
+func (s *campaignService) RestoreDeletion(ctx context.Context, campaignID string) error {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if !campaign.IsPendingDeletion() {
+		return apperror.NewBadRequest("campaign is not scheduled for deletion")
+	}
+	if err := s.repo.CancelDeletion(ctx, campaignID); err != nil {
+		return err
+	}
+	slog.Info("campaign deletion cancelled", slog.String("campaign_id", campaignID))
+	return nil
+}
+
+// ListPendingDeletion returns the user's own campaigns awaiting purge so
+// the campaign list can offer to restore them.
+func (s *campaignService) ListPendingDeletion(ctx context.Context, userID string) ([]Campaign, error) {
+	return s.repo.ListPendingDeletionByOwner(ctx, userID)
+}
+
+// PurgeDueDeletions hard-deletes up to one batch of campaigns whose grace
+// period ended before now and returns how many it deleted. A failing
+// campaign is logged and left for the next run.
+func (s *campaignService) PurgeDueDeletions(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.repo.ListDueForPurge(ctx, now.UTC(), deletionPurgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("listing campaigns due for purge: %w", err)
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.Delete(ctx, id); err != nil {
+			slog.Warn("campaign purge failed", slog.String("campaign_id", id), slog.Any("error", err))
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// DeletionPurger is the slice of CampaignService the purge job needs.
+type DeletionPurger interface {
+	PurgeDueDeletions(ctx context.Context, now time.Time) (int, error)
+}
+
+// DeletionPurgeJob hard-deletes campaigns whose grace period has ended.
+type DeletionPurgeJob struct {
+	purger DeletionPurger
+	now    func() time.Time
+}
+
+// NewDeletionPurgeJob creates the purge job.
+func NewDeletionPurgeJob(purger DeletionPurger) *DeletionPurgeJob {
+	return &DeletionPurgeJob{purger: purger, now: time.Now}
+}
+
+// Run purges every campaign that is due, batch by batch.
+func (j *DeletionPurgeJob) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := j.purger.PurgeDueDeletions(ctx, j.now())
+		total += n
+		if err != nil || n < deletionPurgeBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job hourly until ctx is cancelled, first after a short
+// delay so boot isn't slowed.
+func (j *DeletionPurgeJob) Start(ctx context.Context) {
+	timer := time.NewTimer(deletionPurgeStartDelay)
+	defer timer.Stop()
+
+	slog.Info("campaign purge worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("campaign purge worker stopped")
+			return
+		case <-timer.C:
+			n, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("campaign purge run failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("campaign purge", slog.Int("campaigns", n))
+			}
+			timer.Reset(deletionPurgeInterval)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// deletionRepo layers the deletion-grace-period queries over mockCampaignRepo.
+type deletionRepo struct {
+	*mockCampaignRepo
+	purgeAfter map[string]time.Time
+	cancelled  []string
+	due        []string
+}
+
+func (r *deletionRepo) MarkForDeletion(_ context.Context, id string, purgeAfter time.Time) error {
+	r.purgeAfter[id] = purgeAfter
+	return nil
+}
+
+func (r *deletionRepo) CancelDeletion(_ context.Context, id string) error {
+	r.cancelled = append(r.cancelled, id)
+	return nil
+}
+
+func (r *deletionRepo) ListDueForPurge(_ context.Context, _ time.Time, limit int) ([]string, error) {
+	if len(r.due) > limit {
+		return r.due[:limit], nil
+	}
+	return r.due, nil
+}
+
+func TestMarkForDeletion_SetsGracePeriod(t *testing.T) {
+	repo := &deletionRepo{mockCampaignRepo: &mockCampaignRepo{}, purgeAfter: map[string]time.Time{}}
+	svc := NewCampaignService(repo, &mockUserFinder{}, nil, nil, "")
+
+	before := time.Now().UTC()
+	purgeAfter, err := svc.MarkForDeletion(context.Background(), "camp-1")
+	if err != nil {
+		t.Fatalf("MarkForDeletion: %v", err)
+	}
+	if got := purgeAfter.Sub(before); got < DeletionGracePeriod || got > DeletionGracePeriod+time.Minute {
+		t.Errorf("purge after %v, want about %v from now", got, DeletionGracePeriod)
+	}
+	if !repo.purgeAfter["camp-1"].Equal(purgeAfter) {
+		t.Errorf("repo got %v, service returned %v", repo.purgeAfter["camp-1"], purgeAfter)
+	}
+}
+
+func TestRestoreDeletion(t *testing.T) {
+	marked := time.Now()
+	cases := []struct {
+		name     string
+		campaign *Campaign
+		wantCode int // 0 = success
+	}{
+		{"pending deletion", &Campaign{ID: "camp-1", DeletionRequestedAt: &marked}, 0},
+		{"not marked", &Campaign{ID: "camp-1"}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &deletionRepo{mockCampaignRepo: &mockCampaignRepo{
+				findByIDFn: func(context.Context, string) (*Campaign, error) { return tc.campaign, nil },
+			}}
+			svc := NewCampaignService(repo, &mockUserFinder{}, nil, nil, "")
+
+			err := svc.RestoreDeletion(context.Background(), "camp-1")
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+				if len(repo.cancelled) != 0 {
+					t.Error("cancelled a deletion that was never requested")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RestoreDeletion: %v", err)
+			}
+			if len(repo.cancelled) != 1 {
+				t.Errorf("cancelled = %v, want [camp-1]", repo.cancelled)
+			}
+		})
+	}
+}
+
+func TestPurgeDueDeletions_SkipsFailures(t *testing.T) {
+	var deleted []string
+	repo := &deletionRepo{
+		mockCampaignRepo: &mockCampaignRepo{
+			deleteFn: func(_ context.Context, id string) error {
+				if id == "bad" {
+					return errors.New("db down")
+				}
+				deleted = append(deleted, id)
+				return nil
+			},
+		},
+		due: []string{"a", "bad", "b"},
+	}
+	svc := NewCampaignService(repo, &mockUserFinder{}, nil, nil, "")
+
+	n, err := svc.PurgeDueDeletions(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("PurgeDueDeletions: %v", err)
+	}
+	if n != 2 || len(deleted) != 2 {
+		t.Errorf("purged %d (%v), want a and b", n, deleted)
+	}
+}
+
+// countingPurger reports full batches until it runs out.
+type countingPurger struct{ remaining, calls int }
+
+func (p *countingPurger) PurgeDueDeletions(context.Context, time.Time) (int, error) {
+	p.calls++
+	n := min(p.remaining, deletionPurgeBatchSize)
+	p.remaining -= n
+	return n, nil
+}
+
+func TestDeletionPurgeJob_RunDrainsBatches(t *testing.T) {
+	p := &countingPurger{remaining: deletionPurgeBatchSize*2 + 3}
+	n, err := NewDeletionPurgeJob(p).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != deletionPurgeBatchSize*2+3 || p.calls != 3 {
+		t.Errorf("purged %d in %d calls", n, p.calls)
+	}
+}
+
+func TestCheckPendingDeletion(t *testing.T) {
+	marked := time.Now()
+	cases := []struct {
+		name     string
+		pending  bool
+		role     Role
+		admin    bool
+		method   string
+		path     string
+		wantCode int // 0 = allowed
+	}{
+		{"active campaign", false, RolePlayer, false, http.MethodPost, "/campaigns/:id/entities", 0},
+		{"member of a marked campaign", true, RoleScribe, false, http.MethodGet, "/campaigns/:id", http.StatusNotFound},
+		{"public visitor", true, RoleNone, false, http.MethodGet, "/campaigns/:id", http.StatusNotFound},
+		{"owner reads", true, RoleOwner, false, http.MethodGet, "/campaigns/:id", 0},
+		{"site admin reads", true, RoleNone, true, http.MethodGet, "/campaigns/:id", 0},
+		{"owner edits", true, RoleOwner, false, http.MethodPut, "/campaigns/:id/settings", http.StatusForbidden},
+		{"owner restores", true, RoleOwner, false, http.MethodPost, restoreRoutePath, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			campaign := &Campaign{ID: "camp-1"}
+			if tc.pending {
+				campaign.DeletionRequestedAt = &marked
+			}
+			e := echo.New()
+			c := e.NewContext(httptest.NewRequest(tc.method, "/", nil), httptest.NewRecorder())
+			c.SetPath(tc.path)
+
+			err := checkPendingDeletion(c, &CampaignContext{Campaign: campaign, MemberRole: tc.role, IsSiteAdmin: tc.admin})
+			if tc.wantCode == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			assertAppError(t, err, tc.wantCode)
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package campaigns
+
+// digest.go — the weekly campaign digest email. Once a week each member gets
+// one email per campaign summarizing the past seven days (new and updated
+// pages, announcements, unread notifications) and the week ahead (events,
+// planned sessions). Members opt out per campaign from the announcements
+// page. Most sections come from other plugins' tables, so, like retention
+// targets, they arrive as DigestSources wired in internal/app; this file
+// only builds announcements itself. A quiet week sends nothing.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
+)
+
+const (
+	// digestBatchSize bounds how many campaigns one pass handles.
+	digestBatchSize = 20
+
+	// digestSectionLimit caps the lines per section; the rest is summed up
+	// in an "and N more" line.
+	digestSectionLimit = 10
+
+	// digestInterval is how often the job looks for unsent digests. An hour
+	// gets Monday's digests out early in the day without a precise
+	// scheduler.
+	digestInterval = time.Hour
+)
+
+// DigestItem is one line of a digest section. Link is app-relative
+// ("/campaigns/..."); the service makes it absolute for the email.
+type DigestItem struct {
+	Text string
+	Note string
+	Link string
+}
+
+// DigestWindow is the span a digest covers: changes from Since to Now, and
+// what's coming up from Now to Until.
+type DigestWindow struct {
+	Since time.Time
+	Now   time.Time
+	Until time.Time
+}
+
+// DigestItemsFunc returns one section's lines for a member.
+type DigestItemsFunc func(ctx context.Context, campaignID, userID string, w DigestWindow) ([]DigestItem, error)
+
+// DigestSource is one digest section supplied by another plugin.
+type DigestSource struct {
+	Heading string
+	Items   DigestItemsFunc
+}
+
+// DigestMailer sends digest emails. Subset of smtp.MailService.
+type DigestMailer interface {
+	SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error
+	IsConfigured(ctx context.Context) bool
+}
+
+// DigestService handles the weekly digest and members' opt-outs.
+type DigestService interface {
+	// IsSubscribed reports whether the member gets the campaign's digest.
+	IsSubscribed(ctx context.Context, campaignID, userID string) (bool, error)
+	SetSubscribed(ctx context.Context, campaignID, userID string, subscribed bool) error
+
+	// SendDue sends this week's digest to every member who hasn't had it
+	// and returns how many campaigns it handled.
+	SendDue(ctx context.Context, now time.Time) (int, error)
+
+	// SetSources sets the sections other plugins contribute, in order.
+	SetSources(sources ...DigestSource)
+}
+
+// digestService implements DigestService.
+type digestService struct {
+	repo          DigestRepository
+	members       MemberLister
+	announcements AnnouncementService
+	mailer        DigestMailer
+	baseURL       string
+	sources       []DigestSource
+	now           func() time.Time
+}
+
+// NewDigestService creates a new digest service.
+func NewDigestService(repo DigestRepository, members MemberLister, announcements AnnouncementService, mailer DigestMailer, baseURL string) DigestService {
+	return &digestService{
+		repo:          repo,
+		members:       members,
+		announcements: announcements,
+		mailer:        mailer,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		now:           time.Now,
+	}
+}
+
+// SetSources sets the contributed sections.
+func (s *digestService) SetSources(sources ...DigestSource) {
+	s.sources = sources
+}
+
+// IsSubscribed returns false only for members who opted out.
+func (s *digestService) IsSubscribed(ctx context.Context, campaignID, userID string) (bool, error) {
+	out, err := s.repo.IsOptedOut(ctx, campaignID, userID)
+	return !out, err
+}
+
+// SetSubscribed turns the member's digest on or off.
+func (s *digestService) SetSubscribed(ctx context.Context, campaignID, userID string, subscribed bool) error {
+	return s.repo.SetOptedOut(ctx, campaignID, userID, !subscribed)
+}
+
+// SendDue handles one batch of campaigns with digests outstanding for the
+// week containing now. Nothing is claimed while mail is unconfigured, so
+// the week's digests go out once an admin sets SMTP up.
+func (s *digestService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	if s.mailer == nil || !s.mailer.IsConfigured(ctx) {
+		return 0, nil
+	}
+	period := digestPeriodStart(now)
+	pending, err := s.repo.ListPendingCampaigns(ctx, period, digestBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	handled := 0
+	for _, camp := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.sendCampaign(ctx, camp, period, now); err != nil {
+			return handled, err
+		}
+		handled++
+	}
+	return handled, nil
+}
+
+// sendCampaign claims and sends the week's digest for each subscribed
+// member. A member is claimed before the email is built, so a failed send
+// is logged and not retried: one missed digest beats a double one.
+func (s *digestService) sendCampaign(ctx context.Context, camp DigestCampaign, period, now time.Time) error {
+	members, err := s.members.ListMembers(ctx, camp.ID)
+	if err != nil {
+		return fmt.Errorf("listing members for digest: %w", err)
+	}
+	optedOut, err := s.repo.ListOptedOut(ctx, camp.ID)
+	if err != nil {
+		return err
+	}
+
+	campaignURL := fmt.Sprintf("%s/campaigns/%s", s.baseURL, camp.ID)
+	for _, m := range members {
+		if optedOut[m.UserID] {
+			continue
+		}
+		claimed, err := s.repo.ClaimSend(ctx, camp.ID, m.UserID, period)
+		if err != nil {
+			return err
+		}
+		if !claimed || m.Email == "" {
+			continue
+		}
+		sections := s.buildSections(ctx, camp.ID, m.UserID, now)
+		if len(sections) == 0 {
+			continue
+		}
+		msg := emails.CampaignDigest(camp.Name, sections, campaignURL, campaignURL+"/announcements")
+		plainBody, htmlBody, err := msg.Render(ctx)
+		if err == nil {
+			err = s.mailer.SendHTMLMail(ctx, []string{m.Email}, msg.Subject, plainBody, htmlBody)
+		}
+		if err != nil {
+			slog.Warn("failed to send campaign digest",
+				slog.String("campaign_id", camp.ID),
+				slog.String("user_id", m.UserID),
+				slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+// buildSections gathers the member's non-empty sections: announcements
+// first, then each source in wiring order. A failing source is logged and
+// left out rather than holding up the rest of the digest.
+func (s *digestService) buildSections(ctx context.Context, campaignID, userID string, now time.Time) []emails.Section {
+	w := DigestWindow{Since: now.AddDate(0, 0, -7), Now: now, Until: now.AddDate(0, 0, 7)}
+
+	var sections []emails.Section
+	if items := s.announcementItems(ctx, campaignID, userID, w); len(items) > 0 {
+		sections = append(sections, s.section("Announcements", items))
+	}
+	for _, src := range s.sources {
+		items, err := src.Items(ctx, campaignID, userID, w)
+		if err != nil {
+			slog.Warn("digest section failed",
+				slog.String("section", src.Heading),
+				slog.String("campaign_id", campaignID),
+				slog.Any("error", err))
+			continue
+		}
+		if len(items) > 0 {
+			sections = append(sections, s.section(src.Heading, items))
+		}
+	}
+	return sections
+}
+
+// announcementItems lists posts published during the window.
+func (s *digestService) announcementItems(ctx context.Context, campaignID, userID string, w DigestWindow) []DigestItem {
+	if s.announcements == nil {
+		return nil
+	}
+	list, err := s.announcements.List(ctx, campaignID, userID, RolePlayer)
+	if err != nil {
+		slog.Warn("digest announcements failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+		return nil
+	}
+	var items []DigestItem
+	for _, a := range list {
+		if a.PublishAt.Before(w.Since) || a.PublishAt.After(w.Now) {
+			continue
+		}
+		items = append(items, DigestItem{
+			Text: a.Title,
+			Note: a.PublishAt.Format("Jan 2"),
+			Link: fmt.Sprintf("/campaigns/%s/announcements/%s", campaignID, a.ID),
+		})
+	}
+	return items
+}
+
+// section converts items to an email section, capped at digestSectionLimit
+// lines and with links made absolute.
+func (s *digestService) section(heading string, items []DigestItem) emails.Section {
+	sec := emails.Section{Heading: heading}
+	for i, it := range items {
+		if i == digestSectionLimit {
+			sec.Items = append(sec.Items, emails.SectionItem{Text: fmt.Sprintf("…and %d more", len(items)-digestSectionLimit)})
+			break
+		}
+		url := it.Link
+		if strings.HasPrefix(url, "/") {
+			url = s.baseURL + url
+		}
+		sec.Items = append(sec.Items, emails.SectionItem{Text: it.Text, Note: it.Note, URL: url})
+	}
+	return sec
+}
+
+// digestPeriodStart returns the Monday (00:00 UTC) starting now's week,
+// the key that keeps digests to one per member per week.
+func digestPeriodStart(now time.Time) time.Time {
+	day := now.UTC().Truncate(24 * time.Hour)
+	sinceMonday := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -sinceMonday)
+}
+
+// DigestSender is the slice of DigestService the digest job needs.
+type DigestSender interface {
+	SendDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// DigestJob sends the weekly campaign digests.
+type DigestJob struct {
+	sender DigestSender
+	now    func() time.Time
+}
+
+// NewDigestJob creates the digest job.
+func NewDigestJob(sender DigestSender) *DigestJob {
+	return &DigestJob{sender: sender, now: time.Now}
+}
+
+// Run sends every outstanding digest, batch by batch.
+func (j *DigestJob) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := j.sender.SendDue(ctx, j.now())
+		total += n
+		if err != nil || n < digestBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job hourly until ctx is cancelled.
+func (j *DigestJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(digestInterval)
+	defer ticker.Stop()
+
+	slog.Info("campaign digest worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("campaign digest worker stopped")
+			return
+		case <-ticker.C:
+			n, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("campaign digest run failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("campaign digests sent", slog.Int("campaigns", n))
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package campaigns
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DigestCampaign is a campaign with digests still to send this week.
+type DigestCampaign struct {
+	ID   string
+	Name string
+}
+
+// DigestRepository handles persistence for the weekly digest: per-member
+// opt-outs and the per-week send log.
+type DigestRepository interface {
+	// ListPendingCampaigns returns live campaigns with at least one member
+	// who hasn't opted out and has no digest recorded for periodStart.
+	ListPendingCampaigns(ctx context.Context, periodStart time.Time, limit int) ([]DigestCampaign, error)
+
+	// ListOptedOut returns the campaign's opted-out members as a set.
+	ListOptedOut(ctx context.Context, campaignID string) (map[string]bool, error)
+	IsOptedOut(ctx context.Context, campaignID, userID string) (bool, error)
+	SetOptedOut(ctx context.Context, campaignID, userID string, optedOut bool) error
+
+	// ClaimSend records the member's digest for periodStart and reports
+	// whether this caller won, so a member gets at most one per week.
+	ClaimSend(ctx context.Context, campaignID, userID string, periodStart time.Time) (bool, error)
+}
+
+// digestRepository implements DigestRepository using MariaDB.
+type digestRepository struct {
+	db *sql.DB
+}
+
+// NewDigestRepository creates a new digest repository.
+func NewDigestRepository(db *sql.DB) DigestRepository {
+	return &digestRepository{db: db}
+}
+
+// ListPendingCampaigns skips archived campaigns and those awaiting deletion.
+func (r *digestRepository) ListPendingCampaigns(ctx context.Context, periodStart time.Time, limit int) ([]DigestCampaign, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.id, c.name
+		 FROM campaigns c
+		 WHERE c.archived_at IS NULL AND c.deletion_requested_at IS NULL
+		   AND EXISTS (
+		     SELECT 1 FROM campaign_members cm
+		     LEFT JOIN campaign_digest_opt_outs o
+		       ON o.campaign_id = cm.campaign_id AND o.user_id = cm.user_id
+		     LEFT JOIN campaign_digest_sends s
+		       ON s.campaign_id = cm.campaign_id AND s.user_id = cm.user_id AND s.period_start = ?
+		     WHERE cm.campaign_id = c.id AND o.user_id IS NULL AND s.user_id IS NULL)
+		 ORDER BY c.id
+		 LIMIT ?`,
+		periodStart.Format("2006-01-02"), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending digest campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var list []DigestCampaign
+	for rows.Next() {
+		var c DigestCampaign
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			return nil, fmt.Errorf("scanning digest campaign: %w", err)
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+// ListOptedOut returns the user IDs that turned the digest off.
+func (r *digestRepository) ListOptedOut(ctx context.Context, campaignID string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id FROM campaign_digest_opt_outs WHERE campaign_id = ?`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing digest opt-outs: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("scanning digest opt-out: %w", err)
+		}
+		out[userID] = true
+	}
+	return out, rows.Err()
+}
+
+// IsOptedOut reports whether the member turned the digest off.
+func (r *digestRepository) IsOptedOut(ctx context.Context, campaignID, userID string) (bool, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM campaign_digest_opt_outs WHERE campaign_id = ? AND user_id = ?`,
+		campaignID, userID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("checking digest opt-out: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetOptedOut adds or removes the member's opt-out row.
+func (r *digestRepository) SetOptedOut(ctx context.Context, campaignID, userID string, optedOut bool) error {
+	var err error
+	if optedOut {
+		_, err = r.db.ExecContext(ctx,
+			`INSERT IGNORE INTO campaign_digest_opt_outs (campaign_id, user_id) VALUES (?, ?)`,
+			campaignID, userID)
+	} else {
+		_, err = r.db.ExecContext(ctx,
+			`DELETE FROM campaign_digest_opt_outs WHERE campaign_id = ? AND user_id = ?`,
+			campaignID, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("setting digest opt-out: %w", err)
+	}
+	return nil
+}
+
+// ClaimSend inserts the send row; a duplicate key means another pass (or
+// instance) already has this member's digest for the week.
+func (r *digestRepository) ClaimSend(ctx context.Context, campaignID, userID string, periodStart time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO campaign_digest_sends (campaign_id, user_id, period_start) VALUES (?, ?, ?)`,
+		campaignID, userID, periodStart.Format("2006-01-02"))
+	if err != nil {
+		return false, fmt.Errorf("claiming digest send: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claiming digest send: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,190 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDigestRepo is an in-memory DigestRepository for one campaign.
+type fakeDigestRepo struct {
+	camp     DigestCampaign
+	members  []string
+	optedOut map[string]bool
+	sent     map[string]bool // userID + "/" + period
+}
+
+func (r *fakeDigestRepo) ListPendingCampaigns(_ context.Context, period time.Time, _ int) ([]DigestCampaign, error) {
+	for _, id := range r.members {
+		if !r.optedOut[id] && !r.sent[id+"/"+period.Format("2006-01-02")] {
+			return []DigestCampaign{r.camp}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDigestRepo) ListOptedOut(context.Context, string) (map[string]bool, error) {
+	return r.optedOut, nil
+}
+
+func (r *fakeDigestRepo) IsOptedOut(_ context.Context, _, userID string) (bool, error) {
+	return r.optedOut[userID], nil
+}
+
+func (r *fakeDigestRepo) SetOptedOut(_ context.Context, _, userID string, optedOut bool) error {
+	r.optedOut[userID] = optedOut
+	return nil
+}
+
+func (r *fakeDigestRepo) ClaimSend(_ context.Context, _, userID string, period time.Time) (bool, error) {
+	key := userID + "/" + period.Format("2006-01-02")
+	if r.sent[key] {
+		return false, nil
+	}
+	r.sent[key] = true
+	return true, nil
+}
+
+// recordingMailer collects sent digests.
+type recordingMailer struct {
+	configured bool
+	to         []string
+	plain      []string
+}
+
+func (m *recordingMailer) SendHTMLMail(_ context.Context, to []string, _, plainBody, _ string) error {
+	m.to = append(m.to, to...)
+	m.plain = append(m.plain, plainBody)
+	return nil
+}
+
+func (m *recordingMailer) IsConfigured(context.Context) bool { return m.configured }
+
+func newTestDigestService(now time.Time) (*digestService, *fakeDigestRepo, *recordingMailer, *fakeAnnouncementRepo) {
+	clock := func() time.Time { return now }
+	members := &fakeMemberLister{members: []CampaignMember{
+		{UserID: "owner", Email: "owner@example.com"},
+		{UserID: "p1", Email: "p1@example.com"},
+		{UserID: "p2", Email: "p2@example.com"},
+		{UserID: "p3"}, // no email on file
+	}}
+	repo := &fakeDigestRepo{
+		camp:     DigestCampaign{ID: "camp-1", Name: "Strahd"},
+		members:  []string{"owner", "p1", "p2", "p3"},
+		optedOut: map[string]bool{},
+		sent:     map[string]bool{},
+	}
+	annRepo := newFakeAnnouncementRepo(clock)
+	announcements := NewAnnouncementService(annRepo, members).(*announcementService)
+	announcements.now = clock
+	mailer := &recordingMailer{configured: true}
+	svc := NewDigestService(repo, members, announcements, mailer, "https://chronicle.test/").(*digestService)
+	svc.now = clock
+	return svc, repo, mailer, annRepo
+}
+
+func TestDigestPeriodStart(t *testing.T) {
+	cases := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"monday morning", time.Date(2026, 6, 8, 0, 30, 0, 0, time.UTC), "2026-06-08"},
+		{"midweek", time.Date(2026, 6, 10, 18, 0, 0, 0, time.UTC), "2026-06-08"},
+		{"sunday night", time.Date(2026, 6, 14, 23, 59, 0, 0, time.UTC), "2026-06-08"},
+		{"non-UTC input", time.Date(2026, 6, 8, 1, 0, 0, 0, time.FixedZone("CEST", 2*3600)), "2026-06-01"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := digestPeriodStart(tc.now).Format("2006-01-02"); got != tc.want {
+				t.Errorf("digestPeriodStart(%v) = %s; want %s", tc.now, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDigestService_SendDue(t *testing.T) {
+	now := time.Date(2026, 6, 8, 9, 0, 0, 0, time.UTC)
+	svc, repo, mailer, annRepo := newTestDigestService(now)
+	ctx := context.Background()
+
+	annRepo.items["a1"] = &Announcement{ID: "a1", CampaignID: "camp-1", Title: "Session moved", PublishAt: now.Add(-48 * time.Hour)}
+	annRepo.items["old"] = &Announcement{ID: "old", CampaignID: "camp-1", Title: "Last month", PublishAt: now.AddDate(0, -1, 0)}
+	svc.SetSources(
+		DigestSource{Heading: "Pages", Items: func(context.Context, string, string, DigestWindow) ([]DigestItem, error) {
+			return []DigestItem{{Text: "Ireena", Note: "new", Link: "/campaigns/camp-1/entities/e1"}}, nil
+		}},
+		DigestSource{Heading: "Broken", Items: func(context.Context, string, string, DigestWindow) ([]DigestItem, error) {
+			return nil, errors.New("plugin down")
+		}},
+	)
+	if err := svc.SetSubscribed(ctx, "camp-1", "p2", false); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := svc.SendDue(ctx, now)
+	if err != nil || n != 1 {
+		t.Fatalf("SendDue = %d, %v; want 1 campaign", n, err)
+	}
+	if strings.Join(mailer.to, ",") != "owner@example.com,p1@example.com" {
+		t.Errorf("sent to %v; want subscribed members with an email", mailer.to)
+	}
+	body := mailer.plain[0]
+	for _, want := range []string{"Session moved", "https://chronicle.test/campaigns/camp-1/entities/e1", "Pages"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("digest missing %q:\n%s", want, body)
+		}
+	}
+	for _, bad := range []string{"Last month", "Broken"} {
+		if strings.Contains(body, bad) {
+			t.Errorf("digest contains %q:\n%s", bad, body)
+		}
+	}
+
+	// The same week never sends twice.
+	if n, _ := svc.SendDue(ctx, now.Add(2*time.Hour)); n != 0 || len(mailer.to) != 2 {
+		t.Errorf("second pass handled %d, sent %v", n, mailer.to)
+	}
+	if !repo.sent["p3/2026-06-08"] {
+		t.Error("member without an email should still be marked done for the week")
+	}
+}
+
+func TestDigestService_QuietWeekAndUnconfiguredMail(t *testing.T) {
+	now := time.Date(2026, 6, 8, 9, 0, 0, 0, time.UTC)
+	svc, repo, mailer, _ := newTestDigestService(now)
+	ctx := context.Background()
+
+	mailer.configured = false
+	if n, err := svc.SendDue(ctx, now); err != nil || n != 0 || len(repo.sent) != 0 {
+		t.Fatalf("unconfigured mail: handled %d (%v), claimed %v", n, err, repo.sent)
+	}
+
+	mailer.configured = true
+	if _, err := svc.SendDue(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.to) != 0 {
+		t.Errorf("quiet week sent %v; want nothing", mailer.to)
+	}
+}
+
+func TestDigestService_SectionLimit(t *testing.T) {
+	svc, _, _, _ := newTestDigestService(time.Now())
+	items := make([]DigestItem, digestSectionLimit+3)
+	for i := range items {
+		items[i] = DigestItem{Text: "Page", Link: "/campaigns/c/entities/e"}
+	}
+	sec := svc.section("Pages", items)
+	if len(sec.Items) != digestSectionLimit+1 {
+		t.Fatalf("got %d lines; want %d plus a summary", len(sec.Items), digestSectionLimit)
+	}
+	if last := sec.Items[digestSectionLimit]; last.Text != "…and 3 more" || last.URL != "" {
+		t.Errorf("summary line = %+v", last)
+	}
+	if sec.Items[0].URL != "https://chronicle.test/campaigns/c/entities/e" {
+		t.Errorf("link not made absolute: %q", sec.Items[0].URL)
+	}
+}
@@ -0,0 +1,196 @@
+package campaigns
+
+// directory.go — the public campaign directory on the discover page (/).
+// Owners opt a campaign in separately from making it public: public means
+// readable by anyone with the link, listed means advertised here too. The
+// directory searches name and description, filters by the owner's
+// genre/system tags, and pages through results with the campaign's
+// backdrop as its cover and its member count.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// DirectoryPageSize is how many campaigns one directory page shows.
+	DirectoryPageSize = 24
+
+	// maxDirectoryTags caps the tags on one campaign.
+	maxDirectoryTags = 5
+
+	// maxDirectoryTagLength caps one tag, in characters.
+	maxDirectoryTagLength = 30
+
+	// maxDirectorySearchLength caps the search text.
+	maxDirectorySearchLength = 100
+
+	// directoryPopularTags is how many tags the discover page offers as
+	// filter chips.
+	directoryPopularTags = 12
+)
+
+// DirectoryListing is a campaign's directory opt-in and tags.
+type DirectoryListing struct {
+	Listed bool     `json:"listed"`
+	Tags   []string `json:"tags"`
+}
+
+// DirectoryQuery filters a directory page. Page is 1-based.
+type DirectoryQuery struct {
+	Search string
+	Tag    string
+	Page   int
+}
+
+// DirectoryEntry is one campaign on a directory page. Campaign carries
+// only what the card shows: ID, name, description, backdrop and
+// updated_at.
+type DirectoryEntry struct {
+	Campaign    Campaign
+	Tags        []string
+	MemberCount int
+}
+
+// DirectoryPage is one page of directory results.
+type DirectoryPage struct {
+	Query   DirectoryQuery
+	Entries []DirectoryEntry
+	Total   int
+
+	// PopularTags are the most used tags across listed campaigns, offered
+	// as filter chips.
+	PopularTags []string
+}
+
+// TotalPages returns the number of pages, at least 1.
+func (p *DirectoryPage) TotalPages() int {
+	if p.Total <= DirectoryPageSize {
+		return 1
+	}
+	return (p.Total + DirectoryPageSize - 1) / DirectoryPageSize
+}
+
+// HasPrev reports whether there is an earlier page.
+func (p *DirectoryPage) HasPrev() bool { return p.Query.Page > 1 }
+
+// HasNext reports whether there is a later page.
+func (p *DirectoryPage) HasNext() bool { return p.Query.Page < p.TotalPages() }
+
+// DirectoryService handles directory listings and the discover page search.
+type DirectoryService interface {
+	GetListing(ctx context.Context, campaignID string) (*DirectoryListing, error)
+
+	// UpdateListing sets the campaign's opt-in and tags, normalizing the
+	// tags, and returns the stored listing.
+	UpdateListing(ctx context.Context, campaignID string, listing DirectoryListing) (*DirectoryListing, error)
+
+	// Search returns one page of listed public campaigns.
+	Search(ctx context.Context, q DirectoryQuery) (*DirectoryPage, error)
+
+	// EnsureCampaignDirectory keeps campaigns that were public before the
+	// directory existed listed on it. Run at boot; a second run adds
+	// nothing.
+	EnsureCampaignDirectory(ctx context.Context) (int, error)
+}
+
+// directoryService implements DirectoryService.
+type directoryService struct {
+	repo DirectoryRepository
+}
+
+// NewDirectoryService creates a new directory service.
+func NewDirectoryService(repo DirectoryRepository) DirectoryService {
+	return &directoryService{repo: repo}
+}
+
+// GetListing returns an unlisted, untagged listing for campaigns that
+// never opted in.
+func (s *directoryService) GetListing(ctx context.Context, campaignID string) (*DirectoryListing, error) {
+	return s.repo.GetListing(ctx, campaignID)
+}
+
+// UpdateListing rejects tags it can't normalize rather than dropping them,
+// so the owner sees why a tag didn't stick.
+func (s *directoryService) UpdateListing(ctx context.Context, campaignID string, listing DirectoryListing) (*DirectoryListing, error) {
+	tags, err := normalizeDirectoryTags(listing.Tags)
+	if err != nil {
+		return nil, err
+	}
+	listing.Tags = tags
+	if err := s.repo.SaveListing(ctx, campaignID, listing); err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// Search normalizes the query before running it. Popular tags are
+// best-effort: the page still renders without them.
+func (s *directoryService) Search(ctx context.Context, q DirectoryQuery) (*DirectoryPage, error) {
+	q = normalizeDirectoryQuery(q)
+	entries, total, err := s.repo.Search(ctx, q, DirectoryPageSize, (q.Page-1)*DirectoryPageSize)
+	if err != nil {
+		return nil, err
+	}
+	page := &DirectoryPage{Query: q, Entries: entries, Total: total}
+	if tags, err := s.repo.PopularTags(ctx, directoryPopularTags); err == nil {
+		page.PopularTags = tags
+	}
+	return page, nil
+}
+
+// EnsureCampaignDirectory is a data reconciler, not a migration: before
+// the directory, every public campaign was on the discover page, so those
+// start out listed.
+func (s *directoryService) EnsureCampaignDirectory(ctx context.Context) (int, error) {
+	return s.repo.BackfillListings(ctx)
+}
+
+// normalizeDirectoryTags lowercases, trims, de-duplicates and sorts tags.
+// Inner whitespace collapses to single spaces, so "Sci  Fi" and "sci fi"
+// are one tag.
+func normalizeDirectoryTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		t = normalizeDirectoryTag(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		if utf8.RuneCountInString(t) > maxDirectoryTagLength {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("tags must be %d characters or fewer", maxDirectoryTagLength))
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > maxDirectoryTags {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("at most %d tags are allowed", maxDirectoryTags))
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// normalizeDirectoryTag is the stored form of one tag.
+func normalizeDirectoryTag(t string) string {
+	return strings.ToLower(strings.Join(strings.Fields(t), " "))
+}
+
+// normalizeDirectoryQuery trims the search, normalizes the tag filter and
+// clamps the page to at least 1. Over-long searches are cut rather than
+// rejected, since they come straight from the URL.
+func normalizeDirectoryQuery(q DirectoryQuery) DirectoryQuery {
+	q.Search = strings.Join(strings.Fields(q.Search), " ")
+	if utf8.RuneCountInString(q.Search) > maxDirectorySearchLength {
+		q.Search = string([]rune(q.Search)[:maxDirectorySearchLength])
+	}
+	q.Tag = normalizeDirectoryTag(q.Tag)
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	return q
+}
@@ -0,0 +1,247 @@
+package campaigns
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DirectoryRepository handles persistence for the public campaign
+// directory: per-campaign listing opt-ins and tags, and the discover page
+// search.
+type DirectoryRepository interface {
+	GetListing(ctx context.Context, campaignID string) (*DirectoryListing, error)
+
+	// SaveListing replaces the campaign's opt-in and tags.
+	SaveListing(ctx context.Context, campaignID string, listing DirectoryListing) error
+
+	// Search returns listed campaigns matching q, most recently updated
+	// first, along with the total match count.
+	Search(ctx context.Context, q DirectoryQuery, limit, offset int) ([]DirectoryEntry, int, error)
+
+	// PopularTags returns the most used tags across listed campaigns.
+	PopularTags(ctx context.Context, limit int) ([]string, error)
+
+	// BackfillListings adds a listing row, listed when public, for each
+	// campaign that predates the directory and has none, returning how
+	// many it added.
+	BackfillListings(ctx context.Context) (int, error)
+}
+
+// directoryRepository implements DirectoryRepository using MariaDB.
+type directoryRepository struct {
+	db *sql.DB
+}
+
+// NewDirectoryRepository creates a new directory repository.
+func NewDirectoryRepository(db *sql.DB) DirectoryRepository {
+	return &directoryRepository{db: db}
+}
+
+// directoryVisible restricts c to campaigns the directory may show: listed,
+// public, and neither archived nor awaiting deletion.
+const directoryVisible = `FROM campaigns c
+	 JOIN campaign_directory d ON d.campaign_id = c.id AND d.listed = 1
+	 WHERE c.is_public = 1 AND c.archived_at IS NULL AND c.deletion_requested_at IS NULL`
+
+// GetListing reads the opt-in row and tags; a missing row is unlisted.
+func (r *directoryRepository) GetListing(ctx context.Context, campaignID string) (*DirectoryListing, error) {
+	listing := &DirectoryListing{Tags: []string{}}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT listed FROM campaign_directory WHERE campaign_id = ?`, campaignID,
+	).Scan(&listing.Listed)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("getting directory listing: %w", err)
+	}
+
+	tags, err := r.tagsFor(ctx, []string{campaignID})
+	if err != nil {
+		return nil, err
+	}
+	if t := tags[campaignID]; t != nil {
+		listing.Tags = t
+	}
+	return listing, nil
+}
+
+// SaveListing upserts the opt-in and rewrites the tags in one transaction.
+func (r *directoryRepository) SaveListing(ctx context.Context, campaignID string, listing DirectoryListing) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning directory listing tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO campaign_directory (campaign_id, listed) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE listed = VALUES(listed)`,
+		campaignID, listing.Listed,
+	); err != nil {
+		return fmt.Errorf("saving directory listing: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM campaign_directory_tags WHERE campaign_id = ?`, campaignID,
+	); err != nil {
+		return fmt.Errorf("clearing directory tags: %w", err)
+	}
+	for _, tag := range listing.Tags {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO campaign_directory_tags (campaign_id, tag) VALUES (?, ?)`,
+			campaignID, tag,
+		); err != nil {
+			return fmt.Errorf("inserting directory tag: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Search matches the search text against name and description and the tag
+// exactly. Tags for the page's campaigns come from one follow-up query.
+func (r *directoryRepository) Search(ctx context.Context, q DirectoryQuery, limit, offset int) ([]DirectoryEntry, int, error) {
+	where, args := directoryFilter(q)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) `+directoryVisible+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting directory campaigns: %w", err)
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.id, c.name, c.slug, c.description, c.backdrop_path, c.updated_at,
+		        (SELECT COUNT(*) FROM campaign_members cm WHERE cm.campaign_id = c.id)
+		 `+directoryVisible+where+`
+		 ORDER BY c.updated_at DESC, c.id
+		 LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching directory campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DirectoryEntry
+	var ids []string
+	for rows.Next() {
+		var e DirectoryEntry
+		c := &e.Campaign
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.Description, &c.BackdropPath, &c.UpdatedAt, &e.MemberCount); err != nil {
+			return nil, 0, fmt.Errorf("scanning directory campaign: %w", err)
+		}
+		c.IsPublic = true
+		entries = append(entries, e)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := r.tagsFor(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range entries {
+		entries[i].Tags = tags[entries[i].Campaign.ID]
+	}
+	return entries, total, nil
+}
+
+// directoryFilter builds the search and tag conditions appended to
+// directoryVisible. LIKE wildcards in the search text match literally.
+func directoryFilter(q DirectoryQuery) (string, []any) {
+	var where string
+	var args []any
+	if q.Search != "" {
+		where += " AND (c.name LIKE ? OR c.description LIKE ?)"
+		escaped := strings.NewReplacer("%", "\\%", "_", "\\_").Replace(q.Search)
+		search := "%" + escaped + "%"
+		args = append(args, search, search)
+	}
+	if q.Tag != "" {
+		where += " AND EXISTS (SELECT 1 FROM campaign_directory_tags t WHERE t.campaign_id = c.id AND t.tag = ?)"
+		args = append(args, q.Tag)
+	}
+	return where, args
+}
+
+// PopularTags counts only campaigns the directory would show, so an
+// unlisted campaign's tags don't offer filters that match nothing.
+func (r *directoryRepository) PopularTags(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT t.tag
+		 FROM campaign_directory_tags t
+		 WHERE t.campaign_id IN (SELECT c.id `+directoryVisible+`)
+		 GROUP BY t.tag
+		 ORDER BY COUNT(*) DESC, t.tag
+		 LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing popular directory tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scanning directory tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// tagsFor returns each campaign's tags in alphabetical order.
+func (r *directoryRepository) tagsFor(ctx context.Context, campaignIDs []string) (map[string][]string, error) {
+	out := make(map[string][]string, len(campaignIDs))
+	if len(campaignIDs) == 0 {
+		return out, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(campaignIDs)), ",")
+	args := make([]any, len(campaignIDs))
+	for i, id := range campaignIDs {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT campaign_id, tag FROM campaign_directory_tags
+		 WHERE campaign_id IN (`+placeholders+`)
+		 ORDER BY campaign_id, tag`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing directory tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, fmt.Errorf("scanning directory tag: %w", err)
+		}
+		out[id] = append(out[id], tag)
+	}
+	return out, rows.Err()
+}
+
+// BackfillListings dates the directory by its table's creation time, so
+// campaigns made after it are never listed without their owner opting in.
+// Rows go in for private campaigns too: one made public later stays
+// unlisted. An unknown creation time matches nothing.
+func (r *directoryRepository) BackfillListings(ctx context.Context) (int, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO campaign_directory (campaign_id, listed)
+		 SELECT c.id, c.is_public FROM campaigns c
+		 LEFT JOIN campaign_directory d ON d.campaign_id = c.id
+		 WHERE d.campaign_id IS NULL
+		   AND c.created_at < (SELECT CREATE_TIME FROM information_schema.TABLES
+		                       WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'campaign_directory')`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("backfilling directory listings: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("backfilling directory listings: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,161 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeDirectoryRepo is an in-memory DirectoryRepository that records the
+// last search and saved listing.
+type fakeDirectoryRepo struct {
+	saved     *DirectoryListing
+	query     DirectoryQuery
+	limit     int
+	offset    int
+	total     int
+	tagsErr   error
+	popular   []string
+	searchErr error
+	backfills int
+}
+
+func (r *fakeDirectoryRepo) GetListing(context.Context, string) (*DirectoryListing, error) {
+	if r.saved == nil {
+		return &DirectoryListing{Tags: []string{}}, nil
+	}
+	return r.saved, nil
+}
+
+func (r *fakeDirectoryRepo) SaveListing(_ context.Context, _ string, listing DirectoryListing) error {
+	r.saved = &listing
+	return nil
+}
+
+func (r *fakeDirectoryRepo) Search(_ context.Context, q DirectoryQuery, limit, offset int) ([]DirectoryEntry, int, error) {
+	r.query, r.limit, r.offset = q, limit, offset
+	return nil, r.total, r.searchErr
+}
+
+func (r *fakeDirectoryRepo) PopularTags(context.Context, int) ([]string, error) {
+	return r.popular, r.tagsErr
+}
+
+// BackfillListings adds 2 listings on the first call and none after, as
+// the INSERT IGNORE does.
+func (r *fakeDirectoryRepo) BackfillListings(context.Context) (int, error) {
+	r.backfills++
+	if r.backfills == 1 {
+		return 2, nil
+	}
+	return 0, nil
+}
+
+func TestNormalizeDirectoryTags(t *testing.T) {
+	got, err := normalizeDirectoryTags([]string{" Sci  Fi ", "", "D&D 5e", "sci fi", "Horror"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"d&d 5e", "horror", "sci fi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeDirectoryTags_Limits(t *testing.T) {
+	if _, err := normalizeDirectoryTags([]string{"a", "b", "c", "d", "e", "f"}); err == nil {
+		t.Error("expected an error for six tags")
+	}
+	if _, err := normalizeDirectoryTags([]string{"a", "b", "c", "d", "e", "A"}); err != nil {
+		t.Errorf("duplicates should not count toward the limit: %v", err)
+	}
+	if _, err := normalizeDirectoryTags([]string{strings.Repeat("x", maxDirectoryTagLength+1)}); err == nil {
+		t.Error("expected an error for an over-long tag")
+	}
+}
+
+func TestDirectoryUpdateListing_StoresNormalizedTags(t *testing.T) {
+	repo := &fakeDirectoryRepo{}
+	svc := NewDirectoryService(repo)
+
+	got, err := svc.UpdateListing(context.Background(), "camp-1", DirectoryListing{Listed: true, Tags: []string{"Fantasy", " OSR"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Listed || !reflect.DeepEqual(got.Tags, []string{"fantasy", "osr"}) {
+		t.Errorf("listing = %+v", got)
+	}
+	if repo.saved == nil || !reflect.DeepEqual(repo.saved.Tags, got.Tags) {
+		t.Errorf("saved = %+v, want %+v", repo.saved, got)
+	}
+}
+
+func TestDirectorySearch_NormalizesQueryAndPages(t *testing.T) {
+	repo := &fakeDirectoryRepo{total: 50, popular: []string{"fantasy"}}
+	svc := NewDirectoryService(repo)
+
+	page, err := svc.Search(context.Background(), DirectoryQuery{Search: "  lost   mine ", Tag: " Fantasy ", Page: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.query.Search != "lost mine" || repo.query.Tag != "fantasy" {
+		t.Errorf("query = %+v", repo.query)
+	}
+	if repo.limit != DirectoryPageSize || repo.offset != 2*DirectoryPageSize {
+		t.Errorf("limit/offset = %d/%d", repo.limit, repo.offset)
+	}
+	if page.TotalPages() != 3 || !page.HasPrev() || page.HasNext() {
+		t.Errorf("pages = %d, prev %v, next %v", page.TotalPages(), page.HasPrev(), page.HasNext())
+	}
+	if !reflect.DeepEqual(page.PopularTags, []string{"fantasy"}) {
+		t.Errorf("popular tags = %q", page.PopularTags)
+	}
+}
+
+func TestDirectorySearch_ClampsPage(t *testing.T) {
+	repo := &fakeDirectoryRepo{}
+	page, err := NewDirectoryService(repo).Search(context.Background(), DirectoryQuery{Page: -4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Query.Page != 1 || repo.offset != 0 {
+		t.Errorf("page = %d, offset = %d", page.Query.Page, repo.offset)
+	}
+	if page.TotalPages() != 1 || page.HasPrev() || page.HasNext() {
+		t.Error("an empty directory should be a single page")
+	}
+}
+
+func TestDirectorySearch_PopularTagsBestEffort(t *testing.T) {
+	repo := &fakeDirectoryRepo{tagsErr: errors.New("boom")}
+	if _, err := NewDirectoryService(repo).Search(context.Background(), DirectoryQuery{}); err != nil {
+		t.Errorf("popular tag failure should not fail the search: %v", err)
+	}
+}
+
+func TestDirectoryFilter(t *testing.T) {
+	where, args := directoryFilter(DirectoryQuery{Search: "50%_off", Tag: "osr"})
+	if !strings.Contains(where, "LIKE ?") || !strings.Contains(where, "t.tag = ?") {
+		t.Errorf("where = %q", where)
+	}
+	want := []any{`%50\%\_off%`, `%50\%\_off%`, "osr"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+
+	if where, args := directoryFilter(DirectoryQuery{}); where != "" || len(args) != 0 {
+		t.Errorf("empty query gave %q %v", where, args)
+	}
+}
+
+func TestEnsureCampaignDirectory_Idempotent(t *testing.T) {
+	svc := NewDirectoryService(&fakeDirectoryRepo{})
+	if n, err := svc.EnsureCampaignDirectory(context.Background()); err != nil || n != 2 {
+		t.Fatalf("first run = %d, %v; want 2, nil", n, err)
+	}
+	if n, err := svc.EnsureCampaignDirectory(context.Background()); err != nil || n != 0 {
+		t.Fatalf("second run = %d, %v; want 0, nil", n, err)
+	}
+}
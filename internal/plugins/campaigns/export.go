@@ -48,12 +48,39 @@ type CampaignExport struct {
 
 // ExportCampaignMeta holds the campaign-level configuration.
 type ExportCampaignMeta struct {
+	// OriginalID lets import rewrite links that point into this campaign.
+	OriginalID      string          `json:"original_id,omitempty"`
 	Name            string          `json:"name"`
 	Description     *string         `json:"description,omitempty"`
 	IsPublic        bool            `json:"is_public"`
 	Settings        json.RawMessage `json:"settings,omitempty"`
 	SidebarConfig   json.RawMessage `json:"sidebar_config,omitempty"`
 	DashboardLayout json.RawMessage `json:"dashboard_layout,omitempty"`
+
+	// License spells out the content license kept in Settings, so a reader
+	// of the export needn't know the settings schema. Ignored on import.
+	License *ExportLicense `json:"license,omitempty"`
+}
+
+// ExportLicense is the campaign's content license and attribution.
+type ExportLicense struct {
+	Key         string `json:"key,omitempty"` // LicenseOptions key.
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Attribution string `json:"attribution,omitempty"`
+}
+
+// newExportLicense converts the license settings for export, nil when no
+// license or attribution is set.
+func newExportLicense(s LicenseSettings) *ExportLicense {
+	if !s.IsSet() {
+		return nil
+	}
+	out := &ExportLicense{Key: s.License, Attribution: s.Attribution}
+	if o, ok := s.Option(); ok {
+		out.Name, out.URL = o.Name, o.URL
+	}
+	return out
 }
 
 // --- Entity Types ---
@@ -97,6 +124,7 @@ type ExportEntity struct {
 	FieldsData     json.RawMessage          `json:"fields_data,omitempty"`
 	FieldOverrides json.RawMessage          `json:"field_overrides,omitempty"`
 	PopupConfig    json.RawMessage          `json:"popup_config,omitempty"`
+	Appearance     json.RawMessage          `json:"appearance,omitempty"`
 }
 
 // --- Tags ---
@@ -152,6 +180,7 @@ type ExportCalendarData struct {
 	Moons            []ExportCalendarMoon      `json:"moons,omitempty"`
 	Seasons          []ExportCalendarSeason    `json:"seasons,omitempty"`
 	Eras             []ExportCalendarEra       `json:"eras,omitempty"`
+	YearNames        []ExportCalendarYearName  `json:"year_names,omitempty"`
 	EventCategories  []ExportEventCategory     `json:"event_categories,omitempty"`
 	Events           []ExportCalendarEvent     `json:"events,omitempty"`
 }
@@ -163,6 +192,9 @@ type ExportCalendarMonth struct {
 	SortOrder     int    `json:"sort_order"`
 	IsIntercalary bool   `json:"is_intercalary"`
 	LeapYearDays  int    `json:"leap_year_days"`
+	// Weekday overrides; absent in older exports (flowing weekdays).
+	StartingWeekday *int `json:"starting_weekday,omitempty"`
+	SkipsWeekday    bool `json:"skips_weekday,omitempty"`
 }
 
 // ExportCalendarWeekday is a weekday definition for export.
@@ -201,6 +233,12 @@ type ExportCalendarEra struct {
 	SortOrder   int     `json:"sort_order"`
 }
 
+// ExportCalendarYearName is an explicitly named year for export.
+type ExportCalendarYearName struct {
+	Year int    `json:"year"`
+	Name string `json:"name"`
+}
+
 // ExportEventCategory is an event category definition for export.
 type ExportEventCategory struct {
 	Slug      string `json:"slug"`
@@ -480,4 +518,5 @@ type ExportMediaFile struct {
 	MimeType     string `json:"mime_type"`
 	FileSize     int64  `json:"file_size"`
 	UsageType    string `json:"usage_type"`
+	Credit       string `json:"credit,omitempty"`
 }
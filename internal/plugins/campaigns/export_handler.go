@@ -138,6 +138,14 @@ func (h *ExportHandler) ExportCampaign(c echo.Context) error {
 		return nil
 	}
 
+	// LICENSE.txt beside it when the campaign states a license, so the
+	// terms travel with the media. Import ignores it.
+	if license := cc.Campaign.ParseSettings().GetLicense(); license.IsSet() {
+		if entry, err := zw.Create("LICENSE.txt"); err == nil {
+			_, _ = io.WriteString(entry, license.Notice(cc.Campaign.Name))
+		}
+	}
+
 	// 2. media/<filename> for each file. UUID-based filenames mean no
 	// collisions and no path-traversal risk. Sanitize defensively
 	// anyway: any filename that contains a separator gets dropped with
@@ -191,8 +199,11 @@ func (h *ExportHandler) ImportCampaignForm(c echo.Context) error {
 // ImportCampaign imports a campaign from an uploaded JSON file or zip
 // bundle (POST /campaigns/import). Creates a new campaign owned by the
 // current user. The zip path accepts files produced by ?include_media=1
-// exports; embedded media bytes are NOT yet restored automatically (see
-// docs/campaign-import.md), but the structural data still lands.
+// exports; embedded media bytes are NOT yet restored automatically and are
+// reported as skipped, but the structural data still lands. HTMX callers
+// get the import summary as a fragment, API callers (Accept:
+// application/json) get it as JSON, and plain form posts are redirected
+// to the new campaign.
 func (h *ExportHandler) ImportCampaign(c echo.Context) error {
 	userID := auth.GetUserID(c)
 	if userID == "" {
@@ -252,23 +263,32 @@ func (h *ExportHandler) ImportCampaign(c echo.Context) error {
 		return err
 	}
 
-	campaign, err := h.exportSvc.Import(c.Request().Context(), userID, export)
+	campaign, summary, err := h.exportSvc.Import(c.Request().Context(), userID, export)
 	if err != nil {
 		return err
 	}
 
 	if mediaCount > 0 {
-		// Surfaced in logs so the operator knows the limitation kicked
-		// in. Per-user UI messaging would require a flash-message
-		// surface that isn't built yet; logging is the v1 compromise.
+		summary.count(ImportKindMedia).Skipped += mediaCount
+		summary.Warn("%d media files in the archive weren't restored; upload them again from the media library", mediaCount)
 		slog.Info("campaign import: media bytes in zip not yet restored",
 			slog.String("campaign", campaign.ID),
 			slog.Int("skipped_media_files", mediaCount),
 		)
 	}
+	slog.Info("campaign imported",
+		slog.String("campaign", campaign.ID),
+		slog.Int("skipped", summary.TotalSkipped()),
+		slog.Int("warnings", len(summary.Warnings)),
+	)
 
-	redirectURL := "/campaigns/" + campaign.ID
-	return middleware.HTMXRedirect(c, redirectURL)
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, ImportCampaignResult(campaign, summary))
+	}
+	if c.Request().Header.Get("Accept") == "application/json" {
+		return c.JSON(http.StatusCreated, summary)
+	}
+	return c.Redirect(http.StatusSeeOther, "/campaigns/"+campaign.ID)
 }
 
 // isZip checks the four-byte zip magic. False for any input shorter
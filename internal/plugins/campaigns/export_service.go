@@ -23,6 +23,9 @@ type ExportEntityData struct {
 	Tags      []ExportTag
 	EntityTags []ExportEntityTag
 	Relations []ExportRelation
+
+	// OriginalCampaignID is the exported campaign's ID, for remapping links.
+	OriginalCampaignID string
 }
 
 // EntityExporter gathers all entity-related data for a campaign export.
@@ -273,9 +276,11 @@ func (s *ExportImportService) Export(ctx context.Context, campaignID string) (*C
 		Version:    ExportVersion,
 		ExportedAt: time.Now().UTC(),
 		Campaign: ExportCampaignMeta{
+			OriginalID:  campaign.ID,
 			Name:        campaign.Name,
 			Description: campaign.Description,
 			IsPublic:    campaign.IsPublic,
+			License:     newExportLicense(campaign.ParseSettings().GetLicense()),
 		},
 	}
 
@@ -413,17 +418,20 @@ func (s *ExportImportService) Export(ctx context.Context, campaignID string) (*C
 }
 
 // Import creates a new campaign from a CampaignExport. Returns the newly
-// created campaign. The import processes data in dependency order:
+// created campaign and a summary of what was created and skipped. The
+// import processes data in dependency order:
 // 1. Campaign metadata → 2. Entity types + entities + tags + relations →
 // 3. Calendar → 4. Timelines → 5. Sessions → 6. Maps → 7. Notes → 8. Addons
-func (s *ExportImportService) Import(ctx context.Context, userID string, data *CampaignExport) (*Campaign, error) {
+// Only a failure to create the campaign or its entities aborts the import;
+// later sections that fail are reported as summary warnings.
+func (s *ExportImportService) Import(ctx context.Context, userID string, data *CampaignExport) (*Campaign, *ImportSummary, error) {
 	// Create the new campaign.
 	campaign, err := s.campaigns.Create(ctx, userID, CreateCampaignInput{
 		Name:        data.Campaign.Name,
 		Description: ptrToString(data.Campaign.Description),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("import create campaign: %w", err)
+		return nil, nil, fmt.Errorf("import create campaign: %w", err)
 	}
 
 	campaignID := campaign.ID
@@ -468,21 +476,26 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 			Tags:       data.Tags,
 			EntityTags: data.EntityTags,
 			Relations:  data.Relations,
+
+			OriginalCampaignID: data.Campaign.OriginalID,
 		}
 		idMap, err = s.entityImp.ImportEntities(ctx, campaignID, userID, entityData)
 		if err != nil {
-			return nil, fmt.Errorf("import entities: %w", err)
+			return nil, nil, fmt.Errorf("import entities: %w", err)
 		}
 	}
 	if idMap == nil {
 		idMap = NewIDMap(campaignID)
+		idMap.OriginalCampaignID = data.Campaign.OriginalID
 	}
+	summary := idMap.Summary
 
 	// Import campaign groups (before calendar, since group-based permission
 	// grants may reference group IDs).
 	if s.groupImp != nil && len(data.Groups) > 0 {
 		if err := s.groupImp.ImportGroups(ctx, campaignID, data.Groups); err != nil {
 			slog.Warn("import groups failed", slog.Any("error", err))
+			summary.Warn("Groups could not be imported")
 		}
 	}
 
@@ -490,6 +503,9 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.calendarImp != nil && data.Calendar != nil {
 		if err := s.calendarImp.ImportCalendar(ctx, campaignID, data.Calendar, idMap); err != nil {
 			slog.Warn("import calendar failed", slog.Any("error", err))
+			summary.Skip(ImportKindCalendars, data.Calendar.Name, "could not be created")
+		} else {
+			summary.Created(ImportKindCalendars)
 		}
 	}
 
@@ -497,6 +513,7 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.timelineImp != nil && len(data.Timelines) > 0 {
 		if err := s.timelineImp.ImportTimelines(ctx, campaignID, userID, data.Timelines, idMap); err != nil {
 			slog.Warn("import timelines failed", slog.Any("error", err))
+			summary.Warn("Timelines could not be imported")
 		}
 	}
 
@@ -504,6 +521,7 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.sessionImp != nil && len(data.Sessions) > 0 {
 		if err := s.sessionImp.ImportSessions(ctx, campaignID, userID, data.Sessions, idMap); err != nil {
 			slog.Warn("import sessions failed", slog.Any("error", err))
+			summary.Warn("Sessions could not be imported")
 		}
 	}
 
@@ -511,6 +529,7 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.mapImp != nil && len(data.Maps) > 0 {
 		if err := s.mapImp.ImportMaps(ctx, campaignID, userID, data.Maps, idMap); err != nil {
 			slog.Warn("import maps failed", slog.Any("error", err))
+			summary.Warn("Maps could not be imported")
 		}
 	}
 
@@ -518,6 +537,7 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.noteImp != nil && len(data.Notes) > 0 {
 		if err := s.noteImp.ImportNotes(ctx, campaignID, userID, data.Notes, idMap); err != nil {
 			slog.Warn("import notes failed", slog.Any("error", err))
+			summary.Warn("Notes could not be imported")
 		}
 	}
 
@@ -525,6 +545,7 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.postImp != nil && len(data.Posts) > 0 {
 		if err := s.postImp.ImportPosts(ctx, campaignID, userID, data.Posts, idMap); err != nil {
 			slog.Warn("import posts failed", slog.Any("error", err))
+			summary.Warn("Posts could not be imported")
 		}
 	}
 
@@ -532,10 +553,11 @@ func (s *ExportImportService) Import(ctx context.Context, userID string, data *C
 	if s.addonImp != nil && len(data.Addons) > 0 {
 		if err := s.addonImp.ImportAddons(ctx, campaignID, userID, data.Addons); err != nil {
 			slog.Warn("import addons failed", slog.Any("error", err))
+			summary.Warn("Addons could not be imported")
 		}
 	}
 
-	return campaign, nil
+	return campaign, summary, nil
 }
 
 // Validate checks a CampaignExport for structural integrity before import.
@@ -100,6 +100,21 @@ func (r *mockGroupRepo) ListGroupMembers(_ context.Context, groupID int) ([]Grou
 	return r.members[groupID], nil
 }
 
+func (r *mockGroupRepo) ListUserGroupIDs(_ context.Context, campaignID, userID string) ([]int, error) {
+	var ids []int
+	for id, g := range r.groups {
+		if g.CampaignID != campaignID {
+			continue
+		}
+		for _, m := range r.members[id] {
+			if m.UserID == userID {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
 // --- Tests ---
 
 func TestGroupService_CreateGroup(t *testing.T) {
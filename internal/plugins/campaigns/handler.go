@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 	"github.com/keyxmakerx/chronicle/internal/middleware"
@@ -35,6 +36,9 @@ type SettingsEntityType struct {
 	Color        string  `json:"color"`
 	Description  *string `json:"description,omitempty"`
 	ParentTypeID *int    `json:"parent_type_id,omitempty"`
+
+	// HasDashboardLayout is true when the category dashboard is customized.
+	HasDashboardLayout bool `json:"has_dashboard_layout,omitempty"`
 }
 
 // EntityTypeLayoutFetcher fetches a single entity type's full layout and field
@@ -76,12 +80,38 @@ type RecentEntity struct {
 	UpdatedAt time.Time
 }
 
+// DraftCounter counts a campaign's draft pages for the owner dashboard.
+// Avoids importing the entities package directly.
+type DraftCounter interface {
+	CountDrafts(ctx context.Context, campaignID string) (int, error)
+}
+
+// WelcomeEntityFetcher looks up the entity pinned to the welcome page,
+// applying the viewer's visibility. Avoids importing the entities package.
+type WelcomeEntityFetcher interface {
+	GetWelcomeEntity(ctx context.Context, campaignID, entityID string, role int, userID string) (*RecentEntity, error)
+}
+
 // AuditLogger records audit events for campaign-scoped actions. Defined here
 // as an interface to avoid circular imports with the audit plugin.
 type AuditLogger interface {
 	LogEvent(ctx context.Context, campaignID, userID, action string, details map[string]any) error
 }
 
+// UserNotifier writes an in-app notification for a user. Implemented by the
+// sessions plugin's notification store and injected via SetNotifier.
+type UserNotifier interface {
+	NotifyUser(ctx context.Context, userID, campaignID, kind, message, link string) error
+}
+
+// Notification kinds for the ownership transfer lifecycle.
+const (
+	NotifTransferOffered   = "ownership_transfer_offered"
+	NotifTransferAccepted  = "ownership_transfer_accepted"
+	NotifTransferDeclined  = "ownership_transfer_declined"
+	NotifTransferCancelled = "ownership_transfer_cancelled"
+)
+
 // PluginHubAddon is a minimal addon representation for the plugin hub page
 // and the C-EXT-HUB top-level Extensions hub.
 //
@@ -161,7 +191,16 @@ type Handler struct {
 	entityLister  EntityTypeLister
 	layoutFetcher EntityTypeLayoutFetcher
 	recentLister  RecentEntityLister
+	draftCounter  DraftCounter
+	welcomeEntity WelcomeEntityFetcher
 	auditLogger   AuditLogger
+	notifier      UserNotifier
+	announcements AnnouncementService
+	houseRules    HouseRulesService
+	digests       DigestService
+	directory     DirectoryService
+	layoutVersions LayoutVersionService
+	blockChecker   DashboardBlockChecker
 	addonLister       AddonLister
 	systemAddonEnabler SystemAddonEnabler
 	mediaUploader     MediaUploader
@@ -173,6 +212,9 @@ type Handler struct {
 	// CampaignContext so plugin-side tab content can bind
 	// cc.Campaign.ID into form URLs and similar.
 	extraSettingsTabs    []func(*CampaignContext) SettingsTab
+	// extraSettingsSections holds plugin cards placed inside existing
+	// tabs (settings_sections.go).
+	extraSettingsSections []func(*CampaignContext) SettingsSection
 	baseURL              string
 	contentPacksRenderer ContentPacksCardRenderer
 	// C-EXT-HUB Phase 2: per-extension inline dashboard registry +
@@ -180,6 +222,18 @@ type Handler struct {
 	// renders safe placeholders when either is unwired.
 	extensionDashboardFactories []func(*CampaignContext) ExtensionDashboard
 	extensionEnableChecker      ExtensionEnableChecker
+	// Lazy dashboard block bodies by block type, and their cache
+	// (dashboard_fragments.go).
+	fragments     map[string]DashboardFragment
+	fragmentCache *redis.Client
+
+	// Stream overlay widget data by widget key (overlays.go).
+	overlaySources map[string]OverlayDataFunc
+
+	// Stream mode content (stream_mode.go).
+	streamPages    StreamPageFunc
+	streamCalendar StreamCalendarFunc
+	streamImageSrc StreamImageSrcFunc
 }
 
 // NewHandler creates a new campaign handler.
@@ -208,6 +262,21 @@ func (h *Handler) SetLayoutFetcher(fetcher EntityTypeLayoutFetcher) {
 // Called after both plugins are wired to avoid circular dependencies.
 func (h *Handler) SetRecentEntityLister(lister RecentEntityLister) {
 	h.recentLister = lister
+	h.RegisterDashboardFragment(BlockRecentPages, DashboardFragment{
+		Render: h.recentPagesFragment,
+		Fresh:  30 * time.Second,
+		Stale:  10 * time.Minute,
+	})
+}
+
+// SetDraftCounter sets the draft counter for the owner dashboard.
+func (h *Handler) SetDraftCounter(counter DraftCounter) {
+	h.draftCounter = counter
+}
+
+// SetWelcomeEntityFetcher sets the lookup for the welcome page's pinned entity.
+func (h *Handler) SetWelcomeEntityFetcher(f WelcomeEntityFetcher) {
+	h.welcomeEntity = f
 }
 
 // SetAuditLogger sets the audit logger for recording campaign mutations.
@@ -216,6 +285,37 @@ func (h *Handler) SetAuditLogger(logger AuditLogger) {
 	h.auditLogger = logger
 }
 
+// SetNotifier sets where ownership transfer notifications go.
+func (h *Handler) SetNotifier(n UserNotifier) {
+	h.notifier = n
+}
+
+// SetAnnouncementService sets the service behind campaign announcements.
+func (h *Handler) SetAnnouncementService(svc AnnouncementService) {
+	h.announcements = svc
+}
+
+// SetHouseRulesService sets the service behind the house rules page.
+func (h *Handler) SetHouseRulesService(svc HouseRulesService) {
+	h.houseRules = svc
+}
+
+// SetLayoutVersionService sets the undo history for dashboard layouts.
+func (h *Handler) SetLayoutVersionService(svc LayoutVersionService) {
+	h.layoutVersions = svc
+}
+
+// SetDigestService sets the service behind the weekly digest email toggle.
+func (h *Handler) SetDigestService(svc DigestService) {
+	h.digests = svc
+}
+
+// SetDirectoryService sets the service behind the public directory listing
+// card.
+func (h *Handler) SetDirectoryService(svc DirectoryService) {
+	h.directory = svc
+}
+
 // SetAddonLister sets the addon lister for the plugin hub page.
 func (h *Handler) SetAddonLister(lister AddonLister) {
 	h.addonLister = lister
@@ -255,6 +355,25 @@ func (h *Handler) SetSystemLister(lister SystemLister) {
 
 // logAudit fires a fire-and-forget audit entry. Errors are logged but
 // never block the primary operation.
+// notify writes an in-app notification when a notifier is wired. Failures
+// are logged: the action it reports has already happened.
+func (h *Handler) notify(c echo.Context, userID, campaignID, kind, message, link string) {
+	if h.notifier == nil {
+		return
+	}
+	if err := h.notifier.NotifyUser(c.Request().Context(), userID, campaignID, kind, message, link); err != nil {
+		slog.Warn("notification failed", slog.String("kind", kind), slog.Any("error", err))
+	}
+}
+
+// sessionName is the signed-in user's display name for notification text.
+func sessionName(c echo.Context) string {
+	if s := auth.GetSession(c); s != nil && s.Name != "" {
+		return s.Name
+	}
+	return "The recipient"
+}
+
 func (h *Handler) logAudit(c echo.Context, campaignID, action string, details map[string]any) {
 	if h.auditLogger == nil {
 		return
@@ -282,12 +401,19 @@ func (h *Handler) Index(c echo.Context) error {
 		return err
 	}
 
+	// Campaigns in their deletion grace period are hidden from the list
+	// above; surface them separately so the owner can still restore them.
+	pending, err := h.service.ListPendingDeletion(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
 	csrfToken := middleware.GetCSRFToken(c)
 
 	if middleware.IsHTMX(c) {
-		return middleware.Render(c, http.StatusOK, CampaignListContent(campaigns, total, opts, csrfToken))
+		return middleware.Render(c, http.StatusOK, CampaignListContent(campaigns, pending, total, opts, csrfToken))
 	}
-	return middleware.Render(c, http.StatusOK, CampaignIndexPage(campaigns, total, opts, csrfToken))
+	return middleware.Render(c, http.StatusOK, CampaignIndexPage(campaigns, pending, total, opts, csrfToken))
 }
 
 // Picker returns an HTMX fragment listing the user's campaigns for the
@@ -315,7 +441,7 @@ func (h *Handler) Picker(c echo.Context) error {
 // NewForm renders the campaign creation form (GET /campaigns/new).
 func (h *Handler) NewForm(c echo.Context) error {
 	csrfToken := middleware.GetCSRFToken(c)
-	return middleware.Render(c, http.StatusOK, CampaignNewPage(csrfToken, "", ""))
+	return middleware.Render(c, http.StatusOK, CampaignNewPage(csrfToken, nil, "", h.creationAddons(c.Request().Context())))
 }
 
 // Create processes the campaign creation form (POST /campaigns).
@@ -344,9 +470,9 @@ func (h *Handler) Create(c echo.Context) error {
 		csrfToken := middleware.GetCSRFToken(c)
 		errMsg := apperror.UserMessage(err, "failed to create campaign")
 		if middleware.IsHTMX(c) {
-			return middleware.Render(c, http.StatusOK, CampaignCreateForm(csrfToken, &req, errMsg))
+			return middleware.Render(c, http.StatusOK, CampaignCreateForm(csrfToken, &req, errMsg, h.creationAddons(c.Request().Context())))
 		}
-		return middleware.Render(c, http.StatusOK, CampaignNewPage(csrfToken, req.Name, errMsg))
+		return middleware.Render(c, http.StatusOK, CampaignNewPage(csrfToken, &req, errMsg, h.creationAddons(c.Request().Context())))
 	}
 
 	return middleware.HTMXRedirect(c, "/campaigns/"+campaign.ID)
@@ -364,14 +490,6 @@ func (h *Handler) Show(c echo.Context) error {
 	// Check for pending transfer to show banner.
 	transfer, _ := h.service.GetPendingTransfer(c.Request().Context(), cc.Campaign.ID)
 
-	// Fetch recently updated pages for the dashboard.
-	var recentEntities []RecentEntity
-	if h.recentLister != nil {
-		recentEntities, _ = h.recentLister.ListRecentForDashboard(
-			c.Request().Context(), cc.Campaign.ID, int(cc.MemberRole), auth.GetUserID(c), 8,
-		)
-	}
-
 	csrfToken := middleware.GetCSRFToken(c)
 
 	// VTT update-available banner is now lazy-loaded from
@@ -382,7 +500,19 @@ func (h *Handler) Show(c echo.Context) error {
 	// (the move) + the D2-cleanup PR that removed the now-orphaned
 	// data flow.
 
-	return middleware.Render(c, http.StatusOK, CampaignShowPage(cc, transfer, recentEntities, csrfToken))
+	// Members other than the owner see their checklist until it's done.
+	var onboarding *OnboardingStatus
+	if cc.MemberRole >= RolePlayer && cc.MemberRole < RoleOwner {
+		onboarding, _ = h.service.GetOnboardingStatus(c.Request().Context(), cc.Campaign, auth.GetUserID(c))
+	}
+
+	// Group-limited blocks need the viewer's groups at render time.
+	if DashboardHasGroupRules(cc.Campaign.ParseRoleDashboardLayout(cc.MemberRole)) {
+		LoadViewerGroups(c, cc, h.groupSvc)
+	}
+	c.SetRequest(c.Request().WithContext(withLazyDashboardBlocks(c.Request().Context(), h.fragments)))
+
+	return middleware.Render(c, http.StatusOK, CampaignShowPage(cc, transfer, onboarding, csrfToken))
 }
 
 // EditForm redirects to the unified settings page (GET /campaigns/:id/edit).
@@ -414,7 +544,7 @@ func (h *Handler) Update(c echo.Context) error {
 		errMsg := apperror.UserMessage(err, "failed to update campaign")
 		csrfToken := middleware.GetCSRFToken(c)
 		transfer, _ := h.service.GetPendingTransfer(c.Request().Context(), cc.Campaign.ID)
-		tabs := h.visibleSettingsTabs(cc, transfer, nil, csrfToken, nil, false)
+		tabs := h.settingsTabsFor(c.Request().Context(), cc, transfer, nil, csrfToken, nil, false)
 		return middleware.Render(c, http.StatusOK, CampaignSettingsPage(cc, transfer, csrfToken, errMsg, "general", tabs))
 	}
 
@@ -442,14 +572,34 @@ func (h *Handler) Delete(c echo.Context) error {
 		return apperror.NewBadRequest("campaign name does not match; deletion cancelled")
 	}
 
-	if err := h.service.Delete(c.Request().Context(), cc.Campaign.ID); err != nil {
+	// Mark rather than delete: the purge job removes the campaign once the
+	// grace period ends, and until then the owner can restore it.
+	purgeAfter, err := h.service.MarkForDeletion(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
 		return err
 	}
 
-	h.logAudit(c, cc.Campaign.ID, "campaign.deleted", nil)
+	h.logAudit(c, cc.Campaign.ID, "campaign.deletion_requested", map[string]any{
+		"purge_after": purgeAfter.Format(time.RFC3339),
+	})
 	return middleware.HTMXRedirect(c, "/campaigns")
 }
 
+// RestoreCampaign cancels a pending deletion (POST /campaigns/:id/restore).
+func (h *Handler) RestoreCampaign(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	if err := h.service.RestoreDeletion(c.Request().Context(), cc.Campaign.ID); err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.restored", nil)
+	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID)
+}
+
 // --- Backdrop & Branding ---
 
 // UploadBackdrop handles POST /campaigns/:id/backdrop. Accepts an image file,
@@ -948,6 +1098,136 @@ func (h *Handler) UpdateDefaultVisibilityAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// UpdateRetentionAPI handles PUT /campaigns/:id/retention. Sets how long audit
+// entries, notifications and API request logs are kept (0 = forever).
+func (h *Handler) UpdateRetentionAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change data retention")
+	}
+
+	var req RetentionSettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateRetention(c.Request().Context(), cc.Campaign.ID, req); err != nil {
+		return err
+	}
+
+	// Logged before any purge runs, so the change itself survives a shorter
+	// audit window for at least retentionMinAuditDays.
+	h.logAudit(c, cc.Campaign.ID, "campaign.retention.updated", map[string]any{
+		"audit_days":        req.AuditDays,
+		"notification_days": req.NotificationDays,
+		"request_log_days":  req.RequestLogDays,
+	})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateImageProxyAPI handles PUT /campaigns/:id/image-proxy. Turns the
+// external image proxy on or off and sets its host allowlist.
+func (h *Handler) UpdateImageProxyAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change the image proxy")
+	}
+
+	var req ImageProxySettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateImageProxy(c.Request().Context(), cc.Campaign.ID, req); err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.image_proxy.updated", map[string]any{
+		"enabled":       req.Enabled,
+		"allowed_hosts": strings.Join(req.AllowedHosts, ", "),
+	})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateLicenseAPI handles PUT /campaigns/:id/license. Sets the content
+// license and attribution shown on public pages and in exports.
+func (h *Handler) UpdateLicenseAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change the license")
+	}
+
+	var req LicenseSettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateLicense(c.Request().Context(), cc.Campaign.ID, req); err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.license.updated", map[string]any{
+		"license": req.License,
+	})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// GetDirectoryListingAPI handles GET /campaigns/:id/directory. Returns the
+// campaign's directory opt-in and tags for the settings card.
+func (h *Handler) GetDirectoryListingAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.directory == nil {
+		return apperror.NewMissingContext()
+	}
+	listing, err := h.directory.GetListing(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, listing)
+}
+
+// UpdateDirectoryListingAPI handles PUT /campaigns/:id/directory. Lists or
+// unlists the campaign in the public directory and sets its tags. Listing
+// only takes effect while the campaign is public.
+func (h *Handler) UpdateDirectoryListingAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.directory == nil {
+		return apperror.NewMissingContext()
+	}
+
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change the directory listing")
+	}
+
+	var req DirectoryListing
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	listing, err := h.directory.UpdateListing(c.Request().Context(), cc.Campaign.ID, req)
+	if err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.directory.updated", map[string]any{
+		"listed": listing.Listed,
+		"tags":   strings.Join(listing.Tags, ", "),
+	})
+	return c.JSON(http.StatusOK, listing)
+}
+
 // --- Settings ---
 
 // Settings renders the campaign settings page (GET /campaigns/:id/settings).
@@ -988,7 +1268,7 @@ func (h *Handler) Settings(c echo.Context) error {
 	// `/campaigns/:id/extensions`. The addons-store load goes with
 	// the tab — no remaining built-in tab needs it.
 
-	tabs := h.visibleSettingsTabs(cc, transfer, members, csrfToken, systemOptions, smtpConfigured)
+	tabs := h.settingsTabsFor(ctx, cc, transfer, members, csrfToken, systemOptions, smtpConfigured)
 
 	// Resolve the requested tab against the set actually visible to this
 	// viewer. The raw `?tab=` value is attacker-controllable and flows
@@ -1214,12 +1494,17 @@ func (h *Handler) UpdateDashboardLayout(c echo.Context) error {
 		return apperror.NewInternal(fmt.Errorf("marshaling role layout: %w", err))
 	}
 
+	h.recordLayoutVersion(c, LayoutKindDashboard, campaign.DashboardLayout, "Saved "+roleName+" layout")
 	if err := h.service.UpdateDashboardLayoutRaw(c.Request().Context(), cc.Campaign.ID, fullJSON); err != nil {
 		return err
 	}
 
 	h.logAudit(c, cc.Campaign.ID, "dashboard_layout_updated", map[string]any{"role": roleName})
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	// Accessibility findings are advisory: the layout is already saved.
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":   "ok",
+		"warnings": AuditDashboardLayout(&layout),
+	})
 }
 
 // ResetDashboardLayout removes the custom dashboard layout (DELETE /campaigns/:id/dashboard-layout).
@@ -1236,6 +1521,12 @@ func (h *Handler) ResetDashboardLayout(c echo.Context) error {
 		roleName = "default"
 	}
 
+	campaign, err := h.service.GetByID(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	h.recordLayoutVersion(c, LayoutKindDashboard, campaign.DashboardLayout, "Reset "+roleName+" layout")
+
 	// For "default" reset with no role param, clear the entire column.
 	if roleName == "default" {
 		if err := h.service.ResetDashboardLayout(c.Request().Context(), cc.Campaign.ID); err != nil {
@@ -1243,10 +1534,6 @@ func (h *Handler) ResetDashboardLayout(c echo.Context) error {
 		}
 	} else {
 		// Remove just this role's layout from the wrapper.
-		campaign, err := h.service.GetByID(c.Request().Context(), cc.Campaign.ID)
-		if err != nil {
-			return err
-		}
 		fullJSON, err := campaign.RemoveRoleDashboardJSON(roleName)
 		if err != nil {
 			return apperror.NewInternal(fmt.Errorf("removing role layout: %w", err))
@@ -1276,8 +1563,19 @@ func (h *Handler) OwnerDashboard(c echo.Context) error {
 		)
 	}
 
+	// Drafts awaiting publication. A lookup failure just hides the notice.
+	drafts := 0
+	if h.draftCounter != nil {
+		n, err := h.draftCounter.CountDrafts(c.Request().Context(), cc.Campaign.ID)
+		if err != nil {
+			slog.Warn("counting drafts for owner dashboard", slog.String("campaign_id", cc.Campaign.ID), slog.Any("error", err))
+		}
+		drafts = n
+	}
+
 	csrfToken := middleware.GetCSRFToken(c)
-	return middleware.Render(c, http.StatusOK, OwnerDashboardPage(cc, recentEntities, csrfToken))
+	c.SetRequest(c.Request().WithContext(withLazyDashboardBlocks(c.Request().Context(), h.fragments)))
+	return middleware.Render(c, http.StatusOK, OwnerDashboardPage(cc, recentEntities, drafts, csrfToken))
 }
 
 // GetOwnerDashboardLayout returns the owner dashboard layout JSON (GET /campaigns/:id/owner-dashboard-layout).
@@ -1307,12 +1605,21 @@ func (h *Handler) UpdateOwnerDashboardLayout(c echo.Context) error {
 		return apperror.NewBadRequest("invalid JSON body")
 	}
 
+	if err := validateDashboardLayout(&layout); err != nil {
+		return err
+	}
+	if err := h.recordCurrentOwnerDashboard(c, "Saved owner dashboard"); err != nil {
+		return err
+	}
 	if err := h.service.UpdateOwnerDashboardLayout(c.Request().Context(), cc.Campaign.ID, &layout); err != nil {
 		return err
 	}
 
 	h.logAudit(c, cc.Campaign.ID, "owner_dashboard_layout_updated", nil)
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":   "ok",
+		"warnings": AuditDashboardLayout(&layout),
+	})
 }
 
 // ResetOwnerDashboardLayout removes the custom owner dashboard layout (DELETE /campaigns/:id/owner-dashboard-layout).
@@ -1322,6 +1629,9 @@ func (h *Handler) ResetOwnerDashboardLayout(c echo.Context) error {
 		return apperror.NewMissingContext()
 	}
 
+	if err := h.recordCurrentOwnerDashboard(c, "Reset owner dashboard"); err != nil {
+		return err
+	}
 	if err := h.service.ResetOwnerDashboardLayout(c.Request().Context(), cc.Campaign.ID); err != nil {
 		return err
 	}
@@ -1547,7 +1857,7 @@ func (h *Handler) TransferForm(c echo.Context) error {
 
 	transfer, _ := h.service.GetPendingTransfer(c.Request().Context(), cc.Campaign.ID)
 	csrfToken := middleware.GetCSRFToken(c)
-	tabs := h.visibleSettingsTabs(cc, transfer, nil, csrfToken, nil, false)
+	tabs := h.settingsTabsFor(c.Request().Context(), cc, transfer, nil, csrfToken, nil, false)
 	return middleware.Render(c, http.StatusOK, CampaignSettingsPage(cc, transfer, csrfToken, "", "general", tabs))
 }
 
@@ -1564,34 +1874,81 @@ func (h *Handler) Transfer(c echo.Context) error {
 	}
 
 	userID := auth.GetUserID(c)
-	_, err := h.service.InitiateTransfer(c.Request().Context(), cc.Campaign.ID, userID, req.Email)
+	transfer, err := h.service.InitiateTransfer(c.Request().Context(), cc.Campaign.ID, userID, req.Email)
 	if err != nil {
 		transfer, _ := h.service.GetPendingTransfer(c.Request().Context(), cc.Campaign.ID)
 		csrfToken := middleware.GetCSRFToken(c)
 		errMsg := apperror.UserMessage(err, "failed to initiate transfer")
-		tabs := h.visibleSettingsTabs(cc, transfer, nil, csrfToken, nil, false)
+		tabs := h.settingsTabsFor(c.Request().Context(), cc, transfer, nil, csrfToken, nil, false)
 		return middleware.Render(c, http.StatusOK, CampaignSettingsPage(cc, transfer, csrfToken, errMsg, "general", tabs))
 	}
 
+	h.logAudit(c, cc.Campaign.ID, "campaign.transfer.initiated", map[string]any{"to_user_id": transfer.ToUserID})
+	// The link carries no token: the offer page finds the campaign's
+	// pending transfer for its recipient.
+	h.notify(c, transfer.ToUserID, cc.Campaign.ID, NotifTransferOffered,
+		fmt.Sprintf("You've been offered ownership of %q", cc.Campaign.Name),
+		"/campaigns/"+cc.Campaign.ID+"/accept-transfer")
 	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/settings")
 }
 
-// AcceptTransfer accepts a pending ownership transfer (GET /campaigns/:id/accept-transfer).
+// AcceptTransfer renders the transfer confirmation page for the recipient
+// (GET /campaigns/:id/accept-transfer). The token from the offer email is
+// optional; see GetTransferOffer.
 func (h *Handler) AcceptTransfer(c echo.Context) error {
-	token := c.QueryParam("token")
-	if token == "" {
-		return apperror.NewBadRequest("transfer token is required")
+	offer, err := h.service.GetTransferOffer(c.Request().Context(), c.Param("id"), c.QueryParam("token"), auth.GetUserID(c))
+	if err != nil {
+		return middleware.Render(c, apperror.SafeCode(err), TransferOfferPage(nil, apperror.UserMessage(err, "This transfer can't be opened."), ""))
 	}
+	return middleware.Render(c, http.StatusOK, TransferOfferPage(offer, "", middleware.GetCSRFToken(c)))
+}
 
+// ConfirmTransfer completes a transfer from the confirmation page
+// (POST /campaigns/:id/accept-transfer).
+func (h *Handler) ConfirmTransfer(c echo.Context) error {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
 	userID := auth.GetUserID(c)
-	if err := h.service.AcceptTransfer(c.Request().Context(), token, userID); err != nil {
+
+	// Re-resolve the offer so the token is checked against this campaign
+	// and we know whom to tell.
+	offer, err := h.service.GetTransferOffer(ctx, campaignID, c.FormValue("token"), userID)
+	if err != nil {
+		return err
+	}
+	if err := h.service.AcceptTransfer(ctx, offer.Transfer.Token, userID); err != nil {
 		return err
 	}
 
-	campaignID := c.Param("id")
+	h.logAudit(c, campaignID, "campaign.transfer.accepted", map[string]any{"from_user_id": offer.Transfer.FromUserID})
+	h.notify(c, offer.Transfer.FromUserID, campaignID, NotifTransferAccepted,
+		fmt.Sprintf("%s accepted ownership of %q; you are now a Scribe", sessionName(c), offer.Campaign.Name),
+		"/campaigns/"+campaignID)
 	return middleware.HTMXRedirect(c, "/campaigns/"+campaignID)
 }
 
+// DeclineTransfer turns down a pending transfer
+// (POST /campaigns/:id/decline-transfer).
+func (h *Handler) DeclineTransfer(c echo.Context) error {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+
+	transfer, err := h.service.DeclineTransfer(ctx, campaignID, c.FormValue("token"), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+
+	campaignName := "your campaign"
+	if campaign, err := h.service.GetByID(ctx, campaignID); err == nil {
+		campaignName = campaign.Name
+	}
+	h.logAudit(c, campaignID, "campaign.transfer.declined", map[string]any{"from_user_id": transfer.FromUserID})
+	h.notify(c, transfer.FromUserID, campaignID, NotifTransferDeclined,
+		fmt.Sprintf("%s declined ownership of %q", sessionName(c), campaignName),
+		"/campaigns/"+campaignID+"/settings")
+	return middleware.HTMXRedirect(c, "/campaigns")
+}
+
 // CancelTransfer cancels a pending ownership transfer (POST /campaigns/:id/cancel-transfer).
 func (h *Handler) CancelTransfer(c echo.Context) error {
 	cc := GetCampaignContext(c)
@@ -1599,10 +1956,17 @@ func (h *Handler) CancelTransfer(c echo.Context) error {
 		return apperror.NewMissingContext()
 	}
 
+	// Load the transfer first: once cancelled we can't tell whom it was for.
+	transfer, _ := h.service.GetPendingTransfer(c.Request().Context(), cc.Campaign.ID)
 	if err := h.service.CancelTransfer(c.Request().Context(), cc.Campaign.ID); err != nil {
 		return err
 	}
 
+	if transfer != nil {
+		h.logAudit(c, cc.Campaign.ID, "campaign.transfer.cancelled", map[string]any{"to_user_id": transfer.ToUserID})
+		h.notify(c, transfer.ToUserID, cc.Campaign.ID, NotifTransferCancelled,
+			fmt.Sprintf("The offer to take over %q was withdrawn", cc.Campaign.Name), "")
+	}
 	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/settings")
 }
 
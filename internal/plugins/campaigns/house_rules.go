@@ -0,0 +1,319 @@
+package campaigns
+
+// house_rules.go — the campaign's house rules page. The owner keeps one
+// markdown document apart from entities; every save is a numbered revision
+// that members can look back through and the owner can restore. With
+// "require acknowledgement" on, members are nagged on the dashboard until
+// they acknowledge the rules. Small fixes don't reset anyone: only a save
+// where the owner asks members to read the change again moves the version
+// an acknowledgement has to reach (AckVersion). The onboarding checklist's
+// "house_rules" item completes on the same acknowledgement.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+const (
+	// maxHouseRulesBodyLength caps the house rules' markdown source.
+	maxHouseRulesBodyLength = 50000
+
+	// maxHouseRulesNoteLength caps a revision's change note.
+	maxHouseRulesNoteLength = 200
+)
+
+// NotifHouseRulesUpdated is the notification kind sent to members when the
+// owner asks them to read a change to the house rules.
+const NotifHouseRulesUpdated = "campaign_house_rules_updated"
+
+// HouseRules is the campaign's house rules state. Version is 0 until the
+// first save, and Current is nil until then.
+type HouseRules struct {
+	CampaignID string
+	Version    int
+	RequireAck bool
+
+	// AckVersion is the oldest revision an acknowledgement still counts
+	// for. 0 means any acknowledgement does.
+	AckVersion int
+
+	UpdatedAt time.Time
+	Current   *HouseRulesRevision
+}
+
+// Exists reports whether the owner has written any house rules.
+func (r *HouseRules) Exists() bool {
+	return r != nil && r.Version > 0
+}
+
+// Acknowledged reports whether ack still covers the rules.
+func (r *HouseRules) Acknowledged(ack *HouseRulesAck) bool {
+	return ack != nil && ack.Version >= max(r.AckVersion, 1)
+}
+
+// HouseRulesRevision is one saved version of the house rules.
+type HouseRulesRevision struct {
+	CampaignID   string    `json:"-"`
+	Version      int       `json:"version"`
+	Body         string    `json:"body"`
+	BodyHTML     string    `json:"body_html"`
+	Note         string    `json:"note"`
+	EditedBy     string    `json:"-"`
+	EditedByName string    `json:"edited_by_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// HouseRulesAck is the newest revision a member has acknowledged.
+type HouseRulesAck struct {
+	UserID         string
+	Version        int
+	AcknowledgedAt time.Time
+}
+
+// HouseRulesInput is the save request body. Body is markdown. RequestAck
+// asks members to read and acknowledge this change.
+type HouseRulesInput struct {
+	Body       string `json:"body"`
+	Note       string `json:"note"`
+	RequestAck bool   `json:"request_ack"`
+}
+
+// normalize trims and validates the input.
+func (in *HouseRulesInput) normalize() error {
+	in.Body = strings.TrimSpace(in.Body)
+	in.Note = strings.TrimSpace(in.Note)
+	if in.Body == "" {
+		return apperror.NewBadRequest("the house rules can't be empty")
+	}
+	if utf8.RuneCountInString(in.Body) > maxHouseRulesBodyLength {
+		return apperror.NewBadRequest(fmt.Sprintf("house rules must be %d characters or fewer", maxHouseRulesBodyLength))
+	}
+	if utf8.RuneCountInString(in.Note) > maxHouseRulesNoteLength {
+		return apperror.NewBadRequest(fmt.Sprintf("the change note must be %d characters or fewer", maxHouseRulesNoteLength))
+	}
+	return nil
+}
+
+// HouseRulesView is the house rules page for one member.
+type HouseRulesView struct {
+	Rules *HouseRules
+	MyAck *HouseRulesAck
+
+	// Pending is true when the member still has to acknowledge the rules.
+	Pending bool
+}
+
+// HouseRulesMemberAck is one member's acknowledgement, for the owner's
+// tracking list. Ack is nil when they never acknowledged.
+type HouseRulesMemberAck struct {
+	Member  CampaignMember
+	Ack     *HouseRulesAck
+	Current bool
+}
+
+// HouseRulesService handles business logic for the house rules page.
+type HouseRulesService interface {
+	// Get returns the house rules for a member. Owners are never pending.
+	Get(ctx context.Context, campaignID, userID string, role Role) (*HouseRulesView, error)
+
+	// Save stores a new revision. Saving unchanged text is rejected.
+	Save(ctx context.Context, campaignID, editorID string, in HouseRulesInput) (*HouseRulesRevision, error)
+
+	// Restore saves an old revision's text as a new revision.
+	Restore(ctx context.Context, campaignID, editorID string, version int) (*HouseRulesRevision, error)
+
+	SetRequireAck(ctx context.Context, campaignID string, require bool) error
+	Acknowledge(ctx context.Context, campaignID, userID string) error
+	ListRevisions(ctx context.Context, campaignID string) ([]HouseRulesRevision, error)
+	GetRevision(ctx context.Context, campaignID string, version int) (*HouseRulesRevision, error)
+
+	// Acknowledgements lists every member but the owners with their
+	// acknowledgement, for the owner's tracking list.
+	Acknowledgements(ctx context.Context, campaignID string) ([]HouseRulesMemberAck, error)
+
+	// SetNotifier wires in-app notifications; nil skips them.
+	SetNotifier(n UserNotifier)
+}
+
+// houseRulesService implements HouseRulesService.
+type houseRulesService struct {
+	repo     HouseRulesRepository
+	members  MemberLister
+	notifier UserNotifier
+}
+
+// NewHouseRulesService creates a new house rules service.
+func NewHouseRulesService(repo HouseRulesRepository, members MemberLister) HouseRulesService {
+	return &houseRulesService{repo: repo, members: members}
+}
+
+// SetNotifier sets the in-app notification sink.
+func (s *houseRulesService) SetNotifier(n UserNotifier) {
+	s.notifier = n
+}
+
+// Get loads the rules and the member's acknowledgement.
+func (s *houseRulesService) Get(ctx context.Context, campaignID, userID string, role Role) (*HouseRulesView, error) {
+	rules, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	view := &HouseRulesView{Rules: rules}
+	if userID == "" {
+		return view, nil
+	}
+	if view.MyAck, err = s.repo.GetAck(ctx, campaignID, userID); err != nil {
+		return nil, err
+	}
+	view.Pending = rules.RequireAck && rules.Exists() && role < RoleOwner && !rules.Acknowledged(view.MyAck)
+	return view, nil
+}
+
+// Save renders the markdown and stores it as the next revision. When the
+// owner asks for acknowledgement, members are notified; the first revision
+// under "require acknowledgement" always asks.
+func (s *houseRulesService) Save(ctx context.Context, campaignID, editorID string, in HouseRulesInput) (*HouseRulesRevision, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if rules.Current != nil && rules.Current.Body == in.Body {
+		return nil, apperror.NewBadRequest("the house rules haven't changed")
+	}
+	bodyHTML, err := renderHouseRulesBody(in.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &HouseRulesRevision{
+		CampaignID: campaignID,
+		Body:       in.Body,
+		BodyHTML:   bodyHTML,
+		Note:       in.Note,
+		EditedBy:   editorID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	requestAck := in.RequestAck || (rules.RequireAck && rules.AckVersion == 0)
+	if err := s.repo.CreateRevision(ctx, rev, requestAck); err != nil {
+		return nil, err
+	}
+	if requestAck {
+		s.notifyMembers(ctx, campaignID, editorID)
+	}
+	return rev, nil
+}
+
+// Restore copies an old revision forward. Members aren't asked to
+// acknowledge a restore; the owner can save again to ask.
+func (s *houseRulesService) Restore(ctx context.Context, campaignID, editorID string, version int) (*HouseRulesRevision, error) {
+	old, err := s.repo.GetRevision(ctx, campaignID, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.Save(ctx, campaignID, editorID, HouseRulesInput{
+		Body: old.Body,
+		Note: fmt.Sprintf("Restored version %d", version),
+	})
+}
+
+// SetRequireAck turns acknowledgement tracking on or off. Turning it on
+// asks everyone to acknowledge the current revision.
+func (s *houseRulesService) SetRequireAck(ctx context.Context, campaignID string, require bool) error {
+	return s.repo.SetRequireAck(ctx, campaignID, require)
+}
+
+// Acknowledge records that the member has read the current revision.
+func (s *houseRulesService) Acknowledge(ctx context.Context, campaignID, userID string) error {
+	rules, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if !rules.Exists() {
+		return apperror.NewNotFound("this campaign has no house rules yet")
+	}
+	return s.repo.Acknowledge(ctx, campaignID, userID, rules.Version)
+}
+
+// ListRevisions returns the revision history, newest first, without bodies.
+func (s *houseRulesService) ListRevisions(ctx context.Context, campaignID string) ([]HouseRulesRevision, error) {
+	return s.repo.ListRevisions(ctx, campaignID)
+}
+
+// GetRevision returns one revision.
+func (s *houseRulesService) GetRevision(ctx context.Context, campaignID string, version int) (*HouseRulesRevision, error) {
+	return s.repo.GetRevision(ctx, campaignID, version)
+}
+
+// Acknowledgements merges the member list with the stored acknowledgements.
+func (s *houseRulesService) Acknowledgements(ctx context.Context, campaignID string) ([]HouseRulesMemberAck, error) {
+	rules, err := s.repo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.members.ListMembers(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	acks, err := s.repo.ListAcks(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]*HouseRulesAck, len(acks))
+	for i := range acks {
+		byUser[acks[i].UserID] = &acks[i]
+	}
+
+	list := make([]HouseRulesMemberAck, 0, len(members))
+	for _, m := range members {
+		if m.Role >= RoleOwner {
+			continue
+		}
+		ack := byUser[m.UserID]
+		list = append(list, HouseRulesMemberAck{Member: m, Ack: ack, Current: rules.Acknowledged(ack)})
+	}
+	return list, nil
+}
+
+// notifyMembers asks every member but the editor to read the new rules.
+// Failures are logged rather than returned: the revision is saved either
+// way and the dashboard still nags.
+func (s *houseRulesService) notifyMembers(ctx context.Context, campaignID, editorID string) {
+	if s.notifier == nil || s.members == nil {
+		return
+	}
+	members, err := s.members.ListMembers(ctx, campaignID)
+	if err != nil {
+		slog.Warn("listing members for house rules failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+		return
+	}
+	link := fmt.Sprintf("/campaigns/%s/house-rules", campaignID)
+	for _, m := range members {
+		if m.UserID == editorID {
+			continue
+		}
+		if err := s.notifier.NotifyUser(ctx, m.UserID, campaignID, NotifHouseRulesUpdated, "The house rules were updated. Please read them.", link); err != nil {
+			slog.Warn("notification failed", slog.String("kind", NotifHouseRulesUpdated), slog.Any("error", err))
+		}
+	}
+}
+
+// renderHouseRulesBody converts markdown to sanitized HTML with the same
+// renderer as announcements.
+func renderHouseRulesBody(body string) (string, error) {
+	var buf bytes.Buffer
+	if err := announcementMarkdown.Convert([]byte(body), &buf); err != nil {
+		return "", apperror.NewBadRequest(fmt.Sprintf("could not read the house rules text: %v", err))
+	}
+	return sanitize.HTML(buf.String()), nil
+}
@@ -0,0 +1,157 @@
+package campaigns
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// HouseRules renders the campaign's house rules with the revision history.
+// The owner also gets the editor and the acknowledgement list
+// (GET /campaigns/:id/house-rules).
+func (h *Handler) HouseRules(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	view, err := h.houseRules.Get(ctx, cc.Campaign.ID, auth.GetUserID(c), cc.MemberRole)
+	if err != nil {
+		return err
+	}
+	revisions, err := h.houseRules.ListRevisions(ctx, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	var acks []HouseRulesMemberAck
+	if cc.MemberRole >= RoleOwner && view.Rules.Exists() {
+		if acks, err = h.houseRules.Acknowledgements(ctx, cc.Campaign.ID); err != nil {
+			return err
+		}
+	}
+	return middleware.Render(c, http.StatusOK, HouseRulesPage(cc, view, revisions, acks, middleware.GetCSRFToken(c)))
+}
+
+// HouseRulesFragment returns the dashboard's house rules card
+// (GET /campaigns/:id/house-rules/fragment). Empty until the owner writes
+// the rules.
+func (h *Handler) HouseRulesFragment(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return c.NoContent(http.StatusOK)
+	}
+	view, err := h.houseRules.Get(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), cc.MemberRole)
+	if err != nil {
+		return err
+	}
+	if !view.Rules.Exists() {
+		return c.NoContent(http.StatusOK)
+	}
+	return middleware.Render(c, http.StatusOK, houseRulesDashboardCard(cc, view, middleware.GetCSRFToken(c)))
+}
+
+// ShowHouseRulesRevision renders one old version of the house rules
+// (GET /campaigns/:id/house-rules/revisions/:version).
+func (h *Handler) ShowHouseRulesRevision(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		return apperror.NewNotFound("house rules version not found")
+	}
+	rev, err := h.houseRules.GetRevision(c.Request().Context(), cc.Campaign.ID, version)
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, HouseRulesRevisionPage(cc, rev, middleware.GetCSRFToken(c)))
+}
+
+// AcknowledgeHouseRulesAPI records that the member has read the current
+// house rules (POST /campaigns/:id/house-rules/acknowledge). The dashboard
+// card swaps itself; the page form redirects back.
+func (h *Handler) AcknowledgeHouseRulesAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	if err := h.houseRules.Acknowledge(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c)); err != nil {
+		return err
+	}
+	if !middleware.IsHTMX(c) {
+		return c.Redirect(http.StatusSeeOther, "/campaigns/"+cc.Campaign.ID+"/house-rules")
+	}
+	return h.HouseRulesFragment(c)
+}
+
+// SaveHouseRulesAPI stores a new revision of the house rules
+// (PUT /campaigns/:id/house-rules).
+func (h *Handler) SaveHouseRulesAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	var req HouseRulesInput
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	rev, err := h.houseRules.Save(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), req)
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.house_rules.updated", map[string]any{
+		"version":     rev.Version,
+		"request_ack": req.RequestAck,
+	})
+	return c.JSON(http.StatusOK, rev)
+}
+
+// UpdateHouseRulesSettingsAPI turns acknowledgement tracking on or off
+// (PUT /campaigns/:id/house-rules/settings).
+func (h *Handler) UpdateHouseRulesSettingsAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	var req struct {
+		RequireAck bool `json:"require_ack"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	if err := h.houseRules.SetRequireAck(c.Request().Context(), cc.Campaign.ID, req.RequireAck); err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.house_rules.settings_updated", map[string]any{
+		"require_ack": req.RequireAck,
+	})
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreHouseRulesRevisionAPI saves an old version as the current house
+// rules (POST /campaigns/:id/house-rules/revisions/:version/restore).
+func (h *Handler) RestoreHouseRulesRevisionAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.houseRules == nil {
+		return apperror.NewMissingContext()
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		return apperror.NewNotFound("house rules version not found")
+	}
+	rev, err := h.houseRules.Restore(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), version)
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.house_rules.restored", map[string]any{
+		"version":       rev.Version,
+		"restored_from": version,
+	})
+	return c.JSON(http.StatusOK, rev)
+}
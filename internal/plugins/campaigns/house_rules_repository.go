@@ -0,0 +1,230 @@
+package campaigns
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// HouseRulesRepository handles persistence for the house rules, their
+// revisions and members' acknowledgements.
+type HouseRulesRepository interface {
+	// Get returns the campaign's house rules with the current revision, or
+	// an empty HouseRules (Version 0) when none were ever written.
+	Get(ctx context.Context, campaignID string) (*HouseRules, error)
+
+	// CreateRevision stores rev as the next version and sets rev.Version.
+	// requestAck also makes it the version acknowledgements must reach.
+	CreateRevision(ctx context.Context, rev *HouseRulesRevision, requestAck bool) error
+
+	// ListRevisions returns every revision, newest first, without bodies.
+	ListRevisions(ctx context.Context, campaignID string) ([]HouseRulesRevision, error)
+	GetRevision(ctx context.Context, campaignID string, version int) (*HouseRulesRevision, error)
+
+	// SetRequireAck turns tracking on or off. Turning it on moves
+	// AckVersion to the current version.
+	SetRequireAck(ctx context.Context, campaignID string, require bool) error
+
+	// Acknowledge records version for the member; an older version never
+	// replaces a newer one.
+	Acknowledge(ctx context.Context, campaignID, userID string, version int) error
+	GetAck(ctx context.Context, campaignID, userID string) (*HouseRulesAck, error)
+	ListAcks(ctx context.Context, campaignID string) ([]HouseRulesAck, error)
+}
+
+// houseRulesRepository implements HouseRulesRepository using MariaDB.
+type houseRulesRepository struct {
+	db *sql.DB
+}
+
+// NewHouseRulesRepository creates a new house rules repository.
+func NewHouseRulesRepository(db *sql.DB) HouseRulesRepository {
+	return &houseRulesRepository{db: db}
+}
+
+const houseRulesRevisionColumns = `v.campaign_id, v.version, v.body, v.body_html, v.note,
+	COALESCE(v.edited_by, ''), COALESCE(u.display_name, ''), v.created_at`
+
+func scanHouseRulesRevision(scan func(...any) error) (*HouseRulesRevision, error) {
+	var rev HouseRulesRevision
+	if err := scan(&rev.CampaignID, &rev.Version, &rev.Body, &rev.BodyHTML, &rev.Note,
+		&rev.EditedBy, &rev.EditedByName, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// Get returns the house rules and the current revision.
+func (r *houseRulesRepository) Get(ctx context.Context, campaignID string) (*HouseRules, error) {
+	rules := &HouseRules{CampaignID: campaignID}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT version, require_ack, ack_version, updated_at
+		 FROM campaign_house_rules WHERE campaign_id = ?`, campaignID,
+	).Scan(&rules.Version, &rules.RequireAck, &rules.AckVersion, &rules.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding house rules: %w", err)
+	}
+	if rules.Version == 0 {
+		return rules, nil
+	}
+	if rules.Current, err = r.GetRevision(ctx, campaignID, rules.Version); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateRevision numbers the revision under a row lock on the campaign's
+// house rules, so two saves can't claim the same version.
+func (r *houseRulesRepository) CreateRevision(ctx context.Context, rev *HouseRulesRevision, requestAck bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin house rules tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT IGNORE INTO campaign_house_rules (campaign_id) VALUES (?)`, rev.CampaignID); err != nil {
+		return fmt.Errorf("creating house rules: %w", err)
+	}
+	var current int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT version FROM campaign_house_rules WHERE campaign_id = ? FOR UPDATE`, rev.CampaignID,
+	).Scan(&current); err != nil {
+		return fmt.Errorf("locking house rules: %w", err)
+	}
+	rev.Version = current + 1
+
+	var editedBy any
+	if rev.EditedBy != "" {
+		editedBy = rev.EditedBy
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO campaign_house_rules_revisions
+		 (campaign_id, version, body, body_html, note, edited_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rev.CampaignID, rev.Version, rev.Body, rev.BodyHTML, rev.Note, editedBy, rev.CreatedAt); err != nil {
+		return fmt.Errorf("inserting house rules revision: %w", err)
+	}
+
+	query := `UPDATE campaign_house_rules SET version = ? WHERE campaign_id = ?`
+	if requestAck {
+		query = `UPDATE campaign_house_rules SET version = ?, ack_version = version WHERE campaign_id = ?`
+	}
+	if _, err := tx.ExecContext(ctx, query, rev.Version, rev.CampaignID); err != nil {
+		return fmt.Errorf("updating house rules version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit house rules tx: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns the history with empty bodies.
+func (r *houseRulesRepository) ListRevisions(ctx context.Context, campaignID string) ([]HouseRulesRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT v.campaign_id, v.version, '', '', v.note,
+		        COALESCE(v.edited_by, ''), COALESCE(u.display_name, ''), v.created_at
+		 FROM campaign_house_rules_revisions v LEFT JOIN users u ON u.id = v.edited_by
+		 WHERE v.campaign_id = ? ORDER BY v.version DESC`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing house rules revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var list []HouseRulesRevision
+	for rows.Next() {
+		rev, err := scanHouseRulesRevision(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scanning house rules revision: %w", err)
+		}
+		list = append(list, *rev)
+	}
+	return list, rows.Err()
+}
+
+// GetRevision returns one revision, scoped to the campaign.
+func (r *houseRulesRepository) GetRevision(ctx context.Context, campaignID string, version int) (*HouseRulesRevision, error) {
+	rev, err := scanHouseRulesRevision(r.db.QueryRowContext(ctx,
+		`SELECT `+houseRulesRevisionColumns+`
+		 FROM campaign_house_rules_revisions v LEFT JOIN users u ON u.id = v.edited_by
+		 WHERE v.campaign_id = ? AND v.version = ?`, campaignID, version).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NewNotFound("house rules version not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding house rules revision: %w", err)
+	}
+	return rev, nil
+}
+
+// SetRequireAck upserts the flag. MariaDB applies the assignments left to
+// right, so ack_version still sees the old require_ack.
+func (r *houseRulesRepository) SetRequireAck(ctx context.Context, campaignID string, require bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO campaign_house_rules (campaign_id, require_ack) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE
+		   ack_version = IF(VALUES(require_ack) AND NOT require_ack, version, ack_version),
+		   require_ack = VALUES(require_ack)`,
+		campaignID, require)
+	if err != nil {
+		return fmt.Errorf("updating house rules acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// Acknowledge upserts the member's acknowledgement.
+func (r *houseRulesRepository) Acknowledge(ctx context.Context, campaignID, userID string, version int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO campaign_house_rules_acks (campaign_id, user_id, version) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE
+		   acknowledged_at = IF(VALUES(version) > version, CURRENT_TIMESTAMP, acknowledged_at),
+		   version = GREATEST(version, VALUES(version))`,
+		campaignID, userID, version)
+	if err != nil {
+		return fmt.Errorf("acknowledging house rules: %w", err)
+	}
+	return nil
+}
+
+// GetAck returns the member's acknowledgement, or nil.
+func (r *houseRulesRepository) GetAck(ctx context.Context, campaignID, userID string) (*HouseRulesAck, error) {
+	ack := &HouseRulesAck{UserID: userID}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT version, acknowledged_at FROM campaign_house_rules_acks
+		 WHERE campaign_id = ? AND user_id = ?`, campaignID, userID,
+	).Scan(&ack.Version, &ack.AcknowledgedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding house rules acknowledgement: %w", err)
+	}
+	return ack, nil
+}
+
+// ListAcks returns every member's acknowledgement for the campaign.
+func (r *houseRulesRepository) ListAcks(ctx context.Context, campaignID string) ([]HouseRulesAck, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, version, acknowledged_at FROM campaign_house_rules_acks
+		 WHERE campaign_id = ?`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing house rules acknowledgements: %w", err)
+	}
+	defer rows.Close()
+
+	var list []HouseRulesAck
+	for rows.Next() {
+		var ack HouseRulesAck
+		if err := rows.Scan(&ack.UserID, &ack.Version, &ack.AcknowledgedAt); err != nil {
+			return nil, fmt.Errorf("scanning house rules acknowledgement: %w", err)
+		}
+		list = append(list, ack)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,205 @@
+package campaigns
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeHouseRulesRepo is an in-memory HouseRulesRepository for one campaign.
+type fakeHouseRulesRepo struct {
+	rules     HouseRules
+	revisions []HouseRulesRevision
+	acks      map[string]*HouseRulesAck
+}
+
+func newFakeHouseRulesRepo() *fakeHouseRulesRepo {
+	return &fakeHouseRulesRepo{acks: map[string]*HouseRulesAck{}}
+}
+
+func (r *fakeHouseRulesRepo) Get(_ context.Context, campaignID string) (*HouseRules, error) {
+	rules := r.rules
+	rules.CampaignID = campaignID
+	if rules.Version > 0 {
+		rev := r.revisions[rules.Version-1]
+		rules.Current = &rev
+	}
+	return &rules, nil
+}
+
+func (r *fakeHouseRulesRepo) CreateRevision(_ context.Context, rev *HouseRulesRevision, requestAck bool) error {
+	rev.Version = r.rules.Version + 1
+	r.revisions = append(r.revisions, *rev)
+	r.rules.Version = rev.Version
+	if requestAck {
+		r.rules.AckVersion = rev.Version
+	}
+	return nil
+}
+
+func (r *fakeHouseRulesRepo) ListRevisions(context.Context, string) ([]HouseRulesRevision, error) {
+	return r.revisions, nil
+}
+
+func (r *fakeHouseRulesRepo) GetRevision(_ context.Context, _ string, version int) (*HouseRulesRevision, error) {
+	if version < 1 || version > len(r.revisions) {
+		return nil, apperror.NewNotFound("house rules version not found")
+	}
+	rev := r.revisions[version-1]
+	return &rev, nil
+}
+
+func (r *fakeHouseRulesRepo) SetRequireAck(_ context.Context, _ string, require bool) error {
+	if require && !r.rules.RequireAck {
+		r.rules.AckVersion = r.rules.Version
+	}
+	r.rules.RequireAck = require
+	return nil
+}
+
+func (r *fakeHouseRulesRepo) Acknowledge(_ context.Context, _, userID string, version int) error {
+	if a := r.acks[userID]; a == nil || a.Version < version {
+		r.acks[userID] = &HouseRulesAck{UserID: userID, Version: version}
+	}
+	return nil
+}
+
+func (r *fakeHouseRulesRepo) GetAck(_ context.Context, _, userID string) (*HouseRulesAck, error) {
+	return r.acks[userID], nil
+}
+
+func (r *fakeHouseRulesRepo) ListAcks(context.Context, string) ([]HouseRulesAck, error) {
+	var out []HouseRulesAck
+	for _, a := range r.acks {
+		out = append(out, *a)
+	}
+	return out, nil
+}
+
+// houseRulesNotifier collects the users told about a house rules change.
+type houseRulesNotifier struct{ to []string }
+
+func (n *houseRulesNotifier) NotifyUser(_ context.Context, userID, _, kind, _, _ string) error {
+	if kind == NotifHouseRulesUpdated {
+		n.to = append(n.to, userID)
+	}
+	return nil
+}
+
+func newTestHouseRulesService() (HouseRulesService, *fakeHouseRulesRepo, *houseRulesNotifier) {
+	repo := newFakeHouseRulesRepo()
+	members := &fakeMemberLister{members: []CampaignMember{
+		{UserID: "owner", Role: RoleOwner}, {UserID: "p1", Role: RolePlayer}, {UserID: "p2", Role: RolePlayer},
+	}}
+	svc := NewHouseRulesService(repo, members)
+	n := &houseRulesNotifier{}
+	svc.SetNotifier(n)
+	return svc, repo, n
+}
+
+func TestHouseRulesService_Save(t *testing.T) {
+	svc, _, n := newTestHouseRulesService()
+	ctx := context.Background()
+
+	_, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "   "})
+	assertAppError(t, err, 400)
+
+	rev, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: " No **PvP**.\n<script>x</script> "})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if rev.Version != 1 || !strings.Contains(rev.BodyHTML, "<strong>PvP</strong>") || strings.Contains(rev.BodyHTML, "<script") {
+		t.Errorf("revision = %+v", rev)
+	}
+	if len(n.to) != 0 {
+		t.Errorf("notified %v without an acknowledgement request", n.to)
+	}
+
+	_, err = svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "No **PvP**.\n<script>x</script>"})
+	assertAppError(t, err, 400)
+
+	rev, err = svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "No PvP. No phones.", Note: "Phones", RequestAck: true})
+	if err != nil || rev.Version != 2 {
+		t.Fatalf("second save = %+v, %v", rev, err)
+	}
+	if strings.Join(n.to, ",") != "p1,p2" {
+		t.Errorf("notified %v; want every member but the editor", n.to)
+	}
+}
+
+func TestHouseRulesService_Acknowledgement(t *testing.T) {
+	svc, repo, _ := newTestHouseRulesService()
+	ctx := context.Background()
+
+	assertAppError(t, svc.Acknowledge(ctx, "camp-1", "p1"), 404)
+
+	if err := svc.SetRequireAck(ctx, "camp-1", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.rules.AckVersion != 1 {
+		t.Errorf("ack version = %d; the first revision under tracking must ask", repo.rules.AckVersion)
+	}
+
+	view, _ := svc.Get(ctx, "camp-1", "p1", RolePlayer)
+	if !view.Pending {
+		t.Error("player must be pending before acknowledging")
+	}
+	if view, _ := svc.Get(ctx, "camp-1", "owner", RoleOwner); view.Pending {
+		t.Error("owners are never pending")
+	}
+	if err := svc.Acknowledge(ctx, "camp-1", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := svc.Get(ctx, "camp-1", "p1", RolePlayer); view.Pending {
+		t.Error("still pending after acknowledging")
+	}
+
+	// A quiet fix keeps the acknowledgement; asking again resets it.
+	if _, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "v2 typo fix"}); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := svc.Get(ctx, "camp-1", "p1", RolePlayer); view.Pending {
+		t.Error("a save without a request must not reset acknowledgements")
+	}
+	if _, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: "v3 new rule", RequestAck: true}); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := svc.Get(ctx, "camp-1", "p1", RolePlayer); !view.Pending {
+		t.Error("requested acknowledgement must make the player pending again")
+	}
+
+	acks, err := svc.Acknowledgements(ctx, "camp-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acks) != 2 || acks[0].Member.UserID != "p1" || acks[0].Ack == nil || acks[0].Current || acks[1].Ack != nil {
+		t.Errorf("acknowledgements = %+v; want p1 (stale) and p2 (never), no owner", acks)
+	}
+}
+
+func TestHouseRulesService_Restore(t *testing.T) {
+	svc, _, _ := newTestHouseRulesService()
+	ctx := context.Background()
+	for _, body := range []string{"first", "second"} {
+		if _, err := svc.Save(ctx, "camp-1", "owner", HouseRulesInput{Body: body}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rev, err := svc.Restore(ctx, "camp-1", "owner", 1)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if rev.Version != 3 || rev.Body != "first" || rev.Note != "Restored version 1" {
+		t.Errorf("restored revision = %+v", rev)
+	}
+	_, err = svc.Restore(ctx, "camp-1", "owner", 3)
+	assertAppError(t, err, 400)
+	_, err = svc.Restore(ctx, "camp-1", "owner", 9)
+	assertAppError(t, err, 404)
+}
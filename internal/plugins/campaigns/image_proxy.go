@@ -0,0 +1,101 @@
+package campaigns
+
+// image_proxy.go — per-campaign settings for the external image proxy.
+// Entries can embed images from other sites; the page CSP blocks those, so
+// nothing loads and no player's IP reaches the remote host. With the proxy
+// on, Chronicle fetches allowed images itself and serves them from its own
+// domain (the media plugin does the fetching and rewriting).
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// imageProxyMaxHosts caps the allowlist length.
+const imageProxyMaxHosts = 50
+
+// ImageProxySettings controls the external image proxy for a campaign.
+type ImageProxySettings struct {
+	Enabled bool `json:"enabled"`
+
+	// AllowedHosts limits which hosts are proxied: "example.com" matches that
+	// host only, "*.example.com" also matches its subdomains. Empty allows
+	// every public host.
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+}
+
+// Normalize lowercases, trims and dedupes the allowlist and validates each
+// entry.
+func (s *ImageProxySettings) Normalize() error {
+	seen := make(map[string]bool, len(s.AllowedHosts))
+	hosts := make([]string, 0, len(s.AllowedHosts))
+	for _, raw := range s.AllowedHosts {
+		h := strings.ToLower(strings.TrimSpace(raw))
+		if h == "" || seen[h] {
+			continue
+		}
+		if !isValidProxyHost(strings.TrimPrefix(h, "*.")) {
+			return apperror.NewBadRequest(fmt.Sprintf("%q is not a valid host name", raw))
+		}
+		seen[h] = true
+		hosts = append(hosts, h)
+	}
+	if len(hosts) > imageProxyMaxHosts {
+		return apperror.NewBadRequest(fmt.Sprintf("at most %d allowed hosts", imageProxyMaxHosts))
+	}
+	s.AllowedHosts = hosts
+	return nil
+}
+
+// AllowsHost reports whether images from host may be proxied.
+func (s ImageProxySettings) AllowsHost(host string) bool {
+	if !s.Enabled {
+		return false
+	}
+	if len(s.AllowedHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range s.AllowedHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidProxyHost checks a bare host name: dot-separated labels of
+// letters, digits and hyphens. IP literals are rejected; the proxy only
+// fetches public hosts and an allowlist of addresses invites probing.
+func isValidProxyHost(h string) bool {
+	if len(h) > 253 || !strings.Contains(h, ".") {
+		return false
+	}
+	for _, label := range strings.Split(h, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	last := h[strings.LastIndex(h, ".")+1:]
+	return last[0] < '0' || last[0] > '9'
+}
+
+// GetImageProxy returns the campaign's image proxy settings (off when never
+// configured).
+func (s CampaignSettings) GetImageProxy() ImageProxySettings {
+	if s.ImageProxy == nil {
+		return ImageProxySettings{}
+	}
+	return *s.ImageProxy
+}
@@ -0,0 +1,67 @@
+package campaigns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImageProxySettings_Normalize(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", in: nil, want: []string{}},
+		{name: "lowercased and deduped", in: []string{" Imgur.com ", "imgur.com", "", "*.Example.org"}, want: []string{"imgur.com", "*.example.org"}},
+		{name: "single label", in: []string{"localhost"}, wantErr: true},
+		{name: "ip literal", in: []string{"10.0.0.1"}, wantErr: true},
+		{name: "url not host", in: []string{"https://imgur.com"}, wantErr: true},
+		{name: "bad label", in: []string{"-bad.com"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := ImageProxySettings{Enabled: true, AllowedHosts: tc.in}
+			err := s.Normalize()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Normalize() err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(s.AllowedHosts, tc.want) {
+				t.Errorf("AllowedHosts = %v, want %v", s.AllowedHosts, tc.want)
+			}
+		})
+	}
+
+	tooMany := ImageProxySettings{}
+	for i := 0; i <= imageProxyMaxHosts; i++ {
+		tooMany.AllowedHosts = append(tooMany.AllowedHosts, "h"+string(rune('a'+i%26))+string(rune('a'+i/26))+".com")
+	}
+	if err := tooMany.Normalize(); err == nil {
+		t.Error("an allowlist over the cap was accepted")
+	}
+}
+
+func TestImageProxySettings_AllowsHost(t *testing.T) {
+	list := ImageProxySettings{Enabled: true, AllowedHosts: []string{"imgur.com", "*.example.org"}}
+	cases := []struct {
+		name string
+		cfg  ImageProxySettings
+		host string
+		want bool
+	}{
+		{name: "disabled", cfg: ImageProxySettings{AllowedHosts: []string{"imgur.com"}}, host: "imgur.com", want: false},
+		{name: "enabled, empty list allows all", cfg: ImageProxySettings{Enabled: true}, host: "anything.net", want: true},
+		{name: "exact match", cfg: list, host: "IMGUR.com", want: true},
+		{name: "exact does not cover subdomains", cfg: list, host: "i.imgur.com", want: false},
+		{name: "wildcard subdomain", cfg: list, host: "cdn.example.org", want: true},
+		{name: "wildcard apex", cfg: list, host: "example.org", want: true},
+		{name: "suffix lookalike", cfg: list, host: "badexample.org", want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cfg.AllowsHost(tc.host); got != tc.want {
+				t.Errorf("AllowsHost(%q) = %v, want %v", tc.host, got, tc.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package campaigns
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 )
@@ -74,6 +75,13 @@ type IDMap struct {
 
 	// CalendarID is the new calendar's ID (if created).
 	CalendarID string
+
+	// OriginalCampaignID is the exported campaign's ID, used to rewrite
+	// links into the old campaign. Empty for exports that predate it.
+	OriginalCampaignID string
+
+	// Summary tallies what the importers created and skipped.
+	Summary *ImportSummary
 }
 
 // NewIDMap creates an empty ID mapping structure.
@@ -86,5 +94,103 @@ func NewIDMap(campaignID string) *IDMap {
 		TagSlugToID:    make(map[string]int),
 		MapIDs:         make(map[string]string),
 		CampaignID:     campaignID,
+		Summary:        &ImportSummary{CampaignID: campaignID},
+	}
+}
+
+// uuidPattern matches the IDs entities and campaigns are created with.
+var uuidPattern = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// RemapReferences rewrites entity and campaign IDs from the export to their
+// new IDs in entry content. Mentions carry the target in data-mention-id, in
+// ProseMirror attrs and in /campaigns/<id>/entities/<id> hrefs; IDs are
+// UUIDs, so every occurrence is swapped whatever the markup around it. IDs
+// of entities that weren't imported are left alone.
+func (m *IDMap) RemapReferences(content string) string {
+	return uuidPattern.ReplaceAllStringFunc(content, func(id string) string {
+		if newID, ok := m.EntityIDs[id]; ok {
+			return newID
+		}
+		if id == m.OriginalCampaignID {
+			return m.CampaignID
+		}
+		return id
+	})
+}
+
+// Import summary kinds, in the order the import creates them.
+const (
+	ImportKindEntityTypes    = "entity_types"
+	ImportKindEntities       = "entities"
+	ImportKindTags           = "tags"
+	ImportKindRelations      = "relations"
+	ImportKindCalendars      = "calendars"
+	ImportKindCalendarEvents = "calendar_events"
+	ImportKindMedia          = "media"
+)
+
+// maxImportSkips caps the skipped items listed in a summary; the counts
+// stay exact past it.
+const maxImportSkips = 50
+
+// ImportCount is how many items of one kind were created and skipped.
+type ImportCount struct {
+	Kind    string `json:"kind"`
+	Created int    `json:"created"`
+	Skipped int    `json:"skipped"`
+}
+
+// ImportSkip is one item the import left out, and why.
+type ImportSkip struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// ImportSummary reports what a campaign import created and skipped.
+// Warnings cover sections that failed as a whole (maps, sessions, ...),
+// which aren't counted item by item.
+type ImportSummary struct {
+	CampaignID string        `json:"campaign_id"`
+	Counts     []ImportCount `json:"counts"`
+	Skipped    []ImportSkip  `json:"skipped"`
+	Warnings   []string      `json:"warnings"`
+}
+
+// count returns the tally for kind, adding it on first use.
+func (s *ImportSummary) count(kind string) *ImportCount {
+	for i := range s.Counts {
+		if s.Counts[i].Kind == kind {
+			return &s.Counts[i]
+		}
+	}
+	s.Counts = append(s.Counts, ImportCount{Kind: kind})
+	return &s.Counts[len(s.Counts)-1]
+}
+
+// Created records one created item of kind.
+func (s *ImportSummary) Created(kind string) {
+	s.count(kind).Created++
+}
+
+// Skip records an item of kind that wasn't imported.
+func (s *ImportSummary) Skip(kind, name, reason string) {
+	s.count(kind).Skipped++
+	if len(s.Skipped) < maxImportSkips {
+		s.Skipped = append(s.Skipped, ImportSkip{Kind: kind, Name: name, Reason: reason})
+	}
+}
+
+// Warn records a problem that isn't tied to one item.
+func (s *ImportSummary) Warn(format string, args ...any) {
+	s.Warnings = append(s.Warnings, fmt.Sprintf(format, args...))
+}
+
+// TotalSkipped is the number of skipped items across all kinds.
+func (s *ImportSummary) TotalSkipped() int {
+	n := 0
+	for _, c := range s.Counts {
+		n += c.Skipped
 	}
+	return n
 }
@@ -70,3 +70,50 @@ func TestNewIDMap(t *testing.T) {
 		t.Error("EntityIDs should be initialized")
 	}
 }
+
+func TestIDMap_RemapReferences(t *testing.T) {
+	const (
+		oldCampaign = "11111111-1111-1111-1111-111111111111"
+		oldEntity   = "22222222-2222-2222-2222-222222222222"
+		missing     = "33333333-3333-3333-3333-333333333333"
+	)
+	idMap := NewIDMap("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	idMap.OriginalCampaignID = oldCampaign
+	idMap.EntityIDs[oldEntity] = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+
+	html := `<a data-mention-id="` + oldEntity + `" href="/campaigns/` + oldCampaign + `/entities/` + oldEntity + `">@Bob</a>` +
+		`<a data-mention-id="` + missing + `">@Gone</a>`
+	want := `<a data-mention-id="bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb" href="/campaigns/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/entities/bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb">@Bob</a>` +
+		`<a data-mention-id="` + missing + `">@Gone</a>`
+	if got := idMap.RemapReferences(html); got != want {
+		t.Errorf("RemapReferences(html) =\n%s\nwant\n%s", got, want)
+	}
+
+	doc := `{"type":"mention","attrs":{"id":"` + oldEntity + `","name":"Bob"}}`
+	if got := idMap.RemapReferences(doc); got != `{"type":"mention","attrs":{"id":"bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb","name":"Bob"}}` {
+		t.Errorf("RemapReferences(json) = %s", got)
+	}
+}
+
+func TestImportSummary_CountsAndSkips(t *testing.T) {
+	s := NewIDMap("camp-1").Summary
+	s.Created(ImportKindEntities)
+	s.Created(ImportKindEntities)
+	for i := 0; i < maxImportSkips+5; i++ {
+		s.Skip(ImportKindRelations, "a → b", "an entity it links wasn't imported")
+	}
+	s.Warn("%s could not be imported", "Maps")
+
+	if len(s.Counts) != 2 || s.Counts[0] != (ImportCount{Kind: ImportKindEntities, Created: 2}) {
+		t.Errorf("counts = %+v", s.Counts)
+	}
+	if s.TotalSkipped() != maxImportSkips+5 {
+		t.Errorf("TotalSkipped() = %d, want %d", s.TotalSkipped(), maxImportSkips+5)
+	}
+	if len(s.Skipped) != maxImportSkips {
+		t.Errorf("listed %d skips, want cap %d", len(s.Skipped), maxImportSkips)
+	}
+	if len(s.Warnings) != 1 || s.Warnings[0] != "Maps could not be imported" {
+		t.Errorf("warnings = %v", s.Warnings)
+	}
+}
@@ -65,6 +65,27 @@ func (h *InviteHandler) CreateInviteAPI(c echo.Context) error {
 	return c.JSON(http.StatusCreated, invite)
 }
 
+// BulkInviteAPI adds or invites a pasted list of emails and returns the
+// outcome per email.
+// POST /campaigns/:id/invites/bulk
+func (h *InviteHandler) BulkInviteAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	var input BulkInviteInput
+	if err := c.Bind(&input); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	results, err := h.service.BulkInvite(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
 // RevokeInviteAPI deletes a pending invite.
 // DELETE /campaigns/:id/invites/:inviteId
 func (h *InviteHandler) RevokeInviteAPI(c echo.Context) error {
@@ -34,6 +34,32 @@ type CreateInviteInput struct {
 	Role  string `json:"role"`
 }
 
+// BulkInviteInput holds a pasted list of emails to invite at once. Emails
+// may be separated by commas, semicolons, whitespace or new lines, and may
+// use the "Name <email>" form mail clients copy.
+type BulkInviteInput struct {
+	Emails string `json:"emails"`
+	Role   string `json:"role"`
+}
+
+// Bulk invite row outcomes.
+const (
+	BulkInviteAdded   = "added"   // Had an account; added as a member.
+	BulkInviteInvited = "invited" // No account; invite created and emailed.
+	BulkInviteSkipped = "skipped" // Already a member or already invited.
+	BulkInviteFailed  = "failed"  // Invalid email or the add/invite failed.
+)
+
+// BulkInviteResult is the outcome for one email in a bulk invite.
+type BulkInviteResult struct {
+	Email   string `json:"email"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// maxBulkInvites caps the emails in one bulk invite.
+const maxBulkInvites = 100
+
 // inviteTokenBytes is the number of random bytes in an invite token.
 const inviteTokenBytes = 32
 
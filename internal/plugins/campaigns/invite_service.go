@@ -10,9 +10,11 @@ import (
 	"log/slog"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
 )
 
 // InviteMailer sends invite emails. Subset of smtp.MailService to avoid
@@ -29,6 +31,15 @@ type InviteService interface {
 	RevokeInvite(ctx context.Context, inviteID string) error
 	AcceptInvite(ctx context.Context, token string, userID string) (*Invite, error)
 	GetInviteByToken(ctx context.Context, token string) (*Invite, error)
+
+	// BulkInvite adds or invites every email in the list with one role and
+	// reports the outcome per email. Emails with an account are added as
+	// members directly; the rest get an invite.
+	BulkInvite(ctx context.Context, campaignID, createdBy string, input BulkInviteInput) ([]BulkInviteResult, error)
+
+	// SetUserFinder sets the account lookup BulkInvite uses to add existing
+	// users directly. Without one, every email gets an invite.
+	SetUserFinder(users UserFinder)
 }
 
 // inviteService implements InviteService.
@@ -37,6 +48,7 @@ type inviteService struct {
 	campaigns   CampaignRepository
 	mailer      InviteMailer
 	baseURL     string
+	users       UserFinder
 }
 
 // NewInviteService creates a new invite service.
@@ -49,6 +61,11 @@ func NewInviteService(repo InviteRepository, campaigns CampaignRepository, maile
 	}
 }
 
+// SetUserFinder sets the account lookup for bulk invites.
+func (s *inviteService) SetUserFinder(users UserFinder) {
+	s.users = users
+}
+
 // CreateInvite creates a new invitation and sends an email to the recipient.
 func (s *inviteService) CreateInvite(ctx context.Context, campaignID, createdBy string, input CreateInviteInput) (*Invite, error) {
 	email := strings.TrimSpace(strings.ToLower(input.Email))
@@ -117,26 +134,14 @@ func (s *inviteService) CreateInvite(ctx context.Context, campaignID, createdBy
 			roleName = "Scribe"
 		}
 
-		subject := fmt.Sprintf("You're invited to %s — Chronicle", campaign.Name)
-
-		plainBody := fmt.Sprintf(
-			"You've been invited to join \"%s\" as a %s on Chronicle.\n\n"+
-				"Click the link below to accept:\n%s\n\n"+
-				"This invitation expires in %d days.\n\n"+
-				"If you don't have an account, you'll be able to create one when you accept.",
-			campaign.Name, roleName, acceptURL, inviteExpiryDays)
-
-		htmlBody := fmt.Sprintf(`<div style="font-family:sans-serif;max-width:600px;margin:0 auto;padding:20px">
-<h2 style="color:#111827">You're invited to join "%s"</h2>
-<p style="color:#374151;font-size:16px">You've been invited as a <strong>%s</strong>.</p>
-<p style="margin:24px 0">
-  <a href="%s" style="display:inline-block;padding:12px 24px;background:#6366f1;color:#fff;text-decoration:none;border-radius:8px;font-weight:600">Accept Invitation</a>
-</p>
-<p style="color:#6b7280;font-size:14px">This invitation expires in %d days.</p>
-<p style="color:#6b7280;font-size:14px">If you don't have an account, you'll be able to create one when you accept.</p>
-</div>`, campaign.Name, roleName, acceptURL, inviteExpiryDays)
-
-		if err := s.mailer.SendHTMLMail(ctx, []string{email}, subject, plainBody, htmlBody); err != nil {
+		// The shared layout escapes the owner-chosen campaign name, which the
+		// old hand-built HTML interpolated raw.
+		msg := emails.CampaignInvite(campaign.Name, roleName, acceptURL, inviteExpiryDays)
+		plainBody, htmlBody, err := msg.Render(ctx)
+		if err == nil {
+			err = s.mailer.SendHTMLMail(ctx, []string{email}, msg.Subject, plainBody, htmlBody)
+		}
+		if err != nil {
 			slog.Warn("failed to send invite email",
 				slog.String("email", email),
 				slog.String("campaign", campaignID),
@@ -225,3 +230,100 @@ func (s *inviteService) GetInviteByToken(ctx context.Context, token string) (*In
 	}
 	return invite, nil
 }
+
+// BulkInvite validates the role once, then handles each email on its own so
+// one bad row doesn't stop the rest.
+func (s *inviteService) BulkInvite(ctx context.Context, campaignID, createdBy string, input BulkInviteInput) ([]BulkInviteResult, error) {
+	role := strings.ToLower(strings.TrimSpace(input.Role))
+	if role == "" {
+		role = "player"
+	}
+	if role != "player" && role != "scribe" {
+		return nil, apperror.NewValidation("role must be 'player' or 'scribe'")
+	}
+
+	emails := parseBulkEmails(input.Emails)
+	if len(emails) == 0 {
+		return nil, apperror.NewValidation("enter at least one email address")
+	}
+	if len(emails) > maxBulkInvites {
+		return nil, apperror.NewValidation(fmt.Sprintf("at most %d emails can be invited at once", maxBulkInvites))
+	}
+
+	results := make([]BulkInviteResult, 0, len(emails))
+	for _, email := range emails {
+		results = append(results, s.bulkInviteOne(ctx, campaignID, createdBy, email, role))
+	}
+
+	slog.Info("campaign bulk invite",
+		slog.String("campaign_id", campaignID),
+		slog.Int("emails", len(emails)),
+		slog.String("role", role))
+	return results, nil
+}
+
+// bulkInviteOne adds the email's account as a member when there is one,
+// otherwise creates an invite.
+func (s *inviteService) bulkInviteOne(ctx context.Context, campaignID, createdBy, email, role string) BulkInviteResult {
+	res := BulkInviteResult{Email: email}
+	if !isValidEmail(email) {
+		res.Status, res.Message = BulkInviteFailed, "invalid email address"
+		return res
+	}
+
+	if s.users != nil {
+		if user, err := s.users.FindUserByEmail(ctx, email); err == nil && user != nil {
+			if _, err := s.campaigns.FindMember(ctx, campaignID, user.ID); err == nil {
+				res.Status, res.Message = BulkInviteSkipped, "already a member"
+				return res
+			}
+			member := &CampaignMember{
+				CampaignID: campaignID,
+				UserID:     user.ID,
+				Role:       RoleFromString(role),
+				JoinedAt:   time.Now().UTC(),
+			}
+			if err := s.campaigns.AddMember(ctx, member); err != nil {
+				slog.Warn("bulk invite: adding member failed",
+					slog.String("campaign_id", campaignID),
+					slog.String("email", email),
+					slog.Any("error", err))
+				res.Status, res.Message = BulkInviteFailed, "could not add member"
+				return res
+			}
+			res.Status = BulkInviteAdded
+			return res
+		}
+	}
+
+	if existing, err := s.repo.GetByEmailAndCampaign(ctx, email, campaignID); err == nil && existing != nil && existing.IsPending() {
+		res.Status, res.Message = BulkInviteSkipped, "already invited"
+		return res
+	}
+	if _, err := s.CreateInvite(ctx, campaignID, createdBy, CreateInviteInput{Email: email, Role: role}); err != nil {
+		res.Status, res.Message = BulkInviteFailed, apperror.UserMessage(err, "could not create invite")
+		return res
+	}
+	res.Status = BulkInviteInvited
+	return res
+}
+
+// parseBulkEmails splits a pasted list into lowercase emails, in order and
+// without duplicates. Words without an "@" are dropped, so display names in
+// "Name <email>" entries fall away and only the address is kept.
+func parseBulkEmails(raw string) []string {
+	words := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	var emails []string
+	seen := make(map[string]bool)
+	for _, w := range words {
+		email := strings.ToLower(strings.Trim(w, `<>"'`))
+		if !strings.Contains(email, "@") || seen[email] {
+			continue
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+	return emails
+}
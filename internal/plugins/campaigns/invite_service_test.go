@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -134,6 +135,9 @@ func (m *mockCampaignRepoForInvites) RemoveMember(context.Context, string, strin
 func (m *mockCampaignRepoForInvites) ListMembers(context.Context, string) ([]CampaignMember, error) {
 	return nil, nil
 }
+func (m *mockCampaignRepoForInvites) ListUserMemberships(context.Context, string) ([]UserMembership, error) {
+	return nil, nil
+}
 func (m *mockCampaignRepoForInvites) UpdateMemberRole(context.Context, string, string, Role) error {
 	return nil
 }
@@ -159,6 +163,9 @@ func (m *mockCampaignRepoForInvites) UpdateBackdropPath(context.Context, string,
 func (m *mockCampaignRepoForInvites) UpdateSettings(context.Context, string, string) error {
 	return nil
 }
+func (m *mockCampaignRepoForInvites) ListRetentionSettings(context.Context) (map[string]RetentionSettings, error) {
+	return nil, nil
+}
 func (m *mockCampaignRepoForInvites) UpdateSidebarConfig(context.Context, string, string) error {
 	return nil
 }
@@ -176,6 +183,28 @@ func (m *mockCampaignRepoForInvites) ForceTransferOwnership(context.Context, str
 }
 func (m *mockCampaignRepoForInvites) ArchiveCampaign(context.Context, string) error   { return nil }
 func (m *mockCampaignRepoForInvites) UnarchiveCampaign(context.Context, string) error { return nil }
+func (m *mockCampaignRepoForInvites) MarkForDeletion(context.Context, string, time.Time) error {
+	return nil
+}
+func (m *mockCampaignRepoForInvites) CancelDeletion(context.Context, string) error { return nil }
+func (m *mockCampaignRepoForInvites) ListDueForPurge(context.Context, time.Time, int) ([]string, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepoForInvites) ListPendingDeletionByOwner(context.Context, string) ([]Campaign, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepoForInvites) ListOnboardingProgress(context.Context, string, string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepoForInvites) SetOnboardingProgress(context.Context, string, string, string, bool) error {
+	return nil
+}
+func (m *mockCampaignRepoForInvites) HasCharacter(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+func (m *mockCampaignRepoForInvites) HasAcknowledgedHouseRules(context.Context, string, string) (bool, error) {
+	return false, nil
+}
 func (m *mockCampaignRepoForInvites) SetJoinCode(context.Context, string, string) error {
 	return nil
 }
@@ -412,3 +441,103 @@ func TestCreateInvite_DefaultsToPlayer(t *testing.T) {
 		t.Errorf("expected role player (default), got %s", invite.Role)
 	}
 }
+
+func TestParseBulkEmails(t *testing.T) {
+	got := parseBulkEmails("Alice <Alice@Example.com>, bob@example.com;\ncarol@example.com\n\nbob@example.com  'dave@example.com'")
+	want := []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("email %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
+
+func TestBulkInvite_AddsInvitesAndSkips(t *testing.T) {
+	inviteRepo := newMockInviteRepo()
+	campaignRepo := newMockCampaignRepoForInvites()
+	campaignRepo.members["camp-1"] = map[string]*CampaignMember{
+		"user-member": {CampaignID: "camp-1", UserID: "user-member", Role: RolePlayer},
+	}
+	svc := NewInviteService(inviteRepo, campaignRepo, nil, "http://localhost:3000")
+	svc.SetUserFinder(&mockUserFinder{findByEmailFn: func(_ context.Context, email string) (*MemberUser, error) {
+		switch email {
+		case "existing@example.com":
+			return &MemberUser{ID: "user-existing", Email: email}, nil
+		case "member@example.com":
+			return &MemberUser{ID: "user-member", Email: email}, nil
+		}
+		return nil, fmt.Errorf("not found")
+	}})
+
+	if _, err := svc.CreateInvite(context.Background(), "camp-1", "user-1", CreateInviteInput{Email: "pending@example.com"}); err != nil {
+		t.Fatalf("seeding invite: %v", err)
+	}
+
+	results, err := svc.BulkInvite(context.Background(), "camp-1", "user-1", BulkInviteInput{
+		Emails: "existing@example.com, member@example.com, new@example.com, pending@example.com, nope@x",
+		Role:   "scribe",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"existing@example.com": BulkInviteAdded,
+		"member@example.com":   BulkInviteSkipped,
+		"new@example.com":      BulkInviteInvited,
+		"pending@example.com":  BulkInviteSkipped,
+		"nope@x":               BulkInviteFailed,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for _, r := range results {
+		if r.Status != want[r.Email] {
+			t.Errorf("%s: expected %s, got %s (%s)", r.Email, want[r.Email], r.Status, r.Message)
+		}
+	}
+
+	added := campaignRepo.members["camp-1"]["user-existing"]
+	if added == nil || added.Role != RoleScribe {
+		t.Errorf("expected existing user added as scribe, got %+v", added)
+	}
+	if inv, _ := inviteRepo.GetByEmailAndCampaign(context.Background(), "new@example.com", "camp-1"); inv == nil || inv.Role != "scribe" {
+		t.Errorf("expected scribe invite for new@example.com, got %+v", inv)
+	}
+}
+
+func TestBulkInvite_WithoutUserFinderInvitesEveryone(t *testing.T) {
+	inviteRepo := newMockInviteRepo()
+	svc := NewInviteService(inviteRepo, newMockCampaignRepoForInvites(), nil, "http://localhost:3000")
+
+	results, err := svc.BulkInvite(context.Background(), "camp-1", "user-1", BulkInviteInput{Emails: "a@example.com\nb@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range results {
+		if r.Status != BulkInviteInvited {
+			t.Errorf("%s: expected invited, got %s", r.Email, r.Status)
+		}
+	}
+}
+
+func TestBulkInvite_Validation(t *testing.T) {
+	svc := NewInviteService(newMockInviteRepo(), newMockCampaignRepoForInvites(), nil, "http://localhost:3000")
+	ctx := context.Background()
+
+	if _, err := svc.BulkInvite(ctx, "camp-1", "user-1", BulkInviteInput{Emails: "a@example.com", Role: "owner"}); err == nil {
+		t.Error("expected error for owner role")
+	}
+	if _, err := svc.BulkInvite(ctx, "camp-1", "user-1", BulkInviteInput{Emails: "  ,  "}); err == nil {
+		t.Error("expected error for an empty list")
+	}
+	var many []string
+	for i := 0; i <= maxBulkInvites; i++ {
+		many = append(many, fmt.Sprintf("user%d@example.com", i))
+	}
+	if _, err := svc.BulkInvite(ctx, "camp-1", "user-1", BulkInviteInput{Emails: strings.Join(many, ",")}); err == nil {
+		t.Error("expected error over the batch limit")
+	}
+}
@@ -0,0 +1,303 @@
+package campaigns
+
+// layout_share.go — moving dashboard layouts between campaigns. A layout
+// is exported as a small JSON envelope. Importing one screens its blocks
+// against what this campaign can render (known block types, enabled
+// addons) and hands the cleaned layout back to the editor, which applies
+// it like a preset: nothing is saved until the owner clicks Save. The
+// "copy from my other campaign" picker is the same import fed from an
+// export of a campaign the user also owns.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// LayoutExportFormat identifies a Chronicle dashboard layout export.
+const LayoutExportFormat = "chronicle-dashboard-layout"
+
+// LayoutExportVersion is the envelope version written by this build.
+const LayoutExportVersion = 1
+
+// maxLayoutImportSize caps an uploaded layout file.
+const maxLayoutImportSize = 1 << 20
+
+// LayoutExport is the shareable form of a dashboard layout.
+type LayoutExport struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	Kind       string           `json:"kind"`             // LayoutKind* of the source
+	Source     string           `json:"source,omitempty"` // source campaign name, informational
+	ExportedAt time.Time        `json:"exported_at"`
+	Layout     *DashboardLayout `json:"layout"`
+}
+
+// NewLayoutExport wraps a layout for export.
+func NewLayoutExport(kind, source string, layout *DashboardLayout) *LayoutExport {
+	return &LayoutExport{
+		Format:     LayoutExportFormat,
+		Version:    LayoutExportVersion,
+		Kind:       kind,
+		Source:     source,
+		ExportedAt: time.Now().UTC(),
+		Layout:     layout,
+	}
+}
+
+// WriteLayoutExport sends an export as a JSON file download named after
+// the campaign and what was exported.
+func WriteLayoutExport(c echo.Context, export *LayoutExport, campaignName, what string) error {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshal layout export: %w", err))
+	}
+	filename := fmt.Sprintf("%s-%s-layout.json", sanitizeFilename(campaignName), sanitizeFilename(what))
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return c.Blob(http.StatusOK, "application/json", data)
+}
+
+// parseLayoutImport reads an export envelope, or a bare {"rows": [...]}
+// layout as the editor's own JSON.
+func parseLayoutImport(body []byte) (*DashboardLayout, error) {
+	var doc struct {
+		Format  string           `json:"format"`
+		Version int              `json:"version"`
+		Layout  *DashboardLayout `json:"layout"`
+		Rows    []DashboardRow   `json:"rows"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, apperror.NewBadRequest("file is not valid JSON")
+	}
+	if doc.Format == "" {
+		if doc.Rows == nil {
+			return nil, apperror.NewBadRequest("file is not a Chronicle dashboard layout")
+		}
+		return &DashboardLayout{Rows: doc.Rows}, nil
+	}
+	if doc.Format != LayoutExportFormat {
+		return nil, apperror.NewBadRequest("file is not a Chronicle dashboard layout")
+	}
+	if doc.Version > LayoutExportVersion {
+		return nil, apperror.NewBadRequest("layout was exported by a newer version of Chronicle")
+	}
+	if doc.Layout == nil {
+		return nil, apperror.NewBadRequest("layout file is empty")
+	}
+	return doc.Layout, nil
+}
+
+// DashboardBlockChecker reports whether a campaign can render a dashboard
+// block type. Implemented by an adapter over the entities block registry
+// and the addon service.
+type DashboardBlockChecker interface {
+	// DashboardBlockStatus returns whether blockType is a known dashboard
+	// block, the addon it needs ("" = none) and whether that addon is
+	// enabled for the campaign.
+	DashboardBlockStatus(ctx context.Context, campaignID, blockType string) (known bool, addon string, enabled bool)
+}
+
+// SetDashboardBlockChecker enables addon screening on layout import.
+func (h *Handler) SetDashboardBlockChecker(checker DashboardBlockChecker) {
+	h.blockChecker = checker
+}
+
+// SkippedBlock is an imported block this campaign can't show.
+type SkippedBlock struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// screenImportedLayout drops blocks the campaign can't render and reports
+// them. Without a checker only the built-in type list is consulted.
+func screenImportedLayout(ctx context.Context, checker DashboardBlockChecker, campaignID string, layout *DashboardLayout) []SkippedBlock {
+	reasons := map[string]string{} // block type -> reason, "" = allowed
+	reasonFor := func(blockType string) string {
+		if r, ok := reasons[blockType]; ok {
+			return r
+		}
+		r := ""
+		if !ValidBlockTypes[blockType] {
+			r = "unknown block type"
+		} else if checker != nil {
+			known, addon, enabled := checker.DashboardBlockStatus(ctx, campaignID, blockType)
+			switch {
+			case !known:
+				r = "unknown block type"
+			case addon != "" && !enabled:
+				r = fmt.Sprintf("needs the %s addon, which isn't enabled here", addon)
+			}
+		}
+		reasons[blockType] = r
+		return r
+	}
+
+	skipped := []SkippedBlock{}
+	for ri := range layout.Rows {
+		for ci := range layout.Rows[ri].Columns {
+			col := &layout.Rows[ri].Columns[ci]
+			kept := col.Blocks[:0]
+			for _, b := range col.Blocks {
+				if r := reasonFor(b.Type); r != "" {
+					skipped = append(skipped, SkippedBlock{Type: b.Type, Reason: r})
+					continue
+				}
+				kept = append(kept, b)
+			}
+			col.Blocks = kept
+		}
+	}
+	return skipped
+}
+
+// ImportDashboardLayout checks an uploaded or copied layout against this
+// campaign and returns it ready for the editor, with the blocks it had to
+// drop. Shared by the campaign, owner and category dashboard editors;
+// nothing is saved (POST /campaigns/:id/dashboard-layout/import).
+func (h *Handler) ImportDashboardLayout(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxLayoutImportSize+1))
+	if err != nil {
+		return apperror.NewBadRequest("failed to read request body")
+	}
+	if len(body) > maxLayoutImportSize {
+		return apperror.NewBadRequest("layout file is too large")
+	}
+	layout, err := parseLayoutImport(body)
+	if err != nil {
+		return err
+	}
+	skipped := screenImportedLayout(c.Request().Context(), h.blockChecker, cc.Campaign.ID, layout)
+	if err := validateDashboardLayout(layout); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"layout": layout, "skipped": skipped})
+}
+
+// ExportDashboardLayout downloads one role's campaign page layout; the
+// built-in default is exported when none is saved
+// (GET /campaigns/:id/dashboard-layout/export?role=).
+func (h *Handler) ExportDashboardLayout(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	roleName := c.QueryParam("role")
+	if roleName == "" {
+		roleName = "default"
+	}
+	campaign, err := h.service.GetByID(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	layout := campaign.GetRoleDashboardJSON(roleName)
+	if layout == nil {
+		layout = DefaultDashboardLayout()
+	}
+	return WriteLayoutExport(c, NewLayoutExport(LayoutKindDashboard, campaign.Name, layout), campaign.Name, "campaign-page-"+roleName)
+}
+
+// ExportOwnerDashboardLayout downloads the owner dashboard layout
+// (GET /campaigns/:id/owner-dashboard-layout/export).
+func (h *Handler) ExportOwnerDashboardLayout(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	campaign, err := h.service.GetByID(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	layout := campaign.ParseOwnerDashboardLayout()
+	if layout == nil {
+		return apperror.NewBadRequest("the owner dashboard uses the built-in default; there is no layout to export")
+	}
+	return WriteLayoutExport(c, NewLayoutExport(LayoutKindOwnerDashboard, campaign.Name, layout), campaign.Name, "owner-dashboard")
+}
+
+// LayoutSource is another campaign the user owns, with the saved layouts
+// that can be copied from it.
+type LayoutSource struct {
+	CampaignID   string             `json:"campaign_id"`
+	CampaignName string             `json:"campaign_name"`
+	Layouts      []LayoutSourceItem `json:"layouts"`
+}
+
+// LayoutSourceItem is one copyable layout; URL is its export endpoint.
+type LayoutSourceItem struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// DashboardLayoutSources lists the user's other owned campaigns and their
+// custom dashboard layouts for the copy picker
+// (GET /campaigns/:id/dashboard-layout/sources).
+func (h *Handler) DashboardLayoutSources(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	memberships, err := h.service.ListUserMemberships(ctx, auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+
+	sources := []LayoutSource{}
+	for _, m := range memberships {
+		if m.Role != RoleOwner || m.Archived || m.CampaignID == cc.Campaign.ID {
+			continue
+		}
+		campaign, err := h.service.GetByID(ctx, m.CampaignID)
+		if err != nil {
+			continue
+		}
+		if items := h.layoutSourceItems(ctx, campaign); len(items) > 0 {
+			sources = append(sources, LayoutSource{CampaignID: campaign.ID, CampaignName: campaign.Name, Layouts: items})
+		}
+	}
+	return c.JSON(http.StatusOK, sources)
+}
+
+// layoutSourceItems lists a campaign's saved (non-default) layouts.
+func (h *Handler) layoutSourceItems(ctx context.Context, campaign *Campaign) []LayoutSourceItem {
+	base := "/campaigns/" + campaign.ID
+	var items []LayoutSourceItem
+	for _, r := range []struct{ role, label string }{
+		{"default", "Campaign page"},
+		{"player", "Campaign page (players)"},
+		{"scribe", "Campaign page (scribes)"},
+	} {
+		if campaign.GetRoleDashboardJSON(r.role) != nil {
+			items = append(items, LayoutSourceItem{Label: r.label, URL: base + "/dashboard-layout/export?role=" + r.role})
+		}
+	}
+	if campaign.ParseOwnerDashboardLayout() != nil {
+		items = append(items, LayoutSourceItem{Label: "Owner dashboard", URL: base + "/owner-dashboard-layout/export"})
+	}
+	if h.entityLister != nil {
+		types, err := h.entityLister.GetEntityTypesForSettings(ctx, campaign.ID)
+		if err == nil {
+			for _, et := range types {
+				if et.HasDashboardLayout {
+					items = append(items, LayoutSourceItem{
+						Label: "Category: " + et.NamePlural,
+						URL:   fmt.Sprintf("%s/entity-types/%d/dashboard-layout/export", base, et.ID),
+					})
+				}
+			}
+		}
+	}
+	return items
+}
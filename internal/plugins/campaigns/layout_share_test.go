@@ -0,0 +1,83 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// stubBlockChecker marks calendar blocks as needing a disabled addon.
+type stubBlockChecker struct{}
+
+func (stubBlockChecker) DashboardBlockStatus(_ context.Context, _, blockType string) (bool, string, bool) {
+	if blockType == BlockCalendarPreview {
+		return true, "calendar", false
+	}
+	return true, "", true
+}
+
+func TestParseLayoutImport_EnvelopeAndBare(t *testing.T) {
+	export, _ := json.Marshal(NewLayoutExport(LayoutKindDashboard, "Source", &DashboardLayout{
+		Rows: []DashboardRow{{ID: "r1"}},
+	}))
+	layout, err := parseLayoutImport(export)
+	if err != nil || len(layout.Rows) != 1 || layout.Rows[0].ID != "r1" {
+		t.Fatalf("envelope: layout=%+v err=%v", layout, err)
+	}
+
+	layout, err = parseLayoutImport([]byte(`{"rows":[{"id":"bare"}]}`))
+	if err != nil || len(layout.Rows) != 1 || layout.Rows[0].ID != "bare" {
+		t.Fatalf("bare: layout=%+v err=%v", layout, err)
+	}
+}
+
+func TestParseLayoutImport_Rejects(t *testing.T) {
+	for name, body := range map[string]string{
+		"not json":     `{`,
+		"other format": `{"format":"something-else","layout":{"rows":[]}}`,
+		"newer":        `{"format":"chronicle-dashboard-layout","version":99,"layout":{"rows":[]}}`,
+		"no layout":    `{"format":"chronicle-dashboard-layout","version":1}`,
+		"no rows":      `{"name":"x"}`,
+	} {
+		_, err := parseLayoutImport([]byte(body))
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+			continue
+		}
+		assertAppError(t, err, 400)
+	}
+}
+
+func TestScreenImportedLayout_DropsUnavailableBlocks(t *testing.T) {
+	layout := &DashboardLayout{Rows: []DashboardRow{{
+		ID: "r1",
+		Columns: []DashboardColumn{{ID: "c1", Width: 12, Blocks: []DashboardBlock{
+			{ID: "b1", Type: BlockWelcomeBanner},
+			{ID: "b2", Type: BlockCalendarPreview},
+			{ID: "b3", Type: "made_up"},
+			{ID: "b4", Type: BlockCalendarPreview},
+		}}},
+	}}}
+
+	skipped := screenImportedLayout(context.Background(), stubBlockChecker{}, "c1", layout)
+	blocks := layout.Rows[0].Columns[0].Blocks
+	if len(blocks) != 1 || blocks[0].ID != "b1" {
+		t.Errorf("kept blocks = %+v", blocks)
+	}
+	if len(skipped) != 3 {
+		t.Fatalf("skipped = %+v", skipped)
+	}
+	if skipped[0].Type != BlockCalendarPreview || skipped[1].Reason != "unknown block type" {
+		t.Errorf("skipped = %+v", skipped)
+	}
+}
+
+func TestScreenImportedLayout_NoCheckerUsesBuiltinTypes(t *testing.T) {
+	layout := &DashboardLayout{Rows: []DashboardRow{{Columns: []DashboardColumn{{Width: 12, Blocks: []DashboardBlock{
+		{Type: BlockCalendarPreview}, {Type: "made_up"},
+	}}}}}}
+	skipped := screenImportedLayout(context.Background(), nil, "c1", layout)
+	if len(skipped) != 1 || skipped[0].Type != "made_up" {
+		t.Errorf("skipped = %+v", skipped)
+	}
+}
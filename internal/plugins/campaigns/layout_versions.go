@@ -0,0 +1,263 @@
+package campaigns
+
+// layout_versions.go — undo history for the layout and dashboard editors.
+// Every save or reset of a campaign dashboard, the owner dashboard, an
+// entity type's page layout or a category dashboard first records the
+// layout it is about to replace. The editors list those versions and can
+// restore one; a restore records the layout it replaces too, so it can
+// itself be undone. Only the newest MaxLayoutVersions per target are kept.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Layout kinds with version history.
+const (
+	LayoutKindDashboard         = "dashboard"          // campaign page, all roles
+	LayoutKindOwnerDashboard    = "owner_dashboard"    // owner-only dashboard
+	LayoutKindEntityTypeLayout  = "entity_type_layout" // entity page template
+	LayoutKindCategoryDashboard = "category_dashboard" // entity type's category page
+)
+
+// MaxLayoutVersions is how many versions are kept per target.
+const MaxLayoutVersions = 20
+
+// LayoutVersion is a layout as it was before a save replaced it.
+type LayoutVersion struct {
+	ID            int64     `json:"id"`
+	CampaignID    string    `json:"-"`
+	Kind          string    `json:"kind"`
+	TargetID      string    `json:"target_id,omitempty"`
+	Layout        *string   `json:"-"` // nil = the built-in default
+	IsDefault     bool      `json:"is_default"`
+	Note          string    `json:"note"`
+	CreatedBy     string    `json:"-"`
+	CreatedByName string    `json:"created_by_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LayoutVersionRepository persists layout versions.
+type LayoutVersionRepository interface {
+	// Latest returns the newest version for a target, or nil.
+	Latest(ctx context.Context, campaignID, kind, targetID string) (*LayoutVersion, error)
+	// Create stores v and drops all but the newest keep for its target.
+	Create(ctx context.Context, v *LayoutVersion, keep int) error
+	// List returns a target's versions, newest first.
+	List(ctx context.Context, campaignID, kind, targetID string) ([]LayoutVersion, error)
+	FindByID(ctx context.Context, id int64) (*LayoutVersion, error)
+}
+
+// layoutVersionRepository implements LayoutVersionRepository using MariaDB.
+type layoutVersionRepository struct {
+	db *sql.DB
+}
+
+// NewLayoutVersionRepository creates a new layout version repository.
+func NewLayoutVersionRepository(db *sql.DB) LayoutVersionRepository {
+	return &layoutVersionRepository{db: db}
+}
+
+const layoutVersionColumns = `v.id, v.campaign_id, v.kind, v.target_id, v.layout_json, v.note,
+	COALESCE(v.created_by, ''), COALESCE(u.display_name, ''), v.created_at`
+
+func scanLayoutVersion(scan func(...any) error) (*LayoutVersion, error) {
+	var v LayoutVersion
+	if err := scan(&v.ID, &v.CampaignID, &v.Kind, &v.TargetID, &v.Layout, &v.Note,
+		&v.CreatedBy, &v.CreatedByName, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	v.IsDefault = v.Layout == nil || *v.Layout == ""
+	return &v, nil
+}
+
+// Latest returns the newest version for a target.
+func (r *layoutVersionRepository) Latest(ctx context.Context, campaignID, kind, targetID string) (*LayoutVersion, error) {
+	v, err := scanLayoutVersion(r.db.QueryRowContext(ctx,
+		`SELECT `+layoutVersionColumns+`
+		 FROM layout_versions v LEFT JOIN users u ON u.id = v.created_by
+		 WHERE v.campaign_id = ? AND v.kind = ? AND v.target_id = ?
+		 ORDER BY v.id DESC LIMIT 1`,
+		campaignID, kind, targetID).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding latest layout version: %w", err)
+	}
+	return v, nil
+}
+
+// Create inserts the version and prunes the target's older ones.
+func (r *layoutVersionRepository) Create(ctx context.Context, v *LayoutVersion, keep int) error {
+	var createdBy any
+	if v.CreatedBy != "" {
+		createdBy = v.CreatedBy
+	}
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO layout_versions (campaign_id, kind, target_id, layout_json, note, created_by)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		v.CampaignID, v.Kind, v.TargetID, v.Layout, v.Note, createdBy)
+	if err != nil {
+		return fmt.Errorf("inserting layout version: %w", err)
+	}
+	if v.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("inserting layout version: %w", err)
+	}
+
+	// MariaDB can't LIMIT a subquery on the table being deleted from, so
+	// find the oldest ID worth keeping first.
+	var cutoff int64
+	err = r.db.QueryRowContext(ctx,
+		`SELECT id FROM layout_versions
+		 WHERE campaign_id = ? AND kind = ? AND target_id = ?
+		 ORDER BY id DESC LIMIT 1 OFFSET ?`,
+		v.CampaignID, v.Kind, v.TargetID, keep-1).Scan(&cutoff)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pruning layout versions: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM layout_versions
+		 WHERE campaign_id = ? AND kind = ? AND target_id = ? AND id < ?`,
+		v.CampaignID, v.Kind, v.TargetID, cutoff); err != nil {
+		return fmt.Errorf("pruning layout versions: %w", err)
+	}
+	return nil
+}
+
+// List returns a target's versions, newest first.
+func (r *layoutVersionRepository) List(ctx context.Context, campaignID, kind, targetID string) ([]LayoutVersion, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+layoutVersionColumns+`
+		 FROM layout_versions v LEFT JOIN users u ON u.id = v.created_by
+		 WHERE v.campaign_id = ? AND v.kind = ? AND v.target_id = ?
+		 ORDER BY v.id DESC`,
+		campaignID, kind, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("listing layout versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []LayoutVersion
+	for rows.Next() {
+		v, err := scanLayoutVersion(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scanning layout version: %w", err)
+		}
+		out = append(out, *v)
+	}
+	return out, rows.Err()
+}
+
+// FindByID returns one version, or nil.
+func (r *layoutVersionRepository) FindByID(ctx context.Context, id int64) (*LayoutVersion, error) {
+	v, err := scanLayoutVersion(r.db.QueryRowContext(ctx,
+		`SELECT `+layoutVersionColumns+`
+		 FROM layout_versions v LEFT JOIN users u ON u.id = v.created_by
+		 WHERE v.id = ?`, id).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding layout version: %w", err)
+	}
+	return v, nil
+}
+
+// LayoutVersionService records and looks up layout versions. Used by the
+// campaigns and entities handlers around every layout write.
+type LayoutVersionService interface {
+	// Record stores layout (nil = default) as the version a write is
+	// about to replace. A layout identical to the newest version isn't
+	// stored again. Failures are logged, not returned: losing a history
+	// entry must never block the save.
+	Record(ctx context.Context, campaignID, kind, targetID string, layout *string, note, userID string)
+	List(ctx context.Context, campaignID, kind, targetID string) ([]LayoutVersion, error)
+	// Get returns a version of the given target, or NotFound.
+	Get(ctx context.Context, campaignID, kind, targetID string, id int64) (*LayoutVersion, error)
+}
+
+// layoutVersionService implements LayoutVersionService.
+type layoutVersionService struct {
+	repo LayoutVersionRepository
+}
+
+// NewLayoutVersionService creates a layout version service.
+func NewLayoutVersionService(repo LayoutVersionRepository) LayoutVersionService {
+	return &layoutVersionService{repo: repo}
+}
+
+// Record skips duplicates so repeated saves of the same layout don't push
+// real history out.
+func (s *layoutVersionService) Record(ctx context.Context, campaignID, kind, targetID string, layout *string, note, userID string) {
+	if layout != nil && *layout == "" {
+		layout = nil
+	}
+	latest, err := s.repo.Latest(ctx, campaignID, kind, targetID)
+	if err == nil && latest != nil && sameLayout(latest.Layout, layout) {
+		return
+	}
+	v := &LayoutVersion{
+		CampaignID: campaignID, Kind: kind, TargetID: targetID,
+		Layout: layout, Note: truncateNote(note), CreatedBy: userID,
+	}
+	if err == nil {
+		err = s.repo.Create(ctx, v, MaxLayoutVersions)
+	}
+	if err != nil {
+		slog.Warn("recording layout version failed",
+			slog.String("campaign_id", campaignID),
+			slog.String("kind", kind),
+			slog.Any("error", err))
+	}
+}
+
+// List returns the target's versions, newest first.
+func (s *layoutVersionService) List(ctx context.Context, campaignID, kind, targetID string) ([]LayoutVersion, error) {
+	list, err := s.repo.List(ctx, campaignID, kind, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []LayoutVersion{}
+	}
+	return list, nil
+}
+
+// Get checks the version belongs to the target, so one campaign can't
+// restore another's layout by ID.
+func (s *layoutVersionService) Get(ctx context.Context, campaignID, kind, targetID string, id int64) (*LayoutVersion, error) {
+	v, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || v.CampaignID != campaignID || v.Kind != kind || v.TargetID != targetID {
+		return nil, apperror.NewNotFound("layout version not found")
+	}
+	return v, nil
+}
+
+// sameLayout compares two stored layouts, nil meaning the default.
+func sameLayout(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// truncateNote keeps a note within the column.
+func truncateNote(s string) string {
+	if r := []rune(s); len(r) > 100 {
+		return string(r[:100])
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+package campaigns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// recordLayoutVersion keeps layout as the version the current write is
+// replacing. No-op until the version service is wired.
+func (h *Handler) recordLayoutVersion(c echo.Context, kind string, layout *string, note string) {
+	if h.layoutVersions == nil {
+		return
+	}
+	cc := GetCampaignContext(c)
+	h.layoutVersions.Record(c.Request().Context(), cc.Campaign.ID, kind, "", layout, note, auth.GetUserID(c))
+}
+
+// recordCurrentOwnerDashboard records the owner dashboard before a write.
+func (h *Handler) recordCurrentOwnerDashboard(c echo.Context, note string) error {
+	if h.layoutVersions == nil {
+		return nil
+	}
+	campaign, err := h.service.GetByID(c.Request().Context(), GetCampaignContext(c).Campaign.ID)
+	if err != nil {
+		return err
+	}
+	h.recordLayoutVersion(c, LayoutKindOwnerDashboard, campaign.OwnerDashboardLayout, note)
+	return nil
+}
+
+// layoutVersionID parses the :vid path param.
+func layoutVersionID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("vid"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apperror.NewBadRequest("invalid version ID")
+	}
+	return id, nil
+}
+
+// listLayoutVersions serves a campaign-level target's history.
+func (h *Handler) listLayoutVersions(c echo.Context, kind string) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.layoutVersions == nil {
+		return apperror.NewMissingContext()
+	}
+	list, err := h.layoutVersions.List(c.Request().Context(), cc.Campaign.ID, kind, "")
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// ListDashboardLayoutVersions returns earlier campaign dashboard layouts,
+// newest first (GET /campaigns/:id/dashboard-layout/versions). A version
+// holds every role's layout as it was.
+func (h *Handler) ListDashboardLayoutVersions(c echo.Context) error {
+	return h.listLayoutVersions(c, LayoutKindDashboard)
+}
+
+// RestoreDashboardLayoutVersion puts an earlier campaign dashboard back,
+// recording the current one first so the restore can be undone
+// (POST /campaigns/:id/dashboard-layout/versions/:vid/restore).
+func (h *Handler) RestoreDashboardLayoutVersion(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.layoutVersions == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := layoutVersionID(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	v, err := h.layoutVersions.Get(ctx, cc.Campaign.ID, LayoutKindDashboard, "", id)
+	if err != nil {
+		return err
+	}
+	campaign, err := h.service.GetByID(ctx, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	h.recordLayoutVersion(c, LayoutKindDashboard, campaign.DashboardLayout, fmt.Sprintf("Restored version %d", v.ID))
+	if err := h.service.UpdateDashboardLayoutRaw(ctx, cc.Campaign.ID, v.Layout); err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "dashboard_layout_restored", map[string]any{"version_id": v.ID})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ListOwnerDashboardLayoutVersions returns earlier owner dashboard layouts
+// (GET /campaigns/:id/owner-dashboard-layout/versions).
+func (h *Handler) ListOwnerDashboardLayoutVersions(c echo.Context) error {
+	return h.listLayoutVersions(c, LayoutKindOwnerDashboard)
+}
+
+// RestoreOwnerDashboardLayoutVersion puts an earlier owner dashboard back
+// (POST /campaigns/:id/owner-dashboard-layout/versions/:vid/restore).
+func (h *Handler) RestoreOwnerDashboardLayoutVersion(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil || h.layoutVersions == nil {
+		return apperror.NewMissingContext()
+	}
+	id, err := layoutVersionID(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	v, err := h.layoutVersions.Get(ctx, cc.Campaign.ID, LayoutKindOwnerDashboard, "", id)
+	if err != nil {
+		return err
+	}
+	var layout *DashboardLayout
+	if !v.IsDefault {
+		layout = &DashboardLayout{}
+		if err := json.Unmarshal([]byte(*v.Layout), layout); err != nil {
+			return apperror.NewInternal(fmt.Errorf("parsing layout version %d: %w", v.ID, err))
+		}
+	}
+	if err := h.recordCurrentOwnerDashboard(c, fmt.Sprintf("Restored version %d", v.ID)); err != nil {
+		return err
+	}
+	if layout == nil {
+		err = h.service.ResetOwnerDashboardLayout(ctx, cc.Campaign.ID)
+	} else {
+		err = h.service.UpdateOwnerDashboardLayout(ctx, cc.Campaign.ID, layout)
+	}
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "owner_dashboard_layout_restored", map[string]any{"version_id": v.ID})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
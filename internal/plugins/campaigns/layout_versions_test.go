@@ -0,0 +1,125 @@
+package campaigns
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeLayoutVersionRepo is an in-memory LayoutVersionRepository.
+type fakeLayoutVersionRepo struct {
+	items []LayoutVersion // oldest first
+}
+
+func (r *fakeLayoutVersionRepo) Latest(_ context.Context, campaignID, kind, targetID string) (*LayoutVersion, error) {
+	list, _ := r.List(context.Background(), campaignID, kind, targetID)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	return &list[0], nil
+}
+
+func (r *fakeLayoutVersionRepo) Create(_ context.Context, v *LayoutVersion, keep int) error {
+	v.ID = int64(len(r.items) + 1)
+	v.IsDefault = v.Layout == nil
+	r.items = append(r.items, *v)
+	var kept []LayoutVersion
+	n := 0
+	for i := len(r.items) - 1; i >= 0; i-- {
+		it := r.items[i]
+		if it.CampaignID == v.CampaignID && it.Kind == v.Kind && it.TargetID == v.TargetID {
+			if n++; n > keep {
+				continue
+			}
+		}
+		kept = append([]LayoutVersion{it}, kept...)
+	}
+	r.items = kept
+	return nil
+}
+
+func (r *fakeLayoutVersionRepo) List(_ context.Context, campaignID, kind, targetID string) ([]LayoutVersion, error) {
+	var out []LayoutVersion
+	for i := len(r.items) - 1; i >= 0; i-- {
+		it := r.items[i]
+		if it.CampaignID == campaignID && it.Kind == kind && it.TargetID == targetID {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeLayoutVersionRepo) FindByID(_ context.Context, id int64) (*LayoutVersion, error) {
+	for _, it := range r.items {
+		if it.ID == id {
+			return &it, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestLayoutVersionRecord_SkipsRepeatOfLatest(t *testing.T) {
+	repo := &fakeLayoutVersionRepo{}
+	svc := NewLayoutVersionService(repo)
+	ctx := context.Background()
+	a, b := `{"rows":[]}`, `{"rows":[{"id":"r1"}]}`
+
+	svc.Record(ctx, "c1", LayoutKindDashboard, "", &a, "first", "u1")
+	svc.Record(ctx, "c1", LayoutKindDashboard, "", &a, "again", "u1")
+	svc.Record(ctx, "c1", LayoutKindDashboard, "", nil, "reset", "u1")
+	empty := ""
+	svc.Record(ctx, "c1", LayoutKindDashboard, "", &empty, "reset again", "u1")
+	svc.Record(ctx, "c1", LayoutKindDashboard, "", &b, "second", "u1")
+
+	list, err := svc.List(ctx, "c1", LayoutKindDashboard, "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("want 3 versions, got %+v", list)
+	}
+	if list[0].Note != "second" || !list[1].IsDefault || list[2].Note != "first" {
+		t.Errorf("versions out of order: %+v", list)
+	}
+}
+
+func TestLayoutVersionRecord_KeepsNewest(t *testing.T) {
+	repo := &fakeLayoutVersionRepo{}
+	svc := NewLayoutVersionService(repo)
+	ctx := context.Background()
+	for i := 0; i < MaxLayoutVersions+5; i++ {
+		layout := string(rune('a' + i))
+		svc.Record(ctx, "c1", LayoutKindEntityTypeLayout, "7", &layout, "", "")
+	}
+	list, _ := svc.List(ctx, "c1", LayoutKindEntityTypeLayout, "7")
+	if len(list) != MaxLayoutVersions {
+		t.Fatalf("want %d versions, got %d", MaxLayoutVersions, len(list))
+	}
+	if *list[0].Layout != string(rune('a'+MaxLayoutVersions+4)) {
+		t.Errorf("newest version dropped: %q", *list[0].Layout)
+	}
+}
+
+func TestLayoutVersionGet_ScopedToTarget(t *testing.T) {
+	repo := &fakeLayoutVersionRepo{}
+	svc := NewLayoutVersionService(repo)
+	ctx := context.Background()
+	layout := `{"rows":[]}`
+	svc.Record(ctx, "c1", LayoutKindCategoryDashboard, "3", &layout, "", "")
+
+	if _, err := svc.Get(ctx, "c1", LayoutKindCategoryDashboard, "3", 1); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_, err := svc.Get(ctx, "c2", LayoutKindCategoryDashboard, "3", 1)
+	assertAppError(t, err, 404)
+	_, err = svc.Get(ctx, "c1", LayoutKindCategoryDashboard, "4", 1)
+	assertAppError(t, err, 404)
+	_, err = svc.Get(ctx, "c1", LayoutKindDashboard, "", 1)
+	assertAppError(t, err, 404)
+}
+
+func TestLayoutVersionList_EmptyIsNotNil(t *testing.T) {
+	list, err := NewLayoutVersionService(&fakeLayoutVersionRepo{}).List(context.Background(), "c1", LayoutKindOwnerDashboard, "")
+	if err != nil || list == nil {
+		t.Errorf("List = %v, %v; want empty slice", list, err)
+	}
+}
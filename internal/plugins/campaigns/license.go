@@ -0,0 +1,102 @@
+package campaigns
+
+// license.go — a campaign's content license and attribution. Public
+// campaigns show them in the page footer (layouts.ContentLicenseData,
+// set by the layout injector); campaign exports carry them in the JSON
+// envelope and as LICENSE.txt in the media zip.
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// maxAttributionLength caps the attribution text.
+const maxAttributionLength = 500
+
+// LicenseOption is a license an owner can pick.
+type LicenseOption struct {
+	Key  string // Stored in LicenseSettings.License.
+	Name string // Short display name, e.g. "CC BY 4.0".
+	URL  string // Deed URL; empty for "All rights reserved".
+}
+
+// LicenseOptions lists the licenses offered on the settings page, in
+// display order.
+var LicenseOptions = []LicenseOption{
+	{Key: "cc-by-4.0", Name: "CC BY 4.0", URL: "https://creativecommons.org/licenses/by/4.0/"},
+	{Key: "cc-by-sa-4.0", Name: "CC BY-SA 4.0", URL: "https://creativecommons.org/licenses/by-sa/4.0/"},
+	{Key: "cc-by-nc-4.0", Name: "CC BY-NC 4.0", URL: "https://creativecommons.org/licenses/by-nc/4.0/"},
+	{Key: "cc-by-nc-sa-4.0", Name: "CC BY-NC-SA 4.0", URL: "https://creativecommons.org/licenses/by-nc-sa/4.0/"},
+	{Key: "cc-by-nd-4.0", Name: "CC BY-ND 4.0", URL: "https://creativecommons.org/licenses/by-nd/4.0/"},
+	{Key: "cc-by-nc-nd-4.0", Name: "CC BY-NC-ND 4.0", URL: "https://creativecommons.org/licenses/by-nc-nd/4.0/"},
+	{Key: "cc0-1.0", Name: "CC0 1.0", URL: "https://creativecommons.org/publicdomain/zero/1.0/"},
+	{Key: "all-rights-reserved", Name: "All rights reserved"},
+}
+
+// LicenseSettings is the campaign's content license and attribution.
+type LicenseSettings struct {
+	// License is a LicenseOptions key; empty means no license is stated.
+	License string `json:"license,omitempty"`
+
+	// Attribution is free text such as "World of Aster by J. Doe", shown
+	// next to the license.
+	Attribution string `json:"attribution,omitempty"`
+}
+
+// Normalize trims the fields and validates the license key and the
+// attribution length.
+func (s *LicenseSettings) Normalize() error {
+	s.License = strings.TrimSpace(s.License)
+	s.Attribution = strings.TrimSpace(s.Attribution)
+	if _, ok := s.Option(); s.License != "" && !ok {
+		return apperror.NewBadRequest("unknown license")
+	}
+	if utf8.RuneCountInString(s.Attribution) > maxAttributionLength {
+		return apperror.NewBadRequest(fmt.Sprintf("attribution must be %d characters or fewer", maxAttributionLength))
+	}
+	return nil
+}
+
+// Option returns the chosen license, false when none is set.
+func (s LicenseSettings) Option() (LicenseOption, bool) {
+	for _, o := range LicenseOptions {
+		if o.Key == s.License {
+			return o, true
+		}
+	}
+	return LicenseOption{}, false
+}
+
+// IsSet reports whether there is anything to show.
+func (s LicenseSettings) IsSet() bool {
+	return s.License != "" || s.Attribution != ""
+}
+
+// Notice is the plain-text license notice written into exports.
+func (s LicenseSettings) Notice(campaignName string) string {
+	var b strings.Builder
+	b.WriteString(campaignName + "\n\n")
+	if o, ok := s.Option(); ok {
+		b.WriteString("License: " + o.Name)
+		if o.URL != "" {
+			b.WriteString(" (" + o.URL + ")")
+		}
+		b.WriteString("\n")
+	}
+	if s.Attribution != "" {
+		b.WriteString("Attribution: " + s.Attribution + "\n")
+	}
+	return b.String()
+}
+
+// GetLicense returns the campaign's license settings (empty when never
+// configured).
+func (s CampaignSettings) GetLicense() LicenseSettings {
+	if s.License == nil {
+		return LicenseSettings{}
+	}
+	return *s.License
+}
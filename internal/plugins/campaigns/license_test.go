@@ -0,0 +1,56 @@
+package campaigns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLicenseSettings_Normalize(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      LicenseSettings
+		wantErr bool
+	}{
+		{name: "empty", in: LicenseSettings{}},
+		{name: "known license", in: LicenseSettings{License: " cc-by-4.0 ", Attribution: " World of Aster by J. Doe "}},
+		{name: "attribution only", in: LicenseSettings{Attribution: "J. Doe"}},
+		{name: "unknown license", in: LicenseSettings{License: "gpl-3.0"}, wantErr: true},
+		{name: "attribution too long", in: LicenseSettings{Attribution: strings.Repeat("a", maxAttributionLength+1)}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.in
+			err := s.Normalize()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Normalize() err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if s.License != strings.TrimSpace(s.License) || s.Attribution != strings.TrimSpace(s.Attribution) {
+				t.Errorf("fields not trimmed: %+v", s)
+			}
+		})
+	}
+}
+
+func TestLicenseSettings_Notice(t *testing.T) {
+	s := LicenseSettings{License: "cc-by-sa-4.0", Attribution: "World of Aster by J. Doe"}
+	want := "Aster\n\nLicense: CC BY-SA 4.0 (https://creativecommons.org/licenses/by-sa/4.0/)\nAttribution: World of Aster by J. Doe\n"
+	if got := s.Notice("Aster"); got != want {
+		t.Errorf("Notice() = %q, want %q", got, want)
+	}
+
+	// All rights reserved has no deed URL.
+	s = LicenseSettings{License: "all-rights-reserved"}
+	if got := s.Notice("Aster"); got != "Aster\n\nLicense: All rights reserved\n" {
+		t.Errorf("Notice() = %q", got)
+	}
+}
+
+func TestNewExportLicense(t *testing.T) {
+	if newExportLicense(LicenseSettings{}) != nil {
+		t.Error("unset license exported")
+	}
+	got := newExportLicense(LicenseSettings{License: "cc0-1.0", Attribution: "J. Doe"})
+	if got == nil || got.Name != "CC0 1.0" || got.URL == "" || got.Attribution != "J. Doe" {
+		t.Errorf("export license = %+v", got)
+	}
+}
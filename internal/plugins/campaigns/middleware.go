@@ -2,10 +2,12 @@ package campaigns
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 )
 
@@ -67,12 +69,75 @@ func RequireCampaignAccess(service CampaignService) echo.MiddlewareFunc {
 			// Check if this member has been granted dm_only visibility.
 			cc.IsDmGranted = hasDmGrant(campaign, session.UserID)
 
+			if err := checkPendingDeletion(c, cc); err != nil {
+				return err
+			}
+
 			c.Set(contextKeyCampaign, cc)
+			middleware.SetLogCampaign(c, cc.Campaign.ID)
 			return next(c)
 		}
 	}
 }
 
+// ResolveCampaignSlug returns middleware that accepts a campaign slug in the
+// :id URL parameter and rewrites it to the campaign's ID, so the access
+// middleware and handlers after it see the usual UUID. A value that is not
+// a campaign slug passes through untouched — it is then looked up as an ID,
+// which keeps UUID links working on slug-capable routes.
+//
+// Must be applied BEFORE RequireCampaignAccess / AllowPublicCampaignAccess.
+func ResolveCampaignSlug(service CampaignService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			campaign, err := service.GetBySlug(c.Request().Context(), c.Param("id"))
+			if apperror.SafeCode(err) == http.StatusNotFound || (err == nil && campaign == nil) {
+				return next(c)
+			}
+			if err != nil {
+				return err
+			}
+			names, values := c.ParamNames(), c.ParamValues()
+			for i, name := range names {
+				if name == "id" && i < len(values) {
+					values[i] = campaign.ID
+				}
+			}
+			c.SetParamValues(values...)
+			return next(c)
+		}
+	}
+}
+
+// restoreRoutePath is the one write allowed on a campaign pending deletion.
+const restoreRoutePath = "/campaigns/:id/restore"
+
+// checkPendingDeletion hides a campaign in its deletion grace period.
+// Everyone but its owner and site admins gets a 404 rather than a 403, so
+// the campaign looks gone. The owner and admins keep read-only access plus
+// the restore route: edits made now would be purged anyway.
+func checkPendingDeletion(c echo.Context, cc *CampaignContext) error {
+	method := c.Request().Method
+	write := method != http.MethodGet && method != http.MethodHead && c.Path() != restoreRoutePath
+	return CheckPendingDeletion(cc.Campaign, cc.MemberRole, cc.IsSiteAdmin, write)
+}
+
+// CheckPendingDeletion applies the deletion grace period rules for callers
+// that don't go through RequireCampaignAccess — API keys, WebSockets and
+// overlays. write reports whether the request would change anything.
+func CheckPendingDeletion(campaign *Campaign, role Role, isSiteAdmin, write bool) error {
+	if !campaign.IsPendingDeletion() {
+		return nil
+	}
+	if role < RoleOwner && !isSiteAdmin {
+		return apperror.NewNotFound("campaign not found")
+	}
+	if !write {
+		return nil
+	}
+	return apperror.NewForbidden("campaign is scheduled for deletion; restore it to make changes")
+}
+
 // hasDmGrant checks whether a user has been granted dm_only visibility
 // via the campaign's DmGrantIDs setting.
 func hasDmGrant(campaign *Campaign, userID string) bool {
@@ -133,7 +198,11 @@ func AllowPublicCampaignAccess(service CampaignService) echo.MiddlewareFunc {
 					cc.MemberRole = RoleNone
 				}
 				cc.IsDmGranted = hasDmGrant(campaign, session.UserID)
+				if err := checkPendingDeletion(c, cc); err != nil {
+					return err
+				}
 				c.Set(contextKeyCampaign, cc)
+				middleware.SetLogCampaign(c, cc.Campaign.ID)
 				return next(c)
 			}
 
@@ -148,7 +217,11 @@ func AllowPublicCampaignAccess(service CampaignService) echo.MiddlewareFunc {
 				IsSiteAdmin: false,
 				IsAnonymous: true,
 			}
+			if err := checkPendingDeletion(c, cc); err != nil {
+				return err
+			}
 			c.Set(contextKeyCampaign, cc)
+			middleware.SetLogCampaign(c, cc.Campaign.ID)
 			return next(c)
 		}
 	}
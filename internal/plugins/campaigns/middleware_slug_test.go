@@ -0,0 +1,78 @@
+package campaigns
+
+// middleware_slug_test.go — ResolveCampaignSlug. Drives the middleware through
+// a real Echo router, since it rewrites the router-populated :id param that
+// everything after it reads.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// stubSlugSvc resolves the single campaign slug "waterdeep" to camp-1.
+type stubSlugSvc struct {
+	CampaignService
+	err error
+}
+
+func (s *stubSlugSvc) GetBySlug(_ context.Context, slug string) (*Campaign, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if slug == "waterdeep" {
+		return &Campaign{ID: "camp-1", Slug: "waterdeep"}, nil
+	}
+	return nil, apperror.NewNotFound("campaign not found")
+}
+
+func TestResolveCampaignSlug(t *testing.T) {
+	cases := []struct {
+		name    string
+		path    string
+		svcErr  error
+		wantID  string
+		wantErr bool
+	}{
+		{name: "campaign slug rewritten to ID", path: "/campaigns/waterdeep/e/gandalf", wantID: "camp-1"},
+		{name: "campaign ID passes through", path: "/campaigns/camp-1/e/gandalf", wantID: "camp-1"},
+		{name: "unknown value passes through for the ID lookup", path: "/campaigns/nowhere/e/gandalf", wantID: "nowhere"},
+		{name: "lookup failure surfaces", path: "/campaigns/waterdeep/e/gandalf", svcErr: errors.New("db down"), wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			var gotID, gotSlug string
+			var gotErr error
+			g := e.Group("/campaigns/:id/e", ResolveCampaignSlug(&stubSlugSvc{err: tc.svcErr}))
+			g.GET("/:eslug", func(c echo.Context) error {
+				gotID, gotSlug = c.Param("id"), c.Param("eslug")
+				return c.NoContent(http.StatusOK)
+			})
+			e.HTTPErrorHandler = func(err error, c echo.Context) { gotErr = err }
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if tc.wantErr {
+				if gotErr == nil {
+					t.Error("expected the lookup error to surface")
+				}
+				return
+			}
+			if gotErr != nil {
+				t.Fatalf("unexpected error: %v", gotErr)
+			}
+			if gotID != tc.wantID {
+				t.Errorf(":id = %q, want %q", gotID, tc.wantID)
+			}
+			if gotSlug != "gandalf" {
+				t.Errorf(":eslug = %q, want gandalf (other params untouched)", gotSlug)
+			}
+		})
+	}
+}
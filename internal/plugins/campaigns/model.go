@@ -112,6 +112,8 @@ type Campaign struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 	ArchivedAt      *time.Time `json:"archived_at,omitempty"`               // Soft-archive timestamp; nil = active.
 	JoinCode        *string    `json:"join_code,omitempty"`                  // Shareable invite code; nil = no active link.
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"` // Set while the campaign is in the deletion grace period.
+	PurgeAfter          *time.Time `json:"purge_after,omitempty"`           // When the purge job may hard-delete it.
 }
 
 // IsArchived returns true if the campaign has been soft-archived.
@@ -119,6 +121,12 @@ func (c *Campaign) IsArchived() bool {
 	return c.ArchivedAt != nil
 }
 
+// IsPendingDeletion returns true while the campaign is marked for deletion
+// and can still be restored.
+func (c *Campaign) IsPendingDeletion() bool {
+	return c.DeletionRequestedAt != nil
+}
+
 // SidebarConfig holds campaign-level sidebar customization settings.
 // Stored as JSON in campaigns.sidebar_config. Controls the ordered list of
 // sidebar items plus the sets of individually-hidden entities and folder nodes.
@@ -202,6 +210,17 @@ type CampaignMember struct {
 	CharacterName *string `json:"character_name,omitempty"`
 }
 
+// UserMembership is one campaign a user belongs to, as the admin user
+// detail page lists it. Includes archived campaigns.
+type UserMembership struct {
+	CampaignID   string
+	CampaignName string
+	CampaignSlug string
+	Role         Role
+	JoinedAt     time.Time
+	Archived     bool
+}
+
 // CampaignContext holds the resolved campaign and the requesting user's
 // effective permissions. Injected into the Echo context by
 // RequireCampaignAccess middleware.
@@ -289,6 +308,15 @@ type OwnershipTransfer struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
+// TransferOffer is what the recipient of an ownership transfer sees before
+// accepting or declining it.
+type TransferOffer struct {
+	Transfer    *OwnershipTransfer
+	Campaign    *Campaign
+	FromName    string // Current owner's display name.
+	MemberCount int
+}
+
 // --- Dashboard Layout Types ---
 
 // DefaultDashboardLayout returns the synthesized dashboard layout used
@@ -581,6 +609,27 @@ type CampaignSettings struct {
 	// existing settings JSON per Option B locked 2026-05-28 post-
 	// C-THEME-CUSTOMIZATION-AUDIT.
 	EventTierDefinitions []TierDefinition `json:"event_tier_definitions,omitempty"`
+
+	// Retention sets how long audit entries, notifications and API request
+	// logs are kept. Nil = keep forever. Purged daily by RetentionJob.
+	Retention *RetentionSettings `json:"retention,omitempty"`
+
+	// ImageProxy serves external images in entries through Chronicle.
+	// Nil = off.
+	ImageProxy *ImageProxySettings `json:"image_proxy,omitempty"`
+
+	// License is the content license and attribution shown on public
+	// pages and in exports. Nil = none stated.
+	License *LicenseSettings `json:"license,omitempty"`
+
+	// Onboarding is the welcome page and new-member checklist. Nil = none.
+	Onboarding *OnboardingSettings `json:"onboarding,omitempty"`
+
+	// Overlays holds the stream overlay token and widget config. Nil = off.
+	Overlays *OverlaySettings `json:"overlays,omitempty"`
+
+	// StreamMode is the player-safe stream projection. Nil = off.
+	StreamMode *StreamModeSettings `json:"stream_mode,omitempty"`
 }
 
 // TierDefinition is a single entry in the per-campaign event tier
@@ -658,6 +707,7 @@ const (
 	BlockSessionTracker  = "session_tracker"  // Upcoming sessions with RSVP status.
 	BlockActivityFeed    = "activity_feed"    // Recent campaign activity log.
 	BlockSyncStatus      = "sync_status"      // Foundry VTT sync health/status.
+	BlockFactionStandings = "faction_standings" // Current faction standings summary.
 
 	// Category dashboard blocks.
 	BlockCategoryHeader = "category_header" // Category name, icon, count, description.
@@ -685,6 +735,7 @@ var ValidBlockTypes = map[string]bool{
 	BlockSessionTracker:  true,
 	BlockActivityFeed:    true,
 	BlockSyncStatus:      true,
+	BlockFactionStandings: true,
 	BlockCategoryHeader:  true,
 	BlockEntityGrid:     true,
 	BlockSearchBar:      true,
@@ -712,6 +763,7 @@ type MemberUser struct {
 // nil if SMTP is not configured.
 type MailService interface {
 	SendMail(ctx context.Context, to []string, subject, body string) error
+	SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error
 	IsConfigured(ctx context.Context) bool
 }
 
@@ -745,9 +797,10 @@ type LayoutPresetSeeder interface {
 
 // CreateCampaignRequest holds the data submitted by the campaign creation form.
 type CreateCampaignRequest struct {
-	Name        string `json:"name" form:"name"`
-	Description string `json:"description" form:"description"`
-	Genre       string `json:"genre" form:"genre"`
+	Name        string   `json:"name" form:"name"`
+	Description string   `json:"description" form:"description"`
+	Genre       string   `json:"genre" form:"genre"`
+	Addons      []string `json:"addons" form:"addons"` // Extension slugs ticked on the form.
 }
 
 // UpdateCampaignRequest holds the data submitted by the campaign edit form.
@@ -789,7 +842,8 @@ type UpdateSidebarConfigRequest struct {
 type CreateCampaignInput struct {
 	Name        string
 	Description string
-	Genre       string // Optional genre preset for entity type seeding.
+	Genre       string   // Optional genre preset for entity type seeding.
+	Addons      []string // Extension slugs to enable; see create_addons.go.
 }
 
 // UpdateCampaignInput is the validated input for updating a campaign.
@@ -0,0 +1,299 @@
+package campaigns
+
+// onboarding.go — the campaign welcome page and new-member checklist. The
+// owner configures both in settings (CampaignSettings.Onboarding): a pinned
+// entity and/or custom text to greet members, and a short list of things
+// to do first ("read the house rules", "claim a character"). Each member's
+// ticks are stored per item in campaign_onboarding_progress, and the
+// campaign dashboard nags them until every item is done. A "character"
+// item completes itself once the member has claimed a character or the
+// owner has linked one to them.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+const (
+	onboardingMaxItems     = 12
+	onboardingMaxLabel     = 100
+	onboardingMaxContent   = 5000
+	onboardingItemIDMaxLen = 16
+	onboardingItemIDBytes  = 4
+)
+
+// Checklist item kinds. Manual items are ticked by the member; character
+// items are done when the member has a character (see HasCharacter), and
+// house rules items when they acknowledge the house rules.
+const (
+	OnboardingItemManual     = ""
+	OnboardingItemCharacter  = "character"
+	OnboardingItemHouseRules = "house_rules"
+)
+
+// OnboardingSettings configures a campaign's welcome page and checklist.
+type OnboardingSettings struct {
+	// WelcomeEntityID pins an entity (typically the house rules or a
+	// setting primer) to the welcome page.
+	WelcomeEntityID string `json:"welcome_entity_id,omitempty"`
+
+	// WelcomeContent is plain text shown on the welcome page; line breaks
+	// are kept.
+	WelcomeContent string `json:"welcome_content,omitempty"`
+
+	Checklist []OnboardingItem `json:"checklist,omitempty"`
+}
+
+// OnboardingItem is one entry on the new-member checklist.
+type OnboardingItem struct {
+	// ID keys the member's progress, so it survives renames and
+	// reordering. Assigned by the server when an item is first saved.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Link  string `json:"link,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+// IsZero reports whether there is nothing to show new members.
+func (o OnboardingSettings) IsZero() bool {
+	return o.WelcomeEntityID == "" && strings.TrimSpace(o.WelcomeContent) == "" && len(o.Checklist) == 0
+}
+
+// Normalize trims and validates the settings and gives new checklist items
+// an ID. Items keep the ID the client sent back so progress carries over.
+func (o *OnboardingSettings) Normalize() error {
+	o.WelcomeEntityID = strings.TrimSpace(o.WelcomeEntityID)
+	if len(o.WelcomeEntityID) > 36 {
+		return apperror.NewBadRequest("invalid welcome page entity")
+	}
+	o.WelcomeContent = strings.TrimSpace(o.WelcomeContent)
+	if utf8.RuneCountInString(o.WelcomeContent) > onboardingMaxContent {
+		return apperror.NewBadRequest(fmt.Sprintf("welcome text must be %d characters or fewer", onboardingMaxContent))
+	}
+
+	items := make([]OnboardingItem, 0, len(o.Checklist))
+	seen := make(map[string]bool, len(o.Checklist))
+	for _, item := range o.Checklist {
+		item.Label = strings.TrimSpace(item.Label)
+		item.Link = strings.TrimSpace(item.Link)
+		if item.Label == "" {
+			continue
+		}
+		if utf8.RuneCountInString(item.Label) > onboardingMaxLabel {
+			return apperror.NewBadRequest(fmt.Sprintf("checklist items must be %d characters or fewer", onboardingMaxLabel))
+		}
+		if item.Link != "" {
+			if err := validateNavLinkURL(item.Label, item.Link); err != nil {
+				return err
+			}
+		}
+		if !isOnboardingItemKind(item.Kind) {
+			return apperror.NewBadRequest(fmt.Sprintf("unknown checklist item kind %q", item.Kind))
+		}
+		if !isOnboardingItemID(item.ID) || seen[item.ID] {
+			id, err := newOnboardingItemID()
+			if err != nil {
+				return apperror.NewInternal(fmt.Errorf("generating checklist item id: %w", err))
+			}
+			item.ID = id
+		}
+		seen[item.ID] = true
+		items = append(items, item)
+	}
+	if len(items) > onboardingMaxItems {
+		return apperror.NewBadRequest(fmt.Sprintf("at most %d checklist items", onboardingMaxItems))
+	}
+	o.Checklist = items
+	return nil
+}
+
+// item returns the checklist item with the given ID.
+func (o OnboardingSettings) item(id string) (OnboardingItem, bool) {
+	for _, item := range o.Checklist {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return OnboardingItem{}, false
+}
+
+// GetOnboarding returns the campaign's onboarding settings (zero value when
+// unset).
+func (s CampaignSettings) GetOnboarding() OnboardingSettings {
+	if s.Onboarding == nil {
+		return OnboardingSettings{}
+	}
+	return *s.Onboarding
+}
+
+func isOnboardingItemID(id string) bool {
+	if id == "" || len(id) > onboardingItemIDMaxLen {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func newOnboardingItemID() (string, error) {
+	b := make([]byte, onboardingItemIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isOnboardingItemKind reports whether kind is a known checklist item kind.
+func isOnboardingItemKind(kind string) bool {
+	switch kind {
+	case OnboardingItemManual, OnboardingItemCharacter, OnboardingItemHouseRules:
+		return true
+	}
+	return false
+}
+
+// OnboardingItemStatus is a checklist item with the member's progress.
+type OnboardingItemStatus struct {
+	OnboardingItem
+	Done bool
+}
+
+// OnboardingStatus is one member's view of the welcome page.
+type OnboardingStatus struct {
+	Settings OnboardingSettings
+	Items    []OnboardingItemStatus
+	Done     int
+}
+
+// Complete reports whether the member has finished the checklist. A
+// campaign with only welcome content has nothing to complete.
+func (s *OnboardingStatus) Complete() bool {
+	return s.Done >= len(s.Items)
+}
+
+// buildOnboardingStatus merges the checklist with the member's ticks.
+func buildOnboardingStatus(o OnboardingSettings, done map[string]bool, hasCharacter, ackedHouseRules bool) *OnboardingStatus {
+	status := &OnboardingStatus{Settings: o, Items: make([]OnboardingItemStatus, len(o.Checklist))}
+	for i, item := range o.Checklist {
+		d := done[item.ID]
+		switch item.Kind {
+		case OnboardingItemCharacter:
+			d = hasCharacter
+		case OnboardingItemHouseRules:
+			d = ackedHouseRules
+		}
+		status.Items[i] = OnboardingItemStatus{OnboardingItem: item, Done: d}
+		if d {
+			status.Done++
+		}
+	}
+	return status
+}
+
+// UpdateOnboarding sets the campaign's welcome page and checklist. Saving
+// an empty configuration clears it. Progress on removed items is left in
+// place; it no longer matches anything and costs a few rows.
+func (s *campaignService) UpdateOnboarding(ctx context.Context, campaignID string, onboarding OnboardingSettings) error {
+	if err := onboarding.Normalize(); err != nil {
+		return err
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	settings := campaign.ParseSettings()
+	if onboarding.IsZero() {
+		settings.Onboarding = nil
+	} else {
+		settings.Onboarding = &onboarding
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+
+	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
+}
+
+// GetOnboardingStatus returns the member's welcome page and checklist
+// progress, or nil when the campaign has no onboarding configured.
+func (s *campaignService) GetOnboardingStatus(ctx context.Context, campaign *Campaign, userID string) (*OnboardingStatus, error) {
+	onboarding := campaign.ParseSettings().GetOnboarding()
+	if onboarding.IsZero() {
+		return nil, nil
+	}
+
+	done := map[string]bool{}
+	if userID != "" && len(onboarding.Checklist) > 0 {
+		ids, err := s.repo.ListOnboardingProgress(ctx, campaign.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			done[id] = true
+		}
+	}
+
+	hasCharacter, ackedHouseRules := false, false
+	kinds := map[string]bool{}
+	for _, item := range onboarding.Checklist {
+		kinds[item.Kind] = true
+	}
+	if kinds[OnboardingItemCharacter] {
+		has, err := s.repo.HasCharacter(ctx, campaign.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		hasCharacter = has
+	}
+	if kinds[OnboardingItemHouseRules] {
+		acked, err := s.repo.HasAcknowledgedHouseRules(ctx, campaign.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		ackedHouseRules = acked
+	}
+	return buildOnboardingStatus(onboarding, done, hasCharacter, ackedHouseRules), nil
+}
+
+// SetOnboardingItem ticks or unticks a manual checklist item for a member.
+func (s *campaignService) SetOnboardingItem(ctx context.Context, campaignID, userID, itemID string, done bool) error {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	item, ok := campaign.ParseSettings().GetOnboarding().item(itemID)
+	if !ok {
+		return apperror.NewNotFound("checklist item not found")
+	}
+	switch item.Kind {
+	case OnboardingItemCharacter:
+		return apperror.NewBadRequest("this item completes when you claim a character")
+	case OnboardingItemHouseRules:
+		return apperror.NewBadRequest("this item completes when you acknowledge the house rules")
+	}
+	return s.repo.SetOnboardingProgress(ctx, campaignID, userID, itemID, done)
+}
+
+// onboardingLinkURL re-checks a checklist link at render, as the nav links
+// do, so a value stored before validation can't reach an href.
+func onboardingLinkURL(link string) string {
+	if safe, ok := sanitize.SafeLinkURL(link); ok {
+		return safe
+	}
+	return ""
+}
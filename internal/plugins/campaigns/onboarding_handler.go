@@ -0,0 +1,96 @@
+package campaigns
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+)
+
+// Welcome renders the campaign welcome page and the member's checklist
+// (GET /campaigns/:id/welcome).
+func (h *Handler) Welcome(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	status, err := h.service.GetOnboardingStatus(ctx, cc.Campaign, userID)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		return c.Redirect(http.StatusSeeOther, "/campaigns/"+cc.Campaign.ID)
+	}
+
+	var entity *RecentEntity
+	if id := status.Settings.WelcomeEntityID; id != "" && h.welcomeEntity != nil {
+		// A deleted or hidden entity just drops off the page.
+		entity, _ = h.welcomeEntity.GetWelcomeEntity(ctx, cc.Campaign.ID, id, int(cc.MemberRole), userID)
+	}
+
+	return middleware.Render(c, http.StatusOK, WelcomePage(cc, status, entity, middleware.GetCSRFToken(c)))
+}
+
+// SetOnboardingItem ticks or unticks a checklist item for the current
+// member (POST /campaigns/:id/welcome/items/:item). Returns the refreshed
+// checklist for HTMX.
+func (h *Handler) SetOnboardingItem(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	done := c.FormValue("done") == "true"
+	if err := h.service.SetOnboardingItem(ctx, cc.Campaign.ID, userID, c.Param("item"), done); err != nil {
+		return err
+	}
+
+	if !middleware.IsHTMX(c) {
+		return c.Redirect(http.StatusSeeOther, "/campaigns/"+cc.Campaign.ID+"/welcome")
+	}
+	status, err := h.service.GetOnboardingStatus(ctx, cc.Campaign, userID)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return middleware.Render(c, http.StatusOK, onboardingChecklist(cc, status, middleware.GetCSRFToken(c)))
+}
+
+// UpdateOnboardingAPI handles PUT /campaigns/:id/onboarding. Sets the
+// welcome page and the new-member checklist.
+func (h *Handler) UpdateOnboardingAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	var req OnboardingSettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateOnboarding(c.Request().Context(), cc.Campaign.ID, req); err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.onboarding.updated", map[string]any{
+		"checklist_items": len(req.Checklist),
+	})
+
+	// Return the saved checklist so the editor picks up new item IDs.
+	campaign, err := h.service.GetByID(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, campaign.ParseSettings().GetOnboarding())
+}
@@ -0,0 +1,146 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOnboardingSettings_Normalize(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       OnboardingSettings
+		wantCode int // 0 = valid
+		check    func(t *testing.T, o OnboardingSettings)
+	}{
+		{
+			name: "drops blank items and assigns IDs",
+			in: OnboardingSettings{Checklist: []OnboardingItem{
+				{Label: "  Read the house rules "}, {Label: "   "}, {ID: "keep1", Label: "Claim a character", Kind: OnboardingItemCharacter},
+			}},
+			check: func(t *testing.T, o OnboardingSettings) {
+				if len(o.Checklist) != 2 {
+					t.Fatalf("checklist = %+v", o.Checklist)
+				}
+				if o.Checklist[0].Label != "Read the house rules" || !isOnboardingItemID(o.Checklist[0].ID) {
+					t.Errorf("first item = %+v", o.Checklist[0])
+				}
+				if o.Checklist[1].ID != "keep1" {
+					t.Errorf("existing ID not kept: %+v", o.Checklist[1])
+				}
+			},
+		},
+		{
+			name: "duplicate IDs are reassigned",
+			in:   OnboardingSettings{Checklist: []OnboardingItem{{ID: "a1", Label: "One"}, {ID: "a1", Label: "Two"}}},
+			check: func(t *testing.T, o OnboardingSettings) {
+				if o.Checklist[0].ID != "a1" || o.Checklist[1].ID == "a1" {
+					t.Errorf("ids = %q, %q", o.Checklist[0].ID, o.Checklist[1].ID)
+				}
+			},
+		},
+		{name: "relative link", in: OnboardingSettings{Checklist: []OnboardingItem{{Label: "Calendar", Link: "/campaigns/c/apps/calendar"}}}},
+		{name: "script link", in: OnboardingSettings{Checklist: []OnboardingItem{{Label: "x", Link: "javascript:alert(1)"}}}, wantCode: 400},
+		{name: "unknown kind", in: OnboardingSettings{Checklist: []OnboardingItem{{Label: "x", Kind: "quiz"}}}, wantCode: 400},
+		{name: "welcome text too long", in: OnboardingSettings{WelcomeContent: strings.Repeat("a", onboardingMaxContent+1)}, wantCode: 400},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := tc.in
+			err := o.Normalize()
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize: %v", err)
+			}
+			if tc.check != nil {
+				tc.check(t, o)
+			}
+		})
+	}
+
+	t.Run("item limit", func(t *testing.T) {
+		o := OnboardingSettings{}
+		for range onboardingMaxItems + 1 {
+			o.Checklist = append(o.Checklist, OnboardingItem{Label: "item"})
+		}
+		assertAppError(t, o.Normalize(), 400)
+	})
+}
+
+func TestBuildOnboardingStatus(t *testing.T) {
+	o := OnboardingSettings{Checklist: []OnboardingItem{
+		{ID: "rules", Label: "Read the house rules"},
+		{ID: "char", Label: "Claim a character", Kind: OnboardingItemCharacter},
+		{ID: "cal", Label: "Check the calendar"},
+		{ID: "ack", Label: "Acknowledge the house rules", Kind: OnboardingItemHouseRules},
+	}}
+
+	status := buildOnboardingStatus(o, map[string]bool{"rules": true, "char": true, "ack": true}, false, false)
+	if status.Done != 1 || status.Complete() {
+		t.Errorf("done = %d, complete = %v; ticked automatic items must not count", status.Done, status.Complete())
+	}
+
+	status = buildOnboardingStatus(o, map[string]bool{"rules": true, "cal": true}, true, true)
+	if status.Done != 4 || !status.Complete() {
+		t.Errorf("done = %d, want all 4", status.Done)
+	}
+
+	if !buildOnboardingStatus(OnboardingSettings{WelcomeContent: "hi"}, nil, false, false).Complete() {
+		t.Error("welcome text alone has nothing to complete")
+	}
+}
+
+// onboardingRepo records checklist ticks over mockCampaignRepo.
+type onboardingRepo struct {
+	*mockCampaignRepo
+	settings OnboardingSettings
+	set      map[string]bool
+}
+
+func newOnboardingRepo(o OnboardingSettings) *onboardingRepo {
+	r := &onboardingRepo{settings: o, set: map[string]bool{}}
+	r.mockCampaignRepo = &mockCampaignRepo{
+		findByIDFn: func(_ context.Context, id string) (*Campaign, error) {
+			b, _ := json.Marshal(CampaignSettings{Onboarding: &r.settings})
+			return &Campaign{ID: id, Settings: string(b)}, nil
+		},
+	}
+	return r
+}
+
+func (r *onboardingRepo) SetOnboardingProgress(_ context.Context, _, _, itemID string, done bool) error {
+	r.set[itemID] = done
+	return nil
+}
+
+func TestSetOnboardingItem(t *testing.T) {
+	repo := newOnboardingRepo(OnboardingSettings{Checklist: []OnboardingItem{
+		{ID: "rules", Label: "Read the house rules"},
+		{ID: "char", Label: "Claim a character", Kind: OnboardingItemCharacter},
+		{ID: "ack", Label: "Acknowledge the house rules", Kind: OnboardingItemHouseRules},
+	}})
+	svc := NewCampaignService(repo, &mockUserFinder{}, nil, nil, "")
+	ctx := context.Background()
+
+	if err := svc.SetOnboardingItem(ctx, "camp-1", "user-1", "rules", true); err != nil {
+		t.Fatalf("SetOnboardingItem: %v", err)
+	}
+	if !repo.set["rules"] {
+		t.Error("tick not stored")
+	}
+	assertAppError(t, svc.SetOnboardingItem(ctx, "camp-1", "user-1", "char", true), 400)
+	assertAppError(t, svc.SetOnboardingItem(ctx, "camp-1", "user-1", "ack", true), 400)
+	assertAppError(t, svc.SetOnboardingItem(ctx, "camp-1", "user-1", "gone", true), 404)
+}
+
+func TestGetOnboardingStatus_Unconfigured(t *testing.T) {
+	svc := NewCampaignService(&mockCampaignRepo{}, &mockUserFinder{}, nil, nil, "")
+	status, err := svc.GetOnboardingStatus(context.Background(), &Campaign{ID: "camp-1", Settings: "{}"}, "user-1")
+	if err != nil || status != nil {
+		t.Errorf("got %+v, %v; want nil, nil", status, err)
+	}
+}
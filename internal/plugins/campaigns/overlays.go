@@ -0,0 +1,277 @@
+package campaigns
+
+// overlays.go — stream overlay widgets. Streamers add a widget to OBS (or
+// any external dashboard) as a browser source:
+//
+//	GET /overlays/:id/:widget?token=       transparent HTML page
+//	GET /overlays/:id/:widget/data?token=  the same data as JSON
+//
+// The campaign's overlay token is the only access control: no session, no
+// campaign middleware, and a wrong token, unknown widget or disabled widget
+// all look like a missing page. The owner turns widgets on and sets their
+// refresh intervals in settings; regenerating the token cuts off every URL
+// handed out so far. Widget data comes from other plugins through
+// OverlayDataFuncs wired in app, so this package never imports them.
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+)
+
+// Overlay widget keys.
+const (
+	OverlayDate    = "date"    // current in-game date and time
+	OverlaySession = "session" // countdown to the next planned session
+	OverlayRoster  = "roster"  // claimed player characters
+	OverlayRecap   = "recap"   // latest session recap
+)
+
+// OverlayWidgets lists the widgets in settings order, with their labels.
+var OverlayWidgets = []struct{ Key, Label string }{
+	{OverlayDate, "In-game date & time"},
+	{OverlaySession, "Next session countdown"},
+	{OverlayRoster, "Party roster"},
+	{OverlayRecap, "Latest recap"},
+}
+
+// Refresh interval bounds, in seconds.
+const (
+	overlayRefreshDefault = 60
+	overlayRefreshMin     = 5
+	overlayRefreshMax     = 3600
+)
+
+// OverlayWidgetSettings configures one widget.
+type OverlayWidgetSettings struct {
+	Enabled bool `json:"enabled"`
+	Refresh int  `json:"refresh"` // seconds between polls
+}
+
+// OverlaySettings holds a campaign's overlay token and widget config.
+type OverlaySettings struct {
+	// Token is set by the service on first enable and by regeneration;
+	// never taken from a request.
+	Token   string                           `json:"token,omitempty"`
+	Widgets map[string]OverlayWidgetSettings `json:"widgets,omitempty"`
+}
+
+// Normalize drops unknown widgets and clamps refresh intervals.
+func (s *OverlaySettings) Normalize() error {
+	widgets := make(map[string]OverlayWidgetSettings, len(s.Widgets))
+	for key, w := range s.Widgets {
+		if !isOverlayWidget(key) {
+			return apperror.NewBadRequest(fmt.Sprintf("unknown overlay widget %q", key))
+		}
+		switch {
+		case w.Refresh == 0:
+			w.Refresh = overlayRefreshDefault
+		case w.Refresh < overlayRefreshMin:
+			w.Refresh = overlayRefreshMin
+		case w.Refresh > overlayRefreshMax:
+			w.Refresh = overlayRefreshMax
+		}
+		widgets[key] = w
+	}
+	s.Widgets = widgets
+	return nil
+}
+
+// AnyEnabled reports whether at least one widget is on.
+func (s OverlaySettings) AnyEnabled() bool {
+	for _, w := range s.Widgets {
+		if w.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// Widget returns a widget's settings with the default refresh filled in.
+func (s OverlaySettings) Widget(key string) OverlayWidgetSettings {
+	w := s.Widgets[key]
+	if w.Refresh == 0 {
+		w.Refresh = overlayRefreshDefault
+	}
+	return w
+}
+
+// ValidToken reports whether token opens this campaign's overlays.
+func (s OverlaySettings) ValidToken(token string) bool {
+	return s.Token != "" && subtle.ConstantTimeCompare([]byte(s.Token), []byte(token)) == 1
+}
+
+// isOverlayWidget reports whether key is a known widget.
+func isOverlayWidget(key string) bool {
+	for _, w := range OverlayWidgets {
+		if w.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOverlays returns the campaign's overlay settings (all widgets off when
+// never configured).
+func (s CampaignSettings) GetOverlays() OverlaySettings {
+	if s.Overlays == nil {
+		return OverlaySettings{}
+	}
+	return *s.Overlays
+}
+
+// OverlayURL is the browser-source URL for a widget.
+func OverlayURL(campaignID, widget, token string) string {
+	return fmt.Sprintf("/overlays/%s/%s?token=%s", campaignID, widget, token)
+}
+
+// OverlayData is what a widget shows. Fields a widget doesn't use stay
+// empty. Start is the session widget's countdown target as a zone-less
+// wall-clock "2006-01-02T15:04", like session schedules themselves; the
+// overlay page counts down to it in the browser's own zone.
+type OverlayData struct {
+	Widget    string    `json:"widget"`
+	Title     string    `json:"title,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	Items     []string  `json:"items,omitempty"`
+	Start     string    `json:"start,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Refresh   int       `json:"refresh"`
+}
+
+// OverlayDataFunc loads one widget's data. Only public-safe data belongs
+// here: the overlay is shown on stream. Returning nil data means there is
+// nothing to show yet (no calendar, no session planned).
+type OverlayDataFunc func(ctx context.Context, campaignID string) (*OverlayData, error)
+
+// SetOverlaySource wires the data for one widget. Widgets without a source
+// are unavailable.
+func (h *Handler) SetOverlaySource(widget string, fn OverlayDataFunc) {
+	if h.overlaySources == nil {
+		h.overlaySources = make(map[string]OverlayDataFunc)
+	}
+	h.overlaySources[widget] = fn
+}
+
+// overlayData checks the token and loads the requested widget's data.
+func (h *Handler) overlayData(c echo.Context) (*OverlayData, error) {
+	notFound := apperror.NewNotFound("overlay not found")
+	widget := c.Param("widget")
+	fn, ok := h.overlaySources[widget]
+	if !ok {
+		return nil, notFound
+	}
+	ctx := c.Request().Context()
+	campaign, err := h.service.GetByID(ctx, c.Param("id"))
+	if err != nil || campaign.IsPendingDeletion() {
+		// A campaign in its deletion grace period is hidden from everyone
+		// but its owner, and a token holder is anyone.
+		return nil, notFound
+	}
+	settings := campaign.ParseSettings().GetOverlays()
+	w := settings.Widget(widget)
+	if !w.Enabled || !settings.ValidToken(c.QueryParam("token")) {
+		return nil, notFound
+	}
+
+	data, err := fn(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = &OverlayData{}
+	}
+	data.Widget = widget
+	data.Refresh = w.Refresh
+	data.UpdatedAt = time.Now().UTC()
+	return data, nil
+}
+
+// OverlayDataAPI returns a widget's data as JSON
+// (GET /overlays/:id/:widget/data?token=). Open to any origin so external
+// dashboards can poll it.
+func (h *Handler) OverlayDataAPI(c echo.Context) error {
+	data, err := h.overlayData(c)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+	return c.JSON(http.StatusOK, data)
+}
+
+// OverlayPage renders a widget as a standalone transparent page for a
+// browser source (GET /overlays/:id/:widget?token=). The page polls the
+// data endpoint at the widget's refresh interval.
+func (h *Handler) OverlayPage(c echo.Context) error {
+	data, err := h.overlayData(c)
+	if err != nil {
+		return err
+	}
+	middleware.AllowFraming(c)
+	c.Response().Header().Set("Cache-Control", "no-store")
+	dataURL := fmt.Sprintf("/overlays/%s/%s/data?token=%s", c.Param("id"), data.Widget, c.QueryParam("token"))
+	return middleware.Render(c, http.StatusOK, OverlayWidgetPage(data, dataURL))
+}
+
+// UpdateOverlaysAPI saves which widgets are on and their refresh intervals
+// (PUT /campaigns/:id/overlays). Returns the settings with the token so the
+// settings card can show the widget URLs.
+func (h *Handler) UpdateOverlaysAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change stream overlays")
+	}
+
+	var req OverlaySettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	saved, err := h.service.UpdateOverlays(c.Request().Context(), cc.Campaign.ID, req)
+	if err != nil {
+		return err
+	}
+
+	var enabled []string
+	for _, w := range OverlayWidgets {
+		if saved.Widget(w.Key).Enabled {
+			enabled = append(enabled, w.Key)
+		}
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.overlays.updated", map[string]any{
+		"widgets": strings.Join(enabled, ", "),
+	})
+	return c.JSON(http.StatusOK, saved)
+}
+
+// RegenerateOverlayTokenAPI replaces the overlay token, breaking every
+// overlay URL in use (POST /campaigns/:id/overlays/token).
+func (h *Handler) RegenerateOverlayTokenAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change stream overlays")
+	}
+
+	saved, err := h.service.RegenerateOverlayToken(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	h.logAudit(c, cc.Campaign.ID, "campaign.overlays.token_regenerated", nil)
+	return c.JSON(http.StatusOK, saved)
+}
@@ -0,0 +1,97 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestOverlaySettings_Normalize(t *testing.T) {
+	s := OverlaySettings{Widgets: map[string]OverlayWidgetSettings{
+		OverlayDate:    {Enabled: true},
+		OverlaySession: {Enabled: true, Refresh: 1},
+		OverlayRoster:  {Refresh: 99999},
+		OverlayRecap:   {Refresh: 30},
+	}}
+	if err := s.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]int{
+		OverlayDate:    overlayRefreshDefault,
+		OverlaySession: overlayRefreshMin,
+		OverlayRoster:  overlayRefreshMax,
+		OverlayRecap:   30,
+	} {
+		if got := s.Widgets[key].Refresh; got != want {
+			t.Errorf("%s refresh = %d, want %d", key, got, want)
+		}
+	}
+
+	bad := OverlaySettings{Widgets: map[string]OverlayWidgetSettings{"weather": {Enabled: true}}}
+	assertAppError(t, bad.Normalize(), 400)
+}
+
+func TestOverlaySettings_ValidToken(t *testing.T) {
+	if (OverlaySettings{}).ValidToken("") {
+		t.Error("empty token must never validate")
+	}
+	s := OverlaySettings{Token: "abc123"}
+	if !s.ValidToken("abc123") || s.ValidToken("abc124") || s.ValidToken("") {
+		t.Error("token comparison wrong")
+	}
+}
+
+func TestOverlaySettings_Widget(t *testing.T) {
+	s := OverlaySettings{Widgets: map[string]OverlayWidgetSettings{OverlayRecap: {Enabled: true, Refresh: 120}}}
+	if w := s.Widget(OverlayRecap); !w.Enabled || w.Refresh != 120 {
+		t.Errorf("recap = %+v", w)
+	}
+	if w := s.Widget(OverlayDate); w.Enabled || w.Refresh != overlayRefreshDefault {
+		t.Errorf("unset widget = %+v", w)
+	}
+	if !s.AnyEnabled() || (OverlaySettings{}).AnyEnabled() {
+		t.Error("AnyEnabled wrong")
+	}
+}
+
+// overlayCampaignService serves one campaign to the overlay handler.
+type overlayCampaignService struct {
+	CampaignService
+	campaign *Campaign
+}
+
+func (s *overlayCampaignService) GetByID(context.Context, string) (*Campaign, error) {
+	return s.campaign, nil
+}
+
+func TestOverlayDataAPI_HiddenWhilePendingDeletion(t *testing.T) {
+	settings, _ := json.Marshal(CampaignSettings{Overlays: &OverlaySettings{
+		Token:   "tok",
+		Widgets: map[string]OverlayWidgetSettings{OverlayDate: {Enabled: true, Refresh: 60}},
+	}})
+	campaign := &Campaign{ID: "camp-1", Settings: string(settings)}
+	h := &Handler{service: &overlayCampaignService{campaign: campaign}}
+	h.SetOverlaySource(OverlayDate, func(context.Context, string) (*OverlayData, error) {
+		return &OverlayData{}, nil
+	})
+
+	call := func() error {
+		e := echo.New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/overlays/camp-1/date/data?token=tok", nil), httptest.NewRecorder())
+		c.SetParamNames("id", "widget")
+		c.SetParamValues("camp-1", OverlayDate)
+		return h.OverlayDataAPI(c)
+	}
+	if err := call(); err != nil {
+		t.Fatalf("active campaign: %v", err)
+	}
+
+	marked := time.Now()
+	campaign.DeletionRequestedAt = &marked
+	assertAppError(t, call(), http.StatusNotFound)
+}
@@ -3,8 +3,10 @@ package campaigns
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 )
@@ -29,8 +31,15 @@ type CampaignRepository interface {
 	RemoveMember(ctx context.Context, campaignID, userID string) error
 	FindMember(ctx context.Context, campaignID, userID string) (*CampaignMember, error)
 	ListMembers(ctx context.Context, campaignID string) ([]CampaignMember, error)
+	ListUserMemberships(ctx context.Context, userID string) ([]UserMembership, error)
 	UpdateMemberRole(ctx context.Context, campaignID, userID string, role Role) error
 	UpdateMemberCharacter(ctx context.Context, campaignID, userID string, characterEntityID *string) error
+
+	// Onboarding progress
+	ListOnboardingProgress(ctx context.Context, campaignID, userID string) ([]string, error)
+	SetOnboardingProgress(ctx context.Context, campaignID, userID, itemID string, done bool) error
+	HasCharacter(ctx context.Context, campaignID, userID string) (bool, error)
+	HasAcknowledgedHouseRules(ctx context.Context, campaignID, userID string) (bool, error)
 	FindOwnerMember(ctx context.Context, campaignID string) (*CampaignMember, error)
 
 	// Ownership transfer
@@ -45,6 +54,10 @@ type CampaignRepository interface {
 	// UpdateSettings updates only the settings JSON column.
 	UpdateSettings(ctx context.Context, campaignID, settingsJSON string) error
 
+	// ListRetentionSettings returns the retention windows of every campaign
+	// that has any configured, keyed by campaign ID (the purge job's input).
+	ListRetentionSettings(ctx context.Context) (map[string]RetentionSettings, error)
+
 	// UpdateSidebarConfig updates only the sidebar_config JSON column.
 	UpdateSidebarConfig(ctx context.Context, campaignID, configJSON string) error
 
@@ -70,6 +83,20 @@ type CampaignRepository interface {
 	// UnarchiveCampaign clears the archived_at timestamp, restoring write access.
 	UnarchiveCampaign(ctx context.Context, campaignID string) error
 
+	// MarkForDeletion starts a campaign's deletion grace period.
+	MarkForDeletion(ctx context.Context, campaignID string, purgeAfter time.Time) error
+
+	// CancelDeletion ends a campaign's deletion grace period.
+	CancelDeletion(ctx context.Context, campaignID string) error
+
+	// ListDueForPurge returns IDs of marked campaigns whose grace period
+	// ended before the given time.
+	ListDueForPurge(ctx context.Context, before time.Time, limit int) ([]string, error)
+
+	// ListPendingDeletionByOwner returns the user's own campaigns that are
+	// marked for deletion, soonest purge first.
+	ListPendingDeletionByOwner(ctx context.Context, userID string) ([]Campaign, error)
+
 	// SetJoinCode stores a shareable invite code for the campaign.
 	SetJoinCode(ctx context.Context, campaignID, code string) error
 
@@ -110,7 +137,7 @@ func (r *campaignRepository) Create(ctx context.Context, campaign *Campaign) err
 
 // FindByID retrieves a campaign by its UUID.
 func (r *campaignRepository) FindByID(ctx context.Context, id string) (*Campaign, error) {
-	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code
+	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code, deletion_requested_at, purge_after
 	          FROM campaigns WHERE id = ?`
 
 	c := &Campaign{}
@@ -118,6 +145,7 @@ func (r *campaignRepository) FindByID(ctx context.Context, id string) (*Campaign
 		&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 		&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+		&c.DeletionRequestedAt, &c.PurgeAfter,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, apperror.NewNotFound("campaign not found")
@@ -130,7 +158,7 @@ func (r *campaignRepository) FindByID(ctx context.Context, id string) (*Campaign
 
 // FindBySlug retrieves a campaign by its URL slug.
 func (r *campaignRepository) FindBySlug(ctx context.Context, slug string) (*Campaign, error) {
-	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code
+	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code, deletion_requested_at, purge_after
 	          FROM campaigns WHERE slug = ?`
 
 	c := &Campaign{}
@@ -138,6 +166,7 @@ func (r *campaignRepository) FindBySlug(ctx context.Context, slug string) (*Camp
 		&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 		&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+		&c.DeletionRequestedAt, &c.PurgeAfter,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, apperror.NewNotFound("campaign not found")
@@ -154,7 +183,7 @@ func (r *campaignRepository) ListByUser(ctx context.Context, userID string, opts
 	// Count total for pagination.
 	countQuery := `SELECT COUNT(*) FROM campaigns c
 	               INNER JOIN campaign_members cm ON cm.campaign_id = c.id
-	               WHERE cm.user_id = ? AND c.archived_at IS NULL`
+	               WHERE cm.user_id = ? AND c.archived_at IS NULL AND c.deletion_requested_at IS NULL`
 	var total int
 	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("counting user campaigns: %w", err)
@@ -163,10 +192,10 @@ func (r *campaignRepository) ListByUser(ctx context.Context, userID string, opts
 	query := `SELECT c.id, c.name, c.slug, c.description, c.is_public,
 	                 c.settings, c.backdrop_path, c.sidebar_config, c.dashboard_layout,
 	                 c.owner_dashboard_layout, c.created_by, c.created_at, c.updated_at,
-	                 c.archived_at, c.join_code
+	                 c.archived_at, c.join_code, c.deletion_requested_at, c.purge_after
 	          FROM campaigns c
 	          INNER JOIN campaign_members cm ON cm.campaign_id = c.id
-	          WHERE cm.user_id = ? AND c.archived_at IS NULL
+	          WHERE cm.user_id = ? AND c.archived_at IS NULL AND c.deletion_requested_at IS NULL
 	          ORDER BY c.updated_at DESC
 	          LIMIT ? OFFSET ?`
 
@@ -183,6 +212,7 @@ func (r *campaignRepository) ListByUser(ctx context.Context, userID string, opts
 			&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 			&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 			&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+			&c.DeletionRequestedAt, &c.PurgeAfter,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scanning campaign row: %w", err)
 		}
@@ -199,7 +229,7 @@ func (r *campaignRepository) ListAll(ctx context.Context, opts ListOptions) ([]C
 		return nil, 0, fmt.Errorf("counting all campaigns: %w", err)
 	}
 
-	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code
+	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code, deletion_requested_at, purge_after
 	          FROM campaigns ORDER BY updated_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := r.db.QueryContext(ctx, query, opts.PerPage, opts.Offset())
@@ -215,6 +245,7 @@ func (r *campaignRepository) ListAll(ctx context.Context, opts ListOptions) ([]C
 			&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 			&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 			&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+			&c.DeletionRequestedAt, &c.PurgeAfter,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scanning campaign row: %w", err)
 		}
@@ -226,8 +257,8 @@ func (r *campaignRepository) ListAll(ctx context.Context, opts ListOptions) ([]C
 // ListPublic returns public campaigns ordered by most recently updated.
 // Used for the public landing page to showcase discoverable campaigns.
 func (r *campaignRepository) ListPublic(ctx context.Context, limit int) ([]Campaign, error) {
-	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code
-	          FROM campaigns WHERE is_public = 1 AND archived_at IS NULL
+	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code, deletion_requested_at, purge_after
+	          FROM campaigns WHERE is_public = 1 AND archived_at IS NULL AND deletion_requested_at IS NULL
 	          ORDER BY updated_at DESC LIMIT ?`
 
 	rows, err := r.db.QueryContext(ctx, query, limit)
@@ -243,6 +274,7 @@ func (r *campaignRepository) ListPublic(ctx context.Context, limit int) ([]Campa
 			&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 			&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 			&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+			&c.DeletionRequestedAt, &c.PurgeAfter,
 		); err != nil {
 			return nil, fmt.Errorf("scanning public campaign row: %w", err)
 		}
@@ -324,6 +356,35 @@ func (r *campaignRepository) UpdateBackdropPath(ctx context.Context, campaignID
 	return nil
 }
 
+// ListRetentionSettings reads settings.retention for campaigns that set it.
+// Archived campaigns are included: their logs still occupy space.
+func (r *campaignRepository) ListRetentionSettings(ctx context.Context) (map[string]RetentionSettings, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, JSON_EXTRACT(settings, '$.retention') FROM campaigns
+		 WHERE JSON_EXTRACT(settings, '$.retention') IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("listing retention settings: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]RetentionSettings)
+	for rows.Next() {
+		var id string
+		var raw []byte
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, fmt.Errorf("scanning retention settings: %w", err)
+		}
+		var rs RetentionSettings
+		if err := json.Unmarshal(raw, &rs); err != nil {
+			continue // Malformed JSON: skip rather than purge on a guess.
+		}
+		if !rs.IsZero() {
+			out[id] = rs
+		}
+	}
+	return out, rows.Err()
+}
+
 // UpdateSettings updates only the settings JSON for a campaign.
 func (r *campaignRepository) UpdateSettings(ctx context.Context, campaignID, settingsJSON string) error {
 	result, err := r.db.ExecContext(ctx,
@@ -424,6 +485,100 @@ func (r *campaignRepository) UnarchiveCampaign(ctx context.Context, campaignID s
 	return nil
 }
 
+// --- Deletion grace period ---
+
+// MarkForDeletion records the deletion request and when the campaign may be
+// purged. A campaign already marked keeps its original deadline.
+func (r *campaignRepository) MarkForDeletion(ctx context.Context, campaignID string, purgeAfter time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE campaigns SET deletion_requested_at = NOW(), purge_after = ?, updated_at = NOW()
+		 WHERE id = ? AND deletion_requested_at IS NULL`,
+		purgeAfter, campaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("marking campaign for deletion: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return apperror.NewNotFound("campaign not found or already scheduled for deletion")
+	}
+	return nil
+}
+
+// CancelDeletion clears the deletion request.
+func (r *campaignRepository) CancelDeletion(ctx context.Context, campaignID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE campaigns SET deletion_requested_at = NULL, purge_after = NULL, updated_at = NOW()
+		 WHERE id = ? AND deletion_requested_at IS NOT NULL`,
+		campaignID,
+	)
+	if err != nil {
+		return fmt.Errorf("cancelling campaign deletion: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return apperror.NewNotFound("campaign not found or not scheduled for deletion")
+	}
+	return nil
+}
+
+// ListDueForPurge returns marked campaigns whose purge_after has passed.
+func (r *campaignRepository) ListDueForPurge(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id FROM campaigns
+		 WHERE deletion_requested_at IS NOT NULL AND purge_after <= ?
+		 ORDER BY purge_after LIMIT ?`,
+		before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing campaigns due for purge: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning campaign id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListPendingDeletionByOwner returns marked campaigns the user owns.
+func (r *campaignRepository) ListPendingDeletionByOwner(ctx context.Context, userID string) ([]Campaign, error) {
+	query := `SELECT c.id, c.name, c.slug, c.description, c.is_public,
+	                 c.settings, c.backdrop_path, c.sidebar_config, c.dashboard_layout,
+	                 c.owner_dashboard_layout, c.created_by, c.created_at, c.updated_at,
+	                 c.archived_at, c.join_code, c.deletion_requested_at, c.purge_after
+	          FROM campaigns c
+	          INNER JOIN campaign_members cm ON cm.campaign_id = c.id
+	          WHERE cm.user_id = ? AND cm.role = 'owner' AND c.deletion_requested_at IS NOT NULL
+	          ORDER BY c.purge_after`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing campaigns pending deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []Campaign
+	for rows.Next() {
+		var c Campaign
+		if err := rows.Scan(
+			&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
+			&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
+			&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+			&c.DeletionRequestedAt, &c.PurgeAfter,
+		); err != nil {
+			return nil, fmt.Errorf("scanning campaign row: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
 // SetJoinCode stores a shareable invite code for the campaign.
 func (r *campaignRepository) SetJoinCode(ctx context.Context, campaignID, code string) error {
 	result, err := r.db.ExecContext(ctx,
@@ -458,7 +613,7 @@ func (r *campaignRepository) ClearJoinCode(ctx context.Context, campaignID strin
 
 // FindByJoinCode looks up a campaign by its shareable invite code.
 func (r *campaignRepository) FindByJoinCode(ctx context.Context, code string) (*Campaign, error) {
-	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code
+	query := `SELECT id, name, slug, description, is_public, settings, backdrop_path, sidebar_config, dashboard_layout, owner_dashboard_layout, created_by, created_at, updated_at, archived_at, join_code, deletion_requested_at, purge_after
 	          FROM campaigns WHERE join_code = ?`
 
 	c := &Campaign{}
@@ -466,6 +621,7 @@ func (r *campaignRepository) FindByJoinCode(ctx context.Context, code string) (*
 		&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 		&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+		&c.DeletionRequestedAt, &c.PurgeAfter,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, apperror.NewNotFound("invalid or expired invite code")
@@ -567,6 +723,33 @@ func (r *campaignRepository) ListMembers(ctx context.Context, campaignID string)
 	return members, rows.Err()
 }
 
+// ListUserMemberships returns every campaign a user belongs to, archived
+// ones included, most recently joined first.
+func (r *campaignRepository) ListUserMemberships(ctx context.Context, userID string) ([]UserMembership, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.id, c.name, c.slug, cm.role, cm.joined_at, c.archived_at IS NOT NULL
+		 FROM campaign_members cm
+		 INNER JOIN campaigns c ON c.id = cm.campaign_id
+		 WHERE cm.user_id = ?
+		 ORDER BY cm.joined_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing user memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var out []UserMembership
+	for rows.Next() {
+		var m UserMembership
+		var roleStr string
+		if err := rows.Scan(&m.CampaignID, &m.CampaignName, &m.CampaignSlug, &roleStr, &m.JoinedAt, &m.Archived); err != nil {
+			return nil, fmt.Errorf("scanning user membership: %w", err)
+		}
+		m.Role = RoleFromString(roleStr)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
 // UpdateMemberRole changes a member's role within a campaign.
 func (r *campaignRepository) UpdateMemberRole(ctx context.Context, campaignID, userID string, role Role) error {
 	result, err := r.db.ExecContext(ctx,
@@ -600,6 +783,73 @@ func (r *campaignRepository) UpdateMemberCharacter(ctx context.Context, campaign
 	return nil
 }
 
+// ListOnboardingProgress returns the checklist item IDs the member has ticked.
+func (r *campaignRepository) ListOnboardingProgress(ctx context.Context, campaignID, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT item_id FROM campaign_onboarding_progress WHERE campaign_id = ? AND user_id = ?`,
+		campaignID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing onboarding progress: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning onboarding progress: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// HasCharacter reports whether the member has a character: one the owner
+// linked to their membership, or an entity they claimed.
+func (r *campaignRepository) HasCharacter(ctx context.Context, campaignID, userID string) (bool, error) {
+	var has bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM campaign_members
+		                WHERE campaign_id = ? AND user_id = ? AND character_entity_id IS NOT NULL)
+		     OR EXISTS(SELECT 1 FROM entities WHERE campaign_id = ? AND owner_user_id = ?)`,
+		campaignID, userID, campaignID, userID,
+	).Scan(&has)
+	if err != nil {
+		return false, fmt.Errorf("checking member character: %w", err)
+	}
+	return has, nil
+}
+
+// HasAcknowledgedHouseRules reports whether the member's acknowledgement
+// still covers the campaign's house rules (see HouseRules.Acknowledged).
+func (r *campaignRepository) HasAcknowledgedHouseRules(ctx context.Context, campaignID, userID string) (bool, error) {
+	var has bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM campaign_house_rules_acks a
+		               JOIN campaign_house_rules r ON r.campaign_id = a.campaign_id
+		               WHERE a.campaign_id = ? AND a.user_id = ? AND a.version >= GREATEST(r.ack_version, 1))`,
+		campaignID, userID,
+	).Scan(&has)
+	if err != nil {
+		return false, fmt.Errorf("checking house rules acknowledgement: %w", err)
+	}
+	return has, nil
+}
+
+// SetOnboardingProgress ticks (done) or unticks a checklist item for a
+// member. Ticking twice keeps the first completion time.
+func (r *campaignRepository) SetOnboardingProgress(ctx context.Context, campaignID, userID, itemID string, done bool) error {
+	query := `DELETE FROM campaign_onboarding_progress WHERE campaign_id = ? AND user_id = ? AND item_id = ?`
+	if done {
+		query = `INSERT IGNORE INTO campaign_onboarding_progress (campaign_id, user_id, item_id) VALUES (?, ?, ?)`
+	}
+	if _, err := r.db.ExecContext(ctx, query, campaignID, userID, itemID); err != nil {
+		return fmt.Errorf("updating onboarding progress: %w", err)
+	}
+	return nil
+}
+
 // FindOwnerMember returns the member with role='owner' for a campaign.
 func (r *campaignRepository) FindOwnerMember(ctx context.Context, campaignID string) (*CampaignMember, error) {
 	query := `SELECT cm.campaign_id, cm.user_id, cm.role, cm.character_entity_id, cm.joined_at,
@@ -821,6 +1071,8 @@ type GroupRepository interface {
 	RemoveGroupMember(ctx context.Context, groupID int, userID string) error
 	// ListGroupMembers returns all members of a group with display info.
 	ListGroupMembers(ctx context.Context, groupID int) ([]GroupMemberInfo, error)
+	// ListUserGroupIDs returns the IDs of the campaign's groups a user is in.
+	ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error)
 }
 
 // groupRepository implements GroupRepository with MariaDB queries.
@@ -964,3 +1216,28 @@ func (r *groupRepository) ListGroupMembers(ctx context.Context, groupID int) ([]
 	}
 	return members, rows.Err()
 }
+
+// ListUserGroupIDs returns the IDs of the campaign's groups a user is in.
+func (r *groupRepository) ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT cg.id
+		 FROM campaign_groups cg
+		 JOIN campaign_group_members cgm ON cgm.group_id = cg.id
+		 WHERE cg.campaign_id = ? AND cgm.user_id = ?`,
+		campaignID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing user groups: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning user group: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,178 @@
+package campaigns
+
+// retention.go — per-campaign data retention. Owners set how long audit
+// entries, notifications and API request logs are kept (CampaignSettings
+// .Retention); a daily job deletes older rows so long-running self-hosted
+// instances don't grow without bound. The tables belong to other plugins
+// (audit, sessions, syncapi), so the job takes their purge functions as
+// RetentionTargets wired in internal/app rather than importing them.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// retentionMaxDays caps every window at ten years.
+	retentionMaxDays = 3650
+
+	// retentionMinAuditDays keeps the audit trail long enough to review a
+	// dispute; a shorter window would let an owner erase recent history.
+	retentionMinAuditDays = 30
+
+	// retentionMinDays is the floor for the other windows.
+	retentionMinDays = 7
+
+	// retentionBatchSize bounds each DELETE so a first purge of a large
+	// backlog doesn't hold long row locks.
+	retentionBatchSize = 5000
+
+	// retentionInterval is how often the purge job runs.
+	retentionInterval = 24 * time.Hour
+
+	// retentionStartDelay keeps the first run off the boot path.
+	retentionStartDelay = 10 * time.Minute
+)
+
+// RetentionSettings holds a campaign's retention windows in days. Zero keeps
+// rows forever (the default).
+type RetentionSettings struct {
+	AuditDays        int `json:"audit_days,omitempty"`
+	NotificationDays int `json:"notification_days,omitempty"`
+	RequestLogDays   int `json:"request_log_days,omitempty"`
+}
+
+// IsZero reports whether every window is "keep forever".
+func (r RetentionSettings) IsZero() bool {
+	return r.AuditDays == 0 && r.NotificationDays == 0 && r.RequestLogDays == 0
+}
+
+// Validate checks each window is 0 or within its bounds.
+func (r RetentionSettings) Validate() error {
+	check := func(label string, days, minDays int) error {
+		if days == 0 {
+			return nil
+		}
+		if days < minDays || days > retentionMaxDays {
+			return apperror.NewBadRequest(fmt.Sprintf("%s retention must be 0 (keep forever) or between %d and %d days", label, minDays, retentionMaxDays))
+		}
+		return nil
+	}
+	if err := check("audit log", r.AuditDays, retentionMinAuditDays); err != nil {
+		return err
+	}
+	if err := check("notification", r.NotificationDays, retentionMinDays); err != nil {
+		return err
+	}
+	return check("request log", r.RequestLogDays, retentionMinDays)
+}
+
+// GetRetention returns the campaign's retention windows (zero value when
+// never configured).
+func (s CampaignSettings) GetRetention() RetentionSettings {
+	if s.Retention == nil {
+		return RetentionSettings{}
+	}
+	return *s.Retention
+}
+
+// RetentionPurgeFunc deletes up to limit rows of one kind older than before
+// for a campaign and returns how many it deleted.
+type RetentionPurgeFunc func(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error)
+
+// RetentionTarget is one purgeable row kind.
+type RetentionTarget struct {
+	Name  string                      // For logs, e.g. "audit_log".
+	Days  func(RetentionSettings) int // Which window applies.
+	Purge RetentionPurgeFunc
+}
+
+// RetentionSource lists every campaign with retention configured.
+// Implemented by CampaignRepository.
+type RetentionSource interface {
+	ListRetentionSettings(ctx context.Context) (map[string]RetentionSettings, error)
+}
+
+// RetentionJob purges expired rows across all configured campaigns.
+type RetentionJob struct {
+	source  RetentionSource
+	targets []RetentionTarget
+	now     func() time.Time
+}
+
+// NewRetentionJob creates the purge job.
+func NewRetentionJob(source RetentionSource, targets ...RetentionTarget) *RetentionJob {
+	return &RetentionJob{source: source, targets: targets, now: time.Now}
+}
+
+// Run performs one purge pass and returns the rows deleted per target. A
+// failing target is logged and skipped so one degraded plugin's table
+// doesn't stop the others from being purged.
+func (j *RetentionJob) Run(ctx context.Context) (map[string]int64, error) {
+	configs, err := j.source.ListRetentionSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing retention settings: %w", err)
+	}
+
+	deleted := make(map[string]int64, len(j.targets))
+	for campaignID, cfg := range configs {
+		for _, t := range j.targets {
+			days := t.Days(cfg)
+			if days <= 0 {
+				continue
+			}
+			before := j.now().UTC().AddDate(0, 0, -days)
+			n, err := j.purgeAll(ctx, t, campaignID, before)
+			deleted[t.Name] += n
+			if err != nil {
+				slog.Warn("retention: purge failed",
+					slog.String("target", t.Name), slog.String("campaign_id", campaignID), slog.Any("error", err))
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// purgeAll repeats batched deletes until a batch comes back short.
+func (j *RetentionJob) purgeAll(ctx context.Context, t RetentionTarget, campaignID string, before time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := t.Purge(ctx, campaignID, before, retentionBatchSize)
+		total += n
+		if err != nil || n < retentionBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job daily until ctx is cancelled, first after a short
+// delay so boot isn't slowed.
+func (j *RetentionJob) Start(ctx context.Context) {
+	timer := time.NewTimer(retentionStartDelay)
+	defer timer.Stop()
+
+	slog.Info("retention purge worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("retention purge worker stopped")
+			return
+		case <-timer.C:
+			deleted, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("retention purge run failed", slog.Any("error", err))
+			} else {
+				for name, n := range deleted {
+					if n > 0 {
+						slog.Info("retention purge", slog.String("target", name), slog.Int64("rows", n))
+					}
+				}
+			}
+			timer.Reset(retentionInterval)
+		}
+	}
+}
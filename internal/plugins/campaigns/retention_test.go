@@ -0,0 +1,142 @@
+package campaigns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetentionSettings_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      RetentionSettings
+		wantErr bool
+	}{
+		{name: "all zero keeps forever", in: RetentionSettings{}},
+		{name: "minimums", in: RetentionSettings{AuditDays: 30, NotificationDays: 7, RequestLogDays: 7}},
+		{name: "maximum", in: RetentionSettings{AuditDays: 3650}},
+		{name: "audit below floor", in: RetentionSettings{AuditDays: 29}, wantErr: true},
+		{name: "notifications below floor", in: RetentionSettings{NotificationDays: 6}, wantErr: true},
+		{name: "request log above cap", in: RetentionSettings{RequestLogDays: 3651}, wantErr: true},
+		{name: "negative", in: RetentionSettings{NotificationDays: -1}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.in.Validate()
+			if tc.wantErr {
+				assertAppError(t, err, 400)
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateRetention_WritesAndClears(t *testing.T) {
+	var captured string
+	repo := &mockCampaignRepo{
+		findByIDFn: func(_ context.Context, _ string) (*Campaign, error) {
+			return &Campaign{ID: "camp-1", Settings: `{"welcome_message":"hi","retention":{"audit_days":90}}`}, nil
+		},
+		updateSettingsFn: func(_ context.Context, _, settingsJSON string) error {
+			captured = settingsJSON
+			return nil
+		},
+	}
+	svc := newTestCampaignService(repo, &mockUserFinder{})
+
+	if err := svc.UpdateRetention(context.Background(), "camp-1", RetentionSettings{AuditDays: 60, RequestLogDays: 14}); err != nil {
+		t.Fatalf("UpdateRetention: %v", err)
+	}
+	var got CampaignSettings
+	if err := json.Unmarshal([]byte(captured), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.GetRetention() != (RetentionSettings{AuditDays: 60, RequestLogDays: 14}) {
+		t.Errorf("retention = %+v", got.GetRetention())
+	}
+	if got.WelcomeMessage != "hi" {
+		t.Error("other settings lost on round-trip")
+	}
+
+	// All zero drops the key rather than storing an empty object.
+	if err := svc.UpdateRetention(context.Background(), "camp-1", RetentionSettings{}); err != nil {
+		t.Fatalf("UpdateRetention clear: %v", err)
+	}
+	got = CampaignSettings{}
+	_ = json.Unmarshal([]byte(captured), &got)
+	if got.Retention != nil {
+		t.Errorf("retention = %+v, want nil after clearing", got.Retention)
+	}
+
+	// Invalid input never reaches the repo.
+	captured = ""
+	err := svc.UpdateRetention(context.Background(), "camp-1", RetentionSettings{AuditDays: 1})
+	assertAppError(t, err, 400)
+	if captured != "" {
+		t.Error("invalid retention was written")
+	}
+}
+
+// fakeRetentionSource serves fixed per-campaign settings.
+type fakeRetentionSource map[string]RetentionSettings
+
+func (f fakeRetentionSource) ListRetentionSettings(context.Context) (map[string]RetentionSettings, error) {
+	return f, nil
+}
+
+func TestRetentionJob_Run(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	source := fakeRetentionSource{
+		"camp-1": {AuditDays: 30},
+		"camp-2": {NotificationDays: 7},
+	}
+
+	// The audit table has a backlog larger than one batch for camp-1.
+	auditBacklog := int64(retentionBatchSize + 10)
+	var auditCutoff time.Time
+	var auditCalls int
+	audit := RetentionTarget{
+		Name: "audit_log",
+		Days: func(r RetentionSettings) int { return r.AuditDays },
+		Purge: func(_ context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+			if campaignID != "camp-1" {
+				t.Errorf("audit purge for %s, which has no audit window", campaignID)
+			}
+			auditCalls++
+			auditCutoff = before
+			n := min(auditBacklog, int64(limit))
+			auditBacklog -= n
+			return n, nil
+		},
+	}
+	notifications := RetentionTarget{
+		Name: "notifications",
+		Days: func(r RetentionSettings) int { return r.NotificationDays },
+		Purge: func(context.Context, string, time.Time, int) (int64, error) {
+			return 0, errors.New("table missing")
+		},
+	}
+
+	job := NewRetentionJob(source, audit, notifications)
+	job.now = func() time.Time { return now }
+
+	deleted, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if deleted["audit_log"] != int64(retentionBatchSize+10) {
+		t.Errorf("audit deleted = %d, want the whole backlog", deleted["audit_log"])
+	}
+	if auditCalls != 2 {
+		t.Errorf("audit purge calls = %d, want 2 batches", auditCalls)
+	}
+	if want := now.AddDate(0, 0, -30); !auditCutoff.Equal(want) {
+		t.Errorf("audit cutoff = %v, want %v", auditCutoff, want)
+	}
+	if deleted["notifications"] != 0 {
+		t.Errorf("notifications deleted = %d, want 0 from a failing target", deleted["notifications"])
+	}
+}
@@ -20,8 +20,11 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 	authed.GET("/campaigns/new", h.NewForm)
 	authed.POST("/campaigns", h.Create)
 
-	// Accept transfer requires auth but not campaign membership (uses token).
+	// Transfer offer page, accept and decline require auth but not campaign
+	// membership: the service checks the transfer is addressed to the user.
 	authed.GET("/campaigns/:id/accept-transfer", h.AcceptTransfer)
+	authed.POST("/campaigns/:id/accept-transfer", h.ConfirmTransfer)
+	authed.POST("/campaigns/:id/decline-transfer", h.DeclineTransfer)
 
 	// Join via shareable invite code (auth required, no campaign membership needed).
 	authed.GET("/join/:code", h.JoinByCode)
@@ -33,6 +36,8 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 		AllowPublicCampaignAccess(svc),
 	)
 	pub.GET("", h.Show, RequireViewAccess())
+	// Lazy dashboard block bodies (dashboard_fragments.go).
+	pub.GET("/dashboard/blocks/:bid", h.DashboardBlockFragment, RequireViewAccess())
 	// Sidebar drill-down for public visitors (clicking categories in sidebar).
 	pub.GET("/sidebar/drill/:slug", h.SidebarDrill, RequireViewAccess())
 
@@ -46,6 +51,17 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 	cg.GET("/members", h.Members, RequireRole(RolePlayer))
 	cg.GET("/plugins", h.PluginHub, RequireRole(RolePlayer))
 	cg.GET("/plugins/fragment", h.PluginHubFragment, RequireRole(RolePlayer))
+	cg.GET("/welcome", h.Welcome, RequireRole(RolePlayer))
+	cg.POST("/welcome/items/:item", h.SetOnboardingItem, RequireRole(RolePlayer))
+	cg.GET("/announcements", h.Announcements, RequireRole(RolePlayer))
+	cg.GET("/announcements/fragment", h.AnnouncementsFragment, RequireRole(RolePlayer))
+	cg.GET("/announcements/:aid", h.ShowAnnouncement, RequireRole(RolePlayer))
+	cg.POST("/announcements/:aid/read", h.MarkAnnouncementReadAPI, RequireRole(RolePlayer))
+	cg.POST("/digest", h.UpdateDigestPreferenceAPI, RequireRole(RolePlayer))
+	cg.GET("/house-rules", h.HouseRules, RequireRole(RolePlayer))
+	cg.GET("/house-rules/fragment", h.HouseRulesFragment, RequireRole(RolePlayer))
+	cg.GET("/house-rules/revisions/:version", h.ShowHouseRulesRevision, RequireRole(RolePlayer))
+	cg.POST("/house-rules/acknowledge", h.AcknowledgeHouseRulesAPI, RequireRole(RolePlayer))
 	// /foundry-presence relocated to foundry_vtt's RegisterOwnerRoutes
 	// in NW-2.3 (PR pending). URL preserved.
 
@@ -85,12 +101,20 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 	cg.GET("/dashboard-layout", h.GetDashboardLayout, RequireRole(RoleOwner))
 	cg.PUT("/dashboard-layout", h.UpdateDashboardLayout, RequireRole(RoleOwner))
 	cg.DELETE("/dashboard-layout", h.ResetDashboardLayout, RequireRole(RoleOwner))
+	cg.GET("/dashboard-layout/versions", h.ListDashboardLayoutVersions, RequireRole(RoleOwner))
+	cg.POST("/dashboard-layout/versions/:vid/restore", h.RestoreDashboardLayoutVersion, RequireRole(RoleOwner))
+	cg.GET("/dashboard-layout/export", h.ExportDashboardLayout, RequireRole(RoleOwner))
+	cg.POST("/dashboard-layout/import", h.ImportDashboardLayout, RequireRole(RoleOwner))
+	cg.GET("/dashboard-layout/sources", h.DashboardLayoutSources, RequireRole(RoleOwner))
 
 	// Owner dashboard (Owner + Co-DM).
 	cg.GET("/dashboard", h.OwnerDashboard, RequireRole(RoleOwner))
 	cg.GET("/owner-dashboard-layout", h.GetOwnerDashboardLayout, RequireRole(RoleOwner))
 	cg.PUT("/owner-dashboard-layout", h.UpdateOwnerDashboardLayout, RequireRole(RoleOwner))
 	cg.DELETE("/owner-dashboard-layout", h.ResetOwnerDashboardLayout, RequireRole(RoleOwner))
+	cg.GET("/owner-dashboard-layout/versions", h.ListOwnerDashboardLayoutVersions, RequireRole(RoleOwner))
+	cg.POST("/owner-dashboard-layout/versions/:vid/restore", h.RestoreOwnerDashboardLayoutVersion, RequireRole(RoleOwner))
+	cg.GET("/owner-dashboard-layout/export", h.ExportOwnerDashboardLayout, RequireRole(RoleOwner))
 
 	// Backdrop and branding (Owner only).
 	cg.POST("/backdrop", h.UploadBackdrop, RequireRole(RoleOwner))
@@ -104,6 +128,25 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 	cg.PUT("/font-family", h.UpdateFontFamilyAPI, RequireRole(RoleOwner))
 	cg.PUT("/welcome-message", h.UpdateWelcomeMessageAPI, RequireRole(RoleOwner))
 	cg.PUT("/default-visibility", h.UpdateDefaultVisibilityAPI, RequireRole(RoleOwner))
+	cg.PUT("/retention", h.UpdateRetentionAPI, RequireRole(RoleOwner))
+	cg.PUT("/image-proxy", h.UpdateImageProxyAPI, RequireRole(RoleOwner))
+	cg.PUT("/license", h.UpdateLicenseAPI, RequireRole(RoleOwner))
+	cg.GET("/directory", h.GetDirectoryListingAPI, RequireRole(RoleOwner))
+	cg.PUT("/directory", h.UpdateDirectoryListingAPI, RequireRole(RoleOwner))
+	cg.PUT("/overlays", h.UpdateOverlaysAPI, RequireRole(RoleOwner))
+	cg.POST("/overlays/token", h.RegenerateOverlayTokenAPI, RequireRole(RoleOwner))
+	cg.PUT("/stream-mode", h.UpdateStreamModeAPI, RequireRole(RoleOwner))
+	// Stream mode views (stream_mode.go): owner-only, rendered as players see.
+	cg.GET("/stream", h.StreamIndex, RequireRole(RoleOwner))
+	cg.GET("/stream/pages/:eid", h.StreamPageView, RequireRole(RoleOwner))
+	cg.GET("/stream/calendar", h.StreamCalendarView, RequireRole(RoleOwner))
+	cg.PUT("/onboarding", h.UpdateOnboardingAPI, RequireRole(RoleOwner))
+	cg.POST("/announcements", h.CreateAnnouncementAPI, RequireRole(RoleOwner))
+	cg.PUT("/announcements/:aid", h.UpdateAnnouncementAPI, RequireRole(RoleOwner))
+	cg.DELETE("/announcements/:aid", h.DeleteAnnouncementAPI, RequireRole(RoleOwner))
+	cg.PUT("/house-rules", h.SaveHouseRulesAPI, RequireRole(RoleOwner))
+	cg.PUT("/house-rules/settings", h.UpdateHouseRulesSettingsAPI, RequireRole(RoleOwner))
+	cg.POST("/house-rules/revisions/:version/restore", h.RestoreHouseRulesRevisionAPI, RequireRole(RoleOwner))
 	// V2 Wave 0 PR 2: event tier definitions per campaign. Owner-only
 	// campaign-config surface; not exposed via syncapi (Wave 5 territory).
 	cg.GET("/event-tier-definitions", h.GetEventTierDefinitionsAPI, RequireRole(RoleOwner))
@@ -131,6 +174,10 @@ func RegisterRoutes(e *echo.Echo, h *Handler, svc CampaignService, authSvc auth.
 	cg.POST("/archive", h.ArchiveCampaign, RequireRole(RoleOwner))
 	cg.POST("/unarchive", h.UnarchiveCampaign, RequireRole(RoleOwner))
 
+	// Restore a campaign in its deletion grace period (Owner only). The one
+	// write checkPendingDeletion lets through.
+	cg.POST("/restore", h.RestoreCampaign, RequireRole(RoleOwner))
+
 	// Game system (Owner only).
 	cg.PUT("/system", h.UpdateSystemID, RequireRole(RoleOwner))
 
@@ -165,6 +212,7 @@ func RegisterInviteRoutes(e *echo.Echo, ih *InviteHandler, svc CampaignService,
 	cg.GET("/invites", ih.ListInvitesAPI, RequireRole(RoleOwner))
 	cg.GET("/invites/page", ih.InvitesPage, RequireRole(RoleOwner))
 	cg.POST("/invites", ih.CreateInviteAPI, RequireRole(RoleOwner))
+	cg.POST("/invites/bulk", ih.BulkInviteAPI, RequireRole(RoleOwner), middleware.RateLimit(20, time.Hour))
 	cg.DELETE("/invites/:inviteId", ih.RevokeInviteAPI, RequireRole(RoleOwner))
 }
 
@@ -191,3 +239,12 @@ func RegisterExportRoutes(e *echo.Echo, eh *ExportHandler, svc CampaignService,
 	)
 	cg.GET("/export", eh.ExportCampaign, RequireRole(RoleOwner), middleware.RateLimit(10, 1*time.Hour))
 }
+
+// RegisterOverlayRoutes mounts the stream overlay widgets (overlays.go).
+// No auth or campaign middleware: the overlay token in the query string is
+// the only access control. Rate limiting is applied at the group level.
+func RegisterOverlayRoutes(e *echo.Echo, h *Handler, rl echo.MiddlewareFunc) {
+	g := e.Group("/overlays/:id", rl)
+	g.GET("/:widget", h.OverlayPage)
+	g.GET("/:widget/data", h.OverlayDataAPI)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 	"github.com/keyxmakerx/chronicle/internal/sanitize"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
 )
 
 // transferTokenBytes is the number of random bytes in a transfer token.
@@ -42,12 +43,17 @@ type CampaignService interface {
 	UpdateMemberRole(ctx context.Context, campaignID, userID string, role Role) error
 	UpdateMemberCharacter(ctx context.Context, campaignID, userID string, characterEntityID *string) error
 	ListMembers(ctx context.Context, campaignID string) ([]CampaignMember, error)
+	// ListUserMemberships returns every campaign a user belongs to. Used by
+	// the admin user pages and the dashboard layout copy picker.
+	ListUserMemberships(ctx context.Context, userID string) ([]UserMembership, error)
 
 	// Ownership transfer
 	InitiateTransfer(ctx context.Context, campaignID, ownerID, targetEmail string) (*OwnershipTransfer, error)
 	AcceptTransfer(ctx context.Context, token string, acceptingUserID string) error
 	CancelTransfer(ctx context.Context, campaignID string) error
 	GetPendingTransfer(ctx context.Context, campaignID string) (*OwnershipTransfer, error)
+	GetTransferOffer(ctx context.Context, campaignID, token, userID string) (*TransferOffer, error)
+	DeclineTransfer(ctx context.Context, campaignID, token, userID string) (*OwnershipTransfer, error)
 
 	// Backdrop and branding
 	UpdateBackdropPath(ctx context.Context, campaignID string, path *string) error
@@ -110,6 +116,26 @@ type CampaignService interface {
 	SetEventTierDefinitions(ctx context.Context, campaignID string, defs []TierDefinition) error
 	// UpdateDefaultVisibility sets the default visibility for new entities.
 	UpdateDefaultVisibility(ctx context.Context, campaignID, visibility string) error
+	// UpdateRetention sets the campaign's data retention windows.
+	UpdateRetention(ctx context.Context, campaignID string, retention RetentionSettings) error
+	// UpdateImageProxy sets the campaign's external image proxy settings.
+	UpdateImageProxy(ctx context.Context, campaignID string, proxy ImageProxySettings) error
+	// UpdateLicense sets the campaign's content license and attribution.
+	UpdateLicense(ctx context.Context, campaignID string, license LicenseSettings) error
+	// UpdateOverlays sets which stream overlay widgets are on, creating the
+	// overlay token on first enable.
+	UpdateOverlays(ctx context.Context, campaignID string, overlays OverlaySettings) (*OverlaySettings, error)
+	// RegenerateOverlayToken replaces the stream overlay token.
+	RegenerateOverlayToken(ctx context.Context, campaignID string) (*OverlaySettings, error)
+	// UpdateStreamMode sets the stream mode toggle and the pages it shows.
+	UpdateStreamMode(ctx context.Context, campaignID string, stream StreamModeSettings) error
+	// UpdateOnboarding sets the campaign's welcome page and checklist.
+	UpdateOnboarding(ctx context.Context, campaignID string, onboarding OnboardingSettings) error
+	// GetOnboardingStatus returns a member's checklist progress, nil when
+	// the campaign has no onboarding.
+	GetOnboardingStatus(ctx context.Context, campaign *Campaign, userID string) (*OnboardingStatus, error)
+	// SetOnboardingItem ticks or unticks a checklist item for a member.
+	SetOnboardingItem(ctx context.Context, campaignID, userID, itemID string, done bool) error
 
 	// Sidebar configuration
 	UpdateSidebarConfig(ctx context.Context, campaignID string, req UpdateSidebarConfigRequest) error
@@ -138,6 +164,12 @@ type CampaignService interface {
 	ArchiveCampaign(ctx context.Context, campaignID string) error
 	UnarchiveCampaign(ctx context.Context, campaignID string) error
 
+	// Deletion grace period (see deletion.go)
+	MarkForDeletion(ctx context.Context, campaignID string) (time.Time, error)
+	RestoreDeletion(ctx context.Context, campaignID string) error
+	ListPendingDeletion(ctx context.Context, userID string) ([]Campaign, error)
+	PurgeDueDeletions(ctx context.Context, now time.Time) (int, error)
+
 	// Shareable invite link
 	GenerateJoinCode(ctx context.Context, campaignID string) (string, error)
 	RevokeJoinCode(ctx context.Context, campaignID string) error
@@ -150,6 +182,7 @@ type CampaignService interface {
 	SetContentTemplateSeeder(seeder ContentTemplateSeeder)
 	SetWorldbuildingPromptSeeder(seeder WorldbuildingPromptSeeder)
 	SetLayoutPresetSeeder(seeder LayoutPresetSeeder)
+	SetAddonEnabler(enabler CampaignAddonEnabler)
 	SetMediaCleaner(cleaner MediaCleaner)
 	SetHookDispatcher(dispatcher CampaignHookDispatcher)
 }
@@ -164,6 +197,7 @@ type GroupService interface {
 	AddGroupMember(ctx context.Context, groupID int, userID string) error
 	RemoveGroupMember(ctx context.Context, groupID int, userID string) error
 	ListGroupMembers(ctx context.Context, groupID int) ([]GroupMemberInfo, error)
+	ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error)
 }
 
 // MediaCleaner handles bulk media file cleanup during campaign deletion.
@@ -187,6 +221,7 @@ type campaignService struct {
 	templateSeeder   ContentTemplateSeeder  // Seeds default content templates on campaign creation. May be nil.
 	promptSeeder     WorldbuildingPromptSeeder // Seeds default worldbuilding prompts on campaign creation. May be nil.
 	layoutSeeder     LayoutPresetSeeder     // Seeds default layout presets on campaign creation. May be nil.
+	addonEnabler     CampaignAddonEnabler   // Enables the extensions picked at creation. May be nil.
 	mediaCleaner     MediaCleaner           // Cleans up media files on campaign delete. May be nil.
 	hookDispatcher   CampaignHookDispatcher // Dispatches WASM lifecycle events. May be nil.
 	baseURL          string
@@ -336,6 +371,13 @@ func (s *campaignService) Create(ctx context.Context, userID string, input Creat
 		}
 	}
 
+	// Enable the extensions picked on the create form. Unlike the seeds
+	// above this isn't best-effort: the GM chose them, so a failure undoes
+	// the campaign instead of leaving one missing features.
+	if err := s.enableCreationAddons(ctx, campaign, userID, input.Addons); err != nil {
+		return nil, err
+	}
+
 	slog.Info("campaign created",
 		slog.String("campaign_id", campaign.ID),
 		slog.String("slug", campaign.Slug),
@@ -434,6 +476,9 @@ func (s *campaignService) Update(ctx context.Context, campaignID string, input U
 // 1. Delete media files from disk (before SQL CASCADE nullifies campaign_id)
 // 2. Dispatch campaign.deleted hook to WASM plugins for cache cleanup
 // 3. SQL DELETE with FK CASCADE handles remaining database rows
+//
+// This is the immediate, unrecoverable delete: owners go through
+// MarkForDeletion, and this runs from the purge job and admin force-delete.
 func (s *campaignService) Delete(ctx context.Context, campaignID string) error {
 	// Step 1: Clean up media files from disk before the SQL DELETE.
 	// The media_files FK uses ON DELETE SET NULL, so we must delete files
@@ -590,6 +635,11 @@ func (s *campaignService) ListMembers(ctx context.Context, campaignID string) ([
 	return s.repo.ListMembers(ctx, campaignID)
 }
 
+// ListUserMemberships returns every campaign a user belongs to.
+func (s *campaignService) ListUserMemberships(ctx context.Context, userID string) ([]UserMembership, error) {
+	return s.repo.ListUserMemberships(ctx, userID)
+}
+
 // --- Ownership Transfer ---
 
 // InitiateTransfer starts an ownership transfer. Generates a token and
@@ -650,14 +700,12 @@ func (s *campaignService) InitiateTransfer(ctx context.Context, campaignID, owne
 		}
 
 		link := fmt.Sprintf("%s/campaigns/%s/accept-transfer?token=%s", s.baseURL, campaignID, token)
-		body := fmt.Sprintf(
-			"You have been offered ownership of the campaign \"%s\" on Chronicle.\n\n"+
-				"Click the link below to accept (you must be logged in):\n%s\n\n"+
-				"This link expires in %d hours. If you did not expect this, you can ignore it.",
-			campaignName, link, transferExpiryHours,
-		)
-
-		if err := s.mail.SendMail(ctx, []string{email}, "Campaign Ownership Transfer", body); err != nil {
+		msg := emails.TransferOffer(campaignName, link, transferExpiryHours)
+		plainBody, htmlBody, err := msg.Render(ctx)
+		if err == nil {
+			err = s.mail.SendHTMLMail(ctx, []string{email}, msg.Subject, plainBody, htmlBody)
+		}
+		if err != nil {
 			// Log but don't fail -- the transfer is still created and can be
 			// accepted via the campaign settings page.
 			slog.Warn("failed to send transfer email",
@@ -734,6 +782,74 @@ func (s *campaignService) GetPendingTransfer(ctx context.Context, campaignID str
 	return s.repo.FindTransferByCampaign(ctx, campaignID)
 }
 
+// GetTransferOffer loads a pending transfer for its recipient's
+// confirmation page. The token comes from the offer email; without one,
+// the campaign's pending transfer is used, so the in-app notification
+// link doesn't have to carry the token.
+func (s *campaignService) GetTransferOffer(ctx context.Context, campaignID, token, userID string) (*TransferOffer, error) {
+	transfer, err := s.recipientTransfer(ctx, campaignID, token, userID)
+	if err != nil {
+		return nil, err
+	}
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer := &TransferOffer{Transfer: transfer, Campaign: campaign, FromName: "The current owner"}
+	if from, err := s.users.FindUserByID(ctx, transfer.FromUserID); err == nil && from.DisplayName != "" {
+		offer.FromName = from.DisplayName
+	}
+	if members, err := s.repo.ListMembers(ctx, campaignID); err == nil {
+		offer.MemberCount = len(members)
+	}
+	return offer, nil
+}
+
+// DeclineTransfer lets the recipient turn down a pending transfer. Returns
+// the declined transfer so the caller can tell the owner.
+func (s *campaignService) DeclineTransfer(ctx context.Context, campaignID, token, userID string) (*OwnershipTransfer, error) {
+	transfer, err := s.recipientTransfer(ctx, campaignID, token, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteTransfer(ctx, transfer.ID); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("deleting transfer: %w", err))
+	}
+
+	slog.Info("ownership transfer declined",
+		slog.String("campaign_id", campaignID),
+		slog.String("by", userID),
+	)
+	return transfer, nil
+}
+
+// recipientTransfer finds the live transfer of a campaign addressed to
+// userID, by token when given. Same checks as AcceptTransfer, plus the
+// token must belong to this campaign so a link can't be replayed under
+// another campaign's URL.
+func (s *campaignService) recipientTransfer(ctx context.Context, campaignID, token, userID string) (*OwnershipTransfer, error) {
+	var transfer *OwnershipTransfer
+	var err error
+	if token != "" {
+		transfer, err = s.repo.FindTransferByToken(ctx, token)
+	} else {
+		transfer, err = s.repo.FindTransferByCampaign(ctx, campaignID)
+	}
+	if err != nil || transfer == nil || transfer.CampaignID != campaignID {
+		return nil, apperror.NewBadRequest("invalid or expired transfer link")
+	}
+
+	if time.Now().UTC().After(transfer.ExpiresAt) {
+		_ = s.repo.DeleteTransfer(ctx, transfer.ID)
+		return nil, apperror.NewBadRequest("this transfer link has expired")
+	}
+	if transfer.ToUserID != userID {
+		return nil, apperror.NewForbidden("this transfer is not for your account")
+	}
+	return transfer, nil
+}
+
 // --- Sidebar Configuration ---
 
 // maxSidebarConfigEntries caps the number of entries in sidebar config arrays
@@ -1328,6 +1444,170 @@ func (s *campaignService) UpdateDefaultVisibility(ctx context.Context, campaignI
 	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
 }
 
+// UpdateRetention sets the campaign's retention windows. An all-zero value
+// clears the setting (keep everything).
+func (s *campaignService) UpdateRetention(ctx context.Context, campaignID string, retention RetentionSettings) error {
+	if err := retention.Validate(); err != nil {
+		return err
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	settings := campaign.ParseSettings()
+	if retention.IsZero() {
+		settings.Retention = nil
+	} else {
+		settings.Retention = &retention
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+
+	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
+}
+
+// UpdateImageProxy sets the campaign's image proxy toggle and host
+// allowlist. Turning it off with no allowlist clears the setting.
+func (s *campaignService) UpdateImageProxy(ctx context.Context, campaignID string, proxy ImageProxySettings) error {
+	if err := proxy.Normalize(); err != nil {
+		return err
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	settings := campaign.ParseSettings()
+	if !proxy.Enabled && len(proxy.AllowedHosts) == 0 {
+		settings.ImageProxy = nil
+	} else {
+		settings.ImageProxy = &proxy
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+
+	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
+}
+
+// UpdateLicense sets the campaign's content license and attribution.
+// Clearing both removes the setting.
+func (s *campaignService) UpdateLicense(ctx context.Context, campaignID string, license LicenseSettings) error {
+	if err := license.Normalize(); err != nil {
+		return err
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	settings := campaign.ParseSettings()
+	if license.IsSet() {
+		settings.License = &license
+	} else {
+		settings.License = nil
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+
+	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
+}
+
+// UpdateOverlays sets the stream overlay widgets. The stored token is kept;
+// one is generated the first time any widget is turned on.
+func (s *campaignService) UpdateOverlays(ctx context.Context, campaignID string, overlays OverlaySettings) (*OverlaySettings, error) {
+	if err := overlays.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.saveOverlays(ctx, campaignID, func(current *OverlaySettings) error {
+		current.Widgets = overlays.Widgets
+		if current.Token == "" && current.AnyEnabled() {
+			token, err := generateToken()
+			if err != nil {
+				return apperror.NewInternal(fmt.Errorf("generating overlay token: %w", err))
+			}
+			current.Token = token
+		}
+		return nil
+	})
+}
+
+// RegenerateOverlayToken replaces the overlay token so every overlay URL
+// handed out so far stops working.
+func (s *campaignService) RegenerateOverlayToken(ctx context.Context, campaignID string) (*OverlaySettings, error) {
+	return s.saveOverlays(ctx, campaignID, func(current *OverlaySettings) error {
+		token, err := generateToken()
+		if err != nil {
+			return apperror.NewInternal(fmt.Errorf("generating overlay token: %w", err))
+		}
+		current.Token = token
+		return nil
+	})
+}
+
+// saveOverlays applies update to the stored overlay settings and writes
+// them back.
+func (s *campaignService) saveOverlays(ctx context.Context, campaignID string, update func(*OverlaySettings) error) (*OverlaySettings, error) {
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := campaign.ParseSettings()
+	overlays := settings.GetOverlays()
+	if err := update(&overlays); err != nil {
+		return nil, err
+	}
+	settings.Overlays = &overlays
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+	if err := s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON)); err != nil {
+		return nil, err
+	}
+	return &overlays, nil
+}
+
+// UpdateStreamMode sets the stream mode toggle, calendar flag and pages.
+func (s *campaignService) UpdateStreamMode(ctx context.Context, campaignID string, stream StreamModeSettings) error {
+	if err := stream.Normalize(); err != nil {
+		return err
+	}
+
+	campaign, err := s.repo.FindByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	settings := campaign.ParseSettings()
+	if !stream.Enabled && !stream.Calendar && len(stream.Pages) == 0 {
+		settings.StreamMode = nil
+	} else {
+		settings.StreamMode = &stream
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("marshaling settings: %w", err))
+	}
+
+	return s.repo.UpdateSettings(ctx, campaignID, string(settingsJSON))
+}
+
 // UpdateSidebarConfig applies a partial update to the stored sidebar config via
 // a load-merge-write pattern. Nil pointer fields in req are absent from the
 // JSON body and are left unchanged; non-nil fields (including explicit empty
@@ -1713,6 +1993,9 @@ func (s *campaignService) JoinByCode(ctx context.Context, code, userID string) e
 	if campaign.IsArchived() {
 		return apperror.NewBadRequest("campaign is archived and not accepting new members")
 	}
+	if campaign.IsPendingDeletion() {
+		return apperror.NewNotFound("invalid or expired invite code")
+	}
 
 	// Check if already a member.
 	_, err = s.repo.FindMember(ctx, campaign.ID, userID)
@@ -1871,3 +2154,13 @@ func (s *groupService) ListGroupMembers(ctx context.Context, groupID int) ([]Gro
 	}
 	return members, nil
 }
+
+// ListUserGroupIDs returns the IDs of the campaign's groups a user is in.
+// Used to apply group-limited block visibility.
+func (s *groupService) ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error) {
+	ids, err := s.repo.ListUserGroupIDs(ctx, campaignID, userID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return ids, nil
+}
@@ -143,6 +143,10 @@ func (m *mockCampaignRepo) ListMembers(ctx context.Context, campaignID string) (
 	return nil, nil
 }
 
+func (m *mockCampaignRepo) ListUserMemberships(ctx context.Context, userID string) ([]UserMembership, error) {
+	return nil, nil
+}
+
 func (m *mockCampaignRepo) UpdateMemberRole(ctx context.Context, campaignID, userID string, role Role) error {
 	if m.updateMemberRoleFn != nil {
 		return m.updateMemberRoleFn(ctx, campaignID, userID, role)
@@ -218,6 +222,10 @@ func (m *mockCampaignRepo) UpdateSettings(ctx context.Context, campaignID, setti
 	return nil
 }
 
+func (m *mockCampaignRepo) ListRetentionSettings(ctx context.Context) (map[string]RetentionSettings, error) {
+	return nil, nil
+}
+
 func (m *mockCampaignRepo) TransferOwnership(ctx context.Context, campaignID, fromUserID, toUserID string) error {
 	if m.transferOwnershipFn != nil {
 		return m.transferOwnershipFn(ctx, campaignID, fromUserID, toUserID)
@@ -234,6 +242,28 @@ func (m *mockCampaignRepo) ForceTransferOwnership(ctx context.Context, campaignI
 
 func (m *mockCampaignRepo) ArchiveCampaign(context.Context, string) error   { return nil }
 func (m *mockCampaignRepo) UnarchiveCampaign(context.Context, string) error { return nil }
+func (m *mockCampaignRepo) MarkForDeletion(context.Context, string, time.Time) error {
+	return nil
+}
+func (m *mockCampaignRepo) CancelDeletion(context.Context, string) error { return nil }
+func (m *mockCampaignRepo) ListDueForPurge(context.Context, time.Time, int) ([]string, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepo) ListPendingDeletionByOwner(context.Context, string) ([]Campaign, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepo) ListOnboardingProgress(context.Context, string, string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockCampaignRepo) SetOnboardingProgress(context.Context, string, string, string, bool) error {
+	return nil
+}
+func (m *mockCampaignRepo) HasCharacter(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+func (m *mockCampaignRepo) HasAcknowledgedHouseRules(context.Context, string, string) (bool, error) {
+	return false, nil
+}
 func (m *mockCampaignRepo) SetJoinCode(context.Context, string, string) error { return nil }
 func (m *mockCampaignRepo) ClearJoinCode(context.Context, string) error       { return nil }
 func (m *mockCampaignRepo) FindByJoinCode(context.Context, string) (*Campaign, error) {
@@ -289,6 +319,12 @@ func (m *mockMailService) SendMail(ctx context.Context, to []string, subject, bo
 	return nil
 }
 
+// SendHTMLMail routes through sendMailFn with the plain part, so tests can
+// assert on the text body either way.
+func (m *mockMailService) SendHTMLMail(ctx context.Context, to []string, subject, plainBody, _ string) error {
+	return m.SendMail(ctx, to, subject, plainBody)
+}
+
 func (m *mockMailService) IsConfigured(ctx context.Context) bool {
 	if m.isConfiguredFn != nil {
 		return m.isConfiguredFn(ctx)
@@ -1099,6 +1135,101 @@ func TestCancelTransfer_NoPending(t *testing.T) {
 	assertAppError(t, err, 404)
 }
 
+func TestGetTransferOffer(t *testing.T) {
+	live := func() *OwnershipTransfer {
+		return &OwnershipTransfer{
+			ID: "transfer-1", CampaignID: "camp-1", FromUserID: "user-1", ToUserID: "user-2",
+			Token: "tok", ExpiresAt: time.Now().Add(24 * time.Hour),
+		}
+	}
+	cases := []struct {
+		name     string
+		transfer *OwnershipTransfer
+		token    string
+		userID   string
+		wantCode int // 0 = success
+	}{
+		{"by token", live(), "tok", "user-2", 0},
+		{"from notification without token", live(), "", "user-2", 0},
+		{"no pending transfer", nil, "", "user-2", 400},
+		{"token for another campaign", &OwnershipTransfer{CampaignID: "camp-9", ToUserID: "user-2", ExpiresAt: time.Now().Add(time.Hour)}, "tok", "user-2", 400},
+		{"expired", &OwnershipTransfer{CampaignID: "camp-1", ToUserID: "user-2", ExpiresAt: time.Now().Add(-time.Hour)}, "tok", "user-2", 400},
+		{"someone else", live(), "tok", "user-3", 403},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			find := func(context.Context, string) (*OwnershipTransfer, error) { return tc.transfer, nil }
+			repo := &mockCampaignRepo{
+				findTransferByTokenFn:    find,
+				findTransferByCampaignFn: find,
+				listMembersFn: func(context.Context, string) ([]CampaignMember, error) {
+					return make([]CampaignMember, 3), nil
+				},
+			}
+			users := &mockUserFinder{findByIDFn: func(_ context.Context, id string) (*MemberUser, error) {
+				return &MemberUser{ID: id, DisplayName: "Alice"}, nil
+			}}
+			svc := newTestCampaignService(repo, users)
+
+			offer, err := svc.GetTransferOffer(context.Background(), "camp-1", tc.token, tc.userID)
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if offer.FromName != "Alice" || offer.MemberCount != 3 || offer.Campaign.ID != "camp-1" {
+				t.Errorf("offer = %+v", offer)
+			}
+		})
+	}
+}
+
+func TestDeclineTransfer_DeletesTransfer(t *testing.T) {
+	var deleted string
+	repo := &mockCampaignRepo{
+		findTransferByTokenFn: func(context.Context, string) (*OwnershipTransfer, error) {
+			return &OwnershipTransfer{
+				ID: "transfer-1", CampaignID: "camp-1", FromUserID: "user-1", ToUserID: "user-2",
+				ExpiresAt: time.Now().Add(24 * time.Hour),
+			}, nil
+		},
+		deleteTransferFn: func(_ context.Context, id string) error {
+			deleted = id
+			return nil
+		},
+		transferOwnershipFn: func(context.Context, string, string, string) error {
+			t.Error("declining must not transfer ownership")
+			return nil
+		},
+	}
+	svc := newTestCampaignService(repo, &mockUserFinder{})
+
+	transfer, err := svc.DeclineTransfer(context.Background(), "camp-1", "tok", "user-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != "transfer-1" || transfer.FromUserID != "user-1" {
+		t.Errorf("deleted %q, returned %+v", deleted, transfer)
+	}
+}
+
+func TestDeclineTransfer_WrongUser(t *testing.T) {
+	repo := &mockCampaignRepo{
+		findTransferByTokenFn: func(context.Context, string) (*OwnershipTransfer, error) {
+			return &OwnershipTransfer{ID: "transfer-1", CampaignID: "camp-1", ToUserID: "user-2", ExpiresAt: time.Now().Add(time.Hour)}, nil
+		},
+		deleteTransferFn: func(context.Context, string) error {
+			t.Error("another user must not be able to decline")
+			return nil
+		},
+	}
+	svc := newTestCampaignService(repo, &mockUserFinder{})
+	_, err := svc.DeclineTransfer(context.Background(), "camp-1", "tok", "user-3")
+	assertAppError(t, err, 403)
+}
+
 // ============================================================
 // Sidebar Config Tests
 // ============================================================
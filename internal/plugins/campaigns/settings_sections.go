@@ -0,0 +1,155 @@
+// settings_sections.go — plugin-contributed sections inside Settings
+// tabs. RegisterSettingsTab lets a plugin own a whole tab; most plugins
+// only need a card on an existing one (syncapi's API keys on
+// Integrations, a calendar's sync options, ...). Before this registry
+// each such card was hand-wired into settings.templ with its own role
+// check; now the plugin registers a SettingsSection and campaigns
+// applies the role + addon gates in one place.
+//
+// Ordering inside a tab: the tab's own Content sits at
+// SettingsContentSortOrder, sections with a lower SortOrder render
+// above it and the rest below it.
+
+package campaigns
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/a-h/templ"
+)
+
+// SettingsContentSortOrder is the slot a tab's own Content occupies
+// among its sections.
+const SettingsContentSortOrder = 50
+
+// SettingsSection is one plugin-contributed block inside a Settings tab.
+//
+//   - Tab is the ID of the tab it renders in. A section naming a tab the
+//     viewer can't see (or that doesn't exist) is dropped.
+//   - ID is a stable, DOM-safe slug used as the wrapper's element ID,
+//     so the section's own HTMX swaps can target it (hx-target="#<ID>").
+//   - MinRole gates the section the same way SettingsTab.MinRole gates
+//     a tab. The fragment route must apply the same RequireRole; the
+//     registry only decides what is rendered.
+//   - Addon, when set, hides the section unless that addon is enabled
+//     for the campaign.
+//   - SortOrder places the section relative to the tab's Content (see
+//     SettingsContentSortOrder) and to other sections.
+//   - FragmentURL lazy-loads the section body over HTMX; Content renders
+//     inline. Exactly one should be set; Content wins when both are.
+type SettingsSection struct {
+	Tab         string
+	ID          string
+	MinRole     Role
+	Addon       string
+	SortOrder   int
+	FragmentURL string
+	Content     templ.Component
+}
+
+// RegisterSettingsSection appends a section factory to the Handler's
+// registry. Like RegisterSettingsTab, the factory runs per request with
+// the live CampaignContext so it can bind the campaign ID into URLs.
+func (h *Handler) RegisterSettingsSection(factory func(*CampaignContext) SettingsSection) {
+	h.extraSettingsSections = append(h.extraSettingsSections, factory)
+}
+
+// addonEnabled reports whether slug is enabled for the campaign. An
+// empty slug or a nil checker (tests, early init) counts as enabled. A
+// lookup error is logged and also counts as enabled, like the Extensions
+// hub: a transient store failure must not blank the owner's settings,
+// and the section's own routes still enforce their gates.
+func (h *Handler) addonEnabled(ctx context.Context, campaignID, slug string) bool {
+	if slug == "" || h.extensionEnableChecker == nil {
+		return true
+	}
+	ok, err := h.extensionEnableChecker.IsEnabledForCampaign(ctx, campaignID, slug)
+	if err != nil {
+		slog.Warn("settings addon check failed",
+			slog.String("campaign_id", campaignID),
+			slog.String("addon", slug),
+			slog.Any("error", err),
+		)
+		return true
+	}
+	return ok
+}
+
+// settingsTabsFor is visibleSettingsTabs plus the checks that need the
+// request context: addon-gated tabs are dropped and each remaining tab
+// gets its visible sections attached. Every Settings render goes
+// through here.
+func (h *Handler) settingsTabsFor(
+	ctx context.Context,
+	cc *CampaignContext,
+	transfer *OwnershipTransfer,
+	members []CampaignMember,
+	csrfToken string,
+	systemOptions []SystemOption,
+	smtpConfigured bool,
+) []SettingsTab {
+	tabs := h.visibleSettingsTabs(cc, transfer, members, csrfToken, systemOptions, smtpConfigured)
+
+	out := tabs[:0]
+	for _, t := range tabs {
+		if !h.addonEnabled(ctx, cc.Campaign.ID, t.Addon) {
+			continue
+		}
+		out = append(out, t)
+	}
+
+	sections := h.visibleSettingsSections(ctx, cc)
+	for i := range out {
+		out[i].Sections = sections[out[i].ID]
+	}
+	return out
+}
+
+// visibleSettingsSections runs the section factories and returns the
+// sections the viewer may see, grouped by tab and sorted (stable) by
+// SortOrder.
+func (h *Handler) visibleSettingsSections(ctx context.Context, cc *CampaignContext) map[string][]SettingsSection {
+	byTab := make(map[string][]SettingsSection)
+	for _, factory := range h.extraSettingsSections {
+		s := factory(cc)
+		if cc.MemberRole < s.MinRole {
+			continue
+		}
+		if !h.addonEnabled(ctx, cc.Campaign.ID, s.Addon) {
+			continue
+		}
+		byTab[s.Tab] = append(byTab[s.Tab], s)
+	}
+	for _, list := range byTab {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].SortOrder < list[j].SortOrder
+		})
+	}
+	return byTab
+}
+
+// SectionsBefore returns the tab's sections that render above its
+// Content.
+func (t SettingsTab) SectionsBefore() []SettingsSection {
+	var out []SettingsSection
+	for _, s := range t.Sections {
+		if s.SortOrder < SettingsContentSortOrder {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// SectionsAfter returns the tab's sections that render below its
+// Content.
+func (t SettingsTab) SectionsAfter() []SettingsSection {
+	var out []SettingsSection
+	for _, s := range t.Sections {
+		if s.SortOrder >= SettingsContentSortOrder {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package campaigns
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// slugChecker enables exactly the listed addons.
+type slugChecker map[string]bool
+
+func (s slugChecker) IsEnabledForCampaign(ctx context.Context, campaignID string, slug string) (bool, error) {
+	return s[slug], nil
+}
+
+func sectionFactory(s SettingsSection) func(*CampaignContext) SettingsSection {
+	return func(*CampaignContext) SettingsSection { return s }
+}
+
+func sectionIDs(list []SettingsSection) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = s.ID
+	}
+	return out
+}
+
+func findTab(tabs []SettingsTab, id string) *SettingsTab {
+	for i := range tabs {
+		if tabs[i].ID == id {
+			return &tabs[i]
+		}
+	}
+	return nil
+}
+
+func TestSettingsSections_AttachedToTabAndSplitAroundContent(t *testing.T) {
+	h := &Handler{}
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "below", SortOrder: 60}))
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "above", SortOrder: 10}))
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "people", ID: "people-card", SortOrder: 10}))
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "nonexistent", ID: "orphan"}))
+
+	tabs := h.settingsTabsFor(context.Background(), ctxWithRole(RoleOwner), nil, nil, "csrf", nil, false)
+
+	integrations := findTab(tabs, "integrations")
+	if integrations == nil {
+		t.Fatal("integrations tab missing")
+	}
+	if got := sectionIDs(integrations.Sections); len(got) != 2 || got[0] != "above" || got[1] != "below" {
+		t.Errorf("integrations sections = %v, want [above below]", got)
+	}
+	if got := sectionIDs(integrations.SectionsBefore()); len(got) != 1 || got[0] != "above" {
+		t.Errorf("SectionsBefore = %v, want [above]", got)
+	}
+	if got := sectionIDs(integrations.SectionsAfter()); len(got) != 1 || got[0] != "below" {
+		t.Errorf("SectionsAfter = %v, want [below]", got)
+	}
+	if got := sectionIDs(findTab(tabs, "people").Sections); len(got) != 1 || got[0] != "people-card" {
+		t.Errorf("people sections = %v, want [people-card]", got)
+	}
+	for _, tab := range tabs {
+		for _, s := range tab.Sections {
+			if s.ID == "orphan" {
+				t.Errorf("section for unknown tab rendered on %q", tab.ID)
+			}
+		}
+	}
+}
+
+func TestSettingsSections_RoleGate(t *testing.T) {
+	h := &Handler{}
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "owner-only", MinRole: RoleOwner}))
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "anyone", MinRole: RolePlayer}))
+
+	tabs := h.settingsTabsFor(context.Background(), ctxWithRole(RolePlayer), nil, nil, "csrf", nil, false)
+	if got := sectionIDs(findTab(tabs, "integrations").Sections); len(got) != 1 || got[0] != "anyone" {
+		t.Errorf("player sections = %v, want [anyone]", got)
+	}
+
+	tabs = h.settingsTabsFor(context.Background(), ctxWithRole(RoleOwner), nil, nil, "csrf", nil, false)
+	if got := sectionIDs(findTab(tabs, "integrations").Sections); len(got) != 2 {
+		t.Errorf("owner sections = %v, want both", got)
+	}
+}
+
+func TestSettingsSections_AddonGate(t *testing.T) {
+	h := &Handler{}
+	h.SetExtensionEnableChecker(slugChecker{"calendar": true})
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "cal", Addon: "calendar"}))
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "maps", Addon: "maps"}))
+	h.RegisterSettingsTab(func(*CampaignContext) SettingsTab {
+		return SettingsTab{ID: "maps-tab", Label: "Maps", MinRole: RoleOwner, SortOrder: 45, Addon: "maps"}
+	})
+
+	tabs := h.settingsTabsFor(context.Background(), ctxWithRole(RoleOwner), nil, nil, "csrf", nil, false)
+	if got := sectionIDs(findTab(tabs, "integrations").Sections); len(got) != 1 || got[0] != "cal" {
+		t.Errorf("sections = %v, want [cal]", got)
+	}
+	if findTab(tabs, "maps-tab") != nil {
+		t.Error("tab gated on a disabled addon was rendered")
+	}
+}
+
+// TestSettingsSections_AddonCheckErrorFailsOpen matches the Extensions
+// hub: a store error must not hide the owner's settings.
+func TestSettingsSections_AddonCheckErrorFailsOpen(t *testing.T) {
+	h := &Handler{}
+	h.SetExtensionEnableChecker(stubChecker{err: errors.New("store unavailable")})
+	h.RegisterSettingsSection(sectionFactory(SettingsSection{Tab: "integrations", ID: "cal", Addon: "calendar"}))
+
+	tabs := h.settingsTabsFor(context.Background(), ctxWithRole(RoleOwner), nil, nil, "csrf", nil, false)
+	if got := sectionIDs(findTab(tabs, "integrations").Sections); len(got) != 1 {
+		t.Errorf("sections = %v, want [cal]", got)
+	}
+}
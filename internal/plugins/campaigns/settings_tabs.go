@@ -37,6 +37,8 @@ import (
 //     10 (10..60) so plugins can insert between them; the AI Workspace
 //     plugin (Phase 2) will register itself at SortOrder 55 to land
 //     between AI Export (50) and Activity (60).
+//   - Addon, when set, hides the tab unless that addon is enabled for
+//     the campaign (checked in settingsTabsFor).
 //   - Content is the rendered tab body. The handler captures all
 //     per-tab dependencies (csrfToken, members, addons, services, ...)
 //     in this closure at Settings-handler time, so CampaignSettingsPage
@@ -47,7 +49,12 @@ type SettingsTab struct {
 	Icon      string
 	MinRole   Role
 	SortOrder int
+	Addon     string
 	Content   templ.Component
+
+	// Sections are the plugin-contributed sections for this tab,
+	// attached per request by settingsTabsFor (settings_sections.go).
+	Sections []SettingsSection
 }
 
 // RegisterSettingsTab appends a tab-factory to the Handler's plugin-
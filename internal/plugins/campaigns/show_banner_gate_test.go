@@ -45,3 +45,13 @@ func TestShowBanner_OwnerOnly(t *testing.T) {
 		}
 	}
 }
+
+// The announcements card is members-only for the same reason.
+func TestAnnouncementsFragment_MembersOnly(t *testing.T) {
+	if !strings.Contains(renderShowFor(t, RolePlayer), "announcements/fragment") {
+		t.Errorf("member page must lazy-load the announcements card")
+	}
+	if strings.Contains(renderShowFor(t, RoleNone), "announcements/fragment") {
+		t.Errorf("public visitors must NOT receive the members-only announcements hx-get")
+	}
+}
@@ -17,7 +17,7 @@ import (
 const campaignScanColumns = `id, name, slug, description, is_public,
 	settings, backdrop_path, sidebar_config, dashboard_layout,
 	owner_dashboard_layout, created_by, created_at, updated_at,
-	archived_at, join_code`
+	archived_at, join_code, deletion_requested_at, purge_after`
 
 // ScanSmokeTest returns a startup probe that runs a real SELECT + Scan on the
 // campaigns table. If the column list in the query doesn't match the Campaign
@@ -33,6 +33,7 @@ func ScanSmokeTest() database.SmokeTest {
 				&c.ID, &c.Name, &c.Slug, &c.Description, &c.IsPublic,
 				&c.Settings, &c.BackdropPath, &c.SidebarConfig, &c.DashboardLayout, &c.OwnerDashboardLayout,
 				&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt, &c.ArchivedAt, &c.JoinCode,
+				&c.DeletionRequestedAt, &c.PurgeAfter,
 			)
 		},
 	}
@@ -0,0 +1,268 @@
+package campaigns
+
+// stream_mode.go — a player-safe projection of chosen pages and the
+// calendar for screen-sharing on stream:
+//
+//	GET /campaigns/:id/stream                index of the projected pages
+//	GET /campaigns/:id/stream/pages/:eid     one page
+//	GET /campaigns/:id/stream/calendar       in-game date and upcoming events
+//
+// The owner turns stream mode on and picks the pages in settings. The
+// views are standalone pages without the app sidebar or toolbars, and
+// their content is loaded as a player with no per-user grants would see
+// it: private and user-granted pages are left out, inline secrets are
+// stripped and GM-only events never load. Nothing on these pages is
+// derived from the owner's own access. Content comes from other plugins
+// through StreamPageFunc and StreamCalendarFunc, wired in app.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// streamModeMaxPages caps the pages projected in stream mode.
+const streamModeMaxPages = 24
+
+// StreamModeSettings holds a campaign's stream mode config.
+type StreamModeSettings struct {
+	Enabled  bool            `json:"enabled"`
+	Calendar bool            `json:"calendar"`
+	Pages    []StreamPageRef `json:"pages,omitempty"`
+}
+
+// StreamPageRef is a page picked for stream mode. Name is the label the
+// settings card shows; the stream views always use the live page.
+type StreamPageRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Normalize drops blank and repeated pages and caps the list.
+func (s *StreamModeSettings) Normalize() error {
+	seen := make(map[string]bool, len(s.Pages))
+	pages := make([]StreamPageRef, 0, len(s.Pages))
+	for _, p := range s.Pages {
+		p.ID = strings.TrimSpace(p.ID)
+		if p.ID == "" || seen[p.ID] {
+			continue
+		}
+		seen[p.ID] = true
+		pages = append(pages, p)
+	}
+	if len(pages) > streamModeMaxPages {
+		return apperror.NewBadRequest(fmt.Sprintf("at most %d pages in stream mode", streamModeMaxPages))
+	}
+	s.Pages = pages
+	return nil
+}
+
+// HasPage reports whether the entity was picked for stream mode.
+func (s StreamModeSettings) HasPage(entityID string) bool {
+	for _, p := range s.Pages {
+		if p.ID == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStreamMode returns the campaign's stream mode settings (off when
+// never configured).
+func (s CampaignSettings) GetStreamMode() StreamModeSettings {
+	if s.StreamMode == nil {
+		return StreamModeSettings{}
+	}
+	return *s.StreamMode
+}
+
+// StreamPage is one page as players see it. EntryHTML is sanitized with
+// inline secrets already removed.
+type StreamPage struct {
+	ID        string
+	Name      string
+	TypeName  string
+	TypeIcon  string
+	TypeColor string
+	ImageURL  string
+	EntryHTML string
+}
+
+// StreamPageFunc loads a page in its player projection. A nil page means
+// players can't see it, so stream mode doesn't show it either.
+type StreamPageFunc func(ctx context.Context, campaignID, entityID string) (*StreamPage, error)
+
+// StreamCalendar is the calendar as players see it: today's in-game date
+// and the upcoming events that aren't GM-only.
+type StreamCalendar struct {
+	Name   string
+	Today  string
+	Detail string
+	Events []StreamEvent
+}
+
+// StreamEvent is one upcoming calendar event.
+type StreamEvent struct {
+	Name string
+	Date string
+}
+
+// StreamCalendarFunc loads the calendar projection; nil when the campaign
+// has no calendar.
+type StreamCalendarFunc func(ctx context.Context, campaignID string) (*StreamCalendar, error)
+
+// StreamImageSrcFunc returns the image source rewriter for showing a
+// campaign's content: media.ImageProxy.DisplaySrc, wired in app.
+type StreamImageSrcFunc func(campaignID string, cfg ImageProxySettings) func(src string) string
+
+// SetStreamSources wires where stream mode gets its content. Either may be
+// nil when its plugin is unavailable.
+func (h *Handler) SetStreamSources(pages StreamPageFunc, calendar StreamCalendarFunc) {
+	h.streamPages = pages
+	h.streamCalendar = calendar
+}
+
+// SetStreamImageProxy wires the external image proxy for projected pages.
+func (h *Handler) SetStreamImageProxy(displaySrc StreamImageSrcFunc) {
+	h.streamImageSrc = displaySrc
+}
+
+// streamNavItem is one link in the stream view's top bar.
+type streamNavItem struct {
+	Label string
+	URL   string
+}
+
+// streamNav checks stream mode is on and lists what it shows. Pages are
+// re-checked against their live visibility so a page made private since
+// it was picked drops out, name and all.
+func (h *Handler) streamNav(c echo.Context, cc *CampaignContext) (StreamModeSettings, []streamNavItem, error) {
+	settings := cc.Campaign.ParseSettings().GetStreamMode()
+	if !settings.Enabled {
+		return settings, nil, apperror.NewNotFound("stream mode is off for this campaign")
+	}
+
+	ctx := c.Request().Context()
+	base := "/campaigns/" + cc.Campaign.ID + "/stream"
+	var nav []streamNavItem
+	if h.streamPages != nil {
+		for _, ref := range settings.Pages {
+			page, err := h.streamPages(ctx, cc.Campaign.ID, ref.ID)
+			if err != nil || page == nil {
+				continue
+			}
+			nav = append(nav, streamNavItem{Label: page.Name, URL: base + "/pages/" + page.ID})
+		}
+	}
+	if settings.Calendar && h.streamCalendar != nil {
+		nav = append(nav, streamNavItem{Label: "Calendar", URL: base + "/calendar"})
+	}
+	return settings, nav, nil
+}
+
+// StreamIndex renders the stream mode landing page
+// (GET /campaigns/:id/stream).
+func (h *Handler) StreamIndex(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	_, nav, err := h.streamNav(c, cc)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return middleware.Render(c, http.StatusOK, StreamIndexPage(cc, nav))
+}
+
+// StreamPageView renders one picked page in its player projection
+// (GET /campaigns/:id/stream/pages/:eid).
+func (h *Handler) StreamPageView(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	settings, nav, err := h.streamNav(c, cc)
+	if err != nil {
+		return err
+	}
+	notFound := apperror.NewNotFound("page is not in stream mode")
+	if h.streamPages == nil || !settings.HasPage(c.Param("eid")) {
+		return notFound
+	}
+	page, err := h.streamPages(c.Request().Context(), cc.Campaign.ID, c.Param("eid"))
+	if err != nil {
+		return err
+	}
+	if page == nil {
+		return notFound
+	}
+	// External images from allowed hosts load through the campaign's image
+	// proxy, as on the page itself, so the stream never fetches them from
+	// the third party directly.
+	if cfg := cc.Campaign.ParseSettings().GetImageProxy(); h.streamImageSrc != nil && cfg.Enabled {
+		page.EntryHTML = sanitize.RewriteImageSrcHTML(page.EntryHTML, h.streamImageSrc(cc.Campaign.ID, cfg))
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return middleware.Render(c, http.StatusOK, StreamEntityPage(cc, nav, page))
+}
+
+// StreamCalendarView renders the calendar projection
+// (GET /campaigns/:id/stream/calendar).
+func (h *Handler) StreamCalendarView(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	settings, nav, err := h.streamNav(c, cc)
+	if err != nil {
+		return err
+	}
+	if !settings.Calendar || h.streamCalendar == nil {
+		return apperror.NewNotFound("the calendar is not in stream mode")
+	}
+	cal, err := h.streamCalendar(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	if cal == nil {
+		return apperror.NewNotFound("this campaign has no calendar")
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return middleware.Render(c, http.StatusOK, StreamCalendarPage(cc, nav, cal))
+}
+
+// UpdateStreamModeAPI saves the stream mode toggle and picked pages
+// (PUT /campaigns/:id/stream-mode).
+func (h *Handler) UpdateStreamModeAPI(c echo.Context) error {
+	cc := GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if cc.MemberRole < RoleOwner {
+		return apperror.NewForbidden("only campaign owners can change stream mode")
+	}
+
+	var req StreamModeSettings
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateStreamMode(c.Request().Context(), cc.Campaign.ID, req); err != nil {
+		return err
+	}
+
+	h.logAudit(c, cc.Campaign.ID, "campaign.stream_mode.updated", map[string]any{
+		"enabled":  req.Enabled,
+		"calendar": req.Calendar,
+		"pages":    len(req.Pages),
+	})
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,73 @@
+package campaigns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestStreamModeSettings_Normalize(t *testing.T) {
+	s := StreamModeSettings{Enabled: true, Pages: []StreamPageRef{
+		{ID: " e1 ", Name: "One"}, {ID: ""}, {ID: "e1", Name: "Dup"}, {ID: "e2"},
+	}}
+	if err := s.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Pages) != 2 || s.Pages[0].ID != "e1" || s.Pages[0].Name != "One" || s.Pages[1].ID != "e2" {
+		t.Errorf("pages = %+v", s.Pages)
+	}
+	if !s.HasPage("e2") || s.HasPage("e3") {
+		t.Error("HasPage wrong")
+	}
+
+	tooMany := StreamModeSettings{}
+	for i := 0; i <= streamModeMaxPages; i++ {
+		tooMany.Pages = append(tooMany.Pages, StreamPageRef{ID: fmt.Sprintf("e%d", i)})
+	}
+	assertAppError(t, tooMany.Normalize(), 400)
+}
+
+// TestStreamPageView_ProxiesImages checks projected pages get the same
+// image proxy rewrite as the page itself, so the stream doesn't load
+// third-party images directly.
+func TestStreamPageView_ProxiesImages(t *testing.T) {
+	h := NewHandler(nil)
+	h.SetStreamSources(func(_ context.Context, _, entityID string) (*StreamPage, error) {
+		return &StreamPage{ID: entityID, Name: "Harbor", EntryHTML: `<p><img src="https://img.example.com/harbor.png" alt="harbor"></p>`}, nil
+	}, nil)
+	h.SetStreamImageProxy(func(campaignID string, _ ImageProxySettings) func(string) string {
+		return func(src string) string { return "/campaigns/" + campaignID + "/image-proxy?u=" + url.QueryEscape(src) }
+	})
+
+	for _, proxyOn := range []bool{true, false} {
+		settings := `{"stream_mode":{"enabled":true,"pages":[{"id":"e1"}]}}`
+		if proxyOn {
+			settings = `{"stream_mode":{"enabled":true,"pages":[{"id":"e1"}]},"image_proxy":{"enabled":true}}`
+		}
+		req := httptest.NewRequest(http.MethodGet, "/campaigns/c1/stream/pages/e1", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id", "eid")
+		c.SetParamValues("c1", "e1")
+		c.Set("campaign_context", &CampaignContext{
+			Campaign:   &Campaign{ID: "c1", Settings: settings},
+			MemberRole: RoleOwner,
+		})
+		if err := h.StreamPageView(c); err != nil {
+			t.Fatalf("proxy %v: %v", proxyOn, err)
+		}
+		direct := strings.Contains(rec.Body.String(), `src="https://img.example.com/harbor.png"`)
+		if direct == proxyOn {
+			t.Errorf("proxy %v: direct image src present = %v", proxyOn, direct)
+		}
+		if proxied := strings.Contains(rec.Body.String(), "/campaigns/c1/image-proxy?u="); proxied != proxyOn {
+			t.Errorf("proxy %v: proxied image src present = %v", proxyOn, proxied)
+		}
+	}
+}
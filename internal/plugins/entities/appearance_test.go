@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDisplayColor(t *testing.T) {
+	e := &Entity{TypeColor: "#112233"}
+	if got := e.DisplayColor(); got != "#112233" || e.HasColorOverride() {
+		t.Errorf("no override: DisplayColor = %q", got)
+	}
+	e.Appearance = &EntityAppearance{}
+	if got := e.DisplayColor(); got != "#112233" || e.HasColorOverride() {
+		t.Errorf("empty override: DisplayColor = %q", got)
+	}
+	e.Appearance.Color = "#abcdef"
+	if got := e.DisplayColor(); got != "#abcdef" || !e.HasColorOverride() {
+		t.Errorf("override: DisplayColor = %q", got)
+	}
+}
+
+func TestBannerPath(t *testing.T) {
+	banner := "banner.png"
+	if got := (&Entity{}).BannerPath(); got != "" {
+		t.Errorf("no banner = %q", got)
+	}
+	if got := (&Entity{CoverImagePath: &banner}).BannerPath(); got != "banner.png" {
+		t.Errorf("banner = %q", got)
+	}
+}
+
+func TestUpdateAppearance_Validation(t *testing.T) {
+	svc := newTestService(&mockEntityRepo{}, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	assertAppError(t, svc.UpdateAppearance(ctx, "e1", &EntityAppearance{Color: "red"}), 400)
+	assertAppError(t, svc.UpdateAppearance(ctx, "e1", &EntityAppearance{Color: "#12345"}), 400)
+	if err := svc.UpdateAppearance(ctx, "e1", &EntityAppearance{Color: " #a1B2c3 "}); err != nil {
+		t.Errorf("valid color: %v", err)
+	}
+	if err := svc.UpdateAppearance(ctx, "e1", &EntityAppearance{}); err != nil {
+		t.Errorf("clearing: %v", err)
+	}
+}
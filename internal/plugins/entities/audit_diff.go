@@ -0,0 +1,153 @@
+package entities
+
+// audit_diff.go — what an entity edit changed, for the activity feed. The
+// edit handlers keep the entity they loaded before saving, and
+// logEntityChange re-reads it afterwards and stores the difference on the
+// audit entry. Field values carry their GM-only/owner-only restriction as
+// Private so the entity history can hide them from players. The entry is
+// summarized by word count rather than copied into the audit log.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+)
+
+// entitySnapshot captures the values an entity edit can change. defs are
+// the entity's effective field definitions; restricted holds the keys of
+// GM-only and owner-only fields.
+func entitySnapshot(e *Entity, defs []FieldDefinition, restricted map[string]bool, parentName string) []audit.SnapshotField {
+	snap := []audit.SnapshotField{
+		{Key: "name", Label: "Name", Value: e.Name},
+		{Key: "type_label", Label: "Type", Value: derefString(e.TypeLabel)},
+		{Key: "parent", Label: "Parent", Value: entityRef(e.ParentID, parentName)},
+		{Key: "is_private", Label: "Private", Value: e.IsPrivate},
+		// Custom visibility's permission grants aren't captured, so a change
+		// of mode can't be restored from the diff.
+		{Key: "visibility", Label: "Visibility", Value: string(e.Visibility), Partial: true},
+		{Key: "entry", Label: "Entry", Value: entrySummary(e.EntryHTML), Partial: true},
+	}
+
+	labels := make(map[string]string, len(defs))
+	for _, d := range defs {
+		labels[d.Key] = d.Label
+	}
+	keys := make([]string, 0, len(e.FieldsData))
+	for k := range e.FieldsData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		label := labels[k]
+		if label == "" {
+			label = k
+		}
+		snap = append(snap, audit.SnapshotField{
+			Key: "fields." + k, Label: label, Value: e.FieldsData[k], Private: restricted[k],
+		})
+	}
+	return snap
+}
+
+// entityRef is a reference value that stays readable in the feed and
+// revertible by ID; nil when unset.
+func entityRef(id *string, name string) any {
+	if id == nil || *id == "" {
+		return nil
+	}
+	return map[string]any{"id": *id, "name": name}
+}
+
+// entrySummary stands in for the entry in a snapshot: its word count, plus
+// a digest so a same-length rewrite still shows up as a change. Secrets
+// are left out of the text it is computed from.
+func entrySummary(entryHTML *string) any {
+	text := entryPlainText(derefString(entryHTML))
+	words := len(strings.Fields(text))
+	if words == 0 {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(text))
+	return map[string]any{
+		"summary": fmt.Sprintf("%d %s", words, plural(words, "word", "words")),
+		"digest":  hex.EncodeToString(sum[:4]),
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// logEntityChange audits an update to before's entity with the values it
+// changed. The entity is re-read for its new state; when that fails the
+// entry is logged without a diff.
+func (h *Handler) logEntityChange(c echo.Context, campaignID string, before *Entity) {
+	if h.auditSvc == nil {
+		return
+	}
+	ctx := c.Request().Context()
+	after, err := h.service.GetByID(ctx, before.ID)
+	if err != nil {
+		slog.Warn("audit diff: reloading entity failed", slog.String("entity_id", before.ID), slog.Any("error", err))
+		h.logAudit(c, campaignID, audit.ActionEntityUpdated, before.ID, before.Name)
+		return
+	}
+	changes := h.entityChanges(ctx, before, after)
+	h.logAuditWithDetails(c, campaignID, audit.ActionEntityUpdated, after.ID, after.Name, audit.WithChanges(nil, changes))
+}
+
+// entityChanges diffs two states of one entity.
+func (h *Handler) entityChanges(ctx context.Context, before, after *Entity) []audit.Change {
+	defs, restricted := auditFieldSchema(ctx, h.service, after)
+	// Parent names only matter when the parent changed; skip the lookups
+	// otherwise.
+	var beforeParent, afterParent string
+	if derefString(before.ParentID) != derefString(after.ParentID) {
+		beforeParent, afterParent = parentName(ctx, h.service, before.ParentID), parentName(ctx, h.service, after.ParentID)
+	}
+	return audit.Diff(
+		entitySnapshot(before, defs, restricted, beforeParent),
+		entitySnapshot(after, defs, restricted, afterParent),
+	)
+}
+
+// auditFieldSchema returns an entity's effective field definitions and
+// the keys of its GM-only and owner-only fields. Both are empty when the
+// type can't be loaded.
+func auditFieldSchema(ctx context.Context, svc EntityService, e *Entity) ([]FieldDefinition, map[string]bool) {
+	restricted := map[string]bool{}
+	et, err := svc.GetEntityTypeByID(ctx, e.EntityTypeID)
+	if err != nil {
+		return nil, restricted
+	}
+	// Restriction follows the type schema, as in FilterRestrictedFields.
+	for _, d := range et.Fields {
+		if d.GMOnly || d.OwnerOnly {
+			restricted[d.Key] = true
+		}
+	}
+	return MergeFields(et.Fields, e.FieldOverrides), restricted
+}
+
+// parentName looks up a parent entity's name for a snapshot.
+func parentName(ctx context.Context, svc EntityService, parentID *string) string {
+	if parentID == nil || *parentID == "" {
+		return ""
+	}
+	parent, err := svc.GetByID(ctx, *parentID)
+	if err != nil {
+		return ""
+	}
+	return parent.Name
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+)
+
+func TestEntitySnapshotDiff(t *testing.T) {
+	defs := []FieldDefinition{{Key: "hp", Label: "Hit Points"}, {Key: "plot", Label: "Plot", GMOnly: true}}
+	restricted := map[string]bool{"plot": true}
+	parent := "p-1"
+	entry := "<p>The old keep.</p>"
+	before := &Entity{
+		Name: "Keep", EntryHTML: &entry,
+		FieldsData: map[string]any{"hp": 10, "plot": "a trap"},
+	}
+	newEntry := "<p>The ruined keep.</p>"
+	after := &Entity{
+		Name: "Keep", ParentID: &parent, IsPrivate: true, EntryHTML: &newEntry,
+		FieldsData: map[string]any{"hp": 12, "plot": "a bigger trap"},
+	}
+
+	changes := audit.Diff(entitySnapshot(before, defs, restricted, ""), entitySnapshot(after, defs, restricted, "Waterdeep"))
+	byField := make(map[string]audit.Change, len(changes))
+	for _, c := range changes {
+		byField[c.Field] = c
+	}
+	if len(changes) != 5 {
+		t.Fatalf("changes = %+v, want parent, is_private, entry, hp, plot", changes)
+	}
+	if c := byField["parent"]; audit.FormatChangeValue(c.After) != "Waterdeep" || c.Before != nil {
+		t.Errorf("parent change = %+v", c)
+	}
+	// Same word count, different text: still a change, never restorable.
+	if c := byField["entry"]; !c.Partial || audit.FormatChangeValue(c.After) != "3 words" {
+		t.Errorf("entry change = %+v", c)
+	}
+	if c := byField["fields.hp"]; c.Label != "Hit Points" || c.Private {
+		t.Errorf("hp change = %+v", c)
+	}
+	if c := byField["fields.plot"]; !c.Private {
+		t.Errorf("GM-only field change not private: %+v", c)
+	}
+}
+
+// revertEntitySvc serves one entity and captures the Update a revert makes.
+type revertEntitySvc struct {
+	EntityService
+	entity *Entity
+	got    *UpdateEntityInput
+}
+
+func (s *revertEntitySvc) GetByID(_ context.Context, id string) (*Entity, error) {
+	if id != s.entity.ID {
+		return nil, errors.New("not found")
+	}
+	return s.entity, nil
+}
+
+func (s *revertEntitySvc) Update(_ context.Context, _ string, in UpdateEntityInput) (*Entity, error) {
+	s.got = &in
+	return s.entity, nil
+}
+
+func TestAuditReverter_RestoreValues(t *testing.T) {
+	parent, label := "p-1", "Fortress"
+	svc := &revertEntitySvc{entity: &Entity{
+		ID: "e-1", CampaignID: "c-1", Name: "Keep of Doom", TypeLabel: &label, ParentID: &parent,
+		FieldsData: map[string]any{"hp": 12.0, "plot": "a trap"},
+	}}
+	r := NewAuditReverter(svc)
+	ctx := context.Background()
+
+	err := r.RestoreValues(ctx, "c-1", "e-1", map[string]any{
+		"name": "Keep", "is_private": true, "fields.hp": 10.0, "fields.plot": nil,
+	})
+	if err != nil {
+		t.Fatalf("RestoreValues: %v", err)
+	}
+	in := svc.got
+	if in.Name != "Keep" || in.TypeLabel != "Fortress" || in.ParentID != "p-1" || in.IsPrivate == nil || !*in.IsPrivate {
+		t.Errorf("input = %+v", in)
+	}
+	if len(in.FieldsData) != 1 || in.FieldsData["hp"] != 10.0 {
+		t.Errorf("fields = %+v", in.FieldsData)
+	}
+	if svc.entity.FieldsData["plot"] != "a trap" {
+		t.Error("the loaded entity's fields were modified")
+	}
+
+	if err := r.RestoreValues(ctx, "c-1", "e-1", map[string]any{"visibility": "default"}); err == nil {
+		t.Error("visibility should not be revertible")
+	}
+	if err := r.RestoreValues(ctx, "c-2", "e-1", map[string]any{"name": "Keep"}); err == nil {
+		t.Error("entity from another campaign should not be restored")
+	}
+}
@@ -0,0 +1,119 @@
+package entities
+
+// audit_revert.go — restores entity values for the activity feed's revert
+// action. Values arrive keyed and shaped as entitySnapshot stores them and
+// are written back with one Update, so a revert goes through the same
+// validation, slug, and field-history handling as a form save.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+)
+
+// AuditReverter implements audit.ChangeReverter for entities.
+type AuditReverter struct {
+	svc EntityService
+}
+
+// NewAuditReverter creates the entity reverter registered with the audit
+// plugin's revert service.
+func NewAuditReverter(svc EntityService) *AuditReverter {
+	return &AuditReverter{svc: svc}
+}
+
+// CurrentValues snapshots the entity as the audit diffs do.
+func (r *AuditReverter) CurrentValues(ctx context.Context, campaignID, entityID string) (string, []audit.SnapshotField, error) {
+	e, err := r.load(ctx, campaignID, entityID)
+	if err != nil {
+		return "", nil, err
+	}
+	defs, restricted := auditFieldSchema(ctx, r.svc, e)
+	return e.Name, entitySnapshot(e, defs, restricted, parentName(ctx, r.svc, e.ParentID)), nil
+}
+
+// RestoreValues writes the given snapshot values back to the entity. Keys
+// entitySnapshot marks Partial (entry, visibility) are rejected.
+func (r *AuditReverter) RestoreValues(ctx context.Context, campaignID, entityID string, values map[string]any) error {
+	e, err := r.load(ctx, campaignID, entityID)
+	if err != nil {
+		return err
+	}
+
+	isPrivate := e.IsPrivate
+	input := UpdateEntityInput{
+		Name:      e.Name,
+		TypeLabel: derefString(e.TypeLabel),
+		ParentID:  derefString(e.ParentID),
+		IsPrivate: &isPrivate,
+	}
+	for key, v := range values {
+		switch {
+		case key == "name":
+			s, ok := v.(string)
+			if !ok {
+				return revertTypeError(key)
+			}
+			input.Name = s
+		case key == "type_label":
+			s, ok := v.(string)
+			if !ok && v != nil {
+				return revertTypeError(key)
+			}
+			input.TypeLabel = s
+		case key == "parent":
+			input.ParentID = ""
+			if v != nil {
+				ref, ok := v.(map[string]any)
+				id, _ := ref["id"].(string)
+				if !ok || id == "" {
+					return revertTypeError(key)
+				}
+				input.ParentID = id
+			}
+		case key == "is_private":
+			b, ok := v.(bool)
+			if !ok {
+				return revertTypeError(key)
+			}
+			isPrivate = b
+		case strings.HasPrefix(key, "fields."):
+			if input.FieldsData == nil {
+				input.FieldsData = make(map[string]any, len(e.FieldsData))
+				for k, fv := range e.FieldsData {
+					input.FieldsData[k] = fv
+				}
+			}
+			if v == nil {
+				delete(input.FieldsData, strings.TrimPrefix(key, "fields."))
+			} else {
+				input.FieldsData[strings.TrimPrefix(key, "fields.")] = v
+			}
+		default:
+			return apperror.NewBadRequest(fmt.Sprintf("%s can't be reverted", key))
+		}
+	}
+
+	_, err = r.svc.Update(ctx, entityID, input)
+	return err
+}
+
+// load fetches the entity, hiding one from another campaign as missing.
+func (r *AuditReverter) load(ctx context.Context, campaignID, entityID string) (*Entity, error) {
+	e, err := r.svc.GetByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if e.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	return e, nil
+}
+
+// revertTypeError reports a stored value that doesn't fit its field.
+func revertTypeError(key string) error {
+	return apperror.NewBadRequest(fmt.Sprintf("stored value for %s can't be restored", key))
+}
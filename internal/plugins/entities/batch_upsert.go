@@ -0,0 +1,280 @@
+package entities
+
+// batch_upsert.go — all-or-nothing creation and update of many pages at
+// once, for API clients pushing a whole compendium. Every item is
+// validated first (slugs are reserved across the batch so two new pages of
+// the same name can't collide); only when all pass are the rows written,
+// in one transaction. Events, date sync and field history follow the
+// commit exactly as they do for single writes.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// UpsertEntityInput is one page of an UpsertBatch. EntityID names the page
+// to update; empty creates one of EntityTypeID.
+type UpsertEntityInput struct {
+	EntityID          string
+	Name              string
+	EntityTypeID      int // Create only; an update keeps the page's type.
+	TypeLabel         string
+	IsPrivate         bool
+	Entry             string         // HTML; empty keeps an updated page's entry.
+	FieldsData        map[string]any // nil keeps an updated page's fields.
+	ExpectedUpdatedAt *time.Time     // Update only: 409 if the page changed since.
+}
+
+// UpsertEntityResult is the outcome of one UpsertEntityInput. When any
+// item of a batch has Err, nothing was written and Entity is nil.
+type UpsertEntityResult struct {
+	Entity  *Entity
+	Created bool
+	Err     error
+}
+
+// upsertPlan is a validated item awaiting the write.
+type upsertPlan struct {
+	entity       *Entity
+	created      bool
+	oldSlug      string
+	fieldsBefore map[string]any
+}
+
+// UpsertBatch validates every item, then writes them in one transaction.
+// Parents are kept on update and unset on create; callers needing a
+// hierarchy set it with the single-page endpoints afterwards.
+func (s *entityService) UpsertBatch(ctx context.Context, campaignID, userID string, items []UpsertEntityInput) ([]UpsertEntityResult, error) {
+	results := make([]UpsertEntityResult, len(items))
+	if len(items) == 0 {
+		return results, nil
+	}
+	plans := make([]upsertPlan, len(items))
+	types := make(map[int]*EntityType)
+	claimed := make(map[string]bool)
+	seen := make(map[string]bool)
+	failed := false
+	for i, item := range items {
+		if id := strings.TrimSpace(item.EntityID); id != "" {
+			if seen[id] {
+				results[i].Err = apperror.NewBadRequest("entity appears more than once in the batch")
+				failed = true
+				continue
+			}
+			seen[id] = true
+		}
+		plan, err := s.planUpsert(ctx, campaignID, userID, item, types, claimed)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		plans[i] = plan
+	}
+	if failed {
+		return results, nil
+	}
+
+	var creates, updates []*Entity
+	for _, p := range plans {
+		if p.created {
+			creates = append(creates, p.entity)
+		} else {
+			updates = append(updates, p.entity)
+		}
+	}
+	if err := s.entities.UpsertBatch(ctx, creates, updates); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("writing entity batch: %w", err))
+	}
+
+	for i, p := range plans {
+		e := p.entity
+		results[i] = UpsertEntityResult{Entity: e, Created: p.created}
+		if p.created {
+			s.syncEntityDates(ctx, e)
+			s.events.PublishEntityEvent("created", campaignID, e.ID, e)
+			continue
+		}
+		if p.fieldsBefore != nil {
+			s.fieldHistory.RecordFieldChanges(ctx, e, p.fieldsBefore)
+		}
+		s.syncEntityDates(ctx, e)
+		if e.Slug != p.oldSlug {
+			if err := s.entities.RecordSlugChange(ctx, campaignID, e.ID, p.oldSlug, e.Slug); err != nil {
+				slog.Warn("recording retired entity slug failed",
+					slog.String("entity_id", e.ID), slog.String("slug", p.oldSlug), slog.Any("error", err))
+			}
+		}
+		s.events.PublishEntityEvent("updated", campaignID, e.ID, e)
+	}
+	if len(plans) > 0 {
+		s.hierarchy.HierarchyChanged(campaignID)
+	}
+	slog.Info("entity batch upserted",
+		slog.String("campaign_id", campaignID),
+		slog.Int("created", len(creates)),
+		slog.Int("updated", len(updates)),
+	)
+	return results, nil
+}
+
+// planUpsert validates one item and builds the row it will write.
+func (s *entityService) planUpsert(ctx context.Context, campaignID, userID string, item UpsertEntityInput, types map[int]*EntityType, claimed map[string]bool) (upsertPlan, error) {
+	name := strings.TrimSpace(item.Name)
+	if name == "" {
+		return upsertPlan{}, apperror.NewBadRequest("entity name is required")
+	}
+	if len(name) > 200 {
+		return upsertPlan{}, apperror.NewBadRequest("entity name must be at most 200 characters")
+	}
+	var typeLabel *string
+	if tl := strings.TrimSpace(item.TypeLabel); tl != "" {
+		typeLabel = &tl
+	}
+
+	if id := strings.TrimSpace(item.EntityID); id != "" {
+		entity, err := s.entities.FindByID(ctx, id)
+		if err != nil || entity.CampaignID != campaignID {
+			return upsertPlan{}, apperror.NewNotFound("entity not found")
+		}
+		if item.ExpectedUpdatedAt != nil && entity.UpdatedAt.After(*item.ExpectedUpdatedAt) {
+			return upsertPlan{}, apperror.NewConflict("entity was modified by another user; refresh and retry")
+		}
+		plan := upsertPlan{entity: entity, oldSlug: entity.Slug}
+		if name != entity.Name && !entity.SlugCustom {
+			slug := Slugify(name)
+			owner, err := s.entities.FindSlugOwner(ctx, campaignID, slug)
+			if err != nil {
+				return upsertPlan{}, apperror.NewInternal(err)
+			}
+			if owner != entity.ID || claimed[slug] {
+				if slug, err = s.batchSlug(ctx, campaignID, name, claimed); err != nil {
+					return upsertPlan{}, err
+				}
+			}
+			entity.Slug = slug
+			claimed[slug] = true
+		}
+		entity.Name = name
+		entity.TypeLabel = typeLabel
+		entity.IsPrivate = item.IsPrivate
+		if entry := strings.TrimSpace(item.Entry); entry != "" {
+			sanitized := sanitize.HTML(entry)
+			entity.Entry, entity.EntryHTML = &entry, &sanitized
+		}
+		if item.FieldsData != nil {
+			if err := s.validateEntityDateFields(ctx, entity, item.FieldsData); err != nil {
+				return upsertPlan{}, err
+			}
+			plan.fieldsBefore = entity.FieldsData
+			entity.FieldsData = item.FieldsData
+		}
+		entity.UpdatedAt = time.Now().UTC()
+		return plan, nil
+	}
+
+	if item.EntityTypeID == 0 {
+		return upsertPlan{}, apperror.NewBadRequest("entity_type_id is required")
+	}
+	et, ok := types[item.EntityTypeID]
+	if !ok {
+		found, err := s.types.FindByID(ctx, item.EntityTypeID)
+		if err == nil && found.CampaignID == campaignID {
+			et = found
+		}
+		types[item.EntityTypeID] = et
+	}
+	if et == nil {
+		return upsertPlan{}, apperror.NewBadRequest("invalid entity type")
+	}
+	fieldsData := item.FieldsData
+	if fieldsData == nil {
+		fieldsData = make(map[string]any)
+	}
+	if err := s.validateDateFields(ctx, campaignID, et.Fields, fieldsData, nil); err != nil {
+		return upsertPlan{}, err
+	}
+	slug, err := s.batchSlug(ctx, campaignID, name, claimed)
+	if err != nil {
+		return upsertPlan{}, err
+	}
+	claimed[slug] = true
+
+	now := time.Now().UTC()
+	entity := &Entity{
+		ID:           generateUUID(),
+		CampaignID:   campaignID,
+		EntityTypeID: et.ID,
+		Name:         name,
+		Slug:         slug,
+		TypeLabel:    typeLabel,
+		IsPrivate:    item.IsPrivate,
+		Status:       StatusPublished,
+		FieldsData:   fieldsData,
+		CreatedBy:    userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		TypeName:     et.Name,
+		TypeIcon:     et.Icon,
+		TypeColor:    et.Color,
+		TypeSlug:     et.Slug,
+	}
+	if entry := strings.TrimSpace(item.Entry); entry != "" {
+		sanitized := sanitize.HTML(entry)
+		entity.Entry, entity.EntryHTML = &entry, &sanitized
+	}
+	return upsertPlan{entity: entity, created: true}, nil
+}
+
+// batchSlug is generateSlug that also skips slugs reserved earlier in the
+// batch.
+func (s *entityService) batchSlug(ctx context.Context, campaignID, name string, claimed map[string]bool) (string, error) {
+	base := Slugify(name)
+	slug := base
+	for i := 2; i < maxSlugAttempts+len(claimed)+2; i++ {
+		if !claimed[slug] {
+			exists, err := s.entities.SlugExists(ctx, campaignID, slug)
+			if err != nil {
+				return "", apperror.NewInternal(fmt.Errorf("checking slug: %w", err))
+			}
+			if !exists {
+				return slug, nil
+			}
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", apperror.NewInternal(fmt.Errorf("generating random slug suffix: %w", err))
+	}
+	return fmt.Sprintf("%s-%s", base, hex.EncodeToString(b)), nil
+}
+
+// UpsertBatch writes a validated batch in one transaction.
+func (r *entityRepository) UpsertBatch(ctx context.Context, creates, updates []*Entity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, e := range creates {
+		if err := insertEntity(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+	for _, e := range updates {
+		if err := updateEntity(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
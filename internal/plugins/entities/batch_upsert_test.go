@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// batchUpsertRepos returns a type repo with one "item" category in camp-1
+// and an entity repo holding one existing page, recording the batch write.
+func batchUpsertRepos(written *[][]*Entity) (*mockEntityRepo, *mockEntityTypeRepo) {
+	typeRepo := &mockEntityTypeRepo{
+		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
+			if id != 4 {
+				return nil, apperror.NewNotFound("entity type not found")
+			}
+			return &EntityType{ID: 4, CampaignID: "camp-1", Slug: "item", Name: "Item"}, nil
+		},
+	}
+	entityRepo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			if id != "ent-1" {
+				return nil, apperror.NewNotFound("entity not found")
+			}
+			return &Entity{ID: "ent-1", CampaignID: "camp-1", EntityTypeID: 4, Name: "Rope", Slug: "rope",
+				UpdatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}, nil
+		},
+		slugExistsFn: func(_ context.Context, _, slug string) (bool, error) {
+			return slug == "rope", nil
+		},
+		upsertBatchFn: func(_ context.Context, creates, updates []*Entity) error {
+			*written = [][]*Entity{creates, updates}
+			return nil
+		},
+	}
+	return entityRepo, typeRepo
+}
+
+func TestUpsertBatch_WritesAllItems(t *testing.T) {
+	var written [][]*Entity
+	svc := newTestService(batchUpsertRepos(&written))
+
+	results, err := svc.UpsertBatch(context.Background(), "camp-1", "user-1", []UpsertEntityInput{
+		{Name: "Dagger", EntityTypeID: 4, Entry: "<p>Sharp.</p><script>x</script>"},
+		{Name: "Dagger", EntityTypeID: 4},
+		{EntityID: "ent-1", Name: "Silk Rope", FieldsData: map[string]any{"weight": "5 lb"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, r := range results {
+		if r.Err != nil || r.Entity == nil {
+			t.Fatalf("result %d = %+v", i, r)
+		}
+	}
+	if len(written) != 2 || len(written[0]) != 2 || len(written[1]) != 1 {
+		t.Fatalf("expected 2 creates and 1 update in one write, got %v", written)
+	}
+	// Same-named pages in one batch get distinct slugs.
+	if results[0].Entity.Slug != "dagger" || results[1].Entity.Slug != "dagger-2" {
+		t.Errorf("slugs = %q, %q", results[0].Entity.Slug, results[1].Entity.Slug)
+	}
+	if !results[0].Created || results[2].Created {
+		t.Errorf("created flags = %v, %v", results[0].Created, results[2].Created)
+	}
+	if html := *results[0].Entity.EntryHTML; html != "<p>Sharp.</p>" {
+		t.Errorf("entry HTML not sanitized: %q", html)
+	}
+	if u := results[2].Entity; u.Slug != "silk-rope" || u.FieldsData["weight"] != "5 lb" {
+		t.Errorf("updated page = %+v", u)
+	}
+}
+
+func TestUpsertBatch_InvalidItemWritesNothing(t *testing.T) {
+	var written [][]*Entity
+	svc := newTestService(batchUpsertRepos(&written))
+
+	stale := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	results, err := svc.UpsertBatch(context.Background(), "camp-1", "user-1", []UpsertEntityInput{
+		{Name: "Dagger", EntityTypeID: 4},
+		{Name: "Ghost", EntityTypeID: 9},
+		{EntityID: "ent-1", Name: "Rope", ExpectedUpdatedAt: &stale},
+		{EntityID: "ent-1", Name: "Rope"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != nil {
+		t.Fatalf("expected no write, got %v", written)
+	}
+	if results[0].Err != nil || results[0].Entity != nil {
+		t.Errorf("valid item should be unwritten without error, got %+v", results[0])
+	}
+	for i, code := range map[int]int{1: 400, 2: 409, 3: 400} {
+		appErr, ok := results[i].Err.(*apperror.AppError)
+		if !ok || appErr.Code != code {
+			t.Errorf("result %d err = %v, want %d", i, results[i].Err, code)
+		}
+	}
+}
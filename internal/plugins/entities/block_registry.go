@@ -149,6 +149,14 @@ func (r *BlockRegistry) Types() []BlockMeta {
 	return result
 }
 
+// Meta returns a registered block type's metadata.
+func (r *BlockRegistry) Meta(blockType string) (BlockMeta, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[blockType]
+	return entry.meta, ok
+}
+
 // AddonChecker tests whether an addon slug is enabled for a campaign.
 // Matches the existing AddonChecker interface in handler.go.
 type blockAddonChecker interface {
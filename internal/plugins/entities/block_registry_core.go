@@ -147,10 +147,10 @@ func RegisterCoreBlocks(r *BlockRegistry) {
 
 	r.Register(BlockMeta{
 		Type: "inventory", Label: "Inventory", Icon: "fa-shield-halved",
-		Description: "Character inventory — items with quantity, equipped, and attuned",
+		Description: "Items held by a character or place — quantity, equipped, attuned, and transfers",
 		Addon: "armory", Contexts: []string{"template"},
 	}, func(ctx BlockRenderContext) templ.Component {
-		return blockInventory(ctx.CC, ctx.Entity, ctx.CSRFToken)
+		return blockInventory(ctx.CC, ctx.Entity, ctx.CSRFToken, ctx.UserID)
 	})
 
 	r.Register(BlockMeta{
@@ -369,6 +369,15 @@ func RegisterCoreBlocks(r *BlockRegistry) {
 		Addon: "foundry", Contexts: []string{"dashboard"},
 	}, nil)
 
+	r.Register(BlockMeta{
+		Type: "faction_standings", Label: "Faction Standings", Icon: "fa-scale-balanced",
+		Description: "Current standing with each faction",
+		Contexts: []string{"dashboard"},
+		ConfigFields: []ConfigFieldMeta{
+			{Key: "limit", Label: "Factions to show", Type: "number", Min: IntPtr(1), Max: IntPtr(20), Default: 6},
+		},
+	}, nil)
+
 	// Category dashboard blocks — only available in category dashboard context.
 	r.Register(BlockMeta{
 		Type: "category_header", Label: "Category Header", Icon: "fa-heading",
@@ -0,0 +1,73 @@
+// breadcrumbs.go — schema.org BreadcrumbList structured data for entity
+// pages in public campaigns. Mirrors the visible breadcrumb trail (campaign,
+// category, ancestor chain, page) so search engines show the same hierarchy
+// readers see.
+package entities
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// BreadcrumbList is the JSON-LD document rendered into the show page head.
+type BreadcrumbList struct {
+	Context string           `json:"@context"`
+	Type    string           `json:"@type"`
+	Items   []BreadcrumbItem `json:"itemListElement"`
+}
+
+// BreadcrumbItem is one crumb. Position is 1-based; Item is an absolute URL.
+type BreadcrumbItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+}
+
+// BuildBreadcrumbs assembles the trail for an entity. ancestors is ordered
+// as GetAncestors returns it: immediate parent first. Entity crumbs use the
+// shareable slug URLs; the category crumb uses the type dashboard.
+func BuildBreadcrumbs(baseURL string, campaign *campaigns.Campaign, entity *Entity, ancestors []Entity) *BreadcrumbList {
+	baseURL = strings.TrimRight(baseURL, "/")
+	list := &BreadcrumbList{Context: "https://schema.org", Type: "BreadcrumbList"}
+	add := func(name, path string) {
+		list.Items = append(list.Items, BreadcrumbItem{
+			Type:     "ListItem",
+			Position: len(list.Items) + 1,
+			Name:     name,
+			Item:     baseURL + path,
+		})
+	}
+
+	add(campaign.Name, "/campaigns/"+campaign.ID)
+	if entity.TypeSlug != "" {
+		label := entity.TypeNamePlural
+		if label == "" {
+			label = entity.TypeName + "s"
+		}
+		add(label, "/campaigns/"+campaign.ID+"/"+entity.TypeSlug)
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		add(ancestors[i].Name, EntitySlugPath(campaign.Slug, ancestors[i].Slug))
+	}
+	add(entity.Name, EntitySlugPath(campaign.Slug, entity.Slug))
+	return list
+}
+
+// breadcrumbsKey is the context key for the request's breadcrumb data.
+type breadcrumbsKey struct{}
+
+// WithBreadcrumbs returns a context carrying the page's structured
+// breadcrumbs for the show template.
+func WithBreadcrumbs(ctx context.Context, list *BreadcrumbList) context.Context {
+	return context.WithValue(ctx, breadcrumbsKey{}, list)
+}
+
+// GetBreadcrumbs returns the breadcrumbs injected for this request, or nil
+// for a private campaign (nothing to index, so nothing rendered).
+func GetBreadcrumbs(ctx context.Context) *BreadcrumbList {
+	list, _ := ctx.Value(breadcrumbsKey{}).(*BreadcrumbList)
+	return list
+}
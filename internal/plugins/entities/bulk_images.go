@@ -0,0 +1,444 @@
+package entities
+
+// bulk_images.go — assign a ZIP of images (token packs, art libraries) to
+// pages by matching file names to page names. Two steps, both posting the
+// same form with the ZIP:
+//
+//	POST /campaigns/:id/entities/bulk-images/preview  match and show the review table
+//	POST /campaigns/:id/entities/bulk-images/apply    upload and assign what was confirmed
+//
+// Nothing is stored until apply, so an abandoned review leaves no orphaned
+// media behind. Matching ignores case, punctuation, separators and common
+// token-pack noise ("goblin_boss-token_02.png" finds "Goblin Boss"), and a
+// page's aliases count as its names.
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+const (
+	// maxBulkImageZipSize caps the uploaded ZIP.
+	maxBulkImageZipSize = 100 << 20
+	// maxBulkImageFiles caps the images read from one ZIP.
+	maxBulkImageFiles = 500
+	// maxBulkImageFileSize caps one image inside the ZIP; the media
+	// service applies the site's own upload limit on top.
+	maxBulkImageFileSize = 20 << 20
+	// bulkImageMinScore is the lowest match score suggested in review.
+	bulkImageMinScore = 60
+)
+
+// bulkImageExts are the file types read from the ZIP.
+var bulkImageExts = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".webp": "image/webp",
+	".gif":  "image/gif",
+}
+
+// bulkImageNoise are words token packs add to file names that never appear
+// in the page name.
+var bulkImageNoise = map[string]bool{
+	"token": true, "tokens": true, "portrait": true, "art": true, "img": true,
+	"image": true, "icon": true, "avatar": true, "final": true, "topdown": true,
+}
+
+// BulkImageUploader stores an image for a page header. Implemented by an
+// adapter over the media service.
+type BulkImageUploader interface {
+	UploadEntityImage(ctx context.Context, campaignID, userID string, data []byte, originalName, mimeType string) (string, error)
+}
+
+// SetBulkImageUploader enables the bulk image tool.
+func (h *Handler) SetBulkImageUploader(u BulkImageUploader) {
+	h.bulkImageUploader = u
+}
+
+// bulkImageFile is one image read from the ZIP.
+type bulkImageFile struct {
+	Name string // path inside the ZIP
+	Mime string
+	Data []byte
+}
+
+// BulkImageMatch is the review row for one image: the suggested page, if
+// any, and how sure the match is (0–100).
+type BulkImageMatch struct {
+	File       string
+	EntityID   string
+	EntityName string
+	Score      int
+}
+
+// BulkImageResult reports what apply did.
+type BulkImageResult struct {
+	Assigned int
+	Skipped  []BulkImageSkip
+}
+
+// BulkImageSkip is an image apply didn't assign, and why.
+type BulkImageSkip struct {
+	File   string
+	Reason string
+}
+
+// readBulkImageZip reads the uploaded ZIP from the form's "file" field.
+func readBulkImageZip(c echo.Context) ([]bulkImageFile, error) {
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxBulkImageZipSize+1<<20)
+	file, err := c.FormFile("file")
+	if err != nil {
+		return nil, apperror.NewBadRequest("choose a ZIP file of images")
+	}
+	if file.Size > maxBulkImageZipSize {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("ZIP too large, maximum %d MB", maxBulkImageZipSize>>20))
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("open uploaded zip: %w", err))
+	}
+	defer func() { _ = src.Close() }()
+	data, err := io.ReadAll(io.LimitReader(src, maxBulkImageZipSize+1))
+	if err != nil {
+		return nil, apperror.NewBadRequest("could not read the uploaded file")
+	}
+	if len(data) > maxBulkImageZipSize {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("ZIP too large, maximum %d MB", maxBulkImageZipSize>>20))
+	}
+	return parseBulkImageZip(data)
+}
+
+// parseBulkImageZip returns the images in a ZIP, sorted by path. Folders,
+// hidden files and macOS resource forks are skipped.
+func parseBulkImageZip(data []byte) ([]bulkImageFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, apperror.NewBadRequest("file is not a valid ZIP")
+	}
+	var files []bulkImageFile
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(path.Base(entry.Name), ".") {
+			continue
+		}
+		mime, ok := bulkImageExts[strings.ToLower(path.Ext(entry.Name))]
+		if !ok {
+			continue
+		}
+		if len(files) == maxBulkImageFiles {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("ZIP has more than %d images; split it up", maxBulkImageFiles))
+		}
+		if entry.UncompressedSize64 > maxBulkImageFileSize {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("%s is larger than %d MB", entry.Name, maxBulkImageFileSize>>20))
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("could not read %s from the ZIP", entry.Name))
+		}
+		b, err := io.ReadAll(io.LimitReader(rc, maxBulkImageFileSize+1))
+		_ = rc.Close()
+		if err != nil || len(b) > maxBulkImageFileSize {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("could not read %s from the ZIP", entry.Name))
+		}
+		files = append(files, bulkImageFile{Name: entry.Name, Mime: mime, Data: b})
+	}
+	if len(files) == 0 {
+		return nil, apperror.NewBadRequest("the ZIP has no PNG, JPEG, WebP or GIF images")
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// normalizeImageName reduces a file or page name to lowercase words:
+// extension and folders dropped, separators and punctuation turned into
+// spaces, camelCase split, and token-pack noise removed from file names.
+func normalizeImageName(name string, isFile bool) string {
+	if isFile {
+		name = path.Base(name)
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			b.WriteRune(' ')
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+		prev = r
+	}
+	words := strings.Fields(b.String())
+	if isFile {
+		kept := words[:0]
+		for _, w := range words {
+			if !bulkImageNoise[w] {
+				kept = append(kept, w)
+			}
+		}
+		words = kept
+	}
+	return strings.Join(words, " ")
+}
+
+// trimTrailingNumbers drops the variant numbers packs append
+// ("goblin boss 02" → "goblin boss"), keeping at least one word.
+func trimTrailingNumbers(name string) string {
+	words := strings.Fields(name)
+	for len(words) > 1 && isAllDigits(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// isAllDigits reports whether s is a non-empty run of digits.
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// imageNameScore rates how well a normalized file name matches a
+// normalized page name, 0–100: identical names score 100, otherwise edit
+// distance relative to the longer name, raised when every word of the
+// shorter one appears in the other ("goblin" → "goblin boss").
+func imageNameScore(file, name string) int {
+	if file == "" || name == "" {
+		return 0
+	}
+	if file == name {
+		return 100
+	}
+	a, b := []rune(file), []rune(name)
+	longest := max(len(a), len(b))
+	score := 100 * (longest - levenshtein(a, b)) / longest
+	if containsWords(file, name) || containsWords(name, file) {
+		score = max(score, 75)
+	}
+	return min(score, 99)
+}
+
+// containsWords reports whether every word of inner is a word of outer.
+func containsWords(outer, inner string) bool {
+	have := make(map[string]bool)
+	for _, w := range strings.Fields(outer) {
+		have[w] = true
+	}
+	for _, w := range strings.Fields(inner) {
+		if !have[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// levenshtein is the edit distance between two rune slices.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// matchBulkImages suggests a page for each file. Alias entries match as
+// their page; the row shows the page's own name.
+func matchBulkImages(files []string, names []EntityNameEntry) []BulkImageMatch {
+	display := make(map[string]string, len(names))
+	for _, n := range names {
+		if !n.IsAlias {
+			display[n.ID] = n.Name
+		}
+	}
+	normalized := make([]string, len(names))
+	for i, n := range names {
+		normalized[i] = normalizeImageName(n.Name, false)
+	}
+
+	matches := make([]BulkImageMatch, len(files))
+	for i, f := range files {
+		m := BulkImageMatch{File: f}
+		// Try the name with and without trailing numbers, so "Room 101"
+		// and "goblin_02" both find their page.
+		key := normalizeImageName(f, true)
+		trimmed := trimTrailingNumbers(key)
+		for j, n := range names {
+			s := max(imageNameScore(key, normalized[j]), imageNameScore(trimmed, normalized[j]))
+			if s > m.Score {
+				m.Score, m.EntityID = s, n.ID
+			}
+		}
+		if m.Score < bulkImageMinScore {
+			m.Score, m.EntityID = 0, ""
+		}
+		m.EntityName = display[m.EntityID]
+		matches[i] = m
+	}
+	return matches
+}
+
+// bulkImagesContext checks the tool is wired and returns the campaign.
+func (h *Handler) bulkImagesContext(c echo.Context) (*campaigns.CampaignContext, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, apperror.NewMissingContext()
+	}
+	if h.bulkImageUploader == nil {
+		return nil, apperror.NewNotFound("bulk image assignment is not available")
+	}
+	return cc, nil
+}
+
+// BulkImagesPage renders the upload form.
+// GET /campaigns/:id/entities/bulk-images
+func (h *Handler) BulkImagesPage(c echo.Context) error {
+	cc, err := h.bulkImagesContext(c)
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, BulkImagesPage(cc, middleware.GetCSRFToken(c)))
+}
+
+// BulkImagesPreview matches the ZIP's images to pages and returns the
+// review table. Nothing is stored.
+// POST /campaigns/:id/entities/bulk-images/preview
+func (h *Handler) BulkImagesPreview(c echo.Context) error {
+	cc, err := h.bulkImagesContext(c)
+	if err != nil {
+		return err
+	}
+	files, err := readBulkImageZip(c)
+	if err != nil {
+		return err
+	}
+	names, err := h.service.ListSwitcherNames(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Name
+	}
+	options := make([]EntityNameEntry, 0, len(names))
+	for _, n := range names {
+		if !n.IsAlias {
+			options = append(options, n)
+		}
+	}
+	sort.Slice(options, func(i, j int) bool { return strings.ToLower(options[i].Name) < strings.ToLower(options[j].Name) })
+	return middleware.Render(c, http.StatusOK, BulkImagesReview(cc, matchBulkImages(paths, names), options))
+}
+
+// BulkImagesApply uploads the confirmed images and sets them as page
+// header images. The form pairs each "file_name" with an "entity_id"
+// ("" = skip); pages that already have an image keep it unless
+// "replace" is set.
+// POST /campaigns/:id/entities/bulk-images/apply
+func (h *Handler) BulkImagesApply(c echo.Context) error {
+	cc, err := h.bulkImagesContext(c)
+	if err != nil {
+		return err
+	}
+	files, err := readBulkImageZip(c)
+	if err != nil {
+		return err
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return apperror.NewBadRequest("invalid form")
+	}
+	fileNames, entityIDs := form.Value["file_name"], form.Value["entity_id"]
+	if len(fileNames) != len(entityIDs) {
+		return apperror.NewBadRequest("review form is out of date; preview the ZIP again")
+	}
+	replace := c.FormValue("replace") == "1"
+
+	byName := make(map[string]bulkImageFile, len(files))
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	result := BulkImageResult{}
+	taken := make(map[string]string) // entity ID -> file assigned to it
+	for i, name := range fileNames {
+		eid := entityIDs[i]
+		if eid == "" {
+			continue
+		}
+		f, ok := byName[name]
+		if !ok {
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: "not in the ZIP"})
+			continue
+		}
+		if other, dup := taken[eid]; dup {
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: "page already gets " + path.Base(other)})
+			continue
+		}
+		entity, err := h.service.GetByID(ctx, eid)
+		if err != nil || entity.CampaignID != cc.Campaign.ID {
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: "page not found"})
+			continue
+		}
+		if !replace && entity.ImagePath != nil && *entity.ImagePath != "" {
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: entity.Name + " already has an image"})
+			continue
+		}
+
+		imagePath, err := h.bulkImageUploader.UploadEntityImage(ctx, cc.Campaign.ID, userID, f.Data, path.Base(f.Name), f.Mime)
+		if err != nil {
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: apperror.SafeMessage(err)})
+			continue
+		}
+		// The page name is the alt text: good enough for a portrait, and
+		// public campaigns require one.
+		if err := h.service.UpdateImage(ctx, entity.ID, imagePath, ImageTextInput{AltText: entity.Name}, cc.Campaign.IsPublic); err != nil {
+			slog.Warn("bulk image assign failed", slog.String("entity_id", entity.ID), slog.Any("error", err))
+			result.Skipped = append(result.Skipped, BulkImageSkip{File: name, Reason: apperror.SafeMessage(err)})
+			continue
+		}
+		taken[eid] = name
+		result.Assigned++
+		h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, entity.ID, entity.Name, map[string]any{
+			"image": "bulk assigned from " + path.Base(name),
+		})
+	}
+	return middleware.Render(c, http.StatusOK, BulkImagesResult(cc, result))
+}
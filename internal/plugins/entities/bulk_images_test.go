@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestNormalizeImageName(t *testing.T) {
+	cases := []struct {
+		in     string
+		isFile bool
+		want   string
+	}{
+		{"tokens/Goblin_Boss-token_02.png", true, "goblin boss 02"},
+		{"OldManWillow.webp", true, "old man willow"},
+		{"Room 101.jpg", true, "room 101"},
+		{"portrait.png", true, ""},
+		{"Sir Kay, the Seneschal", false, "sir kay the seneschal"},
+	}
+	for _, tc := range cases {
+		if got := normalizeImageName(tc.in, tc.isFile); got != tc.want {
+			t.Errorf("normalizeImageName(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+	if got := trimTrailingNumbers("goblin boss 02"); got != "goblin boss" {
+		t.Errorf("trimTrailingNumbers = %q", got)
+	}
+	if got := trimTrailingNumbers("101"); got != "101" {
+		t.Errorf("trimTrailingNumbers kept nothing: %q", got)
+	}
+}
+
+func TestMatchBulkImages(t *testing.T) {
+	names := []EntityNameEntry{
+		{ID: "e1", Name: "Goblin Boss"},
+		{ID: "e2", Name: "Gandalf"},
+		{ID: "e2", Name: "Mithrandir", IsAlias: true},
+		{ID: "e3", Name: "Rivendell"},
+		{ID: "e4", Name: "Room 101"},
+		{ID: "e5", Name: "Room 102"},
+	}
+	got := matchBulkImages([]string{
+		"goblin_boss.png",
+		"Gandolf.jpg",
+		"mithrandir-token.png",
+		"rivendell_map_03.png",
+		"Room 101.png",
+		"dragon.png",
+	}, names)
+
+	want := []struct {
+		id    string
+		name  string
+		exact bool
+	}{
+		{"e1", "Goblin Boss", true},
+		{"e2", "Gandalf", false},
+		{"e2", "Gandalf", true}, // alias shows the page's own name
+		{"e3", "Rivendell", false},
+		{"e4", "Room 101", true},
+		{"", "", false},
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.EntityID != w.id || m.EntityName != w.name {
+			t.Errorf("%s matched %q (%q), want %q (%q)", m.File, m.EntityID, m.EntityName, w.id, w.name)
+		}
+		if (m.Score == 100) != w.exact {
+			t.Errorf("%s score = %d, exact want %v", m.File, m.Score, w.exact)
+		}
+	}
+}
+
+func TestParseBulkImageZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"b.png", "a/Goblin.JPG", "__MACOSX/a/._Goblin.JPG", ".DS_Store", "readme.txt"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte("data"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := parseBulkImageZip(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Name != "a/Goblin.JPG" || files[0].Mime != "image/jpeg" || files[1].Name != "b.png" {
+		t.Fatalf("files = %+v", files)
+	}
+
+	_, err = parseBulkImageZip([]byte("not a zip"))
+	assertAppError(t, err, 400)
+}
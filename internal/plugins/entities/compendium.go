@@ -0,0 +1,923 @@
+package entities
+
+// compendium.go — create pages from published game content: D&D 5e
+// monsters, spells and magic items found by searching Open5e or read from
+// a 5eTools JSON file. Same two-step shape as the bulk image tool, both
+// posting the same form:
+//
+//	POST /campaigns/:id/entities/compendium/preview  search or read the file and list what was found
+//	POST /campaigns/:id/entities/compendium/apply    create (or refresh) the checked entries
+//
+// Stats are matched to the target category's fields the way the statblock
+// import matches them; whatever no field takes goes into the page text
+// with the description and a line naming the source document and its
+// licence. Every page made is recorded by source key, so importing the
+// same entry again finds that page instead of creating a duplicate.
+// Entries not published under an open licence are imported as private
+// pages, for the GM's table only.
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Compendium entry kinds.
+const (
+	CompendiumMonster = "monster"
+	CompendiumSpell   = "spell"
+	CompendiumItem    = "item"
+)
+
+// Compendium sources.
+const (
+	CompendiumOpen5e  = "open5e"
+	Compendium5eTools = "5etools"
+)
+
+const (
+	// maxCompendiumFileSize caps an uploaded 5eTools file; the largest
+	// official bestiary files are a few megabytes.
+	maxCompendiumFileSize = 20 << 20
+	// maxCompendiumEntries caps the entries listed for review.
+	maxCompendiumEntries = 1000
+	// maxCompendiumApply caps the pages one apply creates or refreshes.
+	maxCompendiumApply = 200
+)
+
+// compendiumKinds lists the kinds in display order.
+var compendiumKinds = []string{CompendiumMonster, CompendiumSpell, CompendiumItem}
+
+// compendiumKindNames labels each kind.
+var compendiumKindNames = map[string]string{
+	CompendiumMonster: "Monsters",
+	CompendiumSpell:   "Spells",
+	CompendiumItem:    "Magic Items",
+}
+
+// compendiumTypeHints are category slugs and names, normalized, that
+// suggest where each kind goes, best first.
+var compendiumTypeHints = map[string][]string{
+	CompendiumMonster: {"monster", "monsters", "creature", "creatures", "bestiary", "npc", "npcs"},
+	CompendiumSpell:   {"spell", "spells", "magic", "spellbook"},
+	CompendiumItem:    {"item", "items", "magicitem", "magicitems", "equipment", "loot", "treasure"},
+}
+
+// compendiumFields are the values spells and items carry on top of the
+// statblock fields. Source and licence map onto fields of the same name
+// when the category has them; the page text names them regardless.
+var compendiumFields = []statblockField{
+	{Key: "spell_level", Label: "Spell Level", Aliases: []string{"level", "spelllevel"}},
+	{Key: "school", Label: "School", Aliases: []string{"school", "schoolofmagic"}},
+	{Key: "casting_time", Label: "Casting Time", Aliases: []string{"castingtime", "casttime"}},
+	{Key: "range", Label: "Range", Aliases: []string{"range"}},
+	{Key: "components", Label: "Components", Aliases: []string{"components"}},
+	{Key: "duration", Label: "Duration", Aliases: []string{"duration"}},
+	{Key: "classes", Label: "Classes", Aliases: []string{"classes", "class", "spelllists"}},
+	{Key: "item_type", Label: "Item Type", Aliases: []string{"itemtype", "type", "category"}},
+	{Key: "rarity", Label: "Rarity", Aliases: []string{"rarity"}},
+	{Key: "attunement", Label: "Attunement", Aliases: []string{"attunement", "requiresattunement"}},
+	{Key: "source", Label: "Source", Aliases: []string{"source", "sourcebook", "book"}},
+	{Key: "license", Label: "License", Aliases: []string{"license", "licence"}},
+}
+
+// compendiumFieldByKey indexes compendiumFields.
+var compendiumFieldByKey = func() map[string]statblockField {
+	m := make(map[string]statblockField, len(compendiumFields))
+	for _, f := range compendiumFields {
+		m[f.Key] = f
+	}
+	return m
+}()
+
+// importFieldSpec describes an imported value, statblock or compendium.
+func importFieldSpec(key string) statblockField {
+	if f, ok := statblockFieldByKey[key]; ok {
+		return f
+	}
+	return compendiumFieldByKey[key]
+}
+
+// CompendiumEntry is one monster, spell or item read from a source.
+type CompendiumEntry struct {
+	Key     string // stable across imports, e.g. "open5e:monster:goblin"
+	Kind    string
+	Name    string
+	Values  []StatblockValue
+	Text    string // description; blank lines separate paragraphs
+	Source  string // publishing document, e.g. "5e Core Rules (SRD 5.1)"
+	License string // licence name or URL; "" when the source doesn't say
+	Open    bool   // published under an open licence (OGL, CC-BY)
+}
+
+// addValue appends a non-empty value under its field label.
+func (e *CompendiumEntry) addValue(key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "-" {
+		return
+	}
+	e.Values = append(e.Values, StatblockValue{Key: key, Label: importFieldSpec(key).Label, Value: value})
+}
+
+// attribution is the line the page text ends with.
+func (e *CompendiumEntry) attribution() string {
+	line := "Source: " + e.Source + "."
+	switch {
+	case e.License != "":
+		line += " License: " + e.License + "."
+	case !e.Open:
+		line += " Not published under an open license; for use at your own table."
+	}
+	return line
+}
+
+// abilityModifier is the 5e modifier for an ability score.
+func abilityModifier(score int) int {
+	return int(math.Floor(float64(score-10) / 2))
+}
+
+// formatAbility renders a score as "14 (+2)".
+func formatAbility(score int) string {
+	return fmt.Sprintf("%d (%+d)", score, abilityModifier(score))
+}
+
+// ordinalLevel renders a spell level as "Cantrip" or "3rd".
+func ordinalLevel(level int) string {
+	switch level {
+	case 0:
+		return "Cantrip"
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", level)
+}
+
+// compendiumAttacks condenses attack actions ("Scimitar. Melee Weapon
+// Attack: +4 to hit...") to the statblock import's "Scimitar: +4 to hit,
+// 5 (1d6 + 2) slashing damage" lines.
+func compendiumAttacks(actions []string) string {
+	var out []string
+	for _, a := range actions {
+		if m := sb5eAttack.FindStringSubmatch(a); m != nil {
+			out = append(out, describe5eAttack(m[1], m[2], a))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// compendiumSection renders named abilities ("Nimble Escape. The goblin
+// can...") under a heading, one paragraph each.
+func compendiumSection(heading string, paragraphs []string) string {
+	if len(paragraphs) == 0 {
+		return ""
+	}
+	return heading + "\n\n" + strings.Join(paragraphs, "\n\n")
+}
+
+// joinParagraphs joins non-empty blocks with blank lines.
+func joinParagraphs(blocks ...string) string {
+	var kept []string
+	for _, b := range blocks {
+		if b = strings.TrimSpace(b); b != "" {
+			kept = append(kept, b)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// filterCompendium keeps the entries whose name contains query, sorted by
+// kind then name.
+func filterCompendium(entries []CompendiumEntry, query string) []CompendiumEntry {
+	q := strings.ToLower(strings.TrimSpace(query))
+	kept := entries[:0]
+	for _, e := range entries {
+		if q == "" || strings.Contains(strings.ToLower(e.Name), q) {
+			kept = append(kept, e)
+		}
+	}
+	order := map[string]int{CompendiumMonster: 0, CompendiumSpell: 1, CompendiumItem: 2}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Kind != kept[j].Kind {
+			return order[kept[i].Kind] < order[kept[j].Kind]
+		}
+		return strings.ToLower(kept[i].Name) < strings.ToLower(kept[j].Name)
+	})
+	return kept
+}
+
+// suggestCompendiumType picks the category a kind goes to by slug or name,
+// or 0 when none looks right.
+func suggestCompendiumType(kind string, types []EntityType) int {
+	for _, hint := range compendiumTypeHints[kind] {
+		for _, et := range types {
+			if normalizeFieldName(et.Slug) == hint || normalizeFieldName(et.Name) == hint || normalizeFieldName(et.NamePlural) == hint {
+				return et.ID
+			}
+		}
+	}
+	return 0
+}
+
+// compendiumContent is what an entry becomes on a page of a category: the
+// field values the category takes and the page text.
+type compendiumContent struct {
+	Fields map[string]any
+	Text   string
+}
+
+// buildCompendiumPage maps an entry's values onto fields. Values with no
+// importable field, or that a number field can't hold, are listed in the
+// page text above the description; the attribution closes it.
+func buildCompendiumPage(e CompendiumEntry, fields []FieldDefinition) compendiumContent {
+	byKey := make(map[string]FieldDefinition, len(fields))
+	for _, f := range fields {
+		byKey[f.Key] = f
+	}
+	page := compendiumContent{Fields: map[string]any{}}
+	var stats []string
+	for _, row := range mapStatblockFields(e.Values, fields) {
+		if f, ok := byKey[row.Target]; ok {
+			if v, ok := statblockFieldValue(f, row.Value); ok {
+				page.Fields[f.Key] = v
+				continue
+			}
+		}
+		// The attribution names the source; attacks are in the actions.
+		if row.Key == "source" || row.Key == "license" || row.Key == "attacks" {
+			continue
+		}
+		if strings.Contains(row.Value, "\n") {
+			stats = append(stats, row.Label+":\n"+row.Value)
+		} else {
+			stats = append(stats, row.Label+": "+row.Value)
+		}
+	}
+	page.Text = joinParagraphs(strings.Join(stats, "\n"), e.Text, e.attribution())
+	return page
+}
+
+// --- 5eTools ---
+
+// fiveToolsTag is an inline 5eTools tag: "{@damage 1d6}", "{@item
+// leather armor|phb}", "{@spell fireball|phb|the spell}".
+var fiveToolsTag = regexp.MustCompile(`\{@(\w+)\s*([^{}]*)\}`)
+
+// fiveToolsAttack labels the {@atk} and {@atkr} attack kinds.
+var fiveToolsAttack = map[string]string{
+	"mw": "Melee Weapon Attack:", "rw": "Ranged Weapon Attack:", "mw,rw": "Melee or Ranged Weapon Attack:",
+	"ms": "Melee Spell Attack:", "rs": "Ranged Spell Attack:", "ms,rs": "Melee or Ranged Spell Attack:",
+	"m": "Melee Attack Roll:", "r": "Ranged Attack Roll:", "m,r": "Melee or Ranged Attack Roll:",
+}
+
+// fiveToolsAbilities names the ability abbreviations saves use.
+var fiveToolsAbilities = map[string]string{
+	"str": "Strength", "dex": "Dexterity", "con": "Constitution",
+	"int": "Intelligence", "wis": "Wisdom", "cha": "Charisma",
+}
+
+// stripFiveToolsTags replaces inline tags with their display text,
+// innermost first.
+func stripFiveToolsTags(s string) string {
+	for i := 0; i < 10 && strings.Contains(s, "{@"); i++ {
+		s = fiveToolsTag.ReplaceAllStringFunc(s, func(tag string) string {
+			m := fiveToolsTag.FindStringSubmatch(tag)
+			name, parts := strings.ToLower(m[1]), strings.Split(m[2], "|")
+			text := strings.TrimSpace(parts[0])
+			switch name {
+			case "atk", "atkr":
+				return fiveToolsAttack[text]
+			case "h":
+				return "Hit: "
+			case "hit":
+				if n, err := strconv.Atoi(text); err == nil {
+					return fmt.Sprintf("%+d", n)
+				}
+				return text
+			case "dc":
+				return "DC " + text
+			case "recharge":
+				if text == "" {
+					return "(Recharge 6)"
+				}
+				return "(Recharge " + text + "-6)"
+			case "actsave":
+				return fiveToolsAbilities[text] + " Saving Throw:"
+			case "actsavefail":
+				return "Failure:"
+			case "actsavesuccess":
+				return "Success:"
+			case "actsavesuccessorfail":
+				return "Failure or Success:"
+			case "actresponse":
+				return "Response:"
+			case "acttrigger":
+				return "Trigger:"
+			}
+			// Link tags show their third part when one is given.
+			if len(parts) >= 3 && strings.TrimSpace(parts[2]) != "" {
+				return strings.TrimSpace(parts[2])
+			}
+			return text
+		})
+	}
+	return s
+}
+
+// fiveToolsText flattens 5eTools "entries" (strings, nested named
+// entries, lists and tables) into paragraphs separated by blank lines.
+func fiveToolsText(entries []any) string {
+	var paras []string
+	var walk func(v any, prefix string)
+	walk = func(v any, prefix string) {
+		switch e := v.(type) {
+		case string:
+			paras = append(paras, prefix+stripFiveToolsTags(e))
+		case []any:
+			for i, item := range e {
+				p := ""
+				if i == 0 {
+					p = prefix
+				}
+				walk(item, p)
+			}
+		case map[string]any:
+			name, _ := e["name"].(string)
+			if name != "" {
+				prefix += stripFiveToolsTags(name) + ". "
+			}
+			switch e["type"] {
+			case "list":
+				items, _ := e["items"].([]any)
+				var lines []string
+				for _, item := range items {
+					if t := fiveToolsText([]any{item}); t != "" {
+						lines = append(lines, "- "+strings.ReplaceAll(t, "\n\n", " "))
+					}
+				}
+				if len(lines) > 0 {
+					paras = append(paras, prefix+strings.Join(lines, "\n"))
+				}
+				return
+			case "table":
+				var lines []string
+				if caption, _ := e["caption"].(string); caption != "" {
+					lines = append(lines, stripFiveToolsTags(caption))
+				}
+				if labels, ok := e["colLabels"].([]any); ok {
+					lines = append(lines, fiveToolsRow(labels))
+				}
+				rows, _ := e["rows"].([]any)
+				for _, r := range rows {
+					if cells, ok := r.([]any); ok {
+						lines = append(lines, fiveToolsRow(cells))
+					}
+				}
+				if len(lines) > 0 {
+					paras = append(paras, strings.Join(lines, "\n"))
+				}
+				return
+			}
+			if entry, ok := e["entry"]; ok {
+				walk(entry, prefix)
+				return
+			}
+			if nested, ok := e["entries"]; ok {
+				walk(nested, prefix)
+				return
+			}
+			if items, ok := e["items"]; ok {
+				walk(items, prefix)
+			}
+		}
+	}
+	walk(entries, "")
+	return joinParagraphs(paras...)
+}
+
+// fiveToolsRow renders a table row as "a | b | c".
+func fiveToolsRow(cells []any) string {
+	out := make([]string, 0, len(cells))
+	for _, c := range cells {
+		switch v := c.(type) {
+		case string:
+			out = append(out, stripFiveToolsTags(v))
+		case float64:
+			out = append(out, strconv.FormatFloat(v, 'f', -1, 64))
+		case map[string]any:
+			out = append(out, fiveToolsText([]any{v}))
+		}
+	}
+	return strings.Join(out, " | ")
+}
+
+// fiveToolsNamed renders named blocks (traits, actions) as "Name. text"
+// paragraphs.
+func fiveToolsNamed(blocks []fiveToolsBlock) []string {
+	out := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		text := strings.ReplaceAll(fiveToolsText(b.Entries), "\n\n", " ")
+		if b.Name != "" {
+			text = stripFiveToolsTags(b.Name) + ". " + text
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+// fiveToolsFile is the part of a 5eTools data file the import reads.
+// Bestiary, spell and item files each fill one list; homebrew files may
+// fill several.
+type fiveToolsFile struct {
+	Monster  []fiveToolsMonster `json:"monster"`
+	Spell    []fiveToolsSpell   `json:"spell"`
+	Item     []fiveToolsItem    `json:"item"`
+	BaseItem []fiveToolsItem    `json:"baseitem"`
+}
+
+// fiveToolsCommon are the fields every 5eTools record has.
+type fiveToolsCommon struct {
+	Name    string          `json:"name"`
+	Source  string          `json:"source"`
+	Page    any             `json:"page"`
+	SRD     any             `json:"srd"`
+	SRD52   any             `json:"srd52"`
+	Entries []any           `json:"entries"`
+	Copy    json.RawMessage `json:"_copy"`
+}
+
+// fiveToolsBlock is a named trait or action.
+type fiveToolsBlock struct {
+	Name    string `json:"name"`
+	Entries []any  `json:"entries"`
+}
+
+type fiveToolsMonster struct {
+	fiveToolsCommon
+	Size            []string         `json:"size"`
+	Type            any              `json:"type"`
+	Alignment       []any            `json:"alignment"`
+	AC              []any            `json:"ac"`
+	HP              map[string]any   `json:"hp"`
+	Speed           map[string]any   `json:"speed"`
+	Str             int              `json:"str"`
+	Dex             int              `json:"dex"`
+	Con             int              `json:"con"`
+	Int             int              `json:"int"`
+	Wis             int              `json:"wis"`
+	Cha             int              `json:"cha"`
+	Save            map[string]any   `json:"save"`
+	Skill           map[string]any   `json:"skill"`
+	Vulnerable      []any            `json:"vulnerable"`
+	Resist          []any            `json:"resist"`
+	Immune          []any            `json:"immune"`
+	ConditionImmune []any            `json:"conditionImmune"`
+	Senses          []string         `json:"senses"`
+	Passive         any              `json:"passive"`
+	Languages       []string         `json:"languages"`
+	CR              any              `json:"cr"`
+	Trait           []fiveToolsBlock `json:"trait"`
+	Action          []fiveToolsBlock `json:"action"`
+	Bonus           []fiveToolsBlock `json:"bonus"`
+	Reaction        []fiveToolsBlock `json:"reaction"`
+	Legendary       []fiveToolsBlock `json:"legendary"`
+}
+
+type fiveToolsSpell struct {
+	fiveToolsCommon
+	Level              int              `json:"level"`
+	School             string           `json:"school"`
+	Time               []map[string]any `json:"time"`
+	Range              map[string]any   `json:"range"`
+	Components         map[string]any   `json:"components"`
+	Duration           []map[string]any `json:"duration"`
+	EntriesHigherLevel []any            `json:"entriesHigherLevel"`
+	Classes            struct {
+		FromClassList []struct {
+			Name string `json:"name"`
+		} `json:"fromClassList"`
+	} `json:"classes"`
+}
+
+type fiveToolsItem struct {
+	fiveToolsCommon
+	Type      string `json:"type"`
+	Rarity    string `json:"rarity"`
+	ReqAttune any    `json:"reqAttune"`
+	Wondrous  bool   `json:"wondrous"`
+}
+
+var (
+	fiveToolsSizes = map[string]string{
+		"T": "Tiny", "S": "Small", "M": "Medium", "L": "Large", "H": "Huge", "G": "Gargantuan",
+	}
+	fiveToolsAlignments = map[string]string{
+		"L": "lawful", "N": "neutral", "C": "chaotic", "G": "good", "E": "evil",
+		"U": "unaligned", "A": "any alignment",
+	}
+	fiveToolsSchools = map[string]string{
+		"A": "Abjuration", "C": "Conjuration", "D": "Divination", "E": "Enchantment",
+		"V": "Evocation", "I": "Illusion", "N": "Necromancy", "T": "Transmutation", "P": "Psionic",
+	}
+	fiveToolsItemTypes = map[string]string{
+		"M": "Melee weapon", "R": "Ranged weapon", "A": "Ammunition", "LA": "Light armor",
+		"MA": "Medium armor", "HA": "Heavy armor", "S": "Shield", "P": "Potion", "RG": "Ring",
+		"RD": "Rod", "WD": "Wand", "SC": "Scroll", "G": "Adventuring gear", "INS": "Instrument",
+		"AT": "Artisan's tools", "T": "Tools", "GS": "Gaming set", "SCF": "Spellcasting focus",
+		"$": "Treasure", "EXP": "Explosive", "FD": "Food and drink", "TAH": "Tack and harness",
+		"MNT": "Mount", "VEH": "Vehicle", "SHP": "Ship", "OTH": "Other",
+	}
+)
+
+// ParseFiveTools reads the monsters, spells and items in a 5eTools JSON
+// data file. Entries that copy another ("_copy") can't be resolved from
+// one file and are left out.
+func ParseFiveTools(data []byte) ([]CompendiumEntry, error) {
+	var file fiveToolsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, apperror.NewBadRequest("file is not valid 5eTools JSON")
+	}
+	var entries []CompendiumEntry
+	for _, m := range file.Monster {
+		if m.Name != "" && len(m.Copy) == 0 {
+			entries = append(entries, m.entry())
+		}
+	}
+	for _, s := range file.Spell {
+		if s.Name != "" && len(s.Copy) == 0 {
+			entries = append(entries, s.entry())
+		}
+	}
+	for _, list := range [][]fiveToolsItem{file.Item, file.BaseItem} {
+		for _, it := range list {
+			if it.Name != "" && len(it.Copy) == 0 {
+				entries = append(entries, it.entry())
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, apperror.NewBadRequest("no monsters, spells or items found in the file")
+	}
+	return entries, nil
+}
+
+// base starts a compendium entry with the record's key, source and
+// licence. Only SRD content is openly licensed; everything else in a
+// 5eTools file is published under its book's copyright.
+func (r fiveToolsCommon) base(kind string) CompendiumEntry {
+	e := CompendiumEntry{
+		Key:    fmt.Sprintf("5etools:%s:%s|%s", kind, strings.ToLower(r.Name), strings.ToLower(r.Source)),
+		Kind:   kind,
+		Name:   r.Name,
+		Source: r.Source,
+	}
+	if page := fiveToolsString(r.Page); page != "" && page != "0" {
+		e.Source += " p. " + page
+	}
+	switch {
+	case fiveToolsFlag(r.SRD52):
+		e.Open, e.License = true, "CC-BY-4.0 (SRD 5.2)"
+	case fiveToolsFlag(r.SRD):
+		e.Open, e.License = true, "CC-BY-4.0 (SRD 5.1)"
+	}
+	e.addValue("source", e.Source)
+	e.addValue("license", e.License)
+	return e
+}
+
+// fiveToolsFlag reads a flag that is true or an alternate name.
+func fiveToolsFlag(v any) bool {
+	switch f := v.(type) {
+	case bool:
+		return f
+	case string:
+		return f != ""
+	}
+	return false
+}
+
+// fiveToolsString renders the loosely typed values 5eTools uses for
+// types, CRs and damage lists.
+func fiveToolsString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return stripFiveToolsTags(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			if s := fiveToolsString(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		// {"cr": "1/4"}, {"special": "..."}, {"resist": [...], "note": "..."}.
+		for _, k := range []string{"cr", "special", "type"} {
+			if s := fiveToolsString(t[k]); s != "" {
+				return s
+			}
+		}
+		for _, k := range []string{"resist", "immune", "vulnerable", "conditionImmune"} {
+			if list, ok := t[k]; ok {
+				s := fiveToolsString(list)
+				if note, _ := t["note"].(string); note != "" {
+					s += " " + note
+				}
+				if pre, _ := t["preNote"].(string); pre != "" {
+					s = pre + " " + s
+				}
+				return s
+			}
+		}
+		if choose, ok := t["choose"]; ok {
+			return strings.ReplaceAll(fiveToolsString(choose), ", ", " or ")
+		}
+	}
+	return ""
+}
+
+func (m fiveToolsMonster) entry() CompendiumEntry {
+	e := m.base(CompendiumMonster)
+	var sizes []string
+	for _, s := range m.Size {
+		sizes = append(sizes, fiveToolsSizes[s])
+	}
+	e.addValue("size", strings.Join(sizes, " or "))
+
+	typ := fiveToolsString(m.Type)
+	if t, ok := m.Type.(map[string]any); ok {
+		if tags := fiveToolsTags(t["tags"]); tags != "" {
+			typ += " (" + tags + ")"
+		}
+	}
+	e.addValue("creature_type", typ)
+	e.addValue("alignment", fiveToolsAlignment(m.Alignment))
+
+	if len(m.AC) > 0 {
+		switch ac := m.AC[0].(type) {
+		case float64:
+			e.addValue("ac", strconv.Itoa(int(ac)))
+		case map[string]any:
+			v := fiveToolsString(ac["ac"])
+			if from := fiveToolsString(ac["from"]); from != "" {
+				v += " (" + from + ")"
+			}
+			if v == "" {
+				v = fiveToolsString(ac["special"])
+			}
+			e.addValue("ac", v)
+		}
+	}
+	if avg, ok := m.HP["average"].(float64); ok {
+		hp := strconv.Itoa(int(avg))
+		if formula := fiveToolsString(m.HP["formula"]); formula != "" {
+			hp += " (" + formula + ")"
+		}
+		e.addValue("hp", hp)
+	} else {
+		e.addValue("hp", fiveToolsString(m.HP["special"]))
+	}
+	e.addValue("speed", fiveToolsSpeed(m.Speed))
+	for _, a := range []struct {
+		key   string
+		score int
+	}{{"str", m.Str}, {"dex", m.Dex}, {"con", m.Con}, {"int", m.Int}, {"wis", m.Wis}, {"cha", m.Cha}} {
+		if a.score > 0 {
+			e.addValue(a.key, formatAbility(a.score))
+		}
+	}
+	e.addValue("saves", fiveToolsBonuses(m.Save))
+	e.addValue("skills", fiveToolsBonuses(m.Skill))
+	e.addValue("vulnerabilities", fiveToolsString(m.Vulnerable))
+	e.addValue("resistances", fiveToolsString(m.Resist))
+	e.addValue("immunities", fiveToolsString(m.Immune))
+	e.addValue("condition_immunities", fiveToolsString(m.ConditionImmune))
+	senses := stripFiveToolsTags(strings.Join(m.Senses, ", "))
+	if p := fiveToolsString(m.Passive); p != "" {
+		if senses != "" {
+			senses += ", "
+		}
+		senses += "passive Perception " + p
+	}
+	e.addValue("senses", senses)
+	e.addValue("languages", stripFiveToolsTags(strings.Join(m.Languages, ", ")))
+	e.addValue("cr", fiveToolsString(m.CR))
+
+	actions := fiveToolsNamed(m.Action)
+	e.addValue("attacks", compendiumAttacks(actions))
+	e.Text = joinParagraphs(
+		fiveToolsText(m.Entries),
+		strings.Join(fiveToolsNamed(m.Trait), "\n\n"),
+		compendiumSection("Actions", actions),
+		compendiumSection("Bonus Actions", fiveToolsNamed(m.Bonus)),
+		compendiumSection("Reactions", fiveToolsNamed(m.Reaction)),
+		compendiumSection("Legendary Actions", fiveToolsNamed(m.Legendary)),
+	)
+	return e
+}
+
+// fiveToolsTags renders creature type tags: ["goblinoid"] or
+// [{"tag": "shapechanger", "prefix": "..."}].
+func fiveToolsTags(v any) string {
+	tags, _ := v.([]any)
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		switch tag := t.(type) {
+		case string:
+			out = append(out, tag)
+		case map[string]any:
+			if s, _ := tag["tag"].(string); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// fiveToolsAlignment renders alignment codes: ["N", "E"] is "neutral
+// evil", ["U"] "unaligned".
+func fiveToolsAlignment(codes []any) string {
+	var words []string
+	for _, c := range codes {
+		switch a := c.(type) {
+		case string:
+			if w, ok := fiveToolsAlignments[a]; ok {
+				words = append(words, w)
+			}
+		case map[string]any:
+			if s, _ := a["special"].(string); s != "" {
+				return s
+			}
+		}
+	}
+	if len(words) == 2 && words[0] == "neutral" && words[1] == "neutral" {
+		return "neutral"
+	}
+	return strings.Join(words, " ")
+}
+
+// fiveToolsSpeed renders {"walk": 30, "fly": {"number": 60, "condition":
+// "(hover)"}} as "30 ft., fly 60 ft. (hover)".
+func fiveToolsSpeed(speed map[string]any) string {
+	var out []string
+	for _, mode := range []string{"walk", "burrow", "climb", "fly", "swim"} {
+		var n, cond string
+		switch v := speed[mode].(type) {
+		case float64:
+			n = strconv.Itoa(int(v))
+		case map[string]any:
+			n = fiveToolsString(v["number"])
+			cond, _ = v["condition"].(string)
+		default:
+			continue
+		}
+		s := n + " ft."
+		if mode != "walk" {
+			s = mode + " " + s
+		}
+		if cond != "" {
+			s += " " + stripFiveToolsTags(cond)
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ", ")
+}
+
+// fiveToolsBonuses renders {"dex": "+5", "stealth": "+6"} as "Dex +5,
+// Stealth +6", sorted by name.
+func fiveToolsBonuses(m map[string]any) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if v := fiveToolsString(m[k]); v != "" {
+			out = append(out, strings.ToUpper(k[:1])+k[1:]+" "+v)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func (s fiveToolsSpell) entry() CompendiumEntry {
+	e := s.base(CompendiumSpell)
+	e.addValue("spell_level", ordinalLevel(s.Level))
+	e.addValue("school", fiveToolsSchools[s.School])
+
+	var times []string
+	for _, t := range s.Time {
+		times = append(times, strings.TrimSpace(fiveToolsString(t["number"])+" "+fiveToolsString(t["unit"])))
+	}
+	e.addValue("casting_time", strings.Join(times, " or "))
+	e.addValue("range", fiveToolsRange(s.Range))
+
+	var comps []string
+	for _, c := range []string{"v", "s"} {
+		if b, _ := s.Components[c].(bool); b {
+			comps = append(comps, strings.ToUpper(c))
+		}
+	}
+	switch m := s.Components["m"].(type) {
+	case string:
+		comps = append(comps, "M ("+stripFiveToolsTags(m)+")")
+	case map[string]any:
+		comps = append(comps, "M ("+fiveToolsString(m["text"])+")")
+	case bool:
+		if m {
+			comps = append(comps, "M")
+		}
+	}
+	e.addValue("components", strings.Join(comps, ", "))
+	e.addValue("duration", fiveToolsDuration(s.Duration))
+
+	var classes []string
+	for _, c := range s.Classes.FromClassList {
+		classes = append(classes, c.Name)
+	}
+	e.addValue("classes", strings.Join(classes, ", "))
+	e.Text = joinParagraphs(fiveToolsText(s.Entries), fiveToolsText(s.EntriesHigherLevel))
+	return e
+}
+
+// fiveToolsRange renders a spell range: "150 feet", "Self (15-foot
+// cone)", "Touch".
+func fiveToolsRange(r map[string]any) string {
+	typ, _ := r["type"].(string)
+	dist, _ := r["distance"].(map[string]any)
+	unit, _ := dist["type"].(string)
+	amount := fiveToolsString(dist["amount"])
+	switch {
+	case typ == "special":
+		return "Special"
+	case unit == "self" || unit == "touch" || unit == "sight" || unit == "unlimited":
+		return strings.ToUpper(unit[:1]) + unit[1:]
+	case typ == "point":
+		return strings.TrimSpace(amount + " " + unit)
+	case amount != "":
+		// Areas centred on the caster: "Self (15-foot cone)".
+		return fmt.Sprintf("Self (%s-%s %s)", amount, strings.TrimSuffix(unit, "feet")+"foot", typ)
+	}
+	return ""
+}
+
+// fiveToolsDuration renders a spell duration: "Instantaneous",
+// "Concentration, up to 1 minute", "Until dispelled".
+func fiveToolsDuration(durations []map[string]any) string {
+	var out []string
+	for _, d := range durations {
+		switch d["type"] {
+		case "instant":
+			out = append(out, "Instantaneous")
+		case "permanent":
+			out = append(out, "Until dispelled")
+		case "special":
+			out = append(out, "Special")
+		case "timed":
+			inner, _ := d["duration"].(map[string]any)
+			amount, unit := fiveToolsString(inner["amount"]), fiveToolsString(inner["type"])
+			if amount != "1" {
+				unit += "s"
+			}
+			s := amount + " " + unit
+			if c, _ := d["concentration"].(bool); c {
+				s = "Concentration, up to " + s
+			}
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, " or ")
+}
+
+func (it fiveToolsItem) entry() CompendiumEntry {
+	e := it.base(CompendiumItem)
+	typ := fiveToolsItemTypes[strings.SplitN(it.Type, "|", 2)[0]]
+	if it.Wondrous {
+		typ = "Wondrous item"
+	}
+	e.addValue("item_type", typ)
+	if it.Rarity != "none" {
+		e.addValue("rarity", it.Rarity)
+	}
+	switch a := it.ReqAttune.(type) {
+	case bool:
+		if a {
+			e.addValue("attunement", "Requires attunement")
+		}
+	case string:
+		e.addValue("attunement", "Requires attunement "+stripFiveToolsTags(a))
+	}
+	e.Text = fiveToolsText(it.Entries)
+	return e
+}
@@ -0,0 +1,316 @@
+package entities
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetCompendium enables the compendium import. searcher may be nil, which
+// leaves only 5eTools uploads.
+func (h *Handler) SetCompendium(searcher Open5eSearcher, repo ContentImportRepository) {
+	h.open5e = searcher
+	h.contentImports = repo
+}
+
+// CompendiumTarget is the category suggested for one kind in review.
+type CompendiumTarget struct {
+	Kind   string
+	TypeID int
+}
+
+// CompendiumResult reports what apply did.
+type CompendiumResult struct {
+	Created   int
+	Refreshed int
+	Private   int // created as private pages (not openly licensed)
+	Skipped   []CompendiumSkip
+}
+
+// CompendiumSkip is a checked entry apply didn't import, and why.
+type CompendiumSkip struct {
+	Name   string
+	Reason string
+}
+
+// compendiumContext checks the tool is wired and returns the campaign.
+func (h *Handler) compendiumContext(c echo.Context) (*campaigns.CampaignContext, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, apperror.NewMissingContext()
+	}
+	if h.contentImports == nil {
+		return nil, apperror.NewNotFound("compendium import is not available")
+	}
+	return cc, nil
+}
+
+// readCompendium searches Open5e or reads the uploaded 5eTools file,
+// whichever the form's "source" names, and narrows the result to names
+// containing "q".
+func (h *Handler) readCompendium(c echo.Context) ([]CompendiumEntry, error) {
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxCompendiumFileSize+1<<20)
+	query := c.FormValue("q")
+	var entries []CompendiumEntry
+	if c.FormValue("source") == Compendium5eTools {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, apperror.NewBadRequest("choose a 5eTools JSON file")
+		}
+		if file.Size > maxCompendiumFileSize {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("file too large, maximum %d MB", maxCompendiumFileSize>>20))
+		}
+		src, err := file.Open()
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("open uploaded 5etools file: %w", err))
+		}
+		defer func() { _ = src.Close() }()
+		data, err := io.ReadAll(io.LimitReader(src, maxCompendiumFileSize+1))
+		if err != nil || len(data) > maxCompendiumFileSize {
+			return nil, apperror.NewBadRequest("could not read the uploaded file")
+		}
+		if entries, err = ParseFiveTools(data); err != nil {
+			return nil, err
+		}
+	} else {
+		if h.open5e == nil {
+			return nil, apperror.NewBadRequest("Open5e search is not available; upload a 5eTools file instead")
+		}
+		var err error
+		if entries, err = h.open5e.Search(c.Request().Context(), c.FormValue("kind"), query); err != nil {
+			return nil, err
+		}
+		query = "" // Open5e already matched it.
+	}
+
+	entries = filterCompendium(entries, query)
+	switch {
+	case len(entries) == 0:
+		return nil, apperror.NewBadRequest("nothing matched; try another name")
+	case len(entries) > maxCompendiumEntries:
+		return nil, apperror.NewBadRequest(fmt.Sprintf("%d entries found; filter by name to list at most %d", len(entries), maxCompendiumEntries))
+	}
+	return entries, nil
+}
+
+// compendiumTypes returns the campaign's enabled categories.
+func (h *Handler) compendiumTypes(c echo.Context, cc *campaigns.CampaignContext) ([]EntityType, error) {
+	types, err := h.service.GetEntityTypes(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	var out []EntityType
+	for _, et := range creatableTypes(cc, types) {
+		if et.Enabled {
+			out = append(out, et)
+		}
+	}
+	return out, nil
+}
+
+// importedEntries maps the entries already imported into the campaign to
+// their pages.
+func (h *Handler) importedEntries(c echo.Context, campaignID string, entries []CompendiumEntry) (map[string]string, error) {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return h.contentImports.FindEntities(c.Request().Context(), campaignID, keys)
+}
+
+// CompendiumPage renders the search and upload form.
+// GET /campaigns/:id/entities/compendium
+func (h *Handler) CompendiumPage(c echo.Context) error {
+	cc, err := h.compendiumContext(c)
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, CompendiumPage(cc, h.open5e != nil, middleware.GetCSRFToken(c)))
+}
+
+// CompendiumPreview lists the entries found, which of them were imported
+// before, and the category suggested for each kind. Nothing is stored.
+// POST /campaigns/:id/entities/compendium/preview
+func (h *Handler) CompendiumPreview(c echo.Context) error {
+	cc, err := h.compendiumContext(c)
+	if err != nil {
+		return err
+	}
+	entries, err := h.readCompendium(c)
+	if err != nil {
+		return middleware.Render(c, http.StatusOK, CompendiumError(apperror.SafeMessage(err)))
+	}
+	types, err := h.compendiumTypes(c, cc)
+	if err != nil {
+		return err
+	}
+	imported, err := h.importedEntries(c, cc.Campaign.ID, entries)
+	if err != nil {
+		return err
+	}
+
+	present := map[string]bool{}
+	for _, e := range entries {
+		present[e.Kind] = true
+	}
+	var targets []CompendiumTarget
+	for _, kind := range compendiumKinds {
+		if present[kind] {
+			targets = append(targets, CompendiumTarget{Kind: kind, TypeID: suggestCompendiumType(kind, types)})
+		}
+	}
+	return middleware.Render(c, http.StatusOK, CompendiumReview(cc, entries, imported, targets, types))
+}
+
+// CompendiumApply imports the checked entries ("key") into the category
+// chosen for their kind ("type_monster", ...). Entries imported before
+// are skipped, or refreshed in place when "existing" is "refresh".
+// POST /campaigns/:id/entities/compendium/apply
+func (h *Handler) CompendiumApply(c echo.Context) error {
+	cc, err := h.compendiumContext(c)
+	if err != nil {
+		return err
+	}
+	entries, err := h.readCompendium(c)
+	if err != nil {
+		return middleware.Render(c, http.StatusOK, CompendiumError(apperror.SafeMessage(err)))
+	}
+	form, err := c.FormParams()
+	if err != nil {
+		return apperror.NewBadRequest("invalid form")
+	}
+	selected := make(map[string]bool, len(form["key"]))
+	for _, k := range form["key"] {
+		selected[k] = true
+	}
+	switch {
+	case len(selected) == 0:
+		return apperror.NewBadRequest("check at least one entry to import")
+	case len(selected) > maxCompendiumApply:
+		return apperror.NewBadRequest(fmt.Sprintf("import at most %d entries at a time", maxCompendiumApply))
+	}
+	refresh := c.FormValue("existing") == "refresh"
+
+	types, err := h.compendiumTypes(c, cc)
+	if err != nil {
+		return err
+	}
+	byKind := map[string]*EntityType{}
+	for _, kind := range compendiumKinds {
+		id, _ := strconv.Atoi(c.FormValue("type_" + kind))
+		for i := range types {
+			if types[i].ID == id {
+				byKind[kind] = &types[i]
+			}
+		}
+	}
+	imported, err := h.importedEntries(c, cc.Campaign.ID, entries)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	defaultVis := cc.Campaign.ParseSettings().DefaultVisibility
+	defaultPrivate := defaultVis == "dm_only" || defaultVis == "private"
+	result := CompendiumResult{}
+	for _, e := range entries {
+		if !selected[e.Key] {
+			continue
+		}
+		record := ContentImport{SourceKey: e.Key, Source: e.Source, License: e.License}
+
+		if id, ok := imported[e.Key]; ok {
+			if !refresh {
+				result.Skipped = append(result.Skipped, CompendiumSkip{e.Name, "already imported"})
+				continue
+			}
+			if existing, err := h.service.GetByID(ctx, id); err == nil && existing.CampaignID == cc.Campaign.ID {
+				if err := h.refreshCompendiumPage(c, existing, e); err != nil {
+					result.Skipped = append(result.Skipped, CompendiumSkip{e.Name, apperror.UserMessage(err, "could not refresh the page")})
+					continue
+				}
+				record.EntityID = existing.ID
+				if err := h.contentImports.Record(ctx, cc.Campaign.ID, record); err != nil {
+					return err
+				}
+				result.Refreshed++
+				continue
+			}
+			// The page is gone; import the entry afresh.
+		}
+
+		et := byKind[e.Kind]
+		if et == nil {
+			result.Skipped = append(result.Skipped, CompendiumSkip{e.Name, "no category chosen for " + compendiumKindNames[e.Kind]})
+			continue
+		}
+		page := buildCompendiumPage(e, et.Fields)
+		entity, err := h.service.Create(ctx, cc.Campaign.ID, userID, CreateEntityInput{
+			Name:         e.Name,
+			EntityTypeID: et.ID,
+			IsPrivate:    !e.Open || defaultPrivate,
+			FieldsData:   page.Fields,
+		})
+		if err != nil {
+			result.Skipped = append(result.Skipped, CompendiumSkip{e.Name, apperror.UserMessage(err, "could not create the page")})
+			continue
+		}
+		if page.Text != "" {
+			entryJSON, entryHTML := plainTextEntry(page.Text)
+			if err := h.service.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+				slog.Warn("compendium import: entry text not saved",
+					slog.String("entity_id", entity.ID), slog.Any("error", err))
+			}
+		}
+		record.EntityID = entity.ID
+		if err := h.contentImports.Record(ctx, cc.Campaign.ID, record); err != nil {
+			return err
+		}
+		h.logAudit(c, cc.Campaign.ID, audit.ActionEntityCreated, entity.ID, entity.Name)
+		result.Created++
+		if !e.Open {
+			result.Private++
+		}
+	}
+	return middleware.Render(c, http.StatusOK, CompendiumResultView(cc, result))
+}
+
+// refreshCompendiumPage rewrites a previously imported page's stats and
+// text from the entry. Fields the entry doesn't fill keep their values.
+func (h *Handler) refreshCompendiumPage(c echo.Context, entity *Entity, e CompendiumEntry) error {
+	ctx := c.Request().Context()
+	et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	if err != nil {
+		return err
+	}
+	page := buildCompendiumPage(e, MergeFields(et.Fields, entity.FieldOverrides))
+	data := make(map[string]any, len(entity.FieldsData)+len(page.Fields))
+	for k, v := range entity.FieldsData {
+		data[k] = v
+	}
+	for k, v := range page.Fields {
+		data[k] = v
+	}
+	if err := h.service.UpdateFields(ctx, entity.ID, data); err != nil {
+		return err
+	}
+	entryJSON, entryHTML := plainTextEntry(page.Text)
+	if err := h.service.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+		return err
+	}
+	h.logAuditWithDetails(c, entity.CampaignID, audit.ActionEntityUpdated, entity.ID, entity.Name, map[string]any{
+		"compendium": "refreshed from " + e.Source,
+	})
+	return nil
+}
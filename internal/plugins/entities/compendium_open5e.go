@@ -0,0 +1,382 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// defaultOpen5eURL is the public Open5e API. OPEN5E_URL overrides it
+	// for a self-hosted mirror.
+	defaultOpen5eURL = "https://api.open5e.com"
+	// open5eSearchLimit caps the results one search returns.
+	open5eSearchLimit = 50
+	// maxOpen5eResponse caps a search response body.
+	maxOpen5eResponse = 10 << 20
+)
+
+// open5eEndpoints maps each kind to its v1 API list.
+var open5eEndpoints = map[string]string{
+	CompendiumMonster: "/v1/monsters/",
+	CompendiumSpell:   "/v1/spells/",
+	CompendiumItem:    "/v1/magicitems/",
+}
+
+// Open5eSearcher finds monsters, spells and magic items on Open5e.
+type Open5eSearcher interface {
+	Search(ctx context.Context, kind, query string) ([]CompendiumEntry, error)
+}
+
+// Open5eClient searches the Open5e API. Everything Open5e hosts is
+// published under the OGL or CC-BY; each result names its document and
+// licence.
+type Open5eClient struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewOpen5eClient creates an Open5e client for the public API, or the
+// mirror named by OPEN5E_URL.
+func NewOpen5eClient() *Open5eClient {
+	base := strings.TrimSpace(os.Getenv("OPEN5E_URL"))
+	if base == "" {
+		base = defaultOpen5eURL
+	}
+	return &Open5eClient{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		baseURL:    strings.TrimSuffix(base, "/"),
+	}
+}
+
+// open5eDocument is the source document every Open5e record names.
+type open5eDocument struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Title      string `json:"document__title"`
+	LicenseURL string `json:"document__license_url"`
+	Desc       string `json:"desc"`
+}
+
+// open5eAction is a named trait or action.
+type open5eAction struct {
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+}
+
+type open5eMonster struct {
+	open5eDocument
+	Size                  string           `json:"size"`
+	Type                  string           `json:"type"`
+	Subtype               string           `json:"subtype"`
+	Alignment             string           `json:"alignment"`
+	ArmorClass            int              `json:"armor_class"`
+	ArmorDesc             string           `json:"armor_desc"`
+	HitPoints             int              `json:"hit_points"`
+	HitDice               string           `json:"hit_dice"`
+	Speed                 map[string]any   `json:"speed"`
+	Strength              int              `json:"strength"`
+	Dexterity             int              `json:"dexterity"`
+	Constitution          int              `json:"constitution"`
+	Intelligence          int              `json:"intelligence"`
+	Wisdom                int              `json:"wisdom"`
+	Charisma              int              `json:"charisma"`
+	StrengthSave          *int             `json:"strength_save"`
+	DexteritySave         *int             `json:"dexterity_save"`
+	ConstitutionSave      *int             `json:"constitution_save"`
+	IntelligenceSave      *int             `json:"intelligence_save"`
+	WisdomSave            *int             `json:"wisdom_save"`
+	CharismaSave          *int             `json:"charisma_save"`
+	Skills                map[string]any   `json:"skills"`
+	DamageVulnerabilities string           `json:"damage_vulnerabilities"`
+	DamageResistances     string           `json:"damage_resistances"`
+	DamageImmunities      string           `json:"damage_immunities"`
+	ConditionImmunities   string           `json:"condition_immunities"`
+	Senses                string           `json:"senses"`
+	Languages             string           `json:"languages"`
+	ChallengeRating       string           `json:"challenge_rating"`
+	Actions               open5eActionList `json:"actions"`
+	BonusActions          open5eActionList `json:"bonus_actions"`
+	Reactions             open5eActionList `json:"reactions"`
+	LegendaryActions      open5eActionList `json:"legendary_actions"`
+	SpecialAbilities      open5eActionList `json:"special_abilities"`
+}
+
+type open5eSpell struct {
+	open5eDocument
+	HigherLevel   string `json:"higher_level"`
+	Range         string `json:"range"`
+	Components    string `json:"components"`
+	Material      string `json:"material"`
+	Duration      string `json:"duration"`
+	Concentration string `json:"concentration"`
+	CastingTime   string `json:"casting_time"`
+	LevelInt      int    `json:"level_int"`
+	School        string `json:"school"`
+	DnDClass      string `json:"dnd_class"`
+}
+
+type open5eItem struct {
+	open5eDocument
+	Type               string `json:"type"`
+	Rarity             string `json:"rarity"`
+	RequiresAttunement string `json:"requires_attunement"`
+}
+
+// open5eActionList reads an action list; a few documents send "" instead
+// of an empty list.
+type open5eActionList []open5eAction
+
+// UnmarshalJSON accepts a list of actions or anything else as none.
+func (l *open5eActionList) UnmarshalJSON(data []byte) error {
+	var actions []open5eAction
+	if json.Unmarshal(data, &actions) == nil {
+		*l = actions
+	}
+	return nil
+}
+
+// Search returns the entries of a kind whose name matches query.
+func (c *Open5eClient) Search(ctx context.Context, kind, query string) ([]CompendiumEntry, error) {
+	endpoint, ok := open5eEndpoints[kind]
+	if !ok {
+		return nil, apperror.NewBadRequest("choose monsters, spells or magic items to search")
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, apperror.NewBadRequest("enter a name to search for")
+	}
+
+	params := url.Values{}
+	params.Set("search", query)
+	params.Set("limit", strconv.Itoa(open5eSearchLimit))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating open5e request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, apperror.NewBadRequest("Open5e couldn't be reached; try again in a moment")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("Open5e returned an error (HTTP %d); try again in a moment", resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOpen5eResponse))
+	if err != nil {
+		return nil, apperror.NewBadRequest("Open5e's response couldn't be read")
+	}
+	entries, err := parseOpen5e(kind, body)
+	if err != nil {
+		return nil, apperror.NewBadRequest("Open5e's response couldn't be read")
+	}
+	return entries, nil
+}
+
+// parseOpen5e converts one page of search results.
+func parseOpen5e(kind string, body []byte) ([]CompendiumEntry, error) {
+	var entries []CompendiumEntry
+	switch kind {
+	case CompendiumMonster:
+		var page struct{ Results []open5eMonster }
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		for _, m := range page.Results {
+			entries = append(entries, m.entry())
+		}
+	case CompendiumSpell:
+		var page struct{ Results []open5eSpell }
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		for _, s := range page.Results {
+			entries = append(entries, s.entry())
+		}
+	case CompendiumItem:
+		var page struct{ Results []open5eItem }
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		for _, it := range page.Results {
+			entries = append(entries, it.entry())
+		}
+	}
+	return entries, nil
+}
+
+// base starts a compendium entry with the record's key and document.
+func (d open5eDocument) base(kind string) CompendiumEntry {
+	e := CompendiumEntry{
+		Key:     "open5e:" + kind + ":" + d.Slug,
+		Kind:    kind,
+		Name:    d.Name,
+		Source:  d.Title,
+		License: d.LicenseURL,
+		Open:    true,
+	}
+	if e.Source == "" {
+		e.Source = "Open5e"
+	}
+	e.addValue("source", e.Source)
+	e.addValue("license", e.License)
+	return e
+}
+
+// open5eNamed renders actions as "Name. desc" paragraphs.
+func open5eNamed(actions []open5eAction) []string {
+	out := make([]string, 0, len(actions))
+	for _, a := range actions {
+		out = append(out, strings.TrimSpace(a.Name+". "+a.Desc))
+	}
+	return out
+}
+
+func (m open5eMonster) entry() CompendiumEntry {
+	e := m.base(CompendiumMonster)
+	e.addValue("size", m.Size)
+	typ := m.Type
+	if m.Subtype != "" {
+		typ += " (" + m.Subtype + ")"
+	}
+	e.addValue("creature_type", typ)
+	e.addValue("alignment", m.Alignment)
+	if m.ArmorClass > 0 {
+		ac := strconv.Itoa(m.ArmorClass)
+		if m.ArmorDesc != "" {
+			ac += " (" + m.ArmorDesc + ")"
+		}
+		e.addValue("ac", ac)
+	}
+	if m.HitPoints > 0 {
+		hp := strconv.Itoa(m.HitPoints)
+		if m.HitDice != "" {
+			hp += " (" + m.HitDice + ")"
+		}
+		e.addValue("hp", hp)
+	}
+	e.addValue("speed", open5eSpeed(m.Speed))
+	for _, a := range []struct {
+		key   string
+		score int
+	}{{"str", m.Strength}, {"dex", m.Dexterity}, {"con", m.Constitution}, {"int", m.Intelligence}, {"wis", m.Wisdom}, {"cha", m.Charisma}} {
+		if a.score > 0 {
+			e.addValue(a.key, formatAbility(a.score))
+		}
+	}
+	var saves []string
+	for _, s := range []struct {
+		name  string
+		bonus *int
+	}{{"Str", m.StrengthSave}, {"Dex", m.DexteritySave}, {"Con", m.ConstitutionSave}, {"Int", m.IntelligenceSave}, {"Wis", m.WisdomSave}, {"Cha", m.CharismaSave}} {
+		if s.bonus != nil {
+			saves = append(saves, fmt.Sprintf("%s %+d", s.name, *s.bonus))
+		}
+	}
+	e.addValue("saves", strings.Join(saves, ", "))
+	e.addValue("skills", open5eSkills(m.Skills))
+	e.addValue("vulnerabilities", m.DamageVulnerabilities)
+	e.addValue("resistances", m.DamageResistances)
+	e.addValue("immunities", m.DamageImmunities)
+	e.addValue("condition_immunities", m.ConditionImmunities)
+	e.addValue("senses", m.Senses)
+	e.addValue("languages", m.Languages)
+	e.addValue("cr", m.ChallengeRating)
+
+	actions := open5eNamed(m.Actions)
+	e.addValue("attacks", compendiumAttacks(actions))
+	e.Text = joinParagraphs(
+		m.Desc,
+		strings.Join(open5eNamed(m.SpecialAbilities), "\n\n"),
+		compendiumSection("Actions", actions),
+		compendiumSection("Bonus Actions", open5eNamed(m.BonusActions)),
+		compendiumSection("Reactions", open5eNamed(m.Reactions)),
+		compendiumSection("Legendary Actions", open5eNamed(m.LegendaryActions)),
+	)
+	return e
+}
+
+// open5eSpeed renders {"walk": 30, "fly": 60, "hover": true} as "30 ft.,
+// fly 60 ft. (hover)".
+func open5eSpeed(speed map[string]any) string {
+	var out []string
+	for _, mode := range []string{"walk", "burrow", "climb", "fly", "swim"} {
+		n, ok := speed[mode].(float64)
+		if !ok {
+			continue
+		}
+		s := strconv.Itoa(int(n)) + " ft."
+		if mode != "walk" {
+			s = mode + " " + s
+		}
+		if hover, _ := speed["hover"].(bool); hover && mode == "fly" {
+			s += " (hover)"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ", ")
+}
+
+// open5eSkills renders {"stealth": 6} as "Stealth +6", sorted by name.
+func open5eSkills(skills map[string]any) string {
+	names := make([]string, 0, len(skills))
+	for k := range skills {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	out := make([]string, 0, len(names))
+	for _, k := range names {
+		if n, ok := skills[k].(float64); ok {
+			out = append(out, fmt.Sprintf("%s%s %+d", strings.ToUpper(k[:1]), k[1:], int(n)))
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func (s open5eSpell) entry() CompendiumEntry {
+	e := s.base(CompendiumSpell)
+	e.addValue("spell_level", ordinalLevel(s.LevelInt))
+	e.addValue("school", s.School)
+	e.addValue("casting_time", s.CastingTime)
+	e.addValue("range", s.Range)
+	comps := s.Components
+	if s.Material != "" {
+		comps += " (" + strings.TrimSuffix(s.Material, ".") + ")"
+	}
+	e.addValue("components", comps)
+	duration := s.Duration
+	if strings.EqualFold(s.Concentration, "yes") && !strings.HasPrefix(strings.ToLower(duration), "concentration") {
+		duration = "Concentration, " + duration
+	}
+	e.addValue("duration", duration)
+	e.addValue("classes", s.DnDClass)
+	higher := s.HigherLevel
+	if higher != "" {
+		higher = "At Higher Levels. " + higher
+	}
+	e.Text = joinParagraphs(s.Desc, higher)
+	return e
+}
+
+func (it open5eItem) entry() CompendiumEntry {
+	e := it.base(CompendiumItem)
+	e.addValue("item_type", it.Type)
+	e.addValue("rarity", it.Rarity)
+	if it.RequiresAttunement != "" {
+		e.addValue("attunement", strings.ToUpper(it.RequiresAttunement[:1])+it.RequiresAttunement[1:])
+	}
+	e.Text = it.Desc
+	return e
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ContentImport records the page a compendium entry became.
+type ContentImport struct {
+	SourceKey string
+	EntityID  string
+	Source    string
+	License   string
+}
+
+// ContentImportRepository remembers which compendium entries a campaign
+// has imported. Rows go with their page, so deleting the page lets the
+// entry be imported afresh.
+type ContentImportRepository interface {
+	// FindEntities maps each of the keys already imported into the
+	// campaign to its page ID.
+	FindEntities(ctx context.Context, campaignID string, keys []string) (map[string]string, error)
+
+	// Record stores (or repoints) an import.
+	Record(ctx context.Context, campaignID string, imp ContentImport) error
+}
+
+// contentImportRepository implements ContentImportRepository with MariaDB.
+type contentImportRepository struct {
+	db *sql.DB
+}
+
+// NewContentImportRepository creates a content import repository.
+func NewContentImportRepository(db *sql.DB) ContentImportRepository {
+	return &contentImportRepository{db: db}
+}
+
+// FindEntities looks the keys up in batches to keep the IN list bounded.
+func (r *contentImportRepository) FindEntities(ctx context.Context, campaignID string, keys []string) (map[string]string, error) {
+	found := make(map[string]string)
+	for start := 0; start < len(keys); start += 500 {
+		batch := keys[start:min(start+500, len(keys))]
+		args := make([]any, 0, len(batch)+1)
+		args = append(args, campaignID)
+		for _, k := range batch {
+			args = append(args, k)
+		}
+		rows, err := r.db.QueryContext(ctx,
+			`SELECT source_key, entity_id FROM entity_content_imports
+			 WHERE campaign_id = ? AND source_key IN (?`+strings.Repeat(", ?", len(batch)-1)+`)`,
+			args...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("finding content imports: %w", err)
+		}
+		for rows.Next() {
+			var key, entityID string
+			if err := rows.Scan(&key, &entityID); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("scanning content import: %w", err)
+			}
+			found[key] = entityID
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("iterating content imports: %w", err)
+		}
+	}
+	return found, nil
+}
+
+// Record upserts on (campaign, key), so a refresh keeps one row.
+func (r *contentImportRepository) Record(ctx context.Context, campaignID string, imp ContentImport) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO entity_content_imports (campaign_id, source_key, entity_id, source, license)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE entity_id = VALUES(entity_id), source = VALUES(source),
+		   license = VALUES(license), imported_at = CURRENT_TIMESTAMP`,
+		campaignID, truncate(imp.SourceKey, 255), imp.EntityID,
+		truncate(imp.Source, 255), truncate(imp.License, 255),
+	)
+	if err != nil {
+		return fmt.Errorf("recording content import: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const fiveToolsSample = `{
+  "monster": [
+    {
+      "name": "Goblin", "source": "MM", "page": 166, "srd": true,
+      "size": ["S"], "type": {"type": "humanoid", "tags": ["goblinoid"]},
+      "alignment": ["N", "E"],
+      "ac": [{"ac": 15, "from": ["{@item leather armor|phb}", "{@item shield|phb}"]}],
+      "hp": {"average": 7, "formula": "2d6"},
+      "speed": {"walk": 30},
+      "str": 8, "dex": 14, "con": 10, "int": 10, "wis": 8, "cha": 8,
+      "skill": {"stealth": "+6"},
+      "senses": ["darkvision 60 ft."], "passive": 9,
+      "languages": ["Common", "Goblin"], "cr": "1/4",
+      "trait": [{"name": "Nimble Escape", "entries": ["The goblin can take the {@action Disengage} or {@action Hide} action as a bonus action on each of its turns."]}],
+      "action": [{"name": "Scimitar", "entries": ["{@atk mw} {@hit 4} to hit, reach 5 ft., one target. {@h}5 ({@damage 1d6 + 2}) slashing damage."]}]
+    },
+    {"name": "Goblin Boss", "source": "MM", "_copy": {"name": "Goblin", "source": "MM"}},
+    {
+      "name": "Beholder Zombie", "source": "MM", "page": 316,
+      "size": ["L"], "type": "undead", "alignment": ["N", "E"],
+      "ac": [15], "hp": {"average": 93, "formula": "11d10 + 33"},
+      "speed": {"walk": 0, "fly": {"number": 20, "condition": "(hover)"}, "canHover": true},
+      "str": 10, "dex": 8, "con": 16, "int": 3, "wis": 8, "cha": 5,
+      "cr": {"cr": "5"}
+    }
+  ],
+  "spell": [
+    {
+      "name": "Fireball", "source": "PHB", "page": 241, "srd": true,
+      "level": 3, "school": "V",
+      "time": [{"number": 1, "unit": "action"}],
+      "range": {"type": "point", "distance": {"type": "feet", "amount": 150}},
+      "components": {"v": true, "s": true, "m": "a tiny ball of bat guano and sulfur"},
+      "duration": [{"type": "instant"}],
+      "entries": ["A bright streak flashes from your pointing finger.", "Each creature in a 20-foot-radius sphere must make a {@dc 15} Dexterity saving throw."],
+      "entriesHigherLevel": [{"type": "entries", "name": "At Higher Levels", "entries": ["The damage increases by {@scaledamage 8d6|3-9|1d6} for each slot level above 3rd."]}]
+    },
+    {
+      "name": "Cone of Cold", "source": "PHB",
+      "level": 5, "school": "V",
+      "range": {"type": "cone", "distance": {"type": "feet", "amount": 60}},
+      "duration": [{"type": "timed", "duration": {"type": "minute", "amount": 10}, "concentration": true}]
+    }
+  ],
+  "item": [
+    {
+      "name": "Bag of Holding", "source": "DMG", "page": 153, "srd": true,
+      "wondrous": true, "rarity": "uncommon",
+      "entries": [{"type": "list", "items": ["Holds 500 pounds.", "Weighs 15 pounds."]}]
+    },
+    {"name": "Staff of Power", "source": "DMG", "type": "M", "rarity": "very rare", "reqAttune": "by a sorcerer, warlock, or wizard"}
+  ]
+}`
+
+// compendiumByName indexes entries by name.
+func compendiumByName(entries []CompendiumEntry) map[string]CompendiumEntry {
+	m := make(map[string]CompendiumEntry, len(entries))
+	for _, e := range entries {
+		m[e.Name] = e
+	}
+	return m
+}
+
+// compendiumValues indexes an entry's values by key.
+func compendiumValues(e CompendiumEntry) map[string]string {
+	return statblockValues(&ParsedStatblock{Values: e.Values})
+}
+
+func TestParseFiveTools(t *testing.T) {
+	entries, err := ParseFiveTools([]byte(fiveToolsSample))
+	if err != nil {
+		t.Fatalf("ParseFiveTools: %v", err)
+	}
+	byName := compendiumByName(entries)
+	if _, ok := byName["Goblin Boss"]; ok || len(entries) != 6 {
+		t.Fatalf("entries = %d; copies must be left out", len(entries))
+	}
+
+	cases := []struct {
+		name string
+		kind string
+		open bool
+		want map[string]string
+		text []string
+	}{
+		{
+			name: "Goblin", kind: CompendiumMonster, open: true,
+			want: map[string]string{
+				"size": "Small", "creature_type": "humanoid (goblinoid)", "alignment": "neutral evil",
+				"ac": "15 (leather armor, shield)", "hp": "7 (2d6)", "speed": "30 ft.",
+				"str": "8 (-1)", "dex": "14 (+2)", "skills": "Stealth +6",
+				"senses": "darkvision 60 ft., passive Perception 9", "cr": "1/4",
+				"attacks": "Scimitar: +4 to hit, 5 (1d6 + 2) slashing damage",
+				"source":  "MM p. 166", "license": "CC-BY-4.0 (SRD 5.1)",
+			},
+			text: []string{"Nimble Escape. The goblin can take the Disengage or Hide action", "Actions\n\nScimitar. Melee Weapon Attack: +4 to hit"},
+		},
+		{
+			name: "Beholder Zombie", kind: CompendiumMonster,
+			want: map[string]string{"creature_type": "undead", "ac": "15", "hp": "93 (11d10 + 33)", "speed": "0 ft., fly 20 ft. (hover)", "cha": "5 (-3)", "cr": "5"},
+		},
+		{
+			name: "Fireball", kind: CompendiumSpell, open: true,
+			want: map[string]string{
+				"spell_level": "3rd", "school": "Evocation", "casting_time": "1 action", "range": "150 feet",
+				"components": "V, S, M (a tiny ball of bat guano and sulfur)", "duration": "Instantaneous",
+			},
+			text: []string{"A bright streak flashes from your pointing finger.\n\nEach creature", "DC 15 Dexterity", "At Higher Levels. The damage increases by 1d6 for each"},
+		},
+		{
+			name: "Cone of Cold", kind: CompendiumSpell,
+			want: map[string]string{"spell_level": "5th", "range": "Self (60-foot cone)", "duration": "Concentration, up to 10 minutes"},
+		},
+		{
+			name: "Bag of Holding", kind: CompendiumItem, open: true,
+			want: map[string]string{"item_type": "Wondrous item", "rarity": "uncommon"},
+			text: []string{"- Holds 500 pounds.\n- Weighs 15 pounds."},
+		},
+		{
+			name: "Staff of Power", kind: CompendiumItem,
+			want: map[string]string{"item_type": "Melee weapon", "rarity": "very rare", "attunement": "Requires attunement by a sorcerer, warlock, or wizard", "source": "DMG"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, ok := byName[tc.name]
+			if !ok {
+				t.Fatalf("%s not parsed", tc.name)
+			}
+			if e.Kind != tc.kind || e.Open != tc.open {
+				t.Errorf("kind, open = %q, %v; want %q, %v", e.Kind, e.Open, tc.kind, tc.open)
+			}
+			got := compendiumValues(e)
+			for k, want := range tc.want {
+				if got[k] != want {
+					t.Errorf("%s = %q, want %q", k, got[k], want)
+				}
+			}
+			for _, want := range tc.text {
+				if !strings.Contains(e.Text, want) {
+					t.Errorf("text missing %q:\n%s", want, e.Text)
+				}
+			}
+		})
+	}
+	if k := byName["Goblin"].Key; k != "5etools:monster:goblin|mm" {
+		t.Errorf("key = %q", k)
+	}
+}
+
+func TestParseFiveTools_Rejects(t *testing.T) {
+	for _, data := range []string{"", "not json", `{"monster": []}`, `{"class": [{"name": "Fighter"}]}`} {
+		if _, err := ParseFiveTools([]byte(data)); err == nil {
+			t.Errorf("ParseFiveTools(%q) accepted", data)
+		}
+	}
+}
+
+func TestStripFiveToolsTags(t *testing.T) {
+	cases := map[string]string{
+		"{@item longsword|phb|long sword}": "long sword",
+		"{@b {@i nested}} text":            "nested text",
+		"{@recharge 5}":                    "(Recharge 5-6)",
+		"{@actSave dex} {@dc 13}":          "Dexterity Saving Throw: DC 13",
+		"{@atkr m} {@hit 5}, reach 5 ft.":  "Melee Attack Roll: +5, reach 5 ft.",
+		"no tags":                          "no tags",
+	}
+	for in, want := range cases {
+		if got := stripFiveToolsTags(in); got != want {
+			t.Errorf("stripFiveToolsTags(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOpen5eSearch(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.Query().Get("search")
+		_, _ = w.Write([]byte(`{"count": 1, "results": [{
+			"slug": "goblin", "name": "Goblin", "desc": "",
+			"size": "Small", "type": "humanoid", "subtype": "goblinoid", "alignment": "neutral evil",
+			"armor_class": 15, "armor_desc": "leather armor, shield", "hit_points": 7, "hit_dice": "2d6",
+			"speed": {"walk": 30}, "strength": 8, "dexterity": 14, "constitution": 10,
+			"intelligence": 10, "wisdom": 8, "charisma": 8, "dexterity_save": null, "wisdom_save": 1,
+			"skills": {"stealth": 6}, "senses": "darkvision 60 ft., passive Perception 9",
+			"languages": "Common, Goblin", "challenge_rating": "1/4",
+			"actions": [{"name": "Scimitar", "desc": "Melee Weapon Attack: +4 to hit, reach 5 ft., one target. Hit: 5 (1d6 + 2) slashing damage."}],
+			"reactions": "", "special_abilities": [{"name": "Nimble Escape", "desc": "The goblin can take the Disengage or Hide action as a bonus action."}],
+			"document__slug": "wotc-srd", "document__title": "5e Core Rules", "document__license_url": "http://open5e.com/legal"
+		}]}`))
+	}))
+	defer srv.Close()
+
+	client := &Open5eClient{httpClient: srv.Client(), baseURL: srv.URL}
+	entries, err := client.Search(context.Background(), CompendiumMonster, "gob")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if gotPath != "/v1/monsters/" || gotQuery != "gob" {
+		t.Errorf("requested %s?search=%s", gotPath, gotQuery)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d", len(entries))
+	}
+	e := entries[0]
+	if e.Key != "open5e:monster:goblin" || !e.Open || e.Source != "5e Core Rules" || e.License != "http://open5e.com/legal" {
+		t.Errorf("entry = %+v", e)
+	}
+	got := compendiumValues(e)
+	for k, want := range map[string]string{
+		"creature_type": "humanoid (goblinoid)", "ac": "15 (leather armor, shield)", "hp": "7 (2d6)",
+		"dex": "14 (+2)", "saves": "Wis +1", "skills": "Stealth +6",
+		"attacks": "Scimitar: +4 to hit, 5 (1d6 + 2) slashing damage",
+	} {
+		if got[k] != want {
+			t.Errorf("%s = %q, want %q", k, got[k], want)
+		}
+	}
+	if !strings.HasPrefix(e.Text, "Nimble Escape. The goblin") {
+		t.Errorf("text = %q", e.Text)
+	}
+
+	if _, err := client.Search(context.Background(), "vehicle", "cart"); err == nil {
+		t.Error("unknown kind accepted")
+	}
+	if _, err := client.Search(context.Background(), CompendiumSpell, "  "); err == nil {
+		t.Error("empty search accepted")
+	}
+}
+
+func TestOpen5eSearch_Unavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	client := &Open5eClient{httpClient: srv.Client(), baseURL: srv.URL}
+	if _, err := client.Search(context.Background(), CompendiumItem, "bag"); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("err = %v; want the HTTP status", err)
+	}
+}
+
+func TestBuildCompendiumPage(t *testing.T) {
+	e := CompendiumEntry{Name: "Goblin", Source: "MM p. 166", Text: "Small and mean."}
+	for _, v := range [][2]string{
+		{"ac", "15 (leather armor, shield)"}, {"hp", "7 (2d6)"}, {"cr", "1/4"},
+		{"senses", "darkvision 60 ft."}, {"attacks", "Scimitar: +4 to hit"}, {"source", "MM p. 166"},
+	} {
+		e.addValue(v[0], v[1])
+	}
+	fields := []FieldDefinition{
+		{Key: "armor_class", Label: "AC", Type: "number"},
+		{Key: "hit_points", Label: "Hit Points", Type: "text"},
+		{Key: "challenge", Label: "Challenge", Type: "number"},
+		{Key: "book", Label: "Book", Type: "text"},
+	}
+	page := buildCompendiumPage(e, fields)
+	want := map[string]any{"armor_class": "15", "hit_points": "7 (2d6)", "challenge": "1", "book": "MM p. 166"}
+	for k, v := range want {
+		if page.Fields[k] != v {
+			t.Errorf("%s = %v, want %v", k, page.Fields[k], v)
+		}
+	}
+	wantText := "Senses: darkvision 60 ft.\n\nSmall and mean.\n\nSource: MM p. 166. Not published under an open license; for use at your own table."
+	if page.Text != wantText {
+		t.Errorf("text = %q\nwant %q", page.Text, wantText)
+	}
+}
+
+func TestSuggestCompendiumType(t *testing.T) {
+	types := []EntityType{
+		{ID: 1, Slug: "character", Name: "Character", NamePlural: "Characters"},
+		{ID: 2, Slug: "creature", Name: "Creature", NamePlural: "Creatures"},
+		{ID: 3, Slug: "item", Name: "Item", NamePlural: "Items"},
+		{ID: 4, Slug: "npc", Name: "NPC", NamePlural: "NPCs"},
+	}
+	for kind, want := range map[string]int{CompendiumMonster: 2, CompendiumItem: 3, CompendiumSpell: 0} {
+		if got := suggestCompendiumType(kind, types); got != want {
+			t.Errorf("suggestCompendiumType(%s) = %d, want %d", kind, got, want)
+		}
+	}
+}
+
+func TestFilterCompendium(t *testing.T) {
+	entries := []CompendiumEntry{
+		{Name: "Fireball", Kind: CompendiumSpell}, {Name: "Goblin Boss", Kind: CompendiumMonster},
+		{Name: "goblin", Kind: CompendiumMonster}, {Name: "Goblin Mask", Kind: CompendiumItem},
+	}
+	var names []string
+	for _, e := range filterCompendium(entries, " GOB ") {
+		names = append(names, e.Name)
+	}
+	if got := strings.Join(names, ","); got != "goblin,Goblin Boss,Goblin Mask" {
+		t.Errorf("filtered = %s", got)
+	}
+}
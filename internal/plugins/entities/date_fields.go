@@ -0,0 +1,172 @@
+package entities
+
+// date_fields.go — the "date" custom field type. A date field holds an
+// in-game calendar date, stored in fields_data as "year-month-day" (year
+// may be negative: "-120-3-1"). Date fields flagged Anniversary (births,
+// foundings, deaths) put a yearly event on the campaign calendar linked
+// back to the entity; EntityService keeps those events in step with every
+// save through an EntityDateSyncer. Syncing is best-effort: a calendar
+// failure never fails the save.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+// FieldTypeDate is the field type for in-game calendar dates.
+const FieldTypeDate = "date"
+
+// String formats the date the way date fields store it.
+func (d GameDate) String() string {
+	return fmt.Sprintf("%d-%d-%d", d.Year, d.Month, d.Day)
+}
+
+// ParseGameDate reads a stored date field value: "year-month-day" from the
+// form and widget, or a {"year","month","day"} object from the API. Month
+// and day must be positive; the calendar decides whether they exist.
+func ParseGameDate(v any) (GameDate, bool) {
+	switch t := v.(type) {
+	case string:
+		s := strings.TrimSpace(t)
+		neg := strings.HasPrefix(s, "-")
+		parts := strings.Split(strings.TrimPrefix(s, "-"), "-")
+		if len(parts) != 3 {
+			return GameDate{}, false
+		}
+		var n [3]int
+		for i, p := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return GameDate{}, false
+			}
+			n[i] = v
+		}
+		d := GameDate{Year: n[0], Month: n[1], Day: n[2]}
+		if neg {
+			d.Year = -d.Year
+		}
+		return d, d.Month > 0 && d.Day > 0
+	case map[string]any:
+		year, yok := fieldNumber(t["year"])
+		month, mok := fieldNumber(t["month"])
+		day, dok := fieldNumber(t["day"])
+		if !yok || !mok || !dok || month < 1 || day < 1 {
+			return GameDate{}, false
+		}
+		return GameDate{Year: int(year), Month: int(month), Day: int(day)}, true
+	}
+	return GameDate{}, false
+}
+
+// EntityDate is one anniversary date field with a value.
+type EntityDate struct {
+	FieldKey string
+	Label    string
+	Date     GameDate
+}
+
+// EntityDateSyncer keeps an entity's anniversary events on the campaign
+// calendar matching its date fields. An empty dates list removes them.
+// Implemented in internal/app over the calendar plugin.
+type EntityDateSyncer interface {
+	SyncEntityDates(ctx context.Context, entity *Entity, dates []EntityDate) error
+}
+
+// noopEntityDateSyncer is the default when no calendar is wired.
+type noopEntityDateSyncer struct{}
+
+func (noopEntityDateSyncer) SyncEntityDates(context.Context, *Entity, []EntityDate) error {
+	return nil
+}
+
+// anniversaryDates returns the anniversary-flagged date fields holding a
+// readable date, in field order.
+func anniversaryDates(defs []FieldDefinition, fields map[string]any) []EntityDate {
+	var dates []EntityDate
+	for _, fd := range defs {
+		if fd.Type != FieldTypeDate || !fd.Anniversary {
+			continue
+		}
+		if d, ok := ParseGameDate(fields[fd.Key]); ok {
+			dates = append(dates, EntityDate{FieldKey: fd.Key, Label: fd.Label, Date: d})
+		}
+	}
+	return dates
+}
+
+// anniversaryFieldsChanged reports whether a type edit added, removed or
+// relabeled an anniversary date field, which is when its entities need a
+// resync.
+func anniversaryFieldsChanged(before, after []FieldDefinition) bool {
+	labels := func(defs []FieldDefinition) map[string]string {
+		m := map[string]string{}
+		for _, fd := range defs {
+			if fd.Type == FieldTypeDate && fd.Anniversary {
+				m[fd.Key] = fd.Label
+			}
+		}
+		return m
+	}
+	a, b := labels(before), labels(after)
+	if len(a) != len(b) {
+		return true
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return true
+		}
+	}
+	return false
+}
+
+// syncEntityDates pushes the entity's anniversary dates to the calendar.
+// Templates never contribute: they are scaffolding, not people or places.
+func (s *entityService) syncEntityDates(ctx context.Context, entity *Entity) {
+	if entity == nil {
+		return
+	}
+	var dates []EntityDate
+	if !entity.IsTemplate {
+		et, err := s.types.FindByID(ctx, entity.EntityTypeID)
+		if err != nil || et == nil {
+			return
+		}
+		dates = anniversaryDates(MergeFields(et.Fields, entity.FieldOverrides), entity.FieldsData)
+	}
+	if err := s.dateSyncer.SyncEntityDates(ctx, entity, dates); err != nil {
+		slog.Warn("syncing entity date events failed",
+			slog.String("entity_id", entity.ID),
+			slog.Any("error", err))
+	}
+}
+
+// resyncTypeDates re-syncs every entity of a type after its fields change,
+// so toggling a field's Anniversary flag adds or removes the events
+// without waiting for each entity's next save.
+func (s *entityService) resyncTypeDates(ctx context.Context, et *EntityType) {
+	if _, ok := s.dateSyncer.(noopEntityDateSyncer); ok {
+		return
+	}
+	const pageSize = 200
+	for page := 1; ; page++ {
+		batch, total, err := s.entities.ListByCampaign(ctx, et.CampaignID, []int{et.ID}, permissions.RoleOwner, "",
+			ListOptions{Page: page, PerPage: pageSize, Sort: "created"})
+		if err != nil {
+			slog.Warn("listing entities for date resync failed",
+				slog.Int("entity_type_id", et.ID),
+				slog.Any("error", err))
+			return
+		}
+		for i := range batch {
+			s.syncEntityDates(ctx, &batch[i])
+		}
+		if len(batch) == 0 || page*pageSize >= total {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package entities
+
+import "testing"
+
+func TestParseGameDate(t *testing.T) {
+	cases := []struct {
+		in   any
+		want GameDate
+		ok   bool
+	}{
+		{"1492-3-15", GameDate{1492, 3, 15}, true},
+		{" -120-12-1 ", GameDate{-120, 12, 1}, true},
+		{map[string]any{"year": float64(7), "month": float64(2), "day": "9"}, GameDate{7, 2, 9}, true},
+		{"1492-0-15", GameDate{}, false},
+		{"1492-3", GameDate{}, false},
+		{"spring", GameDate{}, false},
+		{float64(1492), GameDate{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := ParseGameDate(tc.in)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Errorf("ParseGameDate(%v) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+	if got := (GameDate{-120, 12, 1}).String(); got != "-120-12-1" {
+		t.Errorf("String() = %q; want round-trippable -120-12-1", got)
+	}
+}
+
+func TestAnniversaryDates(t *testing.T) {
+	defs := []FieldDefinition{
+		{Key: "born", Label: "Born", Type: FieldTypeDate, Anniversary: true},
+		{Key: "met", Label: "Met the party", Type: FieldTypeDate},
+		{Key: "died", Label: "Died", Type: FieldTypeDate, Anniversary: true},
+		{Key: "age", Label: "Age", Type: "number", Anniversary: true},
+	}
+	dates := anniversaryDates(defs, map[string]any{
+		"born": "1470-2-3",
+		"met":  "1490-1-1",
+		"died": "",
+		"age":  "1-1-1",
+	})
+	if len(dates) != 1 || dates[0].FieldKey != "born" || dates[0].Date != (GameDate{1470, 2, 3}) {
+		t.Errorf("anniversaryDates = %+v; want only the dated, flagged birth", dates)
+	}
+
+	if anniversaryFieldsChanged(defs, defs) {
+		t.Error("unchanged fields reported as changed")
+	}
+	relabeled := append([]FieldDefinition(nil), defs...)
+	relabeled[0].Label = "Birthday"
+	if !anniversaryFieldsChanged(defs, relabeled) {
+		t.Error("a relabeled anniversary field should trigger a resync")
+	}
+	unflagged := append([]FieldDefinition(nil), defs...)
+	unflagged[2].Anniversary = false
+	if !anniversaryFieldsChanged(defs, unflagged) {
+		t.Error("turning an anniversary off should trigger a resync")
+	}
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetDateIssueService enables the stranded date field list.
+func (h *Handler) SetDateIssueService(svc DateIssueService) {
+	h.dateIssueSvc = svc
+}
+
+// dateIssuesContext returns the campaign context once the service is known
+// to be wired.
+func (h *Handler) dateIssuesContext(c echo.Context) (*campaigns.CampaignContext, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, apperror.NewMissingContext()
+	}
+	if h.dateIssueSvc == nil {
+		return nil, apperror.NewNotFound("date checks are not available")
+	}
+	return cc, nil
+}
+
+// DateIssuesPage lists date fields that don't fit the campaign calendar.
+// GET /campaigns/:id/entities/date-issues
+func (h *Handler) DateIssuesPage(c echo.Context) error {
+	cc, err := h.dateIssuesContext(c)
+	if err != nil {
+		return err
+	}
+	issues, err := h.dateIssueSvc.ListIssues(c.Request().Context(), cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	csrfToken := middleware.GetCSRFToken(c)
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, DateIssuesContent(cc, issues, csrfToken))
+	}
+	return middleware.Render(c, http.StatusOK, DateIssuesPage(cc, issues, csrfToken))
+}
+
+// RecheckDateIssues re-checks every date field in the campaign now and
+// returns the refreshed list.
+// POST /campaigns/:id/entities/date-issues/recheck
+func (h *Handler) RecheckDateIssues(c echo.Context) error {
+	cc, err := h.dateIssuesContext(c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	if _, err := h.dateIssueSvc.Revalidate(ctx, cc.Campaign.ID); err != nil {
+		return err
+	}
+	issues, err := h.dateIssueSvc.ListIssues(ctx, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, DateIssuesContent(cc, issues, middleware.GetCSRFToken(c)))
+}
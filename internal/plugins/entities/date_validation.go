@@ -0,0 +1,320 @@
+package entities
+
+// date_validation.go — checking date fields against the campaign calendar.
+// Saves reject a date the calendar doesn't have (month 13, day 31 of a
+// 30-day month, a leap day outside a leap year). Changing the calendar can
+// strand dates already saved, so the calendar plugin reports structural
+// changes and DateIssueService re-checks every date field in the campaign,
+// flagging the ones that no longer fit in entity_date_issues.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+// DateCheck explains why a date doesn't exist on a calendar, or returns "".
+type DateCheck func(GameDate) string
+
+// GameDateValidator checks dates against a campaign's calendar.
+// Implemented in internal/app over the calendar plugin.
+type GameDateValidator interface {
+	// DateChecker returns the campaign calendar's check, or nil when the
+	// campaign has no calendar (any well-formed date is accepted).
+	DateChecker(ctx context.Context, campaignID string) (DateCheck, error)
+}
+
+// DateIssue is a date field whose value doesn't fit the calendar.
+type DateIssue struct {
+	EntityID   string    `json:"entity_id"`
+	EntityName string    `json:"entity_name"`
+	FieldKey   string    `json:"field_key"`
+	FieldLabel string    `json:"field_label"`
+	Value      string    `json:"value"`
+	Reason     string    `json:"reason"`
+	FlaggedAt  time.Time `json:"flagged_at"`
+}
+
+// dateFieldProblems lists the date fields in fields that aren't dates or,
+// when check is set, don't exist on the calendar. Empty values are fine.
+func dateFieldProblems(defs []FieldDefinition, fields map[string]any, check DateCheck) []DateIssue {
+	var issues []DateIssue
+	for _, fd := range defs {
+		if fd.Type != FieldTypeDate {
+			continue
+		}
+		raw, ok := fields[fd.Key]
+		if !ok || raw == nil || strings.TrimSpace(fmt.Sprint(raw)) == "" {
+			continue
+		}
+		issue := DateIssue{FieldKey: fd.Key, FieldLabel: fd.Label, Value: fmt.Sprint(raw)}
+		d, ok := ParseGameDate(raw)
+		if !ok {
+			issue.Reason = "isn't a date (use year-month-day, e.g. 1492-3-15)"
+		} else if check != nil {
+			issue.Reason = check(d)
+		}
+		if issue.Reason != "" {
+			if ok {
+				issue.Value = d.String()
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// validateDateFields rejects a save whose date fields don't fit the
+// campaign calendar. Only values the save changes are checked, so an
+// entity whose date a calendar edit stranded can still be saved (the flag
+// lists it for fixing). A calendar that can't be loaded only skips the
+// calendar check; malformed dates are still refused.
+func (s *entityService) validateDateFields(ctx context.Context, campaignID string, defs []FieldDefinition, fields, before map[string]any) error {
+	var changed []FieldDefinition
+	for _, fd := range defs {
+		if fd.Type == FieldTypeDate && fmt.Sprint(fields[fd.Key]) != fmt.Sprint(before[fd.Key]) {
+			changed = append(changed, fd)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	var check DateCheck
+	if s.dateValidator != nil {
+		var err error
+		if check, err = s.dateValidator.DateChecker(ctx, campaignID); err != nil {
+			slog.Warn("loading calendar for date fields failed",
+				slog.String("campaign_id", campaignID),
+				slog.Any("error", err))
+		}
+	}
+	if issues := dateFieldProblems(changed, fields, check); len(issues) > 0 {
+		return apperror.NewValidation(fmt.Sprintf("%s: %s", issues[0].FieldLabel, issues[0].Reason))
+	}
+	return nil
+}
+
+// validateEntityDateFields checks an existing entity's new field values
+// against its type's (and overrides') date fields.
+func (s *entityService) validateEntityDateFields(ctx context.Context, entity *Entity, fields map[string]any) error {
+	et, err := s.types.FindByID(ctx, entity.EntityTypeID)
+	if err != nil || et == nil {
+		return nil
+	}
+	return s.validateDateFields(ctx, entity.CampaignID, MergeFields(et.Fields, entity.FieldOverrides), fields, entity.FieldsData)
+}
+
+// DateIssueRepository persists flagged date fields.
+type DateIssueRepository interface {
+	// Replace makes the campaign's flags exactly issues.
+	Replace(ctx context.Context, campaignID string, issues []DateIssue) error
+	ListByCampaign(ctx context.Context, campaignID string) ([]DateIssue, error)
+	Delete(ctx context.Context, entityID, fieldKey string) error
+}
+
+// dateIssueRepository implements DateIssueRepository with MariaDB.
+type dateIssueRepository struct {
+	db *sql.DB
+}
+
+// NewDateIssueRepository creates a date issue repository.
+func NewDateIssueRepository(db *sql.DB) DateIssueRepository {
+	return &dateIssueRepository{db: db}
+}
+
+// Replace swaps the campaign's flags in one transaction.
+func (r *dateIssueRepository) Replace(ctx context.Context, campaignID string, issues []DateIssue) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM entity_date_issues WHERE campaign_id = ?`, campaignID); err != nil {
+		return fmt.Errorf("clearing date issues: %w", err)
+	}
+	for _, is := range issues {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO entity_date_issues (entity_id, field_key, campaign_id, value, reason, flagged_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			is.EntityID, is.FieldKey, campaignID, truncate(is.Value, 100), truncate(is.Reason, 255), is.FlaggedAt,
+		); err != nil {
+			return fmt.Errorf("flagging date issue: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListByCampaign returns the campaign's flags by entity name.
+func (r *dateIssueRepository) ListByCampaign(ctx context.Context, campaignID string) ([]DateIssue, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT i.entity_id, e.name, i.field_key, i.value, i.reason, i.flagged_at
+		 FROM entity_date_issues i
+		 JOIN entities e ON e.id = i.entity_id
+		 WHERE i.campaign_id = ?
+		 ORDER BY e.name, i.field_key`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing date issues: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []DateIssue
+	for rows.Next() {
+		var is DateIssue
+		if err := rows.Scan(&is.EntityID, &is.EntityName, &is.FieldKey, &is.Value, &is.Reason, &is.FlaggedAt); err != nil {
+			return nil, fmt.Errorf("scanning date issue: %w", err)
+		}
+		issues = append(issues, is)
+	}
+	return issues, rows.Err()
+}
+
+// Delete drops one flag.
+func (r *dateIssueRepository) Delete(ctx context.Context, entityID, fieldKey string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM entity_date_issues WHERE entity_id = ? AND field_key = ?`, entityID, fieldKey)
+	return err
+}
+
+// truncate cuts s to at most n bytes on a rune boundary.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// DateIssueService re-checks date fields against the calendar and serves
+// the flagged list.
+type DateIssueService interface {
+	// Revalidate re-checks every date field in the campaign and replaces
+	// its flags. Returns how many fields are flagged.
+	Revalidate(ctx context.Context, campaignID string) (int, error)
+	// Scan lists the campaign's date fields check rejects without
+	// flagging them, so a proposed calendar change can be previewed.
+	Scan(ctx context.Context, campaignID string, check DateCheck) ([]DateIssue, error)
+	// ListIssues returns the flags still true: a flag whose entity was
+	// since fixed (or whose field is gone) is dropped on the way out.
+	ListIssues(ctx context.Context, campaignID string) ([]DateIssue, error)
+}
+
+// dateIssuePageSize is the entity batch size when re-checking a campaign.
+const dateIssuePageSize = 200
+
+// dateIssueService implements DateIssueService.
+type dateIssueService struct {
+	repo      DateIssueRepository
+	entities  EntityRepository
+	types     EntityTypeRepository
+	validator GameDateValidator
+	now       func() time.Time
+}
+
+// NewDateIssueService creates a date issue service.
+func NewDateIssueService(repo DateIssueRepository, entities EntityRepository, types EntityTypeRepository, validator GameDateValidator) DateIssueService {
+	return &dateIssueService{repo: repo, entities: entities, types: types, validator: validator, now: time.Now}
+}
+
+// Revalidate scans the campaign's entities against its calendar and
+// stores the result as its flags.
+func (s *dateIssueService) Revalidate(ctx context.Context, campaignID string) (int, error) {
+	check, err := s.validator.DateChecker(ctx, campaignID)
+	if err != nil {
+		return 0, err
+	}
+	issues, err := s.Scan(ctx, campaignID, check)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.Replace(ctx, campaignID, issues); err != nil {
+		return 0, err
+	}
+	return len(issues), nil
+}
+
+// Scan walks the campaign's entities of types (or overrides) with date
+// fields.
+func (s *dateIssueService) Scan(ctx context.Context, campaignID string, check DateCheck) ([]DateIssue, error) {
+	types, err := s.types.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	typeFields := make(map[int][]FieldDefinition, len(types))
+	for _, et := range types {
+		typeFields[et.ID] = et.Fields
+	}
+
+	now := s.now().UTC()
+	var issues []DateIssue
+	for page := 1; ; page++ {
+		batch, total, err := s.entities.ListByCampaign(ctx, campaignID, nil, permissions.RoleOwner, "",
+			ListOptions{Page: page, PerPage: dateIssuePageSize, Sort: "created"})
+		if err != nil {
+			return nil, fmt.Errorf("listing entities for date check: %w", err)
+		}
+		for _, e := range batch {
+			for _, is := range dateFieldProblems(MergeFields(typeFields[e.EntityTypeID], e.FieldOverrides), e.FieldsData, check) {
+				is.EntityID, is.EntityName, is.FlaggedAt = e.ID, e.Name, now
+				issues = append(issues, is)
+			}
+		}
+		if len(batch) == 0 || page*dateIssuePageSize >= total {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// ListIssues re-checks each flag against the entity's current value.
+func (s *dateIssueService) ListIssues(ctx context.Context, campaignID string) ([]DateIssue, error) {
+	flagged, err := s.repo.ListByCampaign(ctx, campaignID)
+	if err != nil || len(flagged) == 0 {
+		return flagged, err
+	}
+	check, err := s.validator.DateChecker(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string][]DateIssue) // entity id → its problems now
+	var open []DateIssue
+	for _, is := range flagged {
+		problems, seen := current[is.EntityID]
+		if !seen {
+			e, err := s.entities.FindByID(ctx, is.EntityID)
+			if err == nil && e != nil {
+				if et, err := s.types.FindByID(ctx, e.EntityTypeID); err == nil && et != nil {
+					problems = dateFieldProblems(MergeFields(et.Fields, e.FieldOverrides), e.FieldsData, check)
+				}
+			}
+			current[is.EntityID] = problems
+		}
+		still := false
+		for _, p := range problems {
+			if p.FieldKey == is.FieldKey {
+				is.FieldLabel, is.Value, is.Reason, still = p.FieldLabel, p.Value, p.Reason, true
+				break
+			}
+		}
+		if !still {
+			if err := s.repo.Delete(ctx, is.EntityID, is.FieldKey); err != nil {
+				slog.Warn("dropping resolved date issue failed",
+					slog.String("entity_id", is.EntityID),
+					slog.Any("error", err))
+			}
+			continue
+		}
+		open = append(open, is)
+	}
+	return open, nil
+}
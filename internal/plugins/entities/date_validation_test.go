@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// thirtyDayMonths is a calendar check with twelve 30-day months.
+func thirtyDayMonths(d GameDate) string {
+	if d.Month > 12 {
+		return "no such month"
+	}
+	if d.Day > 30 {
+		return "only 30 days"
+	}
+	return ""
+}
+
+type stubDateValidator struct{ check DateCheck }
+
+func (v stubDateValidator) DateChecker(context.Context, string) (DateCheck, error) {
+	return v.check, nil
+}
+
+func TestDateFieldProblems(t *testing.T) {
+	defs := []FieldDefinition{
+		{Key: "born", Label: "Born", Type: FieldTypeDate},
+		{Key: "died", Label: "Died", Type: FieldTypeDate},
+		{Key: "crowned", Label: "Crowned", Type: FieldTypeDate},
+		{Key: "blank", Label: "Blank", Type: FieldTypeDate},
+		{Key: "title", Label: "Title", Type: "text"},
+	}
+	fields := map[string]any{
+		"born":    "1470-2-31",
+		"died":    "1490-5-1",
+		"crowned": "midsummer",
+		"blank":   "",
+		"title":   "1-99-99",
+	}
+
+	got := dateFieldProblems(defs, fields, thirtyDayMonths)
+	if len(got) != 2 || got[0].FieldKey != "born" || got[1].FieldKey != "crowned" {
+		t.Fatalf("problems = %+v; want born (day 31) and crowned (not a date)", got)
+	}
+	if got[0].Reason != "only 30 days" || got[0].Value != "1470-2-31" {
+		t.Errorf("born = %+v; want the calendar's reason and the stored date", got[0])
+	}
+	if !strings.Contains(got[1].Reason, "isn't a date") {
+		t.Errorf("crowned reason = %q; want a malformed-date reason", got[1].Reason)
+	}
+
+	// Without a calendar only malformed values are problems.
+	if got := dateFieldProblems(defs, fields, nil); len(got) != 1 || got[0].FieldKey != "crowned" {
+		t.Errorf("problems without a calendar = %+v; want only crowned", got)
+	}
+}
+
+func TestUpdateFields_RejectsDateOffCalendar(t *testing.T) {
+	typeRepo := &mockEntityTypeRepo{
+		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
+			return &EntityType{ID: 1, CampaignID: "camp-1", Fields: []FieldDefinition{
+				{Key: "born", Label: "Born", Type: FieldTypeDate},
+			}}, nil
+		},
+	}
+	stored := map[string]any{"born": "1470-2-31"} // stranded by a calendar edit
+	entityRepo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, CampaignID: "camp-1", EntityTypeID: 1, FieldsData: stored}, nil
+		},
+	}
+	svc := newTestService(entityRepo, typeRepo)
+	svc.SetGameDateValidator(stubDateValidator{check: thirtyDayMonths})
+	ctx := context.Background()
+
+	err := svc.UpdateFields(ctx, "ent-1", map[string]any{"born": "1470-13-1"})
+	assertAppError(t, err, http.StatusUnprocessableEntity)
+
+	// An untouched stranded date doesn't block saving other fields.
+	if err := svc.UpdateFields(ctx, "ent-1", map[string]any{"born": "1470-2-31", "notes": "x"}); err != nil {
+		t.Errorf("saving with an unchanged stranded date: %v", err)
+	}
+	if err := svc.UpdateFields(ctx, "ent-1", map[string]any{"born": "1470-2-30"}); err != nil {
+		t.Errorf("saving a valid date: %v", err)
+	}
+}
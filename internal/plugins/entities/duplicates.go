@@ -0,0 +1,139 @@
+package entities
+
+// duplicates.go — duplicate name warnings when creating a page. The new
+// page form checks the name as it's typed and lists existing pages with the
+// same or a very similar name; quick-create returns the same candidates
+// alongside the page it made. Nothing is blocked: two pages may share a
+// name on purpose, so this only catches accidents before they diverge.
+//
+//	GET /campaigns/:id/entities/similar-names?name=   HTMX warning fragment
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Duplicate detection limits.
+const (
+	// similarNameMinScore is the imageNameScore a name needs to count as a
+	// likely duplicate: close spellings ("Gandolf") pass, names that merely
+	// share a word ("Goblin" and "Goblin Boss", 75) do not.
+	similarNameMinScore = 80
+	// maxSimilarNames caps the candidates shown.
+	maxSimilarNames = 5
+)
+
+// SimilarName is an existing page whose name or alias resembles a new
+// page's name.
+type SimilarName struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Alias    string `json:"alias,omitempty"` // The alias that matched, when it wasn't the name.
+	TypeName string `json:"type_name"`
+	TypeIcon string `json:"type_icon"`
+	URL      string `json:"url"`
+	Exact    bool   `json:"exact"`
+	Score    int    `json:"-"`
+}
+
+// findSimilarNames returns the pages whose name or an alias matches name
+// exactly (ignoring case and punctuation) or nearly, best first. Each page
+// appears once, under its best match.
+func findSimilarNames(name string, names []EntityNameEntry) []SimilarName {
+	key := normalizeImageName(name, false)
+	if key == "" {
+		return nil
+	}
+
+	display := make(map[string]string, len(names))
+	for _, n := range names {
+		if !n.IsAlias {
+			display[n.ID] = n.Name
+		}
+	}
+
+	best := make(map[string]SimilarName)
+	for _, n := range names {
+		score := imageNameScore(key, normalizeImageName(n.Name, false))
+		if score < similarNameMinScore {
+			continue
+		}
+		if prev, ok := best[n.ID]; ok && prev.Score >= score {
+			continue
+		}
+		s := SimilarName{
+			ID:       n.ID,
+			Name:     n.Name,
+			TypeName: n.TypeName,
+			TypeIcon: n.TypeIcon,
+			Exact:    score == 100,
+			Score:    score,
+		}
+		if n.IsAlias {
+			s.Alias = n.Name
+			if d, ok := display[n.ID]; ok {
+				s.Name = d
+			}
+		}
+		best[n.ID] = s
+	}
+
+	out := make([]SimilarName, 0, len(best))
+	for _, s := range best {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	if len(out) > maxSimilarNames {
+		out = out[:maxSimilarNames]
+	}
+	return out
+}
+
+// similarNames lists the viewer-visible pages resembling name. A lookup
+// failure is logged and treated as no candidates, since the warning is
+// advisory.
+func (h *Handler) similarNames(c echo.Context, cc *campaigns.CampaignContext, name string) []SimilarName {
+	if strings.TrimSpace(name) == "" {
+		return nil
+	}
+	names, err := h.service.ListSwitcherNames(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		slog.Warn("similar name lookup failed", slog.String("campaign_id", cc.Campaign.ID), slog.Any("error", err))
+		return nil
+	}
+	similar := findSimilarNames(name, names)
+	for i := range similar {
+		similar[i].URL = fmt.Sprintf("/campaigns/%s/entities/%s", cc.Campaign.ID, similar[i].ID)
+	}
+	return similar
+}
+
+// SimilarNamesFragment renders the duplicate name warning under the new
+// page form's name input; empty when nothing resembles the name.
+// GET /campaigns/:id/entities/similar-names?name=
+func (h *Handler) SimilarNamesFragment(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	name := c.QueryParam("name")
+	if err := apperror.ValidateStringLength("name", name, apperror.MaxNameLength); err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, similarNamesWarning(cc, h.similarNames(c, cc, name)))
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "testing"
+
+func TestFindSimilarNames(t *testing.T) {
+	names := []EntityNameEntry{
+		{ID: "e1", Name: "Gandalf", TypeName: "Character"},
+		{ID: "e1", Name: "Mithrandir", IsAlias: true},
+		{ID: "e2", Name: "Gandalf's Staff", TypeName: "Item"},
+		{ID: "e3", Name: "Goblin Boss"},
+		{ID: "e4", Name: "Rivendell"},
+	}
+
+	got := findSimilarNames("gandalf", names)
+	if len(got) != 1 || got[0].ID != "e1" || !got[0].Exact {
+		t.Fatalf("exact match = %+v", got)
+	}
+
+	got = findSimilarNames("Gandolf", names)
+	if len(got) != 1 || got[0].ID != "e1" || got[0].Exact {
+		t.Fatalf("near match = %+v", got)
+	}
+
+	got = findSimilarNames("Mithrandir", names)
+	if len(got) != 1 || got[0].Name != "Gandalf" || got[0].Alias != "Mithrandir" {
+		t.Fatalf("alias match = %+v", got)
+	}
+
+	// Sharing a word isn't enough to warn.
+	if got = findSimilarNames("Goblin", names); len(got) != 0 {
+		t.Errorf("word overlap matched: %+v", got)
+	}
+	if got = findSimilarNames("  ", names); got != nil {
+		t.Errorf("blank name matched: %+v", got)
+	}
+}
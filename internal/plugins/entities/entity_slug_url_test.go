@@ -0,0 +1,126 @@
+// entity_slug_url_test.go — slug URL resolution for the entity show routes.
+// A retired slug must 301 to where the entity lives now (the ID URL from
+// /entities/:eid, the current slug URL from /e/:eslug), and the redirect
+// must not fire for an entity the viewer can't see — otherwise the Location
+// header would confirm a private page exists.
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// stubSvcForSlugURLs knows two entities in campaign c1: "ent-1" (now
+// "the-grey", formerly "gandalf") and the hidden "ent-2" (formerly "secret").
+type stubSvcForSlugURLs struct {
+	EntityService
+}
+
+var slugURLEntities = map[string]*Entity{
+	"ent-1": {ID: "ent-1", CampaignID: "c1", Slug: "the-grey"},
+	"ent-2": {ID: "ent-2", CampaignID: "c1", Slug: "hidden"},
+}
+
+func (s *stubSvcForSlugURLs) GetByID(_ context.Context, id string) (*Entity, error) {
+	if e, ok := slugURLEntities[id]; ok {
+		return e, nil
+	}
+	return nil, apperror.NewNotFound("entity not found")
+}
+
+func (s *stubSvcForSlugURLs) GetBySlug(_ context.Context, _, slug string) (*Entity, error) {
+	for _, e := range slugURLEntities {
+		if e.Slug == slug {
+			return e, nil
+		}
+	}
+	return nil, apperror.NewNotFound("entity not found")
+}
+
+func (s *stubSvcForSlugURLs) ResolveSlug(_ context.Context, _, slug string) (*Entity, error) {
+	retired := map[string]string{"gandalf": "ent-1", "secret": "ent-2"}
+	if id, ok := retired[slug]; ok {
+		return slugURLEntities[id], nil
+	}
+	return nil, apperror.NewNotFound("entity not found")
+}
+
+func (s *stubSvcForSlugURLs) CheckEntityAccess(_ context.Context, entityID string, _ int, _ string) (*EffectivePermission, error) {
+	return &EffectivePermission{CanView: entityID != "ent-2"}, nil
+}
+
+func TestEntitySlugURLs_RedirectRetiredSlugs(t *testing.T) {
+	cases := []struct {
+		name         string
+		show         func(h *Handler, c echo.Context) error
+		param, value string
+		query        string
+		wantLocation string
+		wantCode     int
+	}{
+		{
+			name: "ID route, retired slug → ID URL", show: (*Handler).Show,
+			param: "eid", value: "gandalf", wantLocation: "/campaigns/c1/entities/ent-1",
+		},
+		{
+			name: "ID route keeps the query string", show: (*Handler).Show,
+			param: "eid", value: "gandalf", query: "tab=notes", wantLocation: "/campaigns/c1/entities/ent-1?tab=notes",
+		},
+		{
+			name: "slug route, retired slug → current slug URL", show: (*Handler).ShowBySlug,
+			param: "eslug", value: "gandalf", wantLocation: "/campaigns/waterdeep/e/the-grey",
+		},
+		{
+			name: "hidden entity's retired slug → 404, no redirect", show: (*Handler).ShowBySlug,
+			param: "eslug", value: "secret", wantCode: http.StatusNotFound,
+		},
+		{
+			name: "unknown slug → 404", show: (*Handler).ShowBySlug,
+			param: "eslug", value: "nobody", wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &Handler{service: &stubSvcForSlugURLs{}}
+			e := echo.New()
+			target := "/campaigns/c1/x/" + tc.value
+			if tc.query != "" {
+				target += "?" + tc.query
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+			c.SetParamNames("id", tc.param)
+			c.SetParamValues("c1", tc.value)
+			c.Set("campaign_context", &campaigns.CampaignContext{
+				Campaign:   &campaigns.Campaign{ID: "c1", Slug: "waterdeep"},
+				MemberRole: campaigns.RolePlayer,
+			})
+
+			err := tc.show(h, c)
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+				if loc := rec.Header().Get("Location"); loc != "" {
+					t.Errorf("denied request redirected to %q", loc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != http.StatusMovedPermanently {
+				t.Errorf("status = %d, want 301", rec.Code)
+			}
+			if loc := rec.Header().Get("Location"); loc != tc.wantLocation {
+				t.Errorf("Location = %q, want %q", loc, tc.wantLocation)
+			}
+		})
+	}
+}
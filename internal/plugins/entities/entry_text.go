@@ -0,0 +1,296 @@
+package entities
+
+// entry_text.go — a plain-text rendition of an entity's entry for screen
+// readers, text-to-speech and "read this aloud at the table" prep. Inline
+// secrets are dropped for every role, the GM included: the point of the
+// export is to be read out, and a secret read out is no longer one.
+// Mentions become the plain name, headings stay on their own line, and
+// lists and tables keep one item or row per line so a TTS engine pauses
+// in the right places.
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// GetEntryText returns the entry as plain text
+// (GET /campaigns/:id/entities/:eid/entry.txt). Same visibility gate as
+// GetEntry.
+func (h *Handler) GetEntryText(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	entity, err := h.service.GetByID(c.Request().Context(), c.Param("eid"))
+	if err != nil {
+		return err
+	}
+	if entity.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity not found")
+	}
+	access, err := h.service.CheckEntityAccess(c.Request().Context(), entity.ID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+
+	body := ""
+	if entity.EntryHTML != nil {
+		body = entryPlainText(*entity.EntryHTML)
+	}
+	text := entity.Name + "\n"
+	if body != "" {
+		text += "\n" + body + "\n"
+	}
+
+	c.Response().Header().Set("Content-Disposition", `inline; filename="`+entity.Slug+`.txt"`)
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.String(http.StatusOK, text)
+}
+
+// entryPlainText flattens sanitized entry HTML to plain text. Secret spans
+// are skipped by walking the parsed tree rather than with StripSecretsHTML's
+// regex, so a secret containing nested markup can't leak its tail.
+func entryPlainText(entryHTML string) string {
+	if strings.TrimSpace(entryHTML) == "" {
+		return ""
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(entryHTML),
+		&html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return ""
+	}
+	w := &plainTextWriter{lineStart: true}
+	for _, n := range nodes {
+		w.walk(n, 0)
+	}
+	return w.b.String()
+}
+
+// plainTextWriter collapses HTML whitespace the way a browser would and
+// buffers line breaks until the next visible text, so empty paragraphs and
+// trailing breaks don't turn into runs of blank lines.
+type plainTextWriter struct {
+	b            strings.Builder
+	pendingLines int
+	pendingSpace bool
+	lineStart    bool
+	// afterMarker holds off line breaks between a list marker and its
+	// text: TipTap wraps each <li> body in a <p>.
+	afterMarker bool
+	// inCell flattens blocks inside table cells (also <p>-wrapped) so a
+	// row stays on one line.
+	inCell bool
+}
+
+// breakLines asks for at least n newlines before the next text.
+func (w *plainTextWriter) breakLines(n int) {
+	if w.afterMarker {
+		return
+	}
+	if w.inCell {
+		w.pendingSpace = true
+		return
+	}
+	if n > w.pendingLines {
+		w.pendingLines = n
+	}
+}
+
+// flush writes buffered breaks (never at the very start) or a space.
+func (w *plainTextWriter) flush() {
+	if w.pendingLines > 0 {
+		if w.b.Len() > 0 {
+			w.b.WriteString(strings.Repeat("\n", w.pendingLines))
+		}
+		w.pendingLines = 0
+		w.pendingSpace = false
+		w.lineStart = true
+		return
+	}
+	if w.pendingSpace && !w.lineStart {
+		w.b.WriteByte(' ')
+	}
+	w.pendingSpace = false
+}
+
+// text writes inline text with whitespace collapsed.
+func (w *plainTextWriter) text(s string) {
+	collapsed := strings.Join(strings.Fields(s), " ")
+	if collapsed == "" {
+		if s != "" {
+			w.pendingSpace = true
+		}
+		return
+	}
+	if strings.TrimLeftFunc(s, unicode.IsSpace) != s {
+		w.pendingSpace = true
+	}
+	w.flush()
+	w.b.WriteString(collapsed)
+	w.lineStart = false
+	w.afterMarker = false
+	w.pendingSpace = strings.TrimRightFunc(s, unicode.IsSpace) != s
+}
+
+// prefix starts a line with a list marker.
+func (w *plainTextWriter) prefix(s string) {
+	if w.afterMarker {
+		// The previous item was empty; still give this one its own line.
+		w.afterMarker = false
+		w.breakLines(1)
+	}
+	w.flush()
+	w.b.WriteString(s)
+	w.lineStart = true
+	w.afterMarker = true
+}
+
+func (w *plainTextWriter) walk(n *html.Node, listDepth int) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if hasHTMLAttr(n, "data-secret") {
+		return
+	}
+	switch n.DataAtom {
+	case atom.Script, atom.Style:
+		return
+	case atom.A:
+		if hasHTMLAttr(n, "data-mention-id") {
+			w.text(strings.TrimPrefix(strings.TrimSpace(nodeText(n)), "@"))
+			return
+		}
+	case atom.Img:
+		if alt := htmlAttr(n, "alt"); strings.TrimSpace(alt) != "" {
+			w.text(alt)
+		}
+		return
+	case atom.Br:
+		w.breakLines(1)
+		return
+	case atom.Hr:
+		w.breakLines(2)
+		return
+	case atom.Ul, atom.Ol:
+		w.breakLines(blockBreak(listDepth))
+		for i, li := 0, n.FirstChild; li != nil; li = li.NextSibling {
+			if li.DataAtom != atom.Li {
+				w.walk(li, listDepth+1)
+				continue
+			}
+			i++
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(i) + ". "
+			}
+			w.breakLines(1)
+			w.prefix(strings.Repeat("  ", listDepth) + marker)
+			w.children(li, listDepth+1)
+		}
+		w.breakLines(blockBreak(listDepth))
+		return
+	case atom.Tr:
+		// One row per line, cells separated so TTS pauses between them.
+		w.breakLines(1)
+		first := true
+		for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom != atom.Td && cell.DataAtom != atom.Th {
+				continue
+			}
+			if !first {
+				w.b.WriteString(",")
+				w.pendingSpace = true
+			}
+			first = false
+			w.inCell = true
+			w.children(cell, listDepth)
+			w.inCell = false
+		}
+		w.breakLines(1)
+		return
+	}
+
+	block := isPlainTextBlock(n.DataAtom)
+	if block {
+		w.breakLines(blockBreak(listDepth))
+	}
+	w.children(n, listDepth)
+	if block {
+		w.breakLines(blockBreak(listDepth))
+	}
+}
+
+func (w *plainTextWriter) children(n *html.Node, listDepth int) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c, listDepth)
+	}
+}
+
+// blockBreak separates blocks by a blank line, or a single newline inside
+// a list so items stay together.
+func blockBreak(listDepth int) int {
+	if listDepth > 0 {
+		return 1
+	}
+	return 2
+}
+
+func isPlainTextBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Blockquote, atom.Pre, atom.Table, atom.Figure, atom.Figcaption:
+		return true
+	}
+	return false
+}
+
+func hasHTMLAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// nodeText concatenates a node's text descendants.
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package entities
+
+import "testing"
+
+func TestEntryPlainText(t *testing.T) {
+	cases := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"empty", "", ""},
+		{"paragraphs and whitespace",
+			"<p>The  road\n north.</p><p></p><p>Rain.</p>",
+			"The road north.\n\nRain."},
+		{"headings keep their own line",
+			"<h2>History</h2><p>Founded long ago.</p>",
+			"History\n\nFounded long ago."},
+		{"mentions flatten to the name",
+			`<p>Ask <a href="/campaigns/c1/entities/e1" data-mention-id="e1">@Mira Vane</a> first.</p>`,
+			"Ask Mira Vane first."},
+		{"secrets never read out",
+			`<p>The duke <span data-secret="true">is a <strong>vampire</strong></span>seems kind.</p>`,
+			"The duke seems kind."},
+		{"lists one item per line",
+			"<ul><li><p>Rope</p></li><li><p>Lantern</p><ol><li><p>Oil</p></li></ol></li></ul><p>Done.</p>",
+			"- Rope\n- Lantern\n  1. Oil\n\nDone."},
+		{"table rows",
+			"<table><tr><th><p>Name</p></th><th><p>HP</p></th></tr><tr><td><p>Goblin</p></td><td><p>7</p></td></tr></table>",
+			"Name, HP\nGoblin, 7"},
+		{"line breaks and entities",
+			"<p>Line one<br>Line &amp; two</p>",
+			"Line one\nLine & two"},
+		{"image alt text",
+			`<p>Map: <img src="/media/x" alt="the Sunken Vale"></p>`,
+			"Map: the Sunken Vale"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := entryPlainText(tc.html); got != tc.want {
+				t.Errorf("entryPlainText() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
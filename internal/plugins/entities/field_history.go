@@ -0,0 +1,250 @@
+package entities
+
+// field_history.go — change history for numeric custom fields. Every save
+// that changes a "number" field's value appends a point (old and new value,
+// when, and the campaign calendar's date if it has one), so a GM can chart
+// faction influence, a town's population or a PC's reputation over the
+// campaign. Recording hangs off EntityService via FieldHistoryRecorder and
+// is best-effort: a lost point never fails the save.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldHistoryLimit caps the points one history request returns.
+const fieldHistoryLimit = 500
+
+// GameDate is an in-game calendar date.
+type GameDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// FieldHistoryPoint is one recorded change of a numeric field.
+type FieldHistoryPoint struct {
+	OldValue  *float64  `json:"old_value"`
+	Value     float64   `json:"value"`
+	GameDate  *GameDate `json:"game_date,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// GameDateSource reports a campaign's current in-game date, or nil when it
+// has no calendar. Implemented in internal/app over the calendar plugin.
+type GameDateSource interface {
+	CurrentGameDate(ctx context.Context, campaignID string) (*GameDate, error)
+}
+
+// FieldHistoryRecorder is told about every field save with the values from
+// before it. Wired into EntityService via SetFieldHistoryRecorder.
+type FieldHistoryRecorder interface {
+	RecordFieldChanges(ctx context.Context, entity *Entity, before map[string]any)
+}
+
+// noopFieldHistoryRecorder is the default when no history is wired.
+type noopFieldHistoryRecorder struct{}
+
+func (noopFieldHistoryRecorder) RecordFieldChanges(context.Context, *Entity, map[string]any) {}
+
+// FieldHistoryRepository persists numeric field history.
+type FieldHistoryRepository interface {
+	Record(ctx context.Context, entityID, campaignID, fieldKey string, oldValue *float64, newValue float64, date *GameDate, at time.Time) error
+
+	// ListByField returns the field's most recent points, oldest first.
+	ListByField(ctx context.Context, entityID, fieldKey string, limit int) ([]FieldHistoryPoint, error)
+}
+
+// fieldHistoryRepository implements FieldHistoryRepository with MariaDB.
+type fieldHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewFieldHistoryRepository creates a field history repository.
+func NewFieldHistoryRepository(db *sql.DB) FieldHistoryRepository {
+	return &fieldHistoryRepository{db: db}
+}
+
+// Record appends one point.
+func (r *fieldHistoryRepository) Record(ctx context.Context, entityID, campaignID, fieldKey string, oldValue *float64, newValue float64, date *GameDate, at time.Time) error {
+	var year, month, day sql.NullInt64
+	if date != nil {
+		year = sql.NullInt64{Int64: int64(date.Year), Valid: true}
+		month = sql.NullInt64{Int64: int64(date.Month), Valid: true}
+		day = sql.NullInt64{Int64: int64(date.Day), Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO entity_field_history
+		   (entity_id, campaign_id, field_key, old_value, new_value, game_year, game_month, game_day, changed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entityID, campaignID, fieldKey, oldValue, newValue, year, month, day, at.UTC())
+	if err != nil {
+		return fmt.Errorf("recording field history: %w", err)
+	}
+	return nil
+}
+
+// ListByField reads the newest points and returns them in time order.
+func (r *fieldHistoryRepository) ListByField(ctx context.Context, entityID, fieldKey string, limit int) ([]FieldHistoryPoint, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT old_value, new_value, game_year, game_month, game_day, changed_at
+		 FROM entity_field_history
+		 WHERE entity_id = ? AND field_key = ?
+		 ORDER BY changed_at DESC, id DESC
+		 LIMIT ?`,
+		entityID, fieldKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing field history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []FieldHistoryPoint
+	for rows.Next() {
+		var p FieldHistoryPoint
+		var old sql.NullFloat64
+		var year, month, day sql.NullInt64
+		if err := rows.Scan(&old, &p.Value, &year, &month, &day, &p.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scanning field history: %w", err)
+		}
+		if old.Valid {
+			p.OldValue = &old.Float64
+		}
+		if year.Valid {
+			p.GameDate = &GameDate{Year: int(year.Int64), Month: int(month.Int64), Day: int(day.Int64)}
+		}
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// FieldHistoryService records and reads numeric field history.
+type FieldHistoryService interface {
+	FieldHistoryRecorder
+	ListHistory(ctx context.Context, entityID, fieldKey string) ([]FieldHistoryPoint, error)
+	SetGameDateSource(src GameDateSource)
+}
+
+// fieldHistoryService implements FieldHistoryService.
+type fieldHistoryService struct {
+	repo  FieldHistoryRepository
+	types EntityTypeRepository
+	dates GameDateSource
+	now   func() time.Time
+}
+
+// NewFieldHistoryService creates a field history service.
+func NewFieldHistoryService(repo FieldHistoryRepository, types EntityTypeRepository) FieldHistoryService {
+	return &fieldHistoryService{repo: repo, types: types, now: time.Now}
+}
+
+// SetGameDateSource enables in-game dates on new points.
+func (s *fieldHistoryService) SetGameDateSource(src GameDateSource) {
+	s.dates = src
+}
+
+// ListHistory returns the field's points, oldest first.
+func (s *fieldHistoryService) ListHistory(ctx context.Context, entityID, fieldKey string) ([]FieldHistoryPoint, error) {
+	return s.repo.ListByField(ctx, entityID, fieldKey, fieldHistoryLimit)
+}
+
+// RecordFieldChanges appends a point for each number field whose value
+// changed. Failures are logged, never returned.
+func (s *fieldHistoryService) RecordFieldChanges(ctx context.Context, entity *Entity, before map[string]any) {
+	if entity == nil {
+		return
+	}
+	et, err := s.types.FindByID(ctx, entity.EntityTypeID)
+	if err != nil || et == nil {
+		return
+	}
+	changes := numericFieldChanges(MergeFields(et.Fields, entity.FieldOverrides), before, entity.FieldsData)
+	if len(changes) == 0 {
+		return
+	}
+
+	var date *GameDate
+	if s.dates != nil {
+		if date, err = s.dates.CurrentGameDate(ctx, entity.CampaignID); err != nil {
+			date = nil
+		}
+	}
+	at := s.now()
+	for _, ch := range changes {
+		if err := s.repo.Record(ctx, entity.ID, entity.CampaignID, ch.Key, ch.Old, ch.New, date, at); err != nil {
+			slog.Warn("recording field history failed",
+				slog.String("entity_id", entity.ID),
+				slog.String("field", ch.Key),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// numericFieldChange is one number field's move from Old (nil if it had no
+// numeric value) to New.
+type numericFieldChange struct {
+	Key string
+	Old *float64
+	New float64
+}
+
+// numericFieldChanges compares number fields between two fields_data maps.
+// A field cleared or set to something non-numeric is not a change: the
+// history only holds numbers.
+func numericFieldChanges(defs []FieldDefinition, before, after map[string]any) []numericFieldChange {
+	var changes []numericFieldChange
+	for _, fd := range defs {
+		if fd.Type != "number" {
+			continue
+		}
+		newV, ok := fieldNumber(after[fd.Key])
+		if !ok {
+			continue
+		}
+		ch := numericFieldChange{Key: fd.Key, New: newV}
+		if oldV, ok := fieldNumber(before[fd.Key]); ok {
+			if oldV == newV {
+				continue
+			}
+			ch.Old = &oldV
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
+
+// fieldNumber reads a stored field value as a number. Values arrive as JSON
+// numbers from the widget and as strings from the form.
+func fieldNumber(v any) (float64, bool) {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case string:
+		var err error
+		if f, err = strconv.ParseFloat(strings.TrimSpace(n), 64); err != nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
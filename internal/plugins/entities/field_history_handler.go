@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetFieldHistoryService enables the field history endpoint.
+func (h *Handler) SetFieldHistoryService(svc FieldHistoryService) {
+	h.fieldHistorySvc = svc
+}
+
+// FieldHistoryAPI returns a numeric field's change history, oldest first,
+// for the attributes widget's trend chart.
+// GET /campaigns/:id/entities/:eid/fields/:key/history
+func (h *Handler) FieldHistoryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if h.fieldHistorySvc == nil {
+		return apperror.NewNotFound("field history is not available")
+	}
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+
+	entity, err := h.service.GetByID(ctx, c.Param("eid"))
+	if err != nil {
+		return err
+	}
+	// IDOR protection: verify entity belongs to the campaign in the URL.
+	if entity.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity not found")
+	}
+	access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), userID)
+	if err != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	if err != nil {
+		return apperror.NewNotFound("field not found")
+	}
+
+	key := c.Param("key")
+	var field *FieldDefinition
+	for _, fd := range MergeFields(et.Fields, entity.FieldOverrides) {
+		if fd.Key == key && fd.Type == "number" {
+			field = &fd
+			break
+		}
+	}
+	if field == nil {
+		return apperror.NewNotFound("field not found")
+	}
+
+	// A GM-only or owner-only field's history is as restricted as its
+	// value: run the key through the same filter as fields_data.
+	canSeeGM := cc.MemberRole >= campaigns.RoleScribe
+	probe := FilterRestrictedFields(map[string]any{key: true}, et.Fields, canSeeGM, entity.IsOwnedBy(userID))
+	if _, visible := probe[key]; !visible {
+		return apperror.NewNotFound("field not found")
+	}
+
+	points, err := h.fieldHistorySvc.ListHistory(ctx, entity.ID, key)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("listing field history: %w", err))
+	}
+	if points == nil {
+		points = []FieldHistoryPoint{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"key":    field.Key,
+		"label":  field.Label,
+		"points": points,
+	})
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFieldNumber(t *testing.T) {
+	cases := []struct {
+		in     any
+		want   float64
+		wantOK bool
+	}{
+		{float64(3.5), 3.5, true},
+		{12, 12, true},
+		{" 40 ", 40, true},
+		{"-2.25", -2.25, true},
+		{"", 0, false},
+		{"many", 0, false},
+		{"NaN", 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := fieldNumber(tc.in)
+		if ok != tc.wantOK || got != tc.want {
+			t.Errorf("fieldNumber(%#v) = %v, %v; want %v, %v", tc.in, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestNumericFieldChanges(t *testing.T) {
+	defs := []FieldDefinition{
+		{Key: "influence", Type: "number"},
+		{Key: "population", Type: "number"},
+		{Key: "wealth", Type: "number"},
+		{Key: "motto", Type: "text"},
+	}
+	before := map[string]any{"influence": "3", "population": float64(1200), "wealth": "7", "motto": "Old"}
+	after := map[string]any{"influence": float64(5), "population": "1200", "wealth": "", "motto": "New", "extra": "9"}
+
+	got := numericFieldChanges(defs, before, after)
+	if len(got) != 1 {
+		t.Fatalf("got %d changes; want 1 (%+v)", len(got), got)
+	}
+	if got[0].Key != "influence" || got[0].New != 5 || got[0].Old == nil || *got[0].Old != 3 {
+		t.Errorf("change = %+v; want influence 3 -> 5", got[0])
+	}
+
+	// A first value has no old value.
+	first := numericFieldChanges(defs, nil, map[string]any{"population": "300"})
+	if len(first) != 1 || first[0].Old != nil || first[0].New != 300 {
+		t.Errorf("first value = %+v; want population nil -> 300", first)
+	}
+}
+
+// fakeFieldHistoryRepo records Record calls in memory.
+type fakeFieldHistoryRepo struct {
+	FieldHistoryRepository
+	keys  []string
+	dates []*GameDate
+}
+
+func (f *fakeFieldHistoryRepo) Record(_ context.Context, _, _, fieldKey string, _ *float64, _ float64, date *GameDate, _ time.Time) error {
+	f.keys = append(f.keys, fieldKey)
+	f.dates = append(f.dates, date)
+	return nil
+}
+
+type fixedGameDate struct{ date *GameDate }
+
+func (f fixedGameDate) CurrentGameDate(context.Context, string) (*GameDate, error) {
+	return f.date, nil
+}
+
+func TestRecordFieldChanges(t *testing.T) {
+	types := &mockEntityTypeRepo{
+		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
+			return &EntityType{ID: id, Fields: []FieldDefinition{{Key: "influence", Type: "number"}}}, nil
+		},
+	}
+	repo := &fakeFieldHistoryRepo{}
+	svc := NewFieldHistoryService(repo, types)
+	svc.SetGameDateSource(fixedGameDate{date: &GameDate{Year: 735, Month: 3, Day: 12}})
+
+	entity := &Entity{
+		ID: "e1", CampaignID: "c1", EntityTypeID: 2,
+		// Overrides add a number field the type doesn't define.
+		FieldOverrides: &FieldOverrides{Added: []FieldDefinition{{Key: "renown", Type: "number"}}},
+		FieldsData:     map[string]any{"influence": "4", "renown": "10"},
+	}
+	svc.RecordFieldChanges(context.Background(), entity, map[string]any{"influence": "4"})
+
+	if len(repo.keys) != 1 || repo.keys[0] != "renown" {
+		t.Fatalf("recorded %v; want [renown]", repo.keys)
+	}
+	if d := repo.dates[0]; d == nil || d.Year != 735 || d.Day != 12 {
+		t.Errorf("game date = %+v; want 735-3-12", d)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"log/slog"
 	"net/http"
@@ -82,10 +83,12 @@ type MemberLister interface {
 	ListMembers(ctx context.Context, campaignID string) ([]campaigns.CampaignMember, error)
 }
 
-// GroupLister retrieves campaign groups for the permissions UI.
+// GroupLister retrieves campaign groups for the permissions UI and the
+// viewer's groups for group-limited layout blocks.
 // Satisfied by campaigns.GroupService.
 type GroupLister interface {
 	ListGroups(ctx context.Context, campaignID string) ([]campaigns.CampaignGroup, error)
+	ListUserGroupIDs(ctx context.Context, campaignID, userID string) ([]int, error)
 }
 
 // WidgetBlockLister returns extension widget block metadata for the template
@@ -94,6 +97,14 @@ type WidgetBlockLister interface {
 	GetWidgetBlockMetas(ctx context.Context, campaignID string) []BlockMeta
 }
 
+// ImageProxy rewrites external image URLs in entry content to proxied ones
+// for display, and back before saving. Implemented by media.ImageProxy and
+// injected via SetImageProxy.
+type ImageProxy interface {
+	DisplaySrc(campaignID string, cfg campaigns.ImageProxySettings) func(src string) string
+	StoredSrc(campaignID string) func(src string) string
+}
+
 // Handler handles HTTP requests for entity operations. Handlers are thin:
 // bind request, call service, render response. No business logic lives here.
 type Handler struct {
@@ -108,6 +119,7 @@ type Handler struct {
 	systemSearcher     SystemSearcher
 	memberLister       MemberLister
 	npcSection         NPCSectionProvider
+	linkedProviders    []LinkedContentProvider // "Appears in" panel (linked_content.go).
 	groupLister        GroupLister
 	widgetBlockLister  WidgetBlockLister
 	contentTemplateSvc ContentTemplateService
@@ -116,6 +128,18 @@ type Handler struct {
 	savedFilterRepo    SavedFilterRepository
 	blockRegistry      *BlockRegistry
 	cache              *redis.Client
+	sitemap            SitemapService
+	imageProxy         ImageProxy
+	notifier           UserNotifier
+	watchSvc           WatchService
+	fieldHistorySvc    FieldHistoryService
+	standingSvc        StandingService
+	dateIssueSvc       DateIssueService
+	bulkImageUploader  BulkImageUploader
+	open5e             Open5eSearcher
+	contentImports     ContentImportRepository
+	layoutVersions     campaigns.LayoutVersionService
+	baseURL            string
 }
 
 // NewHandler creates a new entity handler.
@@ -246,6 +270,23 @@ func (h *Handler) SetCache(rdb *redis.Client) {
 	h.cache = rdb
 }
 
+// SetSitemapService wires sitemap.xml for public campaigns. Without it the
+// sitemap route 404s.
+func (h *Handler) SetSitemapService(svc SitemapService) {
+	h.sitemap = svc
+}
+
+// SetImageProxy wires the external image proxy for entry content.
+func (h *Handler) SetImageProxy(proxy ImageProxy) {
+	h.imageProxy = proxy
+}
+
+// SetBaseURL sets the public-facing URL used for absolute links in
+// structured data (breadcrumbs).
+func (h *Handler) SetBaseURL(baseURL string) {
+	h.baseURL = baseURL
+}
+
 // logAudit fires a fire-and-forget audit entry. Errors are logged but
 // never block the primary operation.
 func (h *Handler) logAudit(c echo.Context, campaignID, action, entityID, entityName string) {
@@ -306,6 +347,7 @@ func (h *Handler) Index(c echo.Context) error {
 	if sort := c.QueryParam("sort"); sort == "updated" || sort == "created" || sort == "name" {
 		opts.Sort = sort
 	}
+	opts.Status = parseStatusFilter(c.QueryParam("status"))
 
 	// Resolve entity type filter from shortcut route or query param.
 	var typeID int
@@ -379,6 +421,9 @@ func (h *Handler) Index(c echo.Context) error {
 
 	// When viewing a specific category (type), render the category dashboard.
 	if activeEntityType != nil {
+		if campaigns.DashboardHasGroupRules(activeEntityType.ParseCategoryDashboardLayout()) {
+			campaigns.LoadViewerGroups(c, cc, h.groupLister)
+		}
 		if middleware.IsHTMX(c) {
 			return middleware.Render(c, http.StatusOK,
 				CategoryDashboardContent(cc, activeEntityType, entities, counts, total, opts, csrfToken, roster))
@@ -440,6 +485,11 @@ func (h *Handler) NewForm(c echo.Context) error {
 	}
 
 	entityTypes, _ := h.service.GetEntityTypes(c.Request().Context(), cc.Campaign.ID)
+	// Players only see the types opened to them (type_access.go).
+	entityTypes = creatableTypes(cc, entityTypes)
+	if len(entityTypes) == 0 && cc.MemberRole < campaigns.RoleScribe {
+		return apperror.NewForbidden("insufficient permissions")
+	}
 	csrfToken := middleware.GetCSRFToken(c)
 	preselect, _ := strconv.Atoi(c.QueryParam("type"))
 
@@ -511,6 +561,10 @@ func (h *Handler) Create(c echo.Context) error {
 		return err
 	}
 
+	if err := h.checkPlayerCreate(c.Request().Context(), cc, req.EntityTypeID); err != nil {
+		return err
+	}
+
 	fieldsData := h.parseFieldsFromForm(c, cc.Campaign.ID, req.EntityTypeID)
 
 	userID := auth.GetUserID(c)
@@ -523,6 +577,15 @@ func (h *Handler) Create(c echo.Context) error {
 			isPrivate = true
 		}
 	}
+	// A Player's page is never private (they couldn't see it) and is
+	// either published or their own draft.
+	status := EntityStatus(req.Status)
+	if cc.MemberRole < campaigns.RoleScribe {
+		isPrivate = false
+		if status != StatusDraft {
+			status = StatusPublished
+		}
+	}
 
 	input := CreateEntityInput{
 		Name:         req.Name,
@@ -530,12 +593,14 @@ func (h *Handler) Create(c echo.Context) error {
 		TypeLabel:    req.TypeLabel,
 		ParentID:     req.ParentID,
 		IsPrivate:    isPrivate,
+		Status:       status,
 		FieldsData:   fieldsData,
 	}
 
 	entity, err := h.service.Create(c.Request().Context(), cc.Campaign.ID, userID, input)
 	if err != nil {
 		entityTypes, _ := h.service.GetEntityTypes(c.Request().Context(), cc.Campaign.ID)
+		entityTypes = creatableTypes(cc, entityTypes)
 		csrfToken := middleware.GetCSRFToken(c)
 		errMsg := apperror.UserMessage(err, "failed to create entity")
 		// Preserve the same variant-aware picker across error re-render so a
@@ -578,6 +643,24 @@ func (h *Handler) Show(c echo.Context) error {
 
 	entityID := c.Param("eid")
 	entity, err := h.service.GetByID(c.Request().Context(), entityID)
+	if err != nil && apperror.SafeCode(err) == http.StatusNotFound {
+		// Not an ID: treat the segment as a slug, current or retired, and
+		// redirect to the canonical ID URL so old links keep working.
+		entity, err = h.service.ResolveSlug(c.Request().Context(), cc.Campaign.ID, entityID)
+		if err != nil {
+			return err
+		}
+		userID := auth.GetUserID(c)
+		access, err := h.service.CheckEntityAccess(c.Request().Context(), entity.ID, int(cc.MemberRole), userID)
+		if err != nil || !access.CanView {
+			return apperror.NewNotFound("entity not found")
+		}
+		target := "/campaigns/" + cc.Campaign.ID + "/entities/" + entity.ID
+		if q := c.QueryString(); q != "" {
+			target += "?" + q
+		}
+		return c.Redirect(http.StatusMovedPermanently, target)
+	}
 	if err != nil {
 		return err
 	}
@@ -586,7 +669,47 @@ func (h *Handler) Show(c echo.Context) error {
 	if entity.CampaignID != cc.Campaign.ID {
 		return apperror.NewNotFound("entity not found")
 	}
+	return h.renderShow(c, cc, entity)
+}
+
+// ShowBySlug renders the entity profile page at its human-readable URL
+// (GET /campaigns/:id/e/:eslug). :id may be the campaign slug or its ID
+// (see campaigns.ResolveCampaignSlug). A retired entity slug 301s to the
+// slug the entity holds now.
+func (h *Handler) ShowBySlug(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	slug := c.Param("eslug")
+	entity, err := h.service.GetBySlug(c.Request().Context(), cc.Campaign.ID, slug)
+	if err != nil && apperror.SafeCode(err) == http.StatusNotFound {
+		entity, err = h.service.ResolveSlug(c.Request().Context(), cc.Campaign.ID, slug)
+		if err != nil {
+			return err
+		}
+		userID := auth.GetUserID(c)
+		access, err := h.service.CheckEntityAccess(c.Request().Context(), entity.ID, int(cc.MemberRole), userID)
+		if err != nil || !access.CanView {
+			return apperror.NewNotFound("entity not found")
+		}
+		target := EntitySlugPath(cc.Campaign.Slug, entity.Slug)
+		if q := c.QueryString(); q != "" {
+			target += "?" + q
+		}
+		return c.Redirect(http.StatusMovedPermanently, target)
+	}
+	if err != nil {
+		return err
+	}
+	return h.renderShow(c, cc, entity)
+}
 
+// renderShow renders the profile page for an entity already known to belong
+// to the campaign, shared by the ID and slug routes. Visibility is checked
+// here so neither route can skip it.
+func (h *Handler) renderShow(c echo.Context, cc *campaigns.CampaignContext, entity *Entity) error {
 	// Visibility check: verify the user can view this entity.
 	userID := auth.GetUserID(c)
 	access, err := h.service.CheckEntityAccess(c.Request().Context(), entity.ID, int(cc.MemberRole), userID)
@@ -668,12 +791,68 @@ func (h *Handler) Show(c echo.Context) error {
 		ev := ComputeEffectiveVisibility(entity, grants)
 		ctx = WithEffectiveVisibility(ctx, &ev)
 	}
+	// Pages whose type is open to player editing get their edit controls.
+	if cc.MemberRole == campaigns.RolePlayer && playerMayEdit(entityType, entity, userID) {
+		ctx = withPlayerEdit(ctx)
+	}
+
+	// Structured breadcrumbs only matter where crawlers can read the page.
+	if cc.Campaign.IsPublic {
+		ctx = WithBreadcrumbs(ctx, BuildBreadcrumbs(h.baseURL, cc.Campaign, entity, ancestors))
+	}
 
 	c.SetRequest(c.Request().WithContext(ctx))
+	if layoutHasGroupRules(entityType.Layout) {
+		campaigns.LoadViewerGroups(c, cc, h.groupLister)
+	}
 
 	return middleware.Render(c, http.StatusOK, EntityShowPage(cc, entity, entityType, ancestors, children, showAttributes, showCalendar, claimingEnabled, ownerName, csrfToken, userID))
 }
 
+// SitemapXML serves the campaign's sitemap for public wiki mode
+// (GET /campaigns/:id/sitemap.xml). Private campaigns have none, members
+// included — there is nothing for a crawler to index.
+func (h *Handler) SitemapXML(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if !cc.Campaign.IsPublic || h.sitemap == nil {
+		return apperror.NewNotFound("sitemap not found")
+	}
+
+	data, err := h.sitemap.Sitemap(c.Request().Context(), cc.Campaign)
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	return c.Blob(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+// OfflineManifestAPI serves the viewer's offline content manifest for the
+// service worker (GET /campaigns/:id/offline-manifest). The version is the
+// ETag, so a worker polling an unchanged campaign gets a bodyless 304.
+func (h *Handler) OfflineManifestAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	m, err := h.service.OfflineManifest(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+
+	// Per-viewer content: never shared caches, always revalidated.
+	etag := `"` + m.Version + `"`
+	c.Response().Header().Set("ETag", etag)
+	c.Response().Header().Set("Cache-Control", "private, no-cache")
+	c.Response().Header().Set("Vary", "Cookie")
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSON(http.StatusOK, m)
+}
+
 // Clone creates a copy of an entity (POST /campaigns/:id/entities/:eid/clone).
 // Copies name (with " (Copy)" suffix), entry, fields, image, parent, privacy,
 // field overrides, popup config, and tags. Does NOT copy relations.
@@ -732,7 +911,16 @@ func (h *Handler) Update(c echo.Context) error {
 		return apperror.NewBadRequest("invalid request")
 	}
 
-	fieldsData := h.parseFieldsFromForm(c, cc.Campaign.ID, entity.EntityTypeID)
+	if req.Entry != "" {
+		if err := checkPlayerEntryEdit(cc, entity); err != nil {
+			return err
+		}
+	}
+	fieldsData, err := h.keepPlayerHiddenFields(c.Request().Context(), cc, entity, auth.GetUserID(c),
+		h.parseFieldsFromForm(c, cc.Campaign.ID, entity.EntityTypeID))
+	if err != nil {
+		return err
+	}
 
 	// IsPrivate intentionally omitted: the form no longer carries this
 	// field (the permissions card owns it now via /permissions). A nil
@@ -748,7 +936,7 @@ func (h *Handler) Update(c echo.Context) error {
 		ExpectedUpdatedAt: req.ExpectedUpdatedAt,
 	}
 
-	_, err = h.service.Update(c.Request().Context(), entityID, input)
+	updated, err := h.service.Update(c.Request().Context(), entityID, input)
 	if err != nil {
 		entityTypes, _ := h.service.GetEntityTypes(c.Request().Context(), cc.Campaign.ID)
 		entityType, _ := h.service.GetEntityTypeByID(c.Request().Context(), entity.EntityTypeID)
@@ -765,7 +953,12 @@ func (h *Handler) Update(c echo.Context) error {
 		return middleware.Render(c, http.StatusOK, EntityEditPage(cc, entity, entityType, entityTypes, parentEntity, csrfToken, errMsg))
 	}
 
-	h.logAudit(c, cc.Campaign.ID, audit.ActionEntityUpdated, entityID, entity.Name)
+	h.logEntityChange(c, cc.Campaign.ID, entity)
+
+	// The edit form offers to fix mentions that still show the old name.
+	if req.UpdateMentions && updated.Name != entity.Name {
+		h.startMentionRename(cc.Campaign.ID, entityID, entity.Name, updated.Name, int(cc.MemberRole), auth.GetUserID(c))
+	}
 
 	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/entities/"+entityID)
 }
@@ -840,6 +1033,7 @@ func (h *Handler) SearchAPI(c echo.Context) error {
 	if tags := c.QueryParam("tags"); tags != "" {
 		opts.TagSlugs = strings.Split(tags, ",")
 	}
+	opts.Status = parseStatusFilter(c.QueryParam("status"))
 
 	// Pagination: allow callers to request a specific page.
 	if p, _ := strconv.Atoi(c.QueryParam("page")); p > 1 {
@@ -904,6 +1098,7 @@ func (h *Handler) SearchAPI(c echo.Context) error {
 				"url":        fmt.Sprintf("/campaigns/%s/entities/%s", cc.Campaign.ID, e.ID),
 				"tags":       tags,
 				"sort_order": e.SortOrder,
+				"status":     e.Status,
 			}
 			if e.ParentID != nil {
 				item["parent_id"] = *e.ParentID
@@ -1026,11 +1221,15 @@ func (h *Handler) ReorderAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
-// --- Quick Create API (JSON endpoint for shop widget) ---
+// --- Quick Create API (JSON endpoint for inline creation) ---
 
 // QuickCreateAPI creates a new entity from a JSON request and returns its data.
 // POST /campaigns/:id/entities/quick-create
-// Used by the shop inventory widget to create items inline.
+// Used to create entities without leaving the current flow: shop inventory
+// items, sidebar folders, and the "Create …" option of the parent picker,
+// relations picker and @mention popup. The response has the same keys as a
+// SearchAPI result so pickers can select it directly, plus "similar":
+// existing pages with the same or a near name (see duplicates.go).
 func (h *Handler) QuickCreateAPI(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
 	if cc == nil {
@@ -1060,6 +1259,13 @@ func (h *Handler) QuickCreateAPI(c echo.Context) error {
 		req.EntityTypeID = types[0].ID
 	}
 
+	// Look up likely duplicates before creating so the new page isn't
+	// among them. They're returned, never enforced.
+	similar := h.similarNames(c, cc, req.Name)
+	if similar == nil {
+		similar = []SimilarName{}
+	}
+
 	userID := auth.GetUserID(c)
 	input := CreateEntityInput{
 		Name:         req.Name,
@@ -1073,12 +1279,14 @@ func (h *Handler) QuickCreateAPI(c echo.Context) error {
 
 	h.logAudit(c, cc.Campaign.ID, audit.ActionEntityCreated, entity.ID, entity.Name)
 
-	return c.JSON(http.StatusCreated, map[string]string{
+	return c.JSON(http.StatusCreated, map[string]any{
 		"id":         entity.ID,
 		"name":       entity.Name,
 		"type_name":  entity.TypeName,
 		"type_icon":  entity.TypeIcon,
 		"type_color": entity.TypeColor,
+		"url":        fmt.Sprintf("/campaigns/%s/entities/%s", cc.Campaign.ID, entity.ID),
+		"similar":    similar,
 	})
 }
 
@@ -1712,6 +1920,20 @@ func (h *Handler) GetEntry(c echo.Context) error {
 		}
 	}
 
+	// External images from allowed hosts load through the campaign's image
+	// proxy; UpdateEntryAPI maps them back so storage keeps the originals.
+	if cfg := cc.Campaign.ParseSettings().GetImageProxy(); h.imageProxy != nil && cfg.Enabled {
+		rewrite := h.imageProxy.DisplaySrc(cc.Campaign.ID, cfg)
+		if entry != nil {
+			rewritten := sanitize.RewriteImageSrcJSON(*entry, rewrite)
+			entry = &rewritten
+		}
+		if entryHTML != nil {
+			rewritten := sanitize.RewriteImageSrcHTML(*entryHTML, rewrite)
+			entryHTML = &rewritten
+		}
+	}
+
 	response := map[string]any{
 		"entry":      entry,
 		"entry_html": entryHTML,
@@ -1745,12 +1967,23 @@ func (h *Handler) UpdateEntryAPI(c echo.Context) error {
 	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
 		return apperror.NewBadRequest("invalid JSON body")
 	}
+	if err := checkPlayerEntryEdit(cc, entity); err != nil {
+		return err
+	}
+
+	// Undo GetEntry's image proxy rewrite. Runs even with the proxy now off,
+	// so content loaded before an owner switched it off saves cleanly.
+	if h.imageProxy != nil {
+		restore := h.imageProxy.StoredSrc(cc.Campaign.ID)
+		body.Entry = sanitize.RewriteImageSrcJSON(body.Entry, restore)
+		body.EntryHTML = sanitize.RewriteImageSrcHTML(body.EntryHTML, restore)
+	}
 
 	if err := h.service.UpdateEntry(c.Request().Context(), entityID, body.Entry, body.EntryHTML); err != nil {
 		return err
 	}
 
-	h.logAudit(c, cc.Campaign.ID, audit.ActionEntityUpdated, entityID, entity.Name)
+	h.logEntityChange(c, cc.Campaign.ID, entity)
 
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -1907,12 +2140,18 @@ func (h *Handler) UpdateFieldsAPI(c echo.Context) error {
 	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
 		return apperror.NewBadRequest("invalid JSON body")
 	}
+	// Players were served these fields without GM-only and owner-only
+	// values; keep the stored ones rather than erasing them.
+	fieldsData, err := h.keepPlayerHiddenFields(c.Request().Context(), cc, entity, auth.GetUserID(c), body.FieldsData)
+	if err != nil {
+		return err
+	}
 
-	if err := h.service.UpdateFields(c.Request().Context(), entityID, body.FieldsData); err != nil {
+	if err := h.service.UpdateFields(c.Request().Context(), entityID, fieldsData); err != nil {
 		return err
 	}
 
-	h.logAudit(c, cc.Campaign.ID, audit.ActionEntityUpdated, entityID, entity.Name)
+	h.logEntityChange(c, cc.Campaign.ID, entity)
 
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -2001,15 +2240,56 @@ func (h *Handler) UpdateImageAPI(c echo.Context) error {
 
 	var body struct {
 		ImagePath string `json:"image_path"`
+		ImageTextInput
 	}
 	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
 		return apperror.NewBadRequest("invalid JSON body")
 	}
 
-	if err := h.service.UpdateImage(c.Request().Context(), entityID, body.ImagePath); err != nil {
+	if err := h.service.UpdateImage(c.Request().Context(), entityID, body.ImagePath, body.ImageTextInput, cc.Campaign.IsPublic); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateImageTextAPI edits the header image's alt text and caption.
+// PUT /campaigns/:id/entities/:eid/image-text
+// Accepts JSON or the show page's HTMX form, which gets the re-rendered
+// image card back.
+func (h *Handler) UpdateImageTextAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	entityID := c.Param("eid")
+
+	// IDOR protection.
+	entity, err := h.service.GetByID(c.Request().Context(), entityID)
+	if err != nil {
 		return err
 	}
+	if entity.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity not found")
+	}
 
+	var body ImageTextInput
+	if err := c.Bind(&body); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.service.UpdateImageText(c.Request().Context(), entityID, body, cc.Campaign.IsPublic); err != nil {
+		return err
+	}
+
+	if middleware.IsHTMX(c) {
+		updated, err := h.service.GetByID(c.Request().Context(), entityID)
+		if err != nil {
+			return err
+		}
+		return middleware.Render(c, http.StatusOK, blockImage(cc, updated, middleware.GetCSRFToken(c)))
+	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
@@ -2051,6 +2331,23 @@ func (h *Handler) UpdateCoverImageAPI(c echo.Context) error {
 // htmlTagPattern matches HTML tags for stripping in entry excerpts.
 var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
 
+// EntryExcerpt renders entry HTML as a plain-text excerpt of at most limit
+// characters (plus "..."), cut at a word boundary when one falls in the last
+// third. Callers strip GM secrets first — the excerpt keeps the prose.
+func EntryExcerpt(entryHTML string, limit int) string {
+	plain := html.UnescapeString(htmlTagPattern.ReplaceAllString(entryHTML, " "))
+	plain = strings.Join(strings.Fields(plain), " ") // Normalize whitespace.
+	runes := []rune(plain)
+	if len(runes) <= limit {
+		return plain
+	}
+	truncated := string(runes[:limit])
+	if idx := strings.LastIndex(truncated, " "); idx > len(truncated)*2/3 {
+		truncated = truncated[:idx]
+	}
+	return truncated + "..."
+}
+
 // PreviewAPI returns entity data for tooltip/popover display, respecting the
 // entity's popup_config to control which sections are included.
 // GET /campaigns/:id/entities/:eid/preview
@@ -2085,75 +2382,56 @@ func (h *Handler) PreviewAPI(c echo.Context) error {
 		return apperror.NewMissingContext()
 	}
 
-	cfg := entity.EffectivePopupConfig()
+	// Set cache headers: short-lived cache for fast repeated hovers.
+	c.Response().Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", previewMaxAge))
 
-	// Build an excerpt from entry_html: strip HTML tags, truncate to ~150 chars.
-	var entryExcerpt string
-	if cfg.ShowEntry && entity.EntryHTML != nil && *entity.EntryHTML != "" {
-		plain := htmlTagPattern.ReplaceAllString(*entity.EntryHTML, "")
-		plain = strings.Join(strings.Fields(plain), " ") // Normalize whitespace.
-		if len(plain) > 150 {
-			// Truncate at word boundary.
-			truncated := plain[:150]
-			if idx := strings.LastIndex(truncated, " "); idx > 100 {
-				truncated = truncated[:idx]
-			}
-			entryExcerpt = truncated + "..."
-		} else {
-			entryExcerpt = plain
-		}
-	}
+	return c.JSON(http.StatusOK, buildEntityPreview(entity, entityType, int(cc.MemberRole), userID))
+}
 
-	// Resolve image path when popup config allows it.
-	var imagePath string
-	if cfg.ShowImage && entity.ImagePath != nil && *entity.ImagePath != "" {
-		imagePath = fmt.Sprintf("/media/%s", *entity.ImagePath)
-	}
+// previewMaxAge is how long, in seconds, a tooltip preview may be reused.
+const previewMaxAge = 60
 
-	// Build attributes list: field label + value pairs for the first few fields.
-	// Initialize to empty slice so JSON serializes as [] instead of null.
-	attributes := make([]map[string]string, 0)
-	if cfg.ShowAttributes && entityType != nil {
-		effectiveFields := MergeFields(entityType.Fields, entity.FieldOverrides)
-		// Strip GM-only and owner-only field values for viewers who can't see
-		// them (audit M-1 / C-FIELDS-OWNER-FILTER) — this preview/tooltip is
-		// player-reachable via the public campaign route.
-		fieldsData := FilterRestrictedFields(entity.FieldsData, entityType.Fields, cc.MemberRole >= campaigns.RoleScribe, entity.IsOwnedBy(userID))
-		for _, fd := range effectiveFields {
-			val, ok := fieldsData[fd.Key]
-			if !ok || val == nil || fmt.Sprintf("%v", val) == "" {
-				continue
-			}
-			attributes = append(attributes, map[string]string{
-				"label": fd.Label,
-				"value": fmt.Sprintf("%v", val),
-			})
-			if len(attributes) >= 5 {
-				break // Limit to 5 attributes in tooltip.
-			}
-		}
+// batchPreviewEntry is one entity in a batch preview response. It carries
+// the cache hints the single endpoint sends as headers, since one POST
+// response can't give each entity its own.
+type batchPreviewEntry struct {
+	EntityPreview
+	ETag   string `json:"etag"`
+	MaxAge int    `json:"max_age"`
+}
+
+// BatchPreviewAPI returns tooltip previews for several entities at once so a
+// page full of mentions needs one request instead of one per hover. IDs the
+// viewer can't see (or from another campaign) are left out of the map, which
+// the tooltip treats like the single endpoint's 404.
+// POST /campaigns/:id/entities/previews
+func (h *Handler) BatchPreviewAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
 	}
 
-	// Resolve type label.
-	var typeLabel string
-	if entity.TypeLabel != nil {
-		typeLabel = *entity.TypeLabel
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
 	}
 
-	// Set cache headers: short-lived cache for fast repeated hovers.
-	c.Response().Header().Set("Cache-Control", "private, max-age=60")
+	previews, err := h.service.GetPreviews(c.Request().Context(), cc.Campaign.ID, req.IDs, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
 
-	return c.JSON(http.StatusOK, map[string]any{
-		"name":          entity.Name,
-		"type_name":     entityType.Name,
-		"type_icon":     entityType.Icon,
-		"type_color":    entityType.Color,
-		"image_path":    imagePath,
-		"type_label":    typeLabel,
-		"is_private":    entity.IsPrivate,
-		"entry_excerpt": entryExcerpt,
-		"attributes":    attributes,
-	})
+	out := make(map[string]batchPreviewEntry, len(previews))
+	for _, p := range previews {
+		out[p.ID] = batchPreviewEntry{
+			EntityPreview: p,
+			ETag:          fmt.Sprintf(`W/"%s-%d"`, p.ID, p.UpdatedAt.Unix()),
+			MaxAge:        previewMaxAge,
+		}
+	}
+	return c.JSON(http.StatusOK, map[string]any{"previews": out})
 }
 
 // UpdatePopupConfigAPI saves the entity's hover preview tooltip configuration.
@@ -2186,6 +2464,48 @@ func (h *Handler) UpdatePopupConfigAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// UpdateSlugAPI sets or clears an entity's custom slug. An empty slug
+// returns the entity to a name-derived one. Conflicts come back as 422s.
+// PUT /campaigns/:id/entities/:eid/slug
+func (h *Handler) UpdateSlugAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	entityID := c.Param("eid")
+	entity, err := h.service.GetByID(c.Request().Context(), entityID)
+	if err != nil {
+		return err
+	}
+	if entity.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity not found")
+	}
+
+	var req struct {
+		Slug string `json:"slug" form:"slug"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+
+	updated, err := h.service.SetSlug(c.Request().Context(), entityID, req.Slug)
+	if err != nil {
+		return err
+	}
+	if updated.Slug != entity.Slug {
+		h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, updated.ID, updated.Name, map[string]any{
+			"old_slug": entity.Slug,
+			"slug":     updated.Slug,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"slug":        updated.Slug,
+		"slug_custom": updated.SlugCustom,
+	})
+}
+
 // UpdateMetadataAPI saves entity metadata (name, descriptor, parent, privacy)
 // without touching entry content or field values. Used by the inline metadata
 // panel on the entity show page.
@@ -2231,7 +2551,7 @@ func (h *Handler) UpdateMetadataAPI(c echo.Context) error {
 		return err
 	}
 
-	h.logAudit(c, cc.Campaign.ID, audit.ActionEntityUpdated, entityID, updated.Name)
+	h.logEntityChange(c, cc.Campaign.ID, entity)
 
 	return c.JSON(http.StatusOK, map[string]any{
 		"status": "ok",
@@ -2403,6 +2723,7 @@ func (h *Handler) SetPermissionsAPI(c echo.Context) error {
 	if err := h.service.SetEntityPermissions(ctx, entityID, SetPermissionsInput(req)); err != nil {
 		return respondPermissionsError(c, err)
 	}
+	h.logEntityChange(c, cc.Campaign.ID, entity)
 
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -2498,6 +2819,127 @@ func (h *Handler) EntityNamesAPI(c echo.Context) error {
 	return c.JSONBlob(http.StatusOK, result)
 }
 
+// quickSwitchNamesCacheTTL matches the auto-link index: alias edits clear it
+// explicitly, other renames age out.
+const quickSwitchNamesCacheTTL = entityNamesCacheTTL
+
+// QuickSwitchAPI answers the Ctrl+K quick switcher: entity name/alias
+// fuzzy matches, recently updated pages, and actions in one ranked list.
+// The per-viewer name index is cached in Redis so a keystroke costs one
+// cache read plus the recent-pages query.
+// GET /campaigns/:id/quick-switch?q=
+func (h *Handler) QuickSwitchAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	ctx := c.Request().Context()
+	role := cc.VisibilityRole()
+	userID := auth.GetUserID(c)
+	query := c.QueryParam("q")
+
+	names, err := h.quickSwitchNames(ctx, cc.Campaign.ID, role, userID)
+	if err != nil {
+		return err
+	}
+	recent, err := h.service.ListRecent(ctx, cc.Campaign.ID, role, userID, 8)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("list recent entities: %w", err))
+	}
+
+	results := RankQuickSwitch(QuickSwitchInput{
+		Query:      query,
+		CampaignID: cc.Campaign.ID,
+		Names:      names,
+		Recent:     recent,
+		Actions:    h.quickSwitchActions(ctx, cc),
+	})
+	if results == nil {
+		results = []QuickSwitchResult{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{"query": query, "results": results})
+}
+
+// quickSwitchNames returns the viewer's switcher name index, from Redis when
+// warm. Keyed like the auto-link index (campaign, role, user) because the
+// visible set differs per viewer.
+func (h *Handler) quickSwitchNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error) {
+	cacheKey := fmt.Sprintf("quick-switch-names:%s:%d:%s", campaignID, role, userID)
+	if h.cache != nil {
+		if cached, err := h.cache.Get(ctx, cacheKey).Bytes(); err == nil {
+			var names []EntityNameEntry
+			if err := json.Unmarshal(cached, &names); err == nil {
+				return names, nil
+			}
+		}
+	}
+
+	names, err := h.service.ListSwitcherNames(ctx, campaignID, role, userID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("list switcher names: %w", err))
+	}
+	if h.cache != nil {
+		if data, err := json.Marshal(names); err == nil {
+			if err := h.cache.Set(ctx, cacheKey, data, quickSwitchNamesCacheTTL).Err(); err != nil {
+				slog.Error("failed to cache switcher names", slog.Any("error", err))
+			}
+		}
+	}
+	return names, nil
+}
+
+// quickSwitchActions lists the commands offered to this viewer: "Go to" for
+// each enabled category and addon page, and "New <type>" for each type they
+// may create pages of (creatableTypes, as on the new-page form).
+func (h *Handler) quickSwitchActions(ctx context.Context, cc *campaigns.CampaignContext) []QuickAction {
+	base := "/campaigns/" + cc.Campaign.ID
+	actions := []QuickAction{
+		{ID: "search", Label: "Search all pages", Keywords: []string{"find"}, URL: base + "/search", Icon: "fa-magnifying-glass"},
+	}
+
+	types, err := h.service.GetEntityTypes(ctx, cc.Campaign.ID)
+	if err != nil {
+		slog.Warn("quick switcher: entity types lookup failed", slog.Any("error", err))
+	}
+	canCreate := make(map[int]bool)
+	for _, et := range creatableTypes(cc, types) {
+		canCreate[et.ID] = true
+	}
+	for _, et := range types {
+		if !et.Enabled {
+			continue
+		}
+		plural := et.NamePlural
+		if plural == "" {
+			plural = et.Name + "s"
+		}
+		actions = append(actions, QuickAction{
+			ID: "goto-" + et.Slug, Label: "Go to " + plural, Keywords: []string{plural},
+			URL: base + "/" + et.Slug, Icon: et.Icon,
+		})
+		if canCreate[et.ID] {
+			actions = append(actions, QuickAction{
+				ID: "new-" + et.Slug, Label: "New " + et.Name, Keywords: []string{"create " + et.Name, "add " + et.Name},
+				URL: fmt.Sprintf("%s/entities/new?type=%d", base, et.ID), Icon: "fa-plus",
+			})
+		}
+	}
+
+	// Addon pages, only where the addon is on.
+	for _, p := range []struct{ addon, id, label, path, icon string }{
+		{"calendar", "goto-calendar", "Go to calendar", "/calendar", "fa-calendar-days"},
+		{"calendar", "goto-sessions", "Go to sessions", "/sessions", "fa-dice-d20"},
+		{"maps", "goto-maps", "Go to maps", "/maps", "fa-map"},
+		{"timeline", "goto-timelines", "Go to timelines", "/timelines", "fa-timeline"},
+	} {
+		if h.isAddonEnabled(ctx, cc.Campaign.ID, p.addon) {
+			actions = append(actions, QuickAction{ID: p.id, Label: p.label, URL: base + p.path, Icon: p.icon})
+		}
+	}
+	return actions
+}
+
 // EntityTypesPage renders the entity type management page.
 // GET /campaigns/:id/entity-types
 func (h *Handler) EntityTypesPage(c echo.Context) error {
@@ -2914,11 +3356,16 @@ func (h *Handler) UpdateEntityTypeLayout(c echo.Context) error {
 		return apperror.NewBadRequest("invalid JSON body")
 	}
 
+	h.recordTemplateLayout(c, et, "Saved page layout")
 	if err := h.service.UpdateEntityTypeLayout(c.Request().Context(), etID, body.Layout); err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	// Accessibility findings are advisory: the layout is already saved.
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":   "ok",
+		"warnings": auditTemplateLayout(et, body.Layout),
+	})
 }
 
 // UpdateEntityTypeColor saves the entity type's display color.
@@ -2955,7 +3402,10 @@ func (h *Handler) UpdateEntityTypeColor(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	return c.JSON(http.StatusOK, map[string]any{
+		"status":   "ok",
+		"warnings": auditTypeColor(et, body.Color),
+	})
 }
 
 // UpdateEntityTypeDashboard updates the category dashboard description and pinned pages
@@ -3060,11 +3510,51 @@ func (h *Handler) UpdateCategoryDashboardLayout(c echo.Context) error {
 	}
 
 	layoutJSON := string(body)
+	h.recordCategoryDashboard(c, et, "Saved category dashboard")
 	if err := h.service.UpdateCategoryDashboardLayout(c.Request().Context(), etID, layoutJSON); err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	// The service has already rejected malformed JSON, so this decode
+	// only feeds the accessibility audit.
+	var layout campaigns.DashboardLayout
+	_ = json.Unmarshal(body, &layout)
+	warnings := append(auditTypeColor(et, et.Color), campaigns.AuditDashboardLayout(&layout)...)
+	return c.JSON(http.StatusOK, map[string]any{"status": "ok", "warnings": warnings})
+}
+
+// ExportCategoryDashboardLayout downloads the entity type's custom dashboard
+// layout for import elsewhere (campaigns' layout_share.go)
+// (GET /campaigns/:id/entity-types/:etid/dashboard-layout/export).
+func (h *Handler) ExportCategoryDashboardLayout(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	etID, err := strconv.Atoi(c.Param("etid"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid entity type ID")
+	}
+
+	et, err := h.service.GetEntityTypeByID(c.Request().Context(), etID)
+	if err != nil {
+		return err
+	}
+
+	if et.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity type not found")
+	}
+
+	if et.DashboardLayout == nil || *et.DashboardLayout == "" {
+		return apperror.NewBadRequest("this category uses the built-in dashboard; there is no layout to export")
+	}
+	var layout campaigns.DashboardLayout
+	if err := json.Unmarshal([]byte(*et.DashboardLayout), &layout); err != nil {
+		return apperror.NewInternal(fmt.Errorf("parsing category dashboard layout: %w", err))
+	}
+	export := campaigns.NewLayoutExport(campaigns.LayoutKindCategoryDashboard, cc.Campaign.Name, &layout)
+	return campaigns.WriteLayoutExport(c, export, cc.Campaign.Name, et.NamePlural+"-dashboard")
 }
 
 // ResetCategoryDashboardLayout removes the custom dashboard layout for an entity type,
@@ -3089,6 +3579,7 @@ func (h *Handler) ResetCategoryDashboardLayout(c echo.Context) error {
 		return apperror.NewNotFound("entity type not found")
 	}
 
+	h.recordCategoryDashboard(c, et, "Reset category dashboard")
 	if err := h.service.ResetCategoryDashboardLayout(c.Request().Context(), etID); err != nil {
 		return err
 	}
@@ -3180,8 +3671,8 @@ func (h *Handler) SetAliasesAPI(c echo.Context) error {
 
 	// Invalidate entity names cache for the campaign so auto-linker picks up changes.
 	if h.cache != nil {
-		pattern := fmt.Sprintf("entity-names:%s:*", cc.Campaign.ID)
-		h.invalidateCachePattern(c.Request().Context(), pattern)
+		h.invalidateCachePattern(c.Request().Context(), fmt.Sprintf("entity-names:%s:*", cc.Campaign.ID))
+		h.invalidateCachePattern(c.Request().Context(), fmt.Sprintf("quick-switch-names:%s:*", cc.Campaign.ID))
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
@@ -3192,8 +3683,15 @@ func (h *Handler) SetAliasesAPI(c echo.Context) error {
 // backlinksCacheTTL is how long backlink results are cached in Redis.
 const backlinksCacheTTL = 5 * time.Minute
 
-// BacklinksFragment returns the "Referenced by" section as an HTMX fragment
-// or JSON. Results are cached in Redis for 5 minutes.
+// backlinksPayload is the cached backlinks section: @mention backlinks.
+// Records from other plugins that point at the entity (calendar events,
+// markers, ...) live in the "Appears in" panel (linked_content.go).
+type backlinksPayload struct {
+	Backlinks []BacklinkEntry `json:"backlinks"`
+}
+
+// BacklinksFragment returns the "Referenced by" section as an
+// HTMX fragment or JSON. Results are cached in Redis for 5 minutes.
 // GET /campaigns/:id/entities/:eid/backlinks
 func (h *Handler) BacklinksFragment(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -3206,18 +3704,16 @@ func (h *Handler) BacklinksFragment(c echo.Context) error {
 	role := cc.VisibilityRole()
 	userID := auth.GetUserID(c)
 
-	// Try Redis cache for JSON response.
-	cacheKey := fmt.Sprintf("backlinks:%s:%d:%s", entityID, role, userID)
-	var entries []BacklinkEntry
+	// Try Redis cache for JSON response. The "v3" key segment retires
+	// entries cached while calendar events were part of the payload.
+	cacheKey := fmt.Sprintf("backlinks:v3:%s:%d:%s", entityID, role, userID)
+	var payload backlinksPayload
 
 	if h.cache != nil {
 		cached, err := h.cache.Get(ctx, cacheKey).Result()
 		if err == nil {
-			if err := json.Unmarshal([]byte(cached), &entries); err == nil {
-				if isHTMX(c) {
-					return middleware.Render(c, http.StatusOK, blockBacklinks(cc, entries))
-				}
-				return c.JSON(http.StatusOK, map[string]any{"backlinks": entries})
+			if err := json.Unmarshal([]byte(cached), &payload); err == nil {
+				return h.renderBacklinks(c, cc, payload)
 			}
 		}
 	}
@@ -3226,23 +3722,29 @@ func (h *Handler) BacklinksFragment(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	if entries == nil {
-		entries = []BacklinkEntry{}
+	payload.Backlinks = entries
+	if payload.Backlinks == nil {
+		payload.Backlinks = []BacklinkEntry{}
 	}
 
 	// Cache in Redis.
 	if h.cache != nil {
-		if data, err := json.Marshal(entries); err == nil {
+		if data, err := json.Marshal(payload); err == nil {
 			if err := h.cache.Set(ctx, cacheKey, string(data), backlinksCacheTTL).Err(); err != nil {
 				slog.Error("failed to cache backlinks", slog.Any("error", err))
 			}
 		}
 	}
 
+	return h.renderBacklinks(c, cc, payload)
+}
+
+// renderBacklinks writes the backlinks section as HTML (HTMX) or JSON.
+func (h *Handler) renderBacklinks(c echo.Context, cc *campaigns.CampaignContext, payload backlinksPayload) error {
 	if isHTMX(c) {
-		return middleware.Render(c, http.StatusOK, blockBacklinks(cc, entries))
+		return middleware.Render(c, http.StatusOK, blockBacklinks(cc, payload.Backlinks))
 	}
-	return c.JSON(http.StatusOK, map[string]any{"backlinks": entries})
+	return c.JSON(http.StatusOK, payload)
 }
 
 // isHTMX returns true if the request was sent by HTMX.
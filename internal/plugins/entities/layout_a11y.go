@@ -0,0 +1,27 @@
+package entities
+
+import "github.com/keyxmakerx/chronicle/internal/a11y"
+
+// auditTemplateLayout runs the accessibility checks over a page template
+// and the type's color, which tints the template's badges and header.
+// Never nil, so the save response always carries a "warnings" array.
+func auditTemplateLayout(et *EntityType, layout EntityTypeLayout) []a11y.Warning {
+	warnings := auditTypeColor(et, et.Color)
+	for _, row := range layout.Rows {
+		for _, col := range row.Columns {
+			for _, b := range col.Blocks {
+				warnings = append(warnings, a11y.CheckBlock(b.ID, b.Type, b.Config)...)
+			}
+		}
+	}
+	return warnings
+}
+
+// auditTypeColor checks a type color against both themes.
+func auditTypeColor(et *EntityType, color string) []a11y.Warning {
+	warnings := []a11y.Warning{}
+	if w := a11y.CheckColor("The "+et.Name+" type", color); w != nil {
+		warnings = append(warnings, *w)
+	}
+	return warnings
+}
@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetLayoutVersionService enables undo history for entity type page
+// layouts and category dashboards.
+func (h *Handler) SetLayoutVersionService(svc campaigns.LayoutVersionService) {
+	h.layoutVersions = svc
+}
+
+// recordTemplateLayout keeps the entity type's current page layout as the
+// version a write is replacing.
+func (h *Handler) recordTemplateLayout(c echo.Context, et *EntityType, note string) {
+	if h.layoutVersions == nil {
+		return
+	}
+	raw, err := json.Marshal(et.Layout)
+	if err != nil {
+		return
+	}
+	layout := string(raw)
+	h.layoutVersions.Record(c.Request().Context(), et.CampaignID, campaigns.LayoutKindEntityTypeLayout,
+		strconv.Itoa(et.ID), &layout, note, auth.GetUserID(c))
+}
+
+// recordCategoryDashboard keeps the entity type's current category
+// dashboard (nil = default) as the version a write is replacing.
+func (h *Handler) recordCategoryDashboard(c echo.Context, et *EntityType, note string) {
+	if h.layoutVersions == nil {
+		return
+	}
+	h.layoutVersions.Record(c.Request().Context(), et.CampaignID, campaigns.LayoutKindCategoryDashboard,
+		strconv.Itoa(et.ID), et.DashboardLayout, note, auth.GetUserID(c))
+}
+
+// layoutVersionEntityType resolves :etid for the version endpoints,
+// scoped to the current campaign.
+func (h *Handler) layoutVersionEntityType(c echo.Context) (*EntityType, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil || h.layoutVersions == nil {
+		return nil, apperror.NewMissingContext()
+	}
+	etID, err := strconv.Atoi(c.Param("etid"))
+	if err != nil {
+		return nil, apperror.NewBadRequest("invalid entity type ID")
+	}
+	et, err := h.service.GetEntityTypeByID(c.Request().Context(), etID)
+	if err != nil {
+		return nil, err
+	}
+	// IDOR protection: ensure entity type belongs to this campaign.
+	if et.CampaignID != cc.Campaign.ID {
+		return nil, apperror.NewNotFound("entity type not found")
+	}
+	return et, nil
+}
+
+// layoutVersion loads the :vid version of the entity type's history.
+func (h *Handler) layoutVersion(c echo.Context, et *EntityType, kind string) (*campaigns.LayoutVersion, error) {
+	id, err := strconv.ParseInt(c.Param("vid"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, apperror.NewBadRequest("invalid version ID")
+	}
+	return h.layoutVersions.Get(c.Request().Context(), et.CampaignID, kind, strconv.Itoa(et.ID), id)
+}
+
+// listEntityTypeLayoutVersions serves one entity type's history.
+func (h *Handler) listEntityTypeLayoutVersions(c echo.Context, kind string) error {
+	et, err := h.layoutVersionEntityType(c)
+	if err != nil {
+		return err
+	}
+	list, err := h.layoutVersions.List(c.Request().Context(), et.CampaignID, kind, strconv.Itoa(et.ID))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// ListEntityTypeLayoutVersions returns earlier page layouts, newest first.
+// GET /campaigns/:id/entity-types/:etid/layout/versions
+func (h *Handler) ListEntityTypeLayoutVersions(c echo.Context) error {
+	return h.listEntityTypeLayoutVersions(c, campaigns.LayoutKindEntityTypeLayout)
+}
+
+// RestoreEntityTypeLayoutVersion puts an earlier page layout back,
+// recording the current one first so the restore can be undone.
+// POST /campaigns/:id/entity-types/:etid/layout/versions/:vid/restore
+func (h *Handler) RestoreEntityTypeLayoutVersion(c echo.Context) error {
+	et, err := h.layoutVersionEntityType(c)
+	if err != nil {
+		return err
+	}
+	v, err := h.layoutVersion(c, et, campaigns.LayoutKindEntityTypeLayout)
+	if err != nil {
+		return err
+	}
+	var layout EntityTypeLayout
+	if !v.IsDefault {
+		if err := json.Unmarshal([]byte(*v.Layout), &layout); err != nil {
+			return apperror.NewInternal(fmt.Errorf("parsing layout version %d: %w", v.ID, err))
+		}
+	}
+
+	h.recordTemplateLayout(c, et, fmt.Sprintf("Restored version %d", v.ID))
+	// Goes through the normal save so a version referencing a block type
+	// that has since been removed is rejected rather than stored.
+	if err := h.service.UpdateEntityTypeLayout(c.Request().Context(), et.ID, layout); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]any{"status": "ok", "layout": layout})
+}
+
+// ListCategoryDashboardLayoutVersions returns earlier category dashboards.
+// GET /campaigns/:id/entity-types/:etid/dashboard-layout/versions
+func (h *Handler) ListCategoryDashboardLayoutVersions(c echo.Context) error {
+	return h.listEntityTypeLayoutVersions(c, campaigns.LayoutKindCategoryDashboard)
+}
+
+// RestoreCategoryDashboardLayoutVersion puts an earlier category dashboard
+// back; a default version resets the custom layout.
+// POST /campaigns/:id/entity-types/:etid/dashboard-layout/versions/:vid/restore
+func (h *Handler) RestoreCategoryDashboardLayoutVersion(c echo.Context) error {
+	et, err := h.layoutVersionEntityType(c)
+	if err != nil {
+		return err
+	}
+	v, err := h.layoutVersion(c, et, campaigns.LayoutKindCategoryDashboard)
+	if err != nil {
+		return err
+	}
+
+	h.recordCategoryDashboard(c, et, fmt.Sprintf("Restored version %d", v.ID))
+	ctx := c.Request().Context()
+	if v.IsDefault {
+		err = h.service.ResetCategoryDashboardLayout(ctx, et.ID)
+	} else {
+		err = h.service.UpdateCategoryDashboardLayout(ctx, et.ID, *v.Layout)
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
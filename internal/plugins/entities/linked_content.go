@@ -0,0 +1,155 @@
+package entities
+
+// linked_content.go — the "Appears in" panel on entity pages. Plugins that
+// tie their own records to pages (calendar events, map markers, sessions,
+// ...) register a LinkedContentProvider; the show page lists one lazy
+// fragment per provider and this file renders them all the same way. A
+// provider only supplies rows, so the panel stays consistent and the
+// entity page needs no per-plugin wiring. Providers are wired in
+// internal/app/linked_content_adapters.go.
+//
+//	GET /campaigns/:id/entities/:eid/linked             panel shell, one lazy group per provider
+//	GET /campaigns/:id/entities/:eid/linked/:provider   HTMX group fragment
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// maxLinkedItems caps the rows one provider shows on a page.
+const maxLinkedItems = 25
+
+// LinkedItem is one row in the "Appears in" panel.
+type LinkedItem struct {
+	Name string // Display name.
+	URL  string // Where the row links to.
+	Meta string // Short secondary text, e.g. "1492/4/1 · present".
+}
+
+// LinkedContentProvider lists a plugin's records tied to an entity.
+//
+// List must apply the viewer's visibility itself (role is the campaign
+// visibility role, userID may be empty for public visitors); the panel
+// shows whatever it returns. Addon, when set, hides the provider unless
+// that addon is enabled for the campaign.
+type LinkedContentProvider struct {
+	ID        string // URL-safe slug, unique across providers.
+	Label     string // Group heading, e.g. "Calendar events".
+	Icon      string // FontAwesome icon, e.g. "fa-calendar".
+	Color     string // Hex accent for the row icons.
+	Addon     string
+	SortOrder int
+	List      func(ctx context.Context, campaignID, entityID string, role int, userID string) ([]LinkedItem, error)
+}
+
+// RegisterLinkedContentProvider adds a provider to the "Appears in" panel.
+// Called at startup; providers render in SortOrder, then registration
+// order.
+func (h *Handler) RegisterLinkedContentProvider(p LinkedContentProvider) {
+	h.linkedProviders = append(h.linkedProviders, p)
+	sort.SliceStable(h.linkedProviders, func(i, j int) bool {
+		return h.linkedProviders[i].SortOrder < h.linkedProviders[j].SortOrder
+	})
+}
+
+// linkedProvidersFor returns the providers whose addon is enabled for the
+// campaign, in render order.
+func (h *Handler) linkedProvidersFor(ctx context.Context, campaignID string) []LinkedContentProvider {
+	var out []LinkedContentProvider
+	for _, p := range h.linkedProviders {
+		if p.Addon != "" && !h.isAddonEnabled(ctx, campaignID, p.Addon) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// linkedProvider looks a provider up by ID.
+func (h *Handler) linkedProvider(id string) (LinkedContentProvider, bool) {
+	for _, p := range h.linkedProviders {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return LinkedContentProvider{}, false
+}
+
+// viewableEntity loads the :eid entity and applies campaign scope and the
+// viewer's visibility, as the entity page itself does.
+func (h *Handler) viewableEntity(c echo.Context, cc *campaigns.CampaignContext) (*Entity, error) {
+	ctx := c.Request().Context()
+	entity, err := h.service.GetByID(ctx, c.Param("eid"))
+	if err != nil {
+		return nil, err
+	}
+	if entity.CampaignID != cc.Campaign.ID {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil || !access.CanView {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	return entity, nil
+}
+
+// LinkedContentPanel renders the "Appears in" panel shell: a lazy group
+// per provider enabled for the campaign. Empty when none are.
+// GET /campaigns/:id/entities/:eid/linked
+func (h *Handler) LinkedContentPanel(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	entity, err := h.viewableEntity(c, cc)
+	if err != nil {
+		return err
+	}
+	providers := h.linkedProvidersFor(c.Request().Context(), cc.Campaign.ID)
+	return middleware.Render(c, http.StatusOK, linkedContentPanel(cc, entity.ID, providers))
+}
+
+// LinkedContentFragment renders one provider's group of the "Appears in"
+// panel; empty when the entity has nothing there. A provider error is
+// logged and renders as empty so one broken plugin can't blank the panel.
+// GET /campaigns/:id/entities/:eid/linked/:provider
+func (h *Handler) LinkedContentFragment(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+
+	p, ok := h.linkedProvider(c.Param("provider"))
+	if !ok || (p.Addon != "" && !h.isAddonEnabled(ctx, cc.Campaign.ID, p.Addon)) {
+		return apperror.NewNotFound("linked content not found")
+	}
+
+	entity, err := h.viewableEntity(c, cc)
+	if err != nil {
+		return err
+	}
+
+	items, err := p.List(ctx, cc.Campaign.ID, entity.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		slog.Warn("loading linked content",
+			slog.String("provider", p.ID),
+			slog.String("entity_id", entity.ID),
+			slog.Any("error", err),
+		)
+		items = nil
+	}
+	if len(items) > maxLinkedItems {
+		items = items[:maxLinkedItems]
+	}
+	return middleware.Render(c, http.StatusOK, linkedContentGroup(p, items))
+}
@@ -0,0 +1,104 @@
+// linked_content_test.go — the "Appears in" panel: provider ordering and
+// addon gating, and the group/backlink rendering split that moved
+// calendar events out of the backlinks section.
+
+package entities
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+func renderString(t *testing.T, c templ.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.Render(context.Background(), &buf); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLinkedProvidersFor_SortsAndGatesOnAddon(t *testing.T) {
+	h := &Handler{}
+	h.SetAddonChecker(&mockAddonChecker{enabled: map[string]bool{"calendar": true}})
+	h.RegisterLinkedContentProvider(LinkedContentProvider{ID: "sessions", Addon: "sessions", SortOrder: 30})
+	h.RegisterLinkedContentProvider(LinkedContentProvider{ID: "always", SortOrder: 20})
+	h.RegisterLinkedContentProvider(LinkedContentProvider{ID: "calendar-events", Addon: "calendar", SortOrder: 10})
+
+	got := h.linkedProvidersFor(context.Background(), "camp-1")
+	var ids []string
+	for _, p := range got {
+		ids = append(ids, p.ID)
+	}
+	if strings.Join(ids, ",") != "calendar-events,always" {
+		t.Errorf("providers = %v, want [calendar-events always]", ids)
+	}
+
+	if _, ok := h.linkedProvider("sessions"); !ok {
+		t.Error("registered provider not found by ID")
+	}
+	if _, ok := h.linkedProvider("nope"); ok {
+		t.Error("unknown provider found")
+	}
+}
+
+func TestLinkedContentPanel_LazyGroupPerProvider(t *testing.T) {
+	cc := &campaigns.CampaignContext{Campaign: &campaigns.Campaign{ID: "camp-1"}}
+	providers := []LinkedContentProvider{{ID: "calendar-events"}, {ID: "map-markers"}}
+
+	html := renderString(t, linkedContentPanel(cc, "ent-1", providers))
+	for _, want := range []string{
+		"Appears in",
+		"/campaigns/camp-1/entities/ent-1/linked/calendar-events",
+		"/campaigns/camp-1/entities/ent-1/linked/map-markers",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in:\n%s", want, html)
+		}
+	}
+
+	if html := renderString(t, linkedContentPanel(cc, "ent-1", nil)); strings.TrimSpace(html) != "" {
+		t.Errorf("panel without providers rendered %q", html)
+	}
+}
+
+func TestLinkedContentGroup(t *testing.T) {
+	p := LinkedContentProvider{ID: "calendar-events", Label: "Calendar events", Icon: "fa-calendar", Color: "#f59e0b"}
+	items := []LinkedItem{
+		{Name: "Greengrass", URL: "/campaigns/camp-1/calendar/v2/cal-1?year=1492&month=4&day=1", Meta: "1492/4/1 · present"},
+		{Name: "Shieldmeet", URL: "/campaigns/camp-1/calendar/v2/cal-2?year=1492&month=7&day=2"},
+	}
+
+	html := renderString(t, linkedContentGroup(p, items))
+	for _, want := range []string{"Calendar events", "(2)", "Greengrass", "1492/4/1 · present", "/calendar/v2/cal-1?year=1492", `x-init="any = true"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in:\n%s", want, html)
+		}
+	}
+
+	if html := renderString(t, linkedContentGroup(p, nil)); strings.TrimSpace(html) != "" {
+		t.Errorf("empty group rendered %q", html)
+	}
+}
+
+func TestBlockBacklinks_MentionsOnly(t *testing.T) {
+	cc := &campaigns.CampaignContext{Campaign: &campaigns.Campaign{ID: "camp-1"}}
+
+	html := renderString(t, blockBacklinks(cc, []BacklinkEntry{{Entity: Entity{ID: "ent-2", Name: "Elminster"}}}))
+	if !strings.Contains(html, "Referenced by") || !strings.Contains(html, "Elminster") {
+		t.Errorf("backlinks missing mention:\n%s", html)
+	}
+	if strings.Contains(html, "Appears in") {
+		t.Error("backlinks still render the Appears in list")
+	}
+
+	if html := renderString(t, blockBacklinks(cc, nil)); strings.TrimSpace(html) != "" {
+		t.Errorf("no backlinks rendered %q", html)
+	}
+}
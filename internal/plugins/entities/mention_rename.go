@@ -0,0 +1,257 @@
+package entities
+
+// mention_rename.go — carrying an entity rename over to the mentions that
+// still show its old name. A mention stores the target's ID in the link, so
+// it keeps resolving after a rename, but its text was copied when it was
+// inserted and goes stale. The rewrite only touches links to the renamed
+// entity whose text is exactly the old name (with or without the "@"), so a
+// label an author changed on purpose ("the old king", an alias) is left alone.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NotifMentionRename is the notification type of the rename job's summary.
+const NotifMentionRename = "mention_rename"
+
+// mentionRenameTimeout bounds one background rename job.
+const mentionRenameTimeout = 5 * time.Minute
+
+// UserNotifier writes an in-app notification for a user. Implemented by the
+// sessions plugin's notification store and injected via SetNotifier.
+type UserNotifier interface {
+	NotifyUser(ctx context.Context, userID, campaignID, kind, message, link string) error
+}
+
+// SetNotifier sets where background jobs report their summaries.
+// Called after all plugins are wired to avoid initialization order issues.
+func (h *Handler) SetNotifier(n UserNotifier) {
+	h.notifier = n
+}
+
+// MentionRenamePage is one page whose mentions the rename job updated.
+type MentionRenamePage struct {
+	ID       string
+	Name     string
+	Mentions int
+}
+
+// MentionRenameSummary reports what a rename job changed.
+type MentionRenameSummary struct {
+	OldName  string
+	NewName  string
+	Mentions int
+	Pages    []MentionRenamePage
+}
+
+// Message is the one-line summary shown in the notification.
+func (m *MentionRenameSummary) Message() string {
+	if len(m.Pages) == 0 {
+		return fmt.Sprintf("No mentions of %q needed updating after the rename to %q", m.OldName, m.NewName)
+	}
+	names := make([]string, 0, 3)
+	for i, p := range m.Pages {
+		if i == 3 {
+			break
+		}
+		names = append(names, p.Name)
+	}
+	list := strings.Join(names, ", ")
+	if more := len(m.Pages) - len(names); more > 0 {
+		list += fmt.Sprintf(" and %d more", more)
+	}
+	return fmt.Sprintf("Updated %d %s of %q to %q on %d %s: %s",
+		m.Mentions, plural(m.Mentions, "mention", "mentions"), m.OldName, m.NewName,
+		len(m.Pages), plural(len(m.Pages), "page", "pages"), list)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// PropagateRename rewrites mentions of entityID that still read oldName to
+// read newName, on every page in the campaign the renamer can see. Pages that
+// fail to update are logged and skipped so one bad entry can't stop the rest.
+func (s *entityService) PropagateRename(ctx context.Context, campaignID, entityID, oldName, newName string, role int, userID string) (*MentionRenameSummary, error) {
+	oldName, newName = strings.TrimSpace(oldName), strings.TrimSpace(newName)
+	summary := &MentionRenameSummary{OldName: oldName, NewName: newName, Pages: []MentionRenamePage{}}
+	if oldName == "" || newName == "" || oldName == newName {
+		return summary, nil
+	}
+
+	sources, err := s.entities.FindMentioningEntities(ctx, campaignID, entityID, role, userID)
+	if err != nil {
+		return nil, fmt.Errorf("finding mentions: %w", err)
+	}
+
+	for _, e := range sources {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		if e.Entry == nil || e.EntryHTML == nil {
+			continue
+		}
+		entryJSON, inJSON, err := renameMentionsJSON(*e.Entry, entityID, oldName, newName)
+		if err != nil {
+			slog.Warn("mention rename: unreadable entry", slog.String("entity_id", e.ID), slog.Any("error", err))
+			continue
+		}
+		entryHTML, inHTML := renameMentionsHTML(*e.EntryHTML, entityID, oldName, newName)
+		if inJSON == 0 && inHTML == 0 {
+			continue
+		}
+		if err := s.UpdateEntry(ctx, e.ID, entryJSON, entryHTML); err != nil {
+			slog.Warn("mention rename: updating entry failed", slog.String("entity_id", e.ID), slog.Any("error", err))
+			continue
+		}
+		// Each mention lives in both the JSON and the HTML; count it once.
+		n := max(inJSON, inHTML)
+		summary.Mentions += n
+		summary.Pages = append(summary.Pages, MentionRenamePage{ID: e.ID, Name: e.Name, Mentions: n})
+	}
+	return summary, nil
+}
+
+// isStaleLabel reports whether a mention's text is the old name, returning
+// the label to use instead (keeping the "@" if it had one).
+func isStaleLabel(text, oldName, newName string) (string, bool) {
+	switch text {
+	case oldName:
+		return newName, true
+	case "@" + oldName:
+		return "@" + newName, true
+	}
+	return "", false
+}
+
+// renameMentionsJSON rewrites stale mention labels in ProseMirror JSON: text
+// nodes carrying a link mark to entityID, and mention nodes. Returns the
+// input unchanged when nothing matched.
+func renameMentionsJSON(entryJSON, entityID, oldName, newName string) (string, int, error) {
+	dec := json.NewDecoder(strings.NewReader(entryJSON))
+	dec.UseNumber() // keep numeric attrs byte-for-byte
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return entryJSON, 0, err
+	}
+
+	var count int
+	var walk func(node any)
+	walk = func(node any) {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return
+		}
+		switch m["type"] {
+		case "text":
+			text, _ := m["text"].(string)
+			if label, stale := isStaleLabel(text, oldName, newName); stale && marksMention(m["marks"], entityID) {
+				m["text"] = label
+				count++
+			}
+		case "mention":
+			if attrs, ok := m["attrs"].(map[string]any); ok && attrs["id"] == entityID {
+				name, _ := attrs["name"].(string)
+				if label, stale := isStaleLabel(name, oldName, newName); stale {
+					attrs["name"] = label
+					count++
+				}
+			}
+		}
+		if children, ok := m["content"].([]any); ok {
+			for _, child := range children {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	if count == 0 {
+		return entryJSON, 0, nil
+	}
+
+	// The editor doesn't HTML-escape JSON; neither should we.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return entryJSON, 0, err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), count, nil
+}
+
+// marksMention reports whether a text node's marks include a link to entityID.
+func marksMention(marks any, entityID string) bool {
+	list, _ := marks.([]any)
+	for _, mk := range list {
+		m, _ := mk.(map[string]any)
+		attrs, _ := m["attrs"].(map[string]any)
+		if m["type"] == "link" && attrs["data-mention-id"] == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionAnchorPattern matches one anchor with plain-text content; anchors
+// with nested markup aren't labels the editor produced and are skipped.
+var mentionAnchorPattern = regexp.MustCompile(`<a\s([^>]*)>([^<]*)</a>`)
+
+// renameMentionsHTML rewrites stale mention labels in rendered entry HTML.
+func renameMentionsHTML(entryHTML, entityID, oldName, newName string) (string, int) {
+	attr := `data-mention-id="` + entityID + `"`
+	var count int
+	out := mentionAnchorPattern.ReplaceAllStringFunc(entryHTML, func(a string) string {
+		m := mentionAnchorPattern.FindStringSubmatch(a)
+		if !strings.Contains(" "+m[1], " "+attr) {
+			return a
+		}
+		label, stale := isStaleLabel(html.UnescapeString(m[2]), oldName, newName)
+		if !stale {
+			return a
+		}
+		count++
+		return "<a " + m[1] + ">" + html.EscapeString(label) + "</a>"
+	})
+	return out, count
+}
+
+// startMentionRename runs PropagateRename in the background and sends the
+// renamer a notification summarising the pages it updated. A campaign with
+// hundreds of mentions shouldn't hold the save request open.
+func (h *Handler) startMentionRename(campaignID, entityID, oldName, newName string, role int, userID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mentionRenameTimeout)
+		defer cancel()
+
+		summary, err := h.service.PropagateRename(ctx, campaignID, entityID, oldName, newName, role, userID)
+		if err != nil {
+			slog.Warn("mention rename failed", slog.String("entity_id", entityID), slog.Any("error", err))
+			if summary == nil {
+				return
+			}
+		}
+		slog.Info("mention rename finished",
+			slog.String("entity_id", entityID),
+			slog.Int("pages", len(summary.Pages)),
+			slog.Int("mentions", summary.Mentions))
+
+		if h.notifier == nil {
+			return
+		}
+		link := fmt.Sprintf("/campaigns/%s/entities/%s", campaignID, entityID)
+		if err := h.notifier.NotifyUser(ctx, userID, campaignID, NotifMentionRename, summary.Message(), link); err != nil {
+			slog.Warn("mention rename: notifying failed", slog.Any("error", err))
+		}
+	}()
+}
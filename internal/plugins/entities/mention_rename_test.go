@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRenameMentionsJSON(t *testing.T) {
+	link := func(id string) string {
+		return `[{"type":"link","attrs":{"href":"/e","data-mention-id":"` + id + `"}}]`
+	}
+	cases := []struct {
+		name  string
+		in    string
+		count int
+		want  string // substring of the output
+	}{
+		{"@mention", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"@Red Keep","marks":` + link("e1") + `}]}]}`, 1, `"text":"@Black Keep"`},
+		{"autolink without @", `{"type":"doc","content":[{"type":"text","text":"Red Keep","marks":` + link("e1") + `}]}`, 1, `"text":"Black Keep"`},
+		{"custom label kept", `{"type":"doc","content":[{"type":"text","text":"the old fort","marks":` + link("e1") + `}]}`, 0, `"text":"the old fort"`},
+		{"other entity kept", `{"type":"doc","content":[{"type":"text","text":"@Red Keep","marks":` + link("e2") + `}]}`, 0, `"text":"@Red Keep"`},
+		{"plain text kept", `{"type":"doc","content":[{"type":"text","text":"Red Keep"}]}`, 0, `"text":"Red Keep"`},
+		{"mention node", `{"type":"doc","content":[{"type":"mention","attrs":{"id":"e1","name":"Red Keep"}}]}`, 1, `"name":"Black Keep"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, n, err := renameMentionsJSON(tc.in, "e1", "Red Keep", "Black Keep")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tc.count {
+				t.Errorf("count = %d, want %d", n, tc.count)
+			}
+			if !strings.Contains(out, tc.want) {
+				t.Errorf("output %s missing %s", out, tc.want)
+			}
+			if n == 0 && out != tc.in {
+				t.Errorf("unchanged entry was re-encoded: %s", out)
+			}
+		})
+	}
+}
+
+func TestRenameMentionsHTML(t *testing.T) {
+	in := `<p><a data-mention-id="e1" href="/e1">@Tom &amp; Jerry</a>, ` +
+		`<a data-mention-id="e1" href="/e1">the cat</a>, ` +
+		`<a data-mention-id="e12" href="/e12">@Tom &amp; Jerry</a></p>`
+	out, n := renameMentionsHTML(in, "e1", "Tom & Jerry", "Tom <3 Jerry")
+	if n != 1 {
+		t.Fatalf("count = %d, want 1", n)
+	}
+	want := `<p><a data-mention-id="e1" href="/e1">@Tom &lt;3 Jerry</a>, ` +
+		`<a data-mention-id="e1" href="/e1">the cat</a>, ` +
+		`<a data-mention-id="e12" href="/e12">@Tom &amp; Jerry</a></p>`
+	if out != want {
+		t.Errorf("got  %s\nwant %s", out, want)
+	}
+}
+
+func TestPropagateRename(t *testing.T) {
+	entry := func(label string) (*string, *string) {
+		j := `{"type":"doc","content":[{"type":"text","text":"` + label + `","marks":[{"type":"link","attrs":{"data-mention-id":"e1"}}]}]}`
+		h := `<p><a data-mention-id="e1" href="/e1">` + label + `</a></p>`
+		return &j, &h
+	}
+	staleJSON, staleHTML := entry("@Red Keep")
+	customJSON, customHTML := entry("the fort")
+
+	var written []string
+	repo := &mockEntityRepo{
+		findMentioningFn: func(string) ([]Entity, error) {
+			return []Entity{
+				{ID: "p1", Name: "Kings Landing", Entry: staleJSON, EntryHTML: staleHTML},
+				{ID: "p2", Name: "Dragonstone", Entry: customJSON, EntryHTML: customHTML},
+			}, nil
+		},
+		updateEntryFn: func(_ context.Context, id, entryJSON, entryHTML string) error {
+			written = append(written, id)
+			if !strings.Contains(entryJSON, "@Black Keep") || !strings.Contains(entryHTML, "@Black Keep") {
+				t.Errorf("entry not rewritten: %s / %s", entryJSON, entryHTML)
+			}
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockEntityTypeRepo{})
+
+	summary, err := svc.PropagateRename(context.Background(), "camp-1", "e1", "Red Keep", "Black Keep", 3, "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(written) != 1 || written[0] != "p1" {
+		t.Errorf("updated %v, want only p1", written)
+	}
+	if summary.Mentions != 1 || len(summary.Pages) != 1 || summary.Pages[0].Name != "Kings Landing" {
+		t.Errorf("summary = %+v", summary)
+	}
+	if msg := summary.Message(); !strings.Contains(msg, "1 mention") || !strings.Contains(msg, "Kings Landing") {
+		t.Errorf("message = %q", msg)
+	}
+}
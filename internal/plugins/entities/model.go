@@ -23,25 +23,30 @@ import (
 // Location). Each campaign has its own set of entity types with configurable
 // fields that drive dynamic form rendering and profile display.
 type EntityType struct {
-	ID              int               `json:"id"`
-	CampaignID      string            `json:"campaign_id"`
-	Slug            string            `json:"slug"`
-	Name            string            `json:"name"`
-	NamePlural      string            `json:"name_plural"`
-	Icon            string            `json:"icon"`
-	Color           string            `json:"color"`
-	PresetCategory  *string           `json:"preset_category,omitempty"`   // System preset category ("character", "item", "creature").
-	ParentTypeID    *int              `json:"parent_type_id,omitempty"`    // Parent entity type ID for sub-type hierarchy.
-	Claimable       *bool             `json:"claimable,omitempty"`         // nil = unset (legacy heuristic); true/false = explicit Owner choice for player claiming.
-	Description     *string           `json:"description,omitempty"`       // Rich text shown on category dashboard.
-	PinnedEntityIDs []string          `json:"pinned_entity_ids,omitempty"` // Entity IDs pinned to dashboard top.
-	DashboardLayout *string           `json:"dashboard_layout,omitempty"`  // JSON layout; nil = use hardcoded default.
-	Fields          []FieldDefinition `json:"fields"`
-	Layout          EntityTypeLayout  `json:"layout"`
-	SortOrder       int               `json:"sort_order"`
-	IsDefault       bool              `json:"is_default"`
-	Enabled         bool              `json:"enabled"`
-	ParentTypeName  *string           `json:"parent_type_name,omitempty"` // Joined field: parent type's name (not stored).
+	ID                int               `json:"id"`
+	CampaignID        string            `json:"campaign_id"`
+	Slug              string            `json:"slug"`
+	Name              string            `json:"name"`
+	NamePlural        string            `json:"name_plural"`
+	Icon              string            `json:"icon"`
+	Color             string            `json:"color"`
+	DefaultImage      *string           `json:"default_image,omitempty"`     // Media file shown for pages of this type with no image of their own.
+	AvatarStyle       string            `json:"avatar_style,omitempty"`      // How image-less pages are drawn: AvatarStyleIcon or AvatarStyleInitials.
+	PresetCategory    *string           `json:"preset_category,omitempty"`   // System preset category ("character", "item", "creature").
+	ParentTypeID      *int              `json:"parent_type_id,omitempty"`    // Parent entity type ID for sub-type hierarchy.
+	Claimable         *bool             `json:"claimable,omitempty"`         // nil = unset (legacy heuristic); true/false = explicit Owner choice for player claiming.
+	PlayerSubmissions bool              `json:"player_submissions"`          // Players may submit pages of this type for Scribe+ approval.
+	PlayersCanCreate  bool              `json:"players_can_create"`          // Players may create pages of this type and edit the ones they created.
+	PlayersCanEdit    bool              `json:"players_can_edit"`            // Players may edit any page of this type they can see.
+	Description       *string           `json:"description,omitempty"`       // Rich text shown on category dashboard.
+	PinnedEntityIDs   []string          `json:"pinned_entity_ids,omitempty"` // Entity IDs pinned to dashboard top.
+	DashboardLayout   *string           `json:"dashboard_layout,omitempty"`  // JSON layout; nil = use hardcoded default.
+	Fields            []FieldDefinition `json:"fields"`
+	Layout            EntityTypeLayout  `json:"layout"`
+	SortOrder         int               `json:"sort_order"`
+	IsDefault         bool              `json:"is_default"`
+	Enabled           bool              `json:"enabled"`
+	ParentTypeName    *string           `json:"parent_type_name,omitempty"` // Joined field: parent type's name (not stored).
 }
 
 // ParseCategoryDashboardLayout parses the entity type's dashboard_layout JSON
@@ -66,6 +71,21 @@ type EntityTypeLayout struct {
 	Rows []TemplateRow `json:"rows"`
 }
 
+// layoutHasGroupRules reports whether any top-level block is limited to
+// campaign groups, which needs the viewer's groups loaded before render.
+func layoutHasGroupRules(l EntityTypeLayout) bool {
+	for _, row := range l.Rows {
+		for _, col := range row.Columns {
+			for _, b := range col.Blocks {
+				if len(campaigns.BlockGroupIDs(b.Config)) > 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // TemplateRow is a horizontal row in the page template grid.
 type TemplateRow struct {
 	ID      string           `json:"id"`
@@ -292,7 +312,7 @@ func convertLegacyLayout(sections []struct {
 type FieldDefinition struct {
 	Key     string   `json:"key"`     // Machine-readable identifier (e.g., "age", "alignment").
 	Label   string   `json:"label"`   // Human-readable label (e.g., "Age", "Alignment").
-	Type    string   `json:"type"`    // Input type: text, number, select, textarea, checkbox, url.
+	Type    string   `json:"type"`    // Input type: text, number, select, textarea, checkbox, url, date.
 	Section string   `json:"section"` // Grouping for display (e.g., "Basics", "Appearance").
 	Options []string `json:"options"` // Valid values for select fields. Empty for other types.
 	// GMOnly marks the field's VALUE as GM-only: it is stripped from
@@ -312,33 +332,42 @@ type FieldDefinition struct {
 	// and the GM — e.g. a character's backstory — but not party-wide. See
 	// C-FIELDS-OWNER-FILTER.
 	OwnerOnly bool `json:"owner_only,omitempty"`
+	// Anniversary, on a "date" field, puts a yearly event for the date on
+	// the campaign calendar linked back to the entity (birthdays,
+	// foundings, deaths). See date_fields.go.
+	Anniversary bool `json:"anniversary,omitempty"`
 }
 
 // Entity represents a single worldbuilding object — a character, location,
 // item, or any other type defined in the campaign's entity types.
 type Entity struct {
-	ID              string          `json:"id"`
-	CampaignID      string          `json:"campaign_id"`
-	EntityTypeID    int             `json:"entity_type_id"`
-	Name            string          `json:"name"`
-	Slug            string          `json:"slug"`
-	Entry           *string         `json:"entry,omitempty"`             // TipTap/ProseMirror JSON document.
-	EntryHTML       *string         `json:"entry_html,omitempty"`        // Pre-rendered HTML from entry.
-	PlayerNotes     *string         `json:"player_notes,omitempty"`      // Player-facing ProseMirror JSON (synced as a player-visible Foundry page).
-	PlayerNotesHTML *string         `json:"player_notes_html,omitempty"` // Pre-rendered HTML from player_notes.
-	ImagePath       *string         `json:"image_path,omitempty"`
-	CoverImagePath  *string         `json:"cover_image_path,omitempty"` // Full-width banner image.
-	ParentID        *string         `json:"parent_id,omitempty"`        // Parent entity ID (hierarchy). Mutually exclusive with ParentNodeID.
-	ParentNodeID    *string         `json:"parent_node_id,omitempty"`   // Parent sidebar folder node ID. Mutually exclusive with ParentID.
-	SortOrder       int             `json:"sort_order"`                 // Manual ordering within parent/category (0 = default).
-	TypeLabel       *string         `json:"type_label,omitempty"`       // Freeform subtype (e.g., "City" for a Location).
-	IsPrivate       bool            `json:"is_private"`
-	Visibility      VisibilityMode  `json:"visibility"`
-	IsTemplate      bool            `json:"is_template"`
-	FieldsData      map[string]any  `json:"fields_data"`
-	FieldOverrides  *FieldOverrides `json:"field_overrides,omitempty"` // Per-entity field customizations.
-	PopupConfig     *PopupConfig    `json:"popup_config,omitempty"`    // Controls hover tooltip content.
-	CreatedBy       string          `json:"created_by"`
+	ID              string            `json:"id"`
+	CampaignID      string            `json:"campaign_id"`
+	EntityTypeID    int               `json:"entity_type_id"`
+	Name            string            `json:"name"`
+	Slug            string            `json:"slug"`
+	SlugCustom      bool              `json:"slug_custom"`                 // Owner-set slug; renames keep it instead of re-deriving from the name.
+	Entry           *string           `json:"entry,omitempty"`             // TipTap/ProseMirror JSON document.
+	EntryHTML       *string           `json:"entry_html,omitempty"`        // Pre-rendered HTML from entry.
+	PlayerNotes     *string           `json:"player_notes,omitempty"`      // Player-facing ProseMirror JSON (synced as a player-visible Foundry page).
+	PlayerNotesHTML *string           `json:"player_notes_html,omitempty"` // Pre-rendered HTML from player_notes.
+	ImagePath       *string           `json:"image_path,omitempty"`
+	CoverImagePath  *string           `json:"cover_image_path,omitempty"` // Full-width banner image.
+	ImageAlt        *string           `json:"image_alt,omitempty"`        // Alt text for ImagePath; reset when the image changes.
+	ImageCaption    *string           `json:"image_caption,omitempty"`    // Caption shown under the header image.
+	ParentID        *string           `json:"parent_id,omitempty"`        // Parent entity ID (hierarchy). Mutually exclusive with ParentNodeID.
+	ParentNodeID    *string           `json:"parent_node_id,omitempty"`   // Parent sidebar folder node ID. Mutually exclusive with ParentID.
+	SortOrder       int               `json:"sort_order"`                 // Manual ordering within parent/category (0 = default).
+	TypeLabel       *string           `json:"type_label,omitempty"`       // Freeform subtype (e.g., "City" for a Location).
+	IsPrivate       bool              `json:"is_private"`
+	Visibility      VisibilityMode    `json:"visibility"`
+	IsTemplate      bool              `json:"is_template"`
+	Status          EntityStatus      `json:"status"` // Lifecycle state: draft, published or archived.
+	FieldsData      map[string]any    `json:"fields_data"`
+	FieldOverrides  *FieldOverrides   `json:"field_overrides,omitempty"` // Per-entity field customizations.
+	PopupConfig     *PopupConfig      `json:"popup_config,omitempty"`    // Controls hover tooltip content.
+	Appearance      *EntityAppearance `json:"appearance,omitempty"`      // Per-page color override; nil keeps the type's look.
+	CreatedBy       string            `json:"created_by"`
 	// OwnerUserID claims an entity for a player. Nullable: most entities
 	// (locations, factions, lore) are not owned. Character-shaped entities
 	// surface on the owner's "My Characters" landing page. Set by Foundry
@@ -360,6 +389,10 @@ type Entity struct {
 	TypeIcon       string `json:"type_icon,omitempty"`
 	TypeColor      string `json:"type_color,omitempty"`
 	TypeSlug       string `json:"type_slug,omitempty"`
+	// TypeDefaultImage and TypeAvatarStyle are the type's fallbacks for a
+	// page without its own image; see CardImagePath.
+	TypeDefaultImage *string `json:"type_default_image,omitempty"`
+	TypeAvatarStyle  string  `json:"type_avatar_style,omitempty"`
 
 	// Tags is populated at the handler level via batch fetch, not by the repository.
 	Tags []EntityTagInfo `json:"tags,omitempty"`
@@ -373,6 +406,38 @@ func (e *Entity) IsOwnedBy(userID string) bool {
 	return e != nil && userID != "" && e.OwnerUserID != nil && *e.OwnerUserID == userID
 }
 
+// HeaderImageAlt is the alt text to render for the header image: the
+// author's description, or the entity name for images that predate alt
+// text (better than nothing, though it says what the page is rather than
+// what the picture shows).
+func (e *Entity) HeaderImageAlt() string {
+	if e.ImageAlt != nil && strings.TrimSpace(*e.ImageAlt) != "" {
+		return *e.ImageAlt
+	}
+	return e.Name
+}
+
+// HeaderImageCaption is the header image caption, or "".
+func (e *Entity) HeaderImageCaption() string {
+	if e.ImageCaption == nil {
+		return ""
+	}
+	return *e.ImageCaption
+}
+
+// Limits on image descriptions. Alt text should be a sentence, not an
+// essay -- screen readers can't skip through it -- so its cap is tighter.
+const (
+	maxImageAltLength     = 300
+	maxImageCaptionLength = 500
+)
+
+// ImageTextInput is the alt text and caption for an entity's header image.
+type ImageTextInput struct {
+	AltText string `json:"alt_text" form:"alt_text"`
+	Caption string `json:"caption" form:"caption"`
+}
+
 // FieldOverrides holds per-entity field customizations that override the
 // entity type's field template. This allows individual entities to add,
 // hide, or modify fields without affecting the entire category.
@@ -473,6 +538,7 @@ type CreateEntityRequest struct {
 	ParentID     string `json:"parent_id" form:"parent_id"`
 	IsPrivate    bool   `json:"is_private" form:"is_private"`
 	TemplateID   int    `json:"template_id" form:"template_id"` // Optional content template to pre-fill.
+	Status       string `json:"status" form:"status"`           // "draft" from the Save as Draft button; empty publishes.
 }
 
 // UpdateEntityRequest holds the data submitted by the entity edit form.
@@ -486,7 +552,8 @@ type UpdateEntityRequest struct {
 	TypeLabel         string     `json:"type_label" form:"type_label"`
 	ParentID          string     `json:"parent_id" form:"parent_id"`
 	Entry             string     `json:"entry" form:"entry"`
-	ExpectedUpdatedAt *time.Time `json:"expected_updated_at"` // Optimistic concurrency (optional, JSON API only).
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at"`                    // Optimistic concurrency (optional, JSON API only).
+	UpdateMentions    bool       `json:"update_mentions" form:"update_mentions"` // On rename, fix mentions showing the old name.
 }
 
 // --- Service Input DTOs ---
@@ -498,6 +565,7 @@ type CreateEntityInput struct {
 	TypeLabel    string
 	ParentID     string // Empty string = no parent.
 	IsPrivate    bool
+	Status       EntityStatus // Empty = published.
 	FieldsData   map[string]any
 	// OwnerUserID claims the entity for a player at create time. Optional;
 	// nil means unclaimed. Only honored if the user is a member of the
@@ -538,6 +606,7 @@ type ListOptions struct {
 	PerPage  int
 	Sort     string   // "name" (default), "updated", "created"
 	TagSlugs []string // Filter by tag slugs (AND logic — entity must have all listed tags).
+	Status   string   // Lifecycle filter: "" = any, StatusFilterActive, or one EntityStatus. See status.go.
 }
 
 // DefaultListOptions returns sensible defaults for pagination.
@@ -687,6 +756,13 @@ type CastView struct {
 // slugPattern matches one or more non-alphanumeric characters for replacement.
 var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
 
+// EntitySlugPath is the human-readable URL of an entity page:
+// /campaigns/<campaign-slug>/e/<entity-slug>. The /entities/<uuid> URL stays
+// canonical for everything internal; this one is for sharing.
+func EntitySlugPath(campaignSlug, entitySlug string) string {
+	return "/campaigns/" + campaignSlug + "/e/" + entitySlug
+}
+
 // Slugify creates a URL-safe slug from a name. Lowercase, replace
 // non-alphanumeric characters with hyphens, trim leading/trailing hyphens.
 func Slugify(name string) string {
@@ -793,6 +869,37 @@ type EntityNameEntry struct {
 	IsAlias  bool   `json:"is_alias,omitempty"`
 }
 
+// --- Tooltip Previews ---
+
+// MaxPreviewBatch caps the IDs one batch preview request may ask for. The
+// tooltip widget chunks larger pages.
+const MaxPreviewBatch = 50
+
+// PreviewAttribute is one label/value pair shown in a tooltip.
+type PreviewAttribute struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// EntityPreview is the data behind an entity's hover tooltip, already
+// filtered for the viewer: popup_config sections, GM secrets and restricted
+// field values are removed before it is built.
+type EntityPreview struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	TypeName     string             `json:"type_name"`
+	TypeIcon     string             `json:"type_icon"`
+	TypeColor    string             `json:"type_color"`
+	ImagePath    string             `json:"image_path"`
+	ImageAlt     string             `json:"image_alt,omitempty"`
+	Initials     string             `json:"initials,omitempty"` // Avatar text when there's no image and the type draws initials.
+	TypeLabel    string             `json:"type_label"`
+	IsPrivate    bool               `json:"is_private"`
+	EntryExcerpt string             `json:"entry_excerpt"`
+	Attributes   []PreviewAttribute `json:"attributes"`
+	UpdatedAt    time.Time          `json:"-"`
+}
+
 // --- Entity Aliases ---
 
 // EntityAlias represents an alternative name for an entity. Aliases appear
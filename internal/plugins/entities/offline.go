@@ -0,0 +1,100 @@
+// offline.go — the per-campaign content manifest behind offline read mode.
+// The service worker (static/js/sw.js) fetches it, compares each entry's
+// revision with what it has cached, and refetches only the pages and assets
+// that changed. The manifest is built for the requesting viewer, so it never
+// lists a page they couldn't open online.
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/templates/layouts"
+)
+
+const (
+	// offlineManifestMaxEntries bounds the manifest for very large
+	// campaigns; a table session needs the wiki, not every stub.
+	offlineManifestMaxEntries = 5000
+
+	// offlineManifestPageSize is the listing page size used while building.
+	offlineManifestPageSize = 500
+)
+
+// OfflineManifest lists everything a service worker should cache to read the
+// campaign offline. Version changes whenever any entry's revision does, and
+// doubles as the response ETag.
+type OfflineManifest struct {
+	CampaignID  string         `json:"campaign_id"`
+	Version     string         `json:"version"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Entries     []OfflineEntry `json:"entries"`
+	Truncated   bool           `json:"truncated,omitempty"`
+}
+
+// OfflineEntry is one cacheable page with the assets it renders.
+type OfflineEntry struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Revision  string    `json:"revision"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Assets    []string  `json:"assets,omitempty"`
+}
+
+// OfflineManifest builds the viewer's offline manifest. Templates are left
+// out — they're authoring scaffolding, not reading material.
+func (s *entityService) OfflineManifest(ctx context.Context, campaignID string, role int, userID string) (*OfflineManifest, error) {
+	m := &OfflineManifest{CampaignID: campaignID, GeneratedAt: time.Now().UTC(), Entries: []OfflineEntry{}}
+	hash := sha256.New()
+
+	for page := 1; ; page++ {
+		batch, total, err := s.entities.ListByCampaign(ctx, campaignID, nil, role, userID,
+			ListOptions{Page: page, PerPage: offlineManifestPageSize, Sort: "name"})
+		if err != nil {
+			return nil, fmt.Errorf("listing offline entities: %w", err)
+		}
+		for _, e := range batch {
+			if e.IsTemplate {
+				continue
+			}
+			if len(m.Entries) >= offlineManifestMaxEntries {
+				m.Truncated = true
+				break
+			}
+			entry := offlineEntry(campaignID, e)
+			fmt.Fprintf(hash, "%s:%s\n", entry.ID, entry.Revision)
+			m.Entries = append(m.Entries, entry)
+		}
+		if m.Truncated || len(batch) == 0 || page*offlineManifestPageSize >= total {
+			break
+		}
+	}
+
+	m.Version = hex.EncodeToString(hash.Sum(nil))[:16]
+	return m, nil
+}
+
+// offlineEntry describes one page. The revision is the page's updated_at in
+// milliseconds: every content edit bumps it, and it is cheap to compare.
+func offlineEntry(campaignID string, e Entity) OfflineEntry {
+	entry := OfflineEntry{
+		ID:        e.ID,
+		Name:      e.Name,
+		URL:       "/campaigns/" + campaignID + "/entities/" + e.ID,
+		Revision:  strconv.FormatInt(e.UpdatedAt.UnixMilli(), 10),
+		UpdatedAt: e.UpdatedAt,
+	}
+	// Unsigned media URLs on purpose: signed ones expire and would churn the
+	// worker's cache keys. The worker fetches with the session cookie.
+	for _, p := range []*string{e.ImagePath, e.CoverImagePath} {
+		if p != nil && *p != "" {
+			entry.Assets = append(entry.Assets, layouts.MediaURL(context.Background(), *p))
+		}
+	}
+	return entry
+}
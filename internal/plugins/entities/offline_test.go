@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// offlineRepo lists the given pages, recording the identity it was asked for.
+func offlineRepo(pages *[]Entity, gotRole *int, gotUser *string) *mockEntityRepo {
+	return &mockEntityRepo{
+		listByCampaignFn: func(_ context.Context, _ string, _ []int, role int, userID string, _ ListOptions) ([]Entity, int, error) {
+			*gotRole, *gotUser = role, userID
+			return *pages, len(*pages), nil
+		},
+	}
+}
+
+func TestOfflineManifest(t *testing.T) {
+	img := "2026/03/b7c17bb1-6563-462c-8b49-5b2e8bd57108.webp"
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pages := []Entity{
+		{ID: "e1", Name: "Gandalf", UpdatedAt: updated, ImagePath: &img},
+		{ID: "e2", Name: "NPC template", UpdatedAt: updated, IsTemplate: true},
+		{ID: "e3", Name: "Rivendell", UpdatedAt: updated},
+	}
+	var role int
+	var user string
+	svc := newTestService(offlineRepo(&pages, &role, &user), &mockEntityTypeRepo{})
+
+	m, err := svc.OfflineManifest(context.Background(), "camp-1", 2, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role != 2 || user != "user-1" {
+		t.Errorf("listed as role %d user %q, want the viewer's identity", role, user)
+	}
+	if len(m.Entries) != 2 || m.Entries[0].ID != "e1" || m.Entries[1].ID != "e3" {
+		t.Fatalf("entries = %+v, want e1 and e3 (template skipped)", m.Entries)
+	}
+	first := m.Entries[0]
+	if first.URL != "/campaigns/camp-1/entities/e1" {
+		t.Errorf("url = %q", first.URL)
+	}
+	if first.Revision != "1772366400000" {
+		t.Errorf("revision = %q, want updated_at in ms", first.Revision)
+	}
+	if len(first.Assets) != 1 || first.Assets[0] != "/media/b7c17bb1-6563-462c-8b49-5b2e8bd57108" {
+		t.Errorf("assets = %v, want the header image", first.Assets)
+	}
+
+	// Same content, same version; any edit moves it.
+	again, _ := svc.OfflineManifest(context.Background(), "camp-1", 2, "user-1")
+	if again.Version != m.Version {
+		t.Errorf("version changed without edits: %q → %q", m.Version, again.Version)
+	}
+	pages[2].UpdatedAt = updated.Add(time.Minute)
+	edited, _ := svc.OfflineManifest(context.Background(), "camp-1", 2, "user-1")
+	if edited.Version == m.Version {
+		t.Error("version must change when a page is edited")
+	}
+}
+
+// stubSvcForOffline serves a fixed manifest.
+type stubSvcForOffline struct {
+	EntityService
+}
+
+func (s *stubSvcForOffline) OfflineManifest(_ context.Context, campaignID string, _ int, _ string) (*OfflineManifest, error) {
+	return &OfflineManifest{CampaignID: campaignID, Version: "v1", Entries: []OfflineEntry{}}, nil
+}
+
+func TestOfflineManifestAPI_ETag(t *testing.T) {
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		wantCode    int
+	}{
+		{name: "first fetch returns the manifest", wantCode: http.StatusOK},
+		{name: "matching ETag returns 304", ifNoneMatch: `"v1"`, wantCode: http.StatusNotModified},
+		{name: "stale ETag returns the manifest", ifNoneMatch: `"v0"`, wantCode: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &Handler{service: &stubSvcForOffline{}}
+			req := httptest.NewRequest(http.MethodGet, "/campaigns/c1/offline-manifest", nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.Set("campaign_context", &campaigns.CampaignContext{
+				Campaign:   &campaigns.Campaign{ID: "c1"},
+				MemberRole: campaigns.RolePlayer,
+			})
+
+			if err := h.OfflineManifestAPI(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tc.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantCode)
+			}
+			if got := rec.Header().Get("ETag"); got != `"v1"` {
+				t.Errorf("ETag = %q, want \"v1\"", got)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "private, no-cache" {
+				t.Errorf("Cache-Control = %q, want private, no-cache", got)
+			}
+		})
+	}
+}
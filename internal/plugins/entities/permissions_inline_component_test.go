@@ -38,7 +38,8 @@ import (
 // permissions slide-in card replaces it; the hidden `is_private` input
 // driven by the card is allowed (and necessary).
 func TestEntityCreateFormComponent_NoIsPrivateCheckbox(t *testing.T) {
-	cc := &campaigns.CampaignContext{Campaign: &campaigns.Campaign{ID: "camp-1", Name: "Test"}}
+	// Scribe+ get the widget; a Player's page is never private (type_access.go).
+	cc := &campaigns.CampaignContext{Campaign: &campaigns.Campaign{ID: "camp-1", Name: "Test"}, MemberRole: campaigns.RoleScribe}
 	entityTypes := []EntityType{{ID: 1, Name: "Character", Enabled: true}}
 	component := EntityCreateFormComponent(cc, entityTypes, 1, nil, nil, "csrf", "")
 
@@ -0,0 +1,228 @@
+// quick_switcher.go — ranking for the Ctrl+K quick switcher. One request
+// returns entity matches, recently updated pages, and navigation/creation
+// actions in a single ranked list. Ranking is pure and in-memory over the
+// cached name index (see Handler.QuickSwitchAPI), which is what keeps a
+// keystroke round-trip under ~50ms.
+package entities
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// quickSwitchLimit caps the results returned per query — a palette shows
+// one screenful.
+const quickSwitchLimit = 20
+
+// quickSwitchRecentBoost lifts recently updated pages above equally good
+// matches elsewhere, without letting a weak match outrank a prefix hit.
+const quickSwitchRecentBoost = 150
+
+// Result kinds.
+const (
+	QuickSwitchKindEntity = "entity"
+	QuickSwitchKindAction = "action"
+)
+
+// QuickSwitchResult is one row of the switcher response.
+type QuickSwitchResult struct {
+	Kind         string `json:"kind"`
+	ID           string `json:"id"`
+	Label        string `json:"label"`
+	URL          string `json:"url"`
+	Icon         string `json:"icon,omitempty"`
+	TypeName     string `json:"type_name,omitempty"`
+	MatchedAlias string `json:"matched_alias,omitempty"` // Set when an alias, not the name, matched.
+	Recent       bool   `json:"recent,omitempty"`
+	Score        int    `json:"score"`
+}
+
+// QuickAction is a navigation or creation command ("New NPC", "Go to
+// calendar"). Keywords are extra words it answers to.
+type QuickAction struct {
+	ID       string
+	Label    string
+	Keywords []string
+	URL      string
+	Icon     string
+}
+
+// QuickSwitchInput is everything RankQuickSwitch ranks over.
+type QuickSwitchInput struct {
+	Query      string
+	CampaignID string
+	Names      []EntityNameEntry // Visible names and aliases (the cached index).
+	Recent     []Entity          // Most recently updated first.
+	Actions    []QuickAction
+	Limit      int
+}
+
+// RankQuickSwitch scores entities and actions against the query. An empty
+// query returns the recent pages followed by the actions, unscored, so the
+// palette has something useful to show the moment it opens.
+func RankQuickSwitch(in QuickSwitchInput) []QuickSwitchResult {
+	limit := in.Limit
+	if limit <= 0 {
+		limit = quickSwitchLimit
+	}
+	query := strings.ToLower(strings.TrimSpace(in.Query))
+	recent := make(map[string]bool, len(in.Recent))
+	for _, e := range in.Recent {
+		recent[e.ID] = true
+	}
+
+	var out []QuickSwitchResult
+	if query == "" {
+		for _, e := range in.Recent {
+			out = append(out, QuickSwitchResult{
+				Kind: QuickSwitchKindEntity, ID: e.ID, Label: e.Name, URL: quickSwitchEntityURL(in.CampaignID, e.ID),
+				Icon: e.TypeIcon, TypeName: e.TypeName, Recent: true,
+			})
+		}
+		for _, a := range in.Actions {
+			out = append(out, actionResult(a, 0))
+		}
+		if len(out) > limit {
+			out = out[:limit]
+		}
+		return out
+	}
+
+	// Best match per entity: a page can match under its name and several
+	// aliases but appears once, labelled with its own name.
+	names := make(map[string]string, len(in.Names))
+	for _, n := range in.Names {
+		if !n.IsAlias {
+			names[n.ID] = n.Name
+		}
+	}
+	best := make(map[string]QuickSwitchResult)
+	for _, n := range in.Names {
+		score := fuzzyScore(query, n.Name)
+		if score <= 0 {
+			continue
+		}
+		r := QuickSwitchResult{
+			Kind: QuickSwitchKindEntity, ID: n.ID, Label: names[n.ID], URL: quickSwitchEntityURL(in.CampaignID, n.ID),
+			Icon: n.TypeIcon, TypeName: n.TypeName, Recent: recent[n.ID], Score: score,
+		}
+		if n.IsAlias {
+			r.MatchedAlias, r.Score = n.Name, score-50
+		}
+		if r.Label == "" {
+			r.Label = n.Name
+		}
+		if r.Recent {
+			r.Score += quickSwitchRecentBoost
+		}
+		if prev, ok := best[n.ID]; !ok || r.Score > prev.Score {
+			best[n.ID] = r
+		}
+	}
+	for _, r := range best {
+		out = append(out, r)
+	}
+
+	for _, a := range in.Actions {
+		score := fuzzyScore(query, a.Label)
+		for _, kw := range a.Keywords {
+			if s := fuzzyScore(query, kw); s > score {
+				score = s
+			}
+		}
+		if score > 0 {
+			out = append(out, actionResult(a, score))
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return strings.ToLower(out[i].Label) < strings.ToLower(out[j].Label)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// actionResult converts an action into a result row.
+func actionResult(a QuickAction, score int) QuickSwitchResult {
+	return QuickSwitchResult{Kind: QuickSwitchKindAction, ID: a.ID, Label: a.Label, URL: a.URL, Icon: a.Icon, Score: score}
+}
+
+// quickSwitchEntityURL is the canonical page URL a switcher row opens.
+func quickSwitchEntityURL(campaignID, entityID string) string {
+	return "/campaigns/" + campaignID + "/entities/" + entityID
+}
+
+// fuzzyScore rates how well a lowercase query matches a name: exact beats
+// prefix beats word-start beats substring beats an in-order subsequence
+// ("gdf" → "Gandalf"). Shorter names win ties within a tier. Zero means no
+// match.
+func fuzzyScore(query, name string) int {
+	if query == "" {
+		return 0
+	}
+	lower := strings.ToLower(name)
+	extra := len(lower) - len(query)
+	if extra > 100 {
+		extra = 100
+	}
+	switch {
+	case lower == query:
+		return 1000
+	case strings.HasPrefix(lower, query):
+		return 800 - extra
+	case hasWordPrefix(lower, query):
+		return 600 - extra
+	}
+	if i := strings.Index(lower, query); i >= 0 {
+		return 400 - min(i, 100)
+	}
+	if gaps, ok := subsequenceGaps(query, lower); ok {
+		return max(200-gaps*5-extra, 1)
+	}
+	return 0
+}
+
+// hasWordPrefix reports whether query starts any word of name after the
+// first ("wat" → "deep water").
+func hasWordPrefix(name, query string) bool {
+	prev := rune(-1)
+	for i, r := range name {
+		if prev != -1 && !isWordRune(prev) && isWordRune(r) && strings.HasPrefix(name[i:], query) {
+			return true
+		}
+		prev = r
+	}
+	return false
+}
+
+// isWordRune treats letters and digits as word characters.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// subsequenceGaps finds query's runes in order within name and counts the
+// runes skipped between matches (leading runes don't count).
+func subsequenceGaps(query, name string) (int, bool) {
+	q := []rune(query)
+	qi, gaps, started := 0, 0, false
+	for _, r := range name {
+		if qi == len(q) {
+			break
+		}
+		if r == q[qi] {
+			qi++
+			started = true
+			continue
+		}
+		if started {
+			gaps++
+		}
+	}
+	return gaps, qi == len(q)
+}
@@ -0,0 +1,133 @@
+package entities
+
+import "testing"
+
+func TestFuzzyScore_Tiers(t *testing.T) {
+	// Each case must outrank the next.
+	ordered := []struct{ query, name string }{
+		{"gandalf", "Gandalf"},
+		{"gan", "Gandalf"},
+		{"gan", "Gandalf the Grey"},
+		{"wat", "Deep Water"},
+		{"and", "Gandalf"},
+		{"gdf", "Gandalf"},
+	}
+	prev := 1 << 30
+	for _, tc := range ordered {
+		got := fuzzyScore(tc.query, tc.name)
+		if got <= 0 || got >= prev {
+			t.Errorf("fuzzyScore(%q, %q) = %d, want in (0, %d)", tc.query, tc.name, got, prev)
+		}
+		prev = got
+	}
+
+	for _, tc := range []struct{ query, name string }{
+		{"xyz", "Gandalf"},
+		{"fdg", "Gandalf"}, // out of order
+		{"", "Gandalf"},
+	} {
+		if got := fuzzyScore(tc.query, tc.name); got != 0 {
+			t.Errorf("fuzzyScore(%q, %q) = %d, want 0", tc.query, tc.name, got)
+		}
+	}
+}
+
+func TestRankQuickSwitch(t *testing.T) {
+	names := []EntityNameEntry{
+		{ID: "e1", Name: "Gandalf", TypeName: "Character"},
+		{ID: "e1", Name: "Mithrandir", IsAlias: true},
+		{ID: "e1", Name: "Grey Pilgrim", IsAlias: true},
+		{ID: "e2", Name: "Galadriel", TypeName: "Character"},
+		{ID: "e3", Name: "Bo", TypeName: "Character"},
+	}
+	actions := []QuickAction{
+		{ID: "new-npcs", Label: "New NPC", Keywords: []string{"create NPC"}, URL: "/new"},
+		{ID: "goto-calendar", Label: "Go to calendar", URL: "/calendar"},
+	}
+
+	cases := []struct {
+		name    string
+		query   string
+		recent  []Entity
+		limit   int
+		wantIDs []string
+		check   func(t *testing.T, got []QuickSwitchResult)
+	}{
+		{
+			name:    "empty query shows recents then actions",
+			recent:  []Entity{{ID: "e2", Name: "Galadriel"}},
+			wantIDs: []string{"e2", "new-npcs", "goto-calendar"},
+		},
+		{
+			name:    "alias match appears once under the entity name",
+			query:   "mith",
+			wantIDs: []string{"e1"},
+			check: func(t *testing.T, got []QuickSwitchResult) {
+				if got[0].Label != "Gandalf" || got[0].MatchedAlias != "Mithrandir" {
+					t.Errorf("got label %q alias %q, want Gandalf via Mithrandir", got[0].Label, got[0].MatchedAlias)
+				}
+			},
+		},
+		{
+			name:    "name beats alias hit for the same entity",
+			query:   "g",
+			wantIDs: []string{"e1", "e2", "goto-calendar"},
+			check: func(t *testing.T, got []QuickSwitchResult) {
+				for _, r := range got {
+					if r.ID == "e1" && r.MatchedAlias != "" {
+						t.Errorf("e1 matched by name, got alias %q", r.MatchedAlias)
+					}
+				}
+			},
+		},
+		{
+			name:    "recent page lifted over an equal match",
+			query:   "ga",
+			recent:  []Entity{{ID: "e2", Name: "Galadriel"}},
+			wantIDs: []string{"e2", "e1", "goto-calendar"},
+		},
+		{
+			name:    "short names are searchable",
+			query:   "bo",
+			wantIDs: []string{"e3"},
+		},
+		{
+			name:    "action keyword match",
+			query:   "create",
+			wantIDs: []string{"new-npcs"},
+		},
+		{
+			name:    "limit caps results",
+			query:   "a",
+			limit:   2,
+			wantIDs: nil,
+			check: func(t *testing.T, got []QuickSwitchResult) {
+				if len(got) != 2 {
+					t.Errorf("got %d results, want 2", len(got))
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RankQuickSwitch(QuickSwitchInput{
+				Query: tc.query, CampaignID: "camp-1", Names: names,
+				Recent: tc.recent, Actions: actions, Limit: tc.limit,
+			})
+			if tc.wantIDs != nil {
+				if len(got) != len(tc.wantIDs) {
+					t.Fatalf("got %d results %+v, want %v", len(got), got, tc.wantIDs)
+				}
+				for i, id := range tc.wantIDs {
+					if got[i].ID != id {
+						t.Errorf("result %d = %q, want %q", i, got[i].ID, id)
+					}
+				}
+			}
+			if tc.check != nil {
+				tc.check(t, got)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/database"
 	"github.com/keyxmakerx/chronicle/internal/permissions"
 )
 
@@ -38,6 +39,16 @@ type EntityTypeRepository interface {
 	// reconciler), never layout_json.
 	UpdateFieldsSchema(ctx context.Context, id int, fieldsJSON string) error
 	UpdateColor(ctx context.Context, id int, color string) error
+	// UpdateDefaultImage sets (or, with nil, clears) the image shown for
+	// pages of this type that have none of their own.
+	UpdateDefaultImage(ctx context.Context, id int, imagePath *string) error
+	UpdateAvatarStyle(ctx context.Context, id int, style string) error
+	// UpdatePlayerSubmissions sets whether players may submit pages of
+	// this type for approval.
+	UpdatePlayerSubmissions(ctx context.Context, id int, enabled bool) error
+	// UpdatePlayerAccess sets whether players may create and edit pages
+	// of this type.
+	UpdatePlayerAccess(ctx context.Context, id int, canCreate, canEdit bool) error
 	UpdateDashboard(ctx context.Context, id int, description *string, pinnedIDs []string) error
 	UpdateDashboardLayout(ctx context.Context, id int, layoutJSON *string) error
 	SlugExists(ctx context.Context, campaignID, slug string) (bool, error)
@@ -71,7 +82,7 @@ func NewEntityTypeRepository(db *sql.DB) EntityTypeRepository {
 // read MUST go through this pair; bare column names so it composes into any
 // FROM entity_types query without an alias.
 const entityTypeColumns = `id, campaign_id, slug, name, name_plural, icon, color,
-	preset_category, parent_type_id, claimable, description, pinned_entity_ids, dashboard_layout,
+	default_image, avatar_style, preset_category, parent_type_id, claimable, player_submissions, players_can_create, players_can_edit, description, pinned_entity_ids, dashboard_layout,
 	fields, layout_json, sort_order, is_default, enabled`
 
 // rowScanner is satisfied by both *sql.Row and *sql.Rows, so scanEntityType
@@ -89,8 +100,8 @@ func scanEntityType(s rowScanner) (*EntityType, error) {
 	var fieldsRaw, layoutRaw, pinnedRaw []byte
 	if err := s.Scan(
 		&et.ID, &et.CampaignID, &et.Slug, &et.Name, &et.NamePlural,
-		&et.Icon, &et.Color, &et.PresetCategory, &et.ParentTypeID, &et.Claimable,
-		&et.Description, &pinnedRaw, &et.DashboardLayout,
+		&et.Icon, &et.Color, &et.DefaultImage, &et.AvatarStyle, &et.PresetCategory, &et.ParentTypeID, &et.Claimable,
+		&et.PlayerSubmissions, &et.PlayersCanCreate, &et.PlayersCanEdit, &et.Description, &pinnedRaw, &et.DashboardLayout,
 		&fieldsRaw, &layoutRaw, &et.SortOrder,
 		&et.IsDefault, &et.Enabled,
 	); err != nil {
@@ -349,6 +360,59 @@ func (r *entityTypeRepository) HealDoubledPluralS(ctx context.Context) (int, err
 	return int(n), nil
 }
 
+// UpdateDefaultImage updates only the type's default page image.
+func (r *entityTypeRepository) UpdateDefaultImage(ctx context.Context, id int, imagePath *string) error {
+	return r.updateColumn(ctx, id, "default_image", imagePath)
+}
+
+// UpdateAvatarStyle updates only how image-less pages of the type are drawn.
+func (r *entityTypeRepository) UpdateAvatarStyle(ctx context.Context, id int, style string) error {
+	return r.updateColumn(ctx, id, "avatar_style", style)
+}
+
+// UpdatePlayerSubmissions updates only whether players may submit pages of
+// the type.
+func (r *entityTypeRepository) UpdatePlayerSubmissions(ctx context.Context, id int, enabled bool) error {
+	return r.updateColumn(ctx, id, "player_submissions", enabled)
+}
+
+// UpdatePlayerAccess updates only whether players may create and edit
+// pages of the type.
+func (r *entityTypeRepository) UpdatePlayerAccess(ctx context.Context, id int, canCreate, canEdit bool) error {
+	if err := r.updateColumn(ctx, id, "players_can_create", canCreate); err != nil {
+		return err
+	}
+	return r.updateColumn(ctx, id, "players_can_edit", canEdit)
+}
+
+// updateColumn sets one entity_types column. column is always a constant
+// from the caller, never user input.
+func (r *entityTypeRepository) updateColumn(ctx context.Context, id int, column string, value any) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE entity_types SET `+column+` = ? WHERE id = ?`,
+		value, id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating entity type %s: %w", column, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		// MariaDB reports 0 affected rows when the value is unchanged,
+		// so only a missing row is an error.
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM entity_types WHERE id = ?)`, id).Scan(&exists); err != nil {
+			return fmt.Errorf("checking entity type: %w", err)
+		}
+		if !exists {
+			return apperror.NewNotFound("entity type not found")
+		}
+	}
+	return nil
+}
+
 // UpdateColor updates only the color for an entity type. Used by the
 // entity type settings widget to change the display color.
 func (r *entityTypeRepository) UpdateColor(ctx context.Context, id int, color string) error {
@@ -608,6 +672,9 @@ var defaultEntityTypes = []EntityType{
 			{Key: "currency", Label: "Currency", Type: "text", Section: "Basics"},
 			{Key: "price_modifier", Label: "Price Modifier (%)", Type: "number", Section: "Basics"},
 		}},
+	// Journal pages carry the shared party journal (widgets/party_journal).
+	{Slug: "journal", Name: "Journal", NamePlural: "Journals", Icon: "fa-book", Color: "#64748b", SortOrder: 8, IsDefault: true, Enabled: true,
+		Fields: []FieldDefinition{}},
 }
 
 // SeedFromTypes inserts a specific set of entity types for a campaign.
@@ -696,11 +763,26 @@ type EntityRepository interface {
 	UpdatePlayerNotes(ctx context.Context, id, notesJSON, notesHTML string) error
 	UpdateFields(ctx context.Context, id string, fieldsData map[string]any, searchText string) error
 	UpdateFieldOverrides(ctx context.Context, id string, overrides *FieldOverrides) error
-	UpdateImage(ctx context.Context, id, imagePath string) error
+	UpdateImage(ctx context.Context, id, imagePath string, text ImageTextInput) error
+	UpdateImageText(ctx context.Context, id string, text ImageTextInput) error
 	UpdateCoverImage(ctx context.Context, id, coverImagePath string) error
 	Delete(ctx context.Context, id string) error
 	SlugExists(ctx context.Context, campaignID, slug string) (bool, error)
 
+	// FindSlugOwner returns the ID of the entity holding a slug in the
+	// campaign — live first, then retired (entity_slug_history) — or ""
+	// when the slug is free.
+	FindSlugOwner(ctx context.Context, campaignID, slug string) (string, error)
+
+	// RecordSlugChange files oldSlug as a redirect to the entity and drops
+	// any history row for newSlug (the entity reclaiming an old slug). Run
+	// after the entity row itself carries newSlug.
+	RecordSlugChange(ctx context.Context, campaignID, entityID, oldSlug, newSlug string) error
+
+	// UpsertBatch inserts creates and rewrites updates in one transaction;
+	// nothing is written unless every row is.
+	UpsertBatch(ctx context.Context, creates, updates []*Entity) error
+
 	// ListByCampaign returns entities filtered by campaign, optional types, and visibility.
 	// typeIDs is matched via IN clause; nil or empty means no type filter. This supports
 	// the sub-category-as-template model where a parent entity_type's listing aggregates
@@ -775,20 +857,33 @@ type EntityRepository interface {
 
 	// UpdatePopupConfig persists the entity's hover preview configuration.
 	UpdatePopupConfig(ctx context.Context, entityID string, config *PopupConfig) error
+	// UpdateAppearance persists the entity's look overrides; nil clears them.
+	UpdateAppearance(ctx context.Context, entityID string, appearance *EntityAppearance) error
+	// UpdateStatus sets an entity's lifecycle state. See status.go.
+	UpdateStatus(ctx context.Context, entityID string, status EntityStatus) error
+	// CountByStatus counts a campaign's entities in one lifecycle state.
+	// No visibility filter; callers gate on role.
+	CountByStatus(ctx context.Context, campaignID string, status EntityStatus) (int, error)
 
 	// CopyEntityTags copies all entity_tags associations from one entity to another.
 	CopyEntityTags(ctx context.Context, sourceEntityID, targetEntityID string) error
 
 	// ListNames returns lightweight name entries for all visible entities in a
-	// campaign, including aliases. Used for auto-linking in the editor. Sorted
-	// by name length DESC so longer names match first (prevents partial matches).
-	ListNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error)
+	// campaign, including aliases, skipping names shorter than minLen. Used for
+	// auto-linking in the editor and the quick switcher. Sorted by name length
+	// DESC so longer names match first (prevents partial matches).
+	ListNames(ctx context.Context, campaignID string, role int, userID string, minLen int) ([]EntityNameEntry, error)
 
 	// FilterViewableEntityIDs returns the subset of entityIDs (scoped to
 	// campaignID) that a viewer with the given role + userID may view, applying
 	// the canonical visibility policy. Batched — one query, no N+1.
 	FilterViewableEntityIDs(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) (map[string]bool, error)
 
+	// FindViewableByIDs loads the entities among entityIDs (scoped to
+	// campaignID) that the viewer may view, with joined type info. One query;
+	// IDs that are missing, foreign or hidden are simply absent.
+	FindViewableByIDs(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) ([]Entity, error)
+
 	// ListAliases returns all aliases for a given entity.
 	ListAliases(ctx context.Context, entityID string) ([]EntityAlias, error)
 
@@ -801,6 +896,11 @@ type EntityRepository interface {
 	// source→target pair. Used by the graph visualization to show mention edges.
 	FindAllMentionLinks(ctx context.Context, campaignID string, role int, userID string) ([]MentionLink, error)
 
+	// FindMentioningEntities returns every entity in the campaign whose entry
+	// mentions entityID and the viewer may see. Unlike FindBacklinks it is not
+	// capped, because the mention-rename job must reach every page.
+	FindMentioningEntities(ctx context.Context, campaignID, entityID string, role int, userID string) ([]Entity, error)
+
 	// UpdatePrivate sets an entity's is_private flag. Used by the NPC reveal toggle.
 	UpdatePrivate(ctx context.Context, entityID string, isPrivate bool) error
 
@@ -810,16 +910,40 @@ type EntityRepository interface {
 
 // entityRepository implements EntityRepository with MariaDB queries.
 type entityRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	reads *database.DBRouter
+}
+
+// NewEntityRepository creates a new entity repository. reads, when non-nil,
+// routes the listing and search queries to read replicas; pass nil to keep
+// every query on db.
+func NewEntityRepository(db *sql.DB, reads *database.DBRouter) EntityRepository {
+	return &entityRepository{db: db, reads: reads}
 }
 
-// NewEntityRepository creates a new entity repository.
-func NewEntityRepository(db *sql.DB) EntityRepository {
-	return &entityRepository{db: db}
+// reader returns the pool for a listing or search query. Only reads whose
+// result is displayed use it: anything loaded to be modified and saved
+// (FindByID, FindBySlug, slug checks) stays on r.db so replica lag can't
+// lose an update.
+func (r *entityRepository) reader(ctx context.Context) *sql.DB {
+	if r.reads == nil {
+		return r.db
+	}
+	return r.reads.Reader(ctx)
 }
 
 // Create inserts a new entity row.
 func (r *entityRepository) Create(ctx context.Context, entity *Entity) error {
+	return insertEntity(ctx, r.db, entity)
+}
+
+// entityExecer runs a write on the pool or inside a transaction.
+type entityExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertEntity inserts an entity row through ex.
+func insertEntity(ctx context.Context, ex entityExecer, entity *Entity) error {
 	fieldsJSON, err := json.Marshal(entity.FieldsData)
 	if err != nil {
 		return fmt.Errorf("marshaling fields data: %w", err)
@@ -834,16 +958,16 @@ func (r *entityRepository) Create(ctx context.Context, entity *Entity) error {
 
 	query := `INSERT INTO entities (id, campaign_id, entity_type_id, name, slug, entry, entry_html, search_text,
 	          player_notes, player_notes_html,
-	          image_path, parent_id, parent_node_id, sort_order, type_label, is_private, is_template, fields_data,
+	          image_path, image_alt, image_caption, parent_id, parent_node_id, sort_order, type_label, is_private, is_template, status, fields_data,
 	          created_by, owner_user_id, map_id, created_at, updated_at)
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = ex.ExecContext(ctx, query,
 		entity.ID, entity.CampaignID, entity.EntityTypeID,
 		entity.Name, entity.Slug, entity.Entry, entity.EntryHTML, searchText,
 		entity.PlayerNotes, entity.PlayerNotesHTML,
-		entity.ImagePath, entity.ParentID, entity.ParentNodeID, entity.SortOrder, entity.TypeLabel,
-		entity.IsPrivate, entity.IsTemplate, fieldsJSON,
+		entity.ImagePath, entity.ImageAlt, entity.ImageCaption, entity.ParentID, entity.ParentNodeID, entity.SortOrder, entity.TypeLabel,
+		entity.IsPrivate, entity.IsTemplate, entity.Status, fieldsJSON,
 		entity.CreatedBy, entity.OwnerUserID, entity.MapID, entity.CreatedAt, entity.UpdatedAt,
 	)
 	if err != nil {
@@ -853,12 +977,12 @@ func (r *entityRepository) Create(ctx context.Context, entity *Entity) error {
 }
 
 // entitySelectColumns is the standard column list for entity queries with joined type info.
-const entitySelectColumns = `e.id, e.campaign_id, e.entity_type_id, e.name, e.slug,
+const entitySelectColumns = `e.id, e.campaign_id, e.entity_type_id, e.name, e.slug, e.slug_custom,
 	                 e.entry, e.entry_html, e.player_notes, e.player_notes_html,
-	                 e.image_path, e.cover_image_path, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
-	                 e.is_private, e.visibility, e.is_template, e.fields_data, e.field_overrides, e.popup_config,
+	                 e.image_path, e.cover_image_path, e.image_alt, e.image_caption, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
+	                 e.is_private, e.visibility, e.is_template, e.status, e.fields_data, e.field_overrides, e.popup_config, e.appearance,
 	                 e.created_by, e.owner_user_id, e.map_id, e.created_at, e.updated_at,
-	                 et.name, et.name_plural, et.icon, et.color, et.slug`
+	                 et.name, et.name_plural, et.icon, et.color, et.slug, et.default_image, et.avatar_style`
 
 // FindByID retrieves an entity with joined type info.
 func (r *entityRepository) FindByID(ctx context.Context, id string) (*Entity, error) {
@@ -884,14 +1008,14 @@ func (r *entityRepository) FindBySlug(ctx context.Context, campaignID, slug stri
 // The column order must match entitySelectColumns.
 func (r *entityRepository) scanEntity(row *sql.Row) (*Entity, error) {
 	e := &Entity{}
-	var fieldsRaw, overridesRaw, popupRaw []byte
+	var fieldsRaw, overridesRaw, popupRaw, appearanceRaw []byte
 	err := row.Scan(
-		&e.ID, &e.CampaignID, &e.EntityTypeID, &e.Name, &e.Slug,
+		&e.ID, &e.CampaignID, &e.EntityTypeID, &e.Name, &e.Slug, &e.SlugCustom,
 		&e.Entry, &e.EntryHTML, &e.PlayerNotes, &e.PlayerNotesHTML,
-		&e.ImagePath, &e.CoverImagePath, &e.ParentID, &e.ParentNodeID, &e.SortOrder, &e.TypeLabel,
-		&e.IsPrivate, &e.Visibility, &e.IsTemplate, &fieldsRaw, &overridesRaw, &popupRaw,
+		&e.ImagePath, &e.CoverImagePath, &e.ImageAlt, &e.ImageCaption, &e.ParentID, &e.ParentNodeID, &e.SortOrder, &e.TypeLabel,
+		&e.IsPrivate, &e.Visibility, &e.IsTemplate, &e.Status, &fieldsRaw, &overridesRaw, &popupRaw, &appearanceRaw,
 		&e.CreatedBy, &e.OwnerUserID, &e.MapID, &e.CreatedAt, &e.UpdatedAt,
-		&e.TypeName, &e.TypeNamePlural, &e.TypeIcon, &e.TypeColor, &e.TypeSlug,
+		&e.TypeName, &e.TypeNamePlural, &e.TypeIcon, &e.TypeColor, &e.TypeSlug, &e.TypeDefaultImage, &e.TypeAvatarStyle,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, apperror.NewNotFound("entity not found")
@@ -918,23 +1042,34 @@ func (r *entityRepository) scanEntity(row *sql.Row) (*Entity, error) {
 			return nil, fmt.Errorf("unmarshaling popup config: %w", err)
 		}
 	}
+	if len(appearanceRaw) > 0 {
+		e.Appearance = &EntityAppearance{}
+		if err := json.Unmarshal(appearanceRaw, e.Appearance); err != nil {
+			return nil, fmt.Errorf("unmarshaling appearance: %w", err)
+		}
+	}
 	return e, nil
 }
 
 // Update modifies an existing entity including parent_id.
 func (r *entityRepository) Update(ctx context.Context, entity *Entity) error {
+	return updateEntity(ctx, r.db, entity)
+}
+
+// updateEntity writes an entity's editable columns through ex.
+func updateEntity(ctx context.Context, ex entityExecer, entity *Entity) error {
 	fieldsJSON, err := json.Marshal(entity.FieldsData)
 	if err != nil {
 		return fmt.Errorf("marshaling fields data: %w", err)
 	}
 
-	query := `UPDATE entities SET name = ?, slug = ?, entry = ?, entry_html = ?,
+	query := `UPDATE entities SET name = ?, slug = ?, slug_custom = ?, entry = ?, entry_html = ?,
 	          player_notes = ?, player_notes_html = ?,
 	          type_label = ?, parent_id = ?, sort_order = ?, is_private = ?, fields_data = ?, updated_at = ?
 	          WHERE id = ?`
 
-	result, err := r.db.ExecContext(ctx, query,
-		entity.Name, entity.Slug, entity.Entry, entity.EntryHTML,
+	result, err := ex.ExecContext(ctx, query,
+		entity.Name, entity.Slug, entity.SlugCustom, entity.Entry, entity.EntryHTML,
 		entity.PlayerNotes, entity.PlayerNotesHTML,
 		entity.TypeLabel, entity.ParentID, entity.SortOrder, entity.IsPrivate, fieldsJSON, entity.UpdatedAt,
 		entity.ID,
@@ -1046,16 +1181,18 @@ func (r *entityRepository) UpdateFieldOverrides(ctx context.Context, id string,
 	return nil
 }
 
-// UpdateImage updates only the image_path for an entity. Used by the image
-// upload API to set or clear an entity's header image.
-func (r *entityRepository) UpdateImage(ctx context.Context, id, imagePath string) error {
+// UpdateImage updates the image_path for an entity together with its alt
+// text and caption, which describe the old image and so are replaced in
+// the same statement. Used by the image upload API to set or clear an
+// entity's header image.
+func (r *entityRepository) UpdateImage(ctx context.Context, id, imagePath string, text ImageTextInput) error {
 	var imgVal any
 	if imagePath != "" {
 		imgVal = imagePath
 	}
 
-	query := `UPDATE entities SET image_path = ?, updated_at = NOW() WHERE id = ?`
-	result, err := r.db.ExecContext(ctx, query, imgVal, id)
+	query := `UPDATE entities SET image_path = ?, image_alt = ?, image_caption = ?, updated_at = NOW() WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, imgVal, nullString(text.AltText), nullString(text.Caption), id)
 	if err != nil {
 		return fmt.Errorf("updating entity image: %w", err)
 	}
@@ -1070,6 +1207,29 @@ func (r *entityRepository) UpdateImage(ctx context.Context, id, imagePath string
 	return nil
 }
 
+// UpdateImageText updates the header image's alt text and caption.
+func (r *entityRepository) UpdateImageText(ctx context.Context, id string, text ImageTextInput) error {
+	query := `UPDATE entities SET image_alt = ?, image_caption = ?, updated_at = NOW() WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, nullString(text.AltText), nullString(text.Caption), id)
+	if err != nil {
+		return fmt.Errorf("updating entity image text: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return apperror.NewNotFound("entity not found")
+	}
+	return nil
+}
+
+// nullString stores "" as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
 // UpdateCoverImage updates only the cover_image_path for an entity.
 // Used by the cover image upload API.
 func (r *entityRepository) UpdateCoverImage(ctx context.Context, id, coverImagePath string) error {
@@ -1113,10 +1273,13 @@ func (r *entityRepository) Delete(ctx context.Context, id string) error {
 
 // SlugExists returns true if an entity with the given slug exists in the campaign.
 func (r *entityRepository) SlugExists(ctx context.Context, campaignID, slug string) (bool, error) {
+	// A retired slug counts as taken: handing it to a new entity would
+	// silently re-point the old entity's links.
 	var exists bool
 	err := r.db.QueryRowContext(ctx,
-		`SELECT EXISTS(SELECT 1 FROM entities WHERE campaign_id = ? AND slug = ?)`,
-		campaignID, slug,
+		`SELECT EXISTS(SELECT 1 FROM entities WHERE campaign_id = ? AND slug = ?)
+		     OR EXISTS(SELECT 1 FROM entity_slug_history WHERE campaign_id = ? AND slug = ?)`,
+		campaignID, slug, campaignID, slug,
 	).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("checking slug existence: %w", err)
@@ -1124,6 +1287,51 @@ func (r *entityRepository) SlugExists(ctx context.Context, campaignID, slug stri
 	return exists, nil
 }
 
+// FindSlugOwner returns the entity holding the slug live or, failing that,
+// as a retired slug. "" means no entity has ever held it.
+func (r *entityRepository) FindSlugOwner(ctx context.Context, campaignID, slug string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM (
+		     SELECT id, 0 AS pri FROM entities WHERE campaign_id = ? AND slug = ?
+		     UNION ALL
+		     SELECT entity_id, 1 FROM entity_slug_history WHERE campaign_id = ? AND slug = ?
+		 ) owners ORDER BY pri LIMIT 1`,
+		campaignID, slug, campaignID, slug,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("finding slug owner: %w", err)
+	}
+	return id, nil
+}
+
+// RecordSlugChange files the entity's old slug in entity_slug_history and
+// clears the new slug from it, in one transaction. Re-retiring a slug the
+// entity held before just refreshes its row.
+func (r *entityRepository) RecordSlugChange(ctx context.Context, campaignID, entityID, oldSlug, newSlug string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning slug history tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO entity_slug_history (campaign_id, slug, entity_id) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE entity_id = VALUES(entity_id), created_at = CURRENT_TIMESTAMP`,
+		campaignID, oldSlug, entityID); err != nil {
+		return fmt.Errorf("recording retired slug: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM entity_slug_history WHERE campaign_id = ? AND slug = ?`,
+		campaignID, newSlug); err != nil {
+		return fmt.Errorf("clearing reclaimed slug: %w", err)
+	}
+	return tx.Commit()
+}
+
 // tagFilterClause returns a WHERE clause fragment and args that filter
 // entities to only those having ALL the specified tags (AND logic).
 // Uses a subquery with HAVING COUNT to ensure all tags match.
@@ -1161,6 +1369,10 @@ func tagFilterClause(tagSlugs []string) (string, []any) {
 //     becomes visible if any tag it bears carries a tag_permissions grant whose
 //     subject matches the viewer (role/user/group/public). This branch can only
 //     WIDEN visibility — it never hides anything — so it sits as a top-level OR.
+//   - drafts and pending submissions: a draft is hidden from everyone but
+//     its creator; a page pending review is also shown to Scribe+ (the
+//     approvers). This gate is ANDed outside the branches above, so no
+//     grant can reveal someone else's draft or unapproved submission.
 //
 // Role-tier matching uses subject_id <= role, so a grant to RolePlayer (1) is
 // visible to Player and above but NOT to an anonymous/public viewer (role 0).
@@ -1209,8 +1421,9 @@ func visibilityFilter(role int, userID string) (string, []any) {
 				))
 			)
 		)
-	)`
-	return filter, []any{role, role, userID, userID, role, userID, userID}
+	)
+	AND (e.status NOT IN ('draft', 'pending') OR e.created_by = ? OR (e.status = 'pending' AND ? >= 2))`
+	return filter, []any{role, role, userID, userID, role, userID, userID, userID, userID, role}
 }
 
 // FilterViewableEntityIDs returns the subset of entityIDs (scoped to campaignID)
@@ -1264,6 +1477,49 @@ func (r *entityRepository) FilterViewableEntityIDs(ctx context.Context, campaign
 	return viewable, rows.Err()
 }
 
+// FindViewableByIDs loads the entities among entityIDs (scoped to campaignID)
+// that the viewer may view, with joined type info, in one query. Used by the
+// batch tooltip preview so a page full of mentions costs one round trip
+// instead of one per hover. Same visibilityFilter as FilterViewableEntityIDs.
+func (r *entityRepository) FindViewableByIDs(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) ([]Entity, error) {
+	if len(entityIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(entityIDs))
+	args := make([]any, 0, len(entityIDs)+8)
+	args = append(args, campaignID)
+	for i, id := range entityIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := `SELECT ` + entitySelectColumns + `
+	          FROM entities e
+	          INNER JOIN entity_types et ON et.id = e.entity_type_id
+	          WHERE e.campaign_id = ? AND e.id IN (` + strings.Join(placeholders, ", ") + `)`
+
+	visFilter, visArgs := visibilityFilter(role, userID)
+	query += visFilter
+	args = append(args, visArgs...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("finding entities by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var entities []Entity
+	for rows.Next() {
+		e, err := r.scanEntityRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, *e)
+	}
+	return entities, rows.Err()
+}
+
 // entityTypeInClause builds " AND e.entity_type_id [= ? | IN (?, ...)]" plus
 // the corresponding args, or returns empty strings when the filter is disabled
 // (nil/empty input). Single-ID callers get "=" so existing query plans stay
@@ -1288,6 +1544,8 @@ func entityTypeInClause(typeIDs []int) (string, []any) {
 // Visibility filtering considers both legacy is_private and custom permissions.
 // typeIDs is an OR'd set (IN clause); nil or empty disables the type filter.
 func (r *entityRepository) ListByCampaign(ctx context.Context, campaignID string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error) {
+	// One pool for both queries so the total matches the page.
+	db := r.reader(ctx)
 	where := "WHERE e.campaign_id = ?"
 	args := []any{campaignID}
 
@@ -1303,6 +1561,10 @@ func (r *entityRepository) ListByCampaign(ctx context.Context, campaignID string
 		args = append(args, tagArgs...)
 	}
 
+	statusFilter, statusArgs := statusFilterClause(opts.Status)
+	where += statusFilter
+	args = append(args, statusArgs...)
+
 	visFilter, visArgs := visibilityFilter(role, userID)
 	where += visFilter
 	args = append(args, visArgs...)
@@ -1310,7 +1572,7 @@ func (r *entityRepository) ListByCampaign(ctx context.Context, campaignID string
 	// Count total for pagination.
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM entities e %s", where)
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("counting entities: %w", err)
 	}
 
@@ -1323,7 +1585,7 @@ func (r *entityRepository) ListByCampaign(ctx context.Context, campaignID string
 	          LIMIT ? OFFSET ?`, where, opts.OrderByClause())
 
 	pageArgs := append(args, opts.PerPage, opts.Offset())
-	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
+	rows, err := db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("listing entities: %w", err)
 	}
@@ -1354,7 +1616,7 @@ func (r *entityRepository) ListByOwner(ctx context.Context, campaignID, ownerUse
 	          INNER JOIN entity_types et ON et.id = e.entity_type_id
 	          WHERE e.campaign_id = ? AND e.owner_user_id = ?
 	          ORDER BY e.updated_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, campaignID, ownerUserID)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, campaignID, ownerUserID)
 	if err != nil {
 		return nil, fmt.Errorf("listing owned entities: %w", err)
 	}
@@ -1387,7 +1649,7 @@ func (r *entityRepository) ListClaimed(ctx context.Context, campaignID string, r
 	          INNER JOIN entity_types et ON et.id = e.entity_type_id
 	          ` + where + `
 	          ORDER BY e.updated_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing claimed entities: %w", err)
 	}
@@ -1436,6 +1698,8 @@ func (r *entityRepository) UpdateMapID(ctx context.Context, entityID string, map
 // (FULLTEXT on search_text), and type label (LIKE).
 // typeIDs semantics match ListByCampaign — IN clause, nil/empty disables.
 func (r *entityRepository) Search(ctx context.Context, campaignID, query string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error) {
+	// One pool for both queries so the total matches the page.
+	db := r.reader(ctx)
 	where := "WHERE e.campaign_id = ?"
 	args := []any{campaignID}
 
@@ -1476,6 +1740,10 @@ func (r *entityRepository) Search(ctx context.Context, campaignID, query string,
 		args = append(args, tagArgs...)
 	}
 
+	statusFilter, statusArgs := statusFilterClause(opts.Status)
+	where += statusFilter
+	args = append(args, statusArgs...)
+
 	visFilter, visArgs := visibilityFilter(role, userID)
 	where += visFilter
 	args = append(args, visArgs...)
@@ -1483,7 +1751,7 @@ func (r *entityRepository) Search(ctx context.Context, campaignID, query string,
 	// Count total.
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM entities e %s", where)
 	var total int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("counting search results: %w", err)
 	}
 
@@ -1496,7 +1764,7 @@ func (r *entityRepository) Search(ctx context.Context, campaignID, query string,
 	          LIMIT ? OFFSET ?`, where)
 
 	pageArgs := append(args, opts.PerPage, opts.Offset())
-	rows, err := r.db.QueryContext(ctx, selectQuery, pageArgs...)
+	rows, err := db.QueryContext(ctx, selectQuery, pageArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("searching entities: %w", err)
 	}
@@ -1525,7 +1793,7 @@ func (r *entityRepository) CountByType(ctx context.Context, campaignID string, r
 	args = append(args, visArgs...)
 	query += " GROUP BY e.entity_type_id"
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("counting entities by type: %w", err)
 	}
@@ -1560,7 +1828,7 @@ func (r *entityRepository) ListRecent(ctx context.Context, campaignID string, ro
 	          LIMIT ?`, where)
 
 	args = append(args, limit)
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing recent entities: %w", err)
 	}
@@ -1593,7 +1861,7 @@ func (r *entityRepository) FindChildren(ctx context.Context, parentID string, ro
 	          %s
 	          ORDER BY e.sort_order ASC, e.name ASC`, where)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("finding children: %w", err)
 	}
@@ -1615,31 +1883,31 @@ func (r *entityRepository) FindChildren(ctx context.Context, parentID string, ro
 // with a depth limit of 20 to prevent infinite loops from data corruption.
 func (r *entityRepository) FindAncestors(ctx context.Context, entityID string) ([]Entity, error) {
 	query := `WITH RECURSIVE ancestors AS (
-	    SELECT e.id, e.campaign_id, e.entity_type_id, e.name, e.slug,
+	    SELECT e.id, e.campaign_id, e.entity_type_id, e.name, e.slug, e.slug_custom,
 	           e.entry, e.entry_html, e.player_notes, e.player_notes_html,
-	           e.image_path, e.cover_image_path, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
-	           e.is_private, e.visibility, e.is_template, e.fields_data, e.field_overrides, e.popup_config,
+	           e.image_path, e.cover_image_path, e.image_alt, e.image_caption, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
+	           e.is_private, e.visibility, e.is_template, e.status, e.fields_data, e.field_overrides, e.popup_config, e.appearance,
 	           e.created_by, e.owner_user_id, e.map_id, e.created_at, e.updated_at,
 	           1 AS depth
 	    FROM entities e
 	    WHERE e.id = (SELECT parent_id FROM entities WHERE id = ?)
 	    UNION ALL
-	    SELECT e.id, e.campaign_id, e.entity_type_id, e.name, e.slug,
+	    SELECT e.id, e.campaign_id, e.entity_type_id, e.name, e.slug, e.slug_custom,
 	           e.entry, e.entry_html, e.player_notes, e.player_notes_html,
-	           e.image_path, e.cover_image_path, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
-	           e.is_private, e.visibility, e.is_template, e.fields_data, e.field_overrides, e.popup_config,
+	           e.image_path, e.cover_image_path, e.image_alt, e.image_caption, e.parent_id, e.parent_node_id, e.sort_order, e.type_label,
+	           e.is_private, e.visibility, e.is_template, e.status, e.fields_data, e.field_overrides, e.popup_config, e.appearance,
 	           e.created_by, e.owner_user_id, e.map_id, e.created_at, e.updated_at,
 	           a.depth + 1
 	    FROM entities e
 	    INNER JOIN ancestors a ON e.id = a.parent_id
 	    WHERE a.depth < 20
 	)
-	SELECT a.id, a.campaign_id, a.entity_type_id, a.name, a.slug,
+	SELECT a.id, a.campaign_id, a.entity_type_id, a.name, a.slug, a.slug_custom,
 	       a.entry, a.entry_html, a.player_notes, a.player_notes_html,
-	       a.image_path, a.cover_image_path, a.parent_id, a.parent_node_id, a.sort_order, a.type_label,
-	       a.is_private, a.visibility, a.is_template, a.fields_data, a.field_overrides, a.popup_config,
+	       a.image_path, a.cover_image_path, a.image_alt, a.image_caption, a.parent_id, a.parent_node_id, a.sort_order, a.type_label,
+	       a.is_private, a.visibility, a.is_template, a.status, a.fields_data, a.field_overrides, a.popup_config, a.appearance,
 	       a.created_by, a.owner_user_id, a.map_id, a.created_at, a.updated_at,
-	       et.name, et.name_plural, et.icon, et.color, et.slug
+	       et.name, et.name_plural, et.icon, et.color, et.slug, et.default_image, et.avatar_style
 	FROM ancestors a
 	INNER JOIN entity_types et ON et.id = a.entity_type_id
 	ORDER BY a.depth ASC`
@@ -1811,7 +2079,7 @@ func (r *entityRepository) FindBacklinks(ctx context.Context, entityID string, r
 	          ORDER BY e.name
 	          LIMIT 50`, where)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("finding backlinks: %w", err)
 	}
@@ -1855,6 +2123,65 @@ func (r *entityRepository) UpdatePopupConfig(ctx context.Context, entityID strin
 	return nil
 }
 
+// UpdateAppearance persists the entity's look overrides as JSON.
+func (r *entityRepository) UpdateAppearance(ctx context.Context, entityID string, appearance *EntityAppearance) error {
+	var appearanceJSON []byte
+	var err error
+	if appearance != nil {
+		appearanceJSON, err = json.Marshal(appearance)
+		if err != nil {
+			return fmt.Errorf("marshaling appearance: %w", err)
+		}
+	}
+
+	query := `UPDATE entities SET appearance = ?, updated_at = NOW() WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, appearanceJSON, entityID)
+	if err != nil {
+		return fmt.Errorf("updating appearance: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return apperror.NewNotFound("entity not found")
+	}
+	return nil
+}
+
+// UpdateStatus sets an entity's lifecycle state.
+func (r *entityRepository) UpdateStatus(ctx context.Context, entityID string, status EntityStatus) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE entities SET status = ?, updated_at = NOW() WHERE id = ?`,
+		status, entityID)
+	if err != nil {
+		return fmt.Errorf("updating entity status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rows == 0 {
+		return apperror.NewNotFound("entity not found")
+	}
+	return nil
+}
+
+// CountByStatus counts a campaign's entities in one lifecycle state. Backed
+// by idx_entities_campaign_status.
+func (r *entityRepository) CountByStatus(ctx context.Context, campaignID string, status EntityStatus) (int, error) {
+	var n int
+	err := r.reader(ctx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM entities WHERE campaign_id = ? AND status = ?`,
+		campaignID, status).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("counting entities by status: %w", err)
+	}
+	return n, nil
+}
+
 // CopyEntityTags duplicates all entity_tags rows from one entity to another
 // using a single INSERT...SELECT statement.
 func (r *entityRepository) CopyEntityTags(ctx context.Context, sourceEntityID, targetEntityID string) error {
@@ -1871,14 +2198,14 @@ func (r *entityRepository) CopyEntityTags(ctx context.Context, sourceEntityID, t
 // The column order must match entitySelectColumns.
 func (r *entityRepository) scanEntityRow(rows *sql.Rows) (*Entity, error) {
 	e := &Entity{}
-	var fieldsRaw, overridesRaw, popupRaw []byte
+	var fieldsRaw, overridesRaw, popupRaw, appearanceRaw []byte
 	err := rows.Scan(
-		&e.ID, &e.CampaignID, &e.EntityTypeID, &e.Name, &e.Slug,
+		&e.ID, &e.CampaignID, &e.EntityTypeID, &e.Name, &e.Slug, &e.SlugCustom,
 		&e.Entry, &e.EntryHTML, &e.PlayerNotes, &e.PlayerNotesHTML,
-		&e.ImagePath, &e.CoverImagePath, &e.ParentID, &e.ParentNodeID, &e.SortOrder, &e.TypeLabel,
-		&e.IsPrivate, &e.Visibility, &e.IsTemplate, &fieldsRaw, &overridesRaw, &popupRaw,
+		&e.ImagePath, &e.CoverImagePath, &e.ImageAlt, &e.ImageCaption, &e.ParentID, &e.ParentNodeID, &e.SortOrder, &e.TypeLabel,
+		&e.IsPrivate, &e.Visibility, &e.IsTemplate, &e.Status, &fieldsRaw, &overridesRaw, &popupRaw, &appearanceRaw,
 		&e.CreatedBy, &e.OwnerUserID, &e.MapID, &e.CreatedAt, &e.UpdatedAt,
-		&e.TypeName, &e.TypeNamePlural, &e.TypeIcon, &e.TypeColor, &e.TypeSlug,
+		&e.TypeName, &e.TypeNamePlural, &e.TypeIcon, &e.TypeColor, &e.TypeSlug, &e.TypeDefaultImage, &e.TypeAvatarStyle,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scanning entity row: %w", err)
@@ -1902,6 +2229,12 @@ func (r *entityRepository) scanEntityRow(rows *sql.Rows) (*Entity, error) {
 			return nil, fmt.Errorf("unmarshaling popup config: %w", err)
 		}
 	}
+	if len(appearanceRaw) > 0 {
+		e.Appearance = &EntityAppearance{}
+		if err := json.Unmarshal(appearanceRaw, e.Appearance); err != nil {
+			return nil, fmt.Errorf("unmarshaling appearance: %w", err)
+		}
+	}
 	return e, nil
 }
 
@@ -2068,20 +2401,20 @@ func stripFTOperators(query string) string {
 }
 
 // ListNames returns lightweight name entries for auto-linking, including entity
-// aliases as separate rows. Only returns names >= 3 chars. Sorted by name
+// aliases as separate rows. Only returns names >= minLen chars. Sorted by name
 // length DESC so longer names match first (prevents "King" matching before
 // "King Arthur"). Alias entries have IsAlias=true.
-func (r *entityRepository) ListNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error) {
-	where := "WHERE e.campaign_id = ? AND CHAR_LENGTH(e.name) >= 3"
-	args := []any{campaignID}
+func (r *entityRepository) ListNames(ctx context.Context, campaignID string, role int, userID string, minLen int) ([]EntityNameEntry, error) {
+	where := "WHERE e.campaign_id = ? AND CHAR_LENGTH(e.name) >= ?"
+	args := []any{campaignID, minLen}
 
 	visFilter, visArgs := visibilityFilter(role, userID)
 	where += visFilter
 	args = append(args, visArgs...)
 
 	// UNION entity names with aliases. Aliases get is_alias=1.
-	aliasWhere := "WHERE e.campaign_id = ? AND CHAR_LENGTH(ea.alias) >= 3"
-	aliasArgs := []any{campaignID}
+	aliasWhere := "WHERE e.campaign_id = ? AND CHAR_LENGTH(ea.alias) >= ?"
+	aliasArgs := []any{campaignID, minLen}
 	aliasArgs = append(aliasArgs, visArgs...)
 
 	query := fmt.Sprintf(`
@@ -2169,6 +2502,40 @@ func (r *entityRepository) SetAliases(ctx context.Context, entityID string, alia
 	return tx.Commit()
 }
 
+// FindMentioningEntities returns all visible entities in a campaign whose
+// entry_html mentions entityID, with full entry content for rewriting.
+func (r *entityRepository) FindMentioningEntities(ctx context.Context, campaignID, entityID string, role int, userID string) ([]Entity, error) {
+	escaped := strings.NewReplacer("%", `\%`, "_", `\_`).Replace(entityID)
+	where := `WHERE e.campaign_id = ? AND e.entry_html LIKE ? AND e.id != ?`
+	args := []any{campaignID, `%data-mention-id="` + escaped + `"%`, entityID}
+
+	visFilter, visArgs := visibilityFilter(role, userID)
+	where += visFilter
+	args = append(args, visArgs...)
+
+	query := `SELECT ` + entitySelectColumns + `
+	          FROM entities e
+	          INNER JOIN entity_types et ON et.id = e.entity_type_id
+	          ` + where + `
+	          ORDER BY e.name`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("finding mentioning entities: %w", err)
+	}
+	defer rows.Close()
+
+	var entities []Entity
+	for rows.Next() {
+		e, err := r.scanEntityRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, *e)
+	}
+	return entities, rows.Err()
+}
+
 // mentionIDPattern matches data-mention-id="<uuid>" attributes in entry_html.
 var mentionIDPattern = regexp.MustCompile(`data-mention-id="([a-f0-9-]+)"`)
 
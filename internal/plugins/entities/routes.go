@@ -21,7 +21,7 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 
 	// Entry API (JSON endpoints for editor widget).
 	cg.GET("/entities/:eid/entry", h.GetEntry, campaigns.RequireRole(campaigns.RolePlayer))
-	cg.PUT("/entities/:eid/entry", h.UpdateEntryAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid/entry", h.UpdateEntryAPI, h.requireTypeEdit())
 
 	// Player notes API (player-facing content, synced as a separate Foundry page).
 	cg.GET("/entities/:eid/player-notes", h.GetPlayerNotes, campaigns.RequireRole(campaigns.RolePlayer))
@@ -29,7 +29,7 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 
 	// Fields API (JSON endpoints for attributes widget).
 	cg.GET("/entities/:eid/fields", h.GetFieldsAPI, campaigns.RequireRole(campaigns.RolePlayer))
-	cg.PUT("/entities/:eid/fields", h.UpdateFieldsAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid/fields", h.UpdateFieldsAPI, h.requireTypeEdit())
 	cg.PUT("/entities/:eid/field-overrides", h.UpdateFieldOverridesAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.DELETE("/entities/:eid/field-overrides", h.ResetFieldOverridesAPI, campaigns.RequireRole(campaigns.RoleScribe))
 
@@ -39,37 +39,58 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	cg.GET("/entities/members", h.GetMembersAPI, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Image API.
-	cg.PUT("/entities/:eid/image", h.UpdateImageAPI, campaigns.RequireRole(campaigns.RoleScribe))
-	cg.PUT("/entities/:eid/cover-image", h.UpdateCoverImageAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid/image", h.UpdateImageAPI, h.requireTypeEdit())
+	cg.PUT("/entities/:eid/image-text", h.UpdateImageTextAPI, h.requireTypeEdit())
+	cg.PUT("/entities/:eid/cover-image", h.UpdateCoverImageAPI, h.requireTypeEdit())
 
-	// Inline metadata API (Scribe+): name, descriptor, parent, privacy.
-	cg.PUT("/entities/:eid/metadata", h.UpdateMetadataAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	// Inline metadata API: name, descriptor, parent.
+	cg.PUT("/entities/:eid/metadata", h.UpdateMetadataAPI, h.requireTypeEdit())
+
+	// Custom slug API (Owner only — a slug change moves the page's URL).
+	cg.PUT("/entities/:eid/slug", h.UpdateSlugAPI, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Popup preview config API (Scribe+).
 	cg.PUT("/entities/:eid/popup-config", h.UpdatePopupConfigAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid/appearance", h.UpdateAppearanceAPI, campaigns.RequireRole(campaigns.RoleScribe))
+
+	// Quick switcher (Ctrl+K) API (Player+).
+	cg.GET("/quick-switch", h.QuickSwitchAPI, campaigns.RequireRole(campaigns.RolePlayer))
 
 	// Auto-linking API (Scribe+, used by editor widget).
 	cg.GET("/entity-names", h.EntityNamesAPI, campaigns.RequireRole(campaigns.RoleScribe))
 
-	// Entity aliases API (Scribe+ for write, Player+ for read).
+	// Entity aliases API (editors write, Player+ read).
 	cg.GET("/entities/:eid/aliases", h.GetAliasesAPI, campaigns.RequireRole(campaigns.RolePlayer))
-	cg.PUT("/entities/:eid/aliases", h.SetAliasesAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid/aliases", h.SetAliasesAPI, h.requireTypeEdit())
 
-	// Scribe routes (create/edit).
-	cg.GET("/entities/new", h.NewForm, campaigns.RequireRole(campaigns.RoleScribe))
-	cg.POST("/entities", h.Create, campaigns.RequireRole(campaigns.RoleScribe))
+	// Create/edit routes. The page-content routes use requireTypeEdit and
+	// create checks the type, so Players reach them for types the owner
+	// opened to them (type_access.go); the rest are Scribe+.
+	cg.GET("/entities/new", h.NewForm, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/entities", h.Create, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.POST("/entities/quick-create", h.QuickCreateAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.GET("/entities/similar-names", h.SimilarNamesFragment, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.GET("/entities/types", h.EntityTypesAPI, campaigns.RequireRole(campaigns.RolePlayer))
-	cg.GET("/entities/:eid/edit", h.EditForm, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.GET("/entities/:eid/edit", h.EditForm, h.requireTypeEdit())
 	cg.POST("/entities/:eid/clone", h.Clone, campaigns.RequireRole(campaigns.RoleScribe))
-	cg.PUT("/entities/:eid", h.Update, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/entities/:eid", h.Update, h.requireTypeEdit())
 	cg.PUT("/entities/:eid/reorder", h.ReorderAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.POST("/entities/bulk-move", h.BulkMoveAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.POST("/entities/bulk-type", h.BulkChangeTypeAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.POST("/entities/bulk-tags", h.BulkTagsAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.POST("/entities/bulk-visibility", h.BulkVisibilityAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/bulk-status", h.BulkStatusAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/:eid/status", h.UpdateStatusAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.POST("/entities/bulk-delete", h.BulkDeleteAPI, campaigns.RequireRole(campaigns.RoleOwner))
 
+	// Player submissions: Players submit pages to types open to them;
+	// Scribe+ review the pending pages.
+	cg.GET("/entities/submit", h.SubmitForm, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/entities/submit", h.Submit, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.GET("/entities/review", h.ReviewQueue, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/:eid/approve", h.ApproveSubmissionAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/:eid/reject", h.RejectSubmissionAPI, campaigns.RequireRole(campaigns.RoleScribe))
+
 	// Saved filter presets (per-user tag filter combos, Player+).
 	cg.GET("/saved-filters", h.ListSavedFiltersAPI, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.POST("/saved-filters", h.CreateSavedFilterAPI, campaigns.RequireRole(campaigns.RolePlayer))
@@ -80,6 +101,37 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	cg.GET("/favorites", h.ListFavoritesAPI, campaigns.RequireRole(campaigns.RolePlayer))
 	cg.GET("/favorite-ids", h.FavoriteIDsAPI, campaigns.RequireRole(campaigns.RolePlayer))
 
+	// Watches (change notifications for a page or its type, Player+).
+	cg.GET("/entities/:eid/watch", h.WatchControl, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/entities/:eid/watch", h.UpdateWatchAPI, campaigns.RequireRole(campaigns.RolePlayer))
+
+	// Faction standings (Player+ to read, Scribe+ to edit, Owner for the scale).
+	cg.GET("/standings", h.StandingsPage, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.GET("/standings/history", h.StandingHistory, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.GET("/standings/dashboard-block", h.StandingsDashboardBlock, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.PUT("/standings", h.SetStandingAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.DELETE("/standings", h.StopTrackingAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.PUT("/standings/scale", h.UpdateStandingScaleAPI, campaigns.RequireRole(campaigns.RoleOwner))
+
+	// Date fields the calendar no longer has (Scribe+).
+	cg.GET("/entities/date-issues", h.DateIssuesPage, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/date-issues/recheck", h.RecheckDateIssues, campaigns.RequireRole(campaigns.RoleScribe))
+
+	// Bulk image assignment from a ZIP matched by file name (Scribe+).
+	cg.GET("/entities/bulk-images", h.BulkImagesPage, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/bulk-images/preview", h.BulkImagesPreview, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/bulk-images/apply", h.BulkImagesApply, campaigns.RequireRole(campaigns.RoleScribe))
+
+	// Compendium import: Open5e search or 5eTools JSON to new pages (Scribe+).
+	cg.GET("/entities/compendium", h.CompendiumPage, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/compendium/preview", h.CompendiumPreview, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/compendium/apply", h.CompendiumApply, campaigns.RequireRole(campaigns.RoleScribe))
+
+	// Statblock import: pasted 5e / PF2e text to attribute fields (Scribe+).
+	cg.GET("/entities/:eid/statblock", h.StatblockPage, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/:eid/statblock/preview", h.StatblockPreview, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/entities/:eid/statblock/apply", h.StatblockApply, campaigns.RequireRole(campaigns.RoleScribe))
+
 	// Player Character Experience (CH2 + CH3).
 	// /me — per-campaign player landing page listing the caller's characters.
 	cg.GET("/me", h.MyCharacters, campaigns.RequireRole(campaigns.RolePlayer))
@@ -135,13 +187,22 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	// Entity type layout/color/dashboard API (Owner only).
 	cg.GET("/entity-types/:etid/layout", h.GetEntityTypeLayout, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/entity-types/:etid/layout", h.UpdateEntityTypeLayout, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.GET("/entity-types/:etid/layout/versions", h.ListEntityTypeLayoutVersions, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/entity-types/:etid/layout/versions/:vid/restore", h.RestoreEntityTypeLayoutVersion, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/entity-types/:etid/color", h.UpdateEntityTypeColor, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/entity-types/:etid/default-image", h.UpdateEntityTypeDefaultImage, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/entity-types/:etid/avatar-style", h.UpdateEntityTypeAvatarStyle, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/entity-types/:etid/submissions", h.UpdateEntityTypeSubmissions, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.PUT("/entity-types/:etid/player-access", h.UpdateEntityTypePlayerAccess, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/entity-types/:etid/dashboard", h.UpdateEntityTypeDashboard, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Category dashboard layout API (Owner only).
 	cg.GET("/entity-types/:etid/dashboard-layout", h.GetCategoryDashboardLayout, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/entity-types/:etid/dashboard-layout", h.UpdateCategoryDashboardLayout, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.DELETE("/entity-types/:etid/dashboard-layout", h.ResetCategoryDashboardLayout, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.GET("/entity-types/:etid/dashboard-layout/versions", h.ListCategoryDashboardLayoutVersions, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/entity-types/:etid/dashboard-layout/versions/:vid/restore", h.RestoreCategoryDashboardLayoutVersion, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.GET("/entity-types/:etid/dashboard-layout/export", h.ExportCategoryDashboardLayout, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Public-capable view routes: use AllowPublicCampaignAccess so that
 	// public campaigns can be browsed without logging in.
@@ -153,19 +214,41 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	pub.GET("/entities/search", h.SearchAPI, campaigns.RequireViewAccess())
 	pub.GET("/search", h.SearchPageHandler, campaigns.RequireViewAccess())
 	pub.GET("/entities/:eid", h.Show, campaigns.RequireViewAccess())
+	pub.GET("/sitemap.xml", h.SitemapXML, campaigns.RequireViewAccess())
+	pub.GET("/offline-manifest", h.OfflineManifestAPI, campaigns.RequireViewAccess())
 	pub.GET("/entities/:eid/preview", h.PreviewAPI, campaigns.RequireViewAccess())
+	pub.POST("/entities/previews", h.BatchPreviewAPI, campaigns.RequireViewAccess())
 	pub.GET("/entities/:eid/backlinks", h.BacklinksFragment, campaigns.RequireViewAccess())
+	pub.GET("/entities/:eid/linked", h.LinkedContentPanel, campaigns.RequireViewAccess())
+	pub.GET("/entities/:eid/linked/:provider", h.LinkedContentFragment, campaigns.RequireViewAccess())
+	// Printable summary cards: one page, or every pinned page of a type.
+	pub.GET("/entities/:eid/card", h.EntityCard, campaigns.RequireViewAccess())
+	pub.GET("/entity-types/:etid/cards", h.EntityTypeCards, campaigns.RequireViewAccess())
 
 	// Widget data endpoints (read-only) — needed so public campaign visitors
 	// can load editor content, attribute fields, etc. Handlers already enforce
 	// entity-level privacy checks (private entities require Scribe+).
 	pub.GET("/entities/:eid/entry", h.GetEntry, campaigns.RequireViewAccess())
+	// Plain-text entry for screen readers and TTS prep (secrets stripped).
+	pub.GET("/entities/:eid/entry.txt", h.GetEntryText, campaigns.RequireViewAccess())
 	pub.GET("/entities/:eid/fields", h.GetFieldsAPI, campaigns.RequireViewAccess())
+	// Numeric field history for the attributes widget's trend chart.
+	pub.GET("/entities/:eid/fields/:key/history", h.FieldHistoryAPI, campaigns.RequireViewAccess())
 	// Aliases display data (cordinator#39 finding 3) — the aliases widget mounts
 	// for every viewer, so its read must be public-capable like entry/fields.
 	// GetAliasesAPI now enforces the same IDOR + entity-privacy gate.
 	pub.GET("/entities/:eid/aliases", h.GetAliasesAPI, campaigns.RequireViewAccess())
 
+	// Human-readable entity URLs: /campaigns/<campaign-slug>/e/<entity-slug>.
+	// ResolveCampaignSlug runs first so the access middleware sees the
+	// campaign ID; a UUID in :id still works.
+	slugged := e.Group("/campaigns/:id/e",
+		auth.OptionalAuth(authSvc),
+		campaigns.ResolveCampaignSlug(campaignSvc),
+		campaigns.AllowPublicCampaignAccess(campaignSvc),
+	)
+	slugged.GET("/:eslug", h.ShowBySlug, campaigns.RequireViewAccess())
+
 	// Dynamic category route: resolves any entity type slug to a category
 	// dashboard. Echo's router gives static segments (entities, settings, etc.)
 	// priority over this parameter route, so it only catches actual type slugs.
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 	"github.com/keyxmakerx/chronicle/internal/permissions"
@@ -26,12 +27,15 @@ type EntityService interface {
 	Clone(ctx context.Context, campaignID, userID, sourceEntityID string) (*Entity, error)
 	GetByID(ctx context.Context, id string) (*Entity, error)
 	GetBySlug(ctx context.Context, campaignID, slug string) (*Entity, error)
+	ResolveSlug(ctx context.Context, campaignID, slug string) (*Entity, error)
+	SetSlug(ctx context.Context, entityID, slug string) (*Entity, error)
 	Update(ctx context.Context, entityID string, input UpdateEntityInput) (*Entity, error)
 	UpdateEntry(ctx context.Context, entityID, entryJSON, entryHTML string) error
 	UpdatePlayerNotes(ctx context.Context, entityID, notesJSON, notesHTML string) error
 	UpdateFields(ctx context.Context, entityID string, fieldsData map[string]any) error
 	UpdateFieldOverrides(ctx context.Context, entityID string, overrides *FieldOverrides) error
-	UpdateImage(ctx context.Context, entityID, imagePath string) error
+	UpdateImage(ctx context.Context, entityID, imagePath string, text ImageTextInput, requireAlt bool) error
+	UpdateImageText(ctx context.Context, entityID string, text ImageTextInput, requireAlt bool) error
 	UpdateCoverImage(ctx context.Context, entityID, coverImagePath string) error
 	Delete(ctx context.Context, entityID string) error
 
@@ -48,10 +52,24 @@ type EntityService interface {
 
 	// Popup preview config
 	UpdatePopupConfig(ctx context.Context, entityID string, config *PopupConfig) error
+	UpdateAppearance(ctx context.Context, entityID string, appearance *EntityAppearance) error
+
+	// Lifecycle states (see status.go).
+	SetStatus(ctx context.Context, campaignID, entityID string, status EntityStatus) (*Entity, error)
+	CountDrafts(ctx context.Context, campaignID string) (int, error)
+
+	// Player submissions (see submissions.go).
+	SubmissionTypes(ctx context.Context, campaignID string) ([]EntityType, error)
+	Submit(ctx context.Context, campaignID, userID string, input SubmitEntityInput) (*Entity, error)
+	ReviewSubmission(ctx context.Context, campaignID, entityID string, approve bool) (*Entity, error)
 
 	// Listing and search
 	List(ctx context.Context, campaignID string, typeID int, role int, userID string, opts ListOptions) ([]Entity, int, error)
 	ListRecent(ctx context.Context, campaignID string, role int, userID string, limit int) ([]Entity, error)
+
+	// OfflineManifest lists the pages (with revisions and asset URLs) the
+	// viewer can cache for offline reading. See offline.go.
+	OfflineManifest(ctx context.Context, campaignID string, role int, userID string) (*OfflineManifest, error)
 	Search(ctx context.Context, campaignID, query string, typeID int, role int, userID string, opts ListOptions) ([]Entity, int, error)
 
 	// Entity types
@@ -69,6 +87,9 @@ type EntityService interface {
 	// campaignID) that a viewer with the given role + userID may view. Batched
 	// building block for widgets that must hide private-entity nodes/targets.
 	FilterViewableEntityIDs(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) (map[string]bool, error)
+	// GetPreviews builds tooltip previews for the entities among entityIDs
+	// the viewer may see, from one entity query. Hidden IDs are omitted.
+	GetPreviews(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) ([]EntityPreview, error)
 	CreateEntityType(ctx context.Context, campaignID string, input CreateEntityTypeInput) (*EntityType, error)
 	// EnsurePlayerCharacterType idempotently premakes the campaign's claimable
 	// "Player Character" type (with the dynamic character-surface layout). Called
@@ -94,6 +115,10 @@ type EntityService interface {
 	DeleteEntityType(ctx context.Context, id int) error
 	UpdateEntityTypeLayout(ctx context.Context, id int, layout EntityTypeLayout) error
 	UpdateEntityTypeColor(ctx context.Context, id int, color string) error
+	UpdateEntityTypeDefaultImage(ctx context.Context, id int, imagePath string) error
+	UpdateEntityTypeAvatarStyle(ctx context.Context, id int, style string) error
+	UpdateEntityTypePlayerSubmissions(ctx context.Context, id int, enabled bool) error
+	UpdateEntityTypePlayerAccess(ctx context.Context, id int, canCreate, canEdit bool) error
 	UpdateEntityTypeDashboard(ctx context.Context, id int, description *string, pinnedIDs []string) error
 
 	// Category dashboard layout
@@ -103,6 +128,8 @@ type EntityService interface {
 
 	// Auto-linking — returns lightweight name entries for all visible entities.
 	ListEntityNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error)
+	// Quick switcher — the same entries without the auto-link length floor.
+	ListSwitcherNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error)
 
 	// Entity aliases — alternative names for auto-linking, search, and mentions.
 	GetAliases(ctx context.Context, entityID string) ([]EntityAlias, error)
@@ -114,6 +141,10 @@ type EntityService interface {
 	// GetMentionLinks returns all @mention references across a campaign for the
 	// relations graph. Each link is a source→target pair extracted from entry_html.
 	GetMentionLinks(ctx context.Context, campaignID string, role int, userID string) ([]MentionLink, error)
+	// PropagateRename rewrites mentions of the entity that still show its old
+	// name to show the new one, across the pages the renamer can see.
+	// See mention_rename.go.
+	PropagateRename(ctx context.Context, campaignID, entityID, oldName, newName string, role int, userID string) (*MentionRenameSummary, error)
 
 	// TogglePrivate flips an entity's is_private flag and returns the new state.
 	// Callers MUST have already verified the entity belongs to the acting
@@ -166,6 +197,10 @@ type EntityService interface {
 	// addons service; when unset the gate fails open (used by tests).
 	SetAddonChecker(checker AddonChecker)
 
+	// SetReservedPathChecker wires the route registry so new entity type
+	// slugs avoid segments plugin pages already serve.
+	SetReservedPathChecker(checker ReservedPathChecker)
+
 	// HealAutoPluralizedTypes corrects entity_types rows whose
 	// name_plural was double-s'd by the legacy auto-pluralize default.
 	// Returns the count of healed rows. Idempotent; safe to call on
@@ -207,14 +242,23 @@ type EntityService interface {
 	// target type belongs to the campaign and each entity is campaign-scoped.
 	BulkUpdateType(ctx context.Context, campaignID string, entityIDs []string, typeID int) (int, error)
 
+	// UpsertBatch creates and updates pages all-or-nothing and returns one
+	// result per item. Items failing validation carry Err and nothing is
+	// written; the error return is for storage failures.
+	UpsertBatch(ctx context.Context, campaignID, userID string, items []UpsertEntityInput) ([]UpsertEntityResult, error)
+
 	// Seeder (satisfies campaigns.EntityTypeSeeder interface).
 	SeedDefaults(ctx context.Context, campaignID string) error
 	SeedGenre(ctx context.Context, campaignID string, genre string) error
 
 	// Wiring.
 	SetEventPublisher(pub EntityEventPublisher)
+	SetHierarchyListener(l HierarchyListener)
 	SetBlockRegistry(reg *BlockRegistry)
 	SetSidebarAutoAdder(adder SidebarAutoAdder)
+	SetFieldHistoryRecorder(r FieldHistoryRecorder)
+	SetDateSyncer(d EntityDateSyncer)
+	SetGameDateValidator(v GameDateValidator)
 }
 
 // EntityEventPublisher emits domain events when entities or entity types change.
@@ -230,6 +274,19 @@ type NoopEntityEventPublisher struct{}
 func (NoopEntityEventPublisher) PublishEntityEvent(string, string, string, *Entity) {}
 func (NoopEntityEventPublisher) PublishEntityTypeEvent(string, string, *EntityType) {}
 
+// HierarchyListener is told when a campaign's page tree may have changed
+// shape for the public: a page added, removed, renamed, re-slugged, moved,
+// re-typed, or re-permissioned. Implemented by the sitemap service, which
+// rebuilds off the request path. Content-only edits don't notify.
+type HierarchyListener interface {
+	HierarchyChanged(campaignID string)
+}
+
+// noopHierarchyListener is the default when no sitemap is wired.
+type noopHierarchyListener struct{}
+
+func (noopHierarchyListener) HierarchyChanged(string) {}
+
 // SidebarAutoAdder auto-adds new entity types to the campaign's sidebar config.
 // Implemented by a campaigns-backed adapter in routes.go. Prevents the
 // "I created a sub-type and it doesn't appear" problem.
@@ -271,10 +328,15 @@ type entityService struct {
 	types         EntityTypeRepository
 	permissions   EntityPermissionRepository
 	events        EntityEventPublisher
+	hierarchy     HierarchyListener
+	fieldHistory  FieldHistoryRecorder
+	dateSyncer    EntityDateSyncer
+	dateValidator GameDateValidator
 	sidebarAdder  SidebarAutoAdder
 	blockRegistry *BlockRegistry
 	mapVerifier   MapCampaignVerifier
 	addonChecker  AddonChecker
+	reservedPaths ReservedPathChecker
 }
 
 // NewEntityService creates a new entity service with the given dependencies.
@@ -284,6 +346,9 @@ func NewEntityService(entities EntityRepository, types EntityTypeRepository, per
 		types:        types,
 		permissions:  permissions,
 		events:       NoopEntityEventPublisher{},
+		hierarchy:    noopHierarchyListener{},
+		fieldHistory: noopFieldHistoryRecorder{},
+		dateSyncer:   noopEntityDateSyncer{},
 		sidebarAdder: NoopSidebarAutoAdder{},
 		mapVerifier:  noopMapVerifier{},
 	}
@@ -306,6 +371,27 @@ func (s *entityService) SetAddonChecker(checker AddonChecker) {
 	s.addonChecker = checker
 }
 
+// ReservedPathChecker reports whether /campaigns/:id/<segment> belongs to a
+// plugin route. Echo serves those static segments ahead of the
+// /campaigns/:id/:typeSlug category route, so a type with that slug would
+// have an unreachable category page. Production wires the app's
+// routereg.Registry.
+type ReservedPathChecker interface {
+	IsReserved(segment string) bool
+}
+
+// SetReservedPathChecker wires the reserved route segment check. When
+// unset (tests), only duplicate slugs in the campaign are avoided.
+func (s *entityService) SetReservedPathChecker(checker ReservedPathChecker) {
+	s.reservedPaths = checker
+}
+
+// isReservedTypeSlug reports whether a type slug would collide with a
+// plugin route.
+func (s *entityService) isReservedTypeSlug(slug string) bool {
+	return s.reservedPaths != nil && s.reservedPaths.IsReserved(slug)
+}
+
 // isAddonEnabled reports whether an addon is enabled for the campaign.
 // Fails open (returns true) when the checker is not wired or the lookup
 // errors — matching the fail-open convention used by the handler's
@@ -324,6 +410,40 @@ func (s *entityService) SetEventPublisher(pub EntityEventPublisher) {
 	s.events = pub
 }
 
+// SetHierarchyListener wires the page-tree change hook (the sitemap).
+func (s *entityService) SetHierarchyListener(l HierarchyListener) {
+	if l == nil {
+		s.hierarchy = noopHierarchyListener{}
+		return
+	}
+	s.hierarchy = l
+}
+
+// SetFieldHistoryRecorder wires numeric field history (field_history.go).
+func (s *entityService) SetFieldHistoryRecorder(r FieldHistoryRecorder) {
+	if r == nil {
+		s.fieldHistory = noopFieldHistoryRecorder{}
+		return
+	}
+	s.fieldHistory = r
+}
+
+// SetDateSyncer wires anniversary date fields to the calendar
+// (date_fields.go).
+func (s *entityService) SetDateSyncer(d EntityDateSyncer) {
+	if d == nil {
+		s.dateSyncer = noopEntityDateSyncer{}
+		return
+	}
+	s.dateSyncer = d
+}
+
+// SetGameDateValidator wires the calendar check for date fields
+// (date_validation.go). Unset, only malformed dates are refused.
+func (s *entityService) SetGameDateValidator(v GameDateValidator) {
+	s.dateValidator = v
+}
+
 // SetBlockRegistry sets the block registry for layout validation.
 // Called after all plugins have registered their block types.
 func (s *entityService) SetBlockRegistry(reg *BlockRegistry) {
@@ -347,6 +467,13 @@ func (s *entityService) Create(ctx context.Context, campaignID, userID string, i
 	if len(name) > 200 {
 		return nil, apperror.NewBadRequest("entity name must be at most 200 characters")
 	}
+	status := input.Status
+	if status == "" {
+		status = StatusPublished
+	}
+	if !status.IsValid() {
+		return nil, apperror.NewBadRequest("invalid page status")
+	}
 
 	// Verify the entity type exists and belongs to this campaign.
 	et, err := s.types.FindByID(ctx, input.EntityTypeID)
@@ -387,6 +514,9 @@ func (s *entityService) Create(ctx context.Context, campaignID, userID string, i
 	if fieldsData == nil {
 		fieldsData = make(map[string]any)
 	}
+	if err := s.validateDateFields(ctx, campaignID, et.Fields, fieldsData, nil); err != nil {
+		return nil, err
+	}
 
 	// Owner trim: empty-string and whitespace-only values are treated as
 	// unclaimed. Cross-campaign membership validation lives at the call
@@ -411,11 +541,19 @@ func (s *entityService) Create(ctx context.Context, campaignID, userID string, i
 		TypeLabel:    typeLabelPtr,
 		IsPrivate:    input.IsPrivate,
 		IsTemplate:   false,
+		Status:       status,
 		FieldsData:   fieldsData,
 		CreatedBy:    userID,
 		OwnerUserID:  ownerUserIDPtr,
 		CreatedAt:    now,
 		UpdatedAt:    now,
+
+		// Display fields a read would join in; inline-create callers
+		// render the new entity straight from the return value.
+		TypeName:  et.Name,
+		TypeIcon:  et.Icon,
+		TypeColor: et.Color,
+		TypeSlug:  et.Slug,
 	}
 
 	if err := s.entities.Create(ctx, entity); err != nil {
@@ -429,7 +567,9 @@ func (s *entityService) Create(ctx context.Context, campaignID, userID string, i
 		slog.String("name", name),
 	)
 
+	s.syncEntityDates(ctx, entity)
 	s.events.PublishEntityEvent("created", campaignID, entity.ID, entity)
+	s.hierarchy.HierarchyChanged(campaignID)
 	return entity, nil
 }
 
@@ -461,6 +601,13 @@ func (s *entityService) Clone(ctx context.Context, campaignID, userID, sourceEnt
 		fieldsData = make(map[string]any)
 	}
 
+	// A copy of a draft stays a draft, and a copy of an unapproved
+	// submission starts as one; any other copy starts out published.
+	cloneStatus := StatusPublished
+	if source.Status == StatusDraft || source.Status == StatusPending {
+		cloneStatus = StatusDraft
+	}
+
 	clone := &Entity{
 		ID:             generateUUID(),
 		CampaignID:     campaignID,
@@ -470,10 +617,13 @@ func (s *entityService) Clone(ctx context.Context, campaignID, userID, sourceEnt
 		Entry:          source.Entry,
 		EntryHTML:      source.EntryHTML,
 		ImagePath:      source.ImagePath,
+		ImageAlt:       source.ImageAlt,
+		ImageCaption:   source.ImageCaption,
 		ParentID:       source.ParentID,
 		TypeLabel:      source.TypeLabel,
 		IsPrivate:      source.IsPrivate,
 		IsTemplate:     source.IsTemplate,
+		Status:         cloneStatus,
 		FieldsData:     fieldsData,
 		FieldOverrides: source.FieldOverrides,
 		PopupConfig:    source.PopupConfig,
@@ -498,6 +648,8 @@ func (s *entityService) Clone(ctx context.Context, campaignID, userID, sourceEnt
 		slog.String("name", cloneName),
 	)
 
+	s.syncEntityDates(ctx, clone)
+	s.hierarchy.HierarchyChanged(campaignID)
 	return clone, nil
 }
 
@@ -511,6 +663,110 @@ func (s *entityService) GetBySlug(ctx context.Context, campaignID, slug string)
 	return s.entities.FindBySlug(ctx, campaignID, slug)
 }
 
+// ResolveSlug finds the entity a slug URL refers to: the entity holding it
+// now, else the one that held it last. Lets old links and mentions keep
+// resolving after a rename or a custom slug.
+func (s *entityService) ResolveSlug(ctx context.Context, campaignID, slug string) (*Entity, error) {
+	id, err := s.entities.FindSlugOwner(ctx, campaignID, slug)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if id == "" {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	return s.entities.FindByID(ctx, id)
+}
+
+// reservedEntitySlugs are the static segments under /campaigns/:id/entities/
+// that outrank the :eid route, so an entity slugged "new" could never be
+// reached by its slug URL.
+var reservedEntitySlugs = map[string]bool{
+	"new": true, "search": true, "types": true, "members": true,
+}
+
+// SetSlug gives an entity a custom slug chosen by the Owner. Unlike the
+// name-derived slug there is no silent -2 suffix: a slug held by another
+// entity, now or as one of its retired slugs, is a validation error. An
+// empty slug returns the entity to its name-derived slug. Either way the
+// previous slug becomes a redirect.
+func (s *entityService) SetSlug(ctx context.Context, entityID, slug string) (*Entity, error) {
+	entity, err := s.entities.FindByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	slug = strings.TrimSpace(slug)
+	custom := slug != ""
+	if custom {
+		if err := validateCustomSlug(slug); err != nil {
+			return nil, err
+		}
+		owner, err := s.entities.FindSlugOwner(ctx, entity.CampaignID, slug)
+		if err != nil {
+			return nil, apperror.NewInternal(err)
+		}
+		if owner != "" && owner != entity.ID {
+			return nil, apperror.NewValidation(fmt.Sprintf("slug %q is already used by another page in this campaign", slug))
+		}
+	} else if slug, err = s.slugForName(ctx, entity, entity.Name); err != nil {
+		return nil, err
+	}
+	if slug == entity.Slug && custom == entity.SlugCustom {
+		return entity, nil
+	}
+
+	oldSlug := entity.Slug
+	entity.Slug, entity.SlugCustom = slug, custom
+	entity.UpdatedAt = time.Now().UTC()
+	if err := s.entities.Update(ctx, entity); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("updating entity slug: %w", err))
+	}
+	if slug != oldSlug {
+		if err := s.entities.RecordSlugChange(ctx, entity.CampaignID, entity.ID, oldSlug, slug); err != nil {
+			return nil, apperror.NewInternal(err)
+		}
+	}
+
+	s.events.PublishEntityEvent("updated", entity.CampaignID, entity.ID, entity)
+	s.hierarchy.HierarchyChanged(entity.CampaignID)
+	return entity, nil
+}
+
+// validateCustomSlug checks an Owner-typed slug. It must already be in
+// slug form (what Slugify would produce) rather than being normalized
+// quietly, so the Owner gets exactly the URL they typed.
+func validateCustomSlug(slug string) error {
+	if len(slug) > 200 {
+		return apperror.NewValidation("slug must be at most 200 characters")
+	}
+	if Slugify(slug) != slug {
+		return apperror.NewValidation("slug may only contain lowercase letters, digits, and single hyphens between them")
+	}
+	if reservedEntitySlugs[slug] {
+		return apperror.NewValidation(fmt.Sprintf("slug %q is reserved", slug))
+	}
+	return nil
+}
+
+// slugForName derives an entity's slug from a name. The name's own slug is
+// reused when this entity already holds it, live or retired — renaming back
+// (or changing only letter case) shouldn't pick up a -2.
+func (s *entityService) slugForName(ctx context.Context, entity *Entity, name string) (string, error) {
+	base := Slugify(name)
+	owner, err := s.entities.FindSlugOwner(ctx, entity.CampaignID, base)
+	if err != nil {
+		return "", apperror.NewInternal(err)
+	}
+	if owner == entity.ID {
+		return base, nil
+	}
+	slug, err := s.generateSlug(ctx, entity.CampaignID, name)
+	if err != nil {
+		return "", apperror.NewInternal(fmt.Errorf("generating slug: %w", err))
+	}
+	return slug, nil
+}
+
 // Update modifies an existing entity's name, type_label, privacy, entry, and fields.
 // If ExpectedUpdatedAt is set, the update is rejected with 409 Conflict if the
 // entity has been modified since that timestamp (optimistic concurrency control).
@@ -536,11 +792,13 @@ func (s *entityService) Update(ctx context.Context, entityID string, input Updat
 		return nil, apperror.NewBadRequest("entity name must be at most 200 characters")
 	}
 
-	// Regenerate slug if name changed.
-	if name != entity.Name {
-		slug, err := s.generateSlug(ctx, entity.CampaignID, name)
+	// Regenerate slug if name changed, unless the Owner chose it. The old
+	// slug is retired to the history below so its links keep resolving.
+	oldSlug := entity.Slug
+	if name != entity.Name && !entity.SlugCustom {
+		slug, err := s.slugForName(ctx, entity, name)
 		if err != nil {
-			return nil, apperror.NewInternal(fmt.Errorf("generating slug: %w", err))
+			return nil, err
 		}
 		entity.Slug = slug
 	}
@@ -607,7 +865,11 @@ func (s *entityService) Update(ctx context.Context, entityID string, input Updat
 		}
 	}
 
+	fieldsBefore := entity.FieldsData
 	if input.FieldsData != nil {
+		if err := s.validateEntityDateFields(ctx, entity, input.FieldsData); err != nil {
+			return nil, err
+		}
 		entity.FieldsData = input.FieldsData
 	}
 
@@ -616,8 +878,22 @@ func (s *entityService) Update(ctx context.Context, entityID string, input Updat
 	if err := s.entities.Update(ctx, entity); err != nil {
 		return nil, apperror.NewInternal(fmt.Errorf("updating entity: %w", err))
 	}
+	if input.FieldsData != nil {
+		s.fieldHistory.RecordFieldChanges(ctx, entity, fieldsBefore)
+	}
+	// Also on name/privacy changes: the events carry both.
+	s.syncEntityDates(ctx, entity)
+	if entity.Slug != oldSlug {
+		// The rename itself is saved; a missing redirect only costs old links.
+		if err := s.entities.RecordSlugChange(ctx, entity.CampaignID, entity.ID, oldSlug, entity.Slug); err != nil {
+			slog.Warn("recording retired entity slug failed",
+				slog.String("entity_id", entity.ID), slog.String("slug", oldSlug), slog.Any("error", err))
+		}
+	}
 
 	s.events.PublishEntityEvent("updated", entity.CampaignID, entity.ID, entity)
+	// A form save can rename, re-slug, re-parent, or flip privacy at once.
+	s.hierarchy.HierarchyChanged(entity.CampaignID)
 	return entity, nil
 }
 
@@ -727,6 +1003,7 @@ func (s *entityService) ReorderEntity(ctx context.Context, campaignID, entityID
 	if err := s.entities.ResequenceSiblings(ctx, campaignID, ordered); err != nil {
 		return apperror.NewInternal(fmt.Errorf("re-sequencing siblings: %w", err))
 	}
+	s.hierarchy.HierarchyChanged(campaignID)
 	return nil
 }
 
@@ -845,6 +1122,11 @@ func (s *entityService) UpdateFields(ctx context.Context, entityID string, field
 	// Load the current entity (best-effort) for search_text composition and the
 	// post-update broadcast below.
 	entity, _ := s.entities.FindByID(ctx, entityID)
+	if entity != nil {
+		if err := s.validateEntityDateFields(ctx, entity, fieldsData); err != nil {
+			return err
+		}
+	}
 
 	// Build search_text from existing entry HTML + new field values.
 	var entryHTML string
@@ -865,7 +1147,10 @@ func (s *entityService) UpdateFields(ctx context.Context, entityID string, field
 	// Carry the new fields on the payload; best-effort (only when the entity
 	// loaded), matching this method's existing tolerance of a load failure.
 	if entity != nil {
+		fieldsBefore := entity.FieldsData
 		entity.FieldsData = fieldsData
+		s.fieldHistory.RecordFieldChanges(ctx, entity, fieldsBefore)
+		s.syncEntityDates(ctx, entity)
 		s.events.PublishEntityEvent("updated", entity.CampaignID, entityID, entity)
 	}
 	slog.Info("entity fields updated", slog.String("entity_id", entityID))
@@ -893,19 +1178,32 @@ func (s *entityService) UpdateFieldOverrides(ctx context.Context, entityID strin
 		return err
 	}
 	slog.Info("entity field overrides updated", slog.String("entity_id", entityID))
+	// Added or hidden fields can add or drop an anniversary date.
+	if entity, err := s.entities.FindByID(ctx, entityID); err == nil {
+		s.syncEntityDates(ctx, entity)
+	}
 	return nil
 }
 
-// UpdateImage sets or clears the entity's header image path.
-// Validates the path to prevent directory traversal attacks.
-func (s *entityService) UpdateImage(ctx context.Context, entityID, imagePath string) error {
+// UpdateImage sets or clears the entity's header image path along with
+// its alt text and caption; a new image starts with whatever text came
+// with it, never the old image's. requireAlt (public campaigns) rejects a
+// new image without alt text. Validates the path to prevent directory
+// traversal attacks.
+func (s *entityService) UpdateImage(ctx context.Context, entityID, imagePath string, text ImageTextInput, requireAlt bool) error {
 	if imagePath != "" {
 		// Reject absolute paths and directory traversal attempts.
 		if strings.HasPrefix(imagePath, "/") || strings.Contains(imagePath, "..") {
 			return apperror.NewBadRequest("invalid image path")
 		}
+	} else {
+		text = ImageTextInput{}
+	}
+	text, err := normalizeImageText(text, requireAlt && imagePath != "")
+	if err != nil {
+		return err
 	}
-	if err := s.entities.UpdateImage(ctx, entityID, imagePath); err != nil {
+	if err := s.entities.UpdateImage(ctx, entityID, imagePath, text); err != nil {
 		return err
 	}
 	slog.Info("entity image updated",
@@ -915,6 +1213,41 @@ func (s *entityService) UpdateImage(ctx context.Context, entityID, imagePath str
 	return nil
 }
 
+// UpdateImageText edits the alt text and caption of the entity's current
+// header image.
+func (s *entityService) UpdateImageText(ctx context.Context, entityID string, text ImageTextInput, requireAlt bool) error {
+	entity, err := s.entities.FindByID(ctx, entityID)
+	if err != nil {
+		return err
+	}
+	if entity.ImagePath == nil || *entity.ImagePath == "" {
+		return apperror.NewBadRequest("this page has no header image")
+	}
+	text, err = normalizeImageText(text, requireAlt)
+	if err != nil {
+		return err
+	}
+	return s.entities.UpdateImageText(ctx, entityID, text)
+}
+
+// normalizeImageText trims and length-checks image alt text and caption.
+// requireAlt is set in public wiki mode: anonymous readers include screen
+// reader users, so a public image must say what it shows.
+func normalizeImageText(text ImageTextInput, requireAlt bool) (ImageTextInput, error) {
+	text.AltText = strings.Join(strings.Fields(text.AltText), " ")
+	text.Caption = strings.TrimSpace(text.Caption)
+	if requireAlt && text.AltText == "" {
+		return text, apperror.NewBadRequest("alt text is required for images in a public campaign")
+	}
+	if utf8.RuneCountInString(text.AltText) > maxImageAltLength {
+		return text, apperror.NewBadRequest(fmt.Sprintf("alt text must be %d characters or fewer", maxImageAltLength))
+	}
+	if utf8.RuneCountInString(text.Caption) > maxImageCaptionLength {
+		return text, apperror.NewBadRequest(fmt.Sprintf("caption must be %d characters or fewer", maxImageCaptionLength))
+	}
+	return text, nil
+}
+
 // UpdateCoverImage updates the cover/banner image for an entity.
 func (s *entityService) UpdateCoverImage(ctx context.Context, entityID, coverImagePath string) error {
 	if coverImagePath != "" {
@@ -940,10 +1273,20 @@ func (s *entityService) Delete(ctx context.Context, entityID string) error {
 	if err := s.entities.Delete(ctx, entityID); err != nil {
 		return err
 	}
+	// The events' entity link is ON DELETE SET NULL, so drop the
+	// anniversaries explicitly rather than leave unlinked copies.
+	if entity != nil {
+		if err := s.dateSyncer.SyncEntityDates(ctx, entity, nil); err != nil {
+			slog.Warn("removing entity date events failed",
+				slog.String("entity_id", entityID),
+				slog.Any("error", err))
+		}
+	}
 	slog.Info("entity deleted", slog.String("entity_id", entityID))
 
 	if entity != nil {
 		s.events.PublishEntityEvent("deleted", entity.CampaignID, entityID, entity)
+		s.hierarchy.HierarchyChanged(entity.CampaignID)
 	}
 	return nil
 }
@@ -980,7 +1323,9 @@ func (s *entityService) togglePrivate(ctx context.Context, entityID, campaignID
 
 	// Publish event so WebSocket clients see the visibility change.
 	entity.IsPrivate = newPrivate
+	s.syncEntityDates(ctx, entity)
 	s.events.PublishEntityEvent("updated", entity.CampaignID, entityID, entity)
+	s.hierarchy.HierarchyChanged(entity.CampaignID)
 
 	return newPrivate, nil
 }
@@ -1505,6 +1850,9 @@ func (s *entityService) BulkUpdateType(ctx context.Context, campaignID string, e
 		updated++
 	}
 
+	if updated > 0 {
+		s.hierarchy.HierarchyChanged(campaignID)
+	}
 	return updated, nil
 }
 
@@ -1855,7 +2203,9 @@ func (s *entityService) UpdateEntityType(ctx context.Context, id int, input Upda
 	et.Color = color
 
 	// Update fields if provided.
+	resyncDates := false
 	if input.Fields != nil {
+		resyncDates = anniversaryFieldsChanged(et.Fields, input.Fields)
 		et.Fields = input.Fields
 	}
 
@@ -1894,6 +2244,9 @@ func (s *entityService) UpdateEntityType(ctx context.Context, id int, input Upda
 		slog.String("name", name),
 	)
 
+	if resyncDates {
+		s.resyncTypeDates(ctx, et)
+	}
 	s.events.PublishEntityTypeEvent("updated", et.CampaignID, et)
 	return et, nil
 }
@@ -1979,13 +2332,18 @@ func (s *entityService) DeleteEntityType(ctx context.Context, id int) error {
 }
 
 // generateEntityTypeSlug creates a unique slug for an entity type within a campaign.
-// If the base slug is taken, appends -2, -3, etc. After maxEntityTypeSlugAttempts,
+// If the base slug is taken, or is a segment a plugin route already serves
+// ("maps", "sessions"), appends -2, -3, etc. After maxEntityTypeSlugAttempts,
 // falls back to a random suffix.
 func (s *entityService) generateEntityTypeSlug(ctx context.Context, campaignID, name string) (string, error) {
 	base := Slugify(name)
 	slug := base
 
 	for i := 2; i < maxEntityTypeSlugAttempts+2; i++ {
+		if s.isReservedTypeSlug(slug) {
+			slug = fmt.Sprintf("%s-%d", base, i)
+			continue
+		}
 		exists, err := s.types.SlugExists(ctx, campaignID, slug)
 		if err != nil {
 			return "", fmt.Errorf("checking entity type slug: %w", err)
@@ -2309,7 +2667,9 @@ func (s *entityService) SetEntityPermissions(ctx context.Context, entityID strin
 	// For VisibilityDefault the IsPrivate field was already set on the entity at
 	// the top of that case branch (line 1769), so we only need to set Visibility.
 	entity.Visibility = input.Visibility
+	s.syncEntityDates(ctx, entity)
 	s.events.PublishEntityEvent("updated", entity.CampaignID, entityID, entity)
+	s.hierarchy.HierarchyChanged(entity.CampaignID)
 
 	return nil
 }
@@ -2329,6 +2689,17 @@ func (s *entityService) CheckEntityAccess(ctx context.Context, entityID string,
 		return nil, err
 	}
 
+	// A draft is its creator's alone until published, and a pending
+	// submission is shown only to its creator and the Scribe+ who review
+	// it; no grant reveals either.
+	isCreator := userID != "" && entity.CreatedBy == userID
+	if entity.Status == StatusDraft && !isCreator {
+		return &EffectivePermission{}, nil
+	}
+	if entity.Status == StatusPending && !isCreator && role < permissions.RoleScribe {
+		return &EffectivePermission{}, nil
+	}
+
 	if entity.Visibility == VisibilityCustom {
 		return s.permissions.GetEffectivePermission(ctx, entityID, role, userID)
 	}
@@ -2360,6 +2731,111 @@ func (s *entityService) FilterViewableEntityIDs(ctx context.Context, campaignID
 	return s.entities.FilterViewableEntityIDs(ctx, campaignID, entityIDs, role, userID)
 }
 
+// GetPreviews builds tooltip previews for the entities among entityIDs that
+// the viewer may see. Entities come from one visibility-filtered query and
+// types from one campaign lookup, so a page of mentions costs two queries
+// rather than three per hover. Duplicates and blanks are dropped; more than
+// MaxPreviewBatch distinct IDs is rejected.
+func (s *entityService) GetPreviews(ctx context.Context, campaignID string, entityIDs []string, role int, userID string) ([]EntityPreview, error) {
+	ids := make([]string, 0, len(entityIDs))
+	seen := make(map[string]bool, len(entityIDs))
+	for _, id := range entityIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > MaxPreviewBatch {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("at most %d previews per request", MaxPreviewBatch))
+	}
+	if len(ids) == 0 {
+		return []EntityPreview{}, nil
+	}
+
+	list, err := s.entities.FindViewableByIDs(ctx, campaignID, ids, role, userID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	types, err := s.types.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	byID := make(map[int]*EntityType, len(types))
+	for i := range types {
+		byID[types[i].ID] = &types[i]
+	}
+
+	previews := make([]EntityPreview, 0, len(list))
+	for i := range list {
+		previews = append(previews, buildEntityPreview(&list[i], byID[list[i].EntityTypeID], role, userID))
+	}
+	return previews, nil
+}
+
+// buildEntityPreview assembles an entity's tooltip data for a viewer,
+// honouring its popup_config. Shared by the single and batch preview
+// endpoints so both filter identically. et may be nil (type deleted mid-
+// request); the joined type fields on the entity fill in.
+func buildEntityPreview(e *Entity, et *EntityType, role int, userID string) EntityPreview {
+	p := EntityPreview{
+		ID:         e.ID,
+		Name:       e.Name,
+		TypeName:   e.TypeName,
+		TypeIcon:   e.TypeIcon,
+		TypeColor:  e.TypeColor,
+		IsPrivate:  e.IsPrivate,
+		Attributes: make([]PreviewAttribute, 0), // [] not null in JSON
+		UpdatedAt:  e.UpdatedAt,
+	}
+	if et != nil {
+		p.TypeName, p.TypeIcon, p.TypeColor = et.Name, et.Icon, et.Color
+	}
+	if e.TypeLabel != nil {
+		p.TypeLabel = *e.TypeLabel
+	}
+
+	canSeeGM := role >= permissions.RoleScribe
+	cfg := e.EffectivePopupConfig()
+	if cfg.ShowEntry && e.EntryHTML != nil && *e.EntryHTML != "" {
+		// Players must not read inline secrets in a tooltip any more than
+		// on the page.
+		entryHTML := *e.EntryHTML
+		if !canSeeGM {
+			entryHTML = sanitize.StripSecretsHTML(entryHTML)
+		}
+		p.EntryExcerpt = EntryExcerpt(entryHTML, 150)
+	}
+	if cfg.ShowImage {
+		if img := e.CardImagePath(); img != "" {
+			p.ImagePath = fmt.Sprintf("/media/%s", img)
+			if e.HasOwnImage() {
+				p.ImageAlt = e.HeaderImageAlt()
+			}
+		} else {
+			p.Initials = e.CardInitials()
+		}
+	}
+	if cfg.ShowAttributes && et != nil {
+		// Strip GM-only and owner-only field values for viewers who can't see
+		// them (audit M-1 / C-FIELDS-OWNER-FILTER) — previews are
+		// player-reachable via the public campaign route.
+		fieldsData := FilterRestrictedFields(e.FieldsData, et.Fields, canSeeGM, e.IsOwnedBy(userID))
+		for _, fd := range MergeFields(et.Fields, e.FieldOverrides) {
+			val, ok := fieldsData[fd.Key]
+			if !ok || val == nil || fmt.Sprintf("%v", val) == "" {
+				continue
+			}
+			p.Attributes = append(p.Attributes, PreviewAttribute{Label: fd.Label, Value: fmt.Sprintf("%v", val)})
+			if len(p.Attributes) >= 5 {
+				break // Limit to 5 attributes in tooltip.
+			}
+		}
+	}
+	return p
+}
+
 // --- Seeder ---
 
 // SeedDefaults seeds the default entity types for a campaign. This method
@@ -2414,10 +2890,21 @@ func (s *entityService) generateSlug(ctx context.Context, campaignID, name strin
 	return fmt.Sprintf("%s-%s", base, hex.EncodeToString(b)), nil
 }
 
+// autoLinkMinNameLen keeps the editor auto-linker from turning short names
+// ("Al", "Bo") into links inside ordinary words.
+const autoLinkMinNameLen = 3
+
 // ListEntityNames returns lightweight name entries for all visible entities
 // in a campaign, including aliases. Used by the auto-linking feature in the editor.
 func (s *entityService) ListEntityNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error) {
-	return s.entities.ListNames(ctx, campaignID, role, userID)
+	return s.entities.ListNames(ctx, campaignID, role, userID, autoLinkMinNameLen)
+}
+
+// ListSwitcherNames returns every visible entity name and alias, however
+// short — the quick switcher matches what the user types, so "Bo" must be
+// findable even though the auto-linker ignores it.
+func (s *entityService) ListSwitcherNames(ctx context.Context, campaignID string, role int, userID string) ([]EntityNameEntry, error) {
+	return s.entities.ListNames(ctx, campaignID, role, userID, 1)
 }
 
 // GetAliases returns all aliases for a given entity.
@@ -3,6 +3,7 @@ package entities
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
@@ -101,6 +102,22 @@ func (m *mockEntityTypeRepo) UpdateColor(ctx context.Context, id int, color stri
 	return nil
 }
 
+func (m *mockEntityTypeRepo) UpdateDefaultImage(ctx context.Context, id int, imagePath *string) error {
+	return nil
+}
+
+func (m *mockEntityTypeRepo) UpdateAvatarStyle(ctx context.Context, id int, style string) error {
+	return nil
+}
+
+func (m *mockEntityTypeRepo) UpdatePlayerSubmissions(ctx context.Context, id int, enabled bool) error {
+	return nil
+}
+
+func (m *mockEntityTypeRepo) UpdatePlayerAccess(ctx context.Context, id int, canCreate, canEdit bool) error {
+	return nil
+}
+
 func (m *mockEntityTypeRepo) UpdateDashboard(ctx context.Context, id int, description *string, pinnedIDs []string) error {
 	return nil
 }
@@ -157,31 +174,39 @@ func (m *mockEntityTypeRepo) MaxSortOrder(ctx context.Context, campaignID string
 
 // mockEntityRepo implements EntityRepository for testing.
 type mockEntityRepo struct {
-	createFn         func(ctx context.Context, entity *Entity) error
-	findByIDFn       func(ctx context.Context, id string) (*Entity, error)
-	findBySlugFn     func(ctx context.Context, campaignID, slug string) (*Entity, error)
-	updateFn         func(ctx context.Context, entity *Entity) error
-	updateEntryFn    func(ctx context.Context, id, entryJSON, entryHTML string) error
-	updateImageFn    func(ctx context.Context, id, imagePath string) error
-	deleteFn         func(ctx context.Context, id string) error
-	slugExistsFn     func(ctx context.Context, campaignID, slug string) (bool, error)
-	listByCampaignFn func(ctx context.Context, campaignID string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error)
-	searchFn         func(ctx context.Context, campaignID, query string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error)
-	countByTypeFn    func(ctx context.Context, campaignID string, role int, userID string) (map[int]int, error)
-	listRecentFn     func(ctx context.Context, campaignID string, role int, userID string, limit int) ([]Entity, error)
-	findChildrenFn   func(ctx context.Context, parentID string, role int, userID string) ([]Entity, error)
-	findAncestorsFn  func(ctx context.Context, entityID string) ([]Entity, error)
-	updateParentFn   func(ctx context.Context, entityID string, parentID *string) error
-	findBacklinksFn  func(ctx context.Context, entityID string, role int, userID string) ([]Entity, error)
-	setAliasesFn     func(ctx context.Context, entityID string, aliases []string) error
-	updatePrivateFn  func(ctx context.Context, entityID string, isPrivate bool) error
-	listByOwnerFn    func(ctx context.Context, campaignID, ownerUserID string) ([]Entity, error)
-	listClaimedFn    func(ctx context.Context, campaignID string, role int, userID string) ([]Entity, error)
-	updateOwnerFn    func(ctx context.Context, entityID string, ownerUserID *string) error
-	updateMapIDFn    func(ctx context.Context, entityID string, mapID *string) error
-	listSiblingIDsFn func(ctx context.Context, campaignID string, entityTypeID int, parentID, parentNodeID *string) ([]string, error)
-	resequenceFn     func(ctx context.Context, campaignID string, orderedIDs []string) error
-	filterViewableFn func(entityIDs []string) (map[string]bool, error)
+	createFn          func(ctx context.Context, entity *Entity) error
+	findByIDFn        func(ctx context.Context, id string) (*Entity, error)
+	findBySlugFn      func(ctx context.Context, campaignID, slug string) (*Entity, error)
+	updateFn          func(ctx context.Context, entity *Entity) error
+	updateEntryFn     func(ctx context.Context, id, entryJSON, entryHTML string) error
+	updateImageFn     func(ctx context.Context, id, imagePath string, text ImageTextInput) error
+	updateImageTextFn func(ctx context.Context, id string, text ImageTextInput) error
+	deleteFn          func(ctx context.Context, id string) error
+	updateStatusFn    func(ctx context.Context, id string, status EntityStatus) error
+	slugExistsFn      func(ctx context.Context, campaignID, slug string) (bool, error)
+	findSlugOwnerFn   func(ctx context.Context, campaignID, slug string) (string, error)
+	recordSlugFn      func(ctx context.Context, campaignID, entityID, oldSlug, newSlug string) error
+	upsertBatchFn     func(ctx context.Context, creates, updates []*Entity) error
+	listByCampaignFn  func(ctx context.Context, campaignID string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error)
+	searchFn          func(ctx context.Context, campaignID, query string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error)
+	countByTypeFn     func(ctx context.Context, campaignID string, role int, userID string) (map[int]int, error)
+	listRecentFn      func(ctx context.Context, campaignID string, role int, userID string, limit int) ([]Entity, error)
+	listNamesFn       func(ctx context.Context, campaignID string, role int, userID string, minLen int) ([]EntityNameEntry, error)
+	findChildrenFn    func(ctx context.Context, parentID string, role int, userID string) ([]Entity, error)
+	findAncestorsFn   func(ctx context.Context, entityID string) ([]Entity, error)
+	updateParentFn    func(ctx context.Context, entityID string, parentID *string) error
+	findBacklinksFn   func(ctx context.Context, entityID string, role int, userID string) ([]Entity, error)
+	setAliasesFn      func(ctx context.Context, entityID string, aliases []string) error
+	updatePrivateFn   func(ctx context.Context, entityID string, isPrivate bool) error
+	listByOwnerFn     func(ctx context.Context, campaignID, ownerUserID string) ([]Entity, error)
+	listClaimedFn     func(ctx context.Context, campaignID string, role int, userID string) ([]Entity, error)
+	updateOwnerFn     func(ctx context.Context, entityID string, ownerUserID *string) error
+	updateMapIDFn     func(ctx context.Context, entityID string, mapID *string) error
+	listSiblingIDsFn  func(ctx context.Context, campaignID string, entityTypeID int, parentID, parentNodeID *string) ([]string, error)
+	resequenceFn      func(ctx context.Context, campaignID string, orderedIDs []string) error
+	filterViewableFn  func(entityIDs []string) (map[string]bool, error)
+	findViewableFn    func(entityIDs []string) ([]Entity, error)
+	findMentioningFn  func(entityID string) ([]Entity, error)
 }
 
 func (m *mockEntityRepo) Create(ctx context.Context, entity *Entity) error {
@@ -210,6 +235,20 @@ func (m *mockEntityRepo) FilterViewableEntityIDs(_ context.Context, _ string, en
 	return out, nil
 }
 
+func (m *mockEntityRepo) FindViewableByIDs(_ context.Context, _ string, entityIDs []string, _ int, _ string) ([]Entity, error) {
+	if m.findViewableFn != nil {
+		return m.findViewableFn(entityIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockEntityRepo) FindMentioningEntities(_ context.Context, _, entityID string, _ int, _ string) ([]Entity, error) {
+	if m.findMentioningFn != nil {
+		return m.findMentioningFn(entityID)
+	}
+	return nil, nil
+}
+
 func (m *mockEntityRepo) FindBySlug(ctx context.Context, campaignID, slug string) (*Entity, error) {
 	if m.findBySlugFn != nil {
 		return m.findBySlugFn(ctx, campaignID, slug)
@@ -243,9 +282,16 @@ func (m *mockEntityRepo) UpdateFieldOverrides(ctx context.Context, id string, ov
 	return nil
 }
 
-func (m *mockEntityRepo) UpdateImage(ctx context.Context, id, imagePath string) error {
+func (m *mockEntityRepo) UpdateImage(ctx context.Context, id, imagePath string, text ImageTextInput) error {
 	if m.updateImageFn != nil {
-		return m.updateImageFn(ctx, id, imagePath)
+		return m.updateImageFn(ctx, id, imagePath, text)
+	}
+	return nil
+}
+
+func (m *mockEntityRepo) UpdateImageText(ctx context.Context, id string, text ImageTextInput) error {
+	if m.updateImageTextFn != nil {
+		return m.updateImageTextFn(ctx, id, text)
 	}
 	return nil
 }
@@ -264,6 +310,27 @@ func (m *mockEntityRepo) SlugExists(ctx context.Context, campaignID, slug string
 	return false, nil
 }
 
+func (m *mockEntityRepo) FindSlugOwner(ctx context.Context, campaignID, slug string) (string, error) {
+	if m.findSlugOwnerFn != nil {
+		return m.findSlugOwnerFn(ctx, campaignID, slug)
+	}
+	return "", nil
+}
+
+func (m *mockEntityRepo) RecordSlugChange(ctx context.Context, campaignID, entityID, oldSlug, newSlug string) error {
+	if m.recordSlugFn != nil {
+		return m.recordSlugFn(ctx, campaignID, entityID, oldSlug, newSlug)
+	}
+	return nil
+}
+
+func (m *mockEntityRepo) UpsertBatch(ctx context.Context, creates, updates []*Entity) error {
+	if m.upsertBatchFn != nil {
+		return m.upsertBatchFn(ctx, creates, updates)
+	}
+	return nil
+}
+
 func (m *mockEntityRepo) ListByCampaign(ctx context.Context, campaignID string, typeIDs []int, role int, userID string, opts ListOptions) ([]Entity, int, error) {
 	if m.listByCampaignFn != nil {
 		return m.listByCampaignFn(ctx, campaignID, typeIDs, role, userID, opts)
@@ -338,6 +405,21 @@ func (m *mockEntityRepo) FindBacklinks(ctx context.Context, entityID string, rol
 	return nil, nil
 }
 
+func (m *mockEntityRepo) UpdateAppearance(ctx context.Context, entityID string, appearance *EntityAppearance) error {
+	return nil
+}
+
+func (m *mockEntityRepo) UpdateStatus(ctx context.Context, entityID string, status EntityStatus) error {
+	if m.updateStatusFn != nil {
+		return m.updateStatusFn(ctx, entityID, status)
+	}
+	return nil
+}
+
+func (m *mockEntityRepo) CountByStatus(ctx context.Context, campaignID string, status EntityStatus) (int, error) {
+	return 0, nil
+}
+
 func (m *mockEntityRepo) UpdatePopupConfig(ctx context.Context, entityID string, config *PopupConfig) error {
 	return nil
 }
@@ -346,7 +428,10 @@ func (m *mockEntityRepo) CopyEntityTags(ctx context.Context, sourceEntityID, tar
 	return nil
 }
 
-func (m *mockEntityRepo) ListNames(_ context.Context, _ string, _ int, _ string) ([]EntityNameEntry, error) {
+func (m *mockEntityRepo) ListNames(ctx context.Context, campaignID string, role int, userID string, minLen int) ([]EntityNameEntry, error) {
+	if m.listNamesFn != nil {
+		return m.listNamesFn(ctx, campaignID, role, userID, minLen)
+	}
 	return nil, nil
 }
 
@@ -478,7 +563,7 @@ func assertAppError(t *testing.T, err error, expectedCode int) {
 func TestCreate_Success(t *testing.T) {
 	typeRepo := &mockEntityTypeRepo{
 		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
-			return &EntityType{ID: 1, CampaignID: "camp-1", Slug: "character"}, nil
+			return &EntityType{ID: 1, CampaignID: "camp-1", Slug: "character", Name: "Character", Icon: "fa-user", Color: "#3b82f6"}, nil
 		},
 	}
 	entityRepo := &mockEntityRepo{
@@ -514,6 +599,10 @@ func TestCreate_Success(t *testing.T) {
 	if entity.ID == "" {
 		t.Error("expected a generated UUID, got empty string")
 	}
+	// Inline-create pickers render the result without a re-read.
+	if entity.TypeName != "Character" || entity.TypeIcon != "fa-user" || entity.TypeColor != "#3b82f6" {
+		t.Errorf("type display fields = %q/%q/%q, want the entity type's", entity.TypeName, entity.TypeIcon, entity.TypeColor)
+	}
 }
 
 // TestCreate_OwnerUserID guards CH1+CH5 plumbing: when the API
@@ -875,6 +964,108 @@ func TestUpdate_ClearsTypeLabelWhenEmpty(t *testing.T) {
 	}
 }
 
+func TestUpdate_RecordsRetiredSlug(t *testing.T) {
+	var recorded [2]string
+	entityRepo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, _ string) (*Entity, error) {
+			return &Entity{ID: "ent-1", CampaignID: "camp-1", Name: "Gandalf", Slug: "gandalf"}, nil
+		},
+		recordSlugFn: func(_ context.Context, _, _, oldSlug, newSlug string) error {
+			recorded = [2]string{oldSlug, newSlug}
+			return nil
+		},
+	}
+
+	svc := newTestService(entityRepo, &mockEntityTypeRepo{})
+	if _, err := svc.Update(context.Background(), "ent-1", UpdateEntityInput{Name: "Saruman"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recorded != [2]string{"gandalf", "saruman"} {
+		t.Errorf("recorded slug change %v, want [gandalf saruman]", recorded)
+	}
+}
+
+func TestUpdate_KeepsCustomSlugOnRename(t *testing.T) {
+	entityRepo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, _ string) (*Entity, error) {
+			return &Entity{ID: "ent-1", CampaignID: "camp-1", Name: "Gandalf", Slug: "the-grey", SlugCustom: true}, nil
+		},
+		recordSlugFn: func(_ context.Context, _, _, _, _ string) error {
+			t.Error("a custom slug must not change on rename")
+			return nil
+		},
+	}
+
+	svc := newTestService(entityRepo, &mockEntityTypeRepo{})
+	entity, err := svc.Update(context.Background(), "ent-1", UpdateEntityInput{Name: "Saruman"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entity.Slug != "the-grey" {
+		t.Errorf("expected custom slug 'the-grey' kept, got %q", entity.Slug)
+	}
+}
+
+func TestSetSlug(t *testing.T) {
+	// slug owners in camp-1: "saruman" is another page's live slug, and
+	// "mithrandir" is one of ent-1's own retired slugs.
+	owners := map[string]string{"gandalf": "ent-1", "mithrandir": "ent-1", "saruman": "ent-2"}
+	tests := []struct {
+		name       string
+		slug       string
+		custom     bool
+		wantSlug   string
+		wantCustom bool
+		wantCode   int
+	}{
+		{name: "custom slug", slug: "the-grey", wantSlug: "the-grey", wantCustom: true},
+		{name: "trims whitespace", slug: "  the-grey ", wantSlug: "the-grey", wantCustom: true},
+		{name: "reclaims own retired slug", slug: "mithrandir", wantSlug: "mithrandir", wantCustom: true},
+		{name: "taken by another page", slug: "saruman", wantCode: 422},
+		{name: "not in slug form", slug: "The Grey", wantCode: 422},
+		{name: "reserved segment", slug: "new", wantCode: 422},
+		{name: "too long", slug: strings.Repeat("a", 201), wantCode: 422},
+		{name: "reset to name-derived", slug: "", custom: true, wantSlug: "gandalf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := false
+			entityRepo := &mockEntityRepo{
+				findByIDFn: func(_ context.Context, _ string) (*Entity, error) {
+					slug := "gandalf"
+					if tt.custom {
+						slug = "the-grey"
+					}
+					return &Entity{ID: "ent-1", CampaignID: "camp-1", Name: "Gandalf", Slug: slug, SlugCustom: tt.custom}, nil
+				},
+				findSlugOwnerFn: func(_ context.Context, _, slug string) (string, error) {
+					return owners[slug], nil
+				},
+				updateFn: func(_ context.Context, _ *Entity) error {
+					updated = true
+					return nil
+				},
+			}
+
+			svc := newTestService(entityRepo, &mockEntityTypeRepo{})
+			entity, err := svc.SetSlug(context.Background(), "ent-1", tt.slug)
+			if tt.wantCode != 0 {
+				assertAppError(t, err, tt.wantCode)
+				if updated {
+					t.Error("rejected slug must not reach the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if entity.Slug != tt.wantSlug || entity.SlugCustom != tt.wantCustom {
+				t.Errorf("got slug %q custom=%v, want %q custom=%v", entity.Slug, entity.SlugCustom, tt.wantSlug, tt.wantCustom)
+			}
+		})
+	}
+}
+
 // --- UpdateEntry Tests ---
 
 func TestUpdateEntry_Success(t *testing.T) {
@@ -910,6 +1101,57 @@ func TestUpdateEntry_RepoError(t *testing.T) {
 	assertAppError(t, err, 404)
 }
 
+// --- UpdateImage Tests ---
+
+func TestUpdateImage_AltText(t *testing.T) {
+	cases := []struct {
+		name       string
+		imagePath  string
+		text       ImageTextInput
+		requireAlt bool
+		wantCode   int // 0 = success
+		want       ImageTextInput
+	}{
+		{"new image with description", "2026/01/a.png", ImageTextInput{AltText: " A  red dragon ", Caption: "Ashfang "}, false, 0, ImageTextInput{AltText: "A red dragon", Caption: "Ashfang"}},
+		{"private campaign allows no alt", "2026/01/a.png", ImageTextInput{}, false, 0, ImageTextInput{}},
+		{"public campaign requires alt", "2026/01/a.png", ImageTextInput{Caption: "Ashfang"}, true, 400, ImageTextInput{}},
+		{"clearing the image clears its text", "", ImageTextInput{AltText: "stale"}, true, 0, ImageTextInput{}},
+		{"alt too long", "2026/01/a.png", ImageTextInput{AltText: strings.Repeat("a", maxImageAltLength+1)}, false, 400, ImageTextInput{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *ImageTextInput
+			repo := &mockEntityRepo{
+				updateImageFn: func(_ context.Context, _, _ string, text ImageTextInput) error {
+					got = &text
+					return nil
+				},
+			}
+			err := newTestService(repo, &mockEntityTypeRepo{}).UpdateImage(context.Background(), "e1", tc.imagePath, tc.text, tc.requireAlt)
+			if tc.wantCode != 0 {
+				assertAppError(t, err, tc.wantCode)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == nil || *got != tc.want {
+				t.Errorf("stored text = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateImageText_NeedsAnImage(t *testing.T) {
+	repo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, Name: "Ashfang"}, nil
+		},
+	}
+	err := newTestService(repo, &mockEntityTypeRepo{}).UpdateImageText(context.Background(), "e1", ImageTextInput{AltText: "A dragon"}, false)
+	assertAppError(t, err, 400)
+}
+
 // --- Delete Tests ---
 
 func TestDelete_Success(t *testing.T) {
@@ -2385,3 +2627,107 @@ func TestCreateEntityType_PlayerCharacterGate(t *testing.T) {
 		}
 	})
 }
+
+// --- GetPreviews Tests ---
+
+func TestGetPreviews(t *testing.T) {
+	entry := `<p>The keep <span data-secret="true">hides a vault</span> stands.</p>`
+	stored := map[string]Entity{
+		"e1": {
+			ID: "e1", Name: "Red Keep", EntityTypeID: 3, EntryHTML: &entry,
+			FieldsData: map[string]any{"ruler": "Aegon", "treasury": "9000"},
+		},
+	}
+	typeRepo := &mockEntityTypeRepo{
+		listByCampaignFn: func(_ context.Context, _ string) ([]EntityType, error) {
+			return []EntityType{{ID: 3, Name: "Location", Icon: "fa-castle", Color: "#123456", Fields: []FieldDefinition{
+				{Key: "ruler", Label: "Ruler"},
+				{Key: "treasury", Label: "Treasury", GMOnly: true},
+			}}}, nil
+		},
+	}
+	var asked []string
+	entityRepo := &mockEntityRepo{
+		findViewableFn: func(ids []string) ([]Entity, error) {
+			asked = ids
+			var out []Entity
+			for _, id := range ids {
+				if e, ok := stored[id]; ok {
+					out = append(out, e)
+				}
+			}
+			return out, nil
+		},
+	}
+	svc := newTestService(entityRepo, typeRepo)
+
+	t.Run("player sees no secrets or GM fields", func(t *testing.T) {
+		previews, err := svc.GetPreviews(context.Background(), "camp-1", []string{"e1", " e1 ", "", "hidden"}, 1, "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(asked) != 2 {
+			t.Errorf("repo asked for %v, want deduplicated [e1 hidden]", asked)
+		}
+		if len(previews) != 1 {
+			t.Fatalf("got %d previews, want 1", len(previews))
+		}
+		p := previews[0]
+		if p.TypeName != "Location" || p.TypeIcon != "fa-castle" {
+			t.Errorf("type fields = %q/%q", p.TypeName, p.TypeIcon)
+		}
+		if strings.Contains(p.EntryExcerpt, "vault") {
+			t.Errorf("excerpt leaks a secret: %q", p.EntryExcerpt)
+		}
+		if len(p.Attributes) != 1 || p.Attributes[0].Label != "Ruler" {
+			t.Errorf("attributes = %+v, want only Ruler", p.Attributes)
+		}
+	})
+
+	t.Run("scribe sees everything", func(t *testing.T) {
+		previews, err := svc.GetPreviews(context.Background(), "camp-1", []string{"e1"}, 2, "u1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(previews[0].EntryExcerpt, "vault") || len(previews[0].Attributes) != 2 {
+			t.Errorf("scribe preview = %+v", previews[0])
+		}
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		ids := make([]string, MaxPreviewBatch+1)
+		for i := range ids {
+			ids[i] = "e" + strings.Repeat("x", i)
+		}
+		_, err := svc.GetPreviews(context.Background(), "camp-1", ids, 3, "u1")
+		assertAppError(t, err, 400)
+	})
+}
+
+// reservedSegments is a ReservedPathChecker over a fixed set.
+type reservedSegments map[string]bool
+
+func (r reservedSegments) IsReserved(segment string) bool { return r[segment] }
+
+func TestGenerateEntityTypeSlug_SkipsReservedSegments(t *testing.T) {
+	typeRepo := &mockEntityTypeRepo{
+		slugExistsFn: func(_ context.Context, _, slug string) (bool, error) {
+			return slug == "maps-2", nil
+		},
+	}
+	svc := newTestService(&mockEntityRepo{}, typeRepo).(*entityService)
+	svc.SetReservedPathChecker(reservedSegments{"maps": true})
+
+	slug, err := svc.generateEntityTypeSlug(context.Background(), "c1", "Maps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slug != "maps-3" {
+		t.Errorf("slug = %q, want maps-3", slug)
+	}
+
+	slug, _ = svc.generateEntityTypeSlug(context.Background(), "c1", "Locations")
+	if slug != "locations" {
+		t.Errorf("unreserved slug = %q", slug)
+	}
+}
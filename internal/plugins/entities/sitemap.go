@@ -0,0 +1,208 @@
+// sitemap.go — sitemap.xml for public campaigns. Lists every page the
+// anonymous public can see at its human-readable slug URL. The rendered XML
+// is cached in Redis and rebuilt off the request path whenever the page tree
+// changes (HierarchyListener), so crawlers don't pay for a full listing.
+package entities
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+const (
+	// sitemapCacheTTL is the backstop for any change that never reached
+	// HierarchyChanged (a direct DB edit, a missed hook).
+	sitemapCacheTTL = 24 * time.Hour
+
+	// sitemapRebuildDelay coalesces bursts — a bulk move, an import — into
+	// one rebuild per campaign.
+	sitemapRebuildDelay = 30 * time.Second
+
+	// sitemapMaxURLs is the sitemaps.org per-file limit.
+	sitemapMaxURLs = 50000
+
+	// sitemapPageSize is the listing page size used while building.
+	sitemapPageSize = 500
+)
+
+// SitemapCampaignLookup resolves a campaign for an off-request rebuild.
+// Implemented by campaigns.CampaignService.
+type SitemapCampaignLookup interface {
+	GetByID(ctx context.Context, id string) (*campaigns.Campaign, error)
+}
+
+// SitemapService builds and caches per-campaign sitemaps. It is also the
+// entity service's HierarchyListener.
+type SitemapService interface {
+	// Sitemap returns the campaign's sitemap.xml, from cache when possible.
+	// Callers must check the campaign is public first.
+	Sitemap(ctx context.Context, campaign *campaigns.Campaign) ([]byte, error)
+
+	HierarchyListener
+}
+
+// sitemapService implements SitemapService.
+type sitemapService struct {
+	entities  EntityRepository
+	campaigns SitemapCampaignLookup
+	cache     *redis.Client
+	baseURL   string
+	delay     time.Duration
+
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// NewSitemapService creates the sitemap service. cache may be nil, in which
+// case every request builds the sitemap and HierarchyChanged is a no-op.
+func NewSitemapService(entities EntityRepository, campaignLookup SitemapCampaignLookup, cache *redis.Client, baseURL string) SitemapService {
+	return &sitemapService{
+		entities:  entities,
+		campaigns: campaignLookup,
+		cache:     cache,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		delay:     sitemapRebuildDelay,
+		pending:   make(map[string]bool),
+	}
+}
+
+// sitemapCacheKey is the Redis key holding a campaign's rendered sitemap.
+func sitemapCacheKey(campaignID string) string {
+	return "sitemap:" + campaignID
+}
+
+// Sitemap returns the cached sitemap or builds (and caches) a fresh one.
+func (s *sitemapService) Sitemap(ctx context.Context, campaign *campaigns.Campaign) ([]byte, error) {
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, sitemapCacheKey(campaign.ID)).Bytes(); err == nil {
+			return cached, nil
+		}
+	}
+	data, err := s.build(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	s.store(ctx, campaign.ID, data)
+	return data, nil
+}
+
+// HierarchyChanged drops the cached sitemap at once — a page that just went
+// private must not stay listed until the rebuild — then schedules a rebuild
+// so the next crawler hit is served from cache again.
+func (s *sitemapService) HierarchyChanged(campaignID string) {
+	if s.cache == nil || campaignID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.cache.Del(ctx, sitemapCacheKey(campaignID)).Err(); err != nil {
+		slog.Warn("sitemap: cache invalidation failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[campaignID] {
+		return
+	}
+	s.pending[campaignID] = true
+	time.AfterFunc(s.delay, func() {
+		s.mu.Lock()
+		delete(s.pending, campaignID)
+		s.mu.Unlock()
+		s.rebuild(campaignID)
+	})
+}
+
+// rebuild regenerates a campaign's cached sitemap in the background. Private
+// campaigns get no cached copy at all.
+func (s *sitemapService) rebuild(campaignID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	campaign, err := s.campaigns.GetByID(ctx, campaignID)
+	if err != nil {
+		slog.Warn("sitemap: rebuild campaign lookup failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+		return
+	}
+	if !campaign.IsPublic {
+		return
+	}
+	data, err := s.build(ctx, campaign)
+	if err != nil {
+		slog.Warn("sitemap: rebuild failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+		return
+	}
+	s.store(ctx, campaignID, data)
+}
+
+// store caches a rendered sitemap; a failure only costs the next request a
+// rebuild.
+func (s *sitemapService) store(ctx context.Context, campaignID string, data []byte) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Set(ctx, sitemapCacheKey(campaignID), data, sitemapCacheTTL).Err(); err != nil {
+		slog.Warn("sitemap: cache write failed", slog.String("campaign_id", campaignID), slog.Any("error", err))
+	}
+}
+
+// sitemapURLSet is the <urlset> document root.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is one <url> entry.
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// build lists the campaign's publicly visible pages and renders the XML.
+// Pages are read at the anonymous visitor's identity (RoleNone, no user) so
+// the sitemap can never list what a logged-out crawler couldn't open.
+func (s *sitemapService) build(ctx context.Context, campaign *campaigns.Campaign) ([]byte, error) {
+	set := sitemapURLSet{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  []sitemapURL{{Loc: s.baseURL + "/campaigns/" + campaign.ID}},
+	}
+
+	for page := 1; len(set.URLs) < sitemapMaxURLs; page++ {
+		batch, total, err := s.entities.ListByCampaign(ctx, campaign.ID, nil, permissions.RoleNone, "",
+			ListOptions{Page: page, PerPage: sitemapPageSize, Sort: "name"})
+		if err != nil {
+			return nil, fmt.Errorf("listing sitemap entities: %w", err)
+		}
+		for _, e := range batch {
+			if e.IsTemplate || len(set.URLs) >= sitemapMaxURLs {
+				continue
+			}
+			set.URLs = append(set.URLs, sitemapURL{
+				Loc:     s.baseURL + EntitySlugPath(campaign.Slug, e.Slug),
+				LastMod: e.UpdatedAt.UTC().Format("2006-01-02"),
+			})
+		}
+		if len(batch) == 0 || page*sitemapPageSize >= total {
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(set); err != nil {
+		return nil, fmt.Errorf("encoding sitemap: %w", err)
+	}
+	return buf.Bytes(), nil
+}
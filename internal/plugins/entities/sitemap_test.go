@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// stubSitemapCampaigns serves one campaign for background rebuilds.
+type stubSitemapCampaigns struct{ campaign *campaigns.Campaign }
+
+func (s *stubSitemapCampaigns) GetByID(context.Context, string) (*campaigns.Campaign, error) {
+	return s.campaign, nil
+}
+
+// sitemapRepo lists whatever pages names holds, checking that the sitemap
+// reads at the anonymous identity.
+func sitemapRepo(t *testing.T, names *[]string) *mockEntityRepo {
+	return &mockEntityRepo{
+		listByCampaignFn: func(_ context.Context, _ string, _ []int, role int, userID string, _ ListOptions) ([]Entity, int, error) {
+			if role != permissions.RoleNone || userID != "" {
+				t.Errorf("sitemap listed at role %d user %q, want the anonymous identity", role, userID)
+			}
+			var out []Entity
+			for _, n := range *names {
+				out = append(out, Entity{Slug: n, UpdatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), IsTemplate: n == "template"})
+			}
+			return out, len(out), nil
+		},
+	}
+}
+
+func TestSitemap_ListsPublicPagesAtSlugURLs(t *testing.T) {
+	names := []string{"gandalf", "template"}
+	camp := &campaigns.Campaign{ID: "camp-1", Slug: "waterdeep", IsPublic: true}
+	svc := NewSitemapService(sitemapRepo(t, &names), &stubSitemapCampaigns{camp}, nil, "https://chronicle.test/")
+
+	data, err := svc.Sitemap(context.Background(), camp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	xml := string(data)
+	for _, want := range []string{
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		"<loc>https://chronicle.test/campaigns/camp-1</loc>",
+		"<loc>https://chronicle.test/campaigns/waterdeep/e/gandalf</loc><lastmod>2026-03-01</lastmod>",
+	} {
+		if !strings.Contains(xml, want) {
+			t.Errorf("sitemap missing %q:\n%s", want, xml)
+		}
+	}
+	if strings.Contains(xml, "/e/template") {
+		t.Errorf("template pages must not be listed:\n%s", xml)
+	}
+}
+
+func TestSitemap_HierarchyChangeInvalidatesThenRebuilds(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	names := []string{"gandalf"}
+	camp := &campaigns.Campaign{ID: "camp-1", Slug: "waterdeep", IsPublic: true}
+	svc := NewSitemapService(sitemapRepo(t, &names), &stubSitemapCampaigns{camp}, rdb, "https://chronicle.test").(*sitemapService)
+	svc.delay = 10 * time.Millisecond
+
+	if _, err := svc.Sitemap(context.Background(), camp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mr.Exists(sitemapCacheKey("camp-1")) {
+		t.Fatal("expected the sitemap to be cached")
+	}
+
+	names = []string{"gandalf", "saruman"}
+	svc.HierarchyChanged("camp-1")
+	if mr.Exists(sitemapCacheKey("camp-1")) {
+		t.Error("a hierarchy change must drop the stale sitemap immediately")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !mr.Exists(sitemapCacheKey("camp-1")) {
+		if time.Now().After(deadline) {
+			t.Fatal("sitemap was not rebuilt in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cached, _ := mr.Get(sitemapCacheKey("camp-1"))
+	if !strings.Contains(cached, "/e/saruman") {
+		t.Errorf("rebuilt sitemap missing the new page:\n%s", cached)
+	}
+}
+
+func TestSitemap_PrivateCampaignRebuildCachesNothing(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	names := []string{"gandalf"}
+	camp := &campaigns.Campaign{ID: "camp-1", Slug: "waterdeep"}
+	svc := NewSitemapService(sitemapRepo(t, &names), &stubSitemapCampaigns{camp}, rdb, "").(*sitemapService)
+
+	svc.rebuild("camp-1")
+	if mr.Exists(sitemapCacheKey("camp-1")) {
+		t.Error("a private campaign must not get a cached sitemap")
+	}
+}
+
+func TestBuildBreadcrumbs(t *testing.T) {
+	camp := &campaigns.Campaign{ID: "camp-1", Slug: "waterdeep", Name: "Waterdeep"}
+	entity := &Entity{Name: "Yawning Portal", Slug: "yawning-portal", TypeSlug: "locations", TypeNamePlural: "Locations"}
+	// GetAncestors order: immediate parent first.
+	ancestors := []Entity{
+		{Name: "Castle Ward", Slug: "castle-ward"},
+		{Name: "Waterdeep City", Slug: "waterdeep-city"},
+	}
+
+	list := BuildBreadcrumbs("https://chronicle.test/", camp, entity, ancestors)
+	want := []struct{ name, item string }{
+		{"Waterdeep", "https://chronicle.test/campaigns/camp-1"},
+		{"Locations", "https://chronicle.test/campaigns/camp-1/locations"},
+		{"Waterdeep City", "https://chronicle.test/campaigns/waterdeep/e/waterdeep-city"},
+		{"Castle Ward", "https://chronicle.test/campaigns/waterdeep/e/castle-ward"},
+		{"Yawning Portal", "https://chronicle.test/campaigns/waterdeep/e/yawning-portal"},
+	}
+	if list.Type != "BreadcrumbList" || len(list.Items) != len(want) {
+		t.Fatalf("got %+v, want %d crumbs", list, len(want))
+	}
+	for i, w := range want {
+		got := list.Items[i]
+		if got.Position != i+1 || got.Name != w.name || got.Item != w.item {
+			t.Errorf("crumb %d = %+v, want position %d %q %q", i, got, i+1, w.name, w.item)
+		}
+	}
+}
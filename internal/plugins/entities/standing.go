@@ -0,0 +1,294 @@
+package entities
+
+// standing.go — faction standing model and repository. A standing is one
+// faction entity's score toward a subject: a character entity, or the party
+// as a whole (SubjectEntityID == PartySubject). Scores live on a
+// per-campaign StandingScale whose tiers label ranges ("Hostile" up to
+// -60, "Neutral" from -19, ...). StandingService (standing_service.go)
+// applies access rules and writes history.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PartySubject is the subject ID for a faction's standing with the party as
+// a whole, rather than with one character.
+const PartySubject = ""
+
+// StandingTier labels the scores from Min up to the next tier's Min.
+type StandingTier struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Color string `json:"color"`
+}
+
+// StandingScale is a campaign's standing range and tiers, lowest tier
+// first. The first tier also covers scores below its Min.
+type StandingScale struct {
+	Min   int            `json:"min"`
+	Max   int            `json:"max"`
+	Tiers []StandingTier `json:"tiers"`
+}
+
+// DefaultStandingScale is used until a campaign saves its own.
+func DefaultStandingScale() StandingScale {
+	return StandingScale{
+		Min: -100,
+		Max: 100,
+		Tiers: []StandingTier{
+			{Label: "Hostile", Min: -100, Color: "#dc2626"},
+			{Label: "Unfriendly", Min: -59, Color: "#f97316"},
+			{Label: "Neutral", Min: -19, Color: "#6b7280"},
+			{Label: "Friendly", Min: 20, Color: "#16a34a"},
+			{Label: "Allied", Min: 60, Color: "#2563eb"},
+		},
+	}
+}
+
+// TierFor returns the tier a score falls in.
+func (s StandingScale) TierFor(score int) StandingTier {
+	if len(s.Tiers) == 0 {
+		return StandingTier{Label: "", Color: "#6b7280"}
+	}
+	tier := s.Tiers[0]
+	for _, t := range s.Tiers[1:] {
+		if score >= t.Min {
+			tier = t
+		}
+	}
+	return tier
+}
+
+// Clamp limits a score to the scale's range.
+func (s StandingScale) Clamp(score int) int {
+	if score < s.Min {
+		return s.Min
+	}
+	if score > s.Max {
+		return s.Max
+	}
+	return score
+}
+
+// FactionStanding is one faction's current score toward one subject.
+type FactionStanding struct {
+	FactionEntityID string    `json:"faction_entity_id"`
+	SubjectEntityID string    `json:"subject_entity_id"` // PartySubject for the whole party.
+	CampaignID      string    `json:"campaign_id"`
+	Score           int       `json:"score"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// StandingChange is one recorded change of a standing.
+type StandingChange struct {
+	ID        int64     `json:"id"`
+	OldScore  *int      `json:"old_score"` // nil when tracking started with this change.
+	NewScore  int       `json:"new_score"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// StandingRepository persists standings, their history and campaign scales.
+type StandingRepository interface {
+	// ListByCampaign returns every standing in the campaign whose subject
+	// still exists.
+	ListByCampaign(ctx context.Context, campaignID string) ([]FactionStanding, error)
+
+	// Get returns one standing, or nil when the pair isn't tracked.
+	Get(ctx context.Context, factionID, subjectID string) (*FactionStanding, error)
+
+	// Save writes the standing and appends change to its history in one
+	// transaction.
+	Save(ctx context.Context, st *FactionStanding, change *StandingChange) error
+
+	// Delete stops tracking the pair and drops its history.
+	Delete(ctx context.Context, factionID, subjectID string) error
+
+	// ListHistory returns the pair's most recent changes, newest first.
+	ListHistory(ctx context.Context, factionID, subjectID string, limit int) ([]StandingChange, error)
+
+	// GetScale returns the campaign's saved scale, or nil for the default.
+	GetScale(ctx context.Context, campaignID string) (*StandingScale, error)
+	SaveScale(ctx context.Context, campaignID string, scale StandingScale) error
+}
+
+// standingRepository implements StandingRepository with MariaDB.
+type standingRepository struct {
+	db *sql.DB
+}
+
+// NewStandingRepository creates a standing repository.
+func NewStandingRepository(db *sql.DB) StandingRepository {
+	return &standingRepository{db: db}
+}
+
+// ListByCampaign reads the campaign's standings, skipping subjects that
+// have been deleted (the subject column can't cascade; see the migration).
+func (r *standingRepository) ListByCampaign(ctx context.Context, campaignID string) ([]FactionStanding, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT fs.faction_entity_id, fs.subject_entity_id, fs.campaign_id, fs.score, fs.updated_at
+		 FROM faction_standings fs
+		 LEFT JOIN entities s ON s.id = fs.subject_entity_id
+		 WHERE fs.campaign_id = ? AND (fs.subject_entity_id = '' OR s.id IS NOT NULL)`,
+		campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing standings: %w", err)
+	}
+	defer rows.Close()
+
+	var out []FactionStanding
+	for rows.Next() {
+		var st FactionStanding
+		if err := rows.Scan(&st.FactionEntityID, &st.SubjectEntityID, &st.CampaignID, &st.Score, &st.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning standing: %w", err)
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// Get reads one standing.
+func (r *standingRepository) Get(ctx context.Context, factionID, subjectID string) (*FactionStanding, error) {
+	var st FactionStanding
+	err := r.db.QueryRowContext(ctx,
+		`SELECT faction_entity_id, subject_entity_id, campaign_id, score, updated_at
+		 FROM faction_standings WHERE faction_entity_id = ? AND subject_entity_id = ?`,
+		factionID, subjectID,
+	).Scan(&st.FactionEntityID, &st.SubjectEntityID, &st.CampaignID, &st.Score, &st.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting standing: %w", err)
+	}
+	return &st, nil
+}
+
+// Save upserts the standing and records the change.
+func (r *standingRepository) Save(ctx context.Context, st *FactionStanding, change *StandingChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning standing save: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO faction_standings (faction_entity_id, subject_entity_id, campaign_id, score, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE score = VALUES(score), updated_at = VALUES(updated_at)`,
+		st.FactionEntityID, st.SubjectEntityID, st.CampaignID, st.Score, st.UpdatedAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("saving standing: %w", err)
+	}
+
+	var reason, changedBy sql.NullString
+	if change.Reason != "" {
+		reason = sql.NullString{String: change.Reason, Valid: true}
+	}
+	if change.ChangedBy != "" {
+		changedBy = sql.NullString{String: change.ChangedBy, Valid: true}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO faction_standing_history
+		   (faction_entity_id, subject_entity_id, campaign_id, old_score, new_score, reason, changed_by, changed_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		st.FactionEntityID, st.SubjectEntityID, st.CampaignID, change.OldScore, change.NewScore,
+		reason, changedBy, change.ChangedAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("recording standing change: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Delete removes the standing and its history.
+func (r *standingRepository) Delete(ctx context.Context, factionID, subjectID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning standing delete: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	for _, q := range []string{
+		`DELETE FROM faction_standing_history WHERE faction_entity_id = ? AND subject_entity_id = ?`,
+		`DELETE FROM faction_standings WHERE faction_entity_id = ? AND subject_entity_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, factionID, subjectID); err != nil {
+			return fmt.Errorf("deleting standing: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListHistory reads the pair's changes, newest first.
+func (r *standingRepository) ListHistory(ctx context.Context, factionID, subjectID string, limit int) ([]StandingChange, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, old_score, new_score, COALESCE(reason, ''), COALESCE(changed_by, ''), changed_at
+		 FROM faction_standing_history
+		 WHERE faction_entity_id = ? AND subject_entity_id = ?
+		 ORDER BY changed_at DESC, id DESC
+		 LIMIT ?`,
+		factionID, subjectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing standing history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StandingChange
+	for rows.Next() {
+		var ch StandingChange
+		var old sql.NullInt64
+		if err := rows.Scan(&ch.ID, &old, &ch.NewScore, &ch.Reason, &ch.ChangedBy, &ch.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scanning standing change: %w", err)
+		}
+		if old.Valid {
+			v := int(old.Int64)
+			ch.OldScore = &v
+		}
+		out = append(out, ch)
+	}
+	return out, rows.Err()
+}
+
+// GetScale reads the campaign's scale.
+func (r *standingRepository) GetScale(ctx context.Context, campaignID string) (*StandingScale, error) {
+	var scale StandingScale
+	var tiers []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT min_score, max_score, tiers_json FROM faction_standing_scales WHERE campaign_id = ?`,
+		campaignID,
+	).Scan(&scale.Min, &scale.Max, &tiers)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting standing scale: %w", err)
+	}
+	if err := json.Unmarshal(tiers, &scale.Tiers); err != nil {
+		return nil, fmt.Errorf("decoding standing tiers: %w", err)
+	}
+	return &scale, nil
+}
+
+// SaveScale upserts the campaign's scale.
+func (r *standingRepository) SaveScale(ctx context.Context, campaignID string, scale StandingScale) error {
+	tiers, err := json.Marshal(scale.Tiers)
+	if err != nil {
+		return fmt.Errorf("encoding standing tiers: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO faction_standing_scales (campaign_id, min_score, max_score, tiers_json)
+		 VALUES (?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE min_score = VALUES(min_score), max_score = VALUES(max_score),
+		   tiers_json = VALUES(tiers_json)`,
+		campaignID, scale.Min, scale.Max, tiers,
+	); err != nil {
+		return fmt.Errorf("saving standing scale: %w", err)
+	}
+	return nil
+}
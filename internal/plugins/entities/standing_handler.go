@@ -0,0 +1,203 @@
+package entities
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetStandingService enables the faction standing tracker.
+func (h *Handler) SetStandingService(svc StandingService) {
+	h.standingSvc = svc
+}
+
+// standingsContext returns the campaign context once the tracker is known
+// to be wired.
+func (h *Handler) standingsContext(c echo.Context) (*campaigns.CampaignContext, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, apperror.NewMissingContext()
+	}
+	if h.standingSvc == nil {
+		return nil, apperror.NewNotFound("faction standings are not available")
+	}
+	return cc, nil
+}
+
+// StandingsPage renders the faction × subject standing matrix.
+// GET /campaigns/:id/standings
+func (h *Handler) StandingsPage(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	m, err := h.standingSvc.GetMatrix(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	csrfToken := middleware.GetCSRFToken(c)
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, StandingsContent(cc, m, csrfToken))
+	}
+	return middleware.Render(c, http.StatusOK, StandingsPage(cc, m, csrfToken))
+}
+
+// SetStandingAPI sets or adjusts one standing. A non-empty delta adjusts
+// the current score; otherwise score sets it. HTMX requests get the
+// re-rendered matrix back.
+// PUT /campaigns/:id/standings
+func (h *Handler) SetStandingAPI(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	input := SetStandingInput{
+		FactionEntityID: strings.TrimSpace(c.FormValue("faction_entity_id")),
+		SubjectEntityID: strings.TrimSpace(c.FormValue("subject_entity_id")),
+		Reason:          c.FormValue("reason"),
+	}
+	if raw := strings.TrimSpace(c.FormValue("delta")); raw != "" {
+		if input.Delta, err = strconv.Atoi(raw); err != nil {
+			return apperror.NewBadRequest("delta must be a whole number")
+		}
+	} else if raw := strings.TrimSpace(c.FormValue("score")); raw != "" {
+		score, err := strconv.Atoi(raw)
+		if err != nil {
+			return apperror.NewBadRequest("score must be a whole number")
+		}
+		input.Score = &score
+	}
+
+	ctx := c.Request().Context()
+	st, err := h.standingSvc.SetStanding(ctx, cc.Campaign.ID, auth.GetUserID(c), input)
+	if err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		return h.renderStandingsMatrix(c, cc)
+	}
+	return c.JSON(http.StatusOK, st)
+}
+
+// StopTrackingAPI removes one standing and its history.
+// DELETE /campaigns/:id/standings?faction_entity_id=&subject_entity_id=
+func (h *Handler) StopTrackingAPI(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	if err := h.standingSvc.StopTracking(c.Request().Context(), cc.Campaign.ID,
+		c.FormValue("faction_entity_id"), c.FormValue("subject_entity_id")); err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		return h.renderStandingsMatrix(c, cc)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// StandingHistory returns one standing's recent changes: a list fragment
+// for the matrix cell popover, JSON otherwise.
+// GET /campaigns/:id/standings/history?faction_entity_id=&subject_entity_id=
+func (h *Handler) StandingHistory(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	changes, err := h.standingSvc.ListHistory(c.Request().Context(), cc.Campaign.ID,
+		c.QueryParam("faction_entity_id"), c.QueryParam("subject_entity_id"),
+		cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, standingHistoryList(changes))
+	}
+	if changes == nil {
+		changes = []StandingChange{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{"changes": changes})
+}
+
+// UpdateStandingScaleAPI saves the campaign's score range and tiers. The
+// tier rows arrive as parallel tier_label / tier_min / tier_color fields.
+// PUT /campaigns/:id/standings/scale
+func (h *Handler) UpdateStandingScaleAPI(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	form, err := c.FormParams()
+	if err != nil {
+		return apperror.NewBadRequest("invalid form")
+	}
+	var scale StandingScale
+	if scale.Min, err = strconv.Atoi(strings.TrimSpace(form.Get("min"))); err != nil {
+		return apperror.NewBadRequest("the lowest score must be a whole number")
+	}
+	if scale.Max, err = strconv.Atoi(strings.TrimSpace(form.Get("max"))); err != nil {
+		return apperror.NewBadRequest("the highest score must be a whole number")
+	}
+	labels, mins, colors := form["tier_label"], form["tier_min"], form["tier_color"]
+	for i, label := range labels {
+		if strings.TrimSpace(label) == "" {
+			continue // Blank rows are how the editor drops a tier.
+		}
+		if i >= len(mins) {
+			return apperror.NewBadRequest("each tier needs a starting score")
+		}
+		tierMin, err := strconv.Atoi(strings.TrimSpace(mins[i]))
+		if err != nil {
+			return apperror.NewBadRequest("tier starting scores must be whole numbers")
+		}
+		tier := StandingTier{Label: label, Min: tierMin}
+		if i < len(colors) {
+			tier.Color = colors[i]
+		}
+		scale.Tiers = append(scale.Tiers, tier)
+	}
+
+	saved, err := h.standingSvc.SaveScale(c.Request().Context(), cc.Campaign.ID, scale)
+	if err != nil {
+		return err
+	}
+	if middleware.IsHTMX(c) {
+		return h.renderStandingsMatrix(c, cc)
+	}
+	return c.JSON(http.StatusOK, saved)
+}
+
+// StandingsDashboardBlock renders the dashboard summary, lazy-loaded by the
+// faction_standings block.
+// GET /campaigns/:id/standings/dashboard-block?limit=
+func (h *Handler) StandingsDashboardBlock(c echo.Context) error {
+	cc, err := h.standingsContext(c)
+	if err != nil {
+		return err
+	}
+	m, err := h.standingSvc.GetMatrix(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 20 {
+		limit = 6
+	}
+	return middleware.Render(c, http.StatusOK, standingsDashboardBlock(cc, m, limit))
+}
+
+// renderStandingsMatrix re-renders the matrix after an edit.
+func (h *Handler) renderStandingsMatrix(c echo.Context, cc *campaigns.CampaignContext) error {
+	m, err := h.standingSvc.GetMatrix(c.Request().Context(), cc.Campaign.ID, cc.VisibilityRole(), auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, standingsMatrix(cc, m, middleware.GetCSRFToken(c)))
+}
@@ -0,0 +1,355 @@
+package entities
+
+// standing_service.go — business logic for the faction standing tracker.
+// Builds the faction × subject matrix (only factions and characters the
+// viewer can see), applies set/adjust edits clamped to the campaign's
+// scale, and writes a history row for every change.
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// standingHistoryLimit caps the changes one history request returns.
+const standingHistoryLimit = 100
+
+// Scale bounds: wide enough for any tabletop reputation system, small
+// enough that a typo can't make the matrix unreadable.
+const (
+	standingScaleBound = 10000
+	standingMaxTiers   = 10
+)
+
+// StandingEntityReader loads entities and checks the viewer's access.
+// Implemented by EntityService.
+type StandingEntityReader interface {
+	GetByID(ctx context.Context, id string) (*Entity, error)
+	CheckEntityAccess(ctx context.Context, entityID string, role int, userID string) (*EffectivePermission, error)
+}
+
+// StandingParticipant is a faction or subject as shown in the matrix. The
+// party subject has an empty ID.
+type StandingParticipant struct {
+	ID    string
+	Name  string
+	Icon  string
+	Color string
+}
+
+// StandingCell is one faction's standing toward one subject.
+type StandingCell struct {
+	Tracked bool
+	Score   int
+	Tier    StandingTier
+}
+
+// StandingRow is a faction and its cells, aligned with the matrix subjects.
+type StandingRow struct {
+	Faction StandingParticipant
+	Cells   []StandingCell
+}
+
+// StandingMatrix is the tracker's view model.
+type StandingMatrix struct {
+	Scale    StandingScale
+	Subjects []StandingParticipant // The party first (when tracked), then characters by name.
+	Rows     []StandingRow         // Factions by name.
+}
+
+// SetStandingInput changes one standing. Score sets an absolute value;
+// without it Delta is added to the current score (0 for a new pair).
+type SetStandingInput struct {
+	FactionEntityID string `json:"faction_entity_id"`
+	SubjectEntityID string `json:"subject_entity_id"`
+	Score           *int   `json:"score"`
+	Delta           int    `json:"delta"`
+	Reason          string `json:"reason"`
+}
+
+// StandingService manages faction standings.
+type StandingService interface {
+	// GetMatrix returns the campaign's standings visible to the viewer.
+	GetMatrix(ctx context.Context, campaignID string, role int, userID string) (*StandingMatrix, error)
+
+	// SetStanding applies an edit and returns the new standing. Unchanged
+	// scores on tracked pairs are not recorded.
+	SetStanding(ctx context.Context, campaignID, userID string, input SetStandingInput) (*FactionStanding, error)
+
+	// StopTracking removes a pair and its history.
+	StopTracking(ctx context.Context, campaignID, factionID, subjectID string) error
+
+	// ListHistory returns the pair's changes, newest first.
+	ListHistory(ctx context.Context, campaignID, factionID, subjectID string, role int, userID string) ([]StandingChange, error)
+
+	// GetScale returns the campaign's scale, or the default.
+	GetScale(ctx context.Context, campaignID string) (StandingScale, error)
+
+	// SaveScale validates and stores the campaign's scale.
+	SaveScale(ctx context.Context, campaignID string, scale StandingScale) (StandingScale, error)
+}
+
+// standingService implements StandingService.
+type standingService struct {
+	repo     StandingRepository
+	entities StandingEntityReader
+	now      func() time.Time
+}
+
+// NewStandingService creates a standing service.
+func NewStandingService(repo StandingRepository, entities StandingEntityReader) StandingService {
+	return &standingService{repo: repo, entities: entities, now: time.Now}
+}
+
+// GetScale returns the saved scale or the default.
+func (s *standingService) GetScale(ctx context.Context, campaignID string) (StandingScale, error) {
+	scale, err := s.repo.GetScale(ctx, campaignID)
+	if err != nil {
+		return StandingScale{}, apperror.NewInternal(err)
+	}
+	if scale == nil {
+		return DefaultStandingScale(), nil
+	}
+	return *scale, nil
+}
+
+// SaveScale validates and stores the scale. Existing scores are left as
+// they are; they are clamped on their next edit.
+func (s *standingService) SaveScale(ctx context.Context, campaignID string, scale StandingScale) (StandingScale, error) {
+	scale, err := normalizeStandingScale(scale)
+	if err != nil {
+		return StandingScale{}, err
+	}
+	if err := s.repo.SaveScale(ctx, campaignID, scale); err != nil {
+		return StandingScale{}, apperror.NewInternal(err)
+	}
+	return scale, nil
+}
+
+// GetMatrix builds the matrix. Each distinct faction and subject costs one
+// entity read and one access check; a campaign tracks tens of them, not
+// thousands.
+func (s *standingService) GetMatrix(ctx context.Context, campaignID string, role int, userID string) (*StandingMatrix, error) {
+	scale, err := s.GetScale(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	standings, err := s.repo.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+
+	// Resolve each participant once; nil marks one the viewer can't see.
+	seen := map[string]*StandingParticipant{}
+	resolve := func(id string) *StandingParticipant {
+		if p, ok := seen[id]; ok {
+			return p
+		}
+		var p *StandingParticipant
+		if id == PartySubject {
+			p = &StandingParticipant{Name: "The party", Icon: "fa-users", Color: "#6b7280"}
+		} else if e := s.visibleEntity(ctx, campaignID, id, role, userID); e != nil {
+			p = &StandingParticipant{ID: e.ID, Name: e.Name, Icon: e.TypeIcon, Color: e.TypeColor}
+		}
+		seen[id] = p
+		return p
+	}
+
+	factions := map[string]StandingParticipant{}
+	subjects := map[string]StandingParticipant{}
+	cells := map[[2]string]StandingCell{}
+	for _, st := range standings {
+		f, subj := resolve(st.FactionEntityID), resolve(st.SubjectEntityID)
+		if f == nil || subj == nil {
+			continue
+		}
+		factions[f.ID] = *f
+		subjects[subj.ID] = *subj
+		cells[[2]string{f.ID, subj.ID}] = StandingCell{Tracked: true, Score: st.Score, Tier: scale.TierFor(st.Score)}
+	}
+
+	m := &StandingMatrix{Scale: scale}
+	for _, p := range subjects {
+		m.Subjects = append(m.Subjects, p)
+	}
+	sort.Slice(m.Subjects, func(i, j int) bool {
+		a, b := m.Subjects[i], m.Subjects[j]
+		if (a.ID == PartySubject) != (b.ID == PartySubject) {
+			return a.ID == PartySubject
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	var rows []StandingParticipant
+	for _, p := range factions {
+		rows = append(rows, p)
+	}
+	sort.Slice(rows, func(i, j int) bool { return strings.ToLower(rows[i].Name) < strings.ToLower(rows[j].Name) })
+	for _, f := range rows {
+		row := StandingRow{Faction: f, Cells: make([]StandingCell, len(m.Subjects))}
+		for i, subj := range m.Subjects {
+			row.Cells[i] = cells[[2]string{f.ID, subj.ID}]
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m, nil
+}
+
+// SetStanding validates the pair, applies the edit and records it.
+func (s *standingService) SetStanding(ctx context.Context, campaignID, userID string, input SetStandingInput) (*FactionStanding, error) {
+	if err := s.validatePair(ctx, campaignID, input.FactionEntityID, input.SubjectEntityID); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if len(reason) > 255 {
+		return nil, apperror.NewBadRequest("reason must be 255 characters or fewer")
+	}
+	scale, err := s.GetScale(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.Get(ctx, input.FactionEntityID, input.SubjectEntityID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+
+	var old *int
+	base := 0
+	if current != nil {
+		old = &current.Score
+		base = current.Score
+	}
+	score := base + input.Delta
+	if input.Score != nil {
+		score = *input.Score
+	}
+	score = scale.Clamp(score)
+	if current != nil && score == current.Score {
+		return current, nil
+	}
+
+	now := s.now()
+	st := &FactionStanding{
+		FactionEntityID: input.FactionEntityID,
+		SubjectEntityID: input.SubjectEntityID,
+		CampaignID:      campaignID,
+		Score:           score,
+		UpdatedAt:       now,
+	}
+	change := &StandingChange{OldScore: old, NewScore: score, Reason: reason, ChangedBy: userID, ChangedAt: now}
+	if err := s.repo.Save(ctx, st, change); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return st, nil
+}
+
+// StopTracking removes the pair after checking it belongs to the campaign.
+func (s *standingService) StopTracking(ctx context.Context, campaignID, factionID, subjectID string) error {
+	current, err := s.repo.Get(ctx, factionID, subjectID)
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	if current == nil || current.CampaignID != campaignID {
+		return apperror.NewNotFound("standing not found")
+	}
+	if err := s.repo.Delete(ctx, factionID, subjectID); err != nil {
+		return apperror.NewInternal(err)
+	}
+	return nil
+}
+
+// ListHistory returns the pair's history if the viewer can see both sides.
+func (s *standingService) ListHistory(ctx context.Context, campaignID, factionID, subjectID string, role int, userID string) ([]StandingChange, error) {
+	current, err := s.repo.Get(ctx, factionID, subjectID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if current == nil || current.CampaignID != campaignID ||
+		s.visibleEntity(ctx, campaignID, factionID, role, userID) == nil ||
+		(subjectID != PartySubject && s.visibleEntity(ctx, campaignID, subjectID, role, userID) == nil) {
+		return nil, apperror.NewNotFound("standing not found")
+	}
+	changes, err := s.repo.ListHistory(ctx, factionID, subjectID, standingHistoryLimit)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return changes, nil
+}
+
+// validatePair checks that the faction and subject are distinct entities of
+// the campaign (or the party subject).
+func (s *standingService) validatePair(ctx context.Context, campaignID, factionID, subjectID string) error {
+	if factionID == "" {
+		return apperror.NewBadRequest("faction is required")
+	}
+	if factionID == subjectID {
+		return apperror.NewBadRequest("a faction has no standing with itself")
+	}
+	for _, id := range []string{factionID, subjectID} {
+		if id == PartySubject {
+			continue
+		}
+		e, err := s.entities.GetByID(ctx, id)
+		if err != nil || e == nil || e.CampaignID != campaignID {
+			return apperror.NewNotFound("entity not found")
+		}
+	}
+	return nil
+}
+
+// visibleEntity returns the entity when it is in the campaign and the
+// viewer may see it, else nil.
+func (s *standingService) visibleEntity(ctx context.Context, campaignID, id string, role int, userID string) *Entity {
+	e, err := s.entities.GetByID(ctx, id)
+	if err != nil || e == nil || e.CampaignID != campaignID {
+		return nil
+	}
+	access, err := s.entities.CheckEntityAccess(ctx, id, role, userID)
+	if err != nil || access == nil || !access.CanView {
+		return nil
+	}
+	return e
+}
+
+// standingColorRe matches the #rrggbb colors the tier editor produces.
+var standingColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// normalizeStandingScale validates a scale and sorts its tiers.
+func normalizeStandingScale(scale StandingScale) (StandingScale, error) {
+	if scale.Min >= scale.Max {
+		return StandingScale{}, apperror.NewBadRequest("the lowest score must be below the highest")
+	}
+	if scale.Min < -standingScaleBound || scale.Max > standingScaleBound {
+		return StandingScale{}, apperror.NewBadRequest(fmt.Sprintf("scores must stay within ±%d", standingScaleBound))
+	}
+	if len(scale.Tiers) == 0 || len(scale.Tiers) > standingMaxTiers {
+		return StandingScale{}, apperror.NewBadRequest(fmt.Sprintf("use between 1 and %d tiers", standingMaxTiers))
+	}
+
+	tiers := make([]StandingTier, 0, len(scale.Tiers))
+	mins := map[int]bool{}
+	for _, t := range scale.Tiers {
+		t.Label = strings.TrimSpace(t.Label)
+		if t.Label == "" || len(t.Label) > 40 {
+			return StandingScale{}, apperror.NewBadRequest("each tier needs a label of up to 40 characters")
+		}
+		if t.Min < scale.Min || t.Min > scale.Max {
+			return StandingScale{}, apperror.NewBadRequest(fmt.Sprintf("tier %q starts outside the scale", t.Label))
+		}
+		if mins[t.Min] {
+			return StandingScale{}, apperror.NewBadRequest("two tiers start at the same score")
+		}
+		mins[t.Min] = true
+		if !standingColorRe.MatchString(t.Color) {
+			t.Color = "#6b7280"
+		}
+		tiers = append(tiers, t)
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Min < tiers[j].Min })
+	scale.Tiers = tiers
+	return scale, nil
+}
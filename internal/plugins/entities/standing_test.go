@@ -0,0 +1,143 @@
+package entities
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStandingScaleTierFor(t *testing.T) {
+	scale := DefaultStandingScale()
+	cases := []struct {
+		score int
+		want  string
+	}{
+		{-100, "Hostile"},
+		{-60, "Hostile"},
+		{-59, "Unfriendly"},
+		{0, "Neutral"},
+		{20, "Friendly"},
+		{100, "Allied"},
+		{-500, "Hostile"}, // Below the first tier still gets its label.
+	}
+	for _, tc := range cases {
+		if got := scale.TierFor(tc.score).Label; got != tc.want {
+			t.Errorf("TierFor(%d) = %q; want %q", tc.score, got, tc.want)
+		}
+	}
+	if got := scale.Clamp(250); got != 100 {
+		t.Errorf("Clamp(250) = %d; want 100", got)
+	}
+}
+
+func TestNormalizeStandingScale(t *testing.T) {
+	scale, err := normalizeStandingScale(StandingScale{
+		Min: 0, Max: 10,
+		Tiers: []StandingTier{
+			{Label: " Trusted ", Min: 7, Color: "#00ff00"},
+			{Label: "Suspicious", Min: 0, Color: "red"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if scale.Tiers[0].Label != "Suspicious" || scale.Tiers[1].Label != "Trusted" {
+		t.Errorf("tiers = %+v; want sorted by Min with trimmed labels", scale.Tiers)
+	}
+	if scale.Tiers[0].Color != "#6b7280" {
+		t.Errorf("invalid color kept as %q; want the default gray", scale.Tiers[0].Color)
+	}
+
+	bad := []StandingScale{
+		{Min: 5, Max: 5, Tiers: []StandingTier{{Label: "A", Min: 5}}},
+		{Min: 0, Max: 10},
+		{Min: 0, Max: 10, Tiers: []StandingTier{{Label: "A", Min: 11}}},
+		{Min: 0, Max: 10, Tiers: []StandingTier{{Label: "A", Min: 2}, {Label: "B", Min: 2}}},
+		{Min: -20000, Max: 10, Tiers: []StandingTier{{Label: "A", Min: 0}}},
+	}
+	for i, s := range bad {
+		if _, err := normalizeStandingScale(s); err == nil {
+			t.Errorf("case %d: expected an error for %+v", i, s)
+		}
+	}
+}
+
+// fakeStandingRepo keeps standings and history in memory.
+type fakeStandingRepo struct {
+	StandingRepository
+	standings map[[2]string]FactionStanding
+	changes   []StandingChange
+}
+
+func (f *fakeStandingRepo) Get(_ context.Context, factionID, subjectID string) (*FactionStanding, error) {
+	st, ok := f.standings[[2]string{factionID, subjectID}]
+	if !ok {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+func (f *fakeStandingRepo) Save(_ context.Context, st *FactionStanding, change *StandingChange) error {
+	f.standings[[2]string{st.FactionEntityID, st.SubjectEntityID}] = *st
+	f.changes = append(f.changes, *change)
+	return nil
+}
+
+func (f *fakeStandingRepo) GetScale(context.Context, string) (*StandingScale, error) {
+	return nil, nil
+}
+
+// fakeStandingEntities serves a fixed set of campaign entities.
+type fakeStandingEntities struct {
+	entities map[string]*Entity
+}
+
+func (f fakeStandingEntities) GetByID(_ context.Context, id string) (*Entity, error) {
+	return f.entities[id], nil
+}
+
+func (f fakeStandingEntities) CheckEntityAccess(context.Context, string, int, string) (*EffectivePermission, error) {
+	return &EffectivePermission{CanView: true}, nil
+}
+
+func TestSetStanding(t *testing.T) {
+	repo := &fakeStandingRepo{standings: map[[2]string]FactionStanding{}}
+	svc := NewStandingService(repo, fakeStandingEntities{entities: map[string]*Entity{
+		"guild": {ID: "guild", CampaignID: "c1"},
+		"other": {ID: "other", CampaignID: "c2"},
+	}})
+	ctx := context.Background()
+
+	// Starting to track records a change without an old score.
+	st, err := svc.SetStanding(ctx, "c1", "u1", SetStandingInput{FactionEntityID: "guild", Delta: 15, Reason: "Saved the guildmaster"})
+	if err != nil {
+		t.Fatalf("first set: %v", err)
+	}
+	if st.Score != 15 || len(repo.changes) != 1 || repo.changes[0].OldScore != nil {
+		t.Fatalf("score %d, changes %+v; want 15 with one fresh change", st.Score, repo.changes)
+	}
+
+	// Absolute scores are clamped to the scale.
+	score := 900
+	if st, err = svc.SetStanding(ctx, "c1", "u1", SetStandingInput{FactionEntityID: "guild", Score: &score}); err != nil {
+		t.Fatalf("second set: %v", err)
+	}
+	if st.Score != 100 || *repo.changes[1].OldScore != 15 {
+		t.Errorf("score %d, old %v; want 100 from 15", st.Score, repo.changes[1].OldScore)
+	}
+
+	// No-op edits aren't recorded.
+	if _, err := svc.SetStanding(ctx, "c1", "u1", SetStandingInput{FactionEntityID: "guild", Delta: 5}); err != nil {
+		t.Fatalf("no-op set: %v", err)
+	}
+	if len(repo.changes) != 2 {
+		t.Errorf("recorded %d changes; want 2", len(repo.changes))
+	}
+
+	// Entities from other campaigns and self-standings are rejected.
+	if _, err := svc.SetStanding(ctx, "c1", "u1", SetStandingInput{FactionEntityID: "other"}); err == nil {
+		t.Error("expected an error for another campaign's faction")
+	}
+	if _, err := svc.SetStanding(ctx, "c1", "u1", SetStandingInput{FactionEntityID: "guild", SubjectEntityID: "guild"}); err == nil {
+		t.Error("expected an error for a faction's standing with itself")
+	}
+}
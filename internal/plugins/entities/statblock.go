@@ -0,0 +1,589 @@
+package entities
+
+// statblock.go — turn a pasted D&D 5e or Pathfinder 2e statblock into
+// attribute fields on a page, so GMs stop re-typing monster stats. Same
+// two-step shape as the bulk image tool, both posting the same form:
+//
+//	POST /campaigns/:id/entities/:eid/statblock/preview  parse and show the field mapping
+//	POST /campaigns/:id/entities/:eid/statblock/apply    write the confirmed values
+//
+// Parsing is line based and forgiving: text copied out of a PDF or a
+// website keeps its labels ("Armor Class", "HP", "Fort +6") even when the
+// layout doesn't survive. Each value is matched to one of the page's
+// fields by key or label ("AC" and "Armor Class" both find "ac"); values
+// with no matching field can be added to the page as new fields.
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+const (
+	// maxStatblockLength caps the pasted text.
+	maxStatblockLength = 20000
+
+	// statblockNewField is the mapping target that adds the value to the
+	// page as a new field.
+	statblockNewField = "+new"
+
+	// statblockSection is the attributes section new fields land in.
+	statblockSection = "Statblock"
+)
+
+// Statblock systems the parser recognizes.
+const (
+	StatblockDnD5e = "dnd5e"
+	StatblockPF2e  = "pf2e"
+)
+
+// statblockField describes one value the parser extracts. Aliases are
+// normalized field keys or labels it maps onto.
+type statblockField struct {
+	Key     string
+	Label   string
+	Aliases []string
+	Long    bool // multi-line value; new fields are textareas
+}
+
+// statblockFields lists every extractable value in display order.
+var statblockFields = []statblockField{
+	{Key: "size", Label: "Size", Aliases: []string{"size"}},
+	{Key: "creature_type", Label: "Creature Type", Aliases: []string{"creaturetype", "type", "monstertype"}},
+	{Key: "alignment", Label: "Alignment", Aliases: []string{"alignment"}},
+	{Key: "level", Label: "Level", Aliases: []string{"level", "lvl", "creaturelevel"}},
+	{Key: "cr", Label: "Challenge Rating", Aliases: []string{"cr", "challenge", "challengerating"}},
+	{Key: "ac", Label: "Armor Class", Aliases: []string{"ac", "armorclass", "armor"}},
+	{Key: "hp", Label: "Hit Points", Aliases: []string{"hp", "hitpoints", "health", "maxhp"}},
+	{Key: "initiative", Label: "Initiative", Aliases: []string{"initiative", "init"}},
+	{Key: "speed", Label: "Speed", Aliases: []string{"speed", "movement"}},
+	{Key: "str", Label: "Strength", Aliases: []string{"str", "strength"}},
+	{Key: "dex", Label: "Dexterity", Aliases: []string{"dex", "dexterity"}},
+	{Key: "con", Label: "Constitution", Aliases: []string{"con", "constitution"}},
+	{Key: "int", Label: "Intelligence", Aliases: []string{"int", "intelligence"}},
+	{Key: "wis", Label: "Wisdom", Aliases: []string{"wis", "wisdom"}},
+	{Key: "cha", Label: "Charisma", Aliases: []string{"cha", "charisma"}},
+	{Key: "fort", Label: "Fortitude", Aliases: []string{"fort", "fortitude", "fortitudesave"}},
+	{Key: "ref", Label: "Reflex", Aliases: []string{"ref", "reflex", "reflexsave"}},
+	{Key: "will", Label: "Will", Aliases: []string{"will", "willsave"}},
+	{Key: "perception", Label: "Perception", Aliases: []string{"perception"}},
+	{Key: "saves", Label: "Saving Throws", Aliases: []string{"saves", "savingthrows"}},
+	{Key: "skills", Label: "Skills", Aliases: []string{"skills"}},
+	{Key: "vulnerabilities", Label: "Damage Vulnerabilities", Aliases: []string{"vulnerabilities", "damagevulnerabilities"}},
+	{Key: "weaknesses", Label: "Weaknesses", Aliases: []string{"weaknesses"}},
+	{Key: "resistances", Label: "Resistances", Aliases: []string{"resistances", "damageresistances"}},
+	{Key: "immunities", Label: "Immunities", Aliases: []string{"immunities", "damageimmunities"}},
+	{Key: "condition_immunities", Label: "Condition Immunities", Aliases: []string{"conditionimmunities"}},
+	{Key: "senses", Label: "Senses", Aliases: []string{"senses"}},
+	{Key: "languages", Label: "Languages", Aliases: []string{"languages"}},
+	{Key: "attacks", Label: "Attacks", Aliases: []string{"attacks", "actions", "strikes", "weapons"}, Long: true},
+}
+
+// statblockFieldByKey indexes statblockFields.
+var statblockFieldByKey = func() map[string]statblockField {
+	m := make(map[string]statblockField, len(statblockFields))
+	for _, f := range statblockFields {
+		m[f.Key] = f
+	}
+	return m
+}()
+
+// StatblockValue is one value read from the statblock.
+type StatblockValue struct {
+	Key   string
+	Label string
+	Value string
+}
+
+// ParsedStatblock is what the parser read. Values follow statblockFields
+// order and skip anything not found.
+type ParsedStatblock struct {
+	System string
+	Name   string
+	Values []StatblockValue
+}
+
+var (
+	sbSizeLine     = regexp.MustCompile(`(?i)^(tiny|small|medium|large|huge|gargantuan)\s+([^,]+?)(?:,\s*(.+))?$`)
+	sbPF2eCreature = regexp.MustCompile(`(?i)^(.*?)\s*\bcreature\s+(-?\d+)$`)
+	sbPF2eSaves    = regexp.MustCompile(`(?i)\bfort\s+[+-]\d+.*\bwill\s+[+-]\d+`)
+	sb5eDefenses   = regexp.MustCompile(`(?im)^(armor class|hit points|ac|hp) \d+`)
+	sbScoreMod     = regexp.MustCompile(`(\d+)\s*\(\s*([+-]\d+)\s*\)`)
+	sbAbilityScore = regexp.MustCompile(`(?i)\b(str|dex|con|int|wis|cha)\b\s+(\d+)\s+([+-]\d+)`)
+	sbAbilityMod   = regexp.MustCompile(`(?i)\b(str|dex|con|int|wis|cha)\b\s+([+-]\d+)`)
+	sbPF2eSave     = regexp.MustCompile(`(?i)\b(fort|ref|will)\s+([+-]\d+)`)
+	sbLeadingInt   = regexp.MustCompile(`^[+-]?\d+`)
+
+	// sb5eAttack is a 2014 ("Melee Weapon Attack: +4 to hit") or 2024
+	// ("Melee Attack Roll: +4") attack action at the start of a line.
+	sb5eAttack = regexp.MustCompile(`(?i)^([^.]{1,60})\.\s+(?:melee or ranged|melee|ranged)(?: weapon| spell)? attack(?: roll)?:\s*([+-]\d+)`)
+	sb5eHit    = regexp.MustCompile(`(?i)\bhit:\s*(.+?damage|[^.]+)`)
+	// sb5eAction starts any action ("Multiattack. The goblin makes...").
+	sb5eAction = regexp.MustCompile(`^[A-Z][^.:]{0,60}\.\s`)
+	// sbPF2eStrike is a Strike line; the action glyph pasted from the PDF
+	// or a web page ("◆", "[one-action]", "(1 action)") is dropped.
+	sbPF2eStrike = regexp.MustCompile(`(?i)^(melee|ranged)\s+(?:\[[^\]]*\]|\([^)]*actions?\)|[^\w\s]+)?\s*(.+)$`)
+)
+
+// sbLabelled returns the text after a line's label ("Armor Class 15" with
+// label "armor class" gives "15"). The label must be followed by a space,
+// so "Speed" doesn't match "Speedster".
+func sbLabelled(line, label string) (string, bool) {
+	if len(line) <= len(label) || !strings.EqualFold(line[:len(label)], label) || line[len(label)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(label):]), true
+}
+
+// sbClause returns the "; "-separated clause of s that starts with label,
+// without the label ("HP 16; Immunities fire" with "immunities" gives
+// "fire").
+func sbClause(s, label string) string {
+	for _, part := range strings.Split(s, ";") {
+		if v, ok := sbLabelled(strings.TrimSpace(part), label); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalizeStatblockText splits pasted text into trimmed lines, with the
+// typographic minus signs and dashes PDFs use turned into "-" and blank
+// lines dropped.
+func normalizeStatblockText(text string) []string {
+	text = strings.NewReplacer("−", "-", "–", "-", "—", "-", "\r", "", " ", " ").Replace(text)
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// ParseStatblock reads a pasted 5e or PF2e statblock. Text that looks like
+// neither is rejected rather than half-read.
+func ParseStatblock(text string) (*ParsedStatblock, error) {
+	if utf8.RuneCountInString(text) > maxStatblockLength {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("statblock must be %d characters or fewer", maxStatblockLength))
+	}
+	lines := normalizeStatblockText(text)
+	if len(lines) == 0 {
+		return nil, apperror.NewBadRequest("paste a statblock first")
+	}
+
+	values := map[string]string{}
+	sb := &ParsedStatblock{}
+	joined := strings.Join(lines, "\n")
+	switch {
+	case sbPF2eSaves.MatchString(joined) || sbPF2eCreature.MatchString(lines[0]) || (len(lines) > 1 && sbPF2eCreature.MatchString(lines[1])):
+		sb.System = StatblockPF2e
+		sb.Name = parsePF2eStatblock(lines, values)
+	case sb5eDefenses.MatchString(joined):
+		sb.System = StatblockDnD5e
+		sb.Name = parse5eStatblock(lines, values)
+	default:
+		return nil, apperror.NewBadRequest("this doesn't look like a D&D 5e or Pathfinder 2e statblock")
+	}
+
+	for _, f := range statblockFields {
+		// A lone dash is the statblock's "none".
+		if v := strings.TrimSpace(values[f.Key]); v != "" && v != "-" {
+			sb.Values = append(sb.Values, StatblockValue{Key: f.Key, Label: f.Label, Value: v})
+		}
+	}
+	if len(sb.Values) == 0 {
+		return nil, apperror.NewBadRequest("no stats found in the pasted text")
+	}
+	return sb, nil
+}
+
+// parse5eStatblock fills values from a 5e (2014 or 2024) statblock and
+// returns the creature's name.
+func parse5eStatblock(lines []string, values map[string]string) string {
+	name := lines[0]
+	labels := []struct{ label, key string }{
+		{"armor class", "ac"}, {"ac", "ac"},
+		{"hit points", "hp"}, {"hp", "hp"},
+		{"speed", "speed"},
+		{"saving throws", "saves"},
+		{"skills", "skills"},
+		{"damage vulnerabilities", "vulnerabilities"}, {"vulnerabilities", "vulnerabilities"},
+		{"damage resistances", "resistances"}, {"resistances", "resistances"},
+		{"damage immunities", "immunities"}, {"immunities", "immunities"},
+		{"condition immunities", "condition_immunities"},
+		{"senses", "senses"},
+		{"languages", "languages"},
+		{"challenge", "cr"}, {"cr", "cr"},
+	}
+
+	var attacks []string
+	for i, line := range lines {
+		if m := sbSizeLine.FindStringSubmatch(line); m != nil && values["size"] == "" && i >= 1 && i <= 2 {
+			values["size"], values["creature_type"], values["alignment"] = m[1], strings.TrimSpace(m[2]), m[3]
+			continue
+		}
+		for _, l := range labels {
+			if v, ok := sbLabelled(line, l.label); ok && values[l.key] == "" {
+				values[l.key] = v
+				break
+			}
+		}
+		if m := sb5eAttack.FindStringSubmatch(line); m != nil {
+			attacks = append(attacks, describe5eAttack(m[1], m[2], sb5eActionText(lines, i)))
+		}
+	}
+
+	// 2024 blocks put initiative on the AC line: "AC 15 Initiative +2 (12)".
+	if ac, init, ok := strings.Cut(values["ac"], " Initiative "); ok {
+		values["ac"], values["initiative"] = ac, init
+	}
+	parse5eAbilities(lines, values)
+	values["attacks"] = strings.Join(attacks, "\n")
+	return name
+}
+
+// sb5eActionText joins an action's line with the lines wrapped after it,
+// up to the next action.
+func sb5eActionText(lines []string, i int) string {
+	text := lines[i]
+	for j := i + 1; j < len(lines) && j <= i+4 && !sb5eAction.MatchString(lines[j]); j++ {
+		text += " " + lines[j]
+	}
+	return text
+}
+
+// describe5eAttack condenses an attack action to "Scimitar: +4 to hit,
+// 5 (1d6 + 2) slashing damage".
+func describe5eAttack(name, bonus, text string) string {
+	out := strings.TrimSpace(name) + ": " + bonus + " to hit"
+	if m := sb5eHit.FindStringSubmatch(text); m != nil {
+		out += ", " + strings.TrimSpace(m[1])
+	}
+	return out
+}
+
+// parse5eAbilities reads the ability table. The classic layout is a
+// "STR DEX CON INT WIS CHA" header over "8 (-1) 14 (+2) ..."; the 2024
+// layout lists "Str 8 -1 -1" per ability.
+func parse5eAbilities(lines []string, values map[string]string) {
+	keys := []string{"str", "dex", "con", "int", "wis", "cha"}
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.ToUpper(line), "STR") {
+			continue
+		}
+		// The scores follow the header, on its line or the next few.
+		rest := strings.Join(lines[i:min(i+13, len(lines))], " ")
+		if scores := sbScoreMod.FindAllStringSubmatch(rest, 6); len(scores) == 6 {
+			for k, m := range scores {
+				values[keys[k]] = m[1] + " (" + m[2] + ")"
+			}
+			return
+		}
+	}
+	for _, m := range sbAbilityScore.FindAllStringSubmatch(strings.Join(lines, " "), -1) {
+		if k := strings.ToLower(m[1]); values[k] == "" {
+			values[k] = m[2] + " (" + m[3] + ")"
+		}
+	}
+}
+
+// parsePF2eStatblock fills values from a PF2e statblock and returns the
+// creature's name.
+func parsePF2eStatblock(lines []string, values map[string]string) string {
+	name := lines[0]
+	var strikes []string
+	for i, line := range lines {
+		if m := sbPF2eCreature.FindStringSubmatch(line); m != nil && i <= 1 && values["level"] == "" {
+			values["level"] = m[2]
+			if m[1] != "" {
+				name = m[1]
+			}
+			continue
+		}
+		if v, ok := sbLabelled(line, "perception"); ok && values["perception"] == "" {
+			perception, senses, _ := strings.Cut(v, ";")
+			values["perception"] = strings.TrimSpace(strings.TrimSuffix(perception, ","))
+			values["senses"] = strings.TrimSpace(senses)
+			continue
+		}
+		if v, ok := sbLabelled(line, "ac"); ok && values["ac"] == "" {
+			ac, _, _ := strings.Cut(v, ";")
+			values["ac"] = strings.TrimSpace(ac)
+			for _, m := range sbPF2eSave.FindAllStringSubmatch(v, -1) {
+				values[strings.ToLower(m[1])] = m[2]
+			}
+			continue
+		}
+		if v, ok := sbLabelled(line, "hp"); ok && values["hp"] == "" {
+			hp, _, _ := strings.Cut(v, ";")
+			values["hp"] = strings.TrimSuffix(strings.TrimSpace(hp), ",")
+			values["immunities"] = sbClause(v, "immunities")
+			values["weaknesses"] = sbClause(v, "weaknesses")
+			values["resistances"] = sbClause(v, "resistances")
+			continue
+		}
+		for _, key := range []string{"languages", "skills", "speed"} {
+			if v, ok := sbLabelled(line, key); ok && values[key] == "" {
+				values[key] = v
+			}
+		}
+		if m := sbPF2eStrike.FindStringSubmatch(line); m != nil {
+			strikes = append(strikes, strings.ToUpper(m[1][:1])+strings.ToLower(m[1][1:])+": "+m[2])
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(strings.ToLower(line), "str ") {
+			for _, m := range sbAbilityMod.FindAllStringSubmatch(line, -1) {
+				values[strings.ToLower(m[1])] = m[2]
+			}
+			break
+		}
+	}
+	values["attacks"] = strings.Join(strikes, "\n")
+	return name
+}
+
+// --- Field mapping ---
+
+// StatblockRow is one review row: the parsed value and the field it goes
+// to ("" = skip, statblockNewField = add a new field).
+type StatblockRow struct {
+	StatblockValue
+	Target string
+}
+
+// normalizeFieldName reduces a field key or label to lowercase letters and
+// digits, so "Armor Class", "armor_class" and "ArmorClass" compare equal.
+func normalizeFieldName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// statblockImportable reports whether a field can take a statblock value.
+// Checkboxes, dates, URLs and selects can't hold "15 (natural armor)".
+func statblockImportable(f FieldDefinition) bool {
+	switch f.Type {
+	case "", "text", "textarea", "number":
+		return true
+	}
+	return false
+}
+
+// mapStatblockFields suggests a field for each parsed value: the first
+// importable field whose key or label is one of the value's aliases. Each
+// field takes one value; values without a field are offered as new
+// fields.
+func mapStatblockFields(values []StatblockValue, fields []FieldDefinition) []StatblockRow {
+	used := map[string]bool{}
+	rows := make([]StatblockRow, len(values))
+	for i, v := range values {
+		rows[i] = StatblockRow{StatblockValue: v, Target: statblockNewField}
+		aliases := importFieldSpec(v.Key).Aliases
+	fields:
+		for _, f := range fields {
+			if used[f.Key] || !statblockImportable(f) {
+				continue
+			}
+			key, label := normalizeFieldName(f.Key), normalizeFieldName(f.Label)
+			for _, a := range aliases {
+				if key == a || label == a {
+					rows[i].Target = f.Key
+					used[f.Key] = true
+					break fields
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// statblockFieldValue converts a value for its target field: number fields
+// keep the leading number ("15 (natural armor)" → "15", "+7" → "7").
+func statblockFieldValue(f FieldDefinition, value string) (string, bool) {
+	if f.Type != "number" {
+		return value, true
+	}
+	n := sbLeadingInt.FindString(value)
+	if n == "" {
+		return "", false
+	}
+	return strings.TrimPrefix(n, "+"), true
+}
+
+// uniqueStatblockKey returns key, suffixed if the page already has it.
+func uniqueStatblockKey(key string, taken map[string]bool) string {
+	candidate := key
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s_%d", key, n)
+	}
+	return candidate
+}
+
+// StatblockResult reports what apply did.
+type StatblockResult struct {
+	Updated int
+	Added   int
+	Skipped []string
+}
+
+// --- Handlers ---
+
+// statblockEntity loads the page the tool works on and its fields.
+func (h *Handler) statblockEntity(c echo.Context) (*campaigns.CampaignContext, *Entity, []FieldDefinition, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, nil, nil, apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	entity, err := h.service.GetByID(ctx, c.Param("eid"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if entity.CampaignID != cc.Campaign.ID {
+		return nil, nil, nil, apperror.NewNotFound("entity not found")
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cc, entity, MergeFields(et.Fields, entity.FieldOverrides), nil
+}
+
+// StatblockPage renders the paste form.
+// GET /campaigns/:id/entities/:eid/statblock
+func (h *Handler) StatblockPage(c echo.Context) error {
+	cc, entity, _, err := h.statblockEntity(c)
+	if err != nil {
+		return err
+	}
+	return middleware.Render(c, http.StatusOK, StatblockPage(cc, entity, middleware.GetCSRFToken(c)))
+}
+
+// StatblockPreview parses the pasted text and returns the mapping table.
+// Nothing is stored.
+// POST /campaigns/:id/entities/:eid/statblock/preview
+func (h *Handler) StatblockPreview(c echo.Context) error {
+	cc, entity, fields, err := h.statblockEntity(c)
+	if err != nil {
+		return err
+	}
+	sb, err := ParseStatblock(c.FormValue("text"))
+	if err != nil {
+		return middleware.Render(c, http.StatusOK, StatblockError(apperror.SafeMessage(err)))
+	}
+	var options []FieldDefinition
+	for _, f := range fields {
+		if statblockImportable(f) {
+			options = append(options, f)
+		}
+	}
+	return middleware.Render(c, http.StatusOK, StatblockReview(cc, entity, sb, mapStatblockFields(sb.Values, fields), options))
+}
+
+// StatblockApply writes the confirmed values. The form pairs each
+// "stat_key" with a "stat_value" and a "target" field key ("" = skip,
+// "+new" = add a field). Fields not in the form keep their values.
+// POST /campaigns/:id/entities/:eid/statblock/apply
+func (h *Handler) StatblockApply(c echo.Context) error {
+	cc, entity, fields, err := h.statblockEntity(c)
+	if err != nil {
+		return err
+	}
+	form, err := c.FormParams()
+	if err != nil {
+		return apperror.NewBadRequest("invalid form")
+	}
+	keys, vals, targets := form["stat_key"], form["stat_value"], form["target"]
+	if len(keys) != len(vals) || len(keys) != len(targets) {
+		return apperror.NewBadRequest("review form is out of date; preview the statblock again")
+	}
+
+	byKey := make(map[string]FieldDefinition, len(fields))
+	taken := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		byKey[f.Key] = f
+		taken[f.Key] = true
+	}
+	data := make(map[string]any, len(entity.FieldsData)+len(keys))
+	for k, v := range entity.FieldsData {
+		data[k] = v
+	}
+	overrides := &FieldOverrides{}
+	if entity.FieldOverrides != nil {
+		*overrides = *entity.FieldOverrides
+		overrides.Added = append([]FieldDefinition(nil), entity.FieldOverrides.Added...)
+	}
+
+	result := StatblockResult{}
+	written := map[string]bool{}
+	for i, key := range keys {
+		spec, ok := statblockFieldByKey[key]
+		value := strings.TrimSpace(vals[i])
+		if !ok || value == "" || targets[i] == "" {
+			continue
+		}
+		if utf8.RuneCountInString(value) > maxStatblockLength {
+			return apperror.NewBadRequest(spec.Label + " is too long")
+		}
+		if targets[i] == statblockNewField {
+			f := FieldDefinition{Key: uniqueStatblockKey(key, taken), Label: spec.Label, Type: "text", Section: statblockSection}
+			if spec.Long {
+				f.Type = "textarea"
+			}
+			overrides.Added = append(overrides.Added, f)
+			taken[f.Key] = true
+			data[f.Key] = value
+			result.Added++
+			continue
+		}
+		f, ok := byKey[targets[i]]
+		if !ok || !statblockImportable(f) {
+			result.Skipped = append(result.Skipped, spec.Label+": field not found")
+			continue
+		}
+		if written[f.Key] {
+			result.Skipped = append(result.Skipped, spec.Label+": "+f.Label+" already gets another value")
+			continue
+		}
+		v, ok := statblockFieldValue(f, value)
+		if !ok {
+			result.Skipped = append(result.Skipped, spec.Label+": "+f.Label+" needs a number")
+			continue
+		}
+		data[f.Key] = v
+		written[f.Key] = true
+		result.Updated++
+	}
+
+	ctx := c.Request().Context()
+	if result.Added > 0 {
+		if err := h.service.UpdateFieldOverrides(ctx, entity.ID, overrides); err != nil {
+			return err
+		}
+	}
+	if result.Added+result.Updated > 0 {
+		if err := h.service.UpdateFields(ctx, entity.ID, data); err != nil {
+			return err
+		}
+		h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, entity.ID, entity.Name, map[string]any{
+			"statblock": fmt.Sprintf("imported %d field(s)", result.Added+result.Updated),
+		})
+	}
+	return middleware.Render(c, http.StatusOK, StatblockResultView(cc, entity, result))
+}
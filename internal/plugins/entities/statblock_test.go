@@ -0,0 +1,184 @@
+package entities
+
+import (
+	"testing"
+)
+
+const goblin5e = `Goblin
+Small humanoid (goblinoid), neutral evil
+Armor Class 15 (leather armor, shield)
+Hit Points 7 (2d6)
+Speed 30 ft.
+STR DEX CON INT WIS CHA
+8 (−1) 14 (+2) 10 (+0) 10 (+0) 8 (−1) 8 (−1)
+Skills Stealth +6
+Senses darkvision 60 ft., passive Perception 9
+Languages Common, Goblin
+Challenge 1/4 (50 XP)
+Nimble Escape. The goblin can take the Disengage or Hide action as a bonus action on each of its turns.
+Actions
+Scimitar. Melee Weapon Attack: +4 to hit, reach 5 ft., one target.
+Hit: 5 (1d6 + 2) slashing damage.
+Shortbow. Ranged Weapon Attack: +4 to hit, range 80/320 ft., one target. Hit: 5 (1d6 + 2) piercing damage.`
+
+const goblin2024 = `Goblin Warrior
+Small Fey (Goblinoid), Chaotic Neutral
+AC 15 Initiative +2 (12)
+HP 10 (3d6)
+Speed 30 ft.
+Str 8 −1 −1
+Dex 15 +2 +2
+Con 10 +0 +0
+Int 10 +0 +0
+Wis 8 −1 −1
+Cha 8 −1 −1
+Skills Stealth +6
+Senses Darkvision 60 ft.; Passive Perception 9
+Languages Common, Goblin
+CR 1/4 (XP 50; PB +2)
+Actions
+Scimitar. Melee Attack Roll: +4, reach 5 ft. Hit: 5 (1d6 + 2) Slashing damage, plus 2 (1d4) Slashing damage if the attack roll had Advantage.`
+
+const goblinPF2e = `Goblin Warrior Creature -1
+CE Small Goblin Humanoid
+Perception +2; darkvision
+Languages Common, Goblin
+Skills Acrobatics +5, Athletics +2, Nature +1, Stealth +5
+Str +0, Dex +3, Con +1, Int +0, Wis -1, Cha +1
+Items dogslicer, leather armor, shortbow (12 arrows)
+AC 16; Fort +5, Ref +7, Will +3
+HP 6
+Goblin Scuttle [reaction] Trigger A goblin ally ends a move action adjacent to the warrior.
+Speed 25 feet
+Melee ◆ dogslicer +7 (agile, backstabber, finesse), Damage 1d6 slashing
+Ranged [one-action] shortbow +6 (deadly d10, range increment 60 feet), Damage 1d6 piercing`
+
+// statblockValues indexes parsed values by key.
+func statblockValues(sb *ParsedStatblock) map[string]string {
+	m := make(map[string]string, len(sb.Values))
+	for _, v := range sb.Values {
+		m[v.Key] = v.Value
+	}
+	return m
+}
+
+func TestParseStatblock(t *testing.T) {
+	cases := []struct {
+		name       string
+		text       string
+		system     string
+		statName   string
+		want       map[string]string
+		wantAbsent []string
+	}{
+		{
+			name: "5e 2014", text: goblin5e, system: StatblockDnD5e, statName: "Goblin",
+			want: map[string]string{
+				"size": "Small", "creature_type": "humanoid (goblinoid)", "alignment": "neutral evil",
+				"ac": "15 (leather armor, shield)", "hp": "7 (2d6)", "speed": "30 ft.",
+				"str": "8 (-1)", "dex": "14 (+2)", "cha": "8 (-1)",
+				"skills": "Stealth +6", "languages": "Common, Goblin", "cr": "1/4 (50 XP)",
+				"attacks": "Scimitar: +4 to hit, 5 (1d6 + 2) slashing damage\nShortbow: +4 to hit, 5 (1d6 + 2) piercing damage",
+			},
+			wantAbsent: []string{"fort", "level"},
+		},
+		{
+			name: "5e 2024", text: goblin2024, system: StatblockDnD5e, statName: "Goblin Warrior",
+			want: map[string]string{
+				"creature_type": "Fey (Goblinoid)", "ac": "15", "initiative": "+2 (12)", "hp": "10 (3d6)",
+				"str": "8 (-1)", "dex": "15 (+2)", "wis": "8 (-1)", "cr": "1/4 (XP 50; PB +2)",
+				"attacks": "Scimitar: +4 to hit, 5 (1d6 + 2) Slashing damage",
+			},
+		},
+		{
+			name: "pf2e", text: goblinPF2e, system: StatblockPF2e, statName: "Goblin Warrior",
+			want: map[string]string{
+				"level": "-1", "perception": "+2", "senses": "darkvision", "ac": "16",
+				"fort": "+5", "ref": "+7", "will": "+3", "hp": "6", "speed": "25 feet",
+				"dex": "+3", "wis": "-1", "skills": "Acrobatics +5, Athletics +2, Nature +1, Stealth +5",
+				"attacks": "Melee: dogslicer +7 (agile, backstabber, finesse), Damage 1d6 slashing\nRanged: shortbow +6 (deadly d10, range increment 60 feet), Damage 1d6 piercing",
+			},
+			wantAbsent: []string{"cr", "saves"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sb, err := ParseStatblock(tc.text)
+			if err != nil {
+				t.Fatalf("ParseStatblock: %v", err)
+			}
+			if sb.System != tc.system || sb.Name != tc.statName {
+				t.Errorf("system, name = %q, %q; want %q, %q", sb.System, sb.Name, tc.system, tc.statName)
+			}
+			got := statblockValues(sb)
+			for k, want := range tc.want {
+				if got[k] != want {
+					t.Errorf("%s = %q, want %q", k, got[k], want)
+				}
+			}
+			for _, k := range tc.wantAbsent {
+				if v, ok := got[k]; ok {
+					t.Errorf("%s = %q, want absent", k, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStatblock_Rejects(t *testing.T) {
+	for _, text := range []string{"", "   \n ", "Dear diary, today the goblins were quiet."} {
+		if _, err := ParseStatblock(text); err == nil {
+			t.Errorf("ParseStatblock(%q) accepted", text)
+		}
+	}
+}
+
+func TestMapStatblockFields(t *testing.T) {
+	values := []StatblockValue{
+		{Key: "ac", Label: "Armor Class", Value: "15 (natural armor)"},
+		{Key: "hp", Label: "Hit Points", Value: "7 (2d6)"},
+		{Key: "str", Label: "Strength", Value: "8 (-1)"},
+		{Key: "speed", Label: "Speed", Value: "30 ft."},
+		{Key: "creature_type", Label: "Creature Type", Value: "humanoid"},
+	}
+	fields := []FieldDefinition{
+		{Key: "armor_class", Label: "Armour", Type: "number"},
+		{Key: "hit_points", Label: "HP", Type: "number"},
+		{Key: "strength", Label: "STR", Type: "text"},
+		{Key: "speed", Label: "Speed", Type: "select", Options: []string{"slow", "fast"}},
+		{Key: "type", Label: "Type", Type: "text"},
+	}
+	rows := mapStatblockFields(values, fields)
+	want := []string{"armor_class", "hit_points", "strength", statblockNewField, "type"}
+	for i, r := range rows {
+		if r.Target != want[i] {
+			t.Errorf("%s -> %q, want %q", r.Key, r.Target, want[i])
+		}
+	}
+}
+
+func TestStatblockFieldValue(t *testing.T) {
+	number := FieldDefinition{Type: "number"}
+	cases := []struct {
+		field FieldDefinition
+		in    string
+		want  string
+		ok    bool
+	}{
+		{number, "15 (natural armor)", "15", true},
+		{number, "+7", "7", true},
+		{number, "-1", "-1", true},
+		{number, "1/4 (50 XP)", "1", true},
+		{number, "darkvision", "", false},
+		{FieldDefinition{Type: "text"}, "15 (natural armor)", "15 (natural armor)", true},
+	}
+	for _, tc := range cases {
+		got, ok := statblockFieldValue(tc.field, tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("statblockFieldValue(%s, %q) = %q, %v; want %q, %v", tc.field.Type, tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+	if got := uniqueStatblockKey("ac", map[string]bool{"ac": true, "ac_2": true}); got != "ac_3" {
+		t.Errorf("uniqueStatblockKey = %q", got)
+	}
+}
@@ -0,0 +1,252 @@
+package entities
+
+// status.go — page lifecycle states. A page is a draft while it's being
+// written (only its creator and the campaign owner can see it), pending
+// when a player has submitted it for review (its creator and Scribe+ can
+// see it; see submissions.go), published once it's ready (normal
+// visibility rules), and archived when it's no longer current (still
+// reachable by link, but left out of lists and search unless asked for).
+// The draft and pending gates live in visibilityFilter and
+// CheckEntityAccess so every surface that honors visibility honors them.
+//
+//	POST /campaigns/:id/entities/:eid/status    {"status": "published"}
+//	POST /campaigns/:id/entities/bulk-status    {"entity_ids": [...], "status": "archived"}
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// EntityStatus is a page's lifecycle state.
+type EntityStatus string
+
+const (
+	// StatusDraft pages are visible only to their creator and the owner.
+	StatusDraft EntityStatus = "draft"
+	// StatusPending pages await Scribe+ approval; only their creator and
+	// approvers can see them.
+	StatusPending EntityStatus = "pending"
+	// StatusPublished pages follow the normal visibility rules.
+	StatusPublished EntityStatus = "published"
+	// StatusArchived pages are visible but hidden from lists and search
+	// by default.
+	StatusArchived EntityStatus = "archived"
+)
+
+// StatusFilterActive is the ListOptions.Status value that hides archived
+// pages, the default for the list and search pages.
+const StatusFilterActive = "active"
+
+// maxBulkStatus caps the pages one bulk status change may touch.
+const maxBulkStatus = 100
+
+// IsValid reports whether s is a known lifecycle state.
+func (s EntityStatus) IsValid() bool {
+	switch s {
+	case StatusDraft, StatusPending, StatusPublished, StatusArchived:
+		return true
+	}
+	return false
+}
+
+// Label is the state's display name.
+func (s EntityStatus) Label() string {
+	switch s {
+	case StatusDraft:
+		return "Draft"
+	case StatusPending:
+		return "Pending review"
+	case StatusArchived:
+		return "Archived"
+	default:
+		return "Published"
+	}
+}
+
+// IsDraft reports whether the page is still a draft.
+func (e *Entity) IsDraft() bool { return e.Status == StatusDraft }
+
+// IsPending reports whether the page is awaiting approval.
+func (e *Entity) IsPending() bool { return e.Status == StatusPending }
+
+// IsArchived reports whether the page has been archived.
+func (e *Entity) IsArchived() bool { return e.Status == StatusArchived }
+
+// parseStatusFilter turns the ?status= query value into a ListOptions.Status:
+// "all" lists every state, a state name lists just that state, and anything
+// else (including no value) hides archived pages.
+func parseStatusFilter(q string) string {
+	if q == "all" {
+		return ""
+	}
+	if EntityStatus(q).IsValid() {
+		return q
+	}
+	return StatusFilterActive
+}
+
+// statusFilterParam is the ?status= value that reproduces a
+// ListOptions.Status, for links and selects; "" is the default filter.
+func statusFilterParam(filter string) string {
+	switch filter {
+	case "":
+		return "all"
+	case StatusFilterActive:
+		return ""
+	default:
+		return filter
+	}
+}
+
+// listFilterParams is the query string list pagination carries between
+// pages: the type and, when it isn't the default, the status filter.
+func listFilterParams(typeID int, filter string) string {
+	params := fmt.Sprintf("type=%d", typeID)
+	if p := statusFilterParam(filter); p != "" {
+		params += "&status=" + p
+	}
+	return params
+}
+
+// statusFilterClause returns the WHERE fragment and args for a
+// ListOptions.Status value. Drafts the viewer can't see are already
+// removed by visibilityFilter; this only narrows by state.
+func statusFilterClause(filter string) (string, []any) {
+	switch filter {
+	case "":
+		return "", nil
+	case StatusFilterActive:
+		return " AND e.status <> 'archived'", nil
+	default:
+		return " AND e.status = ?", []any{filter}
+	}
+}
+
+// SetStatus moves a page to a new lifecycle state. The page must belong to
+// campaignID (NotFound otherwise). Setting the current state is a no-op.
+func (s *entityService) SetStatus(ctx context.Context, campaignID, entityID string, status EntityStatus) (*Entity, error) {
+	if !status.IsValid() {
+		return nil, apperror.NewBadRequest("invalid page status")
+	}
+	entity, err := s.entities.FindByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if entity.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	if entity.Status == status {
+		return entity, nil
+	}
+
+	if err := s.entities.UpdateStatus(ctx, entityID, status); err != nil {
+		return nil, err
+	}
+	entity.Status = status
+
+	// Visibility changed for everyone but the creator, so clients and the
+	// sidebar need the same refresh a privacy toggle gets.
+	s.events.PublishEntityEvent("updated", entity.CampaignID, entityID, entity)
+	s.hierarchy.HierarchyChanged(entity.CampaignID)
+	return entity, nil
+}
+
+// CountDrafts returns how many of a campaign's pages are drafts. Not
+// visibility-filtered; it feeds the owner dashboard.
+func (s *entityService) CountDrafts(ctx context.Context, campaignID string) (int, error) {
+	return s.entities.CountByStatus(ctx, campaignID, StatusDraft)
+}
+
+// UpdateStatusAPI moves one page to a new lifecycle state. HTMX callers
+// are sent back to the page so its banner and buttons re-render.
+// POST /campaigns/:id/entities/:eid/status
+func (h *Handler) UpdateStatusAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	entityID := c.Param("eid")
+
+	var req struct {
+		Status string `json:"status" form:"status"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+
+	// Someone else's draft is invisible to a Scribe, so it can't be
+	// published (or even confirmed to exist) by ID either.
+	access, err := h.service.CheckEntityAccess(ctx, entityID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+
+	entity, err := h.service.SetStatus(ctx, cc.Campaign.ID, entityID, EntityStatus(req.Status))
+	if err != nil {
+		return err
+	}
+	h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, entity.ID, entity.Name,
+		map[string]any{"status": string(entity.Status)})
+
+	if middleware.IsHTMX(c) {
+		return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/entities/"+entity.ID)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// BulkStatusAPI moves several pages to one lifecycle state. Pages outside
+// the campaign or hidden from the viewer are skipped.
+// POST /campaigns/:id/entities/bulk-status
+func (h *Handler) BulkStatusAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	var req struct {
+		EntityIDs []string `json:"entity_ids"`
+		Status    string   `json:"status"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	status := EntityStatus(req.Status)
+	if !status.IsValid() {
+		return apperror.NewBadRequest("invalid page status")
+	}
+	if len(req.EntityIDs) == 0 {
+		return apperror.NewBadRequest("no entities selected")
+	}
+	if len(req.EntityIDs) > maxBulkStatus {
+		return apperror.NewBadRequest("maximum 100 entities per status change")
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	updated := 0
+	for _, eid := range req.EntityIDs {
+		access, err := h.service.CheckEntityAccess(ctx, eid, int(cc.MemberRole), userID)
+		if err != nil || !access.CanView {
+			continue
+		}
+		entity, err := h.service.SetStatus(ctx, cc.Campaign.ID, eid, status)
+		if err != nil {
+			continue
+		}
+		h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, entity.ID, entity.Name,
+			map[string]any{"status": string(status)})
+		updated++
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{"status": "ok", "updated": updated})
+}
@@ -0,0 +1,156 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+func TestParseStatusFilter(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"", StatusFilterActive},
+		{"bogus", StatusFilterActive},
+		{"all", ""},
+		{"draft", "draft"},
+		{"pending", "pending"},
+		{"published", "published"},
+		{"archived", "archived"},
+	}
+	for _, tt := range tests {
+		got := parseStatusFilter(tt.query)
+		if got != tt.want {
+			t.Errorf("parseStatusFilter(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		// The param written into links must read back as the same filter.
+		if back := parseStatusFilter(statusFilterParam(got)); back != got {
+			t.Errorf("round trip of %q gave %q", got, back)
+		}
+	}
+}
+
+func TestStatusFilterClause(t *testing.T) {
+	if frag, args := statusFilterClause(""); frag != "" || args != nil {
+		t.Errorf("no filter: got %q %v", frag, args)
+	}
+	if frag, args := statusFilterClause(StatusFilterActive); frag != " AND e.status <> 'archived'" || args != nil {
+		t.Errorf("active: got %q %v", frag, args)
+	}
+	frag, args := statusFilterClause("draft")
+	if frag != " AND e.status = ?" || len(args) != 1 || args[0] != "draft" {
+		t.Errorf("draft: got %q %v", frag, args)
+	}
+}
+
+func TestListFilterParams(t *testing.T) {
+	if got := listFilterParams(3, StatusFilterActive); got != "type=3" {
+		t.Errorf("default filter = %q", got)
+	}
+	if got := listFilterParams(0, "archived"); got != "type=0&status=archived" {
+		t.Errorf("archived = %q", got)
+	}
+	if got := listFilterParams(0, ""); got != "type=0&status=all" {
+		t.Errorf("all = %q", got)
+	}
+}
+
+func TestCreate_Status(t *testing.T) {
+	typeRepo := &mockEntityTypeRepo{
+		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
+			return &EntityType{ID: 1, CampaignID: "camp-1", Slug: "character"}, nil
+		},
+	}
+	svc := newTestService(&mockEntityRepo{}, typeRepo)
+	ctx := context.Background()
+
+	e, err := svc.Create(ctx, "camp-1", "user-1", CreateEntityInput{Name: "Tyne", EntityTypeID: 1})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if e.Status != StatusPublished {
+		t.Errorf("default status = %q, want published", e.Status)
+	}
+
+	e, err = svc.Create(ctx, "camp-1", "user-1", CreateEntityInput{Name: "Tyne", EntityTypeID: 1, Status: StatusDraft})
+	if err != nil {
+		t.Fatalf("create draft: %v", err)
+	}
+	if e.Status != StatusDraft {
+		t.Errorf("draft status = %q", e.Status)
+	}
+
+	_, err = svc.Create(ctx, "camp-1", "user-1", CreateEntityInput{Name: "Tyne", EntityTypeID: 1, Status: "bogus"})
+	assertAppError(t, err, 400)
+}
+
+func TestSetStatus(t *testing.T) {
+	var written []EntityStatus
+	repo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, CampaignID: "camp-1", Status: StatusDraft}, nil
+		},
+		updateStatusFn: func(_ context.Context, _ string, status EntityStatus) error {
+			written = append(written, status)
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	_, err := svc.SetStatus(ctx, "camp-1", "e1", "bogus")
+	assertAppError(t, err, 400)
+
+	_, err = svc.SetStatus(ctx, "camp-2", "e1", StatusPublished)
+	assertAppError(t, err, 404)
+
+	if _, err := svc.SetStatus(ctx, "camp-1", "e1", StatusDraft); err != nil {
+		t.Fatalf("same status: %v", err)
+	}
+	if len(written) != 0 {
+		t.Errorf("setting the current status wrote %v", written)
+	}
+
+	e, err := svc.SetStatus(ctx, "camp-1", "e1", StatusPublished)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if e.Status != StatusPublished || len(written) != 1 || written[0] != StatusPublished {
+		t.Errorf("publish: status %q, writes %v", e.Status, written)
+	}
+}
+
+// TestCheckEntityAccess_Draft pins the draft gate: only the creator (and
+// owners, who skip the check) can see a draft, whatever its visibility.
+func TestCheckEntityAccess_Draft(t *testing.T) {
+	repo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, CampaignID: "camp-1", Status: StatusDraft, CreatedBy: "author", Visibility: VisibilityDefault}, nil
+		},
+	}
+	svc := newTestService(repo, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		role     int
+		userID   string
+		wantView bool
+	}{
+		{"author", permissions.RoleScribe, "author", true},
+		{"other scribe", permissions.RoleScribe, "someone-else", false},
+		{"player", permissions.RolePlayer, "player-1", false},
+		{"anonymous", permissions.RoleNone, "", false},
+		{"owner", permissions.RoleOwner, "owner-1", true},
+	}
+	for _, tt := range tests {
+		ep, err := svc.CheckEntityAccess(ctx, "e1", tt.role, tt.userID)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ep.CanView != tt.wantView {
+			t.Errorf("%s: CanView = %v, want %v", tt.name, ep.CanView, tt.wantView)
+		}
+	}
+}
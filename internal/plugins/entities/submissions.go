@@ -0,0 +1,435 @@
+package entities
+
+// submissions.go — player-submitted pages. The owner opens chosen entity
+// types (backstories, journal entries) to player submissions; a page a
+// player submits there is created pending (see status.go), so only its
+// author and the Scribe+ who review it can see it. The review queue lists
+// pending pages: approving publishes one, rejecting hands it back to its
+// author as a draft. Approvers hear about new submissions, and authors
+// about the decision, through the in-app notification list.
+//
+//	GET  /campaigns/:id/entities/submit                  submission form
+//	POST /campaigns/:id/entities/submit                  create a pending page
+//	GET  /campaigns/:id/entities/review                  review queue (Scribe+)
+//	POST /campaigns/:id/entities/:eid/approve            publish a submission
+//	POST /campaigns/:id/entities/:eid/reject             {"note": "..."} return it as a draft
+//	PUT  /campaigns/:id/entity-types/:etid/submissions   {"enabled": true}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/audit"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+const (
+	// NotifSubmissionPending tells approvers a page awaits review.
+	NotifSubmissionPending = "entity_submission"
+	// NotifSubmissionReviewed tells an author their page was approved or
+	// returned.
+	NotifSubmissionReviewed = "entity_submission_reviewed"
+)
+
+const (
+	// maxSubmissionBodyLength caps a submission's text, in characters.
+	maxSubmissionBodyLength = 20000
+
+	// maxReviewNoteLength caps the note sent back with a rejection.
+	maxReviewNoteLength = 500
+
+	// reviewQueuePageSize is how many pending pages the queue shows at once.
+	reviewQueuePageSize = 50
+)
+
+// SubmitEntityInput is a player's page submission.
+type SubmitEntityInput struct {
+	Name         string
+	EntityTypeID int
+	Body         string // Plain text; blank lines separate paragraphs.
+}
+
+// SubmissionTypes returns the campaign's enabled entity types that accept
+// player submissions.
+func (s *entityService) SubmissionTypes(ctx context.Context, campaignID string) ([]EntityType, error) {
+	types, err := s.types.ListByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	var out []EntityType
+	for _, et := range types {
+		if et.Enabled && et.PlayerSubmissions {
+			out = append(out, et)
+		}
+	}
+	return out, nil
+}
+
+// Submit creates a pending page of a type open to player submissions, with
+// the body as its entry. The page is never private, so approving it shares
+// it with every player.
+func (s *entityService) Submit(ctx context.Context, campaignID, userID string, input SubmitEntityInput) (*Entity, error) {
+	et, err := s.types.FindByID(ctx, input.EntityTypeID)
+	if err != nil || et.CampaignID != campaignID || !et.Enabled || !et.PlayerSubmissions {
+		return nil, apperror.NewBadRequest("that category doesn't accept submissions")
+	}
+	body := strings.TrimSpace(input.Body)
+	if utf8.RuneCountInString(body) > maxSubmissionBodyLength {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("submission text must be at most %d characters", maxSubmissionBodyLength))
+	}
+
+	entity, err := s.Create(ctx, campaignID, userID, CreateEntityInput{
+		Name:         input.Name,
+		EntityTypeID: et.ID,
+		Status:       StatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		entryJSON, entryHTML := plainTextEntry(body)
+		if err := s.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+			return nil, err
+		}
+	}
+	return entity, nil
+}
+
+// ReviewSubmission approves (publishes) or rejects (returns to its author
+// as a draft) a pending page. The page must belong to campaignID and still
+// be pending.
+func (s *entityService) ReviewSubmission(ctx context.Context, campaignID, entityID string, approve bool) (*Entity, error) {
+	entity, err := s.entities.FindByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if entity.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	if !entity.IsPending() {
+		return nil, apperror.NewConflict("this page isn't awaiting review")
+	}
+	status := StatusDraft
+	if approve {
+		status = StatusPublished
+	}
+	return s.SetStatus(ctx, campaignID, entityID, status)
+}
+
+// UpdateEntityTypePlayerSubmissions opens or closes a type to player
+// submissions. Pages already pending stay in the review queue.
+func (s *entityService) UpdateEntityTypePlayerSubmissions(ctx context.Context, id int, enabled bool) error {
+	return s.types.UpdatePlayerSubmissions(ctx, id, enabled)
+}
+
+// blankLines splits submission text into paragraphs.
+var blankLines = regexp.MustCompile(`\n[ \t]*\n`)
+
+// plainTextEntry converts submission text into an editor document and its
+// HTML: blank lines start a new paragraph, single newlines become line
+// breaks.
+func plainTextEntry(text string) (string, string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	paragraphs := []map[string]any{}
+	var out strings.Builder
+	for _, para := range blankLines.Split(text, -1) {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		content := []map[string]any{}
+		out.WriteString("<p>")
+		for i, line := range strings.Split(para, "\n") {
+			if i > 0 {
+				content = append(content, map[string]any{"type": "hardBreak"})
+				out.WriteString("<br>")
+			}
+			if line = strings.TrimRight(line, " \t"); line != "" {
+				content = append(content, map[string]any{"type": "text", "text": line})
+				out.WriteString(html.EscapeString(line))
+			}
+		}
+		out.WriteString("</p>")
+		paragraphs = append(paragraphs, map[string]any{"type": "paragraph", "content": content})
+	}
+	doc, _ := json.Marshal(map[string]any{"type": "doc", "content": paragraphs})
+	return string(doc), out.String()
+}
+
+// hasSubmissionTypes reports whether any of the types accept player
+// submissions, so list pages know to offer the submit button.
+func hasSubmissionTypes(types []EntityType) bool {
+	for _, et := range types {
+		if et.Enabled && et.PlayerSubmissions {
+			return true
+		}
+	}
+	return false
+}
+
+// submitFormValues refills the submission form after an error.
+type submitFormValues struct {
+	Name         string
+	EntityTypeID int
+	Body         string
+}
+
+// SubmitForm renders the submission form and the viewer's pages still
+// awaiting review.
+// GET /campaigns/:id/entities/submit
+func (h *Handler) SubmitForm(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	typeID, _ := strconv.Atoi(c.QueryParam("type"))
+	return h.renderSubmitForm(c, cc, submitFormValues{EntityTypeID: typeID}, "")
+}
+
+// renderSubmitForm renders the submission page with the given values and
+// error message.
+func (h *Handler) renderSubmitForm(c echo.Context, cc *campaigns.CampaignContext, form submitFormValues, errMsg string) error {
+	ctx := c.Request().Context()
+	types, err := h.service.SubmissionTypes(ctx, cc.Campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	// The visibility filter shows a player only their own pending pages.
+	opts := ListOptions{Page: 1, PerPage: reviewQueuePageSize, Sort: "created", Status: string(StatusPending)}
+	pending, _, err := h.service.List(ctx, cc.Campaign.ID, 0, cc.VisibilityRole(), auth.GetUserID(c), opts)
+	if err != nil {
+		slog.Warn("listing pending submissions", slog.Any("error", err))
+		pending = nil
+	}
+
+	csrfToken := middleware.GetCSRFToken(c)
+	return middleware.Render(c, http.StatusOK, SubmitEntityPage(cc, types, form, pending, csrfToken, errMsg))
+}
+
+// Submit creates a pending page from the submission form and notifies the
+// campaign's approvers.
+// POST /campaigns/:id/entities/submit
+func (h *Handler) Submit(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+
+	var req struct {
+		Name         string `form:"name"`
+		EntityTypeID int    `form:"entity_type_id"`
+		Body         string `form:"body"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	form := submitFormValues{Name: req.Name, EntityTypeID: req.EntityTypeID, Body: req.Body}
+
+	if err := apperror.ValidateRequired("name", req.Name); err != nil {
+		return h.renderSubmitForm(c, cc, form, apperror.UserMessage(err, "name is required"))
+	}
+	if err := apperror.ValidateStringLength("name", req.Name, apperror.MaxNameLength); err != nil {
+		return h.renderSubmitForm(c, cc, form, apperror.UserMessage(err, "name is too long"))
+	}
+
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+	entity, err := h.service.Submit(ctx, cc.Campaign.ID, userID, SubmitEntityInput{
+		Name:         req.Name,
+		EntityTypeID: req.EntityTypeID,
+		Body:         req.Body,
+	})
+	if err != nil {
+		return h.renderSubmitForm(c, cc, form, apperror.UserMessage(err, "failed to submit page"))
+	}
+
+	h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityCreated, entity.ID, entity.Name,
+		map[string]any{"status": string(StatusPending)})
+	h.notifyApprovers(ctx, cc.Campaign.ID, entity, userID)
+
+	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/entities/"+entity.ID)
+}
+
+// notifyApprovers tells every Scribe+ member other than the author that a
+// page awaits review. Failures are logged; the submission stands.
+func (h *Handler) notifyApprovers(ctx context.Context, campaignID string, entity *Entity, authorID string) {
+	if h.notifier == nil || h.memberLister == nil {
+		return
+	}
+	members, err := h.memberLister.ListMembers(ctx, campaignID)
+	if err != nil {
+		slog.Warn("listing approvers for submission", slog.String("entity_id", entity.ID), slog.Any("error", err))
+		return
+	}
+	author := ownerDisplayNames(members)[authorID]
+	if author == "" {
+		author = "A player"
+	}
+	message := fmt.Sprintf("%s submitted %q for review", author, entity.Name)
+	link := "/campaigns/" + campaignID + "/entities/review"
+	for _, m := range members {
+		if m.Role < campaigns.RoleScribe || m.UserID == authorID {
+			continue
+		}
+		if err := h.notifier.NotifyUser(ctx, m.UserID, campaignID, NotifSubmissionPending, message, link); err != nil {
+			slog.Warn("notifying approver of submission",
+				slog.String("entity_id", entity.ID),
+				slog.String("user_id", m.UserID),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// notifyAuthor tells a submission's author it was approved or returned,
+// with the reviewer's note if any. Skipped when reviewers review their own.
+func (h *Handler) notifyAuthor(ctx context.Context, campaignID string, entity *Entity, reviewerID string, approved bool, note string) {
+	if h.notifier == nil || entity.CreatedBy == "" || entity.CreatedBy == reviewerID {
+		return
+	}
+	message := fmt.Sprintf("%q was approved and is now published", entity.Name)
+	if !approved {
+		message = fmt.Sprintf("%q was returned to you as a draft", entity.Name)
+		if note != "" {
+			message += ": " + note
+		}
+	}
+	link := "/campaigns/" + campaignID + "/entities/" + entity.ID
+	if err := h.notifier.NotifyUser(ctx, entity.CreatedBy, campaignID, NotifSubmissionReviewed, message, link); err != nil {
+		slog.Warn("notifying author of review",
+			slog.String("entity_id", entity.ID),
+			slog.Any("error", err))
+	}
+}
+
+// ReviewQueue lists the campaign's pages awaiting review, newest first.
+// GET /campaigns/:id/entities/review
+func (h *Handler) ReviewQueue(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+
+	opts := ListOptions{Page: 1, PerPage: reviewQueuePageSize, Sort: "created", Status: string(StatusPending)}
+	pending, total, err := h.service.List(ctx, cc.Campaign.ID, 0, cc.VisibilityRole(), auth.GetUserID(c), opts)
+	if err != nil {
+		return err
+	}
+
+	authors := map[string]string{}
+	if h.memberLister != nil {
+		if members, err := h.memberLister.ListMembers(ctx, cc.Campaign.ID); err == nil {
+			authors = ownerDisplayNames(members)
+		} else {
+			slog.Warn("listing members for review queue", slog.Any("error", err))
+		}
+	}
+
+	csrfToken := middleware.GetCSRFToken(c)
+	return middleware.Render(c, http.StatusOK, ReviewQueuePage(cc, pending, total, authors, csrfToken))
+}
+
+// ApproveSubmissionAPI publishes a pending page.
+// POST /campaigns/:id/entities/:eid/approve
+func (h *Handler) ApproveSubmissionAPI(c echo.Context) error {
+	return h.reviewSubmission(c, true)
+}
+
+// RejectSubmissionAPI returns a pending page to its author as a draft.
+// POST /campaigns/:id/entities/:eid/reject
+func (h *Handler) RejectSubmissionAPI(c echo.Context) error {
+	return h.reviewSubmission(c, false)
+}
+
+// reviewSubmission applies a review decision, records it and tells the
+// author. HTMX callers go back where they came from: the queue when the
+// form says so, else the page.
+func (h *Handler) reviewSubmission(c echo.Context, approve bool) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	entityID := c.Param("eid")
+
+	var req struct {
+		Note   string `json:"note" form:"note"`
+		Return string `json:"return" form:"return"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request")
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxReviewNoteLength {
+		return apperror.NewBadRequest(fmt.Sprintf("note must be at most %d characters", maxReviewNoteLength))
+	}
+
+	userID := auth.GetUserID(c)
+	access, err := h.service.CheckEntityAccess(ctx, entityID, int(cc.MemberRole), userID)
+	if err != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+
+	entity, err := h.service.ReviewSubmission(ctx, cc.Campaign.ID, entityID, approve)
+	if err != nil {
+		return err
+	}
+
+	details := map[string]any{"status": string(entity.Status), "review": "approved"}
+	if !approve {
+		details["review"] = "rejected"
+		if note != "" {
+			details["note"] = note
+		}
+	}
+	h.logAuditWithDetails(c, cc.Campaign.ID, audit.ActionEntityUpdated, entity.ID, entity.Name, details)
+	h.notifyAuthor(ctx, cc.Campaign.ID, entity, userID, approve, note)
+
+	if middleware.IsHTMX(c) {
+		if req.Return == "queue" {
+			return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/entities/review")
+		}
+		return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/entities/"+entity.ID)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": string(entity.Status)})
+}
+
+// UpdateEntityTypeSubmissions opens or closes the type to player
+// submissions.
+// PUT /campaigns/:id/entity-types/:etid/submissions
+func (h *Handler) UpdateEntityTypeSubmissions(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	et, err := h.campaignEntityType(c, cc)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+
+	if err := h.service.UpdateEntityTypePlayerSubmissions(c.Request().Context(), et.ID, body.Enabled); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
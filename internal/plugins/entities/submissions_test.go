@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+func TestPlainTextEntry(t *testing.T) {
+	doc, html := plainTextEntry("Born in <Waterdeep>.\r\nRaised by wolves.\n  \nLeft home at 16.")
+	wantHTML := "<p>Born in &lt;Waterdeep&gt;.<br>Raised by wolves.</p><p>Left home at 16.</p>"
+	if html != wantHTML {
+		t.Errorf("html = %q, want %q", html, wantHTML)
+	}
+	wantDoc := `{"content":[{"content":[{"text":"Born in \u003cWaterdeep\u003e.","type":"text"},{"type":"hardBreak"},{"text":"Raised by wolves.","type":"text"}],"type":"paragraph"},{"content":[{"text":"Left home at 16.","type":"text"}],"type":"paragraph"}],"type":"doc"}`
+	if doc != wantDoc {
+		t.Errorf("doc = %s", doc)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	types := map[int]*EntityType{
+		1: {ID: 1, CampaignID: "camp-1", Slug: "backstory", Enabled: true, PlayerSubmissions: true},
+		2: {ID: 2, CampaignID: "camp-1", Slug: "location", Enabled: true},
+		3: {ID: 3, CampaignID: "camp-2", Slug: "backstory", Enabled: true, PlayerSubmissions: true},
+	}
+	typeRepo := &mockEntityTypeRepo{
+		findByIDFn: func(_ context.Context, id int) (*EntityType, error) {
+			return types[id], nil
+		},
+	}
+	var entryHTML string
+	repo := &mockEntityRepo{
+		updateEntryFn: func(_ context.Context, _, _, html string) error {
+			entryHTML = html
+			return nil
+		},
+	}
+	svc := newTestService(repo, typeRepo)
+	ctx := context.Background()
+
+	e, err := svc.Submit(ctx, "camp-1", "player-1", SubmitEntityInput{Name: "My Past", EntityTypeID: 1, Body: "Once upon a time."})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if e.Status != StatusPending || e.CreatedBy != "player-1" || e.IsPrivate {
+		t.Errorf("submitted page: status %q, creator %q, private %v", e.Status, e.CreatedBy, e.IsPrivate)
+	}
+	if entryHTML != "<p>Once upon a time.</p>" {
+		t.Errorf("entry = %q", entryHTML)
+	}
+
+	// Closed types and other campaigns' types are refused.
+	_, err = svc.Submit(ctx, "camp-1", "player-1", SubmitEntityInput{Name: "Town", EntityTypeID: 2})
+	assertAppError(t, err, 400)
+	_, err = svc.Submit(ctx, "camp-1", "player-1", SubmitEntityInput{Name: "Town", EntityTypeID: 3})
+	assertAppError(t, err, 400)
+}
+
+func TestReviewSubmission(t *testing.T) {
+	status := StatusPending
+	repo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, CampaignID: "camp-1", Status: status}, nil
+		},
+		updateStatusFn: func(_ context.Context, _ string, s EntityStatus) error {
+			status = s
+			return nil
+		},
+	}
+	svc := newTestService(repo, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	_, err := svc.ReviewSubmission(ctx, "camp-2", "e1", true)
+	assertAppError(t, err, 404)
+
+	e, err := svc.ReviewSubmission(ctx, "camp-1", "e1", false)
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if e.Status != StatusDraft {
+		t.Errorf("rejected status = %q, want draft", e.Status)
+	}
+
+	// Only pending pages can be reviewed.
+	_, err = svc.ReviewSubmission(ctx, "camp-1", "e1", true)
+	assertAppError(t, err, 409)
+
+	status = StatusPending
+	if e, err = svc.ReviewSubmission(ctx, "camp-1", "e1", true); err != nil || e.Status != StatusPublished {
+		t.Errorf("approve: %v, status %q", err, e.Status)
+	}
+}
+
+// TestCheckEntityAccess_Pending pins the pending gate: the author and
+// Scribe+ approvers see a submission; other players never do.
+func TestCheckEntityAccess_Pending(t *testing.T) {
+	repo := &mockEntityRepo{
+		findByIDFn: func(_ context.Context, id string) (*Entity, error) {
+			return &Entity{ID: id, CampaignID: "camp-1", Status: StatusPending, CreatedBy: "author", Visibility: VisibilityDefault}, nil
+		},
+	}
+	svc := newTestService(repo, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		role     int
+		userID   string
+		wantView bool
+	}{
+		{"author", permissions.RolePlayer, "author", true},
+		{"scribe", permissions.RoleScribe, "scribe-1", true},
+		{"other player", permissions.RolePlayer, "player-2", false},
+		{"anonymous", permissions.RoleNone, "", false},
+	}
+	for _, tt := range tests {
+		ep, err := svc.CheckEntityAccess(ctx, "e1", tt.role, tt.userID)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ep.CanView != tt.wantView {
+			t.Errorf("%s: CanView = %v, want %v", tt.name, ep.CanView, tt.wantView)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package entities
+
+// summary_card.go — printable one-page summary cards: an entity's image, key
+// fields and an entry excerpt laid out to fit a printed page or a shared
+// screen. A card is a handout, so inline secrets are dropped for every role
+// (as in entry.txt), and a GM can ask for the player version (?view=player)
+// to leave GM-only fields off too. The batch view prints every pinned page
+// of a type in one go.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+const (
+	// summaryCardFieldLimit caps the fields on a card so it stays one page.
+	summaryCardFieldLimit = 8
+
+	// summaryCardExcerptLimit is the entry excerpt length in characters.
+	summaryCardExcerptLimit = 600
+)
+
+// SummaryCardField is one labelled value on a card.
+type SummaryCardField struct {
+	Label string
+	Value string
+}
+
+// SummaryCard is the printable digest of one entity.
+type SummaryCard struct {
+	ID        string
+	Name      string
+	TypeName  string
+	TypeLabel string
+	TypeIcon  string
+	TypeColor string
+	ImageURL  string
+	ImageAlt  string
+	Fields    []SummaryCardField
+	Excerpt   string
+}
+
+// SummaryCardsView is the data for the printable cards page.
+type SummaryCardsView struct {
+	Title      string
+	BackURL    string
+	Cards      []SummaryCard
+	CanSeeGM   bool // Viewer is Scribe+ and may switch to the player version.
+	PlayerView bool
+}
+
+// buildSummaryCard picks a card's contents for a viewer. Fields follow the
+// type's order, skipping empty values, unticked checkboxes and textareas
+// (long prose belongs in the excerpt). et may be nil if the type was
+// deleted mid-request.
+func buildSummaryCard(e *Entity, et *EntityType, canSeeGM bool, userID string) SummaryCard {
+	card := SummaryCard{
+		ID:        e.ID,
+		Name:      e.Name,
+		TypeName:  e.TypeName,
+		TypeIcon:  e.TypeIcon,
+		TypeColor: e.TypeColor,
+	}
+	if et != nil {
+		card.TypeName, card.TypeIcon, card.TypeColor = et.Name, et.Icon, et.Color
+	}
+	if e.TypeLabel != nil {
+		card.TypeLabel = *e.TypeLabel
+	}
+	if img := e.CardImagePath(); img != "" {
+		card.ImageURL = "/media/" + img
+		card.ImageAlt = e.HeaderImageAlt()
+	}
+	if e.EntryHTML != nil && *e.EntryHTML != "" {
+		card.Excerpt = EntryExcerpt(sanitize.StripSecretsHTML(*e.EntryHTML), summaryCardExcerptLimit)
+	}
+	if et == nil {
+		return card
+	}
+
+	fieldsData := FilterRestrictedFields(e.FieldsData, et.Fields, canSeeGM, e.IsOwnedBy(userID))
+	for _, fd := range MergeFields(et.Fields, e.FieldOverrides) {
+		if fd.Type == "textarea" {
+			continue
+		}
+		raw, ok := fieldsData[fd.Key]
+		if !ok || raw == nil {
+			continue
+		}
+		val := fmt.Sprintf("%v", raw)
+		if fd.Type == "checkbox" {
+			if b, err := strconv.ParseBool(val); err != nil || !b {
+				continue
+			}
+			val = "Yes"
+		}
+		if val == "" {
+			continue
+		}
+		card.Fields = append(card.Fields, SummaryCardField{Label: fd.Label, Value: val})
+		if len(card.Fields) == summaryCardFieldLimit {
+			break
+		}
+	}
+	return card
+}
+
+// summaryCardAudience resolves who the card is for: GM-only fields show to
+// Scribe+ unless they asked for the player version.
+func summaryCardAudience(c echo.Context, cc *campaigns.CampaignContext) (canSeeGM, playerView bool) {
+	isGM := cc.MemberRole >= campaigns.RoleScribe
+	playerView = isGM && c.QueryParam("view") == "player"
+	return isGM && !playerView, playerView
+}
+
+// EntityCard renders one entity's printable card.
+// GET /campaigns/:id/entities/:eid/card
+func (h *Handler) EntityCard(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+
+	entity, err := h.service.GetByID(ctx, c.Param("eid"))
+	if err != nil {
+		return err
+	}
+	// IDOR protection: verify entity belongs to the campaign in the URL.
+	if entity.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity not found")
+	}
+	access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), userID)
+	if err != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	if err != nil {
+		et = nil
+	}
+
+	canSeeGM, playerView := summaryCardAudience(c, cc)
+	cards := []SummaryCard{buildSummaryCard(entity, et, canSeeGM, userID)}
+	page := SummaryCardsView{
+		Title:      entity.Name,
+		BackURL:    fmt.Sprintf("/campaigns/%s/entities/%s", cc.Campaign.ID, entity.ID),
+		Cards:      cards,
+		CanSeeGM:   cc.MemberRole >= campaigns.RoleScribe,
+		PlayerView: playerView,
+	}
+	return middleware.Render(c, http.StatusOK, SummaryCardsPage(page))
+}
+
+// EntityTypeCards renders cards for every pinned page of a type, in pin
+// order. Pages the viewer can't see, or that were deleted, are skipped.
+// GET /campaigns/:id/entity-types/:etid/cards
+func (h *Handler) EntityTypeCards(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	ctx := c.Request().Context()
+	userID := auth.GetUserID(c)
+
+	etID, err := strconv.Atoi(c.Param("etid"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid entity type ID")
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, etID)
+	if err != nil {
+		return err
+	}
+	if et.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("entity type not found")
+	}
+
+	canSeeGM, playerView := summaryCardAudience(c, cc)
+	cards := h.pinnedSummaryCards(ctx, cc, et, canSeeGM, userID)
+	page := SummaryCardsView{
+		Title:      "Pinned " + et.NamePlural,
+		BackURL:    fmt.Sprintf("/campaigns/%s/%s", cc.Campaign.ID, et.Slug),
+		Cards:      cards,
+		CanSeeGM:   cc.MemberRole >= campaigns.RoleScribe,
+		PlayerView: playerView,
+	}
+	return middleware.Render(c, http.StatusOK, SummaryCardsPage(page))
+}
+
+// pinnedSummaryCards builds the visible pinned pages' cards.
+func (h *Handler) pinnedSummaryCards(ctx context.Context, cc *campaigns.CampaignContext, et *EntityType, canSeeGM bool, userID string) []SummaryCard {
+	var cards []SummaryCard
+	for _, id := range et.PinnedEntityIDs {
+		entity, err := h.service.GetByID(ctx, id)
+		// The dashboard only shows pins among the type's own pages.
+		if err != nil || entity.CampaignID != cc.Campaign.ID || entity.EntityTypeID != et.ID {
+			continue
+		}
+		access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), userID)
+		if err != nil || !access.CanView {
+			continue
+		}
+		cards = append(cards, buildSummaryCard(entity, et, canSeeGM, userID))
+	}
+	return cards
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildSummaryCard(t *testing.T) {
+	entry := `<p>Ireena is the burgomaster's daughter.</p><p><span data-secret="true">She is Tatyana reborn.</span></p>`
+	image := "abc/ireena.png"
+	et := &EntityType{
+		ID: 3, Name: "NPC", Icon: "fa-user",
+		Fields: []FieldDefinition{
+			{Key: "race", Label: "Race", Type: "text"},
+			{Key: "bio", Label: "Biography", Type: "textarea"},
+			{Key: "alive", Label: "Alive", Type: "checkbox"},
+			{Key: "cursed", Label: "Cursed", Type: "checkbox"},
+			{Key: "motive", Label: "True motive", Type: "text", GMOnly: true},
+			{Key: "age", Label: "Age", Type: "number"},
+		},
+	}
+	e := &Entity{
+		ID: "e1", Name: "Ireena", EntityTypeID: 3, EntryHTML: &entry, ImagePath: &image,
+		FieldsData: map[string]any{
+			"race": "Human", "bio": "Long story", "alive": "true", "cursed": "false",
+			"motive": "Escape Strahd", "age": "",
+		},
+	}
+
+	cases := []struct {
+		name     string
+		canSeeGM bool
+		want     []string
+	}{
+		{"player", false, []string{"Race=Human", "Alive=Yes"}},
+		{"gm", true, []string{"Race=Human", "Alive=Yes", "True motive=Escape Strahd"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			card := buildSummaryCard(e, et, tc.canSeeGM, "")
+			var got []string
+			for _, f := range card.Fields {
+				got = append(got, f.Label+"="+f.Value)
+			}
+			if strings.Join(got, ";") != strings.Join(tc.want, ";") {
+				t.Errorf("fields = %v; want %v", got, tc.want)
+			}
+			if strings.Contains(card.Excerpt, "Tatyana") || !strings.Contains(card.Excerpt, "burgomaster") {
+				t.Errorf("excerpt = %q; want prose without the secret", card.Excerpt)
+			}
+			if card.ImageURL != "/media/abc/ireena.png" || card.TypeName != "NPC" {
+				t.Errorf("image %q, type %q", card.ImageURL, card.TypeName)
+			}
+		})
+	}
+}
+
+func TestBuildSummaryCard_FieldLimit(t *testing.T) {
+	et := &EntityType{ID: 1}
+	data := map[string]any{}
+	for i := 0; i < summaryCardFieldLimit+4; i++ {
+		key := string(rune('a' + i))
+		et.Fields = append(et.Fields, FieldDefinition{Key: key, Label: key, Type: "text"})
+		data[key] = "x"
+	}
+	card := buildSummaryCard(&Entity{ID: "e", FieldsData: data}, et, true, "")
+	if len(card.Fields) != summaryCardFieldLimit {
+		t.Errorf("got %d fields; want %d", len(card.Fields), summaryCardFieldLimit)
+	}
+}
@@ -0,0 +1,237 @@
+package entities
+
+// type_access.go — per-type co-editing for Players. Creating and editing
+// pages is Scribe+ by default; the owner can open a type to Players on its
+// config page. players_can_create lets Players create pages of the type
+// (and edit the ones they created); players_can_edit lets them edit any
+// page of the type they can see. Only the page itself opens up: name,
+// descriptor, parent, entry, fields, images and aliases. Visibility, status,
+// layout, ownership, bulk actions and delete stay Scribe+ or Owner.
+//
+// A Player's save never touches what they can't see: GM-only and
+// owner-only field values are carried over from the stored page, and an
+// entry holding inline GM secrets can't be edited by them at all (saving
+// the stripped copy they were served would delete the secrets).
+//
+//	PUT /campaigns/:id/entity-types/:etid/player-access  {"can_create": true, "can_edit": false}
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// playerMayCreate reports whether Players may create pages of the type.
+func playerMayCreate(et *EntityType) bool {
+	return et != nil && et.Enabled && et.PlayersCanCreate
+}
+
+// playerMayEdit reports whether a Player may edit the page: its type is
+// open to player editing, or open to player creation and they created it.
+// Visibility is checked separately.
+func playerMayEdit(et *EntityType, entity *Entity, userID string) bool {
+	if et == nil || userID == "" {
+		return false
+	}
+	return et.PlayersCanEdit || (et.PlayersCanCreate && entity.CreatedBy == userID)
+}
+
+// creatableTypes returns the types the viewer may create pages of: all of
+// them for Scribe+, only those open to player creation otherwise.
+func creatableTypes(cc *campaigns.CampaignContext, types []EntityType) []EntityType {
+	if cc.MemberRole >= campaigns.RoleScribe {
+		return types
+	}
+	var out []EntityType
+	for i := range types {
+		if playerMayCreate(&types[i]) {
+			out = append(out, types[i])
+		}
+	}
+	return out
+}
+
+// hasInlineSecrets reports whether the page's entry holds GM-only inline
+// secrets, which GetEntry strips for Players.
+func hasInlineSecrets(entity *Entity) bool {
+	return entity.EntryHTML != nil && sanitize.StripSecretsHTML(*entity.EntryHTML) != *entity.EntryHTML
+}
+
+// KeepRestrictedFields returns incoming with every field value the caller
+// can't see replaced by the stored value (or dropped when none is stored),
+// so a save built from FilterRestrictedFields output can't erase or
+// overwrite GM-only or owner-only values. Like the filters it never
+// mutates its inputs and returns incoming as-is when nothing is restricted.
+func KeepRestrictedFields(incoming, stored map[string]any, defs []FieldDefinition, canSeeGM, isOwner bool) map[string]any {
+	if canSeeGM || len(defs) == 0 {
+		return incoming
+	}
+
+	var restricted []string
+	for i := range defs {
+		if defs[i].GMOnly || (defs[i].OwnerOnly && !isOwner) {
+			restricted = append(restricted, defs[i].Key)
+		}
+	}
+	if len(restricted) == 0 {
+		return incoming
+	}
+
+	out := make(map[string]any, len(incoming)+len(restricted))
+	for k, v := range incoming {
+		out[k] = v
+	}
+	for _, k := range restricted {
+		if v, ok := stored[k]; ok {
+			out[k] = v
+		} else {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+// requireTypeEdit replaces RequireRole(RoleScribe) on the routes that edit
+// a page's own content. Scribe+ always pass; a Player passes when the
+// :eid page is one they can see and playerMayEdit allows. Pages in other
+// campaigns or hidden from the Player are NotFound, as on every other
+// entity route.
+func (h *Handler) requireTypeEdit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cc := campaigns.GetCampaignContext(c)
+			if cc == nil {
+				return apperror.NewMissingContext()
+			}
+			if cc.MemberRole >= campaigns.RoleScribe {
+				return next(c)
+			}
+			if cc.MemberRole < campaigns.RolePlayer {
+				return apperror.NewForbidden("insufficient permissions")
+			}
+
+			ctx := c.Request().Context()
+			userID := auth.GetUserID(c)
+			entity, err := h.service.GetByID(ctx, c.Param("eid"))
+			if err != nil {
+				return err
+			}
+			if entity.CampaignID != cc.Campaign.ID {
+				return apperror.NewNotFound("entity not found")
+			}
+			access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), userID)
+			if err != nil || !access.CanView {
+				return apperror.NewNotFound("entity not found")
+			}
+			et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+			if err != nil || !playerMayEdit(et, entity, userID) {
+				return apperror.NewForbidden("insufficient permissions")
+			}
+			return next(c)
+		}
+	}
+}
+
+// checkPlayerCreate refuses a Player's new page unless its type is open to
+// player creation. Scribe+ always pass.
+func (h *Handler) checkPlayerCreate(ctx context.Context, cc *campaigns.CampaignContext, entityTypeID int) error {
+	if cc.MemberRole >= campaigns.RoleScribe {
+		return nil
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, entityTypeID)
+	if err != nil || et.CampaignID != cc.Campaign.ID || !playerMayCreate(et) {
+		return apperror.NewForbidden("players can't create pages of this type")
+	}
+	return nil
+}
+
+// checkPlayerEntryEdit refuses a Player's entry save when the stored entry
+// holds inline secrets they were never served.
+func checkPlayerEntryEdit(cc *campaigns.CampaignContext, entity *Entity) error {
+	if cc.MemberRole < campaigns.RoleScribe && hasInlineSecrets(entity) {
+		return apperror.NewConflict("this text has GM secrets; only a Scribe or the owner can edit it")
+	}
+	return nil
+}
+
+// keepPlayerHiddenFields carries the stored GM-only and owner-only values
+// into a Player's field save. Scribe+ saves pass through untouched.
+func (h *Handler) keepPlayerHiddenFields(ctx context.Context, cc *campaigns.CampaignContext, entity *Entity, userID string, fields map[string]any) (map[string]any, error) {
+	if cc.MemberRole >= campaigns.RoleScribe {
+		return fields, nil
+	}
+	et, err := h.service.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return KeepRestrictedFields(fields, entity.FieldsData, et.Fields, false, entity.IsOwnedBy(userID)), nil
+}
+
+// playerEditKey is the private context key marking a show page a Player
+// may edit, injected by renderShow like the effective-visibility glance.
+type playerEditKey struct{}
+
+// withPlayerEdit returns a context marking the page as editable by the
+// Player viewing it.
+func withPlayerEdit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, playerEditKey{}, true)
+}
+
+// canEditPage reports whether the show page renders its edit controls:
+// Scribe+, or a Player the page's type is open to.
+func canEditPage(ctx context.Context, cc *campaigns.CampaignContext) bool {
+	if cc.MemberRole >= campaigns.RoleScribe {
+		return true
+	}
+	ok, _ := ctx.Value(playerEditKey{}).(bool)
+	return ok
+}
+
+// canEditEntry is canEditPage for the entry editor, which stays read-only
+// for a Player when the entry holds inline secrets.
+func canEditEntry(ctx context.Context, cc *campaigns.CampaignContext, entity *Entity) bool {
+	if cc.MemberRole >= campaigns.RoleScribe {
+		return true
+	}
+	return canEditPage(ctx, cc) && !hasInlineSecrets(entity)
+}
+
+// UpdateEntityTypePlayerAccess opens or closes a type to player creating
+// and editing.
+func (s *entityService) UpdateEntityTypePlayerAccess(ctx context.Context, id int, canCreate, canEdit bool) error {
+	return s.types.UpdatePlayerAccess(ctx, id, canCreate, canEdit)
+}
+
+// UpdateEntityTypePlayerAccess sets whether Players may create and edit
+// pages of the type.
+// PUT /campaigns/:id/entity-types/:etid/player-access
+func (h *Handler) UpdateEntityTypePlayerAccess(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	et, err := h.campaignEntityType(c, cc)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		CanCreate bool `json:"can_create"`
+		CanEdit   bool `json:"can_edit"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+
+	if err := h.service.UpdateEntityTypePlayerAccess(c.Request().Context(), et.ID, body.CanCreate, body.CanEdit); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+func TestKeepRestrictedFields(t *testing.T) {
+	defs := []FieldDefinition{
+		{Key: "might"},
+		{Key: "gm_notes", GMOnly: true},
+		{Key: "backstory", OwnerOnly: true},
+	}
+	stored := map[string]any{"might": 1, "gm_notes": "secret", "backstory": "wolves"}
+
+	// A player's save (built from the filtered copy) tries to clear and
+	// overwrite restricted values; the stored ones win.
+	incoming := map[string]any{"might": 3, "backstory": "forged"}
+	got := KeepRestrictedFields(incoming, stored, defs, false, false)
+	if got["might"] != 3 || got["gm_notes"] != "secret" || got["backstory"] != "wolves" {
+		t.Errorf("player save = %v", got)
+	}
+	if incoming["backstory"] != "forged" {
+		t.Error("input map was mutated")
+	}
+
+	// The claimed owner may edit their own owner-only field.
+	got = KeepRestrictedFields(incoming, stored, defs, false, true)
+	if got["backstory"] != "forged" || got["gm_notes"] != "secret" {
+		t.Errorf("owner save = %v", got)
+	}
+
+	// A restricted key with nothing stored is dropped, not invented.
+	got = KeepRestrictedFields(map[string]any{"gm_notes": "injected"}, map[string]any{}, defs, false, true)
+	if _, ok := got["gm_notes"]; ok {
+		t.Errorf("injected gm_notes kept: %v", got)
+	}
+
+	if got := KeepRestrictedFields(incoming, stored, defs, true, false); got["backstory"] != "forged" {
+		t.Errorf("GM save was altered: %v", got)
+	}
+}
+
+func TestPlayerMayEdit(t *testing.T) {
+	own := &Entity{CreatedBy: "player-1"}
+	tests := []struct {
+		name string
+		et   *EntityType
+		want bool
+	}{
+		{"closed type", &EntityType{}, false},
+		{"edit open", &EntityType{PlayersCanEdit: true}, true},
+		{"create open, own page", &EntityType{PlayersCanCreate: true}, true},
+	}
+	for _, tt := range tests {
+		if got := playerMayEdit(tt.et, own, "player-1"); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if playerMayEdit(&EntityType{PlayersCanCreate: true}, own, "player-2") {
+		t.Error("create-only type let a player edit someone else's page")
+	}
+}
+
+func TestCreatableTypes(t *testing.T) {
+	types := []EntityType{
+		{ID: 1, Enabled: true, PlayersCanCreate: true},
+		{ID: 2, Enabled: true},
+		{ID: 3, PlayersCanCreate: true},
+	}
+	player := &campaigns.CampaignContext{MemberRole: campaigns.RolePlayer}
+	if got := creatableTypes(player, types); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("player types = %v", got)
+	}
+	scribe := &campaigns.CampaignContext{MemberRole: campaigns.RoleScribe}
+	if got := creatableTypes(scribe, types); len(got) != 3 {
+		t.Errorf("scribe types = %d, want 3", len(got))
+	}
+}
+
+func TestHasInlineSecrets(t *testing.T) {
+	plain := "<p>Open text.</p>"
+	secret := `<p>Open <span data-secret="true">hidden</span> text.</p>`
+	if hasInlineSecrets(&Entity{EntryHTML: &plain}) || hasInlineSecrets(&Entity{}) {
+		t.Error("plain entry reported secrets")
+	}
+	if !hasInlineSecrets(&Entity{EntryHTML: &secret}) {
+		t.Error("secret span missed")
+	}
+}
+
+// TestRequireTypeEdit pins the edit-route gate: Scribe+ always pass, a
+// Player only on a type open to them.
+func TestRequireTypeEdit(t *testing.T) {
+	ent := &Entity{ID: "e1", CampaignID: "c1", EntityTypeID: 7, CreatedBy: "player-1"}
+	tests := []struct {
+		name   string
+		role   campaigns.Role
+		et     *EntityType
+		wantOK bool
+	}{
+		{"scribe on closed type", campaigns.RoleScribe, &EntityType{ID: 7}, true},
+		{"player on closed type", campaigns.RolePlayer, &EntityType{ID: 7}, false},
+		{"player on edit-open type", campaigns.RolePlayer, &EntityType{ID: 7, PlayersCanEdit: true}, true},
+		{"viewer on edit-open type", campaigns.RoleNone, &EntityType{ID: 7, PlayersCanEdit: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{service: &stubSvcForFields{entity: ent, etype: tt.et}}
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/campaigns/c1/entities/e1/entry", nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+			c.SetParamNames("id", "eid")
+			c.SetParamValues("c1", "e1")
+			c.Set("campaign_context", &campaigns.CampaignContext{
+				Campaign:   &campaigns.Campaign{ID: "c1"},
+				MemberRole: tt.role,
+			})
+			auth.SetSession(c, &auth.Session{UserID: "player-2"})
+
+			reached := false
+			err := h.requireTypeEdit()(func(echo.Context) error {
+				reached = true
+				return nil
+			})(c)
+			if reached != tt.wantOK {
+				t.Errorf("reached = %v, want %v (err %v)", reached, tt.wantOK, err)
+			}
+			if !tt.wantOK && apperror.SafeCode(err) != http.StatusForbidden {
+				t.Errorf("err = %v, want 403", err)
+			}
+		})
+	}
+}
+
+// stubSvcForTypes serves a fixed entity type list.
+type stubSvcForTypes struct {
+	EntityService
+	types []EntityType
+}
+
+func (s *stubSvcForTypes) GetEntityTypes(_ context.Context, _ string) ([]EntityType, error) {
+	return s.types, nil
+}
+
+// TestQuickSwitchActions_PlayerCreate pins the switcher's "New <type>"
+// actions to the same per-type rule as the new-page form.
+func TestQuickSwitchActions_PlayerCreate(t *testing.T) {
+	h := &Handler{service: &stubSvcForTypes{types: []EntityType{
+		{ID: 1, Slug: "npc", Name: "NPC", Enabled: true, PlayersCanCreate: true},
+		{ID: 2, Slug: "location", Name: "Location", Enabled: true},
+	}}}
+	newActions := func(role campaigns.Role) []string {
+		cc := &campaigns.CampaignContext{Campaign: &campaigns.Campaign{ID: "c1"}, MemberRole: role}
+		var ids []string
+		for _, a := range h.quickSwitchActions(context.Background(), cc) {
+			if strings.HasPrefix(a.ID, "new-") {
+				ids = append(ids, a.ID)
+			}
+		}
+		return ids
+	}
+	if got := newActions(campaigns.RolePlayer); len(got) != 1 || got[0] != "new-npc" {
+		t.Errorf("player new actions = %v, want [new-npc]", got)
+	}
+	if got := newActions(campaigns.RoleScribe); len(got) != 2 {
+		t.Errorf("scribe new actions = %v, want both types", got)
+	}
+}
@@ -0,0 +1,185 @@
+package entities
+
+// type_defaults.go — per-type fallbacks for pages without a header image
+// of their own. A type can set a default image, and picks how a page with
+// no image at all is drawn: the type icon (the original look) or the page's
+// initials on the type color. Cards, tooltip previews and map marker popups
+// use these; the page itself keeps its "Add Image" prompt so the default
+// never hides that a page has no picture yet.
+//
+//	PUT /campaigns/:id/entity-types/:etid/default-image   {"image_path": ""} clears
+//	PUT /campaigns/:id/entity-types/:etid/avatar-style    {"style": "initials"}
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Avatar styles for pages with no image.
+const (
+	AvatarStyleIcon     = "icon"
+	AvatarStyleInitials = "initials"
+)
+
+// CardImagePath is the image to show for the page in cards, previews and
+// map markers: its own header image, else its type's default; "" when
+// neither is set.
+func (e *Entity) CardImagePath() string {
+	if e.ImagePath != nil && *e.ImagePath != "" {
+		return *e.ImagePath
+	}
+	if e.TypeDefaultImage != nil {
+		return *e.TypeDefaultImage
+	}
+	return ""
+}
+
+// HasOwnImage reports whether the page has a header image of its own, as
+// opposed to its type's default.
+func (e *Entity) HasOwnImage() bool {
+	return e.ImagePath != nil && *e.ImagePath != ""
+}
+
+// CardInitials is the initials avatar text for a page with no image, or ""
+// when its type draws the type icon instead.
+func (e *Entity) CardInitials() string {
+	if e.TypeAvatarStyle != AvatarStyleInitials {
+		return ""
+	}
+	return NameInitials(e.Name)
+}
+
+// NameInitials returns up to two initials: the first letters of the first
+// and last words ("Gandalf the Grey" → "GG"), or the first letter of a
+// single word. Leading articles are skipped so "The Prancing Pony" is "PP".
+func NameInitials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 {
+		switch strings.ToLower(words[0]) {
+		case "the", "a", "an":
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	first := []rune(words[0])[0]
+	if len(words) == 1 {
+		return strings.ToUpper(string(first))
+	}
+	last := []rune(words[len(words)-1])[0]
+	return strings.ToUpper(string([]rune{first, last}))
+}
+
+// UpdateEntityTypeDefaultImage sets the type's default page image; "" clears
+// it.
+func (s *entityService) UpdateEntityTypeDefaultImage(ctx context.Context, id int, imagePath string) error {
+	imagePath = strings.TrimSpace(imagePath)
+	if imagePath == "" {
+		return s.types.UpdateDefaultImage(ctx, id, nil)
+	}
+	// Same rule as page header images: a media file name, never a path.
+	if strings.HasPrefix(imagePath, "/") || strings.Contains(imagePath, "..") {
+		return apperror.NewBadRequest("invalid image path")
+	}
+	if err := s.types.UpdateDefaultImage(ctx, id, &imagePath); err != nil {
+		return err
+	}
+	slog.Info("entity type default image updated",
+		slog.Int("entity_type_id", id),
+		slog.String("image_path", imagePath),
+	)
+	return nil
+}
+
+// UpdateEntityTypeAvatarStyle sets how image-less pages of the type are
+// drawn.
+func (s *entityService) UpdateEntityTypeAvatarStyle(ctx context.Context, id int, style string) error {
+	if style != AvatarStyleIcon && style != AvatarStyleInitials {
+		return apperror.NewBadRequest("avatar style must be icon or initials")
+	}
+	return s.types.UpdateAvatarStyle(ctx, id, style)
+}
+
+// campaignEntityType loads the :etid entity type, checking it belongs to
+// the campaign in the URL.
+func (h *Handler) campaignEntityType(c echo.Context, cc *campaigns.CampaignContext) (*EntityType, error) {
+	etID, err := strconv.Atoi(c.Param("etid"))
+	if err != nil {
+		return nil, apperror.NewBadRequest("invalid entity type ID")
+	}
+	et, err := h.service.GetEntityTypeByID(c.Request().Context(), etID)
+	if err != nil {
+		return nil, err
+	}
+	// IDOR protection: ensure entity type belongs to this campaign.
+	if et.CampaignID != cc.Campaign.ID {
+		return nil, apperror.NewNotFound("entity type not found")
+	}
+	return et, nil
+}
+
+// UpdateEntityTypeDefaultImage saves the type's default page image. The
+// config page uploads through the image-upload widget, which sends the
+// media file name as image_path.
+// PUT /campaigns/:id/entity-types/:etid/default-image
+func (h *Handler) UpdateEntityTypeDefaultImage(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	et, err := h.campaignEntityType(c, cc)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		ImagePath string `json:"image_path"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+
+	if err := h.service.UpdateEntityTypeDefaultImage(c.Request().Context(), et.ID, body.ImagePath); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateEntityTypeAvatarStyle saves how image-less pages of the type are
+// drawn.
+// PUT /campaigns/:id/entity-types/:etid/avatar-style
+func (h *Handler) UpdateEntityTypeAvatarStyle(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	et, err := h.campaignEntityType(c, cc)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Style string `json:"style"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+
+	if err := h.service.UpdateEntityTypeAvatarStyle(c.Request().Context(), et.ID, body.Style); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
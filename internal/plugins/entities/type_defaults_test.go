@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNameInitials(t *testing.T) {
+	cases := map[string]string{
+		"Gandalf the Grey":  "GG",
+		"gandalf":           "G",
+		"The Prancing Pony": "PP",
+		"The":               "T",
+		"Room 101":          "R1",
+		"Éowyn of Rohan":    "ÉR",
+		"  -- ":             "",
+	}
+	for name, want := range cases {
+		if got := NameInitials(name); got != want {
+			t.Errorf("NameInitials(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCardImagePath(t *testing.T) {
+	own, def, empty := "own.png", "default.png", ""
+	cases := []struct {
+		image, typeDefault *string
+		want               string
+		ownImage           bool
+	}{
+		{&own, &def, "own.png", true},
+		{nil, &def, "default.png", false},
+		{&empty, &def, "default.png", false},
+		{nil, nil, "", false},
+	}
+	for _, tc := range cases {
+		e := &Entity{ImagePath: tc.image, TypeDefaultImage: tc.typeDefault}
+		if got := e.CardImagePath(); got != tc.want {
+			t.Errorf("CardImagePath = %q, want %q", got, tc.want)
+		}
+		if got := e.HasOwnImage(); got != tc.ownImage {
+			t.Errorf("HasOwnImage = %v, want %v", got, tc.ownImage)
+		}
+	}
+}
+
+func TestBuildEntityPreview_TypeDefaults(t *testing.T) {
+	def := "default.png"
+	p := buildEntityPreview(&Entity{Name: "Goblin Boss", TypeDefaultImage: &def}, nil, 1, "")
+	if p.ImagePath != "/media/default.png" || p.ImageAlt != "" || p.Initials != "" {
+		t.Errorf("default image preview = %+v", p)
+	}
+
+	p = buildEntityPreview(&Entity{Name: "Goblin Boss", TypeAvatarStyle: AvatarStyleInitials}, nil, 1, "")
+	if p.ImagePath != "" || p.Initials != "GB" {
+		t.Errorf("initials preview = %+v", p)
+	}
+
+	p = buildEntityPreview(&Entity{Name: "Goblin Boss", TypeAvatarStyle: AvatarStyleIcon}, nil, 1, "")
+	if p.Initials != "" {
+		t.Errorf("icon style preview has initials %q", p.Initials)
+	}
+}
+
+func TestUpdateEntityTypeDefaults_Validation(t *testing.T) {
+	svc := newTestService(&mockEntityRepo{}, &mockEntityTypeRepo{})
+	ctx := context.Background()
+
+	assertAppError(t, svc.UpdateEntityTypeDefaultImage(ctx, 1, "../secret.png"), 400)
+	assertAppError(t, svc.UpdateEntityTypeDefaultImage(ctx, 1, "/etc/passwd"), 400)
+	if err := svc.UpdateEntityTypeDefaultImage(ctx, 1, "abc.png"); err != nil {
+		t.Errorf("valid image: %v", err)
+	}
+	if err := svc.UpdateEntityTypeDefaultImage(ctx, 1, ""); err != nil {
+		t.Errorf("clearing: %v", err)
+	}
+
+	assertAppError(t, svc.UpdateEntityTypeAvatarStyle(ctx, 1, "emoji"), 400)
+	if err := svc.UpdateEntityTypeAvatarStyle(ctx, 1, AvatarStyleInitials); err != nil {
+		t.Errorf("initials: %v", err)
+	}
+}
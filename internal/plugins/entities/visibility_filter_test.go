@@ -44,6 +44,8 @@ func TestVisibilityFilter(t *testing.T) {
 				"tag_permissions",
 				"ep.subject_type = 'public'",
 				"tp.subject_type = 'public'",
+				"e.status NOT IN ('draft', 'pending') OR e.created_by = ?",
+				"e.status = 'pending' AND ? >= 2",
 			} {
 				if !strings.Contains(frag, want) {
 					t.Errorf("filter missing %q (drift from policy / calendar mirror?)\nfrag=%s", want, frag)
@@ -51,8 +53,10 @@ func TestVisibilityFilter(t *testing.T) {
 			}
 			// entity_permissions branch: role, role, userID, userID.
 			// tag_permissions branch: role, userID, userID.
+			// draft/pending gate: userID (the creator still sees their page), role
+			// (Scribe+ see pages pending review).
 			// The 'public' subject matches unconditionally, so it adds NO arg.
-			wantArgs := []any{role, role, "user-9", "user-9", role, "user-9", "user-9"}
+			wantArgs := []any{role, role, "user-9", "user-9", role, "user-9", "user-9", "user-9", "user-9", role}
 			if len(args) != len(wantArgs) {
 				t.Fatalf("arg count = %d, want %d (%v)", len(args), len(wantArgs), args)
 			}
@@ -0,0 +1,221 @@
+// Package entities contains the entity watch model and repository.
+// Watches are per-member subscriptions to a page or to every page of a
+// type; changes queue up in entity_watch_pending and are sent in batches
+// by WatchService (watch_service.go).
+package entities
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WatchState is a member's watch settings as seen from one page: watching
+// the page itself, watching its whole type, and whether changes are
+// emailed as well as shown in the notification list.
+type WatchState struct {
+	Entity bool `json:"entity"`
+	Type   bool `json:"type"`
+	Email  bool `json:"email"`
+}
+
+// Watcher is a member to notify about a change.
+type Watcher struct {
+	UserID string
+	Email  bool
+}
+
+// PendingWatchChange is a batch of changes to one page waiting to be sent
+// to one watcher.
+type PendingWatchChange struct {
+	UserID         string
+	EntityID       string
+	CampaignID     string
+	IsNew          bool // The batch includes the page's creation.
+	Email          bool
+	ChangeCount    int
+	FirstChangedAt time.Time
+	LastChangedAt  time.Time
+}
+
+// WatchRepository provides persistence for entity and type watches and the
+// pending-change queue.
+type WatchRepository interface {
+	// GetState returns the user's watch state for a page of the given type.
+	GetState(ctx context.Context, userID, entityID string, entityTypeID int) (*WatchState, error)
+
+	// SetEntityWatch and SetTypeWatch add (or update the email flag of) a
+	// watch, or remove it when watching is false.
+	SetEntityWatch(ctx context.Context, userID, campaignID, entityID string, watching, email bool) error
+	SetTypeWatch(ctx context.Context, userID, campaignID string, entityTypeID int, watching, email bool) error
+
+	// ListWatchers returns everyone watching the page or its type, once
+	// each; Email is set if either watch asks for it.
+	ListWatchers(ctx context.Context, entityID string, entityTypeID int) ([]Watcher, error)
+
+	// QueueChange records one change for a watcher, folding it into any
+	// batch already pending for that page.
+	QueueChange(ctx context.Context, w Watcher, campaignID, entityID string, isNew bool, at time.Time) error
+
+	// ListDue returns batches last changed before quietBefore, or first
+	// changed before stuckBefore, oldest first.
+	ListDue(ctx context.Context, quietBefore, stuckBefore time.Time, limit int) ([]PendingWatchChange, error)
+
+	// ClaimPending deletes the batch if it is unchanged since it was read
+	// and reports whether this caller won. A change that lands in between
+	// keeps the batch for the next pass.
+	ClaimPending(ctx context.Context, p PendingWatchChange) (bool, error)
+}
+
+// watchRepository implements WatchRepository with MariaDB.
+type watchRepository struct {
+	db *sql.DB
+}
+
+// NewWatchRepository creates a watch repository.
+func NewWatchRepository(db *sql.DB) WatchRepository {
+	return &watchRepository{db: db}
+}
+
+// GetState reads both watch rows in one query.
+func (r *watchRepository) GetState(ctx context.Context, userID, entityID string, entityTypeID int) (*WatchState, error) {
+	var st WatchState
+	var entityEmail, typeEmail sql.NullBool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+		   (SELECT email FROM entity_watches WHERE user_id = ? AND entity_id = ?),
+		   (SELECT email FROM entity_type_watches WHERE user_id = ? AND entity_type_id = ?)`,
+		userID, entityID, userID, entityTypeID,
+	).Scan(&entityEmail, &typeEmail)
+	if err != nil {
+		return nil, fmt.Errorf("reading watch state: %w", err)
+	}
+	st.Entity, st.Type = entityEmail.Valid, typeEmail.Valid
+	st.Email = (entityEmail.Valid && entityEmail.Bool) || (typeEmail.Valid && typeEmail.Bool)
+	return &st, nil
+}
+
+// SetEntityWatch upserts or deletes the user's watch on a page.
+func (r *watchRepository) SetEntityWatch(ctx context.Context, userID, campaignID, entityID string, watching, email bool) error {
+	var err error
+	if watching {
+		_, err = r.db.ExecContext(ctx,
+			`INSERT INTO entity_watches (user_id, entity_id, campaign_id, email) VALUES (?, ?, ?, ?)
+			 ON DUPLICATE KEY UPDATE email = VALUES(email)`,
+			userID, entityID, campaignID, email)
+	} else {
+		_, err = r.db.ExecContext(ctx,
+			`DELETE FROM entity_watches WHERE user_id = ? AND entity_id = ?`, userID, entityID)
+	}
+	if err != nil {
+		return fmt.Errorf("setting entity watch: %w", err)
+	}
+	return nil
+}
+
+// SetTypeWatch upserts or deletes the user's watch on an entity type.
+func (r *watchRepository) SetTypeWatch(ctx context.Context, userID, campaignID string, entityTypeID int, watching, email bool) error {
+	var err error
+	if watching {
+		_, err = r.db.ExecContext(ctx,
+			`INSERT INTO entity_type_watches (user_id, entity_type_id, campaign_id, email) VALUES (?, ?, ?, ?)
+			 ON DUPLICATE KEY UPDATE email = VALUES(email)`,
+			userID, entityTypeID, campaignID, email)
+	} else {
+		_, err = r.db.ExecContext(ctx,
+			`DELETE FROM entity_type_watches WHERE user_id = ? AND entity_type_id = ?`, userID, entityTypeID)
+	}
+	if err != nil {
+		return fmt.Errorf("setting entity type watch: %w", err)
+	}
+	return nil
+}
+
+// ListWatchers merges page and type watchers.
+func (r *watchRepository) ListWatchers(ctx context.Context, entityID string, entityTypeID int) ([]Watcher, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, MAX(email) FROM (
+		   SELECT user_id, email FROM entity_watches WHERE entity_id = ?
+		   UNION ALL
+		   SELECT user_id, email FROM entity_type_watches WHERE entity_type_id = ?
+		 ) w
+		 GROUP BY user_id`,
+		entityID, entityTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("listing watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Watcher
+	for rows.Next() {
+		var w Watcher
+		if err := rows.Scan(&w.UserID, &w.Email); err != nil {
+			return nil, fmt.Errorf("scanning watcher: %w", err)
+		}
+		list = append(list, w)
+	}
+	return list, rows.Err()
+}
+
+// QueueChange upserts the watcher's pending batch for the page.
+func (r *watchRepository) QueueChange(ctx context.Context, w Watcher, campaignID, entityID string, isNew bool, at time.Time) error {
+	at = at.UTC().Truncate(time.Second)
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO entity_watch_pending
+		   (user_id, entity_id, campaign_id, is_new, email, change_count, first_changed_at, last_changed_at)
+		 VALUES (?, ?, ?, ?, ?, 1, ?, ?)
+		 ON DUPLICATE KEY UPDATE
+		   change_count = change_count + 1,
+		   last_changed_at = VALUES(last_changed_at),
+		   is_new = is_new OR VALUES(is_new),
+		   email = email OR VALUES(email)`,
+		w.UserID, entityID, campaignID, isNew, w.Email, at, at)
+	if err != nil {
+		return fmt.Errorf("queueing watch change: %w", err)
+	}
+	return nil
+}
+
+// ListDue returns batches ready to send.
+func (r *watchRepository) ListDue(ctx context.Context, quietBefore, stuckBefore time.Time, limit int) ([]PendingWatchChange, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, entity_id, campaign_id, is_new, email, change_count, first_changed_at, last_changed_at
+		 FROM entity_watch_pending
+		 WHERE last_changed_at <= ? OR first_changed_at <= ?
+		 ORDER BY first_changed_at
+		 LIMIT ?`,
+		quietBefore.UTC(), stuckBefore.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing due watch changes: %w", err)
+	}
+	defer rows.Close()
+
+	var list []PendingWatchChange
+	for rows.Next() {
+		var p PendingWatchChange
+		if err := rows.Scan(&p.UserID, &p.EntityID, &p.CampaignID, &p.IsNew, &p.Email,
+			&p.ChangeCount, &p.FirstChangedAt, &p.LastChangedAt); err != nil {
+			return nil, fmt.Errorf("scanning watch change: %w", err)
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// ClaimPending deletes the batch only if no change has landed since it was
+// read (same count and last change time).
+func (r *watchRepository) ClaimPending(ctx context.Context, p PendingWatchChange) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM entity_watch_pending
+		 WHERE user_id = ? AND entity_id = ? AND change_count = ? AND last_changed_at = ?`,
+		p.UserID, p.EntityID, p.ChangeCount, p.LastChangedAt)
+	if err != nil {
+		return false, fmt.Errorf("claiming watch change: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claiming watch change: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// SetWatchService enables the watch control on entity pages.
+func (h *Handler) SetWatchService(svc WatchService) {
+	h.watchSvc = svc
+}
+
+// WatchControl renders the page's watch menu, loaded lazily from the title
+// block. GET /campaigns/:id/entities/:eid/watch
+func (h *Handler) WatchControl(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if h.watchSvc == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	entity, err := h.watchableEntity(c, cc)
+	if err != nil {
+		return err
+	}
+	state, err := h.watchSvc.GetState(c.Request().Context(), auth.GetUserID(c), entity)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("loading watch state: %w", err))
+	}
+	return middleware.Render(c, http.StatusOK, watchControl(cc, entity, *state, middleware.GetCSRFToken(c)))
+}
+
+// UpdateWatchAPI saves the watch menu. Form fields "entity", "type" and
+// "email" are checkboxes; an absent field is off.
+// POST /campaigns/:id/entities/:eid/watch
+func (h *Handler) UpdateWatchAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if h.watchSvc == nil {
+		return apperror.NewNotFound("watches are not available")
+	}
+	entity, err := h.watchableEntity(c, cc)
+	if err != nil {
+		return err
+	}
+	state := WatchState{
+		Entity: c.FormValue("entity") != "",
+		Type:   c.FormValue("type") != "",
+		Email:  c.FormValue("email") != "",
+	}
+	if err := h.watchSvc.SetState(c.Request().Context(), auth.GetUserID(c), entity, state); err != nil {
+		return apperror.NewInternal(fmt.Errorf("saving watch state: %w", err))
+	}
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, watchControl(cc, entity, state, middleware.GetCSRFToken(c)))
+	}
+	return c.JSON(http.StatusOK, state)
+}
+
+// watchableEntity loads the :eid entity, checking it belongs to the campaign
+// (IDOR) and that the member can see it. Templates can't be watched.
+func (h *Handler) watchableEntity(c echo.Context, cc *campaigns.CampaignContext) (*Entity, error) {
+	ctx := c.Request().Context()
+	entity, err := h.service.GetByID(ctx, c.Param("eid"))
+	if err != nil {
+		return nil, err
+	}
+	if entity.CampaignID != cc.Campaign.ID || entity.IsTemplate {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	access, err := h.service.CheckEntityAccess(ctx, entity.ID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil || !access.CanView {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	return entity, nil
+}
@@ -0,0 +1,339 @@
+package entities
+
+// watch_service.go — change notifications for watched pages. The entity
+// event publisher hands every create and update to RecordChange, which only
+// queues a pending row per watcher; FlushDue turns a page's rows into one
+// notification once its edits go quiet. Autosave fires an update every few
+// seconds while someone types, so sending per event would bury the bell.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
+)
+
+// NotifEntityWatch is the notification type for changes to a watched page.
+const NotifEntityWatch = "entity_watch"
+
+const (
+	// watchQuietPeriod is how long a page must go without edits before its
+	// pending changes are sent, so one writing session is one notification.
+	watchQuietPeriod = 5 * time.Minute
+
+	// watchMaxDelay caps how long a page under constant editing is held
+	// back, so watchers hear about it within the hour regardless.
+	watchMaxDelay = time.Hour
+
+	// watchFlushBatchSize bounds how many pending batches one pass handles.
+	watchFlushBatchSize = 100
+
+	// watchFlushInterval is how often the flush job runs.
+	watchFlushInterval = time.Minute
+
+	// watchEditorNames is how many editors a notification names before
+	// summing up the rest.
+	watchEditorNames = 2
+)
+
+// WatchEditor is someone who changed a page, from the audit log.
+type WatchEditor struct {
+	UserID string
+	Name   string
+}
+
+// WatchEditorLister returns who changed a page since a time, newest first.
+// Entity events carry no actor, so the audit log is how a flush tells a
+// watcher's own edits from everyone else's. Implemented in internal/app
+// over the audit repository.
+type WatchEditorLister interface {
+	ListEntityEditors(ctx context.Context, campaignID, entityID string, since time.Time) ([]WatchEditor, error)
+}
+
+// WatchMemberGetter looks up a campaign member. Subset of
+// campaigns.CampaignService.
+type WatchMemberGetter interface {
+	GetMember(ctx context.Context, campaignID, userID string) (*campaigns.CampaignMember, error)
+}
+
+// WatchEntityReader is the slice of EntityService a flush needs to re-check
+// the page and the watcher's access at send time.
+type WatchEntityReader interface {
+	GetByID(ctx context.Context, id string) (*Entity, error)
+	CheckEntityAccess(ctx context.Context, entityID string, role int, userID string) (*EffectivePermission, error)
+}
+
+// WatchMailer sends watch emails. Subset of smtp.MailService.
+type WatchMailer interface {
+	SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error
+	IsConfigured(ctx context.Context) bool
+}
+
+// WatchService manages watches and sends their notifications.
+type WatchService interface {
+	GetState(ctx context.Context, userID string, entity *Entity) (*WatchState, error)
+	SetState(ctx context.Context, userID string, entity *Entity, state WatchState) error
+
+	// RecordChange queues a change for the page's watchers. Errors are
+	// logged; a missed watch notification never fails the edit.
+	RecordChange(ctx context.Context, eventType, campaignID string, entity *Entity)
+
+	// FlushDue sends one batch of settled changes and returns how many
+	// pending rows it handled.
+	FlushDue(ctx context.Context, now time.Time) (int, error)
+
+	// Late-bound dependencies, wired after all plugins exist.
+	SetNotifier(n UserNotifier)
+	SetMailer(m WatchMailer, baseURL string)
+	SetEditorLister(l WatchEditorLister)
+}
+
+// watchService implements WatchService.
+type watchService struct {
+	repo     WatchRepository
+	entities WatchEntityReader
+	members  WatchMemberGetter
+	notifier UserNotifier
+	mailer   WatchMailer
+	baseURL  string
+	editors  WatchEditorLister
+	now      func() time.Time
+}
+
+// NewWatchService creates a new watch service.
+func NewWatchService(repo WatchRepository, entities WatchEntityReader, members WatchMemberGetter) WatchService {
+	return &watchService{repo: repo, entities: entities, members: members, now: time.Now}
+}
+
+// SetNotifier sets where notifications are written.
+func (s *watchService) SetNotifier(n UserNotifier) { s.notifier = n }
+
+// SetMailer enables email for watchers who asked for it.
+func (s *watchService) SetMailer(m WatchMailer, baseURL string) {
+	s.mailer = m
+	s.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// SetEditorLister enables skipping a watcher's own edits.
+func (s *watchService) SetEditorLister(l WatchEditorLister) { s.editors = l }
+
+// GetState returns the user's watch state for the page.
+func (s *watchService) GetState(ctx context.Context, userID string, entity *Entity) (*WatchState, error) {
+	return s.repo.GetState(ctx, userID, entity.ID, entity.EntityTypeID)
+}
+
+// SetState writes both watches. The email flag applies to whichever of them
+// remain, so the control reads back what was submitted.
+func (s *watchService) SetState(ctx context.Context, userID string, entity *Entity, state WatchState) error {
+	if err := s.repo.SetEntityWatch(ctx, userID, entity.CampaignID, entity.ID, state.Entity, state.Email); err != nil {
+		return err
+	}
+	return s.repo.SetTypeWatch(ctx, userID, entity.CampaignID, entity.EntityTypeID, state.Type, state.Email)
+}
+
+// RecordChange queues creates and updates. Templates are scaffolding, not
+// content, and deletes have no page left to link to.
+func (s *watchService) RecordChange(ctx context.Context, eventType, campaignID string, entity *Entity) {
+	if entity == nil || entity.IsTemplate || (eventType != "created" && eventType != "updated") {
+		return
+	}
+	watchers, err := s.repo.ListWatchers(ctx, entity.ID, entity.EntityTypeID)
+	if err != nil {
+		slog.Warn("listing entity watchers failed", slog.String("entity_id", entity.ID), slog.Any("error", err))
+		return
+	}
+	at := s.now()
+	for _, w := range watchers {
+		if err := s.repo.QueueChange(ctx, w, campaignID, entity.ID, eventType == "created", at); err != nil {
+			slog.Warn("queueing entity watch change failed",
+				slog.String("entity_id", entity.ID),
+				slog.String("user_id", w.UserID),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// FlushDue claims and delivers settled batches. A batch is claimed before
+// delivery, so a failed send is logged and dropped rather than repeated.
+func (s *watchService) FlushDue(ctx context.Context, now time.Time) (int, error) {
+	if s.notifier == nil {
+		return 0, nil
+	}
+	due, err := s.repo.ListDue(ctx, now.Add(-watchQuietPeriod), now.Add(-watchMaxDelay), watchFlushBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, err := s.repo.ClaimPending(ctx, p)
+		if err != nil {
+			return 0, err
+		}
+		if claimed {
+			s.deliver(ctx, p)
+		}
+	}
+	return len(due), nil
+}
+
+// deliver re-checks membership and access at send time, since either may
+// have changed since the edit, then notifies the watcher.
+func (s *watchService) deliver(ctx context.Context, p PendingWatchChange) {
+	member, err := s.members.GetMember(ctx, p.CampaignID, p.UserID)
+	if err != nil || member == nil {
+		return
+	}
+	entity, err := s.entities.GetByID(ctx, p.EntityID)
+	if err != nil || entity == nil || entity.CampaignID != p.CampaignID {
+		return
+	}
+	perm, err := s.entities.CheckEntityAccess(ctx, entity.ID, int(member.Role), p.UserID)
+	if err != nil || perm == nil || !perm.CanView {
+		return
+	}
+
+	var others []string
+	if s.editors != nil {
+		// Audit timestamps and pending ones are taken separately; the slack
+		// keeps the batch's first edit inside the window.
+		editors, err := s.editors.ListEntityEditors(ctx, p.CampaignID, p.EntityID, p.FirstChangedAt.Add(-time.Minute))
+		if err != nil {
+			slog.Warn("listing entity editors failed", slog.String("entity_id", p.EntityID), slog.Any("error", err))
+		}
+		others = otherEditorNames(editors, p.UserID)
+		if len(editors) > 0 && len(others) == 0 {
+			return // Only the watcher's own edits.
+		}
+	}
+
+	message := watchMessage(entity, p, others)
+	link := fmt.Sprintf("/campaigns/%s/entities/%s", p.CampaignID, p.EntityID)
+	if err := s.notifier.NotifyUser(ctx, p.UserID, p.CampaignID, NotifEntityWatch, message, link); err != nil {
+		slog.Warn("entity watch notification failed",
+			slog.String("entity_id", p.EntityID),
+			slog.String("user_id", p.UserID),
+			slog.Any("error", err))
+	}
+
+	if !p.Email || member.Email == "" || s.mailer == nil || !s.mailer.IsConfigured(ctx) {
+		return
+	}
+	msg := emails.Notification(message, entity.Name, message, "Open page", s.baseURL+link)
+	msg.Outro = []string{"You're getting this because you watch this page or its type. Change that from the watch menu on the page."}
+	plainBody, htmlBody, err := msg.Render(ctx)
+	if err == nil {
+		err = s.mailer.SendHTMLMail(ctx, []string{member.Email}, msg.Subject, plainBody, htmlBody)
+	}
+	if err != nil {
+		slog.Warn("entity watch email failed",
+			slog.String("entity_id", p.EntityID),
+			slog.String("user_id", p.UserID),
+			slog.Any("error", err))
+	}
+}
+
+// otherEditorNames returns the distinct editors other than userID, in the
+// order given.
+func otherEditorNames(editors []WatchEditor, userID string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, e := range editors {
+		if e.UserID == userID || seen[e.UserID] {
+			continue
+		}
+		seen[e.UserID] = true
+		name := e.Name
+		if name == "" {
+			name = "someone"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// watchMessage builds the notification text, e.g. "Ireena was updated by
+// Alice and Bob (4 changes)" or "New NPC: Ireena".
+func watchMessage(entity *Entity, p PendingWatchChange, editors []string) string {
+	var b strings.Builder
+	if p.IsNew {
+		typeName := entity.TypeName
+		if typeName == "" {
+			typeName = "page"
+		}
+		fmt.Fprintf(&b, "New %s: %s", typeName, entity.Name)
+	} else {
+		fmt.Fprintf(&b, "%s was updated", entity.Name)
+	}
+
+	switch n := len(editors); {
+	case n == 1:
+		b.WriteString(" by " + editors[0])
+	case n == 2:
+		b.WriteString(" by " + editors[0] + " and " + editors[1])
+	case n > watchEditorNames:
+		rest := n - watchEditorNames
+		others := "others"
+		if rest == 1 {
+			others = "other"
+		}
+		fmt.Fprintf(&b, " by %s and %d %s", strings.Join(editors[:watchEditorNames], ", "), rest, others)
+	}
+
+	if !p.IsNew && p.ChangeCount > 1 {
+		fmt.Fprintf(&b, " (%d changes)", p.ChangeCount)
+	}
+	return b.String()
+}
+
+// WatchFlusher is the slice of WatchService the flush job needs.
+type WatchFlusher interface {
+	FlushDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// WatchFlushJob sends watch notifications once pages settle.
+type WatchFlushJob struct {
+	flusher WatchFlusher
+	now     func() time.Time
+}
+
+// NewWatchFlushJob creates the flush job.
+func NewWatchFlushJob(flusher WatchFlusher) *WatchFlushJob {
+	return &WatchFlushJob{flusher: flusher, now: time.Now}
+}
+
+// Run flushes every settled batch, batch by batch.
+func (j *WatchFlushJob) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := j.flusher.FlushDue(ctx, j.now())
+		total += n
+		if err != nil || n < watchFlushBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job every minute until ctx is cancelled.
+func (j *WatchFlushJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(watchFlushInterval)
+	defer ticker.Stop()
+
+	slog.Info("entity watch worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("entity watch worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := j.Run(ctx); err != nil {
+				slog.Error("entity watch flush failed", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// fakeWatchRepo is an in-memory WatchRepository.
+type fakeWatchRepo struct {
+	watchers []Watcher
+	pending  map[string]*PendingWatchChange // userID + "/" + entityID
+}
+
+func (r *fakeWatchRepo) GetState(context.Context, string, string, int) (*WatchState, error) {
+	return &WatchState{}, nil
+}
+
+func (r *fakeWatchRepo) SetEntityWatch(context.Context, string, string, string, bool, bool) error {
+	return nil
+}
+
+func (r *fakeWatchRepo) SetTypeWatch(context.Context, string, string, int, bool, bool) error {
+	return nil
+}
+
+func (r *fakeWatchRepo) ListWatchers(context.Context, string, int) ([]Watcher, error) {
+	return r.watchers, nil
+}
+
+func (r *fakeWatchRepo) QueueChange(_ context.Context, w Watcher, campaignID, entityID string, isNew bool, at time.Time) error {
+	key := w.UserID + "/" + entityID
+	if p, ok := r.pending[key]; ok {
+		p.ChangeCount++
+		p.LastChangedAt = at
+		p.IsNew = p.IsNew || isNew
+		p.Email = p.Email || w.Email
+		return nil
+	}
+	r.pending[key] = &PendingWatchChange{
+		UserID: w.UserID, EntityID: entityID, CampaignID: campaignID,
+		IsNew: isNew, Email: w.Email, ChangeCount: 1, FirstChangedAt: at, LastChangedAt: at,
+	}
+	return nil
+}
+
+func (r *fakeWatchRepo) ListDue(_ context.Context, quietBefore, stuckBefore time.Time, _ int) ([]PendingWatchChange, error) {
+	var due []PendingWatchChange
+	for _, p := range r.pending {
+		if !p.LastChangedAt.After(quietBefore) || !p.FirstChangedAt.After(stuckBefore) {
+			due = append(due, *p)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWatchRepo) ClaimPending(_ context.Context, p PendingWatchChange) (bool, error) {
+	key := p.UserID + "/" + p.EntityID
+	cur, ok := r.pending[key]
+	if !ok || cur.ChangeCount != p.ChangeCount {
+		return false, nil
+	}
+	delete(r.pending, key)
+	return true, nil
+}
+
+// fakeWatchEntities serves one entity; hidden lists users who can't view it.
+type fakeWatchEntities struct {
+	entity *Entity
+	hidden map[string]bool
+}
+
+func (f *fakeWatchEntities) GetByID(_ context.Context, id string) (*Entity, error) {
+	if f.entity == nil || f.entity.ID != id {
+		return nil, errors.New("not found")
+	}
+	return f.entity, nil
+}
+
+func (f *fakeWatchEntities) CheckEntityAccess(_ context.Context, _ string, _ int, userID string) (*EffectivePermission, error) {
+	return &EffectivePermission{CanView: !f.hidden[userID]}, nil
+}
+
+type fakeWatchMembers map[string]*campaigns.CampaignMember
+
+func (m fakeWatchMembers) GetMember(_ context.Context, _, userID string) (*campaigns.CampaignMember, error) {
+	if mem, ok := m[userID]; ok {
+		return mem, nil
+	}
+	return nil, errors.New("not a member")
+}
+
+type fakeWatchEditors []WatchEditor
+
+func (e fakeWatchEditors) ListEntityEditors(context.Context, string, string, time.Time) ([]WatchEditor, error) {
+	return e, nil
+}
+
+type recordingWatchNotifier struct {
+	users    []string
+	messages []string
+}
+
+func (n *recordingWatchNotifier) NotifyUser(_ context.Context, userID, _, kind, message, _ string) error {
+	if kind != NotifEntityWatch {
+		return errors.New("unexpected kind " + kind)
+	}
+	n.users = append(n.users, userID)
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+type recordingWatchMailer struct{ to []string }
+
+func (m *recordingWatchMailer) SendHTMLMail(_ context.Context, to []string, _, _, _ string) error {
+	m.to = append(m.to, to...)
+	return nil
+}
+
+func (m *recordingWatchMailer) IsConfigured(context.Context) bool { return true }
+
+func newTestWatchService(now *time.Time) (*watchService, *fakeWatchRepo, *fakeWatchEntities, *recordingWatchNotifier, *recordingWatchMailer) {
+	repo := &fakeWatchRepo{pending: map[string]*PendingWatchChange{}}
+	ents := &fakeWatchEntities{
+		entity: &Entity{ID: "e1", CampaignID: "c1", EntityTypeID: 3, Name: "Ireena", TypeName: "NPC"},
+		hidden: map[string]bool{},
+	}
+	members := fakeWatchMembers{
+		"alice": {UserID: "alice", Role: campaigns.RolePlayer, Email: "alice@example.com"},
+		"bob":   {UserID: "bob", Role: campaigns.RolePlayer, Email: "bob@example.com"},
+	}
+	svc := NewWatchService(repo, ents, members).(*watchService)
+	svc.now = func() time.Time { return *now }
+	notifier := &recordingWatchNotifier{}
+	mailer := &recordingWatchMailer{}
+	svc.SetNotifier(notifier)
+	svc.SetMailer(mailer, "https://chronicle.test/")
+	return svc, repo, ents, notifier, mailer
+}
+
+func TestWatchService_BatchesAutosaves(t *testing.T) {
+	now := time.Date(2026, 6, 8, 12, 0, 0, 0, time.UTC)
+	svc, repo, ents, notifier, mailer := newTestWatchService(&now)
+	svc.SetEditorLister(fakeWatchEditors{{UserID: "bob", Name: "Bob"}})
+	repo.watchers = []Watcher{{UserID: "alice", Email: true}}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		svc.RecordChange(ctx, "updated", "c1", ents.entity)
+		now = now.Add(30 * time.Second)
+	}
+	svc.RecordChange(ctx, "deleted", "c1", ents.entity)
+
+	if _, err := svc.FlushDue(ctx, now); err != nil || len(notifier.messages) != 0 {
+		t.Fatalf("flushed mid-edit: %v, %v", notifier.messages, err)
+	}
+
+	now = now.Add(watchQuietPeriod)
+	if _, err := svc.FlushDue(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.messages) != 1 || notifier.messages[0] != "Ireena was updated by Bob (3 changes)" {
+		t.Fatalf("notifications = %v; want one batched message", notifier.messages)
+	}
+	if strings.Join(mailer.to, ",") != "alice@example.com" {
+		t.Errorf("emailed %v; want alice", mailer.to)
+	}
+	if len(repo.pending) != 0 {
+		t.Errorf("pending not cleared: %v", repo.pending)
+	}
+}
+
+func TestWatchService_MaxDelayUnderConstantEditing(t *testing.T) {
+	now := time.Date(2026, 6, 8, 12, 0, 0, 0, time.UTC)
+	svc, repo, ents, notifier, _ := newTestWatchService(&now)
+	repo.watchers = []Watcher{{UserID: "alice"}}
+	ctx := context.Background()
+
+	start := now
+	for now.Sub(start) <= watchMaxDelay {
+		svc.RecordChange(ctx, "updated", "c1", ents.entity)
+		now = now.Add(time.Minute)
+	}
+	if _, err := svc.FlushDue(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.messages) != 1 {
+		t.Errorf("notifications = %v; want one after the max delay", notifier.messages)
+	}
+}
+
+func TestWatchService_DropsUndeliverable(t *testing.T) {
+	cases := []struct {
+		name    string
+		watcher string
+		editors fakeWatchEditors
+		hidden  bool
+		isTmpl  bool
+	}{
+		{name: "own edits", watcher: "alice", editors: fakeWatchEditors{{UserID: "alice", Name: "Alice"}}},
+		{name: "no longer a member", watcher: "carol"},
+		{name: "lost access", watcher: "alice", hidden: true},
+		{name: "template", watcher: "alice", isTmpl: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Date(2026, 6, 8, 12, 0, 0, 0, time.UTC)
+			svc, repo, ents, notifier, _ := newTestWatchService(&now)
+			svc.SetEditorLister(tc.editors)
+			repo.watchers = []Watcher{{UserID: tc.watcher}}
+			ents.hidden[tc.watcher] = tc.hidden
+			ents.entity.IsTemplate = tc.isTmpl
+			ctx := context.Background()
+
+			svc.RecordChange(ctx, "updated", "c1", ents.entity)
+			if _, err := svc.FlushDue(ctx, now.Add(watchQuietPeriod)); err != nil {
+				t.Fatal(err)
+			}
+			if len(notifier.messages) != 0 {
+				t.Errorf("notified %v; want nothing", notifier.messages)
+			}
+		})
+	}
+}
+
+func TestWatchMessage(t *testing.T) {
+	entity := &Entity{Name: "Ireena", TypeName: "NPC"}
+	cases := []struct {
+		name    string
+		p       PendingWatchChange
+		editors []string
+		want    string
+	}{
+		{"single update", PendingWatchChange{ChangeCount: 1}, nil, "Ireena was updated"},
+		{"created and edited", PendingWatchChange{IsNew: true, ChangeCount: 4}, []string{"Bob"}, "New NPC: Ireena by Bob"},
+		{"two editors", PendingWatchChange{ChangeCount: 2}, []string{"Bob", "Cara"}, "Ireena was updated by Bob and Cara (2 changes)"},
+		{"three editors", PendingWatchChange{ChangeCount: 3}, []string{"Bob", "Cara", "Dan"}, "Ireena was updated by Bob, Cara and 1 other (3 changes)"},
+		{"many editors", PendingWatchChange{ChangeCount: 9}, []string{"Bob", "Cara", "Dan", "Eve"}, "Ireena was updated by Bob, Cara and 2 others (9 changes)"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := watchMessage(entity, tc.p, tc.editors); got != tc.want {
+				t.Errorf("watchMessage = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -84,6 +84,18 @@ type Marker struct {
 	// Joined fields for display (populated by some queries).
 	EntityName string `json:"entity_name,omitempty"`
 	EntityIcon string `json:"entity_icon,omitempty"`
+	// EntityImage is the linked page's image, or its type's default; a
+	// media file name.
+	EntityImage string `json:"entity_image,omitempty"`
+}
+
+// EntityMarker is a marker pinned to an entity, with its map's name, for
+// the entity page's "Appears in" panel.
+type EntityMarker struct {
+	MarkerID   string
+	MarkerName string
+	MapID      string
+	MapName    string
 }
 
 // IsDMOnly returns true if this marker is only visible to the DM.
@@ -25,6 +25,7 @@ type MapRepository interface {
 	UpdateMarker(ctx context.Context, mk *Marker) error
 	DeleteMarker(ctx context.Context, id string) error
 	ListMarkers(ctx context.Context, mapID string, role int, userID string) ([]Marker, error)
+	ListMarkersForEntity(ctx context.Context, campaignID, entityID string, role int, userID string) ([]EntityMarker, error)
 }
 
 // mapRepo is the MariaDB implementation of MapRepository.
@@ -121,7 +122,23 @@ const markerCols = `m.id, m.map_id, m.name, m.description,
        m.x, m.y, m.icon, m.color, m.pin_category,
        m.entity_id, m.visibility, m.visibility_rules,
        m.created_by, m.foundry_id, m.created_at, m.updated_at,
-       COALESCE(ent.name, ''), COALESCE(et.icon, '')`
+       COALESCE(ent.name, ''), COALESCE(et.icon, ''),
+       COALESCE(NULLIF(ent.image_path, ''), et.default_image, '')`
+
+// markerPlayerVisible limits markers to those a non-owner may see:
+// 'everyone' + per-player rules, or 'specific' with allowed_users. Takes
+// the viewer's user ID twice.
+const markerPlayerVisible = `m.visibility != 'dm_only'
+		  AND (
+		    m.visibility_rules IS NULL
+		    OR (
+		      NOT JSON_CONTAINS(m.visibility_rules, JSON_QUOTE(?), '$.denied_users')
+		      AND (
+		        JSON_LENGTH(COALESCE(JSON_EXTRACT(m.visibility_rules, '$.allowed_users'), '[]')) = 0
+		        OR JSON_CONTAINS(m.visibility_rules, JSON_QUOTE(?), '$.allowed_users')
+		      )
+		    )
+		  )`
 
 // markerJoins is the LEFT JOIN clause for entity display data.
 const markerJoins = `LEFT JOIN entities ent ON ent.id = m.entity_id
@@ -134,7 +151,7 @@ func scanMarker(scanner interface{ Scan(...any) error }) (*Marker, error) {
 		&mk.X, &mk.Y, &mk.Icon, &mk.Color, &mk.PinCategory,
 		&mk.EntityID, &mk.Visibility, &mk.VisibilityRules,
 		&mk.CreatedBy, &mk.FoundryID, &mk.CreatedAt, &mk.UpdatedAt,
-		&mk.EntityName, &mk.EntityIcon)
+		&mk.EntityName, &mk.EntityIcon, &mk.EntityImage)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -218,17 +235,7 @@ func (r *mapRepo) ListMarkers(ctx context.Context, mapID string, role int, userI
 		SELECT ` + markerCols + `
 		FROM map_markers m ` + markerJoins + `
 		WHERE m.map_id = ?
-		  AND m.visibility != 'dm_only'
-		  AND (
-		    m.visibility_rules IS NULL
-		    OR (
-		      NOT JSON_CONTAINS(m.visibility_rules, JSON_QUOTE(?), '$.denied_users')
-		      AND (
-		        JSON_LENGTH(COALESCE(JSON_EXTRACT(m.visibility_rules, '$.allowed_users'), '[]')) = 0
-		        OR JSON_CONTAINS(m.visibility_rules, JSON_QUOTE(?), '$.allowed_users')
-		      )
-		    )
-		  )
+		  AND `+markerPlayerVisible+`
 		ORDER BY m.name`
 	rows, err := r.db.QueryContext(ctx, query, mapID, userID, userID)
 	if err != nil {
@@ -247,6 +254,38 @@ func (r *mapRepo) ListMarkers(ctx context.Context, mapID string, role int, userI
 	return result, rows.Err()
 }
 
+// ListMarkersForEntity returns the campaign's markers pinned to an entity
+// that the viewer may see, with their map names, ordered by map then
+// marker name.
+func (r *mapRepo) ListMarkersForEntity(ctx context.Context, campaignID, entityID string, role int, userID string) ([]EntityMarker, error) {
+	query := `SELECT m.id, m.name, mp.id, mp.name
+		FROM map_markers m
+		INNER JOIN maps mp ON mp.id = m.map_id
+		WHERE mp.campaign_id = ? AND m.entity_id = ?`
+	args := []any{campaignID, entityID}
+	if !permissions.CanSeeDmOnly(role) {
+		query += ` AND ` + markerPlayerVisible
+		args = append(args, userID, userID)
+	}
+	query += ` ORDER BY mp.name, m.name`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list markers for entity: %w", err)
+	}
+	defer rows.Close()
+
+	var result []EntityMarker
+	for rows.Next() {
+		var em EntityMarker
+		if err := rows.Scan(&em.MarkerID, &em.MarkerName, &em.MapID, &em.MapName); err != nil {
+			return nil, fmt.Errorf("scan entity marker: %w", err)
+		}
+		result = append(result, em)
+	}
+	return result, rows.Err()
+}
+
 // SearchMaps returns maps matching a name query for a campaign.
 func (r *mapRepo) SearchMaps(ctx context.Context, campaignID, query string) ([]Map, error) {
 	rows, err := r.db.QueryContext(ctx,
@@ -49,6 +49,7 @@ type MapService interface {
 	UpdateMarker(ctx context.Context, id string, input UpdateMarkerInput) error
 	DeleteMarker(ctx context.Context, id string, expectedUpdatedAt *time.Time) error
 	ListMarkers(ctx context.Context, mapID string, role int, userID string) ([]Marker, error)
+	ListMarkersForEntity(ctx context.Context, campaignID, entityID string, role int, userID string) ([]EntityMarker, error)
 
 	// Wiring.
 	SetEventPublisher(pub MapEventPublisher)
@@ -345,6 +346,16 @@ func (s *mapService) ListMarkers(ctx context.Context, mapID string, role int, us
 	return markers, nil
 }
 
+// ListMarkersForEntity returns the markers pinned to an entity across the
+// campaign's maps, filtered by role and user.
+func (s *mapService) ListMarkersForEntity(ctx context.Context, campaignID, entityID string, role int, userID string) ([]EntityMarker, error) {
+	markers, err := s.repo.ListMarkersForEntity(ctx, campaignID, entityID, role, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list markers for entity: %w", err)
+	}
+	return markers, nil
+}
+
 // SearchMaps returns maps matching a query as map results for the quick search system.
 // Results are formatted to match the entity search JSON format.
 func (s *mapService) SearchMaps(ctx context.Context, campaignID, query string) ([]map[string]string, error) {
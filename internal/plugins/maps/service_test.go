@@ -101,6 +101,10 @@ func (m *mockMapRepo) ListMarkers(ctx context.Context, mapID string, role int, u
 	return nil, nil
 }
 
+func (m *mockMapRepo) ListMarkersForEntity(ctx context.Context, campaignID, entityID string, role int, userID string) ([]EntityMarker, error) {
+	return nil, nil
+}
+
 // --- Test Helpers ---
 
 func newTestMapService(repo *mockMapRepo) MapService {
@@ -0,0 +1,111 @@
+package media
+
+import (
+	"strings"
+	"time"
+)
+
+// Attachment target types. Values match the ENUM in
+// db/migrations/000038_media_attachments.up.sql.
+const (
+	AttachTargetEntity  = "entity"
+	AttachTargetSession = "session"
+)
+
+// Attachment visibility. dm_only hides the file from players entirely: it
+// isn't listed and its download 404s.
+const (
+	AttachVisibleEveryone = "everyone"
+	AttachVisibleDMOnly   = "dm_only"
+)
+
+// maxAttachmentTitle bounds the display title (VARCHAR(200)).
+const maxAttachmentTitle = 200
+
+// Attachment is a file (handout PDF, audio, token pack, image) attached to
+// an entity or a session, with its own visibility and download counter.
+type Attachment struct {
+	ID               string     `json:"id"`
+	CampaignID       string     `json:"campaign_id"`
+	MediaID          string     `json:"-"` // never sent: /media/:id skips the visibility check
+	TargetType       string     `json:"target_type"`
+	TargetID         string     `json:"target_id"`
+	Title            string     `json:"title"`
+	Visibility       string     `json:"visibility"`
+	OriginalName     string     `json:"original_name"`
+	MimeType         string     `json:"mime_type"`
+	FileSize         int64      `json:"file_size"`
+	DownloadCount    int        `json:"download_count"`
+	LastDownloadedAt *time.Time `json:"last_downloaded_at,omitempty"`
+	CreatedBy        string     `json:"created_by,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+
+	// Filename is the media file's path under the media root.
+	Filename string `json:"-"`
+	// Kind and DownloadURL are filled in for the client.
+	Kind        string `json:"kind"`
+	DownloadURL string `json:"download_url"`
+}
+
+// AttachmentUploadInput is a validated attachment upload.
+type AttachmentUploadInput struct {
+	CampaignID   string
+	TargetType   string
+	TargetID     string
+	Title        string
+	Visibility   string
+	UploadedBy   string
+	OriginalName string
+	MimeType     string
+	FileBytes    []byte
+}
+
+// UpdateAttachmentRequest changes an attachment's title or visibility.
+type UpdateAttachmentRequest struct {
+	Title      string `json:"title"`
+	Visibility string `json:"visibility"`
+}
+
+// attachmentKind is a family of attachable files with its own size cap. The
+// site-wide upload limit still applies on top; the tighter of the two wins.
+type attachmentKind struct {
+	name    string
+	maxSize int64
+}
+
+var (
+	kindImage    = attachmentKind{"image", 20 << 20}
+	kindAudio    = attachmentKind{"audio", 50 << 20}
+	kindDocument = attachmentKind{"document", 25 << 20}
+	kindArchive  = attachmentKind{"archive", 100 << 20}
+)
+
+// attachmentKinds maps each attachable MIME type to its kind. Anything not
+// listed is refused, whatever the generic upload allows.
+var attachmentKinds = map[string]attachmentKind{
+	"image/jpeg":      kindImage,
+	"image/png":       kindImage,
+	"image/webp":      kindImage,
+	"image/gif":       kindImage,
+	"audio/mpeg":      kindAudio,
+	"audio/ogg":       kindAudio,
+	"audio/wav":       kindAudio,
+	"audio/webm":      kindAudio,
+	"application/pdf": kindDocument,
+	"application/zip": kindArchive,
+}
+
+// normalizeAttachmentMime folds the aliases browsers send for the same
+// format onto the canonical type.
+func normalizeAttachmentMime(mime string) string {
+	mime = strings.ToLower(strings.TrimSpace(strings.SplitN(mime, ";", 2)[0]))
+	switch mime {
+	case "application/x-zip-compressed", "application/x-zip":
+		return "application/zip"
+	case "audio/mp3":
+		return "audio/mpeg"
+	case "audio/x-wav", "audio/wave":
+		return "audio/wav"
+	}
+	return mime
+}
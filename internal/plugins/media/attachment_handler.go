@@ -0,0 +1,209 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// AttachmentTargetResolver reports which campaign an entity or session
+// belongs to and whether the member may see it, so an attachment can't be
+// listed, uploaded or downloaded through a page the member can't open.
+// Implemented via an adapter in app/routes.go over the entities and
+// sessions services.
+type AttachmentTargetResolver interface {
+	ResolveTarget(ctx context.Context, targetType, targetID string, role int, userID string) (campaignID string, canView bool, err error)
+}
+
+// AttachmentHandler handles the entity/session attachment endpoints.
+type AttachmentHandler struct {
+	service AttachmentService
+	targets AttachmentTargetResolver
+}
+
+// NewAttachmentHandler creates an attachment handler.
+func NewAttachmentHandler(service AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{service: service}
+}
+
+// SetTargetResolver wires the entity/session access check. Called during
+// wiring in app/routes.go; without it every request fails closed.
+func (h *AttachmentHandler) SetTargetResolver(r AttachmentTargetResolver) {
+	h.targets = r
+}
+
+// List returns a target's attachments as JSON
+// (GET /campaigns/:id/attachments?target_type=&target_id=).
+func (h *AttachmentHandler) List(c echo.Context) error {
+	cc, err := h.resolve(c, c.QueryParam("target_type"), c.QueryParam("target_id"))
+	if err != nil {
+		return err
+	}
+	list, err := h.service.List(c.Request().Context(), cc.Campaign.ID,
+		c.QueryParam("target_type"), c.QueryParam("target_id"), canSeeDMOnly(cc))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// Upload attaches a multipart file to an entity or session
+// (POST /campaigns/:id/attachments). Form fields: file, target_type,
+// target_id, title, visibility.
+func (h *AttachmentHandler) Upload(c echo.Context) error {
+	targetType, targetID := c.FormValue("target_type"), c.FormValue("target_id")
+	cc, err := h.resolve(c, targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return apperror.NewBadRequest("no file provided")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	defer func() { _ = src.Close() }()
+
+	fileBytes, err := io.ReadAll(src)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return apperror.NewBadRequest("file too large")
+		}
+		return apperror.NewInternal(err)
+	}
+
+	declaredMime := file.Header.Get("Content-Type")
+	if declaredMime == "" || declaredMime == "application/octet-stream" {
+		declaredMime = http.DetectContentType(fileBytes)
+	}
+
+	a, err := h.service.Upload(c.Request().Context(), AttachmentUploadInput{
+		CampaignID:   cc.Campaign.ID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		Title:        c.FormValue("title"),
+		Visibility:   c.FormValue("visibility"),
+		UploadedBy:   auth.GetUserID(c),
+		OriginalName: file.Filename,
+		MimeType:     declaredMime,
+		FileBytes:    fileBytes,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, a)
+}
+
+// Update changes an attachment's title or visibility
+// (PUT /campaigns/:id/attachments/:aid).
+func (h *AttachmentHandler) Update(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewNotFound("campaign not found")
+	}
+	var req UpdateAttachmentRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.service.Update(c.Request().Context(), cc.Campaign.ID, c.Param("aid"), req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, a)
+}
+
+// Delete removes an attachment (DELETE /campaigns/:id/attachments/:aid).
+func (h *AttachmentHandler) Delete(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewNotFound("campaign not found")
+	}
+	if err := h.service.Delete(c.Request().Context(), cc.Campaign.ID, c.Param("aid")); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Download streams an attachment (GET /campaigns/:id/attachments/:aid/download).
+// ?inline=1 previews PDFs, audio and images in the browser without
+// counting as a download; archives are always sent as a download.
+func (h *AttachmentHandler) Download(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewNotFound("campaign not found")
+	}
+	ctx := c.Request().Context()
+	inline := c.QueryParam("inline") == "1"
+
+	a, err := h.service.Get(ctx, cc.Campaign.ID, c.Param("aid"), canSeeDMOnly(cc))
+	if err != nil {
+		return err
+	}
+	// The attachment inherits its target's visibility: a file on an
+	// entity the player can't open is as hidden as the entity.
+	if _, err := h.resolve(c, a.TargetType, a.TargetID); err != nil {
+		return err
+	}
+	if !inline {
+		h.service.RecordDownload(ctx, a.ID)
+	}
+
+	disposition := "attachment"
+	if inline && a.Kind != kindArchive.name {
+		disposition = "inline"
+	}
+	resp := c.Response()
+	resp.Header().Set("Content-Type", a.MimeType)
+	resp.Header().Set("Content-Disposition",
+		fmt.Sprintf(`%s; filename="%s"`, disposition, sanitizeFilename(a.OriginalName)))
+	resp.Header().Set("X-Content-Type-Options", "nosniff")
+	resp.Header().Set("X-Frame-Options", "DENY")
+	resp.Header().Set("Content-Security-Policy", "default-src 'none'; img-src 'self'; media-src 'self'; style-src 'none'; script-src 'none'")
+	resp.Header().Set("Referrer-Policy", "no-referrer")
+	resp.Header().Set("Cache-Control", "private, no-store, max-age=0")
+	return c.File(h.service.FilePath(a))
+}
+
+// resolve checks the caller may see the target and that it belongs to the
+// campaign in the URL. Mismatches are 404s so IDs from other campaigns
+// aren't confirmed.
+func (h *AttachmentHandler) resolve(c echo.Context, targetType, targetID string) (*campaigns.CampaignContext, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, apperror.NewNotFound("campaign not found")
+	}
+	if h.targets == nil {
+		return nil, apperror.NewInternal(errors.New("attachment target resolver not configured"))
+	}
+	if !validAttachTarget(targetType) || strings.TrimSpace(targetID) == "" {
+		return nil, apperror.NewBadRequest("invalid attachment target")
+	}
+	campaignID, canView, err := h.targets.ResolveTarget(c.Request().Context(),
+		targetType, targetID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil {
+		return nil, err
+	}
+	if campaignID != cc.Campaign.ID || !canView {
+		return nil, apperror.NewNotFound("attachment target not found")
+	}
+	return cc, nil
+}
+
+// canSeeDMOnly reports whether the member sees dm_only attachments: Scribes
+// and above, or a player the owner granted DM access.
+func canSeeDMOnly(cc *campaigns.CampaignContext) bool {
+	return cc.MemberRole >= campaigns.RoleScribe || cc.IsDmGranted
+}
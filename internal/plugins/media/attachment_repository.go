@@ -0,0 +1,157 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// AttachmentRepository defines data access for entity/session attachments.
+type AttachmentRepository interface {
+	Create(ctx context.Context, a *Attachment) error
+
+	// FindByID returns an attachment with its media file's details, or
+	// (nil, nil) if it doesn't exist.
+	FindByID(ctx context.Context, id string) (*Attachment, error)
+
+	// ListByTarget returns a target's attachments, oldest first. dm_only
+	// rows are included only when includeDMOnly is set.
+	ListByTarget(ctx context.Context, campaignID, targetType, targetID string, includeDMOnly bool) ([]Attachment, error)
+
+	// Update saves the title and visibility.
+	Update(ctx context.Context, a *Attachment) error
+
+	Delete(ctx context.Context, id string) error
+
+	// CountByMedia returns how many attachments use a media file, so it is
+	// only removed from disk with its last attachment.
+	CountByMedia(ctx context.Context, mediaID string) (int, error)
+
+	// RecordDownload bumps the download counter.
+	RecordDownload(ctx context.Context, id string) error
+}
+
+const attachmentColumns = `a.id, a.campaign_id, a.media_id, a.target_type, a.target_id, a.title,
+	a.visibility, m.original_name, m.mime_type, m.file_size, m.filename, a.download_count,
+	a.last_downloaded_at, COALESCE(a.created_by, ''), a.created_at`
+
+const attachmentFrom = ` FROM media_attachments a JOIN media_files m ON m.id = a.media_id`
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates an attachment repository.
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, a *Attachment) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO media_attachments
+		   (id, campaign_id, media_id, target_type, target_id, title, visibility, created_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, a.CampaignID, a.MediaID, a.TargetType, a.TargetID, a.Title, a.Visibility,
+		a.CreatedBy, a.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("creating attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id string) (*Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRowContext(ctx,
+		`SELECT `+attachmentColumns+attachmentFrom+` WHERE a.id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding attachment: %w", err)
+	}
+	return a, nil
+}
+
+func (r *attachmentRepository) ListByTarget(ctx context.Context, campaignID, targetType, targetID string, includeDMOnly bool) ([]Attachment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+attachmentColumns+attachmentFrom+`
+		 WHERE a.campaign_id = ? AND a.target_type = ? AND a.target_id = ?
+		   AND (a.visibility = 'everyone' OR ?)
+		 ORDER BY a.created_at`,
+		campaignID, targetType, targetID, includeDMOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning attachment: %w", err)
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+func (r *attachmentRepository) Update(ctx context.Context, a *Attachment) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE media_attachments SET title = ?, visibility = ? WHERE id = ?`,
+		a.Title, a.Visibility, a.ID,
+	); err != nil {
+		return fmt.Errorf("updating attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM media_attachments WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("deleting attachment: %w", err)
+	}
+	return nil
+}
+
+func (r *attachmentRepository) CountByMedia(ctx context.Context, mediaID string) (int, error) {
+	var n int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM media_attachments WHERE media_id = ?`, mediaID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting attachments: %w", err)
+	}
+	return n, nil
+}
+
+func (r *attachmentRepository) RecordDownload(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE media_attachments
+		 SET download_count = download_count + 1, last_downloaded_at = UTC_TIMESTAMP()
+		 WHERE id = ?`, id,
+	); err != nil {
+		return fmt.Errorf("recording attachment download: %w", err)
+	}
+	return nil
+}
+
+// attachmentScanner is satisfied by *sql.Row and *sql.Rows.
+type attachmentScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAttachment(row attachmentScanner) (*Attachment, error) {
+	var (
+		a            Attachment
+		downloadedAt sql.NullTime
+	)
+	if err := row.Scan(&a.ID, &a.CampaignID, &a.MediaID, &a.TargetType, &a.TargetID, &a.Title,
+		&a.Visibility, &a.OriginalName, &a.MimeType, &a.FileSize, &a.Filename, &a.DownloadCount,
+		&downloadedAt, &a.CreatedBy, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	if downloadedAt.Valid {
+		a.LastDownloadedAt = &downloadedAt.Time
+	}
+	return &a, nil
+}
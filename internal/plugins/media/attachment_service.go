@@ -0,0 +1,252 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// AttachmentService manages files attached to entities and sessions. The
+// bytes live in media_files like any other upload; an attachment adds the
+// target, a title, visibility and a download counter on top.
+type AttachmentService interface {
+	// List returns a target's attachments. dm_only files are left out
+	// unless includeDMOnly is set.
+	List(ctx context.Context, campaignID, targetType, targetID string, includeDMOnly bool) ([]Attachment, error)
+
+	// Upload stores the file and attaches it to the target. The caller has
+	// already checked that the target exists in campaignID.
+	Upload(ctx context.Context, input AttachmentUploadInput) (*Attachment, error)
+
+	// Update changes the title and visibility.
+	Update(ctx context.Context, campaignID, id string, req UpdateAttachmentRequest) (*Attachment, error)
+
+	// Delete removes the attachment, and its media file once nothing else
+	// uses it.
+	Delete(ctx context.Context, campaignID, id string) error
+
+	// Get returns one attachment. A dm_only file is NotFound unless
+	// includeDMOnly is set.
+	Get(ctx context.Context, campaignID, id string, includeDMOnly bool) (*Attachment, error)
+
+	// RecordDownload bumps the download counter. Failures are logged, not
+	// returned: a lost count shouldn't cost the player the file.
+	RecordDownload(ctx context.Context, id string)
+
+	// FilePath returns the attachment's path on disk.
+	FilePath(a *Attachment) string
+}
+
+type attachmentService struct {
+	repo  AttachmentRepository
+	media MediaService
+}
+
+// NewAttachmentService creates an attachment service on top of the media
+// service, which does the storage, quota and content checks.
+func NewAttachmentService(repo AttachmentRepository, media MediaService) AttachmentService {
+	return &attachmentService{repo: repo, media: media}
+}
+
+func (s *attachmentService) List(ctx context.Context, campaignID, targetType, targetID string, includeDMOnly bool) ([]Attachment, error) {
+	if !validAttachTarget(targetType) || targetID == "" {
+		return nil, apperror.NewBadRequest("invalid attachment target")
+	}
+	list, err := s.repo.ListByTarget(ctx, campaignID, targetType, targetID, includeDMOnly)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if list == nil {
+		list = []Attachment{}
+	}
+	for i := range list {
+		decorateAttachment(&list[i])
+	}
+	return list, nil
+}
+
+func (s *attachmentService) Upload(ctx context.Context, input AttachmentUploadInput) (*Attachment, error) {
+	if !validAttachTarget(input.TargetType) || input.TargetID == "" {
+		return nil, apperror.NewBadRequest("invalid attachment target")
+	}
+	visibility, err := normalizeVisibility(input.Visibility)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.FileBytes) == 0 {
+		return nil, apperror.NewBadRequest("no file provided")
+	}
+
+	mime := normalizeAttachmentMime(input.MimeType)
+	kind, ok := attachmentKinds[mime]
+	if !ok {
+		return nil, apperror.NewBadRequest("this file type can't be attached; use a PDF, audio file, image or zip archive")
+	}
+	if int64(len(input.FileBytes)) > kind.maxSize {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("%s attachments are limited to %d MB", kind.name, kind.maxSize>>20))
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		title = strings.TrimSpace(input.OriginalName)
+	}
+	title = truncateRunes(title, maxAttachmentTitle)
+
+	file, err := s.media.Upload(ctx, UploadInput{
+		CampaignID:   input.CampaignID,
+		UploadedBy:   input.UploadedBy,
+		OriginalName: input.OriginalName,
+		MimeType:     mime,
+		FileSize:     int64(len(input.FileBytes)),
+		UsageType:    UsageHandout,
+		FileBytes:    input.FileBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Attachment{
+		ID:           generateUUID(),
+		CampaignID:   input.CampaignID,
+		MediaID:      file.ID,
+		TargetType:   input.TargetType,
+		TargetID:     input.TargetID,
+		Title:        title,
+		Visibility:   visibility,
+		OriginalName: file.OriginalName,
+		MimeType:     file.MimeType,
+		FileSize:     file.FileSize,
+		Filename:     file.Filename,
+		CreatedBy:    input.UploadedBy,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	decorateAttachment(a)
+	return a, nil
+}
+
+func (s *attachmentService) Update(ctx context.Context, campaignID, id string, req UpdateAttachmentRequest) (*Attachment, error) {
+	a, err := s.find(ctx, campaignID, id, true)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := normalizeVisibility(req.Visibility)
+	if err != nil {
+		return nil, err
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		a.Title = truncateRunes(title, maxAttachmentTitle)
+	}
+	a.Visibility = visibility
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	decorateAttachment(a)
+	return a, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, campaignID, id string) error {
+	a, err := s.find(ctx, campaignID, id, true)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, a.ID); err != nil {
+		return apperror.NewInternal(err)
+	}
+
+	// Upload dedup means the same bytes can back several attachments, or
+	// an image already embedded in an entry. Only remove the file when
+	// this was its last use; a leftover file is harmless, a missing one
+	// breaks another page.
+	remaining, err := s.repo.CountByMedia(ctx, a.MediaID)
+	if err != nil || remaining > 0 {
+		return nil
+	}
+	file, err := s.media.GetByID(ctx, a.MediaID)
+	if err != nil || file.UsageType != UsageHandout {
+		return nil
+	}
+	if refs, err := s.media.FindReferences(ctx, campaignID, a.MediaID); err != nil || len(refs) > 0 {
+		return nil
+	}
+	if err := s.media.Delete(ctx, a.MediaID); err != nil {
+		slog.Warn("attachment delete: removing media file failed",
+			slog.String("media_id", a.MediaID),
+			slog.Any("error", err),
+		)
+	}
+	return nil
+}
+
+func (s *attachmentService) Get(ctx context.Context, campaignID, id string, includeDMOnly bool) (*Attachment, error) {
+	a, err := s.find(ctx, campaignID, id, includeDMOnly)
+	if err != nil {
+		return nil, err
+	}
+	decorateAttachment(a)
+	return a, nil
+}
+
+func (s *attachmentService) RecordDownload(ctx context.Context, id string) {
+	if err := s.repo.RecordDownload(ctx, id); err != nil {
+		slog.Warn("attachment download: recording failed",
+			slog.String("attachment_id", id),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (s *attachmentService) FilePath(a *Attachment) string {
+	return s.media.FilePath(&MediaFile{Filename: a.Filename})
+}
+
+// find loads an attachment and checks it belongs to the campaign. A
+// dm_only file is reported as missing to anyone who may not see it.
+func (s *attachmentService) find(ctx context.Context, campaignID, id string, includeDMOnly bool) (*Attachment, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if a == nil || a.CampaignID != campaignID || (a.Visibility == AttachVisibleDMOnly && !includeDMOnly) {
+		return nil, apperror.NewNotFound("attachment not found")
+	}
+	return a, nil
+}
+
+func validAttachTarget(t string) bool {
+	return t == AttachTargetEntity || t == AttachTargetSession
+}
+
+func normalizeVisibility(v string) (string, error) {
+	switch v {
+	case "", AttachVisibleEveryone:
+		return AttachVisibleEveryone, nil
+	case AttachVisibleDMOnly:
+		return AttachVisibleDMOnly, nil
+	}
+	return "", apperror.NewBadRequest("visibility must be everyone or dm_only")
+}
+
+// decorateAttachment fills in the client-facing kind and download URL.
+func decorateAttachment(a *Attachment) {
+	if k, ok := attachmentKinds[a.MimeType]; ok {
+		a.Kind = k.name
+	} else {
+		a.Kind = "file"
+	}
+	a.DownloadURL = "/campaigns/" + a.CampaignID + "/attachments/" + a.ID + "/download"
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
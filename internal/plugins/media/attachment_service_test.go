@@ -0,0 +1,190 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// stubAttachmentRepo keeps attachments in memory. ListByTarget is unused by
+// these tests; the visibility filter it encodes is covered through find.
+type stubAttachmentRepo struct {
+	rows      map[string]*Attachment
+	downloads int
+}
+
+func (r *stubAttachmentRepo) Create(_ context.Context, a *Attachment) error {
+	if r.rows == nil {
+		r.rows = map[string]*Attachment{}
+	}
+	cp := *a
+	r.rows[a.ID] = &cp
+	return nil
+}
+
+func (r *stubAttachmentRepo) FindByID(_ context.Context, id string) (*Attachment, error) {
+	a, ok := r.rows[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *a
+	return &cp, nil
+}
+
+func (r *stubAttachmentRepo) ListByTarget(context.Context, string, string, string, bool) ([]Attachment, error) {
+	return nil, nil
+}
+
+func (r *stubAttachmentRepo) Update(ctx context.Context, a *Attachment) error {
+	return r.Create(ctx, a)
+}
+func (r *stubAttachmentRepo) Delete(_ context.Context, id string) error {
+	delete(r.rows, id)
+	return nil
+}
+
+func (r *stubAttachmentRepo) CountByMedia(_ context.Context, mediaID string) (int, error) {
+	n := 0
+	for _, a := range r.rows {
+		if a.MediaID == mediaID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *stubAttachmentRepo) RecordDownload(context.Context, string) error {
+	r.downloads++
+	return nil
+}
+
+// stubAttachmentMedia overrides the MediaService calls attachments make;
+// anything else panics on the nil embedded interface.
+type stubAttachmentMedia struct {
+	MediaService
+	uploaded []UploadInput
+	deleted  []string
+}
+
+func (m *stubAttachmentMedia) Upload(_ context.Context, in UploadInput) (*MediaFile, error) {
+	m.uploaded = append(m.uploaded, in)
+	return &MediaFile{ID: "m1", Filename: "2026/10/m1.pdf", OriginalName: in.OriginalName,
+		MimeType: in.MimeType, FileSize: in.FileSize, UsageType: in.UsageType}, nil
+}
+
+func (m *stubAttachmentMedia) GetByID(_ context.Context, id string) (*MediaFile, error) {
+	return &MediaFile{ID: id, UsageType: UsageHandout}, nil
+}
+
+func (m *stubAttachmentMedia) FindReferences(context.Context, string, string) ([]MediaRef, error) {
+	return nil, nil
+}
+
+func (m *stubAttachmentMedia) Delete(_ context.Context, id string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func TestAttachmentUpload_Validation(t *testing.T) {
+	pdf := []byte("%PDF-1.7 handout")
+	cases := []struct {
+		name  string
+		input AttachmentUploadInput
+		want  int // 0 = success
+	}{
+		{"pdf handout", AttachmentUploadInput{TargetType: "entity", TargetID: "e1", MimeType: "application/pdf", FileBytes: pdf}, 0},
+		{"zip alias", AttachmentUploadInput{TargetType: "session", TargetID: "s1", MimeType: "application/x-zip-compressed", FileBytes: []byte("PK\x03\x04")}, 0},
+		{"html refused", AttachmentUploadInput{TargetType: "entity", TargetID: "e1", MimeType: "text/html", FileBytes: []byte("<p>")}, 400},
+		{"unknown target", AttachmentUploadInput{TargetType: "map", TargetID: "m1", MimeType: "application/pdf", FileBytes: pdf}, 400},
+		{"bad visibility", AttachmentUploadInput{TargetType: "entity", TargetID: "e1", Visibility: "hidden", MimeType: "application/pdf", FileBytes: pdf}, 400},
+		{"pdf over its cap", AttachmentUploadInput{TargetType: "entity", TargetID: "e1", MimeType: "application/pdf",
+			FileBytes: bytes.Repeat([]byte{0}, int(kindDocument.maxSize)+1)}, 400},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.input.CampaignID, tc.input.UploadedBy, tc.input.OriginalName = "c1", "gm", "Letter.pdf"
+			media := &stubAttachmentMedia{}
+			svc := NewAttachmentService(&stubAttachmentRepo{}, media)
+
+			a, err := svc.Upload(context.Background(), tc.input)
+			if tc.want != 0 {
+				assertMediaAppError(t, err, tc.want)
+				if len(media.uploaded) != 0 {
+					t.Error("rejected upload still reached media storage")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			in := media.uploaded[0]
+			if in.UsageType != UsageHandout || !HandoutMimeTypes[in.MimeType] {
+				t.Errorf("media upload = %+v, want a handout with a canonical MIME type", in)
+			}
+			if a.Title != "Letter.pdf" || a.Visibility != AttachVisibleEveryone || a.DownloadURL == "" {
+				t.Errorf("attachment = %+v", a)
+			}
+		})
+	}
+}
+
+func TestAttachmentAccess(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAttachmentRepo{}
+	media := &stubAttachmentMedia{}
+	svc := NewAttachmentService(repo, media)
+
+	secret, err := svc.Upload(ctx, AttachmentUploadInput{CampaignID: "c1", TargetType: "entity", TargetID: "e1",
+		Visibility: AttachVisibleDMOnly, OriginalName: "map.zip", MimeType: "application/zip", FileBytes: []byte("PK\x05\x06")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// dm_only is invisible, not forbidden, to players and other campaigns.
+	if _, err := svc.Get(ctx, "c1", secret.ID, false); err == nil {
+		t.Error("player fetched a dm_only attachment")
+	}
+	if _, err := svc.Get(ctx, "c2", secret.ID, true); err == nil {
+		t.Error("attachment reachable from another campaign")
+	}
+	if _, err := svc.Get(ctx, "c1", secret.ID, true); err != nil {
+		t.Errorf("GM get: %v", err)
+	}
+
+	// Revealing the file makes it visible to players.
+	if _, err := svc.Update(ctx, "c1", secret.ID, UpdateAttachmentRequest{Visibility: AttachVisibleEveryone}); err != nil {
+		t.Fatal(err)
+	}
+	if a, err := svc.Get(ctx, "c1", secret.ID, false); err != nil || a.Kind != "archive" {
+		t.Errorf("revealed attachment = %+v, %v", a, err)
+	}
+}
+
+func TestAttachmentDelete_KeepsSharedMedia(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubAttachmentRepo{}
+	media := &stubAttachmentMedia{}
+	svc := NewAttachmentService(repo, media)
+
+	// The stub dedups everything onto media file m1, like re-uploading the
+	// same handout to two sessions.
+	in := AttachmentUploadInput{CampaignID: "c1", TargetType: "session", OriginalName: "recap.pdf",
+		MimeType: "application/pdf", FileBytes: []byte("%PDF-")}
+	in.TargetID = "s1"
+	first, _ := svc.Upload(ctx, in)
+	in.TargetID = "s2"
+	second, _ := svc.Upload(ctx, in)
+
+	if err := svc.Delete(ctx, "c1", first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(media.deleted) != 0 {
+		t.Fatalf("media deleted while still attached elsewhere: %v", media.deleted)
+	}
+	if err := svc.Delete(ctx, "c1", second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(media.deleted) != 1 || media.deleted[0] != "m1" {
+		t.Errorf("deleted media = %v, want [m1]", media.deleted)
+	}
+}
@@ -37,6 +37,7 @@ type Handler struct {
 	signer         *URLSigner
 	memberChecker  MemberChecker
 	securityLogger SecurityEventLogger
+	imageProxy     *ImageProxy
 }
 
 // NewHandler creates a new media handler.
@@ -50,6 +51,12 @@ func (h *Handler) SetURLSigner(signer *URLSigner) {
 	h.signer = signer
 }
 
+// SetImageProxy enables the external image proxy endpoint. Called during
+// wiring in app/routes.go.
+func (h *Handler) SetImageProxy(proxy *ImageProxy) {
+	h.imageProxy = proxy
+}
+
 // SetMemberChecker sets the campaign membership checker for access control
 // on private campaign media. Called during wiring in app/routes.go.
 func (h *Handler) SetMemberChecker(checker MemberChecker) {
@@ -128,6 +135,11 @@ func (h *Handler) Upload(c echo.Context) error {
 	if input.UsageType == "" {
 		input.UsageType = UsageAttachment
 	}
+	// Handouts carry document types the generic upload refuses; they only
+	// come in through the attachment endpoint, which checks the target.
+	if input.UsageType == UsageHandout {
+		return apperror.NewBadRequest("upload handouts as entity or session attachments")
+	}
 
 	mediaFile, err := h.service.Upload(c.Request().Context(), input)
 	if err != nil {
@@ -324,6 +336,49 @@ func (h *Handler) allowUnsignedAccess(c echo.Context, file *MediaFile) bool {
 	return false
 }
 
+// ServeProxiedImage serves an external image through the proxy
+// (GET /campaigns/:id/image-proxy?u=...&s=...). The URL must carry our
+// signature for this campaign, and the campaign must still have the proxy
+// on with the host allowed: an owner switching it off stops serving at once.
+func (h *Handler) ServeProxiedImage(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewMissingContext()
+	}
+	if h.imageProxy == nil {
+		return apperror.NewNotFound("image not found")
+	}
+
+	src := c.QueryParam("u")
+	if !h.imageProxy.Verify(cc.Campaign.ID, src, c.QueryParam("s")) {
+		return apperror.NewNotFound("image not found")
+	}
+	host, ok := externalImageHost(src)
+	if !ok || !cc.Campaign.ParseSettings().GetImageProxy().AllowsHost(host) {
+		return apperror.NewNotFound("image not found")
+	}
+
+	img, err := h.imageProxy.Fetch(c.Request().Context(), src)
+	if err != nil {
+		return &apperror.AppError{
+			Code:    http.StatusBadGateway,
+			Type:    "bad_gateway",
+			Message: "the image could not be loaded from its source",
+		}
+	}
+
+	hdr := c.Response().Header()
+	hdr.Set("X-Content-Type-Options", "nosniff")
+	hdr.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	hdr.Set("Referrer-Policy", "no-referrer")
+	if cc.Campaign.IsPublic {
+		hdr.Set("Cache-Control", "public, max-age=86400")
+	} else {
+		hdr.Set("Cache-Control", "private, max-age=3600")
+	}
+	return c.Blob(http.StatusOK, img.ContentType, img.Data)
+}
+
 // setSecurityHeaders applies defense-in-depth headers to media responses.
 func (h *Handler) setSecurityHeaders(c echo.Context, file *MediaFile) {
 	resp := c.Response()
@@ -488,6 +543,9 @@ func (h *Handler) CampaignMediaList(c echo.Context) error {
 	type mediaListItem struct {
 		ID           string    `json:"id"`
 		OriginalName string    `json:"original_name"`
+		AltText      string    `json:"alt_text,omitempty"`
+		Caption      string    `json:"caption,omitempty"`
+		Credit       string    `json:"credit,omitempty"`
 		MimeType     string    `json:"mime_type"`
 		FileSize     int64     `json:"file_size"`
 		URL          string    `json:"url"`
@@ -501,6 +559,9 @@ func (h *Handler) CampaignMediaList(c echo.Context) error {
 		item := mediaListItem{
 			ID:           f.ID,
 			OriginalName: f.OriginalName,
+			AltText:      f.AltText,
+			Caption:      f.Caption,
+			Credit:       f.Credit,
 			MimeType:     f.MimeType,
 			FileSize:     f.FileSize,
 			CreatedAt:    f.CreatedAt,
@@ -610,6 +671,31 @@ func (h *Handler) CampaignDeleteMedia(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+// CampaignUpdateMediaText sets an image's alt text and caption
+// (PUT /campaigns/:id/media/:mid/text). The media browser's HTMX form gets
+// the re-rendered card back; JSON callers get the file.
+func (h *Handler) CampaignUpdateMediaText(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewNotFound("campaign not found")
+	}
+
+	var input UpdateTextInput
+	if err := c.Bind(&input); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	file, err := h.service.UpdateCampaignMediaText(c.Request().Context(), cc.Campaign.ID, c.Param("mid"), input, cc.Campaign.IsPublic)
+	if err != nil {
+		return err
+	}
+
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, mediaCard(cc, *file, middleware.GetCSRFToken(c)))
+	}
+	return c.JSON(http.StatusOK, file)
+}
+
 // CampaignMediaRefs returns an HTMX fragment showing which entities reference
 // a media file (GET /campaigns/:id/media/:mid/refs).
 func (h *Handler) CampaignMediaRefs(c echo.Context) error {
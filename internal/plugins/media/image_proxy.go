@@ -0,0 +1,370 @@
+package media
+
+// image_proxy.go — fetches external images embedded in entries and serves
+// them from Chronicle's own domain. The page CSP only allows same-origin
+// images, so without the proxy an external image never loads (and never
+// leaks a viewer's IP to the remote host). With the campaign's proxy on,
+// entry content is rewritten on the way out to signed
+// /campaigns/:id/image-proxy URLs and back again on save, so stored entries
+// always keep the author's original URLs.
+//
+// URLs are HMAC-signed per campaign so the endpoint can't be used as an open
+// proxy, connections to non-public addresses are refused at dial time (which
+// also covers redirects and DNS rebinding), and fetched images are cached on
+// disk for a day.
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+const (
+	// imageProxyMaxBytes caps one proxied image.
+	imageProxyMaxBytes = 10 << 20
+
+	// imageProxyMaxURLLength rejects absurd URLs before signing or fetching.
+	imageProxyMaxURLLength = 2048
+
+	// imageProxyTimeout bounds one upstream fetch, including redirects.
+	imageProxyTimeout = 15 * time.Second
+
+	// imageProxyMaxRedirects is how many redirects a fetch follows.
+	imageProxyMaxRedirects = 3
+
+	// imageProxyCacheTTL is how long a fetched image is served from disk.
+	imageProxyCacheTTL = 24 * time.Hour
+
+	// imageProxyPurgeInterval is how often expired cache files are removed.
+	imageProxyPurgeInterval = time.Hour
+)
+
+// imageProxyTypes are the content types the proxy will serve. SVG is left
+// out on purpose: it can carry script.
+var imageProxyTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/avif": true,
+}
+
+// errImageProxyFetch is returned for any upstream failure; the detail is
+// logged, not shown.
+var errImageProxyFetch = errors.New("image proxy: fetch failed")
+
+// ProxiedImage is an image ready to serve.
+type ProxiedImage struct {
+	ContentType string
+	Data        []byte
+}
+
+// ImageProxy signs, rewrites and fetches proxied external images.
+type ImageProxy struct {
+	secret   []byte
+	cacheDir string
+	client   *http.Client
+	now      func() time.Time
+
+	// allowIP decides which addresses the fetcher may connect to. Tests
+	// relax it to reach an httptest server on loopback.
+	allowIP func(net.IP) bool
+}
+
+// NewImageProxy creates an image proxy. secret keys the URL signatures;
+// cacheDir holds fetched images and is created on first write.
+func NewImageProxy(secret, cacheDir string) *ImageProxy {
+	key := sha256.Sum256([]byte("chronicle-image-proxy:" + secret))
+	p := &ImageProxy{
+		secret:   key[:],
+		cacheDir: cacheDir,
+		now:      time.Now,
+		allowIP:  isPublicIP,
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: p.dialControl}
+	p.client = &http.Client{
+		Timeout: imageProxyTimeout,
+		Transport: &http.Transport{
+			// No Proxy: an outbound proxy would do the dialing and bypass
+			// the address check.
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= imageProxyMaxRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errors.New("redirect to a non-http URL")
+			}
+			return nil
+		},
+	}
+	return p
+}
+
+// dialControl refuses connections to loopback, private, link-local and
+// other non-public addresses. It runs on the resolved address, so a public
+// name pointing at an internal IP is caught too.
+func (p *ImageProxy) dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !p.allowIP(ip) {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+// cgnatRange is carrier-grade NAT space, not covered by net.IP.IsPrivate.
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether ip is a globally routable unicast address.
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || cgnatRange.Contains(ip))
+}
+
+// externalImageHost returns the host of an absolute http(s) image URL, or
+// false for anything else (local paths, data: URIs, our own media).
+func externalImageHost(src string) (string, bool) {
+	if len(src) > imageProxyMaxURLLength {
+		return "", false
+	}
+	u, err := url.Parse(src)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return "", false
+	}
+	host := u.Hostname()
+	return host, host != ""
+}
+
+// proxyPath is the campaign's proxy endpoint.
+func proxyPath(campaignID string) string {
+	return "/campaigns/" + campaignID + "/image-proxy"
+}
+
+// sign returns the URL signature for src in a campaign.
+func (p *ImageProxy) sign(campaignID, src string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(campaignID + "\n" + src))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a proxy URL signature.
+func (p *ImageProxy) Verify(campaignID, src, sig string) bool {
+	return hmac.Equal([]byte(sig), []byte(p.sign(campaignID, src)))
+}
+
+// URL returns the signed proxy URL for an external image.
+func (p *ImageProxy) URL(campaignID, src string) string {
+	return proxyPath(campaignID) + "?u=" + url.QueryEscape(src) + "&s=" + p.sign(campaignID, src)
+}
+
+// DisplaySrc returns the image source rewriter for showing a campaign's
+// content: external images from allowed hosts become proxy URLs, anything
+// else is left alone (and stays blocked by the CSP if external).
+func (p *ImageProxy) DisplaySrc(campaignID string, cfg campaigns.ImageProxySettings) func(src string) string {
+	return func(src string) string {
+		host, ok := externalImageHost(src)
+		if !ok || !cfg.AllowsHost(host) {
+			return src
+		}
+		return p.URL(campaignID, src)
+	}
+}
+
+// StoredSrc returns the rewriter that undoes DisplaySrc before content is
+// saved. Only validly signed proxy URLs for this campaign are unwrapped.
+func (p *ImageProxy) StoredSrc(campaignID string) func(src string) string {
+	prefix := proxyPath(campaignID) + "?"
+	return func(src string) string {
+		query, ok := strings.CutPrefix(src, prefix)
+		if !ok {
+			return src
+		}
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			return src
+		}
+		orig := q.Get("u")
+		if orig == "" || !p.Verify(campaignID, orig, q.Get("s")) {
+			return src
+		}
+		return orig
+	}
+}
+
+// Fetch returns the image at src, from the disk cache when fresh.
+func (p *ImageProxy) Fetch(ctx context.Context, src string) (*ProxiedImage, error) {
+	if _, ok := externalImageHost(src); !ok {
+		return nil, errImageProxyFetch
+	}
+	path := p.cachePath(src)
+	if img := p.readCache(path); img != nil {
+		return img, nil
+	}
+
+	img, err := p.fetchRemote(ctx, src)
+	if err != nil {
+		slog.Info("image proxy: fetch failed", slog.String("url", src), slog.Any("error", err))
+		return nil, errImageProxyFetch
+	}
+	if err := p.writeCache(path, img); err != nil {
+		slog.Warn("image proxy: caching failed", slog.Any("error", err))
+	}
+	return img, nil
+}
+
+// fetchRemote downloads and checks one image.
+func (p *ImageProxy) fetchRemote(ctx context.Context, src string) (*ProxiedImage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/avif,image/webp,image/png,image/jpeg,image/gif")
+	req.Header.Set("User-Agent", "Chronicle-ImageProxy/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream returned %d", resp.StatusCode)
+	}
+	if resp.ContentLength > imageProxyMaxBytes {
+		return nil, fmt.Errorf("image is %d bytes", resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, imageProxyMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > imageProxyMaxBytes {
+		return nil, errors.New("image exceeds the size limit")
+	}
+
+	contentType, err := imageContentType(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxiedImage{ContentType: contentType, Data: data}, nil
+}
+
+// imageContentType decides what we serve the bytes as. The sniffed type
+// wins over the upstream header so an HTML page labelled image/png is
+// refused; AVIF is the exception since the sniffer doesn't know it.
+func imageContentType(header string, data []byte) (string, error) {
+	sniffed := http.DetectContentType(data)
+	if imageProxyTypes[sniffed] {
+		return sniffed, nil
+	}
+	declared, _, _ := mime.ParseMediaType(header)
+	if declared == "image/avif" && sniffed == "application/octet-stream" {
+		return declared, nil
+	}
+	return "", fmt.Errorf("unsupported content type %q (declared %q)", sniffed, header)
+}
+
+// cachePath maps a URL to its cache file.
+func (p *ImageProxy) cachePath(src string) string {
+	sum := sha256.Sum256([]byte(src))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(p.cacheDir, key[:2], key)
+}
+
+// readCache returns a fresh cached image, or nil. Cache files hold the
+// content type, a newline, then the image bytes.
+func (p *ImageProxy) readCache(path string) *ProxiedImage {
+	info, err := os.Stat(path)
+	if err != nil || p.now().Sub(info.ModTime()) > imageProxyCacheTTL {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	contentType, data, ok := bytes.Cut(raw, []byte("\n"))
+	if !ok || !imageProxyTypes[string(contentType)] {
+		return nil
+	}
+	return &ProxiedImage{ContentType: string(contentType), Data: data}
+}
+
+// writeCache stores an image atomically so readers never see a partial file.
+func (p *ImageProxy) writeCache(path string, img *ProxiedImage) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(append([]byte(img.ContentType+"\n"), img.Data...))
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return errors.Join(werr, cerr)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Start removes expired cache files hourly until ctx is cancelled.
+func (p *ImageProxy) Start(ctx context.Context) {
+	ticker := time.NewTicker(imageProxyPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n := p.purgeCache(); n > 0 {
+				slog.Info("image proxy: purged cached images", slog.Int("count", n))
+			}
+		}
+	}
+}
+
+// purgeCache deletes cache files older than the TTL and returns how many.
+func (p *ImageProxy) purgeCache() int {
+	cutoff := p.now().Add(-imageProxyCacheTTL)
+	removed := 0
+	_ = filepath.WalkDir(p.cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().Before(cutoff) {
+			if os.Remove(path) == nil {
+				removed++
+			}
+		}
+		return nil
+	})
+	return removed
+}
@@ -0,0 +1,120 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageProxy_RewriteRoundTrip(t *testing.T) {
+	p := NewImageProxy("secret", t.TempDir())
+	cfg := campaigns.ImageProxySettings{Enabled: true, AllowedHosts: []string{"imgur.com"}}
+	display := p.DisplaySrc("camp-1", cfg)
+	stored := p.StoredSrc("camp-1")
+
+	src := "https://imgur.com/a.png?x=1&y=2"
+	proxied := display(src)
+	if !strings.HasPrefix(proxied, "/campaigns/camp-1/image-proxy?u=") {
+		t.Fatalf("allowed image not proxied: %s", proxied)
+	}
+	if got := stored(proxied); got != src {
+		t.Errorf("StoredSrc(DisplaySrc(src)) = %q, want %q", got, src)
+	}
+
+	for _, keep := range []string{"https://other.com/a.png", "/media/abc", "data:image/png;base64,AAAA"} {
+		if got := display(keep); got != keep {
+			t.Errorf("DisplaySrc(%q) = %q, want unchanged", keep, got)
+		}
+	}
+
+	// Another campaign's URL, or a forged signature, is left as-is.
+	if got := p.StoredSrc("camp-2")(proxied); got != proxied {
+		t.Errorf("another campaign unwrapped the URL: %q", got)
+	}
+	forged := "/campaigns/camp-1/image-proxy?u=https%3A%2F%2Fevil.com%2Fx.png&s=bogus"
+	if got := stored(forged); got != forged {
+		t.Errorf("forged URL unwrapped: %q", got)
+	}
+	if NewImageProxy("other", "").Verify("camp-1", src, p.sign("camp-1", src)) {
+		t.Error("signature valid under a different secret")
+	}
+}
+
+func TestImageProxy_Fetch(t *testing.T) {
+	pngData := testPNG(t)
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch r.URL.Path {
+		case "/ok.png":
+			_, _ = w.Write(pngData)
+		case "/page.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("<html><script>alert(1)</script></html>"))
+		case "/svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`))
+		case "/huge.png":
+			_, _ = w.Write(append(pngData, make([]byte, imageProxyMaxBytes)...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewImageProxy("secret", t.TempDir())
+	p.allowIP = func(net.IP) bool { return true }
+	ctx := context.Background()
+
+	img, err := p.Fetch(ctx, srv.URL+"/ok.png")
+	if err != nil || img.ContentType != "image/png" || !bytes.Equal(img.Data, pngData) {
+		t.Fatalf("Fetch(ok.png) = %+v, %v", img, err)
+	}
+	if _, err := p.Fetch(ctx, srv.URL+"/ok.png"); err != nil || hits != 1 {
+		t.Errorf("second fetch: err = %v, upstream hits = %d, want served from cache", err, hits)
+	}
+
+	for _, path := range []string{"/page.png", "/svg", "/huge.png", "/missing.png"} {
+		if _, err := p.Fetch(ctx, srv.URL+path); err == nil {
+			t.Errorf("Fetch(%s) succeeded, want refused", path)
+		}
+	}
+}
+
+func TestImageProxy_RefusesInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("proxy connected to a loopback server")
+	}))
+	defer srv.Close()
+
+	p := NewImageProxy("secret", t.TempDir())
+	if _, err := p.Fetch(context.Background(), srv.URL+"/a.png"); err == nil {
+		t.Fatal("fetch from loopback succeeded")
+	}
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "100.64.0.1", "::1", "fd00::1"} {
+		if isPublicIP(net.ParseIP(ip)) {
+			t.Errorf("isPublicIP(%s) = true", ip)
+		}
+	}
+	if !isPublicIP(net.ParseIP("93.184.216.34")) {
+		t.Error("isPublicIP rejected a public address")
+	}
+}
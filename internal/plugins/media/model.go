@@ -16,6 +16,9 @@ type MediaFile struct {
 	UploadedBy     string            `json:"uploaded_by"`
 	Filename       string            `json:"filename"`       // UUID-based filename on disk.
 	OriginalName   string            `json:"original_name"`  // User's original filename.
+	AltText        string            `json:"alt_text,omitempty"` // Screen-reader description (images).
+	Caption        string            `json:"caption,omitempty"`
+	Credit         string            `json:"credit,omitempty"` // Who made the image, e.g. "Map by J. Doe".
 	MimeType       string            `json:"mime_type"`
 	FileSize       int64             `json:"file_size"`
 	// ContentHash is the sha256 of the original file bytes (hex). Populated
@@ -70,6 +73,14 @@ var AllowedMimeTypes = map[string]bool{
 	"audio/webm": true,
 }
 
+// HandoutMimeTypes are accepted only for attachment uploads (UsageHandout),
+// never as entity images or avatars, so a PDF or archive can't end up
+// rendered in an <img> tag or counted as a picture.
+var HandoutMimeTypes = map[string]bool{
+	"application/pdf": true,
+	"application/zip": true, // token packs, map bundles
+}
+
 // MimeToExtension maps MIME types to file extensions.
 var MimeToExtension = map[string]string{
 	"image/jpeg": ".jpg",
@@ -80,6 +91,32 @@ var MimeToExtension = map[string]string{
 	"audio/ogg":  ".ogg",
 	"audio/wav":  ".wav",
 	"audio/webm": ".webm",
+
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+}
+
+// Limits on an asset's description, matching the entity header image's.
+const (
+	MaxAltTextLength = 300
+	MaxCaptionLength = 500
+	MaxCreditLength  = 300
+)
+
+// UpdateTextInput is the alt text, caption and credit for a library asset.
+type UpdateTextInput struct {
+	AltText string `json:"alt_text" form:"alt_text"`
+	Caption string `json:"caption" form:"caption"`
+	Credit  string `json:"credit" form:"credit"`
+}
+
+// DisplayAlt is the alt text to render: the description, or the original
+// file name for assets that don't have one yet.
+func (f *MediaFile) DisplayAlt() string {
+	if f.AltText != "" {
+		return f.AltText
+	}
+	return f.OriginalName
 }
 
 // IsImage returns true if the file is an image based on MIME type.
@@ -101,6 +138,8 @@ const (
 	UsageEntityImage = "entity_image"
 	UsageAvatar      = "avatar"
 	UsageBackdrop    = "backdrop"
+	UsageHandout     = "handout" // uploaded through an entity/session attachment
+	UsageBranding    = "branding" // instance logo/favicon, uploaded from /admin/branding
 )
 
 // MediaRef is a lightweight reference from an entity to a media file.
@@ -49,6 +49,8 @@ type MediaRepository interface {
 	// hash inline so this is rarely called outside of backfill.
 	SetContentHash(ctx context.Context, id, hash string) error
 	Delete(ctx context.Context, id string) error
+	// UpdateText sets a file's alt text, caption and credit ("" stores NULL).
+	UpdateText(ctx context.Context, id, altText, caption, credit string) error
 	ListByCampaign(ctx context.Context, campaignID string, limit, offset int) ([]MediaFile, int, error)
 	GetStorageStats(ctx context.Context) (*StorageStats, error)
 	ListAll(ctx context.Context, limit, offset int) ([]AdminMediaFile, int, error)
@@ -117,6 +119,7 @@ func (r *mediaRepository) Create(ctx context.Context, file *MediaFile) error {
 // lookups when the serve handler checks campaign privacy.
 func (r *mediaRepository) FindByID(ctx context.Context, id string) (*MediaFile, error) {
 	query := `SELECT m.id, m.campaign_id, m.uploaded_by, m.filename, m.original_name,
+	                 COALESCE(m.alt_text, ''), COALESCE(m.caption, ''), COALESCE(m.credit, ''),
 	                 m.mime_type, m.file_size, m.content_hash, m.usage_type, m.thumbnail_paths, m.created_at,
 	                 c.is_public
 	          FROM media_files m
@@ -128,7 +131,7 @@ func (r *mediaRepository) FindByID(ctx context.Context, id string) (*MediaFile,
 	var contentHash sql.NullString
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&file.ID, &file.CampaignID, &file.UploadedBy,
-		&file.Filename, &file.OriginalName, &file.MimeType,
+		&file.Filename, &file.OriginalName, &file.AltText, &file.Caption, &file.Credit, &file.MimeType,
 		&file.FileSize, &contentHash, &file.UsageType, &thumbJSON,
 		&file.CreatedAt, &file.CampaignIsPublic,
 	)
@@ -266,6 +269,21 @@ func (r *mediaRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// UpdateText sets a media file's alt text, caption and credit.
+func (r *mediaRepository) UpdateText(ctx context.Context, id, altText, caption, credit string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE media_files SET alt_text = ?, caption = ?, credit = ? WHERE id = ?`,
+		sql.NullString{String: altText, Valid: altText != ""},
+		sql.NullString{String: caption, Valid: caption != ""},
+		sql.NullString{String: credit, Valid: credit != ""},
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating media text: %w", err)
+	}
+	return nil
+}
+
 // ListByCampaign returns media files for a campaign with pagination.
 func (r *mediaRepository) ListByCampaign(ctx context.Context, campaignID string, limit, offset int) ([]MediaFile, int, error) {
 	var total int
@@ -277,6 +295,7 @@ func (r *mediaRepository) ListByCampaign(ctx context.Context, campaignID string,
 	}
 
 	query := `SELECT id, campaign_id, uploaded_by, filename, original_name,
+	                 COALESCE(alt_text, ''), COALESCE(caption, ''), COALESCE(credit, ''),
 	                 mime_type, file_size, content_hash, usage_type, thumbnail_paths, created_at
 	          FROM media_files WHERE campaign_id = ?
 	          ORDER BY created_at DESC LIMIT ? OFFSET ?`
@@ -294,7 +313,7 @@ func (r *mediaRepository) ListByCampaign(ctx context.Context, campaignID string,
 		var contentHash sql.NullString
 		if err := rows.Scan(
 			&f.ID, &f.CampaignID, &f.UploadedBy,
-			&f.Filename, &f.OriginalName, &f.MimeType,
+			&f.Filename, &f.OriginalName, &f.AltText, &f.Caption, &f.Credit, &f.MimeType,
 			&f.FileSize, &contentHash, &f.UsageType, &thumbJSON,
 			&f.CreatedAt,
 		); err != nil {
@@ -26,21 +26,25 @@ import (
 // quota check does inside the upload handler.
 type MaxUploadResolver func(c echo.Context) int64
 
+// Default per-IP, per-minute caps for media serving and uploads.
+const (
+	DefaultServeRateLimit  = 300
+	DefaultUploadRateLimit = 30
+)
+
 // RegisterRoutes sets up all media-related routes on the given Echo instance.
 // resolveMaxUpload is consulted on every upload to determine the body-size
 // cap for that request (so the admin can change the global limit without
-// requiring a server restart). serveRateLimit controls the max media serve
-// requests per minute per IP (0 = 300 default).
-func RegisterRoutes(e *echo.Echo, h *Handler, authSvc auth.AuthService, resolveMaxUpload MaxUploadResolver, serveRateLimit int) {
+// requiring a server restart). serveRateLimit and uploadRateLimit return the
+// live per-IP, per-minute caps for media serving and uploads; they're read on
+// every request so runtime settings apply without a restart.
+func RegisterRoutes(e *echo.Echo, h *Handler, authSvc auth.AuthService, resolveMaxUpload MaxUploadResolver, serveRateLimit, uploadRateLimit func() int) {
 	// Serve routes are protected by:
 	// 1. HMAC-signed URLs (handler-level, verifies cryptographic signature)
 	// 2. Campaign membership check for private campaigns (handler-level)
 	// 3. Per-IP rate limiting (middleware-level, prevents scraping/DoS)
 	// 4. OptionalAuth for session-based fallback access during migration
-	if serveRateLimit <= 0 {
-		serveRateLimit = 300
-	}
-	serveRL := middleware.RateLimit(serveRateLimit, time.Minute)
+	serveRL := middleware.DynamicRateLimit(serveRateLimit, time.Minute)
 	authOptional := auth.OptionalAuth(authSvc)
 	e.GET("/media/:id", h.Serve, authOptional, serveRL)
 	e.GET("/media/:id/thumb/:size", h.ServeThumbnail, authOptional, serveRL)
@@ -48,15 +52,15 @@ func RegisterRoutes(e *echo.Echo, h *Handler, authSvc auth.AuthService, resolveM
 	// Authenticated routes.
 	authMw := auth.RequireAuth(authSvc)
 
-	// Rate limit uploads: 30 per minute per IP.
-	uploadRateLimit := middleware.RateLimit(30, time.Minute)
+	// Rate limit uploads per IP.
+	uploadRL := middleware.DynamicRateLimit(uploadRateLimit, time.Minute)
 
 	// Limit upload body size to prevent memory exhaustion. Resolved per-
 	// request so admin changes to the global cap take effect immediately
 	// without restarting the server.
 	bodyLimit := dynamicBodyLimitMiddleware(resolveMaxUpload)
 
-	e.POST("/media/upload", h.Upload, authMw, uploadRateLimit, bodyLimit)
+	e.POST("/media/upload", h.Upload, authMw, uploadRL, bodyLimit)
 	e.GET("/media/:fileID/info", h.Info, authMw)
 	e.DELETE("/media/:fileID", h.Delete, authMw)
 }
@@ -77,6 +81,7 @@ func RegisterCampaignRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.Camp
 
 	gallery.GET("/media", h.CampaignMedia, campaigns.RequireRole(campaigns.RoleOwner))
 	gallery.DELETE("/media/:mid", h.CampaignDeleteMedia, campaigns.RequireRole(campaigns.RoleOwner))
+	gallery.PUT("/media/:mid/text", h.CampaignUpdateMediaText, campaigns.RequireRole(campaigns.RoleOwner))
 	gallery.GET("/media/:mid/refs", h.CampaignMediaRefs, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Picker JSON endpoint — NOT addon-gated, Scribe+ for editing
@@ -88,6 +93,18 @@ func RegisterCampaignRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.Camp
 	picker.GET("/media/list", h.CampaignMediaList, campaigns.RequireRole(campaigns.RoleScribe))
 }
 
+// RegisterImageProxyRoutes sets up the external image proxy. Public-capable
+// like entry content, since public campaign visitors see the same entries;
+// rate limited with the media serve cap.
+func RegisterImageProxyRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignService, authSvc auth.AuthService, serveRateLimit func() int) {
+	pub := e.Group("/campaigns/:id",
+		auth.OptionalAuth(authSvc),
+		campaigns.AllowPublicCampaignAccess(campaignSvc),
+	)
+	pub.GET("/image-proxy", h.ServeProxiedImage,
+		campaigns.RequireViewAccess(), middleware.DynamicRateLimit(serveRateLimit, time.Minute))
+}
+
 // dynamicBodyLimitMiddleware rejects request bodies exceeding the cap
 // returned by the resolver. Adds a 10% margin above the resolver's value
 // to absorb multipart-encoding overhead — the application-layer quota
@@ -119,3 +136,21 @@ func dynamicBodyLimitMiddleware(resolver MaxUploadResolver) echo.MiddlewareFunc
 		}
 	}
 }
+
+// RegisterAttachmentRoutes sets up entity/session attachment routes. Players
+// list and download what they can see; Scribes and above manage files.
+// Uploads share the media upload rate limit and body cap.
+func RegisterAttachmentRoutes(e *echo.Echo, h *AttachmentHandler, campaignSvc campaigns.CampaignService, authSvc auth.AuthService, resolveMaxUpload MaxUploadResolver, uploadRateLimit func() int) {
+	cg := e.Group("/campaigns/:id",
+		auth.RequireAuth(authSvc),
+		campaigns.RequireCampaignAccess(campaignSvc),
+	)
+
+	cg.GET("/attachments", h.List, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.GET("/attachments/:aid/download", h.Download, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/attachments", h.Upload, campaigns.RequireRole(campaigns.RoleScribe),
+		middleware.DynamicRateLimit(uploadRateLimit, time.Minute),
+		dynamicBodyLimitMiddleware(resolveMaxUpload))
+	cg.PUT("/attachments/:aid", h.Update, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.DELETE("/attachments/:aid", h.Delete, campaigns.RequireRole(campaigns.RoleScribe))
+}
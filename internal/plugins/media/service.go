@@ -19,6 +19,7 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	// Register decoders for image formats.
 	_ "golang.org/x/image/webp"
@@ -55,6 +56,11 @@ type MediaService interface {
 	// DeleteCampaignMedia deletes a media file after verifying it belongs to the campaign.
 	DeleteCampaignMedia(ctx context.Context, campaignID, mediaID string) error
 
+	// UpdateCampaignMediaText sets an image's alt text and caption after
+	// verifying it belongs to the campaign. requireAlt (public campaigns)
+	// rejects clearing the alt text.
+	UpdateCampaignMediaText(ctx context.Context, campaignID, mediaID string, input UpdateTextInput, requireAlt bool) (*MediaFile, error)
+
 	// DeleteCampaignFiles removes all media files belonging to a campaign from
 	// both disk and database. Used during campaign deletion to prevent orphaned
 	// files. Returns the number of files deleted.
@@ -164,8 +170,9 @@ func (s *mediaService) Upload(ctx context.Context, input UploadInput) (*MediaFil
 	}
 	defer s.sem.release(input.UploadedBy)
 
-	// Validate MIME type.
-	if !AllowedMimeTypes[input.MimeType] {
+	// Validate MIME type. Documents and archives only come in as handouts.
+	if !AllowedMimeTypes[input.MimeType] &&
+		!(input.UsageType == UsageHandout && HandoutMimeTypes[input.MimeType]) {
 		return nil, apperror.NewBadRequest("unsupported file type: " + input.MimeType)
 	}
 
@@ -532,6 +539,44 @@ func (s *mediaService) DeleteCampaignMedia(ctx context.Context, campaignID, medi
 	return s.Delete(ctx, mediaID)
 }
 
+// UpdateCampaignMediaText sets a campaign image's alt text, caption and
+// credit.
+// Only images take a description; a PDF's alt text would never be read.
+func (s *mediaService) UpdateCampaignMediaText(ctx context.Context, campaignID, mediaID string, input UpdateTextInput, requireAlt bool) (*MediaFile, error) {
+	file, err := s.repo.FindByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if file.CampaignID == nil || *file.CampaignID != campaignID {
+		return nil, apperror.NewNotFound("media file not found")
+	}
+	if !file.IsImage() {
+		return nil, apperror.NewBadRequest("only images have alt text and captions")
+	}
+
+	alt := strings.Join(strings.Fields(input.AltText), " ")
+	caption := strings.TrimSpace(input.Caption)
+	credit := strings.Join(strings.Fields(input.Credit), " ")
+	if requireAlt && alt == "" {
+		return nil, apperror.NewBadRequest("alt text is required for images in a public campaign")
+	}
+	if utf8.RuneCountInString(alt) > MaxAltTextLength {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("alt text must be %d characters or fewer", MaxAltTextLength))
+	}
+	if utf8.RuneCountInString(caption) > MaxCaptionLength {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("caption must be %d characters or fewer", MaxCaptionLength))
+	}
+	if utf8.RuneCountInString(credit) > MaxCreditLength {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("credit must be %d characters or fewer", MaxCreditLength))
+	}
+
+	if err := s.repo.UpdateText(ctx, mediaID, alt, caption, credit); err != nil {
+		return nil, err
+	}
+	file.AltText, file.Caption, file.Credit = alt, caption, credit
+	return file, nil
+}
+
 // DeleteCampaignFiles removes all media files belonging to a campaign.
 // Deletes physical files from disk (main + thumbnails) and then removes
 // the database records. Called before campaign SQL DELETE to prevent orphaned
@@ -737,6 +782,12 @@ func validateMagicBytes(data []byte, declaredMIME string) bool {
 	case "audio/webm":
 		// WebM uses Matroska container: starts with EBML header 0x1A45DFA3.
 		return len(data) >= 4 && data[0] == 0x1A && data[1] == 0x45 && data[2] == 0xDF && data[3] == 0xA3
+	// Handout formats.
+	case "application/pdf":
+		return len(data) >= 5 && string(data[:5]) == "%PDF-"
+	case "application/zip":
+		// Local file header; an empty archive starts with the end-of-central-directory record.
+		return len(data) >= 4 && (string(data[:4]) == "PK\x03\x04" || string(data[:4]) == "PK\x05\x06")
 	default:
 		return false
 	}
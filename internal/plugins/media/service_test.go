@@ -3,6 +3,7 @@ package media
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
@@ -25,6 +26,7 @@ type mockMediaRepo struct {
 	findReferencesFn   func(ctx context.Context, campaignID, mediaID string) ([]MediaRef, error)
 	listAllFilenamesFn    func(ctx context.Context) (map[string]bool, error)
 	listFilesByCampaignFn func(ctx context.Context, campaignID string) ([]MediaFile, error)
+	updateTextFn          func(ctx context.Context, id, altText, caption, credit string) error
 }
 
 func (m *mockMediaRepo) Create(ctx context.Context, file *MediaFile) error {
@@ -111,6 +113,13 @@ func (m *mockMediaRepo) ListAllFilenames(ctx context.Context) (map[string]bool,
 	return make(map[string]bool), nil
 }
 
+func (m *mockMediaRepo) UpdateText(ctx context.Context, id, altText, caption, credit string) error {
+	if m.updateTextFn != nil {
+		return m.updateTextFn(ctx, id, altText, caption, credit)
+	}
+	return nil
+}
+
 func (m *mockMediaRepo) ListFilesByCampaign(ctx context.Context, campaignID string) ([]MediaFile, error) {
 	if m.listFilesByCampaignFn != nil {
 		return m.listFilesByCampaignFn(ctx, campaignID)
@@ -301,6 +310,67 @@ func TestDeleteCampaignMedia_NilCampaignID(t *testing.T) {
 	assertMediaAppError(t, err, 404)
 }
 
+// --- UpdateCampaignMediaText Tests ---
+
+func TestUpdateCampaignMediaText(t *testing.T) {
+	campaignID := "camp-1"
+	other := "camp-2"
+	image := &MediaFile{ID: "img", CampaignID: &campaignID, MimeType: "image/png", OriginalName: "map.png"}
+	pdf := &MediaFile{ID: "pdf", CampaignID: &campaignID, MimeType: "application/pdf"}
+	foreign := &MediaFile{ID: "foreign", CampaignID: &other, MimeType: "image/png"}
+
+	cases := []struct {
+		name       string
+		mediaID    string
+		input      UpdateTextInput
+		requireAlt bool
+		wantCode   int // 0 = success
+		wantAlt    string
+	}{
+		{"describes an image", "img", UpdateTextInput{AltText: "  A river\n map ", Caption: " The Sundering "}, false, 0, "A river map"},
+		{"clears alt in a private campaign", "img", UpdateTextInput{}, false, 0, ""},
+		{"public campaign requires alt", "img", UpdateTextInput{Caption: "x"}, true, 400, ""},
+		{"too long", "img", UpdateTextInput{AltText: strings.Repeat("a", MaxAltTextLength+1)}, false, 400, ""},
+		{"credit too long", "img", UpdateTextInput{AltText: "x", Credit: strings.Repeat("a", MaxCreditLength+1)}, false, 400, ""},
+		{"not an image", "pdf", UpdateTextInput{AltText: "x"}, false, 400, ""},
+		{"other campaign", "foreign", UpdateTextInput{AltText: "x"}, false, 404, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stored []string
+			repo := &mockMediaRepo{
+				findByIDFn: func(_ context.Context, id string) (*MediaFile, error) {
+					for _, f := range []*MediaFile{image, pdf, foreign} {
+						if f.ID == id {
+							cp := *f
+							return &cp, nil
+						}
+					}
+					return nil, apperror.NewNotFound("media file not found")
+				},
+				updateTextFn: func(_ context.Context, _, alt, caption, credit string) error {
+					stored = []string{alt, caption, credit}
+					return nil
+				},
+			}
+			file, err := newTestMediaService(repo).UpdateCampaignMediaText(context.Background(), campaignID, tc.mediaID, tc.input, tc.requireAlt)
+			if tc.wantCode != 0 {
+				assertMediaAppError(t, err, tc.wantCode)
+				if stored != nil {
+					t.Error("rejected update reached the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.AltText != tc.wantAlt || stored[0] != tc.wantAlt {
+				t.Errorf("alt = %q (stored %q), want %q", file.AltText, stored[0], tc.wantAlt)
+			}
+		})
+	}
+}
+
 // --- ListCampaignMedia Tests ---
 
 func TestListCampaignMedia_Success(t *testing.T) {
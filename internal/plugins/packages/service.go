@@ -204,6 +204,11 @@ type packageService struct {
 	// destDir mid-extract and corrupt the install.
 	installLocks sync.Map // packageID → *sync.Mutex
 
+	// autoUpdateLeader, when set, decides per tick whether this instance
+	// runs the auto-update pass. Cluster mode injects a Redis lock so one
+	// instance installs into the shared volume per hour; nil always runs.
+	autoUpdateLeader func(ctx context.Context) bool
+
 	// loadedDirsFn returns the set of on-disk dirs the systems loader is
 	// currently serving; injected via SetLoadedDirsProvider (packages
 	// must not import systems). PruneStaleVersions FAILS CLOSED when nil.
@@ -263,6 +268,15 @@ func SetManifestValidator(svc PackageService, fn func(manifestPath string) error
 	}
 }
 
+// SetAutoUpdateLeader wires the per-tick leader check for the auto-update
+// worker (cluster mode). Without it every instance would download and
+// extract the same update into the shared media volume at once.
+func SetAutoUpdateLeader(svc PackageService, fn func(ctx context.Context) bool) {
+	if s, ok := svc.(*packageService); ok {
+		s.autoUpdateLeader = fn
+	}
+}
+
 // SetPostInstallVerifier wires the loaded-state check that runs after a
 // system install's registry rescan (dependency inversion — packages must
 // not import systems). The app layer passes a closure over
@@ -924,6 +938,9 @@ func (s *packageService) StartAutoUpdateWorker(ctx context.Context) {
 			slog.Info("package auto-update worker stopped")
 			return
 		case <-ticker.C:
+			if s.autoUpdateLeader != nil && !s.autoUpdateLeader(ctx) {
+				continue
+			}
 			if err := s.RunAutoUpdates(ctx); err != nil {
 				slog.Error("auto-update run failed", slog.Any("error", err))
 			}
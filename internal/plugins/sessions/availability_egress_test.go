@@ -25,8 +25,9 @@ import (
 
 // schedulerTokens are the field-name / json-tag fragments that mark data which
 // must stay out of export egress: availability, exceptions, slot proposals,
-// per-option responses, and scheduler notifications.
-var schedulerTokens = []string{"avail", "proposal", "notification"}
+// per-option responses, member polls, scheduler notifications, and the safety
+// tools (lines & veils and private consent survey answers).
+var schedulerTokens = []string{"avail", "proposal", "poll", "notification", "safety", "consent"}
 
 // mentionsSchedulerData reports whether a struct field name or its json tag
 // hints at any scheduler-owned data that must not be exported.
@@ -15,6 +15,7 @@ import (
 	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
 )
 
 // MailSender sends email notifications. Wraps the SMTP service interface.
@@ -427,46 +428,19 @@ func (h *Handler) sendRSVPEmails(ctx context.Context, session *Session, campaign
 			dateStr = session.FormatScheduledDate()
 		}
 
-		subject := fmt.Sprintf("Session Invite: %s — %s", session.Name, campaignName)
 		acceptURL := fmt.Sprintf("%s/rsvp/%s", h.baseURL, acceptToken)
 		declineURL := fmt.Sprintf("%s/rsvp/%s", h.baseURL, declineToken)
 
-		plainBody := fmt.Sprintf(`You've been invited to a game session!
-
-Session: %s
-Campaign: %s
-Date: %s
-
-Accept: %s
-Decline: %s
-
-These links expire in 7 days.
-`, session.Name, campaignName, dateStr, acceptURL, declineURL)
-
-		htmlBody := fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="utf-8"></head><body style="font-family:system-ui,-apple-system,sans-serif;max-width:480px;margin:0 auto;padding:20px;color:#333">
-<div style="text-align:center;margin-bottom:24px">
-  <div style="font-size:32px;margin-bottom:8px">🎲</div>
-  <h1 style="font-size:20px;margin:0">Session Invite</h1>
-</div>
-<div style="background:#f8f9fa;border-radius:8px;padding:20px;margin-bottom:24px">
-  <h2 style="font-size:16px;margin:0 0 8px">%s</h2>
-  <p style="margin:4px 0;color:#666;font-size:14px"><strong>Campaign:</strong> %s</p>
-  <p style="margin:4px 0;color:#666;font-size:14px"><strong>Date:</strong> %s</p>
-</div>
-<div style="text-align:center;margin-bottom:24px">
-  <p style="margin:0 0 16px;color:#666;font-size:14px">Can you make it?</p>
-  <a href="%s" style="display:inline-block;padding:10px 24px;background:#22c55e;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;margin:0 8px">✓ Going</a>
-  <a href="%s" style="display:inline-block;padding:10px 24px;background:#ef4444;color:#fff;text-decoration:none;border-radius:6px;font-weight:600;margin:0 8px">✗ Can't Make It</a>
-</div>
-<p style="text-align:center;color:#999;font-size:12px">These links expire in 7 days.</p>
-</body></html>`,
-			// Escape the operator-authored session name + campaign name so they
-			// can't inject markup into the email (C-SCHED-P3 0c, same sweep as the
-			// proposal invite). dateStr is our own formatted label; URLs are hex
-			// tokens — both safe.
-			html.EscapeString(session.Name), html.EscapeString(campaignName), dateStr, acceptURL, declineURL)
-
-		if err := h.mailer.SendHTMLMail(ctx, []string{m.Email}, subject, plainBody, htmlBody); err != nil {
+		// The shared layout escapes the operator-authored session and campaign
+		// names, so neither can inject markup into the email (C-SCHED-P3 0c).
+		msg := emails.SessionInvite(session.Name, campaignName, dateStr, acceptURL, declineURL)
+		plainBody, htmlBody, err := msg.Render(ctx)
+		if err != nil {
+			slog.Warn("failed to render rsvp email", slog.Any("error", err), slog.String("session_id", session.ID))
+			continue
+		}
+
+		if err := h.mailer.SendHTMLMail(ctx, []string{m.Email}, msg.Subject, plainBody, htmlBody); err != nil {
 			slog.Warn("failed to send rsvp email",
 				slog.Any("error", err),
 				slog.String("to", m.Email),
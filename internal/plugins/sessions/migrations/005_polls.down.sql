@@ -0,0 +1,5 @@
+-- Reverse 005 (polls). IF EXISTS keeps the rollback idempotent. Drop in
+-- FK-dependency order: votes reference options, options reference polls.
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,58 @@
+-- Member polls: "Which night next week?", "Which plot hook?". Chains after 004.
+-- Idempotent (CREATE TABLE IF NOT EXISTS) per the migration-safety rules.
+--
+-- A poll is single- or multi-choice, optionally anonymous, and optionally
+-- closes itself at closes_at. An option is a plain label or a DATE option: a
+-- concrete slot stored as UTC instants like slot_proposal_options (RC-12.5).
+-- When a poll closes on a date option, that slot is dropped onto the campaign's
+-- real-life calendar and calendar_event_id remembers the event it made.
+-- Votes live in their OWN table (never session_attendees), so they stay out of
+-- export egress the same way proposal responses do.
+CREATE TABLE IF NOT EXISTS polls (
+    id                CHAR(36)     PRIMARY KEY,
+    campaign_id       CHAR(36)     NOT NULL,
+    created_by        CHAR(36)     NOT NULL,
+    question          VARCHAR(200) NOT NULL,
+    note              TEXT         DEFAULT NULL,
+    multiple_choice   TINYINT(1)   NOT NULL DEFAULT 0,
+    anonymous         TINYINT(1)   NOT NULL DEFAULT 0,
+    tz                VARCHAR(64)  NOT NULL DEFAULT 'UTC', -- creator's zone; places the calendar event
+    closes_at         DATETIME     DEFAULT NULL,           -- UTC deadline; NULL = closed by hand
+    status            VARCHAR(16)  NOT NULL DEFAULT 'open', -- open | closed
+    winner_option_id  CHAR(36)     DEFAULT NULL,
+    calendar_event_id VARCHAR(36)  DEFAULT NULL,
+    created_at        DATETIME     NOT NULL,
+    updated_at        DATETIME     NOT NULL,
+
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_polls_campaign (campaign_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One choice on a poll. starts_at_utc/ends_at_utc are set only on date options.
+CREATE TABLE IF NOT EXISTS poll_options (
+    id            CHAR(36)     PRIMARY KEY,
+    poll_id       CHAR(36)     NOT NULL,
+    label         VARCHAR(200) NOT NULL,
+    starts_at_utc DATETIME     DEFAULT NULL,
+    ends_at_utc   DATETIME     DEFAULT NULL,
+    ordinal       TINYINT      NOT NULL,
+
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    INDEX idx_poll_options_poll (poll_id, ordinal)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One row per (option, member). poll_id is denormalized so a member's ballot
+-- can be replaced in one statement.
+CREATE TABLE IF NOT EXISTS poll_votes (
+    option_id  CHAR(36) NOT NULL,
+    poll_id    CHAR(36) NOT NULL,
+    user_id    CHAR(36) NOT NULL,
+    created_at DATETIME NOT NULL,
+
+    PRIMARY KEY (option_id, user_id),
+    FOREIGN KEY (option_id) REFERENCES poll_options(id) ON DELETE CASCADE,
+    FOREIGN KEY (poll_id) REFERENCES polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_poll_votes_poll_user (poll_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
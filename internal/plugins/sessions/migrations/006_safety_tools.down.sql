@@ -0,0 +1,4 @@
+-- Reverse 006 (safety tools). IF EXISTS keeps the rollback idempotent.
+DROP TABLE IF EXISTS safety_consent_answers;
+DROP TABLE IF EXISTS safety_entries;
+DROP TABLE IF EXISTS safety_settings;
@@ -0,0 +1,42 @@
+-- Session 0 safety tools. Chains after 005. Idempotent (CREATE TABLE IF NOT
+-- EXISTS) per the migration-safety rules.
+--
+-- safety_settings holds the owner's per-campaign choices; a missing row means
+-- the defaults. safety_entries is the campaign's lines & veils list.
+-- submitted_by is NULL for anonymous player submissions, so not even the
+-- owner can tell who asked. safety_consent_answers holds each member's
+-- consent survey answers (topic keys are defined in code); the owner only
+-- ever sees them summed per topic. All three live in their OWN tables, out
+-- of export egress like poll votes.
+CREATE TABLE IF NOT EXISTS safety_settings (
+    campaign_id     CHAR(36)   PRIMARY KEY,
+    allow_anonymous TINYINT(1) NOT NULL DEFAULT 1,
+    updated_at      DATETIME   NOT NULL,
+
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS safety_entries (
+    id           CHAR(36)     PRIMARY KEY,
+    campaign_id  CHAR(36)     NOT NULL,
+    kind         VARCHAR(8)   NOT NULL, -- line | veil
+    topic        VARCHAR(200) NOT NULL,
+    submitted_by CHAR(36)     DEFAULT NULL, -- NULL = anonymous
+    created_at   DATETIME     NOT NULL,
+
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (submitted_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_safety_entries_campaign (campaign_id, kind, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS safety_consent_answers (
+    campaign_id CHAR(36)    NOT NULL,
+    user_id     CHAR(36)    NOT NULL,
+    topic       VARCHAR(32) NOT NULL,
+    rating      VARCHAR(8)  NOT NULL, -- green | yellow | red
+    updated_at  DATETIME    NOT NULL,
+
+    PRIMARY KEY (campaign_id, user_id, topic),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+-- Reverse 007 (session transcripts). IF EXISTS keeps the rollback idempotent.
+DROP TABLE IF EXISTS session_transcript_mentions;
+DROP TABLE IF EXISTS session_transcripts;
@@ -0,0 +1,37 @@
+-- Session transcripts. Chains after 006. Idempotent (CREATE TABLE IF NOT
+-- EXISTS) per the migration-safety rules.
+--
+-- session_transcripts holds one uploaded speech-to-text transcript per
+-- session (plain text or WebVTT), replaced on re-upload. It is GM material
+-- like session notes: Scribe+ only. session_transcript_mentions is the
+-- derived "who was discussed when" index: one row per page mentioned in a
+-- cue, with the cue's start time when the transcript has timings. It is
+-- rebuilt from the transcript on upload and on rescan.
+CREATE TABLE IF NOT EXISTS session_transcripts (
+    session_id  CHAR(36)     PRIMARY KEY,
+    campaign_id CHAR(36)     NOT NULL,
+    file_name   VARCHAR(255) NOT NULL DEFAULT '',
+    format      VARCHAR(8)   NOT NULL, -- text | vtt
+    content     MEDIUMTEXT   NOT NULL,
+    uploaded_by CHAR(36)     DEFAULT NULL,
+    uploaded_at DATETIME     NOT NULL,
+    scanned_at  DATETIME     NOT NULL,
+
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS session_transcript_mentions (
+    session_id CHAR(36)     NOT NULL,
+    entity_id  CHAR(36)     NOT NULL,
+    cue        INT          NOT NULL, -- 1-based cue (VTT) or line (text)
+    start_ms   INT          DEFAULT NULL, -- NULL when the cue has no timing
+    matched    VARCHAR(200) NOT NULL, -- the name or alias as spoken
+    snippet    VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY (session_id, entity_id, cue),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+    FOREIGN KEY (entity_id) REFERENCES entities(id) ON DELETE CASCADE,
+    INDEX idx_transcript_mentions_entity (entity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -117,6 +117,16 @@ type SessionEntity struct {
 	EntitySlug string `json:"entity_slug,omitempty"`
 }
 
+// EntitySession is a session an entity is linked to, for the entity page's
+// "Appears in" panel.
+type EntitySession struct {
+	SessionID     string
+	Name          string
+	Status        string
+	ScheduledDate *string // YYYY-MM-DD.
+	Role          string  // mentioned, encountered, key
+}
+
 // --- DTOs ---
 
 // CreateSessionInput is the validated input for creating a session.
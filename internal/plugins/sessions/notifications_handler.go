@@ -90,6 +90,11 @@ func (h *Handler) MarkAllNotificationsReadAPI(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// Message is the notification's human-readable text, as the bell shows it.
+func (n Notification) Message() string {
+	return notificationMessage(n)
+}
+
 // notificationMessage extracts the human-readable message from a notification's
 // JSON payload, falling back to a type-based label if the payload is missing.
 func notificationMessage(n Notification) string {
@@ -94,3 +94,16 @@ func (r *sessionRepository) MarkAllNotificationsRead(ctx context.Context, userID
 	}
 	return nil
 }
+
+// PurgeNotificationsBefore deletes up to limit of a campaign's notifications
+// created before the cutoff, read or not. Used by the campaign retention job;
+// the only cross-user write here, and it is scoped by campaign instead.
+func (r *sessionRepository) PurgeNotificationsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM notifications WHERE campaign_id = ? AND created_at < ? LIMIT ?`,
+		campaignID, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("purging notifications: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -10,9 +10,10 @@ import (
 )
 
 // Scheduler-scoped notification business logic (C-SCHED-P2). The scheduler is
-// the only writer this slice: new proposals notify members; received responses
-// notify the proposer. The store itself is generic (T-B2) but no other feature
-// subscribes yet — no prefs, no digests, no per-user websockets (RC-12.5).
+// the main writer: new proposals notify members; received responses notify
+// the proposer. The store itself is generic (T-B2); other plugins write through
+// NotifyUser (the entities mention-rename summary). Still no prefs, no
+// digests, no per-user websockets (RC-12.5).
 
 // notificationPayload is the small render context stored as JSON on each row.
 type notificationPayload struct {
@@ -132,6 +133,157 @@ func (s *sessionService) NotifyProposalConfirmed(ctx context.Context, campaignID
 	return nil
 }
 
+// pollLink is the in-app URL a poll notification points to.
+func pollLink(campaignID, pollID string) string {
+	return fmt.Sprintf("/campaigns/%s/polls/%s", campaignID, pollID)
+}
+
+// NotifyPollCreated writes a "new poll" notification to each recipient (the
+// handler supplies the member list, minus the creator).
+func (s *sessionService) NotifyPollCreated(ctx context.Context, campaignID, pollID, question string, recipientIDs []string) error {
+	link := pollLink(campaignID, pollID)
+	payload := marshalPayload(fmt.Sprintf("New poll: %q", question), NotifPollCreated)
+	now := time.Now().UTC()
+	cid := campaignID
+	for _, uid := range recipientIDs {
+		if uid == "" {
+			continue
+		}
+		n := &Notification{
+			ID:         generateUUID(),
+			UserID:     uid,
+			CampaignID: &cid,
+			Type:       NotifPollCreated,
+			Payload:    payload,
+			Link:       &link,
+			CreatedAt:  now,
+		}
+		if err := s.repo.CreateNotification(ctx, n); err != nil {
+			return apperror.NewInternal(fmt.Errorf("writing poll notification: %w", err))
+		}
+	}
+	return nil
+}
+
+// notifyPollClosed tells the poll's creator and every distinct voter the
+// result. Driven by the service rather than the handler because a deadline
+// close has no request (and no member list) behind it.
+func (s *sessionService) notifyPollClosed(ctx context.Context, p *Poll, winner *PollOption, votes []PollVote) error {
+	message := fmt.Sprintf("Poll closed: %q had no votes", p.Question)
+	if winner != nil {
+		message = fmt.Sprintf("Poll closed: %q — %s won", p.Question, winner.Label)
+	}
+	link := pollLink(p.CampaignID, p.ID)
+	payload := marshalPayload(message, NotifPollClosed)
+	now := time.Now().UTC()
+	cid := p.CampaignID
+	recipients := make([]string, 0, len(votes)+1)
+	recipients = append(recipients, p.CreatedBy)
+	for _, v := range votes {
+		recipients = append(recipients, v.UserID)
+	}
+	seen := make(map[string]bool)
+	for _, uid := range recipients {
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		n := &Notification{
+			ID:         generateUUID(),
+			UserID:     uid,
+			CampaignID: &cid,
+			Type:       NotifPollClosed,
+			Payload:    payload,
+			Link:       &link,
+			CreatedAt:  now,
+		}
+		if err := s.repo.CreateNotification(ctx, n); err != nil {
+			return apperror.NewInternal(fmt.Errorf("writing poll result notification: %w", err))
+		}
+	}
+	return nil
+}
+
+// safetyLink is the in-app URL of a campaign's safety tools page.
+func safetyLink(campaignID string) string {
+	return fmt.Sprintf("/campaigns/%s/safety", campaignID)
+}
+
+// NotifyXCard tells the recipients (the handler supplies the campaign's
+// owners, minus the tapper) that someone tapped the X-card. The message never
+// names the tapper. A sessionID from another campaign is ignored rather than
+// linked.
+func (s *sessionService) NotifyXCard(ctx context.Context, campaignID, sessionID string, recipientIDs []string) error {
+	message := "Someone tapped the X-card. Pause, and move the scene on."
+	link := safetyLink(campaignID)
+	if sessionID != "" {
+		if sess, err := s.repo.FindByID(ctx, sessionID); err == nil && sess != nil && sess.CampaignID == campaignID {
+			message = fmt.Sprintf("Someone tapped the X-card during %q. Pause, and move the scene on.", sess.Name)
+			link = fmt.Sprintf("/campaigns/%s/sessions/%s", campaignID, sess.ID)
+		}
+	}
+	return s.writeSafetyNotifications(ctx, campaignID, NotifXCard, message, link, recipientIDs)
+}
+
+// NotifySafetyEntry tells the recipients (the campaign's owners, minus the
+// submitter) that a line or veil was added. Like the entry itself, the
+// message carries no name.
+func (s *sessionService) NotifySafetyEntry(ctx context.Context, campaignID string, e *SafetyEntry, recipientIDs []string) error {
+	message := fmt.Sprintf("New %s added: %q", e.Kind, e.Topic)
+	return s.writeSafetyNotifications(ctx, campaignID, NotifSafetyEntry, message, safetyLink(campaignID), recipientIDs)
+}
+
+// writeSafetyNotifications writes one safety notification per recipient.
+func (s *sessionService) writeSafetyNotifications(ctx context.Context, campaignID, kind, message, link string, recipientIDs []string) error {
+	payload := marshalPayload(message, kind)
+	now := time.Now().UTC()
+	cid := campaignID
+	for _, uid := range recipientIDs {
+		if uid == "" {
+			continue
+		}
+		n := &Notification{
+			ID:         generateUUID(),
+			UserID:     uid,
+			CampaignID: &cid,
+			Type:       kind,
+			Payload:    payload,
+			Link:       &link,
+			CreatedAt:  now,
+		}
+		if err := s.repo.CreateNotification(ctx, n); err != nil {
+			return apperror.NewInternal(fmt.Errorf("writing safety notification: %w", err))
+		}
+	}
+	return nil
+}
+
+// NotifyUser writes one notification for another plugin (e.g. the entities
+// plugin's mention-rename job summary). kind is stored as the type and
+// defaults the message; link may be empty.
+func (s *sessionService) NotifyUser(ctx context.Context, userID, campaignID, kind, message, link string) error {
+	if userID == "" {
+		return nil
+	}
+	n := &Notification{
+		ID:        generateUUID(),
+		UserID:    userID,
+		Type:      kind,
+		Payload:   marshalPayload(message, kind),
+		CreatedAt: time.Now().UTC(),
+	}
+	if campaignID != "" {
+		n.CampaignID = &campaignID
+	}
+	if link != "" {
+		n.Link = &link
+	}
+	if err := s.repo.CreateNotification(ctx, n); err != nil {
+		return apperror.NewInternal(fmt.Errorf("writing notification: %w", err))
+	}
+	return nil
+}
+
 // ListMyNotifications returns the current user's notifications (newest first).
 func (s *sessionService) ListMyNotifications(ctx context.Context, userID string, limit int) ([]Notification, error) {
 	ns, err := s.repo.ListNotifications(ctx, userID, limit)
@@ -0,0 +1,164 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Member-poll HTTP surface. Thin like the proposal handlers: bind, call the
+// service, render. Create fan-out (enumerating members) lives here; the
+// result notifications are written by the service's close path.
+
+// ListPolls renders the polls list for a campaign.
+// GET /campaigns/:id/polls
+func (h *Handler) ListPolls(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	summaries, err := h.svc.ListPollSummaries(c.Request().Context(), cc.Campaign.ID, userID)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	canManage := cc.MemberRole >= campaigns.RoleScribe
+	return middleware.Render(c, http.StatusOK, PollListPage(cc, summaries, h.resolveViewerTZ(c, userID), canManage))
+}
+
+// ShowPoll renders one poll with the viewer's ballot and the tallies.
+// GET /campaigns/:id/polls/:pid
+func (h *Handler) ShowPoll(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	view, err := h.pollView(c, cc, userID)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	csrf := middleware.GetCSRFToken(c)
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, PollDetailFragment(cc, view, csrf))
+	}
+	return middleware.Render(c, http.StatusOK, PollDetailPage(cc, view, csrf))
+}
+
+// CreatePollAPI creates a poll from the builder form, then notifies members.
+// POST /campaigns/:id/polls
+func (h *Handler) CreatePollAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	var req CreatePollRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	poll, err := h.svc.CreatePoll(c.Request().Context(), cc.Campaign.ID, userID, req)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+
+	// Notify members off the request path.
+	if h.memberLister != nil {
+		if members, mErr := h.memberLister.ListMembers(c.Request().Context(), cc.Campaign.ID); mErr == nil {
+			recipients := make([]string, 0, len(members))
+			for _, m := range members {
+				if m.UserID != userID {
+					recipients = append(recipients, m.UserID)
+				}
+			}
+			go func() {
+				if err := h.svc.NotifyPollCreated(context.Background(), cc.Campaign.ID, poll.ID, poll.Question, recipients); err != nil {
+					slog.Warn("failed to write poll notifications", slog.Any("error", err))
+				}
+			}()
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"status": "ok",
+		"id":     poll.ID,
+		"link":   pollLink(cc.Campaign.ID, poll.ID),
+	})
+}
+
+// VotePollAPI replaces the member's ballot. The HTMX ballot form gets the
+// refreshed poll card back; JSON callers get a status.
+// POST /campaigns/:id/polls/:pid/vote
+func (h *Handler) VotePollAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	var req VotePollRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	if err := h.svc.CastPollVote(c.Request().Context(), cc.Campaign.ID, c.Param("pid"), userID, req.OptionIDs); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderPollResult(c, cc, userID)
+}
+
+// ClosePollAPI closes a poll ahead of its deadline (Scribe+). A winning date
+// option lands on the real-life calendar in the same call.
+// POST /campaigns/:id/polls/:pid/close
+func (h *Handler) ClosePollAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	if err := h.svc.ClosePoll(c.Request().Context(), cc.Campaign.ID, c.Param("pid")); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderPollResult(c, cc, userID)
+}
+
+// renderPollResult answers a poll write: the refreshed card for HTMX, a
+// status for JSON.
+func (h *Handler) renderPollResult(c echo.Context, cc *campaigns.CampaignContext, userID string) error {
+	if !middleware.IsHTMX(c) {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+	view, err := h.pollView(c, cc, userID)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return middleware.Render(c, http.StatusOK, PollDetailFragment(cc, view, middleware.GetCSRFToken(c)))
+}
+
+// pollView loads the :pid poll for the viewer and resolves voter names.
+func (h *Handler) pollView(c echo.Context, cc *campaigns.CampaignContext, userID string) (*PollView, error) {
+	view, err := h.svc.GetPollView(c.Request().Context(), cc.Campaign.ID, c.Param("pid"), userID, h.resolveViewerTZ(c, userID))
+	if err != nil {
+		return nil, err
+	}
+	if view.ShowVoters {
+		h.fillVoterNames(c.Request().Context(), cc.Campaign.ID, view)
+	}
+	return view, nil
+}
+
+// fillVoterNames resolves display names for a non-anonymous poll's voters,
+// the same member-directory lookup fillResponderNames does for proposals.
+func (h *Handler) fillVoterNames(ctx context.Context, campaignID string, view *PollView) {
+	if h.memberLister == nil {
+		return
+	}
+	members, err := h.memberLister.ListMembers(ctx, campaignID)
+	if err != nil {
+		return
+	}
+	nameByUser := make(map[string]string, len(members))
+	for _, m := range members {
+		nameByUser[m.UserID] = m.DisplayName
+	}
+	for i := range view.Options {
+		for j := range view.Options[i].Voters {
+			if n := nameByUser[view.Options[i].Voters[j].UserID]; n != "" {
+				view.Options[i].Voters[j].Name = n
+			} else {
+				view.Options[i].Voters[j].Name = "Member"
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package sessions
+
+import "time"
+
+// This file holds the member-poll domain types. A poll is a question with
+// 2..10 options ("Which night next week?", "Which plot hook?"), single- or
+// multi-choice, optionally anonymous, optionally closing itself at a deadline.
+// An option is a plain label or a DATE option — a concrete slot stored as UTC
+// instants like a proposal option (RC-12.5). When a poll closes on a date
+// option, the slot is dropped onto the campaign's real-life calendar. Votes
+// live in their OWN table (poll_votes), never session_attendees.
+
+// Poll status values.
+const (
+	PollOpen   = "open"
+	PollClosed = "closed"
+)
+
+// Poll notification types.
+const (
+	NotifPollCreated = "poll_created"
+	NotifPollClosed  = "poll_closed"
+)
+
+// A poll needs a real choice (2) and stays readable on a phone (10).
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+// Poll is a member poll header.
+type Poll struct {
+	ID              string
+	CampaignID      string
+	CreatedBy       string
+	Question        string
+	Note            *string
+	MultipleChoice  bool
+	Anonymous       bool
+	TZ              string     // creator's IANA zone; places the calendar event
+	ClosesAt        *time.Time // UTC deadline; nil = closed by hand
+	Status          string     // open | closed
+	WinnerOptionID  *string
+	CalendarEventID *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsDue reports whether an open poll's deadline has passed.
+func (p *Poll) IsDue(now time.Time) bool {
+	return p.Status == PollOpen && p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
+// PollOption is one choice. StartsAtUTC/EndsAtUTC are set only on date options.
+type PollOption struct {
+	ID          string
+	PollID      string
+	Label       string
+	StartsAtUTC *time.Time
+	EndsAtUTC   *time.Time
+	Ordinal     int
+}
+
+// IsDate reports whether the option is a concrete slot.
+func (o PollOption) IsDate() bool {
+	return o.StartsAtUTC != nil && o.EndsAtUTC != nil
+}
+
+// PollVote is one member's vote for one option.
+type PollVote struct {
+	OptionID  string
+	PollID    string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// PollEvent is the calendar event a closed poll asks for: the winning slot,
+// named after the poll. TZ is the poll's zone, the fallback when the calendar
+// has none of its own.
+type PollEvent struct {
+	Name      string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	TZ        string
+	CreatedBy string
+}
+
+// --- API request DTOs (camelCase JSON) ---
+
+// CreatePollRequest is the poll builder submission. Date options and the
+// deadline are wall-clocks in TZ; the service resolves them to UTC instants
+// the same way CreateProposalRequest's slots are.
+type CreatePollRequest struct {
+	Question       string            `json:"question"`
+	Note           string            `json:"note"`
+	MultipleChoice bool              `json:"multipleChoice"`
+	Anonymous      bool              `json:"anonymous"`
+	TZ             string            `json:"tz"`
+	ClosesAt       string            `json:"closesAt"` // YYYY-MM-DDTHH:MM in TZ; empty = no deadline
+	Options        []PollOptionInput `json:"options"`
+}
+
+// PollOptionInput is one choice. A non-empty Date makes it a date option (a
+// [start,end) minute range from local midnight in the request's TZ); Label may
+// then be left empty and defaults to the slot's local label.
+type PollOptionInput struct {
+	Label       string `json:"label"`
+	Date        string `json:"date"`
+	StartMinute int    `json:"startMinute"`
+	EndMinute   int    `json:"endMinute"`
+}
+
+// VotePollRequest is a member's full ballot: it replaces any earlier vote.
+// Bound from JSON or from the HTMX ballot form's repeated optionIds fields.
+type VotePollRequest struct {
+	OptionIDs []string `json:"optionIds" form:"optionIds"`
+}
+
+// --- View types ---
+
+// PollView is one poll for one viewer: options with tallies and the viewer's
+// own ballot, plus who voted what when the poll isn't anonymous.
+type PollView struct {
+	Poll       Poll
+	ViewerTZ   string
+	ShowVoters bool // !Anonymous
+	VoterCount int  // distinct members who voted
+	MyVoted    bool
+	Options    []PollOptionView
+}
+
+// PollOptionView is one option with its tally.
+type PollOptionView struct {
+	Option   PollOption
+	Local    *LocalSlot // date options only, in the viewer's zone
+	Votes    int
+	Mine     bool
+	IsWinner bool
+	Voters   []PollVoterView // ShowVoters only; names filled by the handler
+}
+
+// PollVoterView is one member who voted for an option.
+type PollVoterView struct {
+	UserID string
+	Name   string
+}
+
+// PollSummary is one row in the polls list.
+type PollSummary struct {
+	Poll        Poll
+	OptionCount int
+	VoterCount  int
+	MyVoted     bool
+	Closed      bool // closed, or past its deadline
+}
@@ -0,0 +1,227 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// errPollAlreadyClosed signals that the conditional close matched zero rows:
+// someone (the Scribe's button, the deadline job, a lazy read) closed the poll
+// first. The service treats it as "nothing to do" so the calendar event is
+// only ever created once.
+var errPollAlreadyClosed = errors.New("poll already closed")
+
+// Poll persistence on the existing sessionRepository. Polls, options and votes
+// live in their OWN tables, out of export egress like proposals.
+
+const pollColumns = `id, campaign_id, created_by, question, note, multiple_choice, anonymous,
+	tz, closes_at, status, winner_option_id, calendar_event_id, created_at, updated_at`
+
+// scanPoll scans one pollColumns row.
+func scanPoll(scan func(...any) error) (*Poll, error) {
+	var p Poll
+	var note, winner, eventID sql.NullString
+	var closesAt sql.NullTime
+	if err := scan(&p.ID, &p.CampaignID, &p.CreatedBy, &p.Question, &note, &p.MultipleChoice, &p.Anonymous,
+		&p.TZ, &closesAt, &p.Status, &winner, &eventID, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if note.Valid {
+		p.Note = &note.String
+	}
+	if closesAt.Valid {
+		p.ClosesAt = &closesAt.Time
+	}
+	if winner.Valid {
+		p.WinnerOptionID = &winner.String
+	}
+	if eventID.Valid {
+		p.CalendarEventID = &eventID.String
+	}
+	return &p, nil
+}
+
+// CreatePoll inserts a poll and its options atomically.
+func (r *sessionRepository) CreatePoll(ctx context.Context, p *Poll, options []PollOption) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin poll tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO polls (id, campaign_id, created_by, question, note, multiple_choice, anonymous,
+		                    tz, closes_at, status, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, p.CampaignID, p.CreatedBy, p.Question, p.Note, p.MultipleChoice, p.Anonymous,
+		p.TZ, p.ClosesAt, p.Status, p.CreatedAt, p.UpdatedAt); err != nil {
+		return fmt.Errorf("inserting poll: %w", err)
+	}
+
+	const ins = `INSERT INTO poll_options (id, poll_id, label, starts_at_utc, ends_at_utc, ordinal)
+	             VALUES (?, ?, ?, ?, ?, ?)`
+	for _, o := range options {
+		if _, err := tx.ExecContext(ctx, ins,
+			o.ID, o.PollID, o.Label, o.StartsAtUTC, o.EndsAtUTC, o.Ordinal); err != nil {
+			return fmt.Errorf("inserting poll option: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit poll tx: %w", err)
+	}
+	return nil
+}
+
+// GetPoll fetches a poll scoped to its campaign (IDOR guard) plus its options
+// in display order.
+func (r *sessionRepository) GetPoll(ctx context.Context, campaignID, pollID string) (*Poll, []PollOption, error) {
+	p, err := scanPoll(r.db.QueryRowContext(ctx,
+		`SELECT `+pollColumns+` FROM polls WHERE id = ? AND campaign_id = ?`,
+		pollID, campaignID).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil, apperror.NewNotFound("poll not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading poll: %w", err)
+	}
+	opts, err := r.ListPollOptions(ctx, pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, opts, nil
+}
+
+// ListPolls returns a campaign's polls, newest first.
+func (r *sessionRepository) ListPolls(ctx context.Context, campaignID string) ([]Poll, error) {
+	return r.queryPolls(ctx, `SELECT `+pollColumns+` FROM polls
+		WHERE campaign_id = ? ORDER BY created_at DESC`, campaignID)
+}
+
+// ListDuePolls returns open polls whose deadline has passed, oldest deadline
+// first, across every campaign (the deadline job's work list).
+func (r *sessionRepository) ListDuePolls(ctx context.Context, now time.Time, limit int) ([]Poll, error) {
+	return r.queryPolls(ctx, `SELECT `+pollColumns+` FROM polls
+		WHERE status = ? AND closes_at IS NOT NULL AND closes_at <= ?
+		ORDER BY closes_at LIMIT ?`, PollOpen, now.UTC(), limit)
+}
+
+// queryPolls runs a pollColumns query and scans every row.
+func (r *sessionRepository) queryPolls(ctx context.Context, query string, args ...any) ([]Poll, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing polls: %w", err)
+	}
+	defer rows.Close()
+	var out []Poll
+	for rows.Next() {
+		p, err := scanPoll(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scanning poll: %w", err)
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
+
+// ListPollOptions returns a poll's options in display order.
+func (r *sessionRepository) ListPollOptions(ctx context.Context, pollID string) ([]PollOption, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, poll_id, label, starts_at_utc, ends_at_utc, ordinal
+		 FROM poll_options WHERE poll_id = ? ORDER BY ordinal`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("listing poll options: %w", err)
+	}
+	defer rows.Close()
+	var out []PollOption
+	for rows.Next() {
+		var o PollOption
+		var starts, ends sql.NullTime
+		if err := rows.Scan(&o.ID, &o.PollID, &o.Label, &starts, &ends, &o.Ordinal); err != nil {
+			return nil, fmt.Errorf("scanning poll option: %w", err)
+		}
+		if starts.Valid && ends.Valid {
+			o.StartsAtUTC, o.EndsAtUTC = &starts.Time, &ends.Time
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// ListPollVotes returns every vote on a poll.
+func (r *sessionRepository) ListPollVotes(ctx context.Context, pollID string) ([]PollVote, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT option_id, poll_id, user_id, created_at FROM poll_votes WHERE poll_id = ?`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("listing poll votes: %w", err)
+	}
+	defer rows.Close()
+	var out []PollVote
+	for rows.Next() {
+		var v PollVote
+		if err := rows.Scan(&v.OptionID, &v.PollID, &v.UserID, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning poll vote: %w", err)
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// ReplacePollVotes swaps a member's whole ballot for optionIDs in one
+// transaction, so a changed vote never leaves a half-old ballot behind. An
+// empty optionIDs withdraws the vote.
+func (r *sessionRepository) ReplacePollVotes(ctx context.Context, pollID, userID string, optionIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin vote tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM poll_votes WHERE poll_id = ? AND user_id = ?`, pollID, userID); err != nil {
+		return fmt.Errorf("clearing poll votes: %w", err)
+	}
+	now := time.Now().UTC()
+	for _, id := range optionIDs {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO poll_votes (option_id, poll_id, user_id, created_at) VALUES (?, ?, ?, ?)`,
+			id, pollID, userID, now); err != nil {
+			return fmt.Errorf("inserting poll vote: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit vote tx: %w", err)
+	}
+	return nil
+}
+
+// ClosePoll closes an open poll and records its winner (nil when nobody
+// voted). The conditional `WHERE status = 'open'` is the serialization point:
+// of a racing Scribe close, deadline job and lazy close, exactly one flips the
+// row and the rest get errPollAlreadyClosed.
+func (r *sessionRepository) ClosePoll(ctx context.Context, pollID string, winnerOptionID *string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE polls SET status = ?, winner_option_id = ?, updated_at = ? WHERE id = ? AND status = ?`,
+		PollClosed, winnerOptionID, time.Now().UTC(), pollID, PollOpen)
+	if err != nil {
+		return fmt.Errorf("closing poll: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errPollAlreadyClosed
+	}
+	return nil
+}
+
+// SetPollCalendarEvent records the calendar event created for a closed poll.
+func (r *sessionRepository) SetPollCalendarEvent(ctx context.Context, pollID, eventID string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE polls SET calendar_event_id = ? WHERE id = ?`, eventID, pollID); err != nil {
+		return fmt.Errorf("setting poll calendar event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/timeutil"
+)
+
+// polls_service.go — member polls. Scribe+ asks, any member votes. A poll
+// closes by hand (ClosePoll) or at its deadline: PollCloseJob sweeps due polls
+// every minute, and reads/votes close a due poll on the spot so nobody sees a
+// stale "open" in between. Whichever path wins the conditional close picks the
+// winner and, for a date option, creates the real-life calendar event — once.
+
+const (
+	maxPollQuestionLen = 200
+	maxPollNoteLen     = 2000
+	maxPollLabelLen    = 200
+
+	// pollDeadlineLayout is the wall-clock deadline format (a datetime-local
+	// input's value).
+	pollDeadlineLayout = "2006-01-02T15:04"
+
+	// pollCloseBatchSize bounds one deadline sweep.
+	pollCloseBatchSize = 50
+
+	// pollCloseInterval is how often deadlines are checked.
+	pollCloseInterval = time.Minute
+)
+
+// PollEventCreator puts a closed poll's winning slot on the campaign's
+// real-life calendar and returns the event ID ("" when the campaign has no
+// real-life calendar). Implemented at the app boundary so sessions never
+// imports the calendar plugin.
+type PollEventCreator interface {
+	CreatePollEvent(ctx context.Context, campaignID string, ev PollEvent) (string, error)
+}
+
+// SetPollEventCreator wires the calendar hand-off; nil skips it.
+func (s *sessionService) SetPollEventCreator(c PollEventCreator) {
+	s.pollEvents = c
+}
+
+// CreatePoll validates and stores a poll with 2..10 options. Member
+// notifications are fanned out by the handler, which has the member list.
+func (s *sessionService) CreatePoll(ctx context.Context, campaignID, createdBy string, req CreatePollRequest) (*Poll, error) {
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, apperror.NewBadRequest("a poll question is required")
+	}
+	if len(question) > maxPollQuestionLen {
+		return nil, apperror.NewBadRequest("poll question is too long")
+	}
+	if len(req.Note) > maxPollNoteLen {
+		return nil, apperror.NewBadRequest("poll note is too long")
+	}
+	if len(req.Options) < minPollOptions || len(req.Options) > maxPollOptions {
+		return nil, apperror.NewBadRequest("a poll needs between 2 and 10 options")
+	}
+	if !timeutil.IsValidLocation(req.TZ) {
+		return nil, apperror.NewBadRequest("a valid IANA timezone is required")
+	}
+	loc := timeutil.LoadLocation(req.TZ)
+
+	now := time.Now().UTC()
+	p := &Poll{
+		ID:             generateUUID(),
+		CampaignID:     campaignID,
+		CreatedBy:      createdBy,
+		Question:       question,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		TZ:             req.TZ,
+		Status:         PollOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if note := strings.TrimSpace(req.Note); note != "" {
+		p.Note = &note
+	}
+	if req.ClosesAt != "" {
+		closes, err := time.ParseInLocation(pollDeadlineLayout, req.ClosesAt, loc)
+		if err != nil {
+			return nil, apperror.NewBadRequest("the deadline must be a valid date and time")
+		}
+		closes = closes.UTC()
+		if !closes.After(now) {
+			return nil, apperror.NewBadRequest("the deadline must be in the future")
+		}
+		p.ClosesAt = &closes
+	}
+
+	options := make([]PollOption, 0, len(req.Options))
+	for i, in := range req.Options {
+		o := PollOption{
+			ID:      generateUUID(),
+			PollID:  p.ID,
+			Label:   strings.TrimSpace(in.Label),
+			Ordinal: i + 1,
+		}
+		if in.Date != "" {
+			cd, err := timeutil.ParseCivilDate(in.Date)
+			if err != nil {
+				return nil, apperror.NewBadRequest("each date option needs a valid date")
+			}
+			if in.StartMinute < 0 || in.EndMinute <= in.StartMinute || in.EndMinute > timeutil.MinutesPerDay {
+				return nil, apperror.NewBadRequest("each date option needs a valid time range")
+			}
+			// Same DST-correct wall-clock → instant resolution as proposal slots.
+			start := timeutil.WallClockInstant(loc, cd.Year, cd.Month, cd.Day, in.StartMinute).UTC()
+			end := timeutil.WallClockInstant(loc, cd.Year, cd.Month, cd.Day, in.EndMinute).UTC()
+			if !end.After(start) {
+				return nil, apperror.NewBadRequest("a date option's end must be after its start")
+			}
+			o.StartsAtUTC, o.EndsAtUTC = &start, &end
+			if o.Label == "" {
+				local := renderLocalSlot(start, end, loc, req.TZ)
+				o.Label = local.DateLabel + ", " + local.TimeLabel
+			}
+		}
+		if o.Label == "" {
+			return nil, apperror.NewBadRequest("each option needs a label or a date")
+		}
+		if len(o.Label) > maxPollLabelLen {
+			return nil, apperror.NewBadRequest("an option label is too long")
+		}
+		options = append(options, o)
+	}
+
+	if err := s.repo.CreatePoll(ctx, p, options); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("creating poll: %w", err))
+	}
+	return p, nil
+}
+
+// GetPollView assembles one poll for a viewer, closing it first if its
+// deadline has passed. Voter names are left blank for the handler to fill
+// from the member directory.
+func (s *sessionService) GetPollView(ctx context.Context, campaignID, pollID, viewerID, viewerTZ string) (*PollView, error) {
+	p, opts, err := s.loadPoll(ctx, campaignID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.repo.ListPollVotes(ctx, pollID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading poll votes: %w", err))
+	}
+
+	loc := timeutil.LoadLocation(viewerTZ)
+	view := &PollView{
+		Poll:       *p,
+		ViewerTZ:   viewerTZ,
+		ShowVoters: !p.Anonymous,
+		Options:    make([]PollOptionView, 0, len(opts)),
+	}
+	byOption := make(map[string][]PollVote)
+	voters := make(map[string]bool)
+	for _, v := range votes {
+		byOption[v.OptionID] = append(byOption[v.OptionID], v)
+		voters[v.UserID] = true
+	}
+	view.VoterCount = len(voters)
+	view.MyVoted = voters[viewerID]
+
+	for _, o := range opts {
+		ov := PollOptionView{
+			Option:   o,
+			Votes:    len(byOption[o.ID]),
+			IsWinner: p.WinnerOptionID != nil && *p.WinnerOptionID == o.ID,
+		}
+		if o.IsDate() {
+			local := renderLocalSlot(*o.StartsAtUTC, *o.EndsAtUTC, loc, viewerTZ)
+			ov.Local = &local
+		}
+		for _, v := range byOption[o.ID] {
+			if v.UserID == viewerID {
+				ov.Mine = true
+			}
+			if view.ShowVoters {
+				ov.Voters = append(ov.Voters, PollVoterView{UserID: v.UserID})
+			}
+		}
+		view.Options = append(view.Options, ov)
+	}
+	return view, nil
+}
+
+// ListPollSummaries returns the poll list for a campaign. It only reads: a
+// poll past its deadline shows as closed and is closed for real by the next
+// sweep or the first person to open it.
+func (s *sessionService) ListPollSummaries(ctx context.Context, campaignID, viewerID string) ([]PollSummary, error) {
+	polls, err := s.repo.ListPolls(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("listing polls: %w", err))
+	}
+	now := time.Now().UTC()
+	out := make([]PollSummary, 0, len(polls))
+	for _, p := range polls {
+		opts, err := s.repo.ListPollOptions(ctx, p.ID)
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("listing poll options: %w", err))
+		}
+		votes, err := s.repo.ListPollVotes(ctx, p.ID)
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("listing poll votes: %w", err))
+		}
+		voters := make(map[string]bool)
+		for _, v := range votes {
+			voters[v.UserID] = true
+		}
+		out = append(out, PollSummary{
+			Poll:        p,
+			OptionCount: len(opts),
+			VoterCount:  len(voters),
+			MyVoted:     voters[viewerID],
+			Closed:      p.Status == PollClosed || p.IsDue(now),
+		})
+	}
+	return out, nil
+}
+
+// CastPollVote replaces the member's ballot with optionIDs. Every option must
+// belong to the poll (IDOR guard); a single-choice poll takes exactly one, a
+// multi-choice poll at least one.
+func (s *sessionService) CastPollVote(ctx context.Context, campaignID, pollID, userID string, optionIDs []string) error {
+	p, opts, err := s.loadPoll(ctx, campaignID, pollID)
+	if err != nil {
+		return err
+	}
+	if p.Status == PollClosed {
+		return apperror.NewBadRequest("this poll is closed")
+	}
+
+	valid := make(map[string]bool, len(opts))
+	for _, o := range opts {
+		valid[o.ID] = true
+	}
+	seen := make(map[string]bool, len(optionIDs))
+	ballot := make([]string, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		if seen[id] {
+			continue
+		}
+		if !valid[id] {
+			return apperror.NewNotFound("option not found")
+		}
+		seen[id] = true
+		ballot = append(ballot, id)
+	}
+	if len(ballot) == 0 {
+		return apperror.NewBadRequest("pick at least one option")
+	}
+	if !p.MultipleChoice && len(ballot) > 1 {
+		return apperror.NewBadRequest("this poll takes a single choice")
+	}
+	if err := s.repo.ReplacePollVotes(ctx, pollID, userID, ballot); err != nil {
+		return apperror.NewInternal(fmt.Errorf("recording vote: %w", err))
+	}
+	return nil
+}
+
+// ClosePoll (Scribe+) closes a poll now, ahead of any deadline.
+func (s *sessionService) ClosePoll(ctx context.Context, campaignID, pollID string) error {
+	p, opts, err := s.repo.GetPoll(ctx, campaignID, pollID)
+	if err != nil {
+		return err
+	}
+	if p.Status == PollClosed {
+		return apperror.NewBadRequest("this poll is already closed")
+	}
+	closed, err := s.finishPoll(ctx, p, opts)
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return apperror.NewBadRequest("this poll is already closed")
+	}
+	return nil
+}
+
+// CloseDuePolls closes every poll whose deadline has passed and returns how
+// many this pass closed.
+func (s *sessionService) CloseDuePolls(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDuePolls(ctx, now, pollCloseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	closed := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		opts, err := s.repo.ListPollOptions(ctx, due[i].ID)
+		if err != nil {
+			slog.Warn("loading poll options failed", slog.String("poll_id", due[i].ID), slog.Any("error", err))
+			continue
+		}
+		ok, err := s.finishPoll(ctx, &due[i], opts)
+		if err != nil {
+			slog.Warn("closing due poll failed", slog.String("poll_id", due[i].ID), slog.Any("error", err))
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// loadPoll fetches a poll and, if its deadline has passed, closes it before
+// returning, so reads and votes never act on an expired poll.
+func (s *sessionService) loadPoll(ctx context.Context, campaignID, pollID string) (*Poll, []PollOption, error) {
+	p, opts, err := s.repo.GetPoll(ctx, campaignID, pollID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.IsDue(time.Now().UTC()) {
+		return p, opts, nil
+	}
+	if _, err := s.finishPoll(ctx, p, opts); err != nil {
+		return nil, nil, err
+	}
+	// Re-read either way: if another closer won the race, its winner stands.
+	return s.repo.GetPoll(ctx, campaignID, pollID)
+}
+
+// finishPoll closes p with its current leader as the winner, then hands a
+// winning date option to the calendar and tells voters the result. It reports
+// whether this call did the close; losing the race to another closer is not
+// an error. Calendar and notification failures are logged, not returned: the
+// result stands either way and the event can be added by hand.
+func (s *sessionService) finishPoll(ctx context.Context, p *Poll, opts []PollOption) (bool, error) {
+	votes, err := s.repo.ListPollVotes(ctx, p.ID)
+	if err != nil {
+		return false, apperror.NewInternal(fmt.Errorf("loading poll votes: %w", err))
+	}
+	winner := pollWinner(opts, votes)
+	var winnerID *string
+	if winner != nil {
+		winnerID = &winner.ID
+	}
+	if err := s.repo.ClosePoll(ctx, p.ID, winnerID); err != nil {
+		if errors.Is(err, errPollAlreadyClosed) {
+			return false, nil
+		}
+		return false, apperror.NewInternal(fmt.Errorf("closing poll: %w", err))
+	}
+	p.Status, p.WinnerOptionID = PollClosed, winnerID
+
+	if winner != nil && winner.IsDate() && s.pollEvents != nil {
+		eventID, err := s.pollEvents.CreatePollEvent(ctx, p.CampaignID, PollEvent{
+			Name:      p.Question,
+			StartsAt:  *winner.StartsAtUTC,
+			EndsAt:    *winner.EndsAtUTC,
+			TZ:        p.TZ,
+			CreatedBy: p.CreatedBy,
+		})
+		switch {
+		case err != nil:
+			slog.Warn("creating calendar event for poll failed", slog.String("poll_id", p.ID), slog.Any("error", err))
+		case eventID != "":
+			if err := s.repo.SetPollCalendarEvent(ctx, p.ID, eventID); err != nil {
+				slog.Warn("recording poll calendar event failed", slog.String("poll_id", p.ID), slog.Any("error", err))
+			}
+			p.CalendarEventID = &eventID
+		}
+	}
+
+	if err := s.notifyPollClosed(ctx, p, winner, votes); err != nil {
+		slog.Warn("failed to write poll result notifications", slog.String("poll_id", p.ID), slog.Any("error", err))
+	}
+	return true, nil
+}
+
+// pollWinner returns the option with the most votes, nil when nobody voted.
+// Ties go to the earliest option (opts are in ordinal order), so the result
+// is deterministic.
+func pollWinner(opts []PollOption, votes []PollVote) *PollOption {
+	counts := make(map[string]int, len(opts))
+	for _, v := range votes {
+		counts[v.OptionID]++
+	}
+	var winner *PollOption
+	best := 0
+	for i := range opts {
+		if n := counts[opts[i].ID]; n > best {
+			best, winner = n, &opts[i]
+		}
+	}
+	return winner
+}
+
+// PollCloser is the slice of SessionService the deadline job needs.
+type PollCloser interface {
+	CloseDuePolls(ctx context.Context, now time.Time) (int, error)
+}
+
+// PollCloseJob closes polls when their deadline passes.
+type PollCloseJob struct {
+	closer PollCloser
+	now    func() time.Time
+}
+
+// NewPollCloseJob creates the deadline job.
+func NewPollCloseJob(c PollCloser) *PollCloseJob {
+	return &PollCloseJob{closer: c, now: time.Now}
+}
+
+// Run closes every due poll, batch by batch.
+func (j *PollCloseJob) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := j.closer.CloseDuePolls(ctx, j.now())
+		total += n
+		if err != nil || n < pollCloseBatchSize || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job every minute until ctx is cancelled.
+func (j *PollCloseJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(pollCloseInterval)
+	defer ticker.Stop()
+
+	slog.Info("poll deadline worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("poll deadline worker stopped")
+			return
+		case <-ticker.C:
+			n, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("poll deadline run failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("polls closed at deadline", slog.Int("polls", n))
+			}
+		}
+	}
+}
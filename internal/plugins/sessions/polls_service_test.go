@@ -0,0 +1,292 @@
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// pollStore backs a mockSessionRepo with one in-memory poll, so close and
+// vote paths see each other's writes.
+type pollStore struct {
+	poll   *Poll
+	opts   []PollOption
+	votes  []PollVote
+	events []string // calendar event IDs recorded on the poll
+}
+
+func (st *pollStore) repo() *mockSessionRepo {
+	return &mockSessionRepo{
+		getPollFn: func(_ context.Context, campaignID, pollID string) (*Poll, []PollOption, error) {
+			if st.poll.CampaignID != campaignID || st.poll.ID != pollID {
+				return nil, nil, apperror.NewNotFound("poll not found")
+			}
+			p := *st.poll
+			return &p, st.opts, nil
+		},
+		listPollOptionsFn: func(context.Context, string) ([]PollOption, error) { return st.opts, nil },
+		listPollVotesFn:   func(context.Context, string) ([]PollVote, error) { return st.votes, nil },
+		replacePollVotesFn: func(_ context.Context, pollID, userID string, optionIDs []string) error {
+			kept := st.votes[:0]
+			for _, v := range st.votes {
+				if v.UserID != userID {
+					kept = append(kept, v)
+				}
+			}
+			for _, id := range optionIDs {
+				kept = append(kept, PollVote{OptionID: id, PollID: pollID, UserID: userID})
+			}
+			st.votes = kept
+			return nil
+		},
+		closePollFn: func(_ context.Context, _ string, winner *string) error {
+			if st.poll.Status == PollClosed {
+				return errPollAlreadyClosed
+			}
+			st.poll.Status, st.poll.WinnerOptionID = PollClosed, winner
+			return nil
+		},
+		setPollCalendarEventFn: func(_ context.Context, _, eventID string) error {
+			st.events = append(st.events, eventID)
+			st.poll.CalendarEventID = &eventID
+			return nil
+		},
+	}
+}
+
+// newPollStore returns an open poll on camp-1 with a text option and a date
+// option.
+func newPollStore(t *testing.T) *pollStore {
+	start, end := mustUTC(t, "2026-07-18T23:00:00Z"), mustUTC(t, "2026-07-19T03:00:00Z")
+	return &pollStore{
+		poll: &Poll{ID: "poll-1", CampaignID: "camp-1", CreatedBy: "dm-1", Question: "Which night?", TZ: "America/New_York", Status: PollOpen},
+		opts: []PollOption{
+			{ID: "opt-text", PollID: "poll-1", Label: "Pizza night", Ordinal: 1},
+			{ID: "opt-date", PollID: "poll-1", Label: "Saturday", StartsAtUTC: &start, EndsAtUTC: &end, Ordinal: 2},
+		},
+	}
+}
+
+// recordingEventCreator counts calendar hand-offs.
+type recordingEventCreator struct{ events []PollEvent }
+
+func (r *recordingEventCreator) CreatePollEvent(_ context.Context, _ string, ev PollEvent) (string, error) {
+	r.events = append(r.events, ev)
+	return "evt-1", nil
+}
+
+func TestCreatePoll_DateOptionsAndDeadline(t *testing.T) {
+	var captured []PollOption
+	var poll *Poll
+	repo := &mockSessionRepo{
+		createPollFn: func(_ context.Context, p *Poll, options []PollOption) error {
+			poll, captured = p, options
+			return nil
+		},
+	}
+	svc := NewSessionService(repo, nil)
+	deadline := time.Now().Add(48 * time.Hour).In(time.UTC).Format(pollDeadlineLayout)
+	_, err := svc.CreatePoll(context.Background(), "camp-1", "dm-1", CreatePollRequest{
+		Question: "  Which night?  ",
+		TZ:       "America/New_York",
+		ClosesAt: deadline,
+		Options: []PollOptionInput{
+			{Date: "2026-07-18", StartMinute: 19 * 60, EndMinute: 23 * 60},
+			{Label: "Not this week"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreatePoll error: %v", err)
+	}
+	if poll.Question != "Which night?" || poll.ClosesAt == nil || poll.Status != PollOpen {
+		t.Errorf("poll = %+v, want trimmed question, a deadline and open status", poll)
+	}
+	if len(captured) != 2 {
+		t.Fatalf("expected 2 options, got %d", len(captured))
+	}
+	date := captured[0]
+	if !date.IsDate() || !date.StartsAtUTC.Equal(mustUTC(t, "2026-07-18T23:00:00Z")) || !date.EndsAtUTC.Equal(mustUTC(t, "2026-07-19T03:00:00Z")) {
+		t.Errorf("date option = %+v, want 23:00Z..03:00Z", date)
+	}
+	if date.Label != "Sat, Jul 18, 7:00 PM – 11:00 PM" {
+		t.Errorf("date option label = %q, want the local slot label", date.Label)
+	}
+	if captured[1].IsDate() || captured[1].Ordinal != 2 {
+		t.Errorf("text option = %+v", captured[1])
+	}
+}
+
+func TestCreatePoll_Validation(t *testing.T) {
+	svc := NewSessionService(&mockSessionRepo{}, nil)
+	base := func() CreatePollRequest {
+		return CreatePollRequest{Question: "Q", TZ: "UTC", Options: []PollOptionInput{{Label: "A"}, {Label: "B"}}}
+	}
+	cases := []struct {
+		name string
+		mut  func(*CreatePollRequest)
+	}{
+		{"empty question", func(r *CreatePollRequest) { r.Question = "  " }},
+		{"one option", func(r *CreatePollRequest) { r.Options = r.Options[:1] }},
+		{"too many options", func(r *CreatePollRequest) { r.Options = make([]PollOptionInput, 11) }},
+		{"blank option", func(r *CreatePollRequest) { r.Options[1].Label = " " }},
+		{"bad tz", func(r *CreatePollRequest) { r.TZ = "Not/AZone" }},
+		{"bad date", func(r *CreatePollRequest) { r.Options[0].Date = "2026-13-40" }},
+		{"inverted range", func(r *CreatePollRequest) {
+			r.Options[0] = PollOptionInput{Date: "2026-07-18", StartMinute: 120, EndMinute: 60}
+		}},
+		{"past deadline", func(r *CreatePollRequest) { r.ClosesAt = "2020-01-01T19:00" }},
+		{"bad deadline", func(r *CreatePollRequest) { r.ClosesAt = "next friday" }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := base()
+			tc.mut(&req)
+			_, err := svc.CreatePoll(context.Background(), "camp-1", "dm-1", req)
+			assertAppError(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestCastPollVote(t *testing.T) {
+	st := newPollStore(t)
+	svc := NewSessionService(st.repo(), nil)
+	ctx := context.Background()
+
+	if err := svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", []string{"opt-text", "opt-date"}); err == nil {
+		t.Error("single-choice poll accepted two options")
+	}
+	assertAppError(t, svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", []string{"opt-elsewhere"}), http.StatusNotFound)
+	assertAppError(t, svc.CastPollVote(ctx, "camp-2", "poll-1", "p1", []string{"opt-text"}), http.StatusNotFound)
+	assertAppError(t, svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", nil), http.StatusBadRequest)
+
+	// Re-voting replaces the ballot rather than adding to it.
+	for _, id := range []string{"opt-text", "opt-date"} {
+		if err := svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", []string{id}); err != nil {
+			t.Fatalf("CastPollVote(%s): %v", id, err)
+		}
+	}
+	if len(st.votes) != 1 || st.votes[0].OptionID != "opt-date" {
+		t.Errorf("votes = %+v, want only the latest ballot", st.votes)
+	}
+
+	st.poll.MultipleChoice = true
+	if err := svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", []string{"opt-text", "opt-date", "opt-text"}); err != nil {
+		t.Fatalf("multi-choice vote: %v", err)
+	}
+	if len(st.votes) != 2 {
+		t.Errorf("votes = %+v, want both options once", st.votes)
+	}
+
+	st.poll.Status = PollClosed
+	assertAppError(t, svc.CastPollVote(ctx, "camp-1", "poll-1", "p1", []string{"opt-text"}), http.StatusBadRequest)
+}
+
+func TestPollWinner(t *testing.T) {
+	opts := []PollOption{{ID: "a", Ordinal: 1}, {ID: "b", Ordinal: 2}, {ID: "c", Ordinal: 3}}
+	cases := []struct {
+		name  string
+		votes []string
+		want  string
+	}{
+		{"no votes", nil, ""},
+		{"clear leader", []string{"c", "b", "c"}, "c"},
+		{"tie goes to the earliest option", []string{"c", "b"}, "b"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var votes []PollVote
+			for i, id := range tc.votes {
+				votes = append(votes, PollVote{OptionID: id, UserID: string(rune('u' + i))})
+			}
+			got := pollWinner(opts, votes)
+			if (got == nil && tc.want != "") || (got != nil && got.ID != tc.want) {
+				t.Errorf("winner = %+v, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClosePoll_DateWinnerCreatesCalendarEventOnce(t *testing.T) {
+	st := newPollStore(t)
+	st.votes = []PollVote{{OptionID: "opt-date", UserID: "p1"}, {OptionID: "opt-date", UserID: "p2"}, {OptionID: "opt-text", UserID: "p3"}}
+	var notified []string
+	repo := st.repo()
+	repo.createNotificationFn = func(_ context.Context, n *Notification) error {
+		notified = append(notified, n.UserID)
+		return nil
+	}
+	svc := NewSessionService(repo, nil)
+	events := &recordingEventCreator{}
+	svc.SetPollEventCreator(events)
+	ctx := context.Background()
+
+	if err := svc.ClosePoll(ctx, "camp-1", "poll-1"); err != nil {
+		t.Fatalf("ClosePoll: %v", err)
+	}
+	if st.poll.WinnerOptionID == nil || *st.poll.WinnerOptionID != "opt-date" {
+		t.Errorf("winner = %v, want opt-date", st.poll.WinnerOptionID)
+	}
+	if len(events.events) != 1 || !events.events[0].StartsAt.Equal(mustUTC(t, "2026-07-18T23:00:00Z")) || events.events[0].Name != "Which night?" {
+		t.Errorf("calendar events = %+v, want the winning slot once", events.events)
+	}
+	if len(st.events) != 1 || st.events[0] != "evt-1" {
+		t.Errorf("recorded events = %v, want [evt-1]", st.events)
+	}
+	if len(notified) != 4 {
+		t.Errorf("notified %v, want the creator and each voter once", notified)
+	}
+
+	assertAppError(t, svc.ClosePoll(ctx, "camp-1", "poll-1"), http.StatusBadRequest)
+	if len(events.events) != 1 {
+		t.Errorf("a second close created another event: %+v", events.events)
+	}
+}
+
+func TestClosePoll_TextWinnerSkipsCalendar(t *testing.T) {
+	st := newPollStore(t)
+	st.votes = []PollVote{{OptionID: "opt-text", UserID: "p1"}}
+	svc := NewSessionService(st.repo(), nil)
+	events := &recordingEventCreator{}
+	svc.SetPollEventCreator(events)
+
+	if err := svc.ClosePoll(context.Background(), "camp-1", "poll-1"); err != nil {
+		t.Fatalf("ClosePoll: %v", err)
+	}
+	if len(events.events) != 0 || st.poll.CalendarEventID != nil {
+		t.Errorf("text winner created a calendar event: %+v", events.events)
+	}
+}
+
+func TestGetPollView_DeadlineAndAnonymity(t *testing.T) {
+	st := newPollStore(t)
+	past := time.Now().Add(-time.Minute).UTC()
+	st.poll.ClosesAt = &past
+	st.poll.Anonymous = true
+	st.votes = []PollVote{{OptionID: "opt-text", UserID: "p1"}, {OptionID: "opt-text", UserID: "p2"}}
+	svc := NewSessionService(st.repo(), nil)
+
+	view, err := svc.GetPollView(context.Background(), "camp-1", "poll-1", "p1", "America/New_York")
+	if err != nil {
+		t.Fatalf("GetPollView: %v", err)
+	}
+	if view.Poll.Status != PollClosed {
+		t.Errorf("status = %q, want a poll past its deadline closed on read", view.Poll.Status)
+	}
+	if !view.MyVoted || view.VoterCount != 2 || view.ShowVoters {
+		t.Errorf("view = %+v, want 2 voters, the viewer's vote, and no voter list", view)
+	}
+	text := view.Options[0]
+	if text.Votes != 2 || !text.Mine || !text.IsWinner || len(text.Voters) != 0 {
+		t.Errorf("text option = %+v", text)
+	}
+	if date := view.Options[1]; date.Local == nil || date.Local.TimeLabel != "7:00 PM – 11:00 PM" {
+		t.Errorf("date option local = %+v, want the viewer-zone label", date.Local)
+	}
+
+	// Voting after the deadline is refused even before any sweep runs.
+	st.poll.Status, st.poll.WinnerOptionID = PollOpen, nil
+	assertAppError(t, svc.CastPollVote(context.Background(), "camp-1", "poll-1", "p3", []string{"opt-date"}), http.StatusBadRequest)
+}
@@ -31,6 +31,7 @@ type SessionRepository interface {
 	LinkEntity(ctx context.Context, sessionID, entityID, role string) error
 	UnlinkEntity(ctx context.Context, sessionID, entityID string) error
 	ListSessionEntities(ctx context.Context, sessionID string) ([]SessionEntity, error)
+	ListEntitySessions(ctx context.Context, campaignID, entityID string) ([]EntitySession, error)
 
 	// RSVP tokens for email-based responses.
 	CreateRSVPToken(ctx context.Context, token *RSVPToken) error
@@ -70,6 +71,38 @@ type SessionRepository interface {
 	FindProposalToken(ctx context.Context, tokenStr string) (*SlotProposalToken, error)
 	MarkProposalTokenUsed(ctx context.Context, tokenStr string) error
 
+	// Member polls. Own tables (polls, poll_options, poll_votes) — see
+	// polls_repository.go. ClosePoll is conditional on the poll being open
+	// and returns errPollAlreadyClosed when another closer got there first.
+	CreatePoll(ctx context.Context, p *Poll, options []PollOption) error
+	GetPoll(ctx context.Context, campaignID, pollID string) (*Poll, []PollOption, error)
+	ListPolls(ctx context.Context, campaignID string) ([]Poll, error)
+	ListDuePolls(ctx context.Context, now time.Time, limit int) ([]Poll, error)
+	ListPollOptions(ctx context.Context, pollID string) ([]PollOption, error)
+	ListPollVotes(ctx context.Context, pollID string) ([]PollVote, error)
+	ReplacePollVotes(ctx context.Context, pollID, userID string, optionIDs []string) error
+	ClosePoll(ctx context.Context, pollID string, winnerOptionID *string) error
+	SetPollCalendarEvent(ctx context.Context, pollID, eventID string) error
+
+	// Safety tools. Own tables (safety_settings, safety_entries,
+	// safety_consent_answers) — see safety_repository.go.
+	GetSafetySettings(ctx context.Context, campaignID string) (*SafetySettings, error)
+	SaveSafetySettings(ctx context.Context, s *SafetySettings) error
+	CreateSafetyEntry(ctx context.Context, e *SafetyEntry) error
+	GetSafetyEntry(ctx context.Context, campaignID, entryID string) (*SafetyEntry, error)
+	ListSafetyEntries(ctx context.Context, campaignID string) ([]SafetyEntry, error)
+	DeleteSafetyEntry(ctx context.Context, campaignID, entryID string) error
+	ReplaceConsentAnswers(ctx context.Context, campaignID, userID string, answers []ConsentAnswer) error
+	ListConsentAnswers(ctx context.Context, campaignID string) ([]ConsentAnswer, error)
+
+	// Session transcripts. Own tables (session_transcripts,
+	// session_transcript_mentions) — see transcript_repository.go.
+	GetTranscript(ctx context.Context, sessionID string) (*Transcript, error)
+	SaveTranscript(ctx context.Context, t *Transcript, mentions []TranscriptMention) error
+	ReplaceTranscriptMentions(ctx context.Context, sessionID string, scannedAt time.Time, mentions []TranscriptMention) error
+	ListTranscriptMentions(ctx context.Context, sessionID string) ([]TranscriptMention, error)
+	DeleteTranscript(ctx context.Context, sessionID string) error
+
 	// Scheduler-scoped notifications (C-SCHED-P2). Own table (notifications);
 	// see notifications_repository.go.
 	CreateNotification(ctx context.Context, n *Notification) error
@@ -77,6 +110,7 @@ type SessionRepository interface {
 	CountUnreadNotifications(ctx context.Context, userID string) (int, error)
 	MarkNotificationRead(ctx context.Context, userID, notificationID string) error
 	MarkAllNotificationsRead(ctx context.Context, userID string) error
+	PurgeNotificationsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error)
 }
 
 // sessionRepository implements SessionRepository with MariaDB queries.
@@ -410,6 +444,32 @@ func (r *sessionRepository) ListSessionEntities(ctx context.Context, sessionID s
 	return entities, rows.Err()
 }
 
+// ListEntitySessions returns the campaign's sessions an entity is linked
+// to, most recent first; undated sessions sort last.
+func (r *sessionRepository) ListEntitySessions(ctx context.Context, campaignID, entityID string) ([]EntitySession, error) {
+	query := `SELECT s.id, s.name, s.status, s.scheduled_date, se.role
+	          FROM session_entities se
+	          INNER JOIN sessions s ON s.id = se.session_id
+	          WHERE s.campaign_id = ? AND se.entity_id = ?
+	          ORDER BY s.scheduled_date IS NULL, s.scheduled_date DESC, s.sort_order ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("listing entity sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []EntitySession
+	for rows.Next() {
+		var es EntitySession
+		if err := rows.Scan(&es.SessionID, &es.Name, &es.Status, &es.ScheduledDate, &es.Role); err != nil {
+			return nil, fmt.Errorf("scanning entity session row: %w", err)
+		}
+		sessions = append(sessions, es)
+	}
+	return sessions, rows.Err()
+}
+
 // --- Date Range Queries (for calendar integration) ---
 
 // ListByDateRange returns sessions in a campaign that fall within a date range.
@@ -1,8 +1,11 @@
 package sessions
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/addons"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
@@ -27,6 +30,13 @@ func RegisterRoutes(e *echo.Echo, h *Handler,
 	cg.POST("/sessions/:sid/entities", h.LinkEntityAPI, campaigns.RequireRole(campaigns.RoleScribe))
 	cg.DELETE("/sessions/:sid/entities/:eid", h.UnlinkEntityAPI, campaigns.RequireRole(campaigns.RoleScribe))
 
+	// Session transcripts. GM material like session notes, so Scribe+ only,
+	// and the mention index is still filtered to the pages the viewer sees.
+	cg.GET("/sessions/:sid/transcript", h.ShowTranscript, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/sessions/:sid/transcript", h.UploadTranscriptAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.POST("/sessions/:sid/transcript/rescan", h.RescanTranscriptAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.DELETE("/sessions/:sid/transcript", h.DeleteTranscriptAPI, campaigns.RequireRole(campaigns.RoleScribe))
+
 	// Availability scheduler (C-SCHED-P1). Member-only data — every route rides
 	// the AUTHED cg group above (auth + campaign access + the calendar-addon
 	// guard), NEVER the public pub group below. Any member (Player+) may record
@@ -57,6 +67,26 @@ func RegisterRoutes(e *echo.Echo, h *Handler,
 	// the proposal and mints a planned session from that slot.
 	cg.POST("/proposals/:pid/confirm", h.ConfirmProposalAPI, campaigns.RequireRole(campaigns.RoleScribe))
 
+	// Member polls. Same member-only gating as proposals: any member (Player+)
+	// may view and vote; Scribe+ may create a poll or close it early.
+	cg.GET("/polls", h.ListPolls, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/polls", h.CreatePollAPI, campaigns.RequireRole(campaigns.RoleScribe))
+	cg.GET("/polls/:pid", h.ShowPoll, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/polls/:pid/vote", h.VotePollAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/polls/:pid/close", h.ClosePollAPI, campaigns.RequireRole(campaigns.RoleScribe))
+
+	// Safety tools. Same member-only gating: any member (Player+) may add lines
+	// & veils, answer the consent survey and tap the X-card; only the owner may
+	// change the settings, and submitter names and survey totals are gated to
+	// the owner inside the handler by role. The X-card is rate limited so a
+	// stuck button can't flood the owner's notifications.
+	cg.GET("/safety", h.ShowSafety, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/safety/entries", h.AddSafetyEntryAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.DELETE("/safety/entries/:eid", h.DeleteSafetyEntryAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/safety/consent", h.SaveConsentAPI, campaigns.RequireRole(campaigns.RolePlayer))
+	cg.POST("/safety/settings", h.UpdateSafetySettingsAPI, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/safety/x-card", h.XCardAPI, campaigns.RequireRole(campaigns.RolePlayer), middleware.RateLimit(10, time.Minute))
+
 	// Public-capable view routes.
 	pub := e.Group("/campaigns/:id",
 		auth.OptionalAuth(authSvc),
@@ -0,0 +1,179 @@
+package sessions
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Safety tools HTTP surface. Thin like the poll handlers: bind, call the
+// service, render. Owner lookups for the X-card and new-entry alerts live
+// here, since the handler has the member list.
+
+// ShowSafety renders the safety tools page.
+// GET /campaigns/:id/safety
+func (h *Handler) ShowSafety(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	view, err := h.safetyView(c, cc)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return middleware.Render(c, http.StatusOK, SafetyPage(cc, view, middleware.GetCSRFToken(c)))
+}
+
+// AddSafetyEntryAPI adds a line or veil, then alerts the owners.
+// POST /campaigns/:id/safety/entries
+func (h *Handler) AddSafetyEntryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	userID := auth.GetUserID(c)
+	var req AddSafetyEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	entry, err := h.svc.AddSafetyEntry(c.Request().Context(), cc.Campaign.ID, userID, req)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+
+	if owners := h.campaignOwners(c.Request().Context(), cc.Campaign.ID, userID); len(owners) > 0 {
+		go func() {
+			if err := h.svc.NotifySafetyEntry(context.Background(), cc.Campaign.ID, entry, owners); err != nil {
+				slog.Warn("failed to write safety entry notifications", slog.Any("error", err))
+			}
+		}()
+	}
+	return h.renderSafetyResult(c, cc)
+}
+
+// DeleteSafetyEntryAPI removes a line or veil (the owner, or its submitter).
+// DELETE /campaigns/:id/safety/entries/:eid
+func (h *Handler) DeleteSafetyEntryAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	isOwner := cc.MemberRole >= campaigns.RoleOwner
+	if err := h.svc.DeleteSafetyEntry(c.Request().Context(), cc.Campaign.ID, c.Param("eid"), auth.GetUserID(c), isOwner); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderSafetyResult(c, cc)
+}
+
+// SaveConsentAPI replaces the member's consent survey. The form posts one
+// "consent_<topic>" field per topic; a blank value leaves it unanswered.
+// POST /campaigns/:id/safety/consent
+func (h *Handler) SaveConsentAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	answers := make(map[string]string, len(ConsentTopics))
+	for _, t := range ConsentTopics {
+		answers[t.Key] = c.FormValue("consent_" + t.Key)
+	}
+	if err := h.svc.SaveConsentAnswers(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), answers); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderSafetyResult(c, cc)
+}
+
+// UpdateSafetySettingsAPI turns anonymous submissions on or off (Owner).
+// POST /campaigns/:id/safety/settings
+func (h *Handler) UpdateSafetySettingsAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	allow := c.FormValue("allow_anonymous") != ""
+	if err := h.svc.UpdateSafetySettings(c.Request().Context(), cc.Campaign.ID, allow); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderSafetyResult(c, cc)
+}
+
+// XCardAPI records an X-card tap by notifying the campaign's owners. Nothing
+// is stored and the notification carries no name, so the tap stays discreet.
+// POST /campaigns/:id/safety/x-card
+func (h *Handler) XCardAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	var req XCardRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
+	}
+	owners := h.campaignOwners(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c))
+	if err := h.svc.NotifyXCard(c.Request().Context(), cc.Campaign.ID, req.SessionID, owners); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// renderSafetyResult answers a safety write: the refreshed page body for
+// HTMX, a status for JSON.
+func (h *Handler) renderSafetyResult(c echo.Context, cc *campaigns.CampaignContext) error {
+	if !middleware.IsHTMX(c) {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+	view, err := h.safetyView(c, cc)
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return middleware.Render(c, http.StatusOK, SafetyFragment(cc, view, middleware.GetCSRFToken(c)))
+}
+
+// safetyView loads the safety page for the viewer and, for owners, resolves
+// submitter names.
+func (h *Handler) safetyView(c echo.Context, cc *campaigns.CampaignContext) (*SafetyView, error) {
+	isOwner := cc.MemberRole >= campaigns.RoleOwner
+	view, err := h.svc.GetSafetyView(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), isOwner)
+	if err != nil {
+		return nil, err
+	}
+	if isOwner {
+		h.fillSubmitterNames(c.Request().Context(), cc.Campaign.ID, view)
+	}
+	return view, nil
+}
+
+// fillSubmitterNames resolves display names for named lines & veils, the
+// same member-directory lookup fillVoterNames does for polls.
+func (h *Handler) fillSubmitterNames(ctx context.Context, campaignID string, view *SafetyView) {
+	if h.memberLister == nil {
+		return
+	}
+	members, err := h.memberLister.ListMembers(ctx, campaignID)
+	if err != nil {
+		return
+	}
+	nameByUser := make(map[string]string, len(members))
+	for _, m := range members {
+		nameByUser[m.UserID] = m.DisplayName
+	}
+	for _, list := range [][]SafetyEntryView{view.Lines, view.Veils} {
+		for i := range list {
+			if list[i].Entry.SubmittedBy == nil {
+				continue
+			}
+			if n := nameByUser[*list[i].Entry.SubmittedBy]; n != "" {
+				list[i].SubmitterName = n
+			} else {
+				list[i].SubmitterName = "Member"
+			}
+		}
+	}
+}
+
+// campaignOwners returns the campaign's owners other than exclude.
+func (h *Handler) campaignOwners(ctx context.Context, campaignID, exclude string) []string {
+	if h.memberLister == nil {
+		return nil
+	}
+	members, err := h.memberLister.ListMembers(ctx, campaignID)
+	if err != nil {
+		return nil
+	}
+	var owners []string
+	for _, m := range members {
+		if m.Role == campaigns.RoleOwner && m.UserID != exclude {
+			owners = append(owners, m.UserID)
+		}
+	}
+	return owners
+}
@@ -0,0 +1,128 @@
+package sessions
+
+import "time"
+
+// This file holds the Session 0 safety tool types. A campaign keeps a lines &
+// veils list (lines are never in the game, veils happen off-screen) that any
+// member can add to, anonymously if the owner allows it. Members answer a
+// consent survey per topic; the owner sees only the per-topic totals. The
+// X-card has no stored state: tapping it just notifies the campaign's owners,
+// without the tapper's name.
+
+// Lines & veils entry kinds.
+const (
+	SafetyLine = "line"
+	SafetyVeil = "veil"
+)
+
+// Consent survey ratings.
+const (
+	ConsentGreen  = "green"  // Fine to include.
+	ConsentYellow = "yellow" // Off-screen only; talk to me first.
+	ConsentRed    = "red"    // Not at all.
+)
+
+// Safety notification types.
+const (
+	NotifXCard       = "x_card"
+	NotifSafetyEntry = "safety_entry"
+)
+
+// maxSafetyTopicLen caps a lines & veils entry.
+const maxSafetyTopicLen = 200
+
+// ConsentTopic is one consent survey question.
+type ConsentTopic struct {
+	Key   string
+	Label string
+}
+
+// ConsentTopics is the consent survey, in display order. Keys are stored with
+// each answer, so existing keys must never change; retired topics just drop
+// out of the list and their answers are ignored.
+var ConsentTopics = []ConsentTopic{
+	{Key: "violence", Label: "Graphic violence"},
+	{Key: "gore", Label: "Blood and gore"},
+	{Key: "torture", Label: "Torture"},
+	{Key: "body_horror", Label: "Body horror"},
+	{Key: "sexual_content", Label: "Sexual content"},
+	{Key: "romance", Label: "Romance between characters"},
+	{Key: "harm_children", Label: "Harm to children"},
+	{Key: "animal_cruelty", Label: "Animal cruelty"},
+	{Key: "self_harm", Label: "Self-harm or suicide"},
+	{Key: "abuse", Label: "Abuse or domestic violence"},
+	{Key: "bigotry", Label: "Racism and bigotry"},
+	{Key: "drugs", Label: "Drug use and addiction"},
+	{Key: "mental_illness", Label: "Mental illness"},
+	{Key: "religion", Label: "Real-world religion"},
+	{Key: "insects", Label: "Spiders and insects"},
+}
+
+// SafetySettings are the owner's per-campaign safety tool choices.
+type SafetySettings struct {
+	CampaignID     string
+	AllowAnonymous bool
+}
+
+// SafetyEntry is one line or veil. SubmittedBy is nil for anonymous entries.
+type SafetyEntry struct {
+	ID          string
+	CampaignID  string
+	Kind        string
+	Topic       string
+	SubmittedBy *string
+	CreatedAt   time.Time
+}
+
+// ConsentAnswer is one member's rating for one survey topic.
+type ConsentAnswer struct {
+	UserID string
+	Topic  string
+	Rating string
+}
+
+// --- API request DTOs ---
+
+// AddSafetyEntryRequest adds a line or veil. Anonymous is honoured only when
+// the owner allows anonymous submissions.
+type AddSafetyEntryRequest struct {
+	Kind      string `json:"kind" form:"kind"`
+	Topic     string `json:"topic" form:"topic"`
+	Anonymous bool   `json:"anonymous" form:"anonymous"`
+}
+
+// XCardRequest is an X-card tap. SessionID, when set, names the session in
+// the owner's notification.
+type XCardRequest struct {
+	SessionID string `json:"sessionId" form:"sessionId"`
+}
+
+// --- View types ---
+
+// SafetyView is the safety tools page for one viewer. Results is filled only
+// for owners.
+type SafetyView struct {
+	Settings  SafetySettings
+	Lines     []SafetyEntryView
+	Veils     []SafetyEntryView
+	MyAnswers map[string]string // topic key -> rating
+	Results   []ConsentTally
+	Responded int // members who answered at least one topic
+}
+
+// SafetyEntryView is one entry for one viewer. SubmitterName is set only for
+// owners viewing a named entry; names are filled by the handler.
+type SafetyEntryView struct {
+	Entry         SafetyEntry
+	SubmitterName string
+	Mine          bool
+	CanDelete     bool
+}
+
+// ConsentTally is one survey topic summed across members.
+type ConsentTally struct {
+	Topic  ConsentTopic
+	Green  int
+	Yellow int
+	Red    int
+}
@@ -0,0 +1,151 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Safety tool persistence on the existing sessionRepository. Settings,
+// entries and consent answers live in their OWN tables, out of export egress
+// like poll votes.
+
+// GetSafetySettings returns the campaign's safety settings, or the defaults
+// (anonymous submissions allowed) when the owner never changed them.
+func (r *sessionRepository) GetSafetySettings(ctx context.Context, campaignID string) (*SafetySettings, error) {
+	s := &SafetySettings{CampaignID: campaignID, AllowAnonymous: true}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT allow_anonymous FROM safety_settings WHERE campaign_id = ?`, campaignID,
+	).Scan(&s.AllowAnonymous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting safety settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveSafetySettings upserts the campaign's safety settings.
+func (r *sessionRepository) SaveSafetySettings(ctx context.Context, s *SafetySettings) error {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO safety_settings (campaign_id, allow_anonymous, updated_at) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE allow_anonymous = VALUES(allow_anonymous), updated_at = VALUES(updated_at)`,
+		s.CampaignID, s.AllowAnonymous, time.Now().UTC()); err != nil {
+		return fmt.Errorf("saving safety settings: %w", err)
+	}
+	return nil
+}
+
+// CreateSafetyEntry inserts a line or veil.
+func (r *sessionRepository) CreateSafetyEntry(ctx context.Context, e *SafetyEntry) error {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO safety_entries (id, campaign_id, kind, topic, submitted_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		e.ID, e.CampaignID, e.Kind, e.Topic, e.SubmittedBy, e.CreatedAt); err != nil {
+		return fmt.Errorf("creating safety entry: %w", err)
+	}
+	return nil
+}
+
+// GetSafetyEntry loads one entry, scoped to the campaign.
+func (r *sessionRepository) GetSafetyEntry(ctx context.Context, campaignID, entryID string) (*SafetyEntry, error) {
+	var e SafetyEntry
+	var submittedBy sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, campaign_id, kind, topic, submitted_by, created_at
+		 FROM safety_entries WHERE id = ? AND campaign_id = ?`, entryID, campaignID,
+	).Scan(&e.ID, &e.CampaignID, &e.Kind, &e.Topic, &submittedBy, &e.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperror.NewNotFound("safety entry not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting safety entry: %w", err)
+	}
+	if submittedBy.Valid {
+		e.SubmittedBy = &submittedBy.String
+	}
+	return &e, nil
+}
+
+// ListSafetyEntries returns the campaign's lines & veils, oldest first.
+func (r *sessionRepository) ListSafetyEntries(ctx context.Context, campaignID string) ([]SafetyEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, campaign_id, kind, topic, submitted_by, created_at
+		 FROM safety_entries WHERE campaign_id = ? ORDER BY created_at, id`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing safety entries: %w", err)
+	}
+	defer rows.Close()
+	var out []SafetyEntry
+	for rows.Next() {
+		var e SafetyEntry
+		var submittedBy sql.NullString
+		if err := rows.Scan(&e.ID, &e.CampaignID, &e.Kind, &e.Topic, &submittedBy, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning safety entry: %w", err)
+		}
+		if submittedBy.Valid {
+			e.SubmittedBy = &submittedBy.String
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// DeleteSafetyEntry removes an entry, scoped to the campaign.
+func (r *sessionRepository) DeleteSafetyEntry(ctx context.Context, campaignID, entryID string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM safety_entries WHERE id = ? AND campaign_id = ?`, entryID, campaignID); err != nil {
+		return fmt.Errorf("deleting safety entry: %w", err)
+	}
+	return nil
+}
+
+// ReplaceConsentAnswers swaps a member's whole survey for answers in one
+// transaction, the same way ReplacePollVotes swaps a ballot. Topics missing
+// from answers become unanswered.
+func (r *sessionRepository) ReplaceConsentAnswers(ctx context.Context, campaignID, userID string, answers []ConsentAnswer) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin consent tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM safety_consent_answers WHERE campaign_id = ? AND user_id = ?`, campaignID, userID); err != nil {
+		return fmt.Errorf("clearing consent answers: %w", err)
+	}
+	now := time.Now().UTC()
+	for _, a := range answers {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO safety_consent_answers (campaign_id, user_id, topic, rating, updated_at)
+			 VALUES (?, ?, ?, ?, ?)`,
+			campaignID, userID, a.Topic, a.Rating, now); err != nil {
+			return fmt.Errorf("inserting consent answer: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit consent tx: %w", err)
+	}
+	return nil
+}
+
+// ListConsentAnswers returns every member's answers for the campaign.
+func (r *sessionRepository) ListConsentAnswers(ctx context.Context, campaignID string) ([]ConsentAnswer, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, topic, rating FROM safety_consent_answers WHERE campaign_id = ?`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("listing consent answers: %w", err)
+	}
+	defer rows.Close()
+	var out []ConsentAnswer
+	for rows.Next() {
+		var a ConsentAnswer
+		if err := rows.Scan(&a.UserID, &a.Topic, &a.Rating); err != nil {
+			return nil, fmt.Errorf("scanning consent answer: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
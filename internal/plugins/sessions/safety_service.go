@@ -0,0 +1,186 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// safety_service.go — Session 0 safety tools. Any member adds lines & veils
+// (anonymously when the owner allows it) and answers the consent survey;
+// only the owner sees who submitted what and the survey totals. No one,
+// owner included, ever sees another member's individual survey answers.
+
+// GetSafetyView assembles the safety page for one viewer. Submitter names are
+// left blank for the handler to fill from the member directory, and only for
+// owners.
+func (s *sessionService) GetSafetyView(ctx context.Context, campaignID, viewerID string, isOwner bool) (*SafetyView, error) {
+	settings, err := s.repo.GetSafetySettings(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading safety settings: %w", err))
+	}
+	entries, err := s.repo.ListSafetyEntries(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading safety entries: %w", err))
+	}
+	answers, err := s.repo.ListConsentAnswers(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading consent answers: %w", err))
+	}
+
+	view := &SafetyView{Settings: *settings, MyAnswers: make(map[string]string)}
+	for _, e := range entries {
+		mine := e.SubmittedBy != nil && *e.SubmittedBy == viewerID
+		ev := SafetyEntryView{Entry: e, Mine: mine, CanDelete: isOwner || mine}
+		if !isOwner {
+			// Players see the list, not who wrote it.
+			ev.Entry.SubmittedBy = nil
+		}
+		if e.Kind == SafetyLine {
+			view.Lines = append(view.Lines, ev)
+		} else {
+			view.Veils = append(view.Veils, ev)
+		}
+	}
+
+	topics := make(map[string]bool, len(ConsentTopics))
+	for _, t := range ConsentTopics {
+		topics[t.Key] = true
+	}
+	tallies := make(map[string]*ConsentTally, len(ConsentTopics))
+	respondents := make(map[string]bool)
+	for _, a := range answers {
+		if !topics[a.Topic] {
+			continue // retired topic
+		}
+		if a.UserID == viewerID {
+			view.MyAnswers[a.Topic] = a.Rating
+		}
+		respondents[a.UserID] = true
+		t := tallies[a.Topic]
+		if t == nil {
+			t = &ConsentTally{}
+			tallies[a.Topic] = t
+		}
+		switch a.Rating {
+		case ConsentGreen:
+			t.Green++
+		case ConsentYellow:
+			t.Yellow++
+		case ConsentRed:
+			t.Red++
+		}
+	}
+	view.Responded = len(respondents)
+
+	if isOwner {
+		view.Results = make([]ConsentTally, 0, len(ConsentTopics))
+		for _, topic := range ConsentTopics {
+			tally := ConsentTally{Topic: topic}
+			if t := tallies[topic.Key]; t != nil {
+				tally.Green, tally.Yellow, tally.Red = t.Green, t.Yellow, t.Red
+			}
+			view.Results = append(view.Results, tally)
+		}
+	}
+	return view, nil
+}
+
+// AddSafetyEntry stores a line or veil. An anonymous entry keeps no
+// submitter at all, so not even the owner can trace it.
+func (s *sessionService) AddSafetyEntry(ctx context.Context, campaignID, userID string, req AddSafetyEntryRequest) (*SafetyEntry, error) {
+	if req.Kind != SafetyLine && req.Kind != SafetyVeil {
+		return nil, apperror.NewBadRequest("kind must be a line or a veil")
+	}
+	topic := strings.TrimSpace(req.Topic)
+	if topic == "" {
+		return nil, apperror.NewBadRequest("a topic is required")
+	}
+	if len(topic) > maxSafetyTopicLen {
+		return nil, apperror.NewBadRequest("topic is too long")
+	}
+
+	e := &SafetyEntry{
+		ID:         generateUUID(),
+		CampaignID: campaignID,
+		Kind:       req.Kind,
+		Topic:      topic,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if req.Anonymous {
+		settings, err := s.repo.GetSafetySettings(ctx, campaignID)
+		if err != nil {
+			return nil, apperror.NewInternal(fmt.Errorf("loading safety settings: %w", err))
+		}
+		if !settings.AllowAnonymous {
+			return nil, apperror.NewBadRequest("anonymous submissions are turned off for this campaign")
+		}
+	} else {
+		e.SubmittedBy = &userID
+	}
+	if err := s.repo.CreateSafetyEntry(ctx, e); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("creating safety entry: %w", err))
+	}
+	return e, nil
+}
+
+// DeleteSafetyEntry removes an entry. The owner may remove any entry; a
+// member only their own named ones.
+func (s *sessionService) DeleteSafetyEntry(ctx context.Context, campaignID, entryID, userID string, isOwner bool) error {
+	e, err := s.repo.GetSafetyEntry(ctx, campaignID, entryID)
+	if err != nil {
+		return err
+	}
+	if !isOwner && (e.SubmittedBy == nil || *e.SubmittedBy != userID) {
+		return apperror.NewForbidden("only the campaign owner can remove this entry")
+	}
+	if err := s.repo.DeleteSafetyEntry(ctx, campaignID, entryID); err != nil {
+		return apperror.NewInternal(fmt.Errorf("deleting safety entry: %w", err))
+	}
+	return nil
+}
+
+// SaveConsentAnswers replaces the member's survey. answers maps topic keys to
+// ratings; an empty rating leaves the topic unanswered.
+func (s *sessionService) SaveConsentAnswers(ctx context.Context, campaignID, userID string, answers map[string]string) error {
+	for key, rating := range answers {
+		if !isConsentTopic(key) {
+			return apperror.NewBadRequest("unknown survey topic")
+		}
+		if rating != "" && rating != ConsentGreen && rating != ConsentYellow && rating != ConsentRed {
+			return apperror.NewBadRequest("each answer must be green, yellow or red")
+		}
+	}
+	out := make([]ConsentAnswer, 0, len(answers))
+	for _, topic := range ConsentTopics {
+		if rating := answers[topic.Key]; rating != "" {
+			out = append(out, ConsentAnswer{UserID: userID, Topic: topic.Key, Rating: rating})
+		}
+	}
+	if err := s.repo.ReplaceConsentAnswers(ctx, campaignID, userID, out); err != nil {
+		return apperror.NewInternal(fmt.Errorf("saving consent answers: %w", err))
+	}
+	return nil
+}
+
+// UpdateSafetySettings (owner) turns anonymous submissions on or off.
+// Existing anonymous entries stay anonymous either way.
+func (s *sessionService) UpdateSafetySettings(ctx context.Context, campaignID string, allowAnonymous bool) error {
+	if err := s.repo.SaveSafetySettings(ctx, &SafetySettings{CampaignID: campaignID, AllowAnonymous: allowAnonymous}); err != nil {
+		return apperror.NewInternal(fmt.Errorf("saving safety settings: %w", err))
+	}
+	return nil
+}
+
+// isConsentTopic reports whether key is a current survey topic.
+func isConsentTopic(key string) bool {
+	for _, t := range ConsentTopics {
+		if t.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package sessions
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// safetyStore backs a mockSessionRepo with in-memory safety state.
+type safetyStore struct {
+	allowAnonymous bool
+	entries        []SafetyEntry
+	answers        []ConsentAnswer
+	notes          []*Notification
+}
+
+func (st *safetyStore) repo() *mockSessionRepo {
+	return &mockSessionRepo{
+		getSafetySettingsFn: func(_ context.Context, campaignID string) (*SafetySettings, error) {
+			return &SafetySettings{CampaignID: campaignID, AllowAnonymous: st.allowAnonymous}, nil
+		},
+		createSafetyEntryFn: func(_ context.Context, e *SafetyEntry) error {
+			st.entries = append(st.entries, *e)
+			return nil
+		},
+		getSafetyEntryFn: func(_ context.Context, campaignID, entryID string) (*SafetyEntry, error) {
+			for _, e := range st.entries {
+				if e.ID == entryID && e.CampaignID == campaignID {
+					return &e, nil
+				}
+			}
+			return (&mockSessionRepo{}).GetSafetyEntry(context.Background(), campaignID, entryID)
+		},
+		listSafetyEntriesFn:  func(context.Context, string) ([]SafetyEntry, error) { return st.entries, nil },
+		listConsentAnswersFn: func(context.Context, string) ([]ConsentAnswer, error) { return st.answers, nil },
+		replaceConsentAnswersFn: func(_ context.Context, _, userID string, answers []ConsentAnswer) error {
+			kept := st.answers[:0]
+			for _, a := range st.answers {
+				if a.UserID != userID {
+					kept = append(kept, a)
+				}
+			}
+			st.answers = append(kept, answers...)
+			return nil
+		},
+		createNotificationFn: func(_ context.Context, n *Notification) error {
+			st.notes = append(st.notes, n)
+			return nil
+		},
+	}
+}
+
+func TestAddSafetyEntry_Validation(t *testing.T) {
+	svc := NewSessionService((&safetyStore{allowAnonymous: true}).repo(), nil)
+	cases := []struct {
+		name string
+		req  AddSafetyEntryRequest
+	}{
+		{"bad kind", AddSafetyEntryRequest{Kind: "wall", Topic: "Spiders"}},
+		{"blank topic", AddSafetyEntryRequest{Kind: SafetyLine, Topic: "  "}},
+		{"long topic", AddSafetyEntryRequest{Kind: SafetyVeil, Topic: strings.Repeat("x", maxSafetyTopicLen+1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.AddSafetyEntry(context.Background(), "camp-1", "p-1", tc.req)
+			assertAppError(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestAddSafetyEntry_Anonymous(t *testing.T) {
+	st := &safetyStore{allowAnonymous: true}
+	svc := NewSessionService(st.repo(), nil)
+
+	e, err := svc.AddSafetyEntry(context.Background(), "camp-1", "p-1", AddSafetyEntryRequest{Kind: SafetyLine, Topic: " Spiders ", Anonymous: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.SubmittedBy != nil || e.Topic != "Spiders" {
+		t.Errorf("entry = %+v, want an anonymous trimmed entry", e)
+	}
+
+	st.allowAnonymous = false
+	_, err = svc.AddSafetyEntry(context.Background(), "camp-1", "p-1", AddSafetyEntryRequest{Kind: SafetyLine, Topic: "Rats", Anonymous: true})
+	assertAppError(t, err, http.StatusBadRequest)
+
+	e, err = svc.AddSafetyEntry(context.Background(), "camp-1", "p-1", AddSafetyEntryRequest{Kind: SafetyVeil, Topic: "Rats"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.SubmittedBy == nil || *e.SubmittedBy != "p-1" {
+		t.Errorf("named entry submitter = %v, want p-1", e.SubmittedBy)
+	}
+}
+
+func TestDeleteSafetyEntry_Permissions(t *testing.T) {
+	mine, other := "p-1", "p-2"
+	st := &safetyStore{entries: []SafetyEntry{
+		{ID: "e-mine", CampaignID: "camp-1", Kind: SafetyLine, Topic: "A", SubmittedBy: &mine},
+		{ID: "e-other", CampaignID: "camp-1", Kind: SafetyLine, Topic: "B", SubmittedBy: &other},
+		{ID: "e-anon", CampaignID: "camp-1", Kind: SafetyVeil, Topic: "C"},
+	}}
+	svc := NewSessionService(st.repo(), nil)
+	ctx := context.Background()
+
+	if err := svc.DeleteSafetyEntry(ctx, "camp-1", "e-mine", "p-1", false); err != nil {
+		t.Errorf("deleting own entry: %v", err)
+	}
+	assertAppError(t, svc.DeleteSafetyEntry(ctx, "camp-1", "e-other", "p-1", false), http.StatusForbidden)
+	assertAppError(t, svc.DeleteSafetyEntry(ctx, "camp-1", "e-anon", "p-1", false), http.StatusForbidden)
+	assertAppError(t, svc.DeleteSafetyEntry(ctx, "camp-2", "e-other", "owner-1", true), http.StatusNotFound)
+	if err := svc.DeleteSafetyEntry(ctx, "camp-1", "e-anon", "owner-1", true); err != nil {
+		t.Errorf("owner deleting anonymous entry: %v", err)
+	}
+}
+
+func TestGetSafetyView_OwnerOnlyDetail(t *testing.T) {
+	p1 := "p-1"
+	st := &safetyStore{
+		allowAnonymous: true,
+		entries: []SafetyEntry{
+			{ID: "e-1", CampaignID: "camp-1", Kind: SafetyLine, Topic: "A", SubmittedBy: &p1},
+			{ID: "e-2", CampaignID: "camp-1", Kind: SafetyVeil, Topic: "B"},
+		},
+		answers: []ConsentAnswer{
+			{UserID: "p-1", Topic: "gore", Rating: ConsentRed},
+			{UserID: "p-2", Topic: "gore", Rating: ConsentYellow},
+			{UserID: "p-2", Topic: "retired", Rating: ConsentRed},
+		},
+	}
+	svc := NewSessionService(st.repo(), nil)
+
+	player, err := svc.GetSafetyView(context.Background(), "camp-1", "p-1", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if player.Results != nil {
+		t.Error("players must not see survey totals")
+	}
+	if len(player.Lines) != 1 || len(player.Veils) != 1 {
+		t.Fatalf("lines/veils = %d/%d, want 1/1", len(player.Lines), len(player.Veils))
+	}
+	if !player.Lines[0].Mine || !player.Lines[0].CanDelete || player.Lines[0].Entry.SubmittedBy != nil {
+		t.Errorf("player's own line = %+v, want mine, deletable and unattributed", player.Lines[0])
+	}
+	if player.Veils[0].CanDelete {
+		t.Error("a player must not be able to delete an anonymous entry")
+	}
+	if player.MyAnswers["gore"] != ConsentRed || len(player.MyAnswers) != 1 {
+		t.Errorf("my answers = %v, want only gore=red", player.MyAnswers)
+	}
+
+	owner, err := svc.GetSafetyView(context.Background(), "camp-1", "owner-1", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owner.Lines[0].Entry.SubmittedBy == nil || !owner.Veils[0].CanDelete {
+		t.Errorf("owner view = %+v / %+v, want attribution and delete", owner.Lines[0], owner.Veils[0])
+	}
+	if len(owner.Results) != len(ConsentTopics) || owner.Responded != 2 {
+		t.Fatalf("results = %d topics, %d responded", len(owner.Results), owner.Responded)
+	}
+	for _, r := range owner.Results {
+		if r.Topic.Key == "gore" && (r.Red != 1 || r.Yellow != 1 || r.Green != 0) {
+			t.Errorf("gore tally = %+v", r)
+		}
+	}
+}
+
+func TestSaveConsentAnswers(t *testing.T) {
+	st := &safetyStore{answers: []ConsentAnswer{{UserID: "p-1", Topic: "gore", Rating: ConsentRed}}}
+	svc := NewSessionService(st.repo(), nil)
+	ctx := context.Background()
+
+	assertAppError(t, svc.SaveConsentAnswers(ctx, "camp-1", "p-1", map[string]string{"gore": "purple"}), http.StatusBadRequest)
+	assertAppError(t, svc.SaveConsentAnswers(ctx, "camp-1", "p-1", map[string]string{"made_up": ConsentRed}), http.StatusBadRequest)
+
+	if err := svc.SaveConsentAnswers(ctx, "camp-1", "p-1", map[string]string{"gore": "", "insects": ConsentGreen}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(st.answers) != 1 || st.answers[0].Topic != "insects" || st.answers[0].Rating != ConsentGreen {
+		t.Errorf("answers = %+v, want only insects=green", st.answers)
+	}
+}
+
+func TestNotifyXCard_LinksOwnSessionOnly(t *testing.T) {
+	st := &safetyStore{}
+	repo := st.repo()
+	repo.findByIDFn = func(_ context.Context, id string) (*Session, error) {
+		if id == "s-1" {
+			return &Session{ID: "s-1", CampaignID: "camp-1", Name: "The Heist"}, nil
+		}
+		return &Session{ID: id, CampaignID: "camp-2", Name: "Elsewhere"}, nil
+	}
+	svc := NewSessionService(repo, nil)
+
+	if err := svc.NotifyXCard(context.Background(), "camp-1", "s-1", []string{"owner-1", ""}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(st.notes) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(st.notes))
+	}
+	n := st.notes[0]
+	if n.UserID != "owner-1" || n.Type != NotifXCard || *n.Link != "/campaigns/camp-1/sessions/s-1" {
+		t.Errorf("notification = %+v", n)
+	}
+	if !strings.Contains(*n.Payload, "The Heist") {
+		t.Errorf("payload = %s, want the session name", *n.Payload)
+	}
+
+	// A session from another campaign is not linked.
+	if err := svc.NotifyXCard(context.Background(), "camp-1", "s-9", []string{"owner-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := *st.notes[1].Link; got != "/campaigns/camp-1/safety" {
+		t.Errorf("foreign session link = %q, want the safety page", got)
+	}
+}
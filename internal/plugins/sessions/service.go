@@ -49,6 +49,9 @@ type SessionService interface {
 	LinkEntity(ctx context.Context, sessionID, entityID, role, campaignID string) error
 	UnlinkEntity(ctx context.Context, sessionID, entityID string) error
 	ListSessionEntities(ctx context.Context, sessionID string) ([]SessionEntity, error)
+	// ListEntitySessions lists the sessions an entity is linked to (the
+	// reverse of ListSessionEntities).
+	ListEntitySessions(ctx context.Context, campaignID, entityID string) ([]EntitySession, error)
 
 	// Availability scheduler (C-SCHED-P1). See availability_service.go.
 	GetMyAvailability(ctx context.Context, campaignID, userID string) (*MyAvailabilityResponse, error)
@@ -76,6 +79,34 @@ type SessionService interface {
 	// (C-SCHED-P3).
 	ConfirmProposalWinner(ctx context.Context, campaignID, proposalID, optionID, confirmedBy, confirmerTZ string) (*Session, error)
 
+	// Member polls. See polls_service.go. ClosePoll (Scribe+) and the
+	// deadline paths pick the winner; a winning date option becomes a
+	// real-life calendar event through the PollEventCreator.
+	CreatePoll(ctx context.Context, campaignID, createdBy string, req CreatePollRequest) (*Poll, error)
+	GetPollView(ctx context.Context, campaignID, pollID, viewerID, viewerTZ string) (*PollView, error)
+	ListPollSummaries(ctx context.Context, campaignID, viewerID string) ([]PollSummary, error)
+	CastPollVote(ctx context.Context, campaignID, pollID, userID string, optionIDs []string) error
+	ClosePoll(ctx context.Context, campaignID, pollID string) error
+	CloseDuePolls(ctx context.Context, now time.Time) (int, error)
+	SetPollEventCreator(c PollEventCreator)
+
+	// Safety tools. See safety_service.go. Any member adds lines & veils and
+	// answers the consent survey; only the owner sees submitters and the
+	// survey totals, and only the owner changes the settings.
+	GetSafetyView(ctx context.Context, campaignID, viewerID string, isOwner bool) (*SafetyView, error)
+	AddSafetyEntry(ctx context.Context, campaignID, userID string, req AddSafetyEntryRequest) (*SafetyEntry, error)
+	DeleteSafetyEntry(ctx context.Context, campaignID, entryID, userID string, isOwner bool) error
+	SaveConsentAnswers(ctx context.Context, campaignID, userID string, answers map[string]string) error
+	UpdateSafetySettings(ctx context.Context, campaignID string, allowAnonymous bool) error
+
+	// Session transcripts. See transcript_service.go. Scribe+ only, like
+	// session notes; the mention index is filtered to the viewer's pages.
+	GetTranscriptView(ctx context.Context, campaignID, sessionID string, role int, viewerID string) (*TranscriptView, error)
+	SaveTranscript(ctx context.Context, campaignID, sessionID, userID string, role int, fileName, content string) (*Transcript, error)
+	RescanTranscript(ctx context.Context, campaignID, sessionID, userID string, role int) error
+	DeleteTranscript(ctx context.Context, sessionID string) error
+	SetEntityNameLister(l EntityNameLister)
+
 	// Scheduler-scoped notifications (C-SCHED-P2). Writes are driven by the
 	// handler (which enumerates members / resolves names); the service owns the
 	// payload/link/message construction. See notifications_service.go.
@@ -84,6 +115,14 @@ type SessionService interface {
 	// NotifyProposalConfirmed tells everyone who responded that the winning slot
 	// was picked, linking to the new session (C-SCHED-P3, reuses the P2 store).
 	NotifyProposalConfirmed(ctx context.Context, campaignID, proposalID, sessionID string) error
+	NotifyPollCreated(ctx context.Context, campaignID, pollID, question string, recipientIDs []string) error
+	// NotifyXCard and NotifySafetyEntry alert the owners without naming the
+	// member behind the tap or entry.
+	NotifyXCard(ctx context.Context, campaignID, sessionID string, recipientIDs []string) error
+	NotifySafetyEntry(ctx context.Context, campaignID string, e *SafetyEntry, recipientIDs []string) error
+	// NotifyUser writes a single notification on behalf of another plugin;
+	// the store is generic, the scheduler was just its first writer.
+	NotifyUser(ctx context.Context, userID, campaignID, kind, message, link string) error
 	ListMyNotifications(ctx context.Context, userID string, limit int) ([]Notification, error)
 	CountMyUnreadNotifications(ctx context.Context, userID string) (int, error)
 	MarkNotificationRead(ctx context.Context, userID, notificationID string) error
@@ -94,6 +133,8 @@ type SessionService interface {
 type sessionService struct {
 	repo           SessionRepository
 	entityChecker  EntityCampaignChecker
+	pollEvents     PollEventCreator
+	entityNames    EntityNameLister
 }
 
 // NewSessionService creates a new session service. The EntityCampaignChecker
@@ -370,6 +411,11 @@ func (s *sessionService) ListSessionEntities(ctx context.Context, sessionID stri
 	return s.repo.ListSessionEntities(ctx, sessionID)
 }
 
+// ListEntitySessions returns the campaign's sessions an entity is linked to.
+func (s *sessionService) ListEntitySessions(ctx context.Context, campaignID, entityID string) ([]EntitySession, error) {
+	return s.repo.ListEntitySessions(ctx, campaignID, entityID)
+}
+
 // SearchSessions returns sessions matching a query for the quick search system.
 // Results are formatted to match the entity search JSON format.
 func (s *sessionService) SearchSessions(ctx context.Context, campaignID, query string) ([]map[string]string, error) {
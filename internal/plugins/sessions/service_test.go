@@ -62,6 +62,31 @@ type mockSessionRepo struct {
 	countUnreadNotificationsFn func(ctx context.Context, userID string) (int, error)
 	markNotificationReadFn    func(ctx context.Context, userID, notificationID string) error
 	markAllNotificationsReadFn func(ctx context.Context, userID string) error
+	// Polls.
+	createPollFn           func(ctx context.Context, p *Poll, options []PollOption) error
+	getPollFn              func(ctx context.Context, campaignID, pollID string) (*Poll, []PollOption, error)
+	listPollsFn            func(ctx context.Context, campaignID string) ([]Poll, error)
+	listDuePollsFn         func(ctx context.Context, now time.Time, limit int) ([]Poll, error)
+	listPollOptionsFn      func(ctx context.Context, pollID string) ([]PollOption, error)
+	listPollVotesFn        func(ctx context.Context, pollID string) ([]PollVote, error)
+	replacePollVotesFn     func(ctx context.Context, pollID, userID string, optionIDs []string) error
+	closePollFn            func(ctx context.Context, pollID string, winnerOptionID *string) error
+	setPollCalendarEventFn func(ctx context.Context, pollID, eventID string) error
+	// Safety tools.
+	getSafetySettingsFn     func(ctx context.Context, campaignID string) (*SafetySettings, error)
+	saveSafetySettingsFn    func(ctx context.Context, s *SafetySettings) error
+	createSafetyEntryFn     func(ctx context.Context, e *SafetyEntry) error
+	getSafetyEntryFn        func(ctx context.Context, campaignID, entryID string) (*SafetyEntry, error)
+	listSafetyEntriesFn     func(ctx context.Context, campaignID string) ([]SafetyEntry, error)
+	deleteSafetyEntryFn     func(ctx context.Context, campaignID, entryID string) error
+	replaceConsentAnswersFn func(ctx context.Context, campaignID, userID string, answers []ConsentAnswer) error
+	listConsentAnswersFn    func(ctx context.Context, campaignID string) ([]ConsentAnswer, error)
+	// Session transcripts.
+	getTranscriptFn             func(ctx context.Context, sessionID string) (*Transcript, error)
+	saveTranscriptFn            func(ctx context.Context, t *Transcript, mentions []TranscriptMention) error
+	replaceTranscriptMentionsFn func(ctx context.Context, sessionID string, scannedAt time.Time, mentions []TranscriptMention) error
+	listTranscriptMentionsFn    func(ctx context.Context, sessionID string) ([]TranscriptMention, error)
+	deleteTranscriptFn          func(ctx context.Context, sessionID string) error
 }
 
 func (m *mockSessionRepo) Create(ctx context.Context, campaignID string, s *Session) error {
@@ -169,6 +194,10 @@ func (m *mockSessionRepo) ListSessionEntities(ctx context.Context, sessionID str
 	return nil, nil
 }
 
+func (m *mockSessionRepo) ListEntitySessions(ctx context.Context, campaignID, entityID string) ([]EntitySession, error) {
+	return nil, nil
+}
+
 func (m *mockSessionRepo) CreateRSVPToken(ctx context.Context, token *RSVPToken) error {
 	if m.createRSVPTokenFn != nil {
 		return m.createRSVPTokenFn(ctx, token)
@@ -376,6 +405,164 @@ func (m *mockSessionRepo) MarkAllNotificationsRead(ctx context.Context, userID s
 	return nil
 }
 
+func (m *mockSessionRepo) PurgeNotificationsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSessionRepo) CreatePoll(ctx context.Context, p *Poll, options []PollOption) error {
+	if m.createPollFn != nil {
+		return m.createPollFn(ctx, p, options)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) GetPoll(ctx context.Context, campaignID, pollID string) (*Poll, []PollOption, error) {
+	if m.getPollFn != nil {
+		return m.getPollFn(ctx, campaignID, pollID)
+	}
+	return nil, nil, apperror.NewNotFound("poll not found")
+}
+
+func (m *mockSessionRepo) ListPolls(ctx context.Context, campaignID string) ([]Poll, error) {
+	if m.listPollsFn != nil {
+		return m.listPollsFn(ctx, campaignID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) ListDuePolls(ctx context.Context, now time.Time, limit int) ([]Poll, error) {
+	if m.listDuePollsFn != nil {
+		return m.listDuePollsFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) ListPollOptions(ctx context.Context, pollID string) ([]PollOption, error) {
+	if m.listPollOptionsFn != nil {
+		return m.listPollOptionsFn(ctx, pollID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) ListPollVotes(ctx context.Context, pollID string) ([]PollVote, error) {
+	if m.listPollVotesFn != nil {
+		return m.listPollVotesFn(ctx, pollID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) ReplacePollVotes(ctx context.Context, pollID, userID string, optionIDs []string) error {
+	if m.replacePollVotesFn != nil {
+		return m.replacePollVotesFn(ctx, pollID, userID, optionIDs)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ClosePoll(ctx context.Context, pollID string, winnerOptionID *string) error {
+	if m.closePollFn != nil {
+		return m.closePollFn(ctx, pollID, winnerOptionID)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) SetPollCalendarEvent(ctx context.Context, pollID, eventID string) error {
+	if m.setPollCalendarEventFn != nil {
+		return m.setPollCalendarEventFn(ctx, pollID, eventID)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) GetSafetySettings(ctx context.Context, campaignID string) (*SafetySettings, error) {
+	if m.getSafetySettingsFn != nil {
+		return m.getSafetySettingsFn(ctx, campaignID)
+	}
+	return &SafetySettings{CampaignID: campaignID, AllowAnonymous: true}, nil
+}
+
+func (m *mockSessionRepo) SaveSafetySettings(ctx context.Context, s *SafetySettings) error {
+	if m.saveSafetySettingsFn != nil {
+		return m.saveSafetySettingsFn(ctx, s)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) CreateSafetyEntry(ctx context.Context, e *SafetyEntry) error {
+	if m.createSafetyEntryFn != nil {
+		return m.createSafetyEntryFn(ctx, e)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) GetSafetyEntry(ctx context.Context, campaignID, entryID string) (*SafetyEntry, error) {
+	if m.getSafetyEntryFn != nil {
+		return m.getSafetyEntryFn(ctx, campaignID, entryID)
+	}
+	return nil, apperror.NewNotFound("safety entry not found")
+}
+
+func (m *mockSessionRepo) ListSafetyEntries(ctx context.Context, campaignID string) ([]SafetyEntry, error) {
+	if m.listSafetyEntriesFn != nil {
+		return m.listSafetyEntriesFn(ctx, campaignID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) DeleteSafetyEntry(ctx context.Context, campaignID, entryID string) error {
+	if m.deleteSafetyEntryFn != nil {
+		return m.deleteSafetyEntryFn(ctx, campaignID, entryID)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ReplaceConsentAnswers(ctx context.Context, campaignID, userID string, answers []ConsentAnswer) error {
+	if m.replaceConsentAnswersFn != nil {
+		return m.replaceConsentAnswersFn(ctx, campaignID, userID, answers)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ListConsentAnswers(ctx context.Context, campaignID string) ([]ConsentAnswer, error) {
+	if m.listConsentAnswersFn != nil {
+		return m.listConsentAnswersFn(ctx, campaignID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) GetTranscript(ctx context.Context, sessionID string) (*Transcript, error) {
+	if m.getTranscriptFn != nil {
+		return m.getTranscriptFn(ctx, sessionID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) SaveTranscript(ctx context.Context, t *Transcript, mentions []TranscriptMention) error {
+	if m.saveTranscriptFn != nil {
+		return m.saveTranscriptFn(ctx, t, mentions)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ReplaceTranscriptMentions(ctx context.Context, sessionID string, scannedAt time.Time, mentions []TranscriptMention) error {
+	if m.replaceTranscriptMentionsFn != nil {
+		return m.replaceTranscriptMentionsFn(ctx, sessionID, scannedAt, mentions)
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) ListTranscriptMentions(ctx context.Context, sessionID string) ([]TranscriptMention, error) {
+	if m.listTranscriptMentionsFn != nil {
+		return m.listTranscriptMentionsFn(ctx, sessionID)
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) DeleteTranscript(ctx context.Context, sessionID string) error {
+	if m.deleteTranscriptFn != nil {
+		return m.deleteTranscriptFn(ctx, sessionID)
+	}
+	return nil
+}
+
 // --- Mock Entity Campaign Checker ---
 
 // mockEntityChecker implements EntityCampaignChecker for testing entity linking.
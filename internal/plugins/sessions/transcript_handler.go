@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// Session transcript HTTP surface. Every route is Scribe+ and answers with
+// the refreshed panel, which the session page lazy-loads.
+
+// ShowTranscript renders the transcript panel.
+// GET /campaigns/:id/sessions/:sid/transcript
+func (h *Handler) ShowTranscript(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	sessionID := c.Param("sid")
+	if _, err := h.requireSessionInCampaign(c, sessionID, cc.Campaign.ID); err != nil {
+		return err
+	}
+	return h.renderTranscript(c, cc, sessionID)
+}
+
+// UploadTranscriptAPI stores a transcript from a "file" upload or a pasted
+// "content" field, replacing any earlier one.
+// POST /campaigns/:id/sessions/:sid/transcript
+func (h *Handler) UploadTranscriptAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	sessionID := c.Param("sid")
+	if _, err := h.requireSessionInCampaign(c, sessionID, cc.Campaign.ID); err != nil {
+		return err
+	}
+
+	fileName, content := "", c.FormValue("content")
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read transcript"})
+		}
+		defer f.Close()
+		// Read one byte past the cap so the service can reject it.
+		data, err := io.ReadAll(io.LimitReader(f, maxTranscriptBytes+1))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read transcript"})
+		}
+		fileName, content = fh.Filename, string(data)
+	}
+
+	if _, err := h.svc.SaveTranscript(c.Request().Context(), cc.Campaign.ID, sessionID,
+		auth.GetUserID(c), int(cc.MemberRole), fileName, content); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderTranscriptResult(c, cc, sessionID)
+}
+
+// RescanTranscriptAPI rebuilds the mention index.
+// POST /campaigns/:id/sessions/:sid/transcript/rescan
+func (h *Handler) RescanTranscriptAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	sessionID := c.Param("sid")
+	if _, err := h.requireSessionInCampaign(c, sessionID, cc.Campaign.ID); err != nil {
+		return err
+	}
+	if err := h.svc.RescanTranscript(c.Request().Context(), cc.Campaign.ID, sessionID,
+		auth.GetUserID(c), int(cc.MemberRole)); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderTranscriptResult(c, cc, sessionID)
+}
+
+// DeleteTranscriptAPI removes the transcript and its index.
+// DELETE /campaigns/:id/sessions/:sid/transcript
+func (h *Handler) DeleteTranscriptAPI(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	sessionID := c.Param("sid")
+	if _, err := h.requireSessionInCampaign(c, sessionID, cc.Campaign.ID); err != nil {
+		return err
+	}
+	if err := h.svc.DeleteTranscript(c.Request().Context(), sessionID); err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return h.renderTranscriptResult(c, cc, sessionID)
+}
+
+// renderTranscriptResult answers a transcript write: the refreshed panel
+// for HTMX, a status for JSON.
+func (h *Handler) renderTranscriptResult(c echo.Context, cc *campaigns.CampaignContext, sessionID string) error {
+	if !middleware.IsHTMX(c) {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+	return h.renderTranscript(c, cc, sessionID)
+}
+
+// renderTranscript renders the panel for the viewer.
+func (h *Handler) renderTranscript(c echo.Context, cc *campaigns.CampaignContext, sessionID string) error {
+	view, err := h.svc.GetTranscriptView(c.Request().Context(), cc.Campaign.ID, sessionID,
+		int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil {
+		return c.JSON(apperror.SafeCode(err), map[string]string{"error": apperror.SafeMessage(err)})
+	}
+	return middleware.Render(c, http.StatusOK, TranscriptPanel(cc, sessionID, view, middleware.GetCSRFToken(c)))
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import "time"
+
+// This file holds the session transcript types. A Scribe+ uploads a
+// speech-to-text transcript (plain text or WebVTT) to a session; it is
+// scanned for page names and aliases to build an index of which pages came
+// up in which cue, and when. Transcripts are GM material like session
+// notes, so neither the text nor the index is shown to players.
+
+// Transcript formats.
+const (
+	TranscriptText = "text"
+	TranscriptVTT  = "vtt"
+)
+
+const (
+	// maxTranscriptBytes caps an uploaded transcript. Several hours of speech
+	// is a few hundred KB; the cap stays under the global 2 MB body limit.
+	maxTranscriptBytes = 1 << 20
+
+	// maxTranscriptMentions caps the index rows one transcript may produce.
+	maxTranscriptMentions = 5000
+
+	// maxTranscriptSnippet caps the cue text kept with each mention.
+	maxTranscriptSnippet = 240
+)
+
+// Transcript is a session's uploaded transcript.
+type Transcript struct {
+	SessionID  string
+	CampaignID string
+	FileName   string
+	Format     string // text | vtt
+	Content    string
+	UploadedBy *string
+	UploadedAt time.Time
+	ScannedAt  time.Time
+}
+
+// TranscriptCue is one timed caption (VTT) or line (text) of a transcript.
+// StartMS is nil when the cue has no timing.
+type TranscriptCue struct {
+	Number  int // 1-based
+	StartMS *int
+	Text    string
+}
+
+// TranscriptMention is one page named in one cue.
+type TranscriptMention struct {
+	SessionID string
+	EntityID  string
+	Cue       int
+	StartMS   *int
+	Matched   string // the name or alias as it appears in the cue
+	Snippet   string
+}
+
+// TranscriptName is a page name or alias to look for. Supplied by the
+// entities plugin through EntityNameLister.
+type TranscriptName struct {
+	EntityID string
+	Name     string
+	Slug     string
+	IsAlias  bool
+}
+
+// --- View types ---
+
+// TranscriptView is the transcript panel on the session page.
+type TranscriptView struct {
+	Transcript *Transcript // nil when none was uploaded
+	CueCount   int
+	Index      []TranscriptIndexEntry
+}
+
+// TranscriptIndexEntry is one page's mentions, in cue order. Entries are
+// sorted by page name.
+type TranscriptIndexEntry struct {
+	EntityID   string
+	EntityName string
+	EntitySlug string
+	Mentions   []TranscriptMention
+}
@@ -0,0 +1,149 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Transcript persistence on the existing sessionRepository. The transcript
+// and its mention index live in their own tables and are replaced together.
+
+// GetTranscript returns a session's transcript, or nil when none was
+// uploaded.
+func (r *sessionRepository) GetTranscript(ctx context.Context, sessionID string) (*Transcript, error) {
+	var t Transcript
+	var uploadedBy sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT session_id, campaign_id, file_name, format, content, uploaded_by, uploaded_at, scanned_at
+		 FROM session_transcripts WHERE session_id = ?`, sessionID,
+	).Scan(&t.SessionID, &t.CampaignID, &t.FileName, &t.Format, &t.Content, &uploadedBy, &t.UploadedAt, &t.ScannedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting transcript: %w", err)
+	}
+	if uploadedBy.Valid {
+		t.UploadedBy = &uploadedBy.String
+	}
+	return &t, nil
+}
+
+// SaveTranscript upserts a transcript and replaces its mention index
+// atomically.
+func (r *sessionRepository) SaveTranscript(ctx context.Context, t *Transcript, mentions []TranscriptMention) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transcript tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO session_transcripts (session_id, campaign_id, file_name, format, content, uploaded_by, uploaded_at, scanned_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE file_name = VALUES(file_name), format = VALUES(format), content = VALUES(content),
+		     uploaded_by = VALUES(uploaded_by), uploaded_at = VALUES(uploaded_at), scanned_at = VALUES(scanned_at)`,
+		t.SessionID, t.CampaignID, t.FileName, t.Format, t.Content, t.UploadedBy, t.UploadedAt, t.ScannedAt); err != nil {
+		return fmt.Errorf("saving transcript: %w", err)
+	}
+	if err := replaceTranscriptMentions(ctx, tx, t.SessionID, mentions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transcript tx: %w", err)
+	}
+	return nil
+}
+
+// ReplaceTranscriptMentions rebuilds the mention index after a rescan.
+func (r *sessionRepository) ReplaceTranscriptMentions(ctx context.Context, sessionID string, scannedAt time.Time, mentions []TranscriptMention) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transcript tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE session_transcripts SET scanned_at = ? WHERE session_id = ?`, scannedAt, sessionID); err != nil {
+		return fmt.Errorf("stamping transcript scan: %w", err)
+	}
+	if err := replaceTranscriptMentions(ctx, tx, sessionID, mentions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transcript tx: %w", err)
+	}
+	return nil
+}
+
+// replaceTranscriptMentions swaps a session's mention rows inside tx.
+func replaceTranscriptMentions(ctx context.Context, tx *sql.Tx, sessionID string, mentions []TranscriptMention) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM session_transcript_mentions WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("clearing transcript mentions: %w", err)
+	}
+	const ins = `INSERT INTO session_transcript_mentions (session_id, entity_id, cue, start_ms, matched, snippet)
+	             VALUES (?, ?, ?, ?, ?, ?)`
+	for _, m := range mentions {
+		if _, err := tx.ExecContext(ctx, ins,
+			sessionID, m.EntityID, m.Cue, m.StartMS, m.Matched, m.Snippet); err != nil {
+			return fmt.Errorf("inserting transcript mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListTranscriptMentions returns a session's mention index in cue order.
+func (r *sessionRepository) ListTranscriptMentions(ctx context.Context, sessionID string) ([]TranscriptMention, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT session_id, entity_id, cue, start_ms, matched, snippet
+		 FROM session_transcript_mentions WHERE session_id = ?
+		 ORDER BY cue, entity_id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing transcript mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TranscriptMention
+	for rows.Next() {
+		var m TranscriptMention
+		var startMS sql.NullInt64
+		if err := rows.Scan(&m.SessionID, &m.EntityID, &m.Cue, &startMS, &m.Matched, &m.Snippet); err != nil {
+			return nil, fmt.Errorf("scanning transcript mention: %w", err)
+		}
+		if startMS.Valid {
+			v := int(startMS.Int64)
+			m.StartMS = &v
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// DeleteTranscript removes a session's transcript and its mention index.
+func (r *sessionRepository) DeleteTranscript(ctx context.Context, sessionID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transcript tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM session_transcript_mentions WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("deleting transcript mentions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM session_transcripts WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("deleting transcript: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transcript tx: %w", err)
+	}
+	return nil
+}
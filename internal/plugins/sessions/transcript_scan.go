@@ -0,0 +1,287 @@
+package sessions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// transcript_scan.go — splits a transcript into cues and finds page names in
+// them. WebVTT (and SRT, which shares its timing lines) becomes one cue per
+// caption with its start time; plain text becomes one cue per line, timed
+// when the line starts with a "[hh:mm:ss]" stamp as most speech-to-text
+// tools write it.
+
+// detectTranscriptFormat reports whether content is a timed caption file.
+// SRT files are stored as VTT since only the timing lines are read.
+func detectTranscriptFormat(fileName, content string) string {
+	lower := strings.ToLower(fileName)
+	if strings.HasSuffix(lower, ".vtt") || strings.HasSuffix(lower, ".srt") ||
+		strings.HasPrefix(content, "WEBVTT") {
+		return TranscriptVTT
+	}
+	for _, line := range strings.SplitN(content, "\n", 20) {
+		if strings.Contains(line, "-->") {
+			return TranscriptVTT
+		}
+	}
+	return TranscriptText
+}
+
+// parseTranscript splits normalised content into numbered cues. Empty
+// cues are dropped.
+func parseTranscript(format, content string) []TranscriptCue {
+	if format == TranscriptVTT {
+		return parseVTTCues(content)
+	}
+	var cues []TranscriptCue
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		var start *int
+		if strings.HasPrefix(line, "[") {
+			if end := strings.IndexByte(line, ']'); end > 0 {
+				if ms, ok := parseCueTime(line[1:end]); ok {
+					start = &ms
+					line = strings.TrimSpace(line[end+1:])
+				}
+			}
+		}
+		if line == "" {
+			continue
+		}
+		cues = append(cues, TranscriptCue{Number: len(cues) + 1, StartMS: start, Text: line})
+	}
+	return cues
+}
+
+// parseVTTCues reads caption blocks: an optional identifier line, a
+// "start --> end" timing line, then the caption text. Header, NOTE, STYLE
+// and REGION blocks have no timing line and are skipped.
+func parseVTTCues(content string) []TranscriptCue {
+	var cues []TranscriptCue
+	for _, block := range strings.Split(content, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 {
+			continue
+		}
+		var start *int
+		if ms, ok := parseCueTime(strings.TrimSpace(strings.SplitN(lines[timing], "-->", 2)[0])); ok {
+			start = &ms
+		}
+		var parts []string
+		for _, line := range lines[timing+1:] {
+			if line = strings.TrimSpace(stripCueTags(line)); line != "" {
+				parts = append(parts, line)
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		cues = append(cues, TranscriptCue{Number: len(cues) + 1, StartMS: start, Text: strings.Join(parts, " ")})
+	}
+	return cues
+}
+
+// stripCueTags removes VTT markup such as <v Speaker>, <i> and inline
+// timestamps, keeping the speaker's name as a "Name:" prefix.
+func stripCueTags(line string) string {
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(line, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(line[open:], '>')
+		if end < 0 {
+			break
+		}
+		b.WriteString(line[:open])
+		if tag := line[open+1 : open+end]; strings.HasPrefix(tag, "v ") {
+			if speaker := strings.TrimSpace(tag[2:]); speaker != "" {
+				b.WriteString(speaker + ": ")
+			}
+		}
+		line = line[open+end+1:]
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// parseCueTime reads "hh:mm:ss.mmm", "mm:ss.mmm" or either without the
+// fraction; SRT's comma separator is accepted too. Returns milliseconds.
+func parseCueTime(s string) (int, bool) {
+	s = strings.Replace(s, ",", ".", 1)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		s = s[:i] // VTT cue settings follow the end time, not the start
+	}
+	frac := 0
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		digits := s[dot+1:]
+		if digits == "" || len(digits) > 3 {
+			return 0, false
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return 0, false
+		}
+		for i := len(digits); i < 3; i++ {
+			n *= 10
+		}
+		frac, s = n, s[:dot]
+	}
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, false
+	}
+	total := 0
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, false
+		}
+		total = total*60 + n
+	}
+	return total*1000 + frac, true
+}
+
+// transcriptToken is one word of a cue, lower-cased, with its byte span in
+// the cue text.
+type transcriptToken struct {
+	word       string
+	start, end int
+}
+
+// tokenizeTranscript splits text into runs of letters and digits.
+// "Strahd's" yields "strahd" and "s", so possessives still match.
+func tokenizeTranscript(text string) []transcriptToken {
+	var tokens []transcriptToken
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, transcriptToken{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, transcriptToken{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// transcriptMatcher finds page names in cues on word boundaries,
+// case-insensitively. Where names overlap the longest wins, so "Lady
+// Wakanga" is not also counted as "Wakanga".
+type transcriptMatcher struct {
+	// byFirst maps a name's first word to the names starting with it,
+	// longest first.
+	byFirst map[string][]transcriptPattern
+}
+
+type transcriptPattern struct {
+	entityID string
+	words    []string
+}
+
+func newTranscriptMatcher(names []TranscriptName) *transcriptMatcher {
+	m := &transcriptMatcher{byFirst: make(map[string][]transcriptPattern)}
+	for _, n := range names {
+		tokens := tokenizeTranscript(n.Name)
+		if len(tokens) == 0 {
+			continue
+		}
+		words := make([]string, len(tokens))
+		for i, t := range tokens {
+			words[i] = t.word
+		}
+		m.byFirst[words[0]] = append(m.byFirst[words[0]], transcriptPattern{entityID: n.EntityID, words: words})
+	}
+	for _, list := range m.byFirst {
+		sort.SliceStable(list, func(i, j int) bool { return len(list[i].words) > len(list[j].words) })
+	}
+	return m
+}
+
+// scan returns one mention per page per cue, in cue order, stopping at
+// maxTranscriptMentions.
+func (m *transcriptMatcher) scan(sessionID string, cues []TranscriptCue) []TranscriptMention {
+	var out []TranscriptMention
+	for _, cue := range cues {
+		tokens := tokenizeTranscript(cue.Text)
+		seen := make(map[string]bool)
+		for i := 0; i < len(tokens); {
+			p, ok := m.matchAt(tokens, i)
+			if !ok {
+				i++
+				continue
+			}
+			if !seen[p.entityID] {
+				seen[p.entityID] = true
+				out = append(out, TranscriptMention{
+					SessionID: sessionID,
+					EntityID:  p.entityID,
+					Cue:       cue.Number,
+					StartMS:   cue.StartMS,
+					Matched:   truncateRunes(cue.Text[tokens[i].start:tokens[i+len(p.words)-1].end], 200),
+					Snippet:   truncateRunes(cue.Text, maxTranscriptSnippet),
+				})
+				if len(out) >= maxTranscriptMentions {
+					return out
+				}
+			}
+			i += len(p.words)
+		}
+	}
+	return out
+}
+
+// matchAt returns the longest name starting at tokens[i].
+func (m *transcriptMatcher) matchAt(tokens []transcriptToken, i int) (transcriptPattern, bool) {
+	for _, p := range m.byFirst[tokens[i].word] {
+		if i+len(p.words) > len(tokens) {
+			continue
+		}
+		match := true
+		for k, w := range p.words[1:] {
+			if tokens[i+1+k].word != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return p, true
+		}
+	}
+	return transcriptPattern{}, false
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "…".
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
+
+// formatCueTime renders milliseconds as "h:mm:ss", or "m:ss" under an hour.
+func formatCueTime(ms int) string {
+	sec := ms / 1000
+	if sec >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", sec/3600, sec/60%60, sec%60)
+	}
+	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
+}
@@ -0,0 +1,182 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// transcript_service.go — session transcripts. A Scribe+ uploads the text a
+// speech-to-text tool produced; it is scanned for page names and aliases
+// the uploader can see, and the hits become a per-page index of the cues
+// (and times) each page came up in. The index is filtered again per viewer
+// so a page hidden from one Scribe never shows up in their copy of it.
+
+// EntityNameLister lists the page names and aliases a viewer can see, for
+// the transcript scan. Implemented at the app boundary so sessions never
+// imports the entities plugin.
+type EntityNameLister interface {
+	ListTranscriptNames(ctx context.Context, campaignID string, role int, userID string) ([]TranscriptName, error)
+}
+
+// SetEntityNameLister wires the page name lookup; nil disables scanning.
+func (s *sessionService) SetEntityNameLister(l EntityNameLister) {
+	s.entityNames = l
+}
+
+// GetTranscriptView loads a session's transcript and the mention index,
+// keeping only pages the viewer can see.
+func (s *sessionService) GetTranscriptView(ctx context.Context, campaignID, sessionID string, role int, viewerID string) (*TranscriptView, error) {
+	t, err := s.repo.GetTranscript(ctx, sessionID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading transcript: %w", err))
+	}
+	view := &TranscriptView{Transcript: t}
+	if t == nil {
+		return view, nil
+	}
+	view.CueCount = len(parseTranscript(t.Format, t.Content))
+
+	mentions, err := s.repo.ListTranscriptMentions(ctx, sessionID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("loading transcript mentions: %w", err))
+	}
+	names, err := s.transcriptNames(ctx, campaignID, role, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A page's own name beats its aliases for the index heading.
+	pages := make(map[string]*TranscriptIndexEntry)
+	for _, n := range names {
+		if e, ok := pages[n.EntityID]; ok {
+			if !n.IsAlias {
+				e.EntityName = n.Name
+			}
+			continue
+		}
+		pages[n.EntityID] = &TranscriptIndexEntry{EntityID: n.EntityID, EntityName: n.Name, EntitySlug: n.Slug}
+	}
+	for _, m := range mentions {
+		if e, ok := pages[m.EntityID]; ok {
+			e.Mentions = append(e.Mentions, m)
+		}
+	}
+	for _, e := range pages {
+		if len(e.Mentions) > 0 {
+			view.Index = append(view.Index, *e)
+		}
+	}
+	sort.Slice(view.Index, func(i, j int) bool {
+		return strings.ToLower(view.Index[i].EntityName) < strings.ToLower(view.Index[j].EntityName)
+	})
+	return view, nil
+}
+
+// SaveTranscript validates and stores a session's transcript, replacing any
+// earlier one, and builds its mention index from the pages the uploader can
+// see.
+func (s *sessionService) SaveTranscript(ctx context.Context, campaignID, sessionID, userID string, role int, fileName, content string) (*Transcript, error) {
+	if len(content) > maxTranscriptBytes {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("transcript is too large (max %d KB)", maxTranscriptBytes>>10))
+	}
+	content = normalizeTranscript(content)
+	if content == "" {
+		return nil, apperror.NewBadRequest("transcript is empty")
+	}
+	fileName = strings.TrimSpace(path.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "." || fileName == "/" {
+		fileName = ""
+	}
+	fileName = truncateRunes(fileName, 255)
+
+	format := detectTranscriptFormat(fileName, content)
+	cues := parseTranscript(format, content)
+	if len(cues) == 0 {
+		return nil, apperror.NewBadRequest("transcript has no text")
+	}
+	mentions, err := s.scanTranscript(ctx, campaignID, sessionID, role, userID, cues)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	t := &Transcript{
+		SessionID:  sessionID,
+		CampaignID: campaignID,
+		FileName:   fileName,
+		Format:     format,
+		Content:    content,
+		UploadedBy: &userID,
+		UploadedAt: now,
+		ScannedAt:  now,
+	}
+	if err := s.repo.SaveTranscript(ctx, t, mentions); err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("saving transcript: %w", err))
+	}
+	return t, nil
+}
+
+// RescanTranscript rebuilds the mention index, picking up pages created or
+// renamed since the upload.
+func (s *sessionService) RescanTranscript(ctx context.Context, campaignID, sessionID, userID string, role int) error {
+	t, err := s.repo.GetTranscript(ctx, sessionID)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("loading transcript: %w", err))
+	}
+	if t == nil {
+		return apperror.NewNotFound("no transcript uploaded for this session")
+	}
+	mentions, err := s.scanTranscript(ctx, campaignID, sessionID, role, userID, parseTranscript(t.Format, t.Content))
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceTranscriptMentions(ctx, sessionID, time.Now().UTC(), mentions); err != nil {
+		return apperror.NewInternal(fmt.Errorf("saving transcript mentions: %w", err))
+	}
+	return nil
+}
+
+// DeleteTranscript removes a session's transcript and its index.
+func (s *sessionService) DeleteTranscript(ctx context.Context, sessionID string) error {
+	if err := s.repo.DeleteTranscript(ctx, sessionID); err != nil {
+		return apperror.NewInternal(fmt.Errorf("deleting transcript: %w", err))
+	}
+	return nil
+}
+
+// scanTranscript finds the pages the user can see in the cues.
+func (s *sessionService) scanTranscript(ctx context.Context, campaignID, sessionID string, role int, userID string, cues []TranscriptCue) ([]TranscriptMention, error) {
+	names, err := s.transcriptNames(ctx, campaignID, role, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newTranscriptMatcher(names).scan(sessionID, cues), nil
+}
+
+// transcriptNames lists the names to look for; none when no lister is wired.
+func (s *sessionService) transcriptNames(ctx context.Context, campaignID string, role int, userID string) ([]TranscriptName, error) {
+	if s.entityNames == nil {
+		return nil, nil
+	}
+	names, err := s.entityNames.ListTranscriptNames(ctx, campaignID, role, userID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("listing page names: %w", err))
+	}
+	return names, nil
+}
+
+// normalizeTranscript drops a byte-order mark, invalid UTF-8 and CRLF line
+// endings so VTT block splitting sees plain blank lines.
+func normalizeTranscript(content string) string {
+	content = strings.TrimPrefix(content, "\uFEFF")
+	content = strings.ToValidUTF8(content, "")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	return strings.TrimSpace(content)
+}
@@ -0,0 +1,163 @@
+package sessions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// transcriptStore backs a mockSessionRepo with one in-memory transcript.
+type transcriptStore struct {
+	transcript *Transcript
+	mentions   []TranscriptMention
+}
+
+func (st *transcriptStore) repo() *mockSessionRepo {
+	return &mockSessionRepo{
+		getTranscriptFn: func(context.Context, string) (*Transcript, error) { return st.transcript, nil },
+		saveTranscriptFn: func(_ context.Context, t *Transcript, mentions []TranscriptMention) error {
+			st.transcript, st.mentions = t, mentions
+			return nil
+		},
+		replaceTranscriptMentionsFn: func(_ context.Context, _ string, _ time.Time, mentions []TranscriptMention) error {
+			st.mentions = mentions
+			return nil
+		},
+		listTranscriptMentionsFn: func(context.Context, string) ([]TranscriptMention, error) { return st.mentions, nil },
+	}
+}
+
+// stubNameLister returns every name to the GM (role 3) and hides the
+// entities in hidden from anyone else.
+type stubNameLister struct {
+	names  []TranscriptName
+	hidden map[string]bool
+}
+
+func (l *stubNameLister) ListTranscriptNames(_ context.Context, _ string, role int, _ string) ([]TranscriptName, error) {
+	var out []TranscriptName
+	for _, n := range l.names {
+		if role < 3 && l.hidden[n.EntityID] {
+			continue
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func newTranscriptService(st *transcriptStore) SessionService {
+	svc := NewSessionService(st.repo(), nil)
+	svc.SetEntityNameLister(&stubNameLister{
+		names: []TranscriptName{
+			{EntityID: "e-wakanga", Name: "Lady Wakanga", Slug: "lady-wakanga"},
+			{EntityID: "e-wakanga", Name: "Wakanga", Slug: "lady-wakanga", IsAlias: true},
+			{EntityID: "e-village", Name: "Barovia", Slug: "barovia"},
+			{EntityID: "e-strahd", Name: "Strahd", Slug: "strahd"},
+		},
+		hidden: map[string]bool{"e-strahd": true},
+	})
+	return svc
+}
+
+func TestParseTranscript_VTT(t *testing.T) {
+	content := normalizeTranscript("\uFEFFWEBVTT\r\n\r\nNOTE recorded live\r\n\r\n1\r\n00:00:01.500 --> 00:00:04.000 align:start\r\n<v GM>Welcome back to <i>Barovia</i>.\r\n\r\n" +
+		"01:02:03,250 --> 01:02:05,000\r\nStrahd's carriage\r\nwaits.\r\n")
+	if f := detectTranscriptFormat("session.txt", content); f != TranscriptVTT {
+		t.Fatalf("format = %q, want vtt", f)
+	}
+	cues := parseTranscript(TranscriptVTT, content)
+	if len(cues) != 2 {
+		t.Fatalf("cues = %+v", cues)
+	}
+	if cues[0].Text != "GM: Welcome back to Barovia." || cues[0].StartMS == nil || *cues[0].StartMS != 1500 {
+		t.Errorf("cue 1 = %q at %v", cues[0].Text, cues[0].StartMS)
+	}
+	if cues[1].Number != 2 || cues[1].Text != "Strahd's carriage waits." || *cues[1].StartMS != 3723250 {
+		t.Errorf("cue 2 = %+v", cues[1])
+	}
+}
+
+func TestParseTranscript_Text(t *testing.T) {
+	cues := parseTranscript(TranscriptText, "[00:10] We reach the gates.\n\n[Laughter]\nNo stamp here")
+	if len(cues) != 3 {
+		t.Fatalf("cues = %+v", cues)
+	}
+	if cues[0].Text != "We reach the gates." || cues[0].StartMS == nil || *cues[0].StartMS != 10000 {
+		t.Errorf("cue 1 = %+v", cues[0])
+	}
+	if cues[1].Text != "[Laughter]" || cues[1].StartMS != nil || cues[2].StartMS != nil {
+		t.Errorf("untimed cues = %+v", cues[1:])
+	}
+	if got := formatCueTime(3723250); got != "1:02:03" {
+		t.Errorf("formatCueTime = %q", got)
+	}
+}
+
+func TestSaveTranscript_BuildsIndex(t *testing.T) {
+	st := &transcriptStore{}
+	svc := newTranscriptService(st)
+	content := "[00:01:00] Lady Wakanga greets you at the door.\n" +
+		"[00:02:00] Wakanga, wakanga! Barovia is cursed, says Strahd's man.\n" +
+		"[00:03:00] Nothing of note."
+	if _, err := svc.SaveTranscript(context.Background(), "c1", "s1", "u1", 3, `C:\recordings\night.txt`, content); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if st.transcript.FileName != "night.txt" || st.transcript.Format != TranscriptText {
+		t.Errorf("transcript = %+v", st.transcript)
+	}
+	// Cue 1: the full name wins over the alias; cue 2: the repeated alias
+	// counts once.
+	want := []string{"1:e-wakanga:Lady Wakanga", "2:e-wakanga:Wakanga", "2:e-village:Barovia", "2:e-strahd:Strahd"}
+	if len(st.mentions) != len(want) {
+		t.Fatalf("mentions = %+v", st.mentions)
+	}
+	for i, m := range st.mentions {
+		if got := fmt.Sprintf("%d:%s:%s", m.Cue, m.EntityID, m.Matched); got != want[i] {
+			t.Errorf("mention %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	// A Scribe who can't see Strahd gets an index without him, headed by
+	// the page's own name rather than the alias.
+	view, err := svc.GetTranscriptView(context.Background(), "c1", "s1", 2, "u2")
+	if err != nil {
+		t.Fatalf("view: %v", err)
+	}
+	if view.CueCount != 3 || len(view.Index) != 2 {
+		t.Fatalf("view = %+v", view)
+	}
+	if view.Index[0].EntityName != "Barovia" || view.Index[1].EntityName != "Lady Wakanga" || len(view.Index[1].Mentions) != 2 {
+		t.Errorf("index = %+v", view.Index)
+	}
+}
+
+func TestSaveTranscript_Validation(t *testing.T) {
+	svc := newTranscriptService(&transcriptStore{})
+	cases := map[string]string{
+		"empty":     " \n\r\n ",
+		"no cues":   "WEBVTT\n\nNOTE nothing said",
+		"too large": strings.Repeat("a", maxTranscriptBytes+1),
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.SaveTranscript(context.Background(), "c1", "s1", "u1", 3, "t.vtt", content)
+			assertAppError(t, err, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestRescanTranscript(t *testing.T) {
+	st := &transcriptStore{}
+	svc := newTranscriptService(st)
+	assertAppError(t, svc.RescanTranscript(context.Background(), "c1", "s1", "u1", 3), http.StatusNotFound)
+	st.transcript = &Transcript{SessionID: "s1", Format: TranscriptText, Content: "Off to Barovia."}
+	if err := svc.RescanTranscript(context.Background(), "c1", "s1", "u1", 3); err != nil {
+		t.Fatalf("rescan: %v", err)
+	}
+	if len(st.mentions) != 1 || st.mentions[0].EntityID != "e-village" || st.mentions[0].Snippet != "Off to Barovia." {
+		t.Errorf("mentions = %+v", st.mentions)
+	}
+}
@@ -0,0 +1,174 @@
+package settings
+
+// branding.go — instance white-labeling: the name, logo, favicon, landing
+// page copy and footer links a self-hoster shows in place of Chronicle's
+// own. Stored in site_settings and served from the RuntimeConfig snapshot,
+// since every page render reads it. Empty fields fall back to the built-in
+// Chronicle branding.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/sanitize"
+)
+
+// Branding setting keys in site_settings. Footer links are one JSON array.
+const (
+	KeyBrandingName            = "branding.instance_name"
+	KeyBrandingLogo            = "branding.logo"
+	KeyBrandingFavicon         = "branding.favicon"
+	KeyBrandingLandingHeadline = "branding.landing_headline"
+	KeyBrandingLandingText     = "branding.landing_text"
+	KeyBrandingFooterLinks     = "branding.footer_links"
+)
+
+// DefaultInstanceName is shown when no instance name is set.
+const DefaultInstanceName = "Chronicle"
+
+// Length caps for branding fields.
+const (
+	maxInstanceNameLength    = 60
+	maxLandingHeadlineLength = 120
+	maxLandingTextLength     = 1000
+	maxFooterLinks           = 8
+	maxFooterLabelLength     = 40
+	maxFooterURLLength       = 500
+)
+
+// FooterLink is one link in the instance footer, such as a code of
+// conduct or a community Discord.
+type FooterLink struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// Branding is the instance's white-label settings. Logo and Favicon are
+// media file IDs uploaded from the branding page (campaign-less, so they
+// are served unsigned like avatars).
+type Branding struct {
+	InstanceName    string       `json:"instance_name"`
+	Logo            string       `json:"logo"`
+	Favicon         string       `json:"favicon"`
+	LandingHeadline string       `json:"landing_headline"`
+	LandingText     string       `json:"landing_text"`
+	FooterLinks     []FooterLink `json:"footer_links"`
+}
+
+// Name returns the instance name, or the default when none is set.
+func (b Branding) Name() string {
+	if b.InstanceName != "" {
+		return b.InstanceName
+	}
+	return DefaultInstanceName
+}
+
+// validate trims and checks the fields in place.
+func (b *Branding) validate() error {
+	b.InstanceName = strings.Join(strings.Fields(b.InstanceName), " ")
+	b.Logo = strings.TrimSpace(b.Logo)
+	b.Favicon = strings.TrimSpace(b.Favicon)
+	b.LandingHeadline = strings.Join(strings.Fields(b.LandingHeadline), " ")
+	b.LandingText = strings.TrimSpace(b.LandingText)
+
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"instance name", b.InstanceName, maxInstanceNameLength},
+		{"landing headline", b.LandingHeadline, maxLandingHeadlineLength},
+		{"landing text", b.LandingText, maxLandingTextLength},
+	}
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return apperror.NewBadRequest(fmt.Sprintf("%s must be %d characters or fewer", f.name, f.max))
+		}
+	}
+	for _, id := range []string{b.Logo, b.Favicon} {
+		if strings.ContainsAny(id, `/\?#`) || len(id) > 64 {
+			return apperror.NewBadRequest("logo and favicon must be uploaded images")
+		}
+	}
+
+	if len(b.FooterLinks) > maxFooterLinks {
+		return apperror.NewBadRequest(fmt.Sprintf("at most %d footer links are allowed", maxFooterLinks))
+	}
+	links := make([]FooterLink, 0, len(b.FooterLinks))
+	for _, l := range b.FooterLinks {
+		l.Label = strings.TrimSpace(l.Label)
+		l.URL = strings.TrimSpace(l.URL)
+		if l.Label == "" && l.URL == "" {
+			continue
+		}
+		if l.Label == "" || utf8.RuneCountInString(l.Label) > maxFooterLabelLength {
+			return apperror.NewBadRequest(fmt.Sprintf("footer link labels must be 1 to %d characters", maxFooterLabelLength))
+		}
+		if _, ok := sanitize.SafeLinkURL(l.URL); !ok || len(l.URL) > maxFooterURLLength {
+			return apperror.NewBadRequest(fmt.Sprintf("footer link %q needs an http(s) URL or a path on this site", l.Label))
+		}
+		links = append(links, l)
+	}
+	b.FooterLinks = links
+	return nil
+}
+
+// parseBranding reads branding from stored values. A malformed footer
+// link list is treated as empty, matching how Reload handles bad values.
+func parseBranding(all map[string]string) *Branding {
+	b := &Branding{
+		InstanceName:    all[KeyBrandingName],
+		Logo:            all[KeyBrandingLogo],
+		Favicon:         all[KeyBrandingFavicon],
+		LandingHeadline: all[KeyBrandingLandingHeadline],
+		LandingText:     all[KeyBrandingLandingText],
+	}
+	if raw := all[KeyBrandingFooterLinks]; raw != "" {
+		_ = json.Unmarshal([]byte(raw), &b.FooterLinks)
+	}
+	if err := b.validate(); err != nil {
+		b.FooterLinks = nil
+	}
+	return b
+}
+
+// Branding returns the instance branding from the last reload.
+func (rc *RuntimeConfig) Branding() Branding {
+	if b := rc.branding.Load(); b != nil {
+		return *b
+	}
+	return Branding{}
+}
+
+// UpdateBranding validates and persists the branding, then reloads so the
+// change shows on the next page render.
+func (rc *RuntimeConfig) UpdateBranding(ctx context.Context, b Branding) (Branding, error) {
+	if err := b.validate(); err != nil {
+		return Branding{}, err
+	}
+	links, err := json.Marshal(b.FooterLinks)
+	if err != nil {
+		return Branding{}, apperror.NewInternal(fmt.Errorf("encoding footer links: %w", err))
+	}
+	values := map[string]string{
+		KeyBrandingName:            b.InstanceName,
+		KeyBrandingLogo:            b.Logo,
+		KeyBrandingFavicon:         b.Favicon,
+		KeyBrandingLandingHeadline: b.LandingHeadline,
+		KeyBrandingLandingText:     b.LandingText,
+		KeyBrandingFooterLinks:     string(links),
+	}
+	for key, value := range values {
+		if err := rc.repo.Set(ctx, key, value); err != nil {
+			return Branding{}, apperror.NewInternal(fmt.Errorf("persisting %s: %w", key, err))
+		}
+	}
+	if err := rc.Reload(ctx); err != nil {
+		return Branding{}, apperror.NewInternal(err)
+	}
+	return rc.Branding(), nil
+}
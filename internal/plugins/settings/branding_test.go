@@ -0,0 +1,80 @@
+package settings
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBranding_Validate(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      Branding
+		wantErr bool
+	}{
+		{name: "empty", in: Branding{}},
+		{name: "full", in: Branding{
+			InstanceName: "  Tavern  Tales ",
+			Logo:         "3f2a9c1e-logo",
+			FooterLinks:  []FooterLink{{Label: "Discord", URL: "https://discord.gg/abc"}, {Label: "Rules", URL: "/about"}},
+		}},
+		{name: "name too long", in: Branding{InstanceName: strings.Repeat("a", maxInstanceNameLength+1)}, wantErr: true},
+		{name: "logo path", in: Branding{Logo: "../etc/passwd"}, wantErr: true},
+		{name: "script link", in: Branding{FooterLinks: []FooterLink{{Label: "x", URL: "javascript:alert(1)"}}}, wantErr: true},
+		{name: "protocol-relative link", in: Branding{FooterLinks: []FooterLink{{Label: "x", URL: "//evil.example"}}}, wantErr: true},
+		{name: "link without label", in: Branding{FooterLinks: []FooterLink{{URL: "/about"}}}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.in
+			err := b.validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validate() err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	// Blank rows from the form are dropped and whitespace collapsed.
+	b := Branding{InstanceName: "  Tavern  Tales ", FooterLinks: []FooterLink{{}, {Label: " Rules ", URL: " /rules "}}}
+	if err := b.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if b.InstanceName != "Tavern Tales" || len(b.FooterLinks) != 1 || b.FooterLinks[0] != (FooterLink{Label: "Rules", URL: "/rules"}) {
+		t.Errorf("normalized = %+v", b)
+	}
+}
+
+func TestRuntimeConfig_UpdateBranding(t *testing.T) {
+	store := map[string]string{}
+	rc := NewRuntimeConfig(newMapSettingsRepo(store), RuntimeDefaults{})
+	if got := rc.Branding().Name(); got != DefaultInstanceName {
+		t.Errorf("default name = %q", got)
+	}
+
+	got, err := rc.UpdateBranding(context.Background(), Branding{
+		InstanceName: "Tavern Tales",
+		FooterLinks:  []FooterLink{{Label: "Discord", URL: "https://discord.gg/abc"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name() != "Tavern Tales" || len(got.FooterLinks) != 1 {
+		t.Errorf("branding = %+v", got)
+	}
+	if store[KeyBrandingFooterLinks] != `[{"label":"Discord","url":"https://discord.gg/abc"}]` {
+		t.Errorf("stored links = %s", store[KeyBrandingFooterLinks])
+	}
+
+	// A second instance picks it up on reload.
+	other := NewRuntimeConfig(newMapSettingsRepo(store), RuntimeDefaults{})
+	if err := other.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if other.Branding().InstanceName != "Tavern Tales" {
+		t.Errorf("reloaded name = %q", other.Branding().InstanceName)
+	}
+
+	if _, err := rc.UpdateBranding(context.Background(), Branding{FooterLinks: []FooterLink{{Label: "x", URL: "data:text/html,hi"}}}); err == nil {
+		t.Error("a data: footer link was accepted")
+	}
+}
@@ -0,0 +1,154 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// CampaignFlagOverride is one per-campaign flag override, with the campaign
+// name for the admin table.
+type CampaignFlagOverride struct {
+	CampaignID   string `json:"campaign_id"`
+	CampaignName string `json:"campaign_name"`
+	Key          string `json:"key"`
+	Enabled      bool   `json:"enabled"`
+}
+
+// FlagRepository defines the data access contract for feature flag
+// overrides. Rows exist only where an admin overrode a flag's default.
+type FlagRepository interface {
+	// InstanceOverrides returns the instance-wide overrides by key.
+	InstanceOverrides(ctx context.Context) (map[string]bool, error)
+
+	// CampaignOverrides returns one campaign's overrides by key.
+	CampaignOverrides(ctx context.Context, campaignID string) (map[string]bool, error)
+
+	// ListCampaignOverrides returns every campaign override for the admin page.
+	ListCampaignOverrides(ctx context.Context) ([]CampaignFlagOverride, error)
+
+	// SetInstance upserts an instance-wide override.
+	SetInstance(ctx context.Context, key string, enabled bool, updatedBy string) error
+
+	// DeleteInstance removes an instance-wide override (back to the default).
+	DeleteInstance(ctx context.Context, key string) error
+
+	// SetCampaign upserts a campaign override.
+	SetCampaign(ctx context.Context, campaignID, key string, enabled bool, updatedBy string) error
+
+	// DeleteCampaign removes a campaign override (back to the instance value).
+	DeleteCampaign(ctx context.Context, campaignID, key string) error
+}
+
+// flagRepository implements FlagRepository with MariaDB.
+type flagRepository struct {
+	db *sql.DB
+}
+
+// NewFlagRepository creates a new feature flag repository.
+func NewFlagRepository(db *sql.DB) FlagRepository {
+	return &flagRepository{db: db}
+}
+
+// InstanceOverrides returns the instance-wide overrides.
+func (r *flagRepository) InstanceOverrides(ctx context.Context) (map[string]bool, error) {
+	return r.overrides(ctx, `SELECT flag_key, enabled FROM feature_flags`)
+}
+
+// CampaignOverrides returns one campaign's overrides.
+func (r *flagRepository) CampaignOverrides(ctx context.Context, campaignID string) (map[string]bool, error) {
+	return r.overrides(ctx, `SELECT flag_key, enabled FROM campaign_feature_flags WHERE campaign_id = ?`, campaignID)
+}
+
+// overrides scans (flag_key, enabled) rows into a map.
+func (r *flagRepository) overrides(ctx context.Context, query string, args ...any) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		var enabled bool
+		if err := rows.Scan(&key, &enabled); err != nil {
+			return nil, fmt.Errorf("scanning feature flag: %w", err)
+		}
+		out[key] = enabled
+	}
+	return out, rows.Err()
+}
+
+// ListCampaignOverrides returns every campaign override with its campaign name.
+func (r *flagRepository) ListCampaignOverrides(ctx context.Context) ([]CampaignFlagOverride, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT f.campaign_id, c.name, f.flag_key, f.enabled
+		 FROM campaign_feature_flags f
+		 INNER JOIN campaigns c ON c.id = f.campaign_id
+		 ORDER BY f.flag_key, c.name`)
+	if err != nil {
+		return nil, fmt.Errorf("listing campaign feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CampaignFlagOverride
+	for rows.Next() {
+		var o CampaignFlagOverride
+		if err := rows.Scan(&o.CampaignID, &o.CampaignName, &o.Key, &o.Enabled); err != nil {
+			return nil, fmt.Errorf("scanning campaign feature flag: %w", err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// SetInstance upserts an instance-wide override.
+func (r *flagRepository) SetInstance(ctx context.Context, key string, enabled bool, updatedBy string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO feature_flags (flag_key, enabled, updated_by) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_by = VALUES(updated_by)`,
+		key, enabled, nullableString(updatedBy))
+	if err != nil {
+		return fmt.Errorf("setting feature flag %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteInstance removes an instance-wide override.
+func (r *flagRepository) DeleteInstance(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE flag_key = ?`, key); err != nil {
+		return fmt.Errorf("clearing feature flag %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetCampaign upserts a campaign override.
+func (r *flagRepository) SetCampaign(ctx context.Context, campaignID, key string, enabled bool, updatedBy string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO campaign_feature_flags (campaign_id, flag_key, enabled, updated_by) VALUES (?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_by = VALUES(updated_by)`,
+		campaignID, key, enabled, nullableString(updatedBy))
+	if err != nil {
+		return fmt.Errorf("setting campaign feature flag %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteCampaign removes a campaign override.
+func (r *flagRepository) DeleteCampaign(ctx context.Context, campaignID, key string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM campaign_feature_flags WHERE campaign_id = ? AND flag_key = ?`, campaignID, key,
+	); err != nil {
+		return fmt.Errorf("clearing campaign feature flag %s: %w", key, err)
+	}
+	return nil
+}
+
+// nullableString maps "" to NULL for nullable foreign keys.
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
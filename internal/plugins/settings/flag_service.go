@@ -0,0 +1,233 @@
+package settings
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// flagCacheTTL bounds how long a stale override set can survive if an
+	// invalidation is lost. Writes delete the key, so normally it's exact.
+	flagCacheTTL = 10 * time.Minute
+
+	// flagCacheLoaded marks a cached hash as loaded, so a scope with no
+	// overrides is cached too instead of hitting the DB on every check.
+	flagCacheLoaded = "_loaded"
+)
+
+// FlagState is a registered flag with its instance override for the admin
+// page.
+type FlagState struct {
+	FlagDefinition
+	// Override is the instance override; nil means the default applies.
+	Override *bool `json:"override"`
+	// Enabled is the effective instance value.
+	Enabled bool `json:"enabled"`
+}
+
+// FlagService evaluates and manages feature flags.
+type FlagService interface {
+	// Enabled reports whether key is on for scope: campaign override, then
+	// instance override, then the registered default. Lookup errors are
+	// logged and fall back to the next tier, so a Redis or DB hiccup never
+	// fails a request.
+	Enabled(ctx context.Context, scope FlagScope, key string) bool
+
+	// List returns every registered flag with its instance state, plus every
+	// campaign override.
+	List(ctx context.Context) ([]FlagState, []CampaignFlagOverride, error)
+
+	// SetInstance overrides a flag instance-wide; nil clears the override.
+	SetInstance(ctx context.Context, key string, enabled *bool, actorID string) error
+
+	// SetCampaign overrides a flag for one campaign; nil clears the override.
+	SetCampaign(ctx context.Context, campaignID, key string, enabled *bool, actorID string) error
+}
+
+// flagService implements FlagService with DB storage and a Redis cache.
+type flagService struct {
+	repo  FlagRepository
+	cache *redis.Client
+}
+
+// NewFlagService creates the feature flag service. cache may be nil, in
+// which case every check reads the DB.
+func NewFlagService(repo FlagRepository, cache *redis.Client) FlagService {
+	return &flagService{repo: repo, cache: cache}
+}
+
+// flagCacheKey is the Redis hash holding a scope's overrides.
+func flagCacheKey(scope FlagScope) string {
+	if scope.CampaignID == "" {
+		return "flags:instance"
+	}
+	return "flags:campaign:" + scope.CampaignID
+}
+
+// Enabled resolves a flag through the override tiers.
+func (s *flagService) Enabled(ctx context.Context, scope FlagScope, key string) bool {
+	def, ok := LookupFlag(key)
+	if !ok {
+		return false
+	}
+	if scope.CampaignID != "" && def.PerCampaign {
+		if v, ok := s.override(ctx, scope, key); ok {
+			return v
+		}
+	}
+	if v, ok := s.override(ctx, InstanceScope, key); ok {
+		return v
+	}
+	return def.Default
+}
+
+// override returns the stored override for key in scope, if any.
+func (s *flagService) override(ctx context.Context, scope FlagScope, key string) (bool, bool) {
+	overrides, err := s.overrides(ctx, scope)
+	if err != nil {
+		slog.Warn("feature flags: loading overrides failed",
+			slog.String("scope", flagCacheKey(scope)), slog.Any("error", err))
+		return false, false
+	}
+	v, ok := overrides[key]
+	return v, ok
+}
+
+// overrides returns a scope's overrides from Redis, loading them from the
+// DB (and caching them) on a miss.
+func (s *flagService) overrides(ctx context.Context, scope FlagScope) (map[string]bool, error) {
+	cacheKey := flagCacheKey(scope)
+	if s.cache != nil {
+		if cached, err := s.cache.HGetAll(ctx, cacheKey).Result(); err == nil && cached[flagCacheLoaded] != "" {
+			out := make(map[string]bool, len(cached)-1)
+			for k, v := range cached {
+				if k != flagCacheLoaded {
+					out[k] = v == "1"
+				}
+			}
+			return out, nil
+		}
+	}
+
+	var overrides map[string]bool
+	var err error
+	if scope.CampaignID == "" {
+		overrides, err = s.repo.InstanceOverrides(ctx)
+	} else {
+		overrides, err = s.repo.CampaignOverrides(ctx, scope.CampaignID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		fields := make(map[string]any, len(overrides)+1)
+		fields[flagCacheLoaded] = "1"
+		for k, v := range overrides {
+			if v {
+				fields[k] = "1"
+			} else {
+				fields[k] = "0"
+			}
+		}
+		pipe := s.cache.TxPipeline()
+		pipe.Del(ctx, cacheKey)
+		pipe.HSet(ctx, cacheKey, fields)
+		pipe.Expire(ctx, cacheKey, flagCacheTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			slog.Warn("feature flags: caching overrides failed", slog.String("scope", cacheKey), slog.Any("error", err))
+		}
+	}
+	return overrides, nil
+}
+
+// invalidate drops a scope's cached overrides after a write.
+func (s *flagService) invalidate(ctx context.Context, scope FlagScope) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Del(ctx, flagCacheKey(scope)).Err(); err != nil {
+		slog.Warn("feature flags: cache invalidation failed", slog.String("scope", flagCacheKey(scope)), slog.Any("error", err))
+	}
+}
+
+// List returns every registered flag with its instance state. Read from the
+// DB rather than the cache so the admin page always shows stored truth.
+func (s *flagService) List(ctx context.Context) ([]FlagState, []CampaignFlagOverride, error) {
+	instance, err := s.repo.InstanceOverrides(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(err)
+	}
+	campaignOverrides, err := s.repo.ListCampaignOverrides(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternal(err)
+	}
+
+	defs := RegisteredFlags()
+	states := make([]FlagState, len(defs))
+	for i, def := range defs {
+		states[i] = FlagState{FlagDefinition: def, Enabled: def.Default}
+		if v, ok := instance[def.Key]; ok {
+			states[i].Override = &v
+			states[i].Enabled = v
+		}
+	}
+
+	// Drop overrides for flags no longer registered (a removed experiment):
+	// they're inert, and listing them would invite editing a dead key.
+	known := campaignOverrides[:0]
+	for _, o := range campaignOverrides {
+		if _, ok := LookupFlag(o.Key); ok {
+			known = append(known, o)
+		}
+	}
+	return states, known, nil
+}
+
+// SetInstance overrides or clears a flag instance-wide.
+func (s *flagService) SetInstance(ctx context.Context, key string, enabled *bool, actorID string) error {
+	if _, ok := LookupFlag(key); !ok {
+		return apperror.NewNotFound("unknown feature flag")
+	}
+	var err error
+	if enabled == nil {
+		err = s.repo.DeleteInstance(ctx, key)
+	} else {
+		err = s.repo.SetInstance(ctx, key, *enabled, actorID)
+	}
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	s.invalidate(ctx, InstanceScope)
+	return nil
+}
+
+// SetCampaign overrides or clears a flag for one campaign.
+func (s *flagService) SetCampaign(ctx context.Context, campaignID, key string, enabled *bool, actorID string) error {
+	def, ok := LookupFlag(key)
+	if !ok {
+		return apperror.NewNotFound("unknown feature flag")
+	}
+	if !def.PerCampaign {
+		return apperror.NewBadRequest("this feature flag can only be set for the whole instance")
+	}
+	if campaignID == "" {
+		return apperror.NewBadRequest("campaign is required")
+	}
+	var err error
+	if enabled == nil {
+		err = s.repo.DeleteCampaign(ctx, campaignID, key)
+	} else {
+		err = s.repo.SetCampaign(ctx, campaignID, key, *enabled, actorID)
+	}
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	s.invalidate(ctx, CampaignScope(campaignID))
+	return nil
+}
@@ -0,0 +1,235 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeFlagRepo is an in-memory FlagRepository that counts reads.
+type fakeFlagRepo struct {
+	instance  map[string]bool
+	campaigns map[string]map[string]bool
+	reads     int
+}
+
+func newFakeFlagRepo() *fakeFlagRepo {
+	return &fakeFlagRepo{instance: map[string]bool{}, campaigns: map[string]map[string]bool{}}
+}
+
+func (r *fakeFlagRepo) InstanceOverrides(context.Context) (map[string]bool, error) {
+	r.reads++
+	return copyFlags(r.instance), nil
+}
+
+func (r *fakeFlagRepo) CampaignOverrides(_ context.Context, campaignID string) (map[string]bool, error) {
+	r.reads++
+	return copyFlags(r.campaigns[campaignID]), nil
+}
+
+func (r *fakeFlagRepo) ListCampaignOverrides(context.Context) ([]CampaignFlagOverride, error) {
+	var out []CampaignFlagOverride
+	for id, flags := range r.campaigns {
+		for k, v := range flags {
+			out = append(out, CampaignFlagOverride{CampaignID: id, Key: k, Enabled: v})
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeFlagRepo) SetInstance(_ context.Context, key string, enabled bool, _ string) error {
+	r.instance[key] = enabled
+	return nil
+}
+
+func (r *fakeFlagRepo) DeleteInstance(_ context.Context, key string) error {
+	delete(r.instance, key)
+	return nil
+}
+
+func (r *fakeFlagRepo) SetCampaign(_ context.Context, campaignID, key string, enabled bool, _ string) error {
+	if r.campaigns[campaignID] == nil {
+		r.campaigns[campaignID] = map[string]bool{}
+	}
+	r.campaigns[campaignID][key] = enabled
+	return nil
+}
+
+func (r *fakeFlagRepo) DeleteCampaign(_ context.Context, campaignID, key string) error {
+	delete(r.campaigns[campaignID], key)
+	return nil
+}
+
+func copyFlags(m map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// registerTestFlag registers a flag for one test and removes it afterwards,
+// since the registry is process-wide.
+func registerTestFlag(t *testing.T, def FlagDefinition) {
+	t.Helper()
+	RegisterFlag(def)
+	t.Cleanup(func() {
+		flagRegistryMu.Lock()
+		delete(flagRegistry, def.Key)
+		flagRegistryMu.Unlock()
+	})
+}
+
+func bptr(v bool) *bool { return &v }
+
+func TestFlagService_Resolution(t *testing.T) {
+	registerTestFlag(t, FlagDefinition{Key: "test.campaign_flag", PerCampaign: true})
+	registerTestFlag(t, FlagDefinition{Key: "test.instance_flag", Default: true})
+
+	repo := newFakeFlagRepo()
+	svc := NewFlagService(repo, nil)
+	ctx := context.Background()
+	camp := CampaignScope("camp-1")
+
+	cases := []struct {
+		name  string
+		setup func()
+		scope FlagScope
+		key   string
+		want  bool
+	}{
+		{name: "default off", scope: camp, key: "test.campaign_flag", want: false},
+		{name: "default on", scope: InstanceScope, key: "test.instance_flag", want: true},
+		{name: "unregistered", scope: InstanceScope, key: "test.nope", want: false},
+		{
+			name:  "instance override reaches campaigns",
+			setup: func() { _ = svc.SetInstance(ctx, "test.campaign_flag", bptr(true), "admin") },
+			scope: camp, key: "test.campaign_flag", want: true,
+		},
+		{
+			name:  "campaign override beats instance",
+			setup: func() { _ = svc.SetCampaign(ctx, "camp-1", "test.campaign_flag", bptr(false), "admin") },
+			scope: camp, key: "test.campaign_flag", want: false,
+		},
+		{
+			name:  "other campaigns keep the instance value",
+			scope: CampaignScope("camp-2"), key: "test.campaign_flag", want: true,
+		},
+		{
+			name:  "instance-only flag ignores campaign scope",
+			setup: func() { _ = svc.SetInstance(ctx, "test.instance_flag", bptr(false), "admin") },
+			scope: camp, key: "test.instance_flag", want: false,
+		},
+		{
+			name:  "clearing restores the default",
+			setup: func() { _ = svc.SetInstance(ctx, "test.instance_flag", nil, "admin") },
+			scope: InstanceScope, key: "test.instance_flag", want: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setup != nil {
+				tc.setup()
+			}
+			if got := svc.Enabled(ctx, tc.scope, tc.key); got != tc.want {
+				t.Errorf("Enabled(%+v, %s) = %v, want %v", tc.scope, tc.key, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlagService_Validation(t *testing.T) {
+	registerTestFlag(t, FlagDefinition{Key: "test.global_only"})
+	svc := NewFlagService(newFakeFlagRepo(), nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "unknown instance flag", err: svc.SetInstance(ctx, "test.missing", bptr(true), ""), wantCode: 404},
+		{name: "unknown campaign flag", err: svc.SetCampaign(ctx, "camp-1", "test.missing", bptr(true), ""), wantCode: 404},
+		{name: "campaign override not allowed", err: svc.SetCampaign(ctx, "camp-1", "test.global_only", bptr(true), ""), wantCode: 400},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var appErr *apperror.AppError
+			if !errors.As(tc.err, &appErr) || appErr.Code != tc.wantCode {
+				t.Errorf("err = %v, want AppError %d", tc.err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestFlagService_RedisCache(t *testing.T) {
+	registerTestFlag(t, FlagDefinition{Key: "test.cached", PerCampaign: true})
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	repo := newFakeFlagRepo()
+	svc := NewFlagService(repo, rdb)
+	ctx := context.Background()
+
+	// Empty override sets are cached too: repeated checks read the DB once.
+	for i := 0; i < 3; i++ {
+		if svc.Enabled(ctx, InstanceScope, "test.cached") {
+			t.Fatal("flag should default to off")
+		}
+	}
+	if repo.reads != 1 {
+		t.Errorf("DB reads = %d, want 1", repo.reads)
+	}
+
+	// A write invalidates, so the next check sees it.
+	if err := svc.SetInstance(ctx, "test.cached", bptr(true), "admin"); err != nil {
+		t.Fatalf("SetInstance: %v", err)
+	}
+	if !svc.Enabled(ctx, InstanceScope, "test.cached") {
+		t.Error("flag should be on after the instance override")
+	}
+
+	// Redis down: checks fall through to the DB rather than failing.
+	mr.Close()
+	if !svc.Enabled(ctx, InstanceScope, "test.cached") {
+		t.Error("flag should still be on from the DB with Redis unavailable")
+	}
+}
+
+func TestFlagEnabled_Context(t *testing.T) {
+	registerTestFlag(t, FlagDefinition{Key: "test.ctx", Default: true})
+	ctx := context.Background()
+
+	// No service on the context: the registered default applies.
+	if !FlagEnabled(ctx, InstanceScope, "test.ctx") {
+		t.Error("want the default without a service")
+	}
+	if FlagEnabled(ctx, InstanceScope, "test.unknown") {
+		t.Error("unregistered flags are off")
+	}
+
+	repo := newFakeFlagRepo()
+	repo.instance["test.ctx"] = false
+	ctx = WithFlags(ctx, NewFlagService(repo, nil))
+	if FlagEnabled(ctx, InstanceScope, "test.ctx") {
+		t.Error("want the stored override through the context service")
+	}
+}
+
+func TestRegisterFlag_Invalid(t *testing.T) {
+	for _, key := range []string{"", "nodot", "Upper.case", "trailing."} {
+		t.Run(key, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterFlag(%q) did not panic", key)
+				}
+			}()
+			RegisterFlag(FlagDefinition{Key: key})
+		})
+	}
+}
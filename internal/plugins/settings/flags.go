@@ -0,0 +1,118 @@
+package settings
+
+// flags.go — feature flags for experimental plugins and blocks. A flag is
+// declared in code with RegisterFlag (key, description, default); admins
+// override it for the whole instance or for one campaign. Handlers check a
+// flag with FlagEnabled(ctx, scope, key), which reads the FlagService that
+// InjectFlags put on the request context, so no handler needs it wired in.
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FlagDefinition declares a feature flag.
+type FlagDefinition struct {
+	// Key is the stable identifier, "<plugin>.<feature>" (e.g. "maps.fog").
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Default applies when no override is stored.
+	Default bool `json:"default"`
+
+	// PerCampaign allows campaign overrides. Flags that gate instance-wide
+	// machinery (a background worker, a global route) leave it false.
+	PerCampaign bool `json:"per_campaign"`
+}
+
+// flagKeyPattern keeps keys readable and safe as Redis hash fields.
+var flagKeyPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)+$`)
+
+var (
+	flagRegistryMu sync.RWMutex
+	flagRegistry   = map[string]FlagDefinition{}
+)
+
+// RegisterFlag declares a feature flag. Call it during startup wiring; it
+// panics on a malformed or duplicate key, like a duplicate route would.
+func RegisterFlag(def FlagDefinition) {
+	if !flagKeyPattern.MatchString(def.Key) {
+		panic(fmt.Sprintf("settings: invalid feature flag key %q", def.Key))
+	}
+	flagRegistryMu.Lock()
+	defer flagRegistryMu.Unlock()
+	if _, dup := flagRegistry[def.Key]; dup {
+		panic(fmt.Sprintf("settings: feature flag %q registered twice", def.Key))
+	}
+	flagRegistry[def.Key] = def
+}
+
+// LookupFlag returns a registered flag's definition.
+func LookupFlag(key string) (FlagDefinition, bool) {
+	flagRegistryMu.RLock()
+	defer flagRegistryMu.RUnlock()
+	def, ok := flagRegistry[key]
+	return def, ok
+}
+
+// RegisteredFlags returns every registered flag, sorted by key.
+func RegisteredFlags() []FlagDefinition {
+	flagRegistryMu.RLock()
+	defer flagRegistryMu.RUnlock()
+	defs := make([]FlagDefinition, 0, len(flagRegistry))
+	for _, def := range flagRegistry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Key < defs[j].Key })
+	return defs
+}
+
+// FlagScope is where a flag is evaluated: the instance, or one campaign
+// (which falls back to the instance value).
+type FlagScope struct {
+	CampaignID string
+}
+
+// InstanceScope evaluates a flag for the whole instance.
+var InstanceScope = FlagScope{}
+
+// CampaignScope evaluates a flag for one campaign.
+func CampaignScope(campaignID string) FlagScope {
+	return FlagScope{CampaignID: campaignID}
+}
+
+// flagsCtxKey is the request-context key holding the FlagService.
+type flagsCtxKey struct{}
+
+// WithFlags returns ctx carrying the flag service.
+func WithFlags(ctx context.Context, svc FlagService) context.Context {
+	return context.WithValue(ctx, flagsCtxKey{}, svc)
+}
+
+// InjectFlags puts the flag service on every request's context so handlers
+// and the services they call can use FlagEnabled.
+func InjectFlags(svc FlagService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(WithFlags(c.Request().Context(), svc)))
+			return next(c)
+		}
+	}
+}
+
+// FlagEnabled reports whether a flag is on for scope. Unregistered keys and
+// contexts without a flag service (background jobs, tests) report the
+// registered default, or false.
+func FlagEnabled(ctx context.Context, scope FlagScope, key string) bool {
+	if svc, ok := ctx.Value(flagsCtxKey{}).(FlagService); ok && svc != nil {
+		return svc.Enabled(ctx, scope, key)
+	}
+	def, _ := LookupFlag(key)
+	return def.Default
+}
@@ -0,0 +1,141 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InviteCodeRepository defines the data access contract for registration
+// invite codes.
+type InviteCodeRepository interface {
+	// List returns every code, newest first.
+	List(ctx context.Context) ([]InviteCode, error)
+
+	// FindByCode returns the code row, or nil if it doesn't exist.
+	FindByCode(ctx context.Context, code string) (*InviteCode, error)
+
+	// Create inserts a code and sets its ID.
+	Create(ctx context.Context, code *InviteCode) error
+
+	// Delete removes a code. Returns false if no such code exists.
+	Delete(ctx context.Context, id int) (bool, error)
+
+	// Redeem consumes one use of a code if it is unexpired and has uses left.
+	// Returns false otherwise. Atomic, so concurrent signups can't overrun
+	// max_uses.
+	Redeem(ctx context.Context, code string, now time.Time) (bool, error)
+}
+
+// inviteCodeRepository implements InviteCodeRepository with MariaDB.
+type inviteCodeRepository struct {
+	db *sql.DB
+}
+
+// NewInviteCodeRepository creates a new invite code repository.
+func NewInviteCodeRepository(db *sql.DB) InviteCodeRepository {
+	return &inviteCodeRepository{db: db}
+}
+
+const inviteCodeColumns = `id, code, note, max_uses, use_count, expires_at, created_by, created_at`
+
+// scanInviteCode scans one row selected with inviteCodeColumns.
+func scanInviteCode(row interface{ Scan(...any) error }) (*InviteCode, error) {
+	var c InviteCode
+	var maxUses sql.NullInt64
+	var expiresAt sql.NullTime
+	var createdBy sql.NullString
+	if err := row.Scan(&c.ID, &c.Code, &c.Note, &maxUses, &c.UseCount, &expiresAt, &createdBy, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	if maxUses.Valid {
+		n := int(maxUses.Int64)
+		c.MaxUses = &n
+	}
+	if expiresAt.Valid {
+		c.ExpiresAt = &expiresAt.Time
+	}
+	c.CreatedBy = createdBy.String
+	return &c, nil
+}
+
+// List returns every code, newest first.
+func (r *inviteCodeRepository) List(ctx context.Context) ([]InviteCode, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+inviteCodeColumns+` FROM registration_invite_codes ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing invite codes: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []InviteCode
+	for rows.Next() {
+		c, err := scanInviteCode(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning invite code: %w", err)
+		}
+		codes = append(codes, *c)
+	}
+	return codes, rows.Err()
+}
+
+// FindByCode returns the code row, or nil if it doesn't exist.
+func (r *inviteCodeRepository) FindByCode(ctx context.Context, code string) (*InviteCode, error) {
+	c, err := scanInviteCode(r.db.QueryRowContext(ctx,
+		`SELECT `+inviteCodeColumns+` FROM registration_invite_codes WHERE code = ?`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding invite code: %w", err)
+	}
+	return c, nil
+}
+
+// Create inserts a code and sets its ID.
+func (r *inviteCodeRepository) Create(ctx context.Context, code *InviteCode) error {
+	var createdBy any
+	if code.CreatedBy != "" {
+		createdBy = code.CreatedBy
+	}
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO registration_invite_codes (code, note, max_uses, expires_at, created_by, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		code.Code, code.Note, code.MaxUses, code.ExpiresAt, createdBy, code.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting invite code: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("reading invite code id: %w", err)
+	}
+	code.ID = int(id)
+	return nil
+}
+
+// Delete removes a code.
+func (r *inviteCodeRepository) Delete(ctx context.Context, id int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM registration_invite_codes WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("deleting invite code: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Redeem consumes one use of a live code in a single conditional UPDATE.
+func (r *inviteCodeRepository) Redeem(ctx context.Context, code string, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE registration_invite_codes SET use_count = use_count + 1
+		 WHERE code = ?
+		   AND (max_uses IS NULL OR use_count < max_uses)
+		   AND (expires_at IS NULL OR expires_at > ?)`,
+		code, now)
+	if err != nil {
+		return false, fmt.Errorf("redeeming invite code: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,228 @@
+package settings
+
+// invite_codes.go — admin-issued registration invite codes. While the site
+// registration mode is "invite", an account can be created either through a
+// campaign invite link (checked by the campaigns plugin) or with one of these
+// codes. The auth plugin consumes them through its own small interface, so
+// it never imports this package.
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Invite code limits.
+const (
+	inviteCodeMinLength  = 6
+	inviteCodeMaxLength  = 64
+	inviteCodeMaxNote    = 200
+	inviteCodeMaxUses    = 10000
+	inviteCodeMaxExpires = 365 // days
+)
+
+// inviteCodeAlphabet omits look-alike characters (0/O, 1/I/L) so codes read
+// back correctly over voice chat or from a screenshot.
+const inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// InviteCode is an admin-issued registration invite code.
+type InviteCode struct {
+	ID        int        `json:"id"`
+	Code      string     `json:"code"`
+	Note      string     `json:"note"`
+	MaxUses   *int       `json:"max_uses,omitempty"` // nil = unlimited
+	UseCount  int        `json:"use_count"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil = never
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Usable reports whether the code can still be redeemed at now.
+func (c InviteCode) Usable(now time.Time) bool {
+	if c.ExpiresAt != nil && !c.ExpiresAt.After(now) {
+		return false
+	}
+	return c.MaxUses == nil || c.UseCount < *c.MaxUses
+}
+
+// CreateInviteCodeInput is the admin form for a new code. An empty Code is
+// generated; zero MaxUses and ExpiresInDays mean unlimited and never.
+type CreateInviteCodeInput struct {
+	Code          string `json:"code" form:"code"`
+	Note          string `json:"note" form:"note"`
+	MaxUses       int    `json:"max_uses" form:"max_uses"`
+	ExpiresInDays int    `json:"expires_in_days" form:"expires_in_days"`
+}
+
+// InviteCodeService manages registration invite codes.
+type InviteCodeService interface {
+	// List returns every code, newest first.
+	List(ctx context.Context) ([]InviteCode, error)
+
+	// Create validates and stores a new code.
+	Create(ctx context.Context, input CreateInviteCodeInput, createdBy string) (*InviteCode, error)
+
+	// Delete revokes a code.
+	Delete(ctx context.Context, id int) error
+
+	// IsInviteCodeValid reports whether code exists and can still be redeemed.
+	IsInviteCodeValid(ctx context.Context, code string) bool
+
+	// RedeemInviteCode consumes one use of code. Returns false when the code
+	// is unknown, expired, or used up.
+	RedeemInviteCode(ctx context.Context, code string) (bool, error)
+}
+
+// inviteCodeService implements InviteCodeService.
+type inviteCodeService struct {
+	repo InviteCodeRepository
+	now  func() time.Time
+}
+
+// NewInviteCodeService creates a new invite code service.
+func NewInviteCodeService(repo InviteCodeRepository) InviteCodeService {
+	return &inviteCodeService{repo: repo, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// NormalizeInviteCode canonicalizes a code as typed: codes are matched
+// case-insensitively and surrounding whitespace is ignored.
+func NormalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// List returns every code.
+func (s *inviteCodeService) List(ctx context.Context) ([]InviteCode, error) {
+	codes, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return codes, nil
+}
+
+// Create validates and stores a new code.
+func (s *inviteCodeService) Create(ctx context.Context, input CreateInviteCodeInput, createdBy string) (*InviteCode, error) {
+	code := NormalizeInviteCode(input.Code)
+	if code == "" {
+		generated, err := generateInviteCode()
+		if err != nil {
+			return nil, apperror.NewInternal(err)
+		}
+		code = generated
+	} else if err := validateInviteCode(code); err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(input.Note)
+	if len([]rune(note)) > inviteCodeMaxNote {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("note must be at most %d characters", inviteCodeMaxNote))
+	}
+	if input.MaxUses < 0 || input.MaxUses > inviteCodeMaxUses {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("max uses must be between 0 and %d", inviteCodeMaxUses))
+	}
+	if input.ExpiresInDays < 0 || input.ExpiresInDays > inviteCodeMaxExpires {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("expiry must be between 0 and %d days", inviteCodeMaxExpires))
+	}
+
+	existing, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if existing != nil {
+		return nil, apperror.NewConflict("an invite code with that value already exists")
+	}
+
+	now := s.now()
+	ic := &InviteCode{Code: code, Note: note, CreatedBy: createdBy, CreatedAt: now}
+	if input.MaxUses > 0 {
+		n := input.MaxUses
+		ic.MaxUses = &n
+	}
+	if input.ExpiresInDays > 0 {
+		exp := now.AddDate(0, 0, input.ExpiresInDays)
+		ic.ExpiresAt = &exp
+	}
+	if err := s.repo.Create(ctx, ic); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return ic, nil
+}
+
+// Delete revokes a code.
+func (s *inviteCodeService) Delete(ctx context.Context, id int) error {
+	ok, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	if !ok {
+		return apperror.NewNotFound("invite code not found")
+	}
+	return nil
+}
+
+// IsInviteCodeValid reports whether code can still be redeemed. Errors read
+// as invalid: this only decides what the register page shows.
+func (s *inviteCodeService) IsInviteCodeValid(ctx context.Context, code string) bool {
+	code = NormalizeInviteCode(code)
+	if code == "" {
+		return false
+	}
+	ic, err := s.repo.FindByCode(ctx, code)
+	return err == nil && ic != nil && ic.Usable(s.now())
+}
+
+// RedeemInviteCode consumes one use of code.
+func (s *inviteCodeService) RedeemInviteCode(ctx context.Context, code string) (bool, error) {
+	code = NormalizeInviteCode(code)
+	if code == "" {
+		return false, nil
+	}
+	ok, err := s.repo.Redeem(ctx, code, s.now())
+	if err != nil {
+		return false, apperror.NewInternal(err)
+	}
+	return ok, nil
+}
+
+// validateInviteCode checks an admin-chosen code: letters, digits, and
+// hyphens, of a length that is neither guessable nor unwieldy.
+func validateInviteCode(code string) error {
+	if len(code) < inviteCodeMinLength || len(code) > inviteCodeMaxLength {
+		return apperror.NewBadRequest(fmt.Sprintf("invite code must be %d-%d characters", inviteCodeMinLength, inviteCodeMaxLength))
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return apperror.NewBadRequest("invite code may only contain letters, digits, and hyphens")
+		}
+	}
+	return nil
+}
+
+// generateInviteCode returns a random code like "K7QM-XR4P-2WDN". Bytes at
+// or above the largest multiple of the alphabet size are discarded so every
+// character is equally likely.
+func generateInviteCode() (string, error) {
+	const length = 12
+	limit := byte(256 - 256%len(inviteCodeAlphabet))
+	var b strings.Builder
+	buf := make([]byte, 32)
+	for n := 0; n < length; {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generating invite code: %w", err)
+		}
+		for _, v := range buf {
+			if v >= limit || n == length {
+				continue
+			}
+			if n > 0 && n%4 == 0 {
+				b.WriteByte('-')
+			}
+			b.WriteByte(inviteCodeAlphabet[int(v)%len(inviteCodeAlphabet)])
+			n++
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,145 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeInviteCodeRepo is an in-memory InviteCodeRepository.
+type fakeInviteCodeRepo struct {
+	codes  map[string]*InviteCode
+	nextID int
+}
+
+func newFakeInviteCodeRepo() *fakeInviteCodeRepo {
+	return &fakeInviteCodeRepo{codes: map[string]*InviteCode{}}
+}
+
+func (r *fakeInviteCodeRepo) List(context.Context) ([]InviteCode, error) {
+	var out []InviteCode
+	for _, c := range r.codes {
+		out = append(out, *c)
+	}
+	return out, nil
+}
+
+func (r *fakeInviteCodeRepo) FindByCode(_ context.Context, code string) (*InviteCode, error) {
+	if c, ok := r.codes[code]; ok {
+		cp := *c
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeInviteCodeRepo) Create(_ context.Context, code *InviteCode) error {
+	r.nextID++
+	code.ID = r.nextID
+	cp := *code
+	r.codes[code.Code] = &cp
+	return nil
+}
+
+func (r *fakeInviteCodeRepo) Delete(_ context.Context, id int) (bool, error) {
+	for k, c := range r.codes {
+		if c.ID == id {
+			delete(r.codes, k)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeInviteCodeRepo) Redeem(_ context.Context, code string, now time.Time) (bool, error) {
+	c, ok := r.codes[code]
+	if !ok || !c.Usable(now) {
+		return false, nil
+	}
+	c.UseCount++
+	return true, nil
+}
+
+func TestInviteCodeService_Create(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    CreateInviteCodeInput
+		wantCode int // 0 = success
+	}{
+		{name: "generated", input: CreateInviteCodeInput{}},
+		{name: "custom", input: CreateInviteCodeInput{Code: "  summer-2026 ", MaxUses: 5, ExpiresInDays: 7}},
+		{name: "too short", input: CreateInviteCodeInput{Code: "abc"}, wantCode: 400},
+		{name: "bad characters", input: CreateInviteCodeInput{Code: "hello world"}, wantCode: 400},
+		{name: "negative uses", input: CreateInviteCodeInput{MaxUses: -1}, wantCode: 400},
+		{name: "expiry too far", input: CreateInviteCodeInput{ExpiresInDays: inviteCodeMaxExpires + 1}, wantCode: 400},
+		{name: "duplicate", input: CreateInviteCodeInput{Code: "SUMMER-2026"}, wantCode: 409},
+	}
+
+	svc := NewInviteCodeService(newFakeInviteCodeRepo())
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic, err := svc.Create(context.Background(), tc.input, "admin-1")
+			if tc.wantCode != 0 {
+				var appErr *apperror.AppError
+				if !errors.As(err, &appErr) || appErr.Code != tc.wantCode {
+					t.Fatalf("err = %v, want AppError %d", err, tc.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if tc.input.Code == "" && !regexp.MustCompile(`^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`).MatchString(ic.Code) {
+				t.Errorf("generated code %q has the wrong shape", ic.Code)
+			}
+			if tc.input.Code != "" && ic.Code != "SUMMER-2026" {
+				t.Errorf("custom code stored as %q, want SUMMER-2026", ic.Code)
+			}
+			if (tc.input.MaxUses > 0) != (ic.MaxUses != nil) || (tc.input.ExpiresInDays > 0) != (ic.ExpiresAt != nil) {
+				t.Errorf("limits not applied: max_uses=%v expires_at=%v", ic.MaxUses, ic.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestInviteCodeService_Redeem(t *testing.T) {
+	repo := newFakeInviteCodeRepo()
+	svc := NewInviteCodeService(repo).(*inviteCodeService)
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	if _, err := svc.Create(ctx, CreateInviteCodeInput{Code: "TWICE-ONLY", MaxUses: 2}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Create(ctx, CreateInviteCodeInput{Code: "ONE-DAY", ExpiresInDays: 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Matching ignores case and surrounding whitespace.
+	for i := 0; i < 2; i++ {
+		if ok, err := svc.RedeemInviteCode(ctx, " twice-only "); err != nil || !ok {
+			t.Fatalf("redeem %d = %v, %v; want true", i+1, ok, err)
+		}
+	}
+	if ok, _ := svc.RedeemInviteCode(ctx, "TWICE-ONLY"); ok {
+		t.Error("a code must not be redeemed past max_uses")
+	}
+	if svc.IsInviteCodeValid(ctx, "TWICE-ONLY") {
+		t.Error("a used-up code must not read as valid")
+	}
+
+	if !svc.IsInviteCodeValid(ctx, "one-day") {
+		t.Error("an unexpired code must read as valid")
+	}
+	now = now.Add(25 * time.Hour)
+	if ok, _ := svc.RedeemInviteCode(ctx, "ONE-DAY"); ok {
+		t.Error("an expired code must not be redeemed")
+	}
+	if ok, _ := svc.RedeemInviteCode(ctx, "NO-SUCH-CODE"); ok {
+		t.Error("an unknown code must not be redeemed")
+	}
+}
@@ -140,6 +140,11 @@ const (
 	// Values: "open" (default), "invite", "closed". Enforced in the auth service;
 	// the first-user-admin bootstrap always works regardless of mode.
 	KeyRegistrationMode = "auth.registration_mode"
+
+	// KeyAllowedEmailDomains restricts new accounts to email addresses at
+	// these domains (comma-separated, lowercase). Empty means any domain.
+	// Applies in every mode; the first-user bootstrap is exempt.
+	KeyAllowedEmailDomains = "auth.allowed_email_domains"
 )
 
 // Registration mode values for KeyRegistrationMode. Defined here as the canonical
@@ -147,7 +152,7 @@ const (
 // matching local constants (it must not import this plugin).
 const (
 	RegistrationOpen   = "open"   // Anyone may register (default — zero behavior change).
-	RegistrationInvite = "invite" // Only holders of a valid campaign invite may register.
+	RegistrationInvite = "invite" // Only holders of a campaign invite or an admin invite code may register.
 	RegistrationClosed = "closed" // No new registrations (except the first-user bootstrap).
 )
 
@@ -0,0 +1,338 @@
+package settings
+
+// runtime.go — runtime settings: config values an admin can edit from
+// /admin/runtime instead of through environment variables. A stored value
+// overrides its env default; zero or empty means "use the default".
+// RuntimeConfig keeps an in-memory snapshot so hot paths (rate limiters)
+// read it without a DB round-trip. The snapshot is reloaded on every write
+// and on a timer so other instances converge without a restart.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// Runtime setting keys in site_settings. The upload rate limit reuses
+// KeyRateLimitUploadsPerMin, which the storage settings page edits.
+const (
+	KeyBaseURL                      = "site.base_url"
+	KeyRateLimitLoginPerMin         = "ratelimit.login_per_min"
+	KeyRateLimitRegisterPerMin      = "ratelimit.register_per_min"
+	KeyRateLimitPasswordResetPerMin = "ratelimit.password_reset_per_min"
+	KeyRateLimitMediaServePerMin    = "ratelimit.media_serve_per_min"
+
+	KeyCaptchaProvider           = "auth.captcha_provider"
+	KeyCaptchaSiteKey            = "auth.captcha_site_key"
+	KeyCaptchaSecretKey          = "auth.captcha_secret_key"
+	KeyCaptchaLoginAfterFailures = "auth.captcha_login_after_failures"
+)
+
+// CAPTCHA providers for KeyCaptchaProvider. Empty turns the challenge off.
+// The auth plugin keeps matching local constants.
+const (
+	CaptchaOff       = ""
+	CaptchaHCaptcha  = "hcaptcha"
+	CaptchaTurnstile = "turnstile"
+	CaptchaPoW       = "pow" // Built-in proof-of-work; no third party, no keys.
+)
+
+// IsValidCaptchaProvider reports whether p is a known provider (or off).
+func IsValidCaptchaProvider(p string) bool {
+	return p == CaptchaOff || p == CaptchaHCaptcha || p == CaptchaTurnstile || p == CaptchaPoW
+}
+
+// DefaultCaptchaLoginAfterFailures is how many failed logins for an email
+// trigger the login challenge when no value is stored.
+const DefaultCaptchaLoginAfterFailures = 3
+
+// maxCaptchaLoginAfterFailures caps the threshold; the login throttle takes
+// over at 10 failures anyway.
+const maxCaptchaLoginAfterFailures = 10
+
+// maxRuntimeRateLimit caps admin-entered per-minute limits. Anything higher
+// is effectively no limit and usually a typo.
+const maxRuntimeRateLimit = 100000
+
+// runtimeReloadInterval is how often Start re-reads stored values, bounding
+// how long another instance's edit takes to apply here.
+const runtimeReloadInterval = 30 * time.Second
+
+// RuntimeDefaults are the env-derived values used when nothing is stored.
+type RuntimeDefaults struct {
+	BaseURL                 string `json:"base_url"`
+	LoginRatePerMin         int    `json:"login_rate_per_min"`
+	RegisterRatePerMin      int    `json:"register_rate_per_min"`
+	PasswordResetRatePerMin int    `json:"password_reset_rate_per_min"`
+	MediaServeRatePerMin    int    `json:"media_serve_rate_per_min"`
+	UploadRatePerMin        int    `json:"upload_rate_per_min"`
+}
+
+// RuntimeSettings holds the stored overrides. Zero rate limits and an empty
+// base URL mean the default applies.
+type RuntimeSettings struct {
+	// BaseURL overrides BASE_URL. Links and CORS read it at boot, so a change
+	// applies after the next restart.
+	BaseURL                 string `json:"base_url"`
+	LoginRatePerMin         int    `json:"login_rate_per_min"`
+	RegisterRatePerMin      int    `json:"register_rate_per_min"`
+	PasswordResetRatePerMin int    `json:"password_reset_rate_per_min"`
+	MediaServeRatePerMin    int    `json:"media_serve_rate_per_min"`
+	RegistrationMode        string `json:"registration_mode"`
+
+	// CAPTCHA on signup, password reset, and login after repeated failures.
+	// The secret is write-only: Redacted clears it for API responses, and an
+	// empty secret on update keeps the stored one.
+	CaptchaProvider           string `json:"captcha_provider"`
+	CaptchaSiteKey            string `json:"captcha_site_key"`
+	CaptchaSecretKey          string `json:"captcha_secret_key,omitempty"`
+	CaptchaSecretSet          bool   `json:"captcha_secret_set"`
+	CaptchaLoginAfterFailures int    `json:"captcha_login_after_failures"`
+
+	// UploadRatePerMin is read-only here; the storage settings page owns it.
+	UploadRatePerMin int `json:"upload_rate_per_min"`
+}
+
+// Redacted returns a copy safe to send to the browser.
+func (rs RuntimeSettings) Redacted() RuntimeSettings {
+	rs.CaptchaSecretKey = ""
+	return rs
+}
+
+// NormalizeBaseURL validates an admin-entered base URL: empty, or an absolute
+// http(s) URL with a host and nothing after the path. The trailing slash is
+// trimmed to match how BASE_URL is read from the environment.
+func NormalizeBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", apperror.NewBadRequest("base URL must be an absolute http:// or https:// URL")
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", apperror.NewBadRequest("base URL cannot contain credentials, a query, or a fragment")
+	}
+	return strings.TrimRight(raw, "/"), nil
+}
+
+// validate checks and normalizes the editable fields in place.
+func (rs *RuntimeSettings) validate() error {
+	baseURL, err := NormalizeBaseURL(rs.BaseURL)
+	if err != nil {
+		return err
+	}
+	rs.BaseURL = baseURL
+
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"login", rs.LoginRatePerMin},
+		{"registration", rs.RegisterRatePerMin},
+		{"password reset", rs.PasswordResetRatePerMin},
+		{"media serve", rs.MediaServeRatePerMin},
+	}
+	for _, l := range limits {
+		if l.value < 0 || l.value > maxRuntimeRateLimit {
+			return apperror.NewBadRequest(fmt.Sprintf("%s rate limit must be between 0 and %d", l.name, maxRuntimeRateLimit))
+		}
+	}
+
+	if !IsValidRegistrationMode(rs.RegistrationMode) {
+		return apperror.NewBadRequest("registration mode must be open, invite, or closed")
+	}
+
+	rs.CaptchaSiteKey = strings.TrimSpace(rs.CaptchaSiteKey)
+	rs.CaptchaSecretKey = strings.TrimSpace(rs.CaptchaSecretKey)
+	if !IsValidCaptchaProvider(rs.CaptchaProvider) {
+		return apperror.NewBadRequest("CAPTCHA provider must be hcaptcha, turnstile, pow, or empty")
+	}
+	if rs.CaptchaProvider == CaptchaHCaptcha || rs.CaptchaProvider == CaptchaTurnstile {
+		if rs.CaptchaSiteKey == "" || (rs.CaptchaSecretKey == "" && !rs.CaptchaSecretSet) {
+			return apperror.NewBadRequest("the CAPTCHA provider needs both a site key and a secret key")
+		}
+	}
+	if len(rs.CaptchaSiteKey) > 200 || len(rs.CaptchaSecretKey) > 200 {
+		return apperror.NewBadRequest("CAPTCHA keys must be at most 200 characters")
+	}
+	if rs.CaptchaLoginAfterFailures < 0 || rs.CaptchaLoginAfterFailures > maxCaptchaLoginAfterFailures {
+		return apperror.NewBadRequest(fmt.Sprintf("CAPTCHA login threshold must be between 0 and %d", maxCaptchaLoginAfterFailures))
+	}
+	return nil
+}
+
+// RuntimeConfig serves runtime settings from an in-memory snapshot.
+type RuntimeConfig struct {
+	repo     SettingsRepository
+	defaults RuntimeDefaults
+	current  atomic.Pointer[RuntimeSettings]
+	branding atomic.Pointer[Branding] // See branding.go.
+}
+
+// NewRuntimeConfig creates a runtime config over the settings store. Call
+// Reload before serving so the first requests see stored values; until
+// then every getter returns its default.
+func NewRuntimeConfig(repo SettingsRepository, defaults RuntimeDefaults) *RuntimeConfig {
+	rc := &RuntimeConfig{repo: repo, defaults: defaults}
+	rc.current.Store(&RuntimeSettings{RegistrationMode: RegistrationOpen})
+	return rc
+}
+
+// Defaults returns the env-derived fallbacks.
+func (rc *RuntimeConfig) Defaults() RuntimeDefaults {
+	return rc.defaults
+}
+
+// Current returns the stored overrides from the last reload.
+func (rc *RuntimeConfig) Current() RuntimeSettings {
+	return *rc.current.Load()
+}
+
+// Reload re-reads stored values into the snapshot. Unparseable values are
+// treated as unset rather than failing, matching GetStorageLimits.
+func (rc *RuntimeConfig) Reload(ctx context.Context) error {
+	all, err := rc.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	rs := &RuntimeSettings{
+		LoginRatePerMin:         max(parseInt(all[KeyRateLimitLoginPerMin], 0), 0),
+		RegisterRatePerMin:      max(parseInt(all[KeyRateLimitRegisterPerMin], 0), 0),
+		PasswordResetRatePerMin: max(parseInt(all[KeyRateLimitPasswordResetPerMin], 0), 0),
+		MediaServeRatePerMin:    max(parseInt(all[KeyRateLimitMediaServePerMin], 0), 0),
+		UploadRatePerMin:        max(parseInt(all[KeyRateLimitUploadsPerMin], 0), 0),
+		RegistrationMode:        all[KeyRegistrationMode],
+
+		CaptchaProvider:           all[KeyCaptchaProvider],
+		CaptchaSiteKey:            all[KeyCaptchaSiteKey],
+		CaptchaSecretKey:          all[KeyCaptchaSecretKey],
+		CaptchaSecretSet:          all[KeyCaptchaSecretKey] != "",
+		CaptchaLoginAfterFailures: min(max(parseInt(all[KeyCaptchaLoginAfterFailures], 0), 0), maxCaptchaLoginAfterFailures),
+	}
+	if baseURL, err := NormalizeBaseURL(all[KeyBaseURL]); err == nil {
+		rs.BaseURL = baseURL
+	}
+	if !IsValidRegistrationMode(rs.RegistrationMode) {
+		rs.RegistrationMode = RegistrationOpen
+	}
+	if !IsValidCaptchaProvider(rs.CaptchaProvider) {
+		rs.CaptchaProvider = CaptchaOff
+	}
+	rc.current.Store(rs)
+	rc.branding.Store(parseBranding(all))
+	return nil
+}
+
+// Update validates and persists the editable settings, then reloads so the
+// change applies immediately on this instance.
+func (rc *RuntimeConfig) Update(ctx context.Context, rs RuntimeSettings) (RuntimeSettings, error) {
+	// An empty secret keeps the stored one; the form never receives it.
+	stored := rc.Current()
+	rs.CaptchaSecretSet = stored.CaptchaSecretSet
+	if rs.CaptchaSecretKey == "" {
+		rs.CaptchaSecretKey = stored.CaptchaSecretKey
+	}
+	if err := rs.validate(); err != nil {
+		return RuntimeSettings{}, err
+	}
+	values := map[string]string{
+		KeyBaseURL:                      rs.BaseURL,
+		KeyRateLimitLoginPerMin:         strconv.Itoa(rs.LoginRatePerMin),
+		KeyRateLimitRegisterPerMin:      strconv.Itoa(rs.RegisterRatePerMin),
+		KeyRateLimitPasswordResetPerMin: strconv.Itoa(rs.PasswordResetRatePerMin),
+		KeyRateLimitMediaServePerMin:    strconv.Itoa(rs.MediaServeRatePerMin),
+		KeyRegistrationMode:             rs.RegistrationMode,
+		KeyCaptchaProvider:              rs.CaptchaProvider,
+		KeyCaptchaSiteKey:               rs.CaptchaSiteKey,
+		KeyCaptchaSecretKey:             rs.CaptchaSecretKey,
+		KeyCaptchaLoginAfterFailures:    strconv.Itoa(rs.CaptchaLoginAfterFailures),
+	}
+	for key, value := range values {
+		if err := rc.repo.Set(ctx, key, value); err != nil {
+			return RuntimeSettings{}, apperror.NewInternal(fmt.Errorf("persisting %s: %w", key, err))
+		}
+	}
+	if err := rc.Reload(ctx); err != nil {
+		return RuntimeSettings{}, apperror.NewInternal(err)
+	}
+	return rc.Current(), nil
+}
+
+// Start reloads the snapshot periodically until ctx is cancelled, so edits
+// made on another instance (or on the storage page) reach this one.
+func (rc *RuntimeConfig) Start(ctx context.Context) {
+	ticker := time.NewTicker(runtimeReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloadCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			if err := rc.Reload(reloadCtx); err != nil {
+				slog.Warn("runtime settings: reload failed", slog.Any("error", err))
+			}
+			cancel()
+		}
+	}
+}
+
+// BaseURL returns the stored base URL override, or the default.
+func (rc *RuntimeConfig) BaseURL() string {
+	if v := rc.current.Load().BaseURL; v != "" {
+		return v
+	}
+	return rc.defaults.BaseURL
+}
+
+// LoginRateLimit returns the per-minute login cap.
+func (rc *RuntimeConfig) LoginRateLimit() int {
+	return rateOrDefault(rc.current.Load().LoginRatePerMin, rc.defaults.LoginRatePerMin)
+}
+
+// RegisterRateLimit returns the per-minute registration cap.
+func (rc *RuntimeConfig) RegisterRateLimit() int {
+	return rateOrDefault(rc.current.Load().RegisterRatePerMin, rc.defaults.RegisterRatePerMin)
+}
+
+// PasswordResetRateLimit returns the per-minute password reset cap.
+func (rc *RuntimeConfig) PasswordResetRateLimit() int {
+	return rateOrDefault(rc.current.Load().PasswordResetRatePerMin, rc.defaults.PasswordResetRatePerMin)
+}
+
+// MediaServeRateLimit returns the per-minute media serve cap.
+func (rc *RuntimeConfig) MediaServeRateLimit() int {
+	return rateOrDefault(rc.current.Load().MediaServeRatePerMin, rc.defaults.MediaServeRatePerMin)
+}
+
+// UploadRateLimit returns the per-minute upload cap.
+func (rc *RuntimeConfig) UploadRateLimit() int {
+	return rateOrDefault(rc.current.Load().UploadRatePerMin, rc.defaults.UploadRatePerMin)
+}
+
+// CaptchaLoginAfterFailures returns how many failed logins for an email
+// trigger the login challenge.
+func (rc *RuntimeConfig) CaptchaLoginAfterFailures() int {
+	if v := rc.current.Load().CaptchaLoginAfterFailures; v > 0 {
+		return v
+	}
+	return DefaultCaptchaLoginAfterFailures
+}
+
+// rateOrDefault picks the stored limit, then the default, and never returns
+// less than 1: the rate limiter treats its cap as a positive count.
+func rateOrDefault(stored, fallback int) int {
+	if stored > 0 {
+		return stored
+	}
+	return max(fallback, 1)
+}
@@ -0,0 +1,174 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// newMapSettingsRepo returns a mock repo backed by an in-memory map.
+func newMapSettingsRepo(store map[string]string) *mockSettingsRepo {
+	return &mockSettingsRepo{
+		getAllFn: func(context.Context) (map[string]string, error) {
+			out := make(map[string]string, len(store))
+			for k, v := range store {
+				out[k] = v
+			}
+			return out, nil
+		},
+		setFn: func(_ context.Context, key, value string) error {
+			store[key] = value
+			return nil
+		},
+	}
+}
+
+func TestNormalizeBaseURL(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "  https://chronicle.example.com/  ", want: "https://chronicle.example.com"},
+		{in: "http://localhost:8080", want: "http://localhost:8080"},
+		{in: "https://example.com/chronicle", want: "https://example.com/chronicle"},
+		{in: "chronicle.example.com", wantErr: true},
+		{in: "ftp://example.com", wantErr: true},
+		{in: "https://", wantErr: true},
+		{in: "https://example.com/?a=1", wantErr: true},
+		{in: "https://user:pw@example.com", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := NormalizeBaseURL(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeBaseURL(%q) = %q, want error", tc.in, got)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("NormalizeBaseURL(%q) = %q, %v; want %q", tc.in, got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestRuntimeConfig_Defaults(t *testing.T) {
+	rc := NewRuntimeConfig(newMapSettingsRepo(map[string]string{
+		KeyRateLimitLoginPerMin: "garbage",
+		KeyRegistrationMode:     "bogus",
+	}), RuntimeDefaults{BaseURL: "http://env", LoginRatePerMin: 10, RegisterRatePerMin: 5})
+
+	// Before the first reload and after reloading bad values, defaults apply.
+	for _, phase := range []string{"before reload", "after reload"} {
+		if phase == "after reload" {
+			if err := rc.Reload(context.Background()); err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+		}
+		if got := rc.LoginRateLimit(); got != 10 {
+			t.Errorf("%s: LoginRateLimit = %d, want 10", phase, got)
+		}
+		if got := rc.BaseURL(); got != "http://env" {
+			t.Errorf("%s: BaseURL = %q, want the env value", phase, got)
+		}
+		if got := rc.Current().RegistrationMode; got != RegistrationOpen {
+			t.Errorf("%s: RegistrationMode = %q, want open", phase, got)
+		}
+	}
+
+	// A zero default still yields a usable cap.
+	if got := rc.MediaServeRateLimit(); got != 1 {
+		t.Errorf("MediaServeRateLimit = %d, want 1", got)
+	}
+}
+
+func TestRuntimeConfig_Update(t *testing.T) {
+	store := map[string]string{KeyRateLimitUploadsPerMin: "45"}
+	rc := NewRuntimeConfig(newMapSettingsRepo(store), RuntimeDefaults{LoginRatePerMin: 10, UploadRatePerMin: 30})
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		in       RuntimeSettings
+		wantCode int
+	}{
+		{name: "negative limit", in: RuntimeSettings{LoginRatePerMin: -1, RegistrationMode: RegistrationOpen}, wantCode: 400},
+		{name: "limit too high", in: RuntimeSettings{MediaServeRatePerMin: maxRuntimeRateLimit + 1, RegistrationMode: RegistrationOpen}, wantCode: 400},
+		{name: "bad mode", in: RuntimeSettings{RegistrationMode: "maybe"}, wantCode: 400},
+		{name: "bad base URL", in: RuntimeSettings{BaseURL: "not a url", RegistrationMode: RegistrationOpen}, wantCode: 400},
+		{name: "bad captcha provider", in: RuntimeSettings{RegistrationMode: RegistrationOpen, CaptchaProvider: "recaptcha"}, wantCode: 400},
+		{name: "captcha without keys", in: RuntimeSettings{RegistrationMode: RegistrationOpen, CaptchaProvider: CaptchaTurnstile, CaptchaSiteKey: "site"}, wantCode: 400},
+		{name: "captcha threshold too high", in: RuntimeSettings{RegistrationMode: RegistrationOpen, CaptchaLoginAfterFailures: maxCaptchaLoginAfterFailures + 1}, wantCode: 400},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := rc.Update(ctx, tc.in)
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) || appErr.Code != tc.wantCode {
+				t.Errorf("err = %v, want AppError %d", err, tc.wantCode)
+			}
+		})
+	}
+
+	got, err := rc.Update(ctx, RuntimeSettings{
+		BaseURL:          "https://new.example.com/",
+		LoginRatePerMin:  25,
+		RegistrationMode: RegistrationInvite,
+		UploadRatePerMin: 999, // Owned by the storage page; ignored here.
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.BaseURL != "https://new.example.com" || rc.LoginRateLimit() != 25 {
+		t.Errorf("Update did not apply: %+v", got)
+	}
+	if store[KeyRegistrationMode] != RegistrationInvite {
+		t.Errorf("registration mode stored as %q", store[KeyRegistrationMode])
+	}
+	if rc.UploadRateLimit() != 45 || store[KeyRateLimitUploadsPerMin] != "45" {
+		t.Errorf("upload rate = %d (stored %q), want the storage page's 45", rc.UploadRateLimit(), store[KeyRateLimitUploadsPerMin])
+	}
+}
+
+func TestRuntimeConfig_CaptchaSecret(t *testing.T) {
+	store := map[string]string{}
+	rc := NewRuntimeConfig(newMapSettingsRepo(store), RuntimeDefaults{})
+	ctx := context.Background()
+
+	if _, err := rc.Update(ctx, RuntimeSettings{
+		RegistrationMode: RegistrationOpen,
+		CaptchaProvider:  CaptchaHCaptcha,
+		CaptchaSiteKey:   "site-key",
+		CaptchaSecretKey: "secret-key",
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if rc.CaptchaLoginAfterFailures() != DefaultCaptchaLoginAfterFailures {
+		t.Errorf("threshold = %d, want the default", rc.CaptchaLoginAfterFailures())
+	}
+
+	// The form never sends the secret back; an empty one keeps it.
+	got, err := rc.Update(ctx, RuntimeSettings{
+		RegistrationMode:          RegistrationOpen,
+		CaptchaProvider:           CaptchaHCaptcha,
+		CaptchaSiteKey:            "site-key-2",
+		CaptchaLoginAfterFailures: 5,
+	})
+	if err != nil {
+		t.Fatalf("Update without secret: %v", err)
+	}
+	if store[KeyCaptchaSecretKey] != "secret-key" || !got.CaptchaSecretSet {
+		t.Errorf("stored secret = %q, set = %v; want it kept", store[KeyCaptchaSecretKey], got.CaptchaSecretSet)
+	}
+	if got.Redacted().CaptchaSecretKey != "" {
+		t.Error("Redacted must clear the secret")
+	}
+	if rc.CaptchaLoginAfterFailures() != 5 {
+		t.Errorf("threshold = %d, want 5", rc.CaptchaLoginAfterFailures())
+	}
+}
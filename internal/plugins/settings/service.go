@@ -76,6 +76,14 @@ type SettingsService interface {
 
 	// UpdateRegistrationMode validates and persists the site registration mode.
 	UpdateRegistrationMode(ctx context.Context, mode string) error
+
+	// GetAllowedEmailDomains returns the signup domain allowlist. Empty means
+	// any domain may register.
+	GetAllowedEmailDomains(ctx context.Context) ([]string, error)
+
+	// UpdateAllowedEmailDomains validates and persists the signup domain
+	// allowlist. An empty list removes the restriction.
+	UpdateAllowedEmailDomains(ctx context.Context, domains []string) error
 }
 
 // settingsService implements SettingsService.
@@ -372,6 +380,82 @@ func (s *settingsService) UpdateRegistrationMode(ctx context.Context, mode strin
 	return s.repo.Set(ctx, KeyRegistrationMode, mode)
 }
 
+// GetAllowedEmailDomains returns the signup domain allowlist. Unset means no
+// restriction. A read error is returned as-is so the auth gate can fail
+// closed rather than silently admitting every domain.
+func (s *settingsService) GetAllowedEmailDomains(ctx context.Context) ([]string, error) {
+	raw, err := s.repo.Get(ctx, KeyAllowedEmailDomains)
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == 404 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var domains []string
+	for _, d := range strings.Split(raw, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains, nil
+}
+
+// UpdateAllowedEmailDomains validates and persists the signup domain allowlist.
+func (s *settingsService) UpdateAllowedEmailDomains(ctx context.Context, domains []string) error {
+	cleaned, err := NormalizeEmailDomains(domains)
+	if err != nil {
+		return err
+	}
+	return s.repo.Set(ctx, KeyAllowedEmailDomains, strings.Join(cleaned, ","))
+}
+
+// maxAllowedEmailDomains bounds the allowlist so the setting stays a short
+// list an admin can read, not a blocklist in disguise.
+const maxAllowedEmailDomains = 50
+
+// NormalizeEmailDomains lowercases, de-duplicates, and validates admin-entered
+// domains. A leading "@" is accepted ("@example.com") since that is how people
+// tend to write them. Subdomains are not implied: "example.com" does not admit
+// "mail.example.com".
+func NormalizeEmailDomains(domains []string) ([]string, error) {
+	seen := make(map[string]bool, len(domains))
+	var cleaned []string
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" || seen[d] {
+			continue
+		}
+		if !isValidDomain(d) {
+			return nil, apperror.NewBadRequest(fmt.Sprintf("%q is not a valid email domain", d))
+		}
+		seen[d] = true
+		cleaned = append(cleaned, d)
+	}
+	if len(cleaned) > maxAllowedEmailDomains {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("at most %d email domains can be allowed", maxAllowedEmailDomains))
+	}
+	return cleaned, nil
+}
+
+// isValidDomain checks for a dotted hostname of letters, digits, and hyphens.
+func isValidDomain(d string) bool {
+	if len(d) > 253 || !strings.Contains(d, ".") {
+		return false
+	}
+	for _, label := range strings.Split(d, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // --- CORS Origin Management ---
 
 // GetCORSOrigins reads the comma-separated CORS origin list from site_settings.
@@ -3,6 +3,7 @@ package settings
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -753,3 +754,37 @@ func TestCampaignStorageLimit_HasActiveBypass(t *testing.T) {
 }
 
 func timePtr(t time.Time) *time.Time { return &t }
+
+func TestNormalizeEmailDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", in: nil, want: nil},
+		{name: "cleans and dedupes", in: []string{" Example.COM ", "@example.com", "", "guild.org"}, want: []string{"example.com", "guild.org"}},
+		{name: "subdomain kept as-is", in: []string{"mail.example.com"}, want: []string{"mail.example.com"}},
+		{name: "no dot", in: []string{"localhost"}, wantErr: true},
+		{name: "full address", in: []string{"me@example.com"}, wantErr: true},
+		{name: "leading hyphen", in: []string{"-bad.com"}, wantErr: true},
+		{name: "wildcard", in: []string{"*.example.com"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeEmailDomains(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeEmailDomains(%q) = %q, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package smtp
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
@@ -13,6 +14,7 @@ import (
 // Admin-only -- all routes require site admin middleware.
 type Handler struct {
 	service SMTPService
+	outbox  MailOutbox // nil hides the outbox panel
 }
 
 // NewHandler creates a new SMTP handler.
@@ -20,6 +22,14 @@ func NewHandler(service SMTPService) *Handler {
 	return &Handler{service: service}
 }
 
+// SetOutbox enables the mail outbox panel on the settings page.
+func (h *Handler) SetOutbox(outbox MailOutbox) {
+	h.outbox = outbox
+}
+
+// outboxPanelLimit is how many recent messages the outbox panel lists.
+const outboxPanelLimit = 25
+
 // Settings renders the SMTP settings page (GET /admin/smtp).
 func (h *Handler) Settings(c echo.Context) error {
 	settings, err := h.service.GetSettings(c.Request().Context())
@@ -102,3 +112,39 @@ func (h *Handler) SendTestEmail(c echo.Context) error {
 	}
 	return middleware.Render(c, http.StatusOK, SMTPSettingsPage(settings, csrfToken, ""))
 }
+
+// Outbox renders the mail outbox panel (GET /admin/smtp/outbox). Loaded by
+// the settings page via HTMX so the form handlers don't need the queue.
+func (h *Handler) Outbox(c echo.Context) error {
+	if h.outbox == nil {
+		return apperror.NewNotFound("mail outbox is not enabled")
+	}
+	return h.renderOutbox(c, "")
+}
+
+// RetryOutboxMessage requeues a failed message
+// (POST /admin/smtp/outbox/:id/retry) and re-renders the panel.
+func (h *Handler) RetryOutboxMessage(c echo.Context) error {
+	if h.outbox == nil {
+		return apperror.NewNotFound("mail outbox is not enabled")
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return apperror.NewBadRequest("invalid message ID")
+	}
+	errMsg := ""
+	if err := h.outbox.Retry(c.Request().Context(), id); err != nil {
+		errMsg = apperror.UserMessage(err, "retry failed")
+	}
+	return h.renderOutbox(c, errMsg)
+}
+
+// renderOutbox renders the outbox panel with an optional error banner.
+func (h *Handler) renderOutbox(c echo.Context, errMsg string) error {
+	msgs, stats, err := h.outbox.Recent(c.Request().Context(), outboxPanelLimit)
+	if err != nil {
+		return err
+	}
+	csrfToken := middleware.GetCSRFToken(c)
+	return middleware.Render(c, http.StatusOK, OutboxPanel(msgs, stats, csrfToken, errMsg))
+}
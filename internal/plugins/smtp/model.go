@@ -63,6 +63,36 @@ type UpdateSMTPRequest struct {
 	Enabled     bool   `json:"enabled" form:"enabled"`
 }
 
+// Outbox statuses. A message is pending until delivered (sent) or until it
+// runs out of attempts (failed).
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"
+)
+
+// OutboxMessage is one queued email in mail_outbox.
+type OutboxMessage struct {
+	ID            int64      `json:"id"`
+	To            []string   `json:"to"`
+	Subject       string     `json:"subject"`
+	TextBody      string     `json:"-"`
+	HTMLBody      string     `json:"-"` // Empty for plain-text mail.
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// OutboxStats counts outbox rows by status for the admin page.
+type OutboxStats struct {
+	Pending int `json:"pending"`
+	Sent    int `json:"sent"`
+	Failed  int `json:"failed"`
+}
+
 // Mail represents an email message to be sent.
 type Mail struct {
 	To      []string
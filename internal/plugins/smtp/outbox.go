@@ -0,0 +1,284 @@
+package smtp
+
+// outbox.go — queued mail delivery. MailOutbox implements MailService, so
+// the plugins that send mail (auth, campaigns, sessions, ...) are wired to it
+// instead of the SMTP service: SendMail/SendHTMLMail store the message in
+// mail_outbox and return, and the worker started with Start delivers it,
+// retrying transient SMTP failures with backoff. The admin "send test email"
+// action still sends directly so the admin sees the real SMTP error.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+const (
+	// outboxPollInterval is the worker's idle poll; enqueues wake it early.
+	outboxPollInterval = 15 * time.Second
+
+	// outboxBatchSize bounds how many messages one pass delivers.
+	outboxBatchSize = 20
+
+	// outboxLease is how long a claimed message stays hidden from other
+	// workers. Longer than the SMTP dial+send timeouts, so a slow send isn't
+	// delivered twice, but short enough that a crash mid-send retries soon.
+	outboxLease = 5 * time.Minute
+
+	// outboxMaxAttempts is how many deliveries are tried before giving up.
+	outboxMaxAttempts = 6
+
+	// outboxRetention is how long sent and failed messages are kept for the
+	// admin view.
+	outboxRetention = 30 * 24 * time.Hour
+
+	// outboxPurgeInterval is how often finished messages are purged.
+	outboxPurgeInterval = time.Hour
+
+	// outboxMaxSubjectLength matches mail_outbox.subject.
+	outboxMaxSubjectLength = 500
+
+	// outboxMaxErrorLength keeps last_error readable on the admin page.
+	outboxMaxErrorLength = 1000
+)
+
+// outboxBackoff is the delay before each retry; the last entry repeats.
+var outboxBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// MailOutbox is a MailService that queues mail for background delivery,
+// plus the admin view of the queue.
+type MailOutbox interface {
+	MailService
+
+	// Start runs the delivery worker until ctx is cancelled.
+	Start(ctx context.Context)
+
+	// Recent returns the newest queued messages and the per-status counts.
+	Recent(ctx context.Context, limit int) ([]OutboxMessage, OutboxStats, error)
+
+	// Retry puts a failed message back in the queue.
+	Retry(ctx context.Context, id int64) error
+}
+
+// mailOutbox implements MailOutbox.
+type mailOutbox struct {
+	repo   OutboxRepository
+	sender MailService // Sends directly over SMTP.
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+// NewMailOutbox creates a mail outbox that delivers through sender.
+func NewMailOutbox(repo OutboxRepository, sender MailService) MailOutbox {
+	return &mailOutbox{
+		repo:   repo,
+		sender: sender,
+		wake:   make(chan struct{}, 1),
+		now:    func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// IsConfigured reports whether the underlying SMTP settings can send.
+// Callers use it to decide whether to enqueue at all.
+func (o *mailOutbox) IsConfigured(ctx context.Context) bool {
+	return o.sender.IsConfigured(ctx)
+}
+
+// SendMail queues a plain-text email.
+func (o *mailOutbox) SendMail(ctx context.Context, to []string, subject, body string) error {
+	return o.enqueue(ctx, to, subject, body, "")
+}
+
+// SendHTMLMail queues a multipart email with an HTML part.
+func (o *mailOutbox) SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error {
+	return o.enqueue(ctx, to, subject, plainBody, htmlBody)
+}
+
+// enqueue validates and stores a message, then wakes the worker.
+func (o *mailOutbox) enqueue(ctx context.Context, to []string, subject, textBody, htmlBody string) error {
+	var recipients []string
+	for _, addr := range to {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return apperror.NewBadRequest(fmt.Sprintf("invalid recipient address %q", addr))
+		}
+		recipients = append(recipients, addr)
+	}
+	if len(recipients) == 0 {
+		return apperror.NewBadRequest("at least one recipient is required")
+	}
+
+	now := o.now()
+	msg := &OutboxMessage{
+		To:            recipients,
+		Subject:       truncateRunes(subject, outboxMaxSubjectLength),
+		TextBody:      textBody,
+		HTMLBody:      htmlBody,
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := o.repo.Enqueue(ctx, msg); err != nil {
+		return apperror.NewInternal(err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default: // A wake-up is already pending.
+	}
+	return nil
+}
+
+// Start delivers due mail until ctx is cancelled.
+func (o *mailOutbox) Start(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	var lastPurge time.Time
+
+	for {
+		o.deliverDue(ctx)
+		if o.now().Sub(lastPurge) >= outboxPurgeInterval {
+			lastPurge = o.now()
+			if n, err := o.repo.PurgeFinishedBefore(ctx, lastPurge.Add(-outboxRetention)); err != nil {
+				slog.Warn("mail outbox: purge failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("mail outbox: purged finished messages", slog.Int64("count", n))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// deliverDue sends one batch of due messages. Returns how many were
+// attempted.
+func (o *mailOutbox) deliverDue(ctx context.Context) int {
+	// With SMTP disabled every attempt would fail; leave the queue alone so
+	// messages don't burn their attempts while an admin fixes the settings.
+	if !o.sender.IsConfigured(ctx) {
+		return 0
+	}
+
+	due, err := o.repo.Due(ctx, o.now(), outboxBatchSize)
+	if err != nil {
+		slog.Warn("mail outbox: loading due messages failed", slog.Any("error", err))
+		return 0
+	}
+
+	attempted := 0
+	for _, msg := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, err := o.repo.Claim(ctx, msg.ID, msg.Attempts, o.now().Add(outboxLease))
+		if err != nil {
+			slog.Warn("mail outbox: claim failed", slog.Int64("id", msg.ID), slog.Any("error", err))
+			continue
+		}
+		if !claimed {
+			continue // Another instance took it.
+		}
+		attempted++
+		o.deliver(ctx, msg, msg.Attempts+1)
+	}
+	return attempted
+}
+
+// deliver sends one claimed message and records the outcome. attempt is
+// the 1-based attempt number just claimed.
+func (o *mailOutbox) deliver(ctx context.Context, msg OutboxMessage, attempt int) {
+	var err error
+	if msg.HTMLBody != "" {
+		err = o.sender.SendHTMLMail(ctx, msg.To, msg.Subject, msg.TextBody, msg.HTMLBody)
+	} else {
+		err = o.sender.SendMail(ctx, msg.To, msg.Subject, msg.TextBody)
+	}
+
+	if err == nil {
+		if err := o.repo.MarkSent(ctx, msg.ID, o.now()); err != nil {
+			slog.Warn("mail outbox: recording delivery failed", slog.Int64("id", msg.ID), slog.Any("error", err))
+		}
+		return
+	}
+
+	errMsg := truncateRunes(err.Error(), outboxMaxErrorLength)
+	if attempt >= outboxMaxAttempts {
+		slog.Error("mail outbox: giving up on message",
+			slog.Int64("id", msg.ID), slog.Int("attempts", attempt), slog.String("error", errMsg))
+		if err := o.repo.MarkFailed(ctx, msg.ID, errMsg); err != nil {
+			slog.Warn("mail outbox: recording failure failed", slog.Int64("id", msg.ID), slog.Any("error", err))
+		}
+		return
+	}
+
+	next := o.now().Add(retryDelay(attempt))
+	slog.Warn("mail outbox: delivery failed, will retry",
+		slog.Int64("id", msg.ID), slog.Int("attempt", attempt),
+		slog.Time("next_attempt_at", next), slog.String("error", errMsg))
+	if err := o.repo.MarkRetry(ctx, msg.ID, next, errMsg); err != nil {
+		slog.Warn("mail outbox: scheduling retry failed", slog.Int64("id", msg.ID), slog.Any("error", err))
+	}
+}
+
+// retryDelay is the wait after the given (1-based) failed attempt.
+func retryDelay(attempt int) time.Duration {
+	i := min(max(attempt-1, 0), len(outboxBackoff)-1)
+	return outboxBackoff[i]
+}
+
+// Recent returns the newest messages and counts for the admin page.
+func (o *mailOutbox) Recent(ctx context.Context, limit int) ([]OutboxMessage, OutboxStats, error) {
+	msgs, err := o.repo.Recent(ctx, limit)
+	if err != nil {
+		return nil, OutboxStats{}, apperror.NewInternal(err)
+	}
+	stats, err := o.repo.Stats(ctx)
+	if err != nil {
+		return nil, OutboxStats{}, apperror.NewInternal(err)
+	}
+	return msgs, stats, nil
+}
+
+// Retry requeues a failed message and wakes the worker.
+func (o *mailOutbox) Retry(ctx context.Context, id int64) error {
+	ok, err := o.repo.Requeue(ctx, id, o.now())
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
+	if !ok {
+		return apperror.NewNotFound("no failed message with that ID")
+	}
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// truncateRunes cuts s to at most n runes.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
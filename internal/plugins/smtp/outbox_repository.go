@@ -0,0 +1,236 @@
+package smtp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OutboxRepository handles database operations for the mail outbox.
+type OutboxRepository interface {
+	// Enqueue inserts a pending message and sets its ID.
+	Enqueue(ctx context.Context, msg *OutboxMessage) error
+
+	// Due returns pending messages whose next attempt is at or before now,
+	// oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error)
+
+	// Claim takes a due message for delivery: it bumps attempts and pushes
+	// next_attempt_at to leaseUntil, so a worker that dies mid-send leaves
+	// the message to be retried later. Returns false if another worker
+	// claimed it first (attempts no longer matches).
+	Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error)
+
+	// MarkSent records a successful delivery.
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+
+	// MarkRetry schedules another attempt after a failure.
+	MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+
+	// MarkFailed gives up on a message.
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+
+	// Requeue resets a failed message to pending with a fresh attempt count.
+	// Returns false if the message doesn't exist or isn't failed.
+	Requeue(ctx context.Context, id int64, now time.Time) (bool, error)
+
+	// Recent returns the newest messages (without bodies) for the admin page.
+	Recent(ctx context.Context, limit int) ([]OutboxMessage, error)
+
+	// Stats counts messages by status.
+	Stats(ctx context.Context) (OutboxStats, error)
+
+	// PurgeFinishedBefore deletes sent and failed messages created before
+	// the cutoff. Pending messages are never purged.
+	PurgeFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// outboxRepository implements OutboxRepository with MariaDB.
+type outboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new mail outbox repository.
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Enqueue inserts a pending message.
+func (r *outboxRepository) Enqueue(ctx context.Context, msg *OutboxMessage) error {
+	recipients, err := json.Marshal(msg.To)
+	if err != nil {
+		return fmt.Errorf("encoding recipients: %w", err)
+	}
+	var htmlBody any
+	if msg.HTMLBody != "" {
+		htmlBody = msg.HTMLBody
+	}
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO mail_outbox (recipients, subject, text_body, html_body, status, next_attempt_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		recipients, msg.Subject, msg.TextBody, htmlBody, OutboxPending, msg.NextAttemptAt, msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("enqueueing mail: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("reading outbox id: %w", err)
+	}
+	msg.ID = id
+	return nil
+}
+
+// Due returns pending messages that are ready to send.
+func (r *outboxRepository) Due(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, recipients, subject, text_body, COALESCE(html_body, ''), status, attempts,
+		        COALESCE(last_error, ''), next_attempt_at, created_at, sent_at
+		 FROM mail_outbox
+		 WHERE status = ? AND next_attempt_at <= ?
+		 ORDER BY next_attempt_at, id
+		 LIMIT ?`, OutboxPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying due mail: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OutboxMessage
+	for rows.Next() {
+		var m OutboxMessage
+		var recipients []byte
+		if err := rows.Scan(&m.ID, &recipients, &m.Subject, &m.TextBody, &m.HTMLBody, &m.Status,
+			&m.Attempts, &m.LastError, &m.NextAttemptAt, &m.CreatedAt, &m.SentAt); err != nil {
+			return nil, fmt.Errorf("scanning outbox row: %w", err)
+		}
+		if err := json.Unmarshal(recipients, &m.To); err != nil {
+			return nil, fmt.Errorf("decoding recipients for outbox %d: %w", m.ID, err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// Claim leases a message for one delivery attempt.
+func (r *outboxRepository) Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE mail_outbox SET attempts = attempts + 1, next_attempt_at = ?
+		 WHERE id = ? AND status = ? AND attempts = ?`,
+		leaseUntil, id, OutboxPending, attempts)
+	if err != nil {
+		return false, fmt.Errorf("claiming outbox %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// MarkSent records a delivery.
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE mail_outbox SET status = ?, sent_at = ?, last_error = NULL WHERE id = ?`,
+		OutboxSent, sentAt, id)
+	if err != nil {
+		return fmt.Errorf("marking outbox %d sent: %w", id, err)
+	}
+	return nil
+}
+
+// MarkRetry schedules the next attempt.
+func (r *outboxRepository) MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE mail_outbox SET next_attempt_at = ?, last_error = ? WHERE id = ?`,
+		nextAttemptAt, lastError, id)
+	if err != nil {
+		return fmt.Errorf("scheduling retry for outbox %d: %w", id, err)
+	}
+	return nil
+}
+
+// MarkFailed gives up on a message.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE mail_outbox SET status = ?, last_error = ? WHERE id = ?`,
+		OutboxFailed, lastError, id)
+	if err != nil {
+		return fmt.Errorf("marking outbox %d failed: %w", id, err)
+	}
+	return nil
+}
+
+// Requeue puts a failed message back in the queue.
+func (r *outboxRepository) Requeue(ctx context.Context, id int64, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE mail_outbox SET status = ?, attempts = 0, next_attempt_at = ?
+		 WHERE id = ? AND status = ?`,
+		OutboxPending, now, id, OutboxFailed)
+	if err != nil {
+		return false, fmt.Errorf("requeueing outbox %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// Recent returns the newest messages without their bodies.
+func (r *outboxRepository) Recent(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, recipients, subject, status, attempts, COALESCE(last_error, ''),
+		        next_attempt_at, created_at, sent_at
+		 FROM mail_outbox ORDER BY created_at DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var out []OutboxMessage
+	for rows.Next() {
+		var m OutboxMessage
+		var recipients []byte
+		if err := rows.Scan(&m.ID, &recipients, &m.Subject, &m.Status, &m.Attempts, &m.LastError,
+			&m.NextAttemptAt, &m.CreatedAt, &m.SentAt); err != nil {
+			return nil, fmt.Errorf("scanning outbox row: %w", err)
+		}
+		// A corrupt recipient list shouldn't hide the row from the admin.
+		_ = json.Unmarshal(recipients, &m.To)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// Stats counts messages by status.
+func (r *outboxRepository) Stats(ctx context.Context) (OutboxStats, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM mail_outbox GROUP BY status`)
+	if err != nil {
+		return OutboxStats{}, fmt.Errorf("counting outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var stats OutboxStats
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return OutboxStats{}, fmt.Errorf("scanning outbox count: %w", err)
+		}
+		switch status {
+		case OutboxPending:
+			stats.Pending = n
+		case OutboxSent:
+			stats.Sent = n
+		case OutboxFailed:
+			stats.Failed = n
+		}
+	}
+	return stats, rows.Err()
+}
+
+// PurgeFinishedBefore deletes old sent/failed messages in a bounded batch.
+func (r *outboxRepository) PurgeFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM mail_outbox WHERE status IN (?, ?) AND created_at < ? LIMIT 1000`,
+		OutboxSent, OutboxFailed, before)
+	if err != nil {
+		return 0, fmt.Errorf("purging finished mail: %w", err)
+	}
+	return res.RowsAffected()
+}
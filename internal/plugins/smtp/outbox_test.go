@@ -0,0 +1,246 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// fakeOutboxRepo is an in-memory OutboxRepository.
+type fakeOutboxRepo struct {
+	msgs   map[int64]*OutboxMessage
+	nextID int64
+}
+
+func newFakeOutboxRepo() *fakeOutboxRepo {
+	return &fakeOutboxRepo{msgs: map[int64]*OutboxMessage{}}
+}
+
+func (r *fakeOutboxRepo) Enqueue(_ context.Context, msg *OutboxMessage) error {
+	r.nextID++
+	msg.ID = r.nextID
+	cp := *msg
+	r.msgs[msg.ID] = &cp
+	return nil
+}
+
+func (r *fakeOutboxRepo) Due(_ context.Context, now time.Time, limit int) ([]OutboxMessage, error) {
+	var out []OutboxMessage
+	for id := int64(1); id <= r.nextID && len(out) < limit; id++ {
+		if m, ok := r.msgs[id]; ok && m.Status == OutboxPending && !m.NextAttemptAt.After(now) {
+			out = append(out, *m)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeOutboxRepo) Claim(_ context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error) {
+	m := r.msgs[id]
+	if m == nil || m.Status != OutboxPending || m.Attempts != attempts {
+		return false, nil
+	}
+	m.Attempts++
+	m.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+func (r *fakeOutboxRepo) MarkSent(_ context.Context, id int64, sentAt time.Time) error {
+	r.msgs[id].Status = OutboxSent
+	r.msgs[id].SentAt = &sentAt
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkRetry(_ context.Context, id int64, next time.Time, lastError string) error {
+	r.msgs[id].NextAttemptAt = next
+	r.msgs[id].LastError = lastError
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkFailed(_ context.Context, id int64, lastError string) error {
+	r.msgs[id].Status = OutboxFailed
+	r.msgs[id].LastError = lastError
+	return nil
+}
+
+func (r *fakeOutboxRepo) Requeue(_ context.Context, id int64, now time.Time) (bool, error) {
+	m := r.msgs[id]
+	if m == nil || m.Status != OutboxFailed {
+		return false, nil
+	}
+	m.Status, m.Attempts, m.NextAttemptAt = OutboxPending, 0, now
+	return true, nil
+}
+
+func (r *fakeOutboxRepo) Recent(context.Context, int) ([]OutboxMessage, error) { return nil, nil }
+
+func (r *fakeOutboxRepo) Stats(context.Context) (OutboxStats, error) { return OutboxStats{}, nil }
+
+func (r *fakeOutboxRepo) PurgeFinishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakeSender records deliveries and fails the first failCount sends.
+type fakeSender struct {
+	configured bool
+	failCount  int
+	plain      int
+	html       int
+}
+
+func (s *fakeSender) IsConfigured(context.Context) bool { return s.configured }
+
+func (s *fakeSender) SendMail(context.Context, []string, string, string) error {
+	if s.failCount > 0 {
+		s.failCount--
+		return errors.New("connecting to smtp.test:587: timeout")
+	}
+	s.plain++
+	return nil
+}
+
+func (s *fakeSender) SendHTMLMail(context.Context, []string, string, string, string) error {
+	if s.failCount > 0 {
+		s.failCount--
+		return errors.New("connecting to smtp.test:587: timeout")
+	}
+	s.html++
+	return nil
+}
+
+// newTestOutbox returns an outbox on a controllable clock.
+func newTestOutbox(sender *fakeSender) (*mailOutbox, *fakeOutboxRepo, *time.Time) {
+	repo := newFakeOutboxRepo()
+	clock := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	o := NewMailOutbox(repo, sender).(*mailOutbox)
+	o.now = func() time.Time { return clock }
+	return o, repo, &clock
+}
+
+func TestMailOutbox_EnqueueValidation(t *testing.T) {
+	o, repo, _ := newTestOutbox(&fakeSender{configured: true})
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		to      []string
+		wantErr bool
+	}{
+		{name: "valid", to: []string{"player@example.com"}},
+		{name: "blank entries dropped", to: []string{" ", "gm@example.com"}},
+		{name: "no recipients", to: []string{""}, wantErr: true},
+		{name: "malformed", to: []string{"not an address"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := o.SendMail(ctx, tc.to, "Subject", "Body")
+			var appErr *apperror.AppError
+			if tc.wantErr != (err != nil) || (err != nil && (!errors.As(err, &appErr) || appErr.Code != 400)) {
+				t.Errorf("SendMail(%v) err = %v, wantErr %v", tc.to, err, tc.wantErr)
+			}
+		})
+	}
+	if len(repo.msgs) != 2 {
+		t.Errorf("queued %d messages, want 2", len(repo.msgs))
+	}
+}
+
+func TestMailOutbox_DeliverAndRetry(t *testing.T) {
+	sender := &fakeSender{configured: true, failCount: 2}
+	o, repo, clock := newTestOutbox(sender)
+	ctx := context.Background()
+
+	if err := o.SendHTMLMail(ctx, []string{"a@example.com"}, "Hi", "plain", "<p>html</p>"); err != nil {
+		t.Fatalf("SendHTMLMail: %v", err)
+	}
+	msg := repo.msgs[1]
+
+	// First attempt fails: retried after the first backoff step.
+	if n := o.deliverDue(ctx); n != 1 {
+		t.Fatalf("deliverDue attempted %d, want 1", n)
+	}
+	if msg.Status != OutboxPending || msg.Attempts != 1 || !msg.NextAttemptAt.Equal(clock.Add(outboxBackoff[0])) {
+		t.Fatalf("after failure: %+v", msg)
+	}
+	if msg.LastError == "" {
+		t.Error("last error not recorded")
+	}
+
+	// Not due yet: nothing is attempted.
+	if n := o.deliverDue(ctx); n != 0 {
+		t.Errorf("deliverDue before backoff attempted %d, want 0", n)
+	}
+
+	// Second failure, then success.
+	*clock = clock.Add(outboxBackoff[0])
+	o.deliverDue(ctx)
+	if !msg.NextAttemptAt.Equal(clock.Add(outboxBackoff[1])) {
+		t.Errorf("second retry at %v, want +%v", msg.NextAttemptAt, outboxBackoff[1])
+	}
+	*clock = clock.Add(outboxBackoff[1])
+	o.deliverDue(ctx)
+	if msg.Status != OutboxSent || sender.html != 1 || sender.plain != 0 {
+		t.Errorf("want sent as HTML once; status=%s html=%d plain=%d", msg.Status, sender.html, sender.plain)
+	}
+}
+
+func TestMailOutbox_GivesUpAndRetry(t *testing.T) {
+	sender := &fakeSender{configured: true, failCount: outboxMaxAttempts}
+	o, repo, clock := newTestOutbox(sender)
+	ctx := context.Background()
+
+	_ = o.SendMail(ctx, []string{"a@example.com"}, "Hi", "plain")
+	msg := repo.msgs[1]
+	for i := 0; i < outboxMaxAttempts; i++ {
+		o.deliverDue(ctx)
+		*clock = clock.Add(24 * time.Hour)
+	}
+	if msg.Status != OutboxFailed || msg.Attempts != outboxMaxAttempts {
+		t.Fatalf("want failed after %d attempts, got %+v", outboxMaxAttempts, msg)
+	}
+
+	// Retry only applies to failed messages.
+	if err := o.Retry(ctx, msg.ID); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if err := o.Retry(ctx, msg.ID); err == nil {
+		t.Error("retrying a pending message should fail")
+	}
+	o.deliverDue(ctx)
+	if msg.Status != OutboxSent || sender.plain != 1 {
+		t.Errorf("requeued message not delivered: %+v", msg)
+	}
+}
+
+func TestMailOutbox_SMTPDisabled(t *testing.T) {
+	sender := &fakeSender{configured: false}
+	o, repo, _ := newTestOutbox(sender)
+	ctx := context.Background()
+
+	_ = o.SendMail(ctx, []string{"a@example.com"}, "Hi", "plain")
+	if n := o.deliverDue(ctx); n != 0 {
+		t.Errorf("deliverDue attempted %d with SMTP disabled, want 0", n)
+	}
+	if repo.msgs[1].Attempts != 0 {
+		t.Error("attempts consumed while SMTP was disabled")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: outboxBackoff[0]},
+		{attempt: 1, want: outboxBackoff[0]},
+		{attempt: 3, want: outboxBackoff[2]},
+		{attempt: 99, want: outboxBackoff[len(outboxBackoff)-1]},
+	}
+	for _, tc := range cases {
+		if got := retryDelay(tc.attempt); got != tc.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
@@ -9,4 +9,6 @@ func RegisterRoutes(adminGroup *echo.Group, h *Handler) {
 	adminGroup.PUT("/smtp", h.UpdateSettings)
 	adminGroup.POST("/smtp/test", h.TestConnection)
 	adminGroup.POST("/smtp/send-test", h.SendTestEmail)
+	adminGroup.GET("/smtp/outbox", h.Outbox)
+	adminGroup.POST("/smtp/outbox/:id/retry", h.RetryOutboxMessage)
 }
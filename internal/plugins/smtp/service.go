@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
 )
 
 // MailService is the interface other plugins use to send email.
@@ -476,11 +477,16 @@ func (s *smtpService) SendTestEmail(ctx context.Context, to string) error {
 		return apperror.NewBadRequest("invalid recipient email address")
 	}
 
-	subject := "Chronicle SMTP Test"
-	body := fmt.Sprintf("This is a test email from Chronicle.\n\nIf you are reading this, your SMTP configuration is working correctly.\n\nSent at: %s",
-		time.Now().UTC().Format(time.RFC1123Z))
+	// Sent as HTML with the plain-text fallback, so the test exercises the
+	// same multipart path as real mail. Bypasses the outbox on purpose: the
+	// admin needs the SMTP error now, not a retry later.
+	msg := emails.TestEmail(time.Now().UTC().Format(time.RFC1123Z))
+	plainBody, htmlBody, err := msg.Render(ctx)
+	if err != nil {
+		return apperror.NewInternal(err)
+	}
 
-	if err := s.SendMail(ctx, []string{to}, subject, body); err != nil {
+	if err := s.SendHTMLMail(ctx, []string{to}, msg.Subject, plainBody, htmlBody); err != nil {
 		// Wrap the raw SMTP error with actionable guidance.
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "STARTTLS") || strings.Contains(errMsg, "TLS") {
@@ -49,6 +49,9 @@ type APIHandler struct {
 	systemEnabler        SystemEnabler
 	campaignSystemLister CampaignSystemLister
 	tagGrantLister       TagGrantLister
+	markdownConv         MarkdownEntryConverter
+	announcementSvc      campaigns.AnnouncementService
+	syncMappingSvc       SyncMappingService
 }
 
 // TagGrantLister resolves an entity's tag-derived visibility grants so the
@@ -107,6 +110,7 @@ var degradeSignalThrottle sync.Map
 //     ownership or removing a member quietly stripped private/custom entities
 //     from the sync. When the key's creator HAS lost access we still sync, but
 //     we emit a loud, operator-visible signal instead of degrading silently.
+//   - A demo key resolves to Player, whatever its creator's role.
 func (h *APIHandler) resolveRole(c echo.Context) int {
 	key := GetAPIKey(c)
 	if key == nil {
@@ -121,9 +125,10 @@ func (h *APIHandler) resolveRole(c echo.Context) int {
 		return int(member.Role)
 	}
 	// Stored Bearer key: reliable Owner-level sync visibility, but surface a
-	// lost-access condition loudly rather than silently degrading.
+	// lost-access condition loudly rather than silently degrading. Demo keys
+	// read at player level.
 	h.flagIfKeyOwnerLostAccess(c, key)
-	return int(campaigns.RoleOwner)
+	return key.EffectiveRole()
 }
 
 // flagIfKeyOwnerLostAccess emits a loud, module-surfaceable signal when a stored
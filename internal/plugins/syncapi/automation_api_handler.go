@@ -0,0 +1,267 @@
+package syncapi
+
+// automation_api_handler.go — inbound actions for no-code automation tools
+// (n8n, Zapier, Make). Each action is one POST with a flat JSON body and a
+// flat JSON reply, so a generic "HTTP request" step can call it with a
+// Bearer key. Keys need the "automation" permission, which grants these
+// actions and nothing else: a leaked automation key can add content but
+// can't read the campaign.
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+// entityActionReserved are the create-entity keys that aren't fields.
+var entityActionReserved = map[string]bool{
+	"name": true, "type": true, "text": true, "private": true,
+}
+
+// SetAnnouncementService enables the post-announcement action.
+func (h *APIHandler) SetAnnouncementService(svc campaigns.AnnouncementService) {
+	h.announcementSvc = svc
+}
+
+// flatString reads a string value from a flat payload; numbers and
+// booleans are accepted as their text.
+func flatString(values map[string]any, key string) string {
+	switch v := values[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// flatBool reads a boolean value; automation tools often send "true".
+func flatBool(values map[string]any, key string) bool {
+	switch v := values[key].(type) {
+	case bool:
+		return v
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "1":
+			return true
+		}
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// matchEntityType finds the enabled category a payload names by slug, name
+// or plural name, ignoring case.
+func matchEntityType(types []entities.EntityType, name string) *entities.EntityType {
+	for i := range types {
+		et := &types[i]
+		if !et.Enabled {
+			continue
+		}
+		if strings.EqualFold(et.Slug, name) || strings.EqualFold(et.Name, name) || strings.EqualFold(et.NamePlural, name) {
+			return et
+		}
+	}
+	return nil
+}
+
+// plainTextHTML renders plain text as escaped paragraphs.
+func plainTextHTML(text string) string {
+	var b strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(para), "\n", "<br>") + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// ActionCreateEntity creates a page from a flat payload: "name" and "type"
+// (category slug or name) are required, "text" is the entry as Markdown and
+// "private" hides it from players. Every other key fills the field of the
+// same key; keys that match no field are returned as "ignored".
+// POST /api/v1/campaigns/:id/actions/create-entity
+func (h *APIHandler) ActionCreateEntity(c echo.Context) error {
+	key := GetAPIKey(c)
+	if key == nil {
+		return apperror.NewUnauthorized("api key required")
+	}
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+
+	// Decode the body directly: Bind would also copy the path's :id into
+	// the map, where it would read as a field.
+	values := map[string]any{}
+	if err := json.NewDecoder(c.Request().Body).Decode(&values); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	name := flatString(values, "name")
+	typeName := flatString(values, "type")
+	if name == "" || typeName == "" {
+		return apperror.NewBadRequest("name and type are required")
+	}
+	types, err := h.entitySvc.GetEntityTypes(ctx, campaignID)
+	if err != nil {
+		return apperror.NewInternal(fmt.Errorf("failed to load categories"))
+	}
+	et := matchEntityType(types, typeName)
+	if et == nil {
+		return apperror.NewBadRequest("no category named " + typeName)
+	}
+
+	var entryJSON, entryHTML string
+	if text := flatString(values, "text"); text != "" {
+		if h.markdownConv == nil {
+			return apperror.NewBadRequest("text can't be imported on this server")
+		}
+		if entryJSON, entryHTML, err = h.markdownConv.ConvertMarkdown(text); err != nil {
+			return apperror.NewBadRequest(err.Error())
+		}
+	}
+
+	data := map[string]any{}
+	ignored := mapFlatFields(values, entityActionReserved, et.Fields, data)
+	entity, err := h.entitySvc.Create(ctx, campaignID, key.UserID, entities.CreateEntityInput{
+		Name:         name,
+		EntityTypeID: et.ID,
+		IsPrivate:    flatBool(values, "private"),
+		FieldsData:   data,
+	})
+	if err != nil {
+		return err
+	}
+	if entryJSON != "" {
+		if err := h.entitySvc.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+			return err
+		}
+	}
+
+	resp := map[string]any{
+		"id":   entity.ID,
+		"name": entity.Name,
+		"type": et.Slug,
+		"url":  fmt.Sprintf("/campaigns/%s/entities/%s", campaignID, entity.ID),
+	}
+	if len(ignored) > 0 {
+		resp["ignored"] = strings.Join(ignored, ", ")
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// apiAnnouncementAction is the post-announcement payload. Body is Markdown.
+type apiAnnouncementAction struct {
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Pinned bool   `json:"pinned"`
+}
+
+// ActionPostAnnouncement publishes an announcement on the campaign
+// dashboard and notifies members. Owner-level callers only, like the web
+// form; a Bearer key is Owner-level (see resolveRole).
+// POST /api/v1/campaigns/:id/actions/post-announcement
+func (h *APIHandler) ActionPostAnnouncement(c echo.Context) error {
+	key := GetAPIKey(c)
+	if key == nil {
+		return apperror.NewUnauthorized("api key required")
+	}
+	if h.announcementSvc == nil {
+		return apperror.NewNotFound("announcements are not available")
+	}
+	if h.resolveRole(c) < int(campaigns.RoleOwner) {
+		return apperror.NewForbidden("only campaign owners can post announcements")
+	}
+	var req apiAnnouncementAction
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	a, err := h.announcementSvc.Create(c.Request().Context(), c.Param("id"), key.UserID, campaigns.AnnouncementInput{
+		Title:  req.Title,
+		Body:   req.Body,
+		Pinned: req.Pinned,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, map[string]any{
+		"id":    a.ID,
+		"title": a.Title,
+		"url":   fmt.Sprintf("/campaigns/%s/announcements/%s", c.Param("id"), a.ID),
+	})
+}
+
+// apiEventAction is the add-event payload. A missing date means the
+// calendar's current date; Description is plain text.
+type apiEventAction struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Year        *int   `json:"year"`
+	Month       *int   `json:"month"`
+	Day         *int   `json:"day"`
+	Visibility  string `json:"visibility"`
+	Category    string `json:"category"`
+}
+
+// ActionAddEvent adds an event to the campaign calendar, on the given date
+// or, when the payload has none, on the calendar's current date.
+// POST /api/v1/campaigns/:id/actions/add-event
+func (h *CalendarAPIHandler) ActionAddEvent(c echo.Context) error {
+	key := GetAPIKey(c)
+	if key == nil {
+		return apperror.NewUnauthorized("api key required")
+	}
+	ctx := c.Request().Context()
+
+	cal, err := h.calendarSvc.GetCalendar(ctx, c.Param("id"))
+	if err != nil || cal == nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+	var req apiEventAction
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	input := calendar.CreateEventInput{
+		Name:       strings.TrimSpace(req.Name),
+		Year:       cal.CurrentYear,
+		Month:      cal.CurrentMonth,
+		Day:        cal.CurrentDay,
+		Visibility: req.Visibility,
+		AllDay:     true,
+		CreatedBy:  key.UserID,
+	}
+	if req.Year != nil || req.Month != nil || req.Day != nil {
+		if req.Year == nil || req.Month == nil || req.Day == nil {
+			return apperror.NewBadRequest("give year, month and day together, or none for today")
+		}
+		input.Year, input.Month, input.Day = *req.Year, *req.Month, *req.Day
+	}
+	if desc := plainTextHTML(req.Description); desc != "" {
+		input.DescriptionHTML = &desc
+	}
+	if cat := strings.TrimSpace(req.Category); cat != "" {
+		input.Category = &cat
+	}
+
+	evt, err := h.calendarSvc.CreateEvent(ctx, cal.ID, input)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, map[string]any{
+		"id":    evt.ID,
+		"name":  evt.Name,
+		"year":  evt.Year,
+		"month": evt.Month,
+		"day":   evt.Day,
+	})
+}
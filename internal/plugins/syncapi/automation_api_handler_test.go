@@ -0,0 +1,122 @@
+package syncapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/calendar"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// stubAnnouncementSvc records the announcement an action posted.
+type stubAnnouncementSvc struct {
+	campaigns.AnnouncementService
+	posted *campaigns.AnnouncementInput
+}
+
+func (s *stubAnnouncementSvc) Create(_ context.Context, campaignID, _ string, in campaigns.AnnouncementInput) (*campaigns.Announcement, error) {
+	s.posted = &in
+	return &campaigns.Announcement{ID: "ann-1", CampaignID: campaignID, Title: in.Title}, nil
+}
+
+// stubCalendarForAction adds event capture to the calendar stub.
+type stubCalendarForAction struct {
+	*stubCalendarSvc
+	created *calendar.CreateEventInput
+}
+
+func (s *stubCalendarForAction) CreateEvent(_ context.Context, _ string, in calendar.CreateEventInput) (*calendar.Event, error) {
+	s.created = &in
+	return &calendar.Event{ID: "evt-1", Name: in.Name, Year: in.Year, Month: in.Month, Day: in.Day}, nil
+}
+
+func TestActionCreateEntity_FlatPayload(t *testing.T) {
+	svc := newObsidianStub()
+	body := `{"name":"Quill","type":"NPC","text":"A *scout*.","private":"yes","title":"Scout","source":"zapier","id":"ignored-too"}`
+	c, rec := newObsidianContext(http.MethodPost, "/", body, campaigns.RoleOwner)
+	if err := newObsidianHandler(svc, campaigns.RoleOwner).ActionCreateEntity(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", rec.Code)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["id"] != "ent-new" || resp["type"] != "npc" || resp["ignored"] != "id, source" {
+		t.Errorf("response = %v", resp)
+	}
+	if in := svc.created; in.Name != "Quill" || !in.IsPrivate || in.FieldsData["title"] != "Scout" || len(in.FieldsData) != 1 {
+		t.Errorf("create input = %+v", in)
+	}
+	if !strings.Contains(svc.entry, "A *scout*.") {
+		t.Errorf("entry = %q", svc.entry)
+	}
+}
+
+func TestActionCreateEntity_UnknownType(t *testing.T) {
+	svc := newObsidianStub()
+	c, _ := newObsidianContext(http.MethodPost, "/", `{"name":"Quill","type":"Dragons"}`, campaigns.RoleOwner)
+	err := newObsidianHandler(svc, campaigns.RoleOwner).ActionCreateEntity(c)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusBadRequest {
+		t.Fatalf("err = %v, want 400", err)
+	}
+	if svc.created != nil {
+		t.Error("page created for an unknown category")
+	}
+}
+
+func TestActionPostAnnouncement_OwnerOnly(t *testing.T) {
+	ann := &stubAnnouncementSvc{}
+	body := `{"title":"Session moved","body":"Now on **Friday**.","pinned":true}`
+
+	c, _ := newObsidianContext(http.MethodPost, "/", body, campaigns.RolePlayer)
+	h := newObsidianHandler(newObsidianStub(), campaigns.RolePlayer)
+	h.SetAnnouncementService(ann)
+	err := h.ActionPostAnnouncement(c)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusForbidden {
+		t.Fatalf("player err = %v, want 403", err)
+	}
+
+	c, rec := newObsidianContext(http.MethodPost, "/", body, campaigns.RoleOwner)
+	h = newObsidianHandler(newObsidianStub(), campaigns.RoleOwner)
+	h.SetAnnouncementService(ann)
+	if err := h.ActionPostAnnouncement(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusCreated || ann.posted == nil || ann.posted.Title != "Session moved" || !ann.posted.Pinned {
+		t.Errorf("status %d, posted %+v", rec.Code, ann.posted)
+	}
+}
+
+func TestActionAddEvent_DefaultsToCurrentDate(t *testing.T) {
+	cal := &stubCalendarForAction{stubCalendarSvc: &stubCalendarSvc{
+		onGet: func(context.Context, string) (*calendar.Calendar, error) {
+			return &calendar.Calendar{ID: "cal-1", CurrentYear: 1492, CurrentMonth: 3, CurrentDay: 7}, nil
+		},
+	}}
+	h := NewCalendarAPIHandler(nil, cal)
+
+	c, rec := newObsidianContext(http.MethodPost, "/", `{"name":"Caravan arrives","description":"Dust <and> bells.\n\nTwelve wagons."}`, campaigns.RoleOwner)
+	if err := h.ActionAddEvent(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	in := cal.created
+	if rec.Code != http.StatusCreated || in.Year != 1492 || in.Month != 3 || in.Day != 7 || !in.AllDay {
+		t.Fatalf("status %d, input %+v", rec.Code, in)
+	}
+	if in.DescriptionHTML == nil || *in.DescriptionHTML != "<p>Dust &lt;and&gt; bells.</p><p>Twelve wagons.</p>" {
+		t.Errorf("description = %v", in.DescriptionHTML)
+	}
+
+	c, _ = newObsidianContext(http.MethodPost, "/", `{"name":"Half a date","month":4}`, campaigns.RoleOwner)
+	err := h.ActionAddEvent(c)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusBadRequest {
+		t.Fatalf("partial date err = %v, want 400", err)
+	}
+}
@@ -0,0 +1,280 @@
+package syncapi
+
+// batch_upsert_api_handler.go — bulk create/update of pages keyed by the
+// client's own IDs, so a VTT module can push a whole compendium in a few
+// requests. Each item's external_id is resolved through the sync mappings:
+// a mapped item updates its page, an unmapped one creates a page and gets
+// a mapping. on_conflict decides what a mapped item does to its page:
+// overwrite it (theirs), leave it (ours) or only fill what it lacks
+// (merge), so a repeated Kanka or CSV import never duplicates pages nor
+// clobbers edits made since. The page writes are all-or-nothing (see
+// entities.EntityService.UpsertBatch); mappings are recorded after the
+// commit.
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+// maxBatchUpsert caps the items accepted by one batch upsert.
+const maxBatchUpsert = 500
+
+// Conflict policies for an item whose external_id already maps to a page.
+const (
+	conflictTheirs = "theirs" // The item overwrites the page (default).
+	conflictOurs   = "ours"   // The page is left as it is.
+	conflictMerge  = "merge"  // The item only fills the page's empty entry and fields.
+)
+
+// SetSyncMappingService enables the batch upsert endpoint, which resolves
+// external IDs through the sync mappings.
+func (h *APIHandler) SetSyncMappingService(svc SyncMappingService) {
+	h.syncMappingSvc = svc
+}
+
+// batchUpsertRequest is the body of a batch upsert. ExternalSystem
+// defaults to "foundry" and OnConflict to "theirs".
+type batchUpsertRequest struct {
+	ExternalSystem string            `json:"external_system"`
+	OnConflict     string            `json:"on_conflict"`
+	Entities       []batchUpsertItem `json:"entities"`
+}
+
+// batchUpsertItem is one page, identified by the client's external_id.
+// entity_type_id is required when the page is created and ignored after.
+type batchUpsertItem struct {
+	ExternalID        string         `json:"external_id"`
+	EntityTypeID      int            `json:"entity_type_id"`
+	Name              string         `json:"name"`
+	TypeLabel         string         `json:"type_label"`
+	IsPrivate         bool           `json:"is_private"`
+	Entry             string         `json:"entry"`
+	FieldsData        map[string]any `json:"fields_data"`
+	ExpectedUpdatedAt *time.Time     `json:"expected_updated_at"`
+}
+
+// batchUpsertResult is the outcome for one item, in request order.
+type batchUpsertResult struct {
+	ExternalID string `json:"external_id"`
+	Status     string `json:"status"` // "created", "updated", "unchanged", "error" or "skipped".
+	EntityID   string `json:"entity_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// batchUpsertResponse reports whether the batch was written. When Applied
+// is false nothing changed: failing items say why and the rest are
+// "skipped".
+type batchUpsertResponse struct {
+	Applied bool                `json:"applied"`
+	Results []batchUpsertResult `json:"results"`
+}
+
+// BatchUpsertEntities creates or updates up to maxBatchUpsert pages keyed
+// by external_id.
+// POST /api/v1/campaigns/:id/entities:batchUpsert
+func (h *APIHandler) BatchUpsertEntities(c echo.Context) error {
+	key := GetAPIKey(c)
+	if key == nil {
+		return apperror.NewUnauthorized("api key required")
+	}
+	if h.syncMappingSvc == nil {
+		return apperror.NewBadRequest("batch upsert is not available on this server")
+	}
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+
+	var req batchUpsertRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	system := strings.TrimSpace(req.ExternalSystem)
+	if system == "" {
+		system = "foundry"
+	}
+	if !validExternalSystems[system] {
+		return apperror.NewBadRequest(fmt.Sprintf("invalid external_system: %s", system))
+	}
+	policy := strings.TrimSpace(req.OnConflict)
+	if policy == "" {
+		policy = conflictTheirs
+	}
+	if policy != conflictTheirs && policy != conflictOurs && policy != conflictMerge {
+		return apperror.NewBadRequest("on_conflict must be theirs, ours or merge")
+	}
+	if len(req.Entities) == 0 {
+		return apperror.NewBadRequest("entities is required")
+	}
+	if len(req.Entities) > maxBatchUpsert {
+		return apperror.NewBadRequest(fmt.Sprintf("too many entities; maximum is %d per request", maxBatchUpsert))
+	}
+
+	// Resolve each external_id to its mapped page. A mapping whose page is
+	// gone is dropped so the item creates a fresh page. writes[j] is the
+	// item behind inputs[j]; "ours" items are answered without a write.
+	results := make([]batchUpsertResult, len(req.Entities))
+	mappings := make([]*SyncMapping, len(req.Entities))
+	var inputs []entities.UpsertEntityInput
+	var writes []int
+	seen := make(map[string]bool, len(req.Entities))
+	failed := false
+	for i, item := range req.Entities {
+		extID := strings.TrimSpace(item.ExternalID)
+		results[i].ExternalID = extID
+		m, page, err := h.resolveBatchItem(c, system, extID, seen)
+		if err != nil {
+			results[i].Status, results[i].Error = "error", apperror.SafeMessage(err)
+			failed = true
+			continue
+		}
+		mappings[i] = m
+		input := entities.UpsertEntityInput{
+			Name:              item.Name,
+			EntityTypeID:      item.EntityTypeID,
+			TypeLabel:         item.TypeLabel,
+			IsPrivate:         item.IsPrivate,
+			Entry:             item.Entry,
+			FieldsData:        item.FieldsData,
+			ExpectedUpdatedAt: item.ExpectedUpdatedAt,
+		}
+		if page != nil {
+			switch policy {
+			case conflictOurs:
+				results[i].Status, results[i].EntityID = "unchanged", page.ID
+				continue
+			case conflictMerge:
+				input = mergeBatchItem(page, input)
+			}
+			input.EntityID = page.ID
+		}
+		inputs = append(inputs, input)
+		writes = append(writes, i)
+	}
+	if failed {
+		return c.JSON(http.StatusOK, skippedBatch(results))
+	}
+
+	upserted, err := h.entitySvc.UpsertBatch(ctx, campaignID, key.UserID, inputs)
+	if err != nil {
+		return err
+	}
+	applied := true
+	for j, r := range upserted {
+		if r.Err != nil {
+			i := writes[j]
+			results[i].Status, results[i].Error = "error", apperror.SafeMessage(r.Err)
+			applied = false
+		}
+	}
+	if !applied {
+		return c.JSON(http.StatusOK, skippedBatch(results))
+	}
+
+	for j, r := range upserted {
+		i := writes[j]
+		results[i].EntityID = r.Entity.ID
+		if !r.Created {
+			results[i].Status = "updated"
+			if err := h.syncMappingSvc.BumpVersion(ctx, mappings[i].ID); err != nil {
+				slog.Warn("api: batch upsert failed to bump sync mapping",
+					slog.String("mapping_id", mappings[i].ID), slog.Any("error", err))
+			}
+			continue
+		}
+		results[i].Status = "created"
+		if _, err := h.syncMappingSvc.CreateMapping(ctx, campaignID, CreateSyncMappingInput{
+			ChronicleType:  "entity",
+			ChronicleID:    r.Entity.ID,
+			ExternalSystem: system,
+			ExternalID:     results[i].ExternalID,
+		}); err != nil {
+			// The page exists; the client can link it with POST /sync/mappings.
+			slog.Error("api: batch upsert failed to record sync mapping",
+				slog.String("entity_id", r.Entity.ID), slog.Any("error", err))
+			results[i].Error = "page created but its sync mapping was not recorded"
+		}
+	}
+	return c.JSON(http.StatusOK, batchUpsertResponse{Applied: true, Results: results})
+}
+
+// mergeBatchItem keeps the page's name, label, privacy and every non-empty
+// entry and field, taking from the item only what the page lacks.
+func mergeBatchItem(page *entities.Entity, in entities.UpsertEntityInput) entities.UpsertEntityInput {
+	out := entities.UpsertEntityInput{
+		Name:              page.Name,
+		TypeLabel:         in.TypeLabel,
+		IsPrivate:         page.IsPrivate,
+		ExpectedUpdatedAt: in.ExpectedUpdatedAt,
+	}
+	if page.TypeLabel != nil && *page.TypeLabel != "" {
+		out.TypeLabel = *page.TypeLabel
+	}
+	if page.EntryHTML == nil || strings.TrimSpace(*page.EntryHTML) == "" {
+		out.Entry = in.Entry
+	}
+	if len(in.FieldsData) > 0 {
+		out.FieldsData = make(map[string]any, len(page.FieldsData)+len(in.FieldsData))
+		for k, v := range page.FieldsData {
+			out.FieldsData[k] = v
+		}
+		for k, v := range in.FieldsData {
+			if cur, ok := out.FieldsData[k]; !ok || cur == nil || cur == "" {
+				out.FieldsData[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// resolveBatchItem validates an item's external_id and returns its mapping
+// and page, or nils for a new page.
+func (h *APIHandler) resolveBatchItem(c echo.Context, system, extID string, seen map[string]bool) (*SyncMapping, *entities.Entity, error) {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+	if extID == "" {
+		return nil, nil, apperror.NewBadRequest("external_id is required")
+	}
+	if seen[extID] {
+		return nil, nil, apperror.NewBadRequest("external_id appears more than once in the batch")
+	}
+	seen[extID] = true
+
+	m, err := h.syncMappingSvc.GetMappingByExternal(ctx, campaignID, system, extID)
+	if err != nil {
+		if apperror.SafeCode(err) == http.StatusNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if m.ChronicleType != "entity" {
+		return nil, nil, apperror.NewBadRequest(fmt.Sprintf("external_id is mapped to a %s, not a page", m.ChronicleType))
+	}
+	page, err := h.entitySvc.GetByID(ctx, m.ChronicleID)
+	if err != nil && apperror.SafeCode(err) != http.StatusNotFound {
+		return nil, nil, err
+	}
+	if err != nil || page.CampaignID != campaignID {
+		if err := h.syncMappingSvc.DeleteMapping(ctx, m.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, nil
+	}
+	return m, page, nil
+}
+
+// skippedBatch marks every item that didn't fail as skipped.
+func skippedBatch(results []batchUpsertResult) batchUpsertResponse {
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = "skipped"
+		}
+	}
+	return batchUpsertResponse{Applied: false, Results: results}
+}
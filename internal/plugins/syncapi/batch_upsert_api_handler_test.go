@@ -0,0 +1,206 @@
+package syncapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+// stubEntitySvcForBatch holds page ent-1 and echoes a batch back, failing
+// any item named "bad".
+type stubEntitySvcForBatch struct {
+	entities.EntityService
+	inputs []entities.UpsertEntityInput
+}
+
+func (s *stubEntitySvcForBatch) GetByID(_ context.Context, id string) (*entities.Entity, error) {
+	if id != "ent-1" {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	entry := "<p>Hemp.</p>"
+	return &entities.Entity{ID: id, CampaignID: "camp-1", Name: "Rope", EntryHTML: &entry,
+		FieldsData: map[string]any{"weight": "10 lb", "cost": ""}}, nil
+}
+
+func (s *stubEntitySvcForBatch) UpsertBatch(_ context.Context, _, _ string, items []entities.UpsertEntityInput) ([]entities.UpsertEntityResult, error) {
+	s.inputs = items
+	results := make([]entities.UpsertEntityResult, len(items))
+	failed := false
+	for i, it := range items {
+		if it.Name == "bad" {
+			results[i].Err = apperror.NewBadRequest("invalid entity type")
+			failed = true
+		}
+	}
+	if failed {
+		return results, nil
+	}
+	for i, it := range items {
+		id := it.EntityID
+		if id == "" {
+			id = "new-" + it.Name
+		}
+		results[i] = entities.UpsertEntityResult{Entity: &entities.Entity{ID: id}, Created: it.EntityID == ""}
+	}
+	return results, nil
+}
+
+// stubMappingSvcForBatch maps foundry "a1" to ent-1 and "gone" to a
+// deleted page, recording mapping writes.
+type stubMappingSvcForBatch struct {
+	SyncMappingService
+	created []CreateSyncMappingInput
+	bumped  []string
+	deleted []string
+}
+
+func (s *stubMappingSvcForBatch) GetMappingByExternal(_ context.Context, _, _, externalID string) (*SyncMapping, error) {
+	switch externalID {
+	case "a1":
+		return &SyncMapping{ID: "map-1", ChronicleType: "entity", ChronicleID: "ent-1"}, nil
+	case "gone":
+		return &SyncMapping{ID: "map-2", ChronicleType: "entity", ChronicleID: "ent-deleted"}, nil
+	}
+	return nil, apperror.NewNotFound("sync mapping not found")
+}
+
+func (s *stubMappingSvcForBatch) CreateMapping(_ context.Context, _ string, input CreateSyncMappingInput) (*SyncMapping, error) {
+	s.created = append(s.created, input)
+	return &SyncMapping{}, nil
+}
+
+func (s *stubMappingSvcForBatch) BumpVersion(_ context.Context, id string) error {
+	s.bumped = append(s.bumped, id)
+	return nil
+}
+
+func (s *stubMappingSvcForBatch) DeleteMapping(_ context.Context, id string) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func runBatchUpsert(t *testing.T, body string) (batchUpsertResponse, *stubEntitySvcForBatch, *stubMappingSvcForBatch) {
+	t.Helper()
+	entSvc, mapSvc := &stubEntitySvcForBatch{}, &stubMappingSvcForBatch{}
+	h := &APIHandler{entitySvc: entSvc, syncMappingSvc: mapSvc}
+	c, rec := newObsidianContext(http.MethodPost, "/", body, campaigns.RoleOwner)
+	if err := h.BatchUpsertEntities(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resp batchUpsertResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp, entSvc, mapSvc
+}
+
+func TestBatchUpsertEntities_CreatesAndUpdates(t *testing.T) {
+	resp, entSvc, mapSvc := runBatchUpsert(t, `{"entities":[
+		{"external_id":"a1","name":"Rope"},
+		{"external_id":"b2","name":"Dagger","entity_type_id":4},
+		{"external_id":"gone","name":"Torch","entity_type_id":4}
+	]}`)
+	if !resp.Applied || len(resp.Results) != 3 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if r := resp.Results[0]; r.Status != "updated" || r.EntityID != "ent-1" {
+		t.Errorf("a1 = %+v", r)
+	}
+	if r := resp.Results[1]; r.Status != "created" || r.EntityID != "new-Dagger" {
+		t.Errorf("b2 = %+v", r)
+	}
+	// A mapping to a deleted page is dropped and the item creates a page.
+	if entSvc.inputs[2].EntityID != "" || len(mapSvc.deleted) != 1 || mapSvc.deleted[0] != "map-2" {
+		t.Errorf("stale mapping not replaced: input %+v, deleted %v", entSvc.inputs[2], mapSvc.deleted)
+	}
+	if len(mapSvc.created) != 2 || mapSvc.created[0].ExternalID != "b2" || mapSvc.created[0].ExternalSystem != "foundry" {
+		t.Errorf("mappings created = %+v", mapSvc.created)
+	}
+	if len(mapSvc.bumped) != 1 || mapSvc.bumped[0] != "map-1" {
+		t.Errorf("mappings bumped = %v", mapSvc.bumped)
+	}
+}
+
+func TestBatchUpsertEntities_FailureSkipsBatch(t *testing.T) {
+	resp, _, mapSvc := runBatchUpsert(t, `{"entities":[
+		{"external_id":"b2","name":"Dagger","entity_type_id":4},
+		{"external_id":"c3","name":"bad","entity_type_id":9}
+	]}`)
+	if resp.Applied || resp.Results[0].Status != "skipped" || resp.Results[1].Status != "error" {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if len(mapSvc.created) != 0 {
+		t.Errorf("mappings created for an unapplied batch: %+v", mapSvc.created)
+	}
+
+	// Duplicate or missing external IDs fail before anything is written.
+	resp, entSvc, _ := runBatchUpsert(t, `{"entities":[
+		{"external_id":"b2","name":"Dagger","entity_type_id":4},
+		{"external_id":"b2","name":"Dagger","entity_type_id":4},
+		{"name":"Torch","entity_type_id":4}
+	]}`)
+	if resp.Applied || entSvc.inputs != nil {
+		t.Fatalf("resp = %+v, inputs = %+v", resp, entSvc.inputs)
+	}
+	if resp.Results[0].Status != "skipped" || resp.Results[1].Status != "error" || resp.Results[2].Status != "error" {
+		t.Errorf("results = %+v", resp.Results)
+	}
+}
+
+func TestBatchUpsertEntities_TooMany(t *testing.T) {
+	items := make([]map[string]any, maxBatchUpsert+1)
+	for i := range items {
+		items[i] = map[string]any{"external_id": "x", "name": "x"}
+	}
+	body, _ := json.Marshal(map[string]any{"entities": items})
+	h := &APIHandler{entitySvc: &stubEntitySvcForBatch{}, syncMappingSvc: &stubMappingSvcForBatch{}}
+	c, _ := newObsidianContext(http.MethodPost, "/", string(body), campaigns.RoleOwner)
+	assertAppError(t, h.BatchUpsertEntities(c), http.StatusBadRequest)
+}
+
+func TestBatchUpsertEntities_ConflictPolicies(t *testing.T) {
+	body := func(policy string) string {
+		return `{"external_system":"kanka","on_conflict":"` + policy + `","entities":[
+			{"external_id":"a1","name":"Silk Rope","entry":"<p>Silk.</p>","fields_data":{"weight":"5 lb","cost":"1 gp"}},
+			{"external_id":"b2","name":"Dagger","entity_type_id":4}
+		]}`
+	}
+
+	// ours: the mapped page is left alone; new records are still created.
+	resp, entSvc, mapSvc := runBatchUpsert(t, body("ours"))
+	if r := resp.Results[0]; r.Status != "unchanged" || r.EntityID != "ent-1" {
+		t.Errorf("ours a1 = %+v", r)
+	}
+	if len(entSvc.inputs) != 1 || entSvc.inputs[0].Name != "Dagger" || len(mapSvc.bumped) != 0 {
+		t.Errorf("ours wrote %+v, bumped %v", entSvc.inputs, mapSvc.bumped)
+	}
+	if len(mapSvc.created) != 1 || mapSvc.created[0].ExternalSystem != "kanka" {
+		t.Errorf("ours mappings = %+v", mapSvc.created)
+	}
+
+	// merge: the page keeps its name, entry and set fields; empty ones fill.
+	resp, entSvc, _ = runBatchUpsert(t, body("merge"))
+	if resp.Results[0].Status != "updated" {
+		t.Fatalf("merge a1 = %+v", resp.Results[0])
+	}
+	in := entSvc.inputs[0]
+	if in.EntityID != "ent-1" || in.Name != "Rope" || in.Entry != "" ||
+		in.FieldsData["weight"] != "10 lb" || in.FieldsData["cost"] != "1 gp" {
+		t.Errorf("merge input = %+v", in)
+	}
+
+	// theirs (the default): the item overwrites the page.
+	_, entSvc, _ = runBatchUpsert(t, body(""))
+	if in := entSvc.inputs[0]; in.Name != "Silk Rope" || in.FieldsData["weight"] != "5 lb" {
+		t.Errorf("theirs input = %+v", in)
+	}
+
+	h := &APIHandler{entitySvc: &stubEntitySvcForBatch{}, syncMappingSvc: &stubMappingSvcForBatch{}}
+	c, _ := newObsidianContext(http.MethodPost, "/", body("newest"), campaigns.RoleOwner)
+	assertAppError(t, h.BatchUpsertEntities(c), http.StatusBadRequest)
+}
@@ -311,6 +311,47 @@ func (h *CalendarAPIHandler) GetWeather(c echo.Context) error {
 	return c.JSON(http.StatusOK, weather)
 }
 
+// GetRegions returns every climate region (weather zone) with its season
+// and weather on the calendar's current date and its assigned locations.
+// GET /api/v1/campaigns/:id/calendar/regions
+func (h *CalendarAPIHandler) GetRegions(c echo.Context) error {
+	campaignID := c.Param("id")
+	ctx := c.Request().Context()
+
+	cal, err := h.calendarSvc.GetCalendar(ctx, campaignID)
+	if err != nil || cal == nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+
+	regions, err := h.calendarSvc.RegionalClimate(ctx, cal.ID)
+	if err != nil {
+		return err
+	}
+	if regions == nil {
+		regions = []calendar.RegionClimate{}
+	}
+	return c.JSON(http.StatusOK, regions)
+}
+
+// GetLocationClimate returns the current season and weather at a location
+// entity, resolved through its (possibly inherited) climate region.
+// GET /api/v1/campaigns/:id/calendar/locations/:entityID/climate
+func (h *CalendarAPIHandler) GetLocationClimate(c echo.Context) error {
+	campaignID := c.Param("id")
+	ctx := c.Request().Context()
+
+	cal, err := h.calendarSvc.GetCalendar(ctx, campaignID)
+	if err != nil || cal == nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+
+	climate, err := h.calendarSvc.ClimateForLocation(ctx, cal.ID, c.Param("entityID"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, climate)
+}
+
 // GetCycles returns all cycle definitions.
 // GET /api/v1/campaigns/:id/calendar/cycles
 func (h *CalendarAPIHandler) GetCycles(c echo.Context) error {
@@ -701,7 +742,7 @@ func (h *CalendarAPIHandler) AdvanceDate(c echo.Context) error {
 		return apperror.NewBadRequest("days must be between 1 and 3650")
 	}
 
-	if err := h.calendarSvc.AdvanceDate(ctx, cal.ID, req.Days); err != nil {
+	if err := h.calendarSvc.AdvanceDate(ctx, cal.ID, "", req.Days); err != nil {
 		return err
 	}
 
@@ -746,7 +787,7 @@ func (h *CalendarAPIHandler) AdvanceTime(c echo.Context) error {
 		return apperror.NewBadRequest("must advance by at least 1 minute or 1 hour")
 	}
 
-	if err := h.calendarSvc.AdvanceTime(ctx, cal.ID, req.Hours, req.Minutes); err != nil {
+	if err := h.calendarSvc.AdvanceTime(ctx, cal.ID, "", req.Hours, req.Minutes); err != nil {
 		return err
 	}
 
@@ -984,6 +1025,30 @@ func (h *CalendarAPIHandler) SetWeather(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// SetRegionWeather sets one climate region's current weather. Nil fields
+// keep the region's stored values, as with SetWeather.
+// PUT /api/v1/campaigns/:id/calendar/regions/:zoneID/weather
+func (h *CalendarAPIHandler) SetRegionWeather(c echo.Context) error {
+	campaignID := c.Param("id")
+	ctx := c.Request().Context()
+
+	cal, err := h.calendarSvc.GetCalendar(ctx, campaignID)
+	if err != nil || cal == nil {
+		return apperror.NewNotFound("calendar not found")
+	}
+
+	var input calendar.WeatherInput
+	if err := c.Bind(&input); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+
+	if err := h.calendarSvc.SetZoneWeather(ctx, cal.ID, c.Param("zoneID"), input); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
 // UpdateCycles replaces all calendar cycles.
 // PUT /api/v1/campaigns/:id/calendar/cycles
 func (h *CalendarAPIHandler) UpdateCycles(c echo.Context) error {
@@ -1032,7 +1097,9 @@ func (h *CalendarAPIHandler) UpdateFestivals(c echo.Context) error {
 
 // --- Import/Export ---
 
-// ExportCalendar returns the full calendar as a Chronicle JSON export.
+// ExportCalendar returns the full calendar as a JSON export. Chronicle's
+// native format by default; ?format=simple-calendar or fantasy-calendar
+// selects a foreign format.
 // GET /api/v1/campaigns/:id/calendar/export
 func (h *CalendarAPIHandler) ExportCalendar(c echo.Context) error {
 	campaignID := c.Param("id")
@@ -1051,7 +1118,11 @@ func (h *CalendarAPIHandler) ExportCalendar(c echo.Context) error {
 		}
 	}
 
-	export := calendar.BuildExport(cal, events, c.QueryParam("events") == "true")
+	format := calendar.ImportFormat(c.QueryParam("format"))
+	export, err := calendar.BuildExportAs(format, cal, events, c.QueryParam("events") == "true")
+	if err != nil {
+		return err
+	}
 	return c.JSON(http.StatusOK, export)
 }
 
@@ -1115,6 +1186,14 @@ func (h *CalendarAPIHandler) ImportCalendar(c echo.Context) error {
 	if err := h.calendarSvc.ApplyImport(ctx, cal.ID, result); err != nil {
 		return err
 	}
+	var userID string
+	if key := GetAPIKey(c); key != nil {
+		userID = key.UserID
+	}
+	eventCount, evErr := h.calendarSvc.ImportEvents(ctx, cal.ID, userID, result.Events)
+	if evErr != nil {
+		slog.Warn("api: event import interrupted", slog.Any("error", evErr))
+	}
 
 	status := http.StatusOK
 	if autoCreated {
@@ -1129,6 +1208,7 @@ func (h *CalendarAPIHandler) ImportCalendar(c echo.Context) error {
 		"moons":        len(result.Moons),
 		"seasons":      len(result.Seasons),
 		"eras":         len(result.Eras),
+		"events":       eventCount,
 		"auto_created": autoCreated,
 	})
 }
@@ -107,6 +107,10 @@ func (s *stubCalendarSvc) SetEventCategories(context.Context, string, []calendar
 func (s *stubCalendarSvc) GetEventCategories(context.Context, string) ([]calendar.EventCategory, error) {
 	return nil, nil
 }
+func (s *stubCalendarSvc) UpsertEventCategory(context.Context, string, calendar.EventCategoryInput) error {
+	return nil
+}
+func (s *stubCalendarSvc) DeleteEventCategory(context.Context, string, string) error { return nil }
 func (s *stubCalendarSvc) GetWeather(context.Context, string) (*calendar.Weather, error) {
 	return nil, nil
 }
@@ -146,6 +150,18 @@ func (s *stubCalendarSvc) GetFestivals(context.Context, string) ([]calendar.Fest
 func (s *stubCalendarSvc) SetFestivals(context.Context, string, []calendar.FestivalInput) error {
 	return nil
 }
+func (s *stubCalendarSvc) GetTimePresets(context.Context, string) ([]calendar.TimePreset, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SetTimePresets(context.Context, string, []calendar.TimePresetInput) error {
+	return nil
+}
+func (s *stubCalendarSvc) GetYearNames(context.Context, string) ([]calendar.YearName, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SetYearNames(context.Context, string, []calendar.YearNameInput) error {
+	return nil
+}
 func (s *stubCalendarSvc) CreateEvent(context.Context, string, calendar.CreateEventInput) (*calendar.Event, error) {
 	return nil, nil
 }
@@ -174,6 +190,12 @@ func (s *stubCalendarSvc) ListEventsForYear(context.Context, string, int, int, s
 func (s *stubCalendarSvc) ListEventsForDateRange(context.Context, string, int, int, int, int, int, int, string) ([]calendar.Event, error) {
 	return nil, nil
 }
+func (s *stubCalendarSvc) ListEntityAnniversaries(context.Context, string, int, int, int, int, string) ([]calendar.Event, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SyncEntityDateEvents(context.Context, string, calendar.EntityDateSync) error {
+	return nil
+}
 func (s *stubCalendarSvc) ListAllEventsForCalendar(context.Context, string) ([]calendar.Event, error) {
 	// C-CALENDAR-ENDPOINTS added this to CalendarService; syncapi
 	// doesn't use it. Zero-value return is fine for these tests.
@@ -182,20 +204,71 @@ func (s *stubCalendarSvc) ListAllEventsForCalendar(context.Context, string) ([]c
 func (s *stubCalendarSvc) SearchCalendarEvents(context.Context, string, string, int) ([]map[string]string, error) {
 	return nil, nil
 }
-func (s *stubCalendarSvc) AdvanceDate(context.Context, string, int) error          { return nil }
-func (s *stubCalendarSvc) AdvanceTime(context.Context, string, int, int) error     { return nil }
+func (s *stubCalendarSvc) AdvanceDate(context.Context, string, string, int) error  { return nil }
+func (s *stubCalendarSvc) AdvanceTime(context.Context, string, string, int, int) error { return nil }
+func (s *stubCalendarSvc) ListDateHistory(context.Context, string) ([]calendar.DateAdvancement, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) UndoLastAdvancement(context.Context, string) (*calendar.DateAdvancement, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) ListLocationZones(context.Context, string) ([]calendar.LocationZone, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SetLocationZones(context.Context, string, []calendar.LocationZone) error {
+	return nil
+}
+func (s *stubCalendarSvc) SetZoneWeather(context.Context, string, string, calendar.WeatherInput) error {
+	return nil
+}
+func (s *stubCalendarSvc) RegionalClimate(context.Context, string) ([]calendar.RegionClimate, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) ClimateForLocation(context.Context, string, string) (*calendar.LocationClimate, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) MyUpcomingEvents(context.Context, string, []calendar.MyEventsCampaign) ([]calendar.MyEventsCalendar, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SetCategoryHidden(context.Context, string, string, string, bool) error {
+	return nil
+}
 func (s *stubCalendarSvc) SetDate(context.Context, string, int, int, int, int, int) error {
 	return nil
 }
+func (s *stubCalendarSvc) ImportEvents(context.Context, string, string, []calendar.ExportEvent) (int, error) {
+	return 0, nil
+}
+func (s *stubCalendarSvc) BulkCreateEvents(context.Context, string, string, []calendar.ExportEvent) (*calendar.BulkEventResult, error) {
+	return &calendar.BulkEventResult{}, nil
+}
+func (s *stubCalendarSvc) EntityEventBacklinks(context.Context, string, string, int, string) ([]map[string]string, error) {
+	return nil, nil
+}
 func (s *stubCalendarSvc) ListAllEvents(context.Context, string) ([]calendar.Event, error) {
 	return nil, nil
 }
 func (s *stubCalendarSvc) SetEventPublisher(calendar.CalendarEventPublisher) {}
+func (s *stubCalendarSvc) SetDateStructureListener(calendar.DateStructureListener) {}
+func (s *stubCalendarSvc) SetDateFieldScanner(calendar.DateFieldScanner) {}
+func (s *stubCalendarSvc) PreviewMonthsImpact(context.Context, string, []calendar.MonthInput) (*calendar.StructureImpact, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) PreviewWeekdaysImpact(context.Context, string, []calendar.WeekdayInput) (*calendar.StructureImpact, error) {
+	return nil, nil
+}
+func (s *stubCalendarSvc) SetMonthsWithRemedy(context.Context, string, []calendar.MonthInput, string) error {
+	return nil
+}
+func (s *stubCalendarSvc) SetWeekdaysWithRemedy(context.Context, string, []calendar.WeekdayInput, string) error {
+	return nil
+}
 
 // C-CAL-ENTITY-TIES-DATA-MODEL added these to CalendarService; syncapi
 // doesn't use them. Zero-value returns are fine for these tests.
 func (s *stubCalendarSvc) LinkEntityToEvent(context.Context, string, string, string) error { return nil }
 func (s *stubCalendarSvc) UnlinkEntityFromEvent(context.Context, string, string) error      { return nil }
+func (s *stubCalendarSvc) EnsureEventEntityLinks(context.Context) (int, error)              { return 0, nil }
 func (s *stubCalendarSvc) LinkEntityToEra(context.Context, string, int, *string) error       { return nil }
 func (s *stubCalendarSvc) UnlinkEntityFromEra(context.Context, string, int) error            { return nil }
 func (s *stubCalendarSvc) EventsForEntity(context.Context, string) ([]calendar.EntityEventTie, error) {
@@ -0,0 +1,209 @@
+package syncapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+func TestRequireAPIKey_DeviceBinding(t *testing.T) {
+	rawKey := "chron_devicekey90123456789012345678901234567890123456789012345ab"
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	laptop := "fp-laptop"
+
+	for _, tc := range []struct {
+		name       string
+		bound      *string // Fingerprint on the key when the request arrives.
+		raceWinner *string // Fingerprint bound by a concurrent request, if any.
+		header     string
+		wantStatus int
+		wantBind   bool
+		wantReason string
+	}{
+		{name: "unbound without fingerprint", header: "", wantStatus: http.StatusOK},
+		{name: "first use binds", header: laptop, wantStatus: http.StatusOK, wantBind: true},
+		{name: "bound device", bound: &laptop, header: laptop, wantStatus: http.StatusOK},
+		{name: "other device", bound: &laptop, header: "fp-desktop", wantStatus: http.StatusForbidden, wantReason: "device fingerprint mismatch"},
+		{name: "missing fingerprint", bound: &laptop, header: "", wantStatus: http.StatusForbidden, wantReason: "device fingerprint missing"},
+		{name: "lost bind race to same device", raceWinner: &laptop, header: laptop, wantStatus: http.StatusOK, wantBind: true},
+		{name: "lost bind race to other device", raceWinner: &laptop, header: "fp-desktop", wantStatus: http.StatusForbidden, wantBind: true, wantReason: "device fingerprint mismatch"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var bound string
+			var events []*SecurityEvent
+			repo := &mockSyncAPIRepo{
+				findKeyByPrefixFn: func(_ context.Context, prefix string) (*APIKey, error) {
+					return &APIKey{ID: 7, KeyHash: string(hash), KeyPrefix: prefix, CampaignID: "camp-1",
+						UserID: "user-1", IsActive: true, DeviceFingerprint: tc.bound}, nil
+				},
+				findKeyByIDFn: func(_ context.Context, id int) (*APIKey, error) {
+					return &APIKey{ID: id, CampaignID: "camp-1", DeviceFingerprint: tc.raceWinner}, nil
+				},
+				bindDeviceFn: func(_ context.Context, _ int, fingerprint string) error {
+					bound = fingerprint
+					if tc.raceWinner != nil {
+						return ErrDeviceAlreadyBound
+					}
+					return nil
+				},
+				logSecurityEventFn: func(_ context.Context, e *SecurityEvent) error {
+					events = append(events, e)
+					return nil
+				},
+			}
+			e := echo.New()
+			e.HTTPErrorHandler = func(err error, c echo.Context) {
+				code := http.StatusInternalServerError
+				var appErr *apperror.AppError
+				if errors.As(err, &appErr) {
+					code = appErr.Code
+				}
+				_ = c.NoContent(code)
+			}
+			e.GET("/api/v1/campaigns/:id/entities", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, RequireAPIKey(NewSyncAPIService(repo)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/campaigns/camp-1/entities", nil)
+			req.Header.Set("Authorization", "Bearer "+rawKey)
+			if tc.header != "" {
+				req.Header.Set("X-Device-Fingerprint", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if (bound != "") != tc.wantBind {
+				t.Errorf("bound %q, want bind %v", bound, tc.wantBind)
+			}
+			if tc.wantReason == "" {
+				if len(events) != 0 {
+					t.Errorf("unexpected security events: %+v", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].EventType != EventSuspicious || events[0].Details["reason"] != tc.wantReason {
+				t.Errorf("events = %+v, want one suspicious %q", events, tc.wantReason)
+			}
+		})
+	}
+}
+
+// TestAuthenticateKeyForWS_DeviceBinding: the WebSocket handshake enforces
+// the binding as RequireAPIKey does and logs the same security event.
+func TestAuthenticateKeyForWS_DeviceBinding(t *testing.T) {
+	rawKey := "chron_devicekey90123456789012345678901234567890123456789012345ab"
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	laptop := "fp-laptop"
+
+	for _, tc := range []struct {
+		name        string
+		fingerprint string
+		wantErr     bool
+		wantReason  string
+	}{
+		{name: "bound device", fingerprint: laptop},
+		{name: "other device", fingerprint: "fp-desktop", wantErr: true, wantReason: "device fingerprint mismatch"},
+		{name: "missing fingerprint", wantErr: true, wantReason: "device fingerprint missing"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []*SecurityEvent
+			svc := NewSyncAPIService(&mockSyncAPIRepo{
+				findKeyByPrefixFn: func(_ context.Context, prefix string) (*APIKey, error) {
+					return &APIKey{ID: 7, KeyHash: string(hash), KeyPrefix: prefix, CampaignID: "camp-1",
+						UserID: "user-1", IsActive: true, DeviceFingerprint: &laptop}, nil
+				},
+				logSecurityEventFn: func(_ context.Context, e *SecurityEvent) error {
+					events = append(events, e)
+					return nil
+				},
+			})
+
+			campaignID, _, _, err := svc.AuthenticateKeyForWS(context.Background(), rawKey, tc.fingerprint, "203.0.113.7", "FoundryVTT")
+			if tc.wantErr {
+				assertAppError(t, err, http.StatusForbidden)
+			} else if err != nil || campaignID != "camp-1" {
+				t.Fatalf("AuthenticateKeyForWS = %q, %v", campaignID, err)
+			}
+			if tc.wantReason == "" {
+				if len(events) != 0 {
+					t.Errorf("unexpected security events: %+v", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].EventType != EventSuspicious || events[0].Details["reason"] != tc.wantReason ||
+				events[0].IPAddress != "203.0.113.7" {
+				t.Errorf("events = %+v, want one suspicious %q from the client IP", events, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestUnbindKeyDevice(t *testing.T) {
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/campaigns/camp-1/api-keys/7/unbind-device", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id", "keyID")
+		c.SetParamValues("camp-1", "7")
+		c.Set("campaign_context", &campaigns.CampaignContext{
+			Campaign:   &campaigns.Campaign{ID: "camp-1"},
+			MemberRole: campaigns.RoleOwner,
+			IsMember:   true,
+		})
+		return c, rec
+	}
+
+	t.Run("own key", func(t *testing.T) {
+		var unbound []int
+		repo := &mockSyncAPIRepo{
+			findKeyByIDFn: func(_ context.Context, id int) (*APIKey, error) {
+				return &APIKey{ID: id, CampaignID: "camp-1"}, nil
+			},
+			unbindDeviceFn: func(_ context.Context, keyID int) error {
+				unbound = append(unbound, keyID)
+				return nil
+			},
+		}
+		c, rec := newContext()
+		if err := NewHandler(NewSyncAPIService(repo)).UnbindKeyDevice(c); err != nil {
+			t.Fatalf("UnbindKeyDevice: %v", err)
+		}
+		if len(unbound) != 1 || unbound[0] != 7 {
+			t.Errorf("unbound = %v, want [7]", unbound)
+		}
+		if got := rec.Header().Get(echo.HeaderLocation); got != "/campaigns/camp-1/api-keys" {
+			t.Errorf("redirect = %q", got)
+		}
+	})
+
+	t.Run("other campaign's key", func(t *testing.T) {
+		repo := &mockSyncAPIRepo{
+			findKeyByIDFn: func(_ context.Context, id int) (*APIKey, error) {
+				return &APIKey{ID: id, CampaignID: "camp-2"}, nil
+			},
+			unbindDeviceFn: func(context.Context, int) error {
+				t.Error("unbound a key from another campaign")
+				return nil
+			},
+		}
+		c, _ := newContext()
+		assertAppError(t, NewHandler(NewSyncAPIService(repo)).UnbindKeyDevice(c), http.StatusNotFound)
+	})
+}
@@ -399,6 +399,16 @@ func TestEgressSanitize_HandlersInvokeHelpers(t *testing.T) {
 		// secret stripper, or a player-role caller reads raw GM prose.
 		{"api_handler.go", "GetEntity", "stripEntitySecretsForEgress"},
 		{"api_handler.go", "ListEntities", "stripEntitiesSecretsForEgress"},
+		// Companion-app shapes ride the same egress rules.
+		{"mobile_api_handler.go", "MobileGetEntity", "sanitizeEntityHTMLForEgress"},
+		{"mobile_api_handler.go", "MobileGetEntity", "stripEntitySecretsForEgress"},
+		{"mobile_api_handler.go", "MobileGetEntity", "stripEntityFieldsForEgress"},
+		{"mobile_api_handler.go", "MobileListEntities", "sanitizeEntitiesHTMLForEgress"},
+		{"mobile_api_handler.go", "MobileListEntities", "stripEntitiesSecretsForEgress"},
+		// Obsidian notes are pages rendered as Markdown.
+		{"obsidian_api_handler.go", "ObsidianChanges", "sanitizeEntitiesHTMLForEgress"},
+		{"obsidian_api_handler.go", "ObsidianChanges", "stripEntitiesSecretsForEgress"},
+		{"obsidian_api_handler.go", "ObsidianChanges", "stripEntitiesFieldsForEgress"},
 		{"note_api_handler.go", "GetNote", "sanitizeNoteHTMLForEgress"},
 		{"note_api_handler.go", "ListNotes", "sanitizeNotesHTMLForEgress"},
 		{"calendar_api_handler.go", "GetEvent", "sanitizeCalendarEventHTMLForEgress"},
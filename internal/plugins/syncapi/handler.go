@@ -119,6 +119,9 @@ func (h *Handler) CreateKey(c echo.Context) error {
 		if c.FormValue("perm_sync") == "on" {
 			perms = append(perms, PermSync)
 		}
+		if c.FormValue("perm_automation") == "on" {
+			perms = append(perms, PermAutomation)
+		}
 	}
 
 	rateLimit := 60
@@ -169,6 +172,30 @@ func (h *Handler) CreateKey(c echo.Context) error {
 	return middleware.Render(c, http.StatusOK, KeyCreatedTempl(cc.Campaign.ID, result, csrfToken))
 }
 
+// CreateDemoKey handles POST /campaigns/:id/api-keys/demo: a read-only demo
+// key that expires after the chosen number of hours.
+func (h *Handler) CreateDemoKey(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+
+	hours, err := strconv.Atoi(c.FormValue("lifetime_hours"))
+	if err != nil {
+		return apperror.NewBadRequest("choose a demo key lifetime")
+	}
+	result, err := h.service.CreateDemoKey(c.Request().Context(), auth.GetUserID(c), cc.Campaign.ID,
+		c.FormValue("name"), time.Duration(hours)*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	if middleware.IsHTMX(c) {
+		return middleware.Render(c, http.StatusOK, KeyCreatedFragmentTempl(cc.Campaign.ID, result))
+	}
+	return middleware.Render(c, http.StatusOK, KeyCreatedTempl(cc.Campaign.ID, result, middleware.GetCSRFToken(c)))
+}
+
 // ToggleKey handles PUT /campaigns/:id/api-keys/:keyID/toggle.
 func (h *Handler) ToggleKey(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -207,6 +234,38 @@ func (h *Handler) ToggleKey(c echo.Context) error {
 	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/api-keys")
 }
 
+// UnbindKeyDevice handles POST /campaigns/:id/api-keys/:keyID/unbind-device.
+// Clears the key's device binding so the next device to use it binds anew,
+// e.g. after the owner moves Foundry to another machine.
+func (h *Handler) UnbindKeyDevice(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+
+	keyID, err := strconv.Atoi(c.Param("keyID"))
+	if err != nil {
+		return apperror.NewBadRequest("invalid key ID")
+	}
+
+	ctx := c.Request().Context()
+
+	// IDOR protection: verify key belongs to this campaign.
+	key, err := h.service.GetKey(ctx, keyID)
+	if err != nil || key.CampaignID != cc.Campaign.ID {
+		return apperror.NewNotFound("api key not found")
+	}
+
+	if err := h.service.UnbindDevice(ctx, keyID); err != nil {
+		return err
+	}
+
+	if c.Request().Header.Get("HX-Target") == "integrations-keys" {
+		return h.IntegrationsKeysFragment(c)
+	}
+	return middleware.HTMXRedirect(c, "/campaigns/"+cc.Campaign.ID+"/api-keys")
+}
+
 // RevokeKey handles DELETE /campaigns/:id/api-keys/:keyID.
 func (h *Handler) RevokeKey(c echo.Context) error {
 	cc := campaigns.GetCampaignContext(c)
@@ -601,3 +660,30 @@ type AdminDashboardData struct {
 	CORSOrigins        []string
 	CSRFToken          string
 }
+
+// SettingsSections returns the factories syncapi registers with the
+// campaigns Settings page: connection status + API keys above the
+// Integrations tab's built-in cards, sync management below them. Both
+// are owner-only, matching their fragment routes' RequireRole.
+func (h *Handler) SettingsSections() []func(*campaigns.CampaignContext) campaigns.SettingsSection {
+	return []func(*campaigns.CampaignContext) campaigns.SettingsSection{
+		func(cc *campaigns.CampaignContext) campaigns.SettingsSection {
+			return campaigns.SettingsSection{
+				Tab:         "integrations",
+				ID:          "integrations-keys",
+				MinRole:     campaigns.RoleOwner,
+				SortOrder:   10,
+				FragmentURL: fmt.Sprintf("/campaigns/%s/integrations/keys", cc.Campaign.ID),
+			}
+		},
+		func(cc *campaigns.CampaignContext) campaigns.SettingsSection {
+			return campaigns.SettingsSection{
+				Tab:         "integrations",
+				ID:          "sync-overview",
+				MinRole:     campaigns.RoleOwner,
+				SortOrder:   60,
+				FragmentURL: fmt.Sprintf("/campaigns/%s/api-keys/sync-overview", cc.Campaign.ID),
+			}
+		},
+	}
+}
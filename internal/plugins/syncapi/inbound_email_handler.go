@@ -0,0 +1,160 @@
+package syncapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// inboundMaxMessageSize caps a raw email, attachments included.
+const inboundMaxMessageSize = 10 << 20
+
+// inboundFormFields are the multipart fields inbound-mail providers put
+// the raw message in (SendGrid's "email", Mailgun's "body-mime").
+var inboundFormFields = []string{"email", "body-mime"}
+
+// InboundEmailHandler serves the email-in webhook and its settings card.
+type InboundEmailHandler struct {
+	svc     InboundEmailService
+	baseURL string
+	domain  string
+}
+
+// NewInboundEmailHandler creates the email-in handler. domain is the mail
+// domain addresses are shown on; empty means only the webhook URL is
+// shown.
+func NewInboundEmailHandler(svc InboundEmailService, baseURL, domain string) *InboundEmailHandler {
+	return &InboundEmailHandler{svc: svc, baseURL: baseURL, domain: domain}
+}
+
+// readInboundMessage returns the raw email from a webhook request: a
+// multipart form field for providers that post forms, otherwise the body.
+func readInboundMessage(c echo.Context) ([]byte, error) {
+	req := c.Request()
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+		for _, field := range inboundFormFields {
+			if v := c.FormValue(field); v != "" {
+				return []byte(v), nil
+			}
+		}
+		return nil, apperror.NewBadRequest("no raw email in the form")
+	}
+	raw, err := io.ReadAll(io.LimitReader(req.Body, inboundMaxMessageSize+1))
+	if err != nil {
+		return nil, apperror.NewBadRequest("failed to read email")
+	}
+	if len(raw) > inboundMaxMessageSize {
+		return nil, &apperror.AppError{Code: http.StatusRequestEntityTooLarge, Type: "too_large", Message: "email is too large"}
+	}
+	return raw, nil
+}
+
+// Receive accepts one raw email from a mail provider. The address token
+// comes from the URL, or from the recipients when the provider posts the
+// whole domain to one URL.
+// POST /api/inbound/email, POST /api/inbound/email/:token
+func (h *InboundEmailHandler) Receive(c echo.Context) error {
+	raw, err := readInboundMessage(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.svc.Receive(c.Request().Context(), c.Param("token"), raw)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// renderSettings renders the email-in card for the campaign.
+func (h *InboundEmailHandler) renderSettings(c echo.Context, campaignID string) error {
+	ctx := c.Request().Context()
+	in, err := h.svc.GetConfig(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	categories, err := h.svc.ListCategories(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	webhookURL := ""
+	if in != nil {
+		webhookURL = h.baseURL + "/api/inbound/email/" + in.Token
+	}
+	return middleware.Render(c, http.StatusOK, InboundEmailFragmentTempl(campaignID, in, categories, h.domain, webhookURL, middleware.GetCSRFToken(c)))
+}
+
+// SettingsFragment renders the email-in card on Settings > Integrations.
+// GET /campaigns/:id/integrations/email
+func (h *InboundEmailHandler) SettingsFragment(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+	return h.renderSettings(c, cc.Campaign.ID)
+}
+
+// Save turns email-in on with the chosen category.
+// POST /campaigns/:id/integrations/email
+func (h *InboundEmailHandler) Save(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+	typeID, err := strconv.Atoi(c.FormValue("entity_type_id"))
+	if err != nil {
+		return apperror.NewBadRequest("choose a category for emailed pages")
+	}
+	if _, err := h.svc.Enable(c.Request().Context(), cc.Campaign.ID, auth.GetUserID(c), typeID); err != nil {
+		return err
+	}
+	return h.renderSettings(c, cc.Campaign.ID)
+}
+
+// Disable stops accepting email for the campaign.
+// DELETE /campaigns/:id/integrations/email
+func (h *InboundEmailHandler) Disable(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+	if err := h.svc.Disable(c.Request().Context(), cc.Campaign.ID); err != nil {
+		return err
+	}
+	return h.renderSettings(c, cc.Campaign.ID)
+}
+
+// Rotate replaces the campaign's address with a new one.
+// POST /campaigns/:id/integrations/email/rotate
+func (h *InboundEmailHandler) Rotate(c echo.Context) error {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return apperror.NewForbidden("campaign context required")
+	}
+	if _, err := h.svc.RotateToken(c.Request().Context(), cc.Campaign.ID); err != nil {
+		return err
+	}
+	return h.renderSettings(c, cc.Campaign.ID)
+}
+
+// SettingsSection returns the factory for the email-in card on the
+// Integrations tab, below the API keys. Owner-only like its routes.
+func (h *InboundEmailHandler) SettingsSection() func(*campaigns.CampaignContext) campaigns.SettingsSection {
+	return func(cc *campaigns.CampaignContext) campaigns.SettingsSection {
+		return campaigns.SettingsSection{
+			Tab:         "integrations",
+			ID:          "integrations-email",
+			MinRole:     campaigns.RoleOwner,
+			SortOrder:   20,
+			FragmentURL: fmt.Sprintf("/campaigns/%s/integrations/email", cc.Campaign.ID),
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package syncapi
+
+// inbound_email_parse.go — reads a raw RFC 822 message into the parts
+// email-in uses: the sender, subject, recipients, a text and/or HTML body
+// and the attachments. Bodies in UTF-8, ASCII and Latin-1 are decoded;
+// other charsets pass through with invalid bytes replaced.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	// inboundMaxAttachments caps the files kept from one email.
+	inboundMaxAttachments = 10
+
+	// inboundMaxDepth caps multipart nesting (a forwarded message inside
+	// an alternative inside a mixed part is already three).
+	inboundMaxDepth = 5
+)
+
+// inboundRecipientHeaders are the headers searched for the campaign
+// address when the webhook URL carries no token.
+var inboundRecipientHeaders = []string{"To", "Cc", "Delivered-To", "X-Original-To"}
+
+// inboundEmail is a parsed email.
+type inboundEmail struct {
+	From        string // bare address, lower-cased
+	Subject     string
+	MessageID   string // without angle brackets
+	Recipients  []string
+	Text        string
+	HTML        string
+	Attachments []inboundAttachment
+}
+
+// inboundAttachment is one file from an email.
+type inboundAttachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// parseInboundEmail parses a raw message. Only a missing or malformed
+// From address is an error; an email may have no subject or body.
+func parseInboundEmail(raw []byte) (*inboundEmail, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("not an email message")
+	}
+	dec := new(mime.WordDecoder)
+	parser := &mail.AddressParser{WordDecoder: dec}
+
+	from, err := parser.Parse(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid From address")
+	}
+	subject := msg.Header.Get("Subject")
+	if decoded, err := dec.DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+
+	e := &inboundEmail{
+		From:      strings.ToLower(from.Address),
+		Subject:   strings.TrimSpace(subject),
+		MessageID: strings.Trim(strings.TrimSpace(msg.Header.Get("Message-Id")), "<>"),
+	}
+	for _, name := range inboundRecipientHeaders {
+		for _, value := range msg.Header[textproto.CanonicalMIMEHeaderKey(name)] {
+			list, err := parser.ParseList(value)
+			if err != nil {
+				continue
+			}
+			for _, addr := range list {
+				e.Recipients = append(e.Recipients, strings.ToLower(addr.Address))
+			}
+		}
+	}
+
+	if err := e.readPart(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// readPart walks one MIME part: multiparts recurse, the first text/plain
+// and text/html parts become the body and everything else with a file
+// name (or an attachment disposition) becomes an attachment.
+func (e *inboundEmail) readPart(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= inboundMaxDepth || params["boundary"] == "" {
+			return nil
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("malformed multipart email")
+			}
+			if err := e.readPart(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dparams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+
+	isAttachment := name != "" || disposition == "attachment"
+	switch {
+	case isAttachment && len(e.Attachments) >= inboundMaxAttachments:
+		return nil
+	case isAttachment:
+	case mediaType == "text/plain" && e.Text == "":
+	case mediaType == "text/html" && e.HTML == "":
+	default:
+		return nil
+	}
+
+	data, err := io.ReadAll(decodeTransfer(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("malformed email part")
+	}
+	if !isAttachment {
+		text := decodeCharset(data, params["charset"])
+		if mediaType == "text/plain" {
+			e.Text = text
+		} else {
+			e.HTML = text
+		}
+		return nil
+	}
+	if name == "" {
+		name = "attachment"
+	}
+	e.Attachments = append(e.Attachments, inboundAttachment{Name: name, MimeType: mediaType, Data: data})
+	return nil
+}
+
+// decodeTransfer undoes a part's Content-Transfer-Encoding. multipart
+// already strips quoted-printable from parts, but a single-part message's
+// body arrives encoded.
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeCharset turns body bytes into a valid UTF-8 string.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	default:
+		return strings.ToValidUTF8(string(data), "�")
+	}
+}
+
+// inboundBodyText trims an email's text body: line endings are
+// normalised and everything from the "-- " signature delimiter on is
+// dropped. The delimiter's trailing space is matched loosely because
+// quoted-printable decoding strips it.
+func inboundBodyText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " ") == "--" {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
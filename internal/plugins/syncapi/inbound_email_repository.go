@@ -0,0 +1,114 @@
+package syncapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// InboundEmailRepository defines data access for campaign email-in
+// addresses and the log of messages already received.
+type InboundEmailRepository interface {
+	GetByCampaign(ctx context.Context, campaignID string) (*InboundEmail, error)
+	GetByToken(ctx context.Context, token string) (*InboundEmail, error)
+	Upsert(ctx context.Context, in *InboundEmail) error
+	SetEnabled(ctx context.Context, campaignID string, enabled bool) error
+	MessageSeen(ctx context.Context, campaignID, messageID string) (bool, error)
+	RecordMessage(ctx context.Context, campaignID, messageID, resultType, resultID string) error
+}
+
+// inboundEmailRepo implements InboundEmailRepository with MariaDB.
+type inboundEmailRepo struct {
+	db *sql.DB
+}
+
+// NewInboundEmailRepository creates a new email-in repository.
+func NewInboundEmailRepository(db *sql.DB) InboundEmailRepository {
+	return &inboundEmailRepo{db: db}
+}
+
+const inboundEmailColumns = `campaign_id, token, entity_type_id, enabled, created_by, created_at, updated_at`
+
+// scanInboundEmail reads one sync_inbound_email row, returning nil when
+// there is none.
+func scanInboundEmail(row *sql.Row) (*InboundEmail, error) {
+	var in InboundEmail
+	err := row.Scan(&in.CampaignID, &in.Token, &in.EntityTypeID, &in.Enabled,
+		&in.CreatedBy, &in.CreatedAt, &in.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan inbound email: %w", err)
+	}
+	return &in, nil
+}
+
+// GetByCampaign returns the campaign's email-in address, or nil if the
+// owner never set one up.
+func (r *inboundEmailRepo) GetByCampaign(ctx context.Context, campaignID string) (*InboundEmail, error) {
+	return scanInboundEmail(r.db.QueryRowContext(ctx,
+		`SELECT `+inboundEmailColumns+` FROM sync_inbound_email WHERE campaign_id = ?`, campaignID))
+}
+
+// GetByToken returns the address with the given token, or nil.
+func (r *inboundEmailRepo) GetByToken(ctx context.Context, token string) (*InboundEmail, error) {
+	return scanInboundEmail(r.db.QueryRowContext(ctx,
+		`SELECT `+inboundEmailColumns+` FROM sync_inbound_email WHERE token = ?`, token))
+}
+
+// Upsert creates or replaces the campaign's address. created_by and
+// created_at keep their first values.
+func (r *inboundEmailRepo) Upsert(ctx context.Context, in *InboundEmail) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sync_inbound_email (campaign_id, token, entity_type_id, enabled, created_by)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			token = VALUES(token),
+			entity_type_id = VALUES(entity_type_id),
+			enabled = VALUES(enabled)`,
+		in.CampaignID, in.Token, in.EntityTypeID, in.Enabled, in.CreatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert inbound email: %w", err)
+	}
+	return nil
+}
+
+// SetEnabled turns the campaign's address on or off without touching its
+// token, so re-enabling brings back the same address.
+func (r *inboundEmailRepo) SetEnabled(ctx context.Context, campaignID string, enabled bool) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sync_inbound_email SET enabled = ? WHERE campaign_id = ?`, enabled, campaignID)
+	if err != nil {
+		return fmt.Errorf("set inbound email enabled: %w", err)
+	}
+	return nil
+}
+
+// MessageSeen reports whether a Message-ID was already turned into content.
+func (r *inboundEmailRepo) MessageSeen(ctx context.Context, campaignID, messageID string) (bool, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sync_inbound_email_messages WHERE campaign_id = ? AND message_id = ?`,
+		campaignID, messageID,
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check inbound message: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordMessage logs a received Message-ID and what it became.
+func (r *inboundEmailRepo) RecordMessage(ctx context.Context, campaignID, messageID, resultType, resultID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT IGNORE INTO sync_inbound_email_messages (campaign_id, message_id, result_type, result_id)
+		VALUES (?, ?, ?, ?)`,
+		campaignID, messageID, resultType, resultID,
+	)
+	if err != nil {
+		return fmt.Errorf("record inbound message: %w", err)
+	}
+	return nil
+}
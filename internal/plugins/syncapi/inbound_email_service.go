@@ -0,0 +1,423 @@
+package syncapi
+
+// inbound_email_service.go — email-in: each campaign can have a secret
+// inbound address (<token>@INBOUND_EMAIL_DOMAIN). A mail provider's inbound
+// webhook posts the raw message here; an email from a campaign member
+// becomes a draft page in the owner's chosen category, or, with a
+// "Session:" subject, is appended to the latest session's recap.
+// Attachments are uploaded to the campaign's media and linked from the
+// new content.
+//
+// The From header alone is easy to forge, so the secret address is the
+// real credential; the member allowlist stops strangers who learn it and
+// relies on the provider rejecting mail that fails SPF/DKIM.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/plugins/media"
+)
+
+// inboundTokenBytes is the entropy of an inbound address token.
+const inboundTokenBytes = 16
+
+// inboundSessionPrefix marks a subject as a session recap appendix.
+const inboundSessionPrefix = "session:"
+
+// inboundMessageIDMax matches the message_id column width.
+const inboundMessageIDMax = 255
+
+// inboundTokenEncoding is lower-case base32: safe in an email local part,
+// which some servers lower-case in transit.
+var inboundTokenEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// InboundEmail is a campaign's email-in address.
+type InboundEmail struct {
+	CampaignID   string
+	Token        string
+	EntityTypeID int
+	Enabled      bool
+	CreatedBy    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Address returns the full inbound address on domain.
+func (in *InboundEmail) Address(domain string) string {
+	return in.Token + "@" + domain
+}
+
+// InboundEmailResult reports what a received email became.
+type InboundEmailResult struct {
+	Kind        string   `json:"kind"` // "page" or "session"
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Attachments int      `json:"attachments"`
+	Skipped     []string `json:"skipped,omitempty"`
+}
+
+// SessionRecapAppender appends HTML to the recap of a campaign's latest
+// session. Implemented in app wiring over the sessions plugin, which
+// syncapi must not import.
+type SessionRecapAppender interface {
+	AppendToLatestRecap(ctx context.Context, campaignID, html string) (sessionID, sessionName string, err error)
+}
+
+// InboundEmailService manages email-in addresses and turns received
+// emails into campaign content.
+type InboundEmailService interface {
+	GetConfig(ctx context.Context, campaignID string) (*InboundEmail, error)
+	Enable(ctx context.Context, campaignID, userID string, entityTypeID int) (*InboundEmail, error)
+	Disable(ctx context.Context, campaignID string) error
+	RotateToken(ctx context.Context, campaignID string) (*InboundEmail, error)
+	ListCategories(ctx context.Context, campaignID string) ([]entities.EntityType, error)
+	Receive(ctx context.Context, token string, raw []byte) (*InboundEmailResult, error)
+}
+
+// inboundEmailService implements InboundEmailService.
+type inboundEmailService struct {
+	repo         InboundEmailRepository
+	entitySvc    entities.EntityService
+	campaignSvc  campaigns.CampaignService
+	mediaSvc     media.MediaService
+	markdownConv MarkdownEntryConverter
+	sessions     SessionRecapAppender
+}
+
+// NewInboundEmailService creates the email-in service. sessions may be nil,
+// in which case "Session:" emails are refused.
+func NewInboundEmailService(repo InboundEmailRepository, entitySvc entities.EntityService, campaignSvc campaigns.CampaignService, mediaSvc media.MediaService, markdownConv MarkdownEntryConverter, sessions SessionRecapAppender) InboundEmailService {
+	return &inboundEmailService{
+		repo:         repo,
+		entitySvc:    entitySvc,
+		campaignSvc:  campaignSvc,
+		mediaSvc:     mediaSvc,
+		markdownConv: markdownConv,
+		sessions:     sessions,
+	}
+}
+
+// newInboundToken returns a fresh random address token.
+func newInboundToken() (string, error) {
+	b := make([]byte, inboundTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate inbound token: %w", err)
+	}
+	return inboundTokenEncoding.EncodeToString(b), nil
+}
+
+// GetConfig returns the campaign's address, or nil if none was set up.
+func (s *inboundEmailService) GetConfig(ctx context.Context, campaignID string) (*InboundEmail, error) {
+	return s.repo.GetByCampaign(ctx, campaignID)
+}
+
+// ListCategories returns the enabled categories new pages can land in.
+func (s *inboundEmailService) ListCategories(ctx context.Context, campaignID string) ([]entities.EntityType, error) {
+	types, err := s.entitySvc.GetEntityTypes(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	var enabled []entities.EntityType
+	for _, et := range types {
+		if et.Enabled {
+			enabled = append(enabled, et)
+		}
+	}
+	return enabled, nil
+}
+
+// findCategory returns the campaign's enabled category with the given ID.
+func (s *inboundEmailService) findCategory(ctx context.Context, campaignID string, id int) (*entities.EntityType, error) {
+	types, err := s.ListCategories(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range types {
+		if types[i].ID == id {
+			return &types[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Enable turns email-in on with the given category, creating the address
+// on first use. An existing address keeps its token.
+func (s *inboundEmailService) Enable(ctx context.Context, campaignID, userID string, entityTypeID int) (*InboundEmail, error) {
+	et, err := s.findCategory(ctx, campaignID, entityTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if et == nil {
+		return nil, apperror.NewBadRequest("choose a category for emailed pages")
+	}
+	in, err := s.repo.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if in == nil {
+		token, err := newInboundToken()
+		if err != nil {
+			return nil, apperror.NewInternal(err)
+		}
+		in = &InboundEmail{CampaignID: campaignID, Token: token, CreatedBy: userID}
+	}
+	in.EntityTypeID = et.ID
+	in.Enabled = true
+	if err := s.repo.Upsert(ctx, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// Disable stops accepting email; the address comes back on re-enable.
+func (s *inboundEmailService) Disable(ctx context.Context, campaignID string) error {
+	return s.repo.SetEnabled(ctx, campaignID, false)
+}
+
+// RotateToken replaces the address, retiring the old one at once.
+func (s *inboundEmailService) RotateToken(ctx context.Context, campaignID string) (*InboundEmail, error) {
+	in, err := s.repo.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if in == nil {
+		return nil, apperror.NewNotFound("email-in is not set up for this campaign")
+	}
+	if in.Token, err = newInboundToken(); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if err := s.repo.Upsert(ctx, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// findAddress resolves the campaign address an email was sent to: the
+// webhook URL's token when given, otherwise the first recipient whose
+// local part (or its "+tag") is a known token.
+func (s *inboundEmailService) findAddress(ctx context.Context, token string, recipients []string) (*InboundEmail, error) {
+	if token != "" {
+		return s.repo.GetByToken(ctx, strings.ToLower(token))
+	}
+	for _, addr := range recipients {
+		local, _, ok := strings.Cut(addr, "@")
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(local, "+"); i >= 0 {
+			local = local[i+1:]
+		}
+		if len(local) < inboundTokenEncoding.EncodedLen(inboundTokenBytes) {
+			continue
+		}
+		in, err := s.repo.GetByToken(ctx, local)
+		if err != nil || in != nil {
+			return in, err
+		}
+	}
+	return nil, nil
+}
+
+// findSender returns the campaign member whose account email is from.
+func (s *inboundEmailService) findSender(ctx context.Context, campaignID, from string) (*campaigns.CampaignMember, error) {
+	members, err := s.campaignSvc.ListMembers(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		if members[i].Email != "" && strings.EqualFold(members[i].Email, from) {
+			return &members[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Receive turns one raw email into a draft page or a session recap
+// appendix. token is the address token from the webhook URL, or empty to
+// find it among the recipients.
+func (s *inboundEmailService) Receive(ctx context.Context, token string, raw []byte) (*InboundEmailResult, error) {
+	email, err := parseInboundEmail(raw)
+	if err != nil {
+		return nil, apperror.NewBadRequest(err.Error())
+	}
+	in, err := s.findAddress(ctx, token, email.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	if in == nil || !in.Enabled {
+		return nil, apperror.NewNotFound("unknown inbound address")
+	}
+
+	member, err := s.findSender(ctx, in.CampaignID, email.From)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, apperror.NewForbidden("sender is not a member of this campaign")
+	}
+
+	title, toSession := strings.TrimSpace(email.Subject), false
+	if strings.HasPrefix(strings.ToLower(title), inboundSessionPrefix) {
+		title, toSession = strings.TrimSpace(title[len(inboundSessionPrefix):]), true
+		if s.sessions == nil {
+			return nil, apperror.NewBadRequest("sessions are not available on this server")
+		}
+		if member.Role < campaigns.RoleScribe {
+			return nil, apperror.NewForbidden("only scribes and owners can add to session recaps")
+		}
+	}
+
+	messageID := email.MessageID
+	if len(messageID) > inboundMessageIDMax {
+		messageID = messageID[:inboundMessageIDMax]
+	}
+	if messageID != "" {
+		seen, err := s.repo.MessageSeen(ctx, in.CampaignID, messageID)
+		if err != nil {
+			return nil, err
+		}
+		if seen {
+			return nil, apperror.NewConflict("this email was already received")
+		}
+	}
+
+	body := inboundBodyText(email.Text)
+	if body == "" && email.HTML != "" {
+		if body, err = entryMarkdown(&email.HTML); err != nil {
+			return nil, apperror.NewBadRequest("unreadable email body")
+		}
+	}
+	links, uploaded, skipped := s.uploadAttachments(ctx, in.CampaignID, member.UserID, email.Attachments)
+	if links != "" {
+		body = strings.TrimSpace(body + "\n\n" + links)
+	}
+	if title == "" {
+		title = "Email from " + member.DisplayName
+	}
+
+	var result *InboundEmailResult
+	if toSession {
+		result, err = s.appendToSession(ctx, in.CampaignID, title, body)
+	} else {
+		result, err = s.createDraft(ctx, in, member.UserID, title, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Attachments, result.Skipped = uploaded, skipped
+
+	if messageID != "" {
+		if err := s.repo.RecordMessage(ctx, in.CampaignID, messageID, result.Kind, result.ID); err != nil {
+			slog.Warn("email-in: failed to record message",
+				slog.String("campaign_id", in.CampaignID),
+				slog.Any("error", err),
+			)
+		}
+	}
+	return result, nil
+}
+
+// uploadAttachments stores an email's files in the campaign's media and
+// returns Markdown that embeds images and links everything else. Files of
+// a type media doesn't accept are skipped by name.
+func (s *inboundEmailService) uploadAttachments(ctx context.Context, campaignID, userID string, files []inboundAttachment) (string, int, []string) {
+	var links, skipped []string
+	escape := strings.NewReplacer("[", `\[`, "]", `\]`)
+	for _, f := range files {
+		if s.mediaSvc == nil || !media.AllowedMimeTypes[f.MimeType] {
+			skipped = append(skipped, f.Name)
+			continue
+		}
+		mf, err := s.mediaSvc.Upload(ctx, media.UploadInput{
+			CampaignID:   campaignID,
+			UploadedBy:   userID,
+			OriginalName: f.Name,
+			MimeType:     f.MimeType,
+			FileSize:     int64(len(f.Data)),
+			UsageType:    media.UsageAttachment,
+			FileBytes:    f.Data,
+		})
+		if err != nil {
+			slog.Warn("email-in: attachment upload failed",
+				slog.String("campaign_id", campaignID),
+				slog.String("file", f.Name),
+				slog.Any("error", err),
+			)
+			skipped = append(skipped, f.Name)
+			continue
+		}
+		link := fmt.Sprintf("[%s](/media/%s)", escape.Replace(f.Name), mf.ID)
+		if strings.HasPrefix(f.MimeType, "image/") {
+			link = "!" + link
+		}
+		links = append(links, link)
+	}
+	return strings.Join(links, "\n\n"), len(links), skipped
+}
+
+// createDraft creates the email as a draft page in the address's category,
+// owned by the sender so it shows in their drafts.
+func (s *inboundEmailService) createDraft(ctx context.Context, in *InboundEmail, userID, title, body string) (*InboundEmailResult, error) {
+	et, err := s.findCategory(ctx, in.CampaignID, in.EntityTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if et == nil {
+		return nil, apperror.NewBadRequest("the email-in category no longer exists")
+	}
+	var entryJSON, entryHTML string
+	if body != "" {
+		if entryJSON, entryHTML, err = s.markdownConv.ConvertMarkdown(body); err != nil {
+			return nil, apperror.NewBadRequest("unreadable email body")
+		}
+	}
+
+	entity, err := s.entitySvc.Create(ctx, in.CampaignID, userID, entities.CreateEntityInput{
+		Name:         title,
+		EntityTypeID: et.ID,
+		Status:       entities.StatusDraft,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entryJSON != "" {
+		if err := s.entitySvc.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+			return nil, err
+		}
+	}
+	return &InboundEmailResult{
+		Kind: "page",
+		ID:   entity.ID,
+		Name: entity.Name,
+		URL:  fmt.Sprintf("/campaigns/%s/entities/%s", in.CampaignID, entity.ID),
+	}, nil
+}
+
+// appendToSession adds the email, headed by its subject, to the end of
+// the latest session's recap.
+func (s *inboundEmailService) appendToSession(ctx context.Context, campaignID, title, body string) (*InboundEmailResult, error) {
+	_, appendHTML, err := s.markdownConv.ConvertMarkdown("### " + title + "\n\n" + body)
+	if err != nil {
+		return nil, apperror.NewBadRequest("unreadable email body")
+	}
+	id, name, err := s.sessions.AppendToLatestRecap(ctx, campaignID, appendHTML)
+	if err != nil {
+		return nil, err
+	}
+	return &InboundEmailResult{
+		Kind: "session",
+		ID:   id,
+		Name: name,
+		URL:  fmt.Sprintf("/campaigns/%s/sessions/%s", campaignID, id),
+	}, nil
+}
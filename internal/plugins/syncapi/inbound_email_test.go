@@ -0,0 +1,255 @@
+package syncapi
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/plugins/media"
+)
+
+const inboundTestToken = "abcdefghijklmnopqrstuvwxyz"
+
+// stubInboundRepo keeps addresses and received Message-IDs in memory.
+type stubInboundRepo struct {
+	addresses map[string]*InboundEmail
+	seen      map[string]string
+}
+
+func (r *stubInboundRepo) GetByCampaign(_ context.Context, campaignID string) (*InboundEmail, error) {
+	for _, in := range r.addresses {
+		if in.CampaignID == campaignID {
+			return in, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubInboundRepo) GetByToken(_ context.Context, token string) (*InboundEmail, error) {
+	return r.addresses[token], nil
+}
+
+func (r *stubInboundRepo) Upsert(_ context.Context, in *InboundEmail) error {
+	r.addresses[in.Token] = in
+	return nil
+}
+
+func (r *stubInboundRepo) SetEnabled(_ context.Context, campaignID string, enabled bool) error {
+	in, _ := r.GetByCampaign(context.Background(), campaignID)
+	in.Enabled = enabled
+	return nil
+}
+
+func (r *stubInboundRepo) MessageSeen(_ context.Context, _, messageID string) (bool, error) {
+	_, ok := r.seen[messageID]
+	return ok, nil
+}
+
+func (r *stubInboundRepo) RecordMessage(_ context.Context, _, messageID, resultType, _ string) error {
+	r.seen[messageID] = resultType
+	return nil
+}
+
+// stubMemberLister returns a fixed member list.
+type stubMemberLister struct {
+	campaigns.CampaignService
+	members []campaigns.CampaignMember
+}
+
+func (s *stubMemberLister) ListMembers(context.Context, string) ([]campaigns.CampaignMember, error) {
+	return s.members, nil
+}
+
+// stubMediaUploader records uploads.
+type stubMediaUploader struct {
+	media.MediaService
+	uploads []media.UploadInput
+}
+
+func (s *stubMediaUploader) Upload(_ context.Context, in media.UploadInput) (*media.MediaFile, error) {
+	s.uploads = append(s.uploads, in)
+	return &media.MediaFile{ID: "med-1"}, nil
+}
+
+// stubRecapAppender records the HTML appended to a recap.
+type stubRecapAppender struct {
+	html string
+}
+
+func (s *stubRecapAppender) AppendToLatestRecap(_ context.Context, _, html string) (string, string, error) {
+	s.html = html
+	return "sess-1", "Session 12", nil
+}
+
+type inboundFixture struct {
+	svc      InboundEmailService
+	entities *stubEntitySvcForObsidian
+	media    *stubMediaUploader
+	recaps   *stubRecapAppender
+	repo     *stubInboundRepo
+}
+
+func newInboundFixture() *inboundFixture {
+	f := &inboundFixture{
+		entities: newObsidianStub(),
+		media:    &stubMediaUploader{},
+		recaps:   &stubRecapAppender{},
+		repo: &stubInboundRepo{seen: map[string]string{}, addresses: map[string]*InboundEmail{
+			inboundTestToken: {CampaignID: "camp-1", Token: inboundTestToken, EntityTypeID: 7, Enabled: true},
+		}},
+	}
+	members := &stubMemberLister{members: []campaigns.CampaignMember{
+		{UserID: "u-player", Role: campaigns.RolePlayer, DisplayName: "Pip", Email: "Pip@Example.com"},
+		{UserID: "u-scribe", Role: campaigns.RoleScribe, DisplayName: "Sam", Email: "sam@example.com"},
+	}}
+	f.svc = NewInboundEmailService(f.repo, f.entities, members, f.media, stubMarkdownConverter{}, f.recaps)
+	return f
+}
+
+// inboundMessage builds a multipart email with a text body, an HTML
+// alternative and the given attachments (name -> MIME type).
+func inboundMessage(from, to, subject, messageID string, attachments map[string]string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\nTo: " + to + "\r\nSubject: " + subject + "\r\n")
+	if messageID != "" {
+		b.WriteString("Message-ID: <" + messageID + ">\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=outer\r\n\r\n")
+	b.WriteString("--outer\r\nContent-Type: multipart/alternative; boundary=inner\r\n\r\n")
+	b.WriteString("--inner\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n")
+	b.WriteString("The harbor =E2=80=94 quiet tonight.\r\n\r\n-- \r\nSent from my phone\r\n")
+	b.WriteString("--inner\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<p>The harbor, quiet tonight.</p>\r\n--inner--\r\n")
+	for name, mimeType := range attachments {
+		b.WriteString("--outer\r\nContent-Type: " + mimeType + "\r\nContent-Disposition: attachment; filename=\"" + name + "\"\r\nContent-Transfer-Encoding: base64\r\n\r\n")
+		b.WriteString(base64.StdEncoding.EncodeToString([]byte("bytes of "+name)) + "\r\n")
+	}
+	b.WriteString("--outer--\r\n")
+	return []byte(b.String())
+}
+
+func TestParseInboundEmail(t *testing.T) {
+	raw := inboundMessage(`"Pip" <Pip@Example.com>`, "campaign+"+inboundTestToken+"@in.example.com",
+		"=?utf-8?q?Caf=C3=A9_notes?=", "m1@example.com", map[string]string{"map.png": "image/png"})
+	e, err := parseInboundEmail(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if e.From != "pip@example.com" || e.Subject != "Café notes" || e.MessageID != "m1@example.com" {
+		t.Errorf("headers = %q %q %q", e.From, e.Subject, e.MessageID)
+	}
+	if len(e.Recipients) != 1 || e.Recipients[0] != "campaign+"+inboundTestToken+"@in.example.com" {
+		t.Errorf("recipients = %v", e.Recipients)
+	}
+	if got := inboundBodyText(e.Text); got != "The harbor — quiet tonight." {
+		t.Errorf("text = %q", got)
+	}
+	if e.HTML != "<p>The harbor, quiet tonight.</p>" {
+		t.Errorf("html = %q", e.HTML)
+	}
+	if len(e.Attachments) != 1 || e.Attachments[0].Name != "map.png" || string(e.Attachments[0].Data) != "bytes of map.png" {
+		t.Errorf("attachments = %+v", e.Attachments)
+	}
+
+	if _, err := parseInboundEmail([]byte("Subject: no sender\r\n\r\nhi")); err == nil {
+		t.Error("message without From parsed")
+	}
+}
+
+func TestInboundEmailReceive_DraftPage(t *testing.T) {
+	f := newInboundFixture()
+	raw := inboundMessage("pip@example.com", "campaign+"+inboundTestToken+"@in.example.com", "Harbor rumours", "m1@example.com",
+		map[string]string{"map.png": "image/png", "notes.exe": "application/octet-stream"})
+
+	res, err := f.svc.Receive(context.Background(), "", raw)
+	if err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if res.Kind != "page" || res.ID != "ent-new" || res.Attachments != 1 || len(res.Skipped) != 1 || res.Skipped[0] != "notes.exe" {
+		t.Errorf("result = %+v", res)
+	}
+	if in := f.entities.created; in.Name != "Harbor rumours" || in.EntityTypeID != 7 || in.Status != entities.StatusDraft {
+		t.Errorf("create input = %+v", in)
+	}
+	if !strings.Contains(f.entities.entry, "The harbor — quiet tonight.") || !strings.Contains(f.entities.entry, "![map.png](/media/med-1)") {
+		t.Errorf("entry = %q", f.entities.entry)
+	}
+	if strings.Contains(f.entities.entry, "Sent from my phone") {
+		t.Error("signature kept in the entry")
+	}
+	if len(f.media.uploads) != 1 || f.media.uploads[0].UploadedBy != "u-player" || f.media.uploads[0].CampaignID != "camp-1" {
+		t.Errorf("uploads = %+v", f.media.uploads)
+	}
+
+	_, err = f.svc.Receive(context.Background(), "", raw)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusConflict {
+		t.Fatalf("retry err = %v, want 409", err)
+	}
+}
+
+func TestInboundEmailReceive_RejectsUnknownSenderAndAddress(t *testing.T) {
+	f := newInboundFixture()
+	raw := inboundMessage("stranger@example.com", "x@in.example.com", "Hello", "", nil)
+	_, err := f.svc.Receive(context.Background(), inboundTestToken, raw)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusForbidden {
+		t.Fatalf("stranger err = %v, want 403", err)
+	}
+
+	raw = inboundMessage("pip@example.com", "x@in.example.com", "Hello", "", nil)
+	_, err = f.svc.Receive(context.Background(), "", raw)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusNotFound {
+		t.Fatalf("no address err = %v, want 404", err)
+	}
+
+	f.repo.addresses[inboundTestToken].Enabled = false
+	_, err = f.svc.Receive(context.Background(), inboundTestToken, raw)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusNotFound {
+		t.Fatalf("disabled err = %v, want 404", err)
+	}
+	if f.entities.created != nil || len(f.media.uploads) != 0 {
+		t.Error("content created for a rejected email")
+	}
+}
+
+func TestInboundEmailReceive_SessionRecapNeedsScribe(t *testing.T) {
+	f := newInboundFixture()
+	raw := inboundMessage("pip@example.com", "x@in.example.com", "Session: Night at the docks", "", map[string]string{"map.png": "image/png"})
+	_, err := f.svc.Receive(context.Background(), inboundTestToken, raw)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusForbidden {
+		t.Fatalf("player err = %v, want 403", err)
+	}
+	if len(f.media.uploads) != 0 {
+		t.Error("attachments uploaded for a refused email")
+	}
+
+	raw = inboundMessage("sam@example.com", "x@in.example.com", "Session: Night at the docks", "", nil)
+	res, err := f.svc.Receive(context.Background(), inboundTestToken, raw)
+	if err != nil {
+		t.Fatalf("scribe receive: %v", err)
+	}
+	if res.Kind != "session" || res.ID != "sess-1" || res.URL != "/campaigns/camp-1/sessions/sess-1" {
+		t.Errorf("result = %+v", res)
+	}
+	if !strings.HasPrefix(f.recaps.html, "<p>### Night at the docks") || f.entities.created != nil {
+		t.Errorf("recap html = %q, page created = %v", f.recaps.html, f.entities.created != nil)
+	}
+}
+
+func TestInboundEmailEnable_KeepsToken(t *testing.T) {
+	f := newInboundFixture()
+	in, err := f.svc.Enable(context.Background(), "camp-1", "u-owner", 7)
+	if err != nil || in.Token != inboundTestToken {
+		t.Fatalf("enable = %+v, %v", in, err)
+	}
+	if _, err := f.svc.Enable(context.Background(), "camp-1", "u-owner", 99); err == nil {
+		t.Error("enabled with an unknown category")
+	}
+	rotated, err := f.svc.RotateToken(context.Background(), "camp-1")
+	if err != nil || rotated.Token == inboundTestToken || len(rotated.Token) != len(inboundTestToken) {
+		t.Errorf("rotated = %+v, %v", rotated, err)
+	}
+}
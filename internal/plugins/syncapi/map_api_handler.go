@@ -42,6 +42,9 @@ func (h *MapAPIHandler) resolveRole(c echo.Context) int {
 	if key == nil {
 		return 0
 	}
+	if key.IsDemo {
+		return key.EffectiveRole()
+	}
 	member, err := h.campaignSvc.GetMember(c.Request().Context(), key.CampaignID, key.UserID)
 	if err != nil {
 		return 0
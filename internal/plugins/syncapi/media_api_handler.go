@@ -44,6 +44,9 @@ type apiMediaFileResponse struct {
 	CampaignID   *string           `json:"campaign_id,omitempty"`
 	UploadedBy   string            `json:"uploaded_by"`
 	OriginalName string            `json:"original_name"`
+	AltText      string            `json:"alt_text,omitempty"`
+	Caption      string            `json:"caption,omitempty"`
+	Credit       string            `json:"credit,omitempty"`
 	MimeType     string            `json:"mime_type"`
 	FileSize     int64             `json:"file_size"`
 	UsageType    string            `json:"usage_type"`
@@ -60,6 +63,9 @@ func (h *MediaAPIHandler) toAPIResponse(file *media.MediaFile) apiMediaFileRespo
 		CampaignID:   file.CampaignID,
 		UploadedBy:   file.UploadedBy,
 		OriginalName: file.OriginalName,
+		AltText:      file.AltText,
+		Caption:      file.Caption,
+		Credit:       file.Credit,
 		MimeType:     file.MimeType,
 		FileSize:     file.FileSize,
 		UsageType:    file.UsageType,
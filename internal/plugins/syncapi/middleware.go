@@ -2,7 +2,9 @@ package syncapi
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math"
 	"net"
 	"net/http"
 	"strconv"
@@ -13,6 +15,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
 )
@@ -20,6 +23,11 @@ import (
 // apiKeyContextKey is the Echo context key for the authenticated API key.
 const apiKeyContextKey = "api_key"
 
+// deletionCheckedContextKey records the campaign denyPendingDeletion has
+// already cleared for this request, so RequireCampaignMatch doesn't look it
+// up a second time.
+const deletionCheckedContextKey = "api_deletion_checked"
+
 // synthKeySessionID is the sentinel KeyID used for synthetic APIKeys that
 // represent a session-authed caller on /api/v1/*. A real api_keys row has
 // an AUTO_INCREMENT id >= 1, so ID == 0 unambiguously flags a synthetic
@@ -80,22 +88,61 @@ func permissionsForCampaignRole(role campaigns.Role) []APIKeyPermission {
 //   - IP blocklist / IP allowlist / device fingerprint enforcement run only
 //     on the Bearer path. Session auth trusts the upstream auth service's
 //     session validation (cookie rotation, expiry, IP rate limits on login).
+//   - Either way, a campaign in its deletion grace period is hidden from all
+//     but its owner and read-only for them (denyPendingDeletion).
 //
 // Must be wired in place of RequireAPIKey on the /api/v1 group.
 func RequireAuthOrAPIKey(authSvc auth.AuthService, campaignSvc campaigns.CampaignService, syncSvc SyncAPIService) echo.MiddlewareFunc {
 	apiKey := RequireAPIKey(syncSvc)
+	deletion := denyPendingDeletion(campaignSvc)
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		apiKeyChain := apiKey(next)
+		guarded := deletion(next)
+		apiKeyChain := apiKey(guarded)
 		return func(c echo.Context) error {
 			if key, ok := tryAuthFromSession(c, authSvc, campaignSvc); ok {
 				c.Set(apiKeyContextKey, key)
-				return next(c)
+				return guarded(c)
 			}
 			return apiKeyChain(c)
 		}
 	}
 }
 
+// denyPendingDeletion applies campaigns.CheckPendingDeletion to the
+// authenticated key's campaign, as RequireCampaignAccess does for the web
+// UI: members other than the owner get a 404, and the owner may only read.
+// The owner is whoever the key's user is in the campaign now, so a key
+// created by a since-demoted owner loses access too. Keys without a
+// campaign pass through.
+func denyPendingDeletion(campaignSvc campaigns.CampaignService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := GetAPIKey(c)
+			if key == nil || key.CampaignID == "" || c.Get(deletionCheckedContextKey) == key.CampaignID {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+			campaign, err := campaignSvc.GetByID(ctx, key.CampaignID)
+			if err != nil {
+				return err
+			}
+			if campaign.IsPendingDeletion() {
+				role := campaigns.RoleNone
+				if member, err := campaignSvc.GetMember(ctx, key.CampaignID, key.UserID); err == nil && member != nil {
+					role = member.Role
+				}
+				method := c.Request().Method
+				write := method != http.MethodGet && method != http.MethodHead
+				if err := campaigns.CheckPendingDeletion(campaign, role, false, write); err != nil {
+					return err
+				}
+			}
+			c.Set(deletionCheckedContextKey, key.CampaignID)
+			return next(c)
+		}
+	}
+}
+
 // tryAuthFromSession attempts to resolve the caller from a session cookie.
 // Returns (key, true) when a valid session maps to a campaign membership
 // the URL allows, where `key` is a synthetic APIKey with permissions
@@ -211,33 +258,22 @@ func RequireAPIKey(service SyncAPIService) echo.MiddlewareFunc {
 				return apperror.NewForbidden("ip address not allowed for this key")
 			}
 
-			// Device fingerprint enforcement: if the client sends X-Device-Fingerprint,
-			// auto-bind on first use; reject mismatches on subsequent requests.
-			// This ensures a key can only be used by a single registered device.
-			// Binding uses a conditional UPDATE (WHERE fingerprint IS NULL) so
-			// concurrent first requests race safely — only one wins the bind.
-			deviceFP := c.Request().Header.Get("X-Device-Fingerprint")
-			if deviceFP != "" {
-				if key.DeviceFingerprint == nil {
-					// First use — bind the device synchronously.
-					if bindErr := service.BindDevice(ctx, key.ID, deviceFP); bindErr != nil {
-						slog.Warn("device fingerprint binding failed",
-							slog.Int("key_id", key.ID),
-							slog.Any("error", bindErr),
-						)
-					}
-				} else if *key.DeviceFingerprint != deviceFP {
-					// Device mismatch — reject.
+			// Device fingerprint enforcement: the first request that sends
+			// X-Device-Fingerprint binds the key to that device; afterwards
+			// requests from any other device, or without a fingerprint, are
+			// rejected until the owner unbinds the key.
+			if reason, err := enforceDevice(ctx, service, key, c.Request().Header.Get("X-Device-Fingerprint")); err != nil {
+				if reason != "" {
 					_ = service.LogSecurityEvent(ctx, &SecurityEvent{
 						EventType:  EventSuspicious,
 						APIKeyID:   &key.ID,
 						CampaignID: &key.CampaignID,
 						IPAddress:  ip,
 						UserAgent:  strPtr(c.Request().UserAgent()),
-						Details:    map[string]any{"reason": "device fingerprint mismatch"},
+						Details:    map[string]any{"reason": reason},
 					})
-					return apperror.NewForbidden("device not authorized for this key")
 				}
+				return err
 			}
 
 			// Store the key in context for downstream handlers.
@@ -308,11 +344,28 @@ func RequirePermission(perm APIKeyPermission) echo.MiddlewareFunc {
 	}
 }
 
+// DenyDemoKeys returns middleware that refuses demo keys on endpoints that
+// expose more than a player's view: member emails and the key creator's own
+// notes.
+func DenyDemoKeys() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if key := GetAPIKey(c); key != nil && key.IsDemo {
+				return apperror.NewForbidden("not available to demo keys")
+			}
+			return next(c)
+		}
+	}
+}
+
 // RequireCampaignMatch returns middleware that verifies the API key's campaign
 // matches the :id parameter in the URL. Prevents using a key scoped to one
-// campaign to access another.
-func RequireCampaignMatch() echo.MiddlewareFunc {
+// campaign to access another. It also applies the deletion grace period,
+// which RequireAuthOrAPIKey has usually done already for this campaign.
+func RequireCampaignMatch(campaignSvc campaigns.CampaignService) echo.MiddlewareFunc {
+	deletion := denyPendingDeletion(campaignSvc)
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		guarded := deletion(next)
 		return func(c echo.Context) error {
 			key := GetAPIKey(c)
 			if key == nil {
@@ -322,7 +375,7 @@ func RequireCampaignMatch() echo.MiddlewareFunc {
 			if campaignID != key.CampaignID {
 				return apperror.NewForbidden("api key not authorized for this campaign")
 			}
-			return next(c)
+			return guarded(c)
 		}
 	}
 }
@@ -373,25 +426,27 @@ func RequireJSONContentType() echo.MiddlewareFunc {
 
 // --- Rate Limiting ---
 
-// rateLimiter tracks per-key request counts using a sliding window.
-type rateLimiter struct {
-	mu      sync.Mutex
-	windows map[int]*rateLimitWindow // Keyed by API key ID.
-}
-
-// rateLimitWindow tracks requests in the current minute.
-type rateLimitWindow struct {
-	count   int
-	resetAt time.Time
-}
-
-// globalRateLimiter is the singleton rate limiter instance.
-var globalRateLimiter = &rateLimiter{
-	windows: make(map[int]*rateLimitWindow),
+// apiKeyRateWindow is the per-key request counter, one budget across every
+// API route (and every instance, in cluster mode). Created on first use so
+// its cleanup goroutine doesn't start at package init.
+var apiKeyRateWindow = sync.OnceValue(func() *middleware.RateWindow {
+	return middleware.NewRateWindow("syncapi-key", time.Minute)
+})
+
+// rateLimitedResponse is the 429 body. RetryAfter repeats the Retry-After
+// header in seconds for clients that don't read headers.
+type rateLimitedResponse struct {
+	Error      string `json:"error"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after"`
 }
 
 // RateLimit returns middleware that enforces per-key request rate limits.
-// Uses a simple fixed-window counter per minute.
+// Uses a simple fixed-window counter per minute: the sustained rate is the
+// key's RateLimit per minute, and the whole budget may be spent as a burst
+// at any point in the window. Every limited response carries
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (seconds
+// until the window resets).
 //
 // Synthetic session keys (ID == synthKeySessionID) skip this limiter —
 // they represent an authenticated browser user, not an external client,
@@ -409,26 +464,15 @@ func RateLimit(service SyncAPIService) echo.MiddlewareFunc {
 				return next(c)
 			}
 
-			globalRateLimiter.mu.Lock()
-			window, exists := globalRateLimiter.windows[key.ID]
-			now := time.Now()
-
-			if !exists || now.After(window.resetAt) {
-				// New window.
-				window = &rateLimitWindow{
-					count:   0,
-					resetAt: now.Add(time.Minute),
-				}
-				globalRateLimiter.windows[key.ID] = window
-			}
-
-			window.count++
-			remaining := key.RateLimit - window.count
-			globalRateLimiter.mu.Unlock()
+			count, resetIn := apiKeyRateWindow().Hit(c, strconv.Itoa(key.ID))
+			remaining := key.RateLimit - count
+			reset := max(int(math.Ceil(resetIn.Seconds())), 1)
 
 			// Set rate limit headers.
-			c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
-			c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(remaining, 0)))
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(max(remaining, 0)))
+			h.Set("X-RateLimit-Reset", strconv.Itoa(reset))
 
 			if remaining < 0 {
 				_ = service.LogSecurityEvent(c.Request().Context(), &SecurityEvent{
@@ -438,8 +482,12 @@ func RateLimit(service SyncAPIService) echo.MiddlewareFunc {
 					IPAddress:  c.RealIP(),
 					UserAgent:  strPtr(c.Request().UserAgent()),
 				})
-				c.Response().Header().Set("Retry-After", "60")
-				return &apperror.AppError{Code: http.StatusTooManyRequests, Type: "rate_limit_exceeded", Message: "rate limit exceeded"}
+				h.Set("Retry-After", strconv.Itoa(reset))
+				return c.JSON(http.StatusTooManyRequests, rateLimitedResponse{
+					Error:      http.StatusText(http.StatusTooManyRequests),
+					Message:    "rate limit exceeded",
+					RetryAfter: reset,
+				})
 			}
 
 			return next(c)
@@ -512,3 +560,47 @@ type AddonChecker interface {
 	IsEnabledForCampaign(ctx context.Context, campaignID, slug string) (bool, error)
 }
 
+// enforceDevice checks the request's device fingerprint against key's
+// binding, binding an unbound key on first use. Binding uses a conditional
+// UPDATE (WHERE fingerprint IS NULL) so concurrent first requests race
+// safely; the loser is checked against the winner's fingerprint. A non-empty
+// reason means the request came from the wrong device and should be logged
+// as a security event.
+func enforceDevice(ctx context.Context, service SyncAPIService, key *APIKey, fingerprint string) (reason string, err error) {
+	fingerprint = strings.TrimSpace(fingerprint)
+	if key.DeviceFingerprint == nil {
+		if fingerprint == "" {
+			return "", nil
+		}
+		err := service.BindDevice(ctx, key.ID, fingerprint)
+		if err == nil {
+			return "", nil
+		}
+		if !errors.Is(err, ErrDeviceAlreadyBound) {
+			var appErr *apperror.AppError
+			if errors.As(err, &appErr) {
+				return "", err
+			}
+			// Don't lock clients out over a database hiccup; the next
+			// request tries again.
+			slog.Warn("device fingerprint binding failed",
+				slog.Int("key_id", key.ID),
+				slog.Any("error", err),
+			)
+			return "", nil
+		}
+		bound, err := service.GetKey(ctx, key.ID)
+		if err != nil || bound.DeviceFingerprint == nil {
+			return "", apperror.NewForbidden("device not authorized for this key")
+		}
+		key.DeviceFingerprint, key.DeviceBoundAt = bound.DeviceFingerprint, bound.DeviceBoundAt
+	}
+
+	switch {
+	case fingerprint == "":
+		return "device fingerprint missing", apperror.NewForbidden("device fingerprint required for this key")
+	case *key.DeviceFingerprint != fingerprint:
+		return "device fingerprint mismatch", apperror.NewForbidden("device not authorized for this key")
+	}
+	return "", nil
+}
@@ -4,9 +4,12 @@ import (
 	stderrors "errors"
 
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/bcrypt"
@@ -38,6 +41,16 @@ func (f *fakeAuthService) ValidateSession(ctx context.Context, token string) (*a
 type fakeCampaignService struct {
 	campaigns.CampaignService
 	getMemberFn func(ctx context.Context, campaignID, userID string) (*campaigns.CampaignMember, error)
+	getByIDFn   func(ctx context.Context, id string) (*campaigns.Campaign, error)
+}
+
+// GetByID defaults to an active campaign: every keyed request looks its
+// campaign up for the deletion grace period check.
+func (f *fakeCampaignService) GetByID(ctx context.Context, id string) (*campaigns.Campaign, error) {
+	if f.getByIDFn == nil {
+		return &campaigns.Campaign{ID: id}, nil
+	}
+	return f.getByIDFn(ctx, id)
 }
 
 func (f *fakeCampaignService) GetMember(ctx context.Context, campaignID, userID string) (*campaigns.CampaignMember, error) {
@@ -86,7 +99,7 @@ func newMultiAuthFixture(t *testing.T, authSvc auth.AuthService, campaignSvc cam
 	cg := e.Group("/api/v1/campaigns/:id",
 		RequireAuthOrAPIKey(authSvc, campaignSvc, syncSvc),
 	)
-	cg.POST("/entities", func(c echo.Context) error {
+	cg.Match([]string{http.MethodGet, http.MethodPost}, "/entities", func(c echo.Context) error {
 		key := GetAPIKey(c)
 		if key == nil {
 			return c.JSON(http.StatusInternalServerError, map[string]any{"error": "no key in context"})
@@ -262,6 +275,104 @@ func TestRequireAuthOrAPIKey_SessionPrecedence(t *testing.T) {
 	}
 }
 
+// pendingDeletionCampaigns serves camp-1 as marked for deletion, with the
+// given user roles as its members.
+func pendingDeletionCampaigns(roles map[string]campaigns.Role) *fakeCampaignService {
+	marked := time.Now()
+	return &fakeCampaignService{
+		getByIDFn: func(_ context.Context, id string) (*campaigns.Campaign, error) {
+			return &campaigns.Campaign{ID: id, DeletionRequestedAt: &marked}, nil
+		},
+		getMemberFn: func(_ context.Context, campaignID, userID string) (*campaigns.CampaignMember, error) {
+			role, ok := roles[userID]
+			if !ok {
+				return nil, stderrors.New("not a member")
+			}
+			return &campaigns.CampaignMember{CampaignID: campaignID, UserID: userID, Role: role}, nil
+		},
+	}
+}
+
+// TestRequireAuthOrAPIKey_PendingDeletion: a campaign in its deletion grace
+// period is hidden from members other than the owner and read-only for the
+// owner, by session and by Bearer key alike.
+func TestRequireAuthOrAPIKey_PendingDeletion(t *testing.T) {
+	rawKey := "chron_apikey12345678901234567890123456789012345678901234567890ab"
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	cases := []struct {
+		name   string
+		bearer bool
+		role   campaigns.Role
+		method string
+		want   int
+	}{
+		{"session owner reads", false, campaigns.RoleOwner, http.MethodGet, http.StatusOK},
+		{"session owner writes", false, campaigns.RoleOwner, http.MethodPost, http.StatusForbidden},
+		{"session scribe reads", false, campaigns.RoleScribe, http.MethodGet, http.StatusNotFound},
+		{"bearer owner reads", true, campaigns.RoleOwner, http.MethodGet, http.StatusOK},
+		{"bearer owner writes", true, campaigns.RoleOwner, http.MethodPost, http.StatusForbidden},
+		{"bearer scribe reads", true, campaigns.RoleScribe, http.MethodGet, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			authSvc := &fakeAuthService{}
+			var syncSvc SyncAPIService
+			if tc.bearer {
+				syncSvc = NewSyncAPIService(&mockSyncAPIRepo{
+					findKeyByPrefixFn: func(_ context.Context, prefix string) (*APIKey, error) {
+						return &APIKey{ID: 99, KeyHash: string(hash), KeyPrefix: prefix,
+							CampaignID: "camp-1", UserID: "user-1", IsActive: true}, nil
+					},
+					isIPBlockedFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+					logRequestFn:  func(_ context.Context, _ *APIRequestLog) error { return nil },
+				})
+			} else {
+				authSvc.validateSessionFn = func(_ context.Context, _ string) (*auth.Session, error) {
+					return &auth.Session{UserID: "user-1"}, nil
+				}
+			}
+			campSvc := pendingDeletionCampaigns(map[string]campaigns.Role{"user-1": tc.role})
+			e := newMultiAuthFixture(t, authSvc, campSvc, syncSvc)
+
+			req := httptest.NewRequest(tc.method, "/api/v1/campaigns/camp-1/entities", nil)
+			if tc.bearer {
+				req.Header.Set("Authorization", "Bearer "+rawKey)
+			} else {
+				req.AddCookie(&http.Cookie{Name: "chronicle_session", Value: "valid"})
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d; body = %s", rec.Code, tc.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestRequireCampaignMatch_PendingDeletion: RequireCampaignMatch applies the
+// grace period itself for keys RequireAuthOrAPIKey hasn't checked.
+func TestRequireCampaignMatch_PendingDeletion(t *testing.T) {
+	campSvc := pendingDeletionCampaigns(map[string]campaigns.Role{
+		"owner": campaigns.RoleOwner, "player": campaigns.RolePlayer,
+	})
+	run := func(userID, method string) error {
+		e := echo.New()
+		c := e.NewContext(httptest.NewRequest(method, "/", nil), httptest.NewRecorder())
+		c.SetParamNames("id")
+		c.SetParamValues("camp-1")
+		c.Set(apiKeyContextKey, &APIKey{ID: 7, CampaignID: "camp-1", UserID: userID})
+		return RequireCampaignMatch(campSvc)(func(echo.Context) error { return nil })(c)
+	}
+	if err := run("owner", http.MethodGet); err != nil {
+		t.Errorf("owner read: %v", err)
+	}
+	assertAppError(t, run("owner", http.MethodPut), http.StatusForbidden)
+	assertAppError(t, run("player", http.MethodGet), http.StatusNotFound)
+}
+
 // TestPermissionsForCampaignRole locks in the mapping from campaign role
 // to API-key permissions used by RequireAuthOrAPIKey. The default shape
 // has Owner > Scribe > Player with progressively fewer permissions; a
@@ -285,6 +396,83 @@ func TestPermissionsForCampaignRole(t *testing.T) {
 	}
 }
 
+// TestDenyDemoKeys checks demo keys are refused while regular keys pass.
+func TestDenyDemoKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		key  *APIKey
+		want int
+	}{
+		{"regular key", &APIKey{ID: 1}, http.StatusOK},
+		{"demo key", &APIKey{ID: 2, IsDemo: true}, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = func(err error, c echo.Context) {
+				_ = c.NoContent(apperror.SafeCode(err))
+			}
+			e.GET("/notes", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Set(apiKeyContextKey, tc.key)
+					return next(c)
+				}
+			}, DenyDemoKeys())
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notes", nil))
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
+
+// TestRateLimit_HeadersAndRetryAfter checks every limited response reports
+// the key's budget and that the 429 body carries retry_after.
+func TestRateLimit_HeadersAndRetryAfter(t *testing.T) {
+	syncSvc := &stubSyncSvcForRole{}
+	key := &APIKey{ID: 990001, CampaignID: "camp-1", RateLimit: 2}
+	e := echo.New()
+	e.GET("/entities", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(apiKeyContextKey, key)
+			return next(c)
+		}
+	}, RateLimit(syncSvc))
+
+	for i, want := range []struct {
+		code      int
+		remaining string
+	}{{http.StatusOK, "1"}, {http.StatusOK, "0"}, {http.StatusTooManyRequests, "0"}} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/entities", nil))
+		h := rec.Header()
+		if rec.Code != want.code || h.Get("X-RateLimit-Limit") != "2" || h.Get("X-RateLimit-Remaining") != want.remaining {
+			t.Fatalf("request %d: status %d, headers %v", i, rec.Code, h)
+		}
+		if reset, err := strconv.Atoi(h.Get("X-RateLimit-Reset")); err != nil || reset < 1 || reset > 60 {
+			t.Errorf("request %d: X-RateLimit-Reset = %q", i, h.Get("X-RateLimit-Reset"))
+		}
+		if rec.Code != http.StatusTooManyRequests {
+			continue
+		}
+		var body rateLimitedResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode 429 body: %v", err)
+		}
+		if body.RetryAfter < 1 || strconv.Itoa(body.RetryAfter) != h.Get("Retry-After") {
+			t.Errorf("retry_after = %d, Retry-After = %q", body.RetryAfter, h.Get("Retry-After"))
+		}
+	}
+	if len(syncSvc.events) != 1 || syncSvc.events[0].EventType != EventRateLimit {
+		t.Errorf("security events = %+v", syncSvc.events)
+	}
+}
+
 // --- Helpers ---
 
 func containsIgnoreCase(s, sub string) bool {
@@ -0,0 +1,5 @@
+-- Drops the email-in tables. Pages and recaps created from emails stay;
+-- only the campaign addresses and the retry log are lost.
+
+DROP TABLE IF EXISTS sync_inbound_email_messages;
+DROP TABLE IF EXISTS sync_inbound_email;
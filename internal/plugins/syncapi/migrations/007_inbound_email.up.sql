@@ -0,0 +1,37 @@
+-- Email-in: a per-campaign inbound address that turns emails from campaign
+-- members into draft pages or session recap appendices.
+--
+-- sync_inbound_email holds one row per campaign. The token is the secret
+-- local part of the address (<token>@INBOUND_EMAIL_DOMAIN) and the path of
+-- the provider webhook, so it is stored in plain text for the owner to copy;
+-- rotating it retires the old address. entity_type_id is the category new
+-- pages land in.
+--
+-- sync_inbound_email_messages remembers the Message-IDs already turned into
+-- content, so a provider retrying a webhook doesn't create the page twice.
+
+CREATE TABLE IF NOT EXISTS sync_inbound_email (
+    campaign_id     CHAR(36)     NOT NULL PRIMARY KEY,
+    token           VARCHAR(64)  NOT NULL,
+    entity_type_id  INT          NOT NULL,
+    enabled         BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_by      CHAR(36)     NOT NULL,
+    created_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_sync_inbound_email_token (token),
+    CONSTRAINT fk_sync_inbound_email_campaign
+        FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS sync_inbound_email_messages (
+    campaign_id  CHAR(36)      NOT NULL,
+    message_id   VARCHAR(255)  NOT NULL,
+    result_type  VARCHAR(20)   NOT NULL,
+    result_id    VARCHAR(36)   NOT NULL,
+    received_at  DATETIME      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (campaign_id, message_id),
+    CONSTRAINT fk_sync_inbound_email_messages_campaign
+        FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
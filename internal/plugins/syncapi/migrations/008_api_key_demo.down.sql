@@ -0,0 +1,3 @@
+-- Reverse the demo key flag. Existing demo keys become ordinary read keys
+-- until they expire, so revoke them first if that matters.
+ALTER TABLE api_keys DROP COLUMN IF EXISTS is_demo;
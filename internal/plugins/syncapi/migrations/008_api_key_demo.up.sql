@@ -0,0 +1,6 @@
+-- Demo keys: short-lived, read-only API keys an owner mints for integration
+-- developers to try the API against real campaign data. A demo key sees
+-- what a player sees, is rate limited hard and always carries an
+-- expires_at (see CreateDemoKey in service.go).
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS is_demo BOOLEAN NOT NULL DEFAULT FALSE AFTER vtt_tag;
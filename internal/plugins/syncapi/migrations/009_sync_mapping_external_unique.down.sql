@@ -0,0 +1,3 @@
+ALTER TABLE sync_mappings
+  ADD INDEX IF NOT EXISTS idx_sync_external (campaign_id, external_system, external_id),
+  DROP INDEX IF EXISTS uq_sync_external;
@@ -0,0 +1,9 @@
+-- One Chronicle object per external record. sync_mappings is the table that
+-- maps (external_system, external_id) to a page or event; until now the
+-- external side was only indexed, so a re-import could map the same record
+-- twice and later syncs updated an arbitrary copy. Duplicates are merged
+-- before this runs by MergeDuplicateSyncMappings (premigration.go).
+
+ALTER TABLE sync_mappings
+  ADD UNIQUE KEY IF NOT EXISTS uq_sync_external (campaign_id, external_system, external_id),
+  DROP INDEX IF EXISTS idx_sync_external;
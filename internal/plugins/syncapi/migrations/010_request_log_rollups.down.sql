@@ -0,0 +1,4 @@
+-- Drop the hourly rollups and the sample weight. Rolled-up history is lost,
+-- and sampled rows then count as one request each.
+DROP TABLE IF EXISTS api_request_rollups;
+ALTER TABLE api_request_log DROP COLUMN IF EXISTS sample_weight;
@@ -0,0 +1,22 @@
+-- Request log sampling and hourly rollups. Successful requests may be
+-- sampled (API_LOG_SAMPLE_RATE); each stored row carries the number of
+-- requests it stands for so stats still count them all. Rows older than
+-- API_LOG_ROLLUP_AFTER are folded into per-hour totals per key and IP, which
+-- the stats queries read alongside the recent rows.
+ALTER TABLE api_request_log
+    ADD COLUMN IF NOT EXISTS sample_weight INT NOT NULL DEFAULT 1 AFTER error_message;
+
+CREATE TABLE IF NOT EXISTS api_request_rollups (
+    id                BIGINT      AUTO_INCREMENT PRIMARY KEY,
+    hour_start        DATETIME    NOT NULL,
+    api_key_id        INT         NOT NULL,
+    campaign_id       VARCHAR(36) NULL,
+    ip_address        VARCHAR(45) NOT NULL,
+    requests          BIGINT      NOT NULL DEFAULT 0,
+    errors            BIGINT      NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT      NOT NULL DEFAULT 0,
+
+    UNIQUE KEY uq_api_rollup (hour_start, api_key_id, ip_address),
+    KEY idx_api_rollup_campaign (campaign_id, hour_start),
+    CONSTRAINT fk_api_request_rollups_campaign FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
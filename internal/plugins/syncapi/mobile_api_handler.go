@@ -0,0 +1,231 @@
+package syncapi
+
+// mobile_api_handler.go — compact read endpoints for a companion app under
+// /api/v1/mobile/. Same auth chain as the rest of /api/v1 (session cookie or
+// Bearer key), same egress rules (sanitize, secret strip, restricted-field
+// strip); only the response shape differs: minimal fields, a pre-rendered
+// plain-text excerpt, and thumbnail URLs, so a phone list view is one small
+// request instead of full entity documents.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+	"github.com/keyxmakerx/chronicle/internal/templates/layouts"
+)
+
+const (
+	// mobileListExcerptLen / mobileDetailExcerptLen are plain-text excerpt
+	// lengths: a list row shows two lines, a detail header a short paragraph.
+	mobileListExcerptLen   = 140
+	mobileDetailExcerptLen = 400
+
+	// mobileThumbSize is one of the media plugin's allowed thumbnail sizes.
+	mobileThumbSize = "300"
+)
+
+// mobileCampaign is the compact campaign header with its navigable types.
+type mobileCampaign struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Slug  string       `json:"slug"`
+	Types []mobileType `json:"types"`
+}
+
+// mobileType is a category as the app's navigation shows it.
+type mobileType struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	NamePlural string `json:"name_plural"`
+	Slug       string `json:"slug"`
+	Icon       string `json:"icon,omitempty"`
+	Color      string `json:"color,omitempty"`
+}
+
+// mobileEntitySummary is one list row.
+type mobileEntitySummary struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	TypeID       int       `json:"type_id"`
+	TypeName     string    `json:"type_name,omitempty"`
+	TypeIcon     string    `json:"type_icon,omitempty"`
+	TypeColor    string    `json:"type_color,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	Excerpt      string    `json:"excerpt,omitempty"`
+	IsPrivate    bool      `json:"is_private,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// mobileEntityDetail is the detail view: the summary plus the rendered entry
+// and the (already filtered) custom fields.
+type mobileEntityDetail struct {
+	mobileEntitySummary
+	ImageURL     string         `json:"image_url,omitempty"`
+	ImageAlt     string         `json:"image_alt,omitempty"`
+	ImageCaption string         `json:"image_caption,omitempty"`
+	EntryHTML    string         `json:"entry_html,omitempty"`
+	ParentID     *string        `json:"parent_id,omitempty"`
+	Fields       map[string]any `json:"fields,omitempty"`
+}
+
+// mobileSummary builds a list row. Call after the egress strips so the
+// excerpt can't carry sanitized-away markup or GM secrets.
+func mobileSummary(e *entities.Entity, excerptLen int) mobileEntitySummary {
+	s := mobileEntitySummary{
+		ID:        e.ID,
+		Name:      e.Name,
+		TypeID:    e.EntityTypeID,
+		TypeName:  e.TypeName,
+		TypeIcon:  e.TypeIcon,
+		TypeColor: e.TypeColor,
+		IsPrivate: e.IsPrivate,
+		UpdatedAt: e.UpdatedAt,
+	}
+	if e.ImagePath != nil && *e.ImagePath != "" {
+		s.ThumbnailURL = layouts.MediaThumbURL(context.Background(), *e.ImagePath, mobileThumbSize)
+	}
+	if e.EntryHTML != nil && *e.EntryHTML != "" {
+		s.Excerpt = entities.EntryExcerpt(*e.EntryHTML, excerptLen)
+	}
+	return s
+}
+
+// MobileGetCampaign returns the campaign header and its enabled categories.
+// GET /api/v1/mobile/campaigns/:id
+func (h *APIHandler) MobileGetCampaign(c echo.Context) error {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+
+	campaign, err := h.campaignSvc.GetByID(ctx, campaignID)
+	if err != nil {
+		return apperror.NewNotFound("campaign not found")
+	}
+	types, err := h.entitySvc.GetEntityTypes(ctx, campaignID)
+	if err != nil {
+		slog.Error("api: mobile failed to list entity types", slog.Any("error", err))
+		return apperror.NewInternal(fmt.Errorf("failed to load campaign"))
+	}
+
+	out := mobileCampaign{ID: campaign.ID, Name: campaign.Name, Slug: campaign.Slug, Types: []mobileType{}}
+	for _, et := range types {
+		if !et.Enabled {
+			continue
+		}
+		out.Types = append(out.Types, mobileType{
+			ID: et.ID, Name: et.Name, NamePlural: et.NamePlural, Slug: et.Slug, Icon: et.Icon, Color: et.Color,
+		})
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// MobileListEntities returns compact entity rows, filtered by type or a
+// search query like ListEntities.
+// GET /api/v1/mobile/campaigns/:id/entities?type_id=&q=&page=&per_page=
+func (h *APIHandler) MobileListEntities(c echo.Context) error {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+	role := h.resolveRole(c)
+	userID := h.resolveUserID(c)
+
+	typeID, _ := strconv.Atoi(c.QueryParam("type_id"))
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
+	query := c.QueryParam("q")
+
+	opts := entities.ListOptions{Page: page, PerPage: perPage}
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	// Rows are small, so a phone can take a bigger page than ListEntities.
+	if opts.PerPage < 1 || opts.PerPage > 200 {
+		opts.PerPage = 50
+	}
+
+	var (
+		items []entities.Entity
+		total int
+		err   error
+	)
+	if query != "" {
+		items, total, err = h.entitySvc.Search(ctx, campaignID, query, typeID, role, userID, opts)
+	} else {
+		items, total, err = h.entitySvc.List(ctx, campaignID, typeID, role, userID, opts)
+	}
+	if err != nil {
+		slog.Error("api: mobile failed to list entities", slog.Any("error", err))
+		return apperror.NewInternal(fmt.Errorf("failed to list entities"))
+	}
+
+	// Same egress rules as ListEntities — the excerpt is built from the
+	// stripped HTML. Fields aren't in list rows, so no field strip.
+	sanitizeEntitiesHTMLForEgress(items)
+	stripEntitiesSecretsForEgress(items, role)
+
+	rows := make([]mobileEntitySummary, 0, len(items))
+	for i := range items {
+		if items[i].IsTemplate {
+			continue
+		}
+		rows = append(rows, mobileSummary(&items[i], mobileListExcerptLen))
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"data":     rows,
+		"total":    total,
+		"page":     opts.Page,
+		"per_page": opts.PerPage,
+	})
+}
+
+// MobileGetEntity returns one entity's compact detail view.
+// GET /api/v1/mobile/campaigns/:id/entities/:entityID
+func (h *APIHandler) MobileGetEntity(c echo.Context) error {
+	ctx := c.Request().Context()
+	role := h.resolveRole(c)
+	userID := h.resolveUserID(c)
+
+	entity, err := h.entitySvc.GetByID(ctx, c.Param("entityID"))
+	if err != nil || entity.CampaignID != c.Param("id") {
+		return apperror.NewNotFound("entity not found")
+	}
+	access, accessErr := h.entitySvc.CheckEntityAccess(ctx, entity.ID, role, userID)
+	if accessErr != nil || !access.CanView {
+		return apperror.NewNotFound("entity not found")
+	}
+
+	sanitizeEntityHTMLForEgress(entity)
+	stripEntitySecretsForEgress(entity, role)
+	// Fail closed on a type load error, as GetEntity does.
+	if role < int(campaigns.RoleScribe) {
+		et, terr := h.entitySvc.GetEntityTypeByID(ctx, entity.EntityTypeID)
+		if terr != nil || et == nil {
+			slog.Error("api: mobile field strip could not load entity type",
+				slog.Int("entity_type_id", entity.EntityTypeID), slog.Any("error", terr))
+			return apperror.NewInternal(fmt.Errorf("failed to load entity"))
+		}
+		stripEntityFieldsForEgress(entity, role, userID, func(int) []entities.FieldDefinition { return et.Fields })
+	}
+
+	detail := mobileEntityDetail{
+		mobileEntitySummary: mobileSummary(entity, mobileDetailExcerptLen),
+		ParentID:            entity.ParentID,
+		Fields:              entity.FieldsData,
+	}
+	if entity.ImagePath != nil && *entity.ImagePath != "" {
+		detail.ImageURL = layouts.MediaURL(context.Background(), *entity.ImagePath)
+		detail.ImageAlt = entity.HeaderImageAlt()
+		detail.ImageCaption = entity.HeaderImageCaption()
+	}
+	if entity.EntryHTML != nil {
+		detail.EntryHTML = *entity.EntryHTML
+	}
+	return c.JSON(http.StatusOK, detail)
+}
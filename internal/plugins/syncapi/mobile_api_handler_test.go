@@ -0,0 +1,122 @@
+package syncapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+// stubEntitySvcForMobile serves one visible page with a GM secret in its
+// entry and a header image, plus a template that list rows must skip.
+type stubEntitySvcForMobile struct {
+	entities.EntityService
+}
+
+func mobileTestEntity() entities.Entity {
+	entry := `<p>The vault code is <span data-secret="true">42-42-42</span>. Ask &amp; ye shall receive.</p>`
+	img := "2026/03/b7c17bb1-6563-462c-8b49-5b2e8bd57108.jpg"
+	return entities.Entity{
+		ID: "ent-1", CampaignID: "camp-1", EntityTypeID: 7, Name: "Vault",
+		TypeName: "Location", EntryHTML: &entry, ImagePath: &img,
+		FieldsData: map[string]any{"region": "North", "gm_notes": "trap"},
+	}
+}
+
+func (s *stubEntitySvcForMobile) List(context.Context, string, int, int, string, entities.ListOptions) ([]entities.Entity, int, error) {
+	tmpl := entities.Entity{ID: "tmpl-1", CampaignID: "camp-1", Name: "Location template", IsTemplate: true}
+	return []entities.Entity{mobileTestEntity(), tmpl}, 2, nil
+}
+
+func (s *stubEntitySvcForMobile) GetByID(context.Context, string) (*entities.Entity, error) {
+	e := mobileTestEntity()
+	return &e, nil
+}
+
+func (s *stubEntitySvcForMobile) CheckEntityAccess(context.Context, string, int, string) (*entities.EffectivePermission, error) {
+	return &entities.EffectivePermission{CanView: true}, nil
+}
+
+func (s *stubEntitySvcForMobile) GetEntityTypeByID(context.Context, int) (*entities.EntityType, error) {
+	return &entities.EntityType{ID: 7, Fields: []entities.FieldDefinition{
+		{Key: "region"},
+		{Key: "gm_notes", GMOnly: true},
+	}}, nil
+}
+
+// newMobileContext builds a session-authed (Player) request context.
+func newMobileContext(path string, params ...string) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, path, nil), rec)
+	c.SetParamNames("id", "entityID")
+	c.SetParamValues(append([]string{"camp-1"}, params...)...)
+	c.Set(apiKeyContextKey, &APIKey{ID: synthKeySessionID, CampaignID: "camp-1", UserID: "user-1"})
+	return c, rec
+}
+
+func newMobileHandler() *APIHandler {
+	return &APIHandler{
+		entitySvc:   &stubEntitySvcForMobile{},
+		campaignSvc: &stubCampaignSvcForRole{getMemberFn: memberWithRole(campaigns.RolePlayer)},
+	}
+}
+
+func TestMobileListEntities_CompactRows(t *testing.T) {
+	c, rec := newMobileContext("/api/v1/mobile/campaigns/camp-1/entities", "")
+	if err := newMobileHandler().MobileListEntities(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Data) != 1 {
+		t.Fatalf("got %d rows, want 1 (template skipped): %s", len(body.Data), rec.Body.String())
+	}
+	row := body.Data[0]
+	if got := row["excerpt"]; got != "The vault code is . Ask & ye shall receive." {
+		t.Errorf("excerpt = %q, want plain text with the secret stripped", got)
+	}
+	if got := row["thumbnail_url"]; got != "/media/b7c17bb1-6563-462c-8b49-5b2e8bd57108/thumb/300" {
+		t.Errorf("thumbnail_url = %v", got)
+	}
+	for _, heavy := range []string{"entry", "entry_html", "fields_data", "fields"} {
+		if _, ok := row[heavy]; ok {
+			t.Errorf("list row carries %q; rows must stay compact", heavy)
+		}
+	}
+}
+
+func TestMobileGetEntity_AppliesEgressRules(t *testing.T) {
+	c, rec := newMobileContext("/api/v1/mobile/campaigns/camp-1/entities/ent-1", "ent-1")
+	if err := newMobileHandler().MobileGetEntity(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body mobileEntityDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Contains(body.EntryHTML, "42-42-42") || strings.Contains(body.Excerpt, "42-42-42") {
+		t.Errorf("GM secret reached a player: entry %q excerpt %q", body.EntryHTML, body.Excerpt)
+	}
+	if _, ok := body.Fields["gm_notes"]; ok {
+		t.Error("gm_only field value reached a player")
+	}
+	if body.Fields["region"] != "North" {
+		t.Errorf("fields = %v, want region kept", body.Fields)
+	}
+	if body.ImageURL != "/media/b7c17bb1-6563-462c-8b49-5b2e8bd57108" {
+		t.Errorf("image_url = %q", body.ImageURL)
+	}
+}
@@ -12,6 +12,10 @@ const (
 	PermRead  APIKeyPermission = "read"
 	PermWrite APIKeyPermission = "write"
 	PermSync  APIKeyPermission = "sync"
+
+	// PermAutomation allows only the inbound actions under /actions (see
+	// automation_api_handler.go), for keys handed to n8n or Zapier.
+	PermAutomation APIKeyPermission = "automation"
 )
 
 // APIKey represents a registered API key for external client access.
@@ -21,6 +25,7 @@ type APIKey struct {
 	KeyPrefix   string             `json:"key_prefix"`              // First 8 chars for display.
 	Name        string             `json:"name"`
 	VTTTag      *string            `json:"vtt_tag,omitempty"`       // Cosmetic label: "foundry", "custom".
+	IsDemo      bool               `json:"is_demo"`                 // Short-lived read-only demo key.
 	UserID      string             `json:"user_id"`
 	CampaignID  string             `json:"campaign_id"`
 	Permissions []APIKeyPermission `json:"permissions"`
@@ -44,6 +49,15 @@ func (k *APIKey) IsExpired() bool {
 	return time.Now().After(*k.ExpiresAt)
 }
 
+// EffectiveRole returns the campaign role a stored key reads with: demo keys
+// see what a player sees, every other key is Owner-level.
+func (k *APIKey) EffectiveRole() int {
+	if k.IsDemo {
+		return 1 // RolePlayer
+	}
+	return 3 // RoleOwner
+}
+
 // HasPermission checks if the key has a specific permission.
 func (k *APIKey) HasPermission(perm APIKeyPermission) bool {
 	for _, p := range k.Permissions {
@@ -54,6 +68,17 @@ func (k *APIKey) HasPermission(perm APIKeyPermission) bool {
 	return false
 }
 
+// Demo key limits. Demo keys let integration developers test against real
+// data without a long-lived credential: read-only, player visibility, a hard
+// rate limit and a forced expiry.
+const (
+	DemoKeyRateLimit       = 10 // Requests per minute.
+	MaxDemoKeysPerCampaign = 5  // Unexpired demo keys at once.
+)
+
+// DemoKeyLifetimes are the lifetimes an owner can pick for a demo key.
+var DemoKeyLifetimes = []time.Duration{time.Hour, 4 * time.Hour, 24 * time.Hour}
+
 // CreateAPIKeyInput is the validated input for creating a new API key.
 type CreateAPIKeyInput struct {
 	Name        string
@@ -63,6 +88,7 @@ type CreateAPIKeyInput struct {
 	IPAllowlist []string
 	RateLimit   int
 	ExpiresAt   *time.Time
+	IsDemo      bool
 }
 
 // CreateAPIKeyResult is returned after key creation, containing the
@@ -87,6 +113,7 @@ type APIRequestLog struct {
 	ResponseSize int       `json:"response_size"`
 	DurationMs   int       `json:"duration_ms"`
 	ErrorMessage *string   `json:"error_message,omitempty"`
+	SampleWeight int       `json:"sample_weight"` // Requests this row stands for; >1 when sampled.
 	CreatedAt    time.Time `json:"created_at"`
 }
 
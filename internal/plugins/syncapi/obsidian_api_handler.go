@@ -0,0 +1,566 @@
+package syncapi
+
+// obsidian_api_handler.go — two-way Markdown sync for an Obsidian vault
+// plugin. Pages travel as Markdown notes whose YAML front matter carries the
+// page's identity (chronicle_id, chronicle_type, chronicle_updated) plus one
+// property per custom field. The plugin pulls notes changed since its cursor
+// and pushes edited notes back; a pushed note's chronicle_updated is the
+// version it was based on, so a page edited in Chronicle since then is
+// reported as a conflict instead of overwritten.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+const (
+	// obsidianDefaultLimit / obsidianMaxLimit bound one page of changes.
+	obsidianDefaultLimit = 100
+	obsidianMaxLimit     = 200
+
+	// obsidianMaxPush caps the notes accepted by one push.
+	obsidianMaxPush = 200
+
+	// obsidianMaxNoteSize caps one pushed note's Markdown, front matter
+	// included.
+	obsidianMaxNoteSize = 1 << 20
+)
+
+// MarkdownEntryConverter turns a pushed note's Markdown body into entry
+// ProseMirror JSON and sanitized HTML. Wired in app/routes.go from the AI
+// workspace importer so syncapi doesn't import that plugin.
+type MarkdownEntryConverter interface {
+	ConvertMarkdown(markdown string) (entryJSON, entryHTML string, err error)
+}
+
+// SetMarkdownConverter enables note bodies on Obsidian push. Without it a
+// pushed note with a body is rejected.
+func (h *APIHandler) SetMarkdownConverter(mc MarkdownEntryConverter) {
+	h.markdownConv = mc
+}
+
+// Reserved front-matter keys. Every other key maps to the custom field of
+// the same key; a field whose key is reserved isn't synced.
+const (
+	fmID      = "chronicle_id"
+	fmType    = "chronicle_type"
+	fmUpdated = "chronicle_updated"
+	fmName    = "name"
+	fmPrivate = "private"
+)
+
+var obsidianReserved = map[string]bool{
+	fmID: true, fmType: true, fmUpdated: true, fmName: true, fmPrivate: true,
+}
+
+// obsidianNote is one page as a vault note. Path is a suggested vault path
+// (type slug folder + page slug); the plugin may file notes elsewhere.
+type obsidianNote struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"`
+	Markdown  string    `json:"markdown"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// obsidianChangesResponse is one page of changed notes, oldest first.
+type obsidianChangesResponse struct {
+	Notes      []obsidianNote `json:"notes"`
+	NextCursor string         `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+	ServerTime time.Time      `json:"server_time"`
+}
+
+// obsidianPushRequest carries edited notes. Path is echoed back so the
+// plugin can match results to files.
+type obsidianPushRequest struct {
+	Notes []struct {
+		Path     string `json:"path"`
+		Markdown string `json:"markdown"`
+	} `json:"notes"`
+}
+
+// obsidianPushResult is the outcome for one pushed note. On success the
+// plugin should rewrite the note with Markdown, which carries the new
+// chronicle_id and chronicle_updated.
+type obsidianPushResult struct {
+	Path     string   `json:"path"`
+	Status   string   `json:"status"` // "created", "updated", "conflict" or "error".
+	EntityID string   `json:"entity_id,omitempty"`
+	Markdown string   `json:"markdown,omitempty"`
+	Ignored  []string `json:"ignored,omitempty"` // front-matter keys that matched no field
+	Error    string   `json:"error,omitempty"`
+}
+
+// obsidianCursor is a position in the (updated_at, id) order. The zero
+// cursor precedes every page.
+type obsidianCursor struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// encode renders the cursor as an opaque token.
+func (cur obsidianCursor) encode() string {
+	raw := cur.UpdatedAt.UTC().Format(time.RFC3339Nano) + "|" + cur.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// before reports whether cur sorts before the page's position.
+func (cur obsidianCursor) before(e *entities.Entity) bool {
+	if !e.UpdatedAt.Equal(cur.UpdatedAt) {
+		return e.UpdatedAt.After(cur.UpdatedAt)
+	}
+	return e.ID > cur.ID
+}
+
+// parseObsidianCursor decodes a token from encode. An empty token is the
+// zero cursor.
+func parseObsidianCursor(token string) (obsidianCursor, error) {
+	if token == "" {
+		return obsidianCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return obsidianCursor{}, apperror.NewBadRequest("invalid cursor")
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if !ok || err != nil {
+		return obsidianCursor{}, apperror.NewBadRequest("invalid cursor")
+	}
+	return obsidianCursor{UpdatedAt: t, ID: id}, nil
+}
+
+var (
+	obsidianConverter     *md.Converter
+	obsidianConverterOnce sync.Once
+)
+
+// entryMarkdown converts a page's (already egress-sanitized) entry HTML to
+// Markdown.
+func entryMarkdown(entryHTML *string) (string, error) {
+	if entryHTML == nil || *entryHTML == "" {
+		return "", nil
+	}
+	obsidianConverterOnce.Do(func() {
+		obsidianConverter = md.NewConverter("", true, nil)
+	})
+	out, err := obsidianConverter.ConvertString(*entryHTML)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// renderObsidianNote writes a page as a note: the reserved keys, then its
+// non-empty fields in key order, then the entry as the body.
+func renderObsidianNote(e *entities.Entity, typeSlug string) (string, error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value any) error {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return fmt.Errorf("encoding %s: %w", key, err)
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &v)
+		return nil
+	}
+	head := []struct {
+		key   string
+		value any
+	}{
+		{fmID, e.ID},
+		{fmType, typeSlug},
+		{fmUpdated, e.UpdatedAt.UTC().Format(time.RFC3339Nano)},
+		{fmName, e.Name},
+		{fmPrivate, e.IsPrivate},
+	}
+	for _, kv := range head {
+		if err := add(kv.key, kv.value); err != nil {
+			return "", err
+		}
+	}
+	keys := make([]string, 0, len(e.FieldsData))
+	for k, v := range e.FieldsData {
+		if !obsidianReserved[k] && v != nil && v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := add(k, e.FieldsData[k]); err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return "", fmt.Errorf("encoding front matter: %w", err)
+	}
+	_ = enc.Close()
+	buf.WriteString("---\n")
+
+	body, err := entryMarkdown(e.EntryHTML)
+	if err != nil {
+		return "", fmt.Errorf("converting entry: %w", err)
+	}
+	if body != "" {
+		buf.WriteString("\n" + body + "\n")
+	}
+	return buf.String(), nil
+}
+
+// splitFrontMatter separates a note's YAML front matter from its body. A
+// note without front matter is all body.
+func splitFrontMatter(note string) (map[string]any, string, error) {
+	note = strings.TrimPrefix(strings.ReplaceAll(note, "\r\n", "\n"), "\ufeff")
+	if !strings.HasPrefix(note, "---\n") {
+		return map[string]any{}, strings.TrimSpace(note), nil
+	}
+	rest := note[len("---\n"):]
+	var head, body string
+	if strings.HasPrefix(rest, "---\n") || rest == "---" {
+		body = strings.TrimPrefix(rest, "---")
+	} else {
+		end := strings.Index(rest, "\n---\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n---") {
+				return nil, "", apperror.NewBadRequest("front matter is not closed with ---")
+			}
+			end = len(rest) - len("\n---")
+		}
+		head = rest[:end]
+		body = strings.TrimPrefix(rest[end:], "\n---")
+	}
+	fm := map[string]any{}
+	if err := yaml.Unmarshal([]byte(head), &fm); err != nil {
+		return nil, "", apperror.NewBadRequest("front matter is not valid YAML")
+	}
+	if fm == nil {
+		fm = map[string]any{}
+	}
+	return fm, strings.TrimSpace(body), nil
+}
+
+// frontMatterString reads a reserved string key; YAML may have decoded a
+// timestamp or number where a string was written.
+func frontMatterString(fm map[string]any, key string) string {
+	switch v := fm[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// mapFlatFields copies the flat values (front-matter properties, automation
+// payload keys) that name one of the type's fields into data, skipping the
+// reserved keys. Values that match no field, or hold a nested object, are
+// returned as ignored.
+func mapFlatFields(values map[string]any, reserved map[string]bool, defs []entities.FieldDefinition, data map[string]any) []string {
+	known := make(map[string]bool, len(defs))
+	for _, d := range defs {
+		known[d.Key] = true
+	}
+	var ignored []string
+	for k, v := range values {
+		if reserved[k] {
+			continue
+		}
+		if _, nested := v.(map[string]any); nested || !known[k] {
+			ignored = append(ignored, k)
+			continue
+		}
+		if t, ok := v.(time.Time); ok {
+			v = t.Format("2006-01-02")
+		}
+		data[k] = v
+	}
+	sort.Strings(ignored)
+	return ignored
+}
+
+// obsidianPath suggests a vault path for a page.
+func obsidianPath(typeSlug string, e *entities.Entity) string {
+	name := e.Slug
+	if name == "" {
+		name = e.ID
+	}
+	if typeSlug == "" {
+		return name + ".md"
+	}
+	return path.Join(typeSlug, name+".md")
+}
+
+// ObsidianChanges returns the pages changed after the cursor as Markdown
+// notes, oldest first, with the cursor for the next call. Templates are
+// skipped. Notes get the same egress rules as GetEntity.
+// GET /api/v1/campaigns/:id/obsidian/changes?cursor=&limit=
+func (h *APIHandler) ObsidianChanges(c echo.Context) error {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+	role := h.resolveRole(c)
+	userID := h.resolveUserID(c)
+
+	cursor, err := parseObsidianCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return err
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > obsidianMaxLimit {
+		limit = obsidianDefaultLimit
+	}
+	serverTime := time.Now().UTC()
+
+	types, err := h.entitySvc.GetEntityTypes(ctx, campaignID)
+	if err != nil {
+		slog.Error("api: obsidian changes failed to load entity types", slog.Any("error", err))
+		return apperror.NewInternal(fmt.Errorf("failed to list changes"))
+	}
+	typeByID := make(map[int]*entities.EntityType, len(types))
+	for i := range types {
+		typeByID[types[i].ID] = &types[i]
+	}
+
+	// Walk newest first until the pages fall behind the cursor.
+	var changed []entities.Entity
+	for page := 1; ; page++ {
+		items, total, err := h.entitySvc.List(ctx, campaignID, 0, role, userID, entities.ListOptions{
+			Page: page, PerPage: syncPageSize, Sort: "updated",
+		})
+		if err != nil {
+			slog.Error("api: obsidian changes failed to list entities", slog.Any("error", err))
+			return apperror.NewInternal(fmt.Errorf("failed to list changes"))
+		}
+		passed := false
+		for _, e := range items {
+			if e.UpdatedAt.Before(cursor.UpdatedAt) {
+				passed = true
+				break
+			}
+			if !e.IsTemplate && cursor.before(&e) {
+				changed = append(changed, e)
+			}
+		}
+		if passed || len(items) == 0 || page*syncPageSize >= total {
+			break
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if !changed[i].UpdatedAt.Equal(changed[j].UpdatedAt) {
+			return changed[i].UpdatedAt.Before(changed[j].UpdatedAt)
+		}
+		return changed[i].ID < changed[j].ID
+	})
+	hasMore := len(changed) > limit
+	if hasMore {
+		changed = changed[:limit]
+	}
+
+	sanitizeEntitiesHTMLForEgress(changed)
+	stripEntitiesSecretsForEgress(changed, role)
+	if role < int(campaigns.RoleScribe) {
+		stripEntitiesFieldsForEgress(changed, role, userID, func(typeID int) []entities.FieldDefinition {
+			if et := typeByID[typeID]; et != nil {
+				return et.Fields
+			}
+			return nil
+		})
+	}
+
+	resp := obsidianChangesResponse{Notes: []obsidianNote{}, NextCursor: cursor.encode(), HasMore: hasMore, ServerTime: serverTime}
+	for i := range changed {
+		e := &changed[i]
+		typeSlug := ""
+		if et := typeByID[e.EntityTypeID]; et != nil {
+			typeSlug = et.Slug
+		}
+		text, err := renderObsidianNote(e, typeSlug)
+		if err != nil {
+			slog.Error("api: obsidian note render failed", slog.String("entity_id", e.ID), slog.Any("error", err))
+			return apperror.NewInternal(fmt.Errorf("failed to list changes"))
+		}
+		resp.Notes = append(resp.Notes, obsidianNote{
+			ID: e.ID, Path: obsidianPath(typeSlug, e), Markdown: text, UpdatedAt: e.UpdatedAt,
+		})
+		resp.NextCursor = obsidianCursor{UpdatedAt: e.UpdatedAt, ID: e.ID}.encode()
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ObsidianPush applies edited notes. A note with chronicle_id updates that
+// page; one without creates a page in the category its chronicle_type slug
+// names. Front-matter properties fill the matching fields (others are kept),
+// and a non-empty body replaces the entry. Scribe or above only: a player's
+// pull has GM secrets stripped, so their notes can't round-trip safely.
+// POST /api/v1/campaigns/:id/obsidian/push
+func (h *APIHandler) ObsidianPush(c echo.Context) error {
+	key := GetAPIKey(c)
+	if key == nil {
+		return apperror.NewUnauthorized("api key required")
+	}
+	if h.resolveRole(c) < int(campaigns.RoleScribe) {
+		return apperror.NewForbidden("pushing notes requires the scribe role or above")
+	}
+
+	var req obsidianPushRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.NewBadRequest("invalid request body")
+	}
+	if len(req.Notes) > obsidianMaxPush {
+		return apperror.NewBadRequest(fmt.Sprintf("too many notes; maximum is %d per request", obsidianMaxPush))
+	}
+
+	results := make([]obsidianPushResult, 0, len(req.Notes))
+	for _, n := range req.Notes {
+		result, err := h.pushObsidianNote(c, key, n.Path, n.Markdown)
+		if err != nil {
+			result.Status = "error"
+			result.Error = apperror.SafeMessage(err)
+			if appErr, ok := err.(*apperror.AppError); ok && appErr.Code == http.StatusConflict {
+				result.Status = "conflict"
+			}
+		}
+		results = append(results, result)
+	}
+	return c.JSON(http.StatusOK, map[string]any{"results": results})
+}
+
+// pushObsidianNote creates or updates the page for one note and returns it
+// re-rendered.
+func (h *APIHandler) pushObsidianNote(c echo.Context, key *APIKey, notePath, text string) (obsidianPushResult, error) {
+	ctx := c.Request().Context()
+	campaignID := c.Param("id")
+	result := obsidianPushResult{Path: notePath}
+	if len(text) > obsidianMaxNoteSize {
+		return result, apperror.NewBadRequest(fmt.Sprintf("note too large, maximum %d KB", obsidianMaxNoteSize>>10))
+	}
+	fm, body, err := splitFrontMatter(text)
+	if err != nil {
+		return result, err
+	}
+
+	var entryJSON, entryHTML string
+	if body != "" {
+		if h.markdownConv == nil {
+			return result, apperror.NewBadRequest("note bodies can't be imported on this server")
+		}
+		if entryJSON, entryHTML, err = h.markdownConv.ConvertMarkdown(body); err != nil {
+			return result, apperror.NewBadRequest(err.Error())
+		}
+	}
+
+	// Without a name property an update keeps the page's name and a new
+	// page takes the note's file name.
+	name := frontMatterString(fm, fmName)
+	var isPrivate *bool
+	if p, ok := fm[fmPrivate].(bool); ok {
+		isPrivate = &p
+	}
+
+	var entity *entities.Entity
+	if id := frontMatterString(fm, fmID); id != "" {
+		existing, err := h.entitySvc.GetByID(ctx, id)
+		if err != nil || existing.CampaignID != campaignID {
+			return result, apperror.NewNotFound("entity not found")
+		}
+		et, err := h.entitySvc.GetEntityTypeByID(ctx, existing.EntityTypeID)
+		if err != nil {
+			return result, err
+		}
+		data := make(map[string]any, len(existing.FieldsData))
+		for k, v := range existing.FieldsData {
+			data[k] = v
+		}
+		result.Ignored = mapFlatFields(fm, obsidianReserved, entities.MergeFields(et.Fields, existing.FieldOverrides), data)
+
+		if name == "" {
+			name = existing.Name
+		}
+		input := entities.UpdateEntityInput{Name: name, IsPrivate: isPrivate, FieldsData: data}
+		if existing.TypeLabel != nil {
+			input.TypeLabel = *existing.TypeLabel
+		}
+		if existing.ParentID != nil {
+			input.ParentID = *existing.ParentID
+		}
+		if v := frontMatterString(fm, fmUpdated); v != "" {
+			based, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return result, apperror.NewBadRequest(fmUpdated + " is not an RFC 3339 timestamp")
+			}
+			input.ExpectedUpdatedAt = &based
+		}
+		if entity, err = h.entitySvc.Update(ctx, existing.ID, input); err != nil {
+			return result, err
+		}
+		result.Status = "updated"
+	} else {
+		slug := frontMatterString(fm, fmType)
+		if slug == "" {
+			return result, apperror.NewBadRequest(fmType + " is required for a new note")
+		}
+		et, err := h.entitySvc.GetEntityTypeBySlug(ctx, campaignID, slug)
+		if err != nil || et == nil {
+			return result, apperror.NewBadRequest("unknown " + fmType + " " + slug)
+		}
+		data := map[string]any{}
+		result.Ignored = mapFlatFields(fm, obsidianReserved, et.Fields, data)
+		if name == "" {
+			name = strings.TrimSuffix(path.Base(notePath), path.Ext(notePath))
+		}
+		if entity, err = h.entitySvc.Create(ctx, campaignID, key.UserID, entities.CreateEntityInput{
+			Name:         name,
+			EntityTypeID: et.ID,
+			IsPrivate:    isPrivate != nil && *isPrivate,
+			FieldsData:   data,
+		}); err != nil {
+			return result, err
+		}
+		result.Status = "created"
+	}
+	result.EntityID = entity.ID
+
+	if entryJSON != "" {
+		if err := h.entitySvc.UpdateEntry(ctx, entity.ID, entryJSON, entryHTML); err != nil {
+			return result, err
+		}
+	}
+	// Re-read so the returned note carries the final chronicle_updated.
+	if fresh, err := h.entitySvc.GetByID(ctx, entity.ID); err == nil {
+		entity = fresh
+	}
+	et, err := h.entitySvc.GetEntityTypeByID(ctx, entity.EntityTypeID)
+	typeSlug := ""
+	if err == nil && et != nil {
+		typeSlug = et.Slug
+	}
+	sanitizeEntityHTMLForEgress(entity)
+	if result.Markdown, err = renderObsidianNote(entity, typeSlug); err != nil {
+		slog.Warn("api: obsidian note render failed after push",
+			slog.String("entity_id", entity.ID), slog.Any("error", err))
+	}
+	return result, nil
+}
@@ -0,0 +1,281 @@
+package syncapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+	"github.com/keyxmakerx/chronicle/internal/plugins/entities"
+)
+
+var obsidianBase = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// stubEntitySvcForObsidian holds a few pages of one "npc" category and
+// records what a push wrote.
+type stubEntitySvcForObsidian struct {
+	entities.EntityService
+	pages   map[string]*entities.Entity
+	created *entities.CreateEntityInput
+	updated *entities.UpdateEntityInput
+	entry   string
+}
+
+func newObsidianStub() *stubEntitySvcForObsidian {
+	secret := `<p>Runs the <strong>docks</strong>.<span data-secret="true"> Smuggler.</span></p>`
+	return &stubEntitySvcForObsidian{pages: map[string]*entities.Entity{
+		"ent-a": {ID: "ent-a", CampaignID: "camp-1", EntityTypeID: 7, Name: "Mara", Slug: "mara",
+			EntryHTML: &secret, UpdatedAt: obsidianBase,
+			FieldsData: map[string]any{"title": "Harbormaster", "gm_notes": "bribed"}},
+		"ent-b": {ID: "ent-b", CampaignID: "camp-1", EntityTypeID: 7, Name: "Oren", Slug: "oren",
+			UpdatedAt: obsidianBase.Add(time.Hour)},
+		"ent-c": {ID: "ent-c", CampaignID: "camp-1", EntityTypeID: 7, Name: "Pell", Slug: "pell",
+			UpdatedAt: obsidianBase.Add(2 * time.Hour)},
+	}}
+}
+
+func (s *stubEntitySvcForObsidian) List(_ context.Context, _ string, _ int, _ int, _ string, opts entities.ListOptions) ([]entities.Entity, int, error) {
+	if opts.Page > 1 {
+		return nil, 3, nil
+	}
+	// Newest first, as Sort "updated" orders them.
+	return []entities.Entity{*s.pages["ent-c"], *s.pages["ent-b"], *s.pages["ent-a"]}, 3, nil
+}
+
+func (s *stubEntitySvcForObsidian) GetEntityTypes(context.Context, string) ([]entities.EntityType, error) {
+	et, _ := s.GetEntityTypeByID(context.Background(), 7)
+	return []entities.EntityType{*et}, nil
+}
+
+func (s *stubEntitySvcForObsidian) GetEntityTypeByID(context.Context, int) (*entities.EntityType, error) {
+	return &entities.EntityType{ID: 7, Slug: "npc", Name: "NPC", Enabled: true, Fields: []entities.FieldDefinition{
+		{Key: "title"},
+		{Key: "gm_notes", GMOnly: true},
+	}}, nil
+}
+
+func (s *stubEntitySvcForObsidian) GetEntityTypeBySlug(_ context.Context, _, slug string) (*entities.EntityType, error) {
+	if slug != "npc" {
+		return nil, apperror.NewNotFound("entity type not found")
+	}
+	return s.GetEntityTypeByID(context.Background(), 7)
+}
+
+func (s *stubEntitySvcForObsidian) GetByID(_ context.Context, id string) (*entities.Entity, error) {
+	e, ok := s.pages[id]
+	if !ok {
+		return nil, apperror.NewNotFound("entity not found")
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (s *stubEntitySvcForObsidian) Create(_ context.Context, campaignID, _ string, input entities.CreateEntityInput) (*entities.Entity, error) {
+	s.created = &input
+	e := &entities.Entity{ID: "ent-new", CampaignID: campaignID, EntityTypeID: input.EntityTypeID,
+		Name: input.Name, FieldsData: input.FieldsData, UpdatedAt: obsidianBase.Add(3 * time.Hour)}
+	s.pages[e.ID] = e
+	return e, nil
+}
+
+func (s *stubEntitySvcForObsidian) Update(_ context.Context, id string, input entities.UpdateEntityInput) (*entities.Entity, error) {
+	e := s.pages[id]
+	if input.ExpectedUpdatedAt != nil && e.UpdatedAt.After(*input.ExpectedUpdatedAt) {
+		return nil, apperror.NewConflict("entity was modified by another user; refresh and retry")
+	}
+	s.updated = &input
+	e.Name = input.Name
+	e.FieldsData = input.FieldsData
+	return e, nil
+}
+
+func (s *stubEntitySvcForObsidian) UpdateEntry(_ context.Context, _, entryJSON, _ string) error {
+	s.entry = entryJSON
+	return nil
+}
+
+// stubMarkdownConverter records the Markdown it was given as the entry.
+type stubMarkdownConverter struct{}
+
+func (stubMarkdownConverter) ConvertMarkdown(markdown string) (string, string, error) {
+	out, _ := json.Marshal(map[string]string{"markdown": markdown})
+	return string(out), "<p>" + markdown + "</p>", nil
+}
+
+// newObsidianContext builds a request context for role; a Player is
+// session-authed, anyone else uses a stored Bearer key (Owner-level).
+func newObsidianContext(method, target, body string, role campaigns.Role) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("camp-1")
+	keyID := 42
+	if role == campaigns.RolePlayer {
+		keyID = synthKeySessionID
+	}
+	c.Set(apiKeyContextKey, &APIKey{ID: keyID, CampaignID: "camp-1", UserID: "user-1"})
+	return c, rec
+}
+
+func newObsidianHandler(svc *stubEntitySvcForObsidian, role campaigns.Role) *APIHandler {
+	return &APIHandler{
+		entitySvc:    svc,
+		campaignSvc:  &stubCampaignSvcForRole{getMemberFn: memberWithRole(role)},
+		markdownConv: stubMarkdownConverter{},
+	}
+}
+
+func TestObsidianChanges_PagesOldestFirstWithCursor(t *testing.T) {
+	svc := newObsidianStub()
+	c, rec := newObsidianContext(http.MethodGet, "/?limit=2", "", campaigns.RoleOwner)
+	if err := newObsidianHandler(svc, campaigns.RoleOwner).ObsidianChanges(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var first obsidianChangesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &first); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(first.Notes) != 2 || first.Notes[0].ID != "ent-a" || first.Notes[1].ID != "ent-b" || !first.HasMore {
+		t.Fatalf("first page = %+v, want ent-a, ent-b and has_more", first)
+	}
+	if first.Notes[0].Path != "npc/mara.md" {
+		t.Errorf("path = %q, want npc/mara.md", first.Notes[0].Path)
+	}
+
+	c, rec = newObsidianContext(http.MethodGet, "/?limit=2&cursor="+first.NextCursor, "", campaigns.RoleOwner)
+	if err := newObsidianHandler(svc, campaigns.RoleOwner).ObsidianChanges(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var second obsidianChangesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &second); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(second.Notes) != 1 || second.Notes[0].ID != "ent-c" || second.HasMore {
+		t.Fatalf("second page = %+v, want only ent-c", second)
+	}
+}
+
+func TestObsidianChanges_RejectsBadCursor(t *testing.T) {
+	c, _ := newObsidianContext(http.MethodGet, "/?cursor=%25%25", "", campaigns.RoleOwner)
+	err := newObsidianHandler(newObsidianStub(), campaigns.RoleOwner).ObsidianChanges(c)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusBadRequest {
+		t.Fatalf("err = %v, want 400", err)
+	}
+}
+
+func TestObsidianChanges_PlayerGetsEgressRules(t *testing.T) {
+	c, rec := newObsidianContext(http.MethodGet, "/", "", campaigns.RolePlayer)
+	if err := newObsidianHandler(newObsidianStub(), campaigns.RolePlayer).ObsidianChanges(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resp obsidianChangesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	note := resp.Notes[0].Markdown
+	for _, leaked := range []string{"Smuggler", "gm_notes", "bribed"} {
+		if strings.Contains(note, leaked) {
+			t.Errorf("player note leaks %q:\n%s", leaked, note)
+		}
+	}
+	for _, want := range []string{"chronicle_id: ent-a", "chronicle_type: npc", "title: Harbormaster", "**docks**"} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note missing %q:\n%s", want, note)
+		}
+	}
+}
+
+func TestSplitFrontMatter_RoundTrip(t *testing.T) {
+	svc := newObsidianStub()
+	e := svc.pages["ent-a"]
+	text, err := renderObsidianNote(e, "npc")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	fm, body, err := splitFrontMatter(text)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if frontMatterString(fm, fmID) != "ent-a" || frontMatterString(fm, fmName) != "Mara" {
+		t.Errorf("front matter = %v", fm)
+	}
+	if got := frontMatterString(fm, fmUpdated); got != obsidianBase.Format(time.RFC3339Nano) {
+		t.Errorf("chronicle_updated = %q", got)
+	}
+	if !strings.Contains(body, "**docks**") {
+		t.Errorf("body = %q", body)
+	}
+
+	if _, _, err := splitFrontMatter("---\nname: x\nno close"); err == nil {
+		t.Error("unclosed front matter accepted")
+	}
+	if fm, body, err := splitFrontMatter("Just text.\n"); err != nil || len(fm) != 0 || body != "Just text." {
+		t.Errorf("plain note = %v, %q, %v", fm, body, err)
+	}
+}
+
+func TestObsidianPush_UpdatesCreatesAndConflicts(t *testing.T) {
+	svc := newObsidianStub()
+	stale := obsidianBase.Add(-time.Hour).Format(time.RFC3339)
+	current := obsidianBase.Add(time.Hour).Format(time.RFC3339)
+	body, _ := json.Marshal(map[string]any{"notes": []map[string]string{
+		{"path": "npc/mara.md", "markdown": "---\nchronicle_id: ent-a\nchronicle_updated: " + stale + "\nname: Mara\n---\nEdited."},
+		{"path": "npc/oren.md", "markdown": "---\nchronicle_id: ent-b\nchronicle_updated: " + current + "\ntitle: Smith\nmood: grumpy\n---\n# Oren\n\nNow a *smith*."},
+		{"path": "npc/Quill.md", "markdown": "---\nchronicle_type: npc\ntitle: Scout\n---\n"},
+		{"path": "loose.md", "markdown": "No front matter."},
+	}})
+	c, rec := newObsidianContext(http.MethodPost, "/", string(body), campaigns.RoleOwner)
+	if err := newObsidianHandler(svc, campaigns.RoleOwner).ObsidianPush(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resp struct {
+		Results []obsidianPushResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Results) != 4 {
+		t.Fatalf("results = %+v", resp.Results)
+	}
+	if r := resp.Results[0]; r.Status != "conflict" {
+		t.Errorf("stale note = %+v, want conflict", r)
+	}
+
+	if r := resp.Results[1]; r.Status != "updated" || len(r.Ignored) != 1 || r.Ignored[0] != "mood" {
+		t.Errorf("oren = %+v, want updated with mood ignored", r)
+	}
+	if svc.updated.Name != "Oren" || svc.updated.FieldsData["title"] != "Smith" {
+		t.Errorf("update input = %+v", svc.updated)
+	}
+	if !strings.Contains(svc.entry, "Now a *smith*.") {
+		t.Errorf("entry JSON = %s", svc.entry)
+	}
+
+	if r := resp.Results[2]; r.Status != "created" || r.EntityID != "ent-new" || !strings.Contains(r.Markdown, "chronicle_id: ent-new") {
+		t.Errorf("quill = %+v, want created note", r)
+	}
+	if svc.created.Name != "Quill" || svc.created.EntityTypeID != 7 || svc.created.FieldsData["title"] != "Scout" {
+		t.Errorf("create input = %+v", svc.created)
+	}
+
+	if r := resp.Results[3]; r.Status != "error" || !strings.Contains(r.Error, fmType) {
+		t.Errorf("loose note = %+v, want chronicle_type error", r)
+	}
+}
+
+func TestObsidianPush_PlayerForbidden(t *testing.T) {
+	c, _ := newObsidianContext(http.MethodPost, "/", `{"notes":[]}`, campaigns.RolePlayer)
+	err := newObsidianHandler(newObsidianStub(), campaigns.RolePlayer).ObsidianPush(c)
+	if appErr, ok := err.(*apperror.AppError); !ok || appErr.Code != http.StatusForbidden {
+		t.Fatalf("err = %v, want 403", err)
+	}
+}
@@ -0,0 +1,114 @@
+package syncapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+)
+
+// MergeDuplicateSyncMappings leaves one sync mapping per external record so
+// migration 009 can make (campaign_id, external_system, external_id)
+// unique. Of each duplicate set it keeps the most recently synced mapping
+// (the later id on a tie) and deletes the rest, logging every dropped row
+// so an operator can re-link anything that mattered.
+//
+// Called from cmd/server/main.go right before database.RunPluginMigrations.
+// Idempotent: with no duplicates left (always, once the unique key exists)
+// it deletes nothing. Returns the number of mappings dropped.
+func MergeDuplicateSyncMappings(ctx context.Context, db *sql.DB) (int, error) {
+	return mergeDuplicateSyncMappings(ctx, sqlMappingDeduper{db})
+}
+
+// mappingDeduper is the narrow contract the merge needs, so a test can
+// stub it without a real DB.
+type mappingDeduper interface {
+	// tableExists reports whether sync_mappings exists yet; on a fresh
+	// install the merge runs before migration 001 creates it.
+	tableExists(ctx context.Context) (bool, error)
+	// supersededMappings returns every mapping that a newer mapping of the
+	// same external record supersedes.
+	supersededMappings(ctx context.Context) ([]SyncMapping, error)
+	deleteMapping(ctx context.Context, id string) error
+}
+
+// sqlMappingDeduper is the production implementation backed by a *sql.DB.
+type sqlMappingDeduper struct{ db *sql.DB }
+
+func (d sqlMappingDeduper) tableExists(ctx context.Context) (bool, error) {
+	var n int
+	err := d.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.tables
+		 WHERE table_schema = DATABASE()
+		   AND table_name = 'sync_mappings'
+	`).Scan(&n)
+	return n > 0, err
+}
+
+func (d sqlMappingDeduper) supersededMappings(ctx context.Context) ([]SyncMapping, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT DISTINCT older.id, older.campaign_id, older.chronicle_type, older.chronicle_id,
+		       older.external_system, older.external_id, older.last_synced_at
+		  FROM sync_mappings older
+		  JOIN sync_mappings newer
+		    ON newer.campaign_id = older.campaign_id
+		   AND newer.external_system = older.external_system
+		   AND newer.external_id = older.external_id
+		   AND (newer.last_synced_at > older.last_synced_at
+		        OR (newer.last_synced_at = older.last_synced_at AND newer.id > older.id))`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SyncMapping
+	for rows.Next() {
+		var m SyncMapping
+		if err := rows.Scan(&m.ID, &m.CampaignID, &m.ChronicleType, &m.ChronicleID,
+			&m.ExternalSystem, &m.ExternalID, &m.LastSyncedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (d sqlMappingDeduper) deleteMapping(ctx context.Context, id string) error {
+	_, err := d.db.ExecContext(ctx, `DELETE FROM sync_mappings WHERE id = ?`, id)
+	return err
+}
+
+// mergeDuplicateSyncMappings is the testable core. A failed delete is
+// returned rather than skipped: the unique key can't be added while any
+// duplicate remains, so the migration would fail anyway.
+func mergeDuplicateSyncMappings(ctx context.Context, d mappingDeduper) (int, error) {
+	exists, err := d.tableExists(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("checking for sync_mappings: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	superseded, err := d.supersededMappings(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing duplicate sync mappings: %w", err)
+	}
+	dropped := 0
+	for _, m := range superseded {
+		if err := d.deleteMapping(ctx, m.ID); err != nil {
+			return dropped, fmt.Errorf("dropping duplicate sync mapping %s: %w", m.ID, err)
+		}
+		dropped++
+		slog.Warn("dropped duplicate sync mapping",
+			slog.String("mapping_id", m.ID),
+			slog.String("campaign_id", m.CampaignID),
+			slog.String("chronicle_type", m.ChronicleType),
+			slog.String("chronicle_id", m.ChronicleID),
+			slog.String("external_system", m.ExternalSystem),
+			slog.String("external_id", m.ExternalID),
+			slog.Time("last_synced_at", m.LastSyncedAt),
+		)
+	}
+	return dropped, nil
+}
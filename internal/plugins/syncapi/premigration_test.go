@@ -0,0 +1,68 @@
+package syncapi
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeDeduper stubs mappingDeduper with canned rows and records deletes.
+type fakeDeduper struct {
+	exists     bool
+	superseded []SyncMapping
+	deleteErr  error
+	deleted    []string
+}
+
+func (f *fakeDeduper) tableExists(context.Context) (bool, error) { return f.exists, nil }
+
+func (f *fakeDeduper) supersededMappings(context.Context) ([]SyncMapping, error) {
+	// Deleted rows are gone on the next run, as in the database.
+	var left []SyncMapping
+	for _, m := range f.superseded {
+		if !slices.Contains(f.deleted, m.ID) {
+			left = append(left, m)
+		}
+	}
+	return left, nil
+}
+
+func (f *fakeDeduper) deleteMapping(_ context.Context, id string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestMergeDuplicateSyncMappings_DropsSupersededOnce(t *testing.T) {
+	f := &fakeDeduper{exists: true, superseded: []SyncMapping{
+		{ID: "m1", CampaignID: "c1", ExternalSystem: "kanka", ExternalID: "42"},
+		{ID: "m2", CampaignID: "c1", ExternalSystem: "kanka", ExternalID: "42"},
+	}}
+	n, err := mergeDuplicateSyncMappings(context.Background(), f)
+	if err != nil || n != 2 {
+		t.Fatalf("first run = %d, %v; want 2, nil", n, err)
+	}
+	if len(f.deleted) != 2 || f.deleted[0] != "m1" || f.deleted[1] != "m2" {
+		t.Errorf("deleted = %v", f.deleted)
+	}
+	if n, err := mergeDuplicateSyncMappings(context.Background(), f); err != nil || n != 0 {
+		t.Errorf("second run = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestMergeDuplicateSyncMappings_NoTable(t *testing.T) {
+	f := &fakeDeduper{superseded: []SyncMapping{{ID: "m1"}}}
+	if n, err := mergeDuplicateSyncMappings(context.Background(), f); err != nil || n != 0 {
+		t.Errorf("fresh install = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestMergeDuplicateSyncMappings_DeleteError(t *testing.T) {
+	f := &fakeDeduper{exists: true, superseded: []SyncMapping{{ID: "m1"}}, deleteErr: errors.New("locked")}
+	if _, err := mergeDuplicateSyncMappings(context.Background(), f); err == nil {
+		t.Error("expected the delete error to be returned")
+	}
+}
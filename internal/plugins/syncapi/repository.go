@@ -6,11 +6,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/keyxmakerx/chronicle/internal/apperror"
 )
 
+// ErrDeviceAlreadyBound is returned by BindDevice when another device
+// bound the key first.
+var ErrDeviceAlreadyBound = errors.New("api key already bound to a device")
+
 // SyncAPIRepository defines the data access contract for the sync API.
 type SyncAPIRepository interface {
 	// API key management.
@@ -28,7 +33,10 @@ type SyncAPIRepository interface {
 
 	// Request logging.
 	LogRequest(ctx context.Context, log *APIRequestLog) error
+	InsertRequestLogs(ctx context.Context, logs []APIRequestLog) error
 	ListRequestLogs(ctx context.Context, filter RequestLogFilter) ([]APIRequestLog, int, error)
+	PurgeRequestLogsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error)
+	RollupRequestLogs(ctx context.Context, before time.Time) (int64, error)
 	GetRequestTimeSeries(ctx context.Context, since time.Time, interval string) ([]TimeSeriesPoint, error)
 	GetTopIPs(ctx context.Context, since time.Time, limit int) ([]TopEntry, error)
 	GetTopPaths(ctx context.Context, since time.Time, limit int) ([]TopEntry, error)
@@ -78,9 +86,9 @@ func (r *syncAPIRepository) CreateKey(ctx context.Context, key *APIKey) error {
 	}
 
 	result, err := r.db.ExecContext(ctx,
-		`INSERT INTO api_keys (key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist, rate_limit, expires_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		key.KeyHash, key.KeyPrefix, key.Name, key.VTTTag, key.UserID, key.CampaignID,
+		`INSERT INTO api_keys (key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist, rate_limit, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.KeyHash, key.KeyPrefix, key.Name, key.VTTTag, key.IsDemo, key.UserID, key.CampaignID,
 		permsJSON, ipJSON, key.RateLimit, key.ExpiresAt,
 	)
 	if err != nil {
@@ -94,7 +102,7 @@ func (r *syncAPIRepository) CreateKey(ctx context.Context, key *APIKey) error {
 // FindKeyByID retrieves an API key by its ID.
 func (r *syncAPIRepository) FindKeyByID(ctx context.Context, id int) (*APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist,
+		`SELECT id, key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist,
 		        rate_limit, is_active, last_used_at, last_used_ip, expires_at, device_fingerprint, device_bound_at, created_at, updated_at
 		 FROM api_keys WHERE id = ?`, id))
 }
@@ -102,7 +110,7 @@ func (r *syncAPIRepository) FindKeyByID(ctx context.Context, id int) (*APIKey, e
 // FindKeyByPrefix retrieves an API key by its prefix (for auth lookup).
 func (r *syncAPIRepository) FindKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
 	return r.scanKey(r.db.QueryRowContext(ctx,
-		`SELECT id, key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist,
+		`SELECT id, key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist,
 		        rate_limit, is_active, last_used_at, last_used_ip, expires_at, device_fingerprint, device_bound_at, created_at, updated_at
 		 FROM api_keys WHERE key_prefix = ?`, prefix))
 }
@@ -110,7 +118,7 @@ func (r *syncAPIRepository) FindKeyByPrefix(ctx context.Context, prefix string)
 // ListKeysByUser returns all API keys owned by a user.
 func (r *syncAPIRepository) ListKeysByUser(ctx context.Context, userID string) ([]APIKey, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist,
+		`SELECT id, key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist,
 		        rate_limit, is_active, last_used_at, last_used_ip, expires_at, device_fingerprint, device_bound_at, created_at, updated_at
 		 FROM api_keys WHERE user_id = ? ORDER BY created_at DESC`, userID)
 	if err != nil {
@@ -123,7 +131,7 @@ func (r *syncAPIRepository) ListKeysByUser(ctx context.Context, userID string) (
 // ListKeysByCampaign returns all API keys for a campaign.
 func (r *syncAPIRepository) ListKeysByCampaign(ctx context.Context, campaignID string) ([]APIKey, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist,
+		`SELECT id, key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist,
 		        rate_limit, is_active, last_used_at, last_used_ip, expires_at, device_fingerprint, device_bound_at, created_at, updated_at
 		 FROM api_keys WHERE campaign_id = ? ORDER BY created_at DESC`, campaignID)
 	if err != nil {
@@ -141,7 +149,7 @@ func (r *syncAPIRepository) ListAllKeys(ctx context.Context, limit, offset int)
 	}
 
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, key_hash, key_prefix, name, vtt_tag, user_id, campaign_id, permissions, ip_allowlist,
+		`SELECT id, key_hash, key_prefix, name, vtt_tag, is_demo, user_id, campaign_id, permissions, ip_allowlist,
 		        rate_limit, is_active, last_used_at, last_used_ip, expires_at, device_fingerprint, device_bound_at, created_at, updated_at
 		 FROM api_keys ORDER BY created_at DESC LIMIT ? OFFSET ?`, limit, offset)
 	if err != nil {
@@ -180,14 +188,18 @@ func (r *syncAPIRepository) UpdateKeyLastUsed(ctx context.Context, id int, ip st
 // BindDevice atomically records a device fingerprint on an API key.
 // Uses a conditional UPDATE (WHERE device_fingerprint IS NULL) to prevent
 // race conditions where concurrent first requests could bind different devices.
+// Returns ErrDeviceAlreadyBound when the key already carries a fingerprint.
 func (r *syncAPIRepository) BindDevice(ctx context.Context, keyID int, fingerprint string, boundAt time.Time) error {
-	_, err := r.db.ExecContext(ctx,
+	res, err := r.db.ExecContext(ctx,
 		`UPDATE api_keys SET device_fingerprint = ?, device_bound_at = ?
 		 WHERE id = ? AND device_fingerprint IS NULL`,
 		fingerprint, boundAt, keyID)
 	if err != nil {
 		return fmt.Errorf("binding device: %w", err)
 	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrDeviceAlreadyBound
+	}
 	return nil
 }
 
@@ -218,17 +230,115 @@ func (r *syncAPIRepository) DeleteKey(ctx context.Context, id int) error {
 
 // LogRequest records an API request.
 func (r *syncAPIRepository) LogRequest(ctx context.Context, log *APIRequestLog) error {
-	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO api_request_log (api_key_id, campaign_id, user_id, method, path, status_code,
-		 ip_address, user_agent, request_size, response_size, duration_ms, error_message)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		log.APIKeyID, log.CampaignID, log.UserID, log.Method, log.Path, log.StatusCode,
-		log.IPAddress, log.UserAgent, log.RequestSize, log.ResponseSize, log.DurationMs, log.ErrorMessage,
+	return r.InsertRequestLogs(ctx, []APIRequestLog{*log})
+}
+
+// InsertRequestLogs records a batch of API requests in one statement. A zero
+// CreatedAt means now and a zero SampleWeight means 1.
+func (r *syncAPIRepository) InsertRequestLogs(ctx context.Context, logs []APIRequestLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString(`INSERT INTO api_request_log (api_key_id, campaign_id, user_id, method, path, status_code,
+		 ip_address, user_agent, request_size, response_size, duration_ms, error_message, sample_weight, created_at)
+		 VALUES `)
+	args := make([]any, 0, len(logs)*14)
+	now := time.Now().UTC()
+	for i, l := range logs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		createdAt, weight := l.CreatedAt, l.SampleWeight
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		if weight < 1 {
+			weight = 1
+		}
+		args = append(args, l.APIKeyID, l.CampaignID, l.UserID, l.Method, l.Path, l.StatusCode,
+			l.IPAddress, l.UserAgent, l.RequestSize, l.ResponseSize, l.DurationMs, l.ErrorMessage, weight, createdAt)
+	}
+	if _, err := r.db.ExecContext(ctx, sb.String(), args...); err != nil {
+		return fmt.Errorf("logging api requests: %w", err)
+	}
+	return nil
+}
+
+// PurgeRequestLogsBefore deletes up to limit of a campaign's request log rows
+// and hourly rollups older than before. Used by the campaign retention job.
+func (r *syncAPIRepository) PurgeRequestLogsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM api_request_log WHERE campaign_id = ? AND created_at < ? LIMIT ?`,
+		campaignID, before, limit,
 	)
 	if err != nil {
-		return fmt.Errorf("logging api request: %w", err)
+		return 0, fmt.Errorf("purging api request logs: %w", err)
 	}
-	return nil
+	n, err := res.RowsAffected()
+	if err != nil || n >= int64(limit) {
+		return n, err
+	}
+	res, err = r.db.ExecContext(ctx,
+		`DELETE FROM api_request_rollups WHERE campaign_id = ? AND hour_start < ? LIMIT ?`,
+		campaignID, before, int64(limit)-n,
+	)
+	if err != nil {
+		return n, fmt.Errorf("purging api request rollups: %w", err)
+	}
+	rolled, err := res.RowsAffected()
+	return n + rolled, err
+}
+
+// RollupRequestLogs folds the oldest hour of request log rows before
+// before into per-key, per-IP hourly totals and deletes the rows, in one
+// transaction. It returns the rows folded; 0 means nothing is left to roll
+// up. before should fall on the hour so no hour is split.
+func (r *syncAPIRepository) RollupRequestLogs(ctx context.Context, before time.Time) (int64, error) {
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT MIN(created_at) FROM api_request_log WHERE created_at < ?`, before,
+	).Scan(&oldest); err != nil {
+		return 0, fmt.Errorf("finding oldest request log: %w", err)
+	}
+	if !oldest.Valid {
+		return 0, nil
+	}
+	hourStart := oldest.Time.Truncate(time.Hour)
+	hourEnd := hourStart.Add(time.Hour)
+	if hourEnd.After(before) {
+		hourEnd = before
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO api_request_rollups (hour_start, api_key_id, campaign_id, ip_address, requests, errors, total_duration_ms)
+		 SELECT ?, api_key_id, MAX(campaign_id), ip_address, SUM(sample_weight),
+		        SUM(IF(status_code >= 400, sample_weight, 0)), SUM(duration_ms * sample_weight)
+		 FROM api_request_log WHERE created_at >= ? AND created_at < ?
+		 GROUP BY api_key_id, ip_address
+		 ON DUPLICATE KEY UPDATE requests = requests + VALUES(requests), errors = errors + VALUES(errors),
+		        total_duration_ms = total_duration_ms + VALUES(total_duration_ms)`,
+		hourStart, hourStart, hourEnd,
+	); err != nil {
+		return 0, fmt.Errorf("rolling up request logs: %w", err)
+	}
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM api_request_log WHERE created_at >= ? AND created_at < ?`, hourStart, hourEnd)
+	if err != nil {
+		return 0, fmt.Errorf("deleting rolled-up request logs: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
 }
 
 // ListRequestLogs returns filtered request logs with pagination.
@@ -242,7 +352,7 @@ func (r *syncAPIRepository) ListRequestLogs(ctx context.Context, filter RequestL
 	}
 
 	query := `SELECT id, api_key_id, campaign_id, user_id, method, path, status_code,
-	          ip_address, user_agent, request_size, response_size, duration_ms, error_message, created_at
+	          ip_address, user_agent, request_size, response_size, duration_ms, error_message, sample_weight, created_at
 	          FROM api_request_log` + where + ` ORDER BY created_at DESC LIMIT ? OFFSET ?`
 	args = append(args, filter.Limit, filter.Offset)
 
@@ -257,7 +367,7 @@ func (r *syncAPIRepository) ListRequestLogs(ctx context.Context, filter RequestL
 		var l APIRequestLog
 		if err := rows.Scan(&l.ID, &l.APIKeyID, &l.CampaignID, &l.UserID, &l.Method, &l.Path,
 			&l.StatusCode, &l.IPAddress, &l.UserAgent, &l.RequestSize, &l.ResponseSize,
-			&l.DurationMs, &l.ErrorMessage, &l.CreatedAt); err != nil {
+			&l.DurationMs, &l.ErrorMessage, &l.SampleWeight, &l.CreatedAt); err != nil {
 			return nil, 0, fmt.Errorf("scanning request log: %w", err)
 		}
 		logs = append(logs, l)
@@ -279,9 +389,9 @@ func (r *syncAPIRepository) GetRequestTimeSeries(ctx context.Context, since time
 	}
 
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT DATE_FORMAT(created_at, ?) as bucket, COUNT(*) as cnt
-		 FROM api_request_log WHERE created_at >= ?
-		 GROUP BY bucket ORDER BY bucket`, format, since)
+		`SELECT DATE_FORMAT(r.at, ?) as bucket, SUM(r.requests) as cnt
+		 FROM `+requestRowsSince+`
+		 GROUP BY bucket ORDER BY bucket`, format, since, since)
 	if err != nil {
 		return nil, fmt.Errorf("getting request time series: %w", err)
 	}
@@ -303,15 +413,16 @@ func (r *syncAPIRepository) GetRequestTimeSeries(ctx context.Context, since time
 // GetTopIPs returns the most active IPs by request count.
 func (r *syncAPIRepository) GetTopIPs(ctx context.Context, since time.Time, limit int) ([]TopEntry, error) {
 	return r.getTopEntries(ctx,
-		`SELECT ip_address, COUNT(*) as cnt FROM api_request_log
-		 WHERE created_at >= ? GROUP BY ip_address ORDER BY cnt DESC LIMIT ?`,
-		since, limit)
+		`SELECT r.ip_address, SUM(r.requests) as cnt FROM `+requestRowsSince+`
+		 GROUP BY r.ip_address ORDER BY cnt DESC LIMIT ?`,
+		since, since, limit)
 }
 
-// GetTopPaths returns the most requested API paths.
+// GetTopPaths returns the most requested API paths. Rollups don't keep
+// paths, so only requests not yet rolled up are counted.
 func (r *syncAPIRepository) GetTopPaths(ctx context.Context, since time.Time, limit int) ([]TopEntry, error) {
 	return r.getTopEntries(ctx,
-		`SELECT path, COUNT(*) as cnt FROM api_request_log
+		`SELECT path, SUM(sample_weight) as cnt FROM api_request_log
 		 WHERE created_at >= ? GROUP BY path ORDER BY cnt DESC LIMIT ?`,
 		since, limit)
 }
@@ -319,10 +430,10 @@ func (r *syncAPIRepository) GetTopPaths(ctx context.Context, since time.Time, li
 // GetTopKeys returns the most active API keys by request count.
 func (r *syncAPIRepository) GetTopKeys(ctx context.Context, since time.Time, limit int) ([]TopEntry, error) {
 	return r.getTopEntries(ctx,
-		`SELECT CONCAT(k.key_prefix, ' - ', k.name), COUNT(*) as cnt
-		 FROM api_request_log l JOIN api_keys k ON k.id = l.api_key_id
-		 WHERE l.created_at >= ? GROUP BY l.api_key_id ORDER BY cnt DESC LIMIT ?`,
-		since, limit)
+		`SELECT CONCAT(k.key_prefix, ' - ', k.name), SUM(r.requests) as cnt
+		 FROM `+requestRowsSince+` JOIN api_keys k ON k.id = r.api_key_id
+		 GROUP BY r.api_key_id ORDER BY cnt DESC LIMIT ?`,
+		since, since, limit)
 }
 
 // --- Security Events ---
@@ -497,15 +608,32 @@ func (r *syncAPIRepository) IsIPBlocked(ctx context.Context, ip string) (bool, e
 
 // --- Statistics ---
 
+// requestRowsSince is every request since a time as one derived table r:
+// recent log rows, each weighted by its sample, and the hourly rollups of
+// older ones. Columns: at, api_key_id, campaign_id, ip_address, requests,
+// errors, duration_ms (total). Each half takes the since argument, so
+// callers pass it twice.
+const requestRowsSince = `(
+		SELECT created_at AS at, api_key_id, campaign_id, ip_address, sample_weight AS requests,
+		       IF(status_code >= 400, sample_weight, 0) AS errors, duration_ms * sample_weight AS duration_ms
+		FROM api_request_log WHERE created_at >= ?
+		UNION ALL
+		SELECT hour_start, api_key_id, campaign_id, ip_address, requests, errors, total_duration_ms
+		FROM api_request_rollups WHERE hour_start >= ?
+	) r`
+
+// requestTotalsQuery sums requestRowsSince into request, error, unique IP
+// and average duration figures.
+const requestTotalsQuery = `SELECT COALESCE(SUM(r.requests), 0), COALESCE(SUM(r.errors), 0),
+		COUNT(DISTINCT r.ip_address), COALESCE(ROUND(SUM(r.duration_ms) / NULLIF(SUM(r.requests), 0)), 0)
+	FROM ` + requestRowsSince
+
 // GetStats returns aggregated API statistics since a given time.
 func (r *syncAPIRepository) GetStats(ctx context.Context, since time.Time) (*APIStats, error) {
 	stats := &APIStats{}
 
 	// Request stats.
-	if err := r.db.QueryRowContext(ctx,
-		`SELECT COUNT(*), COALESCE(SUM(CASE WHEN status_code >= 400 THEN 1 ELSE 0 END), 0),
-		        COUNT(DISTINCT ip_address), COALESCE(AVG(duration_ms), 0)
-		 FROM api_request_log WHERE created_at >= ?`, since).
+	if err := r.db.QueryRowContext(ctx, requestTotalsQuery, since, since).
 		Scan(&stats.TotalRequests, &stats.TotalErrors, &stats.UniqueIPs, &stats.AvgResponseTimeMs); err != nil {
 		return nil, fmt.Errorf("scanning request stats: %w", err)
 	}
@@ -538,10 +666,7 @@ func (r *syncAPIRepository) GetStats(ctx context.Context, since time.Time) (*API
 func (r *syncAPIRepository) GetCampaignStats(ctx context.Context, campaignID string, since time.Time) (*APIStats, error) {
 	stats := &APIStats{}
 
-	if err := r.db.QueryRowContext(ctx,
-		`SELECT COUNT(*), COALESCE(SUM(CASE WHEN status_code >= 400 THEN 1 ELSE 0 END), 0),
-		        COUNT(DISTINCT ip_address), COALESCE(AVG(duration_ms), 0)
-		 FROM api_request_log WHERE campaign_id = ? AND created_at >= ?`, campaignID, since).
+	if err := r.db.QueryRowContext(ctx, requestTotalsQuery+` WHERE r.campaign_id = ?`, since, since, campaignID).
 		Scan(&stats.TotalRequests, &stats.TotalErrors, &stats.UniqueIPs, &stats.AvgResponseTimeMs); err != nil {
 		return nil, fmt.Errorf("scanning campaign request stats: %w", err)
 	}
@@ -568,7 +693,7 @@ func (r *syncAPIRepository) scanKey(row *sql.Row) (*APIKey, error) {
 	var deviceFP sql.NullString
 	var deviceBoundAt sql.NullTime
 
-	err := row.Scan(&k.ID, &k.KeyHash, &k.KeyPrefix, &k.Name, &vttTag, &k.UserID, &k.CampaignID,
+	err := row.Scan(&k.ID, &k.KeyHash, &k.KeyPrefix, &k.Name, &vttTag, &k.IsDemo, &k.UserID, &k.CampaignID,
 		&permsRaw, &ipRaw, &k.RateLimit, &k.IsActive,
 		&lastUsedAt, &lastUsedIP, &expiresAt, &deviceFP, &deviceBoundAt,
 		&k.CreatedAt, &k.UpdatedAt)
@@ -619,7 +744,7 @@ func (r *syncAPIRepository) scanKeys(rows *sql.Rows) ([]APIKey, error) {
 		var deviceFP sql.NullString
 		var deviceBoundAt sql.NullTime
 
-		if err := rows.Scan(&k.ID, &k.KeyHash, &k.KeyPrefix, &k.Name, &vttTag, &k.UserID, &k.CampaignID,
+		if err := rows.Scan(&k.ID, &k.KeyHash, &k.KeyPrefix, &k.Name, &vttTag, &k.IsDemo, &k.UserID, &k.CampaignID,
 			&permsRaw, &ipRaw, &k.RateLimit, &k.IsActive,
 			&lastUsedAt, &lastUsedIP, &expiresAt, &deviceFP, &deviceBoundAt,
 			&k.CreatedAt, &k.UpdatedAt); err != nil {
@@ -656,8 +781,8 @@ func (r *syncAPIRepository) scanKeys(rows *sql.Rows) ([]APIKey, error) {
 }
 
 // getTopEntries is a generic helper for top-N queries.
-func (r *syncAPIRepository) getTopEntries(ctx context.Context, query string, since time.Time, limit int) ([]TopEntry, error) {
-	rows, err := r.db.QueryContext(ctx, query, since, limit)
+func (r *syncAPIRepository) getTopEntries(ctx context.Context, query string, args ...any) ([]TopEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("getting top entries: %w", err)
 	}
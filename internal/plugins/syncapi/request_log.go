@@ -0,0 +1,216 @@
+package syncapi
+
+// request_log.go — keeps API request logging off the request path's
+// database budget. LogRequest samples successful requests (failures are
+// always kept) and queues rows in a Redis list; RequestLogBuffer drains the
+// list into multi-row inserts every few seconds. RequestLogRollupJob folds
+// rows older than the rollup age into hourly totals, which the stats
+// queries read alongside the recent rows (see requestRowsSince).
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// requestLogQueueKey is the Redis list holding rows awaiting insert.
+	requestLogQueueKey = "syncapi:request_log"
+
+	// requestLogQueueMax caps the queue so a database outage can't grow it
+	// without bound; the oldest rows are dropped first.
+	requestLogQueueMax = 100_000
+
+	// requestLogFlushSize is the most rows one insert writes.
+	requestLogFlushSize = 500
+
+	// requestLogFlushInterval is how often the buffer drains.
+	requestLogFlushInterval = 2 * time.Second
+
+	// requestLogRollupInterval is how often the rollup job runs.
+	requestLogRollupInterval = time.Hour
+)
+
+// SetRequestLogging configures request log sampling and, with a non-nil
+// buffer, queues rows there instead of inserting them one by one.
+// sampleRate is the share of successful requests kept (0 < rate <= 1).
+func SetRequestLogging(svc SyncAPIService, buffer *RequestLogBuffer, sampleRate float64) {
+	if s, ok := svc.(*syncAPIService); ok {
+		s.logBuffer = buffer
+		s.sampleRate = sampleRate
+	}
+}
+
+// sampleRequest decides whether a request is logged and returns the weight
+// its row carries. Failed requests are always logged at weight 1.
+func (s *syncAPIService) sampleRequest(log *APIRequestLog) (keep bool, weight int) {
+	rate := s.sampleRate
+	if rate <= 0 || rate >= 1 || log.StatusCode >= 400 {
+		return true, 1
+	}
+	if s.random() >= rate {
+		return false, 0
+	}
+	return true, int(math.Round(1 / rate))
+}
+
+// random returns a number in [0, 1) for sampling.
+func (s *syncAPIService) random() float64 {
+	if s.rand != nil {
+		return s.rand()
+	}
+	return rand.Float64()
+}
+
+// RequestLogBuffer queues request log rows in Redis and writes them in
+// batches. The list is shared, so in cluster mode any instance may flush
+// rows queued by another.
+type RequestLogBuffer struct {
+	rdb  *redis.Client
+	repo SyncAPIRepository
+}
+
+// NewRequestLogBuffer creates a buffer over the given Redis client.
+func NewRequestLogBuffer(rdb *redis.Client, repo SyncAPIRepository) *RequestLogBuffer {
+	return &RequestLogBuffer{rdb: rdb, repo: repo}
+}
+
+// Push queues one row.
+func (b *RequestLogBuffer) Push(ctx context.Context, log *APIRequestLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("encoding request log: %w", err)
+	}
+	pipe := b.rdb.Pipeline()
+	pipe.RPush(ctx, requestLogQueueKey, data)
+	pipe.LTrim(ctx, requestLogQueueKey, -requestLogQueueMax, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("queueing request log: %w", err)
+	}
+	return nil
+}
+
+// Flush writes up to requestLogFlushSize queued rows and returns how many
+// it wrote. Rows whose insert fails go back on the queue.
+func (b *RequestLogBuffer) Flush(ctx context.Context) (int, error) {
+	raw, err := b.rdb.LPopCount(ctx, requestLogQueueKey, requestLogFlushSize).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading request log queue: %w", err)
+	}
+
+	logs := make([]APIRequestLog, 0, len(raw))
+	for _, r := range raw {
+		var l APIRequestLog
+		if err := json.Unmarshal([]byte(r), &l); err != nil {
+			slog.Warn("dropping malformed queued request log", slog.Any("error", err))
+			continue
+		}
+		logs = append(logs, l)
+	}
+	if err := b.repo.InsertRequestLogs(ctx, logs); err != nil {
+		items := make([]any, len(raw))
+		for i, r := range raw {
+			items[i] = r
+		}
+		if requeueErr := b.rdb.LPush(ctx, requestLogQueueKey, items...).Err(); requeueErr != nil {
+			slog.Warn("request logs lost after failed insert",
+				slog.Int("rows", len(raw)), slog.Any("error", requeueErr))
+		}
+		return 0, err
+	}
+	return len(logs), nil
+}
+
+// Start drains the queue every requestLogFlushInterval until ctx is
+// cancelled, flushing repeatedly while full batches keep coming.
+func (b *RequestLogBuffer) Start(ctx context.Context) {
+	ticker := time.NewTicker(requestLogFlushInterval)
+	defer ticker.Stop()
+
+	slog.Info("api request log writer started")
+	for {
+		select {
+		case <-ctx.Done():
+			// Write what's left with a fresh context so a shutdown keeps it.
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, _ = b.Flush(flushCtx)
+			cancel()
+			slog.Info("api request log writer stopped")
+			return
+		case <-ticker.C:
+			for {
+				n, err := b.Flush(ctx)
+				if err != nil {
+					slog.Error("api request log flush failed", slog.Any("error", err))
+				}
+				if err != nil || n < requestLogFlushSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RequestLogRollupJob folds old request log rows into hourly totals.
+type RequestLogRollupJob struct {
+	repo   SyncAPIRepository
+	after  time.Duration
+	leader func(ctx context.Context) bool
+	now    func() time.Time
+}
+
+// NewRequestLogRollupJob creates the rollup job for rows older than after.
+// leader, when set, is asked before each run so only one instance of a
+// cluster rolls up.
+func NewRequestLogRollupJob(repo SyncAPIRepository, after time.Duration, leader func(ctx context.Context) bool) *RequestLogRollupJob {
+	return &RequestLogRollupJob{repo: repo, after: after, leader: leader, now: time.Now}
+}
+
+// Run rolls up every whole hour older than the rollup age, oldest first,
+// and returns the rows folded.
+func (j *RequestLogRollupJob) Run(ctx context.Context) (int64, error) {
+	before := j.now().UTC().Add(-j.after).Truncate(time.Hour)
+	var total int64
+	for {
+		n, err := j.repo.RollupRequestLogs(ctx, before)
+		total += n
+		if err != nil || n == 0 || ctx.Err() != nil {
+			return total, err
+		}
+	}
+}
+
+// Start runs the job hourly until ctx is cancelled.
+func (j *RequestLogRollupJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(requestLogRollupInterval)
+	defer ticker.Stop()
+
+	slog.Info("api request log rollup worker started")
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("api request log rollup worker stopped")
+			return
+		case <-ticker.C:
+			if j.leader != nil && !j.leader(ctx) {
+				continue
+			}
+			n, err := j.Run(ctx)
+			if err != nil {
+				slog.Error("api request log rollup failed", slog.Any("error", err))
+			} else if n > 0 {
+				slog.Info("api request logs rolled up", slog.Int64("rows", n))
+			}
+		}
+	}
+}
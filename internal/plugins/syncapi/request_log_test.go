@@ -0,0 +1,117 @@
+package syncapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestLogRequest_Sampling(t *testing.T) {
+	var logged []APIRequestLog
+	repo := &mockSyncAPIRepo{
+		logRequestFn: func(_ context.Context, log *APIRequestLog) error {
+			logged = append(logged, *log)
+			return nil
+		},
+	}
+	svc := NewSyncAPIService(repo).(*syncAPIService)
+	SetRequestLogging(svc, nil, 0.25)
+
+	for _, tc := range []struct {
+		roll   float64
+		status int
+	}{
+		{0.5, 200}, // Sampled out.
+		{0.1, 200}, // Kept, standing for four requests.
+		{0.9, 500}, // Failures are always kept.
+	} {
+		svc.rand = func() float64 { return tc.roll }
+		_ = svc.LogRequest(context.Background(), &APIRequestLog{StatusCode: tc.status})
+	}
+	if len(logged) != 2 {
+		t.Fatalf("logged %d rows, want 2", len(logged))
+	}
+	if logged[0].SampleWeight != 4 || logged[1].SampleWeight != 1 || logged[1].StatusCode != 500 {
+		t.Errorf("rows = %+v", logged)
+	}
+	if logged[0].CreatedAt.IsZero() {
+		t.Error("CreatedAt not stamped before queueing")
+	}
+}
+
+func TestRequestLogBuffer_PushAndFlush(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+
+	var inserted []APIRequestLog
+	insertErr := errors.New("db down")
+	repo := &mockSyncAPIRepo{
+		insertRequestLogsFn: func(_ context.Context, logs []APIRequestLog) error {
+			if insertErr != nil {
+				return insertErr
+			}
+			inserted = append(inserted, logs...)
+			return nil
+		},
+		logRequestFn: func(context.Context, *APIRequestLog) error {
+			t.Error("buffered service wrote a row directly")
+			return nil
+		},
+	}
+	buf := NewRequestLogBuffer(rdb, repo)
+	svc := NewSyncAPIService(repo)
+	SetRequestLogging(svc, buf, 1)
+	for _, path := range []string{"/a", "/b", "/c"} {
+		_ = svc.LogRequest(ctx, &APIRequestLog{Path: path, StatusCode: 200})
+	}
+
+	// A failed insert puts the rows back on the queue.
+	if _, err := buf.Flush(ctx); err == nil {
+		t.Fatal("expected insert error")
+	}
+	if n, _ := rdb.LLen(ctx, requestLogQueueKey).Result(); n != 3 {
+		t.Fatalf("queue length after failed flush = %d, want 3", n)
+	}
+
+	insertErr = nil
+	n, err := buf.Flush(ctx)
+	if err != nil || n != 3 || len(inserted) != 3 {
+		t.Fatalf("flush = %d, %v; inserted %+v", n, err, inserted)
+	}
+	if inserted[0].SampleWeight != 1 {
+		t.Errorf("weight lost in queue: %+v", inserted[0])
+	}
+	if n, err := buf.Flush(ctx); n != 0 || err != nil {
+		t.Errorf("flush of empty queue = %d, %v", n, err)
+	}
+}
+
+func TestRequestLogRollupJob_Run(t *testing.T) {
+	var befores []time.Time
+	batches := []int64{40, 12, 0}
+	repo := &mockSyncAPIRepo{
+		rollupRequestLogsFn: func(_ context.Context, before time.Time) (int64, error) {
+			befores = append(befores, before)
+			n := batches[0]
+			batches = batches[1:]
+			return n, nil
+		},
+	}
+	job := NewRequestLogRollupJob(repo, 7*24*time.Hour, nil)
+	job.now = func() time.Time { return time.Date(2026, 10, 15, 12, 34, 0, 0, time.UTC) }
+
+	n, err := job.Run(context.Background())
+	if err != nil || n != 52 {
+		t.Fatalf("Run = %d, %v; want 52", n, err)
+	}
+	// Only whole hours past the rollup age are folded.
+	want := time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC)
+	if len(befores) != 3 || !befores[0].Equal(want) {
+		t.Errorf("before = %v, want %v", befores, want)
+	}
+}
@@ -1,8 +1,12 @@
 package syncapi
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
 
+	"github.com/keyxmakerx/chronicle/internal/middleware"
 	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
 	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
 )
@@ -39,7 +43,9 @@ func RegisterCampaignRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.Camp
 	// API key management (campaign owner only).
 	cg.GET("/api-keys", h.KeysPage, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.POST("/api-keys", h.CreateKey, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/api-keys/demo", h.CreateDemoKey, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.PUT("/api-keys/:keyID/toggle", h.ToggleKey, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/api-keys/:keyID/unbind-device", h.UnbindKeyDevice, campaigns.RequireRole(campaigns.RoleOwner))
 	cg.DELETE("/api-keys/:keyID", h.RevokeKey, campaigns.RequireRole(campaigns.RoleOwner))
 
 	// Sync status embed (owner only — used by dashboard sync status block).
@@ -59,6 +65,29 @@ func RegisterCampaignRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.Camp
 	cg.GET("/calendar-sync-beacon", h.GetCalendarSyncBeacon)
 }
 
+// RegisterInboundEmailRoutes adds the email-in webhook and its owner
+// settings card. The webhook has no session or key: the secret address
+// token is the credential (see inbound_email_service.go). It lives under
+// /api/ so CSRF doesn't apply, and carries its own body limit because
+// emails with attachments exceed the global one.
+func RegisterInboundEmailRoutes(e *echo.Echo, h *InboundEmailHandler, campaignSvc campaigns.CampaignService, authSvc auth.AuthService) {
+	hook := e.Group("/api/inbound/email",
+		middleware.RateLimit(30, time.Minute),
+		echomw.BodyLimit("12M"),
+	)
+	hook.POST("", h.Receive)
+	hook.POST("/:token", h.Receive)
+
+	cg := e.Group("/campaigns/:id",
+		auth.RequireAuth(authSvc),
+		campaigns.RequireCampaignAccess(campaignSvc),
+	)
+	cg.GET("/integrations/email", h.SettingsFragment, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/integrations/email", h.Save, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.DELETE("/integrations/email", h.Disable, campaigns.RequireRole(campaigns.RoleOwner))
+	cg.POST("/integrations/email/rotate", h.Rotate, campaigns.RequireRole(campaigns.RoleOwner))
+}
+
 // RegisterAPIRoutes adds the public REST API endpoints under /api/v1/.
 // Routes accept EITHER a session cookie (for in-app browser widgets — same
 // origin as the UI) OR an Authorization: Bearer API key (external clients
@@ -114,11 +143,11 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	)
 
 	// Campaign-scoped routes with campaign match enforcement.
-	cg := v1.Group("/campaigns/:id", RequireCampaignMatch())
+	cg := v1.Group("/campaigns/:id", RequireCampaignMatch(campaignSvc))
 
 	// Read endpoints (require "read" permission).
 	cg.GET("", api.GetCampaign, RequirePermission(PermRead))
-	cg.GET("/members", api.ListMembers, RequirePermission(PermRead))
+	cg.GET("/members", api.ListMembers, RequirePermission(PermRead), DenyDemoKeys())
 	cg.GET("/systems", api.ListSystems, RequirePermission(PermRead))
 	cg.GET("/systems/:systemId/character-fields", api.GetCharacterFields, RequirePermission(PermRead))
 	cg.GET("/systems/:systemId/item-fields", api.GetItemFields, RequirePermission(PermRead))
@@ -133,6 +162,13 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	// Addon discovery (read).
 	cg.GET("/addons", api.ListAddons, RequirePermission(PermRead))
 
+	// Companion-app endpoints: compact list/detail shapes, same auth and
+	// egress rules as the entity endpoints above. See mobile_api_handler.go.
+	mobile := v1.Group("/mobile/campaigns/:id", RequireCampaignMatch(campaignSvc))
+	mobile.GET("", api.MobileGetCampaign, RequirePermission(PermRead))
+	mobile.GET("/entities", api.MobileListEntities, RequirePermission(PermRead))
+	mobile.GET("/entities/:entityID", api.MobileGetEntity, RequirePermission(PermRead))
+
 	// Tag endpoints (always available, not addon-gated).
 	cg.GET("/tags", tagAPI.ListTags, RequirePermission(PermRead))
 	cg.POST("/tags", tagAPI.CreateTag, RequirePermission(PermWrite))
@@ -153,6 +189,9 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 
 	// Bulk entity operations.
 	cg.POST("/entities/bulk-update", api.BulkUpdateEntityType, RequirePermission(PermWrite))
+	// Upsert keyed by external_id; records sync mappings, so "sync" like /sync.
+	// The colon is escaped: the route is the literal "entities:batchUpsert".
+	cg.POST("/entities\\:batchUpsert", api.BatchUpsertEntities, RequirePermission(PermSync))
 
 	// Calendar read endpoints (require "read" permission + calendar addon).
 	calGroup := cg.Group("", RequireAddonAPI(addonChecker, "calendar"))
@@ -169,6 +208,8 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	calGroup.GET("/calendar/event-categories", calAPI.GetEventCategories, RequirePermission(PermRead))
 	calGroup.GET("/calendar/structure", calAPI.GetStructure, RequirePermission(PermRead))
 	calGroup.GET("/calendar/weather", calAPI.GetWeather, RequirePermission(PermRead))
+	calGroup.GET("/calendar/regions", calAPI.GetRegions, RequirePermission(PermRead))
+	calGroup.GET("/calendar/locations/:entityID/climate", calAPI.GetLocationClimate, RequirePermission(PermRead))
 	calGroup.GET("/calendar/cycles", calAPI.GetCycles, RequirePermission(PermRead))
 	calGroup.GET("/calendar/festivals", calAPI.GetFestivals, RequirePermission(PermRead))
 	calGroup.GET("/calendar/events", calAPI.ListEvents, RequirePermission(PermRead))
@@ -198,6 +239,7 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	calGroup.PUT("/calendar/seasons", calAPI.UpdateSeasons, RequirePermission(PermWrite))
 	calGroup.PUT("/calendar/event-categories", calAPI.UpdateEventCategories, RequirePermission(PermWrite))
 	calGroup.PUT("/calendar/weather", calAPI.SetWeather, RequirePermission(PermWrite))
+	calGroup.PUT("/calendar/regions/:zoneID/weather", calAPI.SetRegionWeather, RequirePermission(PermWrite))
 	calGroup.PUT("/calendar/cycles", calAPI.UpdateCycles, RequirePermission(PermWrite))
 	calGroup.PUT("/calendar/festivals", calAPI.UpdateFestivals, RequirePermission(PermWrite))
 	calGroup.POST("/calendar/advance", calAPI.AdvanceDate, RequirePermission(PermWrite))
@@ -218,7 +260,7 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	// DeleteMedia stays on the JSON group: it accepts a body-less
 	// DELETE which the Content-Type middleware passes through anyway.
 	v1Multipart.POST("/campaigns/:id/media", mediaAPI.UploadMedia,
-		RequireCampaignMatch(),
+		RequireCampaignMatch(campaignSvc),
 		RequirePermission(PermWrite),
 	)
 	cg.DELETE("/media/:mediaID", mediaAPI.DeleteMedia, RequirePermission(PermWrite))
@@ -253,8 +295,8 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	mapGroup.DELETE("/maps/:mapID/fog", mapAPI.ResetFog, RequirePermission(PermWrite))
 
 	// Note read endpoints (require "read" permission).
-	cg.GET("/notes", noteAPI.ListNotes, RequirePermission(PermRead))
-	cg.GET("/notes/:noteID", noteAPI.GetNote, RequirePermission(PermRead))
+	cg.GET("/notes", noteAPI.ListNotes, RequirePermission(PermRead), DenyDemoKeys())
+	cg.GET("/notes/:noteID", noteAPI.GetNote, RequirePermission(PermRead), DenyDemoKeys())
 
 	// Note write endpoints (require "write" permission).
 	cg.POST("/notes", noteAPI.CreateNote, RequirePermission(PermWrite))
@@ -271,4 +313,15 @@ func RegisterAPIRoutes(e *echo.Echo, api *APIHandler, calAPI *CalendarAPIHandler
 	cg.DELETE("/sync/mappings/:mappingID", syncH.DeleteMapping, RequirePermission(PermSync))
 	cg.GET("/sync/lookup", syncH.LookupMapping, RequirePermission(PermSync))
 	cg.GET("/sync/pull", syncH.PullMappings, RequirePermission(PermSync))
+
+	// Inbound automation actions for n8n/Zapier (require "automation"
+	// permission). See automation_api_handler.go.
+	cg.POST("/actions/create-entity", api.ActionCreateEntity, RequirePermission(PermAutomation))
+	cg.POST("/actions/post-announcement", api.ActionPostAnnouncement, RequirePermission(PermAutomation))
+	calGroup.POST("/actions/add-event", calAPI.ActionAddEvent, RequirePermission(PermAutomation))
+
+	// Obsidian vault sync: pages as Markdown notes with front matter (require
+	// "sync" permission). See obsidian_api_handler.go.
+	cg.GET("/obsidian/changes", api.ObsidianChanges, RequirePermission(PermSync))
+	cg.POST("/obsidian/push", api.ObsidianPush, RequirePermission(PermSync))
 }
@@ -0,0 +1,209 @@
+package syncapi
+
+// security_alerts.go — tells instance admins about high-severity security
+// events as they happen instead of waiting for someone to open the events
+// table. LogSecurityEvent hands every event to SecurityAlerter.Alert, which
+// decides whether it is worth an alert (a burst of failed keys from one IP,
+// a blocklisted IP still calling, a key used from a foreign device, a key
+// whose owner lost the campaign) and sends it to every admin's notification
+// center and inbox. Each alert kind and subject (IP or key) alerts at most
+// once per dedupe window, tracked in Redis so replicas share it.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/keyxmakerx/chronicle/internal/templates/emails"
+)
+
+// NotifSecurityAlert is the notification type for API security alerts.
+const NotifSecurityAlert = "api_security_alert"
+
+const (
+	// bruteForceThreshold failed key attempts from one IP within
+	// bruteForceWindow count as a brute force attempt.
+	bruteForceThreshold = 10
+	bruteForceWindow    = 10 * time.Minute
+
+	// securityAlertWindow is how long an alert of one kind and subject
+	// stays quiet after it is sent.
+	securityAlertWindow = time.Hour
+
+	// securityAlertLongWindow is the dedupe window for conditions that
+	// persist until an admin acts, such as a key whose owner left.
+	securityAlertLongWindow = 24 * time.Hour
+
+	// securityAlertLink is where an alert sends the admin.
+	securityAlertLink = "/admin/api"
+)
+
+// SecurityAlertAdmin is an instance admin to alert.
+type SecurityAlertAdmin struct {
+	UserID string
+	Email  string
+}
+
+// SecurityAlertAdminLister lists enabled instance admins. Implemented in
+// internal/app over the auth user repository.
+type SecurityAlertAdminLister interface {
+	ListAdmins(ctx context.Context) ([]SecurityAlertAdmin, error)
+}
+
+// SecurityAlertNotifier writes notification center entries. Subset of
+// sessions.SessionService.
+type SecurityAlertNotifier interface {
+	NotifyUser(ctx context.Context, userID, campaignID, kind, message, link string) error
+}
+
+// SecurityAlertMailer sends alert emails. Subset of smtp.MailService.
+type SecurityAlertMailer interface {
+	SendHTMLMail(ctx context.Context, to []string, subject, plainBody, htmlBody string) error
+	IsConfigured(ctx context.Context) bool
+}
+
+// securityAlert is one high-severity condition. kind and subject key the
+// dedupe window.
+type securityAlert struct {
+	kind    string
+	subject string
+	message string
+	window  time.Duration
+}
+
+// SecurityAlerter routes high-severity security events to admins.
+type SecurityAlerter struct {
+	admins   SecurityAlertAdminLister
+	notifier SecurityAlertNotifier
+	mailer   SecurityAlertMailer
+	rdb      *redis.Client
+	baseURL  string
+}
+
+// NewSecurityAlerter creates an alerter. mailer may be nil to alert only
+// through the notification center.
+func NewSecurityAlerter(admins SecurityAlertAdminLister, notifier SecurityAlertNotifier, mailer SecurityAlertMailer, rdb *redis.Client, baseURL string) *SecurityAlerter {
+	return &SecurityAlerter{admins: admins, notifier: notifier, mailer: mailer, rdb: rdb, baseURL: baseURL}
+}
+
+// SetSecurityAlerter makes LogSecurityEvent pass events to a.
+func SetSecurityAlerter(svc SyncAPIService, a *SecurityAlerter) {
+	if s, ok := svc.(*syncAPIService); ok {
+		s.alerter = a
+	}
+}
+
+// Alert notifies every admin of event when it is high severity and the
+// same alert hasn't gone out within its dedupe window. Failures are logged;
+// alerting never affects the request that raised the event.
+func (a *SecurityAlerter) Alert(ctx context.Context, event *SecurityEvent) {
+	alert := a.classify(ctx, event)
+	if alert == nil {
+		return
+	}
+	first, err := a.rdb.SetNX(ctx, "syncapi:security-alert:"+alert.kind+":"+alert.subject, 1, alert.window).Result()
+	if err != nil {
+		slog.Warn("security alert dedupe unavailable", slog.String("kind", alert.kind), slog.Any("error", err))
+		return
+	}
+	if !first {
+		return
+	}
+
+	admins, err := a.admins.ListAdmins(ctx)
+	if err != nil {
+		slog.Error("listing admins for security alert failed", slog.Any("error", err))
+		return
+	}
+	slog.Warn("api security alert", slog.String("kind", alert.kind), slog.String("subject", alert.subject),
+		slog.Int("admins", len(admins)))
+
+	mail := a.mailer != nil && a.mailer.IsConfigured(ctx)
+	for _, admin := range admins {
+		if err := a.notifier.NotifyUser(ctx, admin.UserID, "", NotifSecurityAlert, alert.message, securityAlertLink); err != nil {
+			slog.Warn("security alert notification failed", slog.String("user_id", admin.UserID), slog.Any("error", err))
+		}
+		if !mail || admin.Email == "" {
+			continue
+		}
+		msg := emails.Notification("Chronicle security alert", "API security alert", alert.message,
+			"Open API dashboard", a.baseURL+securityAlertLink)
+		msg.Outro = []string{"You're getting this because you are an admin of this Chronicle instance. Repeats of this alert are held back for a while."}
+		plainBody, htmlBody, err := msg.Render(ctx)
+		if err == nil {
+			err = a.mailer.SendHTMLMail(ctx, []string{admin.Email}, msg.Subject, plainBody, htmlBody)
+		}
+		if err != nil {
+			slog.Warn("security alert email failed", slog.String("user_id", admin.UserID), slog.Any("error", err))
+		}
+	}
+}
+
+// classify returns the alert event raises, or nil when it isn't high
+// severity. Failed key attempts only alert once an IP crosses
+// bruteForceThreshold; the count lives in Redis beside the dedupe keys.
+func (a *SecurityAlerter) classify(ctx context.Context, event *SecurityEvent) *securityAlert {
+	switch event.EventType {
+	case EventAuthFailure:
+		key := "syncapi:auth-failures:" + event.IPAddress
+		count, err := a.rdb.Incr(ctx, key).Result()
+		if err == nil && count == 1 {
+			err = a.rdb.Expire(ctx, key, bruteForceWindow).Err()
+		}
+		if err != nil {
+			slog.Warn("counting failed api key attempts failed", slog.Any("error", err))
+			return nil
+		}
+		if count < bruteForceThreshold {
+			return nil
+		}
+		return &securityAlert{
+			kind:    "brute_force",
+			subject: event.IPAddress,
+			message: fmt.Sprintf("%d or more failed API key attempts from %s in %d minutes. Consider blocking the IP.",
+				bruteForceThreshold, event.IPAddress, int(bruteForceWindow.Minutes())),
+			window: securityAlertWindow,
+		}
+
+	case EventIPBlocked:
+		// Allowlist misses carry the key and are a configuration matter;
+		// only blocklisted IPs that keep calling alert.
+		if event.APIKeyID != nil {
+			return nil
+		}
+		return &securityAlert{
+			kind:    "blocked_ip",
+			subject: event.IPAddress,
+			message: fmt.Sprintf("Blocked IP %s is still calling the API.", event.IPAddress),
+			window:  securityAlertWindow,
+		}
+
+	case EventSuspicious:
+		if event.APIKeyID == nil {
+			return nil
+		}
+		return &securityAlert{
+			kind:    "suspicious",
+			subject: fmt.Sprint(*event.APIKeyID),
+			message: fmt.Sprintf("API key #%d was used from an unrecognized device at %s. Revoke it if that wasn't its owner.",
+				*event.APIKeyID, event.IPAddress),
+			window: securityAlertWindow,
+		}
+
+	case EventKeyOwnerDegraded:
+		if event.APIKeyID == nil {
+			return nil
+		}
+		return &securityAlert{
+			kind:    "key_owner_degraded",
+			subject: fmt.Sprint(*event.APIKeyID),
+			message: fmt.Sprintf("API key #%d still syncs, but the user who created it no longer owns its campaign. Rotate the key.",
+				*event.APIKeyID),
+			window: securityAlertLongWindow,
+		}
+	}
+	return nil
+}
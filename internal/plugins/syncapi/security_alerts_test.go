@@ -0,0 +1,96 @@
+package syncapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type stubAlertAdmins struct{}
+
+func (stubAlertAdmins) ListAdmins(context.Context) ([]SecurityAlertAdmin, error) {
+	return []SecurityAlertAdmin{{UserID: "admin-1", Email: "a@example.com"}, {UserID: "admin-2"}}, nil
+}
+
+// stubAlertSink records notifications and mails.
+type stubAlertSink struct {
+	notified []string
+	mailed   [][]string
+}
+
+func (s *stubAlertSink) NotifyUser(_ context.Context, userID, _, kind, _, _ string) error {
+	s.notified = append(s.notified, userID+":"+kind)
+	return nil
+}
+
+func (s *stubAlertSink) SendHTMLMail(_ context.Context, to []string, _, _, _ string) error {
+	s.mailed = append(s.mailed, to)
+	return nil
+}
+
+func (s *stubAlertSink) IsConfigured(context.Context) bool { return true }
+
+func newTestAlerter(t *testing.T) (*SecurityAlerter, *stubAlertSink) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	sink := &stubAlertSink{}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewSecurityAlerter(stubAlertAdmins{}, sink, sink, rdb, "https://chronicle.test"), sink
+}
+
+func TestSecurityAlerter_BruteForceThreshold(t *testing.T) {
+	a, sink := newTestAlerter(t)
+	ctx := context.Background()
+	event := &SecurityEvent{EventType: EventAuthFailure, IPAddress: "203.0.113.9"}
+
+	for i := 1; i < bruteForceThreshold; i++ {
+		a.Alert(ctx, event)
+	}
+	if len(sink.notified) != 0 {
+		t.Fatalf("alerted below threshold: %v", sink.notified)
+	}
+	a.Alert(ctx, event)
+	if len(sink.notified) != 2 || sink.notified[0] != "admin-1:"+NotifSecurityAlert {
+		t.Fatalf("notified = %v, want both admins", sink.notified)
+	}
+	// Only the admin with an email address is mailed.
+	if len(sink.mailed) != 1 || sink.mailed[0][0] != "a@example.com" {
+		t.Errorf("mailed = %v", sink.mailed)
+	}
+
+	// Further failures inside the dedupe window stay quiet.
+	a.Alert(ctx, event)
+	if len(sink.notified) != 2 {
+		t.Errorf("repeat alert sent: %v", sink.notified)
+	}
+}
+
+func TestSecurityAlerter_Classification(t *testing.T) {
+	keyID := 7
+	for _, tc := range []struct {
+		name  string
+		event SecurityEvent
+		alert bool
+	}{
+		{"blocklisted ip", SecurityEvent{EventType: EventIPBlocked, IPAddress: "198.51.100.1"}, true},
+		{"allowlist miss", SecurityEvent{EventType: EventIPBlocked, APIKeyID: &keyID, IPAddress: "198.51.100.1"}, false},
+		{"device mismatch", SecurityEvent{EventType: EventSuspicious, APIKeyID: &keyID}, true},
+		{"owner degraded", SecurityEvent{EventType: EventKeyOwnerDegraded, APIKeyID: &keyID}, true},
+		{"rate limit", SecurityEvent{EventType: EventRateLimit, APIKeyID: &keyID}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, sink := newTestAlerter(t)
+			a.Alert(context.Background(), &tc.event)
+			a.Alert(context.Background(), &tc.event)
+			want := 0
+			if tc.alert {
+				want = 2 // Both admins, once.
+			}
+			if len(sink.notified) != want {
+				t.Errorf("notified = %v, want %d", sink.notified, want)
+			}
+		})
+	}
+}
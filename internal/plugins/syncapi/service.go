@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,10 +20,16 @@ const keyBytes = 32
 // keyPrefixLen is the length of the prefix stored for key identification.
 const keyPrefixLen = 8
 
+// maxDeviceFingerprintLen matches the api_keys.device_fingerprint column.
+const maxDeviceFingerprintLen = 255
+
 // SyncAPIService handles business logic for the sync API.
 type SyncAPIService interface {
 	// Key management.
 	CreateKey(ctx context.Context, userID string, input CreateAPIKeyInput) (*CreateAPIKeyResult, error)
+	// CreateDemoKey mints a short-lived read-only demo key; lifetime must be
+	// one of DemoKeyLifetimes.
+	CreateDemoKey(ctx context.Context, userID, campaignID, name string, lifetime time.Duration) (*CreateAPIKeyResult, error)
 	GetKey(ctx context.Context, id int) (*APIKey, error)
 	ListKeysByUser(ctx context.Context, userID string) ([]APIKey, error)
 	ListKeysByCampaign(ctx context.Context, campaignID string) ([]APIKey, error)
@@ -62,7 +69,7 @@ type SyncAPIService interface {
 	GetCampaignStats(ctx context.Context, campaignID string, since time.Time) (*APIStats, error)
 
 	// WebSocket authentication.
-	AuthenticateKeyForWS(ctx context.Context, rawKey string) (campaignID, userID string, role int, err error)
+	AuthenticateKeyForWS(ctx context.Context, rawKey, fingerprint, ip, userAgent string) (campaignID, userID string, role int, err error)
 
 	// Calendar date beacon (C-SYNC-DATE-BEACON, extended by
 	// C-SYNC-APPLIED-BEACON).
@@ -74,6 +81,16 @@ type SyncAPIService interface {
 // syncAPIService implements SyncAPIService.
 type syncAPIService struct {
 	repo SyncAPIRepository
+
+	// Request logging (see SetRequestLogging). A nil logBuffer inserts
+	// each row directly; rand replaces the sampling source in tests.
+	logBuffer  *RequestLogBuffer
+	sampleRate float64
+	rand       func() float64
+
+	// alerter routes high-severity security events to admins (see
+	// SetSecurityAlerter); nil only records them.
+	alerter *SecurityAlerter
 }
 
 // NewSyncAPIService creates a new sync API service.
@@ -85,9 +102,10 @@ func NewSyncAPIService(repo SyncAPIRepository) SyncAPIService {
 
 // validPermissions enumerates allowed API key permissions.
 var validPermissions = map[APIKeyPermission]bool{
-	PermRead:  true,
-	PermWrite: true,
-	PermSync:  true,
+	PermRead:       true,
+	PermWrite:      true,
+	PermSync:       true,
+	PermAutomation: true,
 }
 
 // CreateKey generates a new API key with bcrypt-hashed storage.
@@ -145,6 +163,7 @@ func (s *syncAPIService) CreateKey(ctx context.Context, userID string, input Cre
 		RateLimit:   input.RateLimit,
 		IsActive:    true,
 		ExpiresAt:   input.ExpiresAt,
+		IsDemo:      input.IsDemo,
 	}
 
 	if err := s.repo.CreateKey(ctx, key); err != nil {
@@ -160,6 +179,42 @@ func (s *syncAPIService) CreateKey(ctx context.Context, userID string, input Cre
 	return &CreateAPIKeyResult{Key: key, RawKey: rawKey}, nil
 }
 
+// CreateDemoKey mints a demo key: read permission only, player visibility
+// (see APIKey.EffectiveRole), DemoKeyRateLimit and an expiry lifetime from
+// now. A campaign holds at most MaxDemoKeysPerCampaign unexpired demo keys
+// so a shared demo can't be used to mint unlimited credentials.
+func (s *syncAPIService) CreateDemoKey(ctx context.Context, userID, campaignID, name string, lifetime time.Duration) (*CreateAPIKeyResult, error) {
+	if !slices.Contains(DemoKeyLifetimes, lifetime) {
+		return nil, apperror.NewBadRequest("choose a demo key lifetime")
+	}
+	keys, err := s.repo.ListKeysByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, apperror.NewInternal(fmt.Errorf("listing keys: %w", err))
+	}
+	live := 0
+	for _, k := range keys {
+		if k.IsDemo && !k.IsExpired() {
+			live++
+		}
+	}
+	if live >= MaxDemoKeysPerCampaign {
+		return nil, apperror.NewBadRequest(fmt.Sprintf("a campaign can have at most %d unexpired demo keys; revoke one first", MaxDemoKeysPerCampaign))
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = "Demo key"
+	}
+	expiresAt := time.Now().UTC().Add(lifetime)
+	return s.CreateKey(ctx, userID, CreateAPIKeyInput{
+		Name:        name,
+		CampaignID:  campaignID,
+		Permissions: []APIKeyPermission{PermRead},
+		RateLimit:   DemoKeyRateLimit,
+		ExpiresAt:   &expiresAt,
+		IsDemo:      true,
+	})
+}
+
 // GetKey retrieves an API key by ID.
 func (s *syncAPIService) GetKey(ctx context.Context, id int) (*APIKey, error) {
 	return s.repo.FindKeyByID(ctx, id)
@@ -297,19 +352,43 @@ func (s *syncAPIService) BindDevice(ctx context.Context, keyID int, fingerprint
 	if fingerprint == "" {
 		return apperror.NewBadRequest("device fingerprint is required")
 	}
+	if len(fingerprint) > maxDeviceFingerprintLen {
+		return apperror.NewBadRequest("device fingerprint is too long")
+	}
 	now := time.Now().UTC()
 	return s.repo.BindDevice(ctx, keyID, fingerprint, now)
 }
 
 // UnbindDevice removes device binding from an API key, allowing re-registration.
+// The next request that sends a fingerprint binds the key again.
 func (s *syncAPIService) UnbindDevice(ctx context.Context, keyID int) error {
-	return s.repo.UnbindDevice(ctx, keyID)
+	if err := s.repo.UnbindDevice(ctx, keyID); err != nil {
+		return err
+	}
+	slog.Info("api key device unbound", slog.Int("key_id", keyID))
+	return nil
 }
 
 // --- Request Logging ---
 
-// LogRequest records an API request.
+// LogRequest records an API request, subject to sampling. Rows go to the
+// Redis buffer when one is set, falling back to a direct insert.
 func (s *syncAPIService) LogRequest(ctx context.Context, log *APIRequestLog) error {
+	keep, weight := s.sampleRequest(log)
+	if !keep {
+		return nil
+	}
+	log.SampleWeight = weight
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now().UTC()
+	}
+	if s.logBuffer != nil {
+		err := s.logBuffer.Push(ctx, log)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("request log buffer unavailable, writing directly", slog.Any("error", err))
+	}
 	if err := s.repo.LogRequest(ctx, log); err != nil {
 		// Log errors are non-critical — don't fail the request.
 		slog.Warn("failed to log api request", slog.Any("error", err))
@@ -364,6 +443,9 @@ func (s *syncAPIService) LogSecurityEvent(ctx context.Context, event *SecurityEv
 	if err := s.repo.LogSecurityEvent(ctx, event); err != nil {
 		slog.Warn("failed to log security event", slog.Any("error", err))
 	}
+	if s.alerter != nil {
+		go s.alerter.Alert(context.Background(), event)
+	}
 	return nil
 }
 
@@ -455,15 +537,32 @@ func (s *syncAPIService) GetCampaignStats(ctx context.Context, campaignID string
 // --- WebSocket Authentication ---
 
 // AuthenticateKeyForWS validates a raw API key and returns the campaign ID,
-// owner user ID, and a default owner role (3). This provides the WebSocket
-// authenticator with the identity needed to register a client.
-func (s *syncAPIService) AuthenticateKeyForWS(ctx context.Context, rawKey string) (campaignID, userID string, role int, err error) {
+// owner user ID, and the key's role (3, or 1 for demo keys). This provides the WebSocket
+// authenticator with the identity needed to register a client. The device
+// fingerprint is enforced as RequireAPIKey does, and a handshake from the
+// wrong device logs the same security event; ip and userAgent are only
+// recorded on that event.
+func (s *syncAPIService) AuthenticateKeyForWS(ctx context.Context, rawKey, fingerprint, ip, userAgent string) (campaignID, userID string, role int, err error) {
 	key, err := s.AuthenticateKey(ctx, rawKey)
 	if err != nil {
 		return "", "", 0, err
 	}
-	// API keys are always created by the campaign owner, so default to owner role.
-	return key.CampaignID, key.UserID, 3, nil
+	if reason, err := enforceDevice(ctx, s, key, fingerprint); err != nil {
+		if reason != "" {
+			_ = s.LogSecurityEvent(ctx, &SecurityEvent{
+				EventType:  EventSuspicious,
+				APIKeyID:   &key.ID,
+				CampaignID: &key.CampaignID,
+				IPAddress:  ip,
+				UserAgent:  strPtr(userAgent),
+				Details:    map[string]any{"reason": reason, "transport": "websocket"},
+			})
+		}
+		return "", "", 0, err
+	}
+	// API keys are always created by the campaign owner, so default to owner
+	// role; demo keys read as a player.
+	return key.CampaignID, key.UserID, key.EffectiveRole(), nil
 }
 
 // --- Calendar Date Beacon (C-SYNC-DATE-BEACON) ---
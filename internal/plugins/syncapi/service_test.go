@@ -25,6 +25,8 @@ type mockSyncAPIRepo struct {
 	updateKeyLastUsedFn   func(ctx context.Context, id int, ip string) error
 	deleteKeyFn           func(ctx context.Context, id int) error
 	logRequestFn          func(ctx context.Context, log *APIRequestLog) error
+	insertRequestLogsFn   func(ctx context.Context, logs []APIRequestLog) error
+	rollupRequestLogsFn   func(ctx context.Context, before time.Time) (int64, error)
 	listRequestLogsFn     func(ctx context.Context, filter RequestLogFilter) ([]APIRequestLog, int, error)
 	getReqTimeSeriesFn    func(ctx context.Context, since time.Time, interval string) ([]TimeSeriesPoint, error)
 	getTopIPsFn           func(ctx context.Context, since time.Time, limit int) ([]TopEntry, error)
@@ -45,6 +47,8 @@ type mockSyncAPIRepo struct {
 	upsertBeaconCalls     []CalendarDateBeacon
 	confirmBeaconFn       func(ctx context.Context, campaignID string, year, month, day int, appliedAt time.Time) error
 	confirmBeaconCalls    []confirmBeaconCall
+	bindDeviceFn          func(ctx context.Context, keyID int, fingerprint string) error
+	unbindDeviceFn        func(ctx context.Context, keyID int) error
 }
 
 // confirmBeaconCall captures one ConfirmCalendarDateBeacon invocation.
@@ -125,6 +129,20 @@ func (m *mockSyncAPIRepo) LogRequest(ctx context.Context, log *APIRequestLog) er
 	return nil
 }
 
+func (m *mockSyncAPIRepo) InsertRequestLogs(ctx context.Context, logs []APIRequestLog) error {
+	if m.insertRequestLogsFn != nil {
+		return m.insertRequestLogsFn(ctx, logs)
+	}
+	return nil
+}
+
+func (m *mockSyncAPIRepo) RollupRequestLogs(ctx context.Context, before time.Time) (int64, error) {
+	if m.rollupRequestLogsFn != nil {
+		return m.rollupRequestLogsFn(ctx, before)
+	}
+	return 0, nil
+}
+
 func (m *mockSyncAPIRepo) ListRequestLogs(ctx context.Context, filter RequestLogFilter) ([]APIRequestLog, int, error) {
 	if m.listRequestLogsFn != nil {
 		return m.listRequestLogsFn(ctx, filter)
@@ -132,6 +150,10 @@ func (m *mockSyncAPIRepo) ListRequestLogs(ctx context.Context, filter RequestLog
 	return nil, 0, nil
 }
 
+func (m *mockSyncAPIRepo) PurgeRequestLogsBefore(ctx context.Context, campaignID string, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockSyncAPIRepo) GetRequestTimeSeries(ctx context.Context, since time.Time, interval string) ([]TimeSeriesPoint, error) {
 	if m.getReqTimeSeriesFn != nil {
 		return m.getReqTimeSeriesFn(ctx, since, interval)
@@ -232,10 +254,16 @@ func (m *mockSyncAPIRepo) GetCampaignStats(ctx context.Context, campaignID strin
 }
 
 func (m *mockSyncAPIRepo) BindDevice(ctx context.Context, keyID int, fingerprint string, boundAt time.Time) error {
+	if m.bindDeviceFn != nil {
+		return m.bindDeviceFn(ctx, keyID, fingerprint)
+	}
 	return nil
 }
 
 func (m *mockSyncAPIRepo) UnbindDevice(ctx context.Context, keyID int) error {
+	if m.unbindDeviceFn != nil {
+		return m.unbindDeviceFn(ctx, keyID)
+	}
 	return nil
 }
 
@@ -446,6 +474,69 @@ func TestCreateKey_NameTrimming(t *testing.T) {
 	}
 }
 
+// --- CreateDemoKey Tests ---
+
+func TestCreateDemoKey_ForcesReadOnly(t *testing.T) {
+	var created *APIKey
+	repo := &mockSyncAPIRepo{
+		createKeyFn: func(_ context.Context, key *APIKey) error {
+			created = key
+			return nil
+		},
+	}
+	svc := NewSyncAPIService(repo)
+
+	before := time.Now().UTC()
+	result, err := svc.CreateDemoKey(context.Background(), "user-1", "camp-1", " ", 4*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RawKey == "" || created == nil {
+		t.Fatal("expected a key to be created")
+	}
+	if !created.IsDemo || created.Name != "Demo key" || created.RateLimit != DemoKeyRateLimit {
+		t.Errorf("unexpected demo key: %+v", created)
+	}
+	if len(created.Permissions) != 1 || created.Permissions[0] != PermRead {
+		t.Errorf("expected read-only permissions, got %v", created.Permissions)
+	}
+	if created.ExpiresAt == nil || created.ExpiresAt.Before(before.Add(4*time.Hour)) {
+		t.Errorf("expected expiry four hours out, got %v", created.ExpiresAt)
+	}
+	if created.EffectiveRole() != 1 {
+		t.Errorf("expected player role, got %d", created.EffectiveRole())
+	}
+}
+
+func TestCreateDemoKey_InvalidLifetime(t *testing.T) {
+	svc := NewSyncAPIService(&mockSyncAPIRepo{})
+	_, err := svc.CreateDemoKey(context.Background(), "user-1", "camp-1", "Demo", 30*24*time.Hour)
+	assertAppError(t, err, 400)
+}
+
+func TestCreateDemoKey_CampaignCap(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	keys := []APIKey{{IsDemo: true, ExpiresAt: &past}, {IsDemo: false}}
+	for range MaxDemoKeysPerCampaign {
+		keys = append(keys, APIKey{IsDemo: true, ExpiresAt: &future})
+	}
+	repo := &mockSyncAPIRepo{
+		listKeysByCampaignFn: func(_ context.Context, _ string) ([]APIKey, error) {
+			return keys, nil
+		},
+	}
+	svc := NewSyncAPIService(repo)
+	_, err := svc.CreateDemoKey(context.Background(), "user-1", "camp-1", "Demo", time.Hour)
+	assertAppError(t, err, 400)
+
+	// Expired demo keys don't count toward the cap.
+	keys = keys[:len(keys)-1]
+	if _, err := svc.CreateDemoKey(context.Background(), "user-1", "camp-1", "Demo", time.Hour); err != nil {
+		t.Fatalf("unexpected error below the cap: %v", err)
+	}
+}
+
 // --- AuthenticateKey Tests ---
 
 func TestAuthenticateKey_Success(t *testing.T) {
@@ -631,7 +722,7 @@ func TestAuthenticateKey_SharedByRESTAndWS(t *testing.T) {
 	}
 
 	// WS path: service.AuthenticateKeyForWS(rawKey) → (campaignID, userID, role, err)
-	wsCampaign, wsUser, wsRole, wsErr := svc.AuthenticateKeyForWS(ctx, rawKey)
+	wsCampaign, wsUser, wsRole, wsErr := svc.AuthenticateKeyForWS(ctx, rawKey, "", "", "")
 	if wsErr != nil {
 		t.Fatalf("WS AuthenticateKeyForWS returned error: %v", wsErr)
 	}
@@ -24,9 +24,12 @@ var validChronicleTypes = map[string]bool{
 	"layer":          true,
 }
 
-// validExternalSystems enumerates allowed external systems.
+// validExternalSystems enumerates allowed external systems: live VTT sync
+// plus the sources of repeatable imports (Kanka exports, CSV sheets).
 var validExternalSystems = map[string]bool{
 	"foundry": true,
+	"kanka":   true,
+	"csv":     true,
 }
 
 // validSyncDirections enumerates allowed sync directions.
@@ -99,6 +102,10 @@ func (s *syncMappingService) CreateMapping(ctx context.Context, campaignID strin
 	if existing != nil {
 		return nil, apperror.NewConflict("sync mapping already exists for this object")
 	}
+	// An external record maps to one object, so re-imports update it.
+	if existing, _ := s.repo.FindByExternal(ctx, campaignID, es, input.ExternalID); existing != nil {
+		return nil, apperror.NewConflict("external_id is already mapped to another object")
+	}
 
 	mapping := &SyncMapping{
 		ID:             uuid.New().String(),
@@ -0,0 +1,177 @@
+// Package routereg is the central registry of the routes Chronicle wires
+// into Echo. It sees every registration through Echo's OnAddRouteHandler
+// hook, so plugins keep calling g.GET/g.POST as before and nothing has to
+// be declared twice.
+//
+// Two things come out of it:
+//
+//   - Conflicts. Echo silently keeps the last handler registered for a
+//     method and path, so two plugins claiming the same route used to mean
+//     one of them quietly stopped working (the chronicle#323 lesson).
+//     Registering the same handler twice is harmless (public and member
+//     groups share read routes) and is not a conflict; a different handler
+//     is. App startup fails on Err().
+//
+//   - Reserved segments. Plugins add top-level campaign pages like
+//     /campaigns/:id/maps, and Echo serves those static segments ahead of
+//     the /campaigns/:id/:typeSlug category route. Every static first
+//     segment under /campaigns/:id/ is therefore reserved, and entity type
+//     slugs steer around them (see entities.ReservedPathChecker).
+//
+// The package imports no Chronicle packages so any layer can consult it.
+package routereg
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CampaignPrefix is the path prefix whose first segment is reserved.
+const CampaignPrefix = "/campaigns/:id/"
+
+// Registry records route registrations, conflicts and reserved segments.
+// Safe for concurrent use; reads happen at request time while registration
+// happens once at startup.
+type Registry struct {
+	mu        sync.RWMutex
+	handlers  map[string]string          // "METHOD path" → handler name.
+	segments  map[string]map[string]bool // Reserved segment → owning packages.
+	conflicts []string
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{
+		handlers: make(map[string]string),
+		segments: make(map[string]map[string]bool),
+	}
+}
+
+// Attach hooks the registry into Echo so every later route registration
+// is recorded. An existing OnAddRouteHandler keeps running.
+func (r *Registry) Attach(e *echo.Echo) {
+	prev := e.OnAddRouteHandler
+	e.OnAddRouteHandler = func(host string, route echo.Route, handler echo.HandlerFunc, mw []echo.MiddlewareFunc) {
+		r.Add(route.Method, route.Path, route.Name)
+		if prev != nil {
+			prev(host, route, handler, mw)
+		}
+	}
+}
+
+// Add records one route. handler is Echo's route name, the handler
+// function's full name, which also identifies the owning package.
+func (r *Registry) Add(method, path, handler string) {
+	// Groups register catch-all not-found routes for every middleware
+	// chain; they are Echo's own and never conflict.
+	if method == echo.RouteNotFound {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := method + " " + path
+	if prev, ok := r.handlers[key]; ok && prev != handler {
+		r.conflicts = append(r.conflicts, fmt.Sprintf("%s: %s replaced by %s", key, prev, handler))
+	}
+	r.handlers[key] = handler
+
+	if seg := campaignSegment(path); seg != "" {
+		r.reserve(seg, handlerPackage(handler))
+	}
+}
+
+// Reserve claims a campaign segment with no route behind it yet, e.g. one
+// a plugin serves only when enabled.
+func (r *Registry) Reserve(segment, owner string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reserve(segment, owner)
+}
+
+func (r *Registry) reserve(segment, owner string) {
+	owners := r.segments[segment]
+	if owners == nil {
+		owners = make(map[string]bool)
+		r.segments[segment] = owners
+	}
+	owners[owner] = true
+}
+
+// IsReserved reports whether segment is taken by a route under
+// /campaigns/:id/.
+func (r *Registry) IsReserved(segment string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.segments[segment] != nil
+}
+
+// Segments lists the reserved campaign segments, sorted.
+func (r *Registry) Segments() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.segments))
+	for s := range r.segments {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Owners lists the packages serving routes under a reserved segment,
+// sorted. Several plugins may share one (widgets add routes under
+// /entities/:eid/); only identical routes conflict.
+func (r *Registry) Owners(segment string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.segments[segment]))
+	for o := range r.segments[segment] {
+		out = append(out, o)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Err returns every conflicting registration, or nil.
+func (r *Registry) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.conflicts) == 0 {
+		return nil
+	}
+	return errors.New("route conflicts:\n  " + strings.Join(r.conflicts, "\n  "))
+}
+
+// campaignSegment returns the static first segment under /campaigns/:id/,
+// or "" for other paths and parameter segments.
+func campaignSegment(path string) string {
+	rest, ok := strings.CutPrefix(path, CampaignPrefix)
+	if !ok {
+		return ""
+	}
+	seg, _, _ := strings.Cut(rest, "/")
+	if seg == "" || seg[0] == ':' || seg[0] == '*' {
+		return ""
+	}
+	return seg
+}
+
+// handlerPackage trims a handler name like
+// "github.com/org/repo/internal/plugins/maps.(*Handler).Index-fm" to its
+// package, "maps".
+func handlerPackage(handler string) string {
+	pkg := handler
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if i := strings.Index(pkg, "."); i >= 0 {
+		pkg = pkg[:i]
+	}
+	return pkg
+}
@@ -0,0 +1,75 @@
+package routereg
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func mapsIndex(c echo.Context) error     { return c.NoContent(http.StatusOK) }
+func sessionsIndex(c echo.Context) error { return c.NoContent(http.StatusOK) }
+
+func TestRegistry_ReservesCampaignSegments(t *testing.T) {
+	e := echo.New()
+	r := New()
+	r.Attach(e)
+
+	g := e.Group("/campaigns/:id", func(next echo.HandlerFunc) echo.HandlerFunc { return next })
+	g.GET("/maps", mapsIndex)
+	g.GET("/maps/:mid", mapsIndex)
+	g.GET("/sessions", sessionsIndex)
+	g.GET("/:typeSlug", sessionsIndex)
+	e.GET("/admin/maps", mapsIndex)
+
+	if got := strings.Join(r.Segments(), ","); got != "maps,sessions" {
+		t.Errorf("Segments = %q", got)
+	}
+	if !r.IsReserved("maps") || r.IsReserved("admin") || r.IsReserved(":typeSlug") {
+		t.Error("IsReserved mismatch")
+	}
+	if got := r.Owners("maps"); len(got) != 1 || got[0] != "routereg" {
+		t.Errorf("Owners = %v", got)
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("unexpected conflict: %v", err)
+	}
+
+	r.Reserve("quests", "quests")
+	if !r.IsReserved("quests") {
+		t.Error("explicit reservation missing")
+	}
+}
+
+func TestRegistry_Conflicts(t *testing.T) {
+	e := echo.New()
+	r := New()
+	r.Attach(e)
+
+	// The same handler on two groups is fine.
+	e.GET("/campaigns/:id/maps", mapsIndex)
+	e.GET("/campaigns/:id/maps", mapsIndex)
+	if err := r.Err(); err != nil {
+		t.Fatalf("same handler flagged: %v", err)
+	}
+
+	e.GET("/campaigns/:id/maps", sessionsIndex)
+	err := r.Err()
+	if err == nil || !strings.Contains(err.Error(), "GET /campaigns/:id/maps") {
+		t.Fatalf("Err = %v", err)
+	}
+}
+
+func TestHandlerPackage(t *testing.T) {
+	cases := map[string]string{
+		"github.com/keyxmakerx/chronicle/internal/plugins/maps.(*Handler).Index-fm": "maps",
+		"github.com/keyxmakerx/chronicle/internal/app.(*App).RegisterRoutes.func3":  "app",
+		"main.handler": "main",
+	}
+	for in, want := range cases {
+		if got := handlerPackage(in); got != want {
+			t.Errorf("handlerPackage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
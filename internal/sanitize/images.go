@@ -0,0 +1,93 @@
+// images.go — rewriting image sources in entry content. Used by the image
+// proxy to swap external image URLs for proxied ones on the way out and back
+// again on save, so stored content always keeps the author's original URLs.
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// imgTagRe matches an <img> start tag.
+	imgTagRe = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+
+	// imgSrcRe matches the double-quoted src attribute inside an <img> tag.
+	// Sanitized HTML always quotes attribute values with double quotes.
+	imgSrcRe = regexp.MustCompile(`(?i)(\ssrc=")([^"]*)(")`)
+)
+
+// RewriteImageSrcHTML applies fn to the src of every <img> in sanitized
+// HTML. fn receives the unescaped URL and returns the replacement; returning
+// the input unchanged leaves the tag untouched.
+func RewriteImageSrcHTML(input string, fn func(src string) string) string {
+	if input == "" {
+		return ""
+	}
+	return imgTagRe.ReplaceAllStringFunc(input, func(tag string) string {
+		return imgSrcRe.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := imgSrcRe.FindStringSubmatch(attr)
+			src := html.UnescapeString(m[2])
+			next := fn(src)
+			if next == src {
+				return attr
+			}
+			return m[1] + html.EscapeString(next) + m[3]
+		})
+	})
+}
+
+// RewriteImageSrcJSON applies fn to the src of every image node in
+// ProseMirror JSON. Input that isn't a ProseMirror document, or that has no
+// changed images, is returned unchanged.
+func RewriteImageSrcJSON(jsonStr string, fn func(src string) string) string {
+	if jsonStr == "" {
+		return ""
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonStr), &doc); err != nil {
+		return jsonStr
+	}
+	if !rewriteImageNodes(doc, fn) {
+		return jsonStr
+	}
+	// No HTML escaping: it would turn every & in a query string into \u0026.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return jsonStr
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// rewriteImageNodes walks ProseMirror JSON and rewrites image node sources
+// in place. Reports whether anything changed.
+func rewriteImageNodes(node map[string]interface{}, fn func(src string) string) bool {
+	changed := false
+	if node["type"] == "image" {
+		if attrs, ok := node["attrs"].(map[string]interface{}); ok {
+			if src, ok := attrs["src"].(string); ok {
+				if next := fn(src); next != src {
+					attrs["src"] = next
+					changed = true
+				}
+			}
+		}
+	}
+	content, ok := node["content"].([]interface{})
+	if !ok {
+		return changed
+	}
+	for _, child := range content {
+		if childMap, ok := child.(map[string]interface{}); ok {
+			if rewriteImageNodes(childMap, fn) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
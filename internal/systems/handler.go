@@ -64,7 +64,7 @@ func (h *SystemHandler) OperatorDiagnosticsAPI(c echo.Context) error {
 		// A browser (clicking the admin card) gets the interactive HTML page;
 		// programmatic/AI clients get the markdown catalog.
 		if strings.Contains(c.Request().Header.Get("Accept"), "text/html") {
-			return c.HTML(http.StatusOK, RenderDiagnosticsHTML(cat))
+			return c.HTML(http.StatusOK, RenderDiagnosticsHTML(cat, middleware.CSPNonce(c)))
 		}
 		return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(renderCatalog(cat)))
 	}
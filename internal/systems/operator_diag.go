@@ -248,8 +248,9 @@ func renderCatalog(cat []Diagnostic) string {
 // take one, a result pane, and a Copy button. It fetches the SAME endpoint with
 // ?name=… (which returns markdown) — so the human clicks instead of typing URLs,
 // then copies the small result to the assistant. Browsers reach this via content
-// negotiation on the bare /admin/diagnostics path.
-func RenderDiagnosticsHTML(cat []Diagnostic) string {
+// negotiation on the bare /admin/diagnostics path. nonce is the request's CSP
+// script nonce, so the page also works under CSP_MODE=strict.
+func RenderDiagnosticsHTML(cat []Diagnostic, nonce string) string {
 	var rows strings.Builder
 	for _, d := range cat {
 		arg := ""
@@ -285,7 +286,7 @@ pre{white-space:pre-wrap;word-break:break-word;background:#0b1220;border:1px sol
 ` + rows.String() + `
 <div id="bar"><button id="copy">Copy result</button><span id="status"></span></div>
 <pre id="out">— run a check above —</pre>
-<script>
+<script nonce="` + html.EscapeString(nonce) + `">
 (function(){
   var out=document.getElementById('out'), status=document.getElementById('status');
   function argFor(name){var el=document.querySelector('input.arg[data-for="'+CSS.escape(name)+'"]');return el?el.value.trim():'';}
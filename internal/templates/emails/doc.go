@@ -0,0 +1,6 @@
+// Package emails renders outbound mail. Every message is built as a Message
+// (heading, paragraphs, detail rows, action buttons) and rendered twice: an
+// HTML version through the shared templ layout, and a plain-text fallback
+// for text-only clients. Plugins build messages with the constructors in
+// messages.go and hand both bodies to their mail sender's SendHTMLMail.
+package emails
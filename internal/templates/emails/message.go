@@ -0,0 +1,123 @@
+package emails
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Action tones map to button colors in the HTML layout.
+const (
+	TonePrimary  = "primary"
+	TonePositive = "positive"
+	ToneNegative = "negative"
+)
+
+// Action is a call-to-action button. The plain-text version prints the URL
+// after the label.
+type Action struct {
+	Label string
+	URL   string
+	Tone  string
+}
+
+// Detail is one label/value row (e.g. "Campaign: Curse of Strahd").
+type Detail struct {
+	Label string
+	Value string
+}
+
+// Section is a headed list of lines, e.g. a digest's "Upcoming events".
+type Section struct {
+	Heading string
+	Items   []SectionItem
+}
+
+// SectionItem is one line of a section. Note is secondary text after it;
+// a set URL links the line (printed after it in plain text).
+type SectionItem struct {
+	Text string
+	Note string
+	URL  string
+}
+
+// Message is a rendered-on-demand email. All values are plain text; the
+// HTML layout escapes them, so user-authored names are safe to pass as-is.
+type Message struct {
+	Subject string
+	Heading string
+	// Intro paragraphs come before the details and actions.
+	Intro   []string
+	Details []Detail
+	// Sections come after the details, before the actions.
+	Sections []Section
+	Actions  []Action
+	// Outro paragraphs are small print after the actions (expiry notes,
+	// "ignore this if you didn't ask").
+	Outro []string
+}
+
+// Render returns the plain-text and HTML bodies.
+func (m Message) Render(ctx context.Context) (plain, html string, err error) {
+	var buf bytes.Buffer
+	if err := layout(m).Render(ctx, &buf); err != nil {
+		return "", "", fmt.Errorf("rendering %q email: %w", m.Subject, err)
+	}
+	return m.Text(), buf.String(), nil
+}
+
+// Text renders the plain-text fallback.
+func (m Message) Text() string {
+	var b strings.Builder
+	if m.Heading != "" {
+		b.WriteString(m.Heading)
+		b.WriteString("\n\n")
+	}
+	for _, p := range m.Intro {
+		b.WriteString(p)
+		b.WriteString("\n\n")
+	}
+	if len(m.Details) > 0 {
+		for _, d := range m.Details {
+			fmt.Fprintf(&b, "%s: %s\n", d.Label, d.Value)
+		}
+		b.WriteString("\n")
+	}
+	for _, sec := range m.Sections {
+		b.WriteString(sec.Heading)
+		b.WriteString("\n")
+		for _, it := range sec.Items {
+			b.WriteString("- ")
+			b.WriteString(it.Text)
+			if it.Note != "" {
+				b.WriteString(" (" + it.Note + ")")
+			}
+			if it.URL != "" {
+				b.WriteString("\n  " + it.URL)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	for _, a := range m.Actions {
+		fmt.Fprintf(&b, "%s:\n%s\n\n", a.Label, a.URL)
+	}
+	for _, p := range m.Outro {
+		b.WriteString(p)
+		b.WriteString("\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// buttonColor is the background for an action tone.
+func buttonColor(tone string) string {
+	switch tone {
+	case TonePositive:
+		return "#22c55e"
+	case ToneNegative:
+		return "#ef4444"
+	default:
+		return "#6366f1"
+	}
+}
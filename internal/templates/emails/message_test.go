@@ -0,0 +1,70 @@
+package emails
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMessage_Render(t *testing.T) {
+	cases := []struct {
+		name      string
+		msg       Message
+		wantPlain []string
+		wantHTML  []string
+		notHTML   []string
+	}{
+		{
+			name:      "password reset",
+			msg:       PasswordReset("https://chronicle.test/reset-password?token=abc"),
+			wantPlain: []string{"Set a new password:\nhttps://chronicle.test/reset-password?token=abc", "expires in 1 hour"},
+			wantHTML:  []string{`href="https://chronicle.test/reset-password?token=abc"`, "Reset your password"},
+		},
+		{
+			name:      "user-authored names are escaped",
+			msg:       SessionInvite(`<img src=x onerror=alert(1)>`, "Strahd & Co", "Friday", "https://c.test/rsvp/a", "https://c.test/rsvp/d"),
+			wantPlain: []string{"Session: <img src=x onerror=alert(1)>", "Going:\nhttps://c.test/rsvp/a"},
+			wantHTML:  []string{"&lt;img src=x onerror=alert(1)&gt;", "Strahd &amp; Co", "#22c55e", "#ef4444"},
+			notHTML:   []string{"<img src=x"},
+		},
+		{
+			name: "digest sections",
+			msg: CampaignDigest("Strahd & Co", []Section{
+				{Heading: "Pages", Items: []SectionItem{{Text: "<b>Ireena</b>", Note: "new", URL: "https://c.test/campaigns/c1/entities/e1"}}},
+				{Heading: "Sessions", Items: []SectionItem{{Text: "Session 4", Note: "Fri 12 Jun"}}},
+			}, "https://c.test/campaigns/c1", "https://c.test/campaigns/c1/announcements"),
+			wantPlain: []string{"Pages\n- <b>Ireena</b> (new)\n  https://c.test/campaigns/c1/entities/e1", "- Session 4 (Fri 12 Jun)", "announcements page: https://c.test/campaigns/c1/announcements"},
+			wantHTML:  []string{"&lt;b&gt;Ireena&lt;/b&gt;", `href="https://c.test/campaigns/c1/entities/e1"`, "Strahd &amp; Co"},
+			notHTML:   []string{"<b>Ireena"},
+		},
+		{
+			name:     "unsafe action URLs are neutralized",
+			msg:      Notification("Hi", "Hi", "Body", "Open", "javascript:alert(1)"),
+			notHTML:  []string{"javascript:alert"},
+			wantHTML: []string{"Body"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			plain, html, err := tc.msg.Render(context.Background())
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			for _, want := range tc.wantPlain {
+				if !strings.Contains(plain, want) {
+					t.Errorf("plain body missing %q:\n%s", want, plain)
+				}
+			}
+			for _, want := range tc.wantHTML {
+				if !strings.Contains(html, want) {
+					t.Errorf("HTML body missing %q", want)
+				}
+			}
+			for _, bad := range tc.notHTML {
+				if strings.Contains(html, bad) {
+					t.Errorf("HTML body contains %q", bad)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package emails
+
+import "fmt"
+
+// PasswordReset is the self-service password reset email.
+func PasswordReset(link string) Message {
+	return Message{
+		Subject: "Password Reset — Chronicle",
+		Heading: "Reset your password",
+		Intro:   []string{"A password reset was requested for your Chronicle account."},
+		Actions: []Action{{Label: "Set a new password", URL: link}},
+		Outro:   []string{"This link expires in 1 hour. If you did not request this, you can safely ignore this email."},
+	}
+}
+
+// AdminPasswordReset is sent when a site admin forces a password reset.
+func AdminPasswordReset(link string) Message {
+	return Message{
+		Subject: "Password Reset — Chronicle",
+		Heading: "Reset your password",
+		Intro:   []string{"A site administrator has asked you to reset the password for your Chronicle account."},
+		Actions: []Action{{Label: "Set a new password", URL: link}},
+		Outro:   []string{"This link expires in 1 hour. You have been signed out of all devices."},
+	}
+}
+
+// EmailVerification confirms a changed account email address.
+func EmailVerification(link string) Message {
+	return Message{
+		Subject: "Verify Your New Email — Chronicle",
+		Heading: "Confirm your new email address",
+		Intro:   []string{"An email change was requested for your Chronicle account."},
+		Actions: []Action{{Label: "Confirm email address", URL: link}},
+		Outro:   []string{"This link expires in 24 hours. If you did not request this change, you can safely ignore this email."},
+	}
+}
+
+// TransferOffer offers campaign ownership to another user.
+func TransferOffer(campaignName, link string, expiryHours int) Message {
+	return Message{
+		Subject: "Campaign Ownership Transfer",
+		Heading: "You've been offered a campaign",
+		Intro:   []string{fmt.Sprintf("You have been offered ownership of the campaign \"%s\" on Chronicle. You must be logged in to accept.", campaignName)},
+		Actions: []Action{{Label: "Accept ownership", URL: link}},
+		Outro:   []string{fmt.Sprintf("This link expires in %d hours. If you did not expect this, you can ignore it.", expiryHours)},
+	}
+}
+
+// CampaignInvite invites an email address to join a campaign.
+func CampaignInvite(campaignName, roleName, link string, expiryDays int) Message {
+	return Message{
+		Subject: fmt.Sprintf("You're invited to %s — Chronicle", campaignName),
+		Heading: fmt.Sprintf("You're invited to join \"%s\"", campaignName),
+		Intro:   []string{fmt.Sprintf("You've been invited to join as a %s.", roleName)},
+		Actions: []Action{{Label: "Accept invitation", URL: link}},
+		Outro: []string{
+			fmt.Sprintf("This invitation expires in %d days.", expiryDays),
+			"If you don't have an account, you'll be able to create one when you accept.",
+		},
+	}
+}
+
+// SessionInvite asks a campaign member to RSVP to a game session.
+func SessionInvite(sessionName, campaignName, date, acceptURL, declineURL string) Message {
+	return Message{
+		Subject: fmt.Sprintf("Session Invite: %s — %s", sessionName, campaignName),
+		Heading: "You've been invited to a game session!",
+		Details: []Detail{
+			{Label: "Session", Value: sessionName},
+			{Label: "Campaign", Value: campaignName},
+			{Label: "Date", Value: date},
+		},
+		Actions: []Action{
+			{Label: "Going", URL: acceptURL, Tone: TonePositive},
+			{Label: "Can't make it", URL: declineURL, Tone: ToneNegative},
+		},
+		Outro: []string{"These links expire in 7 days."},
+	}
+}
+
+// CampaignDigest is a member's weekly summary of a campaign. Empty sections
+// are the caller's to leave out.
+func CampaignDigest(campaignName string, sections []Section, campaignURL, preferencesURL string) Message {
+	return Message{
+		Subject:  fmt.Sprintf("Your week in %s — Chronicle", campaignName),
+		Heading:  fmt.Sprintf("This week in \"%s\"", campaignName),
+		Intro:    []string{"Here's what changed in your campaign over the past week and what's coming up."},
+		Sections: sections,
+		Actions:  []Action{{Label: "Open the campaign", URL: campaignURL}},
+		Outro:    []string{"You get this digest weekly as a member of the campaign. Turn it off on the campaign's announcements page: " + preferencesURL},
+	}
+}
+
+// Notification is a general-purpose notice with an optional link.
+func Notification(subject, heading, body, linkLabel, link string) Message {
+	m := Message{Subject: subject, Heading: heading, Intro: []string{body}}
+	if link != "" {
+		m.Actions = []Action{{Label: linkLabel, URL: link}}
+	}
+	return m
+}
+
+// TestEmail is the admin "send test email" message.
+func TestEmail(sentAt string) Message {
+	return Message{
+		Subject: "Chronicle SMTP Test",
+		Heading: "Your SMTP settings work",
+		Intro: []string{
+			"This is a test email from Chronicle.",
+			"If you are reading this, your SMTP configuration is working correctly.",
+		},
+		Details: []Detail{{Label: "Sent at", Value: sentAt}},
+	}
+}
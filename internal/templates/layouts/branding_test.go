@@ -0,0 +1,51 @@
+package layouts
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestInstanceName(t *testing.T) {
+	if got := InstanceName(context.Background()); got != "Chronicle" {
+		t.Errorf("default = %q", got)
+	}
+	ctx := SetInstanceBranding(context.Background(), &InstanceBrandingData{Name: "Tavern Tales"})
+	if got := InstanceName(ctx); got != "Tavern Tales" {
+		t.Errorf("configured = %q", got)
+	}
+}
+
+// TestInstanceFooterLinks pins that external links open in a new tab and
+// that a stored link which no longer passes the URL allowlist renders
+// inert.
+func TestInstanceFooterLinks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := InstanceFooterLinks("").Render(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("no links rendered %q", buf.String())
+	}
+
+	ctx := SetInstanceBranding(context.Background(), &InstanceBrandingData{FooterLinks: []FooterLinkData{
+		{Label: "Discord", URL: "https://discord.gg/abc"},
+		{Label: "Rules", URL: "/rules"},
+		{Label: "Bad", URL: "javascript:alert(1)"},
+	}})
+	buf.Reset()
+	if err := InstanceFooterLinks("").Render(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		`href="https://discord.gg/abc" class="hover:underline" target="_blank"`,
+		`href="/rules" class="hover:underline">Rules`,
+		`href="#" class="hover:underline">Bad`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %q in %s", want, html)
+		}
+	}
+}
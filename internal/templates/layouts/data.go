@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ctxKey is a private type for context keys to prevent collisions.
@@ -48,6 +49,10 @@ const (
 	keyDegradedPluginCount   ctxKey = "layout_degraded_plugin_count"
 	keyFontFamily            ctxKey = "layout_font_family"
 	keyUserCampaigns         ctxKey = "layout_user_campaigns"
+	keyAnnouncements         ctxKey = "layout_announcements"
+	keyCampaignPurgeAfter    ctxKey = "layout_campaign_purge_after"
+	keyContentLicense        ctxKey = "layout_content_license"
+	keyInstanceBranding      ctxKey = "layout_instance_branding"
 )
 
 // NavCampaign holds the minimum info needed to render a campaign link
@@ -405,6 +410,19 @@ func IsOwner(ctx context.Context) bool {
 	return isOwner
 }
 
+// SetCampaignPurgeAfter marks the current campaign as pending deletion and
+// records when it will be purged. Only set for campaigns in the grace period.
+func SetCampaignPurgeAfter(ctx context.Context, purgeAfter time.Time) context.Context {
+	return context.WithValue(ctx, keyCampaignPurgeAfter, purgeAfter)
+}
+
+// GetCampaignPurgeAfter returns when the current campaign will be purged and
+// whether it is pending deletion at all.
+func GetCampaignPurgeAfter(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(keyCampaignPurgeAfter).(time.Time)
+	return t, ok
+}
+
 // --- Signed Media URLs ---
 
 // MediaURLFunc generates a signed media URL given a file ID.
@@ -790,6 +808,89 @@ func GetDegradedPluginCount(ctx context.Context) int {
 	return count
 }
 
+// AnnouncementBanner is an instance announcement shown above page content.
+// Defined here to avoid importing the admin package.
+type AnnouncementBanner struct {
+	ID          int
+	Title       string
+	Body        string
+	Severity    string // info, success, warning, critical
+	Dismissible bool
+}
+
+// SetAnnouncements stores the banners to show the current user.
+func SetAnnouncements(ctx context.Context, banners []AnnouncementBanner) context.Context {
+	return context.WithValue(ctx, keyAnnouncements, banners)
+}
+
+// GetAnnouncements returns the banners to show, or nil.
+func GetAnnouncements(ctx context.Context) []AnnouncementBanner {
+	banners, _ := ctx.Value(keyAnnouncements).([]AnnouncementBanner)
+	return banners
+}
+
+// ContentLicenseData is a public campaign's content license and
+// attribution for the page footer. Defined here to avoid importing the
+// campaigns package.
+type ContentLicenseData struct {
+	Name        string // e.g. "CC BY 4.0"; empty when only attribution is set.
+	URL         string // License deed; empty for "All rights reserved".
+	Attribution string
+}
+
+// SetContentLicense stores the license shown in the page footer.
+func SetContentLicense(ctx context.Context, license *ContentLicenseData) context.Context {
+	return context.WithValue(ctx, keyContentLicense, license)
+}
+
+// GetContentLicense returns the footer license, or nil when none is shown.
+func GetContentLicense(ctx context.Context) *ContentLicenseData {
+	license, _ := ctx.Value(keyContentLicense).(*ContentLicenseData)
+	return license
+}
+
+// InstanceBrandingData is the admin-configured instance identity shown in
+// place of Chronicle's own. Defined here to avoid importing the settings
+// package. Logo and Favicon are media file IDs; empty fields fall back to
+// the built-in branding.
+type InstanceBrandingData struct {
+	Name            string
+	Logo            string
+	Favicon         string
+	LandingHeadline string
+	LandingText     string
+	FooterLinks     []FooterLinkData
+}
+
+// FooterLinkData is one link in the instance footer.
+type FooterLinkData struct {
+	Label string
+	URL   string
+}
+
+// SetInstanceBranding stores the instance branding.
+func SetInstanceBranding(ctx context.Context, branding *InstanceBrandingData) context.Context {
+	return context.WithValue(ctx, keyInstanceBranding, branding)
+}
+
+// GetInstanceBranding returns the instance branding. Never nil: without
+// any configured, the zero value renders the built-in branding.
+func GetInstanceBranding(ctx context.Context) *InstanceBrandingData {
+	if branding, ok := ctx.Value(keyInstanceBranding).(*InstanceBrandingData); ok && branding != nil {
+		return branding
+	}
+	return &InstanceBrandingData{}
+}
+
+// InstanceName returns the instance's display name, "Chronicle" unless an
+// admin has set one.
+func InstanceName(ctx context.Context) string {
+	if name := GetInstanceBranding(ctx).Name; name != "" {
+		return name
+	}
+	return "Chronicle"
+}
+
 // EscapeJSONString escapes a string for safe embedding inside a JSON
 // double-quoted value. Only handles the characters that could break
 // the JSON structure (backslash and double-quote).
@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
@@ -14,7 +15,41 @@ import (
 type APIKeyAuthenticator interface {
 	// AuthenticateKey validates a raw API key and returns the key's campaign ID,
 	// owner user ID, and whether the owner has owner-level campaign access.
-	AuthenticateKeyForWS(ctx context.Context, rawKey string) (campaignID, userID string, role int, err error)
+	// fingerprint is checked against the key's device binding; ip and
+	// userAgent go on the security event logged for a wrong device.
+	AuthenticateKeyForWS(ctx context.Context, rawKey, fingerprint, ip, userAgent string) (campaignID, userID string, role int, err error)
+}
+
+// clientIPKey carries the upgrade request's client IP, as Echo resolves it
+// behind trusted proxies, in the request context.
+type clientIPKey struct{}
+
+// withClientIP returns ctx carrying the client IP for AuthenticateWS.
+func withClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// clientIP returns the IP HandleUpgrade resolved, or the connection's
+// remote address when called without it.
+func clientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok && ip != "" {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// deviceFingerprint reads the key's device fingerprint from the
+// X-Device-Fingerprint header the REST API uses, or from the
+// ?fingerprint= query parameter for browser-based clients, which can't set
+// headers on a WebSocket handshake.
+func deviceFingerprint(r *http.Request) string {
+	if fp := r.Header.Get("X-Device-Fingerprint"); fp != "" {
+		return fp
+	}
+	return r.URL.Query().Get("fingerprint")
 }
 
 // SessionAuthenticator authenticates browser sessions for WebSocket connections.
@@ -33,6 +68,9 @@ type CampaignRoleLookup interface {
 	// user dm_only visibility via CampaignSettings.DmGrantIDs. Lets the
 	// hub deliver RequiresDM messages to trusted non-Owner members.
 	IsUserDmGranted(ctx context.Context, campaignID, userID string) (bool, error)
+	// IsCampaignPendingDeletion returns true while the campaign is in its
+	// deletion grace period.
+	IsCampaignPendingDeletion(ctx context.Context, campaignID string) (bool, error)
 }
 
 // MultiAuthenticator combines API key and session authentication for WS upgrades.
@@ -55,7 +93,30 @@ func NewMultiAuthenticator(apiKey APIKeyAuthenticator, session SessionAuthentica
 
 // AuthenticateWS implements the Authenticator interface.
 // Priority: API key (via ?token= query param) > Session cookie.
+//
+// A campaign in its deletion grace period refuses every connection. The
+// web UI hides it from everyone but its owner and makes it read-only for
+// them; a socket would let the owner's clients (Foundry included) keep
+// writing, and nothing else changes that it would need to stream.
 func (a *MultiAuthenticator) AuthenticateWS(r *http.Request) (campaignID, userID, source string, role int, isDmGranted bool, err error) {
+	campaignID, userID, source, role, isDmGranted, err = a.authenticate(r)
+	if err != nil {
+		return "", "", "", 0, false, err
+	}
+	if a.roleLookup != nil {
+		pending, err := a.roleLookup.IsCampaignPendingDeletion(r.Context(), campaignID)
+		if err != nil {
+			return "", "", "", 0, false, fmt.Errorf("campaign lookup: %w", err)
+		}
+		if pending {
+			return "", "", "", 0, false, fmt.Errorf("campaign %s is scheduled for deletion", campaignID)
+		}
+	}
+	return campaignID, userID, source, role, isDmGranted, nil
+}
+
+// authenticate resolves the caller for AuthenticateWS.
+func (a *MultiAuthenticator) authenticate(r *http.Request) (campaignID, userID, source string, role int, isDmGranted bool, err error) {
 	ctx := r.Context()
 
 	// foundrySource picks "foundry-module" when the Foundry module
@@ -78,7 +139,7 @@ func (a *MultiAuthenticator) AuthenticateWS(r *http.Request) (campaignID, userID
 		if a.apiKeyAuth == nil {
 			return "", "", "", 0, false, fmt.Errorf("api key auth not configured")
 		}
-		campaignID, userID, role, err = a.apiKeyAuth.AuthenticateKeyForWS(ctx, token)
+		campaignID, userID, role, err = a.apiKeyAuth.AuthenticateKeyForWS(ctx, token, deviceFingerprint(r), clientIP(r), r.UserAgent())
 		if err != nil {
 			return "", "", "", 0, false, fmt.Errorf("api key auth: %w", err)
 		}
@@ -104,7 +165,7 @@ func (a *MultiAuthenticator) AuthenticateWS(r *http.Request) (campaignID, userID
 		if strings.HasPrefix(authHeader, "Bearer ") {
 			rawKey := strings.TrimPrefix(authHeader, "Bearer ")
 			if a.apiKeyAuth != nil {
-				campaignID, userID, role, err = a.apiKeyAuth.AuthenticateKeyForWS(ctx, rawKey)
+				campaignID, userID, role, err = a.apiKeyAuth.AuthenticateKeyForWS(ctx, rawKey, deviceFingerprint(r), clientIP(r), r.UserAgent())
 				if err != nil {
 					return "", "", "", 0, false, fmt.Errorf("bearer auth: %w", err)
 				}
@@ -0,0 +1,88 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubSessionAuth signs every request in as one user.
+type stubSessionAuth struct{ userID string }
+
+func (s stubSessionAuth) AuthenticateSessionForWS(*http.Request) (string, error) {
+	return s.userID, nil
+}
+
+// stubAPIKeyAuth accepts any key for one campaign and user, recording the
+// device details it was handed.
+type stubAPIKeyAuth struct {
+	campaignID, userID string
+	fingerprint, ip    *string
+}
+
+func (s stubAPIKeyAuth) AuthenticateKeyForWS(_ context.Context, _, fingerprint, ip, _ string) (string, string, int, error) {
+	if s.fingerprint != nil {
+		*s.fingerprint, *s.ip = fingerprint, ip
+	}
+	return s.campaignID, s.userID, 3, nil
+}
+
+// stubRoleLookup makes every user an owner of a campaign that may be
+// pending deletion.
+type stubRoleLookup struct{ pending bool }
+
+func (s stubRoleLookup) GetUserCampaignRole(context.Context, string, string) (int, error) {
+	return 3, nil
+}
+
+func (s stubRoleLookup) IsUserDmGranted(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func (s stubRoleLookup) IsCampaignPendingDeletion(context.Context, string) (bool, error) {
+	return s.pending, nil
+}
+
+// TestAuthenticateWS_PendingDeletion: a campaign in its deletion grace
+// period refuses sockets by session and by API key, even for its owner.
+func TestAuthenticateWS_PendingDeletion(t *testing.T) {
+	for _, url := range []string{"/ws?campaign=camp-1", "/ws?token=chron_key"} {
+		for _, pending := range []bool{false, true} {
+			a := NewMultiAuthenticator(stubAPIKeyAuth{campaignID: "camp-1", userID: "u1"}, stubSessionAuth{"u1"}, stubRoleLookup{pending: pending})
+			campaignID, _, _, _, _, err := a.AuthenticateWS(httptest.NewRequest(http.MethodGet, url, nil))
+			if pending && err == nil {
+				t.Errorf("%s: pending campaign accepted a socket", url)
+			}
+			if !pending && (err != nil || campaignID != "camp-1") {
+				t.Errorf("%s: active campaign = %q, %v", url, campaignID, err)
+			}
+		}
+	}
+}
+
+// TestAuthenticateWS_PassesDeviceFingerprint: the key authenticator gets
+// the fingerprint from the header or the query parameter, and the client IP
+// HandleUpgrade resolved.
+func TestAuthenticateWS_PassesDeviceFingerprint(t *testing.T) {
+	var fp, ip string
+	a := NewMultiAuthenticator(stubAPIKeyAuth{campaignID: "camp-1", userID: "u1", fingerprint: &fp, ip: &ip}, nil, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/ws?token=chron_key&fingerprint=dev-query", nil)
+	r = r.WithContext(withClientIP(r.Context(), "203.0.113.7"))
+	if _, _, _, _, _, err := a.AuthenticateWS(r); err != nil {
+		t.Fatal(err)
+	}
+	if fp != "dev-query" || ip != "203.0.113.7" {
+		t.Errorf("query: fingerprint = %q, ip = %q", fp, ip)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/ws?token=chron_key&fingerprint=dev-query", nil)
+	r.Header.Set("X-Device-Fingerprint", "dev-header")
+	if _, _, _, _, _, err := a.AuthenticateWS(r); err != nil {
+		t.Fatal(err)
+	}
+	if fp != "dev-header" || ip != "192.0.2.1" {
+		t.Errorf("header: fingerprint = %q, ip = %q; want the header and the remote address", fp, ip)
+	}
+}
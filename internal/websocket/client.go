@@ -54,6 +54,12 @@ type Client struct {
 	// check; revoking a grant requires the user to reconnect.
 	IsDmGranted bool
 
+	// editing is the entity whose entry this client last said it was
+	// editing, name its display name for presence. Both belong to
+	// readPump's goroutine.
+	editing string
+	name    string
+
 	hub  *Hub
 	conn *gorillaWs.Conn
 	send chan []byte
@@ -65,6 +71,9 @@ type Client struct {
 // to the hub for broadcast. It runs in its own goroutine per client.
 func (c *Client) readPump() {
 	defer func() {
+		if left := c.leftMessage(); left != nil {
+			c.hub.Broadcast(left)
+		}
 		c.hub.unregister <- c
 		c.close()
 	}()
@@ -119,8 +128,12 @@ func (c *Client) readPump() {
 		// Enforce campaign scope: clients can only send messages for their campaign.
 		msg.CampaignID = c.CampaignID
 		msg.SenderID = c.ID
+		msg.MinRole = 0
+		if msg.Type == MsgEditorPresence && !c.preparePresence(msg) {
+			continue
+		}
 
-		c.hub.broadcast <- msg
+		c.hub.Broadcast(msg)
 	}
 }
 
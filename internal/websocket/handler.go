@@ -95,7 +95,9 @@ func HandleUpgrade(hub *Hub, auth Authenticator, allowedOrigins []string, dynami
 	upgrader := newUpgrader(allowedOrigins, dynamicOrigins)
 
 	return func(c echo.Context) error {
-		r := c.Request()
+		// The authenticator only sees the request; carry Echo's proxy-aware
+		// client IP for the security events it logs.
+		r := c.Request().WithContext(withClientIP(c.Request().Context(), c.RealIP()))
 
 		campaignID, userID, source, role, isDmGranted, err := auth.AuthenticateWS(r)
 		if err != nil {
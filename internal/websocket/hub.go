@@ -45,6 +45,13 @@ type Hub struct {
 	// or persistence needed.
 	foundryMu       sync.RWMutex
 	foundryLastSeen map[string]time.Time
+
+	// relay fans broadcasts out to the other instances in cluster mode
+	// (relay.go); nil on a single instance.
+	relay *redisRelay
+
+	// names resolves display names for editor presence (presence.go).
+	names NameLookup
 }
 
 // NewHub creates a new WebSocket hub. Call Run() to start processing.
@@ -65,9 +72,13 @@ func (h *Hub) MarkFoundrySeen(campaignID string) {
 	if campaignID == "" {
 		return
 	}
+	now := time.Now()
 	h.foundryMu.Lock()
-	h.foundryLastSeen[campaignID] = time.Now()
+	h.foundryLastSeen[campaignID] = now
 	h.foundryMu.Unlock()
+	if h.relay != nil {
+		h.relay.markFoundrySeen(campaignID, now)
+	}
 }
 
 // FoundryPresence returns the last-seen timestamp and whether the
@@ -75,10 +86,18 @@ func (h *Hub) MarkFoundrySeen(campaignID string) {
 // A nil lastSeen means we've never recorded a Foundry-module connection
 // for this campaign; connected is true when lastSeen is within the
 // foundryPresenceTTL window.
+//
+// In cluster mode the Foundry connection may live on another instance, so
+// the shared last-seen time wins when it is newer.
 func (h *Hub) FoundryPresence(campaignID string) (lastSeen *time.Time, connected bool) {
 	h.foundryMu.RLock()
 	t, ok := h.foundryLastSeen[campaignID]
 	h.foundryMu.RUnlock()
+	if h.relay != nil {
+		if shared, found := h.relay.foundrySeen(campaignID); found && shared.After(t) {
+			t, ok = shared, true
+		}
+	}
 	if !ok {
 		return nil, false
 	}
@@ -167,6 +186,9 @@ func (h *Hub) Run() {
 				if msg.RequiresDM && !permissions.CanSeeDmOnly(client.Role, client.IsDmGranted) {
 					continue
 				}
+				if msg.MinRole > 0 && client.Role < msg.MinRole {
+					continue
+				}
 
 				select {
 				case client.send <- data:
@@ -190,11 +212,22 @@ func (h *Hub) Run() {
 // It is safe for concurrent use from any goroutine.
 func (h *Hub) Broadcast(msg *Message) {
 	h.broadcast <- msg
+	if h.relay != nil {
+		h.relay.publish(msg, false)
+	}
 }
 
 // BroadcastToAll sends a message to all connected clients across all campaigns.
 // Used for system-wide announcements (e.g., server shutdown notice).
 func (h *Hub) BroadcastToAll(msg *Message) {
+	h.broadcastToAllLocal(msg)
+	if h.relay != nil {
+		h.relay.publish(msg, true)
+	}
+}
+
+// broadcastToAllLocal delivers msg to every client on this instance.
+func (h *Hub) broadcastToAllLocal(msg *Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
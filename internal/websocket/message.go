@@ -84,6 +84,11 @@ const (
 	MsgEntityNoteDeleted MessageType = "entity_note.deleted"
 )
 
+// Editor presence messages (browser only, see presence.go).
+const (
+	MsgEditorPresence MessageType = "editor.presence"
+)
+
 // Sync control messages.
 const (
 	MsgSyncStatus   MessageType = "sync.status"
@@ -131,6 +136,7 @@ var validMessageTypes = map[MessageType]struct{}{
 	MsgEntityNoteCreated:    {},
 	MsgEntityNoteUpdated:    {},
 	MsgEntityNoteDeleted:    {},
+	MsgEditorPresence:       {},
 	MsgSyncStatus:           {},
 	MsgSyncError:            {},
 	MsgSyncConflict:         {},
@@ -160,6 +166,12 @@ type Message struct {
 	// see hub.go. JSON-omitted by default so existing payloads are
 	// unaffected for non-sensitive events.
 	RequiresDM bool `json:"requiresDm,omitempty"`
+
+	// MinRole, when set, drops the message for clients below that campaign
+	// role at delivery time. Editor presence uses it so players never learn
+	// which entities are being edited. Travels in JSON so the cluster relay
+	// keeps the gate.
+	MinRole int `json:"minRole,omitempty"`
 }
 
 // Encode serializes a Message to JSON bytes.
@@ -0,0 +1,111 @@
+package websocket
+
+// presence.go — editor presence: who has an entity's entry open for editing
+// and who is typing. Browsers send editor.presence messages; the server
+// rebuilds the payload from the connection's own identity so a client can't
+// speak for someone else, and only Scribe+ connections send or receive them
+// (the same bar as the Edit button). Presence is never stored: a late joiner
+// asks with "joined" and current editors answer with "editing". A client
+// that disconnects mid-edit gets a "left" sent on its behalf.
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+// Editor presence states carried in the payload.
+const (
+	PresenceJoined  = "joined"  // started editing; current editors answer with "editing"
+	PresenceEditing = "editing" // still editing (answer or heartbeat)
+	PresenceTyping  = "typing"  // changed the content in the last few seconds
+	PresenceLeft    = "left"    // stopped editing or disconnected
+	PresenceQuery   = "query"   // viewer asking who is editing; doesn't mark the sender
+)
+
+// maxPresenceResourceID bounds the entity ID a client may claim to edit.
+const maxPresenceResourceID = 64
+
+// nameLookupTimeout bounds resolving a display name for presence.
+const nameLookupTimeout = 2 * time.Second
+
+// EditorPresence is the payload of an editor.presence message.
+type EditorPresence struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	State  string `json:"state"`
+}
+
+// NameLookup resolves a user ID to the name shown to other editors.
+type NameLookup func(ctx context.Context, userID string) string
+
+// SetNameLookup sets how presence messages name their sender. Without one
+// the user ID is shown.
+func (h *Hub) SetNameLookup(fn NameLookup) {
+	h.names = fn
+}
+
+// preparePresence validates an inbound editor.presence message and rewrites
+// its payload from the client's identity. Returns false to drop it.
+func (c *Client) preparePresence(msg *Message) bool {
+	if c.Source != "browser" || c.Role < permissions.RoleScribe {
+		return false
+	}
+	if msg.ResourceID == "" || len(msg.ResourceID) > maxPresenceResourceID {
+		return false
+	}
+	var in EditorPresence
+	if err := json.Unmarshal(msg.Payload, &in); err != nil {
+		return false
+	}
+	switch in.State {
+	case PresenceJoined, PresenceEditing, PresenceTyping:
+		c.editing = msg.ResourceID
+	case PresenceLeft:
+		c.editing = ""
+	case PresenceQuery:
+		// Asks only; the sender isn't editing anything.
+	default:
+		return false
+	}
+
+	payload, err := json.Marshal(EditorPresence{UserID: c.UserID, Name: c.displayName(), State: in.State})
+	if err != nil {
+		return false
+	}
+	msg.Payload = payload
+	msg.MinRole = permissions.RoleScribe
+	msg.RequiresDM = false
+	return true
+}
+
+// leftMessage is the "left" sent for a client that disconnects while
+// editing, or nil if it wasn't.
+func (c *Client) leftMessage() *Message {
+	if c.editing == "" {
+		return nil
+	}
+	msg := NewMessage(MsgEditorPresence, c.CampaignID, c.editing,
+		EditorPresence{UserID: c.UserID, Name: c.displayName(), State: PresenceLeft})
+	msg.SenderID = c.ID
+	msg.MinRole = permissions.RoleScribe
+	return msg
+}
+
+// displayName resolves the client's name once per connection.
+func (c *Client) displayName() string {
+	if c.name != "" {
+		return c.name
+	}
+	c.name = c.UserID
+	if c.hub != nil && c.hub.names != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), nameLookupTimeout)
+		defer cancel()
+		if n := c.hub.names(ctx, c.UserID); n != "" {
+			c.name = n
+		}
+	}
+	return c.name
+}
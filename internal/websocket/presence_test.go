@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/permissions"
+)
+
+func TestPreparePresence(t *testing.T) {
+	hub := NewHub()
+	hub.SetNameLookup(func(_ context.Context, userID string) string {
+		if userID == "u1" {
+			return "Alice"
+		}
+		return ""
+	})
+
+	cases := []struct {
+		name     string
+		source   string
+		role     int
+		resource string
+		payload  string
+		want     bool
+	}{
+		{"scribe typing", "browser", permissions.RoleScribe, "e1", `{"state":"typing"}`, true},
+		{"owner joined", "browser", permissions.RoleOwner, "e1", `{"state":"joined"}`, true},
+		{"viewer query", "browser", permissions.RoleScribe, "e1", `{"state":"query"}`, true},
+		{"player dropped", "browser", permissions.RolePlayer, "e1", `{"state":"typing"}`, false},
+		{"api key connection dropped", "foundry", permissions.RoleOwner, "e1", `{"state":"typing"}`, false},
+		{"missing entity", "browser", permissions.RoleScribe, "", `{"state":"typing"}`, false},
+		{"unknown state", "browser", permissions.RoleScribe, "e1", `{"state":"deleted"}`, false},
+		{"bad payload", "browser", permissions.RoleScribe, "e1", `"typing"`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Client{ID: "c1", CampaignID: "camp", UserID: "u1", Source: tc.source, Role: tc.role, hub: hub}
+			msg := &Message{Type: MsgEditorPresence, ResourceID: tc.resource, Payload: json.RawMessage(tc.payload)}
+			if got := c.preparePresence(msg); got != tc.want {
+				t.Fatalf("preparePresence = %v, want %v", got, tc.want)
+			}
+			if !tc.want {
+				return
+			}
+			if msg.MinRole != permissions.RoleScribe {
+				t.Errorf("MinRole = %d, want scribe", msg.MinRole)
+			}
+		})
+	}
+}
+
+func TestPreparePresence_RewritesIdentity(t *testing.T) {
+	hub := NewHub()
+	hub.SetNameLookup(func(context.Context, string) string { return "Alice" })
+	c := &Client{ID: "c1", CampaignID: "camp", UserID: "u1", Source: "browser", Role: permissions.RoleScribe, hub: hub}
+
+	msg := &Message{
+		Type:       MsgEditorPresence,
+		ResourceID: "e1",
+		Payload:    json.RawMessage(`{"userId":"someone-else","name":"Mallory","state":"editing"}`),
+	}
+	if !c.preparePresence(msg) {
+		t.Fatal("message dropped")
+	}
+	var p EditorPresence
+	if err := json.Unmarshal(msg.Payload, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p != (EditorPresence{UserID: "u1", Name: "Alice", State: PresenceEditing}) {
+		t.Errorf("payload = %+v", p)
+	}
+
+	// Disconnecting while editing announces "left"; after "left" it doesn't.
+	left := c.leftMessage()
+	if left == nil || left.ResourceID != "e1" || left.MinRole != permissions.RoleScribe {
+		t.Fatalf("leftMessage = %+v", left)
+	}
+	msg.Payload = json.RawMessage(`{"state":"left"}`)
+	if !c.preparePresence(msg) {
+		t.Fatal("left dropped")
+	}
+	if c.leftMessage() != nil {
+		t.Error("leftMessage after an explicit left")
+	}
+}
@@ -0,0 +1,128 @@
+package websocket
+
+// relay.go — cross-instance fan-out for cluster mode. Each instance only
+// holds its own connections, so a broadcast from one instance is also
+// published on a Redis channel; every other instance delivers it to its
+// local clients through the normal broadcast loop (same audience gate).
+// Foundry presence is mirrored into Redis for the same reason: the module's
+// connection and the presence request can land on different instances.
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// relayChannel is the Redis pub/sub channel broadcasts travel on.
+	relayChannel = "chronicle:ws:broadcast"
+
+	// foundrySeenKeyPrefix + campaignID holds the last Foundry-module
+	// activity as Unix milliseconds.
+	foundrySeenKeyPrefix = "ws:foundry-seen:"
+
+	// foundrySeenKeyTTL keeps the last-seen time around well past the
+	// presence window so the pill can still say when it was last connected.
+	foundrySeenKeyTTL = 7 * 24 * time.Hour
+
+	// relayTimeout bounds one Redis call on the broadcast path.
+	relayTimeout = 2 * time.Second
+)
+
+// redisRelay publishes and receives broadcasts for one hub.
+type redisRelay struct {
+	rdb  *redis.Client
+	node string // this instance; its own messages are skipped on receipt
+}
+
+// relayEnvelope is what travels on the channel.
+type relayEnvelope struct {
+	Node string   `json:"node"`
+	All  bool     `json:"all,omitempty"` // BroadcastToAll rather than one campaign
+	Msg  *Message `json:"msg"`
+}
+
+// EnableRelay shares broadcasts with the other instances through rdb until
+// ctx is cancelled. Call once, before the hub starts taking connections.
+func (h *Hub) EnableRelay(ctx context.Context, rdb *redis.Client) {
+	h.relay = &redisRelay{rdb: rdb, node: uuid.New().String()}
+	go h.relay.subscribe(ctx, h)
+}
+
+// publish sends msg to the other instances. A failure loses the message for
+// remote clients only; local delivery has already happened.
+func (r *redisRelay) publish(msg *Message, all bool) {
+	data, err := json.Marshal(relayEnvelope{Node: r.node, All: all, Msg: msg})
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+	defer cancel()
+	if err := r.rdb.Publish(ctx, relayChannel, data).Err(); err != nil {
+		slog.Warn("ws: relay publish failed",
+			slog.String("type", string(msg.Type)),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// subscribe delivers other instances' broadcasts locally. go-redis
+// resubscribes on its own after a dropped connection.
+func (r *redisRelay) subscribe(ctx context.Context, h *Hub) {
+	sub := r.rdb.Subscribe(ctx, relayChannel)
+	defer func() { _ = sub.Close() }()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ch:
+			if !ok {
+				return
+			}
+			var env relayEnvelope
+			if err := json.Unmarshal([]byte(m.Payload), &env); err != nil || env.Msg == nil {
+				slog.Warn("ws: dropping malformed relay message", slog.Any("error", err))
+				continue
+			}
+			if env.Node == r.node {
+				continue
+			}
+			if env.All {
+				h.broadcastToAllLocal(env.Msg)
+			} else {
+				h.broadcast <- env.Msg
+			}
+		}
+	}
+}
+
+// markFoundrySeen records Foundry-module activity for every instance.
+func (r *redisRelay) markFoundrySeen(campaignID string, at time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+	defer cancel()
+	if err := r.rdb.Set(ctx, foundrySeenKeyPrefix+campaignID, at.UnixMilli(), foundrySeenKeyTTL).Err(); err != nil {
+		slog.Warn("ws: recording foundry presence failed", slog.Any("error", err))
+	}
+}
+
+// foundrySeen returns the shared last-seen time for a campaign.
+func (r *redisRelay) foundrySeen(campaignID string) (time.Time, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+	defer cancel()
+	v, err := r.rdb.Get(ctx, foundrySeenKeyPrefix+campaignID).Result()
+	if err != nil {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestRelay checks that a broadcast on one instance reaches another
+// instance's broadcast loop (and not its own twice), and that Foundry
+// presence seen on one instance is visible on the other.
+func TestRelay(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, b := NewHub(), NewHub()
+	a.EnableRelay(ctx, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	b.EnableRelay(ctx, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	// Wait until both subscriptions are live before publishing.
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mr.PubSubNumSub(relayChannel)) == 0 || mr.PubSubNumSub(relayChannel)[relayChannel] < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("relay subscriptions never became active")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	a.Broadcast(&Message{Type: MsgEntityUpdated, CampaignID: "c1", ResourceID: "e1", RequiresDM: true})
+	<-a.broadcast // a's own local delivery
+
+	select {
+	case msg := <-b.broadcast:
+		if msg.CampaignID != "c1" || msg.ResourceID != "e1" || !msg.RequiresDM {
+			t.Errorf("relayed message = %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("broadcast never reached the other instance")
+	}
+
+	select {
+	case msg := <-a.broadcast:
+		t.Errorf("instance received its own relayed message: %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, connected := b.FoundryPresence("c1"); connected {
+		t.Fatal("presence before any Foundry activity")
+	}
+	a.MarkFoundrySeen("c1")
+	if _, connected := b.FoundryPresence("c1"); !connected {
+		t.Error("Foundry activity on one instance not visible on the other")
+	}
+}
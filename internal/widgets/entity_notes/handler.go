@@ -1,7 +1,9 @@
 package entity_notes
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -15,12 +17,34 @@ import (
 // build a ViewerContext from CampaignContext + auth, decode the
 // request body, delegate to the service, return JSON.
 type Handler struct {
-	svc Service
+	svc        Service
+	personal   PersonalNoteService
+	entityGate EntityGate
+}
+
+// EntityGate is the narrow cross-plugin seam personal notes use to check the
+// entity belongs to the campaign and is viewable, without importing the
+// entities plugin. Implemented by the same adapter the posts widget uses.
+type EntityGate interface {
+	// ResolveViewableEntity returns the entity's owning campaign ID and
+	// whether the viewer (role, userID) may view it.
+	ResolveViewableEntity(ctx context.Context, entityID string, role int, userID string) (campaignID string, canView bool, err error)
 }
 
 // NewHandler constructs an HTTP handler against a service.
 func NewHandler(svc Service) *Handler { return &Handler{svc: svc} }
 
+// SetPersonalNoteService injects the personal note service. Called during
+// app wiring.
+func (h *Handler) SetPersonalNoteService(svc PersonalNoteService) {
+	h.personal = svc
+}
+
+// SetEntityGate injects the entity-visibility gate for personal notes.
+func (h *Handler) SetEntityGate(gate EntityGate) {
+	h.entityGate = gate
+}
+
 // viewerFrom collapses the framework-supplied CampaignContext into the
 // audience-relevant facts the service needs. The IsScribe assertion
 // excludes Owner because Owner > Scribe in the role enum and the ACL
@@ -141,3 +165,63 @@ func (h *Handler) Delete(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
+
+// GetPersonalNote returns the caller's own note on an entity.
+// GET /campaigns/:id/entities/:eid/my-note
+func (h *Handler) GetPersonalNote(c echo.Context) error {
+	_, entityID, err := h.personalNoteEntity(c)
+	if err != nil {
+		return err
+	}
+	note, err := h.personal.Get(c.Request().Context(), entityID, auth.GetUserID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, note)
+}
+
+// SavePersonalNote autosaves the caller's own note on an entity.
+// PUT /campaigns/:id/entities/:eid/my-note
+func (h *Handler) SavePersonalNote(c echo.Context) error {
+	cc, entityID, err := h.personalNoteEntity(c)
+	if err != nil {
+		return err
+	}
+	var req SavePersonalNoteRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+	note, err := h.personal.Save(c.Request().Context(), cc.Campaign.ID, entityID, auth.GetUserID(c), req.Body)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, note)
+}
+
+// personalNoteEntity resolves the :eid route param for the personal note
+// endpoints. The note table has no campaign check of its own, so the entity
+// must belong to the URL campaign and be viewable by the caller; otherwise
+// a member could pin notes to (and probe) entities they can't see.
+func (h *Handler) personalNoteEntity(c echo.Context) (*campaigns.CampaignContext, string, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return nil, "", apperror.NewMissingContext()
+	}
+	entityID := c.Param("eid")
+	if entityID == "" {
+		return nil, "", apperror.NewBadRequest("entity ID is required")
+	}
+	if h.personal == nil || h.entityGate == nil {
+		// Fail closed: never serve notes against an unchecked entity.
+		return nil, "", apperror.NewInternal(errors.New("entity_notes: personal notes not configured"))
+	}
+	campaignID, canView, err := h.entityGate.ResolveViewableEntity(
+		c.Request().Context(), entityID, int(cc.MemberRole), auth.GetUserID(c))
+	if err != nil {
+		return nil, "", err
+	}
+	if campaignID != cc.Campaign.ID || !canView {
+		return nil, "", apperror.NewNotFound("entity not found")
+	}
+	return cc, entityID, nil
+}
@@ -95,3 +95,17 @@ type UpdateNoteRequest struct {
 	BodyHTML   *string          `json:"bodyHtml,omitempty"`
 	Pinned     *bool            `json:"pinned,omitempty"`
 }
+
+// PersonalNote is a member's own scratchpad on an entity: one per member
+// per entity, plain text, readable by nobody else. Stored in
+// entity_personal_notes, separate from the audience-bearing notes above.
+type PersonalNote struct {
+	EntityID  string    `json:"entityId"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// SavePersonalNoteRequest is the autosave payload for a personal note.
+type SavePersonalNoteRequest struct {
+	Body string `json:"body"`
+}
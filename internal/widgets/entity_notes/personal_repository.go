@@ -0,0 +1,70 @@
+package entity_notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PersonalNoteRepository is the data access interface for personal notes.
+// Every method is keyed by the owning user, so there is no query that could
+// hand one member's note to another.
+type PersonalNoteRepository interface {
+	// Find returns the user's note on an entity, or (nil, nil) if none.
+	Find(ctx context.Context, entityID, userID string) (*PersonalNote, error)
+
+	// Upsert creates or replaces the user's note on an entity.
+	Upsert(ctx context.Context, campaignID, userID string, note *PersonalNote) error
+
+	// Delete removes the user's note on an entity. Missing rows are fine.
+	Delete(ctx context.Context, entityID, userID string) error
+}
+
+type personalNoteRepository struct {
+	db *sql.DB
+}
+
+// NewPersonalNoteRepository creates a personal note repository.
+func NewPersonalNoteRepository(db *sql.DB) PersonalNoteRepository {
+	return &personalNoteRepository{db: db}
+}
+
+func (r *personalNoteRepository) Find(ctx context.Context, entityID, userID string) (*PersonalNote, error) {
+	n := &PersonalNote{EntityID: entityID}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT body, updated_at FROM entity_personal_notes
+		 WHERE entity_id = ? AND user_id = ?`,
+		entityID, userID,
+	).Scan(&n.Body, &n.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding personal note: %w", err)
+	}
+	return n, nil
+}
+
+func (r *personalNoteRepository) Upsert(ctx context.Context, campaignID, userID string, note *PersonalNote) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO entity_personal_notes (entity_id, user_id, campaign_id, body, updated_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE body = VALUES(body), updated_at = VALUES(updated_at)`,
+		note.EntityID, userID, campaignID, note.Body, note.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("saving personal note: %w", err)
+	}
+	return nil
+}
+
+func (r *personalNoteRepository) Delete(ctx context.Context, entityID, userID string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM entity_personal_notes WHERE entity_id = ? AND user_id = ?`,
+		entityID, userID,
+	); err != nil {
+		return fmt.Errorf("deleting personal note: %w", err)
+	}
+	return nil
+}
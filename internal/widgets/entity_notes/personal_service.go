@@ -0,0 +1,73 @@
+package entity_notes
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// maxPersonalNoteBytes caps a personal note at what the TEXT column holds.
+const maxPersonalNoteBytes = 65535
+
+// PersonalNoteService is the business-logic interface for personal notes.
+// The owner is always the caller; there is deliberately no method that takes
+// a different user ID to read, so owners and DM-granted members have no path
+// to someone else's scratchpad.
+type PersonalNoteService interface {
+	// Get returns the user's note on an entity. A member who hasn't written
+	// one gets an empty note rather than NotFound, so the panel just opens
+	// blank.
+	Get(ctx context.Context, entityID, userID string) (*PersonalNote, error)
+
+	// Save replaces the user's note on an entity. A blank body deletes it.
+	Save(ctx context.Context, campaignID, entityID, userID, body string) (*PersonalNote, error)
+}
+
+type personalNoteService struct {
+	repo PersonalNoteRepository
+}
+
+// NewPersonalNoteService creates a personal note service.
+func NewPersonalNoteService(repo PersonalNoteRepository) PersonalNoteService {
+	return &personalNoteService{repo: repo}
+}
+
+func (s *personalNoteService) Get(ctx context.Context, entityID, userID string) (*PersonalNote, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorized("sign in to keep personal notes")
+	}
+	n, err := s.repo.Find(ctx, entityID, userID)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if n == nil {
+		n = &PersonalNote{EntityID: entityID}
+	}
+	return n, nil
+}
+
+func (s *personalNoteService) Save(ctx context.Context, campaignID, entityID, userID, body string) (*PersonalNote, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorized("sign in to keep personal notes")
+	}
+	if len(body) > maxPersonalNoteBytes {
+		return nil, apperror.NewBadRequest("note is too long")
+	}
+
+	note := &PersonalNote{EntityID: entityID}
+	if strings.TrimSpace(body) == "" {
+		if err := s.repo.Delete(ctx, entityID, userID); err != nil {
+			return nil, apperror.NewInternal(err)
+		}
+		return note, nil
+	}
+
+	note.Body = body
+	note.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Upsert(ctx, campaignID, userID, note); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	return note, nil
+}
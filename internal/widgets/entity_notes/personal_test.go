@@ -0,0 +1,141 @@
+package entity_notes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// stubPersonalRepo keeps personal notes in memory keyed by entity+user.
+type stubPersonalRepo struct {
+	notes   map[string]PersonalNote
+	deleted int
+}
+
+func (r *stubPersonalRepo) Find(_ context.Context, entityID, userID string) (*PersonalNote, error) {
+	n, ok := r.notes[entityID+"/"+userID]
+	if !ok {
+		return nil, nil
+	}
+	return &n, nil
+}
+
+func (r *stubPersonalRepo) Upsert(_ context.Context, _, userID string, note *PersonalNote) error {
+	if r.notes == nil {
+		r.notes = map[string]PersonalNote{}
+	}
+	r.notes[note.EntityID+"/"+userID] = *note
+	return nil
+}
+
+func (r *stubPersonalRepo) Delete(_ context.Context, entityID, userID string) error {
+	delete(r.notes, entityID+"/"+userID)
+	r.deleted++
+	return nil
+}
+
+func TestPersonalNoteService(t *testing.T) {
+	ctx := context.Background()
+	repo := &stubPersonalRepo{}
+	svc := NewPersonalNoteService(repo)
+
+	// No note yet: an empty one, not NotFound.
+	n, err := svc.Get(ctx, "e1", "alice")
+	if err != nil || n.Body != "" {
+		t.Fatalf("Get before save = %+v, %v", n, err)
+	}
+
+	if _, err := svc.Save(ctx, "c1", "e1", "alice", "suspects the innkeeper"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := svc.Get(ctx, "e1", "alice"); n.Body != "suspects the innkeeper" {
+		t.Errorf("own note = %q", n.Body)
+	}
+	// Notes are per member: another member (the GM included) sees nothing.
+	if n, _ := svc.Get(ctx, "e1", "gm"); n.Body != "" {
+		t.Errorf("other member read %q", n.Body)
+	}
+
+	// Clearing the note deletes the row.
+	if _, err := svc.Save(ctx, "c1", "e1", "alice", "  \n"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.deleted != 1 || len(repo.notes) != 0 {
+		t.Errorf("blank save kept the note: %+v", repo.notes)
+	}
+
+	if _, err := svc.Save(ctx, "c1", "e1", "alice", strings.Repeat("x", maxPersonalNoteBytes+1)); !isStatus(err, http.StatusBadRequest) {
+		t.Errorf("oversized save err = %v, want 400", err)
+	}
+	if _, err := svc.Get(ctx, "e1", ""); !isStatus(err, http.StatusUnauthorized) {
+		t.Errorf("anonymous get err = %v, want 401", err)
+	}
+}
+
+// fakeEntityGate maps entity IDs to their owning campaign + view decision.
+type fakeEntityGate map[string]struct {
+	campaignID string
+	canView    bool
+}
+
+func (g fakeEntityGate) ResolveViewableEntity(_ context.Context, entityID string, _ int, _ string) (string, bool, error) {
+	e := g[entityID]
+	return e.campaignID, e.canView, nil
+}
+
+func TestPersonalNoteHandler_EntityGate(t *testing.T) {
+	gate := fakeEntityGate{
+		"visible": {"c1", true},
+		"private": {"c1", false},
+		"foreign": {"c2", true},
+	}
+
+	cases := []struct {
+		name     string
+		gate     EntityGate
+		entityID string
+		want     int
+	}{
+		{"viewable entity", gate, "visible", http.StatusOK},
+		{"entity the member can't see", gate, "private", http.StatusNotFound},
+		{"entity in another campaign", gate, "foreign", http.StatusNotFound},
+		{"no gate wired fails closed", nil, "visible", http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(nil)
+			h.SetPersonalNoteService(NewPersonalNoteService(&stubPersonalRepo{}))
+			if tc.gate != nil {
+				h.SetEntityGate(tc.gate)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id", "eid")
+			c.SetParamValues("c1", tc.entityID)
+			c.Set("campaign_context", &campaigns.CampaignContext{
+				Campaign:   &campaigns.Campaign{ID: "c1"},
+				MemberRole: campaigns.RolePlayer,
+			})
+			c.Set("auth_user_id", "alice")
+
+			err := h.GetPersonalNote(c)
+			got := rec.Code
+			if appErr, ok := err.(*apperror.AppError); ok {
+				got = appErr.Code
+			}
+			if got != tc.want {
+				t.Errorf("status = %d, want %d (err %v)", got, tc.want, err)
+			}
+		})
+	}
+}
@@ -26,4 +26,8 @@ func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignServ
 	g.GET("/entities/:eid/notes/:nid", h.Get)
 	g.PUT("/entities/:eid/notes/:nid", h.Update)
 	g.DELETE("/entities/:eid/notes/:nid", h.Delete)
+
+	// Personal notes: the caller's own scratchpad on the entity. Autosaved.
+	g.GET("/entities/:eid/my-note", h.GetPersonalNote)
+	g.PUT("/entities/:eid/my-note", h.SavePersonalNote)
 }
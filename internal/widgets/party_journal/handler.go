@@ -0,0 +1,170 @@
+package party_journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// EntityGate is the narrow cross-plugin seam the journal uses to check the
+// entity belongs to the campaign and is viewable, without importing the
+// entities plugin. Implemented by the shared entity access adapter.
+type EntityGate interface {
+	// ResolveViewableEntity returns the entity's owning campaign ID and
+	// whether the viewer (role, userID) may view it.
+	ResolveViewableEntity(ctx context.Context, entityID string, role int, userID string) (campaignID string, canView bool, err error)
+}
+
+// Handler is the HTTP boundary for the party journal. Thin: resolve the
+// journal entity and viewer, delegate to the service, return JSON.
+type Handler struct {
+	svc        Service
+	entityGate EntityGate
+}
+
+// NewHandler creates a party journal handler.
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// SetEntityGate injects the entity-visibility gate. Called during app wiring.
+func (h *Handler) SetEntityGate(gate EntityGate) {
+	h.entityGate = gate
+}
+
+// List returns the journal's entries.
+// GET /campaigns/:id/entities/:eid/journal
+func (h *Handler) List(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	entries, err := h.svc.List(c.Request().Context(), entityID, viewer)
+	if err != nil {
+		return err
+	}
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+// Create appends an entry.
+// POST /campaigns/:id/entities/:eid/journal
+func (h *Handler) Create(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	var req CreateEntryRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+	entry, err := h.svc.Create(c.Request().Context(), entityID, viewer, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+// Update edits the caller's own entry.
+// PUT /campaigns/:id/entities/:eid/journal/:jid
+func (h *Handler) Update(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	var req UpdateEntryRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+	entry, err := h.svc.Update(c.Request().Context(), entityID, c.Param("jid"), viewer, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+// Approve publishes a pending entry.
+// POST /campaigns/:id/entities/:eid/journal/:jid/approve
+func (h *Handler) Approve(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	entry, err := h.svc.Approve(c.Request().Context(), entityID, c.Param("jid"), viewer)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+// Annotate sets or clears the GM annotation.
+// PUT /campaigns/:id/entities/:eid/journal/:jid/annotation
+func (h *Handler) Annotate(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	var req AnnotateEntryRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return apperror.NewBadRequest("invalid JSON body")
+	}
+	entry, err := h.svc.Annotate(c.Request().Context(), entityID, c.Param("jid"), viewer, req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+// Delete removes an entry.
+// DELETE /campaigns/:id/entities/:eid/journal/:jid
+func (h *Handler) Delete(c echo.Context) error {
+	entityID, viewer, err := h.resolve(c)
+	if err != nil {
+		return err
+	}
+	if err := h.svc.Delete(c.Request().Context(), entityID, c.Param("jid"), viewer); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// resolve checks the :eid journal belongs to the URL campaign and is
+// viewable by the caller, and builds the viewer. Writing in the journal is
+// gated by view access plus moderation, not by entity edit permission.
+func (h *Handler) resolve(c echo.Context) (string, Viewer, error) {
+	cc := campaigns.GetCampaignContext(c)
+	if cc == nil {
+		return "", Viewer{}, apperror.NewMissingContext()
+	}
+	entityID := c.Param("eid")
+	if entityID == "" {
+		return "", Viewer{}, apperror.NewBadRequest("entity ID is required")
+	}
+	if h.entityGate == nil {
+		// Fail closed: never serve a journal on an unchecked entity.
+		return "", Viewer{}, apperror.NewInternal(errors.New("party_journal: entity gate not configured"))
+	}
+	userID := auth.GetUserID(c)
+	campaignID, canView, err := h.entityGate.ResolveViewableEntity(
+		c.Request().Context(), entityID, int(cc.MemberRole), userID)
+	if err != nil {
+		return "", Viewer{}, err
+	}
+	if campaignID != cc.Campaign.ID || !canView {
+		return "", Viewer{}, apperror.NewNotFound("entity not found")
+	}
+	return entityID, Viewer{
+		UserID:      userID,
+		CampaignID:  cc.Campaign.ID,
+		IsModerator: cc.MemberRole >= campaigns.RoleScribe,
+	}, nil
+}
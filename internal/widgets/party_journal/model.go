@@ -0,0 +1,74 @@
+// Package party_journal implements the shared party journal on Journal-type
+// entities: members append dated entries with their name on them, and the
+// GM approves and annotates them.
+//
+// This is deliberately outside normal entity editing. Players can't edit
+// entity entries (Scribe+ only), but every member may write in the party
+// journal; moderation, not role, decides what the rest of the party reads.
+//
+//   - A player's new entry is pending: only its author and moderators see it.
+//   - A moderator's entry (Scribe+) is approved on arrival.
+//   - Authors may edit and delete their own entries. A player editing an
+//     approved entry sends it back to pending so the GM reviews the change.
+//   - Moderators approve, annotate, and delete, but never rewrite another
+//     member's words; the annotation is where the GM speaks.
+package party_journal
+
+import "time"
+
+// EntryStatus is the moderation state of an entry. Values match the ENUM in
+// db/migrations/000037_party_journal_entries.up.sql.
+type EntryStatus string
+
+const (
+	// StatusPending — written by a player, awaiting GM approval.
+	StatusPending EntryStatus = "pending"
+	// StatusApproved — visible to everyone who can view the journal.
+	StatusApproved EntryStatus = "approved"
+)
+
+// Entry is one dated journal entry.
+type Entry struct {
+	ID           string      `json:"id"`
+	EntityID     string      `json:"entityId"`
+	CampaignID   string      `json:"campaignId"`
+	AuthorUserID string      `json:"authorUserId"` // empty once the author's account is deleted
+	AuthorName   string      `json:"authorName"`
+	EntryDate    string      `json:"entryDate"` // YYYY-MM-DD
+	Body         string      `json:"body"`      // plain text
+	Status       EntryStatus `json:"status"`
+	GMNote       string      `json:"gmNote,omitempty"`
+	ModeratedBy  string      `json:"moderatedBy,omitempty"`
+	ModeratedAt  *time.Time  `json:"moderatedAt,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	UpdatedAt    time.Time   `json:"updatedAt"`
+
+	// Per-viewer affordances, filled by the service so the widget doesn't
+	// re-derive the rules. The service checks them again on every write.
+	CanEdit     bool `json:"canEdit"`
+	CanModerate bool `json:"canModerate"`
+}
+
+// Viewer is the caller as the journal rules see them.
+type Viewer struct {
+	UserID      string
+	CampaignID  string
+	IsModerator bool // Scribe+ on the campaign
+}
+
+// CreateEntryRequest is the payload for appending an entry.
+type CreateEntryRequest struct {
+	EntryDate string `json:"entryDate"` // defaults to today (UTC)
+	Body      string `json:"body"`
+}
+
+// UpdateEntryRequest is the payload for an author's edit.
+type UpdateEntryRequest struct {
+	EntryDate string `json:"entryDate"` // empty keeps the current date
+	Body      string `json:"body"`
+}
+
+// AnnotateEntryRequest is the payload for a GM annotation. Empty clears it.
+type AnnotateEntryRequest struct {
+	GMNote string `json:"gmNote"`
+}
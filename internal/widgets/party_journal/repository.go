@@ -0,0 +1,156 @@
+package party_journal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Repository defines the data access interface for journal entries.
+type Repository interface {
+	// Create inserts a new entry. The service sets every field first.
+	Create(ctx context.Context, e *Entry) error
+
+	// FindByID returns an entry, or (nil, nil) if it doesn't exist.
+	FindByID(ctx context.Context, id string) (*Entry, error)
+
+	// ListByEntity returns a journal's entries in date order. Pending
+	// entries are included only when includePending is set or the viewer
+	// wrote them.
+	ListByEntity(ctx context.Context, entityID, viewerUserID string, includePending bool) ([]Entry, error)
+
+	// Update saves an author's edit: body, date and status.
+	Update(ctx context.Context, e *Entry) error
+
+	// Moderate saves a moderator's status and annotation.
+	Moderate(ctx context.Context, e *Entry) error
+
+	// Delete removes an entry.
+	Delete(ctx context.Context, id string) error
+}
+
+const entryColumns = `j.id, j.entity_id, j.campaign_id, COALESCE(j.author_user_id, ''),
+	COALESCE(u.display_name, ''), DATE_FORMAT(j.entry_date, '%Y-%m-%d'), j.body, j.status, COALESCE(j.gm_note, ''),
+	COALESCE(j.moderated_by, ''), j.moderated_at, j.created_at, j.updated_at`
+
+const entryFrom = ` FROM party_journal_entries j LEFT JOIN users u ON u.id = j.author_user_id`
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a journal entry repository.
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, e *Entry) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO party_journal_entries
+		   (id, entity_id, campaign_id, author_user_id, entry_date, body, status,
+		    moderated_by, moderated_at, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.EntityID, e.CampaignID, e.AuthorUserID, e.EntryDate, e.Body, string(e.Status),
+		nullString(e.ModeratedBy), e.ModeratedAt, e.CreatedAt, e.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("creating journal entry: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*Entry, error) {
+	e, err := scanEntry(r.db.QueryRowContext(ctx,
+		`SELECT `+entryColumns+entryFrom+` WHERE j.id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding journal entry: %w", err)
+	}
+	return e, nil
+}
+
+func (r *repository) ListByEntity(ctx context.Context, entityID, viewerUserID string, includePending bool) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+entryColumns+entryFrom+`
+		 WHERE j.entity_id = ? AND (j.status = 'approved' OR ? OR j.author_user_id = ?)
+		 ORDER BY j.entry_date, j.created_at`,
+		entityID, includePending, viewerUserID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning journal entry: %w", err)
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, e *Entry) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE party_journal_entries SET entry_date = ?, body = ?, status = ?, updated_at = ?
+		 WHERE id = ?`,
+		e.EntryDate, e.Body, string(e.Status), e.UpdatedAt, e.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating journal entry: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) Moderate(ctx context.Context, e *Entry) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE party_journal_entries
+		 SET status = ?, gm_note = ?, moderated_by = ?, moderated_at = ?, updated_at = ?
+		 WHERE id = ?`,
+		string(e.Status), nullString(e.GMNote), nullString(e.ModeratedBy), e.ModeratedAt, e.UpdatedAt, e.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("moderating journal entry: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM party_journal_entries WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("deleting journal entry: %w", err)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row rowScanner) (*Entry, error) {
+	var (
+		e           Entry
+		status      string
+		moderatedAt sql.NullTime
+	)
+	if err := row.Scan(&e.ID, &e.EntityID, &e.CampaignID, &e.AuthorUserID, &e.AuthorName,
+		&e.EntryDate, &e.Body, &status, &e.GMNote, &e.ModeratedBy, &moderatedAt,
+		&e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	e.Status = EntryStatus(status)
+	if moderatedAt.Valid {
+		e.ModeratedAt = &moderatedAt.Time
+	}
+	return &e, nil
+}
+
+// nullString stores an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,26 @@
+package party_journal
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/keyxmakerx/chronicle/internal/plugins/auth"
+	"github.com/keyxmakerx/chronicle/internal/plugins/campaigns"
+)
+
+// RegisterRoutes mounts the party journal endpoints. Every route is open to
+// any member (RolePlayer); moderation and authorship are checked in the
+// service, since writing in the journal is meant to need less than entity
+// edit access.
+func RegisterRoutes(e *echo.Echo, h *Handler, campaignSvc campaigns.CampaignService, authSvc auth.AuthService) {
+	g := e.Group("/campaigns/:id",
+		auth.RequireAuth(authSvc),
+		campaigns.RequireCampaignAccess(campaignSvc),
+		campaigns.RequireRole(campaigns.RolePlayer),
+	)
+	g.GET("/entities/:eid/journal", h.List)
+	g.POST("/entities/:eid/journal", h.Create)
+	g.PUT("/entities/:eid/journal/:jid", h.Update)
+	g.DELETE("/entities/:eid/journal/:jid", h.Delete)
+	g.POST("/entities/:eid/journal/:jid/approve", h.Approve)
+	g.PUT("/entities/:eid/journal/:jid/annotation", h.Annotate)
+}
@@ -0,0 +1,231 @@
+package party_journal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// dateLayout is the wire and storage format of an entry date.
+const dateLayout = "2006-01-02"
+
+// maxEntryBytes caps an entry body at what the TEXT column holds.
+const maxEntryBytes = 65535
+
+// maxGMNoteBytes caps a GM annotation; it's a margin note, not an entry.
+const maxGMNoteBytes = 4000
+
+// Service is the business-logic interface for the party journal. Every
+// method takes the entity the URL names and returns NotFound for an entry on
+// a different journal, so entry IDs can't be replayed across entities.
+type Service interface {
+	// List returns the journal's entries the viewer may read.
+	List(ctx context.Context, entityID string, viewer Viewer) ([]Entry, error)
+
+	// Create appends an entry authored by the viewer.
+	Create(ctx context.Context, entityID string, viewer Viewer, req CreateEntryRequest) (*Entry, error)
+
+	// Update edits the viewer's own entry.
+	Update(ctx context.Context, entityID, id string, viewer Viewer, req UpdateEntryRequest) (*Entry, error)
+
+	// Approve publishes a pending entry to the party. Moderators only.
+	Approve(ctx context.Context, entityID, id string, viewer Viewer) (*Entry, error)
+
+	// Annotate sets or clears the GM annotation. Moderators only.
+	Annotate(ctx context.Context, entityID, id string, viewer Viewer, req AnnotateEntryRequest) (*Entry, error)
+
+	// Delete removes an entry. Its author or a moderator.
+	Delete(ctx context.Context, entityID, id string, viewer Viewer) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a party journal service.
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) List(ctx context.Context, entityID string, viewer Viewer) ([]Entry, error) {
+	entries, err := s.repo.ListByEntity(ctx, entityID, viewer.UserID, viewer.IsModerator)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	for i := range entries {
+		decorate(&entries[i], viewer)
+	}
+	return entries, nil
+}
+
+func (s *service) Create(ctx context.Context, entityID string, viewer Viewer, req CreateEntryRequest) (*Entry, error) {
+	body, err := cleanBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	date, err := cleanDate(req.EntryDate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	e := &Entry{
+		ID:           uuid.New().String(),
+		EntityID:     entityID,
+		CampaignID:   viewer.CampaignID,
+		AuthorUserID: viewer.UserID,
+		EntryDate:    date,
+		Body:         body,
+		Status:       StatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// The GM's own entries need no one's approval.
+	if viewer.IsModerator {
+		e.Status = StatusApproved
+		e.ModeratedBy = viewer.UserID
+		e.ModeratedAt = &now
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	decorate(e, viewer)
+	return e, nil
+}
+
+func (s *service) Update(ctx context.Context, entityID, id string, viewer Viewer, req UpdateEntryRequest) (*Entry, error) {
+	e, err := s.find(ctx, entityID, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if e.AuthorUserID != viewer.UserID {
+		return nil, apperror.NewForbidden("only the author can edit this entry")
+	}
+	body, err := cleanBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if req.EntryDate != "" {
+		if e.EntryDate, err = cleanDate(req.EntryDate); err != nil {
+			return nil, err
+		}
+	}
+
+	e.Body = body
+	e.UpdatedAt = time.Now().UTC()
+	// The GM approved the words they read, not these ones.
+	if !viewer.IsModerator {
+		e.Status = StatusPending
+	}
+	if err := s.repo.Update(ctx, e); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	decorate(e, viewer)
+	return e, nil
+}
+
+func (s *service) Approve(ctx context.Context, entityID, id string, viewer Viewer) (*Entry, error) {
+	return s.moderate(ctx, entityID, id, viewer, func(e *Entry) error {
+		e.Status = StatusApproved
+		return nil
+	})
+}
+
+func (s *service) Annotate(ctx context.Context, entityID, id string, viewer Viewer, req AnnotateEntryRequest) (*Entry, error) {
+	return s.moderate(ctx, entityID, id, viewer, func(e *Entry) error {
+		note := strings.TrimSpace(req.GMNote)
+		if len(note) > maxGMNoteBytes {
+			return apperror.NewBadRequest("annotation is too long")
+		}
+		e.GMNote = note
+		return nil
+	})
+}
+
+func (s *service) Delete(ctx context.Context, entityID, id string, viewer Viewer) error {
+	e, err := s.find(ctx, entityID, id, viewer)
+	if err != nil {
+		return err
+	}
+	if e.AuthorUserID != viewer.UserID && !viewer.IsModerator {
+		return apperror.NewForbidden("only the author or the GM can delete this entry")
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return apperror.NewInternal(err)
+	}
+	return nil
+}
+
+// moderate applies a moderator change and records who made it.
+func (s *service) moderate(ctx context.Context, entityID, id string, viewer Viewer, apply func(*Entry) error) (*Entry, error) {
+	if !viewer.IsModerator {
+		return nil, apperror.NewForbidden("only the GM can moderate the journal")
+	}
+	e, err := s.find(ctx, entityID, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(e); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	e.ModeratedBy = viewer.UserID
+	e.ModeratedAt = &now
+	e.UpdatedAt = now
+	if err := s.repo.Moderate(ctx, e); err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	decorate(e, viewer)
+	return e, nil
+}
+
+// find loads an entry the viewer can see on the given journal. Another
+// player's pending entry is NotFound, the same as a missing one.
+func (s *service) find(ctx context.Context, entityID, id string, viewer Viewer) (*Entry, error) {
+	e, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.NewInternal(err)
+	}
+	if e == nil || e.EntityID != entityID || e.CampaignID != viewer.CampaignID || !canRead(e, viewer) {
+		return nil, apperror.NewNotFound("journal entry not found")
+	}
+	return e, nil
+}
+
+// canRead is the Go mirror of ListByEntity's visibility predicate.
+func canRead(e *Entry, viewer Viewer) bool {
+	return e.Status == StatusApproved || viewer.IsModerator || e.AuthorUserID == viewer.UserID
+}
+
+// decorate fills the per-viewer affordance flags.
+func decorate(e *Entry, viewer Viewer) {
+	e.CanEdit = e.AuthorUserID != "" && e.AuthorUserID == viewer.UserID
+	e.CanModerate = viewer.IsModerator
+}
+
+func cleanBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", apperror.NewBadRequest("entry is empty")
+	}
+	if len(body) > maxEntryBytes {
+		return "", apperror.NewBadRequest("entry is too long")
+	}
+	return body, nil
+}
+
+// cleanDate validates an entry date, defaulting to today (UTC).
+func cleanDate(date string) (string, error) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return time.Now().UTC().Format(dateLayout), nil
+	}
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return "", apperror.NewBadRequest("entry date must be YYYY-MM-DD")
+	}
+	return date, nil
+}
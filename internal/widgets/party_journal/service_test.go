@@ -0,0 +1,163 @@
+package party_journal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/keyxmakerx/chronicle/internal/apperror"
+)
+
+// stubRepo keeps entries in memory. ListByEntity applies canRead so the
+// tests exercise the same visibility rule the SQL encodes.
+type stubRepo struct {
+	entries map[string]*Entry
+}
+
+func newStubRepo() *stubRepo { return &stubRepo{entries: map[string]*Entry{}} }
+
+func (r *stubRepo) Create(_ context.Context, e *Entry) error {
+	cp := *e
+	r.entries[e.ID] = &cp
+	return nil
+}
+
+func (r *stubRepo) FindByID(_ context.Context, id string) (*Entry, error) {
+	e, ok := r.entries[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (r *stubRepo) ListByEntity(_ context.Context, entityID, viewerUserID string, includePending bool) ([]Entry, error) {
+	var out []Entry
+	for _, e := range r.entries {
+		if e.EntityID == entityID && canRead(e, Viewer{UserID: viewerUserID, IsModerator: includePending}) {
+			out = append(out, *e)
+		}
+	}
+	return out, nil
+}
+
+func (r *stubRepo) Update(ctx context.Context, e *Entry) error   { return r.Create(ctx, e) }
+func (r *stubRepo) Moderate(ctx context.Context, e *Entry) error { return r.Create(ctx, e) }
+func (r *stubRepo) Delete(_ context.Context, id string) error    { delete(r.entries, id); return nil }
+
+func isStatus(err error, code int) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.Code == code
+}
+
+var (
+	gm      = Viewer{UserID: "gm", CampaignID: "c1", IsModerator: true}
+	alice   = Viewer{UserID: "alice", CampaignID: "c1"}
+	bob     = Viewer{UserID: "bob", CampaignID: "c1"}
+	outside = Viewer{UserID: "gm2", CampaignID: "c2", IsModerator: true}
+)
+
+func TestJournalModerationFlow(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newStubRepo())
+
+	entry, err := svc.Create(ctx, "j1", alice, CreateEntryRequest{EntryDate: "2026-03-01", Body: "  We met a dragon.  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != StatusPending || entry.Body != "We met a dragon." || !entry.CanEdit {
+		t.Fatalf("player entry = %+v", entry)
+	}
+
+	// Pending: the author and the GM see it, the rest of the party doesn't.
+	for _, tc := range []struct {
+		viewer Viewer
+		want   int
+	}{{alice, 1}, {gm, 1}, {bob, 0}} {
+		got, _ := svc.List(ctx, "j1", tc.viewer)
+		if len(got) != tc.want {
+			t.Errorf("%s sees %d entries, want %d", tc.viewer.UserID, len(got), tc.want)
+		}
+	}
+	if _, err := svc.Update(ctx, "j1", entry.ID, bob, UpdateEntryRequest{Body: "x"}); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("other player touching a pending entry: err = %v, want 404", err)
+	}
+
+	// Only moderators approve and annotate.
+	if _, err := svc.Approve(ctx, "j1", entry.ID, alice); !isStatus(err, http.StatusForbidden) {
+		t.Errorf("self-approve err = %v, want 403", err)
+	}
+	if _, err := svc.Approve(ctx, "j1", entry.ID, gm); err != nil {
+		t.Fatal(err)
+	}
+	annotated, err := svc.Annotate(ctx, "j1", entry.ID, gm, AnnotateEntryRequest{GMNote: "It was a wyvern."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotated.Status != StatusApproved || annotated.GMNote != "It was a wyvern." || annotated.ModeratedBy != "gm" {
+		t.Errorf("moderated entry = %+v", annotated)
+	}
+	if got, _ := svc.List(ctx, "j1", bob); len(got) != 1 || got[0].CanEdit {
+		t.Errorf("approved entry for bob = %+v", got)
+	}
+
+	// The GM annotates but never rewrites a member's words.
+	if _, err := svc.Update(ctx, "j1", entry.ID, gm, UpdateEntryRequest{Body: "edited"}); !isStatus(err, http.StatusForbidden) {
+		t.Errorf("GM edit err = %v, want 403", err)
+	}
+	// A player's edit goes back for review.
+	edited, err := svc.Update(ctx, "j1", entry.ID, alice, UpdateEntryRequest{Body: "We fled a wyvern."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Status != StatusPending || edited.EntryDate != "2026-03-01" {
+		t.Errorf("edited entry = %+v", edited)
+	}
+
+	// Entries are bound to their journal and campaign.
+	if _, err := svc.Approve(ctx, "j2", entry.ID, gm); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("approve via another journal err = %v, want 404", err)
+	}
+	if err := svc.Delete(ctx, "j1", entry.ID, outside); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("delete from another campaign err = %v, want 404", err)
+	}
+	if err := svc.Delete(ctx, "j1", entry.ID, gm); err != nil {
+		t.Errorf("GM delete: %v", err)
+	}
+}
+
+func TestJournalCreateRules(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newStubRepo())
+
+	cases := []struct {
+		name   string
+		viewer Viewer
+		req    CreateEntryRequest
+		want   int // 0 = success
+	}{
+		{"GM entry", gm, CreateEntryRequest{Body: "Session zero."}, 0},
+		{"blank body", alice, CreateEntryRequest{Body: "   "}, http.StatusBadRequest},
+		{"bad date", alice, CreateEntryRequest{EntryDate: "March 1st", Body: "x"}, http.StatusBadRequest},
+		{"impossible date", alice, CreateEntryRequest{EntryDate: "2026-02-30", Body: "x"}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, err := svc.Create(ctx, "j1", tc.viewer, tc.req)
+			if tc.want != 0 {
+				if !isStatus(err, tc.want) {
+					t.Fatalf("err = %v, want %d", err, tc.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The GM's own entries skip the queue; the date defaults to today.
+			if entry.Status != StatusApproved || len(entry.EntryDate) != len(dateLayout) {
+				t.Errorf("entry = %+v", entry)
+			}
+		})
+	}
+}
@@ -619,4 +619,59 @@
 
     return fetch(url, fetchOpts);
   };
+
+  // Entity types per campaign, fetched once per page for the inline
+  // "create" options in pickers.
+  var entityTypesCache = {};
+
+  /**
+   * Enabled entity types for a campaign: [{ id, name, icon, color }].
+   * Failures resolve to [] so a picker just hides its create option.
+   *
+   * @param {string} campaignId
+   * @returns {Promise<Array>}
+   */
+  Chronicle.getEntityTypes = function (campaignId) {
+    if (!entityTypesCache[campaignId]) {
+      entityTypesCache[campaignId] = Chronicle.apiFetch('/campaigns/' + encodeURIComponent(campaignId) + '/entities/types')
+        .then(function (res) { return res.ok ? res.json() : []; })
+        .then(function (types) {
+          return (types || []).filter(function (t) { return t.enabled !== false; });
+        })
+        .catch(function () {
+          delete entityTypesCache[campaignId];
+          return [];
+        });
+    }
+    return entityTypesCache[campaignId];
+  };
+
+  /**
+   * Create a minimal entity (name + type) without leaving the current form.
+   * Resolves with the new entity in the same shape as entity search results
+   * ({ id, name, type_name, type_icon, type_color, url }), so pickers can
+   * select it like any other result. Rejects with the server's message.
+   * When pages with the same or a near name already existed, a warning
+   * toast names them; the page is still created.
+   *
+   * @param {string} campaignId
+   * @param {string} name
+   * @param {number} entityTypeId
+   * @returns {Promise<Object>}
+   */
+  Chronicle.quickCreateEntity = function (campaignId, name, entityTypeId) {
+    return Chronicle.apiFetch('/campaigns/' + encodeURIComponent(campaignId) + '/entities/quick-create', {
+      method: 'POST',
+      body: { name: name, entity_type_id: Number(entityTypeId) || 0 },
+    }).then(function (res) {
+      return res.json().catch(function () { return {}; }).then(function (data) {
+        if (!res.ok) throw new Error(data.message || 'Could not create entity');
+        if (data.similar && data.similar.length && Chronicle.notify) {
+          var names = data.similar.map(function (s) { return s.name; }).join(', ');
+          Chronicle.notify('Created "' + data.name + '". Similar pages already exist: ' + names, 'warning', { duration: 8000 });
+        }
+        return data;
+      });
+    });
+  };
 })();
@@ -0,0 +1,115 @@
+/**
+ * offline.js -- Offline read mode (client side)
+ *
+ * Registers the service worker (/sw.js) and lets a user make a campaign
+ * available offline. The choice is per campaign and per browser
+ * (localStorage), because the cached pages are the user's own view of the
+ * campaign. While enabled, every page load in that campaign asks the worker
+ * to sync against /campaigns/:id/offline-manifest; unchanged pages are not
+ * refetched.
+ *
+ * Markup hook: <button data-offline-toggle data-campaign-id="...">. The
+ * button gets aria-pressed and its title/icon updated.
+ * Logging out (POST /logout) drops every campaign cache.
+ */
+(function () {
+  'use strict';
+
+  if (!('serviceWorker' in navigator)) return;
+
+  // Set by the toggle so only a user-initiated sync announces itself;
+  // background syncs on page load stay silent.
+  var announceSync = false;
+
+  var storageKey = function (campaignId) { return 'chronicle-offline:' + campaignId; };
+
+  function isEnabled(campaignId) {
+    try { return localStorage.getItem(storageKey(campaignId)) === '1'; } catch (e) { return false; }
+  }
+
+  function post(msg) {
+    return navigator.serviceWorker.ready.then(function (reg) {
+      if (reg.active) reg.active.postMessage(msg);
+    });
+  }
+
+  function enable(campaignId) {
+    try { localStorage.setItem(storageKey(campaignId), '1'); } catch (e) { /* private mode */ }
+    return post({ type: 'sync', campaignId: campaignId });
+  }
+
+  function disable(campaignId) {
+    try { localStorage.removeItem(storageKey(campaignId)); } catch (e) { /* private mode */ }
+    return post({ type: 'forget', campaignId: campaignId });
+  }
+
+  function currentCampaignId() {
+    var parts = window.location.pathname.split('/');
+    if (parts.length >= 3 && parts[1] === 'campaigns' && parts[2] && parts[2] !== 'new' && parts[2] !== 'picker') {
+      return parts[2];
+    }
+    return '';
+  }
+
+  function renderToggle(btn) {
+    var on = isEnabled(btn.dataset.campaignId);
+    btn.setAttribute('aria-pressed', on ? 'true' : 'false');
+    btn.title = on ? 'Available offline (click to remove)' : 'Make this campaign available offline';
+    var icon = btn.querySelector('i');
+    if (icon) icon.classList.toggle('text-accent', on);
+  }
+
+  navigator.serviceWorker.register('/sw.js', { scope: '/' }).catch(function (err) {
+    console.warn('[Offline] Service worker registration failed:', err);
+  });
+
+  navigator.serviceWorker.addEventListener('message', function (event) {
+    var msg = event.data || {};
+    if (msg.type === 'sync-revoked') {
+      disable(msg.campaignId);
+    } else if (msg.type === 'sync-done' && announceSync) {
+      announceSync = false;
+      if (window.Chronicle && Chronicle.notify) {
+        Chronicle.notify(msg.pages + ' pages saved for offline reading', 'success');
+      }
+    }
+  });
+
+  document.addEventListener('click', function (e) {
+    var btn = e.target.closest('[data-offline-toggle]');
+    if (!btn) return;
+    var id = btn.dataset.campaignId;
+    if (isEnabled(id)) {
+      disable(id);
+      if (window.Chronicle && Chronicle.notify) Chronicle.notify('Offline copy removed', 'info');
+    } else {
+      announceSync = true;
+      enable(id);
+    }
+    renderToggle(btn);
+  });
+
+  // Cached pages are this user's view; never leave them behind for the next
+  // person on a shared device.
+  document.addEventListener('submit', function (e) {
+    if (e.target.getAttribute('action') === '/logout') {
+      post({ type: 'forget-all' });
+    }
+  });
+
+  function init() {
+    document.querySelectorAll('[data-offline-toggle]').forEach(renderToggle);
+    var id = currentCampaignId();
+    if (id && isEnabled(id) && navigator.onLine) {
+      post({ type: 'sync', campaignId: id });
+    }
+  }
+
+  document.addEventListener('DOMContentLoaded', init);
+  document.addEventListener('htmx:afterSettle', function () {
+    document.querySelectorAll('[data-offline-toggle]').forEach(renderToggle);
+  });
+
+  window.Chronicle = window.Chronicle || {};
+  Chronicle.offline = { enable: enable, disable: disable, isEnabled: isEnabled };
+})();
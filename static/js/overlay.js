@@ -0,0 +1,87 @@
+/**
+ * overlay.js -- Stream Overlay Widget
+ *
+ * Runs on the standalone overlay pages (/overlays/:id/:widget) that
+ * streamers load as OBS browser sources. Polls the widget's data endpoint
+ * every data-refresh seconds and updates the fields in place; the session
+ * widget also ticks a countdown to data-start every second.
+ *
+ * data-start is a zone-less wall-clock time ("2026-10-20T19:00"), parsed
+ * in the browser's own zone like the session schedule it comes from.
+ */
+(function () {
+  'use strict';
+
+  var root = document.getElementById('overlay');
+  if (!root) return;
+
+  var url = root.dataset.url;
+  var refresh = parseInt(root.dataset.refresh, 10) || 60;
+  var start = root.dataset.start || '';
+
+  function field(name) {
+    return root.querySelector('[data-field="' + name + '"]');
+  }
+
+  function setText(name, value) {
+    var el = field(name);
+    if (!el) return;
+    el.textContent = value || '';
+    el.hidden = !value;
+  }
+
+  function setItems(items) {
+    var el = field('items');
+    if (!el) return;
+    el.textContent = '';
+    (items || []).forEach(function (item) {
+      var li = document.createElement('li');
+      li.textContent = item;
+      el.appendChild(li);
+    });
+    el.hidden = !items || items.length === 0;
+  }
+
+  /** Formats the time left until start, e.g. "2d 04:10:09". */
+  function countdownText() {
+    if (!start) return '';
+    var target = new Date(start);
+    if (isNaN(target.getTime())) return '';
+    var secs = Math.floor((target.getTime() - Date.now()) / 1000);
+    if (secs <= 0) return 'Starting now';
+    var days = Math.floor(secs / 86400);
+    secs %= 86400;
+    var pad = function (n) { return (n < 10 ? '0' : '') + n; };
+    var clock = pad(Math.floor(secs / 3600)) + ':' + pad(Math.floor((secs % 3600) / 60)) + ':' + pad(secs % 60);
+    return days > 0 ? days + 'd ' + clock : clock;
+  }
+
+  function tick() {
+    setText('countdown', countdownText());
+  }
+
+  function poll() {
+    fetch(url, { cache: 'no-store' })
+      .then(function (res) { return res.ok ? res.json() : null; })
+      .then(function (data) {
+        // Keep showing the last data through errors; a stream shouldn't
+        // blank out over one failed request.
+        if (!data) return;
+        setText('title', data.title);
+        setText('text', data.text);
+        setText('detail', data.detail);
+        setItems(data.items);
+        start = data.start || '';
+        tick();
+        if (data.refresh && data.refresh !== refresh) {
+          refresh = data.refresh;
+        }
+      })
+      .catch(function () {})
+      .then(function () { setTimeout(poll, refresh * 1000); });
+  }
+
+  tick();
+  setInterval(tick, 1000);
+  setTimeout(poll, refresh * 1000);
+})();
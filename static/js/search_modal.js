@@ -1,13 +1,15 @@
 /**
  * search_modal.js -- Quick Search Modal (Ctrl+K / Cmd+K)
  *
- * Opens a centered search modal for finding entities within the current
- * campaign. Uses the existing /campaigns/:id/entities/search JSON endpoint.
+ * Opens a centered quick switcher for the current campaign. Uses the
+ * /campaigns/:id/quick-switch JSON endpoint, which ranks entity names and
+ * aliases, recently updated pages, and actions ("New NPC", "Go to calendar")
+ * in one list; notes are matched client-side and appended.
  *
  * Features:
  *   - Ctrl+K / Cmd+K keyboard shortcut (global)
  *   - Click the topbar search trigger to open
- *   - Debounced search (200ms)
+ *   - Debounced search (200ms); recent pages + actions on an empty query
  *   - Keyboard navigation: Arrow keys, Enter to open, Escape to close
  *   - Results grouped by entity type with icon + color
  *   - Click outside or press Escape to dismiss
@@ -123,6 +125,7 @@
     activeIndex = -1;
     renderEmpty();
     isOpen = true;
+    doSearch('');
 
     // Focus after repaint so transition works.
     requestAnimationFrame(function () {
@@ -155,14 +158,8 @@
 
     if (debounceTimer) clearTimeout(debounceTimer);
 
-    if (query.length < 2) {
-      results = [];
-      activeIndex = -1;
-      if (query.length === 0) {
-        renderEmpty();
-      } else {
-        renderHint('Type at least 2 characters to search...');
-      }
+    if (query.length === 0) {
+      doSearch('');
       return;
     }
 
@@ -180,7 +177,7 @@
     if (!campaignId) return;
 
     var entityUrl = '/campaigns/' + encodeURIComponent(campaignId) +
-              '/entities/search?q=' + encodeURIComponent(query);
+              '/quick-switch?q=' + encodeURIComponent(query);
     var notesUrl = '/campaigns/' + encodeURIComponent(campaignId) + '/notes';
 
     var signal = abortController.signal;
@@ -188,7 +185,9 @@
     // Fetch entities and notes in parallel. Notes are filtered client-side.
     Promise.all([
       Chronicle.apiFetch(entityUrl, { signal: signal }).then(function (r) { return r.json(); }),
-      Chronicle.apiFetch(notesUrl, { signal: signal }).then(function (r) { return r.json(); }).catch(function () { return []; }),
+      query
+        ? Chronicle.apiFetch(notesUrl, { signal: signal }).then(function (r) { return r.json(); }).catch(function () { return []; })
+        : Promise.resolve([]),
     ])
       .then(function (responses) {
         var entityData = responses[0];
@@ -200,9 +199,17 @@
           return !n.isFolder && n.title && n.title.toLowerCase().indexOf(queryLower) !== -1;
         });
 
-        // Build unified results: entities first, then notes.
-        results = (entityData.results || []).slice();
-        var entityTotal = entityData.total || results.length;
+        // Build unified results: ranked switcher rows first, then notes.
+        results = (entityData.results || []).map(function (r) {
+          return {
+            url: r.url,
+            name: r.label,
+            type_name: r.kind === 'action' ? 'Action'
+              : (r.matched_alias ? (r.type_name || '') + ' \u00b7 aka ' + r.matched_alias : r.type_name),
+            type_icon: r.icon,
+          };
+        });
+        var entityTotal = results.length;
 
         // Append matching notes as search results (max 5).
         for (var i = 0; i < Math.min(matchingNotes.length, 5); i++) {
@@ -0,0 +1,155 @@
+/**
+ * sw.js -- Chronicle service worker (offline read mode)
+ *
+ * Served from /sw.js so its scope covers the whole site. Nothing is cached
+ * opportunistically: the worker only stores
+ *   - the app shell (CSS, core scripts), and
+ *   - the pages + assets listed by a campaign's /offline-manifest, once the
+ *     user has turned offline reading on for that campaign (offline.js).
+ * Page content is per-viewer, so caches are dropped on logout and whenever
+ * the manifest stops being readable (401/403/404).
+ *
+ * Fetch strategy: network first for everything; the cache answers only when
+ * the network fails. Online users always see live content.
+ */
+'use strict';
+
+var SHELL_CACHE = 'chronicle-shell-v1';
+var CAMPAIGN_PREFIX = 'chronicle-offline-';
+var REVISIONS_KEY = '/__offline/revisions';
+
+var SHELL_URLS = [
+  '/static/css/app.css',
+  '/static/img/favicon.svg',
+  '/static/vendor/htmx.min.js',
+  '/static/vendor/alpine-collapse.min.js',
+  '/static/vendor/alpine.min.js',
+  '/static/js/theme.js',
+  '/static/js/notifications.js',
+  '/static/js/boot.js',
+  '/static/js/offline.js',
+];
+
+self.addEventListener('install', function (event) {
+  event.waitUntil(
+    caches.open(SHELL_CACHE)
+      .then(function (cache) { return cache.addAll(SHELL_URLS); })
+      .then(function () { return self.skipWaiting(); })
+  );
+});
+
+self.addEventListener('activate', function (event) {
+  // Drop shell caches from older worker versions.
+  event.waitUntil(
+    caches.keys()
+      .then(function (keys) {
+        return Promise.all(keys.filter(function (k) {
+          return k.indexOf('chronicle-shell-') === 0 && k !== SHELL_CACHE;
+        }).map(function (k) { return caches.delete(k); }));
+      })
+      .then(function () { return self.clients.claim(); })
+  );
+});
+
+self.addEventListener('fetch', function (event) {
+  var req = event.request;
+  if (req.method !== 'GET' || new URL(req.url).origin !== self.location.origin) return;
+
+  event.respondWith(
+    fetch(req).catch(function () {
+      // HTMX requests for a cached page get the full page; hx-boost swaps
+      // the body either way.
+      return caches.match(req, { ignoreVary: true, ignoreSearch: req.mode === 'navigate' })
+        .then(function (hit) {
+          return hit || new Response('Offline and not cached', { status: 503, headers: { 'Content-Type': 'text/plain' } });
+        });
+    })
+  );
+});
+
+self.addEventListener('message', function (event) {
+  var msg = event.data || {};
+  var reply = function (data) {
+    if (event.source) event.source.postMessage(data);
+  };
+
+  if (msg.type === 'sync' && msg.campaignId) {
+    event.waitUntil(syncCampaign(msg.campaignId).then(reply, function (err) {
+      reply({ type: 'sync-error', campaignId: msg.campaignId, error: String(err) });
+    }));
+  } else if (msg.type === 'forget' && msg.campaignId) {
+    event.waitUntil(caches.delete(CAMPAIGN_PREFIX + msg.campaignId));
+  } else if (msg.type === 'forget-all') {
+    event.waitUntil(caches.keys().then(function (keys) {
+      return Promise.all(keys.filter(function (k) {
+        return k.indexOf(CAMPAIGN_PREFIX) === 0;
+      }).map(function (k) { return caches.delete(k); }));
+    }));
+  }
+});
+
+/**
+ * Bring a campaign's cache in line with its manifest: fetch entries whose
+ * revision changed, delete entries no longer listed.
+ */
+function syncCampaign(campaignId) {
+  var cacheName = CAMPAIGN_PREFIX + campaignId;
+  var manifestURL = '/campaigns/' + encodeURIComponent(campaignId) + '/offline-manifest';
+
+  return fetch(manifestURL, { credentials: 'same-origin' }).then(function (resp) {
+    if (resp.status === 401 || resp.status === 403 || resp.status === 404) {
+      // Access gone: nothing cached for this campaign may stay readable.
+      return caches.delete(cacheName).then(function () {
+        return { type: 'sync-revoked', campaignId: campaignId };
+      });
+    }
+    if (!resp.ok) throw new Error('manifest HTTP ' + resp.status);
+
+    return resp.json().then(function (manifest) {
+      return caches.open(cacheName).then(function (cache) {
+        return cache.match(REVISIONS_KEY)
+          .then(function (hit) { return hit ? hit.json() : {}; })
+          .then(function (old) { return applyManifest(cache, manifest, old); });
+      });
+    });
+  });
+}
+
+function applyManifest(cache, manifest, oldRevisions) {
+  var revisions = {};
+  var fetches = [];
+  var changed = 0;
+
+  manifest.entries.forEach(function (entry) {
+    var prev = oldRevisions[entry.id];
+    revisions[entry.id] = { revision: entry.revision, urls: [entry.url].concat(entry.assets || []) };
+    if (prev && prev.revision === entry.revision) return;
+    changed++;
+    revisions[entry.id].urls.forEach(function (url) {
+      fetches.push(cache.add(new Request(url, { credentials: 'same-origin' })).catch(function () {
+        // One broken asset must not abort the sync; retry next time.
+        delete revisions[entry.id];
+      }));
+    });
+  });
+
+  // Remove pages that were deleted or lost visibility.
+  Object.keys(oldRevisions).forEach(function (id) {
+    if (revisions[id]) return;
+    (oldRevisions[id].urls || []).forEach(function (url) { fetches.push(cache.delete(url)); });
+  });
+
+  return Promise.all(fetches).then(function () {
+    return cache.put(REVISIONS_KEY, new Response(JSON.stringify(revisions), {
+      headers: { 'Content-Type': 'application/json' },
+    }));
+  }).then(function () {
+    return {
+      type: 'sync-done',
+      campaignId: manifest.campaign_id,
+      version: manifest.version,
+      pages: manifest.entries.length,
+      changed: changed,
+    };
+  });
+}
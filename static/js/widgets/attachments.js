@@ -0,0 +1,316 @@
+/**
+ * attachments.js -- Chronicle Attachments Widget
+ *
+ * Files attached to an entity or session: handout PDFs, audio (session
+ * recordings, ambience), token-pack zips and images. Players see and
+ * download what's visible to them; Scribes and above upload, rename,
+ * toggle "DM only" and delete, and see how often each file was downloaded.
+ *
+ * The server enforces every rule (internal/plugins/media/attachment_*.go);
+ * data-editable only decides which controls show.
+ *
+ * Auto-mounted by boot.js on elements with data-widget="attachments"
+ * (components.AttachmentsPanel).
+ *
+ * Config (from data-* attributes):
+ *   data-endpoint    - API base (/campaigns/:id/attachments)
+ *   data-target-type - "entity" or "session"
+ *   data-target-id   - the entity or session ID
+ *   data-editable    - "true" for Scribe+
+ *   data-csrf        - CSRF token
+ */
+(function () {
+  'use strict';
+
+  var KIND_ICONS = {
+    document: 'fa-file-pdf',
+    audio: 'fa-file-audio',
+    archive: 'fa-file-zipper',
+    image: 'fa-file-image'
+  };
+
+  Chronicle.register('attachments', {
+    init: function (el, config) {
+      var endpoint = config.endpoint || '';
+      var targetType = config.targetType || '';
+      var targetId = config.targetId || '';
+      var editable = config.editable === true;
+      var csrf = config.csrf || '';
+      if (!endpoint || !targetType || !targetId) return;
+
+      var state = {
+        items: [],
+        loading: true,
+        uploading: false,
+        error: '',
+        playingId: null
+      };
+
+      // --- API ---
+
+      // Chronicle.apiFetch returns a raw Response; surface the server's
+      // message so "audio attachments are limited to 50 MB" reaches the user.
+      function asJSON(resp) {
+        if (!resp.ok) {
+          return resp.json().then(
+            function (body) {
+              var msg = (body && (body.message || body.error)) || ('HTTP ' + resp.status);
+              return Promise.reject(new Error(msg));
+            },
+            function () {
+              return Promise.reject(new Error('HTTP ' + resp.status));
+            }
+          );
+        }
+        return resp.status === 204 ? null : resp.json();
+      }
+
+      function load() {
+        var url = endpoint + '?target_type=' + encodeURIComponent(targetType) +
+          '&target_id=' + encodeURIComponent(targetId);
+        Chronicle.apiFetch(url)
+          .then(asJSON)
+          .then(function (items) {
+            state.items = items || [];
+            state.loading = false;
+            state.error = '';
+            render();
+          })
+          .catch(function (err) {
+            state.loading = false;
+            state.error = humanError(err);
+            render();
+          });
+      }
+
+      function upload(file, title, dmOnly) {
+        var fd = new FormData();
+        fd.append('file', file);
+        fd.append('target_type', targetType);
+        fd.append('target_id', targetId);
+        fd.append('title', title);
+        fd.append('visibility', dmOnly ? 'dm_only' : 'everyone');
+
+        state.uploading = true;
+        render();
+        Chronicle.apiFetch(endpoint, { method: 'POST', body: fd, csrfToken: csrf })
+          .then(asJSON)
+          .then(function (item) {
+            state.items.push(item);
+          })
+          .catch(fail)
+          .then(function () {
+            state.uploading = false;
+            render();
+          });
+      }
+
+      function update(id, changes) {
+        var item = find(id);
+        if (!item) return;
+        var body = {
+          title: changes.title !== undefined ? changes.title : item.title,
+          visibility: changes.visibility || item.visibility
+        };
+        Chronicle.apiFetch(endpoint + '/' + encodeURIComponent(id), { method: 'PUT', body: body, csrfToken: csrf })
+          .then(asJSON)
+          .then(function (updated) { replace(updated); render(); })
+          .catch(fail);
+      }
+
+      function remove(id) {
+        Chronicle.apiFetch(endpoint + '/' + encodeURIComponent(id), { method: 'DELETE', csrfToken: csrf })
+          .then(asJSON)
+          .then(function () {
+            state.items = state.items.filter(function (a) { return a.id !== id; });
+            render();
+          })
+          .catch(fail);
+      }
+
+      function find(id) {
+        for (var i = 0; i < state.items.length; i++) {
+          if (state.items[i].id === id) return state.items[i];
+        }
+        return null;
+      }
+
+      function replace(item) {
+        state.items = state.items.map(function (a) { return a.id === item.id ? item : a; });
+      }
+
+      // --- Render ---
+
+      function render() {
+        // Hide the panel from players when there's nothing to show.
+        if (!editable && !state.loading && !state.error && state.items.length === 0) {
+          el.classList.add('hidden');
+          return;
+        }
+        el.classList.remove('hidden');
+
+        var h = '';
+        h += '<h3 class="text-sm font-semibold text-fg-secondary uppercase tracking-wider mb-3">';
+        h += '<i class="fa-solid fa-paperclip mr-1.5"></i>Attachments</h3>';
+
+        if (state.loading) {
+          h += '<p class="text-sm text-fg-muted"><i class="fa-solid fa-spinner fa-spin mr-1"></i>Loading attachments…</p>';
+        } else if (state.error) {
+          h += '<p class="text-sm text-red-600 dark:text-red-400">' + esc(state.error) + '</p>';
+        } else if (state.items.length === 0) {
+          h += '<p class="text-sm text-fg-muted mb-3">No files attached yet.</p>';
+        }
+
+        h += '<ul class="space-y-2">';
+        state.items.forEach(function (a) { h += renderItem(a); });
+        h += '</ul>';
+
+        if (editable && !state.loading && !state.error) h += renderUpload();
+        el.innerHTML = h;
+      }
+
+      function renderItem(a) {
+        var id = esc(a.id);
+        var h = '';
+        h += '<li class="rounded border border-edge p-2">';
+        h += '<div class="flex items-center gap-2 text-sm">';
+        h += '<i class="fa-solid ' + (KIND_ICONS[a.kind] || 'fa-file') + ' text-fg-muted"></i>';
+        h += '<a class="text-accent hover:underline truncate" href="' + esc(a.download_url) + '">' + esc(a.title || a.original_name) + '</a>';
+        h += '<span class="text-xs text-fg-muted">' + esc(formatSize(a.file_size)) + '</span>';
+        if (a.visibility === 'dm_only') {
+          h += '<span class="px-1.5 py-0.5 rounded text-xs bg-amber-500/10 text-amber-700 dark:text-amber-300">DM only</span>';
+        }
+        h += '<span class="flex-1"></span>';
+        if (editable) {
+          h += '<span class="text-xs text-fg-muted" title="Downloads"><i class="fa-solid fa-download mr-1"></i>' + (a.download_count || 0) + '</span>';
+        }
+        if (a.kind === 'audio') {
+          h += '<button class="btn-ghost btn-sm" data-action="play" data-id="' + id + '" title="Play"><i class="fa-solid fa-play"></i></button>';
+        }
+        if (a.kind === 'document' || a.kind === 'image') {
+          h += '<a class="btn-ghost btn-sm" target="_blank" rel="noopener" href="' + esc(a.download_url) + '?inline=1" title="Preview"><i class="fa-solid fa-eye"></i></a>';
+        }
+        if (editable) {
+          h += '<button class="btn-ghost btn-sm" data-action="rename" data-id="' + id + '" title="Rename"><i class="fa-solid fa-pen"></i></button>';
+          h += '<button class="btn-ghost btn-sm" data-action="toggle" data-id="' + id + '" title="' +
+            (a.visibility === 'dm_only' ? 'Reveal to players' : 'Hide from players') + '"><i class="fa-solid ' +
+            (a.visibility === 'dm_only' ? 'fa-eye-slash' : 'fa-users') + '"></i></button>';
+          h += '<button class="btn-ghost btn-sm" data-action="delete" data-id="' + id + '" title="Delete"><i class="fa-solid fa-trash"></i></button>';
+        }
+        h += '</div>';
+        if (state.playingId === a.id) {
+          // preload="none" until the member asks; inline=1 keeps previews
+          // out of the download count.
+          h += '<audio class="w-full mt-2" controls autoplay preload="none" src="' + esc(a.download_url) + '?inline=1"></audio>';
+        }
+        h += '</li>';
+        return h;
+      }
+
+      function renderUpload() {
+        var h = '';
+        h += '<form class="mt-3 flex flex-wrap items-center gap-2" data-role="upload">';
+        h += '<input type="file" class="text-sm" data-field="file" accept=".pdf,.zip,audio/*,image/*" aria-label="File"/>';
+        h += '<input type="text" class="input text-sm flex-1 min-w-[10rem]" data-field="title" maxlength="200" placeholder="Title (optional)"/>';
+        h += '<label class="text-xs text-fg-muted flex items-center gap-1"><input type="checkbox" data-field="dm-only"/> DM only</label>';
+        h += '<button type="submit" class="btn-primary text-xs"' + (state.uploading ? ' disabled' : '') + '>';
+        h += state.uploading ? '<i class="fa-solid fa-spinner fa-spin mr-1"></i>Uploading…' : '<i class="fa-solid fa-upload mr-1"></i>Attach';
+        h += '</button>';
+        h += '</form>';
+        h += '<p class="text-xs text-fg-muted mt-1">PDF up to 25 MB, audio 50 MB, images 20 MB, zip 100 MB.</p>';
+        return h;
+      }
+
+      // --- Events ---
+
+      function fail(err) {
+        Chronicle.notify && Chronicle.notify(humanError(err), 'error');
+      }
+
+      function onClick(ev) {
+        var btn = ev.target.closest('[data-action]');
+        if (!btn || !el.contains(btn)) return;
+        var id = btn.getAttribute('data-id');
+        var item = find(id);
+        if (!item) return;
+
+        switch (btn.getAttribute('data-action')) {
+          case 'play':
+            state.playingId = state.playingId === id ? null : id;
+            render();
+            break;
+          case 'rename':
+            var title = window.prompt('Attachment title', item.title || item.original_name);
+            if (title !== null && title.trim()) update(id, { title: title.trim() });
+            break;
+          case 'toggle':
+            update(id, { visibility: item.visibility === 'dm_only' ? 'everyone' : 'dm_only' });
+            break;
+          case 'delete':
+            if (!window.confirm('Delete "' + (item.title || item.original_name) + '"?')) return;
+            remove(id);
+            break;
+        }
+      }
+
+      function onSubmit(ev) {
+        var form = ev.target.closest('[data-role="upload"]');
+        if (!form) return;
+        ev.preventDefault();
+        var input = form.querySelector('[data-field="file"]');
+        if (!input || !input.files || !input.files[0]) {
+          Chronicle.notify && Chronicle.notify('Choose a file first', 'warn');
+          return;
+        }
+        upload(
+          input.files[0],
+          form.querySelector('[data-field="title"]').value,
+          form.querySelector('[data-field="dm-only"]').checked
+        );
+      }
+
+      // --- Helpers ---
+
+      function formatSize(bytes) {
+        if (!bytes) return '';
+        if (bytes < 1024) return bytes + ' B';
+        if (bytes < 1024 * 1024) return Math.round(bytes / 1024) + ' KB';
+        return (bytes / (1024 * 1024)).toFixed(1) + ' MB';
+      }
+
+      function humanError(err) {
+        if (err && err.message) return err.message;
+        if (typeof err === 'string') return err;
+        return 'Something went wrong';
+      }
+
+      function esc(s) {
+        return Chronicle.escapeHtml ? Chronicle.escapeHtml(s || '') : escHtml(s || '');
+      }
+      function escHtml(s) {
+        return String(s)
+          .replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;')
+          .replace(/"/g, '&quot;').replace(/'/g, '&#39;');
+      }
+
+      // --- Init ---
+
+      el.addEventListener('click', onClick);
+      el.addEventListener('submit', onSubmit);
+      render();
+      load();
+
+      el.__attachmentsCleanup = function () {
+        el.removeEventListener('click', onClick);
+        el.removeEventListener('submit', onSubmit);
+      };
+    },
+
+    destroy: function (el) {
+      if (el.__attachmentsCleanup) {
+        el.__attachmentsCleanup();
+        delete el.__attachmentsCleanup;
+      }
+    }
+  });
+})();
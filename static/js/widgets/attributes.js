@@ -4,13 +4,16 @@
  * Displays entity custom fields (attributes) with inline editing support.
  * Shows field values in a read-only card by default; clicking "Edit" reveals
  * editable inputs that match the field type definitions (text, number, select,
- * textarea, checkbox, url). Auto-mounted by boot.js on elements with
+ * textarea, checkbox, url, date). Auto-mounted by boot.js on elements with
  * data-widget="attributes".
  *
  * Config (from data-* attributes):
  *   data-endpoint   - Fields API endpoint (GET/PUT),
  *                     e.g. /campaigns/:id/entities/:eid/fields
  *   data-editable   - "true" if user can modify fields (Scribe+)
+ *
+ * Number fields get a trend toggle that loads the field's change history
+ * from <endpoint>/<key>/history and draws it as a small sparkline.
  */
 Chronicle.register('attributes', {
   init: function (el, config) {
@@ -22,7 +25,8 @@ Chronicle.register('attributes', {
       isEditing: false,      // Whether the edit form is shown
       isCustomizing: false,  // Whether the field override panel is shown
       isSaving: false,
-      error: null
+      error: null,
+      openHistory: {}        // Number field keys whose trend is shown
     };
 
     el._attributesState = state;
@@ -153,6 +157,10 @@ Chronicle.register('attributes', {
         label.className = 'text-xs font-medium uppercase tracking-wider';
         label.style.color = 'var(--color-text-secondary)';
         label.textContent = field.label;
+        if (field.type === 'number') {
+          label.classList.add('flex', 'items-center', 'gap-1.5');
+          label.appendChild(historyToggle(field));
+        }
         row.appendChild(label);
 
         var value = document.createElement('dd');
@@ -175,6 +183,12 @@ Chronicle.register('attributes', {
         }
 
         row.appendChild(value);
+        if (state.openHistory[field.key]) {
+          var chart = document.createElement('div');
+          chart.className = 'mt-1';
+          row.appendChild(chart);
+          loadHistory(field, chart);
+        }
         container.appendChild(row);
       });
 
@@ -189,6 +203,89 @@ Chronicle.register('attributes', {
       }
     }
 
+    // --- Number field history ---
+
+    function historyToggle(field) {
+      var btn = document.createElement('button');
+      btn.type = 'button';
+      btn.className = 'hover:text-accent transition-colors';
+      btn.title = 'Show how this value changed';
+      btn.setAttribute('aria-pressed', state.openHistory[field.key] ? 'true' : 'false');
+      btn.innerHTML = '<i class="fa-solid fa-chart-line" style="font-size:10px"></i>';
+      btn.addEventListener('click', function () {
+        state.openHistory[field.key] = !state.openHistory[field.key];
+        render();
+      });
+      return btn;
+    }
+
+    function loadHistory(field, chart) {
+      chart.className = 'mt-1 text-xs';
+      chart.style.color = 'var(--color-text-muted)';
+      chart.textContent = 'Loading…';
+      Chronicle.apiFetch(config.endpoint + '/' + encodeURIComponent(field.key) + '/history')
+        .then(function (r) {
+          if (!r.ok) throw new Error('Failed to load history');
+          return r.json();
+        })
+        .then(function (data) {
+          renderSparkline(chart, data.points || []);
+        })
+        .catch(function (err) {
+          console.error('[attributes] History failed:', err);
+          chart.textContent = 'Could not load history.';
+        });
+    }
+
+    function renderSparkline(chart, points) {
+      chart.innerHTML = '';
+      if (points.length < 2) {
+        chart.textContent = points.length === 1 ? 'Changed once so far.' : 'No changes recorded yet.';
+        return;
+      }
+      var values = points.map(function (p) { return p.value; });
+      var min = Math.min.apply(null, values);
+      var max = Math.max.apply(null, values);
+      var w = 160, h = 32, pad = 2;
+      var coords = values.map(function (v, i) {
+        var x = pad + (i / (values.length - 1)) * (w - 2 * pad);
+        var y = max === min ? h / 2 : pad + (1 - (v - min) / (max - min)) * (h - 2 * pad);
+        return x.toFixed(1) + ',' + y.toFixed(1);
+      });
+
+      var ns = 'http://www.w3.org/2000/svg';
+      var svg = document.createElementNS(ns, 'svg');
+      svg.setAttribute('width', w);
+      svg.setAttribute('height', h);
+      svg.setAttribute('viewBox', '0 0 ' + w + ' ' + h);
+      svg.setAttribute('role', 'img');
+      svg.setAttribute('aria-label', 'Trend from ' + values[0] + ' to ' + values[values.length - 1]);
+      var line = document.createElementNS(ns, 'polyline');
+      line.setAttribute('points', coords.join(' '));
+      line.setAttribute('fill', 'none');
+      line.setAttribute('stroke', 'var(--color-accent, currentColor)');
+      line.setAttribute('stroke-width', '1.5');
+      svg.appendChild(line);
+      chart.appendChild(svg);
+
+      var first = points[0], last = points[points.length - 1];
+      var caption = document.createElement('div');
+      caption.textContent = points.length + ' changes, ' + min + '–' + max +
+        ' · since ' + historyDate(first);
+      chart.appendChild(caption);
+      var tip = document.createElementNS(ns, 'title');
+      tip.textContent = 'Last change ' + historyDate(last) + ': ' + last.value;
+      svg.insertBefore(tip, line);
+    }
+
+    // historyDate prefers the in-game date when the point has one.
+    function historyDate(p) {
+      if (p.game_date) {
+        return p.game_date.year + '-' + p.game_date.month + '-' + p.game_date.day;
+      }
+      return new Date(p.changed_at).toLocaleDateString();
+    }
+
     // --- Edit form ---
 
     function renderEditForm(container) {
@@ -261,11 +358,16 @@ Chronicle.register('attributes', {
               input.checked = currentVal === true || currentVal === 'true' || currentVal === 'on';
               break;
 
-            default: // text, number, url
+            default: // text, number, url, date
               input = document.createElement('input');
               input.type = field.type === 'number' ? 'number' : (field.type === 'url' ? 'url' : 'text');
               input.className = 'input';
               input.value = String(currentVal);
+              if (field.type === 'date') {
+                // In-game date, stored as year-month-day (see date_fields.go).
+                input.placeholder = 'Year-Month-Day';
+                input.pattern = '-?[0-9]+-[0-9]+-[0-9]+';
+              }
               break;
           }
 
@@ -363,7 +465,7 @@ Chronicle.register('attributes', {
 
         var typeSelect = document.createElement('select');
         typeSelect.className = 'input text-sm w-24';
-        ['text', 'number', 'textarea', 'url', 'checkbox', 'select'].forEach(function (t) {
+        ['text', 'number', 'textarea', 'url', 'checkbox', 'select', 'date'].forEach(function (t) {
           var opt = document.createElement('option');
           opt.value = t;
           opt.textContent = t.charAt(0).toUpperCase() + t.slice(1);
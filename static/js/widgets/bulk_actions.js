@@ -3,7 +3,7 @@
  *
  * Adds multi-select checkboxes to entity cards/rows and a floating
  * action bar with bulk operations (change type, add/remove tags,
- * toggle visibility, delete).
+ * toggle visibility, set status, delete).
  *
  * Mount: data-widget="bulk-actions"
  * Config:
@@ -159,6 +159,8 @@
           '<i class="fa-solid fa-tags mr-1"></i>Add Tags</button>' +
           '<button type="button" class="px-3 py-1.5 rounded-md text-xs font-medium bg-surface-alt text-fg hover:bg-accent/10 hover:text-accent transition-colors" data-bulk-action="visibility">' +
           '<i class="fa-solid fa-eye mr-1"></i>Visibility</button>' +
+          '<button type="button" class="px-3 py-1.5 rounded-md text-xs font-medium bg-surface-alt text-fg hover:bg-accent/10 hover:text-accent transition-colors" data-bulk-action="status">' +
+          '<i class="fa-solid fa-pen-ruler mr-1"></i>Status</button>' +
           (isOwner
             ? '<button type="button" class="px-3 py-1.5 rounded-md text-xs font-medium bg-surface-alt text-fg hover:bg-rose-500/10 hover:text-rose-500 transition-colors" data-bulk-action="delete">' +
               '<i class="fa-solid fa-trash mr-1"></i>Delete</button>'
@@ -171,6 +173,7 @@
         actionBar.querySelector('[data-bulk-action="type"]').addEventListener('click', showTypeMenu);
         actionBar.querySelector('[data-bulk-action="add-tags"]').addEventListener('click', showTagMenu);
         actionBar.querySelector('[data-bulk-action="visibility"]').addEventListener('click', toggleVisibility);
+        actionBar.querySelector('[data-bulk-action="status"]').addEventListener('click', showStatusMenu);
         if (actionBar.querySelector('[data-bulk-action="delete"]')) {
           actionBar.querySelector('[data-bulk-action="delete"]').addEventListener('click', confirmDelete);
         }
@@ -248,6 +251,32 @@
         });
       }
 
+      function showStatusMenu() {
+        var ids = getSelectedIds();
+        if (ids.length === 0) return;
+
+        var input = prompt('Set status for ' + ids.length + ' entities:\n\ndraft, published or archived');
+        if (!input) return;
+        var status = input.trim().toLowerCase();
+        if (['draft', 'published', 'archived'].indexOf(status) === -1) {
+          Chronicle.notify('Unknown status: ' + input, 'error');
+          return;
+        }
+
+        Chronicle.apiFetch('/campaigns/' + campaignId + '/entities/bulk-status', {
+          method: 'POST',
+          body: { entity_ids: ids, status: status }
+        }).then(function (res) {
+          if (res.ok) {
+            Chronicle.notify('Set ' + ids.length + ' entities to ' + status, 'success');
+            clearSelection();
+            window.location.reload();
+          } else {
+            Chronicle.notify('Failed to update status', 'error');
+          }
+        });
+      }
+
       function confirmDelete() {
         var ids = getSelectedIds();
         if (ids.length === 0) return;
@@ -0,0 +1,190 @@
+/*
+ * captcha.js — human check on the public auth forms (signup, password reset,
+ * and login after repeated failures).
+ *
+ * The server renders a [data-widget="captcha"] element inside the form with
+ * data-provider set to one of:
+ *   hcaptcha / turnstile — loads the provider's script once and renders its
+ *     widget explicitly; the widget adds its own response field to the form.
+ *   pow — built-in proof-of-work. Finds a nonce such that
+ *     SHA-256(challenge + ":" + nonce) starts with data-difficulty zero bits
+ *     and writes it to the form's pow_nonce input. The submit button stays
+ *     disabled until a nonce is found. Work is chunked with setTimeout so the
+ *     page stays responsive.
+ *
+ * SHA-256 is implemented inline rather than via crypto.subtle: subtle is
+ * only available in secure contexts, and self-hosted instances are often
+ * reached over plain HTTP on a LAN.
+ *
+ * ES5 style to match the rest of static/js. Registered via Chronicle.register
+ * and auto-mounted by boot.js, including after HTMX re-renders the form.
+ */
+(function () {
+  'use strict';
+
+  var SCRIPTS = {
+    hcaptcha: 'https://js.hcaptcha.com/1/api.js?render=explicit&onload=chronicleCaptchaLoaded',
+    turnstile: 'https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit&onload=chronicleCaptchaLoaded'
+  };
+
+  // Hash attempts per setTimeout slice.
+  var POW_CHUNK = 5000;
+
+  // --- Third-party providers ---
+
+  var pending = [];
+  var scriptRequested = false;
+
+  function providerAPI(provider) {
+    return provider === 'hcaptcha' ? window.hcaptcha : window.turnstile;
+  }
+
+  function renderProvider(el, config) {
+    var api = providerAPI(config.provider);
+    if (!api) {
+      pending.push([el, config]);
+      if (!scriptRequested) {
+        scriptRequested = true;
+        var s = document.createElement('script');
+        s.src = SCRIPTS[config.provider];
+        s.async = true;
+        document.head.appendChild(s);
+      }
+      return;
+    }
+    var box = document.createElement('div');
+    el.appendChild(box);
+    // Read the key raw: boot.js coerces numeric-looking data attributes.
+    el._captchaId = api.render(box, { sitekey: el.getAttribute('data-sitekey') });
+  }
+
+  // Called by the provider script once its API is ready.
+  window.chronicleCaptchaLoaded = function () {
+    var queued = pending;
+    pending = [];
+    for (var i = 0; i < queued.length; i++) {
+      if (document.body.contains(queued[i][0])) {
+        renderProvider(queued[i][0], queued[i][1]);
+      }
+    }
+  };
+
+  // --- Proof-of-work ---
+
+  var K = [
+    0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+    0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+    0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+    0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+    0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+    0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+    0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+    0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2
+  ];
+  var W = new Array(64);
+
+  function rotr(x, n) { return (x >>> n) | (x << (32 - n)); }
+
+  // sha256 returns the digest of an ASCII string as eight 32-bit words.
+  // Challenges and nonces are ASCII, so no UTF-8 encoding step is needed.
+  function sha256(msg) {
+    var len = msg.length;
+    var words = [];
+    var i;
+    for (i = 0; i < len; i++) {
+      words[i >> 2] |= msg.charCodeAt(i) << (24 - (i % 4) * 8);
+    }
+    words[len >> 2] |= 0x80 << (24 - (len % 4) * 8);
+    var nblocks = ((len + 8) >> 6) + 1;
+    for (i = (len >> 2) + 1; i < nblocks * 16; i++) {
+      if (words[i] === undefined) words[i] = 0;
+    }
+    words[nblocks * 16 - 1] = len * 8;
+
+    var h0 = 0x6a09e667, h1 = 0xbb67ae85, h2 = 0x3c6ef372, h3 = 0xa54ff53a;
+    var h4 = 0x510e527f, h5 = 0x9b05688c, h6 = 0x1f83d9ab, h7 = 0x5be0cd19;
+
+    for (var b = 0; b < nblocks; b++) {
+      var t;
+      for (t = 0; t < 16; t++) W[t] = words[b * 16 + t] | 0;
+      for (t = 16; t < 64; t++) {
+        var w15 = W[t - 15], w2 = W[t - 2];
+        var s0 = rotr(w15, 7) ^ rotr(w15, 18) ^ (w15 >>> 3);
+        var s1 = rotr(w2, 17) ^ rotr(w2, 19) ^ (w2 >>> 10);
+        W[t] = (W[t - 16] + s0 + W[t - 7] + s1) | 0;
+      }
+      var a = h0, bb = h1, c = h2, d = h3, e = h4, f = h5, g = h6, h = h7;
+      for (t = 0; t < 64; t++) {
+        var S1 = rotr(e, 6) ^ rotr(e, 11) ^ rotr(e, 25);
+        var ch = (e & f) ^ (~e & g);
+        var t1 = (h + S1 + ch + K[t] + W[t]) | 0;
+        var S0 = rotr(a, 2) ^ rotr(a, 13) ^ rotr(a, 22);
+        var maj = (a & bb) ^ (a & c) ^ (bb & c);
+        var t2 = (S0 + maj) | 0;
+        h = g; g = f; f = e; e = (d + t1) | 0;
+        d = c; c = bb; bb = a; a = (t1 + t2) | 0;
+      }
+      h0 = (h0 + a) | 0; h1 = (h1 + bb) | 0; h2 = (h2 + c) | 0; h3 = (h3 + d) | 0;
+      h4 = (h4 + e) | 0; h5 = (h5 + f) | 0; h6 = (h6 + g) | 0; h7 = (h7 + h) | 0;
+    }
+    return [h0, h1, h2, h3, h4, h5, h6, h7];
+  }
+
+  // leadingZeroBits reports whether the digest starts with n zero bits.
+  function leadingZeroBits(digest, n) {
+    for (var i = 0; n > 0; i++) {
+      var take = Math.min(n, 32);
+      if ((digest[i] >>> (32 - take)) !== 0) return false;
+      n -= take;
+    }
+    return true;
+  }
+
+  function solvePoW(el, config) {
+    var form = el.closest('form');
+    var nonceInput = form && form.querySelector('input[name="pow_nonce"]');
+    if (!nonceInput) return;
+    var submit = form.querySelector('button[type="submit"]');
+    var status = el.querySelector('[data-captcha-status]');
+    var prefix = el.getAttribute('data-challenge') + ':';
+    var difficulty = Number(config.difficulty) || 0;
+    var nonce = 0;
+
+    nonceInput.value = '';
+    if (submit) submit.disabled = true;
+
+    function step() {
+      if (el._captchaStopped) return;
+      for (var i = 0; i < POW_CHUNK; i++, nonce++) {
+        if (leadingZeroBits(sha256(prefix + nonce), difficulty)) {
+          nonceInput.value = String(nonce);
+          if (submit) submit.disabled = false;
+          if (status) status.textContent = 'Verified';
+          return;
+        }
+      }
+      el._captchaTimer = setTimeout(step, 0);
+    }
+    el._captchaTimer = setTimeout(step, 0);
+  }
+
+  Chronicle.register('captcha', {
+    init: function (el, config) {
+      el._captchaStopped = false;
+      if (config.provider === 'pow') {
+        solvePoW(el, config);
+      } else if (SCRIPTS[config.provider]) {
+        renderProvider(el, config);
+      }
+    },
+
+    destroy: function (el) {
+      el._captchaStopped = true;
+      clearTimeout(el._captchaTimer);
+      var api = el._captchaId !== undefined && providerAPI(el.getAttribute('data-provider'));
+      if (api && typeof api.remove === 'function') {
+        try { api.remove(el._captchaId); } catch (e) { /* already gone */ }
+      }
+    }
+  });
+})();
@@ -17,6 +17,11 @@
  *   When editor_mention.js is loaded and a campaign ID is available,
  *   typing @ in the editor triggers an entity search popup. Selecting
  *   an entity inserts a styled mention link.
+ *
+ * Editor presence:
+ *   When editor_presence.js is loaded, the header lists other members
+ *   editing the same entry (with a typing indicator), and clicking Edit
+ *   while someone else is editing offers to stay read-only instead.
  */
 (function () {
   'use strict';
@@ -217,10 +222,22 @@
         isEditing: false, // tracks current edit mode state
         el: el,
         autosaveInterval: autosaveInterval,
+        presence: null,
       };
 
       editors.set(el, state);
 
+      // --- Editor Presence ---
+      // Only editors see presence; the server gates it on the same role.
+      var entityMatch = endpoint && endpoint.match(/\/entities\/([^/]+)\/entry$/);
+      if (canEdit && campaignId && entityMatch && Chronicle.EditorPresence) {
+        state.presence = Chronicle.EditorPresence({
+          campaignId: campaignId,
+          entityId: entityMatch[1],
+          onChange: function () { renderPresence(state); },
+        });
+      }
+
       // Update toolbar active states on selection change.
       if (canEdit && toolbar) {
         editor.on('selectionUpdate', function () {
@@ -253,6 +270,7 @@
       if (canEdit) {
         editor.on('update', function () {
           if (!state.isEditing) return; // ignore updates during content loading
+          if (state.presence) state.presence.typing();
           state.dirty = true;
           setStatus(statusEl, 'unsaved');
           updateSaveButton(toolbar, true);
@@ -284,6 +302,10 @@
         clearInterval(state.autosaveTimer);
       }
 
+      if (state.presence) {
+        state.presence.destroy();
+      }
+
       // Clean up mention extension popup and listeners.
       if (state.mentionExt) {
         state.mentionExt.onDestroy();
@@ -324,6 +346,11 @@
     label.textContent = 'Entry';
     headerEl.appendChild(label);
 
+    // Filled by renderPresence; kept across header re-renders.
+    var presenceEl = document.createElement('span');
+    presenceEl.className = 'chronicle-editor__presence';
+    headerEl.appendChild(presenceEl);
+
     var btn = document.createElement('button');
     btn.type = 'button';
 
@@ -346,11 +373,50 @@
       if (state.isEditing) {
         exitEditMode(state);
       } else {
+        // Soft lock: nothing stops two people editing at once, but the
+        // second one should choose to.
+        var others = state.presence ? state.presence.others() : [];
+        if (others.length > 0) {
+          var names = others.map(function (o) { return o.name; }).join(', ');
+          var verb = others.length === 1 ? ' is' : ' are';
+          if (window.confirm(names + verb + ' editing this entry. Open it read-only instead?\n\nOK stays read-only; Cancel edits anyway.')) {
+            return;
+          }
+        }
         enterEditMode(state);
       }
     });
 
     headerEl.appendChild(btn);
+
+    var state = editors.get(headerEl.closest('.chronicle-editor'));
+    if (state) renderPresence(state);
+  }
+
+  /**
+   * Show who else is editing this entry in the header.
+   */
+  function renderPresence(state) {
+    var presenceEl = state.headerEl && state.headerEl.querySelector('.chronicle-editor__presence');
+    if (!presenceEl || !state.presence) return;
+
+    var others = state.presence.others();
+    presenceEl.innerHTML = '';
+    if (others.length === 0) return;
+
+    var typing = others.filter(function (o) { return o.typing; });
+    var names = others.map(function (o) { return o.name; }).join(', ');
+    var text = names + (others.length === 1 ? ' is editing' : ' are editing');
+    if (typing.length > 0) {
+      text = typing.map(function (o) { return o.name; }).join(', ') + ' typing…';
+    }
+
+    var icon = document.createElement('i');
+    icon.className = typing.length > 0 ? 'fa-solid fa-keyboard' : 'fa-solid fa-user-pen';
+    icon.style.fontSize = '11px';
+    presenceEl.appendChild(icon);
+    presenceEl.appendChild(document.createTextNode(' ' + text));
+    presenceEl.title = names + (others.length === 1 ? ' has' : ' have') + ' this entry open for editing';
   }
 
   /**
@@ -376,6 +442,10 @@
     // Add editing visual cue.
     state.el.classList.add('chronicle-editor--editing');
 
+    if (state.presence) {
+      state.presence.join();
+    }
+
     // Start autosave timer.
     if (state.autosaveInterval > 0) {
       state.autosaveTimer = setInterval(function () {
@@ -417,6 +487,10 @@
     // Remove editing visual cue.
     state.el.classList.remove('chronicle-editor--editing');
 
+    if (state.presence) {
+      state.presence.leave();
+    }
+
     // Stop autosave timer.
     if (state.autosaveTimer) {
       clearInterval(state.autosaveTimer);
@@ -14,6 +14,9 @@
  *   - Renders mention nodes as <a> links with data-mention-id attributes.
  *   - Gracefully degrades: API failures close the dropdown, deleted entities
  *     render as plain text.
+ *   - When no result matches the query exactly, "Create <query>" items (one
+ *     per entity type) create a minimal entity via quick-create and insert
+ *     the mention to it, so writers don't have to leave the entry.
  *
  * Integration:
  *   The editor.js widget reads Chronicle.MentionExtension and includes it
@@ -320,8 +323,15 @@
         return res.json();
       })
       .then(function (data) {
-        self.abortController = null;
         var results = data.results || [];
+        return self._createItems(query, results).then(function (extra) {
+          return results.concat(extra);
+        });
+      })
+      .then(function (results) {
+        // A newer query may have started while entity types loaded.
+        if (self.query !== query) return;
+        self.abortController = null;
         self.items = results;
         self.selectedIndex = 0;
         self._renderItems(results);
@@ -338,6 +348,28 @@
       });
   };
 
+  /**
+   * Build "Create <query>" items, one per entity type, unless a result
+   * already has exactly that name.
+   *
+   * @param {string} query - Search query string.
+   * @param {Array} results - Search results for the query.
+   * @returns {Promise<Array>}
+   */
+  MentionPopup.prototype._createItems = function (query, results) {
+    var name = query.trim();
+    var lower = name.toLowerCase();
+    var exists = results.some(function (r) { return (r.name || '').toLowerCase() === lower; });
+    if (exists || name.length < this.MIN_QUERY_LEN || !Chronicle.getEntityTypes) {
+      return Promise.resolve([]);
+    }
+    return Chronicle.getEntityTypes(this.campaignId).then(function (types) {
+      return types.map(function (t) {
+        return { createTypeId: t.id, name: name, type_name: 'Create as ' + t.name, type_color: t.color };
+      });
+    });
+  };
+
   /**
    * Render the list of search result items in the popup.
    *
@@ -376,7 +408,7 @@
         Chronicle.escapeAttr(item.type_color || '#6b7280') + '"></span>' +
         '<div style="min-width:0;flex:1">' +
         '<div style="font-weight:500;font-size:14px;white-space:nowrap;overflow:hidden;text-overflow:ellipsis;">' +
-        Chronicle.escapeHtml(item.name) + '</div>' +
+        (item.createTypeId ? '+ ' : '') + Chronicle.escapeHtml(item.name) + '</div>' +
         '<div style="font-size:12px;opacity:0.6">' +
         Chronicle.escapeHtml(item.type_name || '') + '</div>' +
         '</div>' +
@@ -470,6 +502,33 @@
       var from = mentionStartPos;
       var to = editor.state.selection.from;
 
+      // "Create" item: make the entity first, then mention it in place of
+      // the @query captured now (the popup closes meanwhile).
+      if (entity.createTypeId) {
+        var typed = editor.state.doc.textBetween(from, to);
+        closeMention();
+        Chronicle.quickCreateEntity(options.campaignId, entity.name, entity.createTypeId)
+          .then(function (created) {
+            // If the writer kept typing over the @query, don't delete
+            // whatever is there now; insert at the cursor instead.
+            if (to > editor.state.doc.content.size || editor.state.doc.textBetween(from, to) !== typed) {
+              from = to = editor.state.selection.from;
+            }
+            insertMentionAt(editor, from, to, created);
+          })
+          .catch(function (err) {
+            Chronicle.notify(err.message, 'error');
+          });
+        return;
+      }
+
+      insertMentionAt(editor, from, to, entity);
+    }
+
+    /**
+     * Replace the @query text between from and to with a mention link.
+     */
+    function insertMentionAt(editor, from, to, entity) {
       // Create the mention node content as HTML and insert it.
       // Include data-entity-preview for hover tooltip support.
       var previewAttr = entity.url ? ' data-entity-preview="' + Chronicle.escapeAttr(entity.url + '/preview') + '"' : '';
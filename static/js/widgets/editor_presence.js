@@ -0,0 +1,178 @@
+/**
+ * editor_presence.js -- Chronicle Editor Presence
+ *
+ * Tells other editors of the same entity entry who is editing and who is
+ * typing, over the campaign WebSocket (/ws). The server stamps each
+ * editor.presence message with the sender's real name and only delivers it
+ * to Scribe+ members; see internal/websocket/presence.go.
+ *
+ * Protocol (payload.state):
+ *   joined  - entered edit mode; current editors reply with "editing"
+ *   editing - still editing (reply to "joined", and a heartbeat)
+ *   typing  - changed the content; shows a typing indicator for a few seconds
+ *   left    - exited edit mode (the server sends it on disconnect too)
+ *   query   - a viewer asking who is editing; current editors reply "editing"
+ *
+ * Nothing is stored server-side, so an entry that stops hearing from an
+ * editor drops them after EXPIRE_MS.
+ *
+ * Integration:
+ *   editor.js calls Chronicle.EditorPresence({ campaignId, entityId,
+ *   onChange }) and uses the returned handle for the indicator and the
+ *   soft lock ("X is editing -- open read-only?").
+ */
+(function () {
+  'use strict';
+
+  window.Chronicle = window.Chronicle || {};
+
+  var HEARTBEAT_MS = 20000; // while editing
+  var EXPIRE_MS = 45000; // a bit over two missed heartbeats
+  var TYPING_MS = 4000; // how long "typing" stays lit without another keystroke
+  var TYPING_SEND_MS = 2000; // throttle for outgoing "typing"
+
+  /**
+   * Create a presence channel for one entity entry.
+   *
+   * @param {Object} opts
+   * @param {string} opts.campaignId
+   * @param {string} opts.entityId
+   * @param {Function} opts.onChange - Called with the list from others().
+   * @returns {Object} handle with join, typing, leave, others, destroy.
+   */
+  Chronicle.EditorPresence = function (opts) {
+    var ws = null;
+    var peers = {}; // userId -> { name, typingUntil, seenAt }
+    var editing = false;
+    var heartbeat = null;
+    var sweep = null;
+    var lastTypingSent = 0;
+
+    function send(state) {
+      if (!ws || ws.readyState !== 1) return;
+      ws.send(JSON.stringify({
+        type: 'editor.presence',
+        resourceId: opts.entityId,
+        payload: { state: state },
+      }));
+    }
+
+    function changed() {
+      if (opts.onChange) opts.onChange(others());
+    }
+
+    function onMessage(ev) {
+      var msg;
+      try { msg = JSON.parse(ev.data); } catch (e) { return; }
+      if (!msg || msg.type !== 'editor.presence' || msg.resourceId !== opts.entityId) return;
+      var p = msg.payload || {};
+      if (!p.userId) return;
+
+      var now = Date.now();
+      if (p.state === 'query') {
+        if (editing) send('editing');
+        return;
+      }
+      if (p.state === 'left') {
+        delete peers[p.userId];
+      } else {
+        var peer = peers[p.userId] || { typingUntil: 0 };
+        peer.name = p.name || 'Someone';
+        peer.seenAt = now;
+        if (p.state === 'typing') peer.typingUntil = now + TYPING_MS;
+        peers[p.userId] = peer;
+        // A newcomer can't know we're here until we say so.
+        if (p.state === 'joined' && editing) send('editing');
+      }
+      changed();
+    }
+
+    function connect() {
+      if (typeof window.WebSocket !== 'function') return;
+      try {
+        var protocol = (window.location.protocol === 'https:') ? 'wss:' : 'ws:';
+        ws = new WebSocket(protocol + '//' + window.location.host + '/ws?campaign=' + encodeURIComponent(opts.campaignId));
+        ws.addEventListener('open', function () {
+          // Ask who's already editing, even before we start ourselves, so
+          // the soft lock can warn on the first click of Edit.
+          send(editing ? 'joined' : 'query');
+        });
+        ws.addEventListener('message', onMessage);
+        ws.addEventListener('close', function () { ws = null; });
+        // Presence is a nicety; a blocked /ws just means no indicator.
+        ws.addEventListener('error', function (e) {
+          e.preventDefault && e.preventDefault();
+        });
+      } catch (e) {
+        ws = null;
+      }
+    }
+
+    // Expire silent peers and fade typing indicators.
+    sweep = setInterval(function () {
+      var now = Date.now();
+      var dirty = false;
+      Object.keys(peers).forEach(function (id) {
+        var peer = peers[id];
+        if (now - peer.seenAt > EXPIRE_MS) {
+          delete peers[id];
+          dirty = true;
+        } else if (peer.typingUntil && peer.typingUntil <= now) {
+          peer.typingUntil = 0;
+          dirty = true;
+        }
+      });
+      if (dirty) changed();
+    }, 1000);
+
+    /** Other users editing this entry: [{ name, typing }]. */
+    function others() {
+      var now = Date.now();
+      return Object.keys(peers).map(function (id) {
+        return { name: peers[id].name, typing: peers[id].typingUntil > now };
+      });
+    }
+
+    connect();
+
+    return {
+      others: others,
+
+      /** Announce that this user entered edit mode. */
+      join: function () {
+        editing = true;
+        send('joined');
+        clearInterval(heartbeat);
+        heartbeat = setInterval(function () { send('editing'); }, HEARTBEAT_MS);
+      },
+
+      /** Note a content change (throttled). */
+      typing: function () {
+        if (!editing) return;
+        var now = Date.now();
+        if (now - lastTypingSent < TYPING_SEND_MS) return;
+        lastTypingSent = now;
+        send('typing');
+      },
+
+      /** Announce that this user left edit mode. */
+      leave: function () {
+        if (!editing) return;
+        editing = false;
+        clearInterval(heartbeat);
+        heartbeat = null;
+        send('left');
+      },
+
+      destroy: function () {
+        this.leave();
+        clearInterval(sweep);
+        if (ws) {
+          ws.removeEventListener('message', onMessage);
+          ws.close();
+          ws = null;
+        }
+      },
+    };
+  };
+})();
@@ -24,6 +24,8 @@
  * Features:
  *   - Debounced hover (300ms) to avoid API spam
  *   - Client-side LRU cache (max 100 entries)
+ *   - First hover batch-loads every preview on the page in one POST
+ *     (/campaigns/:id/entities/previews), honouring each entry's max_age
  *   - Smart positioning (above or below, avoids viewport overflow)
  *   - Touch support (long press to show, tap elsewhere to dismiss)
  *   - Dark mode support via .dark class on <html>
@@ -50,6 +52,7 @@
       /* Gradient-bordered image */
       '.et-tooltip__image-wrap { flex-shrink: 0; width: 76px; height: 76px; padding: 2px; border-radius: 10px; background: linear-gradient(135deg, var(--et-color, #6366f1), #a855f7); }',
       '.et-tooltip__image { width: 100%; height: 100%; object-fit: cover; display: block; border-radius: 8px; }',
+      '.et-tooltip__initials { width: 100%; height: 100%; display: flex; align-items: center; justify-content: center; border-radius: 8px; font-size: 26px; font-weight: 600; }',
       '.et-tooltip__info { flex: 1; min-width: 0; }',
       '.et-tooltip__body { padding: 0 12px; }',
       '.et-tooltip__name { font-size: 15px; font-weight: 600; color: #111827; margin: 0 0 4px 0; line-height: 1.3; display: flex; align-items: center; gap: 6px; }',
@@ -79,7 +82,7 @@
   // --- LRU Cache ---
 
   var MAX_CACHE = 100;
-  var cache = {};
+  var cache = {};      // url -> { data, expires } (expires 0 = until evicted)
   var cacheOrder = []; // Most recently used at the end.
 
   /**
@@ -89,19 +92,22 @@
    */
   function cacheGet(url) {
     if (!(url in cache)) return null;
+    if (cache[url].expires && cache[url].expires < Date.now()) return null;
     // Promote to end (most recent).
     var idx = cacheOrder.indexOf(url);
     if (idx !== -1) cacheOrder.splice(idx, 1);
     cacheOrder.push(url);
-    return cache[url];
+    return cache[url].data;
   }
 
   /**
    * Store a preview in the cache, evicting the oldest entry if at capacity.
    * @param {string} url - Preview API URL.
    * @param {Object} data - Preview response data.
+   * @param {number} [maxAge] - Seconds the entry stays fresh (batch entries
+   *   carry their own); omitted means until evicted.
    */
-  function cacheSet(url, data) {
+  function cacheSet(url, data, maxAge) {
     if (url in cache) {
       var idx = cacheOrder.indexOf(url);
       if (idx !== -1) cacheOrder.splice(idx, 1);
@@ -110,10 +116,98 @@
       var oldest = cacheOrder.shift();
       delete cache[oldest];
     }
-    cache[url] = data;
+    cache[url] = { data: data, expires: maxAge ? Date.now() + maxAge * 1000 : 0 };
     cacheOrder.push(url);
   }
 
+  // --- Batch Loading ---
+
+  // Matches a preview URL the batch endpoint can serve:
+  // <prefix>/campaigns/<cid>/entities/<eid>/preview.
+  var PREVIEW_URL_RE = /^(.*\/campaigns\/[^/]+)\/entities\/([^/?#]+)\/preview$/;
+  var MAX_BATCH = 50; // Server-side cap (entities.MaxPreviewBatch).
+  var pending = {};   // url -> in-flight Promise, so hovers share a request.
+
+  /**
+   * Load the preview for a URL. The first hover on a page also fetches every
+   * other uncached preview in view on the same campaign in one POST, so the
+   * next hovers are instant instead of one request each.
+   * @param {string} previewURL
+   * @returns {Promise<Object>} Preview data; rejects when not viewable.
+   */
+  function loadPreview(previewURL) {
+    if (pending[previewURL]) return pending[previewURL];
+
+    var m = PREVIEW_URL_RE.exec(previewURL);
+    if (!m) return fetchSingle(previewURL);
+    var base = m[1];
+
+    // Requested entity first, then the rest of the page, up to the cap.
+    var urls = [previewURL];
+    var triggers = document.querySelectorAll('[data-entity-preview]');
+    for (var i = 0; i < triggers.length && urls.length < MAX_BATCH; i++) {
+      var u = triggers[i].getAttribute('data-entity-preview');
+      var um = PREVIEW_URL_RE.exec(u || '');
+      if (!um || um[1] !== base || urls.indexOf(u) !== -1) continue;
+      if (pending[u] || cacheGet(u)) continue;
+      urls.push(u);
+    }
+    if (urls.length === 1) return fetchSingle(previewURL);
+
+    var ids = urls.map(function (u) { return PREVIEW_URL_RE.exec(u)[2]; });
+    var batch = Chronicle.apiFetch(base + '/entities/previews', { method: 'POST', body: { ids: ids } })
+      .then(function (res) {
+        if (!res.ok) throw new Error('Batch preview failed: ' + res.status);
+        return res.json();
+      })
+      .then(function (body) {
+        var previews = (body && body.previews) || {};
+        urls.forEach(function (u, idx) {
+          var p = previews[ids[idx]];
+          if (p) cacheSet(u, p, p.max_age);
+        });
+        return previews;
+      });
+    batch.then(clear, clear);
+    function clear() {
+      urls.forEach(function (u) { delete pending[u]; });
+    }
+
+    urls.forEach(function (u, idx) {
+      pending[u] = batch.then(function (previews) {
+        var p = previews[ids[idx]];
+        if (!p) throw new Error('Preview not available');
+        return p;
+      }, function (err) {
+        // If the batch fails, only the hovered entity falls back to its own
+        // endpoint; the prefetched rest retry when hovered.
+        if (u === previewURL) return fetchSingle(u);
+        throw err;
+      });
+      // Prefetched entries nobody awaits would otherwise log unhandled
+      // rejections.
+      pending[u].catch(function () {});
+    });
+    return pending[previewURL];
+  }
+
+  /**
+   * Fetch one preview from its own endpoint.
+   * @param {string} previewURL
+   * @returns {Promise<Object>}
+   */
+  function fetchSingle(previewURL) {
+    return Chronicle.apiFetch(previewURL)
+      .then(function (res) {
+        if (!res.ok) throw new Error('Preview fetch failed: ' + res.status);
+        return res.json();
+      })
+      .then(function (data) {
+        cacheSet(previewURL, data);
+        return data;
+      });
+  }
+
   // --- Tooltip Singleton ---
 
   var tooltipEl = null;    // The tooltip DOM element (created lazily).
@@ -156,6 +250,8 @@
     var tip = ensureTooltip();
     var html = '';
     var hasImage = data.image_path && data.image_path !== '';
+    // Pages without an image show their initials when the type asks for it.
+    var hasInitials = !hasImage && data.initials && data.initials !== '';
     var hasAttrs = data.attributes && data.attributes.length > 0;
     var hasExcerpt = data.entry_excerpt && data.entry_excerpt !== '';
 
@@ -163,11 +259,15 @@
     tip.style.setProperty('--et-color', data.type_color || '#6366f1');
 
     // Content area: image (with gradient border) + info side by side.
-    html += '<div class="' + (hasImage ? 'et-tooltip__content' : 'et-tooltip__content--no-image') + '">';
+    html += '<div class="' + (hasImage || hasInitials ? 'et-tooltip__content' : 'et-tooltip__content--no-image') + '">';
 
     if (hasImage) {
       html += '<div class="et-tooltip__image-wrap">';
-      html += '<img class="et-tooltip__image" src="' + Chronicle.escapeAttr(data.image_path) + '" alt="' + Chronicle.escapeAttr(data.name) + '" />';
+      html += '<img class="et-tooltip__image" src="' + Chronicle.escapeAttr(data.image_path) + '" alt="' + Chronicle.escapeAttr(data.image_alt || data.name) + '" />';
+      html += '</div>';
+    } else if (hasInitials) {
+      html += '<div class="et-tooltip__image-wrap" aria-hidden="true">';
+      html += '<div class="et-tooltip__initials" style="background-color: ' + Chronicle.escapeAttr(data.type_color) + '; color: ' + contrastTextColor(data.type_color) + '">' + Chronicle.escapeHtml(data.initials) + '</div>';
       html += '</div>';
     }
 
@@ -289,14 +389,9 @@
       tip.classList.add('et-tooltip--visible');
     });
 
-    // Fetch preview data.
-    Chronicle.apiFetch(previewURL)
-      .then(function (res) {
-        if (!res.ok) throw new Error('Preview fetch failed: ' + res.status);
-        return res.json();
-      })
+    // Fetch preview data (batched with the rest of the page when possible).
+    loadPreview(previewURL)
       .then(function (data) {
-        cacheSet(previewURL, data);
         // Only render if this target is still the active one.
         if (activeTarget === target) {
           renderTooltip(data, target.getAttribute('href') || '#');
@@ -517,6 +612,7 @@
     clearCache: function () {
       cache = {};
       cacheOrder = [];
+      pending = {};
     }
   };
 })();
@@ -230,7 +230,17 @@
           body: { color: newColor }
         })
           .then(function (res) {
-            if (!res.ok) console.error('[entity-type-config] Color save failed: HTTP ' + res.status);
+            if (!res.ok) {
+              console.error('[entity-type-config] Color save failed: HTTP ' + res.status);
+              return;
+            }
+            // Low-contrast colors still save; warn so the owner can pick
+            // one that reads on both themes.
+            return res.json().then(function (body) {
+              ((body && body.warnings) || []).forEach(function (w) {
+                Chronicle.notify && Chronicle.notify('Accessibility: ' + w.message, 'warning');
+              });
+            }, function () {});
           })
           .catch(function (err) {
             console.error('[entity-type-config] Color save error:', err);
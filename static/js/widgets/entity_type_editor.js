@@ -35,7 +35,8 @@
     { value: 'textarea', label: 'Textarea' },
     { value: 'select', label: 'Select' },
     { value: 'checkbox', label: 'Checkbox' },
-    { value: 'url', label: 'URL' }
+    { value: 'url', label: 'URL' },
+    { value: 'date', label: 'Date' }
   ];
 
   Chronicle.register('entity-type-editor', {
@@ -199,9 +200,28 @@
           });
           typeSelect.addEventListener('change', function () {
             fields[idx].type = this.value;
+            if (this.value !== 'date') delete fields[idx].anniversary;
+            renderFields();
           });
           row.appendChild(typeSelect);
 
+          // Date fields can put a yearly event on the campaign calendar.
+          if (field.type === 'date') {
+            var annivLabel = document.createElement('label');
+            annivLabel.className = 'flex items-center gap-1 text-xs text-gray-600 dark:text-gray-400 whitespace-nowrap';
+            annivLabel.title = 'Add a yearly event on the calendar for this date';
+            var annivInput = document.createElement('input');
+            annivInput.type = 'checkbox';
+            annivInput.className = 'rounded';
+            annivInput.checked = !!field.anniversary;
+            annivInput.addEventListener('change', function () {
+              fields[idx].anniversary = this.checked;
+            });
+            annivLabel.appendChild(annivInput);
+            annivLabel.appendChild(document.createTextNode('On calendar'));
+            row.appendChild(annivLabel);
+          }
+
           // Section input.
           var sectionInput = document.createElement('input');
           sectionInput.type = 'text';
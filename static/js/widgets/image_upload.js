@@ -9,6 +9,9 @@
  *   data-endpoint    - Entity image API endpoint (PUT), e.g. /campaigns/:id/entities/:eid/image
  *   data-upload-url  - Media upload endpoint (POST), e.g. /media/upload
  *   data-csrf-token  - CSRF token for mutating requests
+ *   data-ask-alt     - "true" to ask for alt text before uploading (header images)
+ *   data-require-alt - "true" in public campaigns, where the server rejects
+ *                      a header image without alt text
  */
 Chronicle.register('image-upload', {
   init: function (el, config) {
@@ -53,6 +56,24 @@ Chronicle.register('image-upload', {
         return;
       }
 
+      // Ask for a description before uploading, so a public campaign's
+      // required alt text doesn't fail the save after the upload.
+      var altText = '';
+      if (config.askAlt === true) {
+        var answer = window.prompt(
+          config.requireAlt === true
+            ? 'Describe this image for screen readers (required in public campaigns):'
+            : 'Describe this image for screen readers (optional):',
+          ''
+        );
+        altText = (answer || '').trim();
+        if (answer === null || (config.requireAlt === true && !altText)) {
+          if (answer !== null) Chronicle.notify('Alt text is required for images in a public campaign.', 'warning');
+          fileInput.value = '';
+          return;
+        }
+      }
+
       // Show upload feedback.
       el.style.opacity = '0.6';
       el.style.pointerEvents = 'none';
@@ -87,11 +108,15 @@ Chronicle.register('image-upload', {
           // Step 2: Set the uploaded image path on the entity.
           return Chronicle.apiFetch(config.endpoint, {
             method: 'PUT',
-            body: { image_path: data.id },
+            body: config.askAlt === true ? { image_path: data.id, alt_text: altText } : { image_path: data.id },
           });
         })
         .then(function (res) {
-          if (!res.ok) throw new Error('Failed to set entity image: ' + res.status);
+          if (!res.ok) {
+            return res.json().catch(function () { return {}; }).then(function (body) {
+              throw new Error(body.message || 'Failed to set entity image: ' + res.status);
+            });
+          }
           // Reload the page to show the new image.
           window.location.reload();
         })
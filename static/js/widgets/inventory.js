@@ -3,7 +3,9 @@
  *
  * Displays a character's inventory as a list of items (entities linked via
  * "Has Item" relations) with quantity, equipped, and attuned metadata.
- * Items can be searched and added from existing item entities.
+ * Items can be searched and added from existing item entities. With a
+ * transfer endpoint, each item gets a "Give" action that moves some or all
+ * of the stack to another entity via the armory (logged and audited).
  *
  * Mount via:
  *   <div data-widget="inventory"
@@ -12,6 +14,8 @@
  *        data-campaign-url="/campaigns/:id"
  *        data-relation-type="Has Item"
  *        data-reverse-relation-type="In Inventory Of"
+ *        data-entity-id="..."
+ *        data-transfer-endpoint="/campaigns/:id/armory/transfer"
  *        data-editable="true"
  *        data-csrf-token="..."
  *   ></div>
@@ -25,6 +29,8 @@ Chronicle.register('inventory', {
     var reverseRelationType = el.dataset.reverseRelationType || 'In Inventory Of';
     var editable = el.dataset.editable === 'true';
     var csrfToken = el.dataset.csrfToken || '';
+    var entityId = el.dataset.entityId || '';
+    var transferEndpoint = el.dataset.transferEndpoint || '';
 
     // Internal state.
     var state = {
@@ -34,6 +40,13 @@ Chronicle.register('inventory', {
       searchQuery: '',
       searchResults: [],
       searchTimer: null,
+      // Give panel: the relation being transferred and the chosen recipient.
+      giveFor: null,
+      giveQuery: '',
+      giveResults: [],
+      giveTarget: null,
+      giveQty: 1,
+      giveError: '',
     };
     el._invState = state;
 
@@ -74,6 +87,12 @@ Chronicle.register('inventory', {
       '.dark .inv-search-item:hover { background: #374151; }',
       '.inv-add-panel { border: 1px solid #e5e7eb; border-radius: 0.375rem; padding: 0.75rem; margin-bottom: 0.75rem; }',
       '.dark .inv-add-panel { border-color: #374151; }',
+      '.inv-give { color: #6b7280; cursor: pointer; border: none; background: none; font-size: 0.75rem; padding: 0.125rem; }',
+      '.inv-give:hover { color: #3b82f6; }',
+      '.inv-give-panel { border: 1px solid #e5e7eb; border-radius: 0.375rem; padding: 0.5rem; margin: -0.125rem 0 0.25rem 2.25rem; }',
+      '.dark .inv-give-panel { border-color: #374151; }',
+      '.inv-give-row { display: flex; align-items: center; gap: 0.375rem; }',
+      '.inv-give-error { color: #ef4444; font-size: 0.75rem; margin-top: 0.25rem; }',
     ].join('\n');
     el.appendChild(style);
 
@@ -129,6 +148,9 @@ Chronicle.register('inventory', {
         list.className = 'inv-list';
         state.items.forEach(function (item) {
           list.appendChild(renderItem(item));
+          if (state.giveFor === item.id) {
+            list.appendChild(renderGivePanel(item));
+          }
         });
         wrap.appendChild(list);
       }
@@ -223,6 +245,25 @@ Chronicle.register('inventory', {
         controls.appendChild(attBtn);
       }
 
+      // Give button.
+      if (transferEndpoint) {
+        var giveBtn = document.createElement('button');
+        giveBtn.className = 'inv-give';
+        giveBtn.innerHTML = '<i class="fa-solid fa-right-left"></i>';
+        giveBtn.title = 'Give to…';
+        giveBtn.addEventListener('click', function () {
+          var opening = state.giveFor !== item.id;
+          state.giveFor = opening ? item.id : null;
+          state.giveQuery = '';
+          state.giveResults = [];
+          state.giveTarget = null;
+          state.giveQty = meta.quantity || 1;
+          state.giveError = '';
+          render();
+        });
+        controls.appendChild(giveBtn);
+      }
+
       // Remove button.
       if (editable) {
         var removeBtn = document.createElement('button');
@@ -274,6 +315,95 @@ Chronicle.register('inventory', {
       return panel;
     }
 
+    function renderGivePanel(item) {
+      var meta = parseMetadata(item.metadata);
+      var have = meta.quantity || 1;
+      var panel = document.createElement('div');
+      panel.className = 'inv-give-panel';
+
+      if (!state.giveTarget) {
+        var searchInput = document.createElement('input');
+        searchInput.type = 'text';
+        searchInput.className = 'inv-search';
+        searchInput.placeholder = 'Give ' + (item.targetEntityName || 'item') + ' to…';
+        searchInput.value = state.giveQuery;
+        searchInput.addEventListener('input', function () {
+          state.giveQuery = searchInput.value;
+          clearTimeout(state.searchTimer);
+          state.searchTimer = setTimeout(function () {
+            searchRecipients(state.giveQuery);
+          }, 300);
+        });
+        panel.appendChild(searchInput);
+
+        var results = document.createElement('div');
+        results.className = 'inv-search-results';
+        state.giveResults.forEach(function (entity) {
+          if (entity.id === entityId || entity.id === item.targetEntityId) return;
+          var row = document.createElement('div');
+          row.className = 'inv-search-item';
+          row.innerHTML = '<i class="fa-solid ' + Chronicle.escapeHtml(entity.type_icon || 'fa-user') + '" style="color:' + Chronicle.escapeAttr(entity.type_color || '#6b7280') + ';font-size:0.75rem"></i> ' +
+            '<span>' + Chronicle.escapeHtml(entity.name) + '</span>';
+          row.addEventListener('click', function () {
+            state.giveTarget = entity;
+            render();
+          });
+          results.appendChild(row);
+        });
+        panel.appendChild(results);
+        setTimeout(function () { searchInput.focus(); }, 0);
+        return panel;
+      }
+
+      var confirmRow = document.createElement('div');
+      confirmRow.className = 'inv-give-row';
+      var label = document.createElement('span');
+      label.style.flex = '1';
+      label.textContent = 'To ' + state.giveTarget.name;
+      confirmRow.appendChild(label);
+
+      if (have > 1) {
+        var qtyInput = document.createElement('input');
+        qtyInput.type = 'number';
+        qtyInput.className = 'inv-qty';
+        qtyInput.min = 1;
+        qtyInput.max = have;
+        qtyInput.value = Math.min(state.giveQty, have);
+        qtyInput.title = 'How many to give';
+        qtyInput.addEventListener('change', function () {
+          state.giveQty = Math.max(1, Math.min(have, parseInt(qtyInput.value) || 1));
+        });
+        confirmRow.appendChild(qtyInput);
+      }
+
+      var goBtn = document.createElement('button');
+      goBtn.className = 'inv-add-btn';
+      goBtn.textContent = 'Give';
+      goBtn.addEventListener('click', function () {
+        giveItem(item, have > 1 ? Math.min(state.giveQty, have) : 1);
+      });
+      confirmRow.appendChild(goBtn);
+
+      var backBtn = document.createElement('button');
+      backBtn.className = 'inv-remove';
+      backBtn.innerHTML = '<i class="fa-solid fa-xmark"></i>';
+      backBtn.title = 'Choose someone else';
+      backBtn.addEventListener('click', function () {
+        state.giveTarget = null;
+        render();
+      });
+      confirmRow.appendChild(backBtn);
+      panel.appendChild(confirmRow);
+
+      if (state.giveError) {
+        var errEl = document.createElement('div');
+        errEl.className = 'inv-give-error';
+        errEl.textContent = state.giveError;
+        panel.appendChild(errEl);
+      }
+      return panel;
+    }
+
     // --- API Calls ---
 
     function loadItems() {
@@ -338,6 +468,52 @@ Chronicle.register('inventory', {
         });
     }
 
+    function searchRecipients(query) {
+      if (!query || query.length < 2) {
+        state.giveResults = [];
+        render();
+        return;
+      }
+      Chronicle.apiFetch(entitySearchEndpoint + '?q=' + encodeURIComponent(query))
+        .then(function (res) { return res.json(); })
+        .then(function (data) {
+          state.giveResults = data?.data || data || [];
+          render();
+        })
+        .catch(function (err) {
+          console.error('Inventory: Recipient search failed', err);
+        });
+    }
+
+    function giveItem(item, quantity) {
+      state.giveError = '';
+      Chronicle.apiFetch(transferEndpoint, {
+        method: 'POST',
+        body: {
+          item_entity_id: item.targetEntityId,
+          from_entity_id: entityId,
+          to_entity_id: state.giveTarget.id,
+          quantity: quantity,
+        },
+      })
+        .then(function (res) {
+          return res.json().catch(function () { return {}; }).then(function (data) {
+            if (!res.ok) throw new Error(data.message || 'Transfer failed');
+            return data;
+          });
+        })
+        .then(function (data) {
+          state.giveFor = null;
+          state.giveTarget = null;
+          Chronicle.notify && Chronicle.notify('Gave ' + quantity + ' × ' + data.item_name + ' to ' + data.to_name, 'success');
+          loadItems();
+        })
+        .catch(function (err) {
+          state.giveError = err.message;
+          render();
+        });
+    }
+
     function removeItem(relationId) {
       Chronicle.apiFetch(relationsEndpoint + '/' + relationId, {
         method: 'DELETE',
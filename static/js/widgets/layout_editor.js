@@ -38,9 +38,12 @@
     { value: 'xl',   label: 'X-Large', px: '700px' },
   ];
 
+  // Minimum role that sees a block; applied server-side on render.
   var VISIBILITY_OPTIONS = [
-    { value: 'everyone', label: 'Everyone', icon: 'fa-globe' },
-    { value: 'dm_only',  label: 'DM Only',  icon: 'fa-lock' },
+    { value: 'everyone', label: 'Everyone',  icon: 'fa-globe' },
+    { value: 'members',  label: 'Members',   icon: 'fa-user' },
+    { value: 'scribe',   label: 'Scribes+',  icon: 'fa-feather' },
+    { value: 'dm_only',  label: 'DM Only',   icon: 'fa-lock' },
   ];
 
   var TWO_COL_PRESETS = [
@@ -71,6 +74,7 @@
     { type: 'session_tracker',  label: 'Sessions',         icon: 'fa-dice-d20',          desc: 'Upcoming sessions with RSVP',    addon: 'sessions' },
     { type: 'activity_feed',    label: 'Activity Feed',    icon: 'fa-clock-rotate-left', desc: 'Recent campaign activity log' },
     { type: 'sync_status',      label: 'Foundry Sync',     icon: 'fa-plug',              desc: 'Foundry VTT sync status',        addon: 'foundry' },
+    { type: 'faction_standings', label: 'Faction Standings', icon: 'fa-scale-balanced',  desc: 'Current standing with each faction' },
   ];
 
   var FALLBACK_TEMPLATE_BLOCKS = [
@@ -411,6 +415,7 @@
 
         palette.appendChild(presetActions);
       }
+      this._appendHistorySection(palette);
     },
 
     _buildDashboardPalette: function (palette) {
@@ -434,6 +439,64 @@
         rowContent.appendChild(btn);
       });
       palette.appendChild(rowSection);
+      this._appendShareSection(palette);
+      this._appendHistorySection(palette);
+    },
+
+    // _appendShareSection adds export/import and "copy from another
+    // campaign" for dashboard layouts. Imports land in the canvas unsaved,
+    // like a preset.
+    _appendShareSection: function (palette) {
+      if (!this.baseEndpoint || !this.campaignId) return;
+      var self = this;
+      var section = this._createPaletteSectionEl('Share', 'fa-share-nodes', false);
+      var content = section.querySelector('.palette-section-content');
+      var btnClass = 'flex items-center gap-2 w-full px-3 py-2 mb-1 bg-surface-raised border border-edge rounded-md hover:border-accent/50 hover:shadow-sm transition-all text-sm text-left';
+
+      var exportBtn = document.createElement('button');
+      exportBtn.className = btnClass;
+      exportBtn.innerHTML = '<i class="fa-solid fa-file-export w-4 text-fg-muted text-center"></i><span class="text-fg">Export JSON</span>';
+      exportBtn.addEventListener('click', function () { self.exportLayout(); });
+      content.appendChild(exportBtn);
+
+      var fileInput = document.createElement('input');
+      fileInput.type = 'file';
+      fileInput.accept = 'application/json,.json';
+      fileInput.className = 'hidden';
+      fileInput.addEventListener('change', function () {
+        var file = fileInput.files && fileInput.files[0];
+        fileInput.value = '';
+        if (file) file.text().then(function (text) { self.importLayout(text); });
+      });
+      content.appendChild(fileInput);
+
+      var importBtn = document.createElement('button');
+      importBtn.className = btnClass;
+      importBtn.innerHTML = '<i class="fa-solid fa-file-import w-4 text-fg-muted text-center"></i><span class="text-fg">Import JSON</span>';
+      importBtn.addEventListener('click', function () { fileInput.click(); });
+      content.appendChild(importBtn);
+
+      var copyBtn = document.createElement('button');
+      copyBtn.className = btnClass;
+      copyBtn.innerHTML = '<i class="fa-solid fa-copy w-4 text-fg-muted text-center"></i><span class="text-fg">Copy from Campaign</span>';
+      copyBtn.addEventListener('click', function () { self.showCopySourceMenu(copyBtn); });
+      content.appendChild(copyBtn);
+
+      palette.appendChild(section);
+    },
+
+    // _appendHistorySection adds the "Version History" button. Every save
+    // keeps the layout it replaced, so a botched session can be rolled back.
+    _appendHistorySection: function (palette) {
+      if (!this.baseEndpoint) return;
+      var self = this;
+      var section = this._createPaletteSectionEl('History', 'fa-clock-rotate-left', false);
+      var btn = document.createElement('button');
+      btn.className = 'flex items-center gap-2 w-full px-3 py-2 bg-surface-raised border border-edge rounded-md hover:border-accent/50 hover:shadow-sm transition-all text-sm text-left';
+      btn.innerHTML = '<i class="fa-solid fa-clock-rotate-left w-4 text-fg-muted text-center"></i><span class="text-fg">Version History</span>';
+      btn.addEventListener('click', function () { self.showHistoryMenu(btn); });
+      section.querySelector('.palette-section-content').appendChild(btn);
+      palette.appendChild(section);
     },
 
     // ── Canvas Rendering ─────────────────────────────────────────
@@ -596,18 +659,22 @@
         '<i class="fa-solid ' + bt.icon + ' w-4 text-fg-muted text-center text-sm"></i>' +
         '<span class="text-sm font-medium text-fg flex-1">' + Chronicle.escapeHtml(bt.label) + '</span>';
 
-      // Visibility controls (template with visibility feature).
+      // Visibility controls (minimum role plus optional group limit).
       if (this.hasFeature('visibility')) {
         var curVis = block.config.visibility || 'everyone';
-        if (curVis === 'dm_only') {
+        var groupCount = (block.config.visible_groups || []).length;
+        if (curVis !== 'everyone' || groupCount > 0) {
+          var visOpt = VISIBILITY_OPTIONS.find(function (v) { return v.value === curVis; }) || VISIBILITY_OPTIONS[0];
+          var visTitle = visOpt.label + (groupCount > 0 ? ' · ' + groupCount + ' group(s)' : '');
           el.classList.add('border-amber-400', 'dark:border-amber-600');
-          html += '<i class="fa-solid fa-lock text-amber-500 text-[10px]" title="DM Only"></i>';
+          html += '<i class="fa-solid ' + (groupCount > 0 ? 'fa-users' : visOpt.icon) + ' text-amber-500 text-[10px]" title="' + Chronicle.escapeHtml(visTitle) + '"></i>';
         }
         html += '<select class="le-block-vis opacity-0 group-hover/block:opacity-100 text-[10px] bg-transparent text-fg-muted border border-edge rounded px-1 py-0.5 cursor-pointer hover:text-fg transition-all" title="Visibility">';
         VISIBILITY_OPTIONS.forEach(function (v) {
           html += '<option value="' + v.value + '"' + (v.value === curVis ? ' selected' : '') + '>' + v.label + '</option>';
         });
         html += '</select>';
+        html += '<button class="le-block-groups opacity-0 group-hover/block:opacity-100 text-fg-muted hover:text-accent transition-all p-0.5" title="Limit to groups"><i class="fa-solid fa-users text-xs"></i></button>';
       }
 
       // Height controls (template with height feature).
@@ -644,6 +711,15 @@
         visSelect.addEventListener('mousedown', function (e) { e.stopPropagation(); });
       }
 
+      // Bind group limit picker.
+      var groupsBtn = el.querySelector('.le-block-groups');
+      if (groupsBtn) {
+        groupsBtn.addEventListener('click', function (e) {
+          e.stopPropagation();
+          self.showGroupMenu(groupsBtn, block);
+        });
+      }
+
       // Bind height change.
       var heightSelect = el.querySelector('.le-block-height');
       if (heightSelect) {
@@ -1711,6 +1787,289 @@
       }
     },
 
+    // ── Layout Sharing (dashboard context) ──────────────────────
+
+    // exportLayout downloads the saved layout shown in this editor (for
+    // dashboards with roles, the current role's).
+    exportLayout: function () {
+      if (this.dirty) Chronicle.notify('Exporting the saved layout; unsaved changes are not included', 'info');
+      var parts = this.endpoint.split('?');
+      var url = parts[0] + '/export' + (parts[1] ? '?' + parts[1] : '');
+      Chronicle.apiFetch(url)
+        .then(function (res) {
+          if (!res.ok) {
+            return res.json().then(function (body) {
+              throw new Error((body && (body.message || body.error)) || ('HTTP ' + res.status));
+            }, function () { throw new Error('HTTP ' + res.status); });
+          }
+          var match = /filename="([^"]+)"/.exec(res.headers.get('Content-Disposition') || '');
+          return res.blob().then(function (blob) {
+            var a = document.createElement('a');
+            a.href = URL.createObjectURL(blob);
+            a.download = match ? match[1] : 'dashboard-layout.json';
+            document.body.appendChild(a);
+            a.click();
+            a.remove();
+            setTimeout(function () { URL.revokeObjectURL(a.href); }, 0);
+          });
+        })
+        .catch(function (err) {
+          Chronicle.notify('Export failed: ' + err.message, 'error');
+        });
+    },
+
+    // importLayout sends layout JSON to the campaign to be checked against
+    // its block types and addons, then loads the result into the canvas.
+    importLayout: function (text) {
+      if (this.dirty && !confirm('Replace your unsaved changes with the imported layout?')) return;
+      var self = this;
+      Chronicle.apiFetch('/campaigns/' + this.campaignId + '/dashboard-layout/import', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: text,
+      })
+        .then(function (res) {
+          return res.json().then(function (body) {
+            if (!res.ok) throw new Error((body && (body.message || body.error)) || ('HTTP ' + res.status));
+            return body;
+          });
+        })
+        .then(function (data) {
+          self.layout = data.layout;
+          self.markDirty();
+          self.renderCanvas();
+          var status = self._findSaveStatus();
+          if (status) status.textContent = 'Layout imported — save to apply';
+          (data.skipped || []).forEach(function (sk) {
+            Chronicle.notify('Skipped "' + sk.type + '" block: ' + sk.reason, 'warning', { duration: 0 });
+          });
+        })
+        .catch(function (err) {
+          Chronicle.notify('Import failed: ' + err.message, 'error');
+        });
+    },
+
+    // showCopySourceMenu lists saved layouts from the user's other owned
+    // campaigns. Picking one fetches its export and imports it here.
+    showCopySourceMenu: function (anchorEl) {
+      this._closePresetMenu();
+      var self = this;
+
+      var menu = document.createElement('div');
+      menu.className = 'le-preset-menu absolute z-50 mt-1 w-56 bg-surface border border-edge rounded-lg shadow-lg overflow-hidden';
+      menu.style.cssText = 'max-height:300px;overflow-y:auto;';
+      menu.innerHTML = '<div class="px-3 py-2 text-xs text-fg-muted">Loading campaigns...</div>';
+
+      var rect = anchorEl.getBoundingClientRect();
+      var paletteRect = anchorEl.closest('.w-56').getBoundingClientRect();
+      menu.style.position = 'fixed';
+      menu.style.left = paletteRect.left + 'px';
+      menu.style.top = (rect.bottom + 4) + 'px';
+      menu.style.width = paletteRect.width + 'px';
+      document.body.appendChild(menu);
+      this._presetMenu = menu;
+
+      this._presetMenuClickHandler = function (e) {
+        if (!menu.contains(e.target) && e.target !== anchorEl) self._closePresetMenu();
+      };
+      setTimeout(function () { document.addEventListener('click', self._presetMenuClickHandler); }, 0);
+
+      Chronicle.apiFetch('/campaigns/' + this.campaignId + '/dashboard-layout/sources')
+        .then(function (r) { return r.ok ? r.json() : []; })
+        .then(function (sources) {
+          menu.innerHTML = '';
+          if (!sources || sources.length === 0) {
+            menu.innerHTML = '<div class="px-3 py-2 text-xs text-fg-muted">No saved layouts in your other campaigns</div>';
+            return;
+          }
+          sources.forEach(function (src) {
+            var header = document.createElement('div');
+            header.className = 'px-3 pt-2 pb-1 text-[10px] font-semibold uppercase tracking-wider text-fg-muted';
+            header.textContent = src.campaign_name;
+            menu.appendChild(header);
+            src.layouts.forEach(function (item) {
+              var btn = document.createElement('button');
+              btn.className = 'flex items-center gap-2 w-full px-3 py-2 text-sm text-left text-fg hover:bg-accent/10 transition-colors';
+              btn.innerHTML = '<i class="fa-solid fa-table-columns w-4 text-fg-muted text-center"></i><span>' + Chronicle.escapeHtml(item.label) + '</span>';
+              btn.addEventListener('click', function () {
+                self._closePresetMenu();
+                Chronicle.apiFetch(item.url)
+                  .then(function (r) {
+                    if (!r.ok) throw new Error('HTTP ' + r.status);
+                    return r.text();
+                  })
+                  .then(function (text) { self.importLayout(text); })
+                  .catch(function (err) { Chronicle.notify('Copy failed: ' + err.message, 'error'); });
+              });
+              menu.appendChild(btn);
+            });
+          });
+        })
+        .catch(function () {
+          menu.innerHTML = '<div class="px-3 py-2 text-xs text-red-400">Failed to load campaigns</div>';
+        });
+    },
+
+    // ── Version History ──────────────────────────────────────────
+
+    // _versionsEndpoint drops the role query: a dashboard version holds
+    // every role's layout.
+    _versionsEndpoint: function () {
+      return this.baseEndpoint.split('?')[0] + '/versions';
+    },
+
+    // showHistoryMenu lists earlier saved versions, newest first. Shares
+    // the preset menu's popover so only one is ever open.
+    showHistoryMenu: function (anchorEl) {
+      this._closePresetMenu();
+      var self = this;
+
+      var menu = document.createElement('div');
+      menu.className = 'le-preset-menu absolute z-50 mt-1 w-56 bg-surface border border-edge rounded-lg shadow-lg overflow-hidden';
+      menu.style.cssText = 'max-height:300px;overflow-y:auto;';
+      menu.innerHTML = '<div class="px-3 py-2 text-xs text-fg-muted">Loading history...</div>';
+
+      var rect = anchorEl.getBoundingClientRect();
+      var paletteRect = anchorEl.closest('.w-56').getBoundingClientRect();
+      menu.style.position = 'fixed';
+      menu.style.left = paletteRect.left + 'px';
+      menu.style.top = (rect.bottom + 4) + 'px';
+      menu.style.width = paletteRect.width + 'px';
+      document.body.appendChild(menu);
+      this._presetMenu = menu;
+
+      this._presetMenuClickHandler = function (e) {
+        if (!menu.contains(e.target) && e.target !== anchorEl) self._closePresetMenu();
+      };
+      setTimeout(function () { document.addEventListener('click', self._presetMenuClickHandler); }, 0);
+
+      Chronicle.apiFetch(this._versionsEndpoint())
+        .then(function (r) { return r.ok ? r.json() : []; })
+        .then(function (versions) {
+          menu.innerHTML = '';
+          if (!versions || versions.length === 0) {
+            menu.innerHTML = '<div class="px-3 py-2 text-xs text-fg-muted">No earlier versions yet</div>';
+            return;
+          }
+          versions.forEach(function (v) {
+            var when = new Date(v.created_at).toLocaleString();
+            var who = v.created_by_name ? ' · ' + v.created_by_name : '';
+            var item = document.createElement('button');
+            item.className = 'flex items-center gap-2 w-full px-3 py-2 text-sm text-left text-fg hover:bg-accent/10 transition-colors';
+            item.innerHTML = '<i class="fa-solid fa-rotate-left w-4 text-fg-muted text-center"></i>' +
+              '<div><div class="font-medium">' + Chronicle.escapeHtml(v.note || 'Saved layout') + '</div>' +
+              '<div class="text-[10px] text-fg-muted">' + Chronicle.escapeHtml(when + who) + '</div></div>' +
+              (v.is_default ? '<span class="ml-auto text-[9px] text-fg-muted border border-edge rounded px-1">Default</span>' : '');
+            item.addEventListener('click', function () {
+              self._closePresetMenu();
+              self.restoreVersion(v);
+            });
+            menu.appendChild(item);
+          });
+        })
+        .catch(function () {
+          menu.innerHTML = '<div class="px-3 py-2 text-xs text-red-400">Failed to load history</div>';
+        });
+    },
+
+    // showGroupMenu lets the owner limit a block to campaign groups. Ticked
+    // groups are stored in config.visible_groups; viewers outside all of
+    // them don't get the block, whatever its role setting.
+    showGroupMenu: function (anchorEl, block) {
+      this._closePresetMenu();
+      var self = this;
+
+      var menu = document.createElement('div');
+      menu.className = 'le-preset-menu absolute z-50 mt-1 w-56 bg-surface border border-edge rounded-lg shadow-lg overflow-hidden';
+      menu.style.cssText = 'max-height:300px;overflow-y:auto;';
+      menu.innerHTML = '<div class="px-3 py-2 text-xs text-fg-muted">Loading groups...</div>';
+
+      var rect = anchorEl.getBoundingClientRect();
+      menu.style.position = 'fixed';
+      menu.style.left = Math.max(8, rect.right - 224) + 'px';
+      menu.style.top = (rect.bottom + 4) + 'px';
+      document.body.appendChild(menu);
+      this._presetMenu = menu;
+
+      this._presetMenuClickHandler = function (e) {
+        if (!menu.contains(e.target) && e.target !== anchorEl) self._closePresetMenu();
+      };
+      setTimeout(function () { document.addEventListener('click', self._presetMenuClickHandler); }, 0);
+
+      Chronicle.apiFetch('/campaigns/' + this.campaignId + '/groups')
+        .then(function (r) { return r.ok ? r.json() : { groups: [] }; })
+        .then(function (data) {
+          var groups = (data && data.groups) || [];
+          menu.innerHTML = '<div class="px-3 py-2 text-[10px] font-semibold uppercase tracking-wider text-fg-muted border-b border-edge">Only show to</div>';
+          if (groups.length === 0) {
+            menu.innerHTML += '<div class="px-3 py-2 text-xs text-fg-muted">No groups in this campaign yet</div>';
+            return;
+          }
+          var selected = block.config.visible_groups || [];
+          groups.forEach(function (g) {
+            var label = document.createElement('label');
+            label.className = 'flex items-center gap-2 w-full px-3 py-2 text-sm text-fg hover:bg-accent/10 cursor-pointer';
+            var cb = document.createElement('input');
+            cb.type = 'checkbox';
+            cb.checked = selected.indexOf(g.id) >= 0;
+            cb.addEventListener('change', function () {
+              var ids = (block.config.visible_groups || []).filter(function (id) { return id !== g.id; });
+              if (cb.checked) ids.push(g.id);
+              if (ids.length > 0) { block.config.visible_groups = ids; }
+              else { delete block.config.visible_groups; }
+              self.markDirty();
+              self.renderCanvas();
+            });
+            label.appendChild(cb);
+            label.appendChild(document.createTextNode(g.name));
+            menu.appendChild(label);
+          });
+        })
+        .catch(function () {
+          menu.innerHTML = '<div class="px-3 py-2 text-xs text-red-400">Failed to load groups</div>';
+        });
+    },
+
+    // restoreVersion puts a version back server-side and reloads it. The
+    // layout it replaces becomes a version too, so this can be undone.
+    restoreVersion: function (v) {
+      var msg = this.dirty
+        ? 'Restore this version? Your unsaved changes will be lost.'
+        : 'Restore this version? The current layout stays in the history.';
+      if (!confirm(msg)) return;
+      var self = this;
+
+      Chronicle.apiFetch(this._versionsEndpoint() + '/' + v.id + '/restore', { method: 'POST' })
+        .then(function (res) {
+          if (!res.ok) {
+            return res.json().then(function (body) {
+              throw new Error((body && (body.message || body.error)) || ('HTTP ' + res.status));
+            }, function () { throw new Error('HTTP ' + res.status); });
+          }
+          return Chronicle.apiFetch(self.endpoint).then(function (r) {
+            if (!r.ok) throw new Error('HTTP ' + r.status);
+            return r.json();
+          });
+        })
+        .then(function (data) {
+          var layout = self.context === 'template' ? data.layout : data;
+          if (self.context === 'template' && (!layout || !layout.rows || layout.rows.length === 0)) {
+            layout = self._defaultTemplateLayout();
+          }
+          self.layout = layout;
+          self.dirty = false;
+          var btn = self._findSaveBtn();
+          if (btn) btn.classList.remove('animate-pulse');
+          var status = self._findSaveStatus();
+          if (status) status.textContent = '';
+          self.render();
+          Chronicle.notify('Version restored', 'success');
+        })
+        .catch(function (err) {
+          Chronicle.notify('Failed to restore version: ' + err.message, 'error');
+        });
+    },
+
     saveAsPreset: function () {
       var name = prompt('Preset name:');
       if (!name || !name.trim()) return;
@@ -1813,6 +2172,12 @@
           var successLabel = self.context === 'template' ? 'Template saved' : 'Dashboard layout saved';
           if (status) { status.textContent = 'Saved'; setTimeout(function () { if (status && !self.dirty) status.textContent = ''; }, 2000); }
           Chronicle.notify(successLabel, 'success');
+          // The save response carries accessibility warnings (images
+          // without alt text, empty headings, low-contrast type colors).
+          // They never block the save; show them so the owner can fix them.
+          return res.json().then(function (body) {
+            self._notifyA11yWarnings(body && body.warnings);
+          }, function () {});
         })
         .catch(function (err) {
           var label = self.context === 'template' ? 'template' : 'dashboard layout';
@@ -1827,6 +2192,15 @@
         });
     },
 
+    // _notifyA11yWarnings shows one toast per accessibility warning, kept
+    // up until dismissed so there's time to read them.
+    _notifyA11yWarnings: function (warnings) {
+      if (!warnings || !warnings.length) return;
+      warnings.forEach(function (w) {
+        Chronicle.notify('Accessibility: ' + w.message, 'warning', { duration: 0 });
+      });
+    },
+
     load: function () {
       var self = this;
       Chronicle.apiFetch(this.endpoint)
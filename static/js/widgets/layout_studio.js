@@ -449,12 +449,13 @@
 
       if (ctx.type === CTX_CAMPAIGN_DASH) {
         widgetEl.setAttribute('data-endpoint', '/campaigns/' + this.campaignId + '/dashboard-layout');
-        widgetEl.setAttribute('data-features', 'roles');
+        widgetEl.setAttribute('data-features', 'roles,visibility');
         widgetEl.setAttribute('data-role', ctx.role || 'default');
       } else if (ctx.type === CTX_OWNER_DASH) {
         widgetEl.setAttribute('data-endpoint', '/campaigns/' + this.campaignId + '/owner-dashboard-layout');
       } else if (ctx.type === CTX_CATEGORY_DASH) {
         widgetEl.setAttribute('data-endpoint', '/campaigns/' + this.campaignId + '/entity-types/' + ctx.etid + '/dashboard-layout');
+        widgetEl.setAttribute('data-features', 'visibility');
       }
 
       this.editorEl.innerHTML = '';
@@ -250,7 +250,7 @@
         '<button type="button" class="card p-0 text-left hover:ring-2 hover:ring-accent/50 transition-all overflow-hidden group" data-action="picker-pick" data-id="' + Chronicle.escapeAttr(it.id) + '" title="' + Chronicle.escapeAttr(it.original_name) + '">';
       if (it.thumbnail_url || it.url) {
         html += '<div class="aspect-video bg-surface-alt overflow-hidden">';
-        html += '<img src="' + Chronicle.escapeAttr(it.thumbnail_url || it.url) + '" alt="' + Chronicle.escapeAttr(it.original_name) + '" class="w-full h-full object-cover group-hover:scale-105 transition-transform duration-200" loading="lazy"/>';
+        html += '<img src="' + Chronicle.escapeAttr(it.thumbnail_url || it.url) + '" alt="' + Chronicle.escapeAttr(it.alt_text || it.original_name) + '" class="w-full h-full object-cover group-hover:scale-105 transition-transform duration-200" loading="lazy"/>';
         html += '</div>';
       } else {
         html += '<div class="aspect-video bg-surface-alt flex items-center justify-center"><i class="fa-solid fa-file text-2xl text-fg-muted"></i></div>';
@@ -300,6 +300,8 @@
         url: item.url,
         thumbnailUrl: item.thumbnail_url || '',
         originalName: item.original_name,
+        altText: item.alt_text || '',
+        caption: item.caption || '',
         mimeType: item.mime_type,
         fileSize: item.file_size,
       },
@@ -0,0 +1,314 @@
+/**
+ * party_journal.js -- Chronicle Party Journal Widget
+ *
+ * The shared journal on Journal-type entity pages. Every member can append
+ * dated entries, signed with their name; a player's entry waits for the GM
+ * ("Awaiting GM approval") before the rest of the party sees it. The GM
+ * approves, annotates, and deletes entries but never edits them.
+ *
+ * The server enforces every rule (internal/widgets/party_journal); the
+ * canEdit / canModerate flags on each entry only decide which buttons show.
+ *
+ * Auto-mounted by boot.js on elements with data-widget="party-journal".
+ *
+ * Config (from data-* attributes):
+ *   data-endpoint  - API base (/campaigns/:id/entities/:eid/journal)
+ *   data-moderator - "true" for Scribe+ (shows the pending count)
+ *   data-csrf      - CSRF token
+ */
+(function () {
+  'use strict';
+
+  Chronicle.register('party-journal', {
+    init: function (el, config) {
+      var endpoint = config.endpoint || '';
+      var csrf = config.csrf || '';
+      var isModerator = config.moderator === true;
+      if (!endpoint) return;
+
+      var state = {
+        entries: [],
+        loading: true,
+        error: '',
+        editingId: null,
+        annotatingId: null
+      };
+
+      // --- API ---
+
+      // Chronicle.apiFetch returns a raw Response; surface the server's
+      // message so "only the author can edit this entry" reaches the user.
+      function asJSON(resp) {
+        if (!resp.ok) {
+          return resp.json().then(
+            function (body) {
+              var msg = (body && (body.message || body.error)) || ('HTTP ' + resp.status);
+              return Promise.reject(new Error(msg));
+            },
+            function () {
+              return Promise.reject(new Error('HTTP ' + resp.status));
+            }
+          );
+        }
+        return resp.json();
+      }
+
+      function send(method, url, body) {
+        return Chronicle.apiFetch(url, { method: method, body: body, csrfToken: csrf }).then(asJSON);
+      }
+
+      function load() {
+        Chronicle.apiFetch(endpoint)
+          .then(asJSON)
+          .then(function (entries) {
+            state.entries = entries || [];
+            state.loading = false;
+            state.error = '';
+            render();
+          })
+          .catch(function (err) {
+            state.loading = false;
+            state.error = humanError(err);
+            render();
+          });
+      }
+
+      // replace swaps in an entry returned by a write, keeping date order.
+      function replace(entry) {
+        var found = false;
+        state.entries = state.entries.map(function (e) {
+          if (e.id !== entry.id) return e;
+          found = true;
+          return entry;
+        });
+        if (!found) state.entries.push(entry);
+        state.entries.sort(function (a, b) {
+          if (a.entryDate !== b.entryDate) return a.entryDate < b.entryDate ? -1 : 1;
+          return a.createdAt < b.createdAt ? -1 : 1;
+        });
+      }
+
+      // --- Render ---
+
+      function render() {
+        var h = '';
+        h += '<div class="flex items-center justify-between mb-3">';
+        h += '<h3 class="text-sm font-semibold text-fg-secondary uppercase tracking-wider">';
+        h += '<i class="fa-solid fa-book-open mr-1.5"></i>Party Journal';
+        var pending = state.entries.filter(function (e) { return e.status === 'pending'; }).length;
+        if (isModerator && pending > 0) {
+          h += ' <span class="text-xs font-normal text-amber-600 dark:text-amber-400">(' + pending + ' awaiting approval)</span>';
+        }
+        h += '</h3>';
+        h += '</div>';
+
+        if (state.loading) {
+          h += '<p class="text-sm text-fg-muted"><i class="fa-solid fa-spinner fa-spin mr-1"></i>Loading journal…</p>';
+        } else if (state.error) {
+          h += '<p class="text-sm text-red-600 dark:text-red-400">' + esc(state.error) + '</p>';
+        } else if (state.entries.length === 0) {
+          h += '<p class="text-sm text-fg-muted mb-3">No entries yet. Write down what the party got up to.</p>';
+        }
+
+        h += '<div class="space-y-3">';
+        state.entries.forEach(function (e) { h += renderEntry(e); });
+        h += '</div>';
+
+        if (!state.loading && !state.error) h += renderCompose();
+        el.innerHTML = h;
+      }
+
+      function renderEntry(e) {
+        var h = '';
+        h += '<article class="card p-3" data-entry-id="' + esc(e.id) + '">';
+        h += '<header class="flex items-center gap-2 text-xs text-fg-muted mb-1.5">';
+        h += '<span class="font-semibold text-fg">' + esc(formatDate(e.entryDate)) + '</span>';
+        h += '<span>·</span><span>' + esc(e.authorName || 'Former member') + '</span>';
+        if (e.status === 'pending') {
+          h += '<span class="px-1.5 py-0.5 rounded bg-amber-500/10 text-amber-700 dark:text-amber-300">Awaiting GM approval</span>';
+        }
+        h += '<span class="flex-1"></span>';
+        h += renderActions(e);
+        h += '</header>';
+
+        if (state.editingId === e.id) {
+          h += '<input type="date" class="input text-sm mb-2" data-field="edit-date" value="' + esc(e.entryDate) + '"/>';
+          h += '<textarea class="input w-full text-sm" rows="4" data-field="edit-body">' + esc(e.body) + '</textarea>';
+          h += '<div class="flex justify-end gap-2 mt-2">';
+          h += '<button class="btn-secondary text-xs" data-action="cancel">Cancel</button>';
+          h += '<button class="btn-primary text-xs" data-action="save-edit" data-id="' + esc(e.id) + '">Save</button>';
+          h += '</div>';
+        } else {
+          h += '<p class="text-sm text-fg whitespace-pre-line">' + esc(e.body) + '</p>';
+        }
+
+        if (state.annotatingId === e.id) {
+          h += '<textarea class="input w-full text-sm mt-2" rows="2" data-field="gm-note" placeholder="GM annotation (leave empty to remove)">' + esc(e.gmNote) + '</textarea>';
+          h += '<div class="flex justify-end gap-2 mt-2">';
+          h += '<button class="btn-secondary text-xs" data-action="cancel">Cancel</button>';
+          h += '<button class="btn-primary text-xs" data-action="save-annotation" data-id="' + esc(e.id) + '">Save annotation</button>';
+          h += '</div>';
+        } else if (e.gmNote) {
+          h += '<div class="mt-2 pl-3 border-l-2 border-accent text-sm text-fg-secondary italic whitespace-pre-line">';
+          h += '<i class="fa-solid fa-feather-pointed mr-1 not-italic"></i>' + esc(e.gmNote);
+          h += '</div>';
+        }
+        h += '</article>';
+        return h;
+      }
+
+      function renderActions(e) {
+        var h = '';
+        var id = esc(e.id);
+        if (e.canModerate && e.status === 'pending') {
+          h += '<button class="btn-primary btn-sm" data-action="approve" data-id="' + id + '"><i class="fa-solid fa-check mr-1"></i>Approve</button>';
+        }
+        if (e.canModerate) {
+          h += '<button class="btn-ghost btn-sm" data-action="annotate" data-id="' + id + '" title="Annotate"><i class="fa-solid fa-feather-pointed"></i></button>';
+        }
+        if (e.canEdit) {
+          h += '<button class="btn-ghost btn-sm" data-action="edit" data-id="' + id + '" title="Edit"><i class="fa-solid fa-pen"></i></button>';
+        }
+        if (e.canEdit || e.canModerate) {
+          h += '<button class="btn-ghost btn-sm" data-action="delete" data-id="' + id + '" title="Delete"><i class="fa-solid fa-trash"></i></button>';
+        }
+        return h;
+      }
+
+      function renderCompose() {
+        var h = '';
+        h += '<div class="card p-3 mt-3 space-y-2 border-l-4 border-accent">';
+        h += '<div class="flex items-center gap-2">';
+        h += '<input type="date" class="input text-sm" data-field="date" value="' + esc(today()) + '" aria-label="Entry date"/>';
+        if (!isModerator) {
+          h += '<span class="text-xs text-fg-muted">The GM reviews entries before the party sees them.</span>';
+        }
+        h += '</div>';
+        h += '<textarea class="input w-full text-sm" rows="3" data-field="body" placeholder="What happened?"></textarea>';
+        h += '<div class="flex justify-end">';
+        h += '<button class="btn-primary text-xs" data-action="add"><i class="fa-solid fa-plus mr-1"></i>Add entry</button>';
+        h += '</div>';
+        h += '</div>';
+        return h;
+      }
+
+      // --- Events ---
+
+      function field(name) {
+        var f = el.querySelector('[data-field="' + name + '"]');
+        return f ? f.value : '';
+      }
+
+      function fail(err) {
+        Chronicle.notify && Chronicle.notify(humanError(err), 'error');
+      }
+
+      function onClick(ev) {
+        var btn = ev.target.closest('[data-action]');
+        if (!btn || !el.contains(btn)) return;
+        var id = btn.getAttribute('data-id');
+        var url = endpoint + '/' + encodeURIComponent(id || '');
+
+        switch (btn.getAttribute('data-action')) {
+          case 'add':
+            if (!field('body').trim()) {
+              Chronicle.notify && Chronicle.notify('Write something first', 'warn');
+              return;
+            }
+            send('POST', endpoint, { entryDate: field('date'), body: field('body') })
+              .then(function (entry) { replace(entry); render(); })
+              .catch(fail);
+            break;
+          case 'edit':
+            state.editingId = id;
+            state.annotatingId = null;
+            render();
+            break;
+          case 'annotate':
+            state.annotatingId = id;
+            state.editingId = null;
+            render();
+            break;
+          case 'cancel':
+            state.editingId = null;
+            state.annotatingId = null;
+            render();
+            break;
+          case 'save-edit':
+            send('PUT', url, { entryDate: field('edit-date'), body: field('edit-body') })
+              .then(function (entry) { state.editingId = null; replace(entry); render(); })
+              .catch(fail);
+            break;
+          case 'save-annotation':
+            send('PUT', url + '/annotation', { gmNote: field('gm-note') })
+              .then(function (entry) { state.annotatingId = null; replace(entry); render(); })
+              .catch(fail);
+            break;
+          case 'approve':
+            send('POST', url + '/approve')
+              .then(function (entry) { replace(entry); render(); })
+              .catch(fail);
+            break;
+          case 'delete':
+            if (!window.confirm('Delete this journal entry?')) return;
+            send('DELETE', url)
+              .then(function () {
+                state.entries = state.entries.filter(function (e) { return e.id !== id; });
+                render();
+              })
+              .catch(fail);
+            break;
+        }
+      }
+
+      // --- Helpers ---
+
+      function today() {
+        var d = new Date();
+        var m = String(d.getMonth() + 1).padStart(2, '0');
+        return d.getFullYear() + '-' + m + '-' + String(d.getDate()).padStart(2, '0');
+      }
+
+      // formatDate renders YYYY-MM-DD in the reader's locale without the
+      // timezone shift new Date('YYYY-MM-DD') would apply.
+      function formatDate(s) {
+        var p = String(s || '').split('-');
+        if (p.length !== 3) return s || '';
+        var d = new Date(+p[0], +p[1] - 1, +p[2]);
+        return d.toLocaleDateString(undefined, { year: 'numeric', month: 'short', day: 'numeric' });
+      }
+
+      function humanError(err) {
+        if (err && err.message) return err.message;
+        if (typeof err === 'string') return err;
+        return 'Something went wrong';
+      }
+
+      function esc(s) {
+        return Chronicle.escapeHtml ? Chronicle.escapeHtml(s || '') : escHtml(s || '');
+      }
+      function escHtml(s) {
+        return String(s)
+          .replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;')
+          .replace(/"/g, '&quot;').replace(/'/g, '&#39;');
+      }
+
+      // --- Init ---
+
+      el.addEventListener('click', onClick);
+      render();
+      load();
+
+      el.__partyJournalCleanup = function () {
+        el.removeEventListener('click', onClick);
+      };
+    },
+
+    destroy: function (el) {
+      if (el.__partyJournalCleanup) {
+        el.__partyJournalCleanup();
+        delete el.__partyJournalCleanup;
+      }
+    }
+  });
+})();
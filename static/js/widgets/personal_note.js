@@ -0,0 +1,117 @@
+/**
+ * personal_note.js -- Chronicle Personal Note Widget
+ *
+ * The "My notes" panel on entity pages: one plain-text scratchpad per
+ * member per entity that nobody else can read, the GM included. The note
+ * is fetched the first time the panel opens and autosaved shortly after
+ * the member stops typing. Clearing the textarea deletes the note.
+ *
+ * Auto-mounted by boot.js on elements with data-widget="personal-note"
+ * (the <details> rendered by show.templ personalNotePanel).
+ *
+ * Config (from data-* attributes):
+ *   data-endpoint - GET/PUT endpoint (/campaigns/:id/entities/:eid/my-note)
+ *   data-csrf     - CSRF token
+ */
+(function () {
+  'use strict';
+
+  var SAVE_DELAY_MS = 800;
+
+  Chronicle.register('personal-note', {
+    init: function (el, config) {
+      var endpoint = config.endpoint || '';
+      var csrf = config.csrf || '';
+      var body = el.querySelector('[data-role="body"]');
+      var status = el.querySelector('[data-role="status"]');
+      if (!endpoint || !body) return;
+
+      var loaded = false;
+      var timer = null;
+      var saved = '';
+      var saving = null;
+
+      function setStatus(text) {
+        if (status) status.textContent = text;
+      }
+
+      function load() {
+        if (loaded) return;
+        loaded = true;
+        setStatus('Loading...');
+        Chronicle.apiFetch(endpoint)
+          .then(function (res) {
+            if (!res.ok) throw new Error('load failed');
+            return res.json();
+          })
+          .then(function (note) {
+            saved = (note && note.body) || '';
+            body.value = saved;
+            body.disabled = false;
+            setStatus('');
+          })
+          .catch(function () {
+            // Let the next open retry rather than leaving the panel stuck.
+            loaded = false;
+            setStatus("Couldn't load your notes");
+          });
+      }
+
+      function save() {
+        clearTimeout(timer);
+        timer = null;
+        var value = body.value;
+        if (value === saved) return;
+        // One request at a time; whatever was typed meanwhile saves next.
+        if (saving) {
+          saving.then(save);
+          return;
+        }
+        setStatus('Saving...');
+        saving = Chronicle.apiFetch(endpoint, {
+          method: 'PUT',
+          body: { body: value },
+          csrfToken: csrf
+        })
+          .then(function (res) {
+            if (!res.ok) throw new Error('save failed');
+            saved = value;
+            setStatus(body.value === saved ? 'Saved' : '');
+          })
+          .catch(function () {
+            setStatus("Couldn't save -- keep this tab open and try again");
+          })
+          .then(function () { saving = null; });
+      }
+
+      function onInput() {
+        clearTimeout(timer);
+        timer = setTimeout(save, SAVE_DELAY_MS);
+      }
+
+      function onToggle() {
+        if (el.open) load();
+      }
+
+      body.addEventListener('input', onInput);
+      body.addEventListener('blur', save);
+      el.addEventListener('toggle', onToggle);
+      if (el.open) load();
+
+      el.__personalNoteCleanup = function () {
+        body.removeEventListener('input', onInput);
+        body.removeEventListener('blur', save);
+        el.removeEventListener('toggle', onToggle);
+        // Flush a pending autosave so navigating away doesn't drop it.
+        if (timer) save();
+      };
+    },
+
+    destroy: function (el) {
+      if (el.__personalNoteCleanup) {
+        el.__personalNoteCleanup();
+        delete el.__personalNoteCleanup;
+      }
+    }
+  });
+})();
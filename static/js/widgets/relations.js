@@ -14,6 +14,8 @@
  *                                 e.g. /campaigns/:id/entities?q=...
  *   data-campaign-url - Base URL for entity links,
  *                       e.g. /campaigns/:id
+ *   data-campaign-id  - Campaign ID; enables creating the target entity
+ *                       inline when the search finds no exact match
  *   data-editable     - "true" if user can modify relations (Scribe+)
  */
 Chronicle.register('relations', {
@@ -31,6 +33,9 @@ Chronicle.register('relations', {
       dmOnly: false,
       isSearching: false,
       isSubmitting: false,
+      entityTypes: [],
+      createTypeId: '',
+      isCreating: false,
       error: null
     };
 
@@ -100,7 +105,14 @@ Chronicle.register('relations', {
         '.dark .rel-dm-badge { color: #fbbf24; background: #451a03; }',
         '.rel-dm-toggle { display: flex; align-items: center; gap: 6px; margin-top: 8px; font-size: 12px; color: #6b7280; cursor: pointer; }',
         '.dark .rel-dm-toggle { color: #9ca3af; }',
-        '.rel-dm-toggle input { cursor: pointer; }'
+        '.rel-dm-toggle input { cursor: pointer; }',
+        '.rel-create { display: flex; align-items: center; gap: 6px; padding: 6px 8px; margin-top: 4px; border-top: 1px solid #f3f4f6; font-size: 13px; }',
+        '.dark .rel-create { border-top-color: #374151; }',
+        '.rel-create button { color: #4f46e5; background: none; border: none; cursor: pointer; padding: 0; text-align: left; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }',
+        '.dark .rel-create button { color: #818cf8; }',
+        '.rel-create button:disabled { opacity: 0.5; cursor: wait; }',
+        '.rel-create select { margin-left: auto; padding: 2px 6px; font-size: 12px; border: 1px solid #e5e7eb; border-radius: 6px; background: transparent; color: inherit; }',
+        '.dark .rel-create select { border-color: #4b5563; background: #1f2937; }'
       ].join('\n');
       document.head.appendChild(style);
     }
@@ -205,6 +217,7 @@ Chronicle.register('relations', {
             state.searchQuery = '';
             state.searchResults = [];
             render();
+            loadEntityTypes();
           });
           addWrap.appendChild(addBtn);
         } else {
@@ -477,6 +490,7 @@ Chronicle.register('relations', {
         empty.className = 'rel-empty';
         empty.textContent = 'No entities found';
         container.appendChild(empty);
+        renderCreateOption(container);
         return;
       }
 
@@ -511,10 +525,82 @@ Chronicle.register('relations', {
 
         container.appendChild(result);
       });
+
+      renderCreateOption(container);
+    }
+
+    // Offers "Create <query>" (name + type) when no result matches the
+    // query exactly, and selects the new entity as the target.
+    function renderCreateOption(container) {
+      var name = state.searchQuery.trim();
+      if (!config.campaignId || name.length < 2 || state.entityTypes.length === 0) return;
+      var lower = name.toLowerCase();
+      if (state.searchResults.some(function (e) { return (e.name || '').toLowerCase() === lower; })) return;
+
+      var row = document.createElement('div');
+      row.className = 'rel-create';
+
+      var btn = document.createElement('button');
+      btn.type = 'button';
+      btn.disabled = state.isCreating;
+      btn.innerHTML = '<i class="fa-solid fa-plus" style="font-size:10px"></i> ';
+      btn.appendChild(document.createTextNode('Create "' + name + '"'));
+      row.appendChild(btn);
+
+      var select = document.createElement('select');
+      select.setAttribute('aria-label', 'Type for the new entity');
+      state.entityTypes.forEach(function (t) {
+        var opt = document.createElement('option');
+        opt.value = String(t.id);
+        opt.textContent = t.name;
+        select.appendChild(opt);
+      });
+      select.value = state.createTypeId;
+      select.addEventListener('change', function () {
+        state.createTypeId = select.value;
+      });
+      row.appendChild(select);
+
+      btn.addEventListener('click', function () {
+        createTarget(name);
+      });
+
+      container.appendChild(row);
     }
 
     // --- API calls ---
 
+    function loadEntityTypes() {
+      if (!config.campaignId || state.entityTypes.length > 0) return;
+      Chronicle.getEntityTypes(config.campaignId).then(function (types) {
+        state.entityTypes = types;
+        if (types.length > 0 && !state.createTypeId) state.createTypeId = String(types[0].id);
+        var resultsEl = el.querySelector('.rel-results');
+        if (resultsEl) renderSearchResults(resultsEl);
+      });
+    }
+
+    function createTarget(name) {
+      if (state.isCreating) return;
+      state.isCreating = true;
+      var resultsEl = el.querySelector('.rel-results');
+      if (resultsEl) renderSearchResults(resultsEl);
+
+      Chronicle.quickCreateEntity(config.campaignId, name, state.createTypeId)
+        .then(function (entity) {
+          state.isCreating = false;
+          state.selectedTarget = entity;
+          state.searchResults = [entity];
+          render();
+        })
+        .catch(function (err) {
+          state.isCreating = false;
+          Chronicle.notify(err.message, 'error');
+          var resultsEl = el.querySelector('.rel-results');
+          if (resultsEl) renderSearchResults(resultsEl);
+        });
+    }
+
     function searchEntities(query) {
       if (state.isSearching) return;
       state.isSearching = true;
@@ -1569,6 +1569,14 @@ Chronicle.register('template-editor', {
       this.dirty = false;
       if (status) status.textContent = 'Saved';
       setTimeout(() => { if (status && !this.dirty) status.textContent = ''; }, 2000);
+
+      // Accessibility warnings never block the save; surface them so the
+      // owner can fix unlabeled images, empty headings or a hard-to-read
+      // type color.
+      const body = await res.json().catch(() => ({}));
+      (body.warnings || []).forEach((w) => {
+        Chronicle.notify('Accessibility: ' + w.message, 'warning', { duration: 0 });
+      });
     } catch (err) {
       if (status) status.textContent = 'Error: ' + err.message;
       if (status) status.classList.add('text-red-500');